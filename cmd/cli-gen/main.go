@@ -9,6 +9,7 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 
+	"duck-demo/internal/apispec"
 	cligen "duck-demo/internal/codegen/cli"
 )
 
@@ -49,6 +50,15 @@ func main() {
 		fatalf("render: %v", err)
 	}
 
+	// Record which spec the CLI was generated from
+	hash, err := apispec.Hash(spec)
+	if err != nil {
+		fatalf("hash spec: %v", err)
+	}
+	if err := cligen.RenderSpecInfo(cligen.SpecInfoModel{Version: spec.Info.Version, Hash: hash}, *outDir); err != nil {
+		fatalf("render: %v", err)
+	}
+
 	fmt.Printf("Generated %d groups + %d API endpoints in %s\n", len(groups), len(endpoints), *outDir)
 }
 
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"duck-demo/internal/agent"
 )
 
 // AgentConfig holds configuration for the compute agent, loaded from environment variables.
@@ -23,21 +27,43 @@ type AgentConfig struct {
 	CleanupInterval time.Duration
 	CursorMode      bool
 	InternalGRPC    bool
+	OTLPEndpoint    string // OTLP/HTTP trace collector URL; tracing is off when empty
+
+	ExtensionAllowlist []string // DuckDB extensions the agent may load; empty means the server default
+
+	// gRPC TLS. With TLSClientCAFile set, callers must present a client
+	// certificate signed by that CA (mutual TLS) in addition to the token.
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+
+	// Concurrency limiting; MaxConcurrentQueries of 0 disables it.
+	MaxConcurrentQueries int
+	MaxQueuedQueries     int
+	QueueTimeout         time.Duration
+
+	// SQL statement policy applied before execution; see agent.SQLPolicy.
+	SQLAllowPatterns []string
+	SQLDenyPatterns  []string
 }
 
 func loadAgentConfig() (*AgentConfig, error) {
 	cfg := &AgentConfig{
-		CatalogDSN:     os.Getenv("CATALOG_DSN"),
-		S3KeyID:        os.Getenv("S3_KEY_ID"),
-		S3Secret:       os.Getenv("S3_SECRET"),
-		S3Endpoint:     os.Getenv("S3_ENDPOINT"),
-		S3Region:       os.Getenv("S3_REGION"),
-		S3Bucket:       os.Getenv("S3_BUCKET"),
-		AgentToken:     os.Getenv("AGENT_TOKEN"),
-		ListenAddr:     os.Getenv("LISTEN_ADDR"),
-		GRPCListenAddr: os.Getenv("GRPC_LISTEN_ADDR"),
-		CursorMode:     true,
-		InternalGRPC:   true,
+		CatalogDSN:      os.Getenv("CATALOG_DSN"),
+		S3KeyID:         os.Getenv("S3_KEY_ID"),
+		S3Secret:        os.Getenv("S3_SECRET"),
+		S3Endpoint:      os.Getenv("S3_ENDPOINT"),
+		S3Region:        os.Getenv("S3_REGION"),
+		S3Bucket:        os.Getenv("S3_BUCKET"),
+		AgentToken:      os.Getenv("AGENT_TOKEN"),
+		ListenAddr:      os.Getenv("LISTEN_ADDR"),
+		GRPCListenAddr:  os.Getenv("GRPC_LISTEN_ADDR"),
+		OTLPEndpoint:    os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		TLSCertFile:     os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:      os.Getenv("TLS_KEY_FILE"),
+		TLSClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+		CursorMode:      true,
+		InternalGRPC:    true,
 	}
 	if v := os.Getenv("FEATURE_INTERNAL_GRPC"); v != "" {
 		switch v {
@@ -53,6 +79,24 @@ func loadAgentConfig() (*AgentConfig, error) {
 			cfg.CursorMode = false
 		}
 	}
+	if v := os.Getenv("DUCKDB_EXTENSION_ALLOWLIST"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cfg.ExtensionAllowlist = append(cfg.ExtensionAllowlist, name)
+			}
+		}
+	}
+	if v := os.Getenv("SQL_POLICY_FILE"); v != "" {
+		if err := loadSQLPolicyFile(cfg, v); err != nil {
+			return nil, fmt.Errorf("invalid SQL_POLICY_FILE: %w", err)
+		}
+	}
+	// Patterns are newline-separated because regexes routinely contain commas.
+	cfg.SQLAllowPatterns = append(cfg.SQLAllowPatterns, splitLines(os.Getenv("SQL_ALLOW_PATTERNS"))...)
+	cfg.SQLDenyPatterns = append(cfg.SQLDenyPatterns, splitLines(os.Getenv("SQL_DENY_PATTERNS"))...)
+	if _, err := agent.NewSQLPolicy(cfg.SQLAllowPatterns, cfg.SQLDenyPatterns); err != nil {
+		return nil, fmt.Errorf("invalid SQL policy: %w", err)
+	}
 	if v := os.Getenv("MAX_MEMORY_GB"); v != "" {
 		n, err := strconv.Atoi(v)
 		if err != nil {
@@ -60,6 +104,27 @@ func loadAgentConfig() (*AgentConfig, error) {
 		}
 		cfg.MaxMemoryGB = n
 	}
+	if v := os.Getenv("MAX_CONCURRENT_QUERIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_CONCURRENT_QUERIES: %w", err)
+		}
+		cfg.MaxConcurrentQueries = n
+	}
+	if v := os.Getenv("MAX_QUEUED_QUERIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_QUEUED_QUERIES: %w", err)
+		}
+		cfg.MaxQueuedQueries = n
+	}
+	if v := os.Getenv("QUERY_QUEUE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUERY_QUEUE_TIMEOUT: %w", err)
+		}
+		cfg.QueueTimeout = d
+	}
 	if v := os.Getenv("QUERY_RESULT_TTL"); v != "" {
 		d, err := time.ParseDuration(v)
 		if err != nil {
@@ -77,6 +142,12 @@ func loadAgentConfig() (*AgentConfig, error) {
 	if cfg.AgentToken == "" {
 		return nil, fmt.Errorf("AGENT_TOKEN is required")
 	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, fmt.Errorf("both TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "" {
+		return nil, fmt.Errorf("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
 	if cfg.ListenAddr == "" {
 		cfg.ListenAddr = ":9443"
 	}
@@ -92,5 +163,52 @@ func loadAgentConfig() (*AgentConfig, error) {
 	if cfg.CleanupInterval <= 0 {
 		cfg.CleanupInterval = 1 * time.Minute
 	}
+	if cfg.QueueTimeout <= 0 {
+		cfg.QueueTimeout = 30 * time.Second
+	}
 	return cfg, nil
 }
+
+// loadSQLPolicyFile reads allow/deny patterns from path. Each non-blank line
+// is "allow <regex>" or "deny <regex>"; lines starting with # are comments.
+func loadSQLPolicyFile(cfg *AgentConfig, path string) error {
+	f, err := os.Open(path) //nolint:gosec // path comes from operator configuration
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kind, pattern, _ := strings.Cut(line, " ")
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			return fmt.Errorf("line %d: missing pattern", lineNo)
+		}
+		switch kind {
+		case "allow":
+			cfg.SQLAllowPatterns = append(cfg.SQLAllowPatterns, pattern)
+		case "deny":
+			cfg.SQLDenyPatterns = append(cfg.SQLDenyPatterns, pattern)
+		default:
+			return fmt.Errorf("line %d: expected \"allow\" or \"deny\", got %q", lineNo, kind)
+		}
+	}
+	return scanner.Err()
+}
+
+func splitLines(v string) []string {
+	var out []string
+	for _, line := range strings.Split(v, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -29,10 +31,14 @@ func TestLoadAgentConfig(t *testing.T) {
 		t.Setenv("MAX_MEMORY_GB", "")
 		t.Setenv("QUERY_RESULT_TTL", "")
 		t.Setenv("QUERY_CLEANUP_INTERVAL", "")
+		t.Setenv("DUCKDB_EXTENSION_ALLOWLIST", "")
+		t.Setenv("MAX_CONCURRENT_QUERIES", "")
+		t.Setenv("QUERY_QUEUE_TIMEOUT", "")
 
 		cfg, err := loadAgentConfig()
 		require.NoError(t, err)
 		assert.Equal(t, "test-token", cfg.AgentToken)
+		assert.Empty(t, cfg.ExtensionAllowlist)
 		assert.Equal(t, ":9443", cfg.ListenAddr) // default
 		assert.Equal(t, ":9444", cfg.GRPCListenAddr)
 		assert.Equal(t, "duck-demo", cfg.S3Bucket) // default
@@ -41,6 +47,8 @@ func TestLoadAgentConfig(t *testing.T) {
 		assert.Equal(t, 1*time.Minute, cfg.CleanupInterval)
 		assert.True(t, cfg.CursorMode)
 		assert.True(t, cfg.InternalGRPC)
+		assert.Equal(t, 0, cfg.MaxConcurrentQueries)
+		assert.Equal(t, 30*time.Second, cfg.QueueTimeout)
 	})
 
 	t.Run("custom_values", func(t *testing.T) {
@@ -57,6 +65,10 @@ func TestLoadAgentConfig(t *testing.T) {
 		t.Setenv("QUERY_RESULT_TTL", "30m")
 		t.Setenv("QUERY_CLEANUP_INTERVAL", "45s")
 		t.Setenv("FEATURE_INTERNAL_GRPC", "true")
+		t.Setenv("DUCKDB_EXTENSION_ALLOWLIST", "ducklake, httpfs,postgres")
+		t.Setenv("MAX_CONCURRENT_QUERIES", "4")
+		t.Setenv("MAX_QUEUED_QUERIES", "16")
+		t.Setenv("QUERY_QUEUE_TIMEOUT", "5s")
 
 		cfg, err := loadAgentConfig()
 		require.NoError(t, err)
@@ -72,6 +84,10 @@ func TestLoadAgentConfig(t *testing.T) {
 		assert.Equal(t, 64, cfg.MaxMemoryGB)
 		assert.Equal(t, 30*time.Minute, cfg.QueryResultTTL)
 		assert.Equal(t, 45*time.Second, cfg.CleanupInterval)
+		assert.Equal(t, []string{"ducklake", "httpfs", "postgres"}, cfg.ExtensionAllowlist)
+		assert.Equal(t, 4, cfg.MaxConcurrentQueries)
+		assert.Equal(t, 16, cfg.MaxQueuedQueries)
+		assert.Equal(t, 5*time.Second, cfg.QueueTimeout)
 		assert.True(t, cfg.CursorMode)
 		assert.True(t, cfg.InternalGRPC)
 	})
@@ -96,6 +112,26 @@ func TestLoadAgentConfig(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid MAX_MEMORY_GB")
 	})
 
+	t.Run("invalid_max_concurrent_queries", func(t *testing.T) {
+		t.Setenv("AGENT_TOKEN", "tok")
+		t.Setenv("MAX_CONCURRENT_QUERIES", "many")
+
+		_, err := loadAgentConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid MAX_CONCURRENT_QUERIES")
+	})
+
+	t.Run("tls_client_ca_requires_server_cert", func(t *testing.T) {
+		t.Setenv("AGENT_TOKEN", "tok")
+		t.Setenv("TLS_CERT_FILE", "")
+		t.Setenv("TLS_KEY_FILE", "")
+		t.Setenv("TLS_CLIENT_CA_FILE", "/etc/duck/ca.crt")
+
+		_, err := loadAgentConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TLS_CLIENT_CA_FILE requires")
+	})
+
 	t.Run("invalid_query_result_ttl", func(t *testing.T) {
 		t.Setenv("AGENT_TOKEN", "tok")
 		t.Setenv("QUERY_RESULT_TTL", "bogus")
@@ -114,3 +150,52 @@ func TestLoadAgentConfig(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid QUERY_CLEANUP_INTERVAL")
 	})
 }
+
+func TestLoadAgentConfig_SQLPolicy(t *testing.T) {
+	t.Run("env_patterns", func(t *testing.T) {
+		t.Setenv("AGENT_TOKEN", "tok")
+		t.Setenv("SQL_POLICY_FILE", "")
+		t.Setenv("SQL_ALLOW_PATTERNS", "(?i)^SELECT\\b\n(?i)^WITH\\b\n")
+		t.Setenv("SQL_DENY_PATTERNS", "(?i)^DROP\\b")
+
+		cfg, err := loadAgentConfig()
+		require.NoError(t, err)
+		assert.Equal(t, []string{`(?i)^SELECT\b`, `(?i)^WITH\b`}, cfg.SQLAllowPatterns)
+		assert.Equal(t, []string{`(?i)^DROP\b`}, cfg.SQLDenyPatterns)
+	})
+
+	t.Run("policy_file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sql-policy")
+		require.NoError(t, os.WriteFile(path, []byte("# read-only agent\nallow (?i)^SELECT\\b\n\ndeny (?i)read_csv\n"), 0o600))
+		t.Setenv("AGENT_TOKEN", "tok")
+		t.Setenv("SQL_POLICY_FILE", path)
+		t.Setenv("SQL_ALLOW_PATTERNS", "")
+		t.Setenv("SQL_DENY_PATTERNS", "(?i)^DROP\\b")
+
+		cfg, err := loadAgentConfig()
+		require.NoError(t, err)
+		assert.Equal(t, []string{`(?i)^SELECT\b`}, cfg.SQLAllowPatterns)
+		assert.Equal(t, []string{`(?i)read_csv`, `(?i)^DROP\b`}, cfg.SQLDenyPatterns)
+	})
+
+	t.Run("invalid_policy_file_line", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sql-policy")
+		require.NoError(t, os.WriteFile(path, []byte("block DROP\n"), 0o600))
+		t.Setenv("AGENT_TOKEN", "tok")
+		t.Setenv("SQL_POLICY_FILE", path)
+
+		_, err := loadAgentConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid SQL_POLICY_FILE")
+	})
+
+	t.Run("invalid_pattern", func(t *testing.T) {
+		t.Setenv("AGENT_TOKEN", "tok")
+		t.Setenv("SQL_POLICY_FILE", "")
+		t.Setenv("SQL_DENY_PATTERNS", "(")
+
+		_, err := loadAgentConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid SQL policy")
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -19,8 +20,11 @@ import (
 
 	"duck-demo/internal/agent"
 	"duck-demo/internal/compute"
+	"duck-demo/internal/engine"
+	"duck-demo/internal/tracing"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
@@ -42,6 +46,12 @@ func run() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
 
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{Endpoint: cfg.OTLPEndpoint, ServiceName: "duck-compute-agent"})
+	if err != nil {
+		return fmt.Errorf("tracing: %w", err)
+	}
+	defer func() { _ = shutdownTracing(context.Background()) }()
+
 	// Open DuckDB in-memory
 	db, err := sql.Open("duckdb", "")
 	if err != nil {
@@ -57,18 +67,18 @@ func run() error {
 		logger.Info("memory limit set", "max_memory_gb", cfg.MaxMemoryGB)
 	}
 
-	// Install extensions
-	extensions := []string{
-		"INSTALL ducklake; LOAD ducklake;",
-		"INSTALL httpfs; LOAD httpfs;",
-		"INSTALL postgres; LOAD postgres;",
-	}
-	for _, ext := range extensions {
-		if _, err := db.ExecContext(ctx, ext); err != nil {
+	// Install extensions. Autoloading is disabled so queries cannot pull in
+	// extensions outside the allowlist.
+	allow := engine.NewExtensionAllowlist(cfg.ExtensionAllowlist)
+	for _, ext := range []string{"ducklake", "httpfs", "postgres"} {
+		if err := engine.LoadExtension(ctx, db, allow, ext); err != nil {
 			return fmt.Errorf("extension setup (%s): %w", ext, err)
 		}
 	}
-	logger.Info("DuckDB extensions installed")
+	if err := engine.RestrictExtensionAutoload(ctx, db); err != nil {
+		return err
+	}
+	logger.Info("DuckDB extensions installed", "allowlist", strings.Join(allow.Names(), ", "))
 
 	// Create S3 secret if configured
 	if cfg.S3KeyID != "" {
@@ -94,6 +104,14 @@ func run() error {
 		logger.Info("DuckLake attached via PostgreSQL", "catalog_dsn", "[redacted]", "data_path", dataPath)
 	}
 
+	sqlPolicy, err := agent.NewSQLPolicy(cfg.SQLAllowPatterns, cfg.SQLDenyPatterns)
+	if err != nil {
+		return fmt.Errorf("sql policy: %w", err)
+	}
+	if sqlPolicy != nil {
+		logger.Info("SQL statement policy enabled", "allow_patterns", len(cfg.SQLAllowPatterns), "deny_patterns", len(cfg.SQLDenyPatterns))
+	}
+
 	handlerCfg := agent.HandlerConfig{
 		DB:              db,
 		AgentToken:      cfg.AgentToken,
@@ -102,7 +120,12 @@ func run() error {
 		QueryResultTTL:  cfg.QueryResultTTL,
 		CleanupInterval: cfg.CleanupInterval,
 		CursorMode:      cfg.CursorMode,
+		SQLPolicy:       sqlPolicy,
 		Logger:          logger,
+
+		MaxConcurrentQueries: cfg.MaxConcurrentQueries,
+		MaxQueuedQueries:     cfg.MaxQueuedQueries,
+		QueueTimeout:         cfg.QueueTimeout,
 	}
 	handler := agent.NewHandler(handlerCfg)
 
@@ -110,7 +133,16 @@ func run() error {
 	var grpcCompute *agent.ComputeGRPCServer
 	if cfg.InternalGRPC {
 		compute.EnsureGRPCJSONCodec()
-		grpcServer = grpc.NewServer()
+		var grpcOpts []grpc.ServerOption
+		if cfg.TLSCertFile != "" {
+			tlsCfg, err := compute.ServerTLSConfig(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+			if err != nil {
+				return fmt.Errorf("grpc tls: %w", err)
+			}
+			grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+			logger.Info("gRPC TLS enabled", "mutual_tls", cfg.TLSClientCAFile != "")
+		}
+		grpcServer = grpc.NewServer(grpcOpts...)
 		grpcCompute = agent.NewComputeGRPCServer(handlerCfg)
 		agent.RegisterComputeWorkerGRPCServer(grpcServer, grpcCompute)
 		handler = agent.NewHandler(agent.HandlerConfig{
@@ -123,6 +155,7 @@ func run() error {
 			CursorMode:      cfg.CursorMode,
 			MetricsProvider: grpcCompute.Metrics,
 			Logger:          logger,
+			LoadProvider:    grpcCompute.Load,
 		})
 
 		grpcLn, err := (&net.ListenConfig{}).Listen(context.Background(), "tcp", cfg.GRPCListenAddr)
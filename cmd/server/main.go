@@ -19,7 +19,6 @@ import (
 	_ "github.com/duckdb/duckdb-go/v2"
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	_ "github.com/mattn/go-sqlite3"
 
 	"duck-demo/internal/api"
@@ -29,11 +28,42 @@ import (
 	"duck-demo/internal/domain"
 	"duck-demo/internal/engine"
 	"duck-demo/internal/flightsql"
+	"duck-demo/internal/metrics"
 	"duck-demo/internal/middleware"
 	"duck-demo/internal/pgwire"
+	"duck-demo/internal/service/webhook"
+	"duck-demo/internal/tracing"
 	"duck-demo/internal/ui"
 )
 
+// version is the release the server was built as, set with
+// -ldflags "-X main.version=...".
+var version = "dev"
+
+// grantExpiryReapInterval is how often expired grants are deleted. Privilege
+// checks, including memoized ones, stop honoring a grant when it expires.
+const grantExpiryReapInterval = 30 * time.Second
+
+// queryJobReapInterval is how often finished async query jobs past
+// QUERY_JOB_TTL are deleted. Expired jobs are hidden from lookups already.
+const queryJobReapInterval = 5 * time.Minute
+
+// queryHistoryReapInterval is how often query history past
+// QUERY_HISTORY_RETENTION_DAYS is purged.
+const queryHistoryReapInterval = time.Hour
+
+// auditArchiveReapInterval is how often audit log entries past
+// AUDIT_RETENTION_DAYS are archived and deleted.
+const auditArchiveReapInterval = time.Hour
+
+// webhookDrainTimeout bounds how long shutdown waits for in-flight webhook
+// deliveries and their retries after the HTTP server has drained.
+const webhookDrainTimeout = 30 * time.Second
+
+// computeLoadPollInterval is how often remote compute agents are polled for
+// load. It bounds how stale the status in GET /v1/compute-endpoints can be.
+const computeLoadPollInterval = 30 * time.Second
+
 func main() {
 	// Handle admin subcommands before starting the server.
 	if len(os.Args) >= 2 && os.Args[1] == "admin" {
@@ -69,17 +99,31 @@ func run() error {
 		}
 	}
 
-	// Create structured logger
-	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
-		Level: cfg.SlogLevel(),
-	}))
+	// Create structured logger: text for local dev, JSON (LOG_FORMAT=json,
+	// the production default) for log pipelines.
+	logger := cfg.NewLogger(os.Stderr)
 	slog.SetDefault(logger)
+	logger.Info("starting server", "version", version, "env", cfg.Env, "log_level", cfg.LogLevel, "log_format", cfg.LogFormat)
 
 	// Replay config warnings (generated before logger existed)
 	for _, w := range cfg.Warnings {
 		logger.Warn("config warning", "detail", w)
 	}
 
+	// List endpoints serve DEFAULT_PAGE_SIZE rows unless asked otherwise and
+	// never more than MAX_PAGE_SIZE.
+	domain.SetPageSizeLimits(cfg.DefaultPageSize, cfg.MaxPageSize)
+
+	// Tracing is a no-op unless OTEL_EXPORTER_OTLP_ENDPOINT is set.
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{Endpoint: cfg.OTLPEndpoint, ServiceName: "duck-server"})
+	if err != nil {
+		return fmt.Errorf("tracing: %w", err)
+	}
+	defer func() { _ = shutdownTracing(context.Background()) }()
+	if cfg.OTLPEndpoint != "" {
+		logger.Info("OpenTelemetry tracing enabled", "endpoint", cfg.OTLPEndpoint)
+	}
+
 	// Open DuckDB (in-memory)
 	duckDB, err := sql.Open("duckdb", "")
 	if err != nil {
@@ -87,35 +131,91 @@ func run() error {
 	}
 	defer duckDB.Close() //nolint:errcheck
 
-	// Install DuckLake extensions (no credentials needed)
-	if err := engine.InstallExtensions(ctx, duckDB); err != nil {
+	// Install DuckLake extensions (no credentials needed). Autoloading is
+	// disabled so queries cannot pull in extensions outside the allowlist.
+	extensions := engine.NewExtensionAllowlist(cfg.DuckDBExtensionAllowlist)
+	if err := engine.InstallExtensionsWithAllowlist(ctx, duckDB, extensions); err != nil {
 		return fmt.Errorf("install extensions: %w", err)
 	}
-	logger.Info("DuckDB extensions installed", "extensions", "ducklake, sqlite, httpfs")
+	if err := engine.RestrictExtensionAutoload(ctx, duckDB); err != nil {
+		return err
+	}
+	logger.Info("DuckDB extensions installed", "extensions", []string{"ducklake", "sqlite", "httpfs"},
+		"allowlist", extensions.Names())
 
 	// Open SQLite metastore with hardened connection settings.
 	// writeDB: single-connection pool for serialized writes (WAL + txlock=immediate).
-	// readDB:  4-connection pool for concurrent reads (WAL, no txlock).
-	writeDB, readDB, err := internaldb.OpenSQLitePair(cfg.MetaDBPath, 4)
+	// readDB:  META_DB_READ_POOL_SIZE-connection pool for concurrent reads (WAL, no txlock).
+	writeDB, readDB, err := internaldb.OpenSQLitePairWithOptions(cfg.MetaDBPath, internaldb.SQLiteOptions{
+		ReadMaxOpen:  cfg.MetaDBReadPoolSize,
+		BusyTimeout:  cfg.MetaDBBusyTimeout,
+		CacheSizeKiB: cfg.MetaDBCacheSizeKiB,
+	})
 	if err != nil {
 		return fmt.Errorf("open metastore: %w", err)
 	}
 	defer writeDB.Close() //nolint:errcheck
 	defer readDB.Close()  //nolint:errcheck
+	go internaldb.WatchPoolContention(ctx, "metastore_write", writeDB, logger, 30*time.Second)
+	go internaldb.WatchPoolContention(ctx, "metastore_read", readDB, logger, 30*time.Second)
+
+	// Optional query-only pool on a replica kept in sync outside the server
+	// (META_DB_READ_PATH). Only stale-tolerant reads use it.
+	var replicaDB *sql.DB
+	if cfg.MetaDBReadPath != "" {
+		replicaDB, err = internaldb.OpenSQLiteWithOptions(cfg.MetaDBReadPath, "replica", internaldb.SQLiteOptions{
+			ReadMaxOpen:  cfg.MetaDBReadPoolSize,
+			BusyTimeout:  cfg.MetaDBBusyTimeout,
+			CacheSizeKiB: cfg.MetaDBCacheSizeKiB,
+		})
+		if err != nil {
+			return fmt.Errorf("open metastore replica: %w", err)
+		}
+		defer replicaDB.Close() //nolint:errcheck
+		go internaldb.WatchPoolContention(ctx, "metastore_replica", replicaDB, logger, 30*time.Second)
+		logger.Info("metastore read replica enabled", "path", cfg.MetaDBReadPath, "pool_size", cfg.MetaDBReadPoolSize)
+	}
 
-	// Run migrations on the write pool (DDL requires write access)
-	logger.Info("running catalog migrations")
-	if err := internaldb.RunMigrations(writeDB); err != nil {
-		return fmt.Errorf("migration: %w", err)
+	// Run migrations on the write pool (DDL requires write access). With
+	// MIGRATE_ON_START=false the operator applies them via
+	// `duck admin migrate up`; startup still refuses a drifted schema.
+	migrator := internaldb.NewMigrator(writeDB)
+	if cfg.MigrateOnStart {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return fmt.Errorf("migration status: %w", err)
+		}
+		statuses, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("migration: %w", err)
+		}
+		var schemaVersion int64
+		if len(statuses) > 0 {
+			schemaVersion = statuses[len(statuses)-1].Version
+		}
+		logger.Info("metastore migrations applied", "applied", pending, "schema_version", schemaVersion)
+	} else {
+		if err := migrator.Verify(ctx); err != nil {
+			return fmt.Errorf("migration: %w", err)
+		}
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return fmt.Errorf("migration status: %w", err)
+		}
+		if pending > 0 {
+			logger.Warn("metastore has pending migrations; run `duck admin migrate up`", "pending", pending)
+		}
 	}
 
 	// Wire application dependencies
 	application, err := app.New(ctx, app.Deps{
-		Cfg:     cfg,
-		DuckDB:  duckDB,
-		WriteDB: writeDB,
-		ReadDB:  readDB,
-		Logger:  logger,
+		Cfg:       cfg,
+		DuckDB:    duckDB,
+		WriteDB:   writeDB,
+		ReadDB:    readDB,
+		ReplicaDB: replicaDB,
+		Logger:    logger,
+		Version:   version,
 	})
 	if err != nil {
 		return fmt.Errorf("app init: %w", err)
@@ -159,8 +259,13 @@ func run() error {
 		}
 	}()
 
-	// Attach all registered catalogs (concurrent, bounded parallelism)
+	// Attach all registered catalogs (concurrent, bounded parallelism, with
+	// retry). Catalogs that still fail are marked unavailable; with
+	// REQUIRE_CATALOG=true the server refuses to start instead.
 	if err := application.Services.CatalogRegistration.AttachAll(ctx); err != nil {
+		if cfg.RequireCatalog {
+			return fmt.Errorf("catalog attach (REQUIRE_CATALOG=true): %w", err)
+		}
 		logger.Warn("catalog AttachAll failed", "error", err)
 	}
 
@@ -172,6 +277,19 @@ func run() error {
 
 	// Create API handler.
 	svc := application.Services
+
+	// Prometheus metrics (bounded labels: route patterns, never principals).
+	var metricsReg *metrics.Metrics
+	if cfg.MetricsEnabled {
+		metricsReg = metrics.New()
+		metricsReg.RegisterDBPool("metastore_write", writeDB)
+		metricsReg.RegisterDBPool("metastore_read", readDB)
+		if replicaDB != nil {
+			metricsReg.RegisterDBPool("metastore_replica", replicaDB)
+		}
+		metricsReg.RegisterActiveSessions(svc.SessionManager.ActiveSessions)
+		svc.Query.SetMetrics(metricsReg)
+	}
 	handler := api.NewHandler(
 		svc.Query, svc.Principal, svc.Group, svc.Grant,
 		svc.RowFilter, svc.ColumnMask, svc.Audit,
@@ -189,39 +307,59 @@ func run() error {
 		svc.Model,
 		svc.Macro,
 		svc.Semantic,
+		svc.ApplyLock,
+		svc.Webhook,
+		svc.Migration,
+		svc.Backup,
+		svc.Version,
+		svc.Extension,
+		svc.Authorization,
+		svc.DefaultGrant,
+		svc.Approval,
+		svc.Seed,
+		svc.SearchIndex,
 	)
 
 	// Create strict handler wrapper
-	strictHandler := api.NewStrictHandler(handler, nil)
+	strictHandler := api.NewStrictHandlerWithOptions(handler, nil, api.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  api.WriteRequestError,
+		ResponseErrorHandlerFunc: api.WriteResponseError,
+	})
 
 	// Setup Chi router
 	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
 	r.Use(middleware.RequestID)
+	if metricsReg != nil {
+		r.Use(metricsReg.Middleware())
+	}
 	r.Use(chimw.Logger)
 	r.Use(chimw.Recoverer)
-	r.Use(cors.Handler(cors.Options{
+	r.Use(middleware.CORS(middleware.CORSConfig{
 		AllowedOrigins:   cfg.CORSAllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
-		AllowCredentials: false,
-		MaxAge:           300,
+		AllowedMethods:   cfg.CORSAllowedMethods,
+		AllowedHeaders:   cfg.CORSAllowedHeaders,
+		AllowCredentials: cfg.CORSAllowCredentials,
 	}))
 	r.Use(middleware.RateLimiter(middleware.RateLimitConfig{
 		RequestsPerSecond: cfg.RateLimitRPS,
 		Burst:             cfg.RateLimitBurst,
 	}))
+	r.Use(middleware.Compress(middleware.CompressConfig{MinSize: cfg.CompressionMinSize}))
+	// Every JSON error gets a catalog error_code; inside Compress so the body
+	// is still plain JSON.
+	r.Use(middleware.ErrorCodes)
 
 	// Consistent JSON error responses for unknown routes and wrong methods
 	r.NotFound(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 404, "message": "not found"})
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 404, "error_code": domain.ErrorCodeNotFound, "message": "not found"})
 	})
 	r.MethodNotAllowed(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusMethodNotAllowed)
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 405, "message": "method not allowed"})
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 405, "error_code": domain.ErrorCodeMethodNotAllowed, "message": "method not allowed"})
 	})
 
 	// Health check endpoint — no auth required, used by load balancers / K8s probes
@@ -232,15 +370,7 @@ func run() error {
 	})
 
 	// Public endpoints — no auth required
-	r.Get("/openapi.json", func(w http.ResponseWriter, _ *http.Request) {
-		swagger, err := api.GetSwagger()
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(swagger)
-	})
+	r.Get("/openapi.json", api.ServeOpenAPISpec)
 
 	r.Get("/docs", func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "text/html")
@@ -291,15 +421,43 @@ func run() error {
 		cfg.Auth,
 		logger,
 	)
+	if metricsReg != nil {
+		authenticator.SetFailureRecorder(metricsReg)
+		if cfg.MetricsAuthRequired {
+			r.With(authenticator.Middleware()).Method(http.MethodGet, "/metrics", metricsReg.Handler())
+		} else {
+			r.Method(http.MethodGet, "/metrics", metricsReg.Handler())
+		}
+	}
+
+	// Idempotency keys are scoped per principal, so this runs after auth.
+	idempotency := middleware.Idempotency(middleware.IdempotencyConfig{TTL: cfg.IdempotencyKeyTTL})
+	// Refused requests are audited as ACCESS_DENIED; also runs after auth so
+	// the denied principal is known.
+	denialAudit := middleware.DenialAudit(application.DenialAuditor)
+	// ?fields= projection is validated against the documented responses.
+	swagger, err := api.GetSwagger()
+	if err != nil {
+		return fmt.Errorf("load openapi spec: %w", err)
+	}
+	fields := middleware.Fields(middleware.FieldsFromSpec(swagger, "/v1"))
 	if cfg.IsProduction() {
 		r.Route("/v1", func(r chi.Router) {
 			r.Use(authenticator.Middleware())
-			api.HandlerFromMux(strictHandler, r)
+			r.Use(denialAudit)
+			r.Use(idempotency)
+			r.Use(middleware.PageSize)
+			r.Use(fields)
+			api.HandlerWithOptions(strictHandler, api.ChiServerOptions{BaseRouter: r, ErrorHandlerFunc: api.WriteRequestError})
 		})
 	} else {
 		logger.Warn("development mode: API auth disabled for /v1")
 		r.Route("/v1", func(r chi.Router) {
-			api.HandlerFromMux(strictHandler, r)
+			r.Use(denialAudit)
+			r.Use(idempotency)
+			r.Use(middleware.PageSize)
+			r.Use(fields)
+			api.HandlerWithOptions(strictHandler, api.ChiServerOptions{BaseRouter: r, ErrorHandlerFunc: api.WriteRequestError})
 		})
 	}
 
@@ -339,8 +497,27 @@ func run() error {
 	// Start session reaper
 	go application.Services.SessionManager.ReapIdle(ctx)
 
-	// Graceful shutdown: wait for SIGTERM/SIGINT, then drain connections.
+	// Purge expired grants; privilege checks already ignore them.
+	go application.Services.Grant.ReapExpired(ctx, grantExpiryReapInterval)
+
+	// Delete finished async query jobs past their retention TTL.
+	go application.Services.Query.ReapExpiredJobs(ctx, queryJobReapInterval)
+
+	// Delete query history past its retention period, if one is configured.
+	go application.Services.QueryHistory.ReapExpired(ctx, queryHistoryReapInterval)
+
+	// Archive and delete audit log entries past their retention period, if
+	// archiving is configured.
+	go application.Services.Audit.ReapExpired(ctx, auditArchiveReapInterval)
+
+	// Record remote compute agent load for endpoint status and scaling hints.
+	go application.Services.ComputeEndpoint.MonitorLoad(ctx, computeLoadPollInterval)
+
+	// Graceful shutdown: wait for SIGTERM/SIGINT, then drain connections and
+	// pending webhook deliveries. run returns only once this has finished.
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
 		<-ctx.Done()
 		logger.Info("shutting down server")
 		if pgWireServer != nil {
@@ -357,6 +534,7 @@ func run() error {
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer shutdownCancel()
 		_ = srv.Shutdown(shutdownCtx)
+		waitForWebhooks(application.WebhookDispatcher, webhookDrainTimeout, logger)
 	}()
 
 	if cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
@@ -364,15 +542,35 @@ func run() error {
 		if err := srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			return fmt.Errorf("server: %w", err)
 		}
+		<-shutdownDone
 		return nil
 	}
 
 	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("server: %w", err)
 	}
+	<-shutdownDone
 	return nil
 }
 
+// waitForWebhooks blocks until the dispatcher's in-flight deliveries finish or
+// timeout elapses, whichever comes first.
+func waitForWebhooks(d *webhook.Dispatcher, timeout time.Duration, logger *slog.Logger) {
+	if d == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		d.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		logger.Warn("webhook deliveries still pending at shutdown", "timeout", timeout)
+	}
+}
+
 func curlHostForListenAddr(listenAddr string) string {
 	trimmed := strings.TrimSpace(listenAddr)
 	if host, port, err := net.SplitHostPort(trimmed); err == nil {
@@ -463,7 +661,7 @@ func runAdmin(args []string) error {
 		// Create the principal with the desired admin status.
 		id := domain.NewID()
 		_, err = db.ExecContext(context.Background(),
-			"INSERT INTO principals (id, name, type, is_admin) VALUES (?, ?, 'user', ?)",
+			"INSERT INTO principals (id, name, type, is_admin, updated_at) VALUES (?, ?, 'user', ?, datetime('now'))",
 			id, principalName, isAdmin)
 		if err != nil {
 			return fmt.Errorf("create principal: %w", err)
@@ -474,7 +672,7 @@ func runAdmin(args []string) error {
 
 	// Principal exists — update admin status.
 	_, err = db.ExecContext(context.Background(),
-		"UPDATE principals SET is_admin = ? WHERE name = ?",
+		"UPDATE principals SET is_admin = ?, updated_at = datetime('now') WHERE name = ?",
 		isAdmin, principalName)
 	if err != nil {
 		return fmt.Errorf("update principal: %w", err)
@@ -40,7 +40,7 @@ DUCK_HOST="$DUCK_HOST" "$ROOT_DIR/examples/showcase-movielens/scripts/ingest_see
 echo "Running transformation models"
 MODEL_NAMES="bronze_movies,bronze_users,bronze_ratings,silver_movies,silver_users,silver_ratings_enriched,gold_movie_scores,gold_user_engagement"
 if command -v jq >/dev/null 2>&1; then
-  RUN_JSON="$(duck --output json models model-runs trigger-model-run --project-name movielens --model-names "$MODEL_NAMES" --target-catalog lake --target-schema main)"
+  RUN_JSON="$(duck --output json models run --project-name movielens --model-names "$MODEL_NAMES" --target-catalog lake --target-schema main)"
   RUN_ID="$(printf "%s" "$RUN_JSON" | jq -r '.id')"
   if [[ -z "$RUN_ID" || "$RUN_ID" == "null" ]]; then
     echo "failed to parse model run id" >&2
@@ -70,7 +70,7 @@ if command -v jq >/dev/null 2>&1; then
     exit 1
   fi
 else
-  duck models model-runs trigger-model-run --project-name movielens --model-names "$MODEL_NAMES" --target-catalog lake --target-schema main
+  duck models run --project-name movielens --model-names "$MODEL_NAMES" --target-catalog lake --target-schema main
   sleep 3
 fi
 
@@ -2,13 +2,17 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync/atomic"
 	"time"
 
 	"duck-demo/internal/compute"
 	computeproto "duck-demo/internal/compute/proto"
+	"duck-demo/internal/tracing"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -20,6 +24,7 @@ type ComputeGRPCServer struct {
 
 	activeQueries atomic.Int64
 	jobs          *queryStore
+	limiter       *queryLimiter
 }
 
 type computeWorkerAdapter struct {
@@ -33,7 +38,11 @@ var _ computeproto.ComputeWorkerServer = (*computeWorkerAdapter)(nil)
 func NewComputeGRPCServer(cfg HandlerConfig) *ComputeGRPCServer {
 	jobs := newQueryStore(cfg.QueryResultTTL, cfg.CleanupInterval)
 	jobs.db = cfg.DB
-	return &ComputeGRPCServer{cfg: cfg, jobs: jobs}
+	return &ComputeGRPCServer{
+		cfg:     cfg,
+		jobs:    jobs,
+		limiter: newQueryLimiter(cfg.MaxConcurrentQueries, cfg.MaxQueuedQueries, cfg.QueueTimeout),
+	}
 }
 
 func (s *ComputeGRPCServer) Metrics() (active, queued, running, completed, stored, cleaned int64) {
@@ -41,12 +50,41 @@ func (s *ComputeGRPCServer) Metrics() (active, queued, running, completed, store
 	return s.activeQueries.Load(), queued, running, completed, stored, cleaned
 }
 
+// Load reports the concurrency limit (0 when unlimited) and how many
+// statements currently hold or wait for a slot.
+func (s *ComputeGRPCServer) Load() (limit, running, waiting int64) {
+	return s.limiter.load()
+}
+
+// acquireSlot waits for a concurrency slot, mapping a full or stalled queue
+// to ResourceExhausted so callers back off.
+func (s *ComputeGRPCServer) acquireSlot(ctx context.Context) (func(), error) {
+	release, err := s.limiter.acquire(ctx)
+	if err == nil {
+		return release, nil
+	}
+	if errors.Is(err, errQueueFull) || errors.Is(err, errQueueTimeout) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return nil, status.FromContextError(err).Err()
+}
+
 // RegisterComputeWorkerGRPCServer registers ComputeWorker gRPC methods.
 func RegisterComputeWorkerGRPCServer(registrar grpc.ServiceRegistrar, server *ComputeGRPCServer) {
 	computeproto.RegisterComputeWorkerServer(registrar, &computeWorkerAdapter{server: server})
 }
 
-func (a *computeWorkerAdapter) Execute(ctx context.Context, req *computeproto.ExecuteRequest) (*computeproto.ExecuteResponse, error) {
+func (a *computeWorkerAdapter) Execute(ctx context.Context, req *computeproto.ExecuteRequest) (_ *computeproto.ExecuteResponse, err error) {
+	var reqCtx *computeproto.RequestContext
+	if req != nil {
+		reqCtx = req.Context
+	}
+	ctx, span := startServerSpan(ctx, "agent.Execute", reqCtx)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	if err := a.server.authorize(ctx); err != nil {
 		return nil, err
 	}
@@ -54,9 +92,24 @@ func (a *computeWorkerAdapter) Execute(ctx context.Context, req *computeproto.Ex
 		return nil, status.Error(codes.InvalidArgument, "request is required")
 	}
 
+	if err := a.server.cfg.SQLPolicy.Check(req.Sql); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	release, err := a.server.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	requestID := requestIDFromContext(req.Context)
-	result, err := runQuery(ctx, a.server.cfg.DB, req.Sql, &a.server.activeQueries)
+	queryCtx, cancel := withQueryTimeout(ctx, req.TimeoutSeconds)
+	defer cancel()
+	result, err := runQuery(queryCtx, a.server.cfg.DB, req.Sql, &a.server.activeQueries)
 	if err != nil {
+		if queryTimedOut(queryCtx, req.TimeoutSeconds) {
+			return nil, status.Error(codes.DeadlineExceeded, timeoutError(req.TimeoutSeconds).Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -72,7 +125,17 @@ func (a *computeWorkerAdapter) Execute(ctx context.Context, req *computeproto.Ex
 	}, withRequestIDHeader(ctx, requestID)
 }
 
-func (a *computeWorkerAdapter) SubmitQuery(ctx context.Context, req *computeproto.SubmitQueryRequest) (*computeproto.SubmitQueryResponse, error) {
+func (a *computeWorkerAdapter) SubmitQuery(ctx context.Context, req *computeproto.SubmitQueryRequest) (_ *computeproto.SubmitQueryResponse, err error) {
+	var reqCtx *computeproto.RequestContext
+	if req != nil {
+		reqCtx = req.Context
+	}
+	ctx, span := startServerSpan(ctx, "agent.SubmitQuery", reqCtx)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	if err := a.server.authorize(ctx); err != nil {
 		return nil, err
 	}
@@ -82,6 +145,9 @@ func (a *computeWorkerAdapter) SubmitQuery(ctx context.Context, req *computeprot
 	if req == nil || req.Sql == "" {
 		return nil, status.Error(codes.InvalidArgument, "sql is required")
 	}
+	if err := a.server.cfg.SQLPolicy.Check(req.Sql); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
 
 	requestID := requestIDFromContext(req.Context)
 	a.server.jobs.maybeCleanup(time.Now())
@@ -90,6 +156,13 @@ func (a *computeWorkerAdapter) SubmitQuery(ctx context.Context, req *computeprot
 		return &computeproto.SubmitQueryResponse{QueryId: existing.id, Status: state.Status}, withRequestIDHeader(ctx, requestID)
 	}
 
+	// Queue for a slot before accepting the job so an overloaded agent pushes
+	// back on the submitter rather than accumulating unbounded work.
+	release, err := a.server.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	job := &queryJob{
 		id:        newQueryID(),
 		requestID: requestID,
@@ -98,9 +171,17 @@ func (a *computeWorkerAdapter) SubmitQuery(ctx context.Context, req *computeprot
 	}
 	a.server.jobs.set(job)
 
-	go func(sqlQuery string) {
-		jobCtx, cancel := context.WithCancel(context.Background())
+	// The job outlives the request, so it continues the trace from a fresh
+	// context rather than the request context.
+	parent := trace.SpanContextFromContext(ctx)
+	go func(sqlQuery string, timeoutSeconds int64) {
+		defer release()
+		jobCtx, cancel := context.WithCancel(trace.ContextWithSpanContext(context.Background(), parent))
 		job.setRunning(cancel)
+		jobCtx, stop := withQueryTimeout(jobCtx, timeoutSeconds)
+		defer stop()
+		jobCtx, jobSpan := tracing.Start(jobCtx, "agent.run_query", trace.WithAttributes(attribute.String("compute.query_id", job.id)))
+		defer jobSpan.End()
 
 		columns, tableName, rowCount, err := runQueryToTable(jobCtx, a.server.cfg.DB, sqlQuery, &a.server.activeQueries, job.id)
 		if err != nil {
@@ -108,11 +189,15 @@ func (a *computeWorkerAdapter) SubmitQuery(ctx context.Context, req *computeprot
 				job.setCanceled()
 				return
 			}
+			if queryTimedOut(jobCtx, timeoutSeconds) {
+				err = timeoutError(timeoutSeconds)
+			}
+			tracing.RecordError(jobSpan, err)
 			job.setFailed(err)
 			return
 		}
 		job.setSucceeded(columns, tableName, rowCount)
-	}(req.Sql)
+	}(req.Sql, req.TimeoutSeconds)
 
 	return &computeproto.SubmitQueryResponse{QueryId: job.id, Status: compute.QueryStatusQueued}, withRequestIDHeader(ctx, requestID)
 }
@@ -255,6 +340,7 @@ func (a *computeWorkerAdapter) Health(ctx context.Context, _ *computeproto.Healt
 
 	a.server.jobs.maybeCleanup(time.Now())
 	queuedJobs, runningJobs, completedJobs, storedJobs, cleanedJobs := a.server.jobs.metrics()
+	limit, _, waiting := a.server.Load()
 	resp := &computeproto.HealthResponse{
 		Status:        "ok",
 		UptimeSeconds: int64(time.Since(a.server.cfg.StartTime).Seconds()),
@@ -266,6 +352,9 @@ func (a *computeWorkerAdapter) Health(ctx context.Context, _ *computeproto.Healt
 		CleanedJobs:   cleanedJobs,
 		MaxMemoryGb:   int32(a.server.cfg.MaxMemoryGB),
 		ResultTtlSecs: int32(a.server.jobs.ttl.Seconds()),
+
+		MaxConcurrentQueries: limit,
+		WaitingQueries:       waiting,
 	}
 	return resp, nil
 }
@@ -292,6 +381,14 @@ func metadataValue(ctx context.Context, key string) string {
 	return values[0]
 }
 
+// startServerSpan continues the trace propagated in the incoming gRPC metadata.
+func startServerSpan(ctx context.Context, name string, reqCtx *computeproto.RequestContext) (context.Context, trace.Span) {
+	return tracing.Start(tracing.ExtractMetadata(ctx), name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("compute.request_id", requestIDFromContext(reqCtx))),
+	)
+}
+
 func requestIDFromContext(ctx *computeproto.RequestContext) string {
 	if ctx == nil {
 		return ""
@@ -33,8 +33,17 @@ type HandlerConfig struct {
 	QueryResultTTL  time.Duration
 	CleanupInterval time.Duration
 	CursorMode      bool
+	SQLPolicy       *SQLPolicy // nil allows every statement
 	MetricsProvider func() (active, queued, running, completed, stored, cleaned int64)
 	Logger          *slog.Logger
+
+	// MaxConcurrentQueries caps statements running at once; 0 means unlimited.
+	// Excess statements wait in a queue of up to MaxQueuedQueries for at most
+	// QueueTimeout before being rejected.
+	MaxConcurrentQueries int
+	MaxQueuedQueries     int
+	QueueTimeout         time.Duration
+	LoadProvider         func() (limit, running, waiting int64)
 }
 
 type queryJob struct {
@@ -316,6 +325,10 @@ func NewHandler(cfg HandlerConfig) http.Handler {
 		if cfg.MetricsProvider != nil {
 			activeQueries, queuedJobs, runningJobs, completedJobs, storedJobs, cleanedJobs = cfg.MetricsProvider()
 		}
+		var concurrencyLimit, waitingQueries int64
+		if cfg.LoadProvider != nil {
+			concurrencyLimit, _, waitingQueries = cfg.LoadProvider()
+		}
 
 		writeJSON(w, http.StatusOK, map[string]interface{}{
 			"status":                   "ok",
@@ -330,6 +343,8 @@ func NewHandler(cfg HandlerConfig) http.Handler {
 			"stored_jobs":              storedJobs,
 			"cleaned_jobs":             cleanedJobs,
 			"query_result_ttl_seconds": int(ttl.Seconds()),
+			"max_concurrent_queries":   concurrencyLimit,
+			"waiting_queries":          waitingQueries,
 		})
 	})
 
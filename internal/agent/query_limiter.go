@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const defaultQueueTimeout = 30 * time.Second
+
+var (
+	errQueueFull    = errors.New("query queue is full")
+	errQueueTimeout = errors.New("timed out waiting for a query slot")
+)
+
+// queryLimiter caps the number of statements running on the agent's DuckDB
+// at once. Statements beyond the cap wait in a bounded queue for up to
+// queueTimeout; once the queue is full, further statements are rejected so
+// callers can back off instead of piling onto an overloaded agent.
+type queryLimiter struct {
+	slots        chan struct{}
+	maxQueue     int64
+	queueTimeout time.Duration
+	waiting      atomic.Int64
+}
+
+// newQueryLimiter returns nil when maxConcurrent is not positive, which
+// disables limiting.
+func newQueryLimiter(maxConcurrent, maxQueue int, queueTimeout time.Duration) *queryLimiter {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+	if queueTimeout <= 0 {
+		queueTimeout = defaultQueueTimeout
+	}
+	return &queryLimiter{
+		slots:        make(chan struct{}, maxConcurrent),
+		maxQueue:     int64(max(maxQueue, 0)),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// acquire blocks until a slot is free and returns the func that releases it.
+func (l *queryLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+
+	if l.waiting.Add(1) > l.maxQueue {
+		l.waiting.Add(-1)
+		return nil, errQueueFull
+	}
+	defer l.waiting.Add(-1)
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("%w after %s", errQueueTimeout, l.queueTimeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *queryLimiter) release() {
+	<-l.slots
+}
+
+// load reports the configured limit, running statements and queued statements.
+func (l *queryLimiter) load() (limit, running, waiting int64) {
+	if l == nil {
+		return 0, 0, 0
+	}
+	return int64(cap(l.slots)), int64(len(l.slots)), l.waiting.Load()
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	computeproto "duck-demo/internal/compute/proto"
+)
+
+func TestQueryLimiter(t *testing.T) {
+	t.Run("nil_limiter_is_unlimited", func(t *testing.T) {
+		var l *queryLimiter
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+		release()
+		assert.Nil(t, newQueryLimiter(0, 10, time.Second))
+	})
+
+	t.Run("queue_timeout", func(t *testing.T) {
+		l := newQueryLimiter(1, 1, 50*time.Millisecond)
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		_, err = l.acquire(context.Background())
+		require.ErrorIs(t, err, errQueueTimeout)
+		_, _, waiting := l.load()
+		assert.Zero(t, waiting)
+	})
+
+	t.Run("canceled_while_queued", func(t *testing.T) {
+		l := newQueryLimiter(1, 1, time.Minute)
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = l.acquire(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestComputeWorker_ConcurrencyLimit(t *testing.T) {
+	srv := NewComputeGRPCServer(HandlerConfig{
+		DB:                   openTestDuckDB(t),
+		StartTime:            time.Now(),
+		QueryResultTTL:       time.Minute,
+		CursorMode:           true,
+		MaxConcurrentQueries: 1,
+		MaxQueuedQueries:     1,
+		QueueTimeout:         time.Minute,
+	})
+	worker := &computeWorkerAdapter{server: srv}
+
+	// Occupy the only slot so the next statement has to queue.
+	hold, err := srv.limiter.acquire(context.Background())
+	require.NoError(t, err)
+
+	type result struct {
+		resp *computeproto.ExecuteResponse
+		err  error
+	}
+	queued := make(chan result, 1)
+	go func() {
+		resp, err := worker.Execute(context.Background(), &computeproto.ExecuteRequest{Sql: "SELECT 1 AS one"})
+		queued <- result{resp, err}
+	}()
+
+	require.Eventually(t, func() bool {
+		_, _, waiting := srv.Load()
+		return waiting == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	health, err := worker.Health(context.Background(), &computeproto.HealthRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), health.MaxConcurrentQueries)
+	assert.Equal(t, int64(1), health.WaitingQueries)
+
+	// The queue holds one statement, so a third is rejected outright.
+	_, err = worker.Execute(context.Background(), &computeproto.ExecuteRequest{Sql: "SELECT 2"})
+	require.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, err = worker.SubmitQuery(context.Background(), &computeproto.SubmitQueryRequest{Sql: "SELECT 2"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	hold()
+	select {
+	case res := <-queued:
+		require.NoError(t, res.err)
+		assert.Equal(t, int64(1), res.resp.RowCount)
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued statement did not run after the slot was released")
+	}
+	_, running, waiting := srv.Load()
+	assert.Zero(t, running)
+	assert.Zero(t, waiting)
+}
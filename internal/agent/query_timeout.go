@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutErrorCode prefixes the error returned when a query exceeds the
+// timeout requested by the caller.
+const TimeoutErrorCode = "TIMEOUT"
+
+// withQueryTimeout bounds ctx by the caller-requested timeout. A non-positive
+// timeout leaves ctx unchanged.
+func withQueryTimeout(ctx context.Context, timeoutSeconds int64) (context.Context, context.CancelFunc) {
+	if timeoutSeconds <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+}
+
+// queryTimedOut reports whether a query run under ctx failed because the
+// requested timeout elapsed.
+func queryTimedOut(ctx context.Context, timeoutSeconds int64) bool {
+	return timeoutSeconds > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+func timeoutError(timeoutSeconds int64) error {
+	return fmt.Errorf("%s: query exceeded %ds timeout", TimeoutErrorCode, timeoutSeconds)
+}
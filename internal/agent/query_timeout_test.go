@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"duck-demo/internal/compute"
+	computeproto "duck-demo/internal/compute/proto"
+)
+
+const slowQuery = "SELECT sum(hash(i)) FROM range(100000000000) t(i)"
+
+func TestComputeWorker_QueryTimeout(t *testing.T) {
+	srv := NewComputeGRPCServer(HandlerConfig{
+		DB:             openTestDuckDB(t),
+		StartTime:      time.Now(),
+		QueryResultTTL: time.Minute,
+		CursorMode:     true,
+	})
+	worker := &computeWorkerAdapter{server: srv}
+
+	t.Run("execute", func(t *testing.T) {
+		start := time.Now()
+		_, err := worker.Execute(context.Background(), &computeproto.ExecuteRequest{Sql: slowQuery, TimeoutSeconds: 1})
+		require.Error(t, err)
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		assert.Contains(t, err.Error(), TimeoutErrorCode)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+
+	t.Run("submit", func(t *testing.T) {
+		resp, err := worker.SubmitQuery(context.Background(), &computeproto.SubmitQueryRequest{Sql: slowQuery, TimeoutSeconds: 1})
+		require.NoError(t, err)
+
+		var state *computeproto.QueryStatusResponse
+		require.Eventually(t, func() bool {
+			state, err = worker.GetQueryStatus(context.Background(), &computeproto.GetQueryStatusRequest{QueryId: resp.QueryId})
+			require.NoError(t, err)
+			return state.Status == compute.QueryStatusFailed
+		}, 10*time.Second, 50*time.Millisecond)
+		assert.Contains(t, state.Error, TimeoutErrorCode)
+	})
+
+	t.Run("fast_query_within_timeout", func(t *testing.T) {
+		resp, err := worker.Execute(context.Background(), &computeproto.ExecuteRequest{Sql: "SELECT 1 AS one", TimeoutSeconds: 5})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), resp.RowCount)
+	})
+}
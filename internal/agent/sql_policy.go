@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PolicyErrorCode prefixes the error returned for statements refused by the
+// agent's SQL policy.
+const PolicyErrorCode = "POLICY_ERROR"
+
+// SQLPolicy is a coarse guardrail applied to incoming SQL before execution.
+// A statement is refused when it matches any deny pattern, or when allow
+// patterns are configured and it matches none of them. It complements, and
+// does not replace, statement classification on the control plane.
+type SQLPolicy struct {
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+// NewSQLPolicy compiles the allow and deny patterns. Patterns use Go regexp
+// syntax and match anywhere in the statement; anchor them with ^ and add (?i)
+// for case-insensitive matching. It returns nil when both lists are empty.
+func NewSQLPolicy(allow, deny []string) (*SQLPolicy, error) {
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil, nil
+	}
+	p := &SQLPolicy{}
+	var err error
+	if p.allow, err = compilePatterns(allow); err != nil {
+		return nil, fmt.Errorf("allow pattern: %w", err)
+	}
+	if p.deny, err = compilePatterns(deny); err != nil {
+		return nil, fmt.Errorf("deny pattern: %w", err)
+	}
+	return p, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pattern, err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// Check returns an error starting with PolicyErrorCode when the policy refuses
+// sqlQuery. A nil policy allows everything.
+func (p *SQLPolicy) Check(sqlQuery string) error {
+	if p == nil {
+		return nil
+	}
+	stmt := strings.TrimSpace(sqlQuery)
+	for _, re := range p.deny {
+		if re.MatchString(stmt) {
+			return fmt.Errorf("%s: statement matches deny pattern %q", PolicyErrorCode, re.String())
+		}
+	}
+	if len(p.allow) == 0 {
+		return nil
+	}
+	for _, re := range p.allow {
+		if re.MatchString(stmt) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: statement matches no allow pattern", PolicyErrorCode)
+}
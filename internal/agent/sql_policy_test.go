@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	computeproto "duck-demo/internal/compute/proto"
+)
+
+func TestSQLPolicy_Check(t *testing.T) {
+	t.Run("nil_policy_allows_everything", func(t *testing.T) {
+		p, err := NewSQLPolicy(nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, p)
+		assert.NoError(t, p.Check("DROP TABLE t"))
+	})
+
+	t.Run("deny_pattern", func(t *testing.T) {
+		p, err := NewSQLPolicy(nil, []string{`(?i)^DROP\b`})
+		require.NoError(t, err)
+
+		err = p.Check("  drop table lake.main.orders")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), PolicyErrorCode)
+		assert.NoError(t, p.Check("SELECT * FROM orders"))
+	})
+
+	t.Run("allow_list", func(t *testing.T) {
+		p, err := NewSQLPolicy([]string{`(?i)^(SELECT|WITH)\b`}, nil)
+		require.NoError(t, err)
+
+		assert.NoError(t, p.Check("SELECT 1"))
+		err = p.Check("INSERT INTO t VALUES (1)")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "matches no allow pattern")
+	})
+
+	t.Run("deny_wins_over_allow", func(t *testing.T) {
+		p, err := NewSQLPolicy([]string{`(?i)^SELECT\b`}, []string{`(?i)read_csv`})
+		require.NoError(t, err)
+
+		require.Error(t, p.Check("SELECT * FROM read_csv('/etc/passwd')"))
+	})
+
+	t.Run("invalid_pattern", func(t *testing.T) {
+		_, err := NewSQLPolicy(nil, []string{"("})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "deny pattern")
+	})
+}
+
+func TestComputeWorker_SQLPolicy(t *testing.T) {
+	policy, err := NewSQLPolicy(nil, []string{`(?i)^DROP\b`})
+	require.NoError(t, err)
+
+	srv := NewComputeGRPCServer(HandlerConfig{
+		DB:             openTestDuckDB(t),
+		StartTime:      time.Now(),
+		QueryResultTTL: time.Minute,
+		CursorMode:     true,
+		SQLPolicy:      policy,
+	})
+	worker := &computeWorkerAdapter{server: srv}
+
+	t.Run("denied_execute", func(t *testing.T) {
+		_, err := worker.Execute(context.Background(), &computeproto.ExecuteRequest{Sql: "DROP TABLE orders"})
+		require.Error(t, err)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Contains(t, err.Error(), PolicyErrorCode)
+	})
+
+	t.Run("denied_submit", func(t *testing.T) {
+		_, err := worker.SubmitQuery(context.Background(), &computeproto.SubmitQueryRequest{Sql: "drop table orders"})
+		require.Error(t, err)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("allowed_select", func(t *testing.T) {
+		resp, err := worker.Execute(context.Background(), &computeproto.ExecuteRequest{Sql: "SELECT 42 AS answer"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"answer"}, resp.Columns)
+		assert.Equal(t, int64(1), resp.RowCount)
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -13,6 +14,8 @@ func httpStatusFromDomainError(err error) int {
 	var accessDenied *domain.AccessDeniedError
 	var validation *domain.ValidationError
 	var conflict *domain.ConflictError
+	var unavailable *domain.UnavailableError
+	var precondition *domain.PreconditionFailedError
 
 	switch {
 	case errors.As(err, &notFound):
@@ -23,7 +26,47 @@ func httpStatusFromDomainError(err error) int {
 		return http.StatusBadRequest
 	case errors.As(err, &conflict):
 		return http.StatusConflict
+	case errors.As(err, &unavailable):
+		return http.StatusServiceUnavailable
+	case errors.As(err, &precondition):
+		return http.StatusPreconditionFailed
 	default:
 		return http.StatusInternalServerError
 	}
 }
+
+// WriteResponseError is the strict-handler fallback for errors a handler
+// returns instead of a typed response. Domain errors keep their mapped status
+// so, for example, a busy metastore surfaces as 503 rather than an opaque 500.
+func WriteResponseError(w http.ResponseWriter, _ *http.Request, err error) {
+	status := httpStatusFromDomainError(err)
+	if status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "1")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Error{Code: int32(status), ErrorCode: errorCodeOf(err, status), Message: err.Error()}) //nolint:gosec // HTTP status codes are always in [100,599]
+}
+
+// WriteRequestError answers a request the server could not decode, such as a
+// malformed body or an invalid parameter, with a 400 error.
+func WriteRequestError(w http.ResponseWriter, _ *http.Request, err error) {
+	code := ErrorErrorCode(domain.ErrorCodeValidationFailed)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(Error{Code: http.StatusBadRequest, ErrorCode: &code, Message: err.Error()})
+}
+
+// errorCodeOf returns the catalog code for err answered with status.
+func errorCodeOf(err error, status int) *ErrorErrorCode {
+	code := ErrorErrorCode(domain.ErrorCodeOf(err, status))
+	return &code
+}
+
+// conflictErrorCode returns the code for a 409 response to err:
+// RESOURCE_EXISTS for a duplicate resource, CONFLICT otherwise. Other error
+// responses leave error_code unset; middleware.ErrorCodes fills it in from
+// the status.
+func conflictErrorCode(err error) *ErrorErrorCode {
+	return errorCodeOf(err, http.StatusConflict)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+func TestWriteResponseError(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		err        error
+		wantStatus int
+		wantCode   ErrorErrorCode
+	}{
+		"already exists": {
+			err:        fmt.Errorf("create schema: %w", domain.ErrAlreadyExists("schema %q already exists", "sales")),
+			wantStatus: http.StatusConflict,
+			wantCode:   "RESOURCE_EXISTS",
+		},
+		"state conflict": {
+			err:        domain.ErrConflict("approval request %q is not pending", "a-1"),
+			wantStatus: http.StatusConflict,
+			wantCode:   "CONFLICT",
+		},
+		"not found":   {err: domain.ErrNotFound("schema not found"), wantStatus: http.StatusNotFound, wantCode: "NOT_FOUND"},
+		"forbidden":   {err: domain.ErrAccessDenied("admin required"), wantStatus: http.StatusForbidden, wantCode: "FORBIDDEN"},
+		"validation":  {err: domain.ErrValidation("name is required"), wantStatus: http.StatusBadRequest, wantCode: "VALIDATION_FAILED"},
+		"unavailable": {err: domain.ErrUnavailable("metastore busy"), wantStatus: http.StatusServiceUnavailable, wantCode: "UNAVAILABLE"},
+		"stale":       {err: domain.ErrPreconditionFailed("notebook changed"), wantStatus: http.StatusPreconditionFailed, wantCode: "PRECONDITION_FAILED"},
+		"unknown":     {err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantCode: "INTERNAL"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			rec := httptest.NewRecorder()
+			WriteResponseError(rec, httptest.NewRequest(http.MethodPost, "/v1/catalogs", nil), tc.err)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			var body Error
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, int32(tc.wantStatus), body.Code) //nolint:gosec // test statuses are small
+			require.NotNil(t, body.ErrorCode)
+			assert.Equal(t, tc.wantCode, *body.ErrorCode)
+			assert.Equal(t, tc.err.Error(), body.Message)
+		})
+	}
+}
+
+func TestWriteRequestError(t *testing.T) {
+	t.Parallel()
+	rec := httptest.NewRecorder()
+	WriteRequestError(rec, httptest.NewRequest(http.MethodPost, "/v1/catalogs", nil), errors.New("can't decode JSON body"))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var body Error
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.NotNil(t, body.ErrorCode)
+	assert.Equal(t, ErrorErrorCode("VALIDATION_FAILED"), *body.ErrorCode)
+}
@@ -31,6 +31,17 @@ type APIHandler struct {
 	models              modelService
 	macros              macroService
 	semantics           semanticService
+	applyLocks          applyLockService
+	webhooks            webhookService
+	migrations          migrationService
+	backups             backupService
+	versions            versionService
+	extensions          extensionService
+	authz               authzCheckService
+	defaultGrants       defaultGrantService
+	approvals           approvalService
+	seeder              seedService
+	searchIndex         searchIndexService
 }
 
 // NewHandler creates a new APIHandler with all required service dependencies.
@@ -63,6 +74,17 @@ func NewHandler(
 	models modelService,
 	macros macroService,
 	semantics semanticService,
+	applyLocks applyLockService,
+	webhooks webhookService,
+	migrations migrationService,
+	backups backupService,
+	versions versionService,
+	extensions extensionService,
+	authz authzCheckService,
+	defaultGrants defaultGrantService,
+	approvals approvalService,
+	seeder seedService,
+	searchIndex searchIndexService,
 ) *APIHandler {
 	return &APIHandler{
 		query:               query,
@@ -93,6 +115,17 @@ func NewHandler(
 		models:              models,
 		macros:              macros,
 		semantics:           semantics,
+		applyLocks:          applyLocks,
+		webhooks:            webhooks,
+		migrations:          migrations,
+		backups:             backups,
+		versions:            versions,
+		extensions:          extensions,
+		authz:               authz,
+		defaultGrants:       defaultGrants,
+		approvals:           approvals,
+		seeder:              seeder,
+		searchIndex:         searchIndex,
 	}
 }
 
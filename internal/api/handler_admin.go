@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/seed"
+	"duck-demo/internal/service/admin"
+)
+
+// migrationService defines the metastore migration operations used by the API handler.
+type migrationService interface {
+	Status(ctx context.Context) ([]domain.MigrationStatus, error)
+	Up(ctx context.Context) ([]domain.MigrationStatus, error)
+}
+
+// backupService defines the metastore backup operations used by the API handler.
+type backupService interface {
+	Create(ctx context.Context, opts domain.BackupOptions) (*domain.Backup, error)
+}
+
+// extensionService defines the DuckDB extension inventory operations used by the API handler.
+type extensionService interface {
+	List(ctx context.Context) (*admin.ExtensionList, error)
+}
+
+// seedService defines the on-demand demo seeding used by the API handler.
+type seedService interface {
+	Demo(ctx context.Context) (*seed.Result, error)
+}
+
+// searchIndexService defines the search reindex operations used by the API handler.
+type searchIndexService interface {
+	Reindex(ctx context.Context, catalogName *string) (*domain.SearchIndexStats, error)
+}
+
+// === Admin ===
+
+// ListMigrations implements the endpoint for reporting metastore migration status.
+func (h *APIHandler) ListMigrations(ctx context.Context, _ ListMigrationsRequestObject) (ListMigrationsResponseObject, error) {
+	statuses, err := h.migrations.Status(ctx)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ListMigrations403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return ListMigrations200JSONResponse{
+		Body:    migrationListToAPI(statuses),
+		Headers: ListMigrations200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// ApplyMigrations implements the endpoint for applying pending metastore migrations.
+func (h *APIHandler) ApplyMigrations(ctx context.Context, _ ApplyMigrationsRequestObject) (ApplyMigrationsResponseObject, error) {
+	statuses, err := h.migrations.Up(ctx)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ApplyMigrations403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return ApplyMigrations409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return ApplyMigrations200JSONResponse{
+		Body:    migrationListToAPI(statuses),
+		Headers: ApplyMigrations200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// CreateBackup implements the endpoint for streaming a metastore backup.
+func (h *APIHandler) CreateBackup(ctx context.Context, req CreateBackupRequestObject) (CreateBackupResponseObject, error) {
+	opts := domain.BackupOptions{}
+	if req.Params.Gzip != nil {
+		opts.Gzip = *req.Params.Gzip
+	}
+	backup, err := h.backups.Create(ctx, opts)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CreateBackup403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	// The strict server closes Body after writing it, which removes the
+	// staged backup file.
+	return CreateBackup200ApplicationoctetStreamResponse{
+		Body:          backup.Content,
+		ContentLength: backup.SizeBytes,
+		Headers: CreateBackup200ResponseHeaders{
+			XBackupChecksum:     backup.Checksum,
+			XSchemaVersion:      backup.SchemaVersion,
+			XRateLimitLimit:     defaultRateLimitLimit,
+			XRateLimitRemaining: defaultRateLimitRemaining,
+			XRateLimitReset:     defaultRateLimitReset,
+		},
+	}, nil
+}
+
+// ListExtensions implements the endpoint for reporting DuckDB extensions and
+// the extension allowlist.
+func (h *APIHandler) ListExtensions(ctx context.Context, _ ListExtensionsRequestObject) (ListExtensionsResponseObject, error) {
+	list, err := h.extensions.List(ctx)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ListExtensions403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return ListExtensions200JSONResponse{
+		Body:    extensionListToAPI(*list),
+		Headers: ListExtensions200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// SeedData implements the endpoint for seeding demo data into the server.
+func (h *APIHandler) SeedData(ctx context.Context, req SeedDataRequestObject) (SeedDataResponseObject, error) {
+	if !req.Body.Demo {
+		return SeedData400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: "nothing to seed: set demo to true"}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+	res, err := h.seeder.Demo(ctx)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return SeedData403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return SeedData200JSONResponse{
+		Body: SeedResult{
+			PrincipalsCreated: int64(res.PrincipalsCreated),
+			GroupsCreated:     int64(res.GroupsCreated),
+			MembershipsAdded:  int64(res.MembershipsAdded),
+		},
+		Headers: SeedData200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// ReindexSearch implements the endpoint for rebuilding a catalog's search index.
+func (h *APIHandler) ReindexSearch(ctx context.Context, req ReindexSearchRequestObject) (ReindexSearchResponseObject, error) {
+	stats, err := h.searchIndex.Reindex(ctx, req.Params.Catalog)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ReindexSearch403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return ReindexSearch404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return ReindexSearch400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return ReindexSearch200JSONResponse{
+		Body: SearchReindexResult{
+			SchemasIndexed: stats.Schemas,
+			TablesIndexed:  stats.Tables,
+			ViewsIndexed:   stats.Views,
+			ColumnsIndexed: stats.Columns,
+		},
+		Headers: ReindexSearch200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// extensionListToAPI converts an extension inventory to the API type.
+func extensionListToAPI(list admin.ExtensionList) ExtensionList {
+	data := make([]DuckDBExtension, len(list.Extensions))
+	for i, e := range list.Extensions {
+		data[i] = DuckDBExtension{
+			Name:             e.Name,
+			Version:          optStr(e.Version),
+			InstallMode:      optStr(e.InstallMode),
+			Installed:        e.Installed,
+			Loaded:           e.Loaded,
+			StaticallyLinked: e.StaticallyLinked,
+			Allowed:          e.Allowed,
+		}
+	}
+	allowlist := list.Allowlist
+	if allowlist == nil {
+		allowlist = []string{}
+	}
+	return ExtensionList{Allowlist: allowlist, Data: data}
+}
+
+// migrationListToAPI converts domain migration statuses to the API type.
+func migrationListToAPI(statuses []domain.MigrationStatus) MigrationList {
+	data := make([]Migration, len(statuses))
+	for i, st := range statuses {
+		data[i] = Migration{
+			Version:         st.Version,
+			Name:            st.Name,
+			Checksum:        st.Checksum,
+			AppliedChecksum: optStr(st.AppliedChecksum),
+			Applied:         st.Applied,
+			AppliedAt:       st.AppliedAt,
+			Drifted:         st.Drifted,
+		}
+	}
+	return MigrationList{Data: data}
+}
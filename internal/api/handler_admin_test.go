@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/seed"
+	"duck-demo/internal/service/admin"
+)
+
+type mockMigrationService struct {
+	statusFn func(ctx context.Context) ([]domain.MigrationStatus, error)
+	upFn     func(ctx context.Context) ([]domain.MigrationStatus, error)
+}
+
+func (m *mockMigrationService) Status(ctx context.Context) ([]domain.MigrationStatus, error) {
+	if m.statusFn == nil {
+		panic("mockMigrationService.Status called but not configured")
+	}
+	return m.statusFn(ctx)
+}
+
+func (m *mockMigrationService) Up(ctx context.Context) ([]domain.MigrationStatus, error) {
+	if m.upFn == nil {
+		panic("mockMigrationService.Up called but not configured")
+	}
+	return m.upFn(ctx)
+}
+
+type mockBackupService struct {
+	createFn func(ctx context.Context, opts domain.BackupOptions) (*domain.Backup, error)
+}
+
+func (m *mockBackupService) Create(ctx context.Context, opts domain.BackupOptions) (*domain.Backup, error) {
+	if m.createFn == nil {
+		panic("mockBackupService.Create called but not configured")
+	}
+	return m.createFn(ctx, opts)
+}
+
+type mockExtensionService struct {
+	listFn func(ctx context.Context) (*admin.ExtensionList, error)
+}
+
+func (m *mockExtensionService) List(ctx context.Context) (*admin.ExtensionList, error) {
+	if m.listFn == nil {
+		panic("mockExtensionService.List called but not configured")
+	}
+	return m.listFn(ctx)
+}
+
+type mockSeedService struct {
+	demoFn func(ctx context.Context) (*seed.Result, error)
+}
+
+func (m *mockSeedService) Demo(ctx context.Context) (*seed.Result, error) {
+	if m.demoFn == nil {
+		panic("mockSeedService.Demo called but not configured")
+	}
+	return m.demoFn(ctx)
+}
+
+type mockSearchIndexService struct {
+	reindexFn func(ctx context.Context, catalogName *string) (*domain.SearchIndexStats, error)
+}
+
+func (m *mockSearchIndexService) Reindex(ctx context.Context, catalogName *string) (*domain.SearchIndexStats, error) {
+	if m.reindexFn == nil {
+		panic("mockSearchIndexService.Reindex called but not configured")
+	}
+	return m.reindexFn(ctx, catalogName)
+}
+
+func TestHandler_ListMigrations(t *testing.T) {
+	t.Parallel()
+
+	appliedAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	t.Run("maps statuses", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{migrations: &mockMigrationService{statusFn: func(_ context.Context) ([]domain.MigrationStatus, error) {
+			return []domain.MigrationStatus{
+				{Version: 1, Name: "001_a.sql", Checksum: "abc", AppliedChecksum: "abc", Applied: true, AppliedAt: &appliedAt},
+				{Version: 2, Name: "002_b.sql", Checksum: "def"},
+			}, nil
+		}}}
+		resp, err := handler.ListMigrations(storageTestCtx(), ListMigrationsRequestObject{})
+		require.NoError(t, err)
+		ok200, ok := resp.(ListMigrations200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		require.Len(t, ok200.Body.Data, 2)
+		assert.True(t, ok200.Body.Data[0].Applied)
+		require.NotNil(t, ok200.Body.Data[0].AppliedChecksum)
+		assert.Equal(t, "abc", *ok200.Body.Data[0].AppliedChecksum)
+		assert.False(t, ok200.Body.Data[1].Applied)
+		assert.Nil(t, ok200.Body.Data[1].AppliedChecksum)
+	})
+
+	t.Run("non-admin returns 403", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{migrations: &mockMigrationService{statusFn: func(_ context.Context) ([]domain.MigrationStatus, error) {
+			return nil, domain.ErrAccessDenied("admin privileges required")
+		}}}
+		resp, err := handler.ListMigrations(storageTestCtx(), ListMigrationsRequestObject{})
+		require.NoError(t, err)
+		_, ok := resp.(ListMigrations403JSONResponse)
+		assert.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_ApplyMigrations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drift returns 409", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{migrations: &mockMigrationService{upFn: func(_ context.Context) ([]domain.MigrationStatus, error) {
+			return nil, domain.ErrConflict("migration checksum drift: 003_c.sql changed after being applied")
+		}}}
+		resp, err := handler.ApplyMigrations(storageTestCtx(), ApplyMigrationsRequestObject{})
+		require.NoError(t, err)
+		conflict, ok := resp.(ApplyMigrations409JSONResponse)
+		require.True(t, ok, "expected 409 response, got %T", resp)
+		assert.Contains(t, conflict.Body.Message, "003_c.sql")
+	})
+
+	t.Run("applies and returns status", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{migrations: &mockMigrationService{upFn: func(_ context.Context) ([]domain.MigrationStatus, error) {
+			return []domain.MigrationStatus{{Version: 1, Name: "001_a.sql", Applied: true}}, nil
+		}}}
+		resp, err := handler.ApplyMigrations(storageTestCtx(), ApplyMigrationsRequestObject{})
+		require.NoError(t, err)
+		ok200, ok := resp.(ApplyMigrations200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Len(t, ok200.Body.Data, 1)
+	})
+}
+
+func TestHandler_CreateBackup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("streams backup with checksum and schema version", func(t *testing.T) {
+		t.Parallel()
+		gz := true
+		handler := &APIHandler{backups: &mockBackupService{createFn: func(_ context.Context, opts domain.BackupOptions) (*domain.Backup, error) {
+			assert.True(t, opts.Gzip)
+			return &domain.Backup{
+				Content:       io.NopCloser(strings.NewReader("backup-bytes")),
+				SizeBytes:     12,
+				Checksum:      "abc123",
+				SchemaVersion: 50,
+				Gzip:          true,
+			}, nil
+		}}}
+		resp, err := handler.CreateBackup(storageTestCtx(), CreateBackupRequestObject{Params: CreateBackupParams{Gzip: &gz}})
+		require.NoError(t, err)
+		ok200, ok := resp.(CreateBackup200ApplicationoctetStreamResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Equal(t, "abc123", ok200.Headers.XBackupChecksum)
+		assert.Equal(t, int64(50), ok200.Headers.XSchemaVersion)
+		assert.Equal(t, int64(12), ok200.ContentLength)
+		body, err := io.ReadAll(ok200.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "backup-bytes", string(body))
+	})
+
+	t.Run("non-admin returns 403", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{backups: &mockBackupService{createFn: func(_ context.Context, _ domain.BackupOptions) (*domain.Backup, error) {
+			return nil, domain.ErrAccessDenied("admin privileges required")
+		}}}
+		resp, err := handler.CreateBackup(storageTestCtx(), CreateBackupRequestObject{})
+		require.NoError(t, err)
+		_, ok := resp.(CreateBackup403JSONResponse)
+		assert.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_ListExtensions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps inventory", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{extensions: &mockExtensionService{listFn: func(_ context.Context) (*admin.ExtensionList, error) {
+			return &admin.ExtensionList{
+				Allowlist: []string{"ducklake", "httpfs"},
+				Extensions: []domain.DuckDBExtension{
+					{Name: "ducklake", Version: "2b4a7e5", InstallMode: "REPOSITORY", Installed: true, Loaded: true, Allowed: true},
+					{Name: "json", InstallMode: "STATICALLY_LINKED", Installed: true, Loaded: true, StaticallyLinked: true, Allowed: true},
+				},
+			}, nil
+		}}}
+		resp, err := handler.ListExtensions(storageTestCtx(), ListExtensionsRequestObject{})
+		require.NoError(t, err)
+		ok200, ok := resp.(ListExtensions200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Equal(t, []string{"ducklake", "httpfs"}, ok200.Body.Allowlist)
+		require.Len(t, ok200.Body.Data, 2)
+		require.NotNil(t, ok200.Body.Data[0].Version)
+		assert.Equal(t, "2b4a7e5", *ok200.Body.Data[0].Version)
+		assert.Nil(t, ok200.Body.Data[1].Version)
+		assert.True(t, ok200.Body.Data[1].StaticallyLinked)
+	})
+
+	t.Run("non-admin returns 403", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{extensions: &mockExtensionService{listFn: func(_ context.Context) (*admin.ExtensionList, error) {
+			return nil, domain.ErrAccessDenied("admin privileges required")
+		}}}
+		resp, err := handler.ListExtensions(storageTestCtx(), ListExtensionsRequestObject{})
+		require.NoError(t, err)
+		_, ok := resp.(ListExtensions403JSONResponse)
+		assert.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_SeedData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("demo seeds and reports counts", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{seeder: &mockSeedService{demoFn: func(_ context.Context) (*seed.Result, error) {
+			return &seed.Result{PrincipalsCreated: 4, GroupsCreated: 2, MembershipsAdded: 3}, nil
+		}}}
+		resp, err := handler.SeedData(storageTestCtx(), SeedDataRequestObject{Body: &SeedDataJSONRequestBody{Demo: true}})
+		require.NoError(t, err)
+		ok200, ok := resp.(SeedData200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Equal(t, SeedResult{PrincipalsCreated: 4, GroupsCreated: 2, MembershipsAdded: 3}, ok200.Body)
+	})
+
+	t.Run("without demo returns 400", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{seeder: &mockSeedService{}}
+		resp, err := handler.SeedData(storageTestCtx(), SeedDataRequestObject{Body: &SeedDataJSONRequestBody{}})
+		require.NoError(t, err)
+		_, ok := resp.(SeedData400JSONResponse)
+		assert.True(t, ok, "expected 400 response, got %T", resp)
+	})
+
+	t.Run("non-admin returns 403", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{seeder: &mockSeedService{demoFn: func(_ context.Context) (*seed.Result, error) {
+			return nil, domain.ErrAccessDenied("admin privileges required")
+		}}}
+		resp, err := handler.SeedData(storageTestCtx(), SeedDataRequestObject{Body: &SeedDataJSONRequestBody{Demo: true}})
+		require.NoError(t, err)
+		_, ok := resp.(SeedData403JSONResponse)
+		assert.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_ReindexSearch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports counts indexed", func(t *testing.T) {
+		t.Parallel()
+		var gotCatalog *string
+		handler := &APIHandler{searchIndex: &mockSearchIndexService{reindexFn: func(_ context.Context, catalogName *string) (*domain.SearchIndexStats, error) {
+			gotCatalog = catalogName
+			return &domain.SearchIndexStats{Schemas: 2, Tables: 5, Views: 1, Columns: 40}, nil
+		}}}
+		catalogName := "lake"
+		resp, err := handler.ReindexSearch(storageTestCtx(), ReindexSearchRequestObject{Params: ReindexSearchParams{Catalog: &catalogName}})
+		require.NoError(t, err)
+		ok200, ok := resp.(ReindexSearch200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Equal(t, SearchReindexResult{SchemasIndexed: 2, TablesIndexed: 5, ViewsIndexed: 1, ColumnsIndexed: 40}, ok200.Body)
+		assert.Equal(t, &catalogName, gotCatalog)
+	})
+
+	t.Run("unknown catalog returns 404", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{searchIndex: &mockSearchIndexService{reindexFn: func(_ context.Context, _ *string) (*domain.SearchIndexStats, error) {
+			return nil, domain.ErrNotFound("catalog %q not found", "missing")
+		}}}
+		resp, err := handler.ReindexSearch(storageTestCtx(), ReindexSearchRequestObject{})
+		require.NoError(t, err)
+		_, ok := resp.(ReindexSearch404JSONResponse)
+		assert.True(t, ok, "expected 404 response, got %T", resp)
+	})
+
+	t.Run("non-admin returns 403", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{searchIndex: &mockSearchIndexService{reindexFn: func(_ context.Context, _ *string) (*domain.SearchIndexStats, error) {
+			return nil, domain.ErrAccessDenied("admin privileges required")
+		}}}
+		resp, err := handler.ReindexSearch(storageTestCtx(), ReindexSearchRequestObject{})
+		require.NoError(t, err)
+		_, ok := resp.(ReindexSearch403JSONResponse)
+		assert.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"duck-demo/internal/domain"
+)
+
+// approvalService defines the dual-control operations used by the API handler.
+type approvalService interface {
+	RequiresApproval(op string) bool
+	Submit(ctx context.Context, op string, params map[string]string) (*domain.ApprovalRequest, error)
+	Get(ctx context.Context, id string) (*domain.ApprovalRequest, error)
+	List(ctx context.Context, filter domain.ApprovalFilter) ([]domain.ApprovalRequest, int64, error)
+	Approve(ctx context.Context, id string) (*domain.ApprovalRequest, error)
+	Reject(ctx context.Context, id string) (*domain.ApprovalRequest, error)
+}
+
+// submitApproval holds op for a second admin's approval when it is
+// configured to need one. It returns nil when op should run now.
+func (h *APIHandler) submitApproval(ctx context.Context, op string, params map[string]string) (*domain.ApprovalRequest, error) {
+	if h.approvals == nil {
+		return nil, nil
+	}
+	return h.approvals.Submit(ctx, op, params)
+}
+
+// adminGrantNeedsApproval returns an access-denied error when granting admin
+// is dual-controlled. Endpoints that can create an admin principal outright
+// (create, import) refuse is_admin=true then, so that admin is only granted
+// through the approval flow of UpdatePrincipalAdmin.
+func (h *APIHandler) adminGrantNeedsApproval() error {
+	if h.approvals == nil || !h.approvals.RequiresApproval(domain.ApprovalOpSetAdmin) {
+		return nil
+	}
+	return domain.ErrAccessDenied("granting admin requires approval: create the principal without is_admin, then request admin with PUT /principals/{id}/admin")
+}
+
+// === Approvals ===
+
+// ListApprovals implements the endpoint for listing approval requests.
+func (h *APIHandler) ListApprovals(ctx context.Context, req ListApprovalsRequestObject) (ListApprovalsResponseObject, error) {
+	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	filter := domain.ApprovalFilter{Page: page}
+	if req.Params.Status != nil {
+		status := string(*req.Params.Status)
+		filter.Status = &status
+	}
+	requests, total, err := h.approvals.List(ctx, filter)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ListApprovals403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	data := make([]ApprovalRequest, len(requests))
+	for i, r := range requests {
+		data[i] = approvalToAPI(r)
+	}
+	npt := domain.NextPageToken(page.Offset(), page.Limit(), total)
+	return ListApprovals200JSONResponse{
+		Body:    PaginatedApprovalRequests{Data: &data, NextPageToken: optStr(npt)},
+		Headers: ListApprovals200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// GetApproval implements the endpoint for reading one approval request.
+func (h *APIHandler) GetApproval(ctx context.Context, req GetApprovalRequestObject) (GetApprovalResponseObject, error) {
+	result, err := h.approvals.Get(ctx, req.ApprovalId)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return GetApproval403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return GetApproval404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return GetApproval200JSONResponse{
+		Body:    approvalToAPI(*result),
+		Headers: GetApproval200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// ApproveApproval implements the endpoint for approving a pending request,
+// which runs the held operation.
+func (h *APIHandler) ApproveApproval(ctx context.Context, req ApproveApprovalRequestObject) (ApproveApprovalResponseObject, error) {
+	result, err := h.approvals.Approve(ctx, req.ApprovalId)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ApproveApproval403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return ApproveApproval404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return ApproveApproval409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return ApproveApproval200JSONResponse{
+		Body:    approvalToAPI(*result),
+		Headers: ApproveApproval200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// RejectApproval implements the endpoint for rejecting a pending request.
+func (h *APIHandler) RejectApproval(ctx context.Context, req RejectApprovalRequestObject) (RejectApprovalResponseObject, error) {
+	result, err := h.approvals.Reject(ctx, req.ApprovalId)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return RejectApproval403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return RejectApproval404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return RejectApproval409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return RejectApproval200JSONResponse{
+		Body:    approvalToAPI(*result),
+		Headers: RejectApproval200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// approvalToAPI converts a domain ApprovalRequest to the API type.
+func approvalToAPI(r domain.ApprovalRequest) ApprovalRequest {
+	params := r.Params
+	if params == nil {
+		params = map[string]string{}
+	}
+	return ApprovalRequest{
+		Id:           r.ID,
+		Operation:    ApprovalRequestOperation(r.Operation),
+		Params:       params,
+		Status:       ApprovalRequestStatus(r.Status),
+		RequestedBy:  r.RequestedBy,
+		DecidedBy:    r.DecidedBy,
+		ErrorMessage: r.ErrorMessage,
+		CreatedAt:    r.CreatedAt,
+		DecidedAt:    r.DecidedAt,
+	}
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+type mockApprovalService struct {
+	required  map[string]bool
+	submitFn  func(ctx context.Context, op string, params map[string]string) (*domain.ApprovalRequest, error)
+	getFn     func(ctx context.Context, id string) (*domain.ApprovalRequest, error)
+	listFn    func(ctx context.Context, filter domain.ApprovalFilter) ([]domain.ApprovalRequest, int64, error)
+	approveFn func(ctx context.Context, id string) (*domain.ApprovalRequest, error)
+	rejectFn  func(ctx context.Context, id string) (*domain.ApprovalRequest, error)
+}
+
+func (m *mockApprovalService) RequiresApproval(op string) bool {
+	return m.required[op]
+}
+
+func (m *mockApprovalService) Submit(ctx context.Context, op string, params map[string]string) (*domain.ApprovalRequest, error) {
+	if m.submitFn == nil {
+		panic("mockApprovalService.Submit called but not configured")
+	}
+	return m.submitFn(ctx, op, params)
+}
+
+func (m *mockApprovalService) Get(ctx context.Context, id string) (*domain.ApprovalRequest, error) {
+	if m.getFn == nil {
+		panic("mockApprovalService.Get called but not configured")
+	}
+	return m.getFn(ctx, id)
+}
+
+func (m *mockApprovalService) List(ctx context.Context, filter domain.ApprovalFilter) ([]domain.ApprovalRequest, int64, error) {
+	if m.listFn == nil {
+		panic("mockApprovalService.List called but not configured")
+	}
+	return m.listFn(ctx, filter)
+}
+
+func (m *mockApprovalService) Approve(ctx context.Context, id string) (*domain.ApprovalRequest, error) {
+	if m.approveFn == nil {
+		panic("mockApprovalService.Approve called but not configured")
+	}
+	return m.approveFn(ctx, id)
+}
+
+func (m *mockApprovalService) Reject(ctx context.Context, id string) (*domain.ApprovalRequest, error) {
+	if m.rejectFn == nil {
+		panic("mockApprovalService.Reject called but not configured")
+	}
+	return m.rejectFn(ctx, id)
+}
+
+func pendingApproval(op string, params map[string]string) *domain.ApprovalRequest {
+	return &domain.ApprovalRequest{
+		ID: "req-1", Operation: op, Params: params, Status: domain.ApprovalStatusPending,
+		RequestedBy: "test-user", CreatedAt: time.Now(),
+	}
+}
+
+func TestHandler_UpdatePrincipalAdmin_RequiresApproval(t *testing.T) {
+	t.Parallel()
+
+	t.Run("granting admin is held for approval", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{
+			principals: &mockPrincipalService{setAdminFn: func(context.Context, string, bool) error {
+				t.Fatal("SetAdmin must not run before approval")
+				return nil
+			}},
+			approvals: &mockApprovalService{submitFn: func(_ context.Context, op string, params map[string]string) (*domain.ApprovalRequest, error) {
+				assert.Equal(t, domain.ApprovalOpSetAdmin, op)
+				assert.Equal(t, map[string]string{"principal_id": "p-1"}, params)
+				return pendingApproval(op, params), nil
+			}},
+		}
+		resp, err := handler.UpdatePrincipalAdmin(storageTestCtx(), UpdatePrincipalAdminRequestObject{
+			PrincipalId: "p-1", Body: &UpdatePrincipalAdminJSONRequestBody{IsAdmin: true},
+		})
+		require.NoError(t, err)
+		accepted, ok := resp.(UpdatePrincipalAdmin202JSONResponse)
+		require.True(t, ok, "expected 202 response, got %T", resp)
+		assert.Equal(t, "req-1", accepted.Body.Id)
+		assert.Equal(t, ApprovalRequestStatus(domain.ApprovalStatusPending), accepted.Body.Status)
+	})
+
+	t.Run("revoking admin runs immediately", func(t *testing.T) {
+		t.Parallel()
+		called := false
+		handler := &APIHandler{
+			principals: &mockPrincipalService{setAdminFn: func(_ context.Context, _ string, isAdmin bool) error {
+				called = true
+				assert.False(t, isAdmin)
+				return nil
+			}},
+			approvals: &mockApprovalService{},
+		}
+		resp, err := handler.UpdatePrincipalAdmin(storageTestCtx(), UpdatePrincipalAdminRequestObject{
+			PrincipalId: "p-1", Body: &UpdatePrincipalAdminJSONRequestBody{IsAdmin: false},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(UpdatePrincipalAdmin204Response)
+		require.True(t, ok, "expected 204 response, got %T", resp)
+		assert.True(t, called)
+	})
+}
+
+func TestHandler_CreateAdminPrincipal_RequiresApproval(t *testing.T) {
+	t.Parallel()
+
+	approvals := &mockApprovalService{required: map[string]bool{domain.ApprovalOpSetAdmin: true}}
+	principals := &mockPrincipalService{
+		createFn: func(context.Context, domain.CreatePrincipalRequest) (*domain.Principal, error) {
+			t.Fatal("Create must not run for an admin principal while admin needs approval")
+			return nil, nil
+		},
+		importFn: func(context.Context, []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+			t.Fatal("Import must not run for an admin row while admin needs approval")
+			return nil, nil
+		},
+	}
+	handler := &APIHandler{principals: principals, approvals: approvals}
+	isAdmin := true
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+		resp, err := handler.CreatePrincipal(storageTestCtx(), CreatePrincipalRequestObject{
+			Body: &CreatePrincipalJSONRequestBody{Name: "mallory", IsAdmin: &isAdmin},
+		})
+		require.NoError(t, err)
+		forbidden, ok := resp.(CreatePrincipal403JSONResponse)
+		require.True(t, ok, "expected 403 response, got %T", resp)
+		assert.Contains(t, forbidden.Body.Message, "requires approval")
+	})
+
+	t.Run("import", func(t *testing.T) {
+		t.Parallel()
+		resp, err := handler.ImportPrincipals(storageTestCtx(), ImportPrincipalsRequestObject{
+			Body: &ImportPrincipalsJSONRequestBody{Principals: []PrincipalImportRow{
+				{Name: "alice"},
+				{Name: "mallory", IsAdmin: &isAdmin},
+			}},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(ImportPrincipals403JSONResponse)
+		require.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_DeleteCatalogRegistration_RequiresApproval(t *testing.T) {
+	t.Parallel()
+
+	handler := &APIHandler{
+		catalogRegistration: &mockCatalogRegistrationService{deleteFn: func(context.Context, string) error {
+			t.Fatal("Delete must not run before approval")
+			return nil
+		}},
+		approvals: &mockApprovalService{submitFn: func(_ context.Context, op string, params map[string]string) (*domain.ApprovalRequest, error) {
+			assert.Equal(t, domain.ApprovalOpDeleteCatalog, op)
+			assert.Equal(t, "cat", params["catalog_name"])
+			return pendingApproval(op, params), nil
+		}},
+	}
+	resp, err := handler.DeleteCatalogRegistration(catTestCtx(), DeleteCatalogRegistrationRequestObject{CatalogName: "cat"})
+	require.NoError(t, err)
+	_, ok := resp.(DeleteCatalogRegistration202JSONResponse)
+	require.True(t, ok, "expected 202 response, got %T", resp)
+}
+
+func TestHandler_ApproveApproval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, id string) (*domain.ApprovalRequest, error)
+		assertFn func(t *testing.T, resp ApproveApprovalResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 200",
+			svcFn: func(_ context.Context, id string) (*domain.ApprovalRequest, error) {
+				r := pendingApproval(domain.ApprovalOpSetAdmin, map[string]string{"principal_id": "p-1"})
+				r.Status = domain.ApprovalStatusApproved
+				approver := "second-admin"
+				r.DecidedBy = &approver
+				return r, nil
+			},
+			assertFn: func(t *testing.T, resp ApproveApprovalResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ApproveApproval200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, ApprovalRequestStatus(domain.ApprovalStatusApproved), ok200.Body.Status)
+				require.NotNil(t, ok200.Body.DecidedBy)
+				assert.Equal(t, "second-admin", *ok200.Body.DecidedBy)
+			},
+		},
+		{
+			name: "self-approval returns 403",
+			svcFn: func(_ context.Context, id string) (*domain.ApprovalRequest, error) {
+				return nil, domain.ErrAccessDenied("approval request %q must be approved by an admin other than the requester", id)
+			},
+			assertFn: func(t *testing.T, resp ApproveApprovalResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(ApproveApproval403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+			},
+		},
+		{
+			name: "unknown request returns 404",
+			svcFn: func(_ context.Context, id string) (*domain.ApprovalRequest, error) {
+				return nil, domain.ErrNotFound("approval request %q not found", id)
+			},
+			assertFn: func(t *testing.T, resp ApproveApprovalResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(ApproveApproval404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+			},
+		},
+		{
+			name: "decided request returns 409",
+			svcFn: func(_ context.Context, id string) (*domain.ApprovalRequest, error) {
+				return nil, domain.ErrConflict("approval request %q is not pending", id)
+			},
+			assertFn: func(t *testing.T, resp ApproveApprovalResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(ApproveApproval409JSONResponse)
+				require.True(t, ok, "expected 409 response, got %T", resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := &APIHandler{approvals: &mockApprovalService{approveFn: tt.svcFn}}
+			resp, err := handler.ApproveApproval(storageTestCtx(), ApproveApprovalRequestObject{ApprovalId: "req-1"})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
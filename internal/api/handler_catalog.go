@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"duck-demo/internal/domain"
 )
@@ -14,23 +15,41 @@ func principalFromCtx(ctx context.Context) string {
 	return p.Name
 }
 
+// ownerFilter resolves the owner and mine list parameters. mine selects the
+// caller and takes precedence over owner.
+func ownerFilter(ctx context.Context, owner *string, mine *bool) *string {
+	if mine != nil && *mine {
+		name := principalFromCtx(ctx)
+		return &name
+	}
+	return owner
+}
+
 // catalogService defines the catalog operations used by the API handler.
 type catalogService interface {
 	GetCatalogInfo(ctx context.Context, catalogName string) (*domain.CatalogInfo, error)
-	ListSchemas(ctx context.Context, catalogName string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error)
+	ListSchemas(ctx context.Context, catalogName string, owner *string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error)
 	CreateSchema(ctx context.Context, catalogName string, principal string, req domain.CreateSchemaRequest) (*domain.SchemaDetail, error)
 	GetSchema(ctx context.Context, catalogName string, name string) (*domain.SchemaDetail, error)
 	UpdateSchema(ctx context.Context, catalogName string, principal string, name string, req domain.UpdateSchemaRequest) (*domain.SchemaDetail, error)
 	DeleteSchema(ctx context.Context, catalogName string, principal string, name string, force bool) error
-	ListTables(ctx context.Context, catalogName string, schemaName string, page domain.PageRequest) ([]domain.TableDetail, int64, error)
+	ListTables(ctx context.Context, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.TableDetail, int64, error)
 	CreateTable(ctx context.Context, catalogName string, principal string, schemaName string, req domain.CreateTableRequest) (*domain.TableDetail, error)
 	GetTable(ctx context.Context, catalogName string, schemaName, tableName string) (*domain.TableDetail, error)
 	UpdateTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.UpdateTableRequest) (*domain.TableDetail, error)
-	DeleteTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string) error
+	DeleteTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, cascade bool) error
+	RenameTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.RenameRequest) (*domain.TableDetail, error)
+	RegisterDirectory(ctx context.Context, principal string, req domain.RegisterDirectoryRequest) (*domain.RegisterDirectoryResult, error)
 	ListColumns(ctx context.Context, catalogName string, schemaName, tableName string, page domain.PageRequest) ([]domain.ColumnDetail, int64, error)
 	UpdateColumn(ctx context.Context, catalogName string, principal string, schemaName, tableName, columnName string, req domain.UpdateColumnRequest) (*domain.ColumnDetail, error)
 	ProfileTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string) (*domain.TableStatistics, error)
+	CompactTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string) (*domain.TableMaintenanceResult, error)
+	VacuumTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, olderThan time.Duration) (*domain.TableMaintenanceResult, error)
+	RollbackTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error)
+	CopyTablePolicies(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.CopyPoliciesRequest) (*domain.CopyPoliciesResult, error)
+	TableDependents(ctx context.Context, catalogName string, schemaName, tableName string) ([]domain.DependentObject, error)
 	GetMetastoreSummary(ctx context.Context, catalogName string) (*domain.MetastoreSummary, error)
+	GetCatalogStats(ctx context.Context, catalogName string) (*domain.CatalogStats, error)
 }
 
 // === Catalog Management ===
@@ -55,7 +74,8 @@ func (h *APIHandler) GetCatalog(ctx context.Context, request GetCatalogRequestOb
 // ListSchemas implements the endpoint for listing schemas in the catalog.
 func (h *APIHandler) ListSchemas(ctx context.Context, request ListSchemasRequestObject) (ListSchemasResponseObject, error) {
 	page := pageFromParams(request.Params.MaxResults, request.Params.PageToken)
-	schemas, total, err := h.catalog.ListSchemas(ctx, string(request.CatalogName), page)
+	owner := ownerFilter(ctx, request.Params.Owner, request.Params.Mine)
+	schemas, total, err := h.catalog.ListSchemas(ctx, string(request.CatalogName), owner, page)
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +107,14 @@ func (h *APIHandler) CreateSchema(ctx context.Context, request CreateSchemaReque
 
 	principal := principalFromCtx(ctx)
 	result, err := h.catalog.CreateSchema(ctx, string(request.CatalogName), principal, domReq)
+	upserted := false
+	if err != nil && domain.IsAlreadyExists(err) && request.Params.Upsert != nil && *request.Params.Upsert {
+		upserted = true
+		result, err = h.catalog.UpdateSchema(ctx, string(request.CatalogName), principal, domReq.Name, domain.UpdateSchemaRequest{
+			Comment:    request.Body.Comment,
+			Properties: domReq.Properties,
+		})
+	}
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
@@ -94,11 +122,17 @@ func (h *APIHandler) CreateSchema(ctx context.Context, request CreateSchemaReque
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateSchema400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateSchema409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateSchema409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateSchema400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
 	}
+	if upserted {
+		return CreateSchema200JSONResponse{
+			Body:    schemaDetailToAPI(*result),
+			Headers: CreateSchema200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		}, nil
+	}
 	return CreateSchema201JSONResponse{
 		Body:    schemaDetailToAPI(*result),
 		Headers: CreateSchema201ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
@@ -118,14 +152,20 @@ func (h *APIHandler) GetSchema(ctx context.Context, request GetSchemaRequestObje
 	}
 	return GetSchema200JSONResponse{
 		Body:    schemaDetailToAPI(*result),
-		Headers: GetSchema200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: GetSchema200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // UpdateSchema implements the endpoint for updating schema metadata.
 func (h *APIHandler) UpdateSchema(ctx context.Context, request UpdateSchemaRequestObject) (UpdateSchemaResponseObject, error) {
+	ifMatch, err := ifMatchFromParams(request.Params.IfMatch)
+	if err != nil {
+		return UpdateSchema412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
 	domReq := domain.UpdateSchemaRequest{
-		Comment: request.Body.Comment,
+		Comment:            request.Body.Comment,
+		ExpectedRowVersion: ifMatch,
 	}
 	if request.Body.Properties != nil {
 		domReq.Properties = *request.Body.Properties
@@ -139,13 +179,15 @@ func (h *APIHandler) UpdateSchema(ctx context.Context, request UpdateSchemaReque
 			return UpdateSchema403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return UpdateSchema404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return UpdateSchema412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
 	}
 	return UpdateSchema200JSONResponse{
 		Body:    schemaDetailToAPI(*result),
-		Headers: UpdateSchema200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: UpdateSchema200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
@@ -165,7 +207,7 @@ func (h *APIHandler) DeleteSchema(ctx context.Context, request DeleteSchemaReque
 		case http.StatusNotFound:
 			return DeleteSchema404JSONResponse{NotFoundJSONResponse{Body: Error{Code: code, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case http.StatusConflict:
-			return DeleteSchema409JSONResponse{ConflictJSONResponse{Body: Error{Code: code, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return DeleteSchema409JSONResponse{ConflictJSONResponse{Body: Error{Code: code, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return DeleteSchema500JSONResponse{InternalErrorJSONResponse{Body: Error{Code: code, Message: err.Error()}, Headers: InternalErrorResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -176,7 +218,8 @@ func (h *APIHandler) DeleteSchema(ctx context.Context, request DeleteSchemaReque
 // ListTables implements the endpoint for listing tables in a schema.
 func (h *APIHandler) ListTables(ctx context.Context, request ListTablesRequestObject) (ListTablesResponseObject, error) {
 	page := pageFromParams(request.Params.MaxResults, request.Params.PageToken)
-	tables, total, err := h.catalog.ListTables(ctx, string(request.CatalogName), request.SchemaName, page)
+	owner := ownerFilter(ctx, request.Params.Owner, request.Params.Mine)
+	tables, total, err := h.catalog.ListTables(ctx, string(request.CatalogName), request.SchemaName, owner, page)
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.NotFoundError)):
@@ -196,6 +239,22 @@ func (h *APIHandler) ListTables(ctx context.Context, request ListTablesRequestOb
 	}, nil
 }
 
+// upsertTable sets the comment of an existing table, when one is given, for a
+// create with upsert=true. Only the comment can be updated in place, so a table whose type,
+// columns or partitioning differ from req is a conflict rather than a match.
+func (h *APIHandler) upsertTable(ctx context.Context, catalogName, principal, schemaName string, req domain.CreateTableRequest, comment *string) (*domain.TableDetail, error) {
+	existing, err := h.catalog.GetTable(ctx, catalogName, schemaName, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if diff := req.DefinitionDiff(existing); diff != "" {
+		return nil, domain.ErrConflict("table %q already exists with a different definition: %s", req.Name, diff)
+	}
+	return h.catalog.UpdateTable(ctx, catalogName, principal, schemaName, req.Name, domain.UpdateTableRequest{
+		Comment: comment,
+	})
+}
+
 // CreateTable implements the endpoint for creating a new table in a schema.
 func (h *APIHandler) CreateTable(ctx context.Context, request CreateTableRequestObject) (CreateTableResponseObject, error) {
 	var cols []domain.CreateColumnDef
@@ -224,9 +283,17 @@ func (h *APIHandler) CreateTable(ctx context.Context, request CreateTableRequest
 	if request.Body.LocationName != nil {
 		domReq.LocationName = *request.Body.LocationName
 	}
+	if request.Body.PartitionBy != nil {
+		domReq.PartitionBy = *request.Body.PartitionBy
+	}
 
 	principal := principalFromCtx(ctx)
 	result, err := h.catalog.CreateTable(ctx, string(request.CatalogName), principal, request.SchemaName, domReq)
+	upserted := false
+	if err != nil && domain.IsAlreadyExists(err) && request.Params.Upsert != nil && *request.Params.Upsert {
+		upserted = true
+		result, err = h.upsertTable(ctx, string(request.CatalogName), principal, request.SchemaName, domReq, request.Body.Comment)
+	}
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
@@ -234,13 +301,19 @@ func (h *APIHandler) CreateTable(ctx context.Context, request CreateTableRequest
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateTable400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateTable409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateTable409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return CreateTable400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateTable400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
 	}
+	if upserted {
+		return CreateTable200JSONResponse{
+			Body:    tableDetailToAPI(*result),
+			Headers: CreateTable200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		}, nil
+	}
 	return CreateTable201JSONResponse{
 		Body:    tableDetailToAPI(*result),
 		Headers: CreateTable201ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
@@ -260,13 +333,18 @@ func (h *APIHandler) GetTable(ctx context.Context, request GetTableRequestObject
 	}
 	return GetTable200JSONResponse{
 		Body:    tableDetailToAPI(*result),
-		Headers: GetTable200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: GetTable200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // UpdateTable implements the endpoint for updating table metadata.
 func (h *APIHandler) UpdateTable(ctx context.Context, request UpdateTableRequestObject) (UpdateTableResponseObject, error) {
-	domReq := domain.UpdateTableRequest{}
+	ifMatch, err := ifMatchFromParams(request.Params.IfMatch)
+	if err != nil {
+		return UpdateTable412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
+	domReq := domain.UpdateTableRequest{ExpectedRowVersion: ifMatch}
 	if request.Body.Comment != nil {
 		domReq.Comment = request.Body.Comment
 	}
@@ -285,25 +363,101 @@ func (h *APIHandler) UpdateTable(ctx context.Context, request UpdateTableRequest
 			return UpdateTable403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return UpdateTable404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return UpdateTable412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
 	}
 	return UpdateTable200JSONResponse{
 		Body:    tableDetailToAPI(*result),
-		Headers: UpdateTable200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: UpdateTable200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// RenameTable implements the endpoint for renaming a table within its schema.
+func (h *APIHandler) RenameTable(ctx context.Context, request RenameTableRequestObject) (RenameTableResponseObject, error) {
+	domReq := domain.RenameRequest{NewName: request.Body.NewName}
+	if request.Body.Force != nil {
+		domReq.Force = *request.Body.Force
+	}
+
+	principal := principalFromCtx(ctx)
+	result, err := h.catalog.RenameTable(ctx, string(request.CatalogName), principal, request.SchemaName, request.TableName, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return RenameTable403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return RenameTable404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return RenameTable400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return RenameTable409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return RenameTable200JSONResponse{
+		Body:    tableDetailToAPI(*result),
+		Headers: RenameTable200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// RegisterDirectory implements the endpoint for registering the files under a
+// storage prefix as external tables.
+func (h *APIHandler) RegisterDirectory(ctx context.Context, request RegisterDirectoryRequestObject) (RegisterDirectoryResponseObject, error) {
+	domReq := domain.RegisterDirectoryRequest{
+		SchemaName:   request.Body.SchemaName,
+		LocationName: request.Body.LocationName,
+		Path:         request.Body.Path,
+	}
+	if request.Body.CatalogName != nil {
+		domReq.CatalogName = *request.Body.CatalogName
+	}
+	if request.Body.FileFormat != nil {
+		domReq.FileFormat = string(*request.Body.FileFormat)
+	}
+	if request.Body.TableName != nil {
+		domReq.TableName = *request.Body.TableName
+	}
+
+	principal := principalFromCtx(ctx)
+	result, err := h.catalog.RegisterDirectory(ctx, principal, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return RegisterDirectory403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return RegisterDirectory404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return RegisterDirectory400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return RegisterDirectory200JSONResponse{
+		Body:    registerDirectoryResultToAPI(result),
+		Headers: RegisterDirectory200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // DeleteTable implements the endpoint for deleting a table by name.
 func (h *APIHandler) DeleteTable(ctx context.Context, request DeleteTableRequestObject) (DeleteTableResponseObject, error) {
+	cascade := false
+	if request.Params.Cascade != nil {
+		cascade = *request.Params.Cascade
+	}
+
 	principal := principalFromCtx(ctx)
-	if err := h.catalog.DeleteTable(ctx, string(request.CatalogName), principal, request.SchemaName, request.TableName); err != nil {
+	if err := h.catalog.DeleteTable(ctx, string(request.CatalogName), principal, request.SchemaName, request.TableName, cascade); err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
 			return DeleteTable403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return DeleteTable404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return DeleteTable409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
@@ -382,6 +536,157 @@ func (h *APIHandler) ProfileTable(ctx context.Context, request ProfileTableReque
 	}, nil
 }
 
+// CompactTable implements the endpoint for merging a table's small data files.
+func (h *APIHandler) CompactTable(ctx context.Context, request CompactTableRequestObject) (CompactTableResponseObject, error) {
+	principal := principalFromCtx(ctx)
+	result, err := h.catalog.CompactTable(ctx, string(request.CatalogName), principal, request.SchemaName, request.TableName)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CompactTable403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return CompactTable404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return CompactTable400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return CompactTable200JSONResponse{
+		Body:    tableMaintenanceResultToAPI(result),
+		Headers: CompactTable200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// VacuumTable implements the endpoint for expiring old snapshots and deleting
+// the files they alone referenced.
+func (h *APIHandler) VacuumTable(ctx context.Context, request VacuumTableRequestObject) (VacuumTableResponseObject, error) {
+	olderThan := domain.DefaultVacuumOlderThan
+	if request.Body != nil && request.Body.OlderThan != nil {
+		d, err := domain.ParseSnapshotAge(*request.Body.OlderThan)
+		if err != nil {
+			return VacuumTable400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		}
+		olderThan = d
+	}
+
+	principal := principalFromCtx(ctx)
+	result, err := h.catalog.VacuumTable(ctx, string(request.CatalogName), principal, request.SchemaName, request.TableName, olderThan)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return VacuumTable403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return VacuumTable404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return VacuumTable400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return VacuumTable200JSONResponse{
+		Body:    tableMaintenanceResultToAPI(result),
+		Headers: VacuumTable200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// RollbackTable implements the endpoint for previewing or applying a rollback
+// of a table's data to an earlier snapshot.
+func (h *APIHandler) RollbackTable(ctx context.Context, request RollbackTableRequestObject) (RollbackTableResponseObject, error) {
+	req := domain.RollbackTableRequest{
+		SnapshotID: request.Body.SnapshotId,
+		Timestamp:  request.Body.Timestamp,
+	}
+	if request.Body.Confirm != nil {
+		req.Confirm = *request.Body.Confirm
+	}
+
+	principal := principalFromCtx(ctx)
+	result, err := h.catalog.RollbackTable(ctx, string(request.CatalogName), principal, request.SchemaName, request.TableName, req)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return RollbackTable403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return RollbackTable404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return RollbackTable400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return RollbackTable200JSONResponse{
+		Body:    tableRollbackResultToAPI(result),
+		Headers: RollbackTable200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// CopyTablePolicies implements the endpoint for copying a table's grants,
+// row filters, column masks and tags to another table.
+func (h *APIHandler) CopyTablePolicies(ctx context.Context, request CopyTablePoliciesRequestObject) (CopyTablePoliciesResponseObject, error) {
+	req := domain.CopyPoliciesRequest{
+		TargetSchema: request.Body.TargetSchema,
+		TargetTable:  request.Body.TargetTable,
+	}
+	if request.Body.DryRun != nil {
+		req.DryRun = *request.Body.DryRun
+	}
+
+	principal := principalFromCtx(ctx)
+	result, err := h.catalog.CopyTablePolicies(ctx, string(request.CatalogName), principal, request.SchemaName, request.TableName, req)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CopyTablePolicies403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return CopyTablePolicies404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return CopyTablePolicies400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return CopyTablePolicies200JSONResponse{
+		Body:    copyPoliciesResultToAPI(result),
+		Headers: CopyTablePolicies200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// GetTableImpact implements the endpoint for listing the views and models
+// that read a table.
+func (h *APIHandler) GetTableImpact(ctx context.Context, request GetTableImpactRequestObject) (GetTableImpactResponseObject, error) {
+	deps, err := h.catalog.TableDependents(ctx, string(request.CatalogName), request.SchemaName, request.TableName)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.NotFoundError)):
+			return GetTableImpact404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return GetTableImpact200JSONResponse{
+		Body:    TableImpact{Dependents: dependentObjectsToAPI(deps)},
+		Headers: GetTableImpact200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// GetCatalogStats implements the endpoint for retrieving catalog statistics.
+func (h *APIHandler) GetCatalogStats(ctx context.Context, request GetCatalogStatsRequestObject) (GetCatalogStatsResponseObject, error) {
+	stats, err := h.catalog.GetCatalogStats(ctx, string(request.CatalogName))
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.NotFoundError)):
+			return GetCatalogStats404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return GetCatalogStats200JSONResponse{
+		Body:    catalogStatsToAPI(*stats),
+		Headers: GetCatalogStats200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // GetMetastoreSummary implements the endpoint for retrieving the metastore summary.
 func (h *APIHandler) GetMetastoreSummary(ctx context.Context, request GetMetastoreSummaryRequestObject) (GetMetastoreSummaryResponseObject, error) {
 	summary, err := h.catalog.GetMetastoreSummary(ctx, string(request.CatalogName))
@@ -39,7 +39,7 @@ func (h *APIHandler) RegisterCatalog(ctx context.Context, request RegisterCatalo
 		case errors.As(err, new(*domain.ValidationError)):
 			return RegisterCatalog400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return RegisterCatalog409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return RegisterCatalog409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return RegisterCatalog400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -87,16 +87,22 @@ func (h *APIHandler) GetCatalogRegistration(ctx context.Context, request GetCata
 	}
 	return GetCatalogRegistration200JSONResponse{
 		Body:    catalogRegistrationToAPI(*result),
-		Headers: GetCatalogRegistration200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: GetCatalogRegistration200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // UpdateCatalogRegistration implements the endpoint for updating a catalog registration.
 func (h *APIHandler) UpdateCatalogRegistration(ctx context.Context, request UpdateCatalogRegistrationRequestObject) (UpdateCatalogRegistrationResponseObject, error) {
+	ifMatch, err := ifMatchFromParams(request.Params.IfMatch)
+	if err != nil {
+		return UpdateCatalogRegistration412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
 	domReq := domain.UpdateCatalogRegistrationRequest{
-		Comment:  request.Body.Comment,
-		DataPath: request.Body.DataPath,
-		DSN:      request.Body.Dsn,
+		Comment:            request.Body.Comment,
+		DataPath:           request.Body.DataPath,
+		DSN:                request.Body.Dsn,
+		ExpectedRowVersion: ifMatch,
 	}
 
 	result, err := h.catalogRegistration.Update(ctx, string(request.CatalogName), domReq)
@@ -106,18 +112,35 @@ func (h *APIHandler) UpdateCatalogRegistration(ctx context.Context, request Upda
 			return UpdateCatalogRegistration403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return UpdateCatalogRegistration404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return UpdateCatalogRegistration412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
 	}
 	return UpdateCatalogRegistration200JSONResponse{
 		Body:    catalogRegistrationToAPI(*result),
-		Headers: UpdateCatalogRegistration200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: UpdateCatalogRegistration200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // DeleteCatalogRegistration implements the endpoint for deleting a catalog registration.
 func (h *APIHandler) DeleteCatalogRegistration(ctx context.Context, request DeleteCatalogRegistrationRequestObject) (DeleteCatalogRegistrationResponseObject, error) {
+	pending, err := h.submitApproval(ctx, domain.ApprovalOpDeleteCatalog, map[string]string{"catalog_name": string(request.CatalogName)})
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return DeleteCatalogRegistration403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	if pending != nil {
+		return DeleteCatalogRegistration202JSONResponse{
+			Body:    approvalToAPI(*pending),
+			Headers: DeleteCatalogRegistration202ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		}, nil
+	}
 	if err := h.catalogRegistration.Delete(ctx, string(request.CatalogName)); err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
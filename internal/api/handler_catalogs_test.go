@@ -145,7 +145,7 @@ func TestHandler_RegisterCatalog(t *testing.T) {
 		{
 			name: "conflict returns 409",
 			svcFn: func(_ context.Context, _ domain.CreateCatalogRequest) (*domain.CatalogRegistration, error) {
-				return nil, domain.ErrConflict("catalog already exists")
+				return nil, domain.ErrAlreadyExists("catalog already exists")
 			},
 			assertFn: func(t *testing.T, resp RegisterCatalogResponseObject, err error) {
 				t.Helper()
@@ -153,6 +153,8 @@ func TestHandler_RegisterCatalog(t *testing.T) {
 				conflict, ok := resp.(RegisterCatalog409JSONResponse)
 				require.True(t, ok, "expected 409 response, got %T", resp)
 				assert.Equal(t, int32(409), conflict.Body.Code)
+				require.NotNil(t, conflict.Body.ErrorCode)
+				assert.Equal(t, ErrorErrorCode("RESOURCE_EXISTS"), *conflict.Body.ErrorCode)
 			},
 		},
 	}
@@ -18,6 +18,9 @@ type computeEndpointService interface {
 	Assign(ctx context.Context, principal string, endpointName string, req domain.CreateComputeAssignmentRequest) (*domain.ComputeAssignment, error)
 	Unassign(ctx context.Context, principal string, assignmentID string) error
 	HealthCheck(ctx context.Context, principal string, endpointName string) (*domain.ComputeEndpointHealthResult, error)
+	ListAgents(ctx context.Context, principal, endpointName string) ([]domain.ComputeEndpointAgent, error)
+	AddAgent(ctx context.Context, principal, endpointName string, req domain.CreateComputeEndpointAgentRequest) (*domain.ComputeEndpointAgent, error)
+	RemoveAgent(ctx context.Context, principal, endpointName, agentID string) error
 }
 
 // === Compute Endpoints ===
@@ -69,7 +72,7 @@ func (h *APIHandler) CreateComputeEndpoint(ctx context.Context, req CreateComput
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateComputeEndpoint400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateComputeEndpoint409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateComputeEndpoint409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateComputeEndpoint400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -94,16 +97,22 @@ func (h *APIHandler) GetComputeEndpoint(ctx context.Context, req GetComputeEndpo
 	}
 	return GetComputeEndpoint200JSONResponse{
 		Body:    computeEndpointToAPI(*result),
-		Headers: GetComputeEndpoint200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: GetComputeEndpoint200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // UpdateComputeEndpoint implements the endpoint for updating a compute endpoint.
 func (h *APIHandler) UpdateComputeEndpoint(ctx context.Context, req UpdateComputeEndpointRequestObject) (UpdateComputeEndpointResponseObject, error) {
+	ifMatch, err := ifMatchFromParams(req.Params.IfMatch)
+	if err != nil {
+		return UpdateComputeEndpoint412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
 	domReq := domain.UpdateComputeEndpointRequest{
-		URL:         req.Body.Url,
-		MaxMemoryGB: req.Body.MaxMemoryGb,
-		AuthToken:   req.Body.AuthToken,
+		URL:                req.Body.Url,
+		MaxMemoryGB:        req.Body.MaxMemoryGb,
+		AuthToken:          req.Body.AuthToken,
+		ExpectedRowVersion: ifMatch,
 	}
 	if req.Body.Size != nil {
 		s := string(*req.Body.Size)
@@ -123,13 +132,15 @@ func (h *APIHandler) UpdateComputeEndpoint(ctx context.Context, req UpdateComput
 			return UpdateComputeEndpoint403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return UpdateComputeEndpoint404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return UpdateComputeEndpoint412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
 	}
 	return UpdateComputeEndpoint200JSONResponse{
 		Body:    computeEndpointToAPI(*result),
-		Headers: UpdateComputeEndpoint200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: UpdateComputeEndpoint200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
@@ -198,7 +209,7 @@ func (h *APIHandler) CreateComputeAssignment(ctx context.Context, req CreateComp
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateComputeAssignment400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateComputeAssignment409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateComputeAssignment409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateComputeAssignment400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -271,6 +282,73 @@ func (h *APIHandler) DeleteComputeAssignment(ctx context.Context, req DeleteComp
 	return DeleteComputeAssignment204Response{}, nil
 }
 
+// ListComputeEndpointAgents implements the endpoint for listing the agents of a compute endpoint.
+func (h *APIHandler) ListComputeEndpointAgents(ctx context.Context, req ListComputeEndpointAgentsRequestObject) (ListComputeEndpointAgentsResponseObject, error) {
+	principal := principalFromCtx(ctx)
+	agents, err := h.computeEndpoints.ListAgents(ctx, principal, req.EndpointName)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ListComputeEndpointAgents403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return ListComputeEndpointAgents404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	data := make([]ComputeEndpointAgent, len(agents))
+	for i, a := range agents {
+		data[i] = computeEndpointAgentToAPI(a)
+	}
+	return ListComputeEndpointAgents200JSONResponse{
+		Body:    ComputeEndpointAgentList{Data: &data},
+		Headers: ListComputeEndpointAgents200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// CreateComputeEndpointAgent implements the endpoint for adding an agent URL to a compute endpoint.
+func (h *APIHandler) CreateComputeEndpointAgent(ctx context.Context, req CreateComputeEndpointAgentRequestObject) (CreateComputeEndpointAgentResponseObject, error) {
+	principal := principalFromCtx(ctx)
+	result, err := h.computeEndpoints.AddAgent(ctx, principal, req.EndpointName, domain.CreateComputeEndpointAgentRequest{URL: req.Body.Url})
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CreateComputeEndpointAgent403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return CreateComputeEndpointAgent404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return CreateComputeEndpointAgent400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return CreateComputeEndpointAgent409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return CreateComputeEndpointAgent201JSONResponse{
+		Body:    computeEndpointAgentToAPI(*result),
+		Headers: CreateComputeEndpointAgent201ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// DeleteComputeEndpointAgent implements the endpoint for removing an agent URL from a compute endpoint.
+func (h *APIHandler) DeleteComputeEndpointAgent(ctx context.Context, req DeleteComputeEndpointAgentRequestObject) (DeleteComputeEndpointAgentResponseObject, error) {
+	principal := principalFromCtx(ctx)
+	if err := h.computeEndpoints.RemoveAgent(ctx, principal, req.EndpointName, req.AgentId); err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return DeleteComputeEndpointAgent403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return DeleteComputeEndpointAgent404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return DeleteComputeEndpointAgent204Response{
+		Headers: DeleteComputeEndpointAgent204ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // === Compute Endpoint Mappers ===
 
 // computeEndpointToAPI converts a domain ComputeEndpoint to the API type.
@@ -298,9 +376,41 @@ func computeEndpointToAPI(ep domain.ComputeEndpoint) ComputeEndpoint {
 	if ep.MaxMemoryGB != nil {
 		resp.MaxMemoryGb = ep.MaxMemoryGB
 	}
+	if ep.Load != nil {
+		l := computeEndpointLoadToAPI(*ep.Load)
+		resp.Load = &l
+	}
 	return resp
 }
 
+func computeEndpointLoadToAPI(l domain.ComputeEndpointLoad) ComputeEndpointLoad {
+	hint := ComputeEndpointLoadScalingHint(l.ScalingHint())
+	ct := l.CheckedAt
+	return ComputeEndpointLoad{
+		Reachable:            &l.Reachable,
+		ActiveQueries:        &l.ActiveQueries,
+		WaitingQueries:       &l.WaitingQueries,
+		MaxConcurrentQueries: &l.MaxConcurrentQueries,
+		QueuedJobs:           &l.QueuedJobs,
+		RunningJobs:          &l.RunningJobs,
+		MemoryUsedMb:         &l.MemoryUsedMB,
+		ScalingHint:          &hint,
+		LastError:            optStr(l.LastError),
+		LastHeartbeatAt:      l.LastHeartbeatAt,
+		CheckedAt:            &ct,
+	}
+}
+
+func computeEndpointAgentToAPI(a domain.ComputeEndpointAgent) ComputeEndpointAgent {
+	ct := a.CreatedAt
+	return ComputeEndpointAgent{
+		Id:         &a.ID,
+		EndpointId: &a.EndpointID,
+		Url:        &a.URL,
+		CreatedAt:  &ct,
+	}
+}
+
 func computeAssignmentToAPI(a domain.ComputeAssignment) ComputeAssignment {
 	ct := a.CreatedAt
 	pt := ComputeAssignmentPrincipalType(a.PrincipalType)
@@ -23,6 +23,9 @@ type mockComputeEndpointService struct {
 	assignFn          func(ctx context.Context, principal string, endpointName string, req domain.CreateComputeAssignmentRequest) (*domain.ComputeAssignment, error)
 	unassignFn        func(ctx context.Context, principal string, assignmentID string) error
 	healthCheckFn     func(ctx context.Context, principal string, endpointName string) (*domain.ComputeEndpointHealthResult, error)
+	listAgentsFn      func(ctx context.Context, principal, endpointName string) ([]domain.ComputeEndpointAgent, error)
+	addAgentFn        func(ctx context.Context, principal, endpointName string, req domain.CreateComputeEndpointAgentRequest) (*domain.ComputeEndpointAgent, error)
+	removeAgentFn     func(ctx context.Context, principal, endpointName, agentID string) error
 }
 
 func (m *mockComputeEndpointService) List(ctx context.Context, principal string, page domain.PageRequest) ([]domain.ComputeEndpoint, int64, error) {
@@ -88,6 +91,27 @@ func (m *mockComputeEndpointService) HealthCheck(ctx context.Context, principal
 	return m.healthCheckFn(ctx, principal, endpointName)
 }
 
+func (m *mockComputeEndpointService) ListAgents(ctx context.Context, principal, endpointName string) ([]domain.ComputeEndpointAgent, error) {
+	if m.listAgentsFn == nil {
+		panic("mockComputeEndpointService.ListAgents called but not configured")
+	}
+	return m.listAgentsFn(ctx, principal, endpointName)
+}
+
+func (m *mockComputeEndpointService) AddAgent(ctx context.Context, principal, endpointName string, req domain.CreateComputeEndpointAgentRequest) (*domain.ComputeEndpointAgent, error) {
+	if m.addAgentFn == nil {
+		panic("mockComputeEndpointService.AddAgent called but not configured")
+	}
+	return m.addAgentFn(ctx, principal, endpointName, req)
+}
+
+func (m *mockComputeEndpointService) RemoveAgent(ctx context.Context, principal, endpointName, agentID string) error {
+	if m.removeAgentFn == nil {
+		panic("mockComputeEndpointService.RemoveAgent called but not configured")
+	}
+	return m.removeAgentFn(ctx, principal, endpointName, agentID)
+}
+
 // === Helpers ===
 
 func computeTestCtx() context.Context {
@@ -155,6 +179,38 @@ func TestHandler_ListComputeEndpoints(t *testing.T) {
 				assert.Equal(t, "analytics-xl", *(*ok200.Body.Data)[0].Name)
 			},
 		},
+		{
+			name:   "polled load is reported with scaling hint",
+			params: ListComputeEndpointsParams{},
+			svcFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.ComputeEndpoint, int64, error) {
+				ep := sampleComputeEndpoint()
+				ep.Type = "REMOTE"
+				ep.Load = &domain.ComputeEndpointLoad{
+					EndpointID:           ep.ID,
+					Reachable:            true,
+					ActiveQueries:        4,
+					WaitingQueries:       3,
+					MaxConcurrentQueries: 4,
+					LastHeartbeatAt:      &computeFixedTime,
+					CheckedAt:            computeFixedTime,
+				}
+				return []domain.ComputeEndpoint{ep}, 1, nil
+			},
+			assertFn: func(t *testing.T, resp ListComputeEndpointsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ListComputeEndpoints200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.Len(t, *ok200.Body.Data, 1)
+				load := (*ok200.Body.Data)[0].Load
+				require.NotNil(t, load)
+				assert.Equal(t, int64(4), *load.ActiveQueries)
+				assert.Equal(t, int64(3), *load.WaitingQueries)
+				assert.Equal(t, ComputeEndpointLoadScalingHint("SCALE_UP"), *load.ScalingHint)
+				assert.Equal(t, computeFixedTime, *load.LastHeartbeatAt)
+				assert.Nil(t, load.LastError)
+			},
+		},
 		{
 			name:   "empty list returns 200 with empty data",
 			params: ListComputeEndpointsParams{},
@@ -793,3 +849,78 @@ func TestHandler_GetComputeEndpointHealth(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_ComputeEndpointAgents(t *testing.T) {
+	t.Parallel()
+
+	agent := domain.ComputeEndpointAgent{
+		ID: "agent-1", EndpointID: "ep-1", URL: "grpc://compute-2.example.com:9444", CreatedAt: computeFixedTime,
+	}
+
+	t.Run("list returns 200 with agents", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockComputeEndpointService{
+			listAgentsFn: func(_ context.Context, _ string, endpointName string) ([]domain.ComputeEndpointAgent, error) {
+				assert.Equal(t, "analytics-xl", endpointName)
+				return []domain.ComputeEndpointAgent{agent}, nil
+			},
+		}
+		handler := &APIHandler{computeEndpoints: svc}
+		resp, err := handler.ListComputeEndpointAgents(computeTestCtx(), ListComputeEndpointAgentsRequestObject{EndpointName: "analytics-xl"})
+		require.NoError(t, err)
+		ok200, ok := resp.(ListComputeEndpointAgents200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		require.Len(t, *ok200.Body.Data, 1)
+		assert.Equal(t, "grpc://compute-2.example.com:9444", *(*ok200.Body.Data)[0].Url)
+	})
+
+	t.Run("create returns 201", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockComputeEndpointService{
+			addAgentFn: func(_ context.Context, _ string, _ string, req domain.CreateComputeEndpointAgentRequest) (*domain.ComputeEndpointAgent, error) {
+				assert.Equal(t, agent.URL, req.URL)
+				return &agent, nil
+			},
+		}
+		handler := &APIHandler{computeEndpoints: svc}
+		resp, err := handler.CreateComputeEndpointAgent(computeTestCtx(), CreateComputeEndpointAgentRequestObject{
+			EndpointName: "analytics-xl",
+			Body:         &CreateComputeEndpointAgentJSONRequestBody{Url: agent.URL},
+		})
+		require.NoError(t, err)
+		created, ok := resp.(CreateComputeEndpointAgent201JSONResponse)
+		require.True(t, ok, "expected 201 response, got %T", resp)
+		assert.Equal(t, "agent-1", *created.Body.Id)
+	})
+
+	t.Run("create duplicate returns 409", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockComputeEndpointService{
+			addAgentFn: func(_ context.Context, _ string, _ string, _ domain.CreateComputeEndpointAgentRequest) (*domain.ComputeEndpointAgent, error) {
+				return nil, domain.ErrConflict("resource already exists")
+			},
+		}
+		handler := &APIHandler{computeEndpoints: svc}
+		resp, err := handler.CreateComputeEndpointAgent(computeTestCtx(), CreateComputeEndpointAgentRequestObject{
+			EndpointName: "analytics-xl",
+			Body:         &CreateComputeEndpointAgentJSONRequestBody{Url: agent.URL},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(CreateComputeEndpointAgent409JSONResponse)
+		assert.True(t, ok, "expected 409 response, got %T", resp)
+	})
+
+	t.Run("delete unknown agent returns 404", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockComputeEndpointService{
+			removeAgentFn: func(_ context.Context, _ string, _ string, agentID string) error {
+				return domain.ErrNotFound("compute endpoint agent %q not found", agentID)
+			},
+		}
+		handler := &APIHandler{computeEndpoints: svc}
+		resp, err := handler.DeleteComputeEndpointAgent(computeTestCtx(), DeleteComputeEndpointAgentRequestObject{EndpointName: "analytics-xl", AgentId: "missing"})
+		require.NoError(t, err)
+		_, ok := resp.(DeleteComputeEndpointAgent404JSONResponse)
+		assert.True(t, ok, "expected 404 response, got %T", resp)
+	})
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"duck-demo/internal/domain"
+)
+
+// applyLockService defines the apply lock operations used by the API handler.
+type applyLockService interface {
+	Get(ctx context.Context) (*domain.ApplyLock, error)
+	Acquire(ctx context.Context, principal string, req domain.AcquireApplyLockRequest) (*domain.ApplyLock, error)
+	Release(ctx context.Context, leaseID string) error
+	Renew(ctx context.Context, leaseID string, ttl time.Duration) (*domain.ApplyLock, error)
+}
+
+// batchOpGroupMembersSync advertises PUT /groups/{groupId}/members, which
+// replaces a group's membership in one call.
+const batchOpGroupMembersSync = "group_members_sync"
+
+// specVersion returns info.version of the embedded OpenAPI spec.
+var specVersion = sync.OnceValues(func() (string, error) {
+	swagger, err := GetSwagger()
+	if err != nil {
+		return "", err
+	}
+	return swagger.Info.Version, nil
+})
+
+// GetCapabilities implements the endpoint for the capability handshake. A
+// feature is reported as enabled when the handler was wired with its service.
+func (h *APIHandler) GetCapabilities(_ context.Context, _ GetCapabilitiesRequestObject) (GetCapabilitiesResponseObject, error) {
+	version, err := specVersion()
+	if err != nil {
+		return nil, err
+	}
+	batchOps := []string{}
+	if h.groups != nil {
+		batchOps = append(batchOps, batchOpGroupMembersSync)
+	}
+	return GetCapabilities200JSONResponse{
+		Body: Capabilities{
+			ApiVersion: version,
+			Features: CapabilityFeatures{
+				Models:   h.models != nil,
+				Macros:   h.macros != nil,
+				Semantic: h.semantics != nil,
+				Compute:  h.computeEndpoints != nil,
+				Webhooks: h.webhooks != nil,
+			},
+			BatchOperations: batchOps,
+		},
+		Headers: GetCapabilities200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// GetApplyLock implements the endpoint for reading the current apply lock.
+func (h *APIHandler) GetApplyLock(ctx context.Context, _ GetApplyLockRequestObject) (GetApplyLockResponseObject, error) {
+	lock, err := h.applyLocks.Get(ctx)
+	if err != nil {
+		var notFoundErr *domain.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return GetApplyLock404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		}
+		return nil, err
+	}
+	return GetApplyLock200JSONResponse{
+		Body:    applyLockToAPI(*lock),
+		Headers: GetApplyLock200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// AcquireApplyLock implements the endpoint for acquiring the apply lock.
+func (h *APIHandler) AcquireApplyLock(ctx context.Context, req AcquireApplyLockRequestObject) (AcquireApplyLockResponseObject, error) {
+	domReq := domain.AcquireApplyLockRequest{}
+	if req.Body.Owner != nil {
+		domReq.Owner = *req.Body.Owner
+	}
+	if req.Body.TtlSeconds != nil {
+		domReq.TTL = time.Duration(*req.Body.TtlSeconds) * time.Second
+	}
+	if req.Body.Force != nil {
+		domReq.Force = *req.Body.Force
+	}
+
+	cp, _ := domain.PrincipalFromContext(ctx)
+	lock, err := h.applyLocks.Acquire(ctx, cp.Name, domReq)
+	if err != nil {
+		var validErr *domain.ValidationError
+		var conflictErr *domain.ConflictError
+		switch {
+		case errors.As(err, &validErr):
+			return AcquireApplyLock400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, &conflictErr):
+			return AcquireApplyLock409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return AcquireApplyLock200JSONResponse{
+		Body:    applyLockToAPI(*lock),
+		Headers: AcquireApplyLock200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// ReleaseApplyLock implements the endpoint for releasing the apply lock.
+func (h *APIHandler) ReleaseApplyLock(ctx context.Context, req ReleaseApplyLockRequestObject) (ReleaseApplyLockResponseObject, error) {
+	if err := h.applyLocks.Release(ctx, req.Params.LeaseId); err != nil {
+		var validErr *domain.ValidationError
+		var notFoundErr *domain.NotFoundError
+		switch {
+		case errors.As(err, &validErr):
+			return ReleaseApplyLock400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, &notFoundErr):
+			return ReleaseApplyLock404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return ReleaseApplyLock204Response{}, nil
+}
+
+// RenewApplyLock implements the endpoint for renewing the apply lock.
+func (h *APIHandler) RenewApplyLock(ctx context.Context, req RenewApplyLockRequestObject) (RenewApplyLockResponseObject, error) {
+	var ttl time.Duration
+	if req.Body.TtlSeconds != nil {
+		ttl = time.Duration(*req.Body.TtlSeconds) * time.Second
+	}
+	lock, err := h.applyLocks.Renew(ctx, req.Body.LeaseId, ttl)
+	if err != nil {
+		var validErr *domain.ValidationError
+		var notFoundErr *domain.NotFoundError
+		switch {
+		case errors.As(err, &validErr):
+			return RenewApplyLock400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, &notFoundErr):
+			return RenewApplyLock404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return RenewApplyLock200JSONResponse{
+		Body:    applyLockToAPI(*lock),
+		Headers: RenewApplyLock200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// applyLockToAPI converts a domain ApplyLock to the API type.
+func applyLockToAPI(l domain.ApplyLock) ApplyLock {
+	return ApplyLock{
+		LeaseId:       l.LeaseID,
+		Owner:         l.Owner,
+		PrincipalName: l.PrincipalName,
+		AcquiredAt:    l.AcquiredAt,
+		ExpiresAt:     l.ExpiresAt,
+	}
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+type mockApplyLockService struct {
+	getFn     func(ctx context.Context) (*domain.ApplyLock, error)
+	acquireFn func(ctx context.Context, principal string, req domain.AcquireApplyLockRequest) (*domain.ApplyLock, error)
+	releaseFn func(ctx context.Context, leaseID string) error
+	renewFn   func(ctx context.Context, leaseID string, ttl time.Duration) (*domain.ApplyLock, error)
+}
+
+func (m *mockApplyLockService) Get(ctx context.Context) (*domain.ApplyLock, error) {
+	if m.getFn == nil {
+		panic("mockApplyLockService.Get called but not configured")
+	}
+	return m.getFn(ctx)
+}
+
+func (m *mockApplyLockService) Acquire(ctx context.Context, principal string, req domain.AcquireApplyLockRequest) (*domain.ApplyLock, error) {
+	if m.acquireFn == nil {
+		panic("mockApplyLockService.Acquire called but not configured")
+	}
+	return m.acquireFn(ctx, principal, req)
+}
+
+func (m *mockApplyLockService) Release(ctx context.Context, leaseID string) error {
+	if m.releaseFn == nil {
+		panic("mockApplyLockService.Release called but not configured")
+	}
+	return m.releaseFn(ctx, leaseID)
+}
+
+func (m *mockApplyLockService) Renew(ctx context.Context, leaseID string, ttl time.Duration) (*domain.ApplyLock, error) {
+	if m.renewFn == nil {
+		panic("mockApplyLockService.Renew called but not configured")
+	}
+	return m.renewFn(ctx, leaseID, ttl)
+}
+
+func TestHandler_AcquireApplyLock(t *testing.T) {
+	t.Parallel()
+
+	ttl := int64(60)
+	force := true
+	owner := "alice@laptop"
+
+	tests := []struct {
+		name     string
+		body     AcquireApplyLockJSONRequestBody
+		svcFn    func(ctx context.Context, principal string, req domain.AcquireApplyLockRequest) (*domain.ApplyLock, error)
+		assertFn func(t *testing.T, resp AcquireApplyLockResponseObject, err error)
+	}{
+		{
+			name: "happy path maps request and returns 200",
+			body: AcquireApplyLockJSONRequestBody{Owner: &owner, TtlSeconds: &ttl, Force: &force},
+			svcFn: func(_ context.Context, principal string, req domain.AcquireApplyLockRequest) (*domain.ApplyLock, error) {
+				assert.Equal(t, "test-user", principal)
+				assert.Equal(t, time.Minute, req.TTL)
+				assert.True(t, req.Force)
+				return &domain.ApplyLock{LeaseID: "lease-1", Owner: req.Owner, PrincipalName: principal}, nil
+			},
+			assertFn: func(t *testing.T, resp AcquireApplyLockResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(AcquireApplyLock200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, "lease-1", ok200.Body.LeaseId)
+				assert.Equal(t, "alice@laptop", ok200.Body.Owner)
+			},
+		},
+		{
+			name: "held lock returns 409",
+			body: AcquireApplyLockJSONRequestBody{},
+			svcFn: func(_ context.Context, _ string, _ domain.AcquireApplyLockRequest) (*domain.ApplyLock, error) {
+				return nil, domain.ErrConflict("apply lock is held by %q", "bob@ci")
+			},
+			assertFn: func(t *testing.T, resp AcquireApplyLockResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				conflict, ok := resp.(AcquireApplyLock409JSONResponse)
+				require.True(t, ok, "expected 409 response, got %T", resp)
+				assert.Contains(t, conflict.Body.Message, "bob@ci")
+			},
+		},
+		{
+			name: "invalid ttl returns 400",
+			body: AcquireApplyLockJSONRequestBody{TtlSeconds: &ttl},
+			svcFn: func(_ context.Context, _ string, _ domain.AcquireApplyLockRequest) (*domain.ApplyLock, error) {
+				return nil, domain.ErrValidation("lock ttl must not exceed 2h0m0s")
+			},
+			assertFn: func(t *testing.T, resp AcquireApplyLockResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(AcquireApplyLock400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := &APIHandler{applyLocks: &mockApplyLockService{acquireFn: tt.svcFn}}
+			body := tt.body
+			resp, err := handler.AcquireApplyLock(storageTestCtx(), AcquireApplyLockRequestObject{Body: &body})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_ReleaseApplyLock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("held lease returns 204", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{applyLocks: &mockApplyLockService{releaseFn: func(_ context.Context, leaseID string) error {
+			assert.Equal(t, "lease-1", leaseID)
+			return nil
+		}}}
+		resp, err := handler.ReleaseApplyLock(storageTestCtx(), ReleaseApplyLockRequestObject{Params: ReleaseApplyLockParams{LeaseId: "lease-1"}})
+		require.NoError(t, err)
+		_, ok := resp.(ReleaseApplyLock204Response)
+		assert.True(t, ok, "expected 204 response, got %T", resp)
+	})
+
+	t.Run("lost lease returns 404", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{applyLocks: &mockApplyLockService{releaseFn: func(_ context.Context, _ string) error {
+			return domain.ErrNotFound("apply lock lease %q is not held", "lease-1")
+		}}}
+		resp, err := handler.ReleaseApplyLock(storageTestCtx(), ReleaseApplyLockRequestObject{Params: ReleaseApplyLockParams{LeaseId: "lease-1"}})
+		require.NoError(t, err)
+		_, ok := resp.(ReleaseApplyLock404JSONResponse)
+		assert.True(t, ok, "expected 404 response, got %T", resp)
+	})
+}
+
+func TestHandler_RenewApplyLock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("held lease returns 200", func(t *testing.T) {
+		t.Parallel()
+		expires := time.Now().Add(5 * time.Minute)
+		handler := &APIHandler{applyLocks: &mockApplyLockService{renewFn: func(_ context.Context, leaseID string, ttl time.Duration) (*domain.ApplyLock, error) {
+			assert.Equal(t, "lease-1", leaseID)
+			assert.Equal(t, 5*time.Minute, ttl)
+			return &domain.ApplyLock{Name: "apply", LeaseID: leaseID, Owner: "ci", ExpiresAt: expires}, nil
+		}}}
+		resp, err := handler.RenewApplyLock(storageTestCtx(), RenewApplyLockRequestObject{Body: &RenewApplyLockJSONRequestBody{LeaseId: "lease-1", TtlSeconds: helpersIntPtr(300)}})
+		require.NoError(t, err)
+		ok, isOK := resp.(RenewApplyLock200JSONResponse)
+		require.True(t, isOK, "expected 200 response, got %T", resp)
+		assert.Equal(t, "lease-1", ok.Body.LeaseId)
+	})
+
+	t.Run("lost lease returns 404", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{applyLocks: &mockApplyLockService{renewFn: func(_ context.Context, _ string, _ time.Duration) (*domain.ApplyLock, error) {
+			return nil, domain.ErrNotFound("apply lock lease %q is not held", "lease-1")
+		}}}
+		resp, err := handler.RenewApplyLock(storageTestCtx(), RenewApplyLockRequestObject{Body: &RenewApplyLockJSONRequestBody{LeaseId: "lease-1"}})
+		require.NoError(t, err)
+		_, ok := resp.(RenewApplyLock404JSONResponse)
+		assert.True(t, ok, "expected 404 response, got %T", resp)
+	})
+}
+
+func TestHandler_GetCapabilities(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports wired features and batch operations", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{
+			groups:           &mockGroupService{},
+			models:           &mockModelService{},
+			semantics:        &mockSemanticService{},
+			computeEndpoints: &mockComputeEndpointService{},
+			webhooks:         &mockWebhookService{},
+		}
+		resp, err := handler.GetCapabilities(storageTestCtx(), GetCapabilitiesRequestObject{})
+		require.NoError(t, err)
+		ok, isOK := resp.(GetCapabilities200JSONResponse)
+		require.True(t, isOK, "expected 200 response, got %T", resp)
+
+		swagger, err := GetSwagger()
+		require.NoError(t, err)
+		assert.Equal(t, swagger.Info.Version, ok.Body.ApiVersion)
+		assert.Equal(t, CapabilityFeatures{Models: true, Semantic: true, Compute: true, Webhooks: true}, ok.Body.Features)
+		assert.Equal(t, []string{"group_members_sync"}, ok.Body.BatchOperations)
+	})
+
+	t.Run("unwired services are reported as disabled", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{}
+		resp, err := handler.GetCapabilities(storageTestCtx(), GetCapabilitiesRequestObject{})
+		require.NoError(t, err)
+		ok, isOK := resp.(GetCapabilities200JSONResponse)
+		require.True(t, isOK, "expected 200 response, got %T", resp)
+		assert.Equal(t, CapabilityFeatures{}, ok.Body.Features)
+		assert.NotNil(t, ok.Body.BatchOperations)
+		assert.Empty(t, ok.Body.BatchOperations)
+	})
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"duck-demo/internal/domain"
+)
+
+// defaultGrantService defines the default grant operations used by the API handler.
+type defaultGrantService interface {
+	Create(ctx context.Context, req domain.CreateDefaultGrantRequest) (*domain.DefaultGrant, error)
+	List(ctx context.Context, filter domain.DefaultGrantFilter) ([]domain.DefaultGrant, int64, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// === Default Grants ===
+
+// ListDefaultGrants implements the endpoint for listing default grants.
+func (h *APIHandler) ListDefaultGrants(ctx context.Context, req ListDefaultGrantsRequestObject) (ListDefaultGrantsResponseObject, error) {
+	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	grants, total, err := h.defaultGrants.List(ctx, domain.DefaultGrantFilter{
+		CatalogName: req.Params.CatalogName,
+		SchemaName:  req.Params.SchemaName,
+		Page:        page,
+	})
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ListDefaultGrants403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	data := make([]DefaultGrant, len(grants))
+	for i, g := range grants {
+		data[i] = defaultGrantToAPI(g)
+	}
+	npt := domain.NextPageToken(page.Offset(), page.Limit(), total)
+	return ListDefaultGrants200JSONResponse{
+		Body:    PaginatedDefaultGrants{Data: &data, NextPageToken: optStr(npt)},
+		Headers: ListDefaultGrants200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// CreateDefaultGrant implements the endpoint for creating a default grant.
+func (h *APIHandler) CreateDefaultGrant(ctx context.Context, req CreateDefaultGrantRequestObject) (CreateDefaultGrantResponseObject, error) {
+	domReq := domain.CreateDefaultGrantRequest{
+		CatalogName:   req.Body.CatalogName,
+		ObjectType:    string(req.Body.ObjectType),
+		PrincipalID:   req.Body.PrincipalId,
+		PrincipalType: string(req.Body.PrincipalType),
+		Privilege:     string(req.Body.Privilege),
+	}
+	if req.Body.SchemaName != nil {
+		domReq.SchemaName = *req.Body.SchemaName
+	}
+	result, err := h.defaultGrants.Create(ctx, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.ValidationError)):
+			return CreateDefaultGrant400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CreateDefaultGrant403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return CreateDefaultGrant409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return CreateDefaultGrant201JSONResponse{
+		Body:    defaultGrantToAPI(*result),
+		Headers: CreateDefaultGrant201ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// DeleteDefaultGrant implements the endpoint for deleting a default grant.
+func (h *APIHandler) DeleteDefaultGrant(ctx context.Context, req DeleteDefaultGrantRequestObject) (DeleteDefaultGrantResponseObject, error) {
+	if err := h.defaultGrants.Delete(ctx, req.DefaultGrantId); err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return DeleteDefaultGrant403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return DeleteDefaultGrant404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return DeleteDefaultGrant204Response{}, nil
+}
+
+// defaultGrantToAPI converts a domain DefaultGrant to the API type. A
+// catalog-wide grant has no schema_name.
+func defaultGrantToAPI(g domain.DefaultGrant) DefaultGrant {
+	return DefaultGrant{
+		Id:            g.ID,
+		CatalogName:   g.CatalogName,
+		SchemaName:    strPtrIfNonEmpty(g.SchemaName),
+		ObjectType:    DefaultGrantObjectType(g.ObjectType),
+		PrincipalId:   g.PrincipalID,
+		PrincipalType: DefaultGrantPrincipalType(g.PrincipalType),
+		Privilege:     PrivilegeName(g.Privilege),
+		CreatedBy:     g.CreatedBy,
+		CreatedAt:     g.CreatedAt,
+	}
+}
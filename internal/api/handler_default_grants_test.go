@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+type mockDefaultGrantService struct {
+	createFn func(ctx context.Context, req domain.CreateDefaultGrantRequest) (*domain.DefaultGrant, error)
+	listFn   func(ctx context.Context, filter domain.DefaultGrantFilter) ([]domain.DefaultGrant, int64, error)
+	deleteFn func(ctx context.Context, id string) error
+}
+
+func (m *mockDefaultGrantService) Create(ctx context.Context, req domain.CreateDefaultGrantRequest) (*domain.DefaultGrant, error) {
+	if m.createFn == nil {
+		panic("mockDefaultGrantService.Create called but not configured")
+	}
+	return m.createFn(ctx, req)
+}
+
+func (m *mockDefaultGrantService) List(ctx context.Context, filter domain.DefaultGrantFilter) ([]domain.DefaultGrant, int64, error) {
+	if m.listFn == nil {
+		panic("mockDefaultGrantService.List called but not configured")
+	}
+	return m.listFn(ctx, filter)
+}
+
+func (m *mockDefaultGrantService) Delete(ctx context.Context, id string) error {
+	if m.deleteFn == nil {
+		panic("mockDefaultGrantService.Delete called but not configured")
+	}
+	return m.deleteFn(ctx, id)
+}
+
+func TestHandler_CreateDefaultGrant(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, req domain.CreateDefaultGrantRequest) (*domain.DefaultGrant, error)
+		assertFn func(t *testing.T, resp CreateDefaultGrantResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 201",
+			svcFn: func(_ context.Context, req domain.CreateDefaultGrantRequest) (*domain.DefaultGrant, error) {
+				assert.Equal(t, "analytics", req.SchemaName)
+				assert.Equal(t, domain.SecurableTable, req.ObjectType)
+				return &domain.DefaultGrant{
+					ID: "dg-1", CatalogName: req.CatalogName, SchemaName: req.SchemaName, ObjectType: req.ObjectType,
+					PrincipalID: req.PrincipalID, PrincipalType: req.PrincipalType, Privilege: req.Privilege, CreatedBy: "admin",
+				}, nil
+			},
+			assertFn: func(t *testing.T, resp CreateDefaultGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				created, ok := resp.(CreateDefaultGrant201JSONResponse)
+				require.True(t, ok, "expected 201 response, got %T", resp)
+				assert.Equal(t, "dg-1", created.Body.Id)
+				require.NotNil(t, created.Body.SchemaName)
+				assert.Equal(t, "analytics", *created.Body.SchemaName)
+				assert.Equal(t, PrivilegeName("SELECT"), created.Body.Privilege)
+			},
+		},
+		{
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _ domain.CreateDefaultGrantRequest) (*domain.DefaultGrant, error) {
+				return nil, domain.ErrValidation("object_type must be 'schema' or 'table'")
+			},
+			assertFn: func(t *testing.T, resp CreateDefaultGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateDefaultGrant400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+			},
+		},
+		{
+			name: "non-admin returns 403",
+			svcFn: func(_ context.Context, _ domain.CreateDefaultGrantRequest) (*domain.DefaultGrant, error) {
+				return nil, domain.ErrAccessDenied("admin privileges required")
+			},
+			assertFn: func(t *testing.T, resp CreateDefaultGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateDefaultGrant403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+			},
+		},
+		{
+			name: "duplicate returns 409",
+			svcFn: func(_ context.Context, _ domain.CreateDefaultGrantRequest) (*domain.DefaultGrant, error) {
+				return nil, domain.ErrConflict("default grant already exists")
+			},
+			assertFn: func(t *testing.T, resp CreateDefaultGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateDefaultGrant409JSONResponse)
+				require.True(t, ok, "expected 409 response, got %T", resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := &APIHandler{defaultGrants: &mockDefaultGrantService{createFn: tt.svcFn}}
+			schema := "analytics"
+			body := CreateDefaultGrantJSONRequestBody{
+				CatalogName:   "main",
+				SchemaName:    &schema,
+				ObjectType:    CreateDefaultGrantRequestObjectTypeTable,
+				PrincipalId:   "550e8400-e29b-41d4-a716-446655440002",
+				PrincipalType: CreateDefaultGrantRequestPrincipalTypeGroup,
+				Privilege:     PrivilegeName("SELECT"),
+			}
+			resp, err := handler.CreateDefaultGrant(storageTestCtx(), CreateDefaultGrantRequestObject{Body: &body})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_ListDefaultGrants(t *testing.T) {
+	t.Parallel()
+
+	catalog := "main"
+	handler := &APIHandler{defaultGrants: &mockDefaultGrantService{listFn: func(_ context.Context, filter domain.DefaultGrantFilter) ([]domain.DefaultGrant, int64, error) {
+		require.NotNil(t, filter.CatalogName)
+		assert.Equal(t, "main", *filter.CatalogName)
+		assert.Nil(t, filter.SchemaName)
+		return []domain.DefaultGrant{{ID: "dg-1", CatalogName: "main", ObjectType: domain.SecurableSchema, Privilege: domain.PrivUseSchema}}, 1, nil
+	}}}
+
+	resp, err := handler.ListDefaultGrants(storageTestCtx(), ListDefaultGrantsRequestObject{Params: ListDefaultGrantsParams{CatalogName: &catalog}})
+	require.NoError(t, err)
+	ok200, ok := resp.(ListDefaultGrants200JSONResponse)
+	require.True(t, ok, "expected 200 response, got %T", resp)
+	require.Len(t, *ok200.Body.Data, 1)
+	assert.Nil(t, (*ok200.Body.Data)[0].SchemaName, "catalog-wide grants have no schema_name")
+}
+
+func TestHandler_DeleteDefaultGrant(t *testing.T) {
+	t.Parallel()
+
+	t.Run("existing default grant returns 204", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{defaultGrants: &mockDefaultGrantService{deleteFn: func(_ context.Context, id string) error {
+			assert.Equal(t, "dg-1", id)
+			return nil
+		}}}
+		resp, err := handler.DeleteDefaultGrant(storageTestCtx(), DeleteDefaultGrantRequestObject{DefaultGrantId: "dg-1"})
+		require.NoError(t, err)
+		_, ok := resp.(DeleteDefaultGrant204Response)
+		require.True(t, ok, "expected 204 response, got %T", resp)
+	})
+
+	t.Run("unknown default grant returns 404", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{defaultGrants: &mockDefaultGrantService{deleteFn: func(_ context.Context, id string) error {
+			return domain.ErrNotFound("default grant %q not found", id)
+		}}}
+		resp, err := handler.DeleteDefaultGrant(storageTestCtx(), DeleteDefaultGrantRequestObject{DefaultGrantId: "missing"})
+		require.NoError(t, err)
+		_, ok := resp.(DeleteDefaultGrant404JSONResponse)
+		require.True(t, ok, "expected 404 response, got %T", resp)
+	})
+}
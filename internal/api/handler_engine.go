@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"duck-demo/internal/domain"
+)
+
+// versionService defines the version reporting operations used by the API handler.
+type versionService interface {
+	Get(ctx context.Context, includeCompute bool) (*domain.ServerVersion, error)
+}
+
+// === Engine ===
+
+// GetServerVersion implements the endpoint for reporting server, engine and
+// compute endpoint versions.
+func (h *APIHandler) GetServerVersion(ctx context.Context, req GetServerVersionRequestObject) (GetServerVersionResponseObject, error) {
+	includeCompute := req.Params.IncludeCompute != nil && *req.Params.IncludeCompute
+	v, err := h.versions.Get(ctx, includeCompute)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return GetServerVersion403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	apiVersion, err := specVersion()
+	if err != nil {
+		return nil, err
+	}
+	return GetServerVersion200JSONResponse{
+		Body:    serverVersionToAPI(*v, apiVersion),
+		Headers: GetServerVersion200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// GetOpenAPIVersion implements the endpoint for reporting the version and
+// hash of the served OpenAPI spec.
+func (h *APIHandler) GetOpenAPIVersion(_ context.Context, _ GetOpenAPIVersionRequestObject) (GetOpenAPIVersionResponseObject, error) {
+	version, err := specVersion()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := specHash()
+	if err != nil {
+		return nil, err
+	}
+	return GetOpenAPIVersion200JSONResponse{
+		Body:    OpenAPIVersion{Version: version, Hash: hash},
+		Headers: GetOpenAPIVersion200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// serverVersionToAPI converts a domain ServerVersion to the API type.
+func serverVersionToAPI(v domain.ServerVersion, apiVersion string) ServerVersion {
+	extensions := make([]ExtensionVersion, len(v.Extensions))
+	for i, e := range v.Extensions {
+		extensions[i] = ExtensionVersion{Name: e.Name, Version: optStr(e.Version)}
+	}
+	out := ServerVersion{
+		AppVersion:             v.AppVersion,
+		Commit:                 optStr(v.Commit),
+		GoVersion:              v.GoVersion,
+		ApiVersion:             apiVersion,
+		DuckdbVersion:          v.DuckDBVersion,
+		Extensions:             extensions,
+		MetastoreSchemaVersion: v.MetastoreSchemaVersion,
+	}
+	if v.ComputeEndpoints != nil {
+		endpoints := make([]ComputeEndpointVersion, len(v.ComputeEndpoints))
+		for i, ep := range v.ComputeEndpoints {
+			endpoints[i] = ComputeEndpointVersion{
+				Name:          ep.Name,
+				Type:          ep.Type,
+				DuckdbVersion: optStr(ep.DuckDBVersion),
+				Error:         optStr(ep.Error),
+			}
+		}
+		out.ComputeEndpoints = &endpoints
+	}
+	return out
+}
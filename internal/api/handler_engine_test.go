@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/apispec"
+	"duck-demo/internal/domain"
+)
+
+type mockVersionService struct {
+	getFn func(ctx context.Context, includeCompute bool) (*domain.ServerVersion, error)
+}
+
+func (m *mockVersionService) Get(ctx context.Context, includeCompute bool) (*domain.ServerVersion, error) {
+	if m.getFn == nil {
+		panic("mockVersionService.Get called but not configured")
+	}
+	return m.getFn(ctx, includeCompute)
+}
+
+func TestHandler_GetServerVersion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports versions", func(t *testing.T) {
+		t.Parallel()
+		var gotInclude bool
+		handler := &APIHandler{versions: &mockVersionService{getFn: func(_ context.Context, includeCompute bool) (*domain.ServerVersion, error) {
+			gotInclude = includeCompute
+			return &domain.ServerVersion{
+				AppVersion:             "v1.4.0",
+				GoVersion:              "go1.25.1",
+				DuckDBVersion:          "v1.1.3",
+				Extensions:             []domain.ExtensionVersion{{Name: "ducklake", Version: "0.1.0"}},
+				MetastoreSchemaVersion: 51,
+				ComputeEndpoints:       []domain.ComputeEndpointVersion{{Name: "offline", Type: "REMOTE", Error: "connection refused"}},
+			}, nil
+		}}}
+		include := true
+		resp, err := handler.GetServerVersion(storageTestCtx(), GetServerVersionRequestObject{Params: GetServerVersionParams{IncludeCompute: &include}})
+		require.NoError(t, err)
+		ok200, ok := resp.(GetServerVersion200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.True(t, gotInclude)
+
+		body := ok200.Body
+		assert.Equal(t, "v1.1.3", body.DuckdbVersion)
+		assert.NotEmpty(t, body.ApiVersion)
+		assert.Nil(t, body.Commit)
+		assert.Equal(t, int64(51), body.MetastoreSchemaVersion)
+		require.Len(t, body.Extensions, 1)
+		assert.Equal(t, "ducklake", body.Extensions[0].Name)
+		require.NotNil(t, body.ComputeEndpoints)
+		require.Len(t, *body.ComputeEndpoints, 1)
+		ep := (*body.ComputeEndpoints)[0]
+		assert.Nil(t, ep.DuckdbVersion)
+		require.NotNil(t, ep.Error)
+		assert.Equal(t, "connection refused", *ep.Error)
+	})
+
+	t.Run("compute denied returns 403", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{versions: &mockVersionService{getFn: func(_ context.Context, _ bool) (*domain.ServerVersion, error) {
+			return nil, domain.ErrAccessDenied("MANAGE_COMPUTE required")
+		}}}
+		resp, err := handler.GetServerVersion(storageTestCtx(), GetServerVersionRequestObject{})
+		require.NoError(t, err)
+		_, ok := resp.(GetServerVersion403JSONResponse)
+		assert.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_GetOpenAPIVersion(t *testing.T) {
+	t.Parallel()
+	handler := &APIHandler{}
+	resp, err := handler.GetOpenAPIVersion(storageTestCtx(), GetOpenAPIVersionRequestObject{})
+	require.NoError(t, err)
+	ok200, ok := resp.(GetOpenAPIVersion200JSONResponse)
+	require.True(t, ok, "expected 200 response, got %T", resp)
+
+	swagger, err := GetSwagger()
+	require.NoError(t, err)
+	hash, err := apispec.Hash(swagger)
+	require.NoError(t, err)
+	assert.Equal(t, swagger.Info.Version, ok200.Body.Version)
+	assert.Equal(t, hash, ok200.Body.Hash)
+
+	// Decoding the embedded spec again yields the same hash.
+	reloaded, err := GetSwagger()
+	require.NoError(t, err)
+	rehash, err := apispec.Hash(reloaded)
+	require.NoError(t, err)
+	assert.Equal(t, hash, rehash)
+}
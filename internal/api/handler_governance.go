@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"duck-demo/internal/domain"
 )
@@ -10,11 +12,13 @@ import (
 // auditService defines the audit operations used by the API handler.
 type auditService interface {
 	List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int64, error)
+	Archive(ctx context.Context, before *time.Time) (*domain.AuditArchiveResult, error)
 }
 
 // queryHistoryService defines the query history operations used by the API handler.
 type queryHistoryService interface {
 	List(ctx context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error)
+	Purge(ctx context.Context, before *time.Time) (*domain.QueryHistoryPurgeResult, error)
 }
 
 // searchService defines the search operations used by the API handler.
@@ -40,6 +44,8 @@ type tagService interface {
 	DeleteTag(ctx context.Context, principal string, id string) error
 	AssignTag(ctx context.Context, principal string, req domain.AssignTagRequest) (*domain.TagAssignment, error)
 	UnassignTag(ctx context.Context, principal string, id string) error
+	AssignTagBatch(ctx context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error)
+	UnassignTagBatch(ctx context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error)
 }
 
 // === Audit Logs ===
@@ -53,6 +59,10 @@ func (h *APIHandler) ListAuditLogs(ctx context.Context, req ListAuditLogsRequest
 		Status:        req.Params.Status,
 		Page:          page,
 	}
+	if filter.Status == nil && req.Params.Result != nil {
+		status := strings.ToUpper(string(*req.Params.Result))
+		filter.Status = &status
+	}
 
 	entries, total, err := h.audit.List(ctx, filter)
 	if err != nil {
@@ -76,6 +86,61 @@ func (h *APIHandler) ListAuditLogs(ctx context.Context, req ListAuditLogsRequest
 	}, nil
 }
 
+// ArchiveAuditLogs implements the endpoint for archiving and deleting audit
+// log entries recorded before a cutoff.
+func (h *APIHandler) ArchiveAuditLogs(ctx context.Context, req ArchiveAuditLogsRequestObject) (ArchiveAuditLogsResponseObject, error) {
+	var before *time.Time
+	if req.Body != nil {
+		before = req.Body.Before
+		if req.Body.OlderThan != nil {
+			if before != nil {
+				return ArchiveAuditLogs400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: "older_than and before are mutually exclusive"}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			}
+			d, err := domain.ParseSnapshotAge(*req.Body.OlderThan)
+			if err != nil {
+				return ArchiveAuditLogs400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			}
+			cutoff := time.Now().Add(-d)
+			before = &cutoff
+		}
+	}
+
+	result, err := h.audit.Archive(ctx, before)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ArchiveAuditLogs403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return ArchiveAuditLogs400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	archives := make([]AuditArchive, len(result.Archives))
+	for i, a := range result.Archives {
+		archives[i] = AuditArchive{
+			Location:       a.Location,
+			EntryCount:     a.EntryCount,
+			FirstEntryId:   a.FirstEntryID,
+			LastEntryId:    a.LastEntryID,
+			FirstCreatedAt: a.FirstCreatedAt,
+			LastCreatedAt:  a.LastCreatedAt,
+			PrevHash:       a.PrevHash,
+			Hash:           a.Hash,
+		}
+	}
+	return ArchiveAuditLogs200JSONResponse{
+		Body: AuditArchiveResult{
+			Before:        result.Before,
+			ArchivedCount: result.Archived,
+			DeletedCount:  result.Deleted,
+			Archives:      archives,
+		},
+		Headers: ArchiveAuditLogs200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // === Query History ===
 
 // ListQueryHistory implements the endpoint for listing query history entries.
@@ -84,8 +149,10 @@ func (h *APIHandler) ListQueryHistory(ctx context.Context, req ListQueryHistoryR
 	filter := domain.QueryHistoryFilter{
 		PrincipalName: req.Params.PrincipalName,
 		Status:        req.Params.Status,
+		TableName:     req.Params.TableName,
 		From:          req.Params.From,
 		To:            req.Params.To,
+		Mine:          req.Params.Mine != nil && *req.Params.Mine,
 		Page:          page,
 	}
 
@@ -111,6 +178,39 @@ func (h *APIHandler) ListQueryHistory(ctx context.Context, req ListQueryHistoryR
 	}, nil
 }
 
+// PurgeQueryHistory implements the endpoint for deleting query history
+// recorded before a cutoff.
+func (h *APIHandler) PurgeQueryHistory(ctx context.Context, req PurgeQueryHistoryRequestObject) (PurgeQueryHistoryResponseObject, error) {
+	before := req.Params.Before
+	if req.Params.OlderThan != nil {
+		if before != nil {
+			return PurgeQueryHistory400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: "older_than and before are mutually exclusive"}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		}
+		d, err := domain.ParseSnapshotAge(*req.Params.OlderThan)
+		if err != nil {
+			return PurgeQueryHistory400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		}
+		cutoff := time.Now().Add(-d)
+		before = &cutoff
+	}
+
+	result, err := h.queryHistory.Purge(ctx, before)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return PurgeQueryHistory403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return PurgeQueryHistory400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return PurgeQueryHistory200JSONResponse{
+		Body:    QueryHistoryPurgeResult{DeletedCount: result.Deleted, Before: result.Before},
+		Headers: PurgeQueryHistory200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // === Search ===
 
 // SearchCatalog implements the endpoint for searching catalog objects.
@@ -327,7 +427,7 @@ func (h *APIHandler) CreateTag(ctx context.Context, req CreateTagRequestObject)
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateTag400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateTag409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateTag409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
@@ -371,7 +471,7 @@ func (h *APIHandler) CreateTagAssignment(ctx context.Context, req CreateTagAssig
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateTagAssignment409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateTagAssignment409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
@@ -382,6 +482,46 @@ func (h *APIHandler) CreateTagAssignment(ctx context.Context, req CreateTagAssig
 	}, nil
 }
 
+// BatchCreateTagAssignments implements the endpoint for assigning a tag to several securables at once.
+func (h *APIHandler) BatchCreateTagAssignments(ctx context.Context, req BatchCreateTagAssignmentsRequestObject) (BatchCreateTagAssignmentsResponseObject, error) {
+	cp, _ := domain.PrincipalFromContext(ctx)
+	batch, err := h.tags.AssignTagBatch(ctx, cp.Name, batchTagAssignmentRequestFromAPI(req.TagId, req.Body.Assignments))
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.ValidationError)):
+			return BatchCreateTagAssignments400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return BatchCreateTagAssignments404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return BatchCreateTagAssignments200JSONResponse{
+		Body:    tagAssignmentBatchToAPI(*batch),
+		Headers: BatchCreateTagAssignments200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// BatchDeleteTagAssignments implements the endpoint for removing a tag from several securables at once.
+func (h *APIHandler) BatchDeleteTagAssignments(ctx context.Context, req BatchDeleteTagAssignmentsRequestObject) (BatchDeleteTagAssignmentsResponseObject, error) {
+	cp, _ := domain.PrincipalFromContext(ctx)
+	batch, err := h.tags.UnassignTagBatch(ctx, cp.Name, batchTagAssignmentRequestFromAPI(req.TagId, req.Body.Assignments))
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.ValidationError)):
+			return BatchDeleteTagAssignments400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return BatchDeleteTagAssignments404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return BatchDeleteTagAssignments200JSONResponse{
+		Body:    tagAssignmentBatchToAPI(*batch),
+		Headers: BatchDeleteTagAssignments200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // DeleteTagAssignment implements the endpoint for removing a tag assignment.
 func (h *APIHandler) DeleteTagAssignment(ctx context.Context, req DeleteTagAssignmentRequestObject) (DeleteTagAssignmentResponseObject, error) {
 	cp, _ := domain.PrincipalFromContext(ctx)
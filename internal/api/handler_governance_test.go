@@ -14,7 +14,8 @@ import (
 // === Mocks ===
 
 type mockAuditService struct {
-	listFn func(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int64, error)
+	listFn    func(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int64, error)
+	archiveFn func(ctx context.Context, before *time.Time) (*domain.AuditArchiveResult, error)
 }
 
 func (m *mockAuditService) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int64, error) {
@@ -24,8 +25,16 @@ func (m *mockAuditService) List(ctx context.Context, filter domain.AuditFilter)
 	return m.listFn(ctx, filter)
 }
 
+func (m *mockAuditService) Archive(ctx context.Context, before *time.Time) (*domain.AuditArchiveResult, error) {
+	if m.archiveFn == nil {
+		panic("mockAuditService.Archive called but not configured")
+	}
+	return m.archiveFn(ctx, before)
+}
+
 type mockQueryHistoryService struct {
-	listFn func(ctx context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error)
+	listFn  func(ctx context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error)
+	purgeFn func(ctx context.Context, before *time.Time) (*domain.QueryHistoryPurgeResult, error)
 }
 
 func (m *mockQueryHistoryService) List(ctx context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error) {
@@ -35,6 +44,13 @@ func (m *mockQueryHistoryService) List(ctx context.Context, filter domain.QueryH
 	return m.listFn(ctx, filter)
 }
 
+func (m *mockQueryHistoryService) Purge(ctx context.Context, before *time.Time) (*domain.QueryHistoryPurgeResult, error) {
+	if m.purgeFn == nil {
+		panic("mockQueryHistoryService.Purge called but not configured")
+	}
+	return m.purgeFn(ctx, before)
+}
+
 type mockSearchService struct {
 	searchFn func(ctx context.Context, query string, objectType *string, catalogName *string, page domain.PageRequest) ([]domain.SearchResult, int64, error)
 }
@@ -111,6 +127,8 @@ type mockTagService struct {
 	deleteTagFn   func(ctx context.Context, principal string, id string) error
 	assignTagFn   func(ctx context.Context, principal string, req domain.AssignTagRequest) (*domain.TagAssignment, error)
 	unassignTagFn func(ctx context.Context, principal string, id string) error
+	assignBatchFn func(ctx context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error)
+	removeBatchFn func(ctx context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error)
 }
 
 func (m *mockTagService) ListTags(ctx context.Context, page domain.PageRequest) ([]domain.Tag, int64, error) {
@@ -148,6 +166,20 @@ func (m *mockTagService) UnassignTag(ctx context.Context, principal string, id s
 	return m.unassignTagFn(ctx, principal, id)
 }
 
+func (m *mockTagService) AssignTagBatch(ctx context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error) {
+	if m.assignBatchFn == nil {
+		panic("mockTagService.AssignTagBatch called but not configured")
+	}
+	return m.assignBatchFn(ctx, principal, req)
+}
+
+func (m *mockTagService) UnassignTagBatch(ctx context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error) {
+	if m.removeBatchFn == nil {
+		panic("mockTagService.UnassignTagBatch called but not configured")
+	}
+	return m.removeBatchFn(ctx, principal, req)
+}
+
 // === Helpers ===
 
 func govTestCtx() context.Context {
@@ -256,6 +288,22 @@ func TestHandler_ListAuditLogs(t *testing.T) {
 				assert.Equal(t, "audit-1", *(*ok200.Body.Data)[0].Id)
 			},
 		},
+		{
+			name:   "result filter maps to status",
+			params: ListAuditLogsParams{Result: func() *ListAuditLogsParamsResult { r := ListAuditLogsParamsResult("denied"); return &r }()},
+			svcFn: func(_ context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int64, error) {
+				if filter.Status == nil || *filter.Status != "DENIED" {
+					return nil, 0, assert.AnError
+				}
+				return nil, 0, nil
+			},
+			assertFn: func(t *testing.T, resp ListAuditLogsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(ListAuditLogs200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+			},
+		},
 		{
 			name:   "service error propagates",
 			params: ListAuditLogsParams{},
@@ -280,6 +328,98 @@ func TestHandler_ListAuditLogs(t *testing.T) {
 	}
 }
 
+func TestHandler_ArchiveAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	before := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	archived := &domain.AuditArchiveResult{
+		Before:   before,
+		Archived: 2,
+		Deleted:  2,
+		Archives: []domain.AuditArchive{{
+			Location:     "s3://audit-bucket/archive/audit-20240115T093000Z-5e884898da28.ndjson",
+			EntryCount:   2,
+			FirstEntryID: "a1",
+			LastEntryID:  "a2",
+			Hash:         "sha256:5e88",
+		}},
+	}
+	tests := []struct {
+		name     string
+		body     *ArchiveAuditLogsJSONRequestBody
+		svcFn    func(ctx context.Context, before *time.Time) (*domain.AuditArchiveResult, error)
+		assertFn func(t *testing.T, resp ArchiveAuditLogsResponseObject, err error)
+	}{
+		{
+			name: "older_than becomes a cutoff",
+			body: &ArchiveAuditLogsJSONRequestBody{OlderThan: strPtr("365d")},
+			svcFn: func(_ context.Context, cutoff *time.Time) (*domain.AuditArchiveResult, error) {
+				require.NotNil(t, cutoff)
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, -365), *cutoff, time.Minute)
+				return archived, nil
+			},
+			assertFn: func(t *testing.T, resp ArchiveAuditLogsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ArchiveAuditLogs200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, int64(2), ok200.Body.ArchivedCount)
+				assert.Equal(t, int64(2), ok200.Body.DeletedCount)
+				require.Len(t, ok200.Body.Archives, 1)
+				assert.Equal(t, "a2", ok200.Body.Archives[0].LastEntryId)
+				assert.Equal(t, "sha256:5e88", ok200.Body.Archives[0].Hash)
+			},
+		},
+		{
+			name: "no body applies retention",
+			svcFn: func(_ context.Context, cutoff *time.Time) (*domain.AuditArchiveResult, error) {
+				assert.Nil(t, cutoff)
+				return nil, domain.ErrValidation("audit log archiving is not configured")
+			},
+			assertFn: func(t *testing.T, resp ArchiveAuditLogsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(ArchiveAuditLogs400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+			},
+		},
+		{
+			name: "older_than and before together returns 400",
+			body: &ArchiveAuditLogsJSONRequestBody{OlderThan: strPtr("365d"), Before: &before},
+			assertFn: func(t *testing.T, resp ArchiveAuditLogsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				badReq, ok := resp.(ArchiveAuditLogs400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+				assert.Contains(t, badReq.Body.Message, "mutually exclusive")
+			},
+		},
+		{
+			name: "access denied returns 403",
+			body: &ArchiveAuditLogsJSONRequestBody{Before: &before},
+			svcFn: func(_ context.Context, _ *time.Time) (*domain.AuditArchiveResult, error) {
+				return nil, domain.ErrAccessDenied("admin privileges required")
+			},
+			assertFn: func(t *testing.T, resp ArchiveAuditLogsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(ArchiveAuditLogs403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockAuditService{archiveFn: tt.svcFn}
+			handler := &APIHandler{audit: svc}
+			resp, err := handler.ArchiveAuditLogs(govTestCtx(), ArchiveAuditLogsRequestObject{Body: tt.body})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
 func TestHandler_ListQueryHistory(t *testing.T) {
 	t.Parallel()
 
@@ -305,6 +445,23 @@ func TestHandler_ListQueryHistory(t *testing.T) {
 				assert.Equal(t, "qh-1", *(*ok200.Body.Data)[0].Id)
 			},
 		},
+		{
+			name:   "filters pass through to service",
+			params: ListQueryHistoryParams{PrincipalName: strPtr("alice"), Status: strPtr("ERROR"), TableName: strPtr("orders"), Mine: boolPtr(true)},
+			svcFn: func(_ context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error) {
+				if filter.TableName == nil || *filter.TableName != "orders" || !filter.Mine ||
+					filter.Status == nil || *filter.Status != "ERROR" || filter.PrincipalName == nil || *filter.PrincipalName != "alice" {
+					return nil, 0, assert.AnError
+				}
+				return nil, 0, nil
+			},
+			assertFn: func(t *testing.T, resp ListQueryHistoryResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(ListQueryHistory200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+			},
+		},
 		{
 			name:   "service error propagates",
 			params: ListQueryHistoryParams{},
@@ -329,6 +486,109 @@ func TestHandler_ListQueryHistory(t *testing.T) {
 	}
 }
 
+func TestHandler_PurgeQueryHistory(t *testing.T) {
+	t.Parallel()
+
+	before := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		params   PurgeQueryHistoryParams
+		svcFn    func(ctx context.Context, before *time.Time) (*domain.QueryHistoryPurgeResult, error)
+		assertFn func(t *testing.T, resp PurgeQueryHistoryResponseObject, err error)
+	}{
+		{
+			name:   "older_than becomes a cutoff",
+			params: PurgeQueryHistoryParams{OlderThan: strPtr("90d")},
+			svcFn: func(_ context.Context, cutoff *time.Time) (*domain.QueryHistoryPurgeResult, error) {
+				require.NotNil(t, cutoff)
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, -90), *cutoff, time.Minute)
+				return &domain.QueryHistoryPurgeResult{Before: *cutoff, Deleted: 3}, nil
+			},
+			assertFn: func(t *testing.T, resp PurgeQueryHistoryResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(PurgeQueryHistory200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, int64(3), ok200.Body.DeletedCount)
+			},
+		},
+		{
+			name:   "before is passed through",
+			params: PurgeQueryHistoryParams{Before: &before},
+			svcFn: func(_ context.Context, cutoff *time.Time) (*domain.QueryHistoryPurgeResult, error) {
+				require.NotNil(t, cutoff)
+				assert.Equal(t, before, *cutoff)
+				return &domain.QueryHistoryPurgeResult{Before: *cutoff, Deleted: 1}, nil
+			},
+			assertFn: func(t *testing.T, resp PurgeQueryHistoryResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(PurgeQueryHistory200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, before, ok200.Body.Before)
+			},
+		},
+		{
+			name:   "without a cutoff the service applies retention",
+			params: PurgeQueryHistoryParams{},
+			svcFn: func(_ context.Context, cutoff *time.Time) (*domain.QueryHistoryPurgeResult, error) {
+				assert.Nil(t, cutoff)
+				return nil, domain.ErrValidation("no query history retention period is configured")
+			},
+			assertFn: func(t *testing.T, resp PurgeQueryHistoryResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(PurgeQueryHistory400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+			},
+		},
+		{
+			name:   "older_than and before together returns 400",
+			params: PurgeQueryHistoryParams{OlderThan: strPtr("90d"), Before: &before},
+			assertFn: func(t *testing.T, resp PurgeQueryHistoryResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				badReq, ok := resp.(PurgeQueryHistory400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+				assert.Contains(t, badReq.Body.Message, "mutually exclusive")
+			},
+		},
+		{
+			name:   "invalid older_than returns 400",
+			params: PurgeQueryHistoryParams{OlderThan: strPtr("0d")},
+			assertFn: func(t *testing.T, resp PurgeQueryHistoryResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(PurgeQueryHistory400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+			},
+		},
+		{
+			name:   "access denied returns 403",
+			params: PurgeQueryHistoryParams{OlderThan: strPtr("90d")},
+			svcFn: func(_ context.Context, _ *time.Time) (*domain.QueryHistoryPurgeResult, error) {
+				return nil, domain.ErrAccessDenied("admin privileges required")
+			},
+			assertFn: func(t *testing.T, resp PurgeQueryHistoryResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(PurgeQueryHistory403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockQueryHistoryService{purgeFn: tt.svcFn}
+			handler := &APIHandler{queryHistory: svc}
+			resp, err := handler.PurgeQueryHistory(govTestCtx(), PurgeQueryHistoryRequestObject{Params: tt.params})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
 func TestHandler_SearchCatalog(t *testing.T) {
 	t.Parallel()
 
@@ -929,6 +1189,72 @@ func TestHandler_CreateTagAssignment(t *testing.T) {
 	}
 }
 
+func TestHandler_BatchCreateTagAssignments(t *testing.T) {
+	t.Parallel()
+
+	email, backup := "email", "backup_email"
+	body := BatchCreateTagAssignmentsJSONRequestBody{Assignments: []CreateTagAssignmentRequest{
+		{SecurableType: "column", SecurableId: "table-1", ColumnName: &email},
+		{SecurableType: "column", SecurableId: "table-1", ColumnName: &backup},
+	}}
+
+	t.Run("maps items and per-item results", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockTagService{assignBatchFn: func(_ context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error) {
+			assert.Equal(t, "test-user", principal)
+			assert.Equal(t, "tag-1", req.TagID)
+			require.Len(t, req.Targets, 2)
+			assert.Equal(t, "column", req.Targets[1].SecurableType)
+			assert.Equal(t, "backup_email", *req.Targets[1].ColumnName)
+			id := "ta-1"
+			return &domain.TagAssignmentBatch{Applied: true, Results: []domain.TagAssignmentBatchResult{
+				{Target: req.Targets[0], Status: domain.TagBatchStatusAssigned, AssignmentID: &id},
+				{Target: req.Targets[1], Status: domain.TagBatchStatusUnchanged, AssignmentID: &id},
+			}}, nil
+		}}
+		handler := &APIHandler{tags: svc}
+		b := body
+		resp, err := handler.BatchCreateTagAssignments(govTestCtx(), BatchCreateTagAssignmentsRequestObject{TagId: "tag-1", Body: &b})
+		require.NoError(t, err)
+		ok, isOK := resp.(BatchCreateTagAssignments200JSONResponse)
+		require.True(t, isOK, "expected 200 response, got %T", resp)
+		assert.True(t, ok.Body.Applied)
+		require.Len(t, ok.Body.Results, 2)
+		assert.Equal(t, TagAssignmentBatchResultStatus("ASSIGNED"), ok.Body.Results[0].Status)
+		assert.Equal(t, "email", *ok.Body.Results[0].ColumnName)
+		assert.Equal(t, TagAssignmentBatchResultStatus("UNCHANGED"), ok.Body.Results[1].Status)
+	})
+
+	t.Run("unknown tag returns 404", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockTagService{assignBatchFn: func(_ context.Context, _ string, _ domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error) {
+			return nil, domain.ErrNotFound("tag %q not found", "tag-x")
+		}}
+		handler := &APIHandler{tags: svc}
+		b := body
+		resp, err := handler.BatchCreateTagAssignments(govTestCtx(), BatchCreateTagAssignmentsRequestObject{TagId: "tag-x", Body: &b})
+		require.NoError(t, err)
+		_, isNotFound := resp.(BatchCreateTagAssignments404JSONResponse)
+		assert.True(t, isNotFound, "expected 404 response, got %T", resp)
+	})
+}
+
+func TestHandler_BatchDeleteTagAssignments(t *testing.T) {
+	t.Parallel()
+
+	svc := &mockTagService{removeBatchFn: func(_ context.Context, _ string, _ domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error) {
+		return nil, domain.ErrValidation("at least one assignment is required")
+	}}
+	handler := &APIHandler{tags: svc}
+	resp, err := handler.BatchDeleteTagAssignments(govTestCtx(), BatchDeleteTagAssignmentsRequestObject{
+		TagId: "tag-1",
+		Body:  &BatchDeleteTagAssignmentsJSONRequestBody{},
+	})
+	require.NoError(t, err)
+	_, isBadRequest := resp.(BatchDeleteTagAssignments400JSONResponse)
+	assert.True(t, isBadRequest, "expected 400 response, got %T", resp)
+}
+
 func TestHandler_DeleteTagAssignment(t *testing.T) {
 	t.Parallel()
 
@@ -3,6 +3,7 @@ package api
 import (
 	"math"
 	"reflect"
+	"sort"
 
 	"duck-demo/internal/domain"
 )
@@ -33,11 +34,14 @@ func isNilService(v interface{}) bool {
 	}
 }
 
-// pageFromParams extracts a PageRequest from optional max_results/page_token params.
+// pageFromParams extracts a PageRequest from optional max_results/page_token
+// params, applying the configured default page size when max_results is unset
+// and clamping it to the configured maximum. Invalid sizes are rejected
+// earlier by middleware.PageSize.
 func pageFromParams(maxResults *MaxResults, pageToken *PageToken) domain.PageRequest {
-	p := domain.PageRequest{}
+	p := domain.PageRequest{MaxResults: domain.ClampPageSize(0)}
 	if maxResults != nil {
-		p.MaxResults = int(*maxResults)
+		p.MaxResults = domain.ClampPageSize(int(*maxResults))
 	}
 	if pageToken != nil {
 		p.PageToken = *pageToken
@@ -45,6 +49,16 @@ func pageFromParams(maxResults *MaxResults, pageToken *PageToken) domain.PageReq
 	return p
 }
 
+// ifMatchFromParams parses the If-Match header of a conditional update into
+// the row version it names. An absent header yields nil, making the update
+// unconditional.
+func ifMatchFromParams(ifMatch *IfMatch) (*int64, error) {
+	if ifMatch == nil {
+		return nil, nil
+	}
+	return domain.ParseIfMatch(*ifMatch)
+}
+
 // httpStatusFromError returns the HTTP status code for a domain error using
 // the centralized mapper. Unknown errors return 500 Internal Server Error.
 func httpStatusFromError(err error) int {
@@ -61,6 +75,7 @@ func errorCodeFromError(err error) int32 {
 
 func principalToAPI(p domain.Principal) Principal {
 	t := p.CreatedAt
+	u := p.UpdatedAt
 	pt := PrincipalType(p.Type)
 	return Principal{
 		Id:        &p.ID,
@@ -68,26 +83,80 @@ func principalToAPI(p domain.Principal) Principal {
 		Type:      &pt,
 		IsAdmin:   &p.IsAdmin,
 		CreatedAt: &t,
+		UpdatedAt: &u,
+	}
+}
+
+func principalImportResultsToAPI(results []domain.PrincipalImportResult) PrincipalImportResults {
+	out := PrincipalImportResults{Results: make([]PrincipalImportResult, len(results))}
+	for i, r := range results {
+		groups := r.GroupsAdded
+		if groups == nil {
+			groups = []string{}
+		}
+		out.Results[i] = PrincipalImportResult{
+			Name:        r.Name,
+			PrincipalId: r.PrincipalID,
+			Status:      PrincipalImportResultStatus(r.Status),
+			GroupsAdded: groups,
+		}
+		if r.Status == domain.PrincipalImportCreated {
+			out.Created++
+		} else {
+			out.Skipped++
+		}
+	}
+	return out
+}
+
+func principalQueryDefaultsToAPI(principalID string, d domain.PrincipalQueryDefaults) PrincipalQueryDefaults {
+	return PrincipalQueryDefaults{
+		PrincipalId:    principalID,
+		DefaultCatalog: d.DefaultCatalog,
+		DefaultSchema:  d.DefaultSchema,
+		Timezone:       d.Timezone,
 	}
 }
 
 func groupToAPI(g domain.Group) Group {
 	t := g.CreatedAt
+	u := g.UpdatedAt
 	return Group{
 		Id:          &g.ID,
 		Name:        &g.Name,
 		Description: &g.Description,
 		CreatedAt:   &t,
+		UpdatedAt:   &u,
 	}
 }
 
 func groupMemberToAPI(m domain.GroupMember, groupID string) GroupMember {
 	mt := GroupMemberMemberType(m.MemberType)
-	return GroupMember{
+	out := GroupMember{
 		GroupId:    &groupID,
 		MemberType: &mt,
 		MemberId:   &m.MemberID,
+		MemberName: optStr(m.MemberName),
+	}
+	if m.MemberKind != "" {
+		kind := GroupMemberMemberKind(m.MemberKind)
+		out.MemberKind = &kind
+	}
+	return out
+}
+
+func groupMembershipChangesToAPI(c domain.GroupMembershipChanges, groupID string) GroupMembershipChanges {
+	out := GroupMembershipChanges{
+		Added:   make([]GroupMember, len(c.Added)),
+		Removed: make([]GroupMember, len(c.Removed)),
+	}
+	for i, m := range c.Added {
+		out.Added[i] = groupMemberToAPI(m, groupID)
+	}
+	for i, m := range c.Removed {
+		out.Removed[i] = groupMemberToAPI(m, groupID)
 	}
+	return out
 }
 
 func grantToAPI(g domain.PrivilegeGrant) PrivilegeGrant {
@@ -103,30 +172,138 @@ func grantToAPI(g domain.PrivilegeGrant) PrivilegeGrant {
 		Privilege:     &privilege,
 		GrantedBy:     g.GrantedBy,
 		GrantedAt:     &t,
+		ExpiresAt:     g.ExpiresAt,
 	}
 }
 
+func accessDecisionToAPI(d domain.AccessDecision) AccessDecision {
+	out := AccessDecision{
+		Allowed:       d.Allowed,
+		Reason:        d.Reason,
+		Principal:     d.Principal,
+		Privilege:     d.Privilege,
+		SecurableType: d.SecurableType,
+		SecurableId:   d.SecurableID,
+	}
+	if d.Via != nil {
+		out.Via = &AccessGrantPath{
+			PrincipalType: AccessGrantPathPrincipalType(d.Via.PrincipalType),
+			PrincipalName: d.Via.PrincipalName,
+			SecurableType: d.Via.SecurableType,
+			SecurableId:   d.Via.SecurableID,
+			Privilege:     d.Via.Privilege,
+		}
+	}
+	return out
+}
+
+func securableAccessToAPI(a domain.SecurableAccess) SecurableAccess {
+	out := SecurableAccess{
+		SecurableType: a.SecurableType,
+		SecurableId:   a.SecurableID,
+		Entries:       make([]AccessEntry, len(a.Entries)),
+	}
+	for i, e := range a.Entries {
+		entry := AccessEntry{
+			PrincipalType: AccessEntryPrincipalType(e.PrincipalType),
+			PrincipalName: e.PrincipalName,
+			Privilege:     e.Privilege,
+			Source:        AccessEntrySource(e.Source),
+		}
+		if e.ViaGroup != "" {
+			entry.ViaGroup = &e.ViaGroup
+		}
+		if e.GrantedOnType != "" {
+			entry.GrantedOnType = &e.GrantedOnType
+			entry.GrantedOnId = &e.GrantedOnID
+		}
+		out.Entries[i] = entry
+	}
+	if len(a.Policies) > 0 {
+		policies := make([]PrincipalPolicies, len(a.Policies))
+		for i, p := range a.Policies {
+			columns := make([]string, 0, len(p.ColumnMasks))
+			for col := range p.ColumnMasks {
+				columns = append(columns, col)
+			}
+			sort.Strings(columns)
+			masks := make([]ColumnMaskPolicy, len(columns))
+			for j, col := range columns {
+				masks[j] = ColumnMaskPolicy{ColumnName: col, MaskExpression: p.ColumnMasks[col]}
+			}
+			filters := p.RowFilters
+			if filters == nil {
+				filters = []string{}
+			}
+			policies[i] = PrincipalPolicies{PrincipalName: p.PrincipalName, RowFilters: filters, ColumnMasks: masks}
+		}
+		out.Policies = &policies
+	}
+	return out
+}
+
 func rowFilterToAPI(f domain.RowFilter) RowFilter {
 	t := f.CreatedAt
-	return RowFilter{
+	out := RowFilter{
 		Id:          &f.ID,
-		TableId:     &f.TableID,
 		FilterSql:   &f.FilterSQL,
 		Description: &f.Description,
 		CreatedAt:   &t,
 	}
+	if f.TagID != nil {
+		out.TagId = f.TagID
+	} else {
+		out.TableId = &f.TableID
+	}
+	return out
 }
 
 func columnMaskToAPI(m domain.ColumnMask) ColumnMask {
 	t := m.CreatedAt
-	return ColumnMask{
+	out := ColumnMask{
 		Id:             &m.ID,
-		TableId:        &m.TableID,
-		ColumnName:     &m.ColumnName,
 		MaskExpression: &m.MaskExpression,
 		Description:    &m.Description,
 		CreatedAt:      &t,
 	}
+	if m.TagID != nil {
+		out.TagId = m.TagID
+	} else {
+		out.TableId = &m.TableID
+		out.ColumnName = &m.ColumnName
+	}
+	return out
+}
+
+func columnMaskPreviewToAPI(p *domain.ColumnMaskPreview) ColumnMaskPreview {
+	rows := make([]ColumnMaskPreviewRow, len(p.Rows))
+	for i, r := range p.Rows {
+		rows[i] = ColumnMaskPreviewRow{Original: r.Original, Masked: r.Masked}
+	}
+	return ColumnMaskPreview{
+		ColumnMaskId:   p.ColumnMaskID,
+		TableName:      p.TableName,
+		ColumnName:     p.ColumnName,
+		Principal:      p.Principal,
+		Masked:         p.Masked,
+		MaskExpression: optStr(p.MaskExpression),
+		Rows:           rows,
+	}
+}
+
+func policySimulationToAPI(p *domain.PolicySimulation) PolicySimulation {
+	masked := p.MaskedColumns
+	if masked == nil {
+		masked = []string{}
+	}
+	return PolicySimulation{
+		TableName:     p.TableName,
+		Principal:     p.Principal,
+		Columns:       p.Columns,
+		Rows:          p.Rows,
+		RowFiltered:   p.RowFiltered,
+		MaskedColumns: masked,
+	}
 }
 
 func auditEntryToAPI(e domain.AuditEntry) AuditEntry {
@@ -155,6 +332,27 @@ func catalogInfoToAPI(c domain.CatalogInfo) CatalogInfo {
 	}
 }
 
+func catalogStatsToAPI(s domain.CatalogStats) CatalogStats {
+	largest := make([]TableSize, len(s.LargestTables))
+	for i, t := range s.LargestTables {
+		largest[i] = TableSize{
+			SchemaName: t.SchemaName,
+			TableName:  t.TableName,
+			RowCount:   t.RowCount,
+			SizeBytes:  t.SizeBytes,
+		}
+	}
+	return CatalogStats{
+		CatalogName:   s.CatalogName,
+		SchemaCount:   s.SchemaCount,
+		TableCount:    s.TableCount,
+		ViewCount:     s.ViewCount,
+		TotalRows:     s.TotalRows,
+		TotalBytes:    s.TotalBytes,
+		LargestTables: largest,
+	}
+}
+
 func schemaDetailToAPI(s domain.SchemaDetail) SchemaDetail {
 	tags := make([]Tag, len(s.Tags))
 	for i, t := range s.Tags {
@@ -213,6 +411,9 @@ func tableDetailToAPI(t domain.TableDetail) TableDetail {
 	if t.LocationName != "" {
 		td.LocationName = &t.LocationName
 	}
+	if len(t.PartitionBy) > 0 {
+		td.PartitionBy = &t.PartitionBy
+	}
 	return td
 }
 
@@ -253,6 +454,7 @@ func searchResultToAPI(r domain.SearchResult) SearchResult {
 		TableName:  r.TableName,
 		Comment:    r.Comment,
 		MatchField: &r.MatchField,
+		Snippet:    r.Snippet,
 	}
 }
 
@@ -317,22 +519,52 @@ func tagAssignmentToAPI(a domain.TagAssignment) TagAssignment {
 	}
 }
 
+func batchTagAssignmentRequestFromAPI(tagID string, items []CreateTagAssignmentRequest) domain.BatchTagAssignmentRequest {
+	targets := make([]domain.TagAssignmentTarget, len(items))
+	for i, item := range items {
+		targets[i] = domain.TagAssignmentTarget{
+			SecurableType: string(item.SecurableType),
+			SecurableID:   item.SecurableId,
+			ColumnName:    item.ColumnName,
+		}
+	}
+	return domain.BatchTagAssignmentRequest{TagID: tagID, Targets: targets}
+}
+
+func tagAssignmentBatchToAPI(b domain.TagAssignmentBatch) TagAssignmentBatchResponse {
+	results := make([]TagAssignmentBatchResult, len(b.Results))
+	for i, r := range b.Results {
+		results[i] = TagAssignmentBatchResult{
+			SecurableType: r.Target.SecurableType,
+			SecurableId:   r.Target.SecurableID,
+			ColumnName:    r.Target.ColumnName,
+			Status:        TagAssignmentBatchResultStatus(r.Status),
+			AssignmentId:  r.AssignmentID,
+			Error:         r.Error,
+		}
+	}
+	return TagAssignmentBatchResponse{Applied: b.Applied, Results: results}
+}
+
 func viewDetailToAPI(v domain.ViewDetail) ViewDetail {
 	ct := v.CreatedAt
 	ut := v.UpdatedAt
 	return ViewDetail{
-		Id:             &v.ID,
-		SchemaId:       &v.SchemaID,
-		SchemaName:     &v.SchemaName,
-		CatalogName:    &v.CatalogName,
-		Name:           &v.Name,
-		ViewDefinition: &v.ViewDefinition,
-		Comment:        v.Comment,
-		Properties:     &v.Properties,
-		Owner:          &v.Owner,
-		SourceTables:   &v.SourceTables,
-		CreatedAt:      &ct,
-		UpdatedAt:      &ut,
+		Id:              &v.ID,
+		SchemaId:        &v.SchemaID,
+		SchemaName:      &v.SchemaName,
+		CatalogName:     &v.CatalogName,
+		Name:            &v.Name,
+		ViewDefinition:  &v.ViewDefinition,
+		Comment:         v.Comment,
+		Properties:      &v.Properties,
+		Owner:           &v.Owner,
+		SourceTables:    &v.SourceTables,
+		Materialized:    &v.Materialized,
+		RefreshSchedule: v.RefreshSchedule,
+		LastRefreshedAt: v.LastRefreshedAt,
+		CreatedAt:       &ct,
+		UpdatedAt:       &ut,
 	}
 }
 
@@ -349,6 +581,68 @@ func tableStatisticsToAPI(s *domain.TableStatistics) TableStatistics {
 	}
 }
 
+func tableMaintenanceResultToAPI(r *domain.TableMaintenanceResult) TableMaintenanceResult {
+	return TableMaintenanceResult{
+		Operation:        TableMaintenanceResultOperation(r.Operation),
+		FilesBefore:      r.FilesBefore,
+		FilesAfter:       r.FilesAfter,
+		BytesBefore:      r.BytesBefore,
+		BytesAfter:       r.BytesAfter,
+		BytesReclaimed:   r.BytesReclaimed(),
+		SnapshotsExpired: r.SnapshotsExpired,
+	}
+}
+
+func registerDirectoryResultToAPI(r *domain.RegisterDirectoryResult) RegisterDirectoryResult {
+	registered := make([]TableDetail, len(r.Registered))
+	for i, t := range r.Registered {
+		registered[i] = tableDetailToAPI(t)
+	}
+	skipped := make([]SkippedFile, len(r.Skipped))
+	for i, f := range r.Skipped {
+		skipped[i] = SkippedFile{Path: f.Path, TableName: f.TableName, Reason: f.Reason}
+	}
+	return RegisterDirectoryResult{Registered: registered, Skipped: skipped}
+}
+
+func dependentObjectsToAPI(deps []domain.DependentObject) []DependentObject {
+	out := make([]DependentObject, len(deps))
+	for i, d := range deps {
+		out[i] = DependentObject{Type: DependentObjectType(d.Type), Name: d.Name}
+	}
+	return out
+}
+
+func tableRollbackResultToAPI(r *domain.TableRollbackResult) TableRollbackResult {
+	return TableRollbackResult{
+		CurrentSnapshot: r.CurrentSnapshot,
+		TargetSnapshot:  r.TargetSnapshot,
+		NewSnapshot:     r.NewSnapshot,
+		RowsBefore:      r.RowsBefore,
+		RowsAfter:       r.RowsAfter,
+		Applied:         r.Applied,
+		Dependents:      dependentObjectsToAPI(r.Dependents),
+	}
+}
+
+func copyPoliciesResultToAPI(r *domain.CopyPoliciesResult) CopyPoliciesResult {
+	copied := make([]CopiedPolicy, len(r.Copied))
+	for i, p := range r.Copied {
+		copied[i] = CopiedPolicy{Kind: CopiedPolicyKind(p.Kind), Description: p.Description}
+	}
+	skipped := make([]SkippedPolicy, len(r.Skipped))
+	for i, p := range r.Skipped {
+		skipped[i] = SkippedPolicy{Kind: SkippedPolicyKind(p.Kind), Description: p.Description, Reason: p.Reason}
+	}
+	return CopyPoliciesResult{
+		Source:  r.Source,
+		Target:  r.Target,
+		DryRun:  r.DryRun,
+		Copied:  copied,
+		Skipped: skipped,
+	}
+}
+
 func tableStatisticsPtr(s *domain.TableStatistics) *TableStatistics {
 	if s == nil {
 		return nil
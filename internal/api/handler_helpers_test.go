@@ -58,8 +58,9 @@ func TestHelpers_pageFromParams(t *testing.T) {
 		wantMR     int
 		wantPT     string
 	}{
-		{name: "nil nil returns defaults", maxResults: nil, pageToken: nil, wantMR: 0, wantPT: ""},
+		{name: "nil nil returns default page size", maxResults: nil, pageToken: nil, wantMR: domain.DefaultMaxResults, wantPT: ""},
 		{name: "both set", maxResults: ptrInt32(10), pageToken: ptrString("abc"), wantMR: 10, wantPT: "abc"},
+		{name: "oversized is clamped to max page size", maxResults: ptrInt32(50000), pageToken: nil, wantMR: domain.MaxMaxResults, wantPT: ""},
 	}
 
 	for _, tt := range tests {
@@ -89,6 +90,7 @@ func TestHelpers_httpStatusFromError(t *testing.T) {
 		{name: "AccessDeniedError -> 403", err: domain.ErrAccessDenied("nope"), want: http.StatusForbidden},
 		{name: "ValidationError -> 400", err: domain.ErrValidation("bad"), want: http.StatusBadRequest},
 		{name: "ConflictError -> 409", err: domain.ErrConflict("dup"), want: http.StatusConflict},
+		{name: "UnavailableError -> 503", err: domain.ErrUnavailable("metastore busy"), want: http.StatusServiceUnavailable},
 		{name: "generic error -> 500", err: errors.New("boom"), want: http.StatusInternalServerError},
 	}
 
@@ -206,6 +208,18 @@ func TestHelpers_rowFilterToAPI(t *testing.T) {
 	assert.Equal(t, helpersFixedTime, *result.CreatedAt)
 }
 
+func TestHelpers_rowFilterToAPI_TagBased(t *testing.T) {
+	t.Parallel()
+	tagID := "tag-eu"
+	result := rowFilterToAPI(domain.RowFilter{
+		ID: "rf-2", TagID: &tagID, FilterSQL: "region = 'EU'", CreatedAt: helpersFixedTime,
+	})
+
+	require.NotNil(t, result.TagId)
+	assert.Equal(t, "tag-eu", *result.TagId)
+	assert.Nil(t, result.TableId, "tag-based filters name no table")
+}
+
 func TestHelpers_columnMaskToAPI(t *testing.T) {
 	t.Parallel()
 	cm := domain.ColumnMask{
@@ -229,6 +243,19 @@ func TestHelpers_columnMaskToAPI(t *testing.T) {
 	assert.Equal(t, helpersFixedTime, *result.CreatedAt)
 }
 
+func TestHelpers_columnMaskToAPI_TagBased(t *testing.T) {
+	t.Parallel()
+	tagID := "tag-pii"
+	result := columnMaskToAPI(domain.ColumnMask{
+		ID: "cm-2", TagID: &tagID, MaskExpression: "'REDACTED'", CreatedAt: helpersFixedTime,
+	})
+
+	require.NotNil(t, result.TagId)
+	assert.Equal(t, "tag-pii", *result.TagId)
+	assert.Nil(t, result.TableId, "tag-based masks name no table")
+	assert.Nil(t, result.ColumnName)
+}
+
 func TestHelpers_schemaDetailToAPI(t *testing.T) {
 	t.Parallel()
 	s := domain.SchemaDetail{
@@ -335,6 +362,21 @@ func TestHelpers_searchResultToAPI(t *testing.T) {
 	assert.Equal(t, "name", *result.MatchField)
 }
 
+func TestHelpers_searchResultToAPI_CommentSnippet(t *testing.T) {
+	t.Parallel()
+	sr := domain.SearchResult{
+		Type: "view", Name: "daily_rollup", SchemaName: helpersStrPtr("main"),
+		Comment: helpersStrPtr("Clickstream rollup"), MatchField: "comment",
+		Snippet: helpersStrPtr("**Clickstream** rollup"),
+	}
+	result := searchResultToAPI(sr)
+
+	require.NotNil(t, result.MatchField)
+	assert.Equal(t, "comment", *result.MatchField)
+	require.NotNil(t, result.Snippet)
+	assert.Equal(t, "**Clickstream** rollup", *result.Snippet)
+}
+
 func TestHelpers_lineageEdgeToAPI(t *testing.T) {
 	t.Parallel()
 	le := domain.LineageEdge{
@@ -99,7 +99,7 @@ func (h *APIHandler) CreateMacro(ctx context.Context, req CreateMacroRequestObje
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateMacro400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateMacro409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateMacro409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateMacro400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -251,15 +251,21 @@ func (h *APIHandler) GetMacro(ctx context.Context, req GetMacroRequestObject) (G
 	}
 	return GetMacro200JSONResponse{
 		Body:    macroToAPI(*result),
-		Headers: GetMacro200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: GetMacro200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // UpdateMacro implements the endpoint for updating a SQL macro.
 func (h *APIHandler) UpdateMacro(ctx context.Context, req UpdateMacroRequestObject) (UpdateMacroResponseObject, error) {
+	ifMatch, err := ifMatchFromParams(req.Params.IfMatch)
+	if err != nil {
+		return UpdateMacro412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
 	domReq := domain.UpdateMacroRequest{
-		Body:        req.Body.Body,
-		Description: req.Body.Description,
+		Body:               req.Body.Body,
+		Description:        req.Body.Description,
+		ExpectedRowVersion: ifMatch,
 	}
 	if req.Body.Parameters != nil {
 		domReq.Parameters = *req.Body.Parameters
@@ -297,6 +303,8 @@ func (h *APIHandler) UpdateMacro(ctx context.Context, req UpdateMacroRequestObje
 			return UpdateMacro403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return UpdateMacro404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return UpdateMacro412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ValidationError)):
 			return UpdateMacro400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
@@ -305,7 +313,7 @@ func (h *APIHandler) UpdateMacro(ctx context.Context, req UpdateMacroRequestObje
 	}
 	return UpdateMacro200JSONResponse{
 		Body:    macroToAPI(*result),
-		Headers: UpdateMacro200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: UpdateMacro200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
@@ -21,11 +21,14 @@ type modelService interface {
 	GetRun(ctx context.Context, runID string) (*domain.ModelRun, error)
 	ListRuns(ctx context.Context, filter domain.ModelRunFilter) ([]domain.ModelRun, int64, error)
 	ListRunSteps(ctx context.Context, runID string) ([]domain.ModelRunStep, error)
+	ListModelRunHistory(ctx context.Context, projectName, modelName string, page domain.PageRequest) ([]domain.ModelRunStep, int64, error)
 	CancelRun(ctx context.Context, principal, runID string) error
 	CreateTest(ctx context.Context, principal, projectName, modelName string, req domain.CreateModelTestRequest) (*domain.ModelTest, error)
 	ListTests(ctx context.Context, projectName, modelName string) ([]domain.ModelTest, error)
 	DeleteTest(ctx context.Context, principal, projectName, modelName, testID string) error
 	ListTestResults(ctx context.Context, runID, stepID string) ([]domain.ModelTestResult, error)
+	ListTestResultHistory(ctx context.Context, projectName, modelName, testID string, page domain.PageRequest) ([]domain.ModelTestResult, int64, error)
+	RunTests(ctx context.Context, principal, projectName, modelName string, req domain.RunModelTestsRequest) (*domain.ModelTestRun, error)
 	CheckFreshness(ctx context.Context, projectName, modelName string) (*domain.FreshnessStatus, error)
 	CheckSourceFreshness(ctx context.Context, principal, sourceSchema, sourceTable, timestampColumn string, maxLagSeconds int64) (*domain.SourceFreshnessStatus, error)
 	PromoteNotebook(ctx context.Context, principal string, req domain.PromoteNotebookRequest) (*domain.Model, error)
@@ -98,7 +101,7 @@ func (h *APIHandler) CreateModel(ctx context.Context, req CreateModelRequestObje
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateModel400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateModel409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateModel409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateModel400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -122,15 +125,21 @@ func (h *APIHandler) GetModel(ctx context.Context, req GetModelRequestObject) (G
 	}
 	return GetModel200JSONResponse{
 		Body:    modelToAPI(*result),
-		Headers: GetModel200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: GetModel200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // UpdateModel implements the endpoint for updating a transformation model.
 func (h *APIHandler) UpdateModel(ctx context.Context, req UpdateModelRequestObject) (UpdateModelResponseObject, error) {
+	ifMatch, err := ifMatchFromParams(req.Params.IfMatch)
+	if err != nil {
+		return UpdateModel412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
 	domReq := domain.UpdateModelRequest{
-		SQL:         req.Body.Sql,
-		Description: req.Body.Description,
+		SQL:                req.Body.Sql,
+		Description:        req.Body.Description,
+		ExpectedRowVersion: ifMatch,
 	}
 	if req.Body.Materialization != nil {
 		s := string(*req.Body.Materialization)
@@ -161,6 +170,8 @@ func (h *APIHandler) UpdateModel(ctx context.Context, req UpdateModelRequestObje
 			return UpdateModel403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return UpdateModel404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return UpdateModel412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ValidationError)):
 			return UpdateModel400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
@@ -169,7 +180,7 @@ func (h *APIHandler) UpdateModel(ctx context.Context, req UpdateModelRequestObje
 	}
 	return UpdateModel200JSONResponse{
 		Body:    modelToAPI(*result),
-		Headers: UpdateModel200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: UpdateModel200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
@@ -227,6 +238,9 @@ func (h *APIHandler) TriggerModelRun(ctx context.Context, req TriggerModelRunReq
 	if req.Body.FullRefresh != nil {
 		domReq.FullRefresh = *req.Body.FullRefresh
 	}
+	if req.Body.StoreFailures != nil {
+		domReq.StoreFailures = *req.Body.StoreFailures
+	}
 	if req.Body.ModelNames != nil && len(*req.Body.ModelNames) > 0 {
 		domReq.Selector = strings.Join(*req.Body.ModelNames, ",")
 	}
@@ -241,7 +255,7 @@ func (h *APIHandler) TriggerModelRun(ctx context.Context, req TriggerModelRunReq
 		case errors.As(err, new(*domain.ValidationError)):
 			return TriggerModelRun400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return TriggerModelRun409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return TriggerModelRun409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return TriggerModelRun400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -321,6 +335,30 @@ func (h *APIHandler) ListModelRunSteps(ctx context.Context, req ListModelRunStep
 	}, nil
 }
 
+// ListModelRunHistory implements the endpoint for listing the run steps of a single model.
+func (h *APIHandler) ListModelRunHistory(ctx context.Context, req ListModelRunHistoryRequestObject) (ListModelRunHistoryResponseObject, error) {
+	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	steps, total, err := h.models.ListModelRunHistory(ctx, req.ProjectName, req.ModelName, page)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.NotFoundError)):
+			return ListModelRunHistory404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	data := make([]ModelRunStep, len(steps))
+	for i, s := range steps {
+		data[i] = modelRunStepToAPI(s)
+	}
+	nextToken := domain.NextPageToken(page.Offset(), page.Limit(), total)
+	return ListModelRunHistory200JSONResponse{
+		Body:    ModelRunStepList{Data: &data, NextPageToken: optStr(nextToken)},
+		Headers: ListModelRunHistory200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // CancelModelRun implements the endpoint for cancelling a model run.
 func (h *APIHandler) CancelModelRun(ctx context.Context, req CancelModelRunRequestObject) (CancelModelRunResponseObject, error) {
 	cp, _ := domain.PrincipalFromContext(ctx)
@@ -334,7 +372,7 @@ func (h *APIHandler) CancelModelRun(ctx context.Context, req CancelModelRunReque
 		case errors.As(err, new(*domain.NotFoundError)):
 			return CancelModelRun404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CancelModelRun409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CancelModelRun409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
@@ -372,6 +410,9 @@ func modelToAPI(m domain.Model) Model {
 	if len(m.DependsOn) > 0 {
 		resp.DependsOn = &m.DependsOn
 	}
+	if len(m.MacroDeps) > 0 {
+		resp.MacroDependencies = &m.MacroDeps
+	}
 	if len(m.Tags) > 0 {
 		resp.Tags = &m.Tags
 	}
@@ -505,6 +546,12 @@ func modelRunStepToAPI(s domain.ModelRunStep) ModelRunStep {
 	if s.RowsAffected != nil {
 		resp.RowsAffected = s.RowsAffected
 	}
+	if s.BytesWritten != nil {
+		resp.BytesWritten = s.BytesWritten
+	}
+	if s.DurationMs != nil {
+		resp.DurationMs = s.DurationMs
+	}
 	if s.StartedAt != nil {
 		resp.StartedAt = s.StartedAt
 	}
@@ -598,7 +645,7 @@ func (h *APIHandler) CreateModelTest(ctx context.Context, req CreateModelTestReq
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateModelTest400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateModelTest409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateModelTest409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateModelTest400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -672,6 +719,76 @@ func (h *APIHandler) ListModelTestResults(ctx context.Context, req ListModelTest
 	}, nil
 }
 
+// ListModelTestResultHistory implements the endpoint for listing a model test's results across runs.
+func (h *APIHandler) ListModelTestResultHistory(ctx context.Context, req ListModelTestResultHistoryRequestObject) (ListModelTestResultHistoryResponseObject, error) {
+	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	results, total, err := h.models.ListTestResultHistory(ctx, req.ProjectName, req.ModelName, req.TestId, page)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.NotFoundError)):
+			return ListModelTestResultHistory404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	data := make([]ModelTestResult, len(results))
+	for i, r := range results {
+		data[i] = modelTestResultToAPI(r)
+	}
+	nextToken := domain.NextPageToken(page.Offset(), page.Limit(), total)
+	return ListModelTestResultHistory200JSONResponse{
+		Body:    ModelTestResultList{Data: &data, NextPageToken: optStr(nextToken)},
+		Headers: ListModelTestResultHistory200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// RunModelTests implements the endpoint for running a model's tests without rebuilding it.
+func (h *APIHandler) RunModelTests(ctx context.Context, req RunModelTestsRequestObject) (RunModelTestsResponseObject, error) {
+	domReq := domain.RunModelTestsRequest{
+		TargetCatalog: "memory",
+		TargetSchema:  req.ProjectName,
+	}
+	if req.Body.TargetCatalog != nil {
+		domReq.TargetCatalog = *req.Body.TargetCatalog
+	}
+	if req.Body.TargetSchema != nil {
+		domReq.TargetSchema = *req.Body.TargetSchema
+	}
+	if req.Body.StoreFailures != nil {
+		domReq.StoreFailures = *req.Body.StoreFailures
+	}
+
+	cp, _ := domain.PrincipalFromContext(ctx)
+	principal := cp.Name
+	run, err := h.models.RunTests(ctx, principal, req.ProjectName, req.ModelName, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return RunModelTests403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return RunModelTests404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return RunModelTests400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	results := make([]ModelTestResult, len(run.Results))
+	for i, r := range run.Results {
+		results[i] = modelTestResultToAPI(r)
+	}
+	body := ModelTestRun{Results: &results}
+	if run.FailuresTable != "" {
+		body.FailuresTable = &run.FailuresTable
+	}
+	return RunModelTests200JSONResponse{
+		Body:    body,
+		Headers: RunModelTests200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // === Model Test Mappers ===
 
 func modelTestToAPI(t domain.ModelTest) ModelTest {
@@ -695,15 +812,23 @@ func modelTestToAPI(t domain.ModelTest) ModelTest {
 }
 
 func modelTestResultToAPI(r domain.ModelTestResult) ModelTestResult {
-	ct := r.CreatedAt
 	status := ModelTestResultStatus(r.Status)
 	resp := ModelTestResult{
-		Id:        &r.ID,
-		RunStepId: &r.RunStepID,
-		TestId:    &r.TestID,
-		TestName:  &r.TestName,
-		Status:    &status,
-		CreatedAt: &ct,
+		TestId:   &r.TestID,
+		TestName: &r.TestName,
+		Status:   &status,
+	}
+	// Results of ad-hoc test runs are not stored and have no ID, run step
+	// or creation time.
+	if r.ID != "" {
+		resp.Id = &r.ID
+	}
+	if r.RunStepID != "" {
+		resp.RunStepId = &r.RunStepID
+	}
+	if !r.CreatedAt.IsZero() {
+		ct := r.CreatedAt
+		resp.CreatedAt = &ct
 	}
 	if r.RowsReturned != nil {
 		resp.RowsReturned = r.RowsReturned
@@ -916,7 +1041,7 @@ func (h *APIHandler) PromoteNotebookToModel(ctx context.Context, req PromoteNote
 		case errors.As(err, new(*domain.ValidationError)):
 			return PromoteNotebookToModel400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return PromoteNotebookToModel409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return PromoteNotebookToModel409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return PromoteNotebookToModel400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
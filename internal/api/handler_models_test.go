@@ -15,6 +15,9 @@ import (
 type mockModelService struct {
 	triggerRunFn           func(ctx context.Context, principal string, req domain.TriggerModelRunRequest) (*domain.ModelRun, error)
 	listRunsFn             func(ctx context.Context, filter domain.ModelRunFilter) ([]domain.ModelRun, int64, error)
+	listModelRunHistoryFn  func(ctx context.Context, projectName, modelName string, page domain.PageRequest) ([]domain.ModelRunStep, int64, error)
+	listTestHistoryFn      func(ctx context.Context, projectName, modelName, testID string, page domain.PageRequest) ([]domain.ModelTestResult, int64, error)
+	runTestsFn             func(ctx context.Context, principal, projectName, modelName string, req domain.RunModelTestsRequest) (*domain.ModelTestRun, error)
 	checkSourceFreshnessFn func(ctx context.Context, principal, sourceSchema, sourceTable, timestampColumn string, maxLagSeconds int64) (*domain.SourceFreshnessStatus, error)
 }
 
@@ -54,6 +57,12 @@ func (m *mockModelService) ListRuns(ctx context.Context, filter domain.ModelRunF
 func (m *mockModelService) ListRunSteps(context.Context, string) ([]domain.ModelRunStep, error) {
 	panic("not implemented")
 }
+func (m *mockModelService) ListModelRunHistory(ctx context.Context, projectName, modelName string, page domain.PageRequest) ([]domain.ModelRunStep, int64, error) {
+	if m.listModelRunHistoryFn == nil {
+		panic("not implemented")
+	}
+	return m.listModelRunHistoryFn(ctx, projectName, modelName, page)
+}
 func (m *mockModelService) CancelRun(context.Context, string, string) error {
 	panic("not implemented")
 }
@@ -69,6 +78,18 @@ func (m *mockModelService) DeleteTest(context.Context, string, string, string, s
 func (m *mockModelService) ListTestResults(context.Context, string, string) ([]domain.ModelTestResult, error) {
 	panic("not implemented")
 }
+func (m *mockModelService) ListTestResultHistory(ctx context.Context, projectName, modelName, testID string, page domain.PageRequest) ([]domain.ModelTestResult, int64, error) {
+	if m.listTestHistoryFn == nil {
+		panic("not implemented")
+	}
+	return m.listTestHistoryFn(ctx, projectName, modelName, testID, page)
+}
+func (m *mockModelService) RunTests(ctx context.Context, principal, projectName, modelName string, req domain.RunModelTestsRequest) (*domain.ModelTestRun, error) {
+	if m.runTestsFn == nil {
+		panic("not implemented")
+	}
+	return m.runTestsFn(ctx, principal, projectName, modelName, req)
+}
 func (m *mockModelService) CheckFreshness(context.Context, string, string) (*domain.FreshnessStatus, error) {
 	panic("not implemented")
 }
@@ -247,6 +268,115 @@ func TestHandler_ListModelRuns_IncludesModelNamesAndProject(t *testing.T) {
 
 func boolPtr(v bool) *bool { return &v }
 
+func TestHandler_ListModelRunHistory_MapsMetrics(t *testing.T) {
+	t.Parallel()
+
+	fixed := time.Date(2026, 2, 16, 12, 0, 0, 0, time.UTC)
+	rows, bytesWritten, durationMs := int64(1500), int64(48213), int64(3812)
+	var gotProject, gotModel string
+	h := &APIHandler{
+		models: &mockModelService{
+			listModelRunHistoryFn: func(_ context.Context, projectName, modelName string, _ domain.PageRequest) ([]domain.ModelRunStep, int64, error) {
+				gotProject, gotModel = projectName, modelName
+				return []domain.ModelRunStep{{
+					ID:           "step-1",
+					RunID:        "run-1",
+					ModelName:    "sales.stg_orders",
+					Status:       domain.ModelRunStatusSuccess,
+					RowsAffected: &rows,
+					BytesWritten: &bytesWritten,
+					DurationMs:   &durationMs,
+					CreatedAt:    fixed,
+				}}, 1, nil
+			},
+		},
+	}
+
+	resp, err := h.ListModelRunHistory(context.Background(), ListModelRunHistoryRequestObject{
+		ProjectName: "sales",
+		ModelName:   "stg_orders",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "sales", gotProject)
+	assert.Equal(t, "stg_orders", gotModel)
+
+	okResp, ok := resp.(ListModelRunHistory200JSONResponse)
+	require.True(t, ok, "expected 200 response, got %T", resp)
+	require.NotNil(t, okResp.Body.Data)
+	require.Len(t, *okResp.Body.Data, 1)
+	step := (*okResp.Body.Data)[0]
+	require.NotNil(t, step.RowsAffected)
+	assert.Equal(t, int64(1500), *step.RowsAffected)
+	require.NotNil(t, step.BytesWritten)
+	assert.Equal(t, int64(48213), *step.BytesWritten)
+	require.NotNil(t, step.DurationMs)
+	assert.Equal(t, int64(3812), *step.DurationMs)
+	assert.Nil(t, okResp.Body.NextPageToken)
+}
+
+func TestHandler_ListModelRunHistory_UnknownModelReturns404(t *testing.T) {
+	t.Parallel()
+
+	h := &APIHandler{
+		models: &mockModelService{
+			listModelRunHistoryFn: func(context.Context, string, string, domain.PageRequest) ([]domain.ModelRunStep, int64, error) {
+				return nil, 0, domain.ErrNotFound("model sales.missing not found")
+			},
+		},
+	}
+
+	resp, err := h.ListModelRunHistory(context.Background(), ListModelRunHistoryRequestObject{
+		ProjectName: "sales",
+		ModelName:   "missing",
+	})
+	require.NoError(t, err)
+	_, ok := resp.(ListModelRunHistory404JSONResponse)
+	assert.True(t, ok, "expected 404 response, got %T", resp)
+}
+
+func TestHandler_ListModelTestResultHistory_Paginates(t *testing.T) {
+	t.Parallel()
+
+	var gotPage domain.PageRequest
+	h := &APIHandler{
+		models: &mockModelService{
+			listTestHistoryFn: func(_ context.Context, projectName, modelName, testID string, page domain.PageRequest) ([]domain.ModelTestResult, int64, error) {
+				assert.Equal(t, "sales", projectName)
+				assert.Equal(t, "stg_orders", modelName)
+				assert.Equal(t, "test-1", testID)
+				gotPage = page
+				failing := int64(3)
+				return []domain.ModelTestResult{{
+					ID:           "result-2",
+					TestID:       "test-1",
+					TestName:     "not_null_customer",
+					Status:       domain.TestResultFail,
+					RowsReturned: &failing,
+				}}, 5, nil
+			},
+		},
+	}
+
+	maxResults := int32(1)
+	resp, err := h.ListModelTestResultHistory(context.Background(), ListModelTestResultHistoryRequestObject{
+		ProjectName: "sales",
+		ModelName:   "stg_orders",
+		TestId:      "test-1",
+		Params:      ListModelTestResultHistoryParams{MaxResults: &maxResults},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, gotPage.Limit())
+
+	okResp, ok := resp.(ListModelTestResultHistory200JSONResponse)
+	require.True(t, ok, "expected 200 response, got %T", resp)
+	require.NotNil(t, okResp.Body.Data)
+	require.Len(t, *okResp.Body.Data, 1)
+	result := (*okResp.Body.Data)[0]
+	require.NotNil(t, result.RowsReturned)
+	assert.Equal(t, int64(3), *result.RowsReturned)
+	assert.NotNil(t, okResp.Body.NextPageToken)
+}
+
 func TestModelRunToAPI_CompileDiagnosticsStableEmptyArrays(t *testing.T) {
 	t.Parallel()
 
@@ -341,3 +471,50 @@ func TestHandler_CheckSourceFreshness_DefaultsAndMapping(t *testing.T) {
 	require.NotNil(t, okResp.Body.TimestampColumn)
 	assert.Equal(t, "updated_at", *okResp.Body.TimestampColumn)
 }
+
+func TestHandler_RunModelTests_StoreFailures(t *testing.T) {
+	t.Parallel()
+
+	var got domain.RunModelTestsRequest
+	h := &APIHandler{
+		models: &mockModelService{
+			runTestsFn: func(_ context.Context, _, projectName, modelName string, req domain.RunModelTestsRequest) (*domain.ModelTestRun, error) {
+				assert.Equal(t, "sales", projectName)
+				assert.Equal(t, "stg_orders", modelName)
+				got = req
+				failing := int64(2)
+				return &domain.ModelTestRun{
+					Results: []domain.ModelTestResult{{
+						TestID:       "test-1",
+						TestName:     "not_null_customer",
+						Status:       domain.TestResultFail,
+						RowsReturned: &failing,
+					}},
+					FailuresTable: "memory.sales.stg_orders_test_failures",
+				}, nil
+			},
+		},
+	}
+
+	storeFailures := true
+	resp, err := h.RunModelTests(context.Background(), RunModelTestsRequestObject{
+		ProjectName: "sales",
+		ModelName:   "stg_orders",
+		Body:        &RunModelTestsJSONRequestBody{StoreFailures: &storeFailures},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RunModelTestsRequest{TargetCatalog: "memory", TargetSchema: "sales", StoreFailures: true}, got)
+
+	okResp, ok := resp.(RunModelTests200JSONResponse)
+	require.True(t, ok, "expected 200 response, got %T", resp)
+	require.NotNil(t, okResp.Body.FailuresTable)
+	assert.Equal(t, "memory.sales.stg_orders_test_failures", *okResp.Body.FailuresTable)
+	require.NotNil(t, okResp.Body.Results)
+	require.Len(t, *okResp.Body.Results, 1)
+	result := (*okResp.Body.Results)[0]
+	assert.Nil(t, result.Id)
+	assert.Nil(t, result.RunStepId)
+	assert.Nil(t, result.CreatedAt)
+	require.NotNil(t, result.RowsReturned)
+	assert.Equal(t, int64(2), *result.RowsReturned)
+}
@@ -33,11 +33,11 @@ type sessionService interface {
 
 // gitRepoService defines git repository operations.
 type gitRepoService interface {
-	CreateGitRepo(ctx context.Context, principal string, req domain.CreateGitRepoRequest) (*domain.GitRepo, error)
+	CreateGitRepo(ctx context.Context, principal string, isAdmin bool, req domain.CreateGitRepoRequest) (*domain.GitRepo, error)
 	GetGitRepo(ctx context.Context, id string) (*domain.GitRepo, error)
 	ListGitRepos(ctx context.Context, page domain.PageRequest) ([]domain.GitRepo, int64, error)
 	DeleteGitRepo(ctx context.Context, principal string, isAdmin bool, id string) error
-	SyncGitRepo(ctx context.Context, id string) (*domain.GitSyncResult, error)
+	SyncGitRepo(ctx context.Context, principal string, isAdmin bool, id string, opts domain.GitSyncOptions) (*domain.GitSyncResult, error)
 }
 
 // === Notebooks ===
@@ -77,7 +77,7 @@ func (h *APIHandler) CreateNotebook(ctx context.Context, req CreateNotebookReque
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateNotebook400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateNotebook409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateNotebook409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateNotebook400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -107,15 +107,21 @@ func (h *APIHandler) GetNotebook(ctx context.Context, req GetNotebookRequestObje
 	}
 	return GetNotebook200JSONResponse{
 		Body:    NotebookDetail{Notebook: &apiNb, Cells: &apiCells},
-		Headers: GetNotebook200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: GetNotebook200ResponseHeaders{ETag: domain.ETag(nb.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // UpdateNotebook implements the endpoint for updating notebook metadata.
 func (h *APIHandler) UpdateNotebook(ctx context.Context, req UpdateNotebookRequestObject) (UpdateNotebookResponseObject, error) {
+	ifMatch, err := ifMatchFromParams(req.Params.IfMatch)
+	if err != nil {
+		return UpdateNotebook412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
 	domReq := domain.UpdateNotebookRequest{
-		Name:        req.Body.Name,
-		Description: req.Body.Description,
+		Name:               req.Body.Name,
+		Description:        req.Body.Description,
+		ExpectedRowVersion: ifMatch,
 	}
 
 	cp, _ := domain.PrincipalFromContext(ctx)
@@ -129,6 +135,8 @@ func (h *APIHandler) UpdateNotebook(ctx context.Context, req UpdateNotebookReque
 			return UpdateNotebook403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return UpdateNotebook404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return UpdateNotebook412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ValidationError)):
 			return UpdateNotebook400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
@@ -137,7 +145,7 @@ func (h *APIHandler) UpdateNotebook(ctx context.Context, req UpdateNotebookReque
 	}
 	return UpdateNotebook200JSONResponse{
 		Body:    notebookToAPI(*result),
-		Headers: UpdateNotebook200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: UpdateNotebook200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
@@ -459,13 +467,16 @@ func (h *APIHandler) CreateGitRepo(ctx context.Context, req CreateGitRepoRequest
 
 	cp, _ := domain.PrincipalFromContext(ctx)
 	principal := cp.Name
-	result, err := h.gitRepos.CreateGitRepo(ctx, principal, domReq)
+	isAdmin := cp.IsAdmin
+	result, err := h.gitRepos.CreateGitRepo(ctx, principal, isAdmin, domReq)
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateGitRepo400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CreateGitRepo403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateGitRepo409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateGitRepo409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateGitRepo400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -512,11 +523,22 @@ func (h *APIHandler) DeleteGitRepo(ctx context.Context, req DeleteGitRepoRequest
 
 // SyncGitRepo implements the endpoint for triggering a Git sync.
 func (h *APIHandler) SyncGitRepo(ctx context.Context, req SyncGitRepoRequestObject) (SyncGitRepoResponseObject, error) {
-	result, err := h.gitRepos.SyncGitRepo(ctx, req.GitRepoId)
+	cp, _ := domain.PrincipalFromContext(ctx)
+	opts := domain.GitSyncOptions{}
+	if req.Params.Ref != nil {
+		opts.Ref = *req.Params.Ref
+	}
+	if req.Params.DryRun != nil {
+		opts.DryRun = *req.Params.DryRun
+	}
+
+	result, err := h.gitRepos.SyncGitRepo(ctx, cp.Name, cp.IsAdmin, req.GitRepoId, opts)
 	if err != nil {
 		switch {
-		case errors.As(err, new(*domain.NotImplementedError)):
-			return SyncGitRepo500JSONResponse{InternalErrorJSONResponse{Body: Error{Code: 501, Message: err.Error()}, Headers: InternalErrorResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return SyncGitRepo400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return SyncGitRepo403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return SyncGitRepo404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
@@ -527,12 +549,19 @@ func (h *APIHandler) SyncGitRepo(ctx context.Context, req SyncGitRepoRequestObje
 	created := int32(min(result.NotebooksCreated, int(^uint32(0)>>1))) //nolint:gosec // bounded by min
 	updated := int32(min(result.NotebooksUpdated, int(^uint32(0)>>1))) //nolint:gosec // bounded by min
 	deleted := int32(min(result.NotebooksDeleted, int(^uint32(0)>>1))) //nolint:gosec // bounded by min
+	changes := make([]GitSyncChange, len(result.Changes))
+	for i, c := range result.Changes {
+		changes[i] = GitSyncChange{Action: GitSyncChangeAction(c.Action), Notebook: c.Notebook, Path: c.Path}
+	}
 	return SyncGitRepo200JSONResponse{
 		Body: GitSyncResult{
 			CommitSha:        &result.CommitSHA,
 			NotebooksCreated: &created,
 			NotebooksUpdated: &updated,
 			NotebooksDeleted: &deleted,
+			Ref:              &result.Ref,
+			DryRun:           &result.DryRun,
+			Changes:          &changes,
 		},
 		Headers: SyncGitRepo200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
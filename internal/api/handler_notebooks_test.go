@@ -144,16 +144,16 @@ func (m *mockSessionService) ListJobs(ctx context.Context, notebookID string, pa
 
 // mockGitRepoService implements gitRepoService using function fields.
 type mockGitRepoService struct {
-	createGitRepoFn func(ctx context.Context, principal string, req domain.CreateGitRepoRequest) (*domain.GitRepo, error)
+	createGitRepoFn func(ctx context.Context, principal string, isAdmin bool, req domain.CreateGitRepoRequest) (*domain.GitRepo, error)
 	getGitRepoFn    func(ctx context.Context, id string) (*domain.GitRepo, error)
 	listGitReposFn  func(ctx context.Context, page domain.PageRequest) ([]domain.GitRepo, int64, error)
 	deleteGitRepoFn func(ctx context.Context, principal string, isAdmin bool, id string) error
-	syncGitRepoFn   func(ctx context.Context, id string) (*domain.GitSyncResult, error)
+	syncGitRepoFn   func(ctx context.Context, principal string, isAdmin bool, id string, opts domain.GitSyncOptions) (*domain.GitSyncResult, error)
 }
 
-func (m *mockGitRepoService) CreateGitRepo(ctx context.Context, principal string, req domain.CreateGitRepoRequest) (*domain.GitRepo, error) {
+func (m *mockGitRepoService) CreateGitRepo(ctx context.Context, principal string, isAdmin bool, req domain.CreateGitRepoRequest) (*domain.GitRepo, error) {
 	if m.createGitRepoFn != nil {
-		return m.createGitRepoFn(ctx, principal, req)
+		return m.createGitRepoFn(ctx, principal, isAdmin, req)
 	}
 	panic("CreateGitRepo not implemented")
 }
@@ -175,9 +175,9 @@ func (m *mockGitRepoService) DeleteGitRepo(ctx context.Context, principal string
 	}
 	panic("DeleteGitRepo not implemented")
 }
-func (m *mockGitRepoService) SyncGitRepo(ctx context.Context, id string) (*domain.GitSyncResult, error) {
+func (m *mockGitRepoService) SyncGitRepo(ctx context.Context, principal string, isAdmin bool, id string, opts domain.GitSyncOptions) (*domain.GitSyncResult, error) {
 	if m.syncGitRepoFn != nil {
-		return m.syncGitRepoFn(ctx, id)
+		return m.syncGitRepoFn(ctx, principal, isAdmin, id, opts)
 	}
 	panic("SyncGitRepo not implemented")
 }
@@ -197,6 +197,15 @@ func setupNotebookTestServer(t *testing.T, nb notebookService, sess sessionServi
 		nil, // modelSvc
 		nil, // macroSvc
 		nil, // semanticSvc
+		nil, // applyLockSvc
+		nil, // webhookSvc
+		nil, // migrationSvc
+		nil, // backupSvc
+		nil, // versionSvc
+		nil, // extensionSvc
+		nil, // authzSvc
+		nil, // defaultGrantSvc
+		nil, // approvalSvc
 	)
 	strictHandler := NewStrictHandler(handler, nil)
 
@@ -275,15 +284,15 @@ func TestAPI_NotebookCRUD(t *testing.T) {
 		getNotebookFn: func(_ context.Context, id string) (*domain.Notebook, []domain.Cell, error) {
 			if id == nbID {
 				return &domain.Notebook{
-						ID:          nbID,
-						Name:        "My Notebook",
-						Description: &desc,
-						Owner:       "alice",
-						CreatedAt:   now,
-						UpdatedAt:   now,
-					}, []domain.Cell{
-						{ID: "cell-001", NotebookID: nbID, CellType: domain.CellTypeSQL, Content: "SELECT 1", Position: 0, CreatedAt: now, UpdatedAt: now},
-					}, nil
+					ID:          nbID,
+					Name:        "My Notebook",
+					Description: &desc,
+					Owner:       "alice",
+					CreatedAt:   now,
+					UpdatedAt:   now,
+				}, []domain.Cell{
+					{ID: "cell-001", NotebookID: nbID, CellType: domain.CellTypeSQL, Content: "SELECT 1", Position: 0, CreatedAt: now, UpdatedAt: now},
+				}, nil
 			}
 			return nil, nil, domain.ErrNotFound("notebook %s not found", id)
 		},
@@ -625,7 +634,10 @@ func TestAPI_GitRepoCRUD(t *testing.T) {
 	repoID := "repo-001"
 
 	gitSvc := &mockGitRepoService{
-		createGitRepoFn: func(_ context.Context, principal string, req domain.CreateGitRepoRequest) (*domain.GitRepo, error) {
+		createGitRepoFn: func(_ context.Context, principal string, isAdmin bool, req domain.CreateGitRepoRequest) (*domain.GitRepo, error) {
+			if !isAdmin {
+				return nil, domain.ErrAccessDenied("only admins can register git repos")
+			}
 			return &domain.GitRepo{
 				ID:        repoID,
 				URL:       req.URL,
@@ -661,7 +673,7 @@ func TestAPI_GitRepoCRUD(t *testing.T) {
 		},
 	}
 
-	srv := setupNotebookTestServer(t, nil, nil, gitSvc, "alice", false)
+	srv := setupNotebookTestServer(t, nil, nil, gitSvc, "alice", true)
 	defer srv.Close()
 
 	t.Run("create git repo", func(t *testing.T) {
@@ -677,6 +689,16 @@ func TestAPI_GitRepoCRUD(t *testing.T) {
 		assert.Equal(t, "alice", *repo.Owner)
 	})
 
+	t.Run("non-admin create gets 403", func(t *testing.T) {
+		userSrv := setupNotebookTestServer(t, nil, nil, gitSvc, "bob", false)
+		defer userSrv.Close()
+
+		body := `{"url":"https://github.com/org/repo.git","branch":"main","auth_token":"ghp_secret"}`
+		resp := nbDoRequest(t, http.MethodPost, userSrv.URL+"/git-repos", body)
+		defer resp.Body.Close() //nolint:errcheck
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
 	t.Run("get git repo", func(t *testing.T) {
 		resp := nbDoRequest(t, http.MethodGet, srv.URL+"/git-repos/"+repoID, "")
 		require.Equal(t, http.StatusOK, resp.StatusCode)
@@ -703,6 +725,53 @@ func TestAPI_GitRepoCRUD(t *testing.T) {
 	})
 }
 
+func TestAPI_GitRepoSync(t *testing.T) {
+	var gotOpts domain.GitSyncOptions
+	gitSvc := &mockGitRepoService{
+		syncGitRepoFn: func(_ context.Context, _ string, isAdmin bool, _ string, opts domain.GitSyncOptions) (*domain.GitSyncResult, error) {
+			if !isAdmin {
+				return nil, domain.ErrAccessDenied("only admins can sync git repos")
+			}
+			gotOpts = opts
+			return &domain.GitSyncResult{
+				NotebooksCreated: 1,
+				CommitSHA:        "a1b2c3",
+				Ref:              opts.Ref,
+				DryRun:           opts.DryRun,
+				Changes: []domain.GitSyncChange{
+					{Action: domain.GitSyncCreate, Notebook: "kpi", Path: "analytics/notebooks/kpi.yaml"},
+				},
+			}, nil
+		},
+	}
+
+	t.Run("passes ref and dry_run and reports changes", func(t *testing.T) {
+		srv := setupNotebookTestServer(t, nil, nil, gitSvc, "admin", true)
+		defer srv.Close()
+
+		resp := nbDoRequest(t, http.MethodPost, srv.URL+"/git-repos/repo-001/sync?ref=feature&dry_run=true", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, domain.GitSyncOptions{Ref: "feature", DryRun: true}, gotOpts)
+
+		result := nbDecodeJSON[GitSyncResult](t, resp)
+		assert.Equal(t, "feature", *result.Ref)
+		assert.True(t, *result.DryRun)
+		assert.Equal(t, int32(1), *result.NotebooksCreated)
+		require.NotNil(t, result.Changes)
+		require.Len(t, *result.Changes, 1)
+		assert.Equal(t, GitSyncChange{Action: "create", Notebook: "kpi", Path: "analytics/notebooks/kpi.yaml"}, (*result.Changes)[0])
+	})
+
+	t.Run("non-admin gets 403", func(t *testing.T) {
+		srv := setupNotebookTestServer(t, nil, nil, gitSvc, "alice", false)
+		defer srv.Close()
+
+		resp := nbDoRequest(t, http.MethodPost, srv.URL+"/git-repos/repo-001/sync", "")
+		defer resp.Body.Close() //nolint:errcheck
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
 func TestAPI_NotebookNotFound(t *testing.T) {
 	notebookSvc := &mockNotebookService{
 		getNotebookFn: func(_ context.Context, id string) (*domain.Notebook, []domain.Cell, error) {
@@ -12,6 +12,8 @@ import (
 // queryService defines the query operations used by the API handler.
 type queryService interface {
 	Execute(ctx context.Context, principalName, sqlQuery string) (*query.QueryResult, error)
+	ExecutePage(ctx context.Context, principalName, sqlQuery string, maxRows int) (*query.QueryResult, string, error)
+	FetchPage(ctx context.Context, principalName, token string, maxRows int) (*query.QueryResult, string, error)
 }
 
 type queryAsyncService interface {
@@ -35,7 +37,33 @@ type manifestService interface {
 func (h *APIHandler) ExecuteQuery(ctx context.Context, req ExecuteQueryRequestObject) (ExecuteQueryResponseObject, error) {
 	cp, _ := domain.PrincipalFromContext(ctx)
 	principal := cp.Name
-	result, err := h.query.Execute(ctx, principal, req.Body.Sql)
+	sqlQuery := ""
+	if req.Body.Sql != nil {
+		sqlQuery = *req.Body.Sql
+	}
+	maxRows := 0
+	if req.Body.MaxRows != nil {
+		maxRows = int(*req.Body.MaxRows)
+	}
+	var trace *domain.PolicyTrace
+	if req.Body.IncludePolicyMetadata != nil && *req.Body.IncludePolicyMetadata {
+		trace = &domain.PolicyTrace{}
+		ctx = domain.WithPolicyTrace(ctx, trace)
+	}
+
+	var (
+		result    *query.QueryResult
+		nextToken string
+		err       error
+	)
+	switch {
+	case req.Body.ResultToken != nil:
+		result, nextToken, err = h.query.FetchPage(ctx, principal, *req.Body.ResultToken, maxRows)
+	case maxRows > 0:
+		result, nextToken, err = h.query.ExecutePage(ctx, principal, sqlQuery, maxRows)
+	default:
+		result, err = h.query.Execute(ctx, principal, sqlQuery)
+	}
 	if err != nil {
 		code := errorCodeFromError(err)
 		msg := err.Error()
@@ -44,6 +72,10 @@ func (h *APIHandler) ExecuteQuery(ctx context.Context, req ExecuteQueryRequestOb
 			return ExecuteQuery400JSONResponse{BadRequestJSONResponse{Body: Error{Code: code, Message: msg}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case http.StatusForbidden:
 			return ExecuteQuery403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: code, Message: msg}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case http.StatusNotFound:
+			return ExecuteQuery404JSONResponse{NotFoundJSONResponse{Body: Error{Code: code, Message: msg}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case http.StatusConflict:
+			return ExecuteQuery409JSONResponse{ConflictJSONResponse{Body: Error{Code: code, ErrorCode: conflictErrorCode(err), Message: msg}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return ExecuteQuery500JSONResponse{InternalErrorJSONResponse{Body: Error{Code: code, Message: msg}, Headers: InternalErrorResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -56,17 +88,38 @@ func (h *APIHandler) ExecuteQuery(ctx context.Context, req ExecuteQueryRequestOb
 		rows[i] = mapped
 	}
 	rowCount := int64(result.RowCount)
+	body := QueryResult{
+		Columns:  &result.Columns,
+		Rows:     &rows,
+		RowCount: &rowCount,
+	}
+	if nextToken != "" {
+		body.NextPageToken = &nextToken
+	}
+	if trace != nil && req.Body.ResultToken == nil {
+		meta := policyMetadataToAPI(trace.Tables())
+		body.PolicyMetadata = &meta
+	}
 
 	return ExecuteQuery200JSONResponse{
-		Body: QueryResult{
-			Columns:  &result.Columns,
-			Rows:     &rows,
-			RowCount: &rowCount,
-		},
+		Body:    body,
 		Headers: ExecuteQuery200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
+// policyMetadataToAPI converts the policies applied to a query to the API type.
+func policyMetadataToAPI(tables []domain.TablePolicy) QueryPolicyMetadata {
+	out := make([]TablePolicyMetadata, len(tables))
+	for i, t := range tables {
+		masked := t.MaskedColumns
+		if masked == nil {
+			masked = []string{}
+		}
+		out[i] = TablePolicyMetadata{Table: t.Table, MaskedColumns: masked, RowFiltered: t.RowFiltered}
+	}
+	return QueryPolicyMetadata{Tables: out}
+}
+
 // SubmitQuery implements async query submission endpoint.
 func (h *APIHandler) SubmitQuery(ctx context.Context, req SubmitQueryRequestObject) (SubmitQueryResponseObject, error) {
 	cp, _ := domain.PrincipalFromContext(ctx)
@@ -140,22 +193,9 @@ func (h *APIHandler) GetQueryResults(ctx context.Context, req GetQueryResultsReq
 		return GetQueryResults409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: msg}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 	}
 
-	maxResults := int32(100)
-	if req.Params.MaxResults != nil {
-		maxResults = *req.Params.MaxResults
-	}
-	pageToken := ""
-	if req.Params.PageToken != nil {
-		pageToken = *req.Params.PageToken
-	}
-
-	offset := domain.PageRequest{PageToken: pageToken}.Offset()
-	limit := int(maxResults)
-	if limit <= 0 {
-		limit = 100
-	}
-
-	end := offset + limit
+	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	offset := page.Offset()
+	end := offset + page.Limit()
 	if end > len(job.Rows) {
 		end = len(job.Rows)
 	}
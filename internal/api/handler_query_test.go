@@ -17,12 +17,28 @@ type mockQueryAsyncService struct {
 	getFn    func(ctx context.Context, principalName, jobID string) (*domain.QueryJob, error)
 	cancelFn func(ctx context.Context, principalName, jobID string) error
 	deleteFn func(ctx context.Context, principalName, jobID string) error
+	pageFn   func(ctx context.Context, principalName, sqlQuery string, maxRows int) (*query.QueryResult, string, error)
+	fetchFn  func(ctx context.Context, principalName, token string, maxRows int) (*query.QueryResult, string, error)
 }
 
 func (m *mockQueryAsyncService) Execute(_ context.Context, _, _ string) (*query.QueryResult, error) {
 	return nil, domain.ErrNotImplemented("not used")
 }
 
+func (m *mockQueryAsyncService) ExecutePage(ctx context.Context, principalName, sqlQuery string, maxRows int) (*query.QueryResult, string, error) {
+	if m.pageFn == nil {
+		panic("pageFn not set")
+	}
+	return m.pageFn(ctx, principalName, sqlQuery, maxRows)
+}
+
+func (m *mockQueryAsyncService) FetchPage(ctx context.Context, principalName, token string, maxRows int) (*query.QueryResult, string, error) {
+	if m.fetchFn == nil {
+		panic("fetchFn not set")
+	}
+	return m.fetchFn(ctx, principalName, token, maxRows)
+}
+
 func (m *mockQueryAsyncService) SubmitAsync(ctx context.Context, principalName, sqlQuery, requestID string) (*domain.QueryJob, error) {
 	if m.submitFn == nil {
 		panic("submitFn not set")
@@ -76,13 +92,19 @@ func (m *mockManifestService) GetManifest(ctx context.Context, principalName, ca
 }
 
 type mockCatalogServiceForQuery struct {
-	profileTableFn func(ctx context.Context, catalogName string, principal string, schemaName, tableName string) (*domain.TableStatistics, error)
+	profileTableFn  func(ctx context.Context, catalogName string, principal string, schemaName, tableName string) (*domain.TableStatistics, error)
+	compactTableFn  func(ctx context.Context, catalogName string, principal string, schemaName, tableName string) (*domain.TableMaintenanceResult, error)
+	vacuumTableFn   func(ctx context.Context, catalogName string, principal string, schemaName, tableName string, olderThan time.Duration) (*domain.TableMaintenanceResult, error)
+	rollbackTableFn func(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error)
+	renameTableFn   func(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.RenameRequest) (*domain.TableDetail, error)
+	registerDirFn   func(ctx context.Context, principal string, req domain.RegisterDirectoryRequest) (*domain.RegisterDirectoryResult, error)
+	copyPoliciesFn  func(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.CopyPoliciesRequest) (*domain.CopyPoliciesResult, error)
 }
 
 func (m *mockCatalogServiceForQuery) GetCatalogInfo(_ context.Context, _ string) (*domain.CatalogInfo, error) {
 	panic("not implemented")
 }
-func (m *mockCatalogServiceForQuery) ListSchemas(_ context.Context, _ string, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+func (m *mockCatalogServiceForQuery) ListSchemas(_ context.Context, _ string, _ *string, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
 	panic("not implemented")
 }
 func (m *mockCatalogServiceForQuery) CreateSchema(_ context.Context, _ string, _ string, _ domain.CreateSchemaRequest) (*domain.SchemaDetail, error) {
@@ -97,7 +119,7 @@ func (m *mockCatalogServiceForQuery) UpdateSchema(_ context.Context, _ string, _
 func (m *mockCatalogServiceForQuery) DeleteSchema(_ context.Context, _ string, _ string, _ string, _ bool) error {
 	panic("not implemented")
 }
-func (m *mockCatalogServiceForQuery) ListTables(_ context.Context, _ string, _ string, _ domain.PageRequest) ([]domain.TableDetail, int64, error) {
+func (m *mockCatalogServiceForQuery) ListTables(_ context.Context, _ string, _ string, _ *string, _ domain.PageRequest) ([]domain.TableDetail, int64, error) {
 	panic("not implemented")
 }
 func (m *mockCatalogServiceForQuery) CreateTable(_ context.Context, _ string, _ string, _ string, _ domain.CreateTableRequest) (*domain.TableDetail, error) {
@@ -109,9 +131,21 @@ func (m *mockCatalogServiceForQuery) GetTable(_ context.Context, _ string, _ str
 func (m *mockCatalogServiceForQuery) UpdateTable(_ context.Context, _ string, _ string, _ string, _ string, _ domain.UpdateTableRequest) (*domain.TableDetail, error) {
 	panic("not implemented")
 }
-func (m *mockCatalogServiceForQuery) DeleteTable(_ context.Context, _ string, _ string, _ string, _ string) error {
+func (m *mockCatalogServiceForQuery) DeleteTable(_ context.Context, _ string, _ string, _ string, _ string, _ bool) error {
 	panic("not implemented")
 }
+func (m *mockCatalogServiceForQuery) RenameTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.RenameRequest) (*domain.TableDetail, error) {
+	if m.renameTableFn == nil {
+		panic("mockCatalogServiceForQuery.RenameTable called but not configured")
+	}
+	return m.renameTableFn(ctx, catalogName, principal, schemaName, tableName, req)
+}
+func (m *mockCatalogServiceForQuery) RegisterDirectory(ctx context.Context, principal string, req domain.RegisterDirectoryRequest) (*domain.RegisterDirectoryResult, error) {
+	if m.registerDirFn == nil {
+		panic("mockCatalogServiceForQuery.RegisterDirectory called but not configured")
+	}
+	return m.registerDirFn(ctx, principal, req)
+}
 func (m *mockCatalogServiceForQuery) ListColumns(_ context.Context, _ string, _ string, _ string, _ domain.PageRequest) ([]domain.ColumnDetail, int64, error) {
 	panic("not implemented")
 }
@@ -124,9 +158,39 @@ func (m *mockCatalogServiceForQuery) ProfileTable(ctx context.Context, catalogNa
 	}
 	return m.profileTableFn(ctx, catalogName, principal, schemaName, tableName)
 }
+func (m *mockCatalogServiceForQuery) CompactTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string) (*domain.TableMaintenanceResult, error) {
+	if m.compactTableFn == nil {
+		panic("mockCatalogServiceForQuery.CompactTable called but not configured")
+	}
+	return m.compactTableFn(ctx, catalogName, principal, schemaName, tableName)
+}
+func (m *mockCatalogServiceForQuery) VacuumTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, olderThan time.Duration) (*domain.TableMaintenanceResult, error) {
+	if m.vacuumTableFn == nil {
+		panic("mockCatalogServiceForQuery.VacuumTable called but not configured")
+	}
+	return m.vacuumTableFn(ctx, catalogName, principal, schemaName, tableName, olderThan)
+}
+func (m *mockCatalogServiceForQuery) RollbackTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+	if m.rollbackTableFn == nil {
+		panic("mockCatalogServiceForQuery.RollbackTable called but not configured")
+	}
+	return m.rollbackTableFn(ctx, catalogName, principal, schemaName, tableName, req)
+}
+func (m *mockCatalogServiceForQuery) CopyTablePolicies(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.CopyPoliciesRequest) (*domain.CopyPoliciesResult, error) {
+	if m.copyPoliciesFn == nil {
+		panic("mockCatalogServiceForQuery.CopyTablePolicies called but not configured")
+	}
+	return m.copyPoliciesFn(ctx, catalogName, principal, schemaName, tableName, req)
+}
+func (m *mockCatalogServiceForQuery) TableDependents(_ context.Context, _ string, _ string, _ string) ([]domain.DependentObject, error) {
+	panic("not implemented")
+}
 func (m *mockCatalogServiceForQuery) GetMetastoreSummary(_ context.Context, _ string) (*domain.MetastoreSummary, error) {
 	panic("not implemented")
 }
+func (m *mockCatalogServiceForQuery) GetCatalogStats(_ context.Context, _ string) (*domain.CatalogStats, error) {
+	panic("not implemented")
+}
 
 // === CreateManifest Tests ===
 
@@ -332,6 +396,289 @@ func TestHandler_ProfileTable(t *testing.T) {
 	}
 }
 
+// === CompactTable / VacuumTable Tests ===
+
+func TestHandler_CompactTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("happy path returns 200 with bytes reclaimed", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockCatalogServiceForQuery{compactTableFn: func(_ context.Context, _, _, _, _ string) (*domain.TableMaintenanceResult, error) {
+			return &domain.TableMaintenanceResult{
+				Operation: domain.TableMaintenanceCompact, FilesBefore: 48, FilesAfter: 3, BytesBefore: 5000, BytesAfter: 4800,
+			}, nil
+		}}
+		handler := &APIHandler{catalog: svc}
+		resp, err := handler.CompactTable(queryTestCtx(), CompactTableRequestObject{CatalogName: "lake", SchemaName: "main", TableName: "events"})
+		require.NoError(t, err)
+		ok200, ok := resp.(CompactTable200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Equal(t, TableMaintenanceResultOperation("COMPACT"), ok200.Body.Operation)
+		assert.Equal(t, int64(48), ok200.Body.FilesBefore)
+		assert.Equal(t, int64(3), ok200.Body.FilesAfter)
+		assert.Equal(t, int64(200), ok200.Body.BytesReclaimed)
+	})
+
+	t.Run("external table returns 400", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockCatalogServiceForQuery{compactTableFn: func(_ context.Context, _, _, _, _ string) (*domain.TableMaintenanceResult, error) {
+			return nil, domain.ErrValidation("table is EXTERNAL")
+		}}
+		handler := &APIHandler{catalog: svc}
+		resp, err := handler.CompactTable(queryTestCtx(), CompactTableRequestObject{CatalogName: "lake", SchemaName: "main", TableName: "ext"})
+		require.NoError(t, err)
+		_, ok := resp.(CompactTable400JSONResponse)
+		require.True(t, ok, "expected 400 response, got %T", resp)
+	})
+}
+
+func TestHandler_VacuumTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		body          *VacuumTableJSONRequestBody
+		wantOlderThan time.Duration
+		wantStatus    int
+	}{
+		{name: "no body defaults to 7d", wantOlderThan: 7 * 24 * time.Hour, wantStatus: 200},
+		{name: "older_than in days", body: &VacuumTableJSONRequestBody{OlderThan: queryTestStrPtr("30d")}, wantOlderThan: 30 * 24 * time.Hour, wantStatus: 200},
+		{name: "older_than in hours", body: &VacuumTableJSONRequestBody{OlderThan: queryTestStrPtr("36h")}, wantOlderThan: 36 * time.Hour, wantStatus: 200},
+		{name: "invalid older_than returns 400", body: &VacuumTableJSONRequestBody{OlderThan: queryTestStrPtr("0d")}, wantStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var gotOlderThan time.Duration
+			svc := &mockCatalogServiceForQuery{vacuumTableFn: func(_ context.Context, _, _, _, _ string, olderThan time.Duration) (*domain.TableMaintenanceResult, error) {
+				gotOlderThan = olderThan
+				return &domain.TableMaintenanceResult{Operation: domain.TableMaintenanceVacuum, SnapshotsExpired: 5}, nil
+			}}
+			handler := &APIHandler{catalog: svc}
+			resp, err := handler.VacuumTable(queryTestCtx(), VacuumTableRequestObject{
+				CatalogName: "lake", SchemaName: "main", TableName: "events", Body: tt.body,
+			})
+			require.NoError(t, err)
+			if tt.wantStatus == 400 {
+				_, ok := resp.(VacuumTable400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+				return
+			}
+			ok200, ok := resp.(VacuumTable200JSONResponse)
+			require.True(t, ok, "expected 200 response, got %T", resp)
+			assert.Equal(t, int64(5), ok200.Body.SnapshotsExpired)
+			assert.Equal(t, tt.wantOlderThan, gotOlderThan)
+		})
+	}
+}
+
+func TestHandler_RollbackTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps the request and reports dependents", func(t *testing.T) {
+		t.Parallel()
+		var got domain.RollbackTableRequest
+		newSnap := int64(58)
+		svc := &mockCatalogServiceForQuery{rollbackTableFn: func(_ context.Context, _, _, _, _ string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+			got = req
+			return &domain.TableRollbackResult{
+				CurrentSnapshot: 57, TargetSnapshot: 42, NewSnapshot: &newSnap, RowsBefore: 1250, RowsAfter: 1000, Applied: true,
+				Dependents: []domain.DependentObject{{Type: domain.DependentObjectView, Name: "reporting.daily_orders"}},
+			}, nil
+		}}
+		handler := &APIHandler{catalog: svc}
+		snap, confirm := int64(42), true
+		resp, err := handler.RollbackTable(queryTestCtx(), RollbackTableRequestObject{
+			CatalogName: "lake", SchemaName: "main", TableName: "orders",
+			Body: &RollbackTableJSONRequestBody{SnapshotId: &snap, Confirm: &confirm},
+		})
+		require.NoError(t, err)
+		ok200, ok := resp.(RollbackTable200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		require.NotNil(t, got.SnapshotID)
+		assert.Equal(t, int64(42), *got.SnapshotID)
+		assert.True(t, got.Confirm)
+		assert.True(t, ok200.Body.Applied)
+		assert.Equal(t, int64(1000), ok200.Body.RowsAfter)
+		assert.Equal(t, []DependentObject{{Type: DependentObjectType("VIEW"), Name: "reporting.daily_orders"}}, ok200.Body.Dependents)
+	})
+
+	t.Run("validation error returns 400", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockCatalogServiceForQuery{rollbackTableFn: func(_ context.Context, _, _, _, _ string, _ domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+			return nil, domain.ErrValidation("one of snapshot_id or timestamp is required")
+		}}
+		handler := &APIHandler{catalog: svc}
+		resp, err := handler.RollbackTable(queryTestCtx(), RollbackTableRequestObject{
+			CatalogName: "lake", SchemaName: "main", TableName: "orders", Body: &RollbackTableJSONRequestBody{},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(RollbackTable400JSONResponse)
+		require.True(t, ok, "expected 400 response, got %T", resp)
+	})
+
+	t.Run("access denied returns 403", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockCatalogServiceForQuery{rollbackTableFn: func(_ context.Context, _, _, _, _ string, _ domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+			return nil, domain.ErrAccessDenied("lacks MANAGE")
+		}}
+		handler := &APIHandler{catalog: svc}
+		snap := int64(42)
+		resp, err := handler.RollbackTable(queryTestCtx(), RollbackTableRequestObject{
+			CatalogName: "lake", SchemaName: "main", TableName: "orders", Body: &RollbackTableJSONRequestBody{SnapshotId: &snap},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(RollbackTable403JSONResponse)
+		require.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_RenameTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps the request and returns the renamed table", func(t *testing.T) {
+		t.Parallel()
+		var got domain.RenameRequest
+		svc := &mockCatalogServiceForQuery{renameTableFn: func(_ context.Context, _, _, schemaName, _ string, req domain.RenameRequest) (*domain.TableDetail, error) {
+			got = req
+			return &domain.TableDetail{TableID: "t-1", Name: req.NewName, SchemaName: schemaName}, nil
+		}}
+		handler := &APIHandler{catalog: svc}
+		force := true
+		resp, err := handler.RenameTable(queryTestCtx(), RenameTableRequestObject{
+			CatalogName: "lake", SchemaName: "main", TableName: "orders",
+			Body: &RenameTableJSONRequestBody{NewName: "purchases", Force: &force},
+		})
+		require.NoError(t, err)
+		ok200, ok := resp.(RenameTable200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Equal(t, domain.RenameRequest{NewName: "purchases", Force: true}, got)
+		require.NotNil(t, ok200.Body.Name)
+		assert.Equal(t, "purchases", *ok200.Body.Name)
+	})
+
+	t.Run("dependent views return 409", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockCatalogServiceForQuery{renameTableFn: func(_ context.Context, _, _, _, _ string, _ domain.RenameRequest) (*domain.TableDetail, error) {
+			return nil, domain.ErrConflict("table is read by view main.daily_orders")
+		}}
+		handler := &APIHandler{catalog: svc}
+		resp, err := handler.RenameTable(queryTestCtx(), RenameTableRequestObject{
+			CatalogName: "lake", SchemaName: "main", TableName: "orders", Body: &RenameTableJSONRequestBody{NewName: "purchases"},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(RenameTable409JSONResponse)
+		require.True(t, ok, "expected 409 response, got %T", resp)
+	})
+
+	t.Run("access denied returns 403", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockCatalogServiceForQuery{renameTableFn: func(_ context.Context, _, _, _, _ string, _ domain.RenameRequest) (*domain.TableDetail, error) {
+			return nil, domain.ErrAccessDenied("lacks MANAGE")
+		}}
+		handler := &APIHandler{catalog: svc}
+		resp, err := handler.RenameTable(queryTestCtx(), RenameTableRequestObject{
+			CatalogName: "lake", SchemaName: "main", TableName: "orders", Body: &RenameTableJSONRequestBody{NewName: "purchases"},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(RenameTable403JSONResponse)
+		require.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_CopyTablePolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps the request and reports copied and skipped policies", func(t *testing.T) {
+		t.Parallel()
+		var got domain.CopyPoliciesRequest
+		svc := &mockCatalogServiceForQuery{copyPoliciesFn: func(_ context.Context, _, _, schemaName, tableName string, req domain.CopyPoliciesRequest) (*domain.CopyPoliciesResult, error) {
+			got = req
+			return &domain.CopyPoliciesResult{
+				Source:  schemaName + "." + tableName,
+				Target:  req.TargetSchema + "." + req.TargetTable,
+				DryRun:  req.DryRun,
+				Copied:  []domain.CopiedPolicy{{Kind: domain.PolicyKindGrant, Description: "SELECT to group g-1"}},
+				Skipped: []domain.SkippedPolicy{{Kind: domain.PolicyKindColumnMask, Description: "ssn: NULL", Reason: "missing column"}},
+			}, nil
+		}}
+		handler := &APIHandler{catalog: svc}
+		dryRun := true
+		resp, err := handler.CopyTablePolicies(queryTestCtx(), CopyTablePoliciesRequestObject{
+			CatalogName: "lake", SchemaName: "analytics", TableName: "orders",
+			Body: &CopyTablePoliciesJSONRequestBody{TargetSchema: "analytics", TargetTable: "orders_v2", DryRun: &dryRun},
+		})
+		require.NoError(t, err)
+		ok200, ok := resp.(CopyTablePolicies200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Equal(t, domain.CopyPoliciesRequest{TargetSchema: "analytics", TargetTable: "orders_v2", DryRun: true}, got)
+		assert.Equal(t, "analytics.orders_v2", ok200.Body.Target)
+		assert.True(t, ok200.Body.DryRun)
+		require.Len(t, ok200.Body.Copied, 1)
+		assert.Equal(t, CopiedPolicyKind("GRANT"), ok200.Body.Copied[0].Kind)
+		require.Len(t, ok200.Body.Skipped, 1)
+		assert.Equal(t, "missing column", ok200.Body.Skipped[0].Reason)
+	})
+
+	t.Run("access denied returns 403", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockCatalogServiceForQuery{copyPoliciesFn: func(_ context.Context, _, _, _, _ string, _ domain.CopyPoliciesRequest) (*domain.CopyPoliciesResult, error) {
+			return nil, domain.ErrAccessDenied("admin privileges required")
+		}}
+		handler := &APIHandler{catalog: svc}
+		resp, err := handler.CopyTablePolicies(queryTestCtx(), CopyTablePoliciesRequestObject{
+			CatalogName: "lake", SchemaName: "analytics", TableName: "orders",
+			Body: &CopyTablePoliciesJSONRequestBody{TargetSchema: "analytics", TargetTable: "orders_v2"},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(CopyTablePolicies403JSONResponse)
+		require.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_RegisterDirectory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps the request and reports registered and skipped files", func(t *testing.T) {
+		t.Parallel()
+		var got domain.RegisterDirectoryRequest
+		svc := &mockCatalogServiceForQuery{registerDirFn: func(_ context.Context, _ string, req domain.RegisterDirectoryRequest) (*domain.RegisterDirectoryResult, error) {
+			got = req
+			return &domain.RegisterDirectoryResult{
+				Registered: []domain.TableDetail{{Name: "orders", SchemaName: "raw", TableType: domain.TableTypeExternal}},
+				Skipped:    []domain.SkippedFile{{Path: "s3://landing/returns.csv", TableName: "returns", Reason: "exists"}},
+			}, nil
+		}}
+		handler := &APIHandler{catalog: svc}
+		format := RegisterDirectoryRequestFileFormat("csv")
+		resp, err := handler.RegisterDirectory(queryTestCtx(), RegisterDirectoryRequestObject{
+			Body: &RegisterDirectoryJSONRequestBody{SchemaName: "raw", LocationName: "landing", Path: "s3://landing/", FileFormat: &format},
+		})
+		require.NoError(t, err)
+		ok200, ok := resp.(RegisterDirectory200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Equal(t, domain.RegisterDirectoryRequest{SchemaName: "raw", LocationName: "landing", Path: "s3://landing/", FileFormat: "csv"}, got)
+		require.Len(t, ok200.Body.Registered, 1)
+		assert.Equal(t, "orders", *ok200.Body.Registered[0].Name)
+		assert.Equal(t, []SkippedFile{{Path: "s3://landing/returns.csv", TableName: "returns", Reason: "exists"}}, ok200.Body.Skipped)
+	})
+
+	t.Run("no files returns 400", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockCatalogServiceForQuery{registerDirFn: func(_ context.Context, _ string, _ domain.RegisterDirectoryRequest) (*domain.RegisterDirectoryResult, error) {
+			return nil, domain.ErrValidation("no parquet files found")
+		}}
+		handler := &APIHandler{catalog: svc}
+		resp, err := handler.RegisterDirectory(queryTestCtx(), RegisterDirectoryRequestObject{
+			Body: &RegisterDirectoryJSONRequestBody{SchemaName: "raw", LocationName: "landing", Path: "s3://landing/"},
+		})
+		require.NoError(t, err)
+		_, ok := resp.(RegisterDirectory400JSONResponse)
+		require.True(t, ok, "expected 400 response, got %T", resp)
+	})
+}
+
 func TestHandler_SubmitQuery(t *testing.T) {
 	t.Parallel()
 
@@ -380,3 +727,83 @@ func TestHandler_GetQueryResults_Paged(t *testing.T) {
 	require.Len(t, *ok.Body.Rows, 1)
 	require.NotNil(t, ok.Body.NextPageToken)
 }
+
+func TestHandler_ExecuteQuery_Paged(t *testing.T) {
+	t.Parallel()
+
+	handler := &APIHandler{query: &mockQueryAsyncService{
+		pageFn: func(_ context.Context, principalName, sqlQuery string, maxRows int) (*query.QueryResult, string, error) {
+			require.Equal(t, "test-user", principalName)
+			require.Equal(t, "SELECT * FROM t", sqlQuery)
+			require.Equal(t, 2, maxRows)
+			return &query.QueryResult{Columns: []string{"id"}, Rows: [][]interface{}{{1}, {2}}, RowCount: 2}, "tok-1", nil
+		},
+		fetchFn: func(_ context.Context, _, token string, _ int) (*query.QueryResult, string, error) {
+			if token != "tok-1" {
+				return nil, "", domain.ErrNotFound("result token is unknown or expired")
+			}
+			return &query.QueryResult{Columns: []string{"id"}, Rows: [][]interface{}{{3}}, RowCount: 1}, "", nil
+		},
+	}}
+
+	maxRows := int32(2)
+	resp, err := handler.ExecuteQuery(queryTestCtx(), ExecuteQueryRequestObject{Body: &ExecuteQueryJSONRequestBody{Sql: queryTestStrPtr("SELECT * FROM t"), MaxRows: &maxRows}})
+	require.NoError(t, err)
+	first, okType := resp.(ExecuteQuery200JSONResponse)
+	require.True(t, okType)
+	require.Len(t, *first.Body.Rows, 2)
+	require.NotNil(t, first.Body.NextPageToken)
+
+	resp, err = handler.ExecuteQuery(queryTestCtx(), ExecuteQueryRequestObject{Body: &ExecuteQueryJSONRequestBody{ResultToken: first.Body.NextPageToken}})
+	require.NoError(t, err)
+	last, okType := resp.(ExecuteQuery200JSONResponse)
+	require.True(t, okType)
+	require.Len(t, *last.Body.Rows, 1)
+	assert.Nil(t, last.Body.NextPageToken)
+
+	resp, err = handler.ExecuteQuery(queryTestCtx(), ExecuteQueryRequestObject{Body: &ExecuteQueryJSONRequestBody{ResultToken: queryTestStrPtr("expired")}})
+	require.NoError(t, err)
+	_, okType = resp.(ExecuteQuery404JSONResponse)
+	assert.True(t, okType)
+}
+
+func TestHandler_ExecuteQuery_PolicyMetadata(t *testing.T) {
+	t.Parallel()
+
+	handler := &APIHandler{query: &mockQueryAsyncService{
+		pageFn: func(ctx context.Context, _, _ string, _ int) (*query.QueryResult, string, error) {
+			// The engine records applied policies into the trace on the context.
+			if trace := domain.PolicyTraceFromContext(ctx); trace != nil {
+				trace.Record(domain.TablePolicy{Table: "main.users", MaskedColumns: []string{"email"}, RowFiltered: true})
+			}
+			return &query.QueryResult{Columns: []string{"email"}, Rows: [][]interface{}{{"***"}}, RowCount: 1}, "", nil
+		},
+	}}
+	maxRows := int32(10)
+
+	t.Run("included on request", func(t *testing.T) {
+		t.Parallel()
+		include := true
+		resp, err := handler.ExecuteQuery(queryTestCtx(), ExecuteQueryRequestObject{Body: &ExecuteQueryJSONRequestBody{
+			Sql: queryTestStrPtr("SELECT email FROM main.users"), MaxRows: &maxRows, IncludePolicyMetadata: &include,
+		}})
+		require.NoError(t, err)
+		ok200, okType := resp.(ExecuteQuery200JSONResponse)
+		require.True(t, okType, "expected 200 response, got %T", resp)
+		require.NotNil(t, ok200.Body.PolicyMetadata)
+		assert.Equal(t, []TablePolicyMetadata{
+			{Table: "main.users", MaskedColumns: []string{"email"}, RowFiltered: true},
+		}, ok200.Body.PolicyMetadata.Tables)
+	})
+
+	t.Run("omitted by default", func(t *testing.T) {
+		t.Parallel()
+		resp, err := handler.ExecuteQuery(queryTestCtx(), ExecuteQueryRequestObject{Body: &ExecuteQueryJSONRequestBody{
+			Sql: queryTestStrPtr("SELECT email FROM main.users"), MaxRows: &maxRows,
+		}})
+		require.NoError(t, err)
+		ok200, okType := resp.(ExecuteQuery200JSONResponse)
+		require.True(t, okType, "expected 200 response, got %T", resp)
+		assert.Nil(t, ok200.Body.PolicyMetadata)
+	})
+}
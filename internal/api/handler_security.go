@@ -14,6 +14,11 @@ type principalService interface {
 	GetByID(ctx context.Context, id string) (*domain.Principal, error)
 	Delete(ctx context.Context, id string) error
 	SetAdmin(ctx context.Context, id string, isAdmin bool) error
+	GetAttributes(ctx context.Context, id string) (map[string]string, int64, error)
+	SetAttributes(ctx context.Context, id string, attrs map[string]string, expectedRowVersion *int64) (map[string]string, int64, error)
+	GetQueryDefaults(ctx context.Context, id string) (domain.PrincipalQueryDefaults, error)
+	SetQueryDefaults(ctx context.Context, id string, defaults domain.PrincipalQueryDefaults, expectedRowVersion *int64) (domain.PrincipalQueryDefaults, error)
+	Import(ctx context.Context, rows []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error)
 }
 
 // groupService defines the group operations used by the API handler.
@@ -25,33 +30,44 @@ type groupService interface {
 	ListMembers(ctx context.Context, groupID string, page domain.PageRequest) ([]domain.GroupMember, int64, error)
 	AddMember(ctx context.Context, req domain.AddGroupMemberRequest) error
 	RemoveMember(ctx context.Context, req domain.RemoveGroupMemberRequest) error
+	SyncMembers(ctx context.Context, req domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error)
 }
 
 // grantService defines the grant operations used by the API handler.
 type grantService interface {
-	ListAll(ctx context.Context, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
-	ListForPrincipal(ctx context.Context, principalID string, principalType string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
-	ListForSecurable(ctx context.Context, securableType string, securableID string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
+	List(ctx context.Context, filter domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error)
 	Grant(ctx context.Context, req domain.CreateGrantRequest) (*domain.PrivilegeGrant, error)
 	Revoke(ctx context.Context, principal string, grantID string) error
+	UpdateExpiry(ctx context.Context, grantID string, req domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error)
+	RevokeAll(ctx context.Context, principalID string, principalType string, removeMemberships bool) (*domain.RevokedAccess, error)
+}
+
+// authzCheckService defines the privilege-check simulation used by the API handler.
+type authzCheckService interface {
+	ExplainPrivilege(ctx context.Context, req domain.AccessCheckRequest) (*domain.AccessDecision, error)
+	EffectiveAccess(ctx context.Context, securableType, ref string) (*domain.SecurableAccess, error)
 }
 
 // rowFilterService defines the row filter operations used by the API handler.
 type rowFilterService interface {
 	GetForTable(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.RowFilter, int64, error)
+	GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.RowFilter, int64, error)
 	Create(ctx context.Context, req domain.CreateRowFilterRequest) (*domain.RowFilter, error)
 	Delete(ctx context.Context, id string) error
 	Bind(ctx context.Context, req domain.BindRowFilterRequest) error
 	Unbind(ctx context.Context, req domain.BindRowFilterRequest) error
+	SimulatePolicy(ctx context.Context, req domain.PolicySimulationRequest) (*domain.PolicySimulation, error)
 }
 
 // columnMaskService defines the column mask operations used by the API handler.
 type columnMaskService interface {
 	GetForTable(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error)
+	GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error)
 	Create(ctx context.Context, req domain.CreateColumnMaskRequest) (*domain.ColumnMask, error)
 	Delete(ctx context.Context, id string) error
 	Bind(ctx context.Context, req domain.BindColumnMaskRequest) error
 	Unbind(ctx context.Context, req domain.BindColumnMaskRequest) error
+	Preview(ctx context.Context, maskID, principalName string, limit int) (*domain.ColumnMaskPreview, error)
 }
 
 // === Principals ===
@@ -59,11 +75,16 @@ type columnMaskService interface {
 // ListPrincipals implements the endpoint for listing all principals. Requires admin privileges.
 func (h *APIHandler) ListPrincipals(ctx context.Context, req ListPrincipalsRequestObject) (ListPrincipalsResponseObject, error) {
 	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	if req.Params.Sort != nil {
+		page.Sort = string(*req.Params.Sort)
+	}
 	ps, total, err := h.principals.List(ctx, page)
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
 			return ListPrincipals403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return ListPrincipals400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
@@ -79,6 +100,39 @@ func (h *APIHandler) ListPrincipals(ctx context.Context, req ListPrincipalsReque
 	}, nil
 }
 
+// ImportPrincipals implements the endpoint for bulk-creating principals and
+// their group memberships.
+func (h *APIHandler) ImportPrincipals(ctx context.Context, req ImportPrincipalsRequestObject) (ImportPrincipalsResponseObject, error) {
+	rows := make([]domain.PrincipalImportRow, len(req.Body.Principals))
+	for i, p := range req.Body.Principals {
+		rows[i] = domain.PrincipalImportRow{Name: p.Name}
+		if p.Type != nil {
+			rows[i].Type = *p.Type
+		}
+		if p.IsAdmin != nil {
+			rows[i].IsAdmin = *p.IsAdmin
+		}
+		if p.Groups != nil {
+			rows[i].Groups = *p.Groups
+		}
+	}
+	results, err := h.importPrincipals(ctx, rows)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ImportPrincipals403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return ImportPrincipals400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return ImportPrincipals200JSONResponse{
+		Body:    principalImportResultsToAPI(results),
+		Headers: ImportPrincipals200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // CreatePrincipal implements the endpoint for creating a new principal.
 func (h *APIHandler) CreatePrincipal(ctx context.Context, req CreatePrincipalRequestObject) (CreatePrincipalResponseObject, error) {
 	domReq := domain.CreatePrincipalRequest{
@@ -90,7 +144,7 @@ func (h *APIHandler) CreatePrincipal(ctx context.Context, req CreatePrincipalReq
 	if req.Body.IsAdmin != nil {
 		domReq.IsAdmin = *req.Body.IsAdmin
 	}
-	result, err := h.principals.Create(ctx, domReq)
+	result, err := h.createPrincipal(ctx, domReq)
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
@@ -98,7 +152,7 @@ func (h *APIHandler) CreatePrincipal(ctx context.Context, req CreatePrincipalReq
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreatePrincipal400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreatePrincipal409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreatePrincipal409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
@@ -109,6 +163,30 @@ func (h *APIHandler) CreatePrincipal(ctx context.Context, req CreatePrincipalReq
 	}, nil
 }
 
+// createPrincipal creates a principal, refusing an admin one while granting
+// admin needs approval.
+func (h *APIHandler) createPrincipal(ctx context.Context, req domain.CreatePrincipalRequest) (*domain.Principal, error) {
+	if req.IsAdmin {
+		if err := h.adminGrantNeedsApproval(); err != nil {
+			return nil, err
+		}
+	}
+	return h.principals.Create(ctx, req)
+}
+
+// importPrincipals imports principals, refusing the whole batch when a row
+// is an admin while granting admin needs approval.
+func (h *APIHandler) importPrincipals(ctx context.Context, rows []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+	for _, r := range rows {
+		if r.IsAdmin {
+			if err := h.adminGrantNeedsApproval(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return h.principals.Import(ctx, rows)
+}
+
 // GetPrincipal implements the endpoint for retrieving a principal by ID.
 func (h *APIHandler) GetPrincipal(ctx context.Context, req GetPrincipalRequestObject) (GetPrincipalResponseObject, error) {
 	p, err := h.principals.GetByID(ctx, req.PrincipalId)
@@ -143,6 +221,23 @@ func (h *APIHandler) DeletePrincipal(ctx context.Context, req DeletePrincipalReq
 
 // UpdatePrincipalAdmin implements the endpoint for updating a principal's admin status.
 func (h *APIHandler) UpdatePrincipalAdmin(ctx context.Context, req UpdatePrincipalAdminRequestObject) (UpdatePrincipalAdminResponseObject, error) {
+	if req.Body.IsAdmin {
+		pending, err := h.submitApproval(ctx, domain.ApprovalOpSetAdmin, map[string]string{"principal_id": req.PrincipalId})
+		if err != nil {
+			switch {
+			case errors.As(err, new(*domain.AccessDeniedError)):
+				return UpdatePrincipalAdmin403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			default:
+				return nil, err
+			}
+		}
+		if pending != nil {
+			return UpdatePrincipalAdmin202JSONResponse{
+				Body:    approvalToAPI(*pending),
+				Headers: UpdatePrincipalAdmin202ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+			}, nil
+		}
+	}
 	if err := h.principals.SetAdmin(ctx, req.PrincipalId, req.Body.IsAdmin); err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
@@ -156,16 +251,122 @@ func (h *APIHandler) UpdatePrincipalAdmin(ctx context.Context, req UpdatePrincip
 	return UpdatePrincipalAdmin204Response{}, nil
 }
 
+// GetPrincipalAttributes implements the endpoint for reading a principal's attributes.
+func (h *APIHandler) GetPrincipalAttributes(ctx context.Context, req GetPrincipalAttributesRequestObject) (GetPrincipalAttributesResponseObject, error) {
+	attrs, version, err := h.principals.GetAttributes(ctx, req.PrincipalId)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return GetPrincipalAttributes403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return GetPrincipalAttributes404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	return GetPrincipalAttributes200JSONResponse{
+		Body:    PrincipalAttributes{PrincipalId: req.PrincipalId, Attributes: attrs},
+		Headers: GetPrincipalAttributes200ResponseHeaders{ETag: domain.ETag(version), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// SetPrincipalAttributes implements the endpoint for replacing a principal's attributes.
+func (h *APIHandler) SetPrincipalAttributes(ctx context.Context, req SetPrincipalAttributesRequestObject) (SetPrincipalAttributesResponseObject, error) {
+	ifMatch, err := ifMatchFromParams(req.Params.IfMatch)
+	if err != nil {
+		return SetPrincipalAttributes412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
+	attrs, version, err := h.principals.SetAttributes(ctx, req.PrincipalId, req.Body.Attributes, ifMatch)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return SetPrincipalAttributes403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return SetPrincipalAttributes400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return SetPrincipalAttributes404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return SetPrincipalAttributes412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return SetPrincipalAttributes200JSONResponse{
+		Body:    PrincipalAttributes{PrincipalId: req.PrincipalId, Attributes: attrs},
+		Headers: SetPrincipalAttributes200ResponseHeaders{ETag: domain.ETag(version), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// GetPrincipalQueryDefaults implements the endpoint for reading a principal's query defaults.
+func (h *APIHandler) GetPrincipalQueryDefaults(ctx context.Context, req GetPrincipalQueryDefaultsRequestObject) (GetPrincipalQueryDefaultsResponseObject, error) {
+	defaults, err := h.principals.GetQueryDefaults(ctx, req.PrincipalId)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return GetPrincipalQueryDefaults403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return GetPrincipalQueryDefaults404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return GetPrincipalQueryDefaults200JSONResponse{
+		Body:    principalQueryDefaultsToAPI(req.PrincipalId, defaults),
+		Headers: GetPrincipalQueryDefaults200ResponseHeaders{ETag: domain.ETag(defaults.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// SetPrincipalQueryDefaults implements the endpoint for replacing a principal's query defaults.
+func (h *APIHandler) SetPrincipalQueryDefaults(ctx context.Context, req SetPrincipalQueryDefaultsRequestObject) (SetPrincipalQueryDefaultsResponseObject, error) {
+	ifMatch, err := ifMatchFromParams(req.Params.IfMatch)
+	if err != nil {
+		return SetPrincipalQueryDefaults412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
+	defaults, err := h.principals.SetQueryDefaults(ctx, req.PrincipalId, domain.PrincipalQueryDefaults{
+		DefaultCatalog: valOrEmpty(req.Body.DefaultCatalog),
+		DefaultSchema:  valOrEmpty(req.Body.DefaultSchema),
+		Timezone:       valOrEmpty(req.Body.Timezone),
+	}, ifMatch)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return SetPrincipalQueryDefaults403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return SetPrincipalQueryDefaults400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return SetPrincipalQueryDefaults404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return SetPrincipalQueryDefaults412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return SetPrincipalQueryDefaults200JSONResponse{
+		Body:    principalQueryDefaultsToAPI(req.PrincipalId, defaults),
+		Headers: SetPrincipalQueryDefaults200ResponseHeaders{ETag: domain.ETag(defaults.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // === Groups ===
 
 // ListGroups implements the endpoint for listing all groups.
 func (h *APIHandler) ListGroups(ctx context.Context, req ListGroupsRequestObject) (ListGroupsResponseObject, error) {
 	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	if req.Params.Sort != nil {
+		page.Sort = string(*req.Params.Sort)
+	}
 	gs, total, err := h.groups.List(ctx, page)
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
 			return ListGroups403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return ListGroups400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
@@ -173,6 +374,7 @@ func (h *APIHandler) ListGroups(ctx context.Context, req ListGroupsRequestObject
 	out := make([]Group, len(gs))
 	for i, g := range gs {
 		out[i] = groupToAPI(g)
+		out[i].MemberCount = &gs[i].MemberCount
 	}
 	npt := domain.NextPageToken(page.Offset(), page.Limit(), total)
 	return ListGroups200JSONResponse{
@@ -289,23 +491,56 @@ func (h *APIHandler) DeleteGroupMember(ctx context.Context, req DeleteGroupMembe
 	return DeleteGroupMember204Response{}, nil
 }
 
+// SyncGroupMembers implements the endpoint for setting the exact membership of a group.
+func (h *APIHandler) SyncGroupMembers(ctx context.Context, req SyncGroupMembersRequestObject) (SyncGroupMembersResponseObject, error) {
+	domReq := domain.SyncGroupMembersRequest{
+		GroupID: req.GroupId,
+		Members: make([]domain.GroupMember, len(req.Body.Members)),
+	}
+	for i, m := range req.Body.Members {
+		domReq.Members[i] = domain.GroupMember{
+			GroupID:    req.GroupId,
+			MemberType: string(m.MemberType),
+			MemberID:   m.MemberId,
+		}
+	}
+	changes, err := h.groups.SyncMembers(ctx, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.ValidationError)):
+			return SyncGroupMembers400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return SyncGroupMembers403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return SyncGroupMembers404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return SyncGroupMembers200JSONResponse{
+		Body:    groupMembershipChangesToAPI(*changes, req.GroupId),
+		Headers: SyncGroupMembers200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // === Grants ===
 
-// ListGrants implements the endpoint for listing privilege grants filtered by principal or securable.
+// ListGrants implements the endpoint for listing privilege grants, filtered by any combination of principal, securable, and privilege.
 func (h *APIHandler) ListGrants(ctx context.Context, req ListGrantsRequestObject) (ListGrantsResponseObject, error) {
-	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
-	var grants []domain.PrivilegeGrant
-	var total int64
-	var err error
-
-	switch {
-	case req.Params.PrincipalId != nil && req.Params.PrincipalType != nil:
-		grants, total, err = h.grants.ListForPrincipal(ctx, *req.Params.PrincipalId, string(*req.Params.PrincipalType), page)
-	case req.Params.SecurableType != nil && req.Params.SecurableId != nil:
-		grants, total, err = h.grants.ListForSecurable(ctx, *req.Params.SecurableType, *req.Params.SecurableId, page)
-	default:
-		grants, total, err = h.grants.ListAll(ctx, page)
+	filter := domain.GrantFilter{
+		PrincipalID:   req.Params.PrincipalId,
+		SecurableType: req.Params.SecurableType,
+		SecurableID:   req.Params.SecurableId,
+		Page:          pageFromParams(req.Params.MaxResults, req.Params.PageToken),
+	}
+	if req.Params.PrincipalType != nil {
+		filter.PrincipalType = optStr(string(*req.Params.PrincipalType))
 	}
+	if req.Params.Privilege != nil {
+		filter.Privilege = optStr(string(*req.Params.Privilege))
+	}
+
+	grants, total, err := h.grants.List(ctx, filter)
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.AccessDeniedError)):
@@ -319,7 +554,7 @@ func (h *APIHandler) ListGrants(ctx context.Context, req ListGrantsRequestObject
 	for i, g := range grants {
 		out[i] = grantToAPI(g)
 	}
-	npt := domain.NextPageToken(page.Offset(), page.Limit(), total)
+	npt := domain.NextPageToken(filter.Page.Offset(), filter.Page.Limit(), total)
 	return ListGrants200JSONResponse{
 		Body:    PaginatedGrants{Data: &out, NextPageToken: optStr(npt)},
 		Headers: ListGrants200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
@@ -334,6 +569,7 @@ func (h *APIHandler) CreateGrant(ctx context.Context, req CreateGrantRequestObje
 		SecurableType: req.Body.SecurableType,
 		SecurableID:   req.Body.SecurableId,
 		Privilege:     string(req.Body.Privilege),
+		ExpiresAt:     req.Body.ExpiresAt,
 	}
 	result, err := h.grants.Grant(ctx, domReq)
 	if err != nil {
@@ -367,6 +603,145 @@ func (h *APIHandler) DeleteGrant(ctx context.Context, req DeleteGrantRequestObje
 	return DeleteGrant204Response{}, nil
 }
 
+// UpdateGrant implements the endpoint for changing when a grant expires.
+func (h *APIHandler) UpdateGrant(ctx context.Context, req UpdateGrantRequestObject) (UpdateGrantResponseObject, error) {
+	result, err := h.grants.UpdateExpiry(ctx, req.GrantId, domain.UpdateGrantRequest{ExpiresAt: req.Body.ExpiresAt})
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.ValidationError)):
+			return UpdateGrant400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return UpdateGrant403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return UpdateGrant404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return UpdateGrant200JSONResponse{
+		Body:    grantToAPI(*result),
+		Headers: UpdateGrant200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// RevokeAllPrincipalGrants implements the endpoint for revoking every grant
+// and, optionally, every group membership of a principal.
+func (h *APIHandler) RevokeAllPrincipalGrants(ctx context.Context, req RevokeAllPrincipalGrantsRequestObject) (RevokeAllPrincipalGrantsResponseObject, error) {
+	removeMemberships := req.Body != nil && req.Body.RemoveMemberships != nil && *req.Body.RemoveMemberships
+	revoked, err := h.grants.RevokeAll(ctx, req.PrincipalId, "user", removeMemberships)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return RevokeAllPrincipalGrants403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return RevokeAllPrincipalGrants404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	out := RevokedAccess{
+		PrincipalId:        req.PrincipalId,
+		RevokedGrants:      make([]PrivilegeGrant, len(revoked.Grants)),
+		RemovedMemberships: make([]GroupMember, len(revoked.Memberships)),
+	}
+	for i, g := range revoked.Grants {
+		out.RevokedGrants[i] = grantToAPI(g)
+	}
+	for i, m := range revoked.Memberships {
+		out.RemovedMemberships[i] = groupMemberToAPI(m, m.GroupID)
+	}
+	return RevokeAllPrincipalGrants200JSONResponse{
+		Body:    out,
+		Headers: RevokeAllPrincipalGrants200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// CheckPrivilege implements the endpoint for explaining whether a principal
+// holds a privilege on a securable.
+func (h *APIHandler) CheckPrivilege(ctx context.Context, req CheckPrivilegeRequestObject) (CheckPrivilegeResponseObject, error) {
+	decision, err := h.authz.ExplainPrivilege(ctx, domain.AccessCheckRequest{
+		Principal: req.Params.Principal,
+		Privilege: req.Params.Privilege,
+		Securable: req.Params.Securable,
+	})
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.ValidationError)):
+			return CheckPrivilege400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CheckPrivilege403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return CheckPrivilege404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return CheckPrivilege200JSONResponse{
+		Body:    accessDecisionToAPI(*decision),
+		Headers: CheckPrivilege200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// GetSecurableAccess implements the endpoint for listing who can access a
+// securable and how.
+func (h *APIHandler) GetSecurableAccess(ctx context.Context, req GetSecurableAccessRequestObject) (GetSecurableAccessResponseObject, error) {
+	access, err := h.authz.EffectiveAccess(ctx, string(req.SecurableType), req.SecurableId)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.ValidationError)):
+			return GetSecurableAccess400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return GetSecurableAccess403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return GetSecurableAccess404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return GetSecurableAccess200JSONResponse{
+		Body:    securableAccessToAPI(*access),
+		Headers: GetSecurableAccess200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// SimulatePolicy implements the endpoint for previewing a candidate row filter
+// or column mask as a principal without persisting it.
+func (h *APIHandler) SimulatePolicy(ctx context.Context, req SimulatePolicyRequestObject) (SimulatePolicyResponseObject, error) {
+	domReq := domain.PolicySimulationRequest{
+		Table:     req.Body.Table,
+		Principal: req.Body.Principal,
+	}
+	if req.Body.FilterSql != nil {
+		domReq.RowFilter = *req.Body.FilterSql
+	}
+	if req.Body.ColumnName != nil {
+		domReq.ColumnName = *req.Body.ColumnName
+	}
+	if req.Body.MaskExpression != nil {
+		domReq.MaskExpression = *req.Body.MaskExpression
+	}
+	if req.Body.Limit != nil {
+		domReq.Limit = int(*req.Body.Limit)
+	}
+	sim, err := h.rowFilters.SimulatePolicy(ctx, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return SimulatePolicy403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return SimulatePolicy400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return SimulatePolicy404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return SimulatePolicy200JSONResponse{
+		Body:    policySimulationToAPI(sim),
+		Headers: SimulatePolicy200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // === Row Filters ===
 
 // ListRowFilters implements the endpoint for listing row filters for a table.
@@ -418,6 +793,52 @@ func (h *APIHandler) CreateRowFilter(ctx context.Context, req CreateRowFilterReq
 	}, nil
 }
 
+// ListTagRowFilters implements the endpoint for listing the row filters that target a tag.
+func (h *APIHandler) ListTagRowFilters(ctx context.Context, req ListTagRowFiltersRequestObject) (ListTagRowFiltersResponseObject, error) {
+	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	fs, total, err := h.rowFilters.GetForTag(ctx, req.TagId, page)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RowFilter, len(fs))
+	for i, f := range fs {
+		out[i] = rowFilterToAPI(f)
+	}
+	npt := domain.NextPageToken(page.Offset(), page.Limit(), total)
+	return ListTagRowFilters200JSONResponse{
+		Body:    PaginatedRowFilters{Data: &out, NextPageToken: optStr(npt)},
+		Headers: ListTagRowFilters200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// CreateTagRowFilter implements the endpoint for creating a row filter on every table assigned a tag.
+func (h *APIHandler) CreateTagRowFilter(ctx context.Context, req CreateTagRowFilterRequestObject) (CreateTagRowFilterResponseObject, error) {
+	domReq := domain.CreateRowFilterRequest{
+		TagID:     req.TagId,
+		FilterSQL: req.Body.FilterSql,
+	}
+	if req.Body.Description != nil {
+		domReq.Description = *req.Body.Description
+	}
+	result, err := h.rowFilters.Create(ctx, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CreateTagRowFilter403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return CreateTagRowFilter400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return CreateTagRowFilter404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return CreateTagRowFilter201JSONResponse{
+		Body:    rowFilterToAPI(*result),
+		Headers: CreateTagRowFilter201ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // DeleteRowFilter implements the endpoint for deleting a row filter.
 func (h *APIHandler) DeleteRowFilter(ctx context.Context, req DeleteRowFilterRequestObject) (DeleteRowFilterResponseObject, error) {
 	if err := h.rowFilters.Delete(ctx, req.RowFilterId); err != nil {
@@ -518,6 +939,54 @@ func (h *APIHandler) CreateColumnMask(ctx context.Context, req CreateColumnMaskR
 	}, nil
 }
 
+// ListTagColumnMasks implements the endpoint for listing the column masks that target a tag.
+func (h *APIHandler) ListTagColumnMasks(ctx context.Context, req ListTagColumnMasksRequestObject) (ListTagColumnMasksResponseObject, error) {
+	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	ms, total, err := h.columnMasks.GetForTag(ctx, req.TagId, page)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ColumnMask, len(ms))
+	for i, m := range ms {
+		out[i] = columnMaskToAPI(m)
+	}
+	npt := domain.NextPageToken(page.Offset(), page.Limit(), total)
+	return ListTagColumnMasks200JSONResponse{
+		Body:    PaginatedColumnMasks{Data: &out, NextPageToken: optStr(npt)},
+		Headers: ListTagColumnMasks200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// CreateTagColumnMask implements the endpoint for creating a column mask on every column assigned a tag.
+func (h *APIHandler) CreateTagColumnMask(ctx context.Context, req CreateTagColumnMaskRequestObject) (CreateTagColumnMaskResponseObject, error) {
+	domReq := domain.CreateColumnMaskRequest{
+		TagID:          req.TagId,
+		MaskExpression: req.Body.MaskExpression,
+	}
+	if req.Body.Description != nil {
+		domReq.Description = *req.Body.Description
+	}
+	result, err := h.columnMasks.Create(ctx, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CreateTagColumnMask403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return CreateTagColumnMask400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return CreateTagColumnMask404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return CreateTagColumnMask409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return CreateTagColumnMask201JSONResponse{
+		Body:    columnMaskToAPI(*result),
+		Headers: CreateTagColumnMask201ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // DeleteColumnMask implements the endpoint for deleting a column mask.
 func (h *APIHandler) DeleteColumnMask(ctx context.Context, req DeleteColumnMaskRequestObject) (DeleteColumnMaskResponseObject, error) {
 	if err := h.columnMasks.Delete(ctx, req.ColumnMaskId); err != nil {
@@ -557,6 +1026,31 @@ func (h *APIHandler) BindColumnMask(ctx context.Context, req BindColumnMaskReque
 	return BindColumnMask204Response{}, nil
 }
 
+// PreviewColumnMask implements the endpoint for previewing a column mask as a principal.
+func (h *APIHandler) PreviewColumnMask(ctx context.Context, req PreviewColumnMaskRequestObject) (PreviewColumnMaskResponseObject, error) {
+	limit := 0
+	if req.Params.Limit != nil {
+		limit = int(*req.Params.Limit)
+	}
+	p, err := h.columnMasks.Preview(ctx, req.ColumnMaskId, req.Params.Principal, limit)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return PreviewColumnMask403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return PreviewColumnMask400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return PreviewColumnMask404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return PreviewColumnMask200JSONResponse{
+		Body:    columnMaskPreviewToAPI(p),
+		Headers: PreviewColumnMask200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
 // UnbindColumnMask implements the endpoint for unbinding a column mask from a principal.
 func (h *APIHandler) UnbindColumnMask(ctx context.Context, req UnbindColumnMaskRequestObject) (UnbindColumnMaskResponseObject, error) {
 	if err := h.columnMasks.Unbind(ctx, domain.BindColumnMaskRequest{
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -19,6 +20,11 @@ type mockPrincipalService struct {
 	getByIDFn  func(ctx context.Context, id string) (*domain.Principal, error)
 	deleteFn   func(ctx context.Context, id string) error
 	setAdminFn func(ctx context.Context, id string, isAdmin bool) error
+	getAttrsFn func(ctx context.Context, id string) (map[string]string, int64, error)
+	setAttrsFn func(ctx context.Context, id string, attrs map[string]string, expectedRowVersion *int64) (map[string]string, int64, error)
+	getQDefsFn func(ctx context.Context, id string) (domain.PrincipalQueryDefaults, error)
+	setQDefsFn func(ctx context.Context, id string, defaults domain.PrincipalQueryDefaults, expectedRowVersion *int64) (domain.PrincipalQueryDefaults, error)
+	importFn   func(ctx context.Context, rows []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error)
 }
 
 func (m *mockPrincipalService) List(ctx context.Context, page domain.PageRequest) ([]domain.Principal, int64, error) {
@@ -56,6 +62,41 @@ func (m *mockPrincipalService) SetAdmin(ctx context.Context, id string, isAdmin
 	return m.setAdminFn(ctx, id, isAdmin)
 }
 
+func (m *mockPrincipalService) GetAttributes(ctx context.Context, id string) (map[string]string, int64, error) {
+	if m.getAttrsFn == nil {
+		panic("mockPrincipalService.GetAttributes called but not configured")
+	}
+	return m.getAttrsFn(ctx, id)
+}
+
+func (m *mockPrincipalService) SetAttributes(ctx context.Context, id string, attrs map[string]string, expectedRowVersion *int64) (map[string]string, int64, error) {
+	if m.setAttrsFn == nil {
+		panic("mockPrincipalService.SetAttributes called but not configured")
+	}
+	return m.setAttrsFn(ctx, id, attrs, expectedRowVersion)
+}
+
+func (m *mockPrincipalService) GetQueryDefaults(ctx context.Context, id string) (domain.PrincipalQueryDefaults, error) {
+	if m.getQDefsFn == nil {
+		panic("mockPrincipalService.GetQueryDefaults called but not configured")
+	}
+	return m.getQDefsFn(ctx, id)
+}
+
+func (m *mockPrincipalService) SetQueryDefaults(ctx context.Context, id string, defaults domain.PrincipalQueryDefaults, expectedRowVersion *int64) (domain.PrincipalQueryDefaults, error) {
+	if m.setQDefsFn == nil {
+		panic("mockPrincipalService.SetQueryDefaults called but not configured")
+	}
+	return m.setQDefsFn(ctx, id, defaults, expectedRowVersion)
+}
+
+func (m *mockPrincipalService) Import(ctx context.Context, rows []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+	if m.importFn == nil {
+		panic("mockPrincipalService.Import called but not configured")
+	}
+	return m.importFn(ctx, rows)
+}
+
 type mockGroupService struct {
 	listFn         func(ctx context.Context, page domain.PageRequest) ([]domain.Group, int64, error)
 	createFn       func(ctx context.Context, req domain.CreateGroupRequest) (*domain.Group, error)
@@ -64,6 +105,7 @@ type mockGroupService struct {
 	listMembersFn  func(ctx context.Context, groupID string, page domain.PageRequest) ([]domain.GroupMember, int64, error)
 	addMemberFn    func(ctx context.Context, req domain.AddGroupMemberRequest) error
 	removeMemberFn func(ctx context.Context, req domain.RemoveGroupMemberRequest) error
+	syncMembersFn  func(ctx context.Context, req domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error)
 }
 
 func (m *mockGroupService) List(ctx context.Context, page domain.PageRequest) ([]domain.Group, int64, error) {
@@ -115,33 +157,26 @@ func (m *mockGroupService) RemoveMember(ctx context.Context, req domain.RemoveGr
 	return m.removeMemberFn(ctx, req)
 }
 
-type mockGrantService struct {
-	listForPrincipalFn func(ctx context.Context, principalID string, principalType string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
-	listForSecurableFn func(ctx context.Context, securableType string, securableID string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
-	listAllFn          func(ctx context.Context, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
-	grantFn            func(ctx context.Context, req domain.CreateGrantRequest) (*domain.PrivilegeGrant, error)
-	revokeFn           func(ctx context.Context, principal string, grantID string) error
-}
-
-func (m *mockGrantService) ListForPrincipal(ctx context.Context, principalID string, principalType string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
-	if m.listForPrincipalFn == nil {
-		panic("mockGrantService.ListForPrincipal called but not configured")
+func (m *mockGroupService) SyncMembers(ctx context.Context, req domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error) {
+	if m.syncMembersFn == nil {
+		panic("mockGroupService.SyncMembers called but not configured")
 	}
-	return m.listForPrincipalFn(ctx, principalID, principalType, page)
+	return m.syncMembersFn(ctx, req)
 }
 
-func (m *mockGrantService) ListForSecurable(ctx context.Context, securableType string, securableID string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
-	if m.listForSecurableFn == nil {
-		panic("mockGrantService.ListForSecurable called but not configured")
-	}
-	return m.listForSecurableFn(ctx, securableType, securableID, page)
+type mockGrantService struct {
+	listFn      func(ctx context.Context, filter domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error)
+	grantFn     func(ctx context.Context, req domain.CreateGrantRequest) (*domain.PrivilegeGrant, error)
+	revokeFn    func(ctx context.Context, principal string, grantID string) error
+	updateFn    func(ctx context.Context, grantID string, req domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error)
+	revokeAllFn func(ctx context.Context, principalID string, principalType string, removeMemberships bool) (*domain.RevokedAccess, error)
 }
 
-func (m *mockGrantService) ListAll(ctx context.Context, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
-	if m.listAllFn == nil {
-		panic("mockGrantService.ListAll called but not configured")
+func (m *mockGrantService) List(ctx context.Context, filter domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error) {
+	if m.listFn == nil {
+		panic("mockGrantService.List called but not configured")
 	}
-	return m.listAllFn(ctx, page)
+	return m.listFn(ctx, filter)
 }
 
 func (m *mockGrantService) Grant(ctx context.Context, req domain.CreateGrantRequest) (*domain.PrivilegeGrant, error) {
@@ -158,12 +193,47 @@ func (m *mockGrantService) Revoke(ctx context.Context, principal string, grantID
 	return m.revokeFn(ctx, principal, grantID)
 }
 
+func (m *mockGrantService) UpdateExpiry(ctx context.Context, grantID string, req domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error) {
+	if m.updateFn == nil {
+		panic("mockGrantService.UpdateExpiry called but not configured")
+	}
+	return m.updateFn(ctx, grantID, req)
+}
+
+func (m *mockGrantService) RevokeAll(ctx context.Context, principalID string, principalType string, removeMemberships bool) (*domain.RevokedAccess, error) {
+	if m.revokeAllFn == nil {
+		panic("mockGrantService.RevokeAll called but not configured")
+	}
+	return m.revokeAllFn(ctx, principalID, principalType, removeMemberships)
+}
+
+type mockAuthzCheckService struct {
+	explainFn         func(ctx context.Context, req domain.AccessCheckRequest) (*domain.AccessDecision, error)
+	effectiveAccessFn func(ctx context.Context, securableType, ref string) (*domain.SecurableAccess, error)
+}
+
+func (m *mockAuthzCheckService) ExplainPrivilege(ctx context.Context, req domain.AccessCheckRequest) (*domain.AccessDecision, error) {
+	if m.explainFn == nil {
+		panic("mockAuthzCheckService.ExplainPrivilege called but not configured")
+	}
+	return m.explainFn(ctx, req)
+}
+
+func (m *mockAuthzCheckService) EffectiveAccess(ctx context.Context, securableType, ref string) (*domain.SecurableAccess, error) {
+	if m.effectiveAccessFn == nil {
+		panic("mockAuthzCheckService.EffectiveAccess called but not configured")
+	}
+	return m.effectiveAccessFn(ctx, securableType, ref)
+}
+
 type mockColumnMaskService struct {
 	getForTableFn func(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error)
+	getForTagFn   func(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error)
 	createFn      func(ctx context.Context, req domain.CreateColumnMaskRequest) (*domain.ColumnMask, error)
 	deleteFn      func(ctx context.Context, id string) error
 	bindFn        func(ctx context.Context, req domain.BindColumnMaskRequest) error
 	unbindFn      func(ctx context.Context, req domain.BindColumnMaskRequest) error
+	previewFn     func(ctx context.Context, maskID, principalName string, limit int) (*domain.ColumnMaskPreview, error)
 }
 
 func (m *mockColumnMaskService) GetForTable(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error) {
@@ -173,6 +243,13 @@ func (m *mockColumnMaskService) GetForTable(ctx context.Context, tableID string,
 	return m.getForTableFn(ctx, tableID, page)
 }
 
+func (m *mockColumnMaskService) GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error) {
+	if m.getForTagFn == nil {
+		panic("mockColumnMaskService.GetForTag called but not configured")
+	}
+	return m.getForTagFn(ctx, tagID, page)
+}
+
 func (m *mockColumnMaskService) Create(ctx context.Context, req domain.CreateColumnMaskRequest) (*domain.ColumnMask, error) {
 	if m.createFn == nil {
 		panic("mockColumnMaskService.Create called but not configured")
@@ -201,6 +278,72 @@ func (m *mockColumnMaskService) Unbind(ctx context.Context, req domain.BindColum
 	return m.unbindFn(ctx, req)
 }
 
+func (m *mockColumnMaskService) Preview(ctx context.Context, maskID, principalName string, limit int) (*domain.ColumnMaskPreview, error) {
+	if m.previewFn == nil {
+		panic("mockColumnMaskService.Preview called but not configured")
+	}
+	return m.previewFn(ctx, maskID, principalName, limit)
+}
+
+type mockRowFilterService struct {
+	getForTableFn func(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.RowFilter, int64, error)
+	getForTagFn   func(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.RowFilter, int64, error)
+	createFn      func(ctx context.Context, req domain.CreateRowFilterRequest) (*domain.RowFilter, error)
+	deleteFn      func(ctx context.Context, id string) error
+	bindFn        func(ctx context.Context, req domain.BindRowFilterRequest) error
+	unbindFn      func(ctx context.Context, req domain.BindRowFilterRequest) error
+	simulateFn    func(ctx context.Context, req domain.PolicySimulationRequest) (*domain.PolicySimulation, error)
+}
+
+func (m *mockRowFilterService) GetForTable(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.RowFilter, int64, error) {
+	if m.getForTableFn == nil {
+		panic("mockRowFilterService.GetForTable called but not configured")
+	}
+	return m.getForTableFn(ctx, tableID, page)
+}
+
+func (m *mockRowFilterService) GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.RowFilter, int64, error) {
+	if m.getForTagFn == nil {
+		panic("mockRowFilterService.GetForTag called but not configured")
+	}
+	return m.getForTagFn(ctx, tagID, page)
+}
+
+func (m *mockRowFilterService) Create(ctx context.Context, req domain.CreateRowFilterRequest) (*domain.RowFilter, error) {
+	if m.createFn == nil {
+		panic("mockRowFilterService.Create called but not configured")
+	}
+	return m.createFn(ctx, req)
+}
+
+func (m *mockRowFilterService) Delete(ctx context.Context, id string) error {
+	if m.deleteFn == nil {
+		panic("mockRowFilterService.Delete called but not configured")
+	}
+	return m.deleteFn(ctx, id)
+}
+
+func (m *mockRowFilterService) Bind(ctx context.Context, req domain.BindRowFilterRequest) error {
+	if m.bindFn == nil {
+		panic("mockRowFilterService.Bind called but not configured")
+	}
+	return m.bindFn(ctx, req)
+}
+
+func (m *mockRowFilterService) Unbind(ctx context.Context, req domain.BindRowFilterRequest) error {
+	if m.unbindFn == nil {
+		panic("mockRowFilterService.Unbind called but not configured")
+	}
+	return m.unbindFn(ctx, req)
+}
+
+func (m *mockRowFilterService) SimulatePolicy(ctx context.Context, req domain.PolicySimulationRequest) (*domain.PolicySimulation, error) {
+	if m.simulateFn == nil {
+		panic("mockRowFilterService.SimulatePolicy called but not configured")
+	}
+	return m.simulateFn(ctx, req)
+}
+
 // === Helpers ===
 
 func secTestCtx() context.Context {
@@ -316,40 +459,63 @@ func TestHandler_GetPrincipal(t *testing.T) {
 	}
 }
 
-func TestHandler_GetGroup(t *testing.T) {
+func TestHandler_ImportPrincipals(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name     string
-		svcFn    func(ctx context.Context, id string) (*domain.Group, error)
-		assertFn func(t *testing.T, resp GetGroupResponseObject, err error)
+		svcFn    func(ctx context.Context, rows []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error)
+		assertFn func(t *testing.T, resp ImportPrincipalsResponseObject, err error)
 	}{
 		{
-			name: "happy path returns 200",
-			svcFn: func(_ context.Context, _ string) (*domain.Group, error) {
-				g := secSampleGroup()
-				return &g, nil
+			name: "happy path reports each row",
+			svcFn: func(_ context.Context, rows []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+				assert.Equal(t, []domain.PrincipalImportRow{
+					{Name: "alice", Groups: []string{"analysts"}},
+					{Name: "etl-bot", Type: "service_principal", IsAdmin: true},
+				}, rows)
+				return []domain.PrincipalImportResult{
+					{Name: "alice", PrincipalID: "p-1", Status: domain.PrincipalImportSkipped, GroupsAdded: []string{"analysts"}},
+					{Name: "etl-bot", PrincipalID: "p-2", Status: domain.PrincipalImportCreated},
+				}, nil
 			},
-			assertFn: func(t *testing.T, resp GetGroupResponseObject, err error) {
+			assertFn: func(t *testing.T, resp ImportPrincipalsResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				ok200, ok := resp.(GetGroup200JSONResponse)
+				ok200, ok := resp.(ImportPrincipals200JSONResponse)
 				require.True(t, ok, "expected 200 response, got %T", resp)
-				assert.Equal(t, "g-1", *ok200.Body.Id)
-				assert.Equal(t, "engineers", *ok200.Body.Name)
+				assert.Equal(t, int32(1), ok200.Body.Created)
+				assert.Equal(t, int32(1), ok200.Body.Skipped)
+				require.Len(t, ok200.Body.Results, 2)
+				assert.Equal(t, PrincipalImportResultStatus("skipped"), ok200.Body.Results[0].Status)
+				assert.Equal(t, []string{"analysts"}, ok200.Body.Results[0].GroupsAdded)
+				assert.Equal(t, "p-2", ok200.Body.Results[1].PrincipalId)
+				assert.Equal(t, []string{}, ok200.Body.Results[1].GroupsAdded)
 			},
 		},
 		{
-			name: "not found returns 404",
-			svcFn: func(_ context.Context, id string) (*domain.Group, error) {
-				return nil, domain.ErrNotFound("group %s not found", id)
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _ []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+				return nil, domain.ErrValidation("row 2: group %q does not exist", "nonexistent")
 			},
-			assertFn: func(t *testing.T, resp GetGroupResponseObject, err error) {
+			assertFn: func(t *testing.T, resp ImportPrincipalsResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				notFound, ok := resp.(GetGroup404JSONResponse)
-				require.True(t, ok, "expected 404 response, got %T", resp)
-				assert.Equal(t, int32(404), notFound.Body.Code)
+				bad, ok := resp.(ImportPrincipals400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+				assert.Contains(t, bad.Body.Message, "row 2")
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+				return nil, domain.ErrAccessDenied("not allowed")
+			},
+			assertFn: func(t *testing.T, resp ImportPrincipalsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(ImportPrincipals403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
 			},
 		},
 	}
@@ -357,48 +523,79 @@ func TestHandler_GetGroup(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			svc := &mockGroupService{getByIDFn: tt.svcFn}
-			handler := &APIHandler{groups: svc}
-			resp, err := handler.GetGroup(secTestCtx(), GetGroupRequestObject{GroupId: "g-1"})
+			handler := &APIHandler{principals: &mockPrincipalService{importFn: tt.svcFn}}
+			spType, isAdmin, groups := "service_principal", true, []string{"analysts"}
+			body := ImportPrincipalsJSONRequestBody{
+				Principals: []PrincipalImportRow{
+					{Name: "alice", Groups: &groups},
+					{Name: "etl-bot", Type: &spType, IsAdmin: &isAdmin},
+				},
+			}
+			resp, err := handler.ImportPrincipals(secTestCtx(), ImportPrincipalsRequestObject{Body: &body})
 			tt.assertFn(t, resp, err)
 		})
 	}
 }
 
-func TestHandler_ListGroupMembers(t *testing.T) {
+func TestHandler_GetPrincipalAttributes(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name     string
-		svcFn    func(ctx context.Context, groupID string, page domain.PageRequest) ([]domain.GroupMember, int64, error)
-		assertFn func(t *testing.T, resp ListGroupMembersResponseObject, err error)
+		svcFn    func(ctx context.Context, id string) (map[string]string, int64, error)
+		assertFn func(t *testing.T, resp GetPrincipalAttributesResponseObject, err error)
 	}{
 		{
-			name: "happy path returns 200 with members",
-			svcFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.GroupMember, int64, error) {
-				return []domain.GroupMember{secSampleGroupMember()}, 1, nil
+			name: "happy path returns 200",
+			svcFn: func(_ context.Context, _ string) (map[string]string, int64, error) {
+				return map[string]string{"region": "EU"}, 3, nil
 			},
-			assertFn: func(t *testing.T, resp ListGroupMembersResponseObject, err error) {
+			assertFn: func(t *testing.T, resp GetPrincipalAttributesResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				ok200, ok := resp.(ListGroupMembers200JSONResponse)
+				ok200, ok := resp.(GetPrincipalAttributes200JSONResponse)
 				require.True(t, ok, "expected 200 response, got %T", resp)
-				require.NotNil(t, ok200.Body.Data)
-				require.Len(t, *ok200.Body.Data, 1)
-				assert.Equal(t, "p-1", *(*ok200.Body.Data)[0].MemberId)
+				assert.Equal(t, "p-1", ok200.Body.PrincipalId)
+				assert.Equal(t, map[string]string{"region": "EU"}, ok200.Body.Attributes)
+				assert.Equal(t, `"3"`, ok200.Headers.ETag)
+			},
+		},
+		{
+			name: "no attributes returns empty map",
+			svcFn: func(_ context.Context, _ string) (map[string]string, int64, error) {
+				return nil, 0, nil
+			},
+			assertFn: func(t *testing.T, resp GetPrincipalAttributesResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(GetPrincipalAttributes200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.NotNil(t, ok200.Body.Attributes)
+				assert.Empty(t, ok200.Body.Attributes)
 			},
 		},
 		{
 			name: "access denied returns 403",
-			svcFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.GroupMember, int64, error) {
-				return nil, 0, domain.ErrAccessDenied("not allowed")
+			svcFn: func(_ context.Context, _ string) (map[string]string, int64, error) {
+				return nil, 0, domain.ErrAccessDenied("admin required")
 			},
-			assertFn: func(t *testing.T, resp ListGroupMembersResponseObject, err error) {
+			assertFn: func(t *testing.T, resp GetPrincipalAttributesResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				forbidden, ok := resp.(ListGroupMembers403JSONResponse)
+				_, ok := resp.(GetPrincipalAttributes403JSONResponse)
 				require.True(t, ok, "expected 403 response, got %T", resp)
-				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+		{
+			name: "not found returns 404",
+			svcFn: func(_ context.Context, id string) (map[string]string, int64, error) {
+				return nil, 0, domain.ErrNotFound("principal %s not found", id)
+			},
+			assertFn: func(t *testing.T, resp GetPrincipalAttributesResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(GetPrincipalAttributes404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
 			},
 		},
 	}
@@ -406,48 +603,112 @@ func TestHandler_ListGroupMembers(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			svc := &mockGroupService{listMembersFn: tt.svcFn}
-			handler := &APIHandler{groups: svc}
-			resp, err := handler.ListGroupMembers(secTestCtx(), ListGroupMembersRequestObject{
-				GroupId: "g-1",
-				Params:  ListGroupMembersParams{},
-			})
+			svc := &mockPrincipalService{getAttrsFn: tt.svcFn}
+			handler := &APIHandler{principals: svc}
+			resp, err := handler.GetPrincipalAttributes(secTestCtx(), GetPrincipalAttributesRequestObject{PrincipalId: "p-1"})
 			tt.assertFn(t, resp, err)
 		})
 	}
 }
 
-func TestHandler_CreateGroupMember(t *testing.T) {
+func TestHandler_SetPrincipalAttributes(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name     string
-		svcFn    func(ctx context.Context, req domain.AddGroupMemberRequest) error
-		assertFn func(t *testing.T, resp CreateGroupMemberResponseObject, err error)
+		ifMatch  string
+		svcFn    func(ctx context.Context, id string, attrs map[string]string, expectedRowVersion *int64) (map[string]string, int64, error)
+		assertFn func(t *testing.T, resp SetPrincipalAttributesResponseObject, err error)
 	}{
 		{
-			name: "happy path returns 204",
-			svcFn: func(_ context.Context, _ domain.AddGroupMemberRequest) error {
-				return nil
+			name: "happy path returns 200",
+			svcFn: func(_ context.Context, _ string, attrs map[string]string, _ *int64) (map[string]string, int64, error) {
+				return attrs, 1, nil
 			},
-			assertFn: func(t *testing.T, resp CreateGroupMemberResponseObject, err error) {
+			assertFn: func(t *testing.T, resp SetPrincipalAttributesResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				_, ok := resp.(CreateGroupMember204Response)
-				require.True(t, ok, "expected 204 response, got %T", resp)
+				ok200, ok := resp.(SetPrincipalAttributes200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, "p-1", ok200.Body.PrincipalId)
+				assert.Equal(t, map[string]string{"region": "EU"}, ok200.Body.Attributes)
+				assert.Equal(t, `"1"`, ok200.Headers.ETag)
+			},
+		},
+		{
+			name:    "if-match passes the expected version",
+			ifMatch: `"4"`,
+			svcFn: func(_ context.Context, _ string, attrs map[string]string, expectedRowVersion *int64) (map[string]string, int64, error) {
+				if expectedRowVersion == nil || *expectedRowVersion != 4 {
+					return nil, 0, domain.ErrPreconditionFailed("unexpected version %v", expectedRowVersion)
+				}
+				return attrs, 5, nil
+			},
+			assertFn: func(t *testing.T, resp SetPrincipalAttributesResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(SetPrincipalAttributes200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, `"5"`, ok200.Headers.ETag)
+			},
+		},
+		{
+			name:    "stale version returns 412",
+			ifMatch: `"4"`,
+			svcFn: func(_ context.Context, id string, _ map[string]string, _ *int64) (map[string]string, int64, error) {
+				return nil, 0, domain.ErrPreconditionFailed("attributes of principal %q have changed", id)
+			},
+			assertFn: func(t *testing.T, resp SetPrincipalAttributesResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SetPrincipalAttributes412JSONResponse)
+				require.True(t, ok, "expected 412 response, got %T", resp)
+			},
+		},
+		{
+			name:    "foreign etag returns 412 without calling the service",
+			ifMatch: `W/"4"`,
+			assertFn: func(t *testing.T, resp SetPrincipalAttributesResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SetPrincipalAttributes412JSONResponse)
+				require.True(t, ok, "expected 412 response, got %T", resp)
+			},
+		},
+		{
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _ string, _ map[string]string, _ *int64) (map[string]string, int64, error) {
+				return nil, 0, domain.ErrValidation("invalid attribute key")
+			},
+			assertFn: func(t *testing.T, resp SetPrincipalAttributesResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SetPrincipalAttributes400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
 			},
 		},
 		{
 			name: "access denied returns 403",
-			svcFn: func(_ context.Context, _ domain.AddGroupMemberRequest) error {
-				return domain.ErrAccessDenied("not allowed")
+			svcFn: func(_ context.Context, _ string, _ map[string]string, _ *int64) (map[string]string, int64, error) {
+				return nil, 0, domain.ErrAccessDenied("admin required")
 			},
-			assertFn: func(t *testing.T, resp CreateGroupMemberResponseObject, err error) {
+			assertFn: func(t *testing.T, resp SetPrincipalAttributesResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				forbidden, ok := resp.(CreateGroupMember403JSONResponse)
+				_, ok := resp.(SetPrincipalAttributes403JSONResponse)
 				require.True(t, ok, "expected 403 response, got %T", resp)
-				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+		{
+			name: "not found returns 404",
+			svcFn: func(_ context.Context, id string, _ map[string]string, _ *int64) (map[string]string, int64, error) {
+				return nil, 0, domain.ErrNotFound("principal %s not found", id)
+			},
+			assertFn: func(t *testing.T, resp SetPrincipalAttributesResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SetPrincipalAttributes404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
 			},
 		},
 	}
@@ -455,50 +716,704 @@ func TestHandler_CreateGroupMember(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			svc := &mockGroupService{addMemberFn: tt.svcFn}
-			handler := &APIHandler{groups: svc}
-			body := CreateGroupMemberJSONRequestBody{
-				MemberId:   "p-1",
-				MemberType: "user",
+			svc := &mockPrincipalService{setAttrsFn: tt.svcFn}
+			handler := &APIHandler{principals: svc}
+			body := SetPrincipalAttributesJSONRequestBody{Attributes: map[string]string{"region": "EU"}}
+			req := SetPrincipalAttributesRequestObject{PrincipalId: "p-1", Body: &body}
+			if tt.ifMatch != "" {
+				req.Params.IfMatch = &tt.ifMatch
 			}
-			resp, err := handler.CreateGroupMember(secTestCtx(), CreateGroupMemberRequestObject{
-				GroupId: "g-1",
-				Body:    &body,
-			})
+			resp, err := handler.SetPrincipalAttributes(secTestCtx(), req)
 			tt.assertFn(t, resp, err)
 		})
 	}
 }
 
-func TestHandler_DeleteGroupMember(t *testing.T) {
+func TestHandler_SetPrincipalQueryDefaults(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name     string
-		svcFn    func(ctx context.Context, req domain.RemoveGroupMemberRequest) error
-		assertFn func(t *testing.T, resp DeleteGroupMemberResponseObject, err error)
+		svcFn    func(ctx context.Context, id string, defaults domain.PrincipalQueryDefaults, expectedRowVersion *int64) (domain.PrincipalQueryDefaults, error)
+		assertFn func(t *testing.T, resp SetPrincipalQueryDefaultsResponseObject, err error)
 	}{
 		{
-			name: "happy path returns 204",
-			svcFn: func(_ context.Context, _ domain.RemoveGroupMemberRequest) error {
-				return nil
+			name: "happy path returns 200",
+			svcFn: func(_ context.Context, _ string, defaults domain.PrincipalQueryDefaults, _ *int64) (domain.PrincipalQueryDefaults, error) {
+				defaults.RowVersion = 2
+				return defaults, nil
 			},
-			assertFn: func(t *testing.T, resp DeleteGroupMemberResponseObject, err error) {
+			assertFn: func(t *testing.T, resp SetPrincipalQueryDefaultsResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				_, ok := resp.(DeleteGroupMember204Response)
-				require.True(t, ok, "expected 204 response, got %T", resp)
+				ok200, ok := resp.(SetPrincipalQueryDefaults200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, "p-1", ok200.Body.PrincipalId)
+				assert.Equal(t, "demo", ok200.Body.DefaultCatalog)
+				assert.Equal(t, "analytics", ok200.Body.DefaultSchema)
+				assert.Empty(t, ok200.Body.Timezone)
+				assert.Equal(t, `"2"`, ok200.Headers.ETag)
+			},
+		},
+		{
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _ string, _ domain.PrincipalQueryDefaults, _ *int64) (domain.PrincipalQueryDefaults, error) {
+				return domain.PrincipalQueryDefaults{}, domain.ErrValidation("unknown time zone")
+			},
+			assertFn: func(t *testing.T, resp SetPrincipalQueryDefaultsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SetPrincipalQueryDefaults400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ string, _ domain.PrincipalQueryDefaults, _ *int64) (domain.PrincipalQueryDefaults, error) {
+				return domain.PrincipalQueryDefaults{}, domain.ErrAccessDenied("admin required")
+			},
+			assertFn: func(t *testing.T, resp SetPrincipalQueryDefaultsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SetPrincipalQueryDefaults403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+			},
+		},
+		{
+			name: "not found returns 404",
+			svcFn: func(_ context.Context, id string, _ domain.PrincipalQueryDefaults, _ *int64) (domain.PrincipalQueryDefaults, error) {
+				return domain.PrincipalQueryDefaults{}, domain.ErrNotFound("principal %s not found", id)
+			},
+			assertFn: func(t *testing.T, resp SetPrincipalQueryDefaultsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SetPrincipalQueryDefaults404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockPrincipalService{setQDefsFn: tt.svcFn}
+			handler := &APIHandler{principals: svc}
+			catalog, schema := "demo", "analytics"
+			body := SetPrincipalQueryDefaultsJSONRequestBody{DefaultCatalog: &catalog, DefaultSchema: &schema}
+			resp, err := handler.SetPrincipalQueryDefaults(secTestCtx(), SetPrincipalQueryDefaultsRequestObject{PrincipalId: "p-1", Body: &body})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_GetGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, id string) (*domain.Group, error)
+		assertFn func(t *testing.T, resp GetGroupResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 200",
+			svcFn: func(_ context.Context, _ string) (*domain.Group, error) {
+				g := secSampleGroup()
+				return &g, nil
+			},
+			assertFn: func(t *testing.T, resp GetGroupResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(GetGroup200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, "g-1", *ok200.Body.Id)
+				assert.Equal(t, "engineers", *ok200.Body.Name)
+			},
+		},
+		{
+			name: "not found returns 404",
+			svcFn: func(_ context.Context, id string) (*domain.Group, error) {
+				return nil, domain.ErrNotFound("group %s not found", id)
+			},
+			assertFn: func(t *testing.T, resp GetGroupResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				notFound, ok := resp.(GetGroup404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+				assert.Equal(t, int32(404), notFound.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockGroupService{getByIDFn: tt.svcFn}
+			handler := &APIHandler{groups: svc}
+			resp, err := handler.GetGroup(secTestCtx(), GetGroupRequestObject{GroupId: "g-1"})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_ListGroupMembers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, groupID string, page domain.PageRequest) ([]domain.GroupMember, int64, error)
+		assertFn func(t *testing.T, resp ListGroupMembersResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 200 with members",
+			svcFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.GroupMember, int64, error) {
+				return []domain.GroupMember{secSampleGroupMember()}, 1, nil
+			},
+			assertFn: func(t *testing.T, resp ListGroupMembersResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ListGroupMembers200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.NotNil(t, ok200.Body.Data)
+				require.Len(t, *ok200.Body.Data, 1)
+				assert.Equal(t, "p-1", *(*ok200.Body.Data)[0].MemberId)
+			},
+		},
+		{
+			name: "mixed member types carry names and kinds",
+			svcFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.GroupMember, int64, error) {
+				return []domain.GroupMember{
+					{GroupID: "g-1", MemberType: "user", MemberID: "u-1", MemberName: "alice", MemberKind: "user"},
+					{GroupID: "g-1", MemberType: "user", MemberID: "sp-1", MemberName: "etl-bot", MemberKind: "service_principal"},
+					{GroupID: "g-1", MemberType: "group", MemberID: "g-2", MemberName: "data-science", MemberKind: "group"},
+					{GroupID: "g-1", MemberType: "user", MemberID: "gone"},
+				}, 4, nil
+			},
+			assertFn: func(t *testing.T, resp ListGroupMembersResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ListGroupMembers200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				data := *ok200.Body.Data
+				require.Len(t, data, 4)
+				assert.Equal(t, "alice", *data[0].MemberName)
+				assert.Equal(t, GroupMemberMemberKind("user"), *data[0].MemberKind)
+				assert.Equal(t, "etl-bot", *data[1].MemberName)
+				assert.Equal(t, GroupMemberMemberKind("service_principal"), *data[1].MemberKind)
+				assert.Equal(t, "data-science", *data[2].MemberName)
+				assert.Equal(t, GroupMemberMemberKind("group"), *data[2].MemberKind)
+				assert.Nil(t, data[3].MemberName, "unresolved members omit the name")
+				assert.Nil(t, data[3].MemberKind)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.GroupMember, int64, error) {
+				return nil, 0, domain.ErrAccessDenied("not allowed")
+			},
+			assertFn: func(t *testing.T, resp ListGroupMembersResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(ListGroupMembers403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockGroupService{listMembersFn: tt.svcFn}
+			handler := &APIHandler{groups: svc}
+			resp, err := handler.ListGroupMembers(secTestCtx(), ListGroupMembersRequestObject{
+				GroupId: "g-1",
+				Params:  ListGroupMembersParams{},
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_CreateGroupMember(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, req domain.AddGroupMemberRequest) error
+		assertFn func(t *testing.T, resp CreateGroupMemberResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 204",
+			svcFn: func(_ context.Context, _ domain.AddGroupMemberRequest) error {
+				return nil
+			},
+			assertFn: func(t *testing.T, resp CreateGroupMemberResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateGroupMember204Response)
+				require.True(t, ok, "expected 204 response, got %T", resp)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ domain.AddGroupMemberRequest) error {
+				return domain.ErrAccessDenied("not allowed")
+			},
+			assertFn: func(t *testing.T, resp CreateGroupMemberResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(CreateGroupMember403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockGroupService{addMemberFn: tt.svcFn}
+			handler := &APIHandler{groups: svc}
+			body := CreateGroupMemberJSONRequestBody{
+				MemberId:   "p-1",
+				MemberType: "user",
+			}
+			resp, err := handler.CreateGroupMember(secTestCtx(), CreateGroupMemberRequestObject{
+				GroupId: "g-1",
+				Body:    &body,
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_DeleteGroupMember(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, req domain.RemoveGroupMemberRequest) error
+		assertFn func(t *testing.T, resp DeleteGroupMemberResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 204",
+			svcFn: func(_ context.Context, _ domain.RemoveGroupMemberRequest) error {
+				return nil
+			},
+			assertFn: func(t *testing.T, resp DeleteGroupMemberResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(DeleteGroupMember204Response)
+				require.True(t, ok, "expected 204 response, got %T", resp)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ domain.RemoveGroupMemberRequest) error {
+				return domain.ErrAccessDenied("not allowed")
+			},
+			assertFn: func(t *testing.T, resp DeleteGroupMemberResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(DeleteGroupMember403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockGroupService{removeMemberFn: tt.svcFn}
+			handler := &APIHandler{groups: svc}
+			resp, err := handler.DeleteGroupMember(secTestCtx(), DeleteGroupMemberRequestObject{
+				GroupId: "g-1",
+				Params: DeleteGroupMemberParams{
+					MemberId:   "p-1",
+					MemberType: "user",
+				},
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_SyncGroupMembers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, req domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error)
+		assertFn func(t *testing.T, resp SyncGroupMembersResponseObject, err error)
+	}{
+		{
+			name: "happy path returns net changes",
+			svcFn: func(_ context.Context, req domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error) {
+				assert.Equal(t, "g-1", req.GroupID)
+				require.Len(t, req.Members, 2)
+				assert.Equal(t, domain.GroupMember{GroupID: "g-1", MemberType: "group", MemberID: "g-2"}, req.Members[1])
+				return &domain.GroupMembershipChanges{
+					Added:   []domain.GroupMember{{GroupID: "g-1", MemberType: "group", MemberID: "g-2"}},
+					Removed: []domain.GroupMember{{GroupID: "g-1", MemberType: "user", MemberID: "p-9"}},
+				}, nil
+			},
+			assertFn: func(t *testing.T, resp SyncGroupMembersResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(SyncGroupMembers200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.Len(t, ok200.Body.Added, 1)
+				assert.Equal(t, "g-2", *ok200.Body.Added[0].MemberId)
+				require.Len(t, ok200.Body.Removed, 1)
+				assert.Equal(t, "p-9", *ok200.Body.Removed[0].MemberId)
+			},
+		},
+		{
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _ domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error) {
+				return nil, domain.ErrValidation("duplicate member")
+			},
+			assertFn: func(t *testing.T, resp SyncGroupMembersResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SyncGroupMembers400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+			},
+		},
+		{
+			name: "unknown group returns 404",
+			svcFn: func(_ context.Context, _ domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error) {
+				return nil, domain.ErrNotFound("group not found")
+			},
+			assertFn: func(t *testing.T, resp SyncGroupMembersResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(SyncGroupMembers404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error) {
+				return nil, domain.ErrAccessDenied("not allowed")
+			},
+			assertFn: func(t *testing.T, resp SyncGroupMembersResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(SyncGroupMembers403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockGroupService{syncMembersFn: tt.svcFn}
+			handler := &APIHandler{groups: svc}
+			body := SyncGroupMembersJSONRequestBody{
+				Members: []CreateGroupMemberRequest{
+					{MemberId: "p-1", MemberType: "user"},
+					{MemberId: "g-2", MemberType: "group"},
+				},
+			}
+			resp, err := handler.SyncGroupMembers(secTestCtx(), SyncGroupMembersRequestObject{
+				GroupId: "g-1",
+				Body:    &body,
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_ListGrants(t *testing.T) {
+	t.Parallel()
+
+	principalID := "p-1"
+	pt := ListGrantsParamsPrincipalType("user")
+	securableType := "table"
+	securableID := "t-1"
+	privilege := PrivilegeName("SELECT")
+
+	tests := []struct {
+		name     string
+		params   ListGrantsParams
+		listFn   func(ctx context.Context, filter domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error)
+		assertFn func(t *testing.T, resp ListGrantsResponseObject, err error)
+	}{
+		{
+			name:   "with principal filter returns 200",
+			params: ListGrantsParams{PrincipalId: &principalID, PrincipalType: &pt},
+			listFn: func(_ context.Context, f domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error) {
+				if f.PrincipalID == nil || *f.PrincipalID != principalID || f.PrincipalType == nil || *f.PrincipalType != "user" {
+					return nil, 0, fmt.Errorf("unexpected filter %+v", f)
+				}
+				return []domain.PrivilegeGrant{secSampleGrant()}, 1, nil
+			},
+			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ListGrants200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.NotNil(t, ok200.Body.Data)
+				require.Len(t, *ok200.Body.Data, 1)
+				assert.Equal(t, "grant-1", *(*ok200.Body.Data)[0].Id)
+			},
+		},
+		{
+			name:   "with securable filter returns 200",
+			params: ListGrantsParams{SecurableType: &securableType, SecurableId: &securableID},
+			listFn: func(_ context.Context, f domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error) {
+				if f.SecurableType == nil || *f.SecurableType != securableType || f.SecurableID == nil || *f.SecurableID != securableID {
+					return nil, 0, fmt.Errorf("unexpected filter %+v", f)
+				}
+				return []domain.PrivilegeGrant{secSampleGrant()}, 1, nil
+			},
+			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ListGrants200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.NotNil(t, ok200.Body.Data)
+				require.Len(t, *ok200.Body.Data, 1)
+			},
+		},
+		{
+			name:   "partial filters are passed through",
+			params: ListGrantsParams{SecurableType: &securableType, Privilege: &privilege},
+			listFn: func(_ context.Context, f domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error) {
+				if f.SecurableID != nil || f.PrincipalID != nil || f.Privilege == nil || *f.Privilege != "SELECT" {
+					return nil, 0, fmt.Errorf("unexpected filter %+v", f)
+				}
+				return nil, 0, nil
+			},
+			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ListGrants200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.NotNil(t, ok200.Body.Data)
+				assert.Empty(t, *ok200.Body.Data)
+			},
+		},
+		{
+			name:   "missing params returns 200",
+			params: ListGrantsParams{},
+			listFn: func(_ context.Context, _ domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error) {
+				return []domain.PrivilegeGrant{secSampleGrant()}, 1, nil
+			},
+			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(ListGrants200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.NotNil(t, ok200.Body.Data)
+				require.Len(t, *ok200.Body.Data, 1)
+			},
+		},
+		{
+			name:   "access denied returns 403",
+			params: ListGrantsParams{PrincipalId: &principalID, PrincipalType: &pt},
+			listFn: func(_ context.Context, _ domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error) {
+				return nil, 0, domain.ErrAccessDenied("not allowed")
+			},
+			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(ListGrants403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockGrantService{listFn: tt.listFn}
+			handler := &APIHandler{grants: svc}
+			resp, err := handler.ListGrants(secTestCtx(), ListGrantsRequestObject{Params: tt.params})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_CreateGrant_PassesExpiry(t *testing.T) {
+	t.Parallel()
+
+	expires := secFixedTime.Add(24 * time.Hour)
+	var got domain.CreateGrantRequest
+	svc := &mockGrantService{
+		grantFn: func(_ context.Context, req domain.CreateGrantRequest) (*domain.PrivilegeGrant, error) {
+			got = req
+			g := secSampleGrant()
+			g.ExpiresAt = req.ExpiresAt
+			return &g, nil
+		},
+	}
+	handler := &APIHandler{grants: svc}
+
+	resp, err := handler.CreateGrant(secTestCtx(), CreateGrantRequestObject{Body: &CreateGrantJSONRequestBody{
+		PrincipalId:   "p-1",
+		PrincipalType: CreateGrantRequestPrincipalTypeUser,
+		SecurableType: "table",
+		SecurableId:   "t-1",
+		Privilege:     PrivilegeName("SELECT"),
+		ExpiresAt:     &expires,
+	}})
+	require.NoError(t, err)
+	created, ok := resp.(CreateGrant201JSONResponse)
+	require.True(t, ok, "expected 201 response, got %T", resp)
+	require.NotNil(t, got.ExpiresAt)
+	assert.True(t, got.ExpiresAt.Equal(expires))
+	require.NotNil(t, created.Body.ExpiresAt)
+	assert.True(t, created.Body.ExpiresAt.Equal(expires))
+}
+
+func TestHandler_DeleteGrant(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, principal string, grantID string) error
+		assertFn func(t *testing.T, resp DeleteGrantResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 204",
+			svcFn: func(_ context.Context, _ string, _ string) error {
+				return nil
+			},
+			assertFn: func(t *testing.T, resp DeleteGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(DeleteGrant204Response)
+				require.True(t, ok, "expected 204 response, got %T", resp)
+			},
+		},
+		{
+			name: "not found returns 404",
+			svcFn: func(_ context.Context, _ string, id string) error {
+				return domain.ErrNotFound("grant %s not found", id)
+			},
+			assertFn: func(t *testing.T, resp DeleteGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				notFound, ok := resp.(DeleteGrant404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+				assert.Equal(t, int32(404), notFound.Body.Code)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ string, _ string) error {
+				return domain.ErrAccessDenied("not allowed")
+			},
+			assertFn: func(t *testing.T, resp DeleteGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(DeleteGrant403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockGrantService{revokeFn: tt.svcFn}
+			handler := &APIHandler{grants: svc}
+			resp, err := handler.DeleteGrant(secTestCtx(), DeleteGrantRequestObject{GrantId: "grant-1"})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_UpdateGrant(t *testing.T) {
+	t.Parallel()
+
+	expires := secFixedTime.Add(48 * time.Hour)
+	tests := []struct {
+		name     string
+		body     UpdateGrantJSONRequestBody
+		svcFn    func(ctx context.Context, grantID string, req domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error)
+		assertFn func(t *testing.T, resp UpdateGrantResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 200 with the new expiry",
+			body: UpdateGrantJSONRequestBody{ExpiresAt: &expires},
+			svcFn: func(_ context.Context, grantID string, req domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error) {
+				g := secSampleGrant()
+				g.ID = grantID
+				g.ExpiresAt = req.ExpiresAt
+				return &g, nil
+			},
+			assertFn: func(t *testing.T, resp UpdateGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(UpdateGrant200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, "grant-1", *ok200.Body.Id)
+				require.NotNil(t, ok200.Body.ExpiresAt)
+				assert.True(t, ok200.Body.ExpiresAt.Equal(expires))
+			},
+		},
+		{
+			name: "null expiry clears it",
+			body: UpdateGrantJSONRequestBody{},
+			svcFn: func(_ context.Context, _ string, req domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error) {
+				g := secSampleGrant()
+				g.ExpiresAt = req.ExpiresAt
+				return &g, nil
+			},
+			assertFn: func(t *testing.T, resp UpdateGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(UpdateGrant200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Nil(t, ok200.Body.ExpiresAt)
+			},
+		},
+		{
+			name: "past expiry returns 400",
+			body: UpdateGrantJSONRequestBody{ExpiresAt: &expires},
+			svcFn: func(_ context.Context, _ string, _ domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error) {
+				return nil, domain.ErrValidation("expires_at must be in the future")
+			},
+			assertFn: func(t *testing.T, resp UpdateGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				badRequest, ok := resp.(UpdateGrant400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+				assert.Equal(t, int32(400), badRequest.Body.Code)
+			},
+		},
+		{
+			name: "not found returns 404",
+			body: UpdateGrantJSONRequestBody{ExpiresAt: &expires},
+			svcFn: func(_ context.Context, grantID string, _ domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error) {
+				return nil, domain.ErrNotFound("grant %s not found", grantID)
+			},
+			assertFn: func(t *testing.T, resp UpdateGrantResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				notFound, ok := resp.(UpdateGrant404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+				assert.Equal(t, int32(404), notFound.Body.Code)
 			},
 		},
 		{
 			name: "access denied returns 403",
-			svcFn: func(_ context.Context, _ domain.RemoveGroupMemberRequest) error {
-				return domain.ErrAccessDenied("not allowed")
+			body: UpdateGrantJSONRequestBody{ExpiresAt: &expires},
+			svcFn: func(_ context.Context, _ string, _ domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error) {
+				return nil, domain.ErrAccessDenied("not allowed")
 			},
-			assertFn: func(t *testing.T, resp DeleteGroupMemberResponseObject, err error) {
+			assertFn: func(t *testing.T, resp UpdateGrantResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				forbidden, ok := resp.(DeleteGroupMember403JSONResponse)
+				forbidden, ok := resp.(UpdateGrant403JSONResponse)
 				require.True(t, ok, "expected 403 response, got %T", resp)
 				assert.Equal(t, int32(403), forbidden.Body.Code)
 			},
@@ -508,94 +1423,170 @@ func TestHandler_DeleteGroupMember(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			svc := &mockGroupService{removeMemberFn: tt.svcFn}
-			handler := &APIHandler{groups: svc}
-			resp, err := handler.DeleteGroupMember(secTestCtx(), DeleteGroupMemberRequestObject{
-				GroupId: "g-1",
-				Params: DeleteGroupMemberParams{
-					MemberId:   "p-1",
-					MemberType: "user",
-				},
-			})
+			svc := &mockGrantService{updateFn: tt.svcFn}
+			handler := &APIHandler{grants: svc}
+			body := tt.body
+			resp, err := handler.UpdateGrant(secTestCtx(), UpdateGrantRequestObject{GrantId: "grant-1", Body: &body})
 			tt.assertFn(t, resp, err)
 		})
 	}
 }
 
-func TestHandler_ListGrants(t *testing.T) {
+func TestHandler_RevokeAllPrincipalGrants(t *testing.T) {
 	t.Parallel()
 
-	principalID := "p-1"
-	pt := ListGrantsParamsPrincipalType("user")
-	securableType := "table"
-	securableID := "t-1"
+	t.Run("returns what was removed", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockGrantService{revokeAllFn: func(_ context.Context, principalID, principalType string, removeMemberships bool) (*domain.RevokedAccess, error) {
+			assert.Equal(t, "p-1", principalID)
+			assert.Equal(t, "user", principalType)
+			assert.True(t, removeMemberships)
+			return &domain.RevokedAccess{
+				Grants:      []domain.PrivilegeGrant{secSampleGrant()},
+				Memberships: []domain.GroupMember{{GroupID: "g-1", MemberType: "user", MemberID: "p-1"}},
+			}, nil
+		}}
+		handler := &APIHandler{grants: svc}
+		remove := true
+		resp, err := handler.RevokeAllPrincipalGrants(secTestCtx(), RevokeAllPrincipalGrantsRequestObject{
+			PrincipalId: "p-1",
+			Body:        &RevokeAllPrincipalGrantsJSONRequestBody{RemoveMemberships: &remove},
+		})
+		require.NoError(t, err)
+		ok200, ok := resp.(RevokeAllPrincipalGrants200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Len(t, ok200.Body.RevokedGrants, 1)
+		require.Len(t, ok200.Body.RemovedMemberships, 1)
+		assert.Equal(t, "g-1", *ok200.Body.RemovedMemberships[0].GroupId)
+	})
+
+	t.Run("no body keeps memberships", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockGrantService{revokeAllFn: func(_ context.Context, _, _ string, removeMemberships bool) (*domain.RevokedAccess, error) {
+			assert.False(t, removeMemberships)
+			return &domain.RevokedAccess{}, nil
+		}}
+		handler := &APIHandler{grants: svc}
+		resp, err := handler.RevokeAllPrincipalGrants(secTestCtx(), RevokeAllPrincipalGrantsRequestObject{PrincipalId: "p-1"})
+		require.NoError(t, err)
+		ok200, ok := resp.(RevokeAllPrincipalGrants200JSONResponse)
+		require.True(t, ok, "expected 200 response, got %T", resp)
+		assert.Empty(t, ok200.Body.RevokedGrants)
+		assert.Empty(t, ok200.Body.RemovedMemberships)
+	})
+
+	t.Run("unknown principal returns 404", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockGrantService{revokeAllFn: func(_ context.Context, _, _ string, _ bool) (*domain.RevokedAccess, error) {
+			return nil, domain.ErrNotFound("principal not found")
+		}}
+		handler := &APIHandler{grants: svc}
+		resp, err := handler.RevokeAllPrincipalGrants(secTestCtx(), RevokeAllPrincipalGrantsRequestObject{PrincipalId: "missing"})
+		require.NoError(t, err)
+		_, ok := resp.(RevokeAllPrincipalGrants404JSONResponse)
+		require.True(t, ok, "expected 404 response, got %T", resp)
+	})
+
+	t.Run("non-admin returns 403", func(t *testing.T) {
+		t.Parallel()
+		svc := &mockGrantService{revokeAllFn: func(_ context.Context, _, _ string, _ bool) (*domain.RevokedAccess, error) {
+			return nil, domain.ErrAccessDenied("admin privileges required")
+		}}
+		handler := &APIHandler{grants: svc}
+		resp, err := handler.RevokeAllPrincipalGrants(secTestCtx(), RevokeAllPrincipalGrantsRequestObject{PrincipalId: "p-1"})
+		require.NoError(t, err)
+		_, ok := resp.(RevokeAllPrincipalGrants403JSONResponse)
+		require.True(t, ok, "expected 403 response, got %T", resp)
+	})
+}
+
+func TestHandler_CheckPrivilege(t *testing.T) {
+	t.Parallel()
+
+	params := CheckPrivilegeParams{Principal: "alice", Privilege: "SELECT", Securable: "table:demo.analytics.orders"}
 
 	tests := []struct {
-		name               string
-		params             ListGrantsParams
-		listForPrincipalFn func(ctx context.Context, principalID string, principalType string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
-		listForSecurableFn func(ctx context.Context, securableType string, securableID string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
-		listAllFn          func(ctx context.Context, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error)
-		assertFn           func(t *testing.T, resp ListGrantsResponseObject, err error)
+		name     string
+		svcFn    func(ctx context.Context, req domain.AccessCheckRequest) (*domain.AccessDecision, error)
+		assertFn func(t *testing.T, resp CheckPrivilegeResponseObject, err error)
 	}{
 		{
-			name:   "with principal filter returns 200",
-			params: ListGrantsParams{PrincipalId: &principalID, PrincipalType: &pt},
-			listForPrincipalFn: func(_ context.Context, _ string, _ string, _ domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
-				return []domain.PrivilegeGrant{secSampleGrant()}, 1, nil
+			name: "allowed decision includes the granting path",
+			svcFn: func(_ context.Context, req domain.AccessCheckRequest) (*domain.AccessDecision, error) {
+				assert.Equal(t, domain.AccessCheckRequest{Principal: "alice", Privilege: "SELECT", Securable: "table:demo.analytics.orders"}, req)
+				return &domain.AccessDecision{
+					Allowed: true, Reason: "SELECT granted to group analysts on table",
+					Principal: "alice", Privilege: "SELECT", SecurableType: "table", SecurableID: "t-1",
+					Via: &domain.AccessGrantPath{
+						PrincipalType: "group", PrincipalName: "analysts",
+						SecurableType: "table", SecurableID: "t-1", Privilege: "SELECT",
+					},
+				}, nil
 			},
-			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+			assertFn: func(t *testing.T, resp CheckPrivilegeResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				ok200, ok := resp.(ListGrants200JSONResponse)
+				ok200, ok := resp.(CheckPrivilege200JSONResponse)
 				require.True(t, ok, "expected 200 response, got %T", resp)
-				require.NotNil(t, ok200.Body.Data)
-				require.Len(t, *ok200.Body.Data, 1)
-				assert.Equal(t, "grant-1", *(*ok200.Body.Data)[0].Id)
+				assert.True(t, ok200.Body.Allowed)
+				assert.Equal(t, "t-1", ok200.Body.SecurableId)
+				require.NotNil(t, ok200.Body.Via)
+				assert.Equal(t, AccessGrantPathPrincipalType("group"), ok200.Body.Via.PrincipalType)
+				assert.Equal(t, "analysts", ok200.Body.Via.PrincipalName)
 			},
 		},
 		{
-			name:   "with securable filter returns 200",
-			params: ListGrantsParams{SecurableType: &securableType, SecurableId: &securableID},
-			listForSecurableFn: func(_ context.Context, _ string, _ string, _ domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
-				return []domain.PrivilegeGrant{secSampleGrant()}, 1, nil
+			name: "denied decision has no granting path",
+			svcFn: func(_ context.Context, _ domain.AccessCheckRequest) (*domain.AccessDecision, error) {
+				return &domain.AccessDecision{
+					Allowed: false, Reason: "missing USE_SCHEMA on the parent schema",
+					Principal: "alice", Privilege: "SELECT", SecurableType: "table", SecurableID: "t-1",
+				}, nil
 			},
-			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+			assertFn: func(t *testing.T, resp CheckPrivilegeResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				ok200, ok := resp.(ListGrants200JSONResponse)
+				ok200, ok := resp.(CheckPrivilege200JSONResponse)
 				require.True(t, ok, "expected 200 response, got %T", resp)
-				require.NotNil(t, ok200.Body.Data)
-				require.Len(t, *ok200.Body.Data, 1)
+				assert.False(t, ok200.Body.Allowed)
+				assert.Equal(t, "missing USE_SCHEMA on the parent schema", ok200.Body.Reason)
+				assert.Nil(t, ok200.Body.Via)
 			},
 		},
 		{
-			name:   "missing params returns 200",
-			params: ListGrantsParams{},
-			listAllFn: func(_ context.Context, _ domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
-				return []domain.PrivilegeGrant{secSampleGrant()}, 1, nil
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _ domain.AccessCheckRequest) (*domain.AccessDecision, error) {
+				return nil, domain.ErrValidation("unknown securable type %q", "warehouse")
 			},
-			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+			assertFn: func(t *testing.T, resp CheckPrivilegeResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				ok200, ok := resp.(ListGrants200JSONResponse)
-				require.True(t, ok, "expected 200 response, got %T", resp)
-				require.NotNil(t, ok200.Body.Data)
-				require.Len(t, *ok200.Body.Data, 1)
+				_, ok := resp.(CheckPrivilege400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
 			},
 		},
 		{
-			name:   "access denied returns 403",
-			params: ListGrantsParams{PrincipalId: &principalID, PrincipalType: &pt},
-			listForPrincipalFn: func(_ context.Context, _ string, _ string, _ domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
-				return nil, 0, domain.ErrAccessDenied("not allowed")
+			name: "checking another principal without admin returns 403",
+			svcFn: func(_ context.Context, _ domain.AccessCheckRequest) (*domain.AccessDecision, error) {
+				return nil, domain.ErrAccessDenied("admin privileges required")
 			},
-			assertFn: func(t *testing.T, resp ListGrantsResponseObject, err error) {
+			assertFn: func(t *testing.T, resp CheckPrivilegeResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				forbidden, ok := resp.(ListGrants403JSONResponse)
+				_, ok := resp.(CheckPrivilege403JSONResponse)
 				require.True(t, ok, "expected 403 response, got %T", resp)
-				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+		{
+			name: "unknown securable returns 404",
+			svcFn: func(_ context.Context, _ domain.AccessCheckRequest) (*domain.AccessDecision, error) {
+				return nil, domain.ErrNotFound("table %q not found", "demo.analytics.orders")
+			},
+			assertFn: func(t *testing.T, resp CheckPrivilegeResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CheckPrivilege404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
 			},
 		},
 	}
@@ -603,62 +1594,83 @@ func TestHandler_ListGrants(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			svc := &mockGrantService{
-				listForPrincipalFn: tt.listForPrincipalFn,
-				listForSecurableFn: tt.listForSecurableFn,
-				listAllFn:          tt.listAllFn,
-			}
-			handler := &APIHandler{grants: svc}
-			resp, err := handler.ListGrants(secTestCtx(), ListGrantsRequestObject{Params: tt.params})
+			handler := &APIHandler{authz: &mockAuthzCheckService{explainFn: tt.svcFn}}
+			resp, err := handler.CheckPrivilege(secTestCtx(), CheckPrivilegeRequestObject{Params: params})
 			tt.assertFn(t, resp, err)
 		})
 	}
 }
 
-func TestHandler_DeleteGrant(t *testing.T) {
+func TestHandler_GetSecurableAccess(t *testing.T) {
 	t.Parallel()
 
+	req := GetSecurableAccessRequestObject{SecurableType: "table", SecurableId: "demo.analytics.orders"}
+
 	tests := []struct {
 		name     string
-		svcFn    func(ctx context.Context, principal string, grantID string) error
-		assertFn func(t *testing.T, resp DeleteGrantResponseObject, err error)
+		svcFn    func(ctx context.Context, securableType, ref string) (*domain.SecurableAccess, error)
+		assertFn func(t *testing.T, resp GetSecurableAccessResponseObject, err error)
 	}{
 		{
-			name: "happy path returns 204",
-			svcFn: func(_ context.Context, _ string, _ string) error {
-				return nil
+			name: "lists entries and per-principal policies",
+			svcFn: func(_ context.Context, securableType, ref string) (*domain.SecurableAccess, error) {
+				assert.Equal(t, "table", securableType)
+				assert.Equal(t, "demo.analytics.orders", ref)
+				return &domain.SecurableAccess{
+					SecurableType: "table", SecurableID: "t-1",
+					Entries: []domain.AccessEntry{
+						{PrincipalType: "user", PrincipalName: "alice", Privilege: "SELECT",
+							Source: domain.AccessSourceGroup, ViaGroup: "analysts", GrantedOnType: "table", GrantedOnID: "t-1"},
+						{PrincipalType: "user", PrincipalName: "root", Privilege: "ALL_PRIVILEGES", Source: domain.AccessSourceAdmin},
+					},
+					Policies: []domain.PrincipalPolicies{
+						{PrincipalName: "alice", ColumnMasks: map[string]string{"ssn": "'***'", "email": "'***'"}},
+					},
+				}, nil
 			},
-			assertFn: func(t *testing.T, resp DeleteGrantResponseObject, err error) {
+			assertFn: func(t *testing.T, resp GetSecurableAccessResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				_, ok := resp.(DeleteGrant204Response)
-				require.True(t, ok, "expected 204 response, got %T", resp)
+				ok200, ok := resp.(GetSecurableAccess200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.Equal(t, "t-1", ok200.Body.SecurableId)
+				require.Len(t, ok200.Body.Entries, 2)
+				assert.Equal(t, AccessEntrySource("GROUP"), ok200.Body.Entries[0].Source)
+				require.NotNil(t, ok200.Body.Entries[0].ViaGroup)
+				assert.Equal(t, "analysts", *ok200.Body.Entries[0].ViaGroup)
+				assert.Nil(t, ok200.Body.Entries[1].GrantedOnType)
+				require.NotNil(t, ok200.Body.Policies)
+				policies := *ok200.Body.Policies
+				require.Len(t, policies, 1)
+				assert.Empty(t, policies[0].RowFilters)
+				assert.Equal(t, []ColumnMaskPolicy{
+					{ColumnName: "email", MaskExpression: "'***'"},
+					{ColumnName: "ssn", MaskExpression: "'***'"},
+				}, policies[0].ColumnMasks)
 			},
 		},
 		{
-			name: "not found returns 404",
-			svcFn: func(_ context.Context, _ string, id string) error {
-				return domain.ErrNotFound("grant %s not found", id)
+			name: "non-admin caller returns 403",
+			svcFn: func(_ context.Context, _, _ string) (*domain.SecurableAccess, error) {
+				return nil, domain.ErrAccessDenied("admin privileges required")
 			},
-			assertFn: func(t *testing.T, resp DeleteGrantResponseObject, err error) {
+			assertFn: func(t *testing.T, resp GetSecurableAccessResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				notFound, ok := resp.(DeleteGrant404JSONResponse)
-				require.True(t, ok, "expected 404 response, got %T", resp)
-				assert.Equal(t, int32(404), notFound.Body.Code)
+				_, ok := resp.(GetSecurableAccess403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
 			},
 		},
 		{
-			name: "access denied returns 403",
-			svcFn: func(_ context.Context, _ string, _ string) error {
-				return domain.ErrAccessDenied("not allowed")
+			name: "unknown securable returns 404",
+			svcFn: func(_ context.Context, _, _ string) (*domain.SecurableAccess, error) {
+				return nil, domain.ErrNotFound("table %q not found", "demo.analytics.orders")
 			},
-			assertFn: func(t *testing.T, resp DeleteGrantResponseObject, err error) {
+			assertFn: func(t *testing.T, resp GetSecurableAccessResponseObject, err error) {
 				t.Helper()
 				require.NoError(t, err)
-				forbidden, ok := resp.(DeleteGrant403JSONResponse)
-				require.True(t, ok, "expected 403 response, got %T", resp)
-				assert.Equal(t, int32(403), forbidden.Body.Code)
+				_, ok := resp.(GetSecurableAccess404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
 			},
 		},
 	}
@@ -666,9 +1678,8 @@ func TestHandler_DeleteGrant(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			svc := &mockGrantService{revokeFn: tt.svcFn}
-			handler := &APIHandler{grants: svc}
-			resp, err := handler.DeleteGrant(secTestCtx(), DeleteGrantRequestObject{GrantId: "grant-1"})
+			handler := &APIHandler{authz: &mockAuthzCheckService{effectiveAccessFn: tt.svcFn}}
+			resp, err := handler.GetSecurableAccess(secTestCtx(), req)
 			tt.assertFn(t, resp, err)
 		})
 	}
@@ -793,6 +1804,102 @@ func TestHandler_CreateColumnMask(t *testing.T) {
 	}
 }
 
+func TestHandler_ListTagColumnMasks(t *testing.T) {
+	t.Parallel()
+
+	tagID := "tag-1"
+	var gotTag string
+	svc := &mockColumnMaskService{getForTagFn: func(_ context.Context, id string, _ domain.PageRequest) ([]domain.ColumnMask, int64, error) {
+		gotTag = id
+		return []domain.ColumnMask{{ID: "m-2", TagID: &tagID, MaskExpression: "'REDACTED'", CreatedAt: secFixedTime}}, 1, nil
+	}}
+	handler := &APIHandler{columnMasks: svc}
+	resp, err := handler.ListTagColumnMasks(secTestCtx(), ListTagColumnMasksRequestObject{
+		TagId:  tagID,
+		Params: ListTagColumnMasksParams{},
+	})
+	require.NoError(t, err)
+	ok200, ok := resp.(ListTagColumnMasks200JSONResponse)
+	require.True(t, ok, "expected 200 response, got %T", resp)
+	assert.Equal(t, tagID, gotTag)
+	require.NotNil(t, ok200.Body.Data)
+	require.Len(t, *ok200.Body.Data, 1)
+	assert.Equal(t, tagID, *(*ok200.Body.Data)[0].TagId)
+	assert.Nil(t, (*ok200.Body.Data)[0].ColumnName)
+}
+
+func TestHandler_CreateTagColumnMask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcErr   error
+		assertFn func(t *testing.T, resp CreateTagColumnMaskResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 201",
+			assertFn: func(t *testing.T, resp CreateTagColumnMaskResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				created, ok := resp.(CreateTagColumnMask201JSONResponse)
+				require.True(t, ok, "expected 201 response, got %T", resp)
+				require.NotNil(t, created.Body.TagId)
+				assert.Equal(t, "tag-1", *created.Body.TagId)
+			},
+		},
+		{
+			name:   "unknown tag returns 404",
+			svcErr: domain.ErrNotFound("tag tag-1 not found"),
+			assertFn: func(t *testing.T, resp CreateTagColumnMaskResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateTagColumnMask404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+			},
+		},
+		{
+			name:   "second mask for a tag returns 409",
+			svcErr: domain.ErrConflict("tag tag-1 already has a column mask"),
+			assertFn: func(t *testing.T, resp CreateTagColumnMaskResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateTagColumnMask409JSONResponse)
+				require.True(t, ok, "expected 409 response, got %T", resp)
+			},
+		},
+		{
+			name:   "access denied returns 403",
+			svcErr: domain.ErrAccessDenied("not allowed"),
+			assertFn: func(t *testing.T, resp CreateTagColumnMaskResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateTagColumnMask403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockColumnMaskService{createFn: func(_ context.Context, req domain.CreateColumnMaskRequest) (*domain.ColumnMask, error) {
+				if tt.svcErr != nil {
+					return nil, tt.svcErr
+				}
+				assert.Empty(t, req.TableID)
+				assert.Empty(t, req.ColumnName)
+				return &domain.ColumnMask{ID: "m-2", TagID: &req.TagID, MaskExpression: req.MaskExpression}, nil
+			}}
+			handler := &APIHandler{columnMasks: svc}
+			resp, err := handler.CreateTagColumnMask(secTestCtx(), CreateTagColumnMaskRequestObject{
+				TagId: "tag-1",
+				Body:  &CreateTagColumnMaskJSONRequestBody{MaskExpression: "'REDACTED'"},
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
 func TestHandler_DeleteColumnMask(t *testing.T) {
 	t.Parallel()
 
@@ -969,3 +2076,191 @@ func TestHandler_UnbindColumnMask(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_PreviewColumnMask(t *testing.T) {
+	t.Parallel()
+
+	limit := int32(5)
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, maskID, principalName string, limit int) (*domain.ColumnMaskPreview, error)
+		assertFn func(t *testing.T, resp PreviewColumnMaskResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 200 with both values",
+			svcFn: func(_ context.Context, maskID, principalName string, limit int) (*domain.ColumnMaskPreview, error) {
+				assert.Equal(t, "m-1", maskID)
+				assert.Equal(t, "alice", principalName)
+				assert.Equal(t, 5, limit)
+				return &domain.ColumnMaskPreview{
+					ColumnMaskID:   maskID,
+					TableName:      "sales.customers",
+					ColumnName:     "email",
+					Principal:      principalName,
+					Masked:         true,
+					MaskExpression: "'***'",
+					Rows:           []domain.ColumnMaskPreviewRow{{Original: "alice@example.com", Masked: "***"}},
+				}, nil
+			},
+			assertFn: func(t *testing.T, resp PreviewColumnMaskResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(PreviewColumnMask200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.True(t, ok200.Body.Masked)
+				require.NotNil(t, ok200.Body.MaskExpression)
+				assert.Equal(t, "'***'", *ok200.Body.MaskExpression)
+				require.Len(t, ok200.Body.Rows, 1)
+				assert.Equal(t, "alice@example.com", ok200.Body.Rows[0].Original)
+				assert.Equal(t, "***", ok200.Body.Rows[0].Masked)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _, _ string, _ int) (*domain.ColumnMaskPreview, error) {
+				return nil, domain.ErrAccessDenied("admin privileges required")
+			},
+			assertFn: func(t *testing.T, resp PreviewColumnMaskResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(PreviewColumnMask403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+		{
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _, _ string, _ int) (*domain.ColumnMaskPreview, error) {
+				return nil, domain.ErrValidation("principal lacks SELECT")
+			},
+			assertFn: func(t *testing.T, resp PreviewColumnMaskResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				badReq, ok := resp.(PreviewColumnMask400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+				assert.Equal(t, int32(400), badReq.Body.Code)
+			},
+		},
+		{
+			name: "not found returns 404",
+			svcFn: func(_ context.Context, _, _ string, _ int) (*domain.ColumnMaskPreview, error) {
+				return nil, domain.ErrNotFound("column mask not found")
+			},
+			assertFn: func(t *testing.T, resp PreviewColumnMaskResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				notFound, ok := resp.(PreviewColumnMask404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+				assert.Equal(t, int32(404), notFound.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockColumnMaskService{previewFn: tt.svcFn}
+			handler := &APIHandler{columnMasks: svc}
+			resp, err := handler.PreviewColumnMask(secTestCtx(), PreviewColumnMaskRequestObject{
+				ColumnMaskId: "m-1",
+				Params:       PreviewColumnMaskParams{Principal: "alice", Limit: &limit},
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_SimulatePolicy(t *testing.T) {
+	t.Parallel()
+
+	filter := "region = 'EU'"
+	limit := int32(5)
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, req domain.PolicySimulationRequest) (*domain.PolicySimulation, error)
+		assertFn func(t *testing.T, resp SimulatePolicyResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 200 with rows",
+			svcFn: func(_ context.Context, req domain.PolicySimulationRequest) (*domain.PolicySimulation, error) {
+				assert.Equal(t, domain.PolicySimulationRequest{
+					Table: "sales.customers", Principal: "alice", RowFilter: filter, Limit: 5,
+				}, req)
+				return &domain.PolicySimulation{
+					TableName:   req.Table,
+					Principal:   req.Principal,
+					Columns:     []string{"id", "region"},
+					Rows:        [][]interface{}{{int32(1), "EU"}},
+					RowFiltered: true,
+				}, nil
+			},
+			assertFn: func(t *testing.T, resp SimulatePolicyResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(SimulatePolicy200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				assert.True(t, ok200.Body.RowFiltered)
+				assert.Equal(t, []string{"id", "region"}, ok200.Body.Columns)
+				assert.Equal(t, [][]interface{}{{int32(1), "EU"}}, ok200.Body.Rows)
+				assert.NotNil(t, ok200.Body.MaskedColumns)
+				assert.Empty(t, ok200.Body.MaskedColumns)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ domain.PolicySimulationRequest) (*domain.PolicySimulation, error) {
+				return nil, domain.ErrAccessDenied("admin privileges required")
+			},
+			assertFn: func(t *testing.T, resp SimulatePolicyResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(SimulatePolicy403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+		{
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _ domain.PolicySimulationRequest) (*domain.PolicySimulation, error) {
+				return nil, domain.ErrValidation("filter_sql is not valid SQL")
+			},
+			assertFn: func(t *testing.T, resp SimulatePolicyResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				badReq, ok := resp.(SimulatePolicy400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+				assert.Equal(t, int32(400), badReq.Body.Code)
+			},
+		},
+		{
+			name: "unknown table returns 404",
+			svcFn: func(_ context.Context, _ domain.PolicySimulationRequest) (*domain.PolicySimulation, error) {
+				return nil, domain.ErrNotFound("table not found")
+			},
+			assertFn: func(t *testing.T, resp SimulatePolicyResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				notFound, ok := resp.(SimulatePolicy404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+				assert.Equal(t, int32(404), notFound.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockRowFilterService{simulateFn: tt.svcFn}
+			handler := &APIHandler{rowFilters: svc}
+			resp, err := handler.SimulatePolicy(secTestCtx(), SimulatePolicyRequestObject{
+				Body: &SimulatePolicyJSONRequestBody{
+					Table:     "sales.customers",
+					Principal: "alice",
+					FilterSql: &filter,
+					Limit:     &limit,
+				},
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
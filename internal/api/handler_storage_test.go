@@ -100,18 +100,18 @@ func (m *mockExternalLocationService) Delete(ctx context.Context, principal stri
 }
 
 type mockVolumeService struct {
-	listFn   func(ctx context.Context, principal, catalogName string, schemaName string, page domain.PageRequest) ([]domain.Volume, int64, error)
+	listFn   func(ctx context.Context, principal, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.Volume, int64, error)
 	createFn func(ctx context.Context, catalogName string, principal, schemaName string, req domain.CreateVolumeRequest) (*domain.Volume, error)
 	getFn    func(ctx context.Context, principal, catalogName string, schemaName, name string) (*domain.Volume, error)
 	updateFn func(ctx context.Context, catalogName string, principal, schemaName, name string, req domain.UpdateVolumeRequest) (*domain.Volume, error)
 	deleteFn func(ctx context.Context, catalogName string, principal, schemaName, name string) error
 }
 
-func (m *mockVolumeService) List(ctx context.Context, principal, catalogName string, schemaName string, page domain.PageRequest) ([]domain.Volume, int64, error) {
+func (m *mockVolumeService) List(ctx context.Context, principal, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.Volume, int64, error) {
 	if m.listFn == nil {
 		panic("mockVolumeService.List called but not configured")
 	}
-	return m.listFn(ctx, principal, catalogName, schemaName, page)
+	return m.listFn(ctx, principal, catalogName, schemaName, owner, page)
 }
 
 func (m *mockVolumeService) Create(ctx context.Context, catalogName string, principal, schemaName string, req domain.CreateVolumeRequest) (*domain.Volume, error) {
@@ -908,12 +908,12 @@ func TestHandler_ListVolumes(t *testing.T) {
 
 	tests := []struct {
 		name     string
-		svcFn    func(ctx context.Context, principal, catalogName string, schemaName string, page domain.PageRequest) ([]domain.Volume, int64, error)
+		svcFn    func(ctx context.Context, principal, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.Volume, int64, error)
 		assertFn func(t *testing.T, resp ListVolumesResponseObject, err error)
 	}{
 		{
 			name: "happy path returns 200 with results",
-			svcFn: func(_ context.Context, _ string, _ string, _ string, _ domain.PageRequest) ([]domain.Volume, int64, error) {
+			svcFn: func(_ context.Context, _ string, _ string, _ string, _ *string, _ domain.PageRequest) ([]domain.Volume, int64, error) {
 				return []domain.Volume{sampleVolume()}, 1, nil
 			},
 			assertFn: func(t *testing.T, resp ListVolumesResponseObject, err error) {
@@ -928,7 +928,7 @@ func TestHandler_ListVolumes(t *testing.T) {
 		},
 		{
 			name: "service error propagates",
-			svcFn: func(_ context.Context, _ string, _ string, _ string, _ domain.PageRequest) ([]domain.Volume, int64, error) {
+			svcFn: func(_ context.Context, _ string, _ string, _ string, _ *string, _ domain.PageRequest) ([]domain.Volume, int64, error) {
 				return nil, 0, assert.AnError
 			},
 			assertFn: func(t *testing.T, resp ListVolumesResponseObject, err error) {
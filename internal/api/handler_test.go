@@ -148,13 +148,13 @@ func setupTestServer(t *testing.T, principalName string) *httptest.Server {
 	tagRepo := repository.NewTagRepo(metaDB)
 	catalogSvc := catalog.NewCatalogService(catalogRepoFactory, cat, auditRepo, tagRepo, nil, nil)
 
-	queryHistorySvc := governance.NewQueryHistoryService(repository.NewQueryHistoryRepo(metaDB))
+	queryHistorySvc := governance.NewQueryHistoryService(repository.NewQueryHistoryRepo(metaDB, metaDB))
 	lineageSvc := governance.NewLineageService(lineageRepo, nil)
 	searchSvc := catalog.NewSearchService(repository.NewSearchRepo(metaDB, metaDB), nil)
 	tagSvc := governance.NewTagService(repository.NewTagRepo(metaDB), auditRepo)
 	viewSvc := catalog.NewViewService(repository.NewViewRepo(metaDB), catalogRepoFactory, cat, auditRepo)
 
-	handler := NewHandler(querySvc, principalSvc, groupSvc, grantSvc, rowFilterSvc, columnMaskSvc, auditSvc, nil, catalogSvc, nil, queryHistorySvc, lineageSvc, searchSvc, tagSvc, viewSvc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	handler := NewHandler(querySvc, principalSvc, groupSvc, grantSvc, rowFilterSvc, columnMaskSvc, auditSvc, nil, catalogSvc, nil, queryHistorySvc, lineageSvc, searchSvc, tagSvc, viewSvc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	strictHandler := NewStrictHandler(handler, nil)
 
 	// Lookup principal to get admin status for context injection
@@ -430,13 +430,13 @@ func setupCatalogTestServer(t *testing.T, principalName string, mockRepo *mockCa
 	mockFactory := &mockCatalogRepoFactory{repo: mockRepo}
 	catalogSvc := catalog.NewCatalogService(mockFactory, cat, auditRepo, tagRepo2, nil, nil)
 
-	queryHistorySvc := governance.NewQueryHistoryService(repository.NewQueryHistoryRepo(metaDB))
+	queryHistorySvc := governance.NewQueryHistoryService(repository.NewQueryHistoryRepo(metaDB, metaDB))
 	lineageSvc := governance.NewLineageService(lineageRepo2, nil)
 	searchSvc := catalog.NewSearchService(repository.NewSearchRepo(metaDB, metaDB), nil)
 	tagSvc := governance.NewTagService(repository.NewTagRepo(metaDB), auditRepo)
 	viewSvc := catalog.NewViewService(repository.NewViewRepo(metaDB), mockFactory, cat, auditRepo)
 
-	handler := NewHandler(querySvc, principalSvc, groupSvc, grantSvc, rowFilterSvc, columnMaskSvc, auditSvc, nil, catalogSvc, nil, queryHistorySvc, lineageSvc, searchSvc, tagSvc, viewSvc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	handler := NewHandler(querySvc, principalSvc, groupSvc, grantSvc, rowFilterSvc, columnMaskSvc, auditSvc, nil, catalogSvc, nil, queryHistorySvc, lineageSvc, searchSvc, tagSvc, viewSvc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	strictHandler := NewStrictHandler(handler, nil)
 
 	// Lookup principal to get admin status for context injection
@@ -544,6 +544,37 @@ func TestAPI_GetMetastoreSummary(t *testing.T) {
 	}
 }
 
+func TestAPI_GetCatalogStats(t *testing.T) {
+	mock := newMockCatalogRepo()
+	mock.addSchema("main")
+	mock.addSchema("sales")
+	mock.addTable("sales", "orders", []domain.ColumnDetail{{Name: "id", Type: "INTEGER"}})
+	mock.addTable("main", "users", []domain.ColumnDetail{{Name: "id", Type: "INTEGER"}})
+	orders := mock.tables["sales.orders"]
+	rows, size := int64(5000), int64(80000)
+	orders.Statistics = &domain.TableStatistics{RowCount: &rows, SizeBytes: &size}
+	mock.tables["sales.orders"] = orders
+
+	srv := setupCatalogTestServer(t, "admin_user", mock)
+	defer srv.Close()
+
+	resp := doRequest(t, "GET", srv.URL+"/catalogs/lake/stats", "")
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	result := decodeJSON[CatalogStats](t, resp)
+
+	if result.SchemaCount != 2 || result.TableCount != 2 {
+		t.Errorf("expected 2 schemas and 2 tables, got %d and %d", result.SchemaCount, result.TableCount)
+	}
+	if result.TotalRows != 5000 || result.TotalBytes != 80000 {
+		t.Errorf("expected 5000 rows and 80000 bytes, got %d and %d", result.TotalRows, result.TotalBytes)
+	}
+	if len(result.LargestTables) != 2 || result.LargestTables[0].TableName != "orders" {
+		t.Errorf("expected orders to be the largest of 2 tables, got %+v", result.LargestTables)
+	}
+}
+
 func TestAPI_SchemaCRUD(t *testing.T) {
 	mock := newMockCatalogRepo()
 	srv := setupCatalogTestServer(t, "admin_user", mock)
@@ -1269,6 +1300,15 @@ func setupSecurityTestServer(t *testing.T, principalName string, isAdmin bool) *
 		nil, // modelSvc
 		nil, // macroSvc
 		nil, // semanticSvc
+		nil, // applyLockSvc
+		nil, // webhookSvc
+		nil, // migrationSvc
+		nil, // backupSvc
+		nil, // versionSvc
+		nil, // extensionSvc
+		nil, // authzSvc
+		nil, // defaultGrantSvc
+		nil, // approvalSvc
 	)
 	strictHandler := NewStrictHandler(handler, nil)
 
@@ -9,11 +9,13 @@ import (
 
 // viewService defines the view operations used by the API handler.
 type viewService interface {
-	ListViews(ctx context.Context, catalogName string, schemaName string, page domain.PageRequest) ([]domain.ViewDetail, int64, error)
+	ListViews(ctx context.Context, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.ViewDetail, int64, error)
 	CreateView(ctx context.Context, catalogName string, principal string, schemaName string, req domain.CreateViewRequest) (*domain.ViewDetail, error)
 	GetView(ctx context.Context, catalogName string, schemaName, viewName string) (*domain.ViewDetail, error)
 	UpdateView(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.UpdateViewRequest) (*domain.ViewDetail, error)
 	DeleteView(ctx context.Context, catalogName string, principal string, schemaName, viewName string) error
+	RefreshView(ctx context.Context, catalogName string, principal string, schemaName, viewName string) (*domain.ViewDetail, error)
+	RenameView(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.RenameRequest) (*domain.ViewDetail, error)
 }
 
 // === Views ===
@@ -21,7 +23,8 @@ type viewService interface {
 // ListViews implements the endpoint for listing views in a schema.
 func (h *APIHandler) ListViews(ctx context.Context, request ListViewsRequestObject) (ListViewsResponseObject, error) {
 	page := pageFromParams(request.Params.MaxResults, request.Params.PageToken)
-	views, total, err := h.views.ListViews(ctx, string(request.CatalogName), request.SchemaName, page)
+	owner := ownerFilter(ctx, request.Params.Owner, request.Params.Mine)
+	views, total, err := h.views.ListViews(ctx, string(request.CatalogName), request.SchemaName, owner, page)
 	if err != nil {
 		switch {
 		case errors.As(err, new(*domain.NotFoundError)):
@@ -52,6 +55,15 @@ func (h *APIHandler) CreateView(ctx context.Context, request CreateViewRequestOb
 	if request.Body.Comment != nil {
 		domReq.Comment = *request.Body.Comment
 	}
+	if request.Body.Materialized != nil {
+		domReq.Materialized = *request.Body.Materialized
+	}
+	if request.Body.RefreshSchedule != nil {
+		domReq.RefreshSchedule = *request.Body.RefreshSchedule
+	}
+	if request.Params.NoValidate != nil {
+		domReq.SkipValidation = *request.Params.NoValidate
+	}
 
 	principal := principalFromCtx(ctx)
 	result, err := h.views.CreateView(ctx, string(request.CatalogName), principal, request.SchemaName, domReq)
@@ -62,7 +74,7 @@ func (h *APIHandler) CreateView(ctx context.Context, request CreateViewRequestOb
 		case errors.As(err, new(*domain.ValidationError)):
 			return CreateView400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.ConflictError)):
-			return CreateView409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+			return CreateView409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return CreateView400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		}
@@ -86,13 +98,18 @@ func (h *APIHandler) GetView(ctx context.Context, request GetViewRequestObject)
 	}
 	return GetView200JSONResponse{
 		Body:    viewDetailToAPI(*result),
-		Headers: GetView200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: GetView200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
 // UpdateView implements the endpoint for updating a view by name.
 func (h *APIHandler) UpdateView(ctx context.Context, request UpdateViewRequestObject) (UpdateViewResponseObject, error) {
-	domReq := domain.UpdateViewRequest{}
+	ifMatch, err := ifMatchFromParams(request.Params.IfMatch)
+	if err != nil {
+		return UpdateView412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+	}
+
+	domReq := domain.UpdateViewRequest{ExpectedRowVersion: ifMatch}
 	if request.Body.Comment != nil {
 		domReq.Comment = request.Body.Comment
 	}
@@ -111,13 +128,15 @@ func (h *APIHandler) UpdateView(ctx context.Context, request UpdateViewRequestOb
 			return UpdateView403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		case errors.As(err, new(*domain.NotFoundError)):
 			return UpdateView404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.PreconditionFailedError)):
+			return UpdateView412JSONResponse{PreconditionFailedJSONResponse{Body: Error{Code: 412, Message: err.Error()}, Headers: PreconditionFailedResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
 		default:
 			return nil, err
 		}
 	}
 	return UpdateView200JSONResponse{
 		Body:    viewDetailToAPI(*result),
-		Headers: UpdateView200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+		Headers: UpdateView200ResponseHeaders{ETag: domain.ETag(result.RowVersion), XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
 	}, nil
 }
 
@@ -136,3 +155,54 @@ func (h *APIHandler) DeleteView(ctx context.Context, request DeleteViewRequestOb
 	}
 	return DeleteView204Response{}, nil
 }
+
+// RefreshView implements the endpoint for recomputing a materialized view.
+func (h *APIHandler) RefreshView(ctx context.Context, request RefreshViewRequestObject) (RefreshViewResponseObject, error) {
+	principal := principalFromCtx(ctx)
+	result, err := h.views.RefreshView(ctx, string(request.CatalogName), principal, request.SchemaName, request.ViewName)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return RefreshView403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return RefreshView404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return RefreshView400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return RefreshView200JSONResponse{
+		Body:    viewDetailToAPI(*result),
+		Headers: RefreshView200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// RenameView implements the endpoint for renaming a view within its schema.
+func (h *APIHandler) RenameView(ctx context.Context, request RenameViewRequestObject) (RenameViewResponseObject, error) {
+	domReq := domain.RenameRequest{NewName: request.Body.NewName}
+	if request.Body.Force != nil {
+		domReq.Force = *request.Body.Force
+	}
+
+	principal := principalFromCtx(ctx)
+	result, err := h.views.RenameView(ctx, string(request.CatalogName), principal, request.SchemaName, request.ViewName, domReq)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return RenameView403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return RenameView404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ValidationError)):
+			return RenameView400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.ConflictError)):
+			return RenameView409JSONResponse{ConflictJSONResponse{Body: Error{Code: 409, ErrorCode: conflictErrorCode(err), Message: err.Error()}, Headers: ConflictResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return RenameView200JSONResponse{
+		Body:    viewDetailToAPI(*result),
+		Headers: RenameView200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
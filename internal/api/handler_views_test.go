@@ -14,18 +14,20 @@ import (
 // === Mock ===
 
 type mockViewService struct {
-	listViewsFn  func(ctx context.Context, catalogName string, schemaName string, page domain.PageRequest) ([]domain.ViewDetail, int64, error)
-	createViewFn func(ctx context.Context, catalogName string, principal string, schemaName string, req domain.CreateViewRequest) (*domain.ViewDetail, error)
-	getViewFn    func(ctx context.Context, catalogName string, schemaName, viewName string) (*domain.ViewDetail, error)
-	updateViewFn func(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.UpdateViewRequest) (*domain.ViewDetail, error)
-	deleteViewFn func(ctx context.Context, catalogName string, principal string, schemaName, viewName string) error
+	listViewsFn   func(ctx context.Context, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.ViewDetail, int64, error)
+	createViewFn  func(ctx context.Context, catalogName string, principal string, schemaName string, req domain.CreateViewRequest) (*domain.ViewDetail, error)
+	getViewFn     func(ctx context.Context, catalogName string, schemaName, viewName string) (*domain.ViewDetail, error)
+	updateViewFn  func(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.UpdateViewRequest) (*domain.ViewDetail, error)
+	deleteViewFn  func(ctx context.Context, catalogName string, principal string, schemaName, viewName string) error
+	refreshViewFn func(ctx context.Context, catalogName string, principal string, schemaName, viewName string) (*domain.ViewDetail, error)
+	renameViewFn  func(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.RenameRequest) (*domain.ViewDetail, error)
 }
 
-func (m *mockViewService) ListViews(ctx context.Context, catalogName string, schemaName string, page domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+func (m *mockViewService) ListViews(ctx context.Context, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.ViewDetail, int64, error) {
 	if m.listViewsFn == nil {
 		panic("mockViewService.ListViews called but not configured")
 	}
-	return m.listViewsFn(ctx, catalogName, schemaName, page)
+	return m.listViewsFn(ctx, catalogName, schemaName, owner, page)
 }
 
 func (m *mockViewService) CreateView(ctx context.Context, catalogName string, principal string, schemaName string, req domain.CreateViewRequest) (*domain.ViewDetail, error) {
@@ -56,6 +58,20 @@ func (m *mockViewService) DeleteView(ctx context.Context, catalogName string, pr
 	return m.deleteViewFn(ctx, catalogName, principal, schemaName, viewName)
 }
 
+func (m *mockViewService) RefreshView(ctx context.Context, catalogName string, principal string, schemaName, viewName string) (*domain.ViewDetail, error) {
+	if m.refreshViewFn == nil {
+		panic("mockViewService.RefreshView called but not configured")
+	}
+	return m.refreshViewFn(ctx, catalogName, principal, schemaName, viewName)
+}
+
+func (m *mockViewService) RenameView(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.RenameRequest) (*domain.ViewDetail, error) {
+	if m.renameViewFn == nil {
+		panic("mockViewService.RenameView called but not configured")
+	}
+	return m.renameViewFn(ctx, catalogName, principal, schemaName, viewName, req)
+}
+
 // === Helpers ===
 
 func viewTestCtx() context.Context {
@@ -93,12 +109,12 @@ func TestHandler_ListViews(t *testing.T) {
 
 	tests := []struct {
 		name     string
-		svcFn    func(ctx context.Context, catalogName string, schemaName string, page domain.PageRequest) ([]domain.ViewDetail, int64, error)
+		svcFn    func(ctx context.Context, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.ViewDetail, int64, error)
 		assertFn func(t *testing.T, resp ListViewsResponseObject, err error)
 	}{
 		{
 			name: "happy path returns 200 with results",
-			svcFn: func(_ context.Context, _ string, _ string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+			svcFn: func(_ context.Context, _ string, _ string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
 				return []domain.ViewDetail{sampleViewDetail()}, 1, nil
 			},
 			assertFn: func(t *testing.T, resp ListViewsResponseObject, err error) {
@@ -113,7 +129,7 @@ func TestHandler_ListViews(t *testing.T) {
 		},
 		{
 			name: "not found schema returns 404",
-			svcFn: func(_ context.Context, _ string, schemaName string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+			svcFn: func(_ context.Context, _ string, schemaName string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
 				return nil, 0, domain.ErrNotFound("schema %s not found", schemaName)
 			},
 			assertFn: func(t *testing.T, resp ListViewsResponseObject, err error) {
@@ -126,7 +142,7 @@ func TestHandler_ListViews(t *testing.T) {
 		},
 		{
 			name: "service error propagates",
-			svcFn: func(_ context.Context, _ string, _ string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+			svcFn: func(_ context.Context, _ string, _ string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
 				return nil, 0, assert.AnError
 			},
 			assertFn: func(t *testing.T, resp ListViewsResponseObject, err error) {
@@ -151,12 +167,49 @@ func TestHandler_ListViews(t *testing.T) {
 	}
 }
 
+func TestHandler_ListViews_OwnerFilter(t *testing.T) {
+	t.Parallel()
+
+	alice := "alice"
+	yes := true
+	tests := []struct {
+		name   string
+		params ListViewsParams
+		want   *string
+	}{
+		{name: "no filter", params: ListViewsParams{}, want: nil},
+		{name: "owner", params: ListViewsParams{Owner: &alice}, want: &alice},
+		{name: "mine resolves to caller", params: ListViewsParams{Mine: &yes}, want: strPtr("test-user")},
+		{name: "mine overrides owner", params: ListViewsParams{Owner: &alice, Mine: &yes}, want: strPtr("test-user")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got *string
+			svc := &mockViewService{listViewsFn: func(_ context.Context, _ string, _ string, owner *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+				got = owner
+				return nil, 0, nil
+			}}
+			handler := &APIHandler{views: svc}
+			_, err := handler.ListViews(viewTestCtx(), ListViewsRequestObject{
+				CatalogName: CatalogName("test-catalog"),
+				SchemaName:  "test-schema",
+				Params:      tt.params,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestHandler_CreateView(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name     string
 		body     CreateViewJSONRequestBody
+		params   CreateViewParams
 		svcFn    func(ctx context.Context, catalogName string, principal string, schemaName string, req domain.CreateViewRequest) (*domain.ViewDetail, error)
 		assertFn func(t *testing.T, resp CreateViewResponseObject, err error)
 	}{
@@ -176,6 +229,24 @@ func TestHandler_CreateView(t *testing.T) {
 				assert.Equal(t, "view-1", *created.Body.Id)
 			},
 		},
+		{
+			name:   "no_validate skips definition validation",
+			body:   CreateViewJSONRequestBody{Name: "my-view", ViewDefinition: "SELECT * FROM not_yet_created"},
+			params: CreateViewParams{NoValidate: boolPtr(true)},
+			svcFn: func(_ context.Context, _ string, _ string, _ string, req domain.CreateViewRequest) (*domain.ViewDetail, error) {
+				if !req.SkipValidation {
+					return nil, domain.ErrValidation("view_definition is invalid: table not found")
+				}
+				v := sampleViewDetail()
+				return &v, nil
+			},
+			assertFn: func(t *testing.T, resp CreateViewResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateView201JSONResponse)
+				require.True(t, ok, "expected 201 response, got %T", resp)
+			},
+		},
 		{
 			name: "validation error returns 400",
 			body: CreateViewJSONRequestBody{Name: "", ViewDefinition: ""},
@@ -245,6 +316,7 @@ func TestHandler_CreateView(t *testing.T) {
 			resp, err := handler.CreateView(viewTestCtx(), CreateViewRequestObject{
 				CatalogName: CatalogName("test-catalog"),
 				SchemaName:  "test-schema",
+				Params:      tt.params,
 				Body:        &body,
 			})
 			tt.assertFn(t, resp, err)
@@ -450,3 +522,155 @@ func TestHandler_DeleteView(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_RefreshView(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, catalogName string, principal string, schemaName, viewName string) (*domain.ViewDetail, error)
+		assertFn func(t *testing.T, resp RefreshViewResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 200",
+			svcFn: func(_ context.Context, _ string, _ string, _, _ string) (*domain.ViewDetail, error) {
+				v := sampleViewDetail()
+				v.Materialized = true
+				refreshed := viewFixedTime
+				v.LastRefreshedAt = &refreshed
+				return &v, nil
+			},
+			assertFn: func(t *testing.T, resp RefreshViewResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(RefreshView200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.NotNil(t, ok200.Body.Materialized)
+				assert.True(t, *ok200.Body.Materialized)
+				require.NotNil(t, ok200.Body.LastRefreshedAt)
+				assert.Equal(t, viewFixedTime, *ok200.Body.LastRefreshedAt)
+			},
+		},
+		{
+			name: "logical view returns 400",
+			svcFn: func(_ context.Context, _ string, _ string, _, _ string) (*domain.ViewDetail, error) {
+				return nil, domain.ErrValidation("view is not materialized")
+			},
+			assertFn: func(t *testing.T, resp RefreshViewResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				badReq, ok := resp.(RefreshView400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+				assert.Equal(t, int32(400), badReq.Body.Code)
+			},
+		},
+		{
+			name: "access denied returns 403",
+			svcFn: func(_ context.Context, _ string, _ string, _, _ string) (*domain.ViewDetail, error) {
+				return nil, domain.ErrAccessDenied("not allowed")
+			},
+			assertFn: func(t *testing.T, resp RefreshViewResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				forbidden, ok := resp.(RefreshView403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+				assert.Equal(t, int32(403), forbidden.Body.Code)
+			},
+		},
+		{
+			name: "not found returns 404",
+			svcFn: func(_ context.Context, _ string, _ string, _, viewName string) (*domain.ViewDetail, error) {
+				return nil, domain.ErrNotFound("view %s not found", viewName)
+			},
+			assertFn: func(t *testing.T, resp RefreshViewResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				notFound, ok := resp.(RefreshView404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+				assert.Equal(t, int32(404), notFound.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockViewService{refreshViewFn: tt.svcFn}
+			handler := &APIHandler{views: svc}
+			resp, err := handler.RefreshView(viewTestCtx(), RefreshViewRequestObject{
+				CatalogName: CatalogName("test-catalog"),
+				SchemaName:  "test-schema",
+				ViewName:    "my-view",
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_RenameView(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.RenameRequest) (*domain.ViewDetail, error)
+		assertFn func(t *testing.T, resp RenameViewResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 200",
+			svcFn: func(_ context.Context, _ string, _ string, _, _ string, req domain.RenameRequest) (*domain.ViewDetail, error) {
+				v := sampleViewDetail()
+				v.Name = req.NewName
+				return &v, nil
+			},
+			assertFn: func(t *testing.T, resp RenameViewResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				ok200, ok := resp.(RenameView200JSONResponse)
+				require.True(t, ok, "expected 200 response, got %T", resp)
+				require.NotNil(t, ok200.Body.Name)
+				assert.Equal(t, "renamed-view", *ok200.Body.Name)
+			},
+		},
+		{
+			name: "name taken returns 409",
+			svcFn: func(_ context.Context, _ string, _ string, _, _ string, req domain.RenameRequest) (*domain.ViewDetail, error) {
+				return nil, domain.ErrConflict("view %q already exists", req.NewName)
+			},
+			assertFn: func(t *testing.T, resp RenameViewResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				conflict, ok := resp.(RenameView409JSONResponse)
+				require.True(t, ok, "expected 409 response, got %T", resp)
+				assert.Equal(t, int32(409), conflict.Body.Code)
+			},
+		},
+		{
+			name: "not found returns 404",
+			svcFn: func(_ context.Context, _ string, _ string, _, viewName string, _ domain.RenameRequest) (*domain.ViewDetail, error) {
+				return nil, domain.ErrNotFound("view %s not found", viewName)
+			},
+			assertFn: func(t *testing.T, resp RenameViewResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				notFound, ok := resp.(RenameView404JSONResponse)
+				require.True(t, ok, "expected 404 response, got %T", resp)
+				assert.Equal(t, int32(404), notFound.Body.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &mockViewService{renameViewFn: tt.svcFn}
+			handler := &APIHandler{views: svc}
+			resp, err := handler.RenameView(viewTestCtx(), RenameViewRequestObject{
+				CatalogName: CatalogName("test-catalog"),
+				SchemaName:  "test-schema",
+				ViewName:    "my-view",
+				Body:        &RenameViewJSONRequestBody{NewName: "renamed-view"},
+			})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
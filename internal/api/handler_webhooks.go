@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"duck-demo/internal/domain"
+)
+
+// webhookService defines the webhook subscription operations used by the API handler.
+type webhookService interface {
+	Create(ctx context.Context, req domain.CreateWebhookRequest) (*domain.Webhook, error)
+	Get(ctx context.Context, id string) (*domain.Webhook, error)
+	List(ctx context.Context, page domain.PageRequest) ([]domain.Webhook, int64, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// === Webhooks ===
+
+// ListWebhooks implements the endpoint for listing webhook subscriptions.
+func (h *APIHandler) ListWebhooks(ctx context.Context, req ListWebhooksRequestObject) (ListWebhooksResponseObject, error) {
+	page := pageFromParams(req.Params.MaxResults, req.Params.PageToken)
+	hooks, total, err := h.webhooks.List(ctx, page)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return ListWebhooks403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	data := make([]Webhook, len(hooks))
+	for i, w := range hooks {
+		data[i] = webhookToAPI(w)
+	}
+	npt := domain.NextPageToken(page.Offset(), page.Limit(), total)
+	return ListWebhooks200JSONResponse{
+		Body:    PaginatedWebhooks{Data: &data, NextPageToken: optStr(npt)},
+		Headers: ListWebhooks200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// CreateWebhook implements the endpoint for creating a webhook subscription.
+func (h *APIHandler) CreateWebhook(ctx context.Context, req CreateWebhookRequestObject) (CreateWebhookResponseObject, error) {
+	events := make([]string, len(req.Body.Events))
+	for i, e := range req.Body.Events {
+		events[i] = string(e)
+	}
+	hook, err := h.webhooks.Create(ctx, domain.CreateWebhookRequest{
+		URL:         req.Body.Url,
+		Secret:      req.Body.Secret,
+		Events:      events,
+		Description: req.Body.Description,
+	})
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.ValidationError)):
+			return CreateWebhook400JSONResponse{BadRequestJSONResponse{Body: Error{Code: 400, Message: err.Error()}, Headers: BadRequestResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return CreateWebhook403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return CreateWebhook201JSONResponse{
+		Body:    webhookToAPI(*hook),
+		Headers: CreateWebhook201ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// GetWebhook implements the endpoint for reading a webhook subscription.
+func (h *APIHandler) GetWebhook(ctx context.Context, req GetWebhookRequestObject) (GetWebhookResponseObject, error) {
+	hook, err := h.webhooks.Get(ctx, req.WebhookId)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return GetWebhook403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return GetWebhook404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return GetWebhook200JSONResponse{
+		Body:    webhookToAPI(*hook),
+		Headers: GetWebhook200ResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset},
+	}, nil
+}
+
+// DeleteWebhook implements the endpoint for deleting a webhook subscription.
+func (h *APIHandler) DeleteWebhook(ctx context.Context, req DeleteWebhookRequestObject) (DeleteWebhookResponseObject, error) {
+	if err := h.webhooks.Delete(ctx, req.WebhookId); err != nil {
+		switch {
+		case errors.As(err, new(*domain.AccessDeniedError)):
+			return DeleteWebhook403JSONResponse{ForbiddenJSONResponse{Body: Error{Code: 403, Message: err.Error()}, Headers: ForbiddenResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		case errors.As(err, new(*domain.NotFoundError)):
+			return DeleteWebhook404JSONResponse{NotFoundJSONResponse{Body: Error{Code: 404, Message: err.Error()}, Headers: NotFoundResponseHeaders{XRateLimitLimit: defaultRateLimitLimit, XRateLimitRemaining: defaultRateLimitRemaining, XRateLimitReset: defaultRateLimitReset}}}, nil
+		default:
+			return nil, err
+		}
+	}
+	return DeleteWebhook204Response{}, nil
+}
+
+// webhookToAPI converts a domain Webhook to the API type. The signing secret
+// is deliberately omitted.
+func webhookToAPI(w domain.Webhook) Webhook {
+	events := make([]WebhookEventType, len(w.Events))
+	for i, e := range w.Events {
+		events[i] = WebhookEventType(e)
+	}
+	return Webhook{
+		Id:          w.ID,
+		Url:         w.URL,
+		Events:      events,
+		Description: w.Description,
+		CreatedBy:   w.CreatedBy,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
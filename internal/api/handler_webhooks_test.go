@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+type mockWebhookService struct {
+	createFn func(ctx context.Context, req domain.CreateWebhookRequest) (*domain.Webhook, error)
+	getFn    func(ctx context.Context, id string) (*domain.Webhook, error)
+	listFn   func(ctx context.Context, page domain.PageRequest) ([]domain.Webhook, int64, error)
+	deleteFn func(ctx context.Context, id string) error
+}
+
+func (m *mockWebhookService) Create(ctx context.Context, req domain.CreateWebhookRequest) (*domain.Webhook, error) {
+	if m.createFn == nil {
+		panic("mockWebhookService.Create called but not configured")
+	}
+	return m.createFn(ctx, req)
+}
+
+func (m *mockWebhookService) Get(ctx context.Context, id string) (*domain.Webhook, error) {
+	if m.getFn == nil {
+		panic("mockWebhookService.Get called but not configured")
+	}
+	return m.getFn(ctx, id)
+}
+
+func (m *mockWebhookService) List(ctx context.Context, page domain.PageRequest) ([]domain.Webhook, int64, error) {
+	if m.listFn == nil {
+		panic("mockWebhookService.List called but not configured")
+	}
+	return m.listFn(ctx, page)
+}
+
+func (m *mockWebhookService) Delete(ctx context.Context, id string) error {
+	if m.deleteFn == nil {
+		panic("mockWebhookService.Delete called but not configured")
+	}
+	return m.deleteFn(ctx, id)
+}
+
+func TestHandler_CreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		svcFn    func(ctx context.Context, req domain.CreateWebhookRequest) (*domain.Webhook, error)
+		assertFn func(t *testing.T, resp CreateWebhookResponseObject, err error)
+	}{
+		{
+			name: "happy path returns 201 without secret",
+			svcFn: func(_ context.Context, req domain.CreateWebhookRequest) (*domain.Webhook, error) {
+				assert.Equal(t, []string{domain.EventGrantCreated}, req.Events)
+				assert.Equal(t, "whsec_0123456789abcdef", req.Secret)
+				return &domain.Webhook{ID: "hook-1", URL: req.URL, Secret: req.Secret, Events: req.Events}, nil
+			},
+			assertFn: func(t *testing.T, resp CreateWebhookResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				created, ok := resp.(CreateWebhook201JSONResponse)
+				require.True(t, ok, "expected 201 response, got %T", resp)
+				assert.Equal(t, "hook-1", created.Body.Id)
+				assert.Equal(t, []WebhookEventType{WebhookEventType(domain.EventGrantCreated)}, created.Body.Events)
+			},
+		},
+		{
+			name: "validation error returns 400",
+			svcFn: func(_ context.Context, _ domain.CreateWebhookRequest) (*domain.Webhook, error) {
+				return nil, domain.ErrValidation("unknown event type %q", "grant.exploded")
+			},
+			assertFn: func(t *testing.T, resp CreateWebhookResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateWebhook400JSONResponse)
+				require.True(t, ok, "expected 400 response, got %T", resp)
+			},
+		},
+		{
+			name: "non-admin returns 403",
+			svcFn: func(_ context.Context, _ domain.CreateWebhookRequest) (*domain.Webhook, error) {
+				return nil, domain.ErrAccessDenied("admin privileges required")
+			},
+			assertFn: func(t *testing.T, resp CreateWebhookResponseObject, err error) {
+				t.Helper()
+				require.NoError(t, err)
+				_, ok := resp.(CreateWebhook403JSONResponse)
+				require.True(t, ok, "expected 403 response, got %T", resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := &APIHandler{webhooks: &mockWebhookService{createFn: tt.svcFn}}
+			body := CreateWebhookJSONRequestBody{
+				Url:    "https://hooks.example.com/duck",
+				Secret: "whsec_0123456789abcdef",
+				Events: []WebhookEventType{WebhookEventType(domain.EventGrantCreated)},
+			}
+			resp, err := handler.CreateWebhook(storageTestCtx(), CreateWebhookRequestObject{Body: &body})
+			tt.assertFn(t, resp, err)
+		})
+	}
+}
+
+func TestHandler_DeleteWebhook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("existing webhook returns 204", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{webhooks: &mockWebhookService{deleteFn: func(_ context.Context, id string) error {
+			assert.Equal(t, "hook-1", id)
+			return nil
+		}}}
+		resp, err := handler.DeleteWebhook(storageTestCtx(), DeleteWebhookRequestObject{WebhookId: "hook-1"})
+		require.NoError(t, err)
+		_, ok := resp.(DeleteWebhook204Response)
+		assert.True(t, ok, "expected 204 response, got %T", resp)
+	})
+
+	t.Run("missing webhook returns 404", func(t *testing.T) {
+		t.Parallel()
+		handler := &APIHandler{webhooks: &mockWebhookService{deleteFn: func(_ context.Context, _ string) error {
+			return domain.ErrNotFound("webhook %q not found", "hook-1")
+		}}}
+		resp, err := handler.DeleteWebhook(storageTestCtx(), DeleteWebhookRequestObject{WebhookId: "hook-1"})
+		require.NoError(t, err)
+		_, ok := resp.(DeleteWebhook404JSONResponse)
+		assert.True(t, ok, "expected 404 response, got %T", resp)
+	})
+}
@@ -68,6 +68,37 @@ func (m *mockCatalogRepo) GetMetastoreSummary(_ context.Context) (*domain.Metast
 	}, nil
 }
 
+func (m *mockCatalogRepo) GetCatalogStats(_ context.Context, largest int) (*domain.CatalogStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := &domain.CatalogStats{
+		CatalogName:   "lake",
+		SchemaCount:   int64(len(m.schemas)),
+		TableCount:    int64(len(m.tables)),
+		LargestTables: []domain.TableSize{},
+	}
+	for _, t := range m.tables {
+		size := domain.TableSize{SchemaName: t.SchemaName, TableName: t.Name}
+		if t.Statistics != nil && t.Statistics.RowCount != nil {
+			size.RowCount = *t.Statistics.RowCount
+		}
+		if t.Statistics != nil && t.Statistics.SizeBytes != nil {
+			size.SizeBytes = *t.Statistics.SizeBytes
+		}
+		stats.TotalRows += size.RowCount
+		stats.TotalBytes += size.SizeBytes
+		stats.LargestTables = append(stats.LargestTables, size)
+	}
+	sort.Slice(stats.LargestTables, func(i, j int) bool {
+		return stats.LargestTables[i].SizeBytes > stats.LargestTables[j].SizeBytes
+	})
+	if len(stats.LargestTables) > largest {
+		stats.LargestTables = stats.LargestTables[:largest]
+	}
+	return stats, nil
+}
+
 func (m *mockCatalogRepo) CreateSchema(_ context.Context, name, comment, owner string) (*domain.SchemaDetail, error) {
 	if err := m.validateIdentifier(name); err != nil {
 		return nil, err
@@ -105,13 +136,16 @@ func (m *mockCatalogRepo) GetSchema(_ context.Context, name string) (*domain.Sch
 	return &s, nil
 }
 
-func (m *mockCatalogRepo) ListSchemas(_ context.Context, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+func (m *mockCatalogRepo) ListSchemas(_ context.Context, owner *string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Collect and sort by name
 	names := make([]string, 0, len(m.schemas))
-	for n := range m.schemas {
+	for n, s := range m.schemas {
+		if owner != nil && s.Owner != *owner {
+			continue
+		}
 		names = append(names, n)
 	}
 	sort.Strings(names)
@@ -136,7 +170,7 @@ func (m *mockCatalogRepo) ListSchemas(_ context.Context, page domain.PageRequest
 	return result, total, nil
 }
 
-func (m *mockCatalogRepo) UpdateSchema(_ context.Context, name string, comment *string, props map[string]string) (*domain.SchemaDetail, error) {
+func (m *mockCatalogRepo) UpdateSchema(_ context.Context, name string, comment *string, props map[string]string, _ *int64) (*domain.SchemaDetail, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -257,7 +291,7 @@ func (m *mockCatalogRepo) GetTable(_ context.Context, schemaName, tableName stri
 	return &t, nil
 }
 
-func (m *mockCatalogRepo) ListTables(_ context.Context, schemaName string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
+func (m *mockCatalogRepo) ListTables(_ context.Context, schemaName string, owner *string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -269,6 +303,9 @@ func (m *mockCatalogRepo) ListTables(_ context.Context, schemaName string, page
 	var keys []string
 	for key := range m.tables {
 		if len(key) > len(prefix) && key[:len(prefix)] == prefix {
+			if owner != nil && m.tables[key].Owner != *owner {
+				continue
+			}
 			keys = append(keys, key)
 		}
 	}
@@ -349,7 +386,7 @@ func (m *mockCatalogRepo) ListColumns(_ context.Context, schemaName, tableName s
 	return cols[offset:end], total, nil
 }
 
-func (m *mockCatalogRepo) UpdateTable(_ context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string) (*domain.TableDetail, error) {
+func (m *mockCatalogRepo) UpdateTable(_ context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string, _ *int64) (*domain.TableDetail, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -417,6 +454,30 @@ func (m *mockCatalogRepo) CreateExternalTable(_ context.Context, _ string, _ dom
 	panic("unexpected call to mockCatalogRepo.CreateExternalTable")
 }
 
+func (m *mockCatalogRepo) ListFiles(_ context.Context, _ string) ([]string, error) {
+	panic("unexpected call to mockCatalogRepo.ListFiles")
+}
+
+func (m *mockCatalogRepo) CompactTable(_ context.Context, _, _ string) (*domain.TableMaintenanceResult, error) {
+	panic("unexpected call to mockCatalogRepo.CompactTable")
+}
+
+func (m *mockCatalogRepo) VacuumTable(_ context.Context, _, _ string, _ time.Duration) (*domain.TableMaintenanceResult, error) {
+	panic("unexpected call to mockCatalogRepo.VacuumTable")
+}
+
+func (m *mockCatalogRepo) RollbackTable(_ context.Context, _, _ string, _ domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+	panic("unexpected call to mockCatalogRepo.RollbackTable")
+}
+
+func (m *mockCatalogRepo) MaterializeTable(_ context.Context, _, _, _ string) (int64, error) {
+	panic("unexpected call to mockCatalogRepo.MaterializeTable")
+}
+
+func (m *mockCatalogRepo) RenameTable(_ context.Context, _, _, _ string) (*domain.TableDetail, error) {
+	panic("unexpected call to mockCatalogRepo.RenameTable")
+}
+
 // mockCatalogRepoFactory wraps a mockCatalogRepo to implement catalog.CatalogRepoFactory.
 type mockCatalogRepoFactory struct {
 	repo *mockCatalogRepo
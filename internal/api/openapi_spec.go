@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"duck-demo/internal/apispec"
+	"duck-demo/internal/domain"
+)
+
+// specHash returns the content hash of the embedded OpenAPI spec.
+var specHash = sync.OnceValues(func() (string, error) {
+	swagger, err := GetSwagger()
+	if err != nil {
+		return "", err
+	}
+	return apispec.Hash(swagger)
+})
+
+// ServeOpenAPISpec serves the embedded OpenAPI spec as JSON with its hash as
+// the ETag. A version query parameter pins the request to a spec revision,
+// given as info.version or as the hash; if the server serves a different
+// revision it answers 404 instead of a spec the client did not ask for.
+func ServeOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	swagger, err := GetSwagger()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hash, err := specHash()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if want := r.URL.Query().Get("version"); want != "" && want != swagger.Info.Version && want != hash {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":       http.StatusNotFound,
+			"error_code": domain.ErrorCodeNotFound,
+			"message":    fmt.Sprintf("spec version %q is not served; this server serves %s (%s)", want, swagger.Info.Version, hash),
+		})
+		return
+	}
+	w.Header().Set("ETag", `"`+hash+`"`)
+	_ = json.NewEncoder(w).Encode(swagger)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeOpenAPISpec(t *testing.T) {
+	t.Parallel()
+	swagger, err := GetSwagger()
+	require.NoError(t, err)
+	hash, err := specHash()
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		query      string
+		wantStatus int
+	}{
+		"latest":         {"", http.StatusOK},
+		"pinned version": {"?version=" + swagger.Info.Version, http.StatusOK},
+		"pinned hash":    {"?version=" + hash, http.StatusOK},
+		"other version":  {"?version=0.0.1", http.StatusNotFound},
+		"other hash":     {"?version=sha256:0000", http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			rec := httptest.NewRecorder()
+			ServeOpenAPISpec(rec, httptest.NewRequest(http.MethodGet, "/openapi.json"+tc.query, nil))
+			require.Equal(t, tc.wantStatus, rec.Code)
+
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			if tc.wantStatus != http.StatusOK {
+				assert.Contains(t, body["message"], hash)
+				return
+			}
+			assert.Equal(t, `"`+hash+`"`, rec.Header().Get("ETag"))
+			assert.Equal(t, swagger.Info.Version, body["info"].(map[string]interface{})["version"])
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Package apispec identifies revisions of the platform's OpenAPI spec, so the
+// server and the generated CLI can tell whether they were built from the same
+// spec.
+package apispec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// HashPrefix names the digest algorithm in a spec hash.
+const HashPrefix = "sha256:"
+
+// Hash returns a content hash of spec, such as "sha256:3b1f...". The spec is
+// hashed in its JSON encoding, which orders object keys, so the hash depends
+// only on what the spec says and not on how its source file was laid out.
+func Hash(spec *openapi3.T) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("encode spec: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return HashPrefix + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,68 @@
+package apispec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const specYAML = `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.2.0
+paths:
+  /items:
+    get:
+      operationId: listItems
+      responses:
+        '200':
+          description: Items
+`
+
+// Same spec with keys in a different order.
+const reorderedSpecYAML = `
+paths:
+  /items:
+    get:
+      responses:
+        '200':
+          description: Items
+      operationId: listItems
+info:
+  version: 1.2.0
+  title: Test API
+openapi: 3.0.3
+`
+
+func loadSpec(t *testing.T, src string) *openapi3.T {
+	t.Helper()
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(src))
+	require.NoError(t, err)
+	return spec
+}
+
+func TestHash_StableAcrossLoadsAndLayout(t *testing.T) {
+	first, err := Hash(loadSpec(t, specYAML))
+	require.NoError(t, err)
+	again, err := Hash(loadSpec(t, specYAML))
+	require.NoError(t, err)
+	reordered, err := Hash(loadSpec(t, reorderedSpecYAML))
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, HashPrefix), "hash %q lacks %q prefix", first, HashPrefix)
+	assert.Len(t, first, len(HashPrefix)+64)
+	assert.Equal(t, first, again)
+	assert.Equal(t, first, reordered)
+}
+
+func TestHash_ChangesWithContent(t *testing.T) {
+	base, err := Hash(loadSpec(t, specYAML))
+	require.NoError(t, err)
+	changed, err := Hash(loadSpec(t, strings.Replace(specYAML, "description: Items", "description: All items", 1)))
+	require.NoError(t, err)
+	assert.NotEqual(t, base, changed)
+}
@@ -11,10 +11,12 @@ import (
 
 	"duck-demo/internal/compute"
 	"duck-demo/internal/config"
+	"duck-demo/internal/db"
 	"duck-demo/internal/db/crypto"
 	"duck-demo/internal/db/repository"
 	"duck-demo/internal/domain"
 	"duck-demo/internal/engine"
+	"duck-demo/internal/service/admin"
 	"duck-demo/internal/service/catalog"
 	svccompute "duck-demo/internal/service/compute"
 	"duck-demo/internal/service/governance"
@@ -27,6 +29,7 @@ import (
 	"duck-demo/internal/service/security"
 	"duck-demo/internal/service/semantic"
 	"duck-demo/internal/service/storage"
+	"duck-demo/internal/service/webhook"
 )
 
 // Deps holds the external dependencies that main() must provide.
@@ -37,7 +40,12 @@ type Deps struct {
 	DuckDB  *sql.DB
 	WriteDB *sql.DB
 	ReadDB  *sql.DB
-	Logger  *slog.Logger
+	// ReplicaDB is an optional query-only pool on a replica of the metastore.
+	// Repositories that tolerate stale reads use it; everything that must
+	// see its own writes stays on WriteDB or ReadDB. Nil means ReadDB.
+	ReplicaDB *sql.DB
+	Logger    *slog.Logger
+	Version   string // release the server binary was built as
 }
 
 // Services groups all service pointers that the API handler and router need.
@@ -70,16 +78,31 @@ type Services struct {
 	Model               *svcmodel.Service
 	Macro               *macro.Service
 	Semantic            *semantic.Service
+	ApplyLock           *governance.ApplyLockService
+	Webhook             *webhook.Service
+	Migration           *admin.MigrationService
+	Backup              *admin.BackupService
+	Version             *admin.VersionService
+	Extension           *admin.ExtensionService
+	Authorization       *security.AuthorizationService
+	DefaultGrant        *security.DefaultGrantService
+	Approval            *admin.ApprovalService
+	Seed                *admin.SeedService
+	SearchIndex         *admin.SearchIndexService
 }
 
 // App holds the fully-wired application: engine, services, and the
-// repositories needed for router setup (APIKeyRepo for auth middleware).
+// repositories needed for router setup (APIKeyRepo for auth middleware,
+// DenialAuditor for auditing refused requests), and the webhook dispatcher
+// whose in-flight deliveries are drained on shutdown.
 type App struct {
-	Services      Services
-	Engine        *engine.SecureEngine
-	APIKeyRepo    *repository.APIKeyRepo
-	PrincipalRepo *repository.PrincipalRepo
-	Scheduler     *pipeline.Scheduler
+	Services          Services
+	Engine            *engine.SecureEngine
+	APIKeyRepo        *repository.APIKeyRepo
+	PrincipalRepo     *repository.PrincipalRepo
+	Scheduler         *pipeline.Scheduler
+	DenialAuditor     *security.DenialAuditor
+	WebhookDispatcher *webhook.Dispatcher
 }
 
 // New wires all repositories, services, and engine from the provided deps.
@@ -99,10 +122,14 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	// === 2. All repositories (write-pool) ===
 	principalRepo := repository.NewPrincipalRepo(deps.WriteDB)
 	groupRepo := repository.NewGroupRepo(deps.WriteDB)
+	principalAttrRepo := repository.NewPrincipalAttributeRepo(deps.WriteDB)
+	principalQueryDefaultsRepo := repository.NewPrincipalQueryDefaultsRepo(deps.WriteDB)
 	grantRepo := repository.NewGrantRepo(deps.WriteDB)
+	defaultGrantRepo := repository.NewDefaultGrantRepo(deps.WriteDB)
 	rowFilterRepo := repository.NewRowFilterRepo(deps.WriteDB)
 	columnMaskRepo := repository.NewColumnMaskRepo(deps.WriteDB)
 	auditRepo := repository.NewAuditRepo(deps.WriteDB)
+	auditArchiveRepo := repository.NewAuditArchiveRepo(deps.WriteDB)
 	lineageRepo := repository.NewLineageRepo(deps.WriteDB)
 	colLineageRepo := repository.NewColumnLineageRepo(deps.WriteDB)
 	tagRepo := repository.NewTagRepo(deps.WriteDB)
@@ -115,6 +142,8 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	computeEndpointRepo := repository.NewComputeEndpointRepo(deps.WriteDB, encryptor)
 	catalogRegRepo := repository.NewCatalogRegistrationRepo(deps.WriteDB)
 	queryJobRepo := repository.NewQueryJobRepo(deps.WriteDB)
+	applyLockRepo := repository.NewApplyLockRepo(deps.WriteDB)
+	webhookRepo := repository.NewWebhookRepo(deps.WriteDB, encryptor)
 
 	// === 3. Factories (multi-catalog) ===
 	catalogRepoFactory := repository.NewCatalogRepoFactory(
@@ -125,15 +154,26 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	metastoreFactory := repository.NewMetastoreRepoFactory(catalogRegRepo)
 
 	// === 4. Repositories (read-pool) ===
+	// Query history and search tolerate replica lag; introspection feeds
+	// query planning and must see the primary.
+	staleReadDB := deps.ReadDB
+	if deps.ReplicaDB != nil {
+		staleReadDB = deps.ReplicaDB
+	}
 	introspectionRepo := repository.NewIntrospectionRepo(deps.ReadDB)
-	queryHistoryRepo := repository.NewQueryHistoryRepo(deps.ReadDB)
-	searchRepo := repository.NewSearchRepo(deps.ReadDB, deps.ReadDB)
+	queryHistoryRepo := repository.NewQueryHistoryRepo(staleReadDB, deps.WriteDB)
+	searchRepo := repository.NewSearchRepo(staleReadDB, staleReadDB)
 
 	// === 5. Compute resolver (needs endpoint repo, principal repo, group repo) ===
 	localExec := compute.NewLocalExecutor(deps.DuckDB)
+	computeTLS, err := compute.ClientTLSConfig(cfg.ComputeTLSCertFile, cfg.ComputeTLSKeyFile, cfg.ComputeTLSCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("compute TLS: %w", err)
+	}
 	remoteCache := compute.NewRemoteCacheWithOptions(deps.DuckDB, compute.RemoteExecutorOptions{
 		CursorModeEnabled: cfg.FeatureCursorMode,
 		InternalGRPC:      cfg.FeatureInternalGRPC,
+		TLS:               computeTLS,
 	})
 	fullResolver := compute.NewResolver(
 		localExec, computeEndpointRepo, principalRepo, groupRepo,
@@ -141,6 +181,8 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	)
 	fullResolver.SetRoutingEnabled(cfg.FeatureRemoteRouting && cfg.FeatureInternalGRPC)
 	fullResolver.SetCanaryUsers(cfg.RemoteCanaryUsers)
+	fullResolver.SetAgentRepository(computeEndpointRepo)
+	fullResolver.SetAgentRouting(cfg.ComputeAgentRouting)
 
 	// === 6. Authorization (needs all security repos + extTableRepo) ===
 	authSvc := security.NewAuthorizationService(
@@ -155,7 +197,18 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 		}
 		return repo.GetTable(ctx, schemaName, tableName)
 	})
+	authSvc.SetDefaultCatalogLookup(catalogRepoFactory.DefaultCatalogName)
 	authSvc.SetViewRepository(viewRepo)
+	authSvc.SetPrincipalAttributeRepository(principalAttrRepo)
+	authSvc.SetCatalogRegistrationRepository(catalogRegRepo)
+	authSvc.SetCatalogIntrospection(func(ctx context.Context, catalogName string) (domain.IntrospectionRepository, error) {
+		repo, err := introspectionFactory.ForCatalog(ctx, catalogName)
+		if err != nil {
+			return nil, err
+		}
+		return repo, nil
+	})
+	authSvc.SetVolumeRepository(volumeRepo)
 	authSvc.SetCatalogViewLookup(func(ctx context.Context, catalogName, schemaName, viewName string) (*domain.ViewDetail, error) {
 		repo, err := catalogRepoFactory.ForCatalog(ctx, catalogName)
 		if err != nil {
@@ -203,6 +256,11 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	// InformationSchemaProvider aggregates metadata across all active catalogs.
 	infoSchema := engine.NewInformationSchemaProvider(catalogRepoFactory, catalogRegRepo)
 	eng := engine.NewSecureEngine(deps.DuckDB, authSvc, fullResolver, infoSchema, deps.Logger.With("component", "engine"))
+	extensions := engine.NewExtensionAllowlist(cfg.DuckDBExtensionAllowlist)
+	eng.SetExtensionAllowlist(extensions)
+	denialAuditor := security.NewDenialAuditor(auditRepo, security.DefaultDenialAuditWindow)
+	eng.SetDenialRecorder(denialAuditor)
+	eng.SetQueryDefaults(principalQueryDefaultsRepo)
 
 	// Restore external table VIEWs (best-effort)
 	if err := restoreExternalTableViews(ctx, deps.DuckDB, extTableRepo, deps.Logger); err != nil {
@@ -215,24 +273,43 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	catalogAdapter := query.NewCatalogAdapter(introspectionRepo)
 	querySvc.SetColumnLineage(colLineageRepo, catalogAdapter)
 	querySvc.SetJobRepository(queryJobRepo)
+	querySvc.SetJobRetention(cfg.QueryJobTTL)
 	principalSvc := security.NewPrincipalService(principalRepo, auditRepo)
+	principalSvc.SetAttributeRepository(principalAttrRepo)
+	principalSvc.SetQueryDefaultsRepository(principalQueryDefaultsRepo)
 	groupSvc := security.NewGroupService(groupRepo, auditRepo)
 	grantSvc := security.NewGrantService(grantRepo, auditRepo, authSvc)
+	grantSvc.SetPrincipalRepository(principalRepo)
+	defaultGrantSvc := security.NewDefaultGrantService(defaultGrantRepo, auditRepo)
 	rowFilterSvc := security.NewRowFilterService(rowFilterRepo, auditRepo)
+	rowFilterSvc.SetSimulationDeps(authSvc, principalRepo, principalAttrRepo, eng)
 	columnMaskSvc := security.NewColumnMaskService(columnMaskRepo, auditRepo)
+	columnMaskSvc.SetPreviewDeps(authSvc, introspectionRepo, eng)
 	auditSvc := governance.NewAuditService(auditRepo)
-	queryHistorySvc := governance.NewQueryHistoryService(queryHistoryRepo)
+	if cfg.AuditArchiveLocation != "" {
+		archiveStore := governance.NewObjectArchiveStore(storageCredRepo, cfg.AuditArchiveCredential, cfg.AuditArchiveLocation)
+		auditSvc.SetArchiving(auditArchiveRepo, archiveStore, cfg.AuditRetentionDays)
+	}
+	queryHistorySvc := governance.NewQueryHistoryService(queryHistoryRepo, auditRepo)
+	queryHistorySvc.SetRetention(cfg.QueryHistoryRetentionDays)
 	lineageSvc := governance.NewLineageService(lineageRepo, colLineageRepo, auditRepo)
-	searchRepoFactory := repository.NewSearchRepoFactory(deps.ReadDB, catalogRegRepo)
+	searchRepoFactory := repository.NewSearchRepoFactory(staleReadDB, deps.WriteDB, catalogRegRepo)
 	searchSvc := catalog.NewSearchService(searchRepo, searchRepoFactory)
 	tagSvc := governance.NewTagService(tagRepo, auditRepo)
+	applyLockSvc := governance.NewApplyLockService(applyLockRepo, auditRepo)
 	viewSvc := catalog.NewViewService(viewRepo, catalogRepoFactory, authSvc, auditRepo)
+	viewSvc.SetValidationEngine(eng)
 	catalogSvc := catalog.NewCatalogService(catalogRepoFactory, authSvc, auditRepo, tagRepo, tableStatsRepo, externalLocRepo)
 	storageCredSvc := storage.NewStorageCredentialService(storageCredRepo, authSvc, auditRepo)
 	computeEndpointSvc := svccompute.NewComputeEndpointService(computeEndpointRepo, authSvc, auditRepo)
+	computeEndpointSvc.SetLoadRepository(computeEndpointRepo)
+	computeEndpointSvc.SetAgentRepository(computeEndpointRepo)
+	computeEndpointSvc.SetTLSConfig(computeTLS)
+	computeEndpointSvc.SetLogger(deps.Logger.With("component", "compute-load"))
 	volumeSvc := storage.NewVolumeService(volumeRepo, authSvc, auditRepo)
 
 	secretMgr := engine.NewDuckDBSecretManager(deps.DuckDB)
+	secretMgr.SetExtensionAllowlist(extensions)
 	extLocationSvc := storage.NewExternalLocationService(
 		externalLocRepo, storageCredRepo, authSvc, auditRepo, secretMgr,
 		deps.Logger.With("component", "external-location"),
@@ -248,6 +325,37 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 		MetastoreFactory:   metastoreFactory,
 		IntrospectionClose: introspectionFactory.Close,
 		CatalogRepoEvict:   catalogRepoFactory.Evict,
+		AttachAttempts:     cfg.CatalogAttachAttempts,
+		AttachBackoff:      cfg.CatalogAttachBackoff,
+		RequireAttach:      cfg.RequireCatalog,
+	})
+
+	// === Webhooks (signed change notifications) ===
+	webhookSvc := webhook.NewService(webhookRepo, auditRepo)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, deps.Logger.With("component", "webhooks"))
+	grantSvc.SetEventPublisher(webhookDispatcher)
+	principalSvc.SetEventPublisher(webhookDispatcher)
+	catalogRegSvc.SetEventPublisher(webhookDispatcher)
+
+	// === Admin (metastore schema migrations and backups) ===
+	migrator := db.NewMigrator(deps.WriteDB)
+	migrationSvc := admin.NewMigrationService(migrator, auditRepo)
+	backupSvc := admin.NewBackupService(db.NewSnapshotter(deps.WriteDB), migrator, auditRepo)
+	versionSvc := admin.NewVersionService(deps.Version, deps.DuckDB, migrator, computeEndpointSvc)
+	extensionSvc := admin.NewExtensionService(engine.NewExtensionInventory(deps.DuckDB, extensions))
+	seedSvc := admin.NewSeedService(principalRepo, groupRepo, auditRepo)
+	searchIndexSvc := admin.NewSearchIndexService(searchSvc, auditRepo)
+
+	// === Dual control (second-admin approval for destructive operations) ===
+	approvalSvc := admin.NewApprovalService(repository.NewApprovalRepo(deps.WriteDB), auditRepo)
+	if err := approvalSvc.SetRequiredOperations(cfg.ApprovalRequiredOperations); err != nil {
+		return nil, fmt.Errorf("approval operations: %w", err)
+	}
+	approvalSvc.RegisterExecutor(domain.ApprovalOpSetAdmin, func(ctx context.Context, params map[string]string) error {
+		return principalSvc.SetAdmin(ctx, params["principal_id"], true)
+	})
+	approvalSvc.RegisterExecutor(domain.ApprovalOpDeleteCatalog, func(ctx context.Context, params map[string]string) error {
+		return catalogRegSvc.Delete(ctx, params["catalog_name"])
 	})
 
 	// === Manifest and Ingestion services (always available, use factory-based metastore) ===
@@ -274,7 +382,7 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	notebookSvc := notebook.New(notebookRepo, auditRepo)
 	sessionMgr := notebook.NewSessionManager(deps.DuckDB, eng, notebookRepo, notebookJobRepo, auditRepo)
 	gitRepoRepo := repository.NewGitRepoRepo(deps.WriteDB)
-	gitSvc := notebook.NewGitService(gitRepoRepo, auditRepo)
+	gitSvc := notebook.NewGitService(gitRepoRepo, notebookRepo, auditRepo)
 
 	// === Pipeline ===
 	pipelineRepo := repository.NewPipelineRepo(deps.WriteDB)
@@ -288,6 +396,9 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	pipelineScheduler := pipeline.NewScheduler(pipelineSvc, pipelineRepo,
 		deps.Logger.With("component", "pipeline-scheduler"))
 	pipelineSvc.SetScheduleReloader(pipelineScheduler)
+	// Materialized views with a refresh schedule run on the pipeline scheduler.
+	pipelineScheduler.AddJobSource(viewSvc)
+	viewSvc.SetScheduleReloader(pipelineScheduler)
 
 	// === Model ===
 	modelRepo := repository.NewModelRepo(deps.WriteDB)
@@ -308,6 +419,16 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 	// Wire optional dependencies into model service.
 	modelSvc.SetMacroRepo(macroRepo)
 	modelSvc.SetNotebookProvider(notebookProvider)
+	modelSvc.SetTestFailureRetention(cfg.ModelTestFailuresTTL)
+
+	// Table rollback reports dependent views and models and records lineage.
+	catalogSvc.SetDependencyRepos(viewRepo, modelRepo, lineageRepo)
+
+	// New schemas and tables receive the configured default grants.
+	catalogSvc.SetDefaultGrants(defaultGrantRepo, grantRepo)
+
+	// Policies can be copied from one table to another.
+	catalogSvc.SetPolicyRepos(rowFilterRepo, columnMaskRepo)
 
 	// === Semantic ===
 	semanticModelRepo := repository.NewSemanticModelRepo(deps.WriteDB)
@@ -351,10 +472,23 @@ func New(ctx context.Context, deps Deps) (*App, error) {
 			Model:               modelSvc,
 			Macro:               macroSvc,
 			Semantic:            semanticSvc,
+			ApplyLock:           applyLockSvc,
+			Webhook:             webhookSvc,
+			Migration:           migrationSvc,
+			Backup:              backupSvc,
+			Version:             versionSvc,
+			Extension:           extensionSvc,
+			Authorization:       authSvc,
+			DefaultGrant:        defaultGrantSvc,
+			Approval:            approvalSvc,
+			Seed:                seedSvc,
+			SearchIndex:         searchIndexSvc,
 		},
-		Engine:        eng,
-		APIKeyRepo:    apiKeyRepo,
-		PrincipalRepo: principalRepo,
-		Scheduler:     pipelineScheduler,
+		Engine:            eng,
+		APIKeyRepo:        apiKeyRepo,
+		PrincipalRepo:     principalRepo,
+		Scheduler:         pipelineScheduler,
+		DenialAuditor:     denialAuditor,
+		WebhookDispatcher: webhookDispatcher,
 	}, nil
 }
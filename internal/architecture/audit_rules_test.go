@@ -37,6 +37,9 @@ var auditMutationPrefixes = []string{
 // Key format: "path/to/file.go:Receiver.Method".
 var auditRuleExceptions = map[string]string{
 	"internal/service/catalog/registration.go:CatalogRegistrationService.AttachAll": "startup reconciliation path; audit policy handled at caller/system level",
+	"internal/service/query/query_async.go:QueryService.PurgeExpiredJobs":           "background retention sweep with no principal; jobs expire by configured TTL",
+	"internal/service/security/grant.go:GrantService.PurgeExpired":                  "background sweep of grants that already expired; the grant itself was audited",
+	"internal/service/governance/query_history.go:QueryHistoryService.PurgeExpired": "background retention sweep with no principal; history expires by configured retention",
 	"internal/service/notebook/session.go:SessionManager.ExecuteCell":               "high-volume cell execution path; auditing policy handled at run/job level",
 	"internal/service/notebook/session.go:SessionManager.RunAll":                    "delegates execution to ExecuteCell; avoid duplicate per-run noise",
 	"internal/service/semantic/runtime.go:Service.RunMetricQuery":                   "query execution path is covered by query history/audit at execution layer",
@@ -115,6 +115,35 @@ func TestAuthzContractParity_CriticalEndpoints(t *testing.T) {
 			serviceBodySnippets:  []string{"domain.SecurableTable", "domain.PrivCreateTable"},
 			requiresLookupBefore: true,
 		},
+		"compactTable": {
+			mode:                 "privilege",
+			securableType:        "table",
+			privilege:            "MODIFY",
+			securableIDSource:    "runtime_resolved_object_id",
+			serviceFile:          "internal/service/catalog/catalog.go",
+			serviceMethod:        "CompactTable",
+			serviceBodySnippets:  []string{"domain.SecurableTable", "domain.PrivModify"},
+			requiresLookupBefore: true,
+		},
+		"vacuumTable": {
+			mode:                "privilege",
+			securableType:       "catalog",
+			privilege:           "MANAGE",
+			securableIDSource:   "catalog_name_param",
+			serviceFile:         "internal/service/catalog/catalog.go",
+			serviceMethod:       "VacuumTable",
+			serviceBodySnippets: []string{"domain.SecurableCatalog", "domain.PrivManage"},
+		},
+		"rollbackTable": {
+			mode:                 "privilege",
+			securableType:        "table",
+			privilege:            "MANAGE",
+			securableIDSource:    "runtime_resolved_object_id",
+			serviceFile:          "internal/service/catalog/catalog.go",
+			serviceMethod:        "RollbackTable",
+			serviceBodySnippets:  []string{"domain.SecurableTable", "domain.PrivManage"},
+			requiresLookupBefore: true,
+		},
 		"createView": {
 			mode:                 "privilege",
 			securableType:        "schema",
@@ -280,6 +309,26 @@ func TestAuthzContractParity_CriticalEndpoints(t *testing.T) {
 			serviceMethod:       "Unassign",
 			serviceBodySnippets: []string{"requirePrivilege(", "domain.PrivManageCompute"},
 		},
+		"createComputeEndpointAgent": {
+			mode:                 "privilege",
+			securableType:        "compute_endpoint",
+			privilege:            "MANAGE_COMPUTE",
+			securableIDSource:    "runtime_resolved_object_id",
+			serviceFile:          "internal/service/compute/endpoint.go",
+			serviceMethod:        "AddAgent",
+			serviceBodySnippets:  []string{"requireEndpointPrivilege(", "domain.PrivManageCompute"},
+			requiresLookupBefore: true,
+		},
+		"deleteComputeEndpointAgent": {
+			mode:                 "privilege",
+			securableType:        "compute_endpoint",
+			privilege:            "MANAGE_COMPUTE",
+			securableIDSource:    "runtime_resolved_object_id",
+			serviceFile:          "internal/service/compute/endpoint.go",
+			serviceMethod:        "RemoveAgent",
+			serviceBodySnippets:  []string{"requireEndpointPrivilege(", "domain.PrivManageCompute"},
+			requiresLookupBefore: true,
+		},
 		"createGrant": {
 			mode:                "admin_only",
 			serviceFile:         "internal/service/security/grant.go",
@@ -292,6 +341,48 @@ func TestAuthzContractParity_CriticalEndpoints(t *testing.T) {
 			serviceMethod:       "Revoke",
 			serviceBodySnippets: []string{"requireAdmin("},
 		},
+		"previewColumnMask": {
+			mode:                "admin_only",
+			serviceFile:         "internal/service/security/column_mask.go",
+			serviceMethod:       "Preview",
+			serviceBodySnippets: []string{"requireAdmin("},
+		},
+		"importPrincipals": {
+			mode:                "admin_only",
+			serviceFile:         "internal/service/security/principal.go",
+			serviceMethod:       "Import",
+			serviceBodySnippets: []string{"requireAdmin("},
+		},
+		"archiveAuditLogs": {
+			mode:                "admin_only",
+			serviceFile:         "internal/service/governance/audit.go",
+			serviceMethod:       "Archive",
+			serviceBodySnippets: []string{"requireAdmin("},
+		},
+		"purgeQueryHistory": {
+			mode:                "admin_only",
+			serviceFile:         "internal/service/governance/query_history.go",
+			serviceMethod:       "Purge",
+			serviceBodySnippets: []string{"requireAdmin("},
+		},
+		"simulatePolicy": {
+			mode:                "admin_only",
+			serviceFile:         "internal/service/security/row_filter.go",
+			serviceMethod:       "SimulatePolicy",
+			serviceBodySnippets: []string{"requireAdmin("},
+		},
+		"getPrincipalAttributes": {
+			mode:                "admin_only",
+			serviceFile:         "internal/service/security/principal.go",
+			serviceMethod:       "GetAttributes",
+			serviceBodySnippets: []string{"requireAdmin("},
+		},
+		"setPrincipalAttributes": {
+			mode:                "admin_only",
+			serviceFile:         "internal/service/security/principal.go",
+			serviceMethod:       "SetAttributes",
+			serviceBodySnippets: []string{"requireAdmin("},
+		},
 	}
 
 	for opID, exp := range expects {
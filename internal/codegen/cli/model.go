@@ -126,6 +126,12 @@ type APIEndpointModel struct {
 	CLICommand  string // corresponding CLI command path, e.g. "catalog schemas create"
 }
 
+// SpecInfoModel identifies the OpenAPI spec the CLI is generated from.
+type SpecInfoModel struct {
+	Version string // info.version
+	Hash    string // apispec.Hash of the spec
+}
+
 // APIAuthzModel represents endpoint authorization metadata from OpenAPI x-authz.
 type APIAuthzModel struct {
 	Mode        string
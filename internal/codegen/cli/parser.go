@@ -231,7 +231,9 @@ func buildCommandModel(groupName, _ string, cmdCfg CommandConfig, info *opInfo,
 	// Parse parameters
 	for _, pRef := range info.params {
 		p := pRef.Value
-		if p == nil {
+		if p == nil || p.In == "header" {
+			// Header parameters such as If-Match are protocol-level and
+			// sent by the client itself, not exposed as flags.
 			continue
 		}
 		pm := paramToModel(p)
@@ -247,6 +249,9 @@ func buildCommandModel(groupName, _ string, cmdCfg CommandConfig, info *opInfo,
 		}
 		if p.In == "query" {
 			cm.QueryParams = append(cm.QueryParams, pm)
+			if positionalSet[p.Name] && !p.Required {
+				return nil, fmt.Errorf("positional arg %q for %q must be required in spec", p.Name, cmdCfg.OperationID)
+			}
 		}
 		// Add as flag unless it's a positional
 		if !positionalSet[p.Name] {
@@ -373,13 +378,20 @@ func buildCommandModel(groupName, _ string, cmdCfg CommandConfig, info *opInfo,
 	return cm, nil
 }
 
-// validatePositionalArgs checks that every positional arg exists as a path param or body field.
+// validatePositionalArgs checks that every positional arg exists as a path
+// param, query param, or body field.
 func validatePositionalArgs(cmdCfg CommandConfig, cm *CommandModel) error {
 	for _, pa := range cmdCfg.PositionalArgs {
-		foundInPath := false
+		foundInParams := false
 		for _, pp := range cm.PathParams {
 			if pp.Name == pa {
-				foundInPath = true
+				foundInParams = true
+				break
+			}
+		}
+		for _, qp := range cm.QueryParams {
+			if qp.Name == pa {
+				foundInParams = true
 				break
 			}
 		}
@@ -391,14 +403,14 @@ func validatePositionalArgs(cmdCfg CommandConfig, cm *CommandModel) error {
 			}
 		}
 		// Also check if it's a body property that was skipped via positionalSet
-		if !foundInPath && !foundInBody {
+		if !foundInParams && !foundInBody {
 			// Check request body properties directly
 			if cm.HasBody {
 				foundInBody = true // if it's marked positional and body exists, it was skipped intentionally
 			}
 		}
-		if !foundInPath && !foundInBody {
-			return fmt.Errorf("positional_arg %q for %q not found as path param or body field", pa, cmdCfg.OperationID)
+		if !foundInParams && !foundInBody {
+			return fmt.Errorf("positional_arg %q for %q not found as path param, query param, or body field", pa, cmdCfg.OperationID)
 		}
 	}
 	return nil
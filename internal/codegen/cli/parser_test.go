@@ -188,6 +188,14 @@ func TestParse_FullSpec(t *testing.T) {
 				}
 			}
 			assert.True(t, found, "listSchemas command not found")
+			for _, cmd := range g.Commands {
+				if cmd.OperationID != "updateSchema" {
+					continue
+				}
+				for _, f := range cmd.Flags {
+					assert.NotEqual(t, "if-match", f.Name, "header parameters are not flags")
+				}
+			}
 
 		case "security":
 			assert.Greater(t, len(g.Commands), 15, "security should have many commands")
@@ -208,6 +216,18 @@ func TestParse_FullSpec(t *testing.T) {
 
 		case "pipelines":
 			assert.Greater(t, len(g.Commands), 10, "pipelines should have many commands")
+
+		case "models":
+			foundRun := false
+			for _, cmd := range g.Commands {
+				if cmd.OperationID == "triggerModelRun" {
+					foundRun = true
+					assert.Equal(t, "run", cmd.Verb, "models run")
+					assert.Empty(t, cmd.CommandPath)
+					break
+				}
+			}
+			assert.True(t, foundRun, "triggerModelRun command not found")
 		}
 	}
 }
@@ -219,3 +219,28 @@ func renderAPIRegistry(endpoints []APIEndpointModel, outDir string) error {
 
 	return nil
 }
+
+// RenderSpecInfo generates the spec.gen.go file, which records the version
+// and hash of the spec the CLI was generated from.
+func RenderSpecInfo(info SpecInfoModel, outDir string) error {
+	tmpl, err := template.New("").ParseFS(templateFS, "templates/spec.go.tmpl")
+	if err != nil {
+		return fmt.Errorf("parse spec template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "spec.go.tmpl", info); err != nil {
+		return fmt.Errorf("execute spec template: %w", err)
+	}
+
+	formatted, err := imports.Process("spec.gen.go", buf.Bytes(), nil)
+	if err != nil {
+		return fmt.Errorf("goimports spec.gen.go: %w", err)
+	}
+
+	outPath := filepath.Join(outDir, "spec.gen.go")
+	if err := os.WriteFile(outPath, formatted, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", outPath, err)
+	}
+	return nil
+}
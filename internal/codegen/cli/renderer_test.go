@@ -482,3 +482,57 @@ func TestRender_FuncMap_GroupCommands(t *testing.T) {
 		assert.Len(t, result[""], 2)
 	})
 }
+
+func TestRender_PositionalQueryParams(t *testing.T) {
+	op := &openapi3.Operation{
+		OperationID: "checkItem",
+		Tags:        []string{"Items"},
+		Summary:     "Check an item",
+		Parameters: openapi3.Parameters{
+			makeParam("principal", "query", "string", true),
+			makeParam("verbose", "query", "boolean", false),
+		},
+		Responses: &openapi3.Responses{},
+	}
+	op.Responses.Set("200", makeResponse("OK"))
+	spec := buildSpecFromOp("checkItem", "GET", "/items/check", op)
+
+	args := []string{"principal"}
+	cfg := &Config{CommandOverrides: map[string]CommandOverride{
+		"checkItem": {Verb: stringPtr("check"), PositionalArgs: &args},
+	}}
+	groups, err := Parse(spec, cfg)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0].Commands, 1)
+
+	cmd := groups[0].Commands[0]
+	assert.Equal(t, "check <principal>", cmd.Use)
+	flagNames := make([]string, 0, len(cmd.Flags))
+	for _, f := range cmd.Flags {
+		flagNames = append(flagNames, f.Name)
+	}
+	assert.NotContains(t, flagNames, "principal", "positional query params are not flags")
+	assert.Contains(t, flagNames, "verbose")
+
+	outDir := t.TempDir()
+	require.NoError(t, Render(groups, cfg, outDir))
+	src, err := os.ReadFile(filepath.Join(outDir, "items.gen.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(src), `query.Set("principal", args[0])`)
+
+	optional := []string{"verbose"}
+	cfg.CommandOverrides["checkItem"] = CommandOverride{Verb: stringPtr("check"), PositionalArgs: &optional}
+	_, err = Parse(spec, cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be required")
+}
+
+func TestRenderSpecInfo(t *testing.T) {
+	outDir := t.TempDir()
+	require.NoError(t, RenderSpecInfo(SpecInfoModel{Version: "3.0.0", Hash: "sha256:abc"}, outDir))
+	src, err := os.ReadFile(filepath.Join(outDir, "spec.gen.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(src), `const SpecVersion = "3.0.0"`)
+	assert.Contains(t, string(src), `const SpecHash = "sha256:abc"`)
+}
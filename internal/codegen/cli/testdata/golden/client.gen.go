@@ -3,21 +3,65 @@ package gen
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Default client settings; the CLI overrides them from --timeout/--retries.
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 2
+
+	defaultRetryBaseDelay = 250 * time.Millisecond
+	maxRetryDelay         = 30 * time.Second
+)
+
+// IdempotencyKeyHeader carries a client-chosen key that lets the server
+// recognise a repeated POST and replay its original response.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IfMatchHeader carries the ETag a client read, making an update conditional
+// on the resource being unchanged since then.
+const IfMatchHeader = "If-Match"
+
 // Client is the HTTP client used by generated commands.
 type Client struct {
 	BaseURL    string
 	APIKey     string
 	Token      string
 	HTTPClient *http.Client
+
+	// MaxRetries is how many times a retryable request is retried after a
+	// connection error, a 5xx, or a 429. Zero disables retries.
+	MaxRetries int
+	// RetryBaseDelay is the first backoff delay; it doubles on each retry.
+	// A Retry-After header from the server takes precedence.
+	RetryBaseDelay time.Duration
+
+	// Verbose enables wire tracing: 1 logs each request and response, 2 also
+	// logs retry attempts and a connection timing breakdown. Credentials are
+	// always redacted.
+	Verbose int
+	// TraceWriter receives verbose output; nil means stderr.
+	TraceWriter io.Writer
+
+	// BeforeFirstRequest, if set, runs once before the first request the
+	// client sends. Requests it sends itself go out without waiting for it.
+	BeforeFirstRequest func()
+	firstRequest       sync.Once
+	inFirstRequest     atomic.Bool
 }
 
 // NewClient creates a new API client.
@@ -27,20 +71,64 @@ func NewClient(baseURL, apiKey, token string) *Client {
 		APIKey:  apiKey,
 		Token:   token,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultTimeout,
 		},
+		MaxRetries:     DefaultMaxRetries,
+		RetryBaseDelay: defaultRetryBaseDelay,
 	}
 }
 
-// Do executes an HTTP request with auth headers.
+// Do executes an HTTP request with auth headers. Idempotent methods (GET,
+// HEAD, OPTIONS, PUT, DELETE) are retried with exponential backoff on
+// transient failures; POST is sent once. Use DoIdempotent for POSTs that are
+// safe to repeat.
 func (c *Client) Do(method, path string, query url.Values, body interface{}) (*http.Response, error) {
-	var bodyReader io.Reader
+	return c.do(method, path, query, body, isIdempotentMethod(method), nil)
+}
+
+// DoIdempotent is like Do but retries the request whatever its method. Use it
+// only for requests that have no additional effect when repeated.
+func (c *Client) DoIdempotent(method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	return c.do(method, path, query, body, true, nil)
+}
+
+// DoWithIdempotencyKey is like DoIdempotent and sends key in the
+// Idempotency-Key header, so a server that already handled the request
+// replays its response instead of running it again.
+func (c *Client) DoWithIdempotencyKey(method, path string, query url.Values, body interface{}, key string) (*http.Response, error) {
+	header := http.Header{}
+	header.Set(IdempotencyKeyHeader, key)
+	return c.do(method, path, query, body, true, header)
+}
+
+// DoIfMatch is like Do and sends etag in the If-Match header, so the server
+// rejects the update with 412 Precondition Failed if the resource changed
+// since etag was read. An empty etag sends the request unconditionally.
+func (c *Client) DoIfMatch(method, path string, query url.Values, body interface{}, etag string) (*http.Response, error) {
+	var header http.Header
+	if etag != "" {
+		header = http.Header{}
+		header.Set(IfMatchHeader, etag)
+	}
+	return c.do(method, path, query, body, isIdempotentMethod(method), header)
+}
+
+func (c *Client) do(method, path string, query url.Values, body interface{}, retryable bool, header http.Header) (*http.Response, error) {
+	if c.BeforeFirstRequest != nil && !c.inFirstRequest.Load() {
+		c.firstRequest.Do(func() {
+			c.inFirstRequest.Store(true)
+			defer c.inFirstRequest.Store(false)
+			c.BeforeFirstRequest()
+		})
+	}
+
+	var data []byte
 	if body != nil {
-		data, err := json.Marshal(body)
+		var err error
+		data, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(data)
 	}
 
 	u := c.BaseURL + "/v1" + path
@@ -48,29 +136,292 @@ func (c *Client) Do(method, path string, query url.Values, body interface{}) (*h
 		u += "?" + query.Encode()
 	}
 
-	req, err := http.NewRequest(method, u, bodyReader)
+	attempts := 1
+	if retryable && c.MaxRetries > 0 {
+		attempts += c.MaxRetries
+	}
+	for attempt := 1; ; attempt++ {
+		var bodyReader io.Reader
+		if data != nil {
+			bodyReader = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, u, bodyReader)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+
+		// Auth: prefer token, then API key
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		} else if c.APIKey != "" {
+			req.Header.Set("X-API-Key", c.APIKey)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		var timing *requestTiming
+		if c.Verbose > 0 {
+			c.traceRequest(req, data)
+			if c.Verbose > 1 {
+				timing = &requestTiming{}
+				req = req.WithContext(httptrace.WithClientTrace(req.Context(), timing.clientTrace()))
+			}
+		}
+		start := time.Now()
+		resp, err := c.HTTPClient.Do(req)
+		if c.Verbose > 0 {
+			resp = c.traceResponse(resp, err, start, timing)
+		}
+		if attempt >= attempts || !shouldRetry(resp, err) {
+			if err != nil {
+				return nil, fmt.Errorf("execute request: %w", err)
+			}
+			return resp, nil
+		}
+
+		delay := c.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp); ok {
+				delay = d
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if c.Verbose > 1 {
+			c.tracef("* Retrying in %s (attempt %d of %d)\n", delay, attempt+1, attempts)
+		}
+		time.Sleep(delay)
+	}
+}
+
+// isIdempotentMethod reports whether repeating a request with method has the
+// same effect as sending it once.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether a request failed transiently: a connection
+// error, a 5xx response, or 429 Too Many Requests.
+func shouldRetry(resp *http.Response, err error) bool {
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns the delay before retry number attempt (1-based).
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.RetryBaseDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
 	}
+	return delay
+}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	} else {
+		return 0, false
 	}
-	req.Header.Set("Accept", "application/json")
+	if d < 0 {
+		d = 0
+	}
+	if d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	return d, true
+}
+
+// maxTraceBody caps how much of a request or response body verbose tracing
+// prints.
+const maxTraceBody = 8 << 10
+
+// redactedValue replaces credentials in verbose output.
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders are never printed by verbose tracing.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"X-Api-Key":           true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+func (c *Client) tracef(format string, args ...interface{}) {
+	w := c.TraceWriter
+	if w == nil {
+		w = os.Stderr
+	}
+	_, _ = fmt.Fprintf(w, format, args...)
+}
+
+func (c *Client) traceHeaders(prefix string, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range h[k] {
+			if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+				v = redactedValue
+			}
+			c.tracef("%s %s: %s\n", prefix, k, v)
+		}
+	}
+}
+
+func (c *Client) traceBody(prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	body = redactBody(body)
+	truncated := 0
+	if len(body) > maxTraceBody {
+		truncated = len(body) - maxTraceBody
+		body = body[:maxTraceBody]
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(body), "\n"), "\n") {
+		c.tracef("%s %s\n", prefix, line)
+	}
+	if truncated > 0 {
+		c.tracef("%s ... (%d more bytes)\n", prefix, truncated)
+	}
+}
+
+// traceRequest logs the request line, headers and body.
+func (c *Client) traceRequest(req *http.Request, body []byte) {
+	c.tracef("> %s %s\n", req.Method, req.URL.String())
+	c.traceHeaders(">", req.Header)
+	c.tracef(">\n")
+	c.traceBody(">", body)
+}
+
+// traceResponse logs the response status, headers, duration and body. The
+// body is buffered so the caller can still read it.
+func (c *Client) traceResponse(resp *http.Response, err error, start time.Time, timing *requestTiming) *http.Response {
+	elapsed := time.Since(start)
+	if err != nil {
+		c.tracef("* Request failed after %s: %v\n", elapsed.Round(time.Microsecond), err)
+		return resp
+	}
+	c.tracef("< %s %s (%s)\n", resp.Proto, resp.Status, elapsed.Round(time.Microsecond))
+	c.traceHeaders("<", resp.Header)
+	c.tracef("<\n")
+	body, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		c.tracef("* Reading response body failed: %v\n", readErr)
+	}
+	c.traceBody("<", body)
+	if timing != nil {
+		c.tracef("* Timing: %s total=%s\n", timing, time.Since(start).Round(time.Microsecond))
+	}
+	return resp
+}
 
-	// Auth: prefer token, then API key
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-	} else if c.APIKey != "" {
-		req.Header.Set("X-API-Key", c.APIKey)
+// sensitiveBodyKey reports whether a JSON field holds a credential.
+func sensitiveBodyKey(key string) bool {
+	k := strings.ToLower(key)
+	if strings.HasSuffix(k, "page_token") {
+		return false
+	}
+	for _, s := range []string{"password", "secret", "token", "api_key", "apikey", "private_key", "access_key", "credential"} {
+		if strings.Contains(k, s) {
+			return true
+		}
 	}
+	return false
+}
 
-	resp, err := c.HTTPClient.Do(req)
+// redactBody replaces credential fields in a JSON body. Bodies that are not
+// JSON are returned unchanged.
+func redactBody(body []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	out, err := json.MarshalIndent(redactValue(v), "", "  ")
 	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+		return body
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if sensitiveBodyKey(k) {
+				if _, isString := val.(string); isString {
+					t[k] = redactedValue
+					continue
+				}
+			}
+			t[k] = redactValue(val)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redactValue(t[i])
+		}
+	}
+	return v
+}
+
+// requestTiming records connection phase durations for -vv output.
+type requestTiming struct {
+	start, dnsStart, connectStart, tlsStart time.Time
+
+	dns, connect, tls, firstByte time.Duration
+	reused                       bool
+}
+
+func (t *requestTiming) clientTrace() *httptrace.ClientTrace {
+	t.start = time.Now()
+	return &httptrace.ClientTrace{
+		GotConn:              func(info httptrace.GotConnInfo) { t.reused = info.Reused },
+		DNSStart:             func(httptrace.DNSStartInfo) { t.dnsStart = time.Now() },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.dns = time.Since(t.dnsStart) },
+		ConnectStart:         func(string, string) { t.connectStart = time.Now() },
+		ConnectDone:          func(string, string, error) { t.connect = time.Since(t.connectStart) },
+		TLSHandshakeStart:    func() { t.tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.tls = time.Since(t.tlsStart) },
+		GotFirstResponseByte: func() { t.firstByte = time.Since(t.start) },
 	}
+}
 
-	return resp, nil
+func (t *requestTiming) String() string {
+	r := func(d time.Duration) time.Duration { return d.Round(time.Microsecond) }
+	s := fmt.Sprintf("dns=%s connect=%s tls=%s first_byte=%s", r(t.dns), r(t.connect), r(t.tls), r(t.firstByte))
+	if t.reused {
+		s += " (connection reused)"
+	}
+	return s
 }
 
 // ReadBody reads and returns the response body, closing it.
@@ -83,7 +434,11 @@ func ReadBody(resp *http.Response) ([]byte, error) {
 type APIError struct {
 	HTTPStatus int
 	Code       string
-	Message    string
+	// ErrorCode is the server's stable error code, such as RESOURCE_EXISTS,
+	// or empty for servers that do not send one. Branch on it rather than
+	// on Message.
+	ErrorCode string
+	Message   string
 }
 
 func (e *APIError) Error() string {
@@ -96,20 +451,27 @@ func CheckError(resp *http.Response) error {
 		return nil
 	}
 	body, _ := ReadBody(resp)
+	return NewAPIError(resp.StatusCode, body)
+}
+
+// NewAPIError builds the APIError for an error response's status and body.
+func NewAPIError(status int, body []byte) *APIError {
 	var apiErr struct {
-		Code    interface{} `json:"code"`
-		Message string      `json:"message"`
+		Code      interface{} `json:"code"`
+		ErrorCode string      `json:"error_code"`
+		Message   string      `json:"message"`
 	}
 	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
 		return &APIError{
-			HTTPStatus: resp.StatusCode,
+			HTTPStatus: status,
 			Code:       fmt.Sprintf("%v", apiErr.Code),
+			ErrorCode:  apiErr.ErrorCode,
 			Message:    apiErr.Message,
 		}
 	}
 	return &APIError{
-		HTTPStatus: resp.StatusCode,
-		Code:       fmt.Sprintf("%d", resp.StatusCode),
+		HTTPStatus: status,
+		Code:       fmt.Sprintf("%d", status),
 		Message:    string(body),
 	}
 }
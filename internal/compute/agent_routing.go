@@ -0,0 +1,84 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"duck-demo/internal/domain"
+)
+
+// agentRouter spreads queries across the agents of a multi-agent endpoint.
+// It keeps a per-endpoint cursor so consecutive resolutions start probing at
+// a different agent.
+type agentRouter struct {
+	policy string
+
+	mu   sync.Mutex
+	next map[string]int // endpoint ID → index of the agent to probe first
+}
+
+func newAgentRouter(policy string) *agentRouter {
+	return &agentRouter{policy: policy, next: map[string]int{}}
+}
+
+// start returns the index to start probing at and advances the cursor.
+func (a *agentRouter) start(endpointID string, n int) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	i := a.next[endpointID] % n
+	a.next[endpointID] = (i + 1) % n
+	return i
+}
+
+// pick probes the agents in rotation order and returns the executor for the
+// chosen one. Round-robin takes the first healthy agent; least-loaded probes
+// every agent and takes the one with the fewest running and queued
+// statements, with ties going to the earliest in rotation order. Unhealthy
+// agents are skipped; the last health error is returned when none is healthy.
+func (a *agentRouter) pick(ctx context.Context, agents []*RemoteExecutor, endpointID string) (*RemoteExecutor, error) {
+	first := a.start(endpointID, len(agents))
+
+	var (
+		best     *RemoteExecutor
+		bestLoad int64
+		lastErr  error
+	)
+	for i := range agents {
+		exec := agents[(first+i)%len(agents)]
+		health, err := exec.health(ctx)
+		if err != nil {
+			lastErr = fmt.Errorf("agent %s: %w", exec.endpointURL, err)
+			continue
+		}
+		if a.policy != domain.AgentRoutingLeastLoaded {
+			return exec, nil
+		}
+		load := health.ActiveQueries + health.WaitingQueries
+		if best == nil || load < bestLoad {
+			best, bestLoad = exec, load
+		}
+	}
+	if best == nil {
+		return nil, lastErr
+	}
+	return best, nil
+}
+
+// agentURLs returns the endpoint URL followed by any additional agent URLs.
+func (r *DefaultResolver) agentURLs(ctx context.Context, ep *domain.ComputeEndpoint) ([]string, error) {
+	urls := []string{ep.URL}
+	if r.agentRepo == nil {
+		return urls, nil
+	}
+	agents, err := r.agentRepo.ListAgents(ctx, ep.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list agents for endpoint %q: %w", ep.Name, err)
+	}
+	for _, agent := range agents {
+		if agent.URL != ep.URL {
+			urls = append(urls, agent.URL)
+		}
+	}
+	return urls, nil
+}
@@ -0,0 +1,149 @@
+package compute
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	computeproto "duck-demo/internal/compute/proto"
+	"duck-demo/internal/domain"
+)
+
+// deadAgentURL refuses connections, so health checks against it fail fast.
+const deadAgentURL = "grpc://127.0.0.1:1"
+
+type loadReportingGRPCServer struct {
+	computeproto.UnimplementedComputeWorkerServer
+	active int64
+}
+
+func (s *loadReportingGRPCServer) Health(_ context.Context, _ *computeproto.HealthRequest) (*computeproto.HealthResponse, error) {
+	return &computeproto.HealthResponse{Status: "ok", ActiveQueries: s.active}, nil
+}
+
+func startLoadReportingAgent(t *testing.T, active int64) string {
+	t.Helper()
+
+	EnsureGRPCJSONCodec()
+	grpcServer := grpc.NewServer()
+	computeproto.RegisterComputeWorkerServer(grpcServer, &loadReportingGRPCServer{active: active})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		grpcServer.GracefulStop()
+		_ = ln.Close()
+	})
+	go func() {
+		_ = grpcServer.Serve(ln)
+	}()
+	return "grpc://" + ln.Addr().String()
+}
+
+type staticAgentRepo struct {
+	agents []domain.ComputeEndpointAgent
+}
+
+func (r *staticAgentRepo) AddAgent(_ context.Context, _ *domain.ComputeEndpointAgent) (*domain.ComputeEndpointAgent, error) {
+	panic("unexpected")
+}
+func (r *staticAgentRepo) RemoveAgent(_ context.Context, _, _ string) error { panic("unexpected") }
+func (r *staticAgentRepo) ListAgents(_ context.Context, endpointID string) ([]domain.ComputeEndpointAgent, error) {
+	var out []domain.ComputeEndpointAgent
+	for _, a := range r.agents {
+		if a.EndpointID == endpointID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+var _ domain.ComputeEndpointAgentRepository = (*staticAgentRepo)(nil)
+
+// newMultiAgentResolver resolves alice to endpoint "ha-ep", served by
+// primaryURL plus the given agent URLs.
+func newMultiAgentResolver(t *testing.T, primaryURL string, agentURLs ...string) *DefaultResolver {
+	t.Helper()
+	localDB := openTestDuckDB(t)
+
+	principalRepo := &mockPrincipalRepo{
+		getByNameFn: func(_ context.Context, _ string) (*domain.Principal, error) {
+			return &domain.Principal{ID: "1", Name: "alice"}, nil
+		},
+	}
+	computeRepo := &mockComputeRepo{
+		getDefaultForPrincipalFn: func(_ context.Context, _ string, _ string) (*domain.ComputeEndpoint, error) {
+			return &domain.ComputeEndpoint{
+				ID: "10", Name: "ha-ep", Type: "REMOTE", Status: "ACTIVE",
+				URL: primaryURL, AuthToken: "tok",
+			}, nil
+		},
+	}
+	agents := &staticAgentRepo{}
+	for i, u := range agentURLs {
+		agents.agents = append(agents.agents, domain.ComputeEndpointAgent{ID: string(rune('a' + i)), EndpointID: "10", URL: u})
+	}
+
+	resolver := NewResolver(NewLocalExecutor(localDB), computeRepo, principalRepo, &mockGroupRepo{}, NewRemoteCache(localDB), nil)
+	resolver.SetAgentRepository(agents)
+	return resolver
+}
+
+// resolvedAgents resolves alice n times and counts the agent URL chosen.
+func resolvedAgents(t *testing.T, resolver *DefaultResolver, n int) map[string]int {
+	t.Helper()
+	counts := map[string]int{}
+	for range n {
+		executor, err := resolver.Resolve(context.Background(), "alice")
+		require.NoError(t, err)
+		remote, ok := executor.(*RemoteExecutor)
+		require.True(t, ok, "expected remote executor, got %T", executor)
+		counts[remote.endpointURL]++
+	}
+	return counts
+}
+
+func TestResolver_RoundRobinAcrossAgents(t *testing.T) {
+	first := startLoadReportingAgent(t, 0)
+	second := startLoadReportingAgent(t, 0)
+
+	t.Run("distributes across healthy agents", func(t *testing.T) {
+		resolver := newMultiAgentResolver(t, first, second)
+
+		counts := resolvedAgents(t, resolver, 6)
+		assert.Equal(t, map[string]int{first: 3, second: 3}, counts)
+	})
+
+	t.Run("skips a dead agent", func(t *testing.T) {
+		resolver := newMultiAgentResolver(t, first, deadAgentURL, second)
+
+		counts := resolvedAgents(t, resolver, 6)
+		assert.Zero(t, counts[deadAgentURL])
+		assert.Positive(t, counts[first])
+		assert.Positive(t, counts[second])
+		assert.Equal(t, 6, counts[first]+counts[second])
+	})
+
+	t.Run("all agents dead", func(t *testing.T) {
+		resolver := newMultiAgentResolver(t, deadAgentURL, "grpc://127.0.0.1:2")
+
+		_, err := resolver.Resolve(context.Background(), "alice")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unhealthy")
+	})
+}
+
+func TestResolver_LeastLoadedAgent(t *testing.T) {
+	busy := startLoadReportingAgent(t, 8)
+	quiet := startLoadReportingAgent(t, 1)
+
+	resolver := newMultiAgentResolver(t, busy, deadAgentURL, quiet)
+	resolver.SetAgentRouting(domain.AgentRoutingLeastLoaded)
+
+	counts := resolvedAgents(t, resolver, 4)
+	assert.Equal(t, map[string]int{quiet: 4}, counts)
+}
@@ -2,13 +2,15 @@
 package compute
 
 import (
+	"crypto/tls"
 	"database/sql"
 	"sync"
 
 	"duck-demo/internal/domain"
 )
 
-// RemoteCache manages cached RemoteExecutor instances keyed by endpoint ID.
+// RemoteCache manages cached RemoteExecutor instances keyed by endpoint ID
+// and agent URL.
 // It avoids creating duplicate HTTP clients for the same endpoint.
 type RemoteCache struct {
 	mu      sync.RWMutex
@@ -21,6 +23,9 @@ type RemoteCache struct {
 type RemoteExecutorOptions struct {
 	CursorModeEnabled bool
 	InternalGRPC      bool
+	// TLS is used for grpcs:// endpoints; set a client certificate on it to
+	// authenticate to agents that require mutual TLS. Nil uses system roots.
+	TLS *tls.Config
 }
 
 // NewRemoteCache creates a RemoteCache that materializes remote results into
@@ -41,8 +46,16 @@ func NewRemoteCacheWithOptions(localDB *sql.DB, opts RemoteExecutorOptions) *Rem
 // GetOrCreate returns an existing RemoteExecutor for the endpoint or creates a
 // new one. Uses double-checked locking to minimise lock contention.
 func (c *RemoteCache) GetOrCreate(ep *domain.ComputeEndpoint) *RemoteExecutor {
+	return c.GetOrCreateAgent(ep, ep.URL)
+}
+
+// GetOrCreateAgent is GetOrCreate for one agent URL of a multi-agent
+// endpoint. Agents share the endpoint's auth token.
+func (c *RemoteCache) GetOrCreateAgent(ep *domain.ComputeEndpoint, agentURL string) *RemoteExecutor {
+	key := ep.ID + " " + agentURL
+
 	c.mu.RLock()
-	if exec, ok := c.entries[ep.ID]; ok {
+	if exec, ok := c.entries[key]; ok {
 		c.mu.RUnlock()
 		return exec
 	}
@@ -52,11 +65,11 @@ func (c *RemoteCache) GetOrCreate(ep *domain.ComputeEndpoint) *RemoteExecutor {
 	defer c.mu.Unlock()
 
 	// Double-check after acquiring write lock
-	if exec, ok := c.entries[ep.ID]; ok {
+	if exec, ok := c.entries[key]; ok {
 		return exec
 	}
 
-	exec := NewRemoteExecutor(ep.URL, ep.AuthToken, c.localDB, c.opts)
-	c.entries[ep.ID] = exec
+	exec := NewRemoteExecutor(agentURL, ep.AuthToken, c.localDB, c.opts)
+	c.entries[key] = exec
 	return exec
 }
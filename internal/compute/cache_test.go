@@ -50,6 +50,15 @@ func TestRemoteCache_GetOrCreate(t *testing.T) {
 		assert.Equal(t, "https://compute-2.example.com:9443", exec2.endpointURL)
 	})
 
+	t.Run("agents_of_one_endpoint_get_own_executors", func(t *testing.T) {
+		primary := cache.GetOrCreate(ep)
+		replica := cache.GetOrCreateAgent(ep, "https://compute-1b.example.com:9443")
+		assert.NotSame(t, primary, replica)
+		assert.Same(t, primary, cache.GetOrCreateAgent(ep, ep.URL))
+		assert.Equal(t, "https://compute-1b.example.com:9443", replica.endpointURL)
+		assert.Equal(t, "secret", replica.authToken)
+	})
+
 	t.Run("concurrent_access", func(t *testing.T) {
 		concurrentCache := NewRemoteCache(localDB)
 		var wg sync.WaitGroup
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	computeproto "duck-demo/internal/compute/proto"
+	"duck-demo/internal/tracing"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -25,7 +26,7 @@ type grpcWorkerClient struct {
 	authToken string
 }
 
-func newGRPCWorkerClient(endpointURL, authToken string) (*grpcWorkerClient, error) {
+func newGRPCWorkerClient(endpointURL, authToken string, tlsConfig *tls.Config) (*grpcWorkerClient, error) {
 	EnsureGRPCJSONCodec()
 
 	target, secure, err := grpcDialTarget(endpointURL)
@@ -35,7 +36,10 @@ func newGRPCWorkerClient(endpointURL, authToken string) (*grpcWorkerClient, erro
 
 	creds := insecure.NewCredentials()
 	if secure {
-		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		creds = credentials.NewTLS(tlsConfig)
 	}
 
 	conn, err := grpc.NewClient(target,
@@ -72,7 +76,7 @@ func (c *grpcWorkerClient) withMetadata(ctx context.Context, requestID string) c
 	if requestID != "" {
 		pairs = append(pairs, "x-request-id", requestID)
 	}
-	return metadata.NewOutgoingContext(ctx, metadata.Pairs(pairs...))
+	return tracing.InjectMetadata(metadata.NewOutgoingContext(ctx, metadata.Pairs(pairs...)))
 }
 
 func (c *grpcWorkerClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
@@ -91,6 +95,7 @@ func (c *grpcWorkerClient) execute(ctx context.Context, req ExecuteRequest) (Exe
 		Context: &computeproto.RequestContext{
 			RequestId: req.RequestID,
 		},
+		TimeoutSeconds: req.TimeoutSeconds,
 	})
 	if err != nil {
 		return ExecuteResponse{}, err
@@ -107,6 +112,7 @@ func (c *grpcWorkerClient) submitQuery(ctx context.Context, req SubmitQueryReque
 		Context: &computeproto.RequestContext{
 			RequestId: req.RequestID,
 		},
+		TimeoutSeconds: req.TimeoutSeconds,
 	})
 	if err != nil {
 		return SubmitQueryResponse{}, err
@@ -260,6 +266,8 @@ func healthResponseFromProto(resp *computeproto.HealthResponse) HealthResponse {
 		StoredJobs:            resp.StoredJobs,
 		CleanedJobs:           resp.CleanedJobs,
 		QueryResultTTLSeconds: int(resp.ResultTtlSecs),
+		MaxConcurrentQueries:  resp.MaxConcurrentQueries,
+		WaitingQueries:        resp.WaitingQueries,
 	}
 }
 
@@ -6,8 +6,9 @@ type RequestContext struct {
 }
 
 type ExecuteRequest struct {
-	Sql     string          `json:"sql,omitempty"`
-	Context *RequestContext `json:"context,omitempty"`
+	Sql            string          `json:"sql,omitempty"`
+	Context        *RequestContext `json:"context,omitempty"`
+	TimeoutSeconds int64           `json:"timeout_seconds,omitempty"`
 }
 
 type ExecuteResponse struct {
@@ -17,8 +18,9 @@ type ExecuteResponse struct {
 }
 
 type SubmitQueryRequest struct {
-	Sql     string          `json:"sql,omitempty"`
-	Context *RequestContext `json:"context,omitempty"`
+	Sql            string          `json:"sql,omitempty"`
+	Context        *RequestContext `json:"context,omitempty"`
+	TimeoutSeconds int64           `json:"timeout_seconds,omitempty"`
 }
 
 type SubmitQueryResponse struct {
@@ -90,4 +92,7 @@ type HealthResponse struct {
 	MemoryUsedMb  int64  `json:"memory_used_mb,omitempty"`
 	MaxMemoryGb   int32  `json:"max_memory_gb,omitempty"`
 	ResultTtlSecs int32  `json:"query_result_ttl_seconds,omitempty"`
+
+	MaxConcurrentQueries int64 `json:"max_concurrent_queries,omitempty"`
+	WaitingQueries       int64 `json:"waiting_queries,omitempty"`
 }
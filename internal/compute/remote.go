@@ -3,6 +3,7 @@ package compute
 import (
 	"context"
 	"crypto/rand"
+	"crypto/tls"
 	"database/sql"
 	"encoding/hex"
 	"fmt"
@@ -11,8 +12,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"duck-demo/internal/domain"
+	"duck-demo/internal/tracing"
 )
 
 var _ domain.ComputeExecutor = (*RemoteExecutor)(nil)
@@ -24,6 +28,7 @@ type RemoteExecutor struct {
 	authToken   string
 	localDB     *sql.DB // for temp table materialization
 	cursorMode  bool
+	tlsConfig   *tls.Config
 	grpcClient  *grpcWorkerClient
 	grpcMu      sync.Mutex
 }
@@ -41,20 +46,34 @@ func NewRemoteExecutor(endpointURL, authToken string, localDB *sql.DB, opts ...R
 		authToken:   authToken,
 		localDB:     localDB,
 		cursorMode:  options.CursorModeEnabled,
+		tlsConfig:   options.TLS,
 	}
 }
 
 // QueryContext sends the query to the remote agent and materializes the result
 // into a local DuckDB temp table, returning *sql.Rows from that table.
-func (e *RemoteExecutor) QueryContext(ctx context.Context, query string) (*sql.Rows, error) {
+func (e *RemoteExecutor) QueryContext(ctx context.Context, query string) (_ *sql.Rows, err error) {
 	requestID := uuid.New().String()
+	ctx, span := tracing.Start(ctx, "compute.remote_execute",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("compute.endpoint", e.endpointURL),
+			attribute.String("compute.request_id", requestID),
+		),
+	)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	client, err := e.ensureGRPCClient()
 	if err != nil {
 		return nil, err
 	}
 
+	timeoutSeconds := queryTimeoutSeconds(ctx)
 	if e.cursorMode {
-		rows, lifecycleErr := e.queryViaGRPCLifecycleToRows(ctx, client, query, requestID)
+		rows, lifecycleErr := e.queryViaGRPCLifecycleToRows(ctx, client, query, requestID, timeoutSeconds)
 		if lifecycleErr == nil {
 			return rows, nil
 		}
@@ -63,7 +82,7 @@ func (e *RemoteExecutor) QueryContext(ctx context.Context, query string) (*sql.R
 		}
 	}
 
-	result, err := client.execute(ctx, ExecuteRequest{SQL: query, RequestID: requestID})
+	result, err := client.execute(ctx, ExecuteRequest{SQL: query, RequestID: requestID, TimeoutSeconds: timeoutSeconds})
 	if err != nil {
 		return nil, fmt.Errorf("grpc execute: %w", err)
 	}
@@ -73,8 +92,19 @@ func (e *RemoteExecutor) QueryContext(ctx context.Context, query string) (*sql.R
 	return e.materialize(ctx, result)
 }
 
-func (e *RemoteExecutor) queryViaGRPCLifecycleToRows(ctx context.Context, client *grpcWorkerClient, query, requestID string) (*sql.Rows, error) {
-	submitResp, err := client.submitQuery(ctx, SubmitQueryRequest{SQL: query, RequestID: requestID})
+// queryTimeoutSeconds forwards the caller's context deadline to the agent as a
+// per-query timeout. It rounds down to whole seconds so the agent gives up no
+// later than the caller, and returns 0 when ctx has no deadline.
+func queryTimeoutSeconds(ctx context.Context) int64 {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	return max(int64(time.Until(deadline)/time.Second), 1)
+}
+
+func (e *RemoteExecutor) queryViaGRPCLifecycleToRows(ctx context.Context, client *grpcWorkerClient, query, requestID string, timeoutSeconds int64) (*sql.Rows, error) {
+	submitResp, err := client.submitQuery(ctx, SubmitQueryRequest{SQL: query, RequestID: requestID, TimeoutSeconds: timeoutSeconds})
 	if err != nil {
 		return nil, fmt.Errorf("submit grpc query lifecycle request: %w", err)
 	}
@@ -167,7 +197,7 @@ func (e *RemoteExecutor) ensureGRPCClient() (*grpcWorkerClient, error) {
 	if e.grpcClient != nil {
 		return e.grpcClient, nil
 	}
-	client, err := newGRPCWorkerClient(e.endpointURL, e.authToken)
+	client, err := newGRPCWorkerClient(e.endpointURL, e.authToken, e.tlsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -270,14 +300,21 @@ func insertResultRows(ctx context.Context, conn *sql.Conn, tableName string, row
 
 // Ping performs a health check against the remote agent.
 func (e *RemoteExecutor) Ping(ctx context.Context) error {
+	_, err := e.health(ctx)
+	return err
+}
+
+// health returns the agent's health report, including its current load.
+func (e *RemoteExecutor) health(ctx context.Context) (HealthResponse, error) {
 	client, err := e.ensureGRPCClient()
 	if err != nil {
-		return err
+		return HealthResponse{}, err
 	}
-	if _, err := client.health(ctx); err != nil {
-		return fmt.Errorf("grpc health check: %w", err)
+	resp, err := client.health(ctx)
+	if err != nil {
+		return HealthResponse{}, fmt.Errorf("grpc health check: %w", err)
 	}
-	return nil
+	return resp, nil
 }
 
 // randomSuffix generates a cryptographically random hex suffix for temp table names.
@@ -58,6 +58,42 @@ func TestRemoteExecutor_QueryContext_GRPC(t *testing.T) {
 	require.NoError(t, rows.Err())
 }
 
+func TestRemoteExecutor_QueryContext_GRPCForwardsDeadline(t *testing.T) {
+	grpcAgent := agent.NewComputeGRPCServer(agent.HandlerConfig{
+		DB:             openDuckDB(t),
+		AgentToken:     "tok",
+		StartTime:      time.Now(),
+		QueryResultTTL: time.Minute,
+		CursorMode:     true,
+		Logger:         slog.Default(),
+	})
+
+	compute.EnsureGRPCJSONCodec()
+	grpcServer := grpc.NewServer()
+	agent.RegisterComputeWorkerGRPCServer(grpcServer, grpcAgent)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		grpcServer.Stop()
+		_ = ln.Close()
+	})
+	go func() {
+		_ = grpcServer.Serve(ln)
+	}()
+
+	exec := compute.NewRemoteExecutor("grpc://"+ln.Addr().String(), "tok", openDuckDB(t), compute.RemoteExecutorOptions{
+		CursorModeEnabled: true,
+		InternalGRPC:      true,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err = exec.QueryContext(ctx, "SELECT sum(hash(i)) FROM range(100000000000) t(i)")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), agent.TimeoutErrorCode)
+}
+
 func openDuckDB(t *testing.T) *sql.DB {
 	t.Helper()
 	db, err := sql.Open("duckdb", "")
@@ -7,8 +7,12 @@ import (
 	"log/slog"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	computerouter "duck-demo/internal/compute/router"
 	"duck-demo/internal/domain"
+	"duck-demo/internal/tracing"
 )
 
 const assignmentLookupPageSize = 200
@@ -28,6 +32,8 @@ type DefaultResolver struct {
 	logger         *slog.Logger
 	routingEnabled bool
 	canaryUsers    map[string]struct{}
+	agentRepo      domain.ComputeEndpointAgentRepository
+	agents         *agentRouter
 }
 
 // NewResolver creates a fully-wired resolver that can resolve principals to
@@ -50,6 +56,7 @@ func NewResolver(
 		logger:         logger,
 		routingEnabled: true,
 		canaryUsers:    map[string]struct{}{},
+		agents:         newAgentRouter(domain.AgentRoutingRoundRobin),
 	}
 }
 
@@ -72,6 +79,19 @@ func (r *DefaultResolver) SetCanaryUsers(users []string) {
 	r.canaryUsers = allow
 }
 
+// SetAgentRepository enables routing across the additional agent URLs of
+// multi-agent endpoints. Without it only each endpoint's own URL is used.
+func (r *DefaultResolver) SetAgentRepository(repo domain.ComputeEndpointAgentRepository) {
+	r.agentRepo = repo
+}
+
+// SetAgentRouting selects how queries are spread across the agents of an
+// endpoint: domain.AgentRoutingRoundRobin (default) or
+// domain.AgentRoutingLeastLoaded.
+func (r *DefaultResolver) SetAgentRouting(policy string) {
+	r.agents = newAgentRouter(policy)
+}
+
 // Resolve maps a principal name to a ComputeExecutor. Returns nil when no
 // compute endpoint is assigned (engine falls back to local *sql.DB).
 //
@@ -79,7 +99,13 @@ func (r *DefaultResolver) SetCanaryUsers(users []string) {
 //  1. Direct user assignment (is_default=true, status=ACTIVE)
 //  2. Group assignments (check each group the user belongs to)
 //  3. nil (local fallback)
-func (r *DefaultResolver) Resolve(ctx context.Context, principalName string) (domain.ComputeExecutor, error) {
+func (r *DefaultResolver) Resolve(ctx context.Context, principalName string) (_ domain.ComputeExecutor, err error) {
+	ctx, span := tracing.Start(ctx, "compute.resolve")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	if !r.routingEnabled {
 		return nil, nil
 	}
@@ -197,8 +223,13 @@ func (r *DefaultResolver) selectFromAssignments(ctx context.Context, principalID
 
 // resolveEndpoint returns a ComputeExecutor for the given endpoint.
 // For LOCAL endpoints, returns the local executor.
-// For REMOTE endpoints, returns a cached RemoteExecutor after a health check.
+// For REMOTE endpoints, returns a cached RemoteExecutor for a healthy agent
+// chosen by the agent routing policy.
 func (r *DefaultResolver) resolveEndpoint(ctx context.Context, ep *domain.ComputeEndpoint) (domain.ComputeExecutor, error) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("compute.endpoint", ep.Name),
+		attribute.String("compute.endpoint_type", ep.Type),
+	)
 	if ep.Type == "LOCAL" {
 		return r.localExec, nil
 	}
@@ -207,10 +238,18 @@ func (r *DefaultResolver) resolveEndpoint(ctx context.Context, ep *domain.Comput
 		return nil, fmt.Errorf("remote cache not configured for endpoint %q", ep.Name)
 	}
 
-	remote := r.cache.GetOrCreate(ep)
+	urls, err := r.agentURLs(ctx, ep)
+	if err != nil {
+		return nil, err
+	}
+	agents := make([]*RemoteExecutor, len(urls))
+	for i, u := range urls {
+		agents[i] = r.cache.GetOrCreateAgent(ep, u)
+	}
 
-	// Health check
-	if err := remote.Ping(ctx); err != nil {
+	// Health check; unhealthy agents are skipped.
+	remote, err := r.agents.pick(ctx, agents, ep.ID)
+	if err != nil {
 		fallbackLocal, lookupErr := r.fallbackLocalEnabled(ctx, ep)
 		if lookupErr != nil {
 			return nil, fmt.Errorf("resolve assignment fallback policy for endpoint %q: %w", ep.Name, lookupErr)
@@ -227,6 +266,7 @@ func (r *DefaultResolver) resolveEndpoint(ctx context.Context, ep *domain.Comput
 		return nil, fmt.Errorf("remote agent %q unhealthy: %w", ep.Name, err)
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("compute.agent", remote.endpointURL))
 	return remote, nil
 }
 
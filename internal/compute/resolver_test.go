@@ -74,6 +74,9 @@ func (m *mockPrincipalRepo) GetByExternalID(_ context.Context, _, _ string) (*do
 func (m *mockPrincipalRepo) BindExternalID(_ context.Context, _ string, _ string, _ string) error {
 	panic("unexpected")
 }
+func (m *mockPrincipalRepo) Import(_ context.Context, _ []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+	panic("unexpected")
+}
 
 type mockGroupRepo struct {
 	getGroupsForMemberFn func(ctx context.Context, memberType string, memberID string) ([]domain.Group, error)
@@ -101,6 +104,9 @@ func (m *mockGroupRepo) RemoveMember(_ context.Context, _ *domain.GroupMember) e
 func (m *mockGroupRepo) ListMembers(_ context.Context, _ string, _ domain.PageRequest) ([]domain.GroupMember, int64, error) {
 	panic("unexpected")
 }
+func (m *mockGroupRepo) SyncMembers(_ context.Context, _ string, _ []domain.GroupMember) (*domain.GroupMembershipChanges, error) {
+	panic("unexpected")
+}
 func (m *mockGroupRepo) GetGroupsForMember(ctx context.Context, memberType string, memberID string) ([]domain.Group, error) {
 	if m.getGroupsForMemberFn != nil {
 		return m.getGroupsForMemberFn(ctx, memberType, memberID)
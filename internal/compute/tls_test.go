@@ -0,0 +1,156 @@
+package compute_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"duck-demo/internal/agent"
+	"duck-demo/internal/compute"
+)
+
+func TestRemoteExecutor_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := writePEM(t, dir, "ca.crt", "CERTIFICATE", ca.cert.Raw)
+	serverCert, serverKey := ca.issue(t, dir, "agent", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, dir, "control-plane", x509.ExtKeyUsageClientAuth)
+
+	serverTLS, err := compute.ServerTLSConfig(serverCert, serverKey, caFile)
+	require.NoError(t, err)
+
+	compute.EnsureGRPCJSONCodec()
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverTLS)))
+	agent.RegisterComputeWorkerGRPCServer(grpcServer, agent.NewComputeGRPCServer(agent.HandlerConfig{
+		DB:         openDuckDB(t),
+		AgentToken: "tok",
+		StartTime:  time.Now(),
+		Logger:     slog.Default(),
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		grpcServer.Stop()
+		_ = ln.Close()
+	})
+	go func() {
+		_ = grpcServer.Serve(ln)
+	}()
+	endpoint := "grpcs://" + ln.Addr().String()
+
+	t.Run("client_certificate_accepted", func(t *testing.T) {
+		clientTLS, err := compute.ClientTLSConfig(clientCert, clientKey, caFile)
+		require.NoError(t, err)
+		exec := compute.NewRemoteExecutor(endpoint, "tok", openDuckDB(t), compute.RemoteExecutorOptions{
+			InternalGRPC: true,
+			TLS:          clientTLS,
+		})
+
+		rows, err := exec.QueryContext(context.Background(), "SELECT 42 AS answer")
+		require.NoError(t, err)
+		defer func() { _ = rows.Close() }()
+		require.True(t, rows.Next())
+		var answer string
+		require.NoError(t, rows.Scan(&answer))
+		assert.Equal(t, "42", answer)
+	})
+
+	t.Run("missing_client_certificate_rejected", func(t *testing.T) {
+		clientTLS, err := compute.ClientTLSConfig("", "", caFile)
+		require.NoError(t, err)
+		exec := compute.NewRemoteExecutor(endpoint, "tok", openDuckDB(t), compute.RemoteExecutorOptions{
+			InternalGRPC: true,
+			TLS:          clientTLS,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.Error(t, exec.Ping(ctx))
+	})
+
+	t.Run("untrusted_client_certificate_rejected", func(t *testing.T) {
+		rogue := newTestCA(t)
+		rogueCert, rogueKey := rogue.issue(t, t.TempDir(), "intruder", x509.ExtKeyUsageClientAuth)
+		clientTLS, err := compute.ClientTLSConfig(rogueCert, rogueKey, caFile)
+		require.NoError(t, err)
+		exec := compute.NewRemoteExecutor(endpoint, "tok", openDuckDB(t), compute.RemoteExecutorOptions{
+			InternalGRPC: true,
+			TLS:          clientTLS,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.Error(t, exec.Ping(ctx))
+	})
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue signs a leaf certificate valid for 127.0.0.1 and writes it and its
+// key to dir, returning both paths.
+func (ca *testCA) issue(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return writePEM(t, dir, name+".crt", "CERTIFICATE", der), writePEM(t, dir, name+".key", "EC PRIVATE KEY", keyDER)
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
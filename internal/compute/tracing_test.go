@@ -0,0 +1,108 @@
+package compute_test
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+
+	"duck-demo/internal/agent"
+	"duck-demo/internal/compute"
+	"duck-demo/internal/middleware"
+	"duck-demo/internal/tracing"
+)
+
+// TestTracing_PropagatesToComputeAgent drives a query through an HTTP route
+// into a remote executor and verifies the agent's spans continue the server's
+// trace: server span → remote execute span → agent span → agent query job.
+func TestTracing_PropagatesToComputeAgent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	_, err := tracing.Setup(context.Background(), tracing.Config{})
+	require.NoError(t, err)
+
+	grpcAgent := agent.NewComputeGRPCServer(agent.HandlerConfig{
+		DB:         openDuckDB(t),
+		AgentToken: "tok",
+		StartTime:  time.Now(),
+		CursorMode: true,
+		Logger:     slog.Default(),
+	})
+	compute.EnsureGRPCJSONCodec()
+	grpcServer := grpc.NewServer()
+	agent.RegisterComputeWorkerGRPCServer(grpcServer, grpcAgent)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		grpcServer.GracefulStop()
+		_ = ln.Close()
+	})
+	go func() { _ = grpcServer.Serve(ln) }()
+
+	exec := compute.NewRemoteExecutor("grpc://"+ln.Addr().String(), "tok", openDuckDB(t), compute.RemoteExecutorOptions{
+		CursorModeEnabled: true,
+		InternalGRPC:      true,
+	})
+
+	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
+	r.Use(middleware.RequestID)
+	r.Post("/v1/query", func(w http.ResponseWriter, req *http.Request) {
+		rows, err := exec.QueryContext(req.Context(), "SELECT 42 AS answer")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = rows.Close()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/query", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	// The agent's query job ends asynchronously; wait for it to be recorded.
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	require.Eventually(t, func() bool {
+		for _, s := range recorder.Ended() {
+			byName[s.Name()] = s
+		}
+		_, ok := byName["agent.run_query"]
+		return ok
+	}, 5*time.Second, 20*time.Millisecond)
+
+	server := byName["POST /v1/query"]
+	remote := byName["compute.remote_execute"]
+	submit := byName["agent.SubmitQuery"]
+	job := byName["agent.run_query"]
+	require.NotNil(t, server)
+	require.NotNil(t, remote)
+	require.NotNil(t, submit)
+
+	traceID := server.SpanContext().TraceID()
+	for _, s := range []sdktrace.ReadOnlySpan{remote, submit, job} {
+		assert.Equal(t, traceID, s.SpanContext().TraceID(), "span %s should share the server trace", s.Name())
+	}
+	assert.Equal(t, server.SpanContext().SpanID(), remote.Parent().SpanID())
+	assert.Equal(t, remote.SpanContext().SpanID(), submit.Parent().SpanID())
+	assert.True(t, submit.Parent().IsRemote(), "agent span should continue a propagated context")
+	assert.Equal(t, submit.SpanContext().SpanID(), job.Parent().SpanID())
+
+	// Without a client-supplied request ID, the request ID is the trace ID.
+	assert.Equal(t, traceID.String(), rec.Header().Get("X-Request-ID"))
+}
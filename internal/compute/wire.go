@@ -11,6 +11,8 @@ import (
 type ExecuteRequest struct {
 	SQL       string `json:"sql"`
 	RequestID string `json:"request_id"`
+	// TimeoutSeconds bounds query execution on the agent; zero means no limit.
+	TimeoutSeconds int64 `json:"timeout_seconds,omitempty"`
 }
 
 // ExecuteResponse is the payload returned by ComputeWorker.Execute on success.
@@ -41,8 +43,9 @@ const (
 
 // SubmitQueryRequest creates a query job on the compute agent.
 type SubmitQueryRequest struct {
-	SQL       string `json:"sql"`
-	RequestID string `json:"request_id,omitempty"`
+	SQL            string `json:"sql"`
+	RequestID      string `json:"request_id,omitempty"`
+	TimeoutSeconds int64  `json:"timeout_seconds,omitempty"`
 }
 
 // GetQueryStatusRequest requests current query lifecycle status.
@@ -119,6 +122,8 @@ type HealthResponse struct {
 	StoredJobs            int64  `json:"stored_jobs,omitempty"`
 	CleanedJobs           int64  `json:"cleaned_jobs,omitempty"`
 	QueryResultTTLSeconds int    `json:"query_result_ttl_seconds,omitempty"`
+	MaxConcurrentQueries  int64  `json:"max_concurrent_queries,omitempty"`
+	WaitingQueries        int64  `json:"waiting_queries,omitempty"`
 }
 
 // DecodePageToken converts opaque page token into an integer offset.
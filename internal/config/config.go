@@ -4,11 +4,15 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"duck-demo/internal/domain"
 )
 
 // AuthConfig holds authentication and identity provider configuration.
@@ -26,8 +30,9 @@ type AuthConfig struct {
 	APIKeyHeader  string // Header name for API keys (default: X-API-Key)
 
 	// JIT provisioning
-	NameClaim      string // JWT claim for principal name (default: "email")
-	BootstrapAdmin string // External ID (sub) of the bootstrap admin user
+	NameClaim       string            // JWT claim for principal name (default: "email")
+	BootstrapAdmin  string            // External ID (sub) of the bootstrap admin user
+	AttributeClaims map[string]string // principal attribute key -> JWT claim copied on JIT provisioning
 }
 
 // OIDCEnabled returns true when an external identity provider is configured.
@@ -63,14 +68,67 @@ type Config struct {
 	PGWireAddr        string // PostgreSQL wire listen address (default ":5433")
 	EncryptionKey     string // 64-char hex string (32-byte AES key) for encrypting stored credentials
 	LogLevel          string // log level: debug, info, warn, error (default "info")
+	LogFormat         string // log format: text or json (default "text", "json" in production)
 	Env               string // environment: "development" (default) or "production"
 
+	// Metastore SQLite pools
+	MetaDBReadPath     string        // optional replica of MetaDBPath for stale-tolerant reads
+	MetaDBReadPoolSize int           // read pool size (default 4)
+	MetaDBBusyTimeout  time.Duration // SQLite busy_timeout before a "metastore busy" error (default 5s)
+	MetaDBCacheSizeKiB int           // per-connection page cache in KiB (default: SQLite's own)
+	MigrateOnStart     bool          // apply pending migrations at startup (default true)
+
+	// Catalog attach at startup
+	RequireCatalog        bool          // exit when a registered catalog cannot be attached (default false)
+	CatalogAttachAttempts int           // attach attempts per catalog before giving up (default 3)
+	CatalogAttachBackoff  time.Duration // wait before the first retry, doubling after each (default 1s)
+
 	// Rate limiting
 	RateLimitRPS   float64 // sustained requests per second (default 100)
 	RateLimitBurst int     // burst capacity (default 200)
 
+	// Response compression
+	CompressionMinSize int // smallest response body, in bytes, that is gzip/deflate-encoded (default 1024)
+
+	// Idempotency
+	IdempotencyKeyTTL time.Duration // how long POST responses are replayed for an Idempotency-Key (default 24h)
+
+	// Pagination
+	DefaultPageSize int // page size of list endpoints when max_results is unset (default 100)
+	MaxPageSize     int // largest page a list endpoint returns; larger max_results are clamped (default 1000)
+
+	// Async queries
+	QueryJobTTL time.Duration // how long finished async query jobs and their results are kept (default 24h)
+
+	// Model tests
+	ModelTestFailuresTTL time.Duration // how long rows stored by store_failures are kept (default 168h)
+
+	// Query history
+	QueryHistoryRetentionDays int // days of query history kept before the background purge deletes it (default 0: keep forever)
+
+	// Audit log archiving
+	AuditRetentionDays     int    // days of audit log kept before it is archived and deleted (default 0: keep forever)
+	AuditArchiveLocation   string // object store URL archives are written below, e.g. s3://bucket/audit/
+	AuditArchiveCredential string // name of the storage credential used to write archives
+
+	// DuckDB extensions
+	DuckDBExtensionAllowlist []string // extensions the engine may install and load (default: ducklake, sqlite, httpfs, postgres, azure)
+
+	// Dual control
+	ApprovalRequiredOperations []string // admin operations held until a second admin approves (set_admin, delete_catalog)
+
 	// CORS
-	CORSAllowedOrigins []string // allowed origins for CORS (default: ["*"])
+	CORSAllowedOrigins   []string // origins browsers may call the API from; empty disables CORS (default)
+	CORSAllowedMethods   []string // methods allowed in cross-origin requests (default: GET, POST, PUT, PATCH, DELETE, OPTIONS)
+	CORSAllowedHeaders   []string // request headers allowed in cross-origin requests (default includes Authorization and X-API-Key)
+	CORSAllowCredentials bool     // allow cookies and HTTP auth in cross-origin requests (default false)
+
+	// Metrics
+	MetricsEnabled      bool // serve Prometheus metrics on /metrics (default true)
+	MetricsAuthRequired bool // require authentication for /metrics (default true)
+
+	// Tracing
+	OTLPEndpoint string // OTLP/HTTP trace collector URL; tracing is off when empty
 
 	// Auth holds identity provider and authentication configuration.
 	Auth AuthConfig
@@ -83,6 +141,16 @@ type Config struct {
 	FeatureFlightSQL     bool
 	FeaturePGWire        bool
 	RemoteCanaryUsers    []string
+	// ComputeAgentRouting picks an agent for endpoints backed by several
+	// agents: "round_robin" (default) or "least_loaded".
+	ComputeAgentRouting string
+
+	// Compute-plane TLS for grpcs:// agent endpoints: the client certificate
+	// presented to agents that require mutual TLS and the CA that signed the
+	// agents' certificates. All optional.
+	ComputeTLSCertFile string
+	ComputeTLSKeyFile  string
+	ComputeTLSCAFile   string
 
 	// Warnings collects non-fatal warnings generated during config loading.
 	// These are logged by the caller after the logger is initialised.
@@ -103,6 +171,16 @@ func (c *Config) SlogLevel() slog.Level {
 	}
 }
 
+// NewLogger returns a logger writing to w in the configured LogFormat at the
+// configured LogLevel.
+func (c *Config) NewLogger(w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: c.SlogLevel()}
+	if strings.EqualFold(c.LogFormat, "json") {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
 // IsProduction returns true when the server is running in production mode.
 func (c *Config) IsProduction() bool {
 	return strings.EqualFold(c.Env, "production")
@@ -119,13 +197,20 @@ func (c *Config) HasS3Config() bool {
 func LoadFromEnv() (*Config, error) {
 	cfg := &Config{
 		MetaDBPath:           os.Getenv("META_DB_PATH"),
+		MetaDBReadPath:       os.Getenv("META_DB_READ_PATH"),
 		ListenAddr:           os.Getenv("LISTEN_ADDR"),
 		TLSCertFile:          os.Getenv("TLS_CERT_FILE"),
 		TLSKeyFile:           os.Getenv("TLS_KEY_FILE"),
+		ComputeTLSCertFile:   os.Getenv("COMPUTE_TLS_CERT_FILE"),
+		ComputeTLSKeyFile:    os.Getenv("COMPUTE_TLS_KEY_FILE"),
+		ComputeTLSCAFile:     os.Getenv("COMPUTE_TLS_CA_FILE"),
+		ComputeAgentRouting:  strings.ToLower(strings.TrimSpace(os.Getenv("COMPUTE_AGENT_ROUTING"))),
 		FlightSQLAddr:        os.Getenv("FLIGHT_SQL_LISTEN_ADDR"),
 		PGWireAddr:           os.Getenv("PG_WIRE_LISTEN_ADDR"),
 		EncryptionKey:        os.Getenv("ENCRYPTION_KEY"),
+		OTLPEndpoint:         os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
 		LogLevel:             os.Getenv("LOG_LEVEL"),
+		LogFormat:            strings.ToLower(strings.TrimSpace(os.Getenv("LOG_FORMAT"))),
 		Env:                  os.Getenv("ENV"),
 		FeatureRemoteRouting: parseBoolEnvDefault("FEATURE_REMOTE_ROUTING", true),
 		FeatureAsyncQueue:    parseBoolEnvDefault("FEATURE_ASYNC_QUEUE", true),
@@ -133,6 +218,39 @@ func LoadFromEnv() (*Config, error) {
 		FeatureInternalGRPC:  parseBoolEnvDefault("FEATURE_INTERNAL_GRPC", true),
 		FeatureFlightSQL:     parseBoolEnvDefault("FEATURE_FLIGHT_SQL", true),
 		FeaturePGWire:        parseBoolEnvDefault("FEATURE_PG_WIRE", true),
+		MetricsEnabled:       parseBoolEnvDefault("METRICS_ENABLED", true),
+		MetricsAuthRequired:  parseBoolEnvDefault("METRICS_AUTH_REQUIRED", true),
+		MigrateOnStart:       parseBoolEnvDefault("MIGRATE_ON_START", true),
+		RequireCatalog:       parseBoolEnvDefault("REQUIRE_CATALOG", false),
+	}
+
+	// Metastore SQLite pools
+	if v := os.Getenv("META_DB_READ_POOL_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.MetaDBReadPoolSize = n
+		}
+	}
+	if v := os.Getenv("META_DB_BUSY_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.MetaDBBusyTimeout = d
+		}
+	}
+	if v := os.Getenv("META_DB_CACHE_SIZE_KB"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.MetaDBCacheSizeKiB = n
+		}
+	}
+
+	// Catalog attach at startup
+	if v := os.Getenv("CATALOG_ATTACH_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.CatalogAttachAttempts = n
+		}
+	}
+	if v := os.Getenv("CATALOG_ATTACH_BACKOFF"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.CatalogAttachBackoff = d
+		}
 	}
 
 	// Rate limiting
@@ -147,6 +265,76 @@ func LoadFromEnv() (*Config, error) {
 		}
 	}
 
+	// Response compression
+	if v := os.Getenv("COMPRESSION_MIN_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.CompressionMinSize = n
+		}
+	}
+
+	// Idempotency
+	if v := os.Getenv("IDEMPOTENCY_KEY_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.IdempotencyKeyTTL = d
+		}
+	}
+
+	// Pagination
+	if v := os.Getenv("DEFAULT_PAGE_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DEFAULT_PAGE_SIZE %q: %w", v, err)
+		}
+		cfg.DefaultPageSize = n
+	}
+	if v := os.Getenv("MAX_PAGE_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_PAGE_SIZE %q: %w", v, err)
+		}
+		cfg.MaxPageSize = n
+	}
+
+	// Async queries
+	if v := os.Getenv("QUERY_JOB_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.QueryJobTTL = d
+		}
+	}
+
+	// Model tests
+	if v := os.Getenv("MODEL_TEST_FAILURES_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.ModelTestFailuresTTL = d
+		}
+	}
+
+	// Query history
+	if v := os.Getenv("QUERY_HISTORY_RETENTION_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid QUERY_HISTORY_RETENTION_DAYS %q: must be a non-negative number of days", v)
+		}
+		cfg.QueryHistoryRetentionDays = n
+	}
+
+	// Audit log archiving
+	if v := os.Getenv("AUDIT_RETENTION_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid AUDIT_RETENTION_DAYS %q: must be a non-negative number of days", v)
+		}
+		cfg.AuditRetentionDays = n
+	}
+	cfg.AuditArchiveLocation = os.Getenv("AUDIT_ARCHIVE_LOCATION")
+	cfg.AuditArchiveCredential = os.Getenv("AUDIT_ARCHIVE_CREDENTIAL")
+	if (cfg.AuditArchiveLocation == "") != (cfg.AuditArchiveCredential == "") {
+		return nil, fmt.Errorf("AUDIT_ARCHIVE_LOCATION and AUDIT_ARCHIVE_CREDENTIAL must be set together")
+	}
+	if cfg.AuditRetentionDays > 0 && cfg.AuditArchiveLocation == "" {
+		return nil, fmt.Errorf("AUDIT_RETENTION_DAYS requires AUDIT_ARCHIVE_LOCATION: audit log entries are only deleted once archived")
+	}
+
 	// S3 fields are optional — only set if present
 	if v := os.Getenv("KEY_ID"); v != "" {
 		cfg.S3KeyID = &v
@@ -164,14 +352,47 @@ func LoadFromEnv() (*Config, error) {
 		cfg.S3Bucket = &v
 	}
 
+	// DuckDB extensions
+	if v := os.Getenv("DUCKDB_EXTENSION_ALLOWLIST"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cfg.DuckDBExtensionAllowlist = append(cfg.DuckDBExtensionAllowlist, name)
+			}
+		}
+	}
+
+	// Dual control
+	if v := os.Getenv("APPROVAL_REQUIRED_OPERATIONS"); v != "" {
+		for _, op := range strings.Split(v, ",") {
+			if op = strings.TrimSpace(op); op != "" {
+				cfg.ApprovalRequiredOperations = append(cfg.ApprovalRequiredOperations, op)
+			}
+		}
+	}
+
 	// CORS
 	if v := os.Getenv("CORS_ALLOWED_ORIGINS"); v != "" {
 		origins := strings.Split(v, ",")
 		for i := range origins {
 			origins[i] = strings.TrimSpace(origins[i])
 		}
-		cfg.CORSAllowedOrigins = origins
+		cfg.CORSAllowedOrigins = compactNonEmpty(origins)
 	}
+	if v := os.Getenv("CORS_ALLOWED_METHODS"); v != "" {
+		methods := strings.Split(v, ",")
+		for i := range methods {
+			methods[i] = strings.ToUpper(strings.TrimSpace(methods[i]))
+		}
+		cfg.CORSAllowedMethods = compactNonEmpty(methods)
+	}
+	if v := os.Getenv("CORS_ALLOWED_HEADERS"); v != "" {
+		headers := strings.Split(v, ",")
+		for i := range headers {
+			headers[i] = strings.TrimSpace(headers[i])
+		}
+		cfg.CORSAllowedHeaders = compactNonEmpty(headers)
+	}
+	cfg.CORSAllowCredentials = parseBoolEnvDefault("CORS_ALLOW_CREDENTIALS", false)
 	if strings.EqualFold(os.Getenv("ALLOW_INSECURE_HTTP"), "true") {
 		cfg.AllowInsecureHTTP = true
 	}
@@ -198,6 +419,13 @@ func LoadFromEnv() (*Config, error) {
 	if v := os.Getenv("AUTH_ALLOWED_ISSUERS"); v != "" {
 		cfg.Auth.AllowedIssuers = strings.Split(v, ",")
 	}
+	if v := os.Getenv("AUTH_ATTRIBUTE_CLAIMS"); v != "" {
+		claims, err := parseAttributeClaims(v)
+		if err != nil {
+			return nil, fmt.Errorf("AUTH_ATTRIBUTE_CLAIMS: %w", err)
+		}
+		cfg.Auth.AttributeClaims = claims
+	}
 	if v := os.Getenv("AUTH_JWKS_CACHE_TTL"); v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
 			cfg.Auth.JWKSCacheTTL = d
@@ -222,12 +450,37 @@ func LoadFromEnv() (*Config, error) {
 	if cfg.MetaDBPath == "" {
 		cfg.MetaDBPath = "ducklake_meta.sqlite"
 	}
+	if cfg.MetaDBReadPath == cfg.MetaDBPath {
+		cfg.MetaDBReadPath = ""
+	}
+	if cfg.MetaDBReadPoolSize <= 0 {
+		cfg.MetaDBReadPoolSize = 4
+	}
+	if cfg.MetaDBBusyTimeout <= 0 {
+		cfg.MetaDBBusyTimeout = 5 * time.Second
+	}
+	if cfg.CatalogAttachAttempts <= 0 {
+		cfg.CatalogAttachAttempts = 3
+	}
+	if cfg.CatalogAttachBackoff <= 0 {
+		cfg.CatalogAttachBackoff = time.Second
+	}
 	if cfg.ListenAddr == "" {
 		cfg.ListenAddr = ":8080"
 	}
 	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
 		return nil, fmt.Errorf("both TLS_CERT_FILE and TLS_KEY_FILE must be set together")
 	}
+	if (cfg.ComputeTLSCertFile == "") != (cfg.ComputeTLSKeyFile == "") {
+		return nil, fmt.Errorf("both COMPUTE_TLS_CERT_FILE and COMPUTE_TLS_KEY_FILE must be set together")
+	}
+	switch cfg.ComputeAgentRouting {
+	case "":
+		cfg.ComputeAgentRouting = "round_robin"
+	case "round_robin", "least_loaded":
+	default:
+		return nil, fmt.Errorf("COMPUTE_AGENT_ROUTING must be round_robin or least_loaded, got %q", cfg.ComputeAgentRouting)
+	}
 	if cfg.FlightSQLAddr == "" {
 		cfg.FlightSQLAddr = ":32010"
 	}
@@ -244,14 +497,56 @@ func LoadFromEnv() (*Config, error) {
 	if cfg.LogLevel == "" {
 		cfg.LogLevel = "info"
 	}
+	switch strings.ToLower(cfg.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("unknown LOG_LEVEL %q — using info", cfg.LogLevel))
+	}
+	if cfg.LogFormat == "" {
+		cfg.LogFormat = "text"
+		if cfg.IsProduction() {
+			cfg.LogFormat = "json"
+		}
+	}
+	if cfg.LogFormat != "text" && cfg.LogFormat != "json" {
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q: use text or json", cfg.LogFormat)
+	}
 	if cfg.RateLimitRPS == 0 {
 		cfg.RateLimitRPS = 100
 	}
 	if cfg.RateLimitBurst == 0 {
 		cfg.RateLimitBurst = 200
 	}
-	if len(cfg.CORSAllowedOrigins) == 0 {
-		cfg.CORSAllowedOrigins = []string{"*"}
+	if cfg.CompressionMinSize <= 0 {
+		cfg.CompressionMinSize = 1024
+	}
+	if cfg.IdempotencyKeyTTL <= 0 {
+		cfg.IdempotencyKeyTTL = 24 * time.Hour
+	}
+	if cfg.DefaultPageSize == 0 {
+		cfg.DefaultPageSize = domain.DefaultMaxResults
+	}
+	if cfg.MaxPageSize == 0 {
+		cfg.MaxPageSize = max(domain.MaxMaxResults, cfg.DefaultPageSize)
+	}
+	if cfg.DefaultPageSize < 1 || cfg.MaxPageSize < cfg.DefaultPageSize || cfg.MaxPageSize > domain.PageSizeCeiling {
+		return nil, fmt.Errorf("invalid page sizes: need 1 <= DEFAULT_PAGE_SIZE (%d) <= MAX_PAGE_SIZE (%d) <= %d",
+			cfg.DefaultPageSize, cfg.MaxPageSize, domain.PageSizeCeiling)
+	}
+	if cfg.QueryJobTTL <= 0 {
+		cfg.QueryJobTTL = 24 * time.Hour
+	}
+	if cfg.ModelTestFailuresTTL <= 0 {
+		cfg.ModelTestFailuresTTL = 7 * 24 * time.Hour
+	}
+	if len(cfg.CORSAllowedMethods) == 0 {
+		cfg.CORSAllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	}
+	if len(cfg.CORSAllowedHeaders) == 0 {
+		cfg.CORSAllowedHeaders = []string{"Accept", "Authorization", "Content-Type", "If-Match", "X-API-Key", "X-Request-ID", "Idempotency-Key"}
+	}
+	if cfg.CORSAllowCredentials && slices.Contains(cfg.CORSAllowedOrigins, "*") {
+		return nil, fmt.Errorf("CORS_ALLOW_CREDENTIALS cannot be combined with the CORS wildcard origin (*)")
 	}
 
 	// Production mode: insecure defaults are fatal errors.
@@ -262,7 +557,7 @@ func LoadFromEnv() (*Config, error) {
 		if cfg.EncryptionKey == "0000000000000000000000000000000000000000000000000000000000000000" {
 			return nil, fmt.Errorf("ENCRYPTION_KEY must be set in production (ENV=production)")
 		}
-		if len(cfg.CORSAllowedOrigins) == 1 && cfg.CORSAllowedOrigins[0] == "*" {
+		if slices.Contains(cfg.CORSAllowedOrigins, "*") {
 			return nil, fmt.Errorf("CORS wildcard (*) is not allowed in production (ENV=production)")
 		}
 		if cfg.TLSCertFile == "" && !cfg.AllowInsecureHTTP {
@@ -287,6 +582,29 @@ func parseBoolEnvDefault(key string, defaultVal bool) bool {
 	return defaultVal
 }
 
+// parseAttributeClaims parses a comma-separated list of JWT claims to copy
+// into principal attributes. Each entry is either "claim", stored under the
+// claim's own name, or "key=claim" to store the claim under another key.
+func parseAttributeClaims(v string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, entry := range strings.Split(v, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, claim, found := strings.Cut(entry, "=")
+		if !found {
+			claim = key
+		}
+		key, claim = strings.TrimSpace(key), strings.TrimSpace(claim)
+		if key == "" || claim == "" {
+			return nil, fmt.Errorf("invalid entry %q: want claim or key=claim", entry)
+		}
+		out[key] = claim
+	}
+	return out, nil
+}
+
 func compactNonEmpty(values []string) []string {
 	out := make([]string, 0, len(values))
 	for _, v := range values {
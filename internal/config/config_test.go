@@ -1,9 +1,14 @@
 package config
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,6 +57,25 @@ func TestLoadFromEnv_Defaults(t *testing.T) {
 	assert.True(t, cfg.FeatureInternalGRPC)
 	assert.True(t, cfg.FeatureFlightSQL)
 	assert.True(t, cfg.FeaturePGWire)
+	assert.True(t, cfg.MetricsEnabled)
+	assert.True(t, cfg.MetricsAuthRequired)
+	assert.Equal(t, 4, cfg.MetaDBReadPoolSize)
+	assert.Equal(t, 5*time.Second, cfg.MetaDBBusyTimeout)
+	assert.Zero(t, cfg.MetaDBCacheSizeKiB)
+	assert.True(t, cfg.MigrateOnStart)
+}
+
+func TestLoadFromEnv_MetaDBPoolTuning(t *testing.T) {
+	t.Setenv("META_DB_READ_POOL_SIZE", "16")
+	t.Setenv("META_DB_BUSY_TIMEOUT", "250ms")
+	t.Setenv("META_DB_CACHE_SIZE_KB", "65536")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+
+	assert.Equal(t, 16, cfg.MetaDBReadPoolSize)
+	assert.Equal(t, 250*time.Millisecond, cfg.MetaDBBusyTimeout)
+	assert.Equal(t, 65536, cfg.MetaDBCacheSizeKiB)
 }
 
 func TestLoadFromEnv_DistributedFeatureFlags(t *testing.T) {
@@ -79,6 +103,38 @@ func TestLoadFromEnv_DistributedFeatureFlags(t *testing.T) {
 	assert.Equal(t, []string{"alice", "bob", "carol"}, cfg.RemoteCanaryUsers)
 }
 
+func TestLoadFromEnv_ComputeTLSRequiresKeyPair(t *testing.T) {
+	t.Setenv("COMPUTE_TLS_CERT_FILE", "/etc/duck/client.crt")
+	t.Setenv("COMPUTE_TLS_KEY_FILE", "")
+
+	_, err := LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "COMPUTE_TLS_CERT_FILE and COMPUTE_TLS_KEY_FILE")
+
+	t.Setenv("COMPUTE_TLS_KEY_FILE", "/etc/duck/client.key")
+	t.Setenv("COMPUTE_TLS_CA_FILE", "/etc/duck/ca.crt")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/duck/client.key", cfg.ComputeTLSKeyFile)
+	assert.Equal(t, "/etc/duck/ca.crt", cfg.ComputeTLSCAFile)
+}
+
+func TestLoadFromEnv_ComputeAgentRouting(t *testing.T) {
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "round_robin", cfg.ComputeAgentRouting)
+
+	t.Setenv("COMPUTE_AGENT_ROUTING", " Least_Loaded ")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "least_loaded", cfg.ComputeAgentRouting)
+
+	t.Setenv("COMPUTE_AGENT_ROUTING", "random")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "COMPUTE_AGENT_ROUTING")
+}
+
 func TestLoadFromEnv_NoS3(t *testing.T) {
 	t.Setenv("KEY_ID", "")
 	t.Setenv("SECRET", "")
@@ -216,12 +272,183 @@ func TestLoadFromEnv_RateLimitCustom(t *testing.T) {
 	assert.Equal(t, 100, cfg.RateLimitBurst)
 }
 
-func TestLoadFromEnv_CORSDefaultWildcard(t *testing.T) {
+func TestLoadFromEnv_CompressionMinSize(t *testing.T) {
+	t.Setenv("COMPRESSION_MIN_SIZE", "")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 1024, cfg.CompressionMinSize)
+
+	t.Setenv("COMPRESSION_MIN_SIZE", "4096")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 4096, cfg.CompressionMinSize)
+}
+
+func TestLoadFromEnv_IdempotencyKeyTTL(t *testing.T) {
+	t.Setenv("IDEMPOTENCY_KEY_TTL", "")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, cfg.IdempotencyKeyTTL)
+
+	t.Setenv("IDEMPOTENCY_KEY_TTL", "90m")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, cfg.IdempotencyKeyTTL)
+}
+
+func TestLoadFromEnv_QueryJobTTL(t *testing.T) {
+	t.Setenv("QUERY_JOB_TTL", "")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, cfg.QueryJobTTL)
+
+	t.Setenv("QUERY_JOB_TTL", "2h")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, cfg.QueryJobTTL)
+}
+
+func TestLoadFromEnv_QueryHistoryRetentionDays(t *testing.T) {
+	t.Setenv("QUERY_HISTORY_RETENTION_DAYS", "")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.QueryHistoryRetentionDays)
+
+	t.Setenv("QUERY_HISTORY_RETENTION_DAYS", "90")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 90, cfg.QueryHistoryRetentionDays)
+
+	for _, v := range []string{"-1", "90d"} {
+		t.Setenv("QUERY_HISTORY_RETENTION_DAYS", v)
+		_, err = LoadFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "QUERY_HISTORY_RETENTION_DAYS")
+	}
+}
+
+func TestLoadFromEnv_AuditArchiving(t *testing.T) {
+	t.Setenv("AUDIT_RETENTION_DAYS", "")
+	t.Setenv("AUDIT_ARCHIVE_LOCATION", "")
+	t.Setenv("AUDIT_ARCHIVE_CREDENTIAL", "")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.AuditRetentionDays)
+	assert.Empty(t, cfg.AuditArchiveLocation)
+
+	t.Setenv("AUDIT_RETENTION_DAYS", "365")
+	_, err = LoadFromEnv()
+	require.Error(t, err, "retention without an archive location would delete unarchived entries")
+	assert.Contains(t, err.Error(), "AUDIT_ARCHIVE_LOCATION")
+
+	t.Setenv("AUDIT_ARCHIVE_LOCATION", "s3://audit-bucket/archive/")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be set together")
+
+	t.Setenv("AUDIT_ARCHIVE_CREDENTIAL", "audit-writer")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 365, cfg.AuditRetentionDays)
+	assert.Equal(t, "s3://audit-bucket/archive/", cfg.AuditArchiveLocation)
+	assert.Equal(t, "audit-writer", cfg.AuditArchiveCredential)
+
+	t.Setenv("AUDIT_RETENTION_DAYS", "-1")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AUDIT_RETENTION_DAYS")
+}
+
+func TestLoadFromEnv_ModelTestFailuresTTL(t *testing.T) {
+	t.Setenv("MODEL_TEST_FAILURES_TTL", "")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, cfg.ModelTestFailuresTTL)
+
+	t.Setenv("MODEL_TEST_FAILURES_TTL", "48h")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, cfg.ModelTestFailuresTTL)
+}
+
+func TestLoadFromEnv_PageSizes(t *testing.T) {
+	t.Setenv("DEFAULT_PAGE_SIZE", "")
+	t.Setenv("MAX_PAGE_SIZE", "")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 100, cfg.DefaultPageSize)
+	assert.Equal(t, 1000, cfg.MaxPageSize)
+
+	t.Setenv("DEFAULT_PAGE_SIZE", "50")
+	t.Setenv("MAX_PAGE_SIZE", "200")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 50, cfg.DefaultPageSize)
+	assert.Equal(t, 200, cfg.MaxPageSize)
+
+	for _, tc := range []struct{ def, max string }{
+		{"abc", "200"},
+		{"-1", "200"},
+		{"500", "200"},
+		{"50", "1000000"},
+	} {
+		t.Setenv("DEFAULT_PAGE_SIZE", tc.def)
+		t.Setenv("MAX_PAGE_SIZE", tc.max)
+		_, err = LoadFromEnv()
+		assert.Error(t, err, "DEFAULT_PAGE_SIZE=%s MAX_PAGE_SIZE=%s", tc.def, tc.max)
+	}
+}
+
+func TestLoadFromEnv_DuckDBExtensionAllowlist(t *testing.T) {
+	t.Setenv("DUCKDB_EXTENSION_ALLOWLIST", "ducklake, sqlite,,httpfs ")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ducklake", "sqlite", "httpfs"}, cfg.DuckDBExtensionAllowlist)
+}
+
+func TestLoadFromEnv_ApprovalRequiredOperations(t *testing.T) {
+	t.Setenv("APPROVAL_REQUIRED_OPERATIONS", "set_admin, delete_catalog,")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"set_admin", "delete_catalog"}, cfg.ApprovalRequiredOperations)
+}
+
+func TestLoadFromEnv_CORSDisabledByDefault(t *testing.T) {
 	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+	t.Setenv("CORS_ALLOWED_METHODS", "")
+	t.Setenv("CORS_ALLOWED_HEADERS", "")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "")
 
 	cfg, err := LoadFromEnv()
 	require.NoError(t, err)
-	assert.Equal(t, []string{"*"}, cfg.CORSAllowedOrigins)
+	assert.Empty(t, cfg.CORSAllowedOrigins)
+	assert.Contains(t, cfg.CORSAllowedMethods, "POST")
+	assert.Contains(t, cfg.CORSAllowedHeaders, "Authorization")
+	assert.False(t, cfg.CORSAllowCredentials)
+}
+
+func TestLoadFromEnv_CORSMethodsHeadersAndCredentials(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	t.Setenv("CORS_ALLOWED_METHODS", "get, post")
+	t.Setenv("CORS_ALLOWED_HEADERS", "Authorization, Content-Type")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET", "POST"}, cfg.CORSAllowedMethods)
+	assert.Equal(t, []string{"Authorization", "Content-Type"}, cfg.CORSAllowedHeaders)
+	assert.True(t, cfg.CORSAllowCredentials)
+}
+
+func TestLoadFromEnv_CORSCredentialsRejectWildcard(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "*")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	_, err := LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CORS_ALLOW_CREDENTIALS")
 }
 
 func TestLoadFromEnv_CORSCustomOrigins(t *testing.T) {
@@ -364,3 +591,121 @@ func TestLoadFromEnv_CORSWildcardRejectedInProduction(t *testing.T) {
 	assert.NotEqual(t, []string{"*"}, cfg.CORSAllowedOrigins,
 		"production should not default to wildcard CORS — this allows any website to make authenticated API requests")
 }
+
+func TestLoadFromEnv_AttributeClaims(t *testing.T) {
+	t.Setenv("AUTH_ATTRIBUTE_CLAIMS", "region, dept=department ,team=custom:team")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"region": "region",
+		"dept":   "department",
+		"team":   "custom:team",
+	}, cfg.Auth.AttributeClaims)
+}
+
+func TestLoadFromEnv_AttributeClaimsInvalid(t *testing.T) {
+	t.Setenv("AUTH_ATTRIBUTE_CLAIMS", "region,=department")
+
+	_, err := LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AUTH_ATTRIBUTE_CLAIMS")
+}
+
+func TestLoadFromEnv_LogFormatDefaults(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("ENV", "")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "text", cfg.LogFormat)
+
+	t.Setenv("LOG_FORMAT", "JSON")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "json", cfg.LogFormat)
+}
+
+func TestLoadFromEnv_LogFormatInvalid(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "logfmt")
+
+	_, err := LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "LOG_FORMAT")
+}
+
+func TestLoadFromEnv_UnknownLogLevelWarns(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Contains(t, strings.Join(cfg.Warnings, "\n"), `unknown LOG_LEVEL "verbose"`)
+}
+
+func TestNewLogger_JSONFormatWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := &Config{LogLevel: "debug", LogFormat: "json"}
+	logger := cfg.NewLogger(&buf)
+
+	logger.Debug("migrations applied", "applied", 3, "schema_version", 52)
+	logger.Info("catalog attached at startup", "catalog", "main", "metastore_type", "sqlite")
+	logger.Warn("config warning", "detail", "line\nbreak")
+
+	var lines int
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		lines++
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec), "line %d is not JSON: %s", lines, scanner.Text())
+		assert.Contains(t, rec, "time")
+		assert.Contains(t, rec, "level")
+		assert.Contains(t, rec, "msg")
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, 3, lines)
+}
+
+func TestNewLogger_TextFormatAndLevel(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := &Config{LogLevel: "warn", LogFormat: "text"}
+	logger := cfg.NewLogger(&buf)
+
+	logger.Info("hidden")
+	logger.Warn("shown", "catalog", "main")
+
+	out := buf.String()
+	assert.NotContains(t, out, "hidden")
+	assert.Contains(t, out, "level=WARN msg=shown catalog=main")
+	assert.False(t, json.Valid(bytes.TrimSpace(buf.Bytes())))
+}
+
+func TestLoadFromEnv_CatalogAttach(t *testing.T) {
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.False(t, cfg.RequireCatalog)
+	assert.Equal(t, 3, cfg.CatalogAttachAttempts)
+	assert.Equal(t, time.Second, cfg.CatalogAttachBackoff)
+
+	t.Setenv("REQUIRE_CATALOG", "true")
+	t.Setenv("CATALOG_ATTACH_ATTEMPTS", "5")
+	t.Setenv("CATALOG_ATTACH_BACKOFF", "250ms")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.RequireCatalog)
+	assert.Equal(t, 5, cfg.CatalogAttachAttempts)
+	assert.Equal(t, 250*time.Millisecond, cfg.CatalogAttachBackoff)
+}
+
+func TestLoadFromEnv_MetaDBReadPath(t *testing.T) {
+	t.Setenv("META_DB_PATH", "/data/meta.sqlite")
+	t.Setenv("META_DB_READ_PATH", "/replica/meta.sqlite")
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "/replica/meta.sqlite", cfg.MetaDBReadPath)
+
+	// Pointing the replica at the primary is the same as no replica.
+	t.Setenv("META_DB_READ_PATH", "/data/meta.sqlite")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.MetaDBReadPath)
+}
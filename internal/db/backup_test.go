@@ -0,0 +1,74 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotter_BackupMatchesSource(t *testing.T) {
+	writeDB, readDB := OpenTestSQLite(t)
+
+	_, err := writeDB.ExecContext(ctx, `INSERT INTO principals (name, type, is_admin) VALUES ('alice', 'user', 1), ('bob', 'user', 0)`)
+	require.NoError(t, err)
+	_, err = writeDB.ExecContext(ctx, `INSERT INTO groups (name) VALUES ('analysts')`)
+	require.NoError(t, err)
+
+	dest := filepath.Join(t.TempDir(), "backup.sqlite")
+	require.NoError(t, NewSnapshotter(writeDB).Snapshot(ctx, dest))
+
+	backup, err := OpenSQLite(dest, "read", 1)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backup.Close() })
+
+	tables := tableNames(t, readDB)
+	require.Contains(t, tables, "principals")
+	assert.Equal(t, tables, tableNames(t, backup))
+	for _, table := range tables {
+		assert.Equal(t, rowCount(t, readDB, table), rowCount(t, backup, table), "row count for %s", table)
+	}
+	assert.Equal(t, 2, rowCount(t, backup, "principals"))
+
+	// The backup carries the migration history, so its schema version is known.
+	statuses, err := NewMigrator(backup).Status(ctx)
+	require.NoError(t, err)
+	for _, st := range statuses {
+		assert.True(t, st.Applied, "migration %s should be applied in backup", st.Name)
+	}
+}
+
+func TestSnapshotter_RefusesExistingDestination(t *testing.T) {
+	writeDB, _ := OpenTestSQLite(t)
+
+	dest := filepath.Join(t.TempDir(), "backup.sqlite")
+	require.NoError(t, NewSnapshotter(writeDB).Snapshot(ctx, dest))
+
+	err := NewSnapshotter(writeDB).Snapshot(ctx, dest)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func tableNames(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	rows, err := db.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	require.NoError(t, err)
+	defer rows.Close() //nolint:errcheck
+	var names []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	require.NoError(t, rows.Err())
+	return names
+}
+
+func rowCount(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT count(*) FROM "`+table+`"`).Scan(&n))
+	return n
+}
@@ -11,7 +11,7 @@ import (
 )
 
 const bindExternalID = `-- name: BindExternalID :exec
-UPDATE principals SET external_id = ?, external_issuer = ? WHERE id = ?
+UPDATE principals SET external_id = ?, external_issuer = ?, updated_at = datetime('now') WHERE id = ?
 `
 
 type BindExternalIDParams struct {
@@ -37,9 +37,9 @@ func (q *Queries) CountPrincipals(ctx context.Context) (int64, error) {
 }
 
 const createPrincipal = `-- name: CreatePrincipal :one
-INSERT INTO principals (id, name, type, is_admin)
-VALUES (?, ?, ?, ?)
-RETURNING id, name, type, is_admin, created_at, external_id, external_issuer
+INSERT INTO principals (id, name, type, is_admin, updated_at)
+VALUES (?, ?, ?, ?, datetime('now'))
+RETURNING id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version
 `
 
 type CreatePrincipalParams struct {
@@ -65,14 +65,16 @@ func (q *Queries) CreatePrincipal(ctx context.Context, arg CreatePrincipalParams
 		&i.CreatedAt,
 		&i.ExternalID,
 		&i.ExternalIssuer,
+		&i.UpdatedAt,
+		&i.AttributesVersion,
 	)
 	return i, err
 }
 
 const createPrincipalWithExternalID = `-- name: CreatePrincipalWithExternalID :one
-INSERT INTO principals (id, name, type, is_admin, external_id, external_issuer)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, name, type, is_admin, created_at, external_id, external_issuer
+INSERT INTO principals (id, name, type, is_admin, external_id, external_issuer, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, datetime('now'))
+RETURNING id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version
 `
 
 type CreatePrincipalWithExternalIDParams struct {
@@ -102,6 +104,8 @@ func (q *Queries) CreatePrincipalWithExternalID(ctx context.Context, arg CreateP
 		&i.CreatedAt,
 		&i.ExternalID,
 		&i.ExternalIssuer,
+		&i.UpdatedAt,
+		&i.AttributesVersion,
 	)
 	return i, err
 }
@@ -116,7 +120,7 @@ func (q *Queries) DeletePrincipal(ctx context.Context, id string) error {
 }
 
 const getPrincipal = `-- name: GetPrincipal :one
-SELECT id, name, type, is_admin, created_at, external_id, external_issuer FROM principals WHERE id = ?
+SELECT id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version FROM principals WHERE id = ?
 `
 
 func (q *Queries) GetPrincipal(ctx context.Context, id string) (Principal, error) {
@@ -130,12 +134,14 @@ func (q *Queries) GetPrincipal(ctx context.Context, id string) (Principal, error
 		&i.CreatedAt,
 		&i.ExternalID,
 		&i.ExternalIssuer,
+		&i.UpdatedAt,
+		&i.AttributesVersion,
 	)
 	return i, err
 }
 
 const getPrincipalByExternalID = `-- name: GetPrincipalByExternalID :one
-SELECT id, name, type, is_admin, created_at, external_id, external_issuer FROM principals
+SELECT id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version FROM principals
 WHERE external_issuer IS ? AND external_id = ?
 LIMIT 1
 `
@@ -156,12 +162,14 @@ func (q *Queries) GetPrincipalByExternalID(ctx context.Context, arg GetPrincipal
 		&i.CreatedAt,
 		&i.ExternalID,
 		&i.ExternalIssuer,
+		&i.UpdatedAt,
+		&i.AttributesVersion,
 	)
 	return i, err
 }
 
 const getPrincipalByName = `-- name: GetPrincipalByName :one
-SELECT id, name, type, is_admin, created_at, external_id, external_issuer FROM principals WHERE name = ?
+SELECT id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version FROM principals WHERE name = ?
 `
 
 func (q *Queries) GetPrincipalByName(ctx context.Context, name string) (Principal, error) {
@@ -175,12 +183,14 @@ func (q *Queries) GetPrincipalByName(ctx context.Context, name string) (Principa
 		&i.CreatedAt,
 		&i.ExternalID,
 		&i.ExternalIssuer,
+		&i.UpdatedAt,
+		&i.AttributesVersion,
 	)
 	return i, err
 }
 
 const listPrincipals = `-- name: ListPrincipals :many
-SELECT id, name, type, is_admin, created_at, external_id, external_issuer FROM principals ORDER BY name
+SELECT id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version FROM principals ORDER BY name
 `
 
 func (q *Queries) ListPrincipals(ctx context.Context) ([]Principal, error) {
@@ -200,6 +210,8 @@ func (q *Queries) ListPrincipals(ctx context.Context) ([]Principal, error) {
 			&i.CreatedAt,
 			&i.ExternalID,
 			&i.ExternalIssuer,
+			&i.UpdatedAt,
+			&i.AttributesVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -215,7 +227,7 @@ func (q *Queries) ListPrincipals(ctx context.Context) ([]Principal, error) {
 }
 
 const listPrincipalsPaginated = `-- name: ListPrincipalsPaginated :many
-SELECT id, name, type, is_admin, created_at, external_id, external_issuer FROM principals ORDER BY id LIMIT ? OFFSET ?
+SELECT id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version FROM principals ORDER BY id LIMIT ? OFFSET ?
 `
 
 type ListPrincipalsPaginatedParams struct {
@@ -240,6 +252,134 @@ func (q *Queries) ListPrincipalsPaginated(ctx context.Context, arg ListPrincipal
 			&i.CreatedAt,
 			&i.ExternalID,
 			&i.ExternalIssuer,
+			&i.UpdatedAt,
+			&i.AttributesVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPrincipalsPaginatedByCreatedAt = `-- name: ListPrincipalsPaginatedByCreatedAt :many
+SELECT id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version FROM principals ORDER BY created_at, id LIMIT ? OFFSET ?
+`
+
+type ListPrincipalsPaginatedByCreatedAtParams struct {
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) ListPrincipalsPaginatedByCreatedAt(ctx context.Context, arg ListPrincipalsPaginatedByCreatedAtParams) ([]Principal, error) {
+	rows, err := q.db.QueryContext(ctx, listPrincipalsPaginatedByCreatedAt, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Principal
+	for rows.Next() {
+		var i Principal
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Type,
+			&i.IsAdmin,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.ExternalIssuer,
+			&i.UpdatedAt,
+			&i.AttributesVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPrincipalsPaginatedByName = `-- name: ListPrincipalsPaginatedByName :many
+SELECT id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version FROM principals ORDER BY name, id LIMIT ? OFFSET ?
+`
+
+type ListPrincipalsPaginatedByNameParams struct {
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) ListPrincipalsPaginatedByName(ctx context.Context, arg ListPrincipalsPaginatedByNameParams) ([]Principal, error) {
+	rows, err := q.db.QueryContext(ctx, listPrincipalsPaginatedByName, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Principal
+	for rows.Next() {
+		var i Principal
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Type,
+			&i.IsAdmin,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.ExternalIssuer,
+			&i.UpdatedAt,
+			&i.AttributesVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPrincipalsPaginatedByUpdatedAt = `-- name: ListPrincipalsPaginatedByUpdatedAt :many
+SELECT id, name, type, is_admin, created_at, external_id, external_issuer, updated_at, attributes_version FROM principals ORDER BY updated_at, id LIMIT ? OFFSET ?
+`
+
+type ListPrincipalsPaginatedByUpdatedAtParams struct {
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) ListPrincipalsPaginatedByUpdatedAt(ctx context.Context, arg ListPrincipalsPaginatedByUpdatedAtParams) ([]Principal, error) {
+	rows, err := q.db.QueryContext(ctx, listPrincipalsPaginatedByUpdatedAt, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Principal
+	for rows.Next() {
+		var i Principal
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Type,
+			&i.IsAdmin,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.ExternalIssuer,
+			&i.UpdatedAt,
+			&i.AttributesVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -255,7 +395,7 @@ func (q *Queries) ListPrincipalsPaginated(ctx context.Context, arg ListPrincipal
 }
 
 const setAdmin = `-- name: SetAdmin :exec
-UPDATE principals SET is_admin = ? WHERE id = ?
+UPDATE principals SET is_admin = ?, updated_at = datetime('now') WHERE id = ?
 `
 
 type SetAdminParams struct {
@@ -38,6 +38,24 @@ func parseTime(s string) time.Time {
 	return t
 }
 
+// parseNullTime parses an optional time column; NULL maps to nil.
+func parseNullTime(ns sql.NullString) *time.Time {
+	if !ns.Valid || ns.String == "" {
+		return nil
+	}
+	t := parseTime(ns.String)
+	return &t
+}
+
+// parseUpdatedAt parses an updated_at column added after its table, which is
+// NULL for rows inserted without it; those fall back to created_at.
+func parseUpdatedAt(updatedAt sql.NullString, createdAt string) time.Time {
+	if !updatedAt.Valid || updatedAt.String == "" {
+		return parseTime(createdAt)
+	}
+	return parseTime(updatedAt.String)
+}
+
 func nullStr(s *string) sql.NullString {
 	if s == nil || *s == "" {
 		return sql.NullString{}
@@ -109,6 +127,7 @@ func PrincipalFromDB(p dbstore.Principal) *domain.Principal {
 		ExternalID:     ptrStr(p.ExternalID),
 		ExternalIssuer: ptrStr(p.ExternalIssuer),
 		CreatedAt:      parseTime(p.CreatedAt),
+		UpdatedAt:      parseUpdatedAt(p.UpdatedAt, p.CreatedAt),
 	}
 }
 
@@ -130,6 +149,7 @@ func GroupFromDB(g dbstore.Group) *domain.Group {
 		Name:        g.Name,
 		Description: g.Description.String,
 		CreatedAt:   parseTime(g.CreatedAt),
+		UpdatedAt:   parseUpdatedAt(g.UpdatedAt, g.CreatedAt),
 	}
 }
 
@@ -173,6 +193,7 @@ func GrantFromDB(g dbstore.PrivilegeGrant) *domain.PrivilegeGrant {
 		Privilege:     g.Privilege,
 		GrantedBy:     ptrStr(g.GrantedBy),
 		GrantedAt:     parseTime(g.GrantedAt),
+		ExpiresAt:     parseNullTime(g.ExpiresAt),
 	}
 }
 
@@ -192,6 +213,7 @@ func RowFilterFromDB(f dbstore.RowFilter) *domain.RowFilter {
 	return &domain.RowFilter{
 		ID:          f.ID,
 		TableID:     f.TableID,
+		TagID:       ptrStr(f.TagID),
 		FilterSQL:   f.FilterSql,
 		Description: f.Description.String,
 		CreatedAt:   parseTime(f.CreatedAt),
@@ -234,6 +256,7 @@ func ColumnMaskFromDB(m dbstore.ColumnMask) *domain.ColumnMask {
 		ID:             m.ID,
 		TableID:        m.TableID,
 		ColumnName:     m.ColumnName,
+		TagID:          ptrStr(m.TagID),
 		MaskExpression: m.MaskExpression,
 		Description:    m.Description.String,
 		CreatedAt:      parseTime(m.CreatedAt),
@@ -317,6 +340,22 @@ func AuditEntriesToDBParams(e *domain.AuditEntry) dbstore.InsertAuditLogParams {
 	}
 }
 
+// AuditArchiveFromDB converts a dbstore.AuditArchive to a domain.AuditArchive.
+func AuditArchiveFromDB(a dbstore.AuditArchive) *domain.AuditArchive {
+	return &domain.AuditArchive{
+		ID:             a.ID,
+		Location:       a.Location,
+		EntryCount:     a.EntryCount,
+		FirstEntryID:   a.FirstEntryID,
+		LastEntryID:    a.LastEntryID,
+		FirstCreatedAt: parseTime(a.FirstCreatedAt),
+		LastCreatedAt:  parseTime(a.LastCreatedAt),
+		PrevHash:       a.PrevHash,
+		Hash:           a.Hash,
+		CreatedAt:      parseTime(a.CreatedAt),
+	}
+}
+
 // --- QueryHistory ---
 
 // QueryHistoryEntryFromDB converts a dbstore.AuditLog to a domain.QueryHistoryEntry.
@@ -407,6 +446,7 @@ func NotebookFromDB(n dbstore.Notebook) *domain.Notebook {
 		GitPath:     ptrStr(n.GitPath),
 		CreatedAt:   parseTime(n.CreatedAt),
 		UpdatedAt:   parseTime(n.UpdatedAt),
+		RowVersion:  n.RowVersion,
 	}
 }
 
@@ -515,6 +555,7 @@ func CatalogRegistrationFromDB(c dbstore.Catalog) *domain.CatalogRegistration {
 		Comment:       c.Comment.String,
 		CreatedAt:     parseTime(c.CreatedAt),
 		UpdatedAt:     parseTime(c.UpdatedAt),
+		RowVersion:    c.RowVersion,
 	}
 }
 
@@ -544,6 +585,14 @@ func ViewFromDB(v dbstore.View) *domain.ViewDetail {
 		SourceTables:   sources,
 		CreatedAt:      parseTime(v.CreatedAt),
 		UpdatedAt:      parseTime(v.UpdatedAt),
+		RowVersion:     v.RowVersion,
+
+		Materialized:    v.Materialized != 0,
+		RefreshSchedule: ptrStr(v.RefreshSchedule),
+		LastRefreshedAt: parseNullTime(v.LastRefreshedAt),
+	}
+	if v.CatalogName.Valid {
+		vd.CatalogName = v.CatalogName.String
 	}
 	if v.DeletedAt.Valid {
 		t := parseTime(v.DeletedAt.String)
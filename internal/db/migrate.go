@@ -1,23 +1,262 @@
 package db
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pressly/goose/v3"
+
+	"duck-demo/internal/domain"
 )
 
-// RunMigrations executes all pending goose migrations against the SQLite metastore.
+const migrationsDir = "migrations"
+
+// createChecksumTable tracks the content hash of every applied migration so
+// an edited migration file is detected on the next start.
+const createChecksumTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER PRIMARY KEY,
+	name       TEXT NOT NULL,
+	checksum   TEXT NOT NULL,
+	applied_at TEXT NOT NULL
+)`
+
+// migrationFile is an embedded migration with its content checksum.
+type migrationFile struct {
+	version  int64
+	name     string
+	checksum string
+}
+
+// RunMigrations executes all pending goose migrations against the SQLite
+// metastore. It refuses to run when a previously applied migration no longer
+// matches its recorded checksum.
 func RunMigrations(db *sql.DB) error {
-	goose.SetBaseFS(EmbedMigrations)
+	_, err := NewMigrator(db).Up(context.Background())
+	return err
+}
 
+// Migrator reports and applies metastore schema migrations. It implements
+// domain.MigrationRunner.
+type Migrator struct {
+	db *sql.DB
+}
+
+var _ domain.MigrationRunner = (*Migrator)(nil)
+
+// NewMigrator creates a Migrator for the given write pool.
+func NewMigrator(db *sql.DB) *Migrator {
+	return &Migrator{db: db}
+}
+
+// Status lists every embedded migration with whether it has been applied
+// and whether its checksum has drifted from the applied version.
+func (m *Migrator) Status(ctx context.Context) ([]domain.MigrationStatus, error) {
+	files, err := embeddedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.db.ExecContext(ctx, createChecksumTable); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := m.recordedChecksums(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.MigrationStatus, 0, len(files))
+	for _, f := range files {
+		st := domain.MigrationStatus{Version: f.version, Name: f.name, Checksum: f.checksum}
+		if at, ok := applied[f.version]; ok {
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+		if sum, ok := recorded[f.version]; ok {
+			st.AppliedChecksum = sum
+			st.Drifted = sum != f.checksum
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// Verify returns an error naming every applied migration whose embedded
+// file no longer matches the checksum recorded when it was applied.
+func (m *Migrator) Verify(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	var drifted []string
+	for _, st := range statuses {
+		if st.Drifted {
+			drifted = append(drifted, st.Name)
+		}
+	}
+	if len(drifted) > 0 {
+		return domain.ErrConflict("migration checksum drift: %s changed after being applied", strings.Join(drifted, ", "))
+	}
+	return nil
+}
+
+// Pending returns the number of embedded migrations not yet applied.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, st := range statuses {
+		if !st.Applied {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Up verifies checksums, applies all pending migrations, and records the
+// checksum of every applied migration. It returns the resulting status.
+func (m *Migrator) Up(ctx context.Context) ([]domain.MigrationStatus, error) {
+	if err := m.Verify(ctx); err != nil {
+		return nil, err
+	}
+
+	goose.SetBaseFS(EmbedMigrations)
 	if err := goose.SetDialect("sqlite3"); err != nil {
-		return fmt.Errorf("goose set dialect: %w", err)
+		return nil, fmt.Errorf("goose set dialect: %w", err)
+	}
+	if err := goose.UpContext(ctx, m.db, migrationsDir); err != nil {
+		return nil, fmt.Errorf("goose up: %w", err)
 	}
 
-	if err := goose.Up(db, "migrations"); err != nil {
-		return fmt.Errorf("goose up: %w", err)
+	if err := m.recordChecksums(ctx); err != nil {
+		return nil, err
 	}
+	return m.Status(ctx)
+}
 
+// recordChecksums stores checksums for applied migrations that have none
+// yet: freshly applied ones, and ones applied before checksums were tracked.
+func (m *Migrator) recordChecksums(ctx context.Context) error {
+	files, err := embeddedMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		at, ok := applied[f.version]
+		if !ok {
+			continue
+		}
+		if _, err := m.db.ExecContext(ctx,
+			`INSERT OR IGNORE INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+			f.version, f.name, f.checksum, at.UTC().Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("record migration checksum %s: %w", f.name, err)
+		}
+	}
 	return nil
 }
+
+// appliedVersions reads goose's version table. A fresh database has no
+// table yet, which is reported as nothing applied.
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+	var exists int
+	if err := m.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version'`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check goose_db_version: %w", err)
+	}
+	applied := make(map[int64]time.Time)
+	if exists == 0 {
+		return applied, nil
+	}
+
+	// goose appends a row per up/down; the latest row per version wins.
+	rows, err := m.db.QueryContext(ctx,
+		`SELECT version_id, is_applied, tstamp FROM goose_db_version WHERE version_id > 0 ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("read goose_db_version: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	for rows.Next() {
+		var (
+			version   int64
+			isApplied bool
+			tstamp    time.Time
+		)
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, fmt.Errorf("scan goose_db_version: %w", err)
+		}
+		if isApplied {
+			applied[version] = tstamp
+		} else {
+			delete(applied, version)
+		}
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) recordedChecksums(ctx context.Context) (map[int64]string, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT version, checksum FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	out := make(map[int64]string)
+	for rows.Next() {
+		var (
+			version int64
+			sum     string
+		)
+		if err := rows.Scan(&version, &sum); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		out[version] = sum
+	}
+	return out, rows.Err()
+}
+
+// embeddedMigrations lists the embedded migration files in version order.
+func embeddedMigrations() ([]migrationFile, error) {
+	entries, err := fs.ReadDir(EmbedMigrations, migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("read embedded migrations: %w", err)
+	}
+	files := make([]migrationFile, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || path.Ext(name) != ".sql" {
+			continue
+		}
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: missing version prefix", name)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version prefix: %w", name, err)
+		}
+		content, err := fs.ReadFile(EmbedMigrations, path.Join(migrationsDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+		sum := sha256.Sum256(content)
+		files = append(files, migrationFile{version: version, name: name, checksum: hex.EncodeToString(sum[:])})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].version < files[j].version })
+	return files, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+func TestMigrator_UpRecordsChecksums(t *testing.T) {
+	writeDB, _ := OpenTestSQLite(t)
+	m := NewMigrator(writeDB)
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	for _, st := range statuses {
+		assert.True(t, st.Applied, "migration %s should be applied", st.Name)
+		assert.NotNil(t, st.AppliedAt)
+		assert.Equal(t, st.Checksum, st.AppliedChecksum)
+		assert.False(t, st.Drifted)
+	}
+	assert.Equal(t, int64(1), statuses[0].Version)
+
+	pending, err := m.Pending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+
+	// Re-running is a no-op.
+	require.NoError(t, RunMigrations(writeDB))
+}
+
+func TestMigrator_StatusOnFreshDatabase(t *testing.T) {
+	writeDB, err := OpenSQLite(filepath.Join(t.TempDir(), "fresh.db"), "write", 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = writeDB.Close() })
+
+	m := NewMigrator(writeDB)
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	pending, err := m.Pending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(statuses), pending)
+
+	statuses, err = m.Up(ctx)
+	require.NoError(t, err)
+	for _, st := range statuses {
+		assert.True(t, st.Applied)
+	}
+}
+
+func TestMigrator_DetectsChangedChecksum(t *testing.T) {
+	writeDB, _ := OpenTestSQLite(t)
+	m := NewMigrator(writeDB)
+
+	// Simulate migration 3 having been applied from different file content.
+	_, err := writeDB.ExecContext(ctx, `UPDATE schema_migrations SET checksum = 'edited' WHERE version = 3`)
+	require.NoError(t, err)
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	var drifted []int64
+	for _, st := range statuses {
+		if st.Drifted {
+			drifted = append(drifted, st.Version)
+			assert.Equal(t, "edited", st.AppliedChecksum)
+		}
+	}
+	assert.Equal(t, []int64{3}, drifted)
+
+	err = m.Verify(ctx)
+	require.ErrorAs(t, err, new(*domain.ConflictError))
+	assert.Contains(t, err.Error(), "checksum drift")
+	assert.Contains(t, err.Error(), statuses[2].Name)
+
+	// Startup refuses to migrate a drifted database.
+	err = RunMigrations(writeDB)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum drift")
+}
+
+func TestMigrator_BackfillsChecksumsForLegacyDatabase(t *testing.T) {
+	writeDB, _ := OpenTestSQLite(t)
+
+	// Databases migrated before checksums were tracked have no records.
+	_, err := writeDB.ExecContext(ctx, `DELETE FROM schema_migrations`)
+	require.NoError(t, err)
+
+	require.NoError(t, RunMigrations(writeDB))
+
+	var n int
+	require.NoError(t, writeDB.QueryRowContext(ctx, `SELECT count(*) FROM schema_migrations`).Scan(&n))
+	files, err := embeddedMigrations()
+	require.NoError(t, err)
+	assert.Equal(t, len(files), n)
+}
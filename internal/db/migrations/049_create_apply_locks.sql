@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE apply_locks (
+  name TEXT PRIMARY KEY,
+  lease_id TEXT NOT NULL,
+  owner TEXT NOT NULL,
+  principal_name TEXT NOT NULL,
+  acquired_at DATETIME NOT NULL,
+  expires_at DATETIME NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS apply_locks;
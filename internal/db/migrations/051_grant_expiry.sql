@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE privilege_grants ADD COLUMN expires_at TEXT;
+
+CREATE INDEX idx_grants_expires_at ON privilege_grants(expires_at) WHERE expires_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_grants_expires_at;
+ALTER TABLE privilege_grants DROP COLUMN expires_at;
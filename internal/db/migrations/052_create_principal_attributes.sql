@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE principal_attributes (
+  principal_id TEXT NOT NULL REFERENCES principals(id) ON DELETE CASCADE,
+  key TEXT NOT NULL,
+  value TEXT NOT NULL,
+  PRIMARY KEY (principal_id, key)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS principal_attributes;
@@ -0,0 +1,74 @@
+-- +goose Up
+-- A column mask now targets either one column of one table (table_id,
+-- column_name) or every column carrying a tag (tag_id). Tag-based masks
+-- store empty table_id and column_name, so uniqueness moves to partial
+-- indexes. Bindings are rebuilt alongside so their foreign key follows the
+-- new table instead of cascading on the DROP of the old one.
+CREATE TABLE column_masks_new (
+    id              TEXT PRIMARY KEY,
+    table_id        TEXT NOT NULL DEFAULT '',
+    column_name     TEXT NOT NULL DEFAULT '',
+    mask_expression TEXT NOT NULL,
+    description     TEXT,
+    created_at      TEXT NOT NULL DEFAULT (datetime('now')),
+    tag_id          TEXT REFERENCES tags(id) ON DELETE CASCADE,
+    CHECK ((tag_id IS NULL) = (table_id <> '' AND column_name <> ''))
+);
+INSERT INTO column_masks_new (id, table_id, column_name, mask_expression, description, created_at)
+    SELECT id, table_id, column_name, mask_expression, description, created_at
+    FROM column_masks;
+
+CREATE TABLE column_mask_bindings_new (
+    id             TEXT PRIMARY KEY,
+    column_mask_id TEXT NOT NULL REFERENCES column_masks_new(id) ON DELETE CASCADE,
+    principal_id   TEXT NOT NULL,
+    principal_type TEXT NOT NULL,
+    see_original   INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(column_mask_id, principal_id, principal_type)
+);
+INSERT INTO column_mask_bindings_new
+    SELECT id, column_mask_id, principal_id, principal_type, see_original
+    FROM column_mask_bindings;
+
+DROP TABLE column_mask_bindings;
+DROP TABLE column_masks;
+ALTER TABLE column_masks_new RENAME TO column_masks;
+ALTER TABLE column_mask_bindings_new RENAME TO column_mask_bindings;
+
+CREATE UNIQUE INDEX idx_column_masks_table_column
+    ON column_masks(table_id, column_name) WHERE tag_id IS NULL;
+CREATE UNIQUE INDEX idx_column_masks_tag
+    ON column_masks(tag_id) WHERE tag_id IS NOT NULL;
+
+-- +goose Down
+CREATE TABLE column_masks_old (
+    id              TEXT PRIMARY KEY,
+    table_id        TEXT NOT NULL,
+    column_name     TEXT NOT NULL,
+    mask_expression TEXT NOT NULL,
+    description     TEXT,
+    created_at      TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE(table_id, column_name)
+);
+INSERT INTO column_masks_old
+    SELECT id, table_id, column_name, mask_expression, description, created_at
+    FROM column_masks
+    WHERE tag_id IS NULL;
+
+CREATE TABLE column_mask_bindings_old (
+    id             TEXT PRIMARY KEY,
+    column_mask_id TEXT NOT NULL REFERENCES column_masks_old(id) ON DELETE CASCADE,
+    principal_id   TEXT NOT NULL,
+    principal_type TEXT NOT NULL,
+    see_original   INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(column_mask_id, principal_id, principal_type)
+);
+INSERT INTO column_mask_bindings_old
+    SELECT b.id, b.column_mask_id, b.principal_id, b.principal_type, b.see_original
+    FROM column_mask_bindings b
+    JOIN column_masks_old m ON m.id = b.column_mask_id;
+
+DROP TABLE column_mask_bindings;
+DROP TABLE column_masks;
+ALTER TABLE column_masks_old RENAME TO column_masks;
+ALTER TABLE column_mask_bindings_old RENAME TO column_mask_bindings;
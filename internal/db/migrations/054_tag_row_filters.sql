@@ -0,0 +1,11 @@
+-- +goose Up
+-- A row filter can target a tag instead of a table: it then applies to every
+-- table assigned the tag. Tag-based filters store an empty table_id.
+ALTER TABLE row_filters ADD COLUMN tag_id TEXT REFERENCES tags(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_row_filters_tag ON row_filters(tag_id) WHERE tag_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_row_filters_tag;
+DELETE FROM row_filters WHERE tag_id IS NOT NULL;
+ALTER TABLE row_filters DROP COLUMN tag_id;
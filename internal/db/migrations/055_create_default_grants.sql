@@ -0,0 +1,22 @@
+-- +goose Up
+-- Default grants are privilege templates copied into privilege_grants when a
+-- schema or table is created. An empty schema_name matches every schema in
+-- the catalog.
+CREATE TABLE default_grants (
+  id TEXT PRIMARY KEY,
+  catalog_name TEXT NOT NULL,
+  schema_name TEXT NOT NULL DEFAULT '',
+  object_type TEXT NOT NULL CHECK (object_type IN ('schema', 'table')),
+  principal_id TEXT NOT NULL,
+  principal_type TEXT NOT NULL CHECK (principal_type IN ('user', 'group')),
+  privilege TEXT NOT NULL,
+  created_by TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (catalog_name, schema_name, object_type, principal_id, principal_type, privilege)
+);
+
+CREATE INDEX idx_default_grants_scope ON default_grants(catalog_name, object_type);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_default_grants_scope;
+DROP TABLE IF EXISTS default_grants;
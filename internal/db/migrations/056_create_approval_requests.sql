@@ -0,0 +1,21 @@
+-- +goose Up
+-- Approval requests hold destructive admin operations that need a second
+-- admin's approval before they run. params is a JSON object of the
+-- operation's arguments.
+CREATE TABLE approval_requests (
+  id TEXT PRIMARY KEY,
+  operation TEXT NOT NULL,
+  params TEXT NOT NULL DEFAULT '{}',
+  status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'FAILED')),
+  requested_by TEXT NOT NULL,
+  decided_by TEXT,
+  error_message TEXT,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  decided_at DATETIME
+);
+
+CREATE INDEX idx_approval_requests_status ON approval_requests(status, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_approval_requests_status;
+DROP TABLE IF EXISTS approval_requests;
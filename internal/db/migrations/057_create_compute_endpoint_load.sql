@@ -0,0 +1,20 @@
+-- +goose Up
+-- Latest load reported by each remote compute agent, written by the control
+-- plane's periodic health poller. last_heartbeat_at is the last successful
+-- poll; checked_at is the last attempt, successful or not.
+CREATE TABLE compute_endpoint_load (
+  endpoint_id TEXT PRIMARY KEY REFERENCES compute_endpoints(id) ON DELETE CASCADE,
+  reachable INTEGER NOT NULL DEFAULT 0,
+  active_queries INTEGER NOT NULL DEFAULT 0,
+  waiting_queries INTEGER NOT NULL DEFAULT 0,
+  max_concurrent_queries INTEGER NOT NULL DEFAULT 0,
+  queued_jobs INTEGER NOT NULL DEFAULT 0,
+  running_jobs INTEGER NOT NULL DEFAULT 0,
+  memory_used_mb INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  last_heartbeat_at DATETIME,
+  checked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS compute_endpoint_load;
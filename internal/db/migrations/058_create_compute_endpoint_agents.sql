@@ -0,0 +1,16 @@
+-- +goose Up
+-- Additional agent URLs backing a REMOTE compute endpoint. The endpoint's own
+-- url is always an agent too; rows here add replicas the resolver routes
+-- across.
+CREATE TABLE compute_endpoint_agents (
+  id TEXT PRIMARY KEY,
+  endpoint_id TEXT NOT NULL REFERENCES compute_endpoints(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (endpoint_id, url)
+);
+
+CREATE INDEX idx_compute_endpoint_agents_endpoint ON compute_endpoint_agents(endpoint_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS compute_endpoint_agents;
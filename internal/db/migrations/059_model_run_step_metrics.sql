@@ -0,0 +1,11 @@
+-- +goose Up
+-- Wall-clock duration of each model step and, where the target catalog
+-- tracks data files, the size of the materialized table.
+ALTER TABLE model_run_steps ADD COLUMN duration_ms INTEGER;
+ALTER TABLE model_run_steps ADD COLUMN bytes_written INTEGER;
+CREATE INDEX idx_model_run_steps_model ON model_run_steps(model_name, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_model_run_steps_model;
+ALTER TABLE model_run_steps DROP COLUMN bytes_written;
+ALTER TABLE model_run_steps DROP COLUMN duration_ms;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Supports listing a test's results across runs, newest first.
+CREATE INDEX idx_model_test_results_test ON model_test_results(test_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_model_test_results_test;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Registered macros a model's SQL calls, extracted when the model is
+-- created or its SQL changes.
+ALTER TABLE models ADD COLUMN macro_deps TEXT NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE models DROP COLUMN macro_deps;
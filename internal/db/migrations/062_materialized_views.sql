@@ -0,0 +1,14 @@
+-- +goose Up
+-- Materialized views are backed by a DuckLake table of the same name.
+-- catalog_name records the catalog holding that table so scheduled refreshes
+-- can find it; schema IDs are only unique within a catalog.
+ALTER TABLE views ADD COLUMN materialized INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE views ADD COLUMN refresh_schedule TEXT;
+ALTER TABLE views ADD COLUMN last_refreshed_at TEXT;
+ALTER TABLE views ADD COLUMN catalog_name TEXT;
+
+-- +goose Down
+ALTER TABLE views DROP COLUMN catalog_name;
+ALTER TABLE views DROP COLUMN last_refreshed_at;
+ALTER TABLE views DROP COLUMN refresh_schedule;
+ALTER TABLE views DROP COLUMN materialized;
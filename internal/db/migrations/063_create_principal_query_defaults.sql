@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE principal_query_defaults (
+  principal_id TEXT PRIMARY KEY REFERENCES principals(id) ON DELETE CASCADE,
+  default_catalog TEXT NOT NULL DEFAULT '',
+  default_schema TEXT NOT NULL DEFAULT '',
+  timezone TEXT NOT NULL DEFAULT '',
+  updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS principal_query_defaults;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE principals ADD COLUMN updated_at TEXT;
+UPDATE principals SET updated_at = created_at;
+
+ALTER TABLE groups ADD COLUMN updated_at TEXT;
+UPDATE groups SET updated_at = created_at;
+
+-- +goose Down
+ALTER TABLE groups DROP COLUMN updated_at;
+ALTER TABLE principals DROP COLUMN updated_at;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE audit_archives (
+    id               TEXT PRIMARY KEY,
+    location         TEXT NOT NULL,
+    entry_count      INTEGER NOT NULL,
+    first_entry_id   TEXT NOT NULL,
+    last_entry_id    TEXT NOT NULL,
+    first_created_at TEXT NOT NULL,
+    last_created_at  TEXT NOT NULL,
+    prev_hash        TEXT NOT NULL,
+    hash             TEXT NOT NULL,
+    created_at       TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_audit_archives_last ON audit_archives(last_created_at, last_entry_id);
+CREATE INDEX idx_audit_created_id ON audit_log(created_at, id);
+
+-- +goose Down
+DROP INDEX idx_audit_created_id;
+DROP TABLE audit_archives;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Each archive extends the chain from exactly one predecessor; two archives
+-- claiming the same prev_hash would fork it.
+CREATE UNIQUE INDEX idx_audit_archives_prev_hash ON audit_archives(prev_hash);
+
+-- +goose Down
+DROP INDEX idx_audit_archives_prev_hash;
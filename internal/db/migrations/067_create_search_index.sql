@@ -0,0 +1,20 @@
+-- +goose Up
+-- A copy of each catalog's schema, table, view and column names, kept in the
+-- control plane so tag matches can be resolved to names when the metastore is
+-- a separate database. Rebuilt per catalog by a search reindex.
+CREATE TABLE search_index (
+    catalog_name TEXT NOT NULL,
+    object_type  TEXT NOT NULL,
+    securable_id TEXT NOT NULL,
+    name         TEXT NOT NULL,
+    schema_name  TEXT,
+    table_name   TEXT,
+    comment      TEXT,
+    indexed_at   TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_search_index_securable ON search_index(catalog_name, object_type, securable_id);
+
+-- +goose Down
+DROP INDEX idx_search_index_securable;
+DROP TABLE search_index;
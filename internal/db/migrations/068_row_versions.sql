@@ -0,0 +1,37 @@
+-- +goose Up
+-- row_version is bumped by every update of a row. The API exposes it as the
+-- resource's ETag, and conditional updates (If-Match) require it unchanged
+-- in the UPDATE's WHERE clause. A schema or table without a metadata row,
+-- and a principal without query defaults, is at version 0.
+ALTER TABLE catalogs ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE catalog_metadata ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE views ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE volumes ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE compute_endpoints ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE macros ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE models ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE notebooks ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE pipelines ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE semantic_models ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE storage_credentials ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE external_locations ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE principal_query_defaults ADD COLUMN row_version INTEGER NOT NULL DEFAULT 1;
+-- The attributes of a principal are many rows; their version is kept on the
+-- principal and starts at 0, before any attribute is set.
+ALTER TABLE principals ADD COLUMN attributes_version INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE principals DROP COLUMN attributes_version;
+ALTER TABLE principal_query_defaults DROP COLUMN row_version;
+ALTER TABLE external_locations DROP COLUMN row_version;
+ALTER TABLE storage_credentials DROP COLUMN row_version;
+ALTER TABLE semantic_models DROP COLUMN row_version;
+ALTER TABLE pipelines DROP COLUMN row_version;
+ALTER TABLE notebooks DROP COLUMN row_version;
+ALTER TABLE models DROP COLUMN row_version;
+ALTER TABLE macros DROP COLUMN row_version;
+ALTER TABLE compute_endpoints DROP COLUMN row_version;
+ALTER TABLE volumes DROP COLUMN row_version;
+ALTER TABLE views DROP COLUMN row_version;
+ALTER TABLE catalog_metadata DROP COLUMN row_version;
+ALTER TABLE catalogs DROP COLUMN row_version;
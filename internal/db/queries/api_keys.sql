@@ -9,13 +9,13 @@ JOIN principals p ON ak.principal_id = p.id
 WHERE ak.key_hash = ? AND (ak.expires_at IS NULL OR ak.expires_at > datetime('now', 'localtime'));
 
 -- name: ListAPIKeysForPrincipal :many
-SELECT * FROM api_keys WHERE principal_id = ? ORDER BY created_at DESC;
+SELECT * FROM api_keys WHERE principal_id = ? ORDER BY created_at DESC, id;
 
 -- name: CountAPIKeysForPrincipal :one
 SELECT COUNT(*) as cnt FROM api_keys WHERE principal_id = ?;
 
 -- name: ListAPIKeysForPrincipalPaginated :many
-SELECT * FROM api_keys WHERE principal_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?;
+SELECT * FROM api_keys WHERE principal_id = ? ORDER BY created_at DESC, id LIMIT ? OFFSET ?;
 
 -- name: DeleteAPIKey :exec
 DELETE FROM api_keys WHERE id = ?;
@@ -24,7 +24,7 @@ DELETE FROM api_keys WHERE id = ?;
 SELECT * FROM api_keys WHERE id = ?;
 
 -- name: ListAllAPIKeysPaginated :many
-SELECT * FROM api_keys ORDER BY created_at DESC LIMIT ? OFFSET ?;
+SELECT * FROM api_keys ORDER BY created_at DESC, id LIMIT ? OFFSET ?;
 
 -- name: CountAllAPIKeys :one
 SELECT COUNT(*) as cnt FROM api_keys;
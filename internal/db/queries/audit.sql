@@ -7,7 +7,7 @@ SELECT * FROM audit_log
 WHERE (? IS NULL OR principal_name = ?)
   AND (? IS NULL OR action = ?)
   AND (? IS NULL OR status = ?)
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id DESC
 LIMIT ? OFFSET ?;
 
 -- name: CountAuditLogs :one
@@ -19,17 +19,71 @@ WHERE (? IS NULL OR principal_name = ?)
 -- name: ListQueryHistory :many
 SELECT * FROM audit_log
 WHERE action = 'QUERY'
-  AND (? IS NULL OR principal_name = ?)
-  AND (? IS NULL OR status = ?)
-  AND (? IS NULL OR created_at >= ?)
-  AND (? IS NULL OR created_at <= ?)
-ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+  AND (sqlc.narg('principal_name') IS NULL OR principal_name = sqlc.narg('principal_name'))
+  AND (sqlc.narg('status') IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('from_time') IS NULL OR created_at >= sqlc.narg('from_time'))
+  AND (sqlc.narg('to_time') IS NULL OR created_at <= sqlc.narg('to_time'))
+  AND (sqlc.narg('table_name') IS NULL
+       OR instr(lower(tables_accessed), '"' || lower(sqlc.narg('table_name')) || '"') > 0
+       OR instr(lower(tables_accessed), '.' || lower(sqlc.narg('table_name')) || '"') > 0)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountQueryHistory :one
 SELECT COUNT(*) as cnt FROM audit_log
 WHERE action = 'QUERY'
-  AND (? IS NULL OR principal_name = ?)
-  AND (? IS NULL OR status = ?)
-  AND (? IS NULL OR created_at >= ?)
-  AND (? IS NULL OR created_at <= ?);
+  AND (sqlc.narg('principal_name') IS NULL OR principal_name = sqlc.narg('principal_name'))
+  AND (sqlc.narg('status') IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('from_time') IS NULL OR created_at >= sqlc.narg('from_time'))
+  AND (sqlc.narg('to_time') IS NULL OR created_at <= sqlc.narg('to_time'))
+  AND (sqlc.narg('table_name') IS NULL
+       OR instr(lower(tables_accessed), '"' || lower(sqlc.narg('table_name')) || '"') > 0
+       OR instr(lower(tables_accessed), '.' || lower(sqlc.narg('table_name')) || '"') > 0);
+
+-- name: PurgeQueryHistoryBatch :execrows
+DELETE FROM audit_log
+WHERE id IN (
+    SELECT q.id FROM audit_log q
+    WHERE q.action = 'QUERY' AND q.created_at < sqlc.arg('before')
+    LIMIT sqlc.arg('batch_size')
+);
+
+-- name: GetLatestAuditArchive :one
+SELECT * FROM audit_archives
+ORDER BY last_created_at DESC, last_entry_id DESC
+LIMIT 1;
+
+-- name: InsertAuditArchive :exec
+INSERT INTO audit_archives (id, location, entry_count, first_entry_id, last_entry_id, first_created_at, last_created_at, prev_hash, hash)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListUnarchivedAuditLogs :many
+-- Entries after the latest archive's last entry, oldest first.
+SELECT a.* FROM audit_log a
+LEFT JOIN (
+    SELECT last_created_at, last_entry_id FROM audit_archives
+    ORDER BY last_created_at DESC, last_entry_id DESC
+    LIMIT 1
+) w ON 1 = 1
+WHERE a.created_at < sqlc.arg('before')
+  AND (w.last_created_at IS NULL
+       OR a.created_at > w.last_created_at
+       OR (a.created_at = w.last_created_at AND a.id > w.last_entry_id))
+ORDER BY a.created_at, a.id
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteArchivedAuditLogsBatch :execrows
+-- Only entries up to the latest archive's last entry are deleted, so an
+-- entry that was never archived cannot be removed.
+DELETE FROM audit_log
+WHERE id IN (
+    SELECT a.id FROM audit_log a
+    CROSS JOIN (
+        SELECT last_created_at, last_entry_id FROM audit_archives
+        ORDER BY last_created_at DESC, last_entry_id DESC
+        LIMIT 1
+    ) w
+    WHERE a.created_at < w.last_created_at
+       OR (a.created_at = w.last_created_at AND a.id <= w.last_entry_id)
+    LIMIT sqlc.arg('batch_size')
+);
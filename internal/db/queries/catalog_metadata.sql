@@ -2,6 +2,11 @@
 SELECT * FROM catalog_metadata
 WHERE securable_type = ? AND securable_name = ?;
 
+-- name: ListCatalogMetadataNamesByOwner :many
+SELECT securable_name FROM catalog_metadata
+WHERE securable_type = ? AND owner = ? AND deleted_at IS NULL
+ORDER BY securable_name;
+
 -- name: UpsertCatalogMetadata :exec
 INSERT INTO catalog_metadata (securable_type, securable_name, comment, properties, owner)
 VALUES (?, ?, ?, ?, ?)
@@ -10,8 +15,26 @@ DO UPDATE SET comment = COALESCE(excluded.comment, comment),
               properties = COALESCE(excluded.properties, properties),
               owner = COALESCE(excluded.owner, owner),
               deleted_at = NULL,
+              row_version = row_version + 1,
               updated_at = datetime('now');
 
+-- name: InsertCatalogMetadataIfAbsent :execrows
+-- Conditional update of a securable without metadata (row version 0).
+INSERT INTO catalog_metadata (securable_type, securable_name, comment, properties, owner)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(securable_type, securable_name) DO NOTHING;
+
+-- name: UpdateCatalogMetadataIfVersion :execrows
+UPDATE catalog_metadata
+SET comment = COALESCE(sqlc.narg('comment'), comment),
+    properties = COALESCE(sqlc.narg('properties'), properties),
+    owner = COALESCE(sqlc.narg('owner'), owner),
+    deleted_at = NULL,
+    row_version = row_version + 1,
+    updated_at = datetime('now')
+WHERE securable_type = sqlc.arg('securable_type') AND securable_name = sqlc.arg('securable_name')
+  AND row_version = sqlc.arg('expected_row_version');
+
 -- name: InsertOrReplaceCatalogMetadata :exec
 INSERT OR REPLACE INTO catalog_metadata (securable_type, securable_name, comment, owner)
 VALUES (?, ?, ?, ?);
@@ -31,3 +54,7 @@ WHERE securable_type = ? AND securable_name = ?;
 -- name: DeleteCatalogMetadataByTypeAndPattern :exec
 DELETE FROM catalog_metadata
 WHERE securable_type = ? AND securable_name LIKE ?;
+
+-- name: RenameCatalogMetadata :exec
+UPDATE catalog_metadata SET securable_name = @new_name, updated_at = datetime('now')
+WHERE securable_type = @securable_type AND securable_name = @old_name;
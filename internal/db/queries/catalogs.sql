@@ -20,8 +20,10 @@ UPDATE catalogs
 SET comment = COALESCE(?, comment),
     data_path = COALESCE(?, data_path),
     dsn = COALESCE(?, dsn),
+    row_version = row_version + 1,
     updated_at = datetime('now')
 WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'))
 RETURNING *;
 
 -- name: UpdateCatalogStatus :exec
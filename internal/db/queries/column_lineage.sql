@@ -29,3 +29,7 @@ ORDER BY cle.target_column;
 
 -- name: DeleteColumnLineageByEdgeID :exec
 DELETE FROM column_lineage_edges WHERE lineage_edge_id = ?;
+
+-- name: RenameColumnLineageSourceTable :exec
+UPDATE column_lineage_edges SET source_table = @new_table
+WHERE source_schema = @source_schema AND source_table = @old_table;
@@ -1,8 +1,11 @@
 -- name: CreateColumnMask :one
-INSERT INTO column_masks (id, table_id, column_name, mask_expression, description)
-VALUES (?, ?, ?, ?, ?)
+INSERT INTO column_masks (id, table_id, column_name, tag_id, mask_expression, description)
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING *;
 
+-- name: GetColumnMask :one
+SELECT * FROM column_masks WHERE id = ?;
+
 -- name: GetColumnMasksForTable :many
 SELECT * FROM column_masks WHERE table_id = ?;
 
@@ -32,6 +35,21 @@ FROM column_masks cm
 JOIN column_mask_bindings cmb ON cm.id = cmb.column_mask_id
 WHERE cm.table_id = ? AND cmb.principal_id = ? AND cmb.principal_type = ?;
 
+-- name: GetTagColumnMasksForTableAndPrincipal :many
+SELECT ta.column_name, cm.mask_expression, cmb.see_original, cm.tag_id
+FROM column_masks cm
+JOIN column_mask_bindings cmb ON cm.id = cmb.column_mask_id
+JOIN tag_assignments ta ON ta.tag_id = cm.tag_id
+WHERE ta.securable_type = 'column' AND ta.securable_id = ? AND ta.column_name IS NOT NULL
+  AND cmb.principal_id = ? AND cmb.principal_type = ?
+ORDER BY cm.created_at, cm.id;
+
+-- name: CountColumnMasksForTag :one
+SELECT COUNT(*) as cnt FROM column_masks WHERE tag_id = ?;
+
+-- name: ListColumnMasksForTagPaginated :many
+SELECT * FROM column_masks WHERE tag_id = ? ORDER BY id LIMIT ? OFFSET ?;
+
 -- name: CountColumnMasksForTable :one
 SELECT COUNT(*) as cnt FROM column_masks WHERE table_id = ?;
 
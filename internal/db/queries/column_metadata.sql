@@ -15,3 +15,7 @@ DELETE FROM column_metadata WHERE table_securable_name = ?;
 
 -- name: DeleteColumnMetadataByTablePattern :exec
 DELETE FROM column_metadata WHERE table_securable_name LIKE ?;
+
+-- name: RenameColumnMetadataTable :exec
+UPDATE column_metadata SET table_securable_name = @new_name
+WHERE table_securable_name = @old_name;
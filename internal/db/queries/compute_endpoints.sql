@@ -16,14 +16,16 @@ SELECT * FROM compute_endpoints ORDER BY name LIMIT ? OFFSET ?;
 -- name: CountComputeEndpoints :one
 SELECT COUNT(*) FROM compute_endpoints;
 
--- name: UpdateComputeEndpoint :exec
+-- name: UpdateComputeEndpoint :execrows
 UPDATE compute_endpoints
 SET url = COALESCE(?, url),
     size = COALESCE(?, size),
     max_memory_gb = COALESCE(?, max_memory_gb),
     auth_token = COALESCE(?, auth_token),
+    row_version = row_version + 1,
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: UpdateComputeEndpointStatus :exec
 UPDATE compute_endpoints
@@ -15,15 +15,17 @@ SELECT * FROM external_locations ORDER BY name LIMIT ? OFFSET ?;
 -- name: CountExternalLocations :one
 SELECT COUNT(*) FROM external_locations;
 
--- name: UpdateExternalLocation :exec
+-- name: UpdateExternalLocation :execrows
 UPDATE external_locations
 SET url = COALESCE(?, url),
     credential_name = COALESCE(?, credential_name),
     comment = COALESCE(?, comment),
     owner = COALESCE(?, owner),
     read_only = COALESCE(?, read_only),
+    row_version = row_version + 1,
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: DeleteExternalLocation :exec
 DELETE FROM external_locations WHERE id = ?;
@@ -55,4 +55,9 @@ WHERE external_table_id = ?;
 
 -- name: ListAllExternalTables :many
 SELECT * FROM external_tables
-WHERE deleted_at IS NULL;
+WHERE deleted_at IS NULL
+ORDER BY schema_name, table_name;
+
+-- name: RenameExternalTable :exec
+UPDATE external_tables SET table_name = @new_name, updated_at = datetime('now')
+WHERE schema_name = @schema_name AND table_name = @old_name AND deleted_at IS NULL;
@@ -7,7 +7,7 @@ RETURNING *;
 SELECT * FROM git_repos WHERE id = ?;
 
 -- name: ListGitRepos :many
-SELECT * FROM git_repos ORDER BY created_at DESC LIMIT ? OFFSET ?;
+SELECT * FROM git_repos ORDER BY created_at DESC, id LIMIT ? OFFSET ?;
 
 -- name: CountGitRepos :one
 SELECT COUNT(*) FROM git_repos;
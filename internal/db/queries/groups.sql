@@ -1,6 +1,6 @@
 -- name: CreateGroup :one
-INSERT INTO groups (id, name, description)
-VALUES (?, ?, ?)
+INSERT INTO groups (id, name, description, updated_at)
+VALUES (?, ?, ?, datetime('now'))
 RETURNING *;
 
 -- name: GetGroup :one
@@ -15,6 +15,9 @@ SELECT * FROM groups ORDER BY name;
 -- name: DeleteGroup :exec
 DELETE FROM groups WHERE id = ?;
 
+-- name: TouchGroup :exec
+UPDATE groups SET updated_at = datetime('now') WHERE id = ?;
+
 -- name: AddGroupMember :exec
 INSERT OR IGNORE INTO group_members (group_id, member_type, member_id)
 VALUES (?, ?, ?);
@@ -23,8 +26,14 @@ VALUES (?, ?, ?);
 DELETE FROM group_members
 WHERE group_id = ? AND member_type = ? AND member_id = ?;
 
+-- name: ListMembershipsForMember :many
+SELECT * FROM group_members WHERE member_type = ? AND member_id = ? ORDER BY group_id;
+
+-- name: RemoveMemberFromAllGroups :exec
+DELETE FROM group_members WHERE member_type = ? AND member_id = ?;
+
 -- name: ListGroupMembers :many
-SELECT * FROM group_members WHERE group_id = ?;
+SELECT * FROM group_members WHERE group_id = ? ORDER BY member_type, member_id;
 
 -- name: GetGroupsForMember :many
 SELECT g.* FROM groups g
@@ -34,11 +43,5 @@ WHERE gm.member_type = ? AND gm.member_id = ?;
 -- name: CountGroups :one
 SELECT COUNT(*) as cnt FROM groups;
 
--- name: ListGroupsPaginated :many
-SELECT * FROM groups ORDER BY id LIMIT ? OFFSET ?;
-
 -- name: CountGroupMembers :one
 SELECT COUNT(*) as cnt FROM group_members WHERE group_id = ?;
-
--- name: ListGroupMembersPaginated :many
-SELECT * FROM group_members WHERE group_id = ? ORDER BY member_id LIMIT ? OFFSET ?;
@@ -6,14 +6,14 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 SELECT DISTINCT source_table, target_table, edge_type, principal_name, created_at, source_schema, target_schema
 FROM lineage_edges
 WHERE target_table = ?
-ORDER BY created_at DESC
+ORDER BY created_at DESC, source_table, target_table
 LIMIT ? OFFSET ?;
 
 -- name: GetDownstreamLineage :many
 SELECT DISTINCT source_table, target_table, edge_type, principal_name, created_at, source_schema, target_schema
 FROM lineage_edges
 WHERE source_table = ?
-ORDER BY created_at DESC
+ORDER BY created_at DESC, source_table, target_table
 LIMIT ? OFFSET ?;
 
 -- name: CountUpstreamLineage :one
@@ -33,3 +33,9 @@ DELETE FROM lineage_edges WHERE source_table = ? OR target_table = ?;
 
 -- name: DeleteLineageByTablePattern :exec
 DELETE FROM lineage_edges WHERE source_table LIKE ? OR target_table LIKE ?;
+
+-- name: RenameLineageTable :exec
+UPDATE lineage_edges
+SET source_table = CASE WHEN source_table = @old_name THEN @new_name ELSE source_table END,
+    target_table = CASE WHEN target_table = @old_name THEN @new_name ELSE target_table END
+WHERE source_table = @old_name OR target_table = @old_name;
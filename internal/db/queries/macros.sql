@@ -15,7 +15,7 @@ SELECT COUNT(*) FROM macros;
 -- name: ListAllMacros :many
 SELECT * FROM macros ORDER BY name;
 
--- name: UpdateMacro :exec
+-- name: UpdateMacro :execrows
 UPDATE macros
 SET body = ?,
     description = ?,
@@ -27,8 +27,10 @@ SET body = ?,
     owner = ?,
     properties = ?,
     tags = ?,
+    row_version = row_version + 1,
     updated_at = datetime('now')
-WHERE name = ?;
+WHERE name = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: DeleteMacro :exec
 DELETE FROM macros WHERE name = ?;
@@ -19,3 +19,11 @@ RETURNING *;
 
 -- name: ListModelTestResultsByStep :many
 SELECT * FROM model_test_results WHERE run_step_id = ? ORDER BY test_name;
+
+-- name: ListModelTestResultsByTest :many
+SELECT * FROM model_test_results WHERE test_id = ?
+ORDER BY created_at DESC, rowid DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountModelTestResultsByTest :one
+SELECT COUNT(*) FROM model_test_results WHERE test_id = ?;
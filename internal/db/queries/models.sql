@@ -1,6 +1,6 @@
 -- name: CreateModel :one
-INSERT INTO models (id, project_name, name, sql_body, materialization, description, owner, tags, depends_on, config, created_by, contract, freshness_max_lag, freshness_cron)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO models (id, project_name, name, sql_body, materialization, description, owner, tags, depends_on, macro_deps, config, created_by, contract, freshness_max_lag, freshness_cron)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetModelByID :one
@@ -22,7 +22,7 @@ WHERE (? = '' OR project_name = ?);
 -- name: ListAllModels :many
 SELECT * FROM models ORDER BY project_name, name;
 
--- name: UpdateModel :exec
+-- name: UpdateModel :execrows
 UPDATE models
 SET sql_body = COALESCE(?, sql_body),
     materialization = COALESCE(?, materialization),
@@ -32,12 +32,17 @@ SET sql_body = COALESCE(?, sql_body),
     contract = COALESCE(?, contract),
     freshness_max_lag = ?,
     freshness_cron = ?,
+    row_version = row_version + 1,
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: UpdateModelDependencies :exec
 UPDATE models SET depends_on = ?, updated_at = datetime('now') WHERE id = ?;
 
+-- name: UpdateModelMacroDependencies :exec
+UPDATE models SET macro_deps = ?, updated_at = datetime('now') WHERE id = ?;
+
 -- name: DeleteModel :exec
 DELETE FROM models WHERE id = ?;
 
@@ -52,7 +57,7 @@ SELECT * FROM model_runs WHERE id = ?;
 -- name: ListModelRuns :many
 SELECT * FROM model_runs
 WHERE (? = '' OR status = ?)
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id
 LIMIT ? OFFSET ?;
 
 -- name: CountModelRuns :one
@@ -78,3 +83,14 @@ UPDATE model_run_steps SET status = 'RUNNING', started_at = datetime('now') WHER
 
 -- name: UpdateModelRunStepFinished :exec
 UPDATE model_run_steps SET status = ?, finished_at = datetime('now'), rows_affected = ?, error_message = ? WHERE id = ?;
+
+-- name: UpdateModelRunStepMetrics :exec
+UPDATE model_run_steps SET duration_ms = ?, bytes_written = ? WHERE id = ?;
+
+-- name: ListModelRunStepsByModel :many
+SELECT * FROM model_run_steps WHERE model_name = ?
+ORDER BY created_at DESC, rowid DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountModelRunStepsByModel :one
+SELECT COUNT(*) FROM model_run_steps WHERE model_name = ?;
@@ -9,7 +9,7 @@ SELECT * FROM notebook_jobs WHERE id = ?;
 -- name: ListNotebookJobs :many
 SELECT * FROM notebook_jobs
 WHERE notebook_id = ?
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id
 LIMIT ? OFFSET ?;
 
 -- name: CountNotebookJobs :one
@@ -1,6 +1,6 @@
 -- name: CreateNotebook :one
-INSERT INTO notebooks (id, name, description, owner)
-VALUES (?, ?, ?, ?)
+INSERT INTO notebooks (id, name, description, owner, git_repo_id, git_path)
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetNotebook :one
@@ -9,17 +9,23 @@ SELECT * FROM notebooks WHERE id = ?;
 -- name: ListNotebooks :many
 SELECT * FROM notebooks
 WHERE (sqlc.narg('owner') IS NULL OR owner = sqlc.narg('owner'))
-ORDER BY updated_at DESC
+ORDER BY updated_at DESC, id
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
+-- name: ListNotebooksByGitRepo :many
+SELECT * FROM notebooks
+WHERE git_repo_id = ?
+ORDER BY name;
+
 -- name: CountNotebooks :one
 SELECT COUNT(*) FROM notebooks
 WHERE (sqlc.narg('owner') IS NULL OR owner = sqlc.narg('owner'));
 
 -- name: UpdateNotebook :one
 UPDATE notebooks
-SET name = ?, description = ?, updated_at = datetime('now')
+SET name = ?, description = ?, row_version = row_version + 1, updated_at = datetime('now')
 WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'))
 RETURNING *;
 
 -- name: DeleteNotebook :exec
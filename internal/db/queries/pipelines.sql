@@ -15,20 +15,22 @@ SELECT * FROM pipelines ORDER BY name LIMIT ? OFFSET ?;
 -- name: CountPipelines :one
 SELECT COUNT(*) FROM pipelines;
 
--- name: UpdatePipeline :exec
+-- name: UpdatePipeline :execrows
 UPDATE pipelines
 SET description = COALESCE(?, description),
     schedule_cron = COALESCE(?, schedule_cron),
     is_paused = COALESCE(?, is_paused),
     concurrency_limit = COALESCE(?, concurrency_limit),
+    row_version = row_version + 1,
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: DeletePipeline :exec
 DELETE FROM pipelines WHERE id = ?;
 
 -- name: ListScheduledPipelines :many
-SELECT * FROM pipelines WHERE schedule_cron IS NOT NULL AND is_paused = 0;
+SELECT * FROM pipelines WHERE schedule_cron IS NOT NULL AND is_paused = 0 ORDER BY name;
 
 -- name: CreatePipelineJob :one
 INSERT INTO pipeline_jobs (id, pipeline_id, name, compute_endpoint_id, depends_on, notebook_id, timeout_seconds, retry_count, job_order, job_type, model_selector)
@@ -59,7 +61,7 @@ SELECT * FROM pipeline_runs WHERE id = ?;
 SELECT * FROM pipeline_runs
 WHERE (? = '' OR pipeline_id = ?)
   AND (? = '' OR status = ?)
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id
 LIMIT ? OFFSET ?;
 
 -- name: CountPipelineRuns :one
@@ -91,7 +93,7 @@ RETURNING *;
 SELECT * FROM pipeline_job_runs WHERE id = ?;
 
 -- name: ListPipelineJobRunsByRun :many
-SELECT * FROM pipeline_job_runs WHERE run_id = ? ORDER BY created_at;
+SELECT * FROM pipeline_job_runs WHERE run_id = ? ORDER BY created_at, id;
 
 -- name: UpdatePipelineJobRunStatus :exec
 UPDATE pipeline_job_runs SET status = ?, error_message = ? WHERE id = ?;
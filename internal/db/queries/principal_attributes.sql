@@ -0,0 +1,20 @@
+-- name: ListPrincipalAttributes :many
+SELECT * FROM principal_attributes WHERE principal_id = ? ORDER BY key;
+
+-- name: DeletePrincipalAttributes :exec
+DELETE FROM principal_attributes WHERE principal_id = ?;
+
+-- name: InsertPrincipalAttribute :exec
+INSERT INTO principal_attributes (principal_id, key, value)
+VALUES (?, ?, ?);
+
+-- name: GetPrincipalAttributesVersion :one
+SELECT attributes_version FROM principals WHERE id = ?;
+
+-- name: BumpPrincipalAttributesVersion :one
+-- Bumped before the attributes are replaced, in the same transaction, so
+-- the check and the replacement are atomic.
+UPDATE principals SET attributes_version = attributes_version + 1
+WHERE id = sqlc.arg('id')
+  AND (sqlc.narg('expected_row_version') IS NULL OR attributes_version = sqlc.narg('expected_row_version'))
+RETURNING attributes_version;
@@ -0,0 +1,34 @@
+-- name: GetPrincipalQueryDefaults :one
+SELECT default_catalog, default_schema, timezone, row_version FROM principal_query_defaults
+WHERE principal_id = ?;
+
+-- name: GetPrincipalQueryDefaultsByName :one
+SELECT d.default_catalog, d.default_schema, d.timezone, d.row_version FROM principal_query_defaults d
+JOIN principals p ON p.id = d.principal_id
+WHERE p.name = ?;
+
+-- name: UpsertPrincipalQueryDefaults :one
+INSERT INTO principal_query_defaults (principal_id, default_catalog, default_schema, timezone)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(principal_id)
+DO UPDATE SET default_catalog = excluded.default_catalog,
+              default_schema = excluded.default_schema,
+              timezone = excluded.timezone,
+              row_version = row_version + 1,
+              updated_at = datetime('now')
+RETURNING row_version;
+
+-- name: InsertPrincipalQueryDefaultsIfAbsent :execrows
+-- Conditional update of a principal without defaults (row version 0).
+INSERT INTO principal_query_defaults (principal_id, default_catalog, default_schema, timezone)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(principal_id) DO NOTHING;
+
+-- name: UpdatePrincipalQueryDefaultsIfVersion :execrows
+UPDATE principal_query_defaults
+SET default_catalog = sqlc.arg('default_catalog'),
+    default_schema = sqlc.arg('default_schema'),
+    timezone = sqlc.arg('timezone'),
+    row_version = row_version + 1,
+    updated_at = datetime('now')
+WHERE principal_id = sqlc.arg('principal_id') AND row_version = sqlc.arg('expected_row_version');
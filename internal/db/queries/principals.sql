@@ -1,11 +1,11 @@
 -- name: CreatePrincipal :one
-INSERT INTO principals (id, name, type, is_admin)
-VALUES (?, ?, ?, ?)
+INSERT INTO principals (id, name, type, is_admin, updated_at)
+VALUES (?, ?, ?, ?, datetime('now'))
 RETURNING *;
 
 -- name: CreatePrincipalWithExternalID :one
-INSERT INTO principals (id, name, type, is_admin, external_id, external_issuer)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO principals (id, name, type, is_admin, external_id, external_issuer, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, datetime('now'))
 RETURNING *;
 
 -- name: GetPrincipal :one
@@ -26,13 +26,22 @@ SELECT * FROM principals ORDER BY name;
 DELETE FROM principals WHERE id = ?;
 
 -- name: SetAdmin :exec
-UPDATE principals SET is_admin = ? WHERE id = ?;
+UPDATE principals SET is_admin = ?, updated_at = datetime('now') WHERE id = ?;
 
 -- name: BindExternalID :exec
-UPDATE principals SET external_id = ?, external_issuer = ? WHERE id = ?;
+UPDATE principals SET external_id = ?, external_issuer = ?, updated_at = datetime('now') WHERE id = ?;
 
 -- name: CountPrincipals :one
 SELECT COUNT(*) as cnt FROM principals;
 
 -- name: ListPrincipalsPaginated :many
 SELECT * FROM principals ORDER BY id LIMIT ? OFFSET ?;
+
+-- name: ListPrincipalsPaginatedByName :many
+SELECT * FROM principals ORDER BY name, id LIMIT ? OFFSET ?;
+
+-- name: ListPrincipalsPaginatedByCreatedAt :many
+SELECT * FROM principals ORDER BY created_at, id LIMIT ? OFFSET ?;
+
+-- name: ListPrincipalsPaginatedByUpdatedAt :many
+SELECT * FROM principals ORDER BY updated_at, id LIMIT ? OFFSET ?;
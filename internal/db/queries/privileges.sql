@@ -1,6 +1,6 @@
 -- name: GrantPrivilege :one
-INSERT INTO privilege_grants (id, principal_id, principal_type, securable_type, securable_id, privilege, granted_by)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO privilege_grants (id, principal_id, principal_type, securable_type, securable_id, privilege, granted_by, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: RevokePrivilege :exec
@@ -12,31 +12,33 @@ DELETE FROM privilege_grants WHERE id = ?;
 
 -- name: ListGrantsForPrincipal :many
 SELECT * FROM privilege_grants
-WHERE principal_id = ? AND principal_type = ?;
+WHERE principal_id = ? AND principal_type = ?
+ORDER BY id;
 
 -- name: ListGrantsForSecurable :many
 SELECT * FROM privilege_grants
-WHERE securable_type = ? AND securable_id = ?;
+WHERE securable_type = ? AND securable_id = ?
+ORDER BY id;
 
 -- name: CheckDirectGrant :one
 SELECT COUNT(*) as cnt FROM privilege_grants
-WHERE principal_id = ? AND principal_type = ? AND securable_type = ? AND securable_id = ? AND privilege = ?;
-
--- name: CheckDirectGrantAny :one
-SELECT COUNT(*) as cnt FROM privilege_grants
-WHERE principal_id = ? AND principal_type = ? AND securable_type = ? AND securable_id = ?
-  AND privilege IN ('ALL_PRIVILEGES', ?);
+WHERE principal_id = ? AND principal_type = ? AND securable_type = ? AND securable_id = ? AND privilege = ?
+  AND (expires_at IS NULL OR expires_at > datetime('now'));
 
 -- name: ListGrantsForPrincipalOnSecurable :many
 SELECT * FROM privilege_grants
-WHERE principal_id = ? AND principal_type = ? AND securable_type = ? AND securable_id = ?;
+WHERE principal_id = ? AND principal_type = ? AND securable_type = ? AND securable_id = ?
+  AND (expires_at IS NULL OR expires_at > datetime('now'))
+ORDER BY id;
 
 -- name: ListAllGrantsForIdentities :many
 SELECT * FROM privilege_grants
-WHERE (principal_type = 'user' AND principal_id = ?)
+WHERE ((principal_type = 'user' AND principal_id = ?)
    OR (principal_type = 'group' AND principal_id IN (
        SELECT group_id FROM group_members WHERE member_type = 'user' AND member_id = ?
-   ));
+   )))
+  AND (expires_at IS NULL OR expires_at > datetime('now'))
+ORDER BY id;
 
 -- name: CountGrantsForPrincipal :one
 SELECT COUNT(*) as cnt FROM privilege_grants
@@ -62,3 +64,9 @@ SELECT COUNT(*) as cnt FROM privilege_grants;
 -- name: ListAllGrantsPaginated :many
 SELECT * FROM privilege_grants
 ORDER BY id LIMIT ? OFFSET ?;
+
+-- name: DeleteExpiredGrants :execresult
+DELETE FROM privilege_grants WHERE expires_at IS NOT NULL AND expires_at <= datetime('now');
+
+-- name: RevokeAllGrantsForPrincipal :exec
+DELETE FROM privilege_grants WHERE principal_id = ? AND principal_type = ?;
@@ -1,6 +1,6 @@
 -- name: CreateRowFilter :one
-INSERT INTO row_filters (id, table_id, filter_sql, description)
-VALUES (?, ?, ?, ?)
+INSERT INTO row_filters (id, table_id, filter_sql, description, tag_id)
+VALUES (?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetRowFiltersForTable :many
@@ -25,6 +25,20 @@ SELECT rf.* FROM row_filters rf
 JOIN row_filter_bindings rfb ON rf.id = rfb.row_filter_id
 WHERE rf.table_id = ? AND rfb.principal_id = ? AND rfb.principal_type = ?;
 
+-- name: GetTagRowFiltersForTableAndPrincipal :many
+SELECT rf.* FROM row_filters rf
+JOIN row_filter_bindings rfb ON rf.id = rfb.row_filter_id
+JOIN tag_assignments ta ON ta.tag_id = rf.tag_id
+WHERE ta.securable_type = 'table' AND ta.securable_id = ? AND ta.column_name IS NULL
+  AND rfb.principal_id = ? AND rfb.principal_type = ?
+ORDER BY rf.created_at, rf.id;
+
+-- name: CountRowFiltersForTag :one
+SELECT COUNT(*) as cnt FROM row_filters WHERE tag_id = ?;
+
+-- name: ListRowFiltersForTagPaginated :many
+SELECT * FROM row_filters WHERE tag_id = ? ORDER BY id LIMIT ? OFFSET ?;
+
 -- name: CountRowFiltersForTable :one
 SELECT COUNT(*) as cnt FROM row_filters WHERE table_id = ?;
 
@@ -25,15 +25,17 @@ WHERE (? = '' OR project_name = ?);
 -- name: ListAllSemanticModels :many
 SELECT * FROM semantic_models ORDER BY project_name, name;
 
--- name: UpdateSemanticModel :exec
+-- name: UpdateSemanticModel :execrows
 UPDATE semantic_models
 SET description = COALESCE(?, description),
     owner = COALESCE(?, owner),
     base_model_ref = COALESCE(?, base_model_ref),
     default_time_dimension = COALESCE(?, default_time_dimension),
     tags = COALESCE(?, tags),
+    row_version = row_version + 1,
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: DeleteSemanticModel :exec
 DELETE FROM semantic_models WHERE id = ?;
@@ -20,7 +20,7 @@ SELECT * FROM storage_credentials ORDER BY name LIMIT ? OFFSET ?;
 -- name: CountStorageCredentials :one
 SELECT COUNT(*) FROM storage_credentials;
 
--- name: UpdateStorageCredential :exec
+-- name: UpdateStorageCredential :execrows
 UPDATE storage_credentials
 SET key_id_encrypted = COALESCE(?, key_id_encrypted),
     secret_encrypted = COALESCE(?, secret_encrypted),
@@ -34,8 +34,10 @@ SET key_id_encrypted = COALESCE(?, key_id_encrypted),
     azure_client_secret_encrypted = COALESCE(?, azure_client_secret_encrypted),
     gcs_key_file_path = COALESCE(?, gcs_key_file_path),
     comment = COALESCE(?, comment),
+    row_version = row_version + 1,
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: DeleteStorageCredential :exec
 DELETE FROM storage_credentials WHERE id = ?;
@@ -16,3 +16,7 @@ DELETE FROM table_statistics WHERE table_securable_name = ?;
 
 -- name: DeleteTableStatisticsByPattern :exec
 DELETE FROM table_statistics WHERE table_securable_name LIKE ?;
+
+-- name: RenameTableStatistics :exec
+UPDATE table_statistics SET table_securable_name = @new_name
+WHERE table_securable_name = @old_name;
@@ -28,10 +28,18 @@ WHERE ta.securable_type = ? AND ta.securable_id = ?
 ORDER BY t.key, t.value;
 
 -- name: ListAssignmentsForTag :many
-SELECT * FROM tag_assignments WHERE tag_id = ?;
+SELECT * FROM tag_assignments WHERE tag_id = ? ORDER BY id;
 
 -- name: DeleteTagAssignmentsBySecurable :exec
 DELETE FROM tag_assignments WHERE securable_type = ? AND securable_id = ?;
 
 -- name: DeleteTagAssignmentsBySecurableTypes :exec
 DELETE FROM tag_assignments WHERE securable_type IN (?, ?) AND securable_id = ?;
+
+-- name: GetTagAssignmentByTarget :one
+SELECT * FROM tag_assignments
+WHERE tag_id = ? AND securable_type = ? AND securable_id = ? AND column_name IS ?;
+
+-- name: DeleteTagAssignmentByTarget :execrows
+DELETE FROM tag_assignments
+WHERE tag_id = ? AND securable_type = ? AND securable_id = ? AND column_name IS ?;
@@ -1,22 +1,41 @@
 -- name: CreateView :one
-INSERT INTO views (id, schema_id, name, view_definition, comment, properties, owner, source_tables)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
+INSERT INTO views (id, schema_id, name, view_definition, comment, properties, owner, source_tables, materialized, refresh_schedule, catalog_name)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
 
 -- name: GetViewByName :one
 SELECT * FROM views WHERE schema_id = ? AND name = ? AND deleted_at IS NULL;
 
 -- name: ListViews :many
-SELECT * FROM views WHERE schema_id = ? AND deleted_at IS NULL ORDER BY name LIMIT ? OFFSET ?;
+SELECT * FROM views
+WHERE schema_id = sqlc.arg('schema_id') AND deleted_at IS NULL
+  AND (sqlc.narg('owner') IS NULL OR owner = sqlc.narg('owner'))
+ORDER BY name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountViews :one
-SELECT COUNT(*) as cnt FROM views WHERE schema_id = ? AND deleted_at IS NULL;
+SELECT COUNT(*) as cnt FROM views
+WHERE schema_id = sqlc.arg('schema_id') AND deleted_at IS NULL
+  AND (sqlc.narg('owner') IS NULL OR owner = sqlc.narg('owner'));
 
 -- name: DeleteView :exec
 UPDATE views SET deleted_at = datetime('now') WHERE schema_id = ? AND name = ?;
 
--- name: UpdateView :exec
-UPDATE views SET comment = ?, properties = ?, view_definition = ?, source_tables = ?, updated_at = datetime('now')
-WHERE schema_id = ? AND name = ?;
+-- name: UpdateView :execrows
+UPDATE views SET comment = ?, properties = ?, view_definition = ?, source_tables = ?, row_version = row_version + 1, updated_at = datetime('now')
+WHERE schema_id = ? AND name = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: DeleteViewsBySchema :exec
 UPDATE views SET deleted_at = datetime('now') WHERE schema_id = ?;
+
+-- name: ListScheduledViews :many
+SELECT * FROM views
+WHERE materialized = 1 AND refresh_schedule IS NOT NULL AND refresh_schedule != '' AND deleted_at IS NULL
+ORDER BY name;
+
+-- name: MarkViewRefreshed :exec
+UPDATE views SET last_refreshed_at = ? WHERE schema_id = ? AND name = ? AND deleted_at IS NULL;
+
+-- name: RenameView :exec
+UPDATE views SET name = @new_name, updated_at = datetime('now')
+WHERE schema_id = @schema_id AND name = @old_name AND deleted_at IS NULL;
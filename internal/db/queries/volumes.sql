@@ -7,18 +7,26 @@ RETURNING *;
 SELECT * FROM volumes WHERE schema_name = ? AND name = ?;
 
 -- name: ListVolumes :many
-SELECT * FROM volumes WHERE schema_name = ? ORDER BY name LIMIT ? OFFSET ?;
+SELECT * FROM volumes
+WHERE schema_name = sqlc.arg('schema_name')
+  AND (sqlc.narg('owner') IS NULL OR owner = sqlc.narg('owner'))
+ORDER BY name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountVolumes :one
-SELECT COUNT(*) FROM volumes WHERE schema_name = ?;
+SELECT COUNT(*) FROM volumes
+WHERE schema_name = sqlc.arg('schema_name')
+  AND (sqlc.narg('owner') IS NULL OR owner = sqlc.narg('owner'));
 
--- name: UpdateVolume :exec
+-- name: UpdateVolume :execrows
 UPDATE volumes
 SET name = COALESCE(?, name),
     comment = COALESCE(?, comment),
     owner = COALESCE(?, owner),
+    row_version = row_version + 1,
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+  AND (sqlc.narg('expected_row_version') IS NULL OR row_version = sqlc.narg('expected_row_version'));
 
 -- name: DeleteVolume :exec
 DELETE FROM volumes WHERE id = ?;
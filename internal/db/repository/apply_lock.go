@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"duck-demo/internal/domain"
+)
+
+var _ domain.ApplyLockRepository = (*ApplyLockRepo)(nil)
+
+// ApplyLockRepo stores the declarative apply lease in SQLite.
+type ApplyLockRepo struct {
+	db *sql.DB
+}
+
+// NewApplyLockRepo creates a new ApplyLockRepo.
+func NewApplyLockRepo(db *sql.DB) *ApplyLockRepo {
+	return &ApplyLockRepo{db: db}
+}
+
+// Get returns the current lease for the named lock.
+func (r *ApplyLockRepo) Get(ctx context.Context, name string) (*domain.ApplyLock, error) {
+	var lock domain.ApplyLock
+	err := r.db.QueryRowContext(ctx, `
+		SELECT name, lease_id, owner, principal_name, acquired_at, expires_at
+		FROM apply_locks WHERE name = ?
+	`, name).Scan(&lock.Name, &lock.LeaseID, &lock.Owner, &lock.PrincipalName, &lock.AcquiredAt, &lock.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound("lock %q is not held", name)
+		}
+		return nil, mapDBError(err)
+	}
+	return &lock, nil
+}
+
+// TryAcquire stores lock in a single upsert so concurrent applies cannot both
+// win. An existing lease is only replaced when it expired before now or force
+// is set. Timestamps are stored in UTC at second precision so the expiry
+// comparison is a plain string comparison in SQLite.
+func (r *ApplyLockRepo) TryAcquire(ctx context.Context, lock *domain.ApplyLock, now time.Time, force bool) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO apply_locks (name, lease_id, owner, principal_name, acquired_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			lease_id = excluded.lease_id,
+			owner = excluded.owner,
+			principal_name = excluded.principal_name,
+			acquired_at = excluded.acquired_at,
+			expires_at = excluded.expires_at
+		WHERE ? OR apply_locks.expires_at <= ?
+	`, lock.Name, lock.LeaseID, lock.Owner, lock.PrincipalName,
+		lockTime(lock.AcquiredAt), lockTime(lock.ExpiresAt), force, lockTime(now))
+	if err != nil {
+		return false, mapDBError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Release deletes the lease with the given ID. It reports false when the
+// lease is no longer held, e.g. because another apply took it over.
+func (r *ApplyLockRepo) Release(ctx context.Context, name, leaseID string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM apply_locks WHERE name = ? AND lease_id = ?`, name, leaseID)
+	if err != nil {
+		return false, mapDBError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Renew moves the expiry of the lease with the given ID. It reports false
+// when the lease is no longer held, e.g. because another apply took it over.
+func (r *ApplyLockRepo) Renew(ctx context.Context, name, leaseID string, expiresAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE apply_locks SET expires_at = ? WHERE name = ? AND lease_id = ?`,
+		lockTime(expiresAt), name, leaseID)
+	if err != nil {
+		return false, mapDBError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func lockTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/db"
+	"duck-demo/internal/domain"
+)
+
+func TestApplyLockRepo_AcquireContendRelease(t *testing.T) {
+	t.Parallel()
+
+	writeDB, _ := db.OpenTestSQLite(t)
+	repo := NewApplyLockRepo(writeDB)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	lease := func(id string, ttl time.Duration) *domain.ApplyLock {
+		return &domain.ApplyLock{
+			Name: domain.ApplyLockName, LeaseID: id, Owner: id + "@host", PrincipalName: "alice",
+			AcquiredAt: now, ExpiresAt: now.Add(ttl),
+		}
+	}
+
+	ok, err := repo.TryAcquire(ctx, lease("first", time.Minute), now, false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	held, err := repo.Get(ctx, domain.ApplyLockName)
+	require.NoError(t, err)
+	assert.Equal(t, "first", held.LeaseID)
+	assert.True(t, held.ExpiresAt.Equal(now.Add(time.Minute)))
+
+	// A live lease blocks a second acquirer.
+	ok, err = repo.TryAcquire(ctx, lease("second", time.Minute), now.Add(30*time.Second), false)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Once the lease expires it can be taken over.
+	ok, err = repo.TryAcquire(ctx, lease("second", time.Minute), now.Add(time.Minute), false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Force breaks a live lease.
+	ok, err = repo.TryAcquire(ctx, lease("third", time.Minute), now.Add(time.Minute), true)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Only the current holder can renew.
+	renewed, err := repo.Renew(ctx, domain.ApplyLockName, "second", now.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.False(t, renewed)
+
+	renewed, err = repo.Renew(ctx, domain.ApplyLockName, "third", now.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, renewed)
+	held, err = repo.Get(ctx, domain.ApplyLockName)
+	require.NoError(t, err)
+	assert.True(t, held.ExpiresAt.Equal(now.Add(5*time.Minute)))
+
+	// The replaced lease can no longer release the lock.
+	released, err := repo.Release(ctx, domain.ApplyLockName, "second")
+	require.NoError(t, err)
+	assert.False(t, released)
+
+	released, err = repo.Release(ctx, domain.ApplyLockName, "third")
+	require.NoError(t, err)
+	assert.True(t, released)
+
+	_, err = repo.Get(ctx, domain.ApplyLockName)
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
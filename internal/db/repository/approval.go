@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"duck-demo/internal/domain"
+)
+
+var _ domain.ApprovalRepository = (*ApprovalRepo)(nil)
+
+// ApprovalRepo stores approval requests in SQLite.
+type ApprovalRepo struct {
+	db *sql.DB
+}
+
+// NewApprovalRepo creates a new ApprovalRepo.
+func NewApprovalRepo(db *sql.DB) *ApprovalRepo {
+	return &ApprovalRepo{db: db}
+}
+
+const approvalColumns = `id, operation, params, status, requested_by, decided_by, error_message, created_at, decided_at`
+
+// Create inserts a new pending approval request.
+func (r *ApprovalRepo) Create(ctx context.Context, a *domain.ApprovalRequest) (*domain.ApprovalRequest, error) {
+	if a.ID == "" {
+		a.ID = domain.NewID()
+	}
+	params, err := json.Marshal(a.Params)
+	if err != nil {
+		return nil, fmt.Errorf("encode approval params: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO approval_requests (id, operation, params, status, requested_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, a.ID, a.Operation, string(params), domain.ApprovalStatusPending, a.RequestedBy)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	return r.GetByID(ctx, a.ID)
+}
+
+// GetByID returns an approval request by ID.
+func (r *ApprovalRepo) GetByID(ctx context.Context, id string) (*domain.ApprovalRequest, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+approvalColumns+` FROM approval_requests WHERE id = ?`, id)
+	a, err := scanApproval(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound("approval request %q not found", id)
+	}
+	return a, err
+}
+
+// List returns a page of approval requests, newest first.
+func (r *ApprovalRepo) List(ctx context.Context, filter domain.ApprovalFilter) ([]domain.ApprovalRequest, int64, error) {
+	clause := ""
+	var args []interface{}
+	if filter.Status != nil {
+		clause = " WHERE status = ?"
+		args = append(args, *filter.Status)
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM approval_requests`+clause, args...).Scan(&total); err != nil {
+		return nil, 0, mapDBError(err)
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT `+approvalColumns+` FROM approval_requests`+clause+
+		` ORDER BY created_at DESC, id LIMIT ? OFFSET ?`,
+		append(args, filter.Page.Limit(), filter.Page.Offset())...)
+	if err != nil {
+		return nil, 0, mapDBError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var out []domain.ApprovalRequest
+	for rows.Next() {
+		a, err := scanApproval(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, *a)
+	}
+	return out, total, rows.Err()
+}
+
+// Decide moves a pending request to status in a single conditional update,
+// so two admins deciding at once cannot both succeed.
+func (r *ApprovalRepo) Decide(ctx context.Context, id, status, decidedBy string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE approval_requests SET status = ?, decided_by = ?, decided_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?
+	`, status, decidedBy, id, domain.ApprovalStatusPending)
+	if err != nil {
+		return false, mapDBError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// SetFailed marks an approved request whose operation failed.
+func (r *ApprovalRepo) SetFailed(ctx context.Context, id, message string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE approval_requests SET status = ?, error_message = ? WHERE id = ?
+	`, domain.ApprovalStatusFailed, message, id)
+	return mapDBError(err)
+}
+
+type approvalRowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanApproval(row approvalRowScanner) (*domain.ApprovalRequest, error) {
+	var (
+		a         domain.ApprovalRequest
+		params    string
+		decidedBy sql.NullString
+		errMsg    sql.NullString
+		decidedAt sql.NullTime
+	)
+	if err := row.Scan(&a.ID, &a.Operation, &params, &a.Status, &a.RequestedBy,
+		&decidedBy, &errMsg, &a.CreatedAt, &decidedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(params), &a.Params); err != nil {
+		return nil, fmt.Errorf("decode approval params: %w", err)
+	}
+	if decidedBy.Valid {
+		a.DecidedBy = &decidedBy.String
+	}
+	if errMsg.Valid {
+		a.ErrorMessage = &errMsg.String
+	}
+	if decidedAt.Valid {
+		a.DecidedAt = &decidedAt.Time
+	}
+	return &a, nil
+}
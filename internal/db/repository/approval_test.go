@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/db"
+	"duck-demo/internal/domain"
+)
+
+func setupApprovalRepo(t *testing.T) *ApprovalRepo {
+	t.Helper()
+	writeDB, _ := db.OpenTestSQLite(t)
+	return NewApprovalRepo(writeDB)
+}
+
+func TestApprovalRepo_CreateAndGet(t *testing.T) {
+	t.Parallel()
+	repo := setupApprovalRepo(t)
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &domain.ApprovalRequest{
+		Operation:   domain.ApprovalOpSetAdmin,
+		Params:      map[string]string{"principal_id": "p-1"},
+		RequestedBy: "alice",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	assert.Equal(t, domain.ApprovalStatusPending, created.Status)
+	assert.Equal(t, map[string]string{"principal_id": "p-1"}, created.Params)
+	assert.Nil(t, created.DecidedBy)
+	assert.Nil(t, created.DecidedAt)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	_, err = repo.GetByID(ctx, "missing")
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
+
+func TestApprovalRepo_Decide(t *testing.T) {
+	t.Parallel()
+	repo := setupApprovalRepo(t)
+	ctx := context.Background()
+
+	a, err := repo.Create(ctx, &domain.ApprovalRequest{Operation: domain.ApprovalOpDeleteCatalog, RequestedBy: "alice"})
+	require.NoError(t, err)
+
+	ok, err := repo.Decide(ctx, a.ID, domain.ApprovalStatusApproved, "bob")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.Decide(ctx, a.ID, domain.ApprovalStatusRejected, "carol")
+	require.NoError(t, err)
+	assert.False(t, ok, "a decided request cannot be decided again")
+
+	got, err := repo.GetByID(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ApprovalStatusApproved, got.Status)
+	require.NotNil(t, got.DecidedBy)
+	assert.Equal(t, "bob", *got.DecidedBy)
+	assert.NotNil(t, got.DecidedAt)
+
+	require.NoError(t, repo.SetFailed(ctx, a.ID, "catalog not found"))
+	got, err = repo.GetByID(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ApprovalStatusFailed, got.Status)
+	require.NotNil(t, got.ErrorMessage)
+	assert.Equal(t, "catalog not found", *got.ErrorMessage)
+}
+
+func TestApprovalRepo_ListByStatus(t *testing.T) {
+	t.Parallel()
+	repo := setupApprovalRepo(t)
+	ctx := context.Background()
+
+	first, err := repo.Create(ctx, &domain.ApprovalRequest{Operation: domain.ApprovalOpSetAdmin, RequestedBy: "alice"})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &domain.ApprovalRequest{Operation: domain.ApprovalOpDeleteCatalog, RequestedBy: "alice"})
+	require.NoError(t, err)
+	_, err = repo.Decide(ctx, first.ID, domain.ApprovalStatusRejected, "bob")
+	require.NoError(t, err)
+
+	all, total, err := repo.List(ctx, domain.ApprovalFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, all, 2)
+
+	pending := domain.ApprovalStatusPending
+	got, total, err := repo.List(ctx, domain.ApprovalFilter{Status: &pending})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, got, 1)
+	assert.Equal(t, domain.ApprovalOpDeleteCatalog, got[0].Operation)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	dbstore "duck-demo/internal/db/dbstore"
+	"duck-demo/internal/db/mapper"
+	"duck-demo/internal/domain"
+)
+
+// auditArchiveDeleteBatchSize bounds how many archived entries one DELETE
+// removes, so a large purge never holds the SQLite write lock for long.
+const auditArchiveDeleteBatchSize = 1000
+
+// AuditArchiveRepo implements domain.AuditArchiveRepository using SQLite.
+type AuditArchiveRepo struct {
+	q *dbstore.Queries
+
+	deleteBatchSize int64
+}
+
+// NewAuditArchiveRepo creates a new AuditArchiveRepo.
+func NewAuditArchiveRepo(db *sql.DB) *AuditArchiveRepo {
+	return &AuditArchiveRepo{q: dbstore.New(db), deleteBatchSize: auditArchiveDeleteBatchSize}
+}
+
+// Latest returns the most recent archive, or nil if nothing was archived yet.
+func (r *AuditArchiveRepo) Latest(ctx context.Context) (*domain.AuditArchive, error) {
+	row, err := r.q.GetLatestAuditArchive(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapper.AuditArchiveFromDB(row), nil
+}
+
+// ListUnarchived returns up to limit entries recorded before the cutoff that
+// follow the latest archive, oldest first.
+func (r *AuditArchiveRepo) ListUnarchived(ctx context.Context, before time.Time, limit int) ([]domain.AuditEntry, error) {
+	rows, err := r.q.ListUnarchivedAuditLogs(ctx, dbstore.ListUnarchivedAuditLogsParams{
+		Before: before.UTC().Format("2006-01-02 15:04:05"),
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]domain.AuditEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = *mapper.AuditEntryFromDB(row)
+	}
+	return entries, nil
+}
+
+// Create records an archive. Its last entry becomes the archived mark.
+func (r *AuditArchiveRepo) Create(ctx context.Context, a *domain.AuditArchive) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	err := r.q.InsertAuditArchive(ctx, dbstore.InsertAuditArchiveParams{
+		ID:             a.ID,
+		Location:       a.Location,
+		EntryCount:     a.EntryCount,
+		FirstEntryID:   a.FirstEntryID,
+		LastEntryID:    a.LastEntryID,
+		FirstCreatedAt: a.FirstCreatedAt.UTC().Format("2006-01-02 15:04:05"),
+		LastCreatedAt:  a.LastCreatedAt.UTC().Format("2006-01-02 15:04:05"),
+		PrevHash:       a.PrevHash,
+		Hash:           a.Hash,
+	})
+	return mapDBError(err)
+}
+
+// DeleteArchived deletes the entries covered by archives, in bounded batches,
+// and returns how many were deleted. Entries after the latest archive's last
+// entry are never deleted.
+func (r *AuditArchiveRepo) DeleteArchived(ctx context.Context) (int64, error) {
+	var deleted int64
+	for {
+		n, err := r.q.DeleteArchivedAuditLogsBatch(ctx, r.deleteBatchSize)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+		if n < r.deleteBatchSize {
+			return deleted, nil
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internaldb "duck-demo/internal/db"
+	"duck-demo/internal/domain"
+)
+
+func TestAuditArchiveRepo_OnlyArchivedEntriesAreDeleted(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	auditRepo, archiveRepo := NewAuditRepo(writeDB), NewAuditArchiveRepo(writeDB)
+	archiveRepo.deleteBatchSize = 2 // make the delete take several batches
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	insert := func(age time.Duration) {
+		require.NoError(t, auditRepo.Insert(ctx, &domain.AuditEntry{
+			ID:            uuid.New().String(),
+			PrincipalName: "alice",
+			Action:        "GRANT",
+			Status:        "ALLOWED",
+		}))
+		// Insert assigns its own ID, so find the new row by its fresh timestamp.
+		_, err := writeDB.ExecContext(ctx,
+			`UPDATE audit_log SET created_at = ? WHERE created_at > ?`,
+			now.Add(-age).Format("2006-01-02 15:04:05"), now.Add(-time.Hour).Format("2006-01-02 15:04:05"))
+		require.NoError(t, err)
+	}
+	for i := 5; i > 0; i-- {
+		insert(time.Duration(i) * 24 * time.Hour)
+	}
+
+	latest, err := archiveRepo.Latest(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	deleted, err := archiveRepo.DeleteArchived(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "nothing is archived yet")
+
+	unarchived, err := archiveRepo.ListUnarchived(ctx, now.Add(-36*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, unarchived, 4, "entries older than the cutoff, oldest first")
+	assert.True(t, unarchived[0].CreatedAt.Before(unarchived[3].CreatedAt))
+
+	// Archive the three oldest entries only.
+	archived := unarchived[:3]
+	require.NoError(t, archiveRepo.Create(ctx, &domain.AuditArchive{
+		Location:       "s3://audit/archive-1.ndjson",
+		EntryCount:     3,
+		FirstEntryID:   archived[0].ID,
+		LastEntryID:    archived[2].ID,
+		FirstCreatedAt: archived[0].CreatedAt,
+		LastCreatedAt:  archived[2].CreatedAt,
+		Hash:           "sha256:one",
+	}))
+
+	latest, err = archiveRepo.Latest(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, archived[2].ID, latest.LastEntryID)
+	assert.Equal(t, "sha256:one", latest.Hash)
+
+	unarchived, err = archiveRepo.ListUnarchived(ctx, now.Add(-36*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, unarchived, 1, "the fourth old entry is not archived yet")
+
+	deleted, err = archiveRepo.DeleteArchived(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+
+	_, total, err := auditRepo.List(ctx, domain.AuditFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total, "unarchived entries are never deleted")
+}
+
+func TestAuditArchiveRepo_RejectsForkedChain(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	archiveRepo := NewAuditArchiveRepo(writeDB)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	archive := func(hash string) *domain.AuditArchive {
+		return &domain.AuditArchive{
+			Location:       "s3://audit/" + hash + ".ndjson",
+			EntryCount:     1,
+			FirstEntryID:   uuid.New().String(),
+			LastEntryID:    uuid.New().String(),
+			FirstCreatedAt: now,
+			LastCreatedAt:  now,
+			PrevHash:       "sha256:one",
+			Hash:           hash,
+		}
+	}
+	require.NoError(t, archiveRepo.Create(ctx, archive("sha256:two")))
+
+	err := archiveRepo.Create(ctx, archive("sha256:other"))
+	require.Error(t, err, "a second archive chaining off the same predecessor forks the chain")
+	assert.ErrorAs(t, err, new(*domain.ConflictError))
+}
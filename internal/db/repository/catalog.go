@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -96,3 +97,79 @@ func (r *CatalogRepo) GetMetastoreSummary(ctx context.Context) (*domain.Metastor
 
 	return summary, nil
 }
+
+// GetCatalogStats counts the catalog's schemas, tables and views and sums
+// the row counts and data sizes DuckLake keeps in ducklake_table_stats.
+// NOTE: ducklake_* tables are not managed by sqlc.
+func (r *CatalogRepo) GetCatalogStats(ctx context.Context, largest int) (*domain.CatalogStats, error) {
+	stats := &domain.CatalogStats{CatalogName: r.catalogName, LargestTables: []domain.TableSize{}}
+
+	rows, err := r.metaDB.QueryContext(ctx,
+		`SELECT schema_id, schema_name FROM ducklake_schema WHERE end_snapshot IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	schemaIDs := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		schemaIDs[id] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	stats.SchemaCount = int64(len(schemaIDs))
+
+	if err := r.metaDB.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(st.record_count), 0), COALESCE(SUM(st.file_size_bytes), 0)
+		 FROM ducklake_table t
+		 LEFT JOIN ducklake_table_stats st ON st.table_id = t.table_id
+		 WHERE t.end_snapshot IS NULL`).Scan(&stats.TableCount, &stats.TotalRows, &stats.TotalBytes); err != nil {
+		return nil, fmt.Errorf("aggregate table statistics: %w", err)
+	}
+
+	// Views and external tables live in the control plane, per schema.
+	for id, name := range schemaIDs {
+		views, err := r.q.CountViews(ctx, dbstore.CountViewsParams{SchemaID: domain.DuckLakeIDToString(id)})
+		if err != nil {
+			return nil, fmt.Errorf("count views: %w", err)
+		}
+		stats.ViewCount += views
+		external, err := r.q.CountExternalTables(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("count external tables: %w", err)
+		}
+		stats.TableCount += external
+	}
+
+	if largest <= 0 {
+		return stats, nil
+	}
+	largestRows, err := r.metaDB.QueryContext(ctx,
+		`SELECT s.schema_name, t.table_name, COALESCE(st.record_count, 0), COALESCE(st.file_size_bytes, 0)
+		 FROM ducklake_table t
+		 JOIN ducklake_schema s ON s.schema_id = t.schema_id AND s.end_snapshot IS NULL
+		 LEFT JOIN ducklake_table_stats st ON st.table_id = t.table_id
+		 WHERE t.end_snapshot IS NULL
+		 ORDER BY 4 DESC, 3 DESC, s.schema_name, t.table_name
+		 LIMIT ?`, largest)
+	if err != nil {
+		return nil, fmt.Errorf("list largest tables: %w", err)
+	}
+	defer largestRows.Close() //nolint:errcheck
+	for largestRows.Next() {
+		var ts domain.TableSize
+		if err := largestRows.Scan(&ts.SchemaName, &ts.TableName, &ts.RowCount, &ts.SizeBytes); err != nil {
+			return nil, err
+		}
+		stats.LargestTables = append(stats.LargestTables, ts)
+	}
+	if err := largestRows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -46,7 +46,7 @@ func (r *CatalogRepo) CreateExternalTable(ctx context.Context, schemaName string
 	if _, err := r.duckDB.ExecContext(ctx, viewSQL); err != nil {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "already exists") {
-			return nil, domain.ErrConflict("table %q already exists in schema %q", req.Name, schemaName)
+			return nil, domain.ErrAlreadyExists("table %q already exists in schema %q", req.Name, schemaName)
 		}
 		return nil, fmt.Errorf("create external table view: %w", err)
 	}
@@ -165,6 +165,29 @@ func (r *CatalogRepo) deleteExternalTable(ctx context.Context, schemaName, table
 	return nil
 }
 
+// ListFiles returns the files matching a glob pattern, in path order.
+func (r *CatalogRepo) ListFiles(ctx context.Context, pattern string) ([]string, error) {
+	globSQL, err := ddl.GlobFilesSQL(pattern)
+	if err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+	rows, err := r.duckDB.QueryContext(ctx, globSQL)
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var files []string
+	for rows.Next() {
+		var f string
+		if err := rows.Scan(&f); err != nil {
+			return nil, fmt.Errorf("scan file: %w", err)
+		}
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
 // discoverColumns runs a DESCRIBE query on DuckDB to discover column metadata.
 func (r *CatalogRepo) discoverColumns(ctx context.Context, sourcePath, fileFormat string) ([]domain.CreateColumnDef, error) {
 	descSQL, err := ddl.DiscoverColumnsSQL(sourcePath, fileFormat)
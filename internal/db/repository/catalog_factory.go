@@ -48,7 +48,16 @@ func NewCatalogRepoFactory(
 }
 
 // ForCatalog returns a CatalogRepository for the given catalog name.
+// An empty name resolves to the registered default catalog.
 func (f *CatalogRepoFactory) ForCatalog(ctx context.Context, catalogName string) (domain.CatalogRepository, error) {
+	if catalogName == "" {
+		name, err := f.DefaultCatalogName(ctx)
+		if err != nil {
+			return nil, err
+		}
+		catalogName = name
+	}
+
 	f.mu.RLock()
 	if entry, ok := f.cache[catalogName]; ok {
 		f.mu.RUnlock()
@@ -68,6 +77,9 @@ func (f *CatalogRepoFactory) ForCatalog(ctx context.Context, catalogName string)
 	if err != nil {
 		return nil, fmt.Errorf("lookup catalog %q: %w", catalogName, err)
 	}
+	if err := checkCatalogAttached(reg); err != nil {
+		return nil, err
+	}
 
 	metaDB, err := f.openMetastore(reg)
 	if err != nil {
@@ -80,6 +92,25 @@ func (f *CatalogRepoFactory) ForCatalog(ctx context.Context, catalogName string)
 	return repo, nil
 }
 
+// checkCatalogAttached reports a catalog whose attach failed as unavailable,
+// so requests against it get a 503 naming the cause instead of an opaque
+// DuckDB "catalog does not exist" error.
+func checkCatalogAttached(reg *domain.CatalogRegistration) error {
+	if reg.Status != domain.CatalogStatusError {
+		return nil
+	}
+	return domain.ErrUnavailable("catalog %q is unavailable: attach failed: %s", reg.Name, reg.StatusMessage)
+}
+
+// DefaultCatalogName returns the name of the registered default catalog.
+func (f *CatalogRepoFactory) DefaultCatalogName(ctx context.Context) (string, error) {
+	reg, err := f.catalogRegRepo.GetDefault(ctx)
+	if err != nil {
+		return "", fmt.Errorf("lookup default catalog: %w", err)
+	}
+	return reg.Name, nil
+}
+
 // Evict removes and closes a cached CatalogRepo when a catalog is deleted.
 func (f *CatalogRepoFactory) Evict(catalogName string) {
 	f.mu.Lock()
@@ -6,12 +6,33 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"strings"
 	"time"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/domain"
 )
 
+// ownedNames returns the catalog_metadata names of securableType objects that
+// owner owns. Ownership lives in the control plane, not in the metastore, so
+// list queries filter on these names rather than joining.
+func (r *CatalogRepo) ownedNames(ctx context.Context, securableType, owner string) ([]string, error) {
+	return r.q.ListCatalogMetadataNamesByOwner(ctx, dbstore.ListCatalogMetadataNamesByOwnerParams{
+		SecurableType: securableType,
+		Owner:         sql.NullString{String: owner, Valid: true},
+	})
+}
+
+// inClause returns "column IN (?, ...)" with one placeholder per value, and
+// the values as query arguments. values must not be empty.
+func inClause(column string, values []string) (string, []interface{}) {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return column + " IN (?" + strings.Repeat(", ?", len(values)-1) + ")", args
+}
+
 // resolveSchemaID looks up the ducklake_schema row by name and returns its ID.
 func (r *CatalogRepo) resolveSchemaID(ctx context.Context, schemaName string) (int64, error) {
 	var schemaID int64
@@ -100,6 +121,69 @@ func (r *CatalogRepo) loadColumns(ctx context.Context, tableID string) ([]domain
 	return cols, rows.Err()
 }
 
+// loadPartitionColumns returns the current partition key of a DuckLake table
+// in key order (ducklake_partition_* — not managed by sqlc). Keys with a
+// transform other than identity are rendered as transform(column). A table
+// without partitioning, or a metastore without partition tables, yields nil.
+func (r *CatalogRepo) loadPartitionColumns(ctx context.Context, tableID string) []string {
+	rows, err := r.metaDB.QueryContext(ctx,
+		`SELECT c.column_name, COALESCE(pc.transform, 'identity')
+		 FROM ducklake_partition_info pi
+		 JOIN ducklake_partition_column pc ON pc.partition_id = pi.partition_id AND pc.table_id = pi.table_id
+		 JOIN ducklake_column c ON c.table_id = pc.table_id AND c.column_id = pc.column_id AND c.end_snapshot IS NULL
+		 WHERE pi.table_id = ? AND pi.end_snapshot IS NULL
+		 ORDER BY pc.partition_key_index`,
+		tableID)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var cols []string
+	for rows.Next() {
+		var name, transform string
+		if err := rows.Scan(&name, &transform); err != nil {
+			return nil
+		}
+		if transform != "" && transform != "identity" {
+			name = transform + "(" + name + ")"
+		}
+		cols = append(cols, name)
+	}
+	if rows.Err() != nil {
+		return nil
+	}
+	return cols
+}
+
+// writeCatalogMetadata upserts the metadata of a securable. A non-nil
+// expectedRowVersion makes the write conditional on the stored row version,
+// where version 0 means the securable has no metadata yet.
+func (r *CatalogRepo) writeCatalogMetadata(ctx context.Context, arg dbstore.UpsertCatalogMetadataParams, expectedRowVersion *int64) error {
+	if expectedRowVersion == nil {
+		return r.q.UpsertCatalogMetadata(ctx, arg)
+	}
+
+	var n int64
+	var err error
+	if *expectedRowVersion == 0 {
+		n, err = r.q.InsertCatalogMetadataIfAbsent(ctx, dbstore.InsertCatalogMetadataIfAbsentParams(arg))
+	} else {
+		n, err = r.q.UpdateCatalogMetadataIfVersion(ctx, dbstore.UpdateCatalogMetadataIfVersionParams{
+			Comment:            arg.Comment,
+			Properties:         arg.Properties,
+			Owner:              arg.Owner,
+			SecurableType:      arg.SecurableType,
+			SecurableName:      arg.SecurableName,
+			ExpectedRowVersion: *expectedRowVersion,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return checkRowVersion(n, expectedRowVersion, arg.SecurableType, arg.SecurableName)
+}
+
 // enrichSchemaMetadata reads catalog_metadata for a schema via sqlc.
 func (r *CatalogRepo) enrichSchemaMetadata(ctx context.Context, s *domain.SchemaDetail) {
 	row, err := r.q.GetCatalogMetadata(ctx, dbstore.GetCatalogMetadataParams{
@@ -115,6 +199,7 @@ func (r *CatalogRepo) enrichSchemaMetadata(ctx context.Context, s *domain.Schema
 	if row.Owner.Valid {
 		s.Owner = row.Owner.String
 	}
+	s.RowVersion = row.RowVersion
 	if row.Properties.Valid {
 		_ = json.Unmarshal([]byte(row.Properties.String), &s.Properties)
 	}
@@ -153,6 +238,7 @@ func (r *CatalogRepo) enrichTableMetadata(ctx context.Context, t *domain.TableDe
 	if row.Owner.Valid {
 		t.Owner = row.Owner.String
 	}
+	t.RowVersion = row.RowVersion
 	if row.Properties.Valid {
 		_ = json.Unmarshal([]byte(row.Properties.String), &t.Properties)
 	}
@@ -70,6 +70,37 @@ func createCatalogDuckLakeTables(t *testing.T, db *sql.DB) {
 			nulls_allowed INTEGER DEFAULT 1,
 			end_snapshot  INTEGER
 		)`,
+		`CREATE TABLE IF NOT EXISTS ducklake_partition_info (
+			partition_id   INTEGER NOT NULL,
+			table_id       INTEGER NOT NULL,
+			begin_snapshot INTEGER,
+			end_snapshot   INTEGER
+		)`,
+		`CREATE TABLE IF NOT EXISTS ducklake_partition_column (
+			partition_id        INTEGER NOT NULL,
+			table_id            INTEGER NOT NULL,
+			partition_key_index INTEGER NOT NULL,
+			column_id           INTEGER NOT NULL,
+			transform           TEXT
+		)`,
+		`CREATE TABLE IF NOT EXISTS ducklake_data_file (
+			data_file_id    INTEGER PRIMARY KEY AUTOINCREMENT,
+			table_id        INTEGER NOT NULL,
+			begin_snapshot  INTEGER,
+			end_snapshot    INTEGER,
+			path            TEXT,
+			file_size_bytes INTEGER
+		)`,
+		`CREATE TABLE IF NOT EXISTS ducklake_table_stats (
+			table_id        INTEGER NOT NULL,
+			record_count    INTEGER,
+			next_row_id     INTEGER,
+			file_size_bytes INTEGER
+		)`,
+		`CREATE TABLE IF NOT EXISTS ducklake_snapshot (
+			snapshot_id   INTEGER PRIMARY KEY,
+			snapshot_time TEXT
+		)`,
 	}
 	for _, stmt := range stmts {
 		_, err := db.ExecContext(context.Background(), stmt)
@@ -112,6 +143,37 @@ func seedColumn(t *testing.T, db *sql.DB, tableID int64, name, colType string, n
 	require.NoError(t, err)
 }
 
+// seedPartition records a partition key for a table in
+// ducklake_partition_info/ducklake_partition_column, one key per column name.
+func seedPartition(t *testing.T, db *sql.DB, tableID int64, columns ...string) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO ducklake_partition_info (partition_id, table_id) VALUES (1, ?)`, tableID)
+	require.NoError(t, err)
+	for i, name := range columns {
+		_, err := db.ExecContext(ctx,
+			`INSERT INTO ducklake_partition_column (partition_id, table_id, partition_key_index, column_id, transform)
+			 SELECT 1, table_id, ?, column_id, 'identity' FROM ducklake_column WHERE table_id = ? AND column_name = ?`,
+			i, tableID, name)
+		require.NoError(t, err)
+	}
+}
+
+// seedDataFile records a data file of the given size for a table. A file
+// with live=false has been replaced and is only read by older snapshots.
+func seedDataFile(t *testing.T, db *sql.DB, tableID int64, size int64, live bool) {
+	t.Helper()
+	var end sql.NullInt64
+	if !live {
+		end = sql.NullInt64{Int64: 2, Valid: true}
+	}
+	_, err := db.ExecContext(context.Background(),
+		`INSERT INTO ducklake_data_file (table_id, begin_snapshot, end_snapshot, path, file_size_bytes) VALUES (?, 1, ?, 'data.parquet', ?)`,
+		tableID, end, size)
+	require.NoError(t, err)
+}
+
 // createDuckLakeTables is a package-wide alias used by introspection and search
 // tests. It delegates to createCatalogDuckLakeTables which includes the full
 // column set (path, path_is_relative, nulls_allowed, ducklake_metadata).
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"duck-demo/internal/ddl"
+	"duck-demo/internal/domain"
+)
+
+// CompactTable merges a MANAGED table's small data files into larger ones
+// and reports the table's live file count and size before and after.
+// The replaced files stay on storage until a vacuum cleans them up.
+func (r *CatalogRepo) CompactTable(ctx context.Context, schemaName, tableName string) (*domain.TableMaintenanceResult, error) {
+	tableID, err := r.maintainableTableID(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := ddl.MergeAdjacentFiles(r.catalogName, schemaName, tableName)
+	if err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+
+	result := &domain.TableMaintenanceResult{Operation: domain.TableMaintenanceCompact}
+	if result.FilesBefore, result.BytesBefore, err = r.countDataFiles(ctx, tableID, true); err != nil {
+		return nil, err
+	}
+	if _, err := r.duckDB.ExecContext(ctx, stmt); err != nil {
+		return nil, fmt.Errorf("compact table: %w", err)
+	}
+	r.refreshMetaDB(ctx)
+	if result.FilesAfter, result.BytesAfter, err = r.countDataFiles(ctx, tableID, true); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// VacuumTable expires snapshots older than olderThan and deletes the data
+// files no remaining snapshot references. Snapshot expiry applies to the
+// whole catalog; the file counts report the effect on the given table.
+func (r *CatalogRepo) VacuumTable(ctx context.Context, schemaName, tableName string, olderThan time.Duration) (*domain.TableMaintenanceResult, error) {
+	tableID, err := r.maintainableTableID(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	expireStmt, err := ddl.ExpireSnapshots(r.catalogName, olderThan)
+	if err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+	cleanupStmt, err := ddl.CleanupOldFiles(r.catalogName)
+	if err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+
+	result := &domain.TableMaintenanceResult{Operation: domain.TableMaintenanceVacuum}
+	if result.FilesBefore, result.BytesBefore, err = r.countDataFiles(ctx, tableID, false); err != nil {
+		return nil, err
+	}
+	snapshotsBefore, err := r.countSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.duckDB.ExecContext(ctx, expireStmt); err != nil {
+		return nil, fmt.Errorf("expire snapshots: %w", err)
+	}
+	if _, err := r.duckDB.ExecContext(ctx, cleanupStmt); err != nil {
+		return nil, fmt.Errorf("clean up old files: %w", err)
+	}
+	r.refreshMetaDB(ctx)
+
+	if result.FilesAfter, result.BytesAfter, err = r.countDataFiles(ctx, tableID, false); err != nil {
+		return nil, err
+	}
+	snapshotsAfter, err := r.countSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.SnapshotsExpired = max(snapshotsBefore-snapshotsAfter, 0)
+	return result, nil
+}
+
+// maintainableTableID resolves a table's DuckLake table_id, rejecting
+// EXTERNAL tables whose files DuckLake does not manage.
+func (r *CatalogRepo) maintainableTableID(ctx context.Context, schemaName, tableName string) (int64, error) {
+	tbl, err := r.GetTable(ctx, schemaName, tableName)
+	if err != nil {
+		return 0, err
+	}
+	if tbl.TableType == domain.TableTypeExternal {
+		return 0, domain.ErrValidation("table %q.%q is EXTERNAL; only MANAGED tables can be compacted or vacuumed", schemaName, tableName)
+	}
+	id, err := strconv.ParseInt(tbl.TableID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse table id %q: %w", tbl.TableID, err)
+	}
+	return id, nil
+}
+
+// countDataFiles returns the number and total size of a table's data files.
+// With liveOnly it counts only the files the current snapshot reads;
+// otherwise it also counts files that older snapshots still reference.
+// NOTE: ducklake_data_file is not managed by sqlc.
+func (r *CatalogRepo) countDataFiles(ctx context.Context, tableID int64, liveOnly bool) (files, size int64, err error) {
+	query := `SELECT COUNT(*), COALESCE(SUM(file_size_bytes), 0) FROM ducklake_data_file WHERE table_id = ?`
+	if liveOnly {
+		query += ` AND end_snapshot IS NULL`
+	}
+	if err := r.metaDB.QueryRowContext(ctx, query, tableID).Scan(&files, &size); err != nil {
+		return 0, 0, fmt.Errorf("count data files: %w", err)
+	}
+	return files, size, nil
+}
+
+// countSnapshots returns the number of snapshots the catalog retains.
+func (r *CatalogRepo) countSnapshots(ctx context.Context) (int64, error) {
+	var n int64
+	if err := r.metaDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM ducklake_snapshot`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count snapshots: %w", err)
+	}
+	return n, nil
+}
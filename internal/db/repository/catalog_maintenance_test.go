@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+func TestCatalogRepo_CountDataFiles(t *testing.T) {
+	repo := setupCatalogRepo(t)
+	ctx := context.Background()
+
+	schemaID := seedSchema(t, repo.metaDB, "public")
+	tableID := seedTable(t, repo.metaDB, schemaID, "events")
+	otherID := seedTable(t, repo.metaDB, schemaID, "other")
+	seedDataFile(t, repo.metaDB, tableID, 100, true)
+	seedDataFile(t, repo.metaDB, tableID, 200, true)
+	seedDataFile(t, repo.metaDB, tableID, 400, false)
+	seedDataFile(t, repo.metaDB, otherID, 800, true)
+
+	t.Run("live files only", func(t *testing.T) {
+		files, size, err := repo.countDataFiles(ctx, tableID, true)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), files)
+		assert.Equal(t, int64(300), size)
+	})
+
+	t.Run("including files of older snapshots", func(t *testing.T) {
+		files, size, err := repo.countDataFiles(ctx, tableID, false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), files)
+		assert.Equal(t, int64(700), size)
+	})
+
+	t.Run("table without files", func(t *testing.T) {
+		files, size, err := repo.countDataFiles(ctx, seedTable(t, repo.metaDB, schemaID, "empty"), true)
+		require.NoError(t, err)
+		assert.Zero(t, files)
+		assert.Zero(t, size)
+	})
+}
+
+func TestCatalogRepo_CompactTable_NotFound(t *testing.T) {
+	repo := setupCatalogRepo(t)
+	ctx := context.Background()
+	seedSchema(t, repo.metaDB, "public")
+
+	_, err := repo.CompactTable(ctx, "public", "missing")
+	require.Error(t, err)
+	var nf *domain.NotFoundError
+	assert.ErrorAs(t, err, &nf)
+
+	_, err = repo.VacuumTable(ctx, "public", "missing", domain.DefaultVacuumOlderThan)
+	require.Error(t, err)
+	assert.ErrorAs(t, err, &nf)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	dbstore "duck-demo/internal/db/dbstore"
@@ -88,7 +89,8 @@ func (r *CatalogRegistrationRepo) List(ctx context.Context, page domain.PageRequ
 func (r *CatalogRegistrationRepo) Update(ctx context.Context, id string, req domain.UpdateCatalogRegistrationRequest) (*domain.CatalogRegistration, error) {
 	// We need to pass proper COALESCE-friendly values
 	params := dbstore.UpdateCatalogParams{
-		ID: id,
+		ID:                 id,
+		ExpectedRowVersion: rowVersionArg(req.ExpectedRowVersion),
 	}
 	if req.Comment != nil {
 		params.Comment = sql.NullString{String: *req.Comment, Valid: true}
@@ -101,6 +103,9 @@ func (r *CatalogRegistrationRepo) Update(ctx context.Context, id string, req dom
 	}
 
 	row, err := r.q.UpdateCatalog(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) && req.ExpectedRowVersion != nil {
+		return nil, staleRowVersion("catalog", id)
+	}
 	if err != nil {
 		return nil, mapDBError(err)
 	}
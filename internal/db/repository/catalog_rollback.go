@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"duck-demo/internal/ddl"
+	"duck-demo/internal/domain"
+)
+
+// RollbackTable restores a MANAGED table's rows to the given snapshot. The
+// restore is an ordinary write: it commits a new snapshot, so the table's
+// history is kept and a rollback can itself be rolled back. Without
+// req.Confirm it only reports the row counts the rollback would produce.
+// Only data is restored; the table's columns must not have changed since
+// the target snapshot.
+func (r *CatalogRepo) RollbackTable(ctx context.Context, schemaName, tableName string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	tbl, err := r.GetTable(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if tbl.TableType == domain.TableTypeExternal {
+		return nil, domain.ErrValidation("table %q.%q is EXTERNAL; only MANAGED tables can be rolled back", schemaName, tableName)
+	}
+	tableID, err := strconv.ParseInt(tbl.TableID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse table id %q: %w", tbl.TableID, err)
+	}
+
+	current, err := r.currentSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := r.resolveRollbackSnapshot(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if target >= current {
+		return nil, domain.ErrValidation("snapshot %d is not older than the current snapshot %d", target, current)
+	}
+	if err := r.checkTableExistedAt(ctx, tableID, schemaName, tableName, target); err != nil {
+		return nil, err
+	}
+
+	source, err := ddl.TableAtSnapshot(r.catalogName, schemaName, tableName, target)
+	if err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+	if err := r.checkSameColumns(ctx, schemaName, tableName, source, target); err != nil {
+		return nil, err
+	}
+
+	result := &domain.TableRollbackResult{CurrentSnapshot: current, TargetSnapshot: target}
+	if result.RowsBefore, err = r.countRows(ctx, r.qualifiedTable(schemaName, tableName)); err != nil {
+		return nil, err
+	}
+	if result.RowsAfter, err = r.countRows(ctx, source); err != nil {
+		return nil, err
+	}
+	if !req.Confirm {
+		return result, nil
+	}
+
+	stmts, err := ddl.RestoreTableFromSnapshot(r.catalogName, schemaName, tableName, target)
+	if err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+	if err := r.execInDuckDBTx(ctx, stmts); err != nil {
+		return nil, fmt.Errorf("restore table: %w", err)
+	}
+	r.refreshMetaDB(ctx)
+
+	newSnapshot, err := r.currentSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAfter, err = r.countRows(ctx, r.qualifiedTable(schemaName, tableName)); err != nil {
+		return nil, err
+	}
+	result.NewSnapshot = &newSnapshot
+	result.Applied = true
+	return result, nil
+}
+
+// resolveRollbackSnapshot returns the snapshot a rollback targets: the given
+// snapshot ID, which must still be retained, or the latest snapshot taken at
+// or before the given timestamp.
+// NOTE: ducklake_snapshot is not managed by sqlc.
+func (r *CatalogRepo) resolveRollbackSnapshot(ctx context.Context, req domain.RollbackTableRequest) (int64, error) {
+	if req.SnapshotID != nil {
+		var n int64
+		if err := r.metaDB.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM ducklake_snapshot WHERE snapshot_id = ?`, *req.SnapshotID).Scan(&n); err != nil {
+			return 0, fmt.Errorf("look up snapshot: %w", err)
+		}
+		if n == 0 {
+			return 0, domain.ErrNotFound("snapshot %d not found; it may have been expired by a vacuum", *req.SnapshotID)
+		}
+		return *req.SnapshotID, nil
+	}
+
+	query := fmt.Sprintf(`SELECT MAX(snapshot_id) FROM ducklake_snapshots(%s) WHERE snapshot_time <= ?`,
+		ddl.QuoteLiteral(r.catalogName))
+	var id sql.NullInt64
+	if err := r.duckDB.QueryRowContext(ctx, query, *req.Timestamp).Scan(&id); err != nil {
+		return 0, fmt.Errorf("resolve snapshot at timestamp: %w", err)
+	}
+	if !id.Valid {
+		return 0, domain.ErrNotFound("no snapshot at or before %s", req.Timestamp.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	return id.Int64, nil
+}
+
+// currentSnapshot returns the catalog's latest snapshot ID.
+func (r *CatalogRepo) currentSnapshot(ctx context.Context) (int64, error) {
+	var id sql.NullInt64
+	if err := r.metaDB.QueryRowContext(ctx, `SELECT MAX(snapshot_id) FROM ducklake_snapshot`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("read current snapshot: %w", err)
+	}
+	return id.Int64, nil
+}
+
+// checkTableExistedAt rejects a rollback to a snapshot taken before the
+// table was created. A table that was dropped and re-created since has a
+// new table_id, so its earlier incarnation does not count.
+func (r *CatalogRepo) checkTableExistedAt(ctx context.Context, tableID int64, schemaName, tableName string, snapshot int64) error {
+	var n int64
+	if err := r.metaDB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM ducklake_table WHERE table_id = ? AND begin_snapshot <= ?`,
+		tableID, snapshot).Scan(&n); err != nil {
+		return fmt.Errorf("check table history: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrValidation("table %q.%q did not exist at snapshot %d", schemaName, tableName, snapshot)
+	}
+	return nil
+}
+
+// checkSameColumns rejects a rollback when the table's columns differ from
+// those at the target snapshot; restoring rows cannot undo a schema change.
+func (r *CatalogRepo) checkSameColumns(ctx context.Context, schemaName, tableName, source string, snapshot int64) error {
+	now, err := r.columnNames(ctx, r.qualifiedTable(schemaName, tableName))
+	if err != nil {
+		return err
+	}
+	then, err := r.columnNames(ctx, source)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(now, then) {
+		return domain.ErrValidation("columns of %q.%q changed since snapshot %d (%s -> %s); only data can be rolled back",
+			schemaName, tableName, snapshot, strings.Join(then, ", "), strings.Join(now, ", "))
+	}
+	return nil
+}
+
+func (r *CatalogRepo) columnNames(ctx context.Context, from string) ([]string, error) {
+	rows, err := r.duckDB.QueryContext(ctx, "SELECT * FROM "+from+" LIMIT 0")
+	if err != nil {
+		return nil, fmt.Errorf("read columns: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	return rows.Columns()
+}
+
+func (r *CatalogRepo) countRows(ctx context.Context, from string) (int64, error) {
+	var n int64
+	if err := r.duckDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+from).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count rows: %w", err)
+	}
+	return n, nil
+}
+
+// qualifiedTable returns the quoted catalog.schema.table reference for a
+// table whose names GetTable has already resolved.
+func (r *CatalogRepo) qualifiedTable(schemaName, tableName string) string {
+	return ddl.QuoteIdentifier(r.catalogName) + "." + ddl.QuoteIdentifier(schemaName) + "." + ddl.QuoteIdentifier(tableName)
+}
+
+// execInDuckDBTx runs stmts in a single DuckDB transaction.
+func (r *CatalogRepo) execInDuckDBTx(ctx context.Context, stmts []string) error {
+	tx, err := r.duckDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+func TestCatalogRepo_RollbackTable_Validation(t *testing.T) {
+	repo := setupCatalogRepo(t)
+	ctx := context.Background()
+
+	schemaID := seedSchema(t, repo.metaDB, "public")
+	tableID := seedTable(t, repo.metaDB, schemaID, "events")
+	for _, id := range []int64{1, 2, 3, 5} {
+		_, err := repo.metaDB.ExecContext(ctx, `INSERT INTO ducklake_snapshot (snapshot_id, snapshot_time) VALUES (?, '2026-01-01 00:00:00')`, id)
+		require.NoError(t, err)
+	}
+	_, err := repo.metaDB.ExecContext(ctx, `UPDATE ducklake_table SET begin_snapshot = 3 WHERE table_id = ?`, tableID)
+	require.NoError(t, err)
+
+	snap := func(id int64) domain.RollbackTableRequest { return domain.RollbackTableRequest{SnapshotID: &id} }
+
+	tests := []struct {
+		name    string
+		table   string
+		req     domain.RollbackTableRequest
+		wantErr string
+	}{
+		{name: "no target", table: "events", req: domain.RollbackTableRequest{}, wantErr: "one of snapshot_id or timestamp is required"},
+		{name: "missing table", table: "missing", req: snap(3), wantErr: "not found"},
+		{name: "expired snapshot", table: "events", req: snap(4), wantErr: "snapshot 4 not found"},
+		{name: "current snapshot", table: "events", req: snap(5), wantErr: "not older than the current snapshot 5"},
+		{name: "before table existed", table: "events", req: snap(2), wantErr: "did not exist at snapshot 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.RollbackTable(ctx, "public", tt.table, tt.req)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -21,7 +21,7 @@ func (r *CatalogRepo) CreateSchema(ctx context.Context, name, comment, owner str
 
 	// Check if schema already exists (DuckDB silently succeeds on duplicate CREATE SCHEMA)
 	if _, err := r.GetSchema(ctx, name); err == nil {
-		return nil, domain.ErrConflict("schema %q already exists", name)
+		return nil, domain.ErrAlreadyExists("schema %q already exists", name)
 	}
 
 	stmt, err := ddl.CreateSchema(r.catalogName, name)
@@ -31,7 +31,7 @@ func (r *CatalogRepo) CreateSchema(ctx context.Context, name, comment, owner str
 	if _, err := r.duckDB.ExecContext(ctx, stmt); err != nil {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "already exists") {
-			return nil, domain.ErrConflict("schema %q already exists", name)
+			return nil, domain.ErrAlreadyExists("schema %q already exists", name)
 		}
 		return nil, fmt.Errorf("create schema: %w", err)
 	}
@@ -73,18 +73,34 @@ func (r *CatalogRepo) GetSchema(ctx context.Context, name string) (*domain.Schem
 	return &s, nil
 }
 
-// ListSchemas returns a paginated list of schemas.
+// ListSchemas returns a paginated list of schemas, limited to those owner owns
+// when it is non-nil.
 // NOTE: ducklake_schema is not managed by sqlc.
-func (r *CatalogRepo) ListSchemas(ctx context.Context, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+func (r *CatalogRepo) ListSchemas(ctx context.Context, owner *string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+	where := "end_snapshot IS NULL"
+	var args []interface{}
+	if owner != nil {
+		names, err := r.ownedNames(ctx, "schema", *owner)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(names) == 0 {
+			return nil, 0, nil
+		}
+		clause, inArgs := inClause("schema_name", names)
+		where += " AND " + clause
+		args = inArgs
+	}
+
 	var total int64
 	if err := r.metaDB.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM ducklake_schema WHERE end_snapshot IS NULL`).Scan(&total); err != nil {
+		`SELECT COUNT(*) FROM ducklake_schema WHERE `+where, args...).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 
 	rows, err := r.metaDB.QueryContext(ctx,
-		`SELECT schema_id, schema_name FROM ducklake_schema WHERE end_snapshot IS NULL ORDER BY schema_name LIMIT ? OFFSET ?`,
-		page.Limit(), page.Offset())
+		`SELECT schema_id, schema_name FROM ducklake_schema WHERE `+where+` ORDER BY schema_name LIMIT ? OFFSET ?`,
+		append(args, page.Limit(), page.Offset())...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -110,8 +126,9 @@ func (r *CatalogRepo) ListSchemas(ctx context.Context, page domain.PageRequest)
 	return schemas, total, nil
 }
 
-// UpdateSchema updates schema metadata (comment, properties).
-func (r *CatalogRepo) UpdateSchema(ctx context.Context, name string, comment *string, props map[string]string) (*domain.SchemaDetail, error) {
+// UpdateSchema updates schema metadata (comment, properties). A non-nil
+// expectedRowVersion makes the update conditional on the metadata row version.
+func (r *CatalogRepo) UpdateSchema(ctx context.Context, name string, comment *string, props map[string]string, expectedRowVersion *int64) (*domain.SchemaDetail, error) {
 	// Verify schema exists
 	_, err := r.GetSchema(ctx, name)
 	if err != nil {
@@ -124,13 +141,17 @@ func (r *CatalogRepo) UpdateSchema(ctx context.Context, name string, comment *st
 		propsJSON = sql.NullString{String: string(b), Valid: true}
 	}
 
-	err = r.q.UpsertCatalogMetadata(ctx, dbstore.UpsertCatalogMetadataParams{
+	err = r.writeCatalogMetadata(ctx, dbstore.UpsertCatalogMetadataParams{
 		SecurableType: "schema",
 		SecurableName: name,
 		Comment:       sql.NullString{String: ptrToStr(comment), Valid: comment != nil},
 		Properties:    propsJSON,
 		Owner:         sql.NullString{},
-	})
+	}, expectedRowVersion)
+	var stale *domain.PreconditionFailedError
+	if errors.As(err, &stale) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("update schema metadata: %w", err)
 	}
@@ -82,7 +82,7 @@ func TestCatalogRepo_ListSchemas(t *testing.T) {
 			`INSERT INTO ducklake_schema (schema_name, end_snapshot) VALUES ('deleted', 5)`)
 		require.NoError(t, err)
 
-		schemas, total, err := repo.ListSchemas(ctx, domain.PageRequest{})
+		schemas, total, err := repo.ListSchemas(ctx, nil, domain.PageRequest{})
 		require.NoError(t, err)
 		assert.Equal(t, int64(3), total)
 		require.Len(t, schemas, 3)
@@ -107,7 +107,7 @@ func TestCatalogRepo_ListSchemas(t *testing.T) {
 		seedSchema(t, repo.metaDB, "c")
 
 		// Page 1
-		schemas, total, err := repo.ListSchemas(ctx, domain.PageRequest{MaxResults: 2})
+		schemas, total, err := repo.ListSchemas(ctx, nil, domain.PageRequest{MaxResults: 2})
 		require.NoError(t, err)
 		assert.Equal(t, int64(3), total)
 		require.Len(t, schemas, 2)
@@ -115,7 +115,7 @@ func TestCatalogRepo_ListSchemas(t *testing.T) {
 		assert.Equal(t, "b", schemas[1].Name)
 
 		// Page 2
-		schemas, total, err = repo.ListSchemas(ctx, domain.PageRequest{
+		schemas, total, err = repo.ListSchemas(ctx, nil, domain.PageRequest{
 			MaxResults: 2,
 			PageToken:  domain.EncodePageToken(2),
 		})
@@ -129,11 +129,49 @@ func TestCatalogRepo_ListSchemas(t *testing.T) {
 		repo := setupCatalogRepo(t)
 		ctx := context.Background()
 
-		schemas, total, err := repo.ListSchemas(ctx, domain.PageRequest{})
+		schemas, total, err := repo.ListSchemas(ctx, nil, domain.PageRequest{})
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), total)
 		assert.Nil(t, schemas)
 	})
+
+	t.Run("owner filter", func(t *testing.T) {
+		repo := setupCatalogRepo(t)
+		ctx := context.Background()
+
+		seedSchema(t, repo.metaDB, "alice_a")
+		seedSchema(t, repo.metaDB, "bob_b")
+		seedSchema(t, repo.metaDB, "alice_c")
+		seedSchema(t, repo.metaDB, "unowned")
+		_, err := repo.controlDB.ExecContext(ctx,
+			`INSERT INTO catalog_metadata (securable_type, securable_name, owner)
+			 VALUES ('schema', 'alice_a', 'alice'), ('schema', 'bob_b', 'bob'), ('schema', 'alice_c', 'alice'),
+			        ('table', 'alice_a', 'bob')`)
+		require.NoError(t, err)
+
+		alice := "alice"
+		schemas, total, err := repo.ListSchemas(ctx, &alice, domain.PageRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, schemas, 2)
+		assert.Equal(t, "alice_a", schemas[0].Name)
+		assert.Equal(t, "alice_c", schemas[1].Name)
+		for _, s := range schemas {
+			assert.Equal(t, "alice", s.Owner)
+		}
+
+		// Pagination counts only the owner's schemas.
+		schemas, total, err = repo.ListSchemas(ctx, &alice, domain.PageRequest{MaxResults: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, schemas, 1)
+
+		carol := "carol"
+		schemas, total, err = repo.ListSchemas(ctx, &carol, domain.PageRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, schemas)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -147,7 +185,7 @@ func TestCatalogRepo_UpdateSchema(t *testing.T) {
 		seedSchema(t, repo.metaDB, "sales")
 
 		comment := "sales data"
-		updated, err := repo.UpdateSchema(ctx, "sales", &comment, nil)
+		updated, err := repo.UpdateSchema(ctx, "sales", &comment, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "sales data", updated.Comment)
 	})
@@ -158,7 +196,7 @@ func TestCatalogRepo_UpdateSchema(t *testing.T) {
 		seedSchema(t, repo.metaDB, "warehouse")
 
 		props := map[string]string{"env": "prod", "team": "platform"}
-		updated, err := repo.UpdateSchema(ctx, "warehouse", nil, props)
+		updated, err := repo.UpdateSchema(ctx, "warehouse", nil, props, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "prod", updated.Properties["env"])
 		assert.Equal(t, "platform", updated.Properties["team"])
@@ -171,7 +209,7 @@ func TestCatalogRepo_UpdateSchema(t *testing.T) {
 
 		comment := "updated"
 		props := map[string]string{"key": "val"}
-		updated, err := repo.UpdateSchema(ctx, "mixed", &comment, props)
+		updated, err := repo.UpdateSchema(ctx, "mixed", &comment, props, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "updated", updated.Comment)
 		assert.Equal(t, "val", updated.Properties["key"])
@@ -182,7 +220,7 @@ func TestCatalogRepo_UpdateSchema(t *testing.T) {
 		ctx := context.Background()
 
 		comment := "nope"
-		_, err := repo.UpdateSchema(ctx, "ghost", &comment, nil)
+		_, err := repo.UpdateSchema(ctx, "ghost", &comment, nil, nil)
 		require.Error(t, err)
 		var nf *domain.NotFoundError
 		assert.ErrorAs(t, err, &nf)
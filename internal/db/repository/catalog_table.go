@@ -38,13 +38,26 @@ func (r *CatalogRepo) CreateTable(ctx context.Context, schemaName string, req do
 	if _, err := r.duckDB.ExecContext(ctx, stmt); err != nil {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "already exists") {
-			return nil, domain.ErrConflict("table %q already exists in schema %q", req.Name, schemaName)
+			return nil, domain.ErrAlreadyExists("table %q already exists in schema %q", req.Name, schemaName)
 		}
 		if strings.Contains(errMsg, "not found") || strings.Contains(errMsg, "does not exist") {
 			return nil, domain.ErrNotFound("schema %q not found", schemaName)
 		}
 		return nil, fmt.Errorf("create table: %w", err)
 	}
+	if len(req.PartitionBy) > 0 {
+		partStmt, err := ddl.SetPartitionedBy(r.catalogName, schemaName, req.Name, req.PartitionBy)
+		if err != nil {
+			return nil, domain.ErrValidation("%s", err.Error())
+		}
+		if _, err := r.duckDB.ExecContext(ctx, partStmt); err != nil {
+			// Don't leave an unpartitioned table behind for a retry to trip over.
+			if dropStmt, dropErr := ddl.DropTable(r.catalogName, schemaName, req.Name); dropErr == nil {
+				_, _ = r.duckDB.ExecContext(ctx, dropStmt)
+			}
+			return nil, fmt.Errorf("set table partitioning: %w", err)
+		}
+	}
 	r.refreshMetaDB(ctx)
 
 	// Store metadata via sqlc
@@ -115,6 +128,7 @@ func (r *CatalogRepo) GetTable(ctx context.Context, schemaName, tableName string
 		return nil, err
 	}
 	t.Columns = cols
+	t.PartitionBy = r.loadPartitionColumns(ctx, t.TableID)
 
 	// Enrich columns with metadata via sqlc
 	securableName := schemaName + "." + tableName
@@ -128,26 +142,52 @@ func (r *CatalogRepo) GetTable(ctx context.Context, schemaName, tableName string
 	return &t, nil
 }
 
-// ListTables returns a paginated list of tables in a schema.
+// ListTables returns a paginated list of tables in a schema, limited to those
+// owner owns when it is non-nil.
 // NOTE: ducklake_schema and ducklake_table are not managed by sqlc.
-func (r *CatalogRepo) ListTables(ctx context.Context, schemaName string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
+func (r *CatalogRepo) ListTables(ctx context.Context, schemaName string, owner *string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
 	// First get the schema_id
 	schemaID, err := r.resolveSchemaID(ctx, schemaName)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	where := "schema_id = ? AND end_snapshot IS NULL"
+	args := []interface{}{schemaID}
+	var owned map[string]bool
+	if owner != nil {
+		names, err := r.ownedNames(ctx, "table", *owner)
+		if err != nil {
+			return nil, 0, err
+		}
+		prefix := schemaName + "."
+		owned = make(map[string]bool)
+		var tableNames []string
+		for _, n := range names {
+			if strings.HasPrefix(n, prefix) {
+				tableNames = append(tableNames, strings.TrimPrefix(n, prefix))
+				owned[strings.TrimPrefix(n, prefix)] = true
+			}
+		}
+		if len(tableNames) == 0 {
+			return []domain.TableDetail{}, 0, nil
+		}
+		clause, inArgs := inClause("table_name", tableNames)
+		where += " AND " + clause
+		args = append(args, inArgs...)
+	}
+
 	// Fetch ALL managed tables (no LIMIT/OFFSET) so we can merge with external
 	// tables before applying pagination, preventing duplicate/missing items.
 	var managedCount int64
 	if err := r.metaDB.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM ducklake_table WHERE schema_id = ? AND end_snapshot IS NULL`, schemaID).Scan(&managedCount); err != nil {
+		`SELECT COUNT(*) FROM ducklake_table WHERE `+where, args...).Scan(&managedCount); err != nil {
 		return nil, 0, err
 	}
 
 	rows, err := r.metaDB.QueryContext(ctx,
-		`SELECT table_id, table_name FROM ducklake_table WHERE schema_id = ? AND end_snapshot IS NULL ORDER BY table_name`,
-		schemaID)
+		`SELECT table_id, table_name FROM ducklake_table WHERE `+where+` ORDER BY table_name`,
+		args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -164,6 +204,7 @@ func (r *CatalogRepo) ListTables(ctx context.Context, schemaName string, page do
 		t.SchemaName = schemaName
 		t.CatalogName = r.catalogName
 		t.TableType = "MANAGED"
+		t.PartitionBy = r.loadPartitionColumns(ctx, t.TableID)
 		allTables = append(allTables, t)
 	}
 	if err := rows.Err(); err != nil {
@@ -175,6 +216,9 @@ func (r *CatalogRepo) ListTables(ctx context.Context, schemaName string, page do
 		extTables, _, extErr := r.extRepo.List(ctx, schemaName, domain.PageRequest{MaxResults: domain.MaxMaxResults})
 		if extErr == nil {
 			for _, et := range extTables {
+				if owned != nil && !owned[et.TableName] {
+					continue
+				}
 				detail := r.externalTableToDetail(&et, schemaName)
 				allTables = append(allTables, *detail)
 			}
@@ -281,6 +325,155 @@ func (r *CatalogRepo) DeleteTable(ctx context.Context, schemaName, tableName str
 	return nil
 }
 
+// RenameTable renames a table via DuckDB DDL. Grants, tags, row filters and
+// column masks are keyed on the table ID and follow the table; metadata,
+// statistics and lineage keyed on the table's name are moved to the new name.
+func (r *CatalogRepo) RenameTable(ctx context.Context, schemaName, tableName, newName string) (*domain.TableDetail, error) {
+	if err := ddl.ValidateIdentifier(schemaName); err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+	if err := ddl.ValidateIdentifier(tableName); err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+	if err := ddl.ValidateIdentifier(newName); err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+
+	if _, err := r.GetTable(ctx, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	if _, err := r.GetTable(ctx, schemaName, newName); err == nil {
+		return nil, domain.ErrAlreadyExists("table %q already exists in schema %q", newName, schemaName)
+	} else if !errors.As(err, new(*domain.NotFoundError)) {
+		return nil, err
+	}
+
+	// External tables are DuckDB views over their source files.
+	var external bool
+	if r.extRepo != nil {
+		if _, err := r.extRepo.GetByName(ctx, schemaName, tableName); err == nil {
+			external = true
+		}
+	}
+
+	var stmt string
+	var err error
+	if external {
+		stmt, err = ddl.RenameView(r.catalogName, schemaName, tableName, newName)
+	} else {
+		stmt, err = ddl.RenameTable(r.catalogName, schemaName, tableName, newName)
+	}
+	if err != nil {
+		return nil, domain.ErrValidation("%s", err.Error())
+	}
+	if _, err := r.duckDB.ExecContext(ctx, stmt); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return nil, domain.ErrAlreadyExists("table %q already exists in schema %q", newName, schemaName)
+		}
+		return nil, fmt.Errorf("rename table: %w", err)
+	}
+	r.refreshMetaDB(ctx)
+	if external {
+		if err := r.extRepo.Rename(ctx, schemaName, tableName, newName); err != nil {
+			return nil, fmt.Errorf("rename external table: %w", err)
+		}
+	}
+
+	if err := r.renameTableReferences(ctx, schemaName, tableName, newName); err != nil {
+		return nil, err
+	}
+	return r.GetTable(ctx, schemaName, newName)
+}
+
+// renameTableReferences moves the control-plane records keyed on a table's
+// name to its new name.
+func (r *CatalogRepo) renameTableReferences(ctx context.Context, schemaName, tableName, newName string) error {
+	tx, err := r.controlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin rename tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := r.q.WithTx(tx)
+	oldName := schemaName + "." + tableName
+	qualifiedNew := schemaName + "." + newName
+
+	// A dropped table of the new name may have left soft-deleted metadata.
+	if err := qtx.DeleteCatalogMetadataByTypeAndName(ctx, dbstore.DeleteCatalogMetadataByTypeAndNameParams{
+		SecurableType: "table",
+		SecurableName: qualifiedNew,
+	}); err != nil {
+		return fmt.Errorf("clear table metadata: %w", err)
+	}
+	if err := qtx.RenameCatalogMetadata(ctx, dbstore.RenameCatalogMetadataParams{
+		NewName:       qualifiedNew,
+		SecurableType: "table",
+		OldName:       oldName,
+	}); err != nil {
+		return fmt.Errorf("rename table metadata: %w", err)
+	}
+	if err := qtx.RenameColumnMetadataTable(ctx, dbstore.RenameColumnMetadataTableParams{
+		NewName: qualifiedNew,
+		OldName: oldName,
+	}); err != nil {
+		return fmt.Errorf("rename column metadata: %w", err)
+	}
+	if err := qtx.RenameTableStatistics(ctx, dbstore.RenameTableStatisticsParams{
+		NewName: qualifiedNew,
+		OldName: oldName,
+	}); err != nil {
+		return fmt.Errorf("rename table statistics: %w", err)
+	}
+	if err := qtx.RenameLineageTable(ctx, dbstore.RenameLineageTableParams{
+		OldName: oldName,
+		NewName: qualifiedNew,
+	}); err != nil {
+		return fmt.Errorf("rename lineage: %w", err)
+	}
+	if err := qtx.RenameColumnLineageSourceTable(ctx, dbstore.RenameColumnLineageSourceTableParams{
+		NewTable:     newName,
+		SourceSchema: schemaName,
+		OldTable:     tableName,
+	}); err != nil {
+		return fmt.Errorf("rename column lineage: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit rename: %w", err)
+	}
+	return nil
+}
+
+// MaterializeTable fills a table with the result of query, creating it on
+// first use. An existing table keeps its ID, and with it its grants, row
+// filters and column masks; its rows are replaced in one transaction, so
+// query must still produce the table's columns.
+func (r *CatalogRepo) MaterializeTable(ctx context.Context, schemaName, tableName, query string) (int64, error) {
+	_, err := r.GetTable(ctx, schemaName, tableName)
+	switch {
+	case err == nil:
+		stmts, err := ddl.ReplaceTableContents(r.catalogName, schemaName, tableName, query)
+		if err != nil {
+			return 0, domain.ErrValidation("%s", err.Error())
+		}
+		if err := r.execInDuckDBTx(ctx, stmts); err != nil {
+			return 0, fmt.Errorf("replace table contents: %w", err)
+		}
+	case errors.As(err, new(*domain.NotFoundError)):
+		stmt, err := ddl.CreateTableAs(r.catalogName, schemaName, tableName, query)
+		if err != nil {
+			return 0, domain.ErrValidation("%s", err.Error())
+		}
+		if _, err := r.duckDB.ExecContext(ctx, stmt); err != nil {
+			return 0, fmt.Errorf("create table: %w", err)
+		}
+	default:
+		return 0, err
+	}
+	r.refreshMetaDB(ctx)
+	return r.countRows(ctx, r.qualifiedTable(schemaName, tableName))
+}
+
 // ListColumns returns a paginated list of columns for a table.
 // NOTE: ducklake_schema, ducklake_table, ducklake_column are not managed by sqlc.
 func (r *CatalogRepo) ListColumns(ctx context.Context, schemaName, tableName string, page domain.PageRequest) ([]domain.ColumnDetail, int64, error) {
@@ -336,8 +529,9 @@ func (r *CatalogRepo) ListColumns(ctx context.Context, schemaName, tableName str
 	return columns, total, rows.Err()
 }
 
-// UpdateTable updates table metadata (comment, properties, owner).
-func (r *CatalogRepo) UpdateTable(ctx context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string) (*domain.TableDetail, error) {
+// UpdateTable updates table metadata (comment, properties, owner). A non-nil
+// expectedRowVersion makes the update conditional on the metadata row version.
+func (r *CatalogRepo) UpdateTable(ctx context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string, expectedRowVersion *int64) (*domain.TableDetail, error) {
 	// Verify table exists
 	_, err := r.GetTable(ctx, schemaName, tableName)
 	if err != nil {
@@ -351,13 +545,17 @@ func (r *CatalogRepo) UpdateTable(ctx context.Context, schemaName, tableName str
 		propsJSON = sql.NullString{String: string(b), Valid: true}
 	}
 
-	err = r.q.UpsertCatalogMetadata(ctx, dbstore.UpsertCatalogMetadataParams{
+	err = r.writeCatalogMetadata(ctx, dbstore.UpsertCatalogMetadataParams{
 		SecurableType: "table",
 		SecurableName: securableName,
 		Comment:       sql.NullString{String: ptrToStr(comment), Valid: comment != nil},
 		Properties:    propsJSON,
 		Owner:         sql.NullString{String: ptrToStr(owner), Valid: owner != nil},
-	})
+	}, expectedRowVersion)
+	var stale *domain.PreconditionFailedError
+	if errors.As(err, &stale) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("update table metadata: %w", err)
 	}
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"duck-demo/internal/db/dbstore"
 	"duck-demo/internal/domain"
 )
 
@@ -41,6 +43,33 @@ func TestCatalogRepo_GetTable(t *testing.T) {
 		assert.True(t, tbl.Columns[1].Nullable)
 	})
 
+	t.Run("partitioned table reports partition columns", func(t *testing.T) {
+		repo := setupCatalogRepo(t)
+		ctx := context.Background()
+
+		schemaID := seedSchema(t, repo.metaDB, "public")
+		tableID := seedTable(t, repo.metaDB, schemaID, "events")
+		seedColumn(t, repo.metaDB, tableID, "id", "BIGINT", false)
+		seedColumn(t, repo.metaDB, tableID, "region", "VARCHAR", true)
+		seedColumn(t, repo.metaDB, tableID, "day", "DATE", true)
+		seedPartition(t, repo.metaDB, tableID, "region", "day")
+
+		tbl, err := repo.GetTable(ctx, "public", "events")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"region", "day"}, tbl.PartitionBy)
+
+		tables, _, err := repo.ListTables(ctx, "public", nil, domain.PageRequest{})
+		require.NoError(t, err)
+		require.Len(t, tables, 1)
+		assert.Equal(t, []string{"region", "day"}, tables[0].PartitionBy)
+
+		unpartitioned := seedTable(t, repo.metaDB, schemaID, "users")
+		seedColumn(t, repo.metaDB, unpartitioned, "id", "INTEGER", false)
+		tbl, err = repo.GetTable(ctx, "public", "users")
+		require.NoError(t, err)
+		assert.Nil(t, tbl.PartitionBy)
+	})
+
 	t.Run("table not found", func(t *testing.T) {
 		repo := setupCatalogRepo(t)
 		ctx := context.Background()
@@ -116,7 +145,7 @@ func TestCatalogRepo_ListTables(t *testing.T) {
 			schemaID, "deleted_tbl", 99)
 		require.NoError(t, err)
 
-		tables, total, err := repo.ListTables(ctx, "public", domain.PageRequest{})
+		tables, total, err := repo.ListTables(ctx, "public", nil, domain.PageRequest{})
 		require.NoError(t, err)
 		assert.Equal(t, int64(3), total)
 		require.Len(t, tables, 3)
@@ -143,13 +172,13 @@ func TestCatalogRepo_ListTables(t *testing.T) {
 		seedTable(t, repo.metaDB, schemaID, "c")
 
 		// Page 1
-		tables, total, err := repo.ListTables(ctx, "paged", domain.PageRequest{MaxResults: 2})
+		tables, total, err := repo.ListTables(ctx, "paged", nil, domain.PageRequest{MaxResults: 2})
 		require.NoError(t, err)
 		assert.Equal(t, int64(3), total)
 		require.Len(t, tables, 2)
 
 		// Page 2
-		tables, total, err = repo.ListTables(ctx, "paged", domain.PageRequest{
+		tables, total, err = repo.ListTables(ctx, "paged", nil, domain.PageRequest{
 			MaxResults: 2,
 			PageToken:  domain.EncodePageToken(2),
 		})
@@ -162,7 +191,7 @@ func TestCatalogRepo_ListTables(t *testing.T) {
 		repo := setupCatalogRepo(t)
 		ctx := context.Background()
 
-		_, _, err := repo.ListTables(ctx, "no_such", domain.PageRequest{})
+		_, _, err := repo.ListTables(ctx, "no_such", nil, domain.PageRequest{})
 		require.Error(t, err)
 		var nf *domain.NotFoundError
 		assert.ErrorAs(t, err, &nf)
@@ -174,7 +203,38 @@ func TestCatalogRepo_ListTables(t *testing.T) {
 
 		seedSchema(t, repo.metaDB, "empty")
 
-		tables, total, err := repo.ListTables(ctx, "empty", domain.PageRequest{})
+		tables, total, err := repo.ListTables(ctx, "empty", nil, domain.PageRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, tables)
+	})
+
+	t.Run("owner filter", func(t *testing.T) {
+		repo := setupCatalogRepo(t)
+		ctx := context.Background()
+
+		schemaID := seedSchema(t, repo.metaDB, "public")
+		seedTable(t, repo.metaDB, schemaID, "orders")
+		seedTable(t, repo.metaDB, schemaID, "products")
+		seedTable(t, repo.metaDB, schemaID, "users")
+		otherID := seedSchema(t, repo.metaDB, "other")
+		seedTable(t, repo.metaDB, otherID, "orders")
+		_, err := repo.controlDB.ExecContext(ctx,
+			`INSERT INTO catalog_metadata (securable_type, securable_name, owner)
+			 VALUES ('table', 'public.orders', 'alice'), ('table', 'public.products', 'bob'),
+			        ('table', 'public.users', 'alice'), ('table', 'other.orders', 'alice')`)
+		require.NoError(t, err)
+
+		alice := "alice"
+		tables, total, err := repo.ListTables(ctx, "public", &alice, domain.PageRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, tables, 2)
+		assert.Equal(t, "orders", tables[0].Name)
+		assert.Equal(t, "users", tables[1].Name)
+
+		bob := "bob"
+		tables, total, err = repo.ListTables(ctx, "other", &bob, domain.PageRequest{})
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), total)
 		assert.Empty(t, tables)
@@ -257,7 +317,7 @@ func TestCatalogRepo_UpdateTable(t *testing.T) {
 		seedTable(t, repo.metaDB, schemaID, "orders")
 
 		comment := "order data"
-		tbl, err := repo.UpdateTable(ctx, "public", "orders", &comment, nil, nil)
+		tbl, err := repo.UpdateTable(ctx, "public", "orders", &comment, nil, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "order data", tbl.Comment)
 	})
@@ -270,7 +330,7 @@ func TestCatalogRepo_UpdateTable(t *testing.T) {
 		seedTable(t, repo.metaDB, schemaID, "products")
 
 		props := map[string]string{"retention": "30d"}
-		tbl, err := repo.UpdateTable(ctx, "public", "products", nil, props, nil)
+		tbl, err := repo.UpdateTable(ctx, "public", "products", nil, props, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "30d", tbl.Properties["retention"])
 	})
@@ -283,7 +343,7 @@ func TestCatalogRepo_UpdateTable(t *testing.T) {
 		seedTable(t, repo.metaDB, schemaID, "events")
 
 		owner := "data-team"
-		tbl, err := repo.UpdateTable(ctx, "public", "events", nil, nil, &owner)
+		tbl, err := repo.UpdateTable(ctx, "public", "events", nil, nil, &owner, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "data-team", tbl.Owner)
 	})
@@ -298,7 +358,7 @@ func TestCatalogRepo_UpdateTable(t *testing.T) {
 		comment := "metrics table"
 		props := map[string]string{"tier": "gold"}
 		owner := "sre"
-		tbl, err := repo.UpdateTable(ctx, "public", "metrics", &comment, props, &owner)
+		tbl, err := repo.UpdateTable(ctx, "public", "metrics", &comment, props, &owner, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "metrics table", tbl.Comment)
 		assert.Equal(t, "gold", tbl.Properties["tier"])
@@ -312,13 +372,64 @@ func TestCatalogRepo_UpdateTable(t *testing.T) {
 		seedSchema(t, repo.metaDB, "public")
 
 		comment := "nope"
-		_, err := repo.UpdateTable(ctx, "public", "ghost", &comment, nil, nil)
+		_, err := repo.UpdateTable(ctx, "public", "ghost", &comment, nil, nil, nil)
 		require.Error(t, err)
 		var nf *domain.NotFoundError
 		assert.ErrorAs(t, err, &nf)
 	})
 }
 
+// ---------------------------------------------------------------------------
+// renameTableReferences
+// ---------------------------------------------------------------------------
+
+func TestCatalogRepo_RenameTableReferences(t *testing.T) {
+	repo := setupCatalogRepo(t)
+	ctx := context.Background()
+
+	schemaID := seedSchema(t, repo.metaDB, "public")
+	seedTable(t, repo.metaDB, schemaID, "orders")
+
+	comment := "order data"
+	_, err := repo.UpdateTable(ctx, "public", "orders", &comment, nil, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.q.UpsertTableStatistics(ctx, dbstore.UpsertTableStatisticsParams{
+		TableSecurableName: "public.orders",
+		RowCount:           sql.NullInt64{Int64: 42, Valid: true},
+	}))
+	require.NoError(t, repo.q.InsertLineageEdge(ctx, dbstore.InsertLineageEdgeParams{
+		ID:            "edge-1",
+		SourceTable:   "public.orders",
+		TargetTable:   sql.NullString{String: "public.order_totals", Valid: true},
+		EdgeType:      "READ_WRITE",
+		PrincipalName: "alice",
+	}))
+
+	require.NoError(t, repo.renameTableReferences(ctx, "public", "orders", "purchases"))
+
+	meta, err := repo.q.GetCatalogMetadata(ctx, dbstore.GetCatalogMetadataParams{
+		SecurableType: "table",
+		SecurableName: "public.purchases",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "order data", meta.Comment.String)
+
+	stats, err := repo.q.GetTableStatistics(ctx, "public.purchases")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), stats.RowCount.Int64)
+
+	edges, err := repo.q.GetDownstreamLineage(ctx, dbstore.GetDownstreamLineageParams{
+		SourceTable: "public.purchases",
+		Limit:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	assert.Equal(t, "public.order_totals", edges[0].TargetTable.String)
+
+	_, err = repo.q.GetTableStatistics(ctx, "public.orders")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
 // ---------------------------------------------------------------------------
 // UpdateColumn
 // ---------------------------------------------------------------------------
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+func TestCatalogRepo_GetCatalogStats(t *testing.T) {
+	repo := setupCatalogRepo(t)
+	ctx := context.Background()
+
+	sales := seedSchema(t, repo.metaDB, "sales")
+	staging := seedSchema(t, repo.metaDB, "staging")
+	_, err := repo.metaDB.ExecContext(ctx,
+		`INSERT INTO ducklake_schema (schema_name, end_snapshot) VALUES ('dropped', 3)`)
+	require.NoError(t, err)
+
+	orders := seedTable(t, repo.metaDB, sales, "orders")
+	customers := seedTable(t, repo.metaDB, sales, "customers")
+	seedTable(t, repo.metaDB, staging, "empty")
+	_, err = repo.metaDB.ExecContext(ctx,
+		`INSERT INTO ducklake_table (schema_id, table_name, end_snapshot) VALUES (?, 'old_orders', 3)`, sales)
+	require.NoError(t, err)
+	for _, s := range []struct{ tableID, rows, bytes int64 }{{orders, 5000, 80000}, {customers, 200, 6000}} {
+		_, err := repo.metaDB.ExecContext(ctx,
+			`INSERT INTO ducklake_table_stats (table_id, record_count, next_row_id, file_size_bytes) VALUES (?, ?, ?, ?)`,
+			s.tableID, s.rows, s.rows, s.bytes)
+		require.NoError(t, err)
+	}
+
+	_, err = repo.controlDB.ExecContext(ctx,
+		`INSERT INTO views (id, schema_id, name, view_definition, owner) VALUES ('v1', ?, 'big_orders', 'SELECT 1', 'admin')`,
+		domain.DuckLakeIDToString(sales))
+	require.NoError(t, err)
+	_, err = repo.controlDB.ExecContext(ctx,
+		`INSERT INTO external_tables (id, schema_name, table_name, source_path, location_name) VALUES ('et1', 'staging', 'landing', 's3://bucket/landing/*.parquet', 'raw')`)
+	require.NoError(t, err)
+
+	t.Run("counts and totals", func(t *testing.T) {
+		stats, err := repo.GetCatalogStats(ctx, domain.CatalogStatsLargestTables)
+		require.NoError(t, err)
+		assert.Equal(t, "lake", stats.CatalogName)
+		assert.Equal(t, int64(2), stats.SchemaCount)
+		assert.Equal(t, int64(4), stats.TableCount)
+		assert.Equal(t, int64(1), stats.ViewCount)
+		assert.Equal(t, int64(5200), stats.TotalRows)
+		assert.Equal(t, int64(86000), stats.TotalBytes)
+		assert.Equal(t, []domain.TableSize{
+			{SchemaName: "sales", TableName: "orders", RowCount: 5000, SizeBytes: 80000},
+			{SchemaName: "sales", TableName: "customers", RowCount: 200, SizeBytes: 6000},
+			{SchemaName: "staging", TableName: "empty"},
+		}, stats.LargestTables)
+	})
+
+	t.Run("largest tables are limited", func(t *testing.T) {
+		stats, err := repo.GetCatalogStats(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, stats.LargestTables, 1)
+		assert.Equal(t, "orders", stats.LargestTables[0].TableName)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/db/mapper"
@@ -27,9 +28,27 @@ func (r *ColumnMaskRepo) Create(ctx context.Context, m *domain.ColumnMask) (*dom
 		ID:             newID(),
 		TableID:        m.TableID,
 		ColumnName:     m.ColumnName,
+		TagID:          mapper.NullStrFromPtr(m.TagID),
 		MaskExpression: m.MaskExpression,
 		Description:    sql.NullString{String: m.Description, Valid: m.Description != ""},
 	})
+	if err != nil {
+		if m.TagID != nil {
+			switch {
+			case strings.Contains(err.Error(), "FOREIGN KEY constraint failed"):
+				return nil, domain.ErrNotFound("tag %s not found", *m.TagID)
+			case strings.Contains(err.Error(), "UNIQUE constraint failed"):
+				return nil, domain.ErrAlreadyExists("tag %s already has a column mask", *m.TagID)
+			}
+		}
+		return nil, mapDBError(err)
+	}
+	return mapper.ColumnMaskFromDB(row), nil
+}
+
+// GetByID returns a column mask by its ID.
+func (r *ColumnMaskRepo) GetByID(ctx context.Context, id string) (*domain.ColumnMask, error) {
+	row, err := r.q.GetColumnMask(ctx, id)
 	if err != nil {
 		return nil, mapDBError(err)
 	}
@@ -55,6 +74,26 @@ func (r *ColumnMaskRepo) GetForTable(ctx context.Context, tableID string, page d
 	return mapper.ColumnMasksFromDB(rows), total, nil
 }
 
+// GetForTag returns a paginated list of the tag-based column masks for a tag.
+func (r *ColumnMaskRepo) GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error) {
+	tag := sql.NullString{String: tagID, Valid: true}
+	total, err := r.q.CountColumnMasksForTag(ctx, tag)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.q.ListColumnMasksForTagPaginated(ctx, dbstore.ListColumnMasksForTagPaginatedParams{
+		TagID:  tag,
+		Limit:  int64(page.Limit()),
+		Offset: int64(page.Offset()),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return mapper.ColumnMasksFromDB(rows), total, nil
+}
+
 // Delete removes a column mask by ID.
 func (r *ColumnMaskRepo) Delete(ctx context.Context, id string) error {
 	res, err := r.db.ExecContext(ctx, "DELETE FROM column_masks WHERE id = ?", id)
@@ -100,7 +139,9 @@ func (r *ColumnMaskRepo) ListBindings(ctx context.Context, maskID string) ([]dom
 	return mapper.ColumnMaskBindingsFromDB(rows), nil
 }
 
-// GetForTableAndPrincipal returns column masks with bindings for a specific table and principal.
+// GetForTableAndPrincipal returns column masks with bindings for a specific
+// table and principal. Masks on the table's columns come first, followed by
+// tag-based masks for each column assigned their tag, oldest mask first.
 func (r *ColumnMaskRepo) GetForTableAndPrincipal(ctx context.Context, tableID, principalID string, principalType string) ([]domain.ColumnMaskWithBinding, error) {
 	rows, err := r.q.GetColumnMaskForTableAndPrincipal(ctx, dbstore.GetColumnMaskForTableAndPrincipalParams{
 		TableID:       tableID,
@@ -118,5 +159,23 @@ func (r *ColumnMaskRepo) GetForTableAndPrincipal(ctx context.Context, tableID, p
 			SeeOriginal:    row.SeeOriginal != 0,
 		}
 	}
+
+	tagRows, err := r.q.GetTagColumnMasksForTableAndPrincipal(ctx, dbstore.GetTagColumnMasksForTableAndPrincipalParams{
+		SecurableID:   tableID,
+		PrincipalID:   principalID,
+		PrincipalType: principalType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range tagRows {
+		tagID := row.TagID.String
+		result = append(result, domain.ColumnMaskWithBinding{
+			ColumnName:     row.ColumnName.String,
+			MaskExpression: row.MaskExpression,
+			SeeOriginal:    row.SeeOriginal != 0,
+			TagID:          &tagID,
+		})
+	}
 	return result, nil
 }
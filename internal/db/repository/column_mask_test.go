@@ -143,6 +143,77 @@ func TestColumnMaskRepo_GetForTableAndPrincipal(t *testing.T) {
 	assert.False(t, masks[0].SeeOriginal)
 }
 
+func TestColumnMaskRepo_TagBasedMask(t *testing.T) {
+	repo := setupColumnMaskRepo(t)
+	tags := NewTagRepo(repo.db)
+	ctx := context.Background()
+
+	tag, err := tags.CreateTag(ctx, &domain.Tag{Key: "pii", CreatedBy: "admin"})
+	require.NoError(t, err)
+
+	direct, err := repo.Create(ctx, &domain.ColumnMask{
+		TableID:        "t-1",
+		ColumnName:     "email",
+		MaskExpression: "'***@***'",
+	})
+	require.NoError(t, err)
+	tagged, err := repo.Create(ctx, &domain.ColumnMask{
+		TagID:          &tag.ID,
+		MaskExpression: "'REDACTED'",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, tagged.TagID)
+	assert.Equal(t, tag.ID, *tagged.TagID)
+	assert.Empty(t, tagged.TableID)
+
+	for _, id := range []string{direct.ID, tagged.ID} {
+		require.NoError(t, repo.Bind(ctx, &domain.ColumnMaskBinding{ColumnMaskID: id, PrincipalID: "p-1", PrincipalType: "user"}))
+	}
+	for _, col := range []string{"email", "phone"} {
+		_, err := tags.AssignTag(ctx, &domain.TagAssignment{
+			TagID: tag.ID, SecurableType: "column", SecurableID: "t-1", ColumnName: &col, AssignedBy: "admin",
+		})
+		require.NoError(t, err)
+	}
+
+	masks, err := repo.GetForTableAndPrincipal(ctx, "t-1", "p-1", "user")
+	require.NoError(t, err)
+	require.Len(t, masks, 3)
+	assert.Equal(t, "email", masks[0].ColumnName, "the column's own mask comes first")
+	assert.Nil(t, masks[0].TagID)
+	for _, m := range masks[1:] {
+		require.NotNil(t, m.TagID)
+		assert.Equal(t, "'REDACTED'", m.MaskExpression)
+	}
+	assert.ElementsMatch(t, []string{"email", "phone"}, []string{masks[1].ColumnName, masks[2].ColumnName})
+
+	// GetForTag lists only the tag's mask; GetForTable only the column mask.
+	byTag, total, err := repo.GetForTag(ctx, tag.ID, domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, byTag, 1)
+	assert.Equal(t, tagged.ID, byTag[0].ID)
+	_, total, err = repo.GetForTable(ctx, "t-1", domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// One mask per tag.
+	_, err = repo.Create(ctx, &domain.ColumnMask{TagID: &tag.ID, MaskExpression: "NULL"})
+	var conflict *domain.ConflictError
+	assert.ErrorAs(t, err, &conflict)
+
+	unknown := "no-such-tag"
+	_, err = repo.Create(ctx, &domain.ColumnMask{TagID: &unknown, MaskExpression: "NULL"})
+	var notFound *domain.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	// Deleting the tag removes its mask and bindings.
+	require.NoError(t, tags.DeleteTag(ctx, tag.ID))
+	masks, err = repo.GetForTableAndPrincipal(ctx, "t-1", "p-1", "user")
+	require.NoError(t, err)
+	assert.Len(t, masks, 1)
+}
+
 func TestColumnMaskRepo_SeeOriginal(t *testing.T) {
 	repo := setupColumnMaskRepo(t)
 	ctx := context.Background()
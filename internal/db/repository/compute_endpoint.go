@@ -12,8 +12,12 @@ import (
 	"duck-demo/internal/domain"
 )
 
-// Compile-time check.
-var _ domain.ComputeEndpointRepository = (*ComputeEndpointRepo)(nil)
+// Compile-time checks.
+var (
+	_ domain.ComputeEndpointRepository      = (*ComputeEndpointRepo)(nil)
+	_ domain.ComputeEndpointLoadRepository  = (*ComputeEndpointRepo)(nil)
+	_ domain.ComputeEndpointAgentRepository = (*ComputeEndpointRepo)(nil)
+)
 
 // ComputeEndpointRepo implements ComputeEndpointRepository with encrypted auth_token storage.
 type ComputeEndpointRepo struct {
@@ -124,16 +128,20 @@ func (r *ComputeEndpointRepo) Update(ctx context.Context, id string, req domain.
 		return nil, fmt.Errorf("encrypt auth_token: %w", err)
 	}
 
-	err = r.q.UpdateComputeEndpoint(ctx, dbstore.UpdateComputeEndpointParams{
-		Url:         url,
-		Size:        size,
-		MaxMemoryGb: nullInt64Ptr(maxMem),
-		AuthToken:   encToken,
-		ID:          id,
+	n, err := r.q.UpdateComputeEndpoint(ctx, dbstore.UpdateComputeEndpointParams{
+		Url:                url,
+		Size:               size,
+		MaxMemoryGb:        nullInt64Ptr(maxMem),
+		AuthToken:          encToken,
+		ID:                 id,
+		ExpectedRowVersion: rowVersionArg(req.ExpectedRowVersion),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, req.ExpectedRowVersion, "compute endpoint", current.Name); err != nil {
+		return nil, err
+	}
 	return r.GetByID(ctx, id)
 }
 
@@ -252,9 +260,130 @@ func (r *ComputeEndpointRepo) endpointFromDB(row dbstore.ComputeEndpoint) (*doma
 		Owner:       row.Owner,
 		CreatedAt:   row.CreatedAt,
 		UpdatedAt:   row.UpdatedAt,
+		RowVersion:  row.RowVersion,
 	}, nil
 }
 
+// RecordLoad upserts the latest polled load for an endpoint. A failed poll
+// keeps the previous last_heartbeat_at so operators can see how long the
+// agent has been unreachable.
+func (r *ComputeEndpointRepo) RecordLoad(ctx context.Context, load *domain.ComputeEndpointLoad) error {
+	var lastErr sql.NullString
+	if load.LastError != "" {
+		lastErr = sql.NullString{String: load.LastError, Valid: true}
+	}
+	var heartbeat sql.NullTime
+	if load.LastHeartbeatAt != nil {
+		heartbeat = sql.NullTime{Time: load.LastHeartbeatAt.UTC(), Valid: true}
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO compute_endpoint_load (
+			endpoint_id, reachable, active_queries, waiting_queries, max_concurrent_queries,
+			queued_jobs, running_jobs, memory_used_mb, last_error, last_heartbeat_at, checked_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (endpoint_id) DO UPDATE SET
+			reachable = excluded.reachable,
+			active_queries = excluded.active_queries,
+			waiting_queries = excluded.waiting_queries,
+			max_concurrent_queries = excluded.max_concurrent_queries,
+			queued_jobs = excluded.queued_jobs,
+			running_jobs = excluded.running_jobs,
+			memory_used_mb = excluded.memory_used_mb,
+			last_error = excluded.last_error,
+			last_heartbeat_at = COALESCE(excluded.last_heartbeat_at, compute_endpoint_load.last_heartbeat_at),
+			checked_at = excluded.checked_at
+	`, load.EndpointID, boolToInt(load.Reachable), load.ActiveQueries, load.WaitingQueries, load.MaxConcurrentQueries,
+		load.QueuedJobs, load.RunningJobs, load.MemoryUsedMB, lastErr, heartbeat, load.CheckedAt.UTC())
+	if err != nil {
+		return mapDBError(err)
+	}
+	return nil
+}
+
+// ListLoads returns the latest polled load of every endpoint, keyed by endpoint ID.
+func (r *ComputeEndpointRepo) ListLoads(ctx context.Context) (map[string]domain.ComputeEndpointLoad, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT endpoint_id, reachable, active_queries, waiting_queries, max_concurrent_queries,
+			queued_jobs, running_jobs, memory_used_mb, last_error, last_heartbeat_at, checked_at
+		FROM compute_endpoint_load
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	loads := make(map[string]domain.ComputeEndpointLoad)
+	for rows.Next() {
+		var (
+			l         domain.ComputeEndpointLoad
+			reachable int64
+			lastErr   sql.NullString
+			heartbeat sql.NullTime
+		)
+		if err := rows.Scan(&l.EndpointID, &reachable, &l.ActiveQueries, &l.WaitingQueries, &l.MaxConcurrentQueries,
+			&l.QueuedJobs, &l.RunningJobs, &l.MemoryUsedMB, &lastErr, &heartbeat, &l.CheckedAt); err != nil {
+			return nil, err
+		}
+		l.Reachable = reachable != 0
+		l.LastError = lastErr.String
+		if heartbeat.Valid {
+			l.LastHeartbeatAt = &heartbeat.Time
+		}
+		loads[l.EndpointID] = l
+	}
+	return loads, rows.Err()
+}
+
+// AddAgent adds an agent URL to an endpoint.
+func (r *ComputeEndpointRepo) AddAgent(ctx context.Context, agent *domain.ComputeEndpointAgent) (*domain.ComputeEndpointAgent, error) {
+	out := domain.ComputeEndpointAgent{ID: newID(), EndpointID: agent.EndpointID, URL: agent.URL}
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO compute_endpoint_agents (id, endpoint_id, url) VALUES (?, ?, ?)
+		RETURNING created_at
+	`, out.ID, out.EndpointID, out.URL).Scan(&out.CreatedAt)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	return &out, nil
+}
+
+// RemoveAgent deletes an agent URL from an endpoint.
+func (r *ComputeEndpointRepo) RemoveAgent(ctx context.Context, endpointID, agentID string) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM compute_endpoint_agents WHERE endpoint_id = ? AND id = ?`, endpointID, agentID)
+	if err != nil {
+		return mapDBError(err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound("compute endpoint agent %q not found", agentID)
+	}
+	return nil
+}
+
+// ListAgents returns the agent URLs added to an endpoint, oldest first.
+func (r *ComputeEndpointRepo) ListAgents(ctx context.Context, endpointID string) ([]domain.ComputeEndpointAgent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, endpoint_id, url, created_at
+		FROM compute_endpoint_agents
+		WHERE endpoint_id = ?
+		ORDER BY created_at, rowid
+	`, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var agents []domain.ComputeEndpointAgent
+	for rows.Next() {
+		var a domain.ComputeEndpointAgent
+		if err := rows.Scan(&a.ID, &a.EndpointID, &a.URL, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		agents = append(agents, a)
+	}
+	return agents, rows.Err()
+}
+
 // assignmentFromDB converts a dbstore ComputeAssignment to a domain ComputeAssignment.
 func assignmentFromDB(row dbstore.ComputeAssignment) *domain.ComputeAssignment {
 	return &domain.ComputeAssignment{
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -235,6 +236,92 @@ func TestComputeEndpoint_UpdateStatus(t *testing.T) {
 	assert.Equal(t, "ACTIVE", got.Status)
 }
 
+func TestComputeEndpoint_RecordLoad(t *testing.T) {
+	repo := setupComputeEndpointRepo(t)
+	ctx := context.Background()
+
+	ep, err := repo.Create(ctx, &domain.ComputeEndpoint{
+		Name: "load-ep", URL: "grpc://compute-1:9444", Type: "REMOTE", AuthToken: "tok", Owner: "admin",
+	})
+	require.NoError(t, err)
+
+	heartbeat := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.RecordLoad(ctx, &domain.ComputeEndpointLoad{
+		EndpointID:           ep.ID,
+		Reachable:            true,
+		ActiveQueries:        4,
+		WaitingQueries:       2,
+		MaxConcurrentQueries: 4,
+		LastHeartbeatAt:      &heartbeat,
+		CheckedAt:            heartbeat,
+	}))
+
+	loads, err := repo.ListLoads(ctx)
+	require.NoError(t, err)
+	got, ok := loads[ep.ID]
+	require.True(t, ok)
+	assert.True(t, got.Reachable)
+	assert.Equal(t, int64(4), got.ActiveQueries)
+	assert.Equal(t, int64(2), got.WaitingQueries)
+	require.NotNil(t, got.LastHeartbeatAt)
+	assert.True(t, heartbeat.Equal(*got.LastHeartbeatAt))
+
+	// A failed poll keeps the last successful heartbeat.
+	require.NoError(t, repo.RecordLoad(ctx, &domain.ComputeEndpointLoad{
+		EndpointID: ep.ID,
+		LastError:  "connection refused",
+		CheckedAt:  heartbeat.Add(time.Minute),
+	}))
+	loads, err = repo.ListLoads(ctx)
+	require.NoError(t, err)
+	got = loads[ep.ID]
+	assert.False(t, got.Reachable)
+	assert.Equal(t, "connection refused", got.LastError)
+	require.NotNil(t, got.LastHeartbeatAt)
+	assert.True(t, heartbeat.Equal(*got.LastHeartbeatAt))
+
+	require.NoError(t, repo.Delete(ctx, ep.ID))
+	loads, err = repo.ListLoads(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, loads, ep.ID)
+}
+
+func TestComputeEndpoint_Agents(t *testing.T) {
+	repo := setupComputeEndpointRepo(t)
+	ctx := context.Background()
+
+	ep, err := repo.Create(ctx, &domain.ComputeEndpoint{
+		Name: "ha-ep", URL: "grpc://compute-1:9444", Type: "REMOTE", AuthToken: "tok", Owner: "admin",
+	})
+	require.NoError(t, err)
+
+	a2, err := repo.AddAgent(ctx, &domain.ComputeEndpointAgent{EndpointID: ep.ID, URL: "grpc://compute-2:9444"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, a2.ID)
+	assert.False(t, a2.CreatedAt.IsZero())
+	_, err = repo.AddAgent(ctx, &domain.ComputeEndpointAgent{EndpointID: ep.ID, URL: "grpc://compute-3:9444"})
+	require.NoError(t, err)
+
+	_, err = repo.AddAgent(ctx, &domain.ComputeEndpointAgent{EndpointID: ep.ID, URL: "grpc://compute-2:9444"})
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict)
+
+	agents, err := repo.ListAgents(ctx, ep.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 2)
+	assert.Equal(t, "grpc://compute-2:9444", agents[0].URL)
+	assert.Equal(t, "grpc://compute-3:9444", agents[1].URL)
+
+	require.NoError(t, repo.RemoveAgent(ctx, ep.ID, a2.ID))
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, repo.RemoveAgent(ctx, ep.ID, a2.ID), &notFound)
+
+	require.NoError(t, repo.Delete(ctx, ep.ID))
+	agents, err = repo.ListAgents(ctx, ep.ID)
+	require.NoError(t, err)
+	assert.Empty(t, agents)
+}
+
 func TestComputeAssignment_CRUD(t *testing.T) {
 	repo := setupComputeEndpointRepo(t)
 	ctx := context.Background()
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"duck-demo/internal/domain"
+)
+
+var _ domain.DefaultGrantRepository = (*DefaultGrantRepo)(nil)
+
+// DefaultGrantRepo stores default grant templates in SQLite.
+type DefaultGrantRepo struct {
+	db *sql.DB
+}
+
+// NewDefaultGrantRepo creates a new DefaultGrantRepo.
+func NewDefaultGrantRepo(db *sql.DB) *DefaultGrantRepo {
+	return &DefaultGrantRepo{db: db}
+}
+
+const defaultGrantColumns = `id, catalog_name, schema_name, object_type, principal_id, principal_type, privilege, created_by, created_at`
+
+// Create inserts a new default grant.
+func (r *DefaultGrantRepo) Create(ctx context.Context, g *domain.DefaultGrant) (*domain.DefaultGrant, error) {
+	if g.ID == "" {
+		g.ID = domain.NewID()
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO default_grants (id, catalog_name, schema_name, object_type, principal_id, principal_type, privilege, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, g.ID, g.CatalogName, g.SchemaName, g.ObjectType, g.PrincipalID, g.PrincipalType, g.Privilege, g.CreatedBy)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	return r.GetByID(ctx, g.ID)
+}
+
+// GetByID returns a default grant by ID.
+func (r *DefaultGrantRepo) GetByID(ctx context.Context, id string) (*domain.DefaultGrant, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+defaultGrantColumns+` FROM default_grants WHERE id = ?`, id)
+	g, err := scanDefaultGrant(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound("default grant %q not found", id)
+	}
+	return g, err
+}
+
+// List returns a page of default grants matching the filter, ordered by
+// scope and creation time.
+func (r *DefaultGrantRepo) List(ctx context.Context, filter domain.DefaultGrantFilter) ([]domain.DefaultGrant, int64, error) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	if filter.CatalogName != nil {
+		where = append(where, "catalog_name = ?")
+		args = append(args, *filter.CatalogName)
+	}
+	if filter.SchemaName != nil {
+		where = append(where, "schema_name = ?")
+		args = append(args, *filter.SchemaName)
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM default_grants`+clause, args...).Scan(&total); err != nil {
+		return nil, 0, mapDBError(err)
+	}
+	grants, err := r.query(ctx, `SELECT `+defaultGrantColumns+` FROM default_grants`+clause+
+		` ORDER BY catalog_name, schema_name, object_type, created_at, id LIMIT ? OFFSET ?`,
+		append(args, filter.Page.Limit(), filter.Page.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return grants, total, nil
+}
+
+// ListForObject returns the default grants for a new object of objectType in
+// catalogName. Catalog-wide templates always match; schema-scoped templates
+// match only when schemaName is their schema.
+func (r *DefaultGrantRepo) ListForObject(ctx context.Context, catalogName, schemaName, objectType string) ([]domain.DefaultGrant, error) {
+	return r.query(ctx, `SELECT `+defaultGrantColumns+` FROM default_grants
+		WHERE catalog_name = ? AND object_type = ? AND (schema_name = '' OR schema_name = ?)
+		ORDER BY created_at, id`, catalogName, objectType, schemaName)
+}
+
+// Delete removes a default grant. Grants it already created are kept.
+func (r *DefaultGrantRepo) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM default_grants WHERE id = ?`, id)
+	if err != nil {
+		return mapDBError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrNotFound("default grant %q not found", id)
+	}
+	return nil
+}
+
+func (r *DefaultGrantRepo) query(ctx context.Context, stmt string, args ...interface{}) ([]domain.DefaultGrant, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var grants []domain.DefaultGrant
+	for rows.Next() {
+		g, err := scanDefaultGrant(rows)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, *g)
+	}
+	return grants, rows.Err()
+}
+
+type defaultGrantRowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDefaultGrant(row defaultGrantRowScanner) (*domain.DefaultGrant, error) {
+	var g domain.DefaultGrant
+	if err := row.Scan(&g.ID, &g.CatalogName, &g.SchemaName, &g.ObjectType, &g.PrincipalID,
+		&g.PrincipalType, &g.Privilege, &g.CreatedBy, &g.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/db"
+	"duck-demo/internal/domain"
+)
+
+func setupDefaultGrantRepo(t *testing.T) *DefaultGrantRepo {
+	t.Helper()
+	writeDB, _ := db.OpenTestSQLite(t)
+	return NewDefaultGrantRepo(writeDB)
+}
+
+func TestDefaultGrantRepo_CRUD(t *testing.T) {
+	t.Parallel()
+	repo := setupDefaultGrantRepo(t)
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &domain.DefaultGrant{
+		CatalogName: "main", SchemaName: "analytics", ObjectType: domain.SecurableTable,
+		PrincipalID: "group-1", PrincipalType: "group", Privilege: domain.PrivSelect, CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	assert.Equal(t, "analytics", created.SchemaName)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	_, err = repo.Create(ctx, &domain.DefaultGrant{
+		CatalogName: "main", SchemaName: "analytics", ObjectType: domain.SecurableTable,
+		PrincipalID: "group-1", PrincipalType: "group", Privilege: domain.PrivSelect, CreatedBy: "admin",
+	})
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict, "the same template twice conflicts")
+
+	grants, total, err := repo.List(ctx, domain.DefaultGrantFilter{Page: domain.PageRequest{MaxResults: 10}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, grants, 1)
+
+	require.NoError(t, repo.Delete(ctx, created.ID))
+	_, err = repo.GetByID(ctx, created.ID)
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+	require.ErrorAs(t, repo.Delete(ctx, created.ID), &notFound)
+}
+
+func TestDefaultGrantRepo_ListForObject(t *testing.T) {
+	t.Parallel()
+	repo := setupDefaultGrantRepo(t)
+	ctx := context.Background()
+
+	seed := []domain.DefaultGrant{
+		{CatalogName: "main", ObjectType: domain.SecurableTable, Privilege: domain.PrivSelect},
+		{CatalogName: "main", SchemaName: "analytics", ObjectType: domain.SecurableTable, Privilege: domain.PrivModify},
+		{CatalogName: "main", SchemaName: "staging", ObjectType: domain.SecurableTable, Privilege: domain.PrivInsert},
+		{CatalogName: "main", ObjectType: domain.SecurableSchema, Privilege: domain.PrivUseSchema},
+		{CatalogName: "other", ObjectType: domain.SecurableTable, Privilege: domain.PrivDelete},
+	}
+	for i := range seed {
+		seed[i].PrincipalID = "group-1"
+		seed[i].PrincipalType = "group"
+		seed[i].CreatedBy = "admin"
+		_, err := repo.Create(ctx, &seed[i])
+		require.NoError(t, err)
+	}
+
+	privileges := func(grants []domain.DefaultGrant) []string {
+		var out []string
+		for _, g := range grants {
+			out = append(out, g.Privilege)
+		}
+		return out
+	}
+
+	tables, err := repo.ListForObject(ctx, "main", "analytics", domain.SecurableTable)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{domain.PrivSelect, domain.PrivModify}, privileges(tables))
+
+	schemas, err := repo.ListForObject(ctx, "main", "", domain.SecurableSchema)
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.PrivUseSchema}, privileges(schemas))
+
+	none, err := repo.ListForObject(ctx, "empty", "analytics", domain.SecurableTable)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	catalog := "main"
+	schema := "staging"
+	filtered, total, err := repo.List(ctx, domain.DefaultGrantFilter{CatalogName: &catalog, SchemaName: &schema, Page: domain.PageRequest{MaxResults: 10}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{domain.PrivInsert}, privileges(filtered))
+}
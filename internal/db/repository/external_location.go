@@ -111,17 +111,21 @@ func (r *ExternalLocationRepo) Update(ctx context.Context, id string, req domain
 		readOnly = boolToInt(*req.ReadOnly)
 	}
 
-	err = r.q.UpdateExternalLocation(ctx, dbstore.UpdateExternalLocationParams{
-		Url:            urlVal,
-		CredentialName: credName,
-		Comment:        comment,
-		Owner:          owner,
-		ReadOnly:       readOnly,
-		ID:             id,
+	n, err := r.q.UpdateExternalLocation(ctx, dbstore.UpdateExternalLocationParams{
+		Url:                urlVal,
+		CredentialName:     credName,
+		Comment:            comment,
+		Owner:              owner,
+		ReadOnly:           readOnly,
+		ID:                 id,
+		ExpectedRowVersion: rowVersionArg(req.ExpectedRowVersion),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, req.ExpectedRowVersion, "external location", current.Name); err != nil {
+		return nil, err
+	}
 	return r.GetByID(ctx, id)
 }
 
@@ -150,5 +154,6 @@ func fromDBLocation(row dbstore.ExternalLocation) *domain.ExternalLocation {
 		ReadOnly:       row.ReadOnly != 0,
 		CreatedAt:      createdAt,
 		UpdatedAt:      updatedAt,
+		RowVersion:     row.RowVersion,
 	}
 }
@@ -163,6 +163,15 @@ func (r *ExternalTableRepo) Delete(ctx context.Context, schemaName, tableName st
 	})
 }
 
+// Rename changes the name of a live external table.
+func (r *ExternalTableRepo) Rename(ctx context.Context, schemaName, tableName, newName string) error {
+	return mapDBError(r.q.RenameExternalTable(ctx, dbstore.RenameExternalTableParams{
+		NewName:    newName,
+		SchemaName: schemaName,
+		OldName:    tableName,
+	}))
+}
+
 // DeleteBySchema soft-deletes all external tables in a schema.
 func (r *ExternalTableRepo) DeleteBySchema(ctx context.Context, schemaName string) error {
 	return r.q.SoftDeleteExternalTablesBySchema(ctx, schemaName)
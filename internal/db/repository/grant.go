@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/db/mapper"
@@ -13,12 +16,13 @@ var _ domain.GrantRepository = (*GrantRepo)(nil)
 
 // GrantRepo implements domain.GrantRepository using SQLite.
 type GrantRepo struct {
-	q *dbstore.Queries
+	q  *dbstore.Queries
+	db *sql.DB
 }
 
 // NewGrantRepo creates a new GrantRepo.
 func NewGrantRepo(db *sql.DB) *GrantRepo {
-	return &GrantRepo{q: dbstore.New(db)}
+	return &GrantRepo{q: dbstore.New(db), db: db}
 }
 
 // Grant creates a new privilege grant.
@@ -27,6 +31,15 @@ func (r *GrantRepo) Grant(ctx context.Context, g *domain.PrivilegeGrant) (*domai
 	if g.GrantedBy != nil {
 		grantedBy = sql.NullString{String: *g.GrantedBy, Valid: true}
 	}
+	expiresAt := sql.NullString{}
+	if g.ExpiresAt != nil {
+		expiresAt = mapper.NullStrFromStr(g.ExpiresAt.UTC().Format(time.DateTime))
+	}
+	// Drop lapsed grants first so re-granting an expired privilege does not
+	// collide with the unique key before the background sweep runs.
+	if _, err := r.q.DeleteExpiredGrants(ctx); err != nil {
+		return nil, mapDBError(err)
+	}
 	row, err := r.q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
 		ID:            domain.NewID(),
 		PrincipalID:   g.PrincipalID,
@@ -35,6 +48,7 @@ func (r *GrantRepo) Grant(ctx context.Context, g *domain.PrivilegeGrant) (*domai
 		SecurableID:   g.SecurableID,
 		Privilege:     g.Privilege,
 		GrantedBy:     grantedBy,
+		ExpiresAt:     expiresAt,
 	})
 	if err != nil {
 		return nil, mapDBError(err)
@@ -58,6 +72,46 @@ func (r *GrantRepo) RevokeByID(ctx context.Context, id string) error {
 	return r.q.RevokePrivilegeByID(ctx, id)
 }
 
+// UpdateExpiry sets the expiry of a grant and returns the updated grant. The
+// update and the read-back share a transaction, so the grant is never
+// observed without the privilege it carries.
+func (r *GrantRepo) UpdateExpiry(ctx context.Context, id string, expiresAt *time.Time) (*domain.PrivilegeGrant, error) {
+	expires := sql.NullString{}
+	if expiresAt != nil {
+		expires = mapper.NullStrFromStr(expiresAt.UTC().Format(time.DateTime))
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin update grant tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.ExecContext(ctx, `UPDATE privilege_grants SET expires_at = ? WHERE id = ?`, expires, id)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, domain.ErrNotFound("grant %s not found", id)
+	}
+
+	var g dbstore.PrivilegeGrant
+	if err := tx.QueryRowContext(ctx, `
+		SELECT id, principal_id, principal_type, securable_type, securable_id, privilege, granted_by, granted_at, expires_at
+		FROM privilege_grants WHERE id = ?`, id).Scan(&g.ID, &g.PrincipalID, &g.PrincipalType, &g.SecurableType,
+		&g.SecurableID, &g.Privilege, &g.GrantedBy, &g.GrantedAt, &g.ExpiresAt); err != nil {
+		return nil, mapDBError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit update grant tx: %w", err)
+	}
+	return mapper.GrantFromDB(g), nil
+}
+
 // ListAll returns a paginated list of all grants.
 func (r *GrantRepo) ListAll(ctx context.Context, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
 	total, err := r.q.CountAllGrants(ctx)
@@ -122,9 +176,77 @@ func (r *GrantRepo) ListForSecurable(ctx context.Context, securableType string,
 	return mapper.GrantsFromDB(rows), total, nil
 }
 
-// HasPrivilege checks whether a principal has a specific privilege on a securable.
+// List returns a paginated list of grants matching every set field of the
+// filter. An unset filter matches all grants.
+func (r *GrantRepo) List(ctx context.Context, filter domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	for _, c := range []struct {
+		column string
+		value  *string
+	}{
+		{"principal_id", filter.PrincipalID},
+		{"principal_type", filter.PrincipalType},
+		{"securable_type", filter.SecurableType},
+		{"securable_id", filter.SecurableID},
+		{"privilege", filter.Privilege},
+	} {
+		if c.value != nil {
+			where = append(where, c.column+" = ?")
+			args = append(args, *c.value)
+		}
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM privilege_grants`+clause, args...).Scan(&total); err != nil {
+		return nil, 0, mapDBError(err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, principal_id, principal_type, securable_type, securable_id, privilege, granted_by, granted_at, expires_at
+		FROM privilege_grants`+clause+` ORDER BY id LIMIT ? OFFSET ?`,
+		append(args, filter.Page.Limit(), filter.Page.Offset())...)
+	if err != nil {
+		return nil, 0, mapDBError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var grants []dbstore.PrivilegeGrant
+	for rows.Next() {
+		var g dbstore.PrivilegeGrant
+		if err := rows.Scan(&g.ID, &g.PrincipalID, &g.PrincipalType, &g.SecurableType,
+			&g.SecurableID, &g.Privilege, &g.GrantedBy, &g.GrantedAt, &g.ExpiresAt); err != nil {
+			return nil, 0, err
+		}
+		grants = append(grants, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return mapper.GrantsFromDB(grants), total, nil
+}
+
+// DeleteExpired removes all grants whose expiry has passed and returns the
+// count deleted.
+func (r *GrantRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.q.DeleteExpiredGrants(ctx)
+	if err != nil {
+		return 0, mapDBError(err)
+	}
+	return result.RowsAffected()
+}
+
+// HasPrivilege checks whether a principal holds exactly the given, unexpired
+// privilege on a securable. Implied privileges such as ALL_PRIVILEGES are
+// expanded by the authorization service, not here.
 func (r *GrantRepo) HasPrivilege(ctx context.Context, principalID string, principalType, securableType string, securableID string, privilege string) (bool, error) {
-	cnt, err := r.q.CheckDirectGrantAny(ctx, dbstore.CheckDirectGrantAnyParams{
+	cnt, err := r.q.CheckDirectGrant(ctx, dbstore.CheckDirectGrantParams{
 		PrincipalID:   principalID,
 		PrincipalType: principalType,
 		SecurableType: securableType,
@@ -136,3 +258,82 @@ func (r *GrantRepo) HasPrivilege(ctx context.Context, principalID string, princi
 	}
 	return cnt > 0, nil
 }
+
+// NextExpiry returns the earliest expiry among the unexpired grants held by
+// the user principalID or any of groupIDs, or nil when none of them expires.
+func (r *GrantRepo) NextExpiry(ctx context.Context, principalID string, groupIDs []string) (*time.Time, error) {
+	identities := "(principal_type = 'user' AND principal_id = ?)"
+	args := []interface{}{principalID}
+	if len(groupIDs) > 0 {
+		identities += " OR (principal_type = 'group' AND principal_id IN (?" + strings.Repeat(", ?", len(groupIDs)-1) + "))"
+		for _, id := range groupIDs {
+			args = append(args, id)
+		}
+	}
+
+	var next sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT MIN(expires_at) FROM privilege_grants
+		WHERE (`+identities+`)
+		  AND expires_at IS NOT NULL AND expires_at > datetime('now')`, args...).Scan(&next)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	if !next.Valid {
+		return nil, nil
+	}
+	t, err := mapper.ParseTime(next.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// RevokeAllForPrincipal deletes every direct grant held by the principal and,
+// when removeMemberships is set, removes it from all groups. Both happen in a
+// single transaction so a failure leaves the principal's access untouched.
+func (r *GrantRepo) RevokeAllForPrincipal(ctx context.Context, principalID, principalType string, removeMemberships bool) (*domain.RevokedAccess, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin revoke-all tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := r.q.WithTx(tx)
+	grants, err := qtx.ListGrantsForPrincipal(ctx, dbstore.ListGrantsForPrincipalParams{
+		PrincipalID:   principalID,
+		PrincipalType: principalType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list grants: %w", err)
+	}
+	if err := qtx.RevokeAllGrantsForPrincipal(ctx, dbstore.RevokeAllGrantsForPrincipalParams{
+		PrincipalID:   principalID,
+		PrincipalType: principalType,
+	}); err != nil {
+		return nil, fmt.Errorf("revoke grants: %w", err)
+	}
+	revoked := &domain.RevokedAccess{Grants: mapper.GrantsFromDB(grants)}
+
+	if removeMemberships {
+		members, err := qtx.ListMembershipsForMember(ctx, dbstore.ListMembershipsForMemberParams{
+			MemberType: principalType,
+			MemberID:   principalID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list memberships: %w", err)
+		}
+		if err := qtx.RemoveMemberFromAllGroups(ctx, dbstore.RemoveMemberFromAllGroupsParams{
+			MemberType: principalType,
+			MemberID:   principalID,
+		}); err != nil {
+			return nil, fmt.Errorf("remove memberships: %w", err)
+		}
+		revoked.Memberships = mapper.GroupMembersFromDB(members)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit revoke-all tx: %w", err)
+	}
+	return revoked, nil
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
@@ -157,6 +159,57 @@ func TestGrantRepo_ListForSecurable(t *testing.T) {
 	assert.Len(t, grants, 2)
 }
 
+func TestGrantRepo_List_Filters(t *testing.T) {
+	grantRepo, principalRepo := setupGrantRepo(t)
+	ctx := context.Background()
+
+	alice := createTestPrincipal(t, principalRepo, "alice")
+	bob := createTestPrincipal(t, principalRepo, "bob")
+
+	for _, g := range []domain.PrivilegeGrant{
+		{PrincipalID: alice.ID, PrincipalType: "user", SecurableType: "table", SecurableID: "t1", Privilege: "SELECT"},
+		{PrincipalID: alice.ID, PrincipalType: "user", SecurableType: "table", SecurableID: "t1", Privilege: "INSERT"},
+		{PrincipalID: alice.ID, PrincipalType: "user", SecurableType: "schema", SecurableID: "s1", Privilege: "USAGE"},
+		{PrincipalID: bob.ID, PrincipalType: "user", SecurableType: "table", SecurableID: "t1", Privilege: "SELECT"},
+	} {
+		_, err := grantRepo.Grant(ctx, &g)
+		require.NoError(t, err)
+	}
+
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name   string
+		filter domain.GrantFilter
+		want   int
+	}{
+		{"no filter", domain.GrantFilter{}, 4},
+		{"principal", domain.GrantFilter{PrincipalID: str(alice.ID), PrincipalType: str("user")}, 3},
+		{"securable", domain.GrantFilter{SecurableType: str("table"), SecurableID: str("t1")}, 3},
+		{"privilege", domain.GrantFilter{Privilege: str("SELECT")}, 2},
+		{"principal and privilege are ANDed", domain.GrantFilter{PrincipalID: str(alice.ID), Privilege: str("SELECT")}, 1},
+		{"securable type only", domain.GrantFilter{SecurableType: str("schema")}, 1},
+		{"unknown principal is empty", domain.GrantFilter{PrincipalID: str("00000000-0000-0000-0000-000000000000")}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants, total, err := grantRepo.List(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(tt.want), total)
+			assert.Len(t, grants, tt.want)
+		})
+	}
+
+	t.Run("paginates", func(t *testing.T) {
+		grants, total, err := grantRepo.List(ctx, domain.GrantFilter{
+			PrincipalID: str(alice.ID),
+			Page:        domain.PageRequest{MaxResults: 2},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, grants, 2)
+	})
+}
+
 func TestGrantRepo_HasPrivilege_False(t *testing.T) {
 	grantRepo, principalRepo := setupGrantRepo(t)
 	ctx := context.Background()
@@ -167,3 +220,146 @@ func TestGrantRepo_HasPrivilege_False(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, has)
 }
+
+func TestGrantRepo_Expiry(t *testing.T) {
+	grantRepo, principalRepo := setupGrantRepo(t)
+	ctx := context.Background()
+
+	p := createTestPrincipal(t, principalRepo, "temp")
+	future := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	g, err := grantRepo.Grant(ctx, &domain.PrivilegeGrant{
+		PrincipalID: p.ID, PrincipalType: "user",
+		SecurableType: "table", SecurableID: "t1", Privilege: "SELECT",
+		ExpiresAt: &future,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, g.ExpiresAt)
+	assert.True(t, g.ExpiresAt.Equal(future), "expires_at should round-trip")
+
+	has, err := grantRepo.HasPrivilege(ctx, p.ID, "user", "table", "t1", "SELECT")
+	require.NoError(t, err)
+	assert.True(t, has, "unexpired grant should count")
+
+	past := time.Now().Add(-time.Minute)
+	_, err = grantRepo.Grant(ctx, &domain.PrivilegeGrant{
+		PrincipalID: p.ID, PrincipalType: "user",
+		SecurableType: "table", SecurableID: "t2", Privilege: "SELECT",
+		ExpiresAt: &past,
+	})
+	require.NoError(t, err)
+
+	has, err = grantRepo.HasPrivilege(ctx, p.ID, "user", "table", "t2", "SELECT")
+	require.NoError(t, err)
+	assert.False(t, has, "expired grant should be ignored")
+
+	n, err := grantRepo.DeleteExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	_, total, err := grantRepo.List(ctx, domain.GrantFilter{PrincipalID: &p.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total, "only the unexpired grant should remain")
+}
+
+func TestGrantRepo_NextExpiry(t *testing.T) {
+	grantRepo, principalRepo := setupGrantRepo(t)
+	ctx := context.Background()
+
+	p := createTestPrincipal(t, principalRepo, "temp")
+	next, err := grantRepo.NextExpiry(ctx, p.ID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, next, "no grants means nothing expires")
+
+	soon := time.Now().Add(time.Hour).Truncate(time.Second)
+	later := soon.Add(time.Hour)
+	past := time.Now().Add(-time.Minute)
+	for i, exp := range []*time.Time{nil, &later, &soon, &past} {
+		_, err := grantRepo.Grant(ctx, &domain.PrivilegeGrant{
+			PrincipalID: p.ID, PrincipalType: "user",
+			SecurableType: "table", SecurableID: fmt.Sprintf("t%d", i), Privilege: "SELECT",
+			ExpiresAt: exp,
+		})
+		require.NoError(t, err)
+	}
+	groupSoonest := soon.Add(-30 * time.Minute)
+	_, err = grantRepo.Grant(ctx, &domain.PrivilegeGrant{
+		PrincipalID: "group-1", PrincipalType: "group",
+		SecurableType: "table", SecurableID: "g", Privilege: "SELECT",
+		ExpiresAt: &groupSoonest,
+	})
+	require.NoError(t, err)
+
+	next, err = grantRepo.NextExpiry(ctx, p.ID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.True(t, next.Equal(soon), "earliest unexpired expiry of the user's grants, got %v", next)
+
+	next, err = grantRepo.NextExpiry(ctx, p.ID, []string{"group-1"})
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.True(t, next.Equal(groupSoonest), "group grants count too, got %v", next)
+}
+
+func TestGrantRepo_RevokeAllForPrincipal(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	grantRepo, principalRepo, groupRepo := NewGrantRepo(writeDB), NewPrincipalRepo(writeDB), NewGroupRepo(writeDB)
+	ctx := context.Background()
+
+	leaver := createTestPrincipal(t, principalRepo, "leaver")
+	stayer := createTestPrincipal(t, principalRepo, "stayer")
+	g, err := groupRepo.Create(ctx, &domain.Group{Name: "analysts"})
+	require.NoError(t, err)
+
+	for _, p := range []*domain.Principal{leaver, stayer} {
+		for _, securable := range []string{"t-1", "t-2"} {
+			_, err := grantRepo.Grant(ctx, &domain.PrivilegeGrant{
+				PrincipalID: p.ID, PrincipalType: "user",
+				SecurableType: "table", SecurableID: securable, Privilege: "SELECT",
+			})
+			require.NoError(t, err)
+		}
+		require.NoError(t, groupRepo.AddMember(ctx, &domain.GroupMember{GroupID: g.ID, MemberType: "user", MemberID: p.ID}))
+	}
+
+	revoked, err := grantRepo.RevokeAllForPrincipal(ctx, leaver.ID, "user", true)
+	require.NoError(t, err)
+	assert.Len(t, revoked.Grants, 2)
+	require.Len(t, revoked.Memberships, 1)
+	assert.Equal(t, g.ID, revoked.Memberships[0].GroupID)
+
+	_, total, err := grantRepo.ListForPrincipal(ctx, leaver.ID, "user", domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total, "all grants should be revoked")
+	groups, err := groupRepo.GetGroupsForMember(ctx, "user", leaver.ID)
+	require.NoError(t, err)
+	assert.Empty(t, groups, "all memberships should be removed")
+
+	// Other principals keep their access.
+	_, total, err = grantRepo.ListForPrincipal(ctx, stayer.ID, "user", domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	groups, err = groupRepo.GetGroupsForMember(ctx, "user", stayer.ID)
+	require.NoError(t, err)
+	assert.Len(t, groups, 1)
+}
+
+func TestGrantRepo_RevokeAllForPrincipal_KeepsMemberships(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	grantRepo, principalRepo, groupRepo := NewGrantRepo(writeDB), NewPrincipalRepo(writeDB), NewGroupRepo(writeDB)
+	ctx := context.Background()
+
+	p := createTestPrincipal(t, principalRepo, "mover")
+	g, err := groupRepo.Create(ctx, &domain.Group{Name: "engineering"})
+	require.NoError(t, err)
+	require.NoError(t, groupRepo.AddMember(ctx, &domain.GroupMember{GroupID: g.ID, MemberType: "user", MemberID: p.ID}))
+
+	revoked, err := grantRepo.RevokeAllForPrincipal(ctx, p.ID, "user", false)
+	require.NoError(t, err)
+	assert.Empty(t, revoked.Grants)
+	assert.Empty(t, revoked.Memberships)
+
+	groups, err := groupRepo.GetGroupsForMember(ctx, "user", p.ID)
+	require.NoError(t, err)
+	assert.Len(t, groups, 1)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/db/mapper"
@@ -11,12 +12,13 @@ import (
 
 // GroupRepo implements domain.GroupRepository using SQLite.
 type GroupRepo struct {
-	q *dbstore.Queries
+	q  *dbstore.Queries
+	db *sql.DB
 }
 
 // NewGroupRepo creates a new GroupRepo.
 func NewGroupRepo(db *sql.DB) *GroupRepo {
-	return &GroupRepo{q: dbstore.New(db)}
+	return &GroupRepo{q: dbstore.New(db), db: db}
 }
 
 // Create inserts a new group into the database.
@@ -50,22 +52,51 @@ func (r *GroupRepo) GetByName(ctx context.Context, name string) (*domain.Group,
 	return mapper.GroupFromDB(row), nil
 }
 
-// List returns a paginated list of groups.
+// List returns a paginated list of groups with their direct member counts,
+// ordered by page.Sort (by ID when it is empty).
 func (r *GroupRepo) List(ctx context.Context, page domain.PageRequest) ([]domain.Group, int64, error) {
 	total, err := r.q.CountGroups(ctx)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	rows, err := r.q.ListGroupsPaginated(ctx, dbstore.ListGroupsPaginatedParams{
-		Limit:  int64(page.Limit()),
-		Offset: int64(page.Offset()),
-	})
+	orderBy := "g.id"
+	switch page.Sort {
+	case domain.SortByName:
+		orderBy = "g.name, g.id"
+	case domain.SortByCreatedAt:
+		orderBy = "g.created_at, g.id"
+	case domain.SortByUpdatedAt:
+		orderBy = "g.updated_at, g.id"
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.id, g.name, g.description, g.created_at, g.updated_at,
+			(SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.id)
+		FROM groups g ORDER BY `+orderBy+` LIMIT ? OFFSET ?`,
+		page.Limit(), page.Offset())
 	if err != nil {
 		return nil, 0, err
 	}
-
-	return mapper.GroupsFromDB(rows), total, nil
+	defer rows.Close() //nolint:errcheck
+
+	var groups []domain.Group
+	for rows.Next() {
+		var (
+			row   dbstore.Group
+			count int64
+		)
+		if err := rows.Scan(&row.ID, &row.Name, &row.Description, &row.CreatedAt, &row.UpdatedAt, &count); err != nil {
+			return nil, 0, err
+		}
+		g := mapper.GroupFromDB(row)
+		g.MemberCount = count
+		groups = append(groups, *g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
 }
 
 // Delete removes a group by ID.
@@ -73,41 +104,132 @@ func (r *GroupRepo) Delete(ctx context.Context, id string) error {
 	return r.q.DeleteGroup(ctx, id)
 }
 
-// AddMember adds a principal to a group.
+// AddMember adds a principal to a group and bumps the group's updated_at.
 func (r *GroupRepo) AddMember(ctx context.Context, m *domain.GroupMember) error {
-	return r.q.AddGroupMember(ctx, dbstore.AddGroupMemberParams{
+	if err := r.q.AddGroupMember(ctx, dbstore.AddGroupMemberParams{
 		GroupID:    m.GroupID,
 		MemberType: m.MemberType,
 		MemberID:   m.MemberID,
-	})
+	}); err != nil {
+		return err
+	}
+	return r.q.TouchGroup(ctx, m.GroupID)
 }
 
-// RemoveMember removes a principal from a group.
+// RemoveMember removes a principal from a group and bumps the group's
+// updated_at.
 func (r *GroupRepo) RemoveMember(ctx context.Context, m *domain.GroupMember) error {
-	return r.q.RemoveGroupMember(ctx, dbstore.RemoveGroupMemberParams{
+	if err := r.q.RemoveGroupMember(ctx, dbstore.RemoveGroupMemberParams{
 		GroupID:    m.GroupID,
 		MemberType: m.MemberType,
 		MemberID:   m.MemberID,
-	})
+	}); err != nil {
+		return err
+	}
+	return r.q.TouchGroup(ctx, m.GroupID)
 }
 
-// ListMembers returns a paginated list of members in a group.
+// ListMembers returns a paginated list of members in a group with each
+// member's name and kind resolved.
 func (r *GroupRepo) ListMembers(ctx context.Context, groupID string, page domain.PageRequest) ([]domain.GroupMember, int64, error) {
 	total, err := r.q.CountGroupMembers(ctx, groupID)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	rows, err := r.q.ListGroupMembersPaginated(ctx, dbstore.ListGroupMembersPaginatedParams{
-		GroupID: groupID,
-		Limit:   int64(page.Limit()),
-		Offset:  int64(page.Offset()),
-	})
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT gm.group_id, gm.member_type, gm.member_id,
+			COALESCE(p.name, g.name, ''),
+			CASE WHEN gm.member_type = 'group' THEN 'group' ELSE COALESCE(p.type, 'user') END
+		FROM group_members gm
+		LEFT JOIN principals p ON gm.member_type = 'user' AND p.id = gm.member_id
+		LEFT JOIN groups g ON gm.member_type = 'group' AND g.id = gm.member_id
+		WHERE gm.group_id = ?
+		ORDER BY gm.member_id LIMIT ? OFFSET ?`,
+		groupID, page.Limit(), page.Offset())
 	if err != nil {
 		return nil, 0, err
 	}
+	defer rows.Close() //nolint:errcheck
+
+	var members []domain.GroupMember
+	for rows.Next() {
+		var m domain.GroupMember
+		if err := rows.Scan(&m.GroupID, &m.MemberType, &m.MemberID, &m.MemberName, &m.MemberKind); err != nil {
+			return nil, 0, err
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return members, total, nil
+}
+
+// SyncMembers replaces the membership of a group with members in a single
+// transaction, adding missing members and removing extra ones. It returns the
+// net changes; members present in both sets are left untouched, and the
+// group's updated_at is bumped only when something changed.
+func (r *GroupRepo) SyncMembers(ctx context.Context, groupID string, members []domain.GroupMember) (*domain.GroupMembershipChanges, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin sync-members tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := r.q.WithTx(tx)
+	current, err := qtx.ListGroupMembers(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("list current members: %w", err)
+	}
 
-	return mapper.GroupMembersFromDB(rows), total, nil
+	type memberKey struct{ memberType, memberID string }
+	desired := make(map[memberKey]bool, len(members))
+	for _, m := range members {
+		desired[memberKey{m.MemberType, m.MemberID}] = true
+	}
+	existing := make(map[memberKey]bool, len(current))
+	changes := &domain.GroupMembershipChanges{}
+
+	for _, c := range current {
+		k := memberKey{c.MemberType, c.MemberID}
+		existing[k] = true
+		if desired[k] {
+			continue
+		}
+		if err := qtx.RemoveGroupMember(ctx, dbstore.RemoveGroupMemberParams{
+			GroupID: groupID, MemberType: c.MemberType, MemberID: c.MemberID,
+		}); err != nil {
+			return nil, fmt.Errorf("remove member %s %s: %w", c.MemberType, c.MemberID, err)
+		}
+		changes.Removed = append(changes.Removed, domain.GroupMember{
+			GroupID: groupID, MemberType: c.MemberType, MemberID: c.MemberID,
+		})
+	}
+	for _, m := range members {
+		if existing[memberKey{m.MemberType, m.MemberID}] {
+			continue
+		}
+		if err := qtx.AddGroupMember(ctx, dbstore.AddGroupMemberParams{
+			GroupID: groupID, MemberType: m.MemberType, MemberID: m.MemberID,
+		}); err != nil {
+			return nil, fmt.Errorf("add member %s %s: %w", m.MemberType, m.MemberID, err)
+		}
+		changes.Added = append(changes.Added, domain.GroupMember{
+			GroupID: groupID, MemberType: m.MemberType, MemberID: m.MemberID,
+		})
+	}
+
+	if len(changes.Added) > 0 || len(changes.Removed) > 0 {
+		if err := qtx.TouchGroup(ctx, groupID); err != nil {
+			return nil, fmt.Errorf("touch group: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit sync-members tx: %w", err)
+	}
+	return changes, nil
 }
 
 // GetGroupsForMember returns all groups that the given member belongs to.
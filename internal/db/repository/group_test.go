@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
@@ -74,6 +75,88 @@ func TestGroupRepo_List(t *testing.T) {
 	assert.Len(t, groups, 3)
 }
 
+func TestGroupRepo_List_SortedPagesNeverOverlap(t *testing.T) {
+	groupRepo, _ := setupGroupRepo(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"epsilon", "beta", "delta", "alpha", "gamma"} {
+		_, err := groupRepo.Create(ctx, &domain.Group{Name: name})
+		require.NoError(t, err)
+	}
+
+	for _, sort := range []string{"", domain.SortByName, domain.SortByCreatedAt, domain.SortByUpdatedAt} {
+		t.Run("sort="+sort, func(t *testing.T) {
+			all, _, err := groupRepo.List(ctx, domain.PageRequest{Sort: sort})
+			require.NoError(t, err)
+			require.Len(t, all, 5)
+
+			var paged []string
+			for offset := 0; offset < 5; offset += 2 {
+				page, total, err := groupRepo.List(ctx, domain.PageRequest{
+					MaxResults: 2, PageToken: domain.EncodePageToken(offset), Sort: sort,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, int64(5), total)
+				for _, g := range page {
+					paged = append(paged, g.ID)
+				}
+			}
+
+			want := make([]string, len(all))
+			for i, g := range all {
+				want[i] = g.ID
+			}
+			assert.Equal(t, want, paged, "pages concatenate to the unpaged order")
+		})
+	}
+
+	byName, _, err := groupRepo.List(ctx, domain.PageRequest{Sort: domain.SortByName})
+	require.NoError(t, err)
+	names := make([]string, len(byName))
+	for i, g := range byName {
+		names[i] = g.Name
+	}
+	assert.Equal(t, []string{"alpha", "beta", "delta", "epsilon", "gamma"}, names)
+}
+
+func TestGroupRepo_MembershipChangeBumpsUpdatedAt(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	groupRepo, principalRepo := NewGroupRepo(writeDB), NewPrincipalRepo(writeDB)
+	ctx := context.Background()
+
+	g, err := groupRepo.Create(ctx, &domain.Group{Name: "team"})
+	require.NoError(t, err)
+	assert.Equal(t, g.CreatedAt, g.UpdatedAt)
+	p, err := principalRepo.Create(ctx, &domain.Principal{Name: "alice", Type: "user"})
+	require.NoError(t, err)
+
+	backdated := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	backdate := func() {
+		t.Helper()
+		_, err := writeDB.ExecContext(ctx, `UPDATE groups SET updated_at = '2020-01-01 00:00:00' WHERE id = ?`, g.ID)
+		require.NoError(t, err)
+	}
+	updatedAt := func() time.Time {
+		t.Helper()
+		found, err := groupRepo.GetByID(ctx, g.ID)
+		require.NoError(t, err)
+		return found.UpdatedAt
+	}
+
+	backdate()
+	require.NoError(t, groupRepo.AddMember(ctx, &domain.GroupMember{GroupID: g.ID, MemberType: "user", MemberID: p.ID}))
+	assert.True(t, updatedAt().After(backdated), "AddMember bumps updated_at")
+
+	backdate()
+	_, err = groupRepo.SyncMembers(ctx, g.ID, []domain.GroupMember{{GroupID: g.ID, MemberType: "user", MemberID: p.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, backdated, updatedAt(), "a no-op sync leaves updated_at alone")
+
+	_, err = groupRepo.SyncMembers(ctx, g.ID, nil)
+	require.NoError(t, err)
+	assert.True(t, updatedAt().After(backdated), "SyncMembers bumps updated_at when membership changes")
+}
+
 func TestGroupRepo_UniqueNameConstraint(t *testing.T) {
 	groupRepo, _ := setupGroupRepo(t)
 	ctx := context.Background()
@@ -165,3 +248,136 @@ func TestGroupRepo_ListMembers_Empty(t *testing.T) {
 	assert.Equal(t, int64(0), total)
 	assert.Empty(t, members)
 }
+
+func TestGroupRepo_ListMembers_MixedKinds(t *testing.T) {
+	groupRepo, principalRepo := setupGroupRepo(t)
+	ctx := context.Background()
+
+	g, err := groupRepo.Create(ctx, &domain.Group{Name: "platform"})
+	require.NoError(t, err)
+	nested, err := groupRepo.Create(ctx, &domain.Group{Name: "oncall"})
+	require.NoError(t, err)
+	user, err := principalRepo.Create(ctx, &domain.Principal{Name: "alice", Type: "user"})
+	require.NoError(t, err)
+	sp, err := principalRepo.Create(ctx, &domain.Principal{Name: "etl-bot", Type: "service_principal"})
+	require.NoError(t, err)
+
+	for _, m := range []domain.GroupMember{
+		{GroupID: g.ID, MemberType: "user", MemberID: user.ID},
+		{GroupID: g.ID, MemberType: "user", MemberID: sp.ID},
+		{GroupID: g.ID, MemberType: "group", MemberID: nested.ID},
+	} {
+		require.NoError(t, groupRepo.AddMember(ctx, &m))
+	}
+
+	members, total, err := groupRepo.ListMembers(ctx, g.ID, domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	byID := make(map[string]domain.GroupMember, len(members))
+	for _, m := range members {
+		byID[m.MemberID] = m
+	}
+	assert.Equal(t, "alice", byID[user.ID].MemberName)
+	assert.Equal(t, "user", byID[user.ID].MemberKind)
+	assert.Equal(t, "etl-bot", byID[sp.ID].MemberName)
+	assert.Equal(t, "service_principal", byID[sp.ID].MemberKind)
+	assert.Equal(t, "oncall", byID[nested.ID].MemberName)
+	assert.Equal(t, "group", byID[nested.ID].MemberKind)
+
+	groups, _, err := groupRepo.List(ctx, domain.PageRequest{})
+	require.NoError(t, err)
+	counts := make(map[string]int64, len(groups))
+	for _, gr := range groups {
+		counts[gr.Name] = gr.MemberCount
+	}
+	assert.Equal(t, int64(3), counts["platform"])
+	assert.Equal(t, int64(0), counts["oncall"])
+}
+
+func TestGroupRepo_SyncMembers(t *testing.T) {
+	groupRepo, principalRepo := setupGroupRepo(t)
+	ctx := context.Background()
+
+	g, err := groupRepo.Create(ctx, &domain.Group{Name: "sync-team"})
+	require.NoError(t, err)
+	nested, err := groupRepo.Create(ctx, &domain.Group{Name: "sync-nested"})
+	require.NoError(t, err)
+	var users []*domain.Principal
+	for _, name := range []string{"keep", "drop", "join"} {
+		p, err := principalRepo.Create(ctx, &domain.Principal{Name: name, Type: "user"})
+		require.NoError(t, err)
+		users = append(users, p)
+	}
+	keep, drop, join := users[0], users[1], users[2]
+
+	for _, p := range []*domain.Principal{keep, drop} {
+		require.NoError(t, groupRepo.AddMember(ctx, &domain.GroupMember{GroupID: g.ID, MemberType: "user", MemberID: p.ID}))
+	}
+
+	changes, err := groupRepo.SyncMembers(ctx, g.ID, []domain.GroupMember{
+		{MemberType: "user", MemberID: keep.ID},
+		{MemberType: "user", MemberID: join.ID},
+		{MemberType: "group", MemberID: nested.ID},
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []domain.GroupMember{
+		{GroupID: g.ID, MemberType: "user", MemberID: join.ID},
+		{GroupID: g.ID, MemberType: "group", MemberID: nested.ID},
+	}, changes.Added)
+	assert.Equal(t, []domain.GroupMember{{GroupID: g.ID, MemberType: "user", MemberID: drop.ID}}, changes.Removed)
+
+	members, total, err := groupRepo.ListMembers(ctx, g.ID, domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	ids := make([]string, len(members))
+	for i, m := range members {
+		ids[i] = m.MemberID
+	}
+	assert.ElementsMatch(t, []string{keep.ID, join.ID, nested.ID}, ids)
+
+	// Syncing to the same set is a no-op.
+	changes, err = groupRepo.SyncMembers(ctx, g.ID, []domain.GroupMember{
+		{MemberType: "user", MemberID: keep.ID},
+		{MemberType: "user", MemberID: join.ID},
+		{MemberType: "group", MemberID: nested.ID},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, changes.Added)
+	assert.Empty(t, changes.Removed)
+
+	// Syncing to an empty set removes everyone.
+	changes, err = groupRepo.SyncMembers(ctx, g.ID, nil)
+	require.NoError(t, err)
+	assert.Len(t, changes.Removed, 3)
+	_, total, err = groupRepo.ListMembers(ctx, g.ID, domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestGroupRepo_SyncMembers_Atomic(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	groupRepo := NewGroupRepo(writeDB)
+	ctx := context.Background()
+
+	g, err := groupRepo.Create(ctx, &domain.Group{Name: "atomic-team"})
+	require.NoError(t, err)
+	require.NoError(t, groupRepo.AddMember(ctx, &domain.GroupMember{GroupID: g.ID, MemberType: "user", MemberID: "old"}))
+
+	// Fail the insert of one member after the removal has already run.
+	_, err = writeDB.ExecContext(ctx, `
+		CREATE TRIGGER reject_member BEFORE INSERT ON group_members
+		WHEN NEW.member_id = 'poison'
+		BEGIN SELECT RAISE(ABORT, 'rejected'); END`)
+	require.NoError(t, err)
+
+	_, err = groupRepo.SyncMembers(ctx, g.ID, []domain.GroupMember{
+		{MemberType: "user", MemberID: "new"},
+		{MemberType: "user", MemberID: "poison"},
+	})
+	require.Error(t, err)
+
+	members, _, err := groupRepo.ListMembers(ctx, g.ID, domain.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, members, 1, "a failed sync must leave membership unchanged")
+	assert.Equal(t, "old", members[0].MemberID)
+}
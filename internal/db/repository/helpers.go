@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"duck-demo/internal/db"
 	"duck-demo/internal/domain"
 )
 
@@ -31,7 +32,33 @@ func mapDBError(err error) error {
 		return &domain.NotFoundError{Message: "resource not found"}
 	}
 	if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-		return &domain.ConflictError{Message: "resource already exists"}
+		return &domain.ConflictError{Message: "resource already exists", AlreadyExists: true}
+	}
+	if db.IsBusy(err) {
+		return db.MapBusy(err)
 	}
 	return err
 }
+
+// rowVersionArg is the query argument for the expected row version of a
+// conditional update; nil leaves the update unconditional.
+func rowVersionArg(expected *int64) interface{} {
+	if expected == nil {
+		return nil
+	}
+	return *expected
+}
+
+// checkRowVersion returns a PreconditionFailedError when an update guarded by
+// an expected row version matched no row: the resource changed since the
+// client read it. Unconditional updates are left to report their own errors.
+func checkRowVersion(rows int64, expected *int64, kind, name string) error {
+	if rows == 0 && expected != nil {
+		return staleRowVersion(kind, name)
+	}
+	return nil
+}
+
+func staleRowVersion(kind, name string) error {
+	return domain.ErrPreconditionFailed("%s %q has changed since it was read; fetch it again and retry", kind, name)
+}
@@ -12,12 +12,13 @@ import (
 
 // LineageRepo implements domain.LineageRepository using SQLite.
 type LineageRepo struct {
-	q *dbstore.Queries
+	q  *dbstore.Queries
+	db *sql.DB
 }
 
 // NewLineageRepo creates a new LineageRepo.
 func NewLineageRepo(db *sql.DB) *LineageRepo {
-	return &LineageRepo{q: dbstore.New(db)}
+	return &LineageRepo{q: dbstore.New(db), db: db}
 }
 
 // InsertEdge records a new lineage edge between tables.
@@ -92,6 +93,14 @@ func (r *LineageRepo) DeleteEdge(ctx context.Context, id string) error {
 	return r.q.DeleteLineageEdge(ctx, id)
 }
 
+// DeleteEdgesByTarget removes every edge of the given type that points at
+// targetTable. Column lineage attached to those edges is removed by cascade.
+func (r *LineageRepo) DeleteEdgesByTarget(ctx context.Context, targetTable, edgeType string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM lineage_edges WHERE target_table = ? AND edge_type = ?`, targetTable, edgeType)
+	return err
+}
+
 // PurgeOlderThan removes lineage edges created before the given time.
 func (r *LineageRepo) PurgeOlderThan(ctx context.Context, before time.Time) (int64, error) {
 	return r.q.PurgeLineageOlderThan(ctx, before.Format("2006-01-02 15:04:05"))
@@ -135,6 +135,31 @@ func TestLineageRepo_DeleteEdge(t *testing.T) {
 	assert.Empty(t, edges)
 }
 
+func TestLineageRepo_DeleteEdgesByTarget(t *testing.T) {
+	repo, _ := setupLineageRepo(t)
+	ctx := context.Background()
+
+	for _, edge := range []*domain.LineageEdge{
+		{SourceTable: "raw.orders", TargetTable: lineagePtrStr("sales.stg_orders"), EdgeType: "MODEL", PrincipalName: "alice"},
+		{SourceTable: "raw.customers", TargetTable: lineagePtrStr("sales.stg_orders"), EdgeType: "MODEL", PrincipalName: "alice"},
+		{SourceTable: "raw.orders", TargetTable: lineagePtrStr("sales.stg_orders"), EdgeType: "READ", PrincipalName: "alice"},
+		{SourceTable: "raw.orders", TargetTable: lineagePtrStr("sales.other"), EdgeType: "MODEL", PrincipalName: "alice"},
+	} {
+		require.NoError(t, repo.InsertEdge(ctx, edge))
+	}
+
+	require.NoError(t, repo.DeleteEdgesByTarget(ctx, "sales.stg_orders", "MODEL"))
+
+	edges, _, err := repo.GetUpstream(ctx, "sales.stg_orders", domain.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	assert.Equal(t, "READ", edges[0].EdgeType)
+
+	_, total, err := repo.GetUpstream(ctx, "sales.other", domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
 func TestLineageRepo_PurgeOlderThan(t *testing.T) {
 	repo, _ := setupLineageRepo(t)
 	ctx := context.Background()
@@ -151,22 +151,26 @@ func (r *MacroRepo) Update(ctx context.Context, name string, req domain.UpdateMa
 		!equalStrings(params, current.Parameters) ||
 		status != current.Status
 
-	err = r.q.UpdateMacro(ctx, dbstore.UpdateMacroParams{
-		Body:        body,
-		Description: description,
-		Parameters:  string(paramsJSON),
-		Status:      status,
-		CatalogName: catalogName,
-		ProjectName: projectName,
-		Visibility:  visibility,
-		Owner:       owner,
-		Properties:  mustJSONString(properties),
-		Tags:        mustJSONArray(tags),
-		Name:        name,
+	n, err := r.q.UpdateMacro(ctx, dbstore.UpdateMacroParams{
+		Body:               body,
+		Description:        description,
+		Parameters:         string(paramsJSON),
+		Status:             status,
+		CatalogName:        catalogName,
+		ProjectName:        projectName,
+		Visibility:         visibility,
+		Owner:              owner,
+		Properties:         mustJSONString(properties),
+		Tags:               mustJSONArray(tags),
+		Name:               name,
+		ExpectedRowVersion: rowVersionArg(req.ExpectedRowVersion),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, req.ExpectedRowVersion, "macro", name); err != nil {
+		return nil, err
+	}
 	updated, err := r.GetByName(ctx, name)
 	if err != nil {
 		return nil, err
@@ -294,6 +298,7 @@ func macroFromDB(row dbstore.Macro) *domain.Macro {
 		CreatedBy:   row.CreatedBy,
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
+		RowVersion:  row.RowVersion,
 	}
 }
 
@@ -57,6 +57,9 @@ func (f *MetastoreRepoFactory) ForCatalog(ctx context.Context, catalogName strin
 	if err != nil {
 		return nil, fmt.Errorf("lookup catalog %q: %w", catalogName, err)
 	}
+	if err := checkCatalogAttached(reg); err != nil {
+		return nil, err
+	}
 
 	db, err := f.openMetastore(reg)
 	if err != nil {
@@ -36,6 +36,14 @@ func (r *ModelRepo) Create(ctx context.Context, m *domain.Model) (*domain.Model,
 	if err != nil {
 		return nil, fmt.Errorf("marshal depends_on: %w", err)
 	}
+	macroDeps := m.MacroDeps
+	if macroDeps == nil {
+		macroDeps = []string{}
+	}
+	macroDepsJSON, err := json.Marshal(macroDeps)
+	if err != nil {
+		return nil, fmt.Errorf("marshal macro_deps: %w", err)
+	}
 	configJSON, err := json.Marshal(m.Config)
 	if err != nil {
 		return nil, fmt.Errorf("marshal config: %w", err)
@@ -70,6 +78,7 @@ func (r *ModelRepo) Create(ctx context.Context, m *domain.Model) (*domain.Model,
 		Owner:           m.Owner,
 		Tags:            string(tagsJSON),
 		DependsOn:       string(depsJSON),
+		MacroDeps:       string(macroDepsJSON),
 		Config:          string(configJSON),
 		CreatedBy:       m.CreatedBy,
 		Contract:        contractJSON,
@@ -197,20 +206,24 @@ func (r *ModelRepo) Update(ctx context.Context, id string, req domain.UpdateMode
 		}
 	}
 
-	err = r.q.UpdateModel(ctx, dbstore.UpdateModelParams{
-		SqlBody:         sqlBody,
-		Materialization: materialization,
-		Description:     description,
-		Tags:            string(tagsJSON),
-		Config:          string(configJSON),
-		Contract:        string(contractJSON),
-		FreshnessMaxLag: freshnessMaxLag,
-		FreshnessCron:   freshnessCron,
-		ID:              id,
+	n, err := r.q.UpdateModel(ctx, dbstore.UpdateModelParams{
+		SqlBody:            sqlBody,
+		Materialization:    materialization,
+		Description:        description,
+		Tags:               string(tagsJSON),
+		Config:             string(configJSON),
+		Contract:           string(contractJSON),
+		FreshnessMaxLag:    freshnessMaxLag,
+		FreshnessCron:      freshnessCron,
+		ID:                 id,
+		ExpectedRowVersion: rowVersionArg(req.ExpectedRowVersion),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, req.ExpectedRowVersion, "model", current.Name); err != nil {
+		return nil, err
+	}
 	return r.GetByID(ctx, id)
 }
 
@@ -245,6 +258,21 @@ func (r *ModelRepo) UpdateDependencies(ctx context.Context, id string, deps []st
 	}))
 }
 
+// UpdateMacroDependencies updates the macros a model's SQL calls.
+func (r *ModelRepo) UpdateMacroDependencies(ctx context.Context, id string, macros []string) error {
+	if macros == nil {
+		macros = []string{}
+	}
+	macrosJSON, err := json.Marshal(macros)
+	if err != nil {
+		return fmt.Errorf("marshal macro_deps: %w", err)
+	}
+	return mapDBError(r.q.UpdateModelMacroDependencies(ctx, dbstore.UpdateModelMacroDependenciesParams{
+		MacroDeps: string(macrosJSON),
+		ID:        id,
+	}))
+}
+
 // === Private mappers ===
 
 func modelFromDB(row dbstore.Model) *domain.Model {
@@ -269,6 +297,12 @@ func modelFromDB(row dbstore.Model) *domain.Model {
 		deps = []string{}
 	}
 
+	var macroDeps []string
+	_ = json.Unmarshal([]byte(row.MacroDeps), &macroDeps)
+	if macroDeps == nil {
+		macroDeps = []string{}
+	}
+
 	var config domain.ModelConfig
 	_ = json.Unmarshal([]byte(row.Config), &config)
 
@@ -299,11 +333,13 @@ func modelFromDB(row dbstore.Model) *domain.Model {
 		Owner:           row.Owner,
 		Tags:            tags,
 		DependsOn:       deps,
+		MacroDeps:       macroDeps,
 		Config:          config,
 		Contract:        contract,
 		Freshness:       freshness,
 		CreatedBy:       row.CreatedBy,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
+		RowVersion:      row.RowVersion,
 	}
 }
@@ -266,6 +266,28 @@ func TestModelRepo_UpdateDependencies(t *testing.T) {
 	assert.Equal(t, []string{"sales.stg_orders", "sales.stg_customers"}, got.DependsOn)
 }
 
+func TestModelRepo_MacroDependencies(t *testing.T) {
+	repo := setupModelRepo(t)
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &domain.Model{
+		ProjectName:     "sales",
+		Name:            "fct_orders",
+		SQL:             "SELECT fmt_money(amount) FROM orders",
+		Materialization: "VIEW",
+		MacroDeps:       []string{"fmt_money"},
+		CreatedBy:       "admin",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fmt_money"}, created.MacroDeps)
+
+	require.NoError(t, repo.UpdateMacroDependencies(ctx, created.ID, nil))
+
+	got, err := repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.MacroDeps)
+}
+
 func TestModelRepo_DuplicateConflict(t *testing.T) {
 	repo := setupModelRepo(t)
 	ctx := context.Background()
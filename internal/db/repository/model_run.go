@@ -175,6 +175,44 @@ func (r *ModelRunRepo) UpdateStepFinished(ctx context.Context, id string, status
 	}))
 }
 
+// UpdateStepMetrics records how long a model run step took and, when known,
+// the size of the relation it materialized.
+func (r *ModelRunRepo) UpdateStepMetrics(ctx context.Context, id string, durationMs int64, bytesWritten *int64) error {
+	var bw sql.NullInt64
+	if bytesWritten != nil {
+		bw = sql.NullInt64{Int64: *bytesWritten, Valid: true}
+	}
+
+	return mapDBError(r.q.UpdateModelRunStepMetrics(ctx, dbstore.UpdateModelRunStepMetricsParams{
+		DurationMs:   sql.NullInt64{Int64: durationMs, Valid: true},
+		BytesWritten: bw,
+		ID:           id,
+	}))
+}
+
+// ListStepsByModel returns the run steps of a single model, newest first.
+func (r *ModelRunRepo) ListStepsByModel(ctx context.Context, modelName string, page domain.PageRequest) ([]domain.ModelRunStep, int64, error) {
+	total, err := r.q.CountModelRunStepsByModel(ctx, modelName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.q.ListModelRunStepsByModel(ctx, dbstore.ListModelRunStepsByModelParams{
+		ModelName: modelName,
+		Limit:     int64(page.Limit()),
+		Offset:    int64(page.Offset()),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	steps := make([]domain.ModelRunStep, 0, len(rows))
+	for _, row := range rows {
+		steps = append(steps, *modelRunStepFromDB(row))
+	}
+	return steps, total, nil
+}
+
 // === Private mappers ===
 
 func modelRunFromDB(row dbstore.ModelRun) *domain.ModelRun {
@@ -284,6 +322,16 @@ func modelRunStepFromDB(row dbstore.ModelRunStep) *domain.ModelRunStep {
 		rowsAffected = &row.RowsAffected.Int64
 	}
 
+	var bytesWritten *int64
+	if row.BytesWritten.Valid {
+		bytesWritten = &row.BytesWritten.Int64
+	}
+
+	var durationMs *int64
+	if row.DurationMs.Valid {
+		durationMs = &row.DurationMs.Int64
+	}
+
 	dependsOn := make([]string, 0)
 	if row.DependsOn != "" {
 		_ = json.Unmarshal([]byte(row.DependsOn), &dependsOn)
@@ -319,6 +367,8 @@ func modelRunStepFromDB(row dbstore.ModelRunStep) *domain.ModelRunStep {
 		Status:       row.Status,
 		Tier:         int(row.Tier),
 		RowsAffected: rowsAffected,
+		BytesWritten: bytesWritten,
+		DurationMs:   durationMs,
 		StartedAt:    startedAt,
 		FinishedAt:   finishedAt,
 		ErrorMessage: errMsg,
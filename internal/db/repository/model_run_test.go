@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	internaldb "duck-demo/internal/db"
+	"duck-demo/internal/domain"
+)
+
+func setupModelRunRepo(t *testing.T) *ModelRunRepo {
+	t.Helper()
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	return NewModelRunRepo(writeDB)
+}
+
+func TestModelRunRepo_StepMetricsAndHistory(t *testing.T) {
+	repo := setupModelRunRepo(t)
+	ctx := context.Background()
+
+	run, err := repo.CreateRun(ctx, &domain.ModelRun{
+		Status:        domain.ModelRunStatusPending,
+		TriggerType:   domain.ModelTriggerTypeManual,
+		TriggeredBy:   "admin",
+		TargetCatalog: "lake",
+		TargetSchema:  "analytics",
+	})
+	require.NoError(t, err)
+
+	var stepIDs []string
+	for _, name := range []string{"sales.stg_orders", "sales.fct_orders", "sales.stg_orders"} {
+		step, err := repo.CreateStep(ctx, &domain.ModelRunStep{
+			RunID:     run.ID,
+			ModelID:   "m-" + name,
+			ModelName: name,
+			Status:    domain.ModelRunStatusPending,
+		})
+		require.NoError(t, err)
+		stepIDs = append(stepIDs, step.ID)
+	}
+
+	rows := int64(42)
+	bytesWritten := int64(4096)
+	require.NoError(t, repo.UpdateStepMetrics(ctx, stepIDs[0], 125, &bytesWritten))
+	require.NoError(t, repo.UpdateStepFinished(ctx, stepIDs[0], domain.ModelRunStatusSuccess, &rows, nil))
+	require.NoError(t, repo.UpdateStepMetrics(ctx, stepIDs[2], 7, nil))
+
+	steps, total, err := repo.ListStepsByModel(ctx, "sales.stg_orders", domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, steps, 2)
+
+	// Newest first: the third step was created last.
+	assert.Equal(t, stepIDs[2], steps[0].ID)
+	require.NotNil(t, steps[0].DurationMs)
+	assert.Equal(t, int64(7), *steps[0].DurationMs)
+	assert.Nil(t, steps[0].BytesWritten)
+
+	assert.Equal(t, stepIDs[0], steps[1].ID)
+	assert.Equal(t, domain.ModelRunStatusSuccess, steps[1].Status)
+	require.NotNil(t, steps[1].RowsAffected)
+	assert.Equal(t, int64(42), *steps[1].RowsAffected)
+	require.NotNil(t, steps[1].DurationMs)
+	assert.Equal(t, int64(125), *steps[1].DurationMs)
+	require.NotNil(t, steps[1].BytesWritten)
+	assert.Equal(t, int64(4096), *steps[1].BytesWritten)
+
+	_, total, err = repo.ListStepsByModel(ctx, "sales.unknown", domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
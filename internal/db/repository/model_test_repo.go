@@ -147,6 +147,29 @@ func (r *ModelTestResultRepo) ListByStep(ctx context.Context, runStepID string)
 	return results, nil
 }
 
+// ListByTest returns the results of one test across runs, newest first.
+func (r *ModelTestResultRepo) ListByTest(ctx context.Context, testID string, page domain.PageRequest) ([]domain.ModelTestResult, int64, error) {
+	total, err := r.q.CountModelTestResultsByTest(ctx, testID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.q.ListModelTestResultsByTest(ctx, dbstore.ListModelTestResultsByTestParams{
+		TestID: testID,
+		Limit:  int64(page.Limit()),
+		Offset: int64(page.Offset()),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]domain.ModelTestResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, *modelTestResultFromDB(row))
+	}
+	return results, total, nil
+}
+
 // === Private mapper ===
 
 func modelTestResultFromDB(row dbstore.ModelTestResult) *domain.ModelTestResult {
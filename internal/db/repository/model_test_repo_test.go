@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	internaldb "duck-demo/internal/db"
+	"duck-demo/internal/domain"
+)
+
+func TestModelTestResultRepo_ListByTest(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	runs := NewModelRunRepo(writeDB)
+	repo := NewModelTestResultRepo(writeDB)
+	ctx := context.Background()
+
+	run, err := runs.CreateRun(ctx, &domain.ModelRun{
+		Status:       domain.ModelRunStatusPending,
+		TriggerType:  domain.ModelTriggerTypeManual,
+		TriggeredBy:  "admin",
+		TargetSchema: "analytics",
+	})
+	require.NoError(t, err)
+
+	for i, status := range []string{domain.TestResultPass, domain.TestResultFail, domain.TestResultFail} {
+		step, err := runs.CreateStep(ctx, &domain.ModelRunStep{
+			RunID:     run.ID,
+			ModelID:   "m1",
+			ModelName: "sales.stg_orders",
+			Status:    domain.ModelRunStatusSuccess,
+		})
+		require.NoError(t, err)
+		failing := int64(i)
+		_, err = repo.Create(ctx, &domain.ModelTestResult{
+			RunStepID:    step.ID,
+			TestID:       "test-1",
+			TestName:     "not_null_customer",
+			Status:       status,
+			RowsReturned: &failing,
+		})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, &domain.ModelTestResult{
+			RunStepID: step.ID,
+			TestID:    "test-2",
+			TestName:  "unique_id",
+			Status:    domain.TestResultPass,
+		})
+		require.NoError(t, err)
+	}
+
+	results, total, err := repo.ListByTest(ctx, "test-1", domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, results, 3)
+	for i, want := range []int64{2, 1, 0} {
+		assert.Equal(t, "test-1", results[i].TestID)
+		require.NotNil(t, results[i].RowsReturned)
+		assert.Equal(t, want, *results[i].RowsReturned, "results are newest first")
+	}
+
+	page, total, err := repo.ListByTest(ctx, "test-1", domain.PageRequest{MaxResults: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, page, 2)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"duck-demo/internal/db/dbstore"
@@ -29,6 +30,8 @@ func (r *NotebookRepo) CreateNotebook(ctx context.Context, nb *domain.Notebook)
 		Name:        nb.Name,
 		Description: mapper.NullStrFromPtr(nb.Description),
 		Owner:       nb.Owner,
+		GitRepoID:   mapper.NullStrFromPtr(nb.GitRepoID),
+		GitPath:     mapper.NullStrFromPtr(nb.GitPath),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
@@ -64,6 +67,15 @@ func (r *NotebookRepo) ListNotebooks(ctx context.Context, owner *string, page do
 	return mapper.NotebooksFromDB(rows), total, nil
 }
 
+// ListNotebooksByGitRepo returns the notebooks synced from a git repo, ordered by name.
+func (r *NotebookRepo) ListNotebooksByGitRepo(ctx context.Context, gitRepoID string) ([]domain.Notebook, error) {
+	rows, err := r.q.ListNotebooksByGitRepo(ctx, mapper.NullStrFromStr(gitRepoID))
+	if err != nil {
+		return nil, err
+	}
+	return mapper.NotebooksFromDB(rows), nil
+}
+
 // UpdateNotebook applies partial updates to an existing notebook.
 func (r *NotebookRepo) UpdateNotebook(ctx context.Context, id string, req domain.UpdateNotebookRequest) (*domain.Notebook, error) {
 	existing, err := r.q.GetNotebook(ctx, id)
@@ -82,10 +94,14 @@ func (r *NotebookRepo) UpdateNotebook(ctx context.Context, id string, req domain
 	}
 
 	row, err := r.q.UpdateNotebook(ctx, dbstore.UpdateNotebookParams{
-		Name:        name,
-		Description: description,
-		ID:          id,
+		Name:               name,
+		Description:        description,
+		ID:                 id,
+		ExpectedRowVersion: rowVersionArg(req.ExpectedRowVersion),
 	})
+	if errors.Is(err, sql.ErrNoRows) && req.ExpectedRowVersion != nil {
+		return nil, staleRowVersion("notebook", existing.Name)
+	}
 	if err != nil {
 		return nil, mapDBError(err)
 	}
@@ -118,6 +118,31 @@ func TestNotebookRepo_UpdateNotebook(t *testing.T) {
 		var notFound *domain.NotFoundError
 		assert.ErrorAs(t, err, &notFound)
 	})
+
+	t.Run("row version", func(t *testing.T) {
+		current, err := repo.GetNotebook(ctx, nb.ID)
+		require.NoError(t, err)
+		read := current.RowVersion
+
+		updated, err := repo.UpdateNotebook(ctx, nb.ID, domain.UpdateNotebookRequest{
+			Name:               notebookPtrStr("Versioned"),
+			ExpectedRowVersion: &read,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, read+1, updated.RowVersion)
+
+		// A second writer holding the same version loses.
+		_, err = repo.UpdateNotebook(ctx, nb.ID, domain.UpdateNotebookRequest{
+			Name:               notebookPtrStr("Overwritten"),
+			ExpectedRowVersion: &read,
+		})
+		var stale *domain.PreconditionFailedError
+		require.ErrorAs(t, err, &stale)
+
+		got, err := repo.GetNotebook(ctx, nb.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Versioned", got.Name)
+	})
 }
 
 func TestNotebookRepo_DeleteNotebook(t *testing.T) {
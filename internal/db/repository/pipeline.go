@@ -107,16 +107,20 @@ func (r *PipelineRepo) UpdatePipeline(ctx context.Context, id string, req domain
 		sched = sql.NullString{String: *req.ScheduleCron, Valid: *req.ScheduleCron != ""}
 	}
 
-	err = r.q.UpdatePipeline(ctx, dbstore.UpdatePipelineParams{
-		Description:      desc,
-		ScheduleCron:     sched,
-		IsPaused:         boolToInt(paused),
-		ConcurrencyLimit: int64(concLimit),
-		ID:               id,
+	n, err := r.q.UpdatePipeline(ctx, dbstore.UpdatePipelineParams{
+		Description:        desc,
+		ScheduleCron:       sched,
+		IsPaused:           boolToInt(paused),
+		ConcurrencyLimit:   int64(concLimit),
+		ID:                 id,
+		ExpectedRowVersion: rowVersionArg(req.ExpectedRowVersion),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, req.ExpectedRowVersion, "pipeline", current.Name); err != nil {
+		return nil, err
+	}
 	return r.GetPipelineByID(ctx, id)
 }
 
@@ -230,6 +234,7 @@ func pipelineFromDB(row dbstore.Pipeline) *domain.Pipeline {
 		CreatedBy:        row.CreatedBy,
 		CreatedAt:        createdAt,
 		UpdatedAt:        updatedAt,
+		RowVersion:       row.RowVersion,
 	}
 }
 
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/db/mapper"
@@ -11,12 +13,13 @@ import (
 
 // PrincipalRepo implements domain.PrincipalRepository using SQLite.
 type PrincipalRepo struct {
-	q *dbstore.Queries
+	q  *dbstore.Queries
+	db *sql.DB
 }
 
 // NewPrincipalRepo creates a new PrincipalRepo.
 func NewPrincipalRepo(db *sql.DB) *PrincipalRepo {
-	return &PrincipalRepo{q: dbstore.New(db)}
+	return &PrincipalRepo{q: dbstore.New(db), db: db}
 }
 
 // Create inserts a new principal into the database.
@@ -77,17 +80,37 @@ func (r *PrincipalRepo) GetByExternalID(ctx context.Context, issuer, externalID
 	return mapper.PrincipalFromDB(row), nil
 }
 
-// List returns a paginated list of principals.
+// List returns a paginated list of principals ordered by page.Sort, by ID
+// when it is empty.
 func (r *PrincipalRepo) List(ctx context.Context, page domain.PageRequest) ([]domain.Principal, int64, error) {
 	total, err := r.q.CountPrincipals(ctx)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	rows, err := r.q.ListPrincipalsPaginated(ctx, dbstore.ListPrincipalsPaginatedParams{
-		Limit:  int64(page.Limit()),
-		Offset: int64(page.Offset()),
-	})
+	var rows []dbstore.Principal
+	switch page.Sort {
+	case domain.SortByName:
+		rows, err = r.q.ListPrincipalsPaginatedByName(ctx, dbstore.ListPrincipalsPaginatedByNameParams{
+			Limit:  int64(page.Limit()),
+			Offset: int64(page.Offset()),
+		})
+	case domain.SortByCreatedAt:
+		rows, err = r.q.ListPrincipalsPaginatedByCreatedAt(ctx, dbstore.ListPrincipalsPaginatedByCreatedAtParams{
+			Limit:  int64(page.Limit()),
+			Offset: int64(page.Offset()),
+		})
+	case domain.SortByUpdatedAt:
+		rows, err = r.q.ListPrincipalsPaginatedByUpdatedAt(ctx, dbstore.ListPrincipalsPaginatedByUpdatedAtParams{
+			Limit:  int64(page.Limit()),
+			Offset: int64(page.Offset()),
+		})
+	default:
+		rows, err = r.q.ListPrincipalsPaginated(ctx, dbstore.ListPrincipalsPaginatedParams{
+			Limit:  int64(page.Limit()),
+			Offset: int64(page.Offset()),
+		})
+	}
 	if err != nil {
 		return nil, 0, err
 	}
@@ -116,3 +139,84 @@ func (r *PrincipalRepo) BindExternalID(ctx context.Context, id string, externalI
 		ID:             id,
 	})
 }
+
+// Import creates the principals of rows that do not exist yet and adds each
+// row's principal to its groups, all in a single transaction. Existing
+// principals are skipped, apart from joining groups they are not yet in. A
+// group that does not exist fails the whole import.
+func (r *PrincipalRepo) Import(ctx context.Context, rows []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin import tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := r.q.WithTx(tx)
+	groupIDs := make(map[string]string)
+	results := make([]domain.PrincipalImportResult, len(rows))
+	for i, row := range rows {
+		res := domain.PrincipalImportResult{Name: row.Name, Status: domain.PrincipalImportSkipped}
+		p, err := qtx.GetPrincipalByName(ctx, row.Name)
+		switch {
+		case err == nil:
+		case errors.Is(err, sql.ErrNoRows):
+			p, err = qtx.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{
+				ID:      newID(),
+				Name:    row.Name,
+				Type:    row.Type,
+				IsAdmin: boolToInt(row.IsAdmin),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("create principal %q: %w", row.Name, mapDBError(err))
+			}
+			res.Status = domain.PrincipalImportCreated
+		default:
+			return nil, fmt.Errorf("look up principal %q: %w", row.Name, err)
+		}
+		res.PrincipalID = p.ID
+
+		memberships, err := qtx.ListMembershipsForMember(ctx, dbstore.ListMembershipsForMemberParams{
+			MemberType: "user", MemberID: p.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list groups of %q: %w", row.Name, err)
+		}
+		member := make(map[string]bool, len(memberships))
+		for _, m := range memberships {
+			member[m.GroupID] = true
+		}
+		for _, groupName := range row.Groups {
+			groupID, ok := groupIDs[groupName]
+			if !ok {
+				g, err := qtx.GetGroupByName(ctx, groupName)
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, domain.ErrValidation("row %d: group %q does not exist", i+1, groupName)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("look up group %q: %w", groupName, err)
+				}
+				groupID = g.ID
+				groupIDs[groupName] = groupID
+			}
+			if member[groupID] {
+				continue
+			}
+			if err := qtx.AddGroupMember(ctx, dbstore.AddGroupMemberParams{
+				GroupID: groupID, MemberType: "user", MemberID: p.ID,
+			}); err != nil {
+				return nil, fmt.Errorf("add %q to group %q: %w", row.Name, groupName, err)
+			}
+			if err := qtx.TouchGroup(ctx, groupID); err != nil {
+				return nil, fmt.Errorf("touch group %q: %w", groupName, err)
+			}
+			member[groupID] = true
+			res.GroupsAdded = append(res.GroupsAdded, groupName)
+		}
+		results[i] = res
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit import tx: %w", err)
+	}
+	return results, nil
+}
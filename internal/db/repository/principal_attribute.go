@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	dbstore "duck-demo/internal/db/dbstore"
+	"duck-demo/internal/domain"
+)
+
+var _ domain.PrincipalAttributeRepository = (*PrincipalAttributeRepo)(nil)
+
+// PrincipalAttributeRepo implements domain.PrincipalAttributeRepository using SQLite.
+type PrincipalAttributeRepo struct {
+	q  *dbstore.Queries
+	db *sql.DB
+}
+
+// NewPrincipalAttributeRepo creates a new PrincipalAttributeRepo.
+func NewPrincipalAttributeRepo(db *sql.DB) *PrincipalAttributeRepo {
+	return &PrincipalAttributeRepo{q: dbstore.New(db), db: db}
+}
+
+// Get returns the attributes of a principal. A principal without attributes
+// yields an empty map.
+func (r *PrincipalAttributeRepo) Get(ctx context.Context, principalID string) (map[string]string, error) {
+	rows, err := r.q.ListPrincipalAttributes(ctx, principalID)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make(map[string]string, len(rows))
+	for _, row := range rows {
+		attrs[row.Key] = row.Value
+	}
+	return attrs, nil
+}
+
+// GetVersioned returns the attributes of a principal together with their
+// version, read in one transaction so the two agree.
+func (r *PrincipalAttributeRepo) GetVersioned(ctx context.Context, principalID string) (map[string]string, int64, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, 0, fmt.Errorf("begin get-attributes tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := r.q.WithTx(tx)
+	version, err := qtx.GetPrincipalAttributesVersion(ctx, principalID)
+	if err != nil {
+		return nil, 0, mapDBError(err)
+	}
+	rows, err := qtx.ListPrincipalAttributes(ctx, principalID)
+	if err != nil {
+		return nil, 0, err
+	}
+	attrs := make(map[string]string, len(rows))
+	for _, row := range rows {
+		attrs[row.Key] = row.Value
+	}
+	return attrs, version, nil
+}
+
+// Replace overwrites all attributes of a principal in a single transaction
+// and returns their new version. A non-nil expectedRowVersion makes the
+// replacement conditional on the current version.
+func (r *PrincipalAttributeRepo) Replace(ctx context.Context, principalID string, attrs map[string]string, expectedRowVersion *int64) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin replace-attributes tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := r.q.WithTx(tx)
+	version, err := qtx.BumpPrincipalAttributesVersion(ctx, dbstore.BumpPrincipalAttributesVersionParams{
+		ID:                 principalID,
+		ExpectedRowVersion: rowVersionArg(expectedRowVersion),
+	})
+	if errors.Is(err, sql.ErrNoRows) && expectedRowVersion != nil {
+		if _, lookupErr := qtx.GetPrincipalAttributesVersion(ctx, principalID); lookupErr == nil {
+			return 0, staleRowVersion("attributes of principal", principalID)
+		}
+	}
+	if err != nil {
+		return 0, mapDBError(err)
+	}
+	if err := qtx.DeletePrincipalAttributes(ctx, principalID); err != nil {
+		return 0, fmt.Errorf("delete attributes: %w", err)
+	}
+	for k, v := range attrs {
+		if err := qtx.InsertPrincipalAttribute(ctx, dbstore.InsertPrincipalAttributeParams{
+			PrincipalID: principalID, Key: k, Value: v,
+		}); err != nil {
+			return 0, mapDBError(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	dbstore "duck-demo/internal/db/dbstore"
+	"duck-demo/internal/domain"
+)
+
+// PrincipalQueryDefaultsRepo implements domain.PrincipalQueryDefaultsRepository using SQLite.
+type PrincipalQueryDefaultsRepo struct {
+	q *dbstore.Queries
+}
+
+// NewPrincipalQueryDefaultsRepo creates a new PrincipalQueryDefaultsRepo.
+func NewPrincipalQueryDefaultsRepo(db *sql.DB) *PrincipalQueryDefaultsRepo {
+	return &PrincipalQueryDefaultsRepo{q: dbstore.New(db)}
+}
+
+// Get returns the query defaults of a principal by ID.
+func (r *PrincipalQueryDefaultsRepo) Get(ctx context.Context, principalID string) (domain.PrincipalQueryDefaults, error) {
+	row, err := r.q.GetPrincipalQueryDefaults(ctx, principalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PrincipalQueryDefaults{}, nil
+	}
+	if err != nil {
+		return domain.PrincipalQueryDefaults{}, err
+	}
+	return domain.PrincipalQueryDefaults{
+		DefaultCatalog: row.DefaultCatalog,
+		DefaultSchema:  row.DefaultSchema,
+		Timezone:       row.Timezone,
+		RowVersion:     row.RowVersion,
+	}, nil
+}
+
+// GetByPrincipalName returns the query defaults of a principal by name.
+func (r *PrincipalQueryDefaultsRepo) GetByPrincipalName(ctx context.Context, principalName string) (domain.PrincipalQueryDefaults, error) {
+	row, err := r.q.GetPrincipalQueryDefaultsByName(ctx, principalName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PrincipalQueryDefaults{}, nil
+	}
+	if err != nil {
+		return domain.PrincipalQueryDefaults{}, err
+	}
+	return domain.PrincipalQueryDefaults{
+		DefaultCatalog: row.DefaultCatalog,
+		DefaultSchema:  row.DefaultSchema,
+		Timezone:       row.Timezone,
+		RowVersion:     row.RowVersion,
+	}, nil
+}
+
+// Set stores the query defaults of a principal, replacing any existing ones,
+// and returns the new row version. A non-nil expectedRowVersion makes the
+// write conditional; version 0 means the principal has no defaults yet.
+func (r *PrincipalQueryDefaultsRepo) Set(ctx context.Context, principalID string, defaults domain.PrincipalQueryDefaults, expectedRowVersion *int64) (int64, error) {
+	if expectedRowVersion == nil {
+		version, err := r.q.UpsertPrincipalQueryDefaults(ctx, dbstore.UpsertPrincipalQueryDefaultsParams{
+			PrincipalID:    principalID,
+			DefaultCatalog: defaults.DefaultCatalog,
+			DefaultSchema:  defaults.DefaultSchema,
+			Timezone:       defaults.Timezone,
+		})
+		return version, mapDBError(err)
+	}
+
+	var n int64
+	var err error
+	if *expectedRowVersion == 0 {
+		n, err = r.q.InsertPrincipalQueryDefaultsIfAbsent(ctx, dbstore.InsertPrincipalQueryDefaultsIfAbsentParams{
+			PrincipalID:    principalID,
+			DefaultCatalog: defaults.DefaultCatalog,
+			DefaultSchema:  defaults.DefaultSchema,
+			Timezone:       defaults.Timezone,
+		})
+	} else {
+		n, err = r.q.UpdatePrincipalQueryDefaultsIfVersion(ctx, dbstore.UpdatePrincipalQueryDefaultsIfVersionParams{
+			DefaultCatalog:     defaults.DefaultCatalog,
+			DefaultSchema:      defaults.DefaultSchema,
+			Timezone:           defaults.Timezone,
+			PrincipalID:        principalID,
+			ExpectedRowVersion: *expectedRowVersion,
+		})
+	}
+	if err != nil {
+		return 0, mapDBError(err)
+	}
+	if err := checkRowVersion(n, expectedRowVersion, "query defaults of principal", principalID); err != nil {
+		return 0, err
+	}
+	return *expectedRowVersion + 1, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
@@ -64,6 +65,54 @@ func TestPrincipalRepo_CRUD(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestPrincipalRepo_List_SortedPagesNeverOverlap(t *testing.T) {
+	repo := setupPrincipalRepo(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"erin", "bob", "dave", "alice", "carol"} {
+		_, err := repo.Create(ctx, &domain.Principal{Name: name, Type: "user"})
+		require.NoError(t, err)
+	}
+
+	for _, sort := range []string{"", domain.SortByName, domain.SortByCreatedAt, domain.SortByUpdatedAt} {
+		t.Run("sort="+sort, func(t *testing.T) {
+			all, _, err := repo.List(ctx, domain.PageRequest{Sort: sort})
+			require.NoError(t, err)
+			require.Len(t, all, 5)
+
+			var paged []string
+			for offset := 0; offset < 5; offset += 2 {
+				page, total, err := repo.List(ctx, domain.PageRequest{
+					MaxResults: 2, PageToken: domain.EncodePageToken(offset), Sort: sort,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, int64(5), total)
+				for _, p := range page {
+					paged = append(paged, p.ID)
+				}
+			}
+
+			want := make([]string, len(all))
+			for i, p := range all {
+				want[i] = p.ID
+			}
+			assert.Equal(t, want, paged, "pages concatenate to the unpaged order")
+
+			again, _, err := repo.List(ctx, domain.PageRequest{Sort: sort})
+			require.NoError(t, err)
+			assert.Equal(t, all, again, "repeated listings return the same order")
+		})
+	}
+
+	byName, _, err := repo.List(ctx, domain.PageRequest{Sort: domain.SortByName})
+	require.NoError(t, err)
+	names := make([]string, len(byName))
+	for i, p := range byName {
+		names[i] = p.Name
+	}
+	assert.Equal(t, []string{"alice", "bob", "carol", "dave", "erin"}, names)
+}
+
 func TestPrincipalRepo_GetByExternalID(t *testing.T) {
 	repo := setupPrincipalRepo(t)
 	ctx := context.Background()
@@ -144,6 +193,29 @@ func TestPrincipalRepo_SetAdmin(t *testing.T) {
 	assert.False(t, found.IsAdmin)
 }
 
+func TestPrincipalRepo_UpdateBumpsUpdatedAt(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	repo := NewPrincipalRepo(writeDB)
+	ctx := context.Background()
+
+	p, err := repo.Create(ctx, &domain.Principal{Name: "bob", Type: "user"})
+	require.NoError(t, err)
+	assert.Equal(t, p.CreatedAt, p.UpdatedAt)
+
+	// Backdate the row so the update is visible at second resolution.
+	_, err = writeDB.ExecContext(ctx,
+		`UPDATE principals SET created_at = '2020-01-01 00:00:00', updated_at = '2020-01-01 00:00:00' WHERE id = ?`, p.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.SetAdmin(ctx, p.ID, true))
+
+	found, err := repo.GetByID(ctx, p.ID)
+	require.NoError(t, err)
+	backdated := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, backdated, found.CreatedAt)
+	assert.True(t, found.UpdatedAt.After(backdated), "SetAdmin bumps updated_at")
+}
+
 func TestPrincipalRepo_GetByName_NotFound(t *testing.T) {
 	repo := setupPrincipalRepo(t)
 	ctx := context.Background()
@@ -185,3 +257,46 @@ func TestPrincipalRepo_GetByExternalID_NotFound(t *testing.T) {
 	var notFound *domain.NotFoundError
 	assert.ErrorAs(t, err, &notFound)
 }
+
+func TestPrincipalQueryDefaultsRepo_SetAndGet(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	principals := NewPrincipalRepo(writeDB)
+	repo := NewPrincipalQueryDefaultsRepo(writeDB)
+	ctx := context.Background()
+
+	p, err := principals.Create(ctx, &domain.Principal{Name: "alice", Type: "user"})
+	require.NoError(t, err)
+
+	// No stored defaults yields the zero value.
+	got, err := repo.Get(ctx, p.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	want := domain.PrincipalQueryDefaults{DefaultCatalog: "demo", DefaultSchema: "analytics", Timezone: "Europe/Amsterdam"}
+	version, err := repo.Set(ctx, p.ID, want, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+	want.RowVersion = version
+	got, err = repo.GetByPrincipalName(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// Setting again replaces every field.
+	version, err = repo.Set(ctx, p.ID, domain.PrincipalQueryDefaults{Timezone: "UTC"}, &version)
+	require.NoError(t, err)
+	got, err = repo.Get(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PrincipalQueryDefaults{Timezone: "UTC", RowVersion: 2}, got)
+
+	// A write based on an earlier version is rejected.
+	stale := int64(1)
+	_, err = repo.Set(ctx, p.ID, want, &stale)
+	var precondition *domain.PreconditionFailedError
+	require.ErrorAs(t, err, &precondition)
+
+	// Deleting the principal removes its defaults.
+	require.NoError(t, principals.Delete(ctx, p.ID))
+	got, err = repo.GetByPrincipalName(ctx, "alice")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+}
@@ -3,81 +3,61 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/db/mapper"
 	"duck-demo/internal/domain"
 )
 
+// queryHistoryPurgeBatchSize bounds how many entries one DELETE removes, so
+// a large purge never holds the SQLite write lock for long.
+const queryHistoryPurgeBatchSize = 1000
+
 // QueryHistoryRepo implements domain.QueryHistoryRepository using SQLite.
 type QueryHistoryRepo struct {
 	q  *dbstore.Queries
 	db *sql.DB
+	wq *dbstore.Queries
+
+	purgeBatchSize int64
 }
 
-// NewQueryHistoryRepo creates a new QueryHistoryRepo.
-func NewQueryHistoryRepo(db *sql.DB) *QueryHistoryRepo {
-	return &QueryHistoryRepo{q: dbstore.New(db), db: db}
+// NewQueryHistoryRepo creates a new QueryHistoryRepo. Lists read from db,
+// which may lag the primary; purges go to writeDB.
+func NewQueryHistoryRepo(db, writeDB *sql.DB) *QueryHistoryRepo {
+	return &QueryHistoryRepo{q: dbstore.New(db), db: db, wq: dbstore.New(writeDB), purgeBatchSize: queryHistoryPurgeBatchSize}
 }
 
 // List returns a filtered, paginated list of query history entries.
 func (r *QueryHistoryRepo) List(ctx context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error) {
-	var principalFilter interface{}
-	var principalName string
-	if filter.PrincipalName != nil {
-		principalFilter = *filter.PrincipalName
-		principalName = *filter.PrincipalName
-	}
-
-	var statusFilter interface{}
-	var status string
-	if filter.Status != nil {
-		statusFilter = *filter.Status
-		status = *filter.Status
-	}
-
-	var fromFilter interface{}
-	var fromStr string
+	var fromTime, toTime interface{}
 	if filter.From != nil {
-		fromFilter = filter.From.Format("2006-01-02 15:04:05")
-		fromStr = filter.From.Format("2006-01-02 15:04:05")
+		fromTime = filter.From.Format("2006-01-02 15:04:05")
 	}
-
-	var toFilter interface{}
-	var toStr string
 	if filter.To != nil {
-		toFilter = filter.To.Format("2006-01-02 15:04:05")
-		toStr = filter.To.Format("2006-01-02 15:04:05")
+		toTime = filter.To.Format("2006-01-02 15:04:05")
 	}
 
-	limit := int64(filter.Page.Limit())
-	offset := int64(filter.Page.Offset())
-
 	total, err := r.q.CountQueryHistory(ctx, dbstore.CountQueryHistoryParams{
-		Column1:       principalFilter,
-		PrincipalName: principalName,
-		Column3:       statusFilter,
-		Status:        status,
-		Column5:       fromFilter,
-		CreatedAt:     fromStr,
-		Column7:       toFilter,
-		CreatedAt_2:   toStr,
+		PrincipalName: mapper.InterfaceFromPtr(filter.PrincipalName),
+		Status:        mapper.InterfaceFromPtr(filter.Status),
+		FromTime:      fromTime,
+		ToTime:        toTime,
+		TableName:     mapper.InterfaceFromPtr(filter.TableName),
 	})
 	if err != nil {
 		return nil, 0, err
 	}
 
 	rows, err := r.q.ListQueryHistory(ctx, dbstore.ListQueryHistoryParams{
-		Column1:       principalFilter,
-		PrincipalName: principalName,
-		Column3:       statusFilter,
-		Status:        status,
-		Column5:       fromFilter,
-		CreatedAt:     fromStr,
-		Column7:       toFilter,
-		CreatedAt_2:   toStr,
-		Limit:         limit,
-		Offset:        offset,
+		PrincipalName: mapper.InterfaceFromPtr(filter.PrincipalName),
+		Status:        mapper.InterfaceFromPtr(filter.Status),
+		FromTime:      fromTime,
+		ToTime:        toTime,
+		TableName:     mapper.InterfaceFromPtr(filter.TableName),
+		Limit:         int64(filter.Page.Limit()),
+		Offset:        int64(filter.Page.Offset()),
 	})
 	if err != nil {
 		return nil, 0, err
@@ -90,3 +70,23 @@ func (r *QueryHistoryRepo) List(ctx context.Context, filter domain.QueryHistoryF
 
 	return entries, total, nil
 }
+
+// PurgeOlderThan deletes query history entries recorded before the cutoff, in
+// bounded batches, and returns how many were deleted.
+// Each batch is its own statement, so other writers get the lock in between.
+func (r *QueryHistoryRepo) PurgeOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for {
+		n, err := r.wq.PurgeQueryHistoryBatch(ctx, dbstore.PurgeQueryHistoryBatchParams{
+			Before:    before.UTC().Format("2006-01-02 15:04:05"),
+			BatchSize: r.purgeBatchSize,
+		})
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+		if n < r.purgeBatchSize {
+			return deleted, nil
+		}
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
@@ -16,7 +17,7 @@ import (
 func setupQueryHistoryRepo(t *testing.T) (*QueryHistoryRepo, *AuditRepo) {
 	t.Helper()
 	writeDB, _ := internaldb.OpenTestSQLite(t)
-	return NewQueryHistoryRepo(writeDB), NewAuditRepo(writeDB)
+	return NewQueryHistoryRepo(writeDB, writeDB), NewAuditRepo(writeDB)
 }
 
 func qhPtrStr(s string) *string { return &s }
@@ -120,6 +121,45 @@ func TestQueryHistoryRepo_FilterByStatus(t *testing.T) {
 	assert.Equal(t, "ALLOWED", entries[0].Status)
 }
 
+func TestQueryHistoryRepo_FilterByTable(t *testing.T) {
+	qhRepo, auditRepo := setupQueryHistoryRepo(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		principal string
+		tables    []string
+	}{
+		{"alice", []string{"main.analytics.orders"}},
+		{"bob", []string{"orders", "customers"}},
+		{"carol", []string{"main.analytics.orders_archive"}},
+	} {
+		require.NoError(t, auditRepo.Insert(ctx, &domain.AuditEntry{
+			ID:             uuid.New().String(),
+			PrincipalName:  tc.principal,
+			Action:         "QUERY",
+			TablesAccessed: tc.tables,
+			Status:         "ALLOWED",
+		}))
+	}
+
+	entries, total, err := qhRepo.List(ctx, domain.QueryHistoryFilter{
+		TableName: qhPtrStr("Orders"),
+		Page:      domain.PageRequest{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	names := []string{entries[0].PrincipalName, entries[1].PrincipalName}
+	assert.ElementsMatch(t, []string{"alice", "bob"}, names)
+
+	entries, total, err = qhRepo.List(ctx, domain.QueryHistoryFilter{
+		TableName: qhPtrStr("main.analytics.orders"),
+		Page:      domain.PageRequest{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "alice", entries[0].PrincipalName)
+}
+
 func TestQueryHistoryRepo_OnlyQUERYAction(t *testing.T) {
 	qhRepo, auditRepo := setupQueryHistoryRepo(t)
 	ctx := context.Background()
@@ -162,3 +202,52 @@ func TestQueryHistoryRepo_EmptyList(t *testing.T) {
 	assert.Equal(t, int64(0), total)
 	assert.Empty(t, entries)
 }
+
+func TestQueryHistoryRepo_PurgeOlderThan(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	qhRepo, auditRepo := NewQueryHistoryRepo(writeDB, writeDB), NewAuditRepo(writeDB)
+	qhRepo.purgeBatchSize = 2 // make the purge take several batches
+	ctx := context.Background()
+
+	insert := func(action string, age time.Duration) string {
+		require.NoError(t, auditRepo.Insert(ctx, &domain.AuditEntry{
+			ID:            uuid.New().String(),
+			PrincipalName: "alice",
+			Action:        action,
+			OriginalSQL:   qhPtrStr("SELECT 1"),
+			Status:        "ALLOWED",
+		}))
+		// Insert assigns its own ID, so find the row it just added.
+		var id string
+		require.NoError(t, writeDB.QueryRowContext(ctx,
+			`SELECT id FROM audit_log ORDER BY rowid DESC LIMIT 1`).Scan(&id))
+		_, err := writeDB.ExecContext(ctx, `UPDATE audit_log SET created_at = ? WHERE id = ?`,
+			time.Now().UTC().Add(-age).Format("2006-01-02 15:04:05"), id)
+		require.NoError(t, err)
+		return id
+	}
+
+	for range 5 {
+		insert("QUERY", 100*24*time.Hour)
+	}
+	recent := insert("QUERY", 10*24*time.Hour)
+	oldGrant := insert("GRANT", 100*24*time.Hour)
+
+	deleted, err := qhRepo.PurgeOlderThan(ctx, time.Now().AddDate(0, 0, -90))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), deleted)
+
+	entries, total, err := qhRepo.List(ctx, domain.QueryHistoryFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, recent, entries[0].ID)
+
+	var n int
+	require.NoError(t, writeDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log WHERE id = ?`, oldGrant).Scan(&n))
+	assert.Equal(t, 1, n, "audit entries other than queries are not query history")
+
+	deleted, err = qhRepo.PurgeOlderThan(ctx, time.Now().AddDate(0, 0, -90))
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
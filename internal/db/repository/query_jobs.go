@@ -174,6 +174,24 @@ func (r *QueryJobRepo) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// DeleteCompletedBefore removes finished jobs that completed before cutoff.
+// completed_at is written by CURRENT_TIMESTAMP, so cutoff is compared in the
+// same UTC text format.
+func (r *QueryJobRepo) DeleteCompletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM query_jobs
+		WHERE status IN (?, ?, ?) AND completed_at IS NOT NULL AND completed_at < ?
+	`, string(domain.QueryJobStatusSucceeded), string(domain.QueryJobStatusFailed), string(domain.QueryJobStatusCanceled), cutoff.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, mapDBError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+	return rows, nil
+}
+
 func (r *QueryJobRepo) getOne(ctx context.Context, stmt string, args ...interface{}) (*domain.QueryJob, error) {
 	var (
 		job                                                  domain.QueryJob
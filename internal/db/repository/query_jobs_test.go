@@ -56,3 +56,41 @@ func TestQueryJobRepo_CRUDLifecycle(t *testing.T) {
 	_, err = repo.GetByID(context.Background(), created.ID)
 	require.Error(t, err)
 }
+
+func TestQueryJobRepo_DeleteCompletedBefore(t *testing.T) {
+	t.Parallel()
+
+	writeDB, _ := db.OpenTestSQLite(t)
+	repo := NewQueryJobRepo(writeDB)
+	ctx := context.Background()
+
+	create := func(requestID string) string {
+		job, err := repo.Create(ctx, &domain.QueryJob{
+			PrincipalName: "alice",
+			RequestID:     requestID,
+			SQLText:       "SELECT 1",
+			Status:        domain.QueryJobStatusQueued,
+		})
+		require.NoError(t, err)
+		return job.ID
+	}
+	succeeded := create("req-succeeded")
+	require.NoError(t, repo.MarkSucceeded(ctx, succeeded, []string{"id"}, [][]interface{}{{1}}, 1))
+	canceled := create("req-canceled")
+	require.NoError(t, repo.MarkCanceled(ctx, canceled))
+	running := create("req-running")
+	require.NoError(t, repo.MarkRunning(ctx, running, 1))
+
+	n, err := repo.DeleteCompletedBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, n, "recently completed jobs are kept")
+
+	n, err = repo.DeleteCompletedBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	_, err = repo.GetByID(ctx, succeeded)
+	require.Error(t, err)
+	_, err = repo.GetByID(ctx, running)
+	require.NoError(t, err, "unfinished jobs are never removed")
+}
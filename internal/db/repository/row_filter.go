@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/db/mapper"
@@ -26,8 +27,12 @@ func (r *RowFilterRepo) Create(ctx context.Context, f *domain.RowFilter) (*domai
 		TableID:     f.TableID,
 		FilterSql:   f.FilterSQL,
 		Description: sql.NullString{String: f.Description, Valid: f.Description != ""},
+		TagID:       mapper.NullStrFromPtr(f.TagID),
 	})
 	if err != nil {
+		if f.TagID != nil && strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+			return nil, domain.ErrNotFound("tag %s not found", *f.TagID)
+		}
 		return nil, mapDBError(err)
 	}
 	return mapper.RowFilterFromDB(row), nil
@@ -52,6 +57,26 @@ func (r *RowFilterRepo) GetForTable(ctx context.Context, tableID string, page do
 	return mapper.RowFiltersFromDB(rows), total, nil
 }
 
+// GetForTag returns a paginated list of the tag-based row filters for a tag.
+func (r *RowFilterRepo) GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.RowFilter, int64, error) {
+	tag := sql.NullString{String: tagID, Valid: true}
+	total, err := r.q.CountRowFiltersForTag(ctx, tag)
+	if err != nil {
+		return nil, 0, mapDBError(err)
+	}
+
+	rows, err := r.q.ListRowFiltersForTagPaginated(ctx, dbstore.ListRowFiltersForTagPaginatedParams{
+		TagID:  tag,
+		Limit:  int64(page.Limit()),
+		Offset: int64(page.Offset()),
+	})
+	if err != nil {
+		return nil, 0, mapDBError(err)
+	}
+
+	return mapper.RowFiltersFromDB(rows), total, nil
+}
+
 // Delete removes a row filter by ID. Returns NotFoundError if the filter does not exist.
 func (r *RowFilterRepo) Delete(ctx context.Context, id string) error {
 	result, err := r.q.DeleteRowFilter(ctx, id)
@@ -109,7 +134,9 @@ func (r *RowFilterRepo) ListBindings(ctx context.Context, filterID string) ([]do
 	return mapper.RowFilterBindingsFromDB(rows), nil
 }
 
-// GetForTableAndPrincipal returns row filters bound to a specific table and principal.
+// GetForTableAndPrincipal returns row filters bound to a specific table and
+// principal: the table's own filters, then tag-based filters for each tag
+// assigned to the table, oldest filter first.
 func (r *RowFilterRepo) GetForTableAndPrincipal(ctx context.Context, tableID, principalID string, principalType string) ([]domain.RowFilter, error) {
 	rows, err := r.q.GetRowFiltersForTableAndPrincipal(ctx, dbstore.GetRowFiltersForTableAndPrincipalParams{
 		TableID:       tableID,
@@ -119,5 +146,13 @@ func (r *RowFilterRepo) GetForTableAndPrincipal(ctx context.Context, tableID, pr
 	if err != nil {
 		return nil, mapDBError(err)
 	}
-	return mapper.RowFiltersFromDB(rows), nil
+	tagRows, err := r.q.GetTagRowFiltersForTableAndPrincipal(ctx, dbstore.GetTagRowFiltersForTableAndPrincipalParams{
+		SecurableID:   tableID,
+		PrincipalID:   principalID,
+		PrincipalType: principalType,
+	})
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	return mapper.RowFiltersFromDB(append(rows, tagRows...)), nil
 }
@@ -131,6 +131,65 @@ func TestRowFilterRepo_GetForTableAndPrincipal(t *testing.T) {
 	assert.Equal(t, `"Pclass" = 1`, filters[0].FilterSQL)
 }
 
+func TestRowFilterRepo_TableInheritsTagBasedFilter(t *testing.T) {
+	writeDB, _ := internaldb.OpenTestSQLite(t)
+	repo := NewRowFilterRepo(writeDB)
+	tags := NewTagRepo(writeDB)
+	ctx := context.Background()
+
+	tag, err := tags.CreateTag(ctx, &domain.Tag{Key: "region", Value: tagPtrStr("eu"), CreatedBy: "admin"})
+	require.NoError(t, err)
+	direct, err := repo.Create(ctx, &domain.RowFilter{TableID: "t-1", FilterSQL: `"Pclass" = 1`})
+	require.NoError(t, err)
+	tagged, err := repo.Create(ctx, &domain.RowFilter{TagID: &tag.ID, FilterSQL: `region = 'EU'`})
+	require.NoError(t, err)
+	require.NotNil(t, tagged.TagID)
+	assert.Empty(t, tagged.TableID)
+	for _, id := range []string{direct.ID, tagged.ID} {
+		require.NoError(t, repo.Bind(ctx, &domain.RowFilterBinding{RowFilterID: id, PrincipalID: "p-1", PrincipalType: "user"}))
+	}
+
+	filters, err := repo.GetForTableAndPrincipal(ctx, "t-2", "p-1", "user")
+	require.NoError(t, err)
+	assert.Empty(t, filters, "t-2 has no filter and no tag yet")
+
+	// Tagging t-2 is all it takes for it to pick up the tag's filter.
+	_, err = tags.AssignTag(ctx, &domain.TagAssignment{TagID: tag.ID, SecurableType: "table", SecurableID: "t-2", AssignedBy: "admin"})
+	require.NoError(t, err)
+	filters, err = repo.GetForTableAndPrincipal(ctx, "t-2", "p-1", "user")
+	require.NoError(t, err)
+	require.Len(t, filters, 1)
+	assert.Equal(t, tagged.ID, filters[0].ID)
+
+	// A tagged table with its own filter gets both, its own first.
+	_, err = tags.AssignTag(ctx, &domain.TagAssignment{TagID: tag.ID, SecurableType: "table", SecurableID: "t-1", AssignedBy: "admin"})
+	require.NoError(t, err)
+	filters, err = repo.GetForTableAndPrincipal(ctx, "t-1", "p-1", "user")
+	require.NoError(t, err)
+	require.Len(t, filters, 2)
+	assert.Equal(t, direct.ID, filters[0].ID)
+	assert.Equal(t, tagged.ID, filters[1].ID)
+
+	// A column tag does not put the filter on the table.
+	col := "region"
+	_, err = tags.AssignTag(ctx, &domain.TagAssignment{TagID: tag.ID, SecurableType: "column", SecurableID: "t-3", ColumnName: &col, AssignedBy: "admin"})
+	require.NoError(t, err)
+	filters, err = repo.GetForTableAndPrincipal(ctx, "t-3", "p-1", "user")
+	require.NoError(t, err)
+	assert.Empty(t, filters)
+
+	byTag, total, err := repo.GetForTag(ctx, tag.ID, domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, byTag, 1)
+	assert.Equal(t, tagged.ID, byTag[0].ID)
+
+	unknown := "no-such-tag"
+	_, err = repo.Create(ctx, &domain.RowFilter{TagID: &unknown, FilterSQL: "true"})
+	var notFound *domain.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
 func TestRowFilterRepo_GetForTableAndPrincipal_Empty(t *testing.T) {
 	repo := setupRowFilterRepo(t)
 	ctx := context.Background()
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"duck-demo/internal/domain"
 )
@@ -15,21 +17,38 @@ import (
 //   - controlDB: the control plane DB (catalog_metadata, column_metadata, tags, tag_assignments)
 //
 // When metaDB == controlDB (legacy single-DB mode), all queries run against one DB.
+//
+// Names, comments and properties are searched in the live tables. Tag matches
+// on schemas, tables and columns need the object names next to the tags, so
+// when the metastore is a separate database they are resolved through
+// search_index, a copy of the catalog's names in the control plane that
+// Reindex rebuilds.
 type SearchRepo struct {
 	metaDB    *sql.DB
 	controlDB *sql.DB
+	writeDB   *sql.DB // control plane write pool, for Reindex
+	catalog   string  // catalog whose rows in search_index this repo reads and writes
 }
 
 // NewSearchRepo creates a new SearchRepo.
 // Both metaDB and controlDB are required. In single-DB mode, pass the same *sql.DB for both.
+// Reindex writes through controlDB, so it must not be a read-only pool.
 func NewSearchRepo(metaDB, controlDB *sql.DB) *SearchRepo {
-	return &SearchRepo{metaDB: metaDB, controlDB: controlDB}
+	return &SearchRepo{metaDB: metaDB, controlDB: controlDB, writeDB: controlDB}
+}
+
+// NewCatalogSearchRepo creates a SearchRepo for a named catalog whose search
+// index is written through writeDB, the control plane write pool.
+func NewCatalogSearchRepo(catalogName string, metaDB, controlDB, writeDB *sql.DB) *SearchRepo {
+	return &SearchRepo{metaDB: metaDB, controlDB: controlDB, writeDB: writeDB, catalog: catalogName}
 }
 
-// Search performs a full-text search across schemas, tables, columns, and macros.
-// Name-based searches query the catalog metastore (ducklake_* tables).
-// Comment/tag/property searches query the control plane (catalog_metadata, tags).
-// Results from both sources are merged and deduplicated.
+// Search performs a full-text search across schemas, tables, columns, macros,
+// views, and models. Name-based searches query the catalog metastore
+// (ducklake_* tables). Comment/tag/property searches query the control plane
+// (catalog_metadata, tags, views, models). Results from both sources are
+// merged and deduplicated, and comment matches carry a snippet of the comment
+// around the matched text.
 func (r *SearchRepo) Search(ctx context.Context, query string, objectType *string, maxResults int, offset int) ([]domain.SearchResult, int64, error) {
 	return r.searchMultiDB(ctx, query, objectType, maxResults, offset)
 }
@@ -52,6 +71,15 @@ func (r *SearchRepo) searchMultiDB(ctx context.Context, query string, objectType
 		return nil, 0, fmt.Errorf("search governance from control plane: %w", err)
 	}
 
+	// Phase 3: Views, whose schema names must be resolved against the metastore
+	if objectType == nil || *objectType == "view" {
+		viewResults, err := r.searchViews(ctx, likePattern)
+		if err != nil {
+			return nil, 0, fmt.Errorf("search views: %w", err)
+		}
+		govResults = append(govResults, viewResults...)
+	}
+
 	// Merge and deduplicate (name match takes priority)
 	all := mergeSearchResults(nameResults, govResults)
 	total := int64(len(all))
@@ -65,7 +93,11 @@ func (r *SearchRepo) searchMultiDB(ctx context.Context, query string, objectType
 		end = len(all)
 	}
 
-	return all[offset:end], total, nil
+	page := all[offset:end]
+	for i := range page {
+		page[i].Snippet = searchSnippet(page[i], query)
+	}
+	return page, total, nil
 }
 
 // searchNamesMeta searches ducklake_* tables for name matches.
@@ -207,6 +239,18 @@ func (r *SearchRepo) searchGovernanceControl(ctx context.Context, likePattern st
 		args = append(args, likePattern)
 	}
 
+	// Models by name or description. Models are scoped by project rather
+	// than schema, so the project name is reported as schema_name.
+	if objectType == nil || *objectType == "model" {
+		unions = append(unions, `
+			SELECT 'model' as type, m.name as name, m.project_name as schema_name, NULL as table_name,
+				NULLIF(m.description, '') as comment,
+				CASE WHEN LOWER(m.name) LIKE ? THEN 'name' ELSE 'comment' END as match_field
+			FROM models m
+			WHERE LOWER(m.name) LIKE ? OR LOWER(m.description) LIKE ?`)
+		args = append(args, likePattern, likePattern, likePattern)
+	}
+
 	// Tags (all types) — tags are in control plane.
 	// When both DB handles point to the same SQLite DB, enrich tag matches with
 	// object names via ducklake_* tables.
@@ -251,6 +295,26 @@ func (r *SearchRepo) searchGovernanceControl(ctx context.Context, likePattern st
 		}
 	}
 
+	if r.metaDB != r.controlDB && (objectType == nil || *objectType == "schema" || *objectType == "table" || *objectType == "column") {
+		// Tag matches through search_index. Column tags are assigned to the
+		// table's ID with the column named alongside.
+		query := `
+			SELECT si.object_type as type, si.name as name, si.schema_name as schema_name, si.table_name as table_name,
+				si.comment as comment, 'tag' as match_field
+			FROM search_index si
+			JOIN tag_assignments ta ON ta.securable_type = si.object_type AND ta.securable_id = si.securable_id
+				AND (si.object_type <> 'column' OR ta.column_name = si.name)
+			JOIN tags t ON t.id = ta.tag_id
+			WHERE si.catalog_name = ? AND si.object_type IN ('schema', 'table', 'column')
+			AND (LOWER(t.key) LIKE ? OR LOWER(COALESCE(t.value, '')) LIKE ?)`
+		args = append(args, r.catalog, likePattern, likePattern)
+		if objectType != nil {
+			query += ` AND si.object_type = ?`
+			args = append(args, *objectType)
+		}
+		unions = append(unions, query)
+	}
+
 	if len(unions) == 0 {
 		return nil, nil
 	}
@@ -258,6 +322,120 @@ func (r *SearchRepo) searchGovernanceControl(ctx context.Context, likePattern st
 	return r.execSearchQuery(ctx, r.controlDB, strings.Join(unions, " UNION ALL "), args)
 }
 
+// searchViews searches views by name or comment. Views live in the control
+// plane keyed by metastore schema_id, so schema names are resolved against
+// metaDB; views whose schema is not active in this catalog are dropped.
+func (r *SearchRepo) searchViews(ctx context.Context, likePattern string) ([]domain.SearchResult, error) {
+	rows, err := r.controlDB.QueryContext(ctx, `
+		SELECT v.schema_id, v.name, v.comment,
+			CASE WHEN LOWER(v.name) LIKE ? THEN 'name' ELSE 'comment' END as match_field
+		FROM views v
+		WHERE v.deleted_at IS NULL AND (LOWER(v.name) LIKE ? OR LOWER(COALESCE(v.comment, '')) LIKE ?)`,
+		likePattern, likePattern, likePattern)
+	if err != nil {
+		return nil, fmt.Errorf("search query: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	type viewMatch struct {
+		schemaID string
+		result   domain.SearchResult
+	}
+	var matches []viewMatch
+	for rows.Next() {
+		var m viewMatch
+		var comment sql.NullString
+		if err := rows.Scan(&m.schemaID, &m.result.Name, &comment, &m.result.MatchField); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		m.result.Type = "view"
+		if comment.Valid {
+			m.result.Comment = &comment.String
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search results: %w", err)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	schemaRows, err := r.metaDB.QueryContext(ctx,
+		`SELECT CAST(schema_id AS TEXT), schema_name FROM ducklake_schema WHERE end_snapshot IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+	defer schemaRows.Close() //nolint:errcheck
+
+	schemaNames := make(map[string]string)
+	for schemaRows.Next() {
+		var id, name string
+		if err := schemaRows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("scan schema: %w", err)
+		}
+		schemaNames[id] = name
+	}
+	if err := schemaRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schemas: %w", err)
+	}
+
+	results := make([]domain.SearchResult, 0, len(matches))
+	for _, m := range matches {
+		name, ok := schemaNames[m.schemaID]
+		if !ok {
+			continue
+		}
+		m.result.SchemaName = &name
+		results = append(results, m.result)
+	}
+	return results, nil
+}
+
+// snippetContext is the number of characters of comment kept on each side
+// of the matched text in a search snippet.
+const snippetContext = 40
+
+// searchSnippet returns an excerpt of a comment match with the matched text
+// wrapped in ** markers, trimmed to snippetContext characters either side.
+// It returns nil for results that did not match on their comment.
+func searchSnippet(r domain.SearchResult, query string) *string {
+	if r.MatchField != "comment" || r.Comment == nil || query == "" {
+		return nil
+	}
+	comment := *r.Comment
+	loc := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query)).FindStringIndex(comment)
+	if loc == nil {
+		return nil
+	}
+
+	start := loc[0]
+	for n := 0; n < snippetContext && start > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(comment[:start])
+		start -= size
+	}
+	end := loc[1]
+	for n := 0; n < snippetContext && end < len(comment); n++ {
+		_, size := utf8.DecodeRuneInString(comment[end:])
+		end += size
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(comment[start:loc[0]])
+	b.WriteString("**")
+	b.WriteString(comment[loc[0]:loc[1]])
+	b.WriteString("**")
+	b.WriteString(comment[loc[1]:end])
+	if end < len(comment) {
+		b.WriteString("…")
+	}
+	snippet := b.String()
+	return &snippet
+}
+
 // execSearchQuery runs a search SQL query and scans results.
 func (r *SearchRepo) execSearchQuery(ctx context.Context, db *sql.DB, query string, args []interface{}) ([]domain.SearchResult, error) {
 	rows, err := db.QueryContext(ctx, query, args...)
@@ -12,9 +12,11 @@ import (
 
 // SearchRepoFactory creates SearchRepo instances per catalog by opening
 // read-only connections to each catalog's metastore. The control plane DB
-// is shared across all catalogs.
+// is shared across all catalogs; writeDB is its write pool, used to rebuild
+// the search index.
 type SearchRepoFactory struct {
 	controlDB      *sql.DB
+	writeDB        *sql.DB
 	catalogRegRepo domain.CatalogRegistrationRepository
 
 	mu    sync.RWMutex
@@ -27,9 +29,10 @@ type searchEntry struct {
 }
 
 // NewSearchRepoFactory creates a new SearchRepoFactory.
-func NewSearchRepoFactory(controlDB *sql.DB, catalogRegRepo domain.CatalogRegistrationRepository) *SearchRepoFactory {
+func NewSearchRepoFactory(controlDB, writeDB *sql.DB, catalogRegRepo domain.CatalogRegistrationRepository) *SearchRepoFactory {
 	return &SearchRepoFactory{
 		controlDB:      controlDB,
+		writeDB:        writeDB,
 		catalogRegRepo: catalogRegRepo,
 		cache:          make(map[string]*searchEntry),
 	}
@@ -74,7 +77,7 @@ func (f *SearchRepoFactory) ForCatalog(ctx context.Context, catalogName string)
 		return nil, fmt.Errorf("open metastore for catalog %q: %w", catalogName, err)
 	}
 
-	repo := NewSearchRepo(db, f.controlDB)
+	repo := NewCatalogSearchRepo(catalogName, db, f.controlDB, f.writeDB)
 	f.cache[catalogName] = &searchEntry{db: db, repo: repo}
 	return repo, nil
 }
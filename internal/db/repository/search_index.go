@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"duck-demo/internal/domain"
+)
+
+// searchIndexRow is one object written to search_index.
+type searchIndexRow struct {
+	objectType  string
+	securableID string
+	name        string
+	schemaName  *string
+	tableName   *string
+	comment     *string
+}
+
+// Reindex rebuilds this catalog's rows in search_index from the schemas,
+// tables and columns active in the metastore, the views in the control plane,
+// and their comments. The old rows are replaced in one transaction, so a
+// search never sees a half-built index.
+func (r *SearchRepo) Reindex(ctx context.Context) (*domain.SearchIndexStats, error) {
+	rows, err := r.metastoreIndexRows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.writeDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin reindex: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := attachComments(ctx, tx, rows); err != nil {
+		return nil, err
+	}
+	views, err := r.viewIndexRows(ctx, tx, rows)
+	if err != nil {
+		return nil, err
+	}
+	rows = append(rows, views...)
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM search_index WHERE catalog_name = ?`, r.catalog); err != nil {
+		return nil, fmt.Errorf("clear search index: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO search_index (catalog_name, object_type, securable_id, name, schema_name, table_name, comment)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, fmt.Errorf("prepare search index insert: %w", err)
+	}
+	defer stmt.Close() //nolint:errcheck
+
+	stats := &domain.SearchIndexStats{}
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, r.catalog, row.objectType, row.securableID, row.name,
+			row.schemaName, row.tableName, row.comment); err != nil {
+			return nil, fmt.Errorf("index %s %q: %w", row.objectType, row.name, err)
+		}
+		switch row.objectType {
+		case "schema":
+			stats.Schemas++
+		case "table":
+			stats.Tables++
+		case "view":
+			stats.Views++
+		case "column":
+			stats.Columns++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit reindex: %w", err)
+	}
+	return stats, nil
+}
+
+// metastoreIndexRows reads the active schemas, tables and columns from the
+// metastore.
+func (r *SearchRepo) metastoreIndexRows(ctx context.Context) ([]searchIndexRow, error) {
+	var out []searchIndexRow
+
+	schemas, err := r.metaDB.QueryContext(ctx,
+		`SELECT CAST(schema_id AS TEXT), schema_name FROM ducklake_schema WHERE end_snapshot IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+	defer schemas.Close() //nolint:errcheck
+	for schemas.Next() {
+		row := searchIndexRow{objectType: "schema"}
+		if err := schemas.Scan(&row.securableID, &row.name); err != nil {
+			return nil, fmt.Errorf("scan schema: %w", err)
+		}
+		out = append(out, row)
+	}
+	if err := schemas.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schemas: %w", err)
+	}
+
+	tables, err := r.metaDB.QueryContext(ctx, `
+		SELECT CAST(dt.table_id AS TEXT), ds.schema_name, dt.table_name
+		FROM ducklake_table dt
+		JOIN ducklake_schema ds ON dt.schema_id = ds.schema_id AND ds.end_snapshot IS NULL
+		WHERE dt.end_snapshot IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	defer tables.Close() //nolint:errcheck
+	for tables.Next() {
+		row := searchIndexRow{objectType: "table"}
+		var schemaName string
+		if err := tables.Scan(&row.securableID, &schemaName, &row.name); err != nil {
+			return nil, fmt.Errorf("scan table: %w", err)
+		}
+		row.schemaName = &schemaName
+		out = append(out, row)
+	}
+	if err := tables.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tables: %w", err)
+	}
+
+	columns, err := r.metaDB.QueryContext(ctx, `
+		SELECT CAST(dt.table_id AS TEXT), ds.schema_name, dt.table_name, dc.column_name
+		FROM ducklake_column dc
+		JOIN ducklake_table dt ON dc.table_id = dt.table_id AND dt.end_snapshot IS NULL
+		JOIN ducklake_schema ds ON dt.schema_id = ds.schema_id AND ds.end_snapshot IS NULL
+		WHERE dc.end_snapshot IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("list columns: %w", err)
+	}
+	defer columns.Close() //nolint:errcheck
+	for columns.Next() {
+		row := searchIndexRow{objectType: "column"}
+		var schemaName, tableName string
+		if err := columns.Scan(&row.securableID, &schemaName, &tableName, &row.name); err != nil {
+			return nil, fmt.Errorf("scan column: %w", err)
+		}
+		row.schemaName, row.tableName = &schemaName, &tableName
+		out = append(out, row)
+	}
+	if err := columns.Err(); err != nil {
+		return nil, fmt.Errorf("iterate columns: %w", err)
+	}
+	return out, nil
+}
+
+// attachComments fills in the comments of schema, table and column rows from
+// catalog_metadata and column_metadata.
+func attachComments(ctx context.Context, tx *sql.Tx, rows []searchIndexRow) error {
+	comments := make(map[string]string)
+	meta, err := tx.QueryContext(ctx, `
+		SELECT securable_type, securable_name, comment FROM catalog_metadata
+		WHERE securable_type IN ('schema', 'table') AND deleted_at IS NULL AND comment IS NOT NULL`)
+	if err != nil {
+		return fmt.Errorf("list comments: %w", err)
+	}
+	defer meta.Close() //nolint:errcheck
+	for meta.Next() {
+		var securableType, name, comment string
+		if err := meta.Scan(&securableType, &name, &comment); err != nil {
+			return fmt.Errorf("scan comment: %w", err)
+		}
+		comments[securableType+":"+name] = comment
+	}
+	if err := meta.Err(); err != nil {
+		return fmt.Errorf("iterate comments: %w", err)
+	}
+
+	cols, err := tx.QueryContext(ctx,
+		`SELECT table_securable_name, column_name, comment FROM column_metadata WHERE comment IS NOT NULL`)
+	if err != nil {
+		return fmt.Errorf("list column comments: %w", err)
+	}
+	defer cols.Close() //nolint:errcheck
+	for cols.Next() {
+		var table, column, comment string
+		if err := cols.Scan(&table, &column, &comment); err != nil {
+			return fmt.Errorf("scan column comment: %w", err)
+		}
+		comments["column:"+table+"."+column] = comment
+	}
+	if err := cols.Err(); err != nil {
+		return fmt.Errorf("iterate column comments: %w", err)
+	}
+
+	for i := range rows {
+		var key string
+		switch rows[i].objectType {
+		case "schema":
+			key = "schema:" + rows[i].name
+		case "table":
+			key = "table:" + *rows[i].schemaName + "." + rows[i].name
+		case "column":
+			key = "column:" + *rows[i].schemaName + "." + *rows[i].tableName + "." + rows[i].name
+		}
+		if c, ok := comments[key]; ok {
+			rows[i].comment = &c
+		}
+	}
+	return nil
+}
+
+// viewIndexRows reads the views whose schema is one of the schema rows, the
+// same views searchViews reports for this catalog.
+func (r *SearchRepo) viewIndexRows(ctx context.Context, tx *sql.Tx, rows []searchIndexRow) ([]searchIndexRow, error) {
+	schemaNames := make(map[string]string)
+	for _, row := range rows {
+		if row.objectType == "schema" {
+			schemaNames[row.securableID] = row.name
+		}
+	}
+
+	views, err := tx.QueryContext(ctx,
+		`SELECT id, CAST(schema_id AS TEXT), name, comment FROM views WHERE deleted_at IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("list views: %w", err)
+	}
+	defer views.Close() //nolint:errcheck
+
+	var out []searchIndexRow
+	for views.Next() {
+		row := searchIndexRow{objectType: "view"}
+		var schemaID string
+		var comment sql.NullString
+		if err := views.Scan(&row.securableID, &schemaID, &row.name, &comment); err != nil {
+			return nil, fmt.Errorf("scan view: %w", err)
+		}
+		schemaName, ok := schemaNames[schemaID]
+		if !ok {
+			continue
+		}
+		row.schemaName = &schemaName
+		if comment.Valid {
+			row.comment = &comment.String
+		}
+		out = append(out, row)
+	}
+	if err := views.Err(); err != nil {
+		return nil, fmt.Errorf("iterate views: %w", err)
+	}
+	return out, nil
+}
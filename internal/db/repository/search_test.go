@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -399,6 +400,107 @@ func TestSearchRepo_SearchByColumnTag(t *testing.T) {
 	assert.True(t, found, "expected column matched by tag")
 }
 
+// ---------------------------------------------------------------------------
+// Comment/description matches on tables, views, and models
+// ---------------------------------------------------------------------------
+
+func TestSearchRepo_SearchWordOnlyInTableComment(t *testing.T) {
+	repo, db := setupSearchRepo(t)
+	seedSearchData(t, db)
+	ctx := context.Background()
+
+	// "tracking" appears only in the comment on analytics.events
+	results, total, err := repo.Search(ctx, "TRACKING", nil, 100, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Len(t, results, 1)
+
+	r := results[0]
+	assert.Equal(t, "table", r.Type)
+	assert.Equal(t, "events", r.Name)
+	require.NotNil(t, r.SchemaName)
+	assert.Equal(t, "analytics", *r.SchemaName)
+	assert.Equal(t, "comment", r.MatchField)
+	require.NotNil(t, r.Snippet)
+	assert.Equal(t, "Event **tracking** table", *r.Snippet)
+}
+
+func TestSearchRepo_SearchByViewComment(t *testing.T) {
+	repo, db := setupSearchRepo(t)
+	seedSearchData(t, db)
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `INSERT INTO views (id, schema_id, name, view_definition, comment, owner) VALUES ('v1', '1', 'daily_rollup', 'SELECT 1', 'Clickstream rollup per day', 'admin')`)
+	require.NoError(t, err)
+	// View in a soft-deleted schema is not part of the catalog
+	_, err = db.ExecContext(ctx, `INSERT INTO views (id, schema_id, name, view_definition, comment, owner) VALUES ('v2', '3', 'stale_rollup', 'SELECT 1', 'Old clickstream rollup', 'admin')`)
+	require.NoError(t, err)
+
+	results, total, err := repo.Search(ctx, "clickstream", nil, 100, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Len(t, results, 1)
+	assert.Equal(t, "view", results[0].Type)
+	assert.Equal(t, "daily_rollup", results[0].Name)
+	require.NotNil(t, results[0].SchemaName)
+	assert.Equal(t, "analytics", *results[0].SchemaName)
+	assert.Equal(t, "comment", results[0].MatchField)
+	require.NotNil(t, results[0].Snippet)
+	assert.Equal(t, "**Clickstream** rollup per day", *results[0].Snippet)
+
+	viewType := "view"
+	results, _, err = repo.Search(ctx, "daily", &viewType, 100, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "name", results[0].MatchField)
+	assert.Nil(t, results[0].Snippet)
+}
+
+func TestSearchRepo_SearchByModelDescription(t *testing.T) {
+	repo, db := setupSearchRepo(t)
+	seedSearchData(t, db)
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `INSERT INTO models (id, project_name, name, sql_body, description) VALUES ('m1', 'marts', 'fct_orders', 'SELECT 1', 'One row per fulfilled order')`)
+	require.NoError(t, err)
+
+	modelType := "model"
+	results, total, err := repo.Search(ctx, "fulfilled", &modelType, 100, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, "model", results[0].Type)
+	assert.Equal(t, "fct_orders", results[0].Name)
+	require.NotNil(t, results[0].SchemaName)
+	assert.Equal(t, "marts", *results[0].SchemaName)
+	assert.Equal(t, "comment", results[0].MatchField)
+	require.NotNil(t, results[0].Snippet)
+	assert.Equal(t, "One row per **fulfilled** order", *results[0].Snippet)
+}
+
+func TestSearchSnippet(t *testing.T) {
+	comment := func(s string) *string { return &s }
+	long := strings.Repeat("a", 60) + " needle " + strings.Repeat("b", 60)
+
+	tests := []struct {
+		name   string
+		result domain.SearchResult
+		query  string
+		want   *string
+	}{
+		{"name match has no snippet", domain.SearchResult{MatchField: "name", Comment: comment("needle")}, "needle", nil},
+		{"missing comment", domain.SearchResult{MatchField: "comment"}, "needle", nil},
+		{"case insensitive", domain.SearchResult{MatchField: "comment", Comment: comment("Find the NEEDLE here")}, "needle", comment("Find the **NEEDLE** here")},
+		{"query with regexp metacharacters", domain.SearchResult{MatchField: "comment", Comment: comment("costs (usd)")}, "(usd)", comment("costs **(usd)**")},
+		{"long comment is trimmed", domain.SearchResult{MatchField: "comment", Comment: &long}, "needle",
+			comment("…" + strings.Repeat("a", 39) + " **needle** " + strings.Repeat("b", 39) + "…")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, searchSnippet(tc.result, tc.query))
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Search excludes soft-deleted ducklake objects
 // ---------------------------------------------------------------------------
@@ -427,6 +529,69 @@ func TestSearchRepo_SearchExcludesSoftDeleted(t *testing.T) {
 	assert.Empty(t, results)
 }
 
+// ---------------------------------------------------------------------------
+// Reindex
+// ---------------------------------------------------------------------------
+
+func TestSearchRepo_ReindexResolvesTagsAcrossDatabases(t *testing.T) {
+	ctx := context.Background()
+	metaDB, _ := internaldb.OpenTestSQLite(t)
+	createDuckLakeTables(t, metaDB)
+	controlDB, _ := internaldb.OpenTestSQLite(t)
+	repo := NewCatalogSearchRepo("lake", metaDB, controlDB, controlDB)
+
+	for _, stmt := range []string{
+		`INSERT INTO ducklake_schema (schema_id, schema_name, end_snapshot) VALUES (1, 'analytics', NULL)`,
+		`INSERT INTO ducklake_table (table_id, schema_id, table_name, end_snapshot) VALUES (10, 1, 'events', NULL)`,
+		`INSERT INTO ducklake_table (table_id, schema_id, table_name, end_snapshot) VALUES (12, 1, 'deleted_tbl', 99)`,
+		`INSERT INTO ducklake_column (column_id, table_id, column_name, column_type, end_snapshot) VALUES (100, 10, 'event_id', 'INTEGER', NULL)`,
+		`INSERT INTO ducklake_column (column_id, table_id, column_name, column_type, end_snapshot) VALUES (101, 10, 'user_email', 'VARCHAR', NULL)`,
+	} {
+		_, err := metaDB.ExecContext(ctx, stmt)
+		require.NoError(t, err)
+	}
+	for _, stmt := range []string{
+		`INSERT INTO catalog_metadata (securable_type, securable_name, comment, deleted_at) VALUES ('table', 'analytics.events', 'Event tracking table', NULL)`,
+		`INSERT INTO views (id, schema_id, name, view_definition, comment, owner) VALUES ('v-1', '1', 'daily_events', 'SELECT 1', NULL, 'admin')`,
+		`INSERT INTO tags (id, key, value, created_by) VALUES ('tag-2', 'classification', 'internal', 'admin')`,
+		`INSERT INTO tag_assignments (id, tag_id, securable_type, securable_id, assigned_by) VALUES ('ta-2', 'tag-2', 'table', '10', 'admin')`,
+	} {
+		_, err := controlDB.ExecContext(ctx, stmt)
+		require.NoError(t, err)
+	}
+
+	findTaggedTable := func() *domain.SearchResult {
+		results, _, err := repo.Search(ctx, "classification", nil, 100, 0)
+		require.NoError(t, err)
+		for i, r := range results {
+			if r.Type == "table" && r.Name == "events" && r.MatchField == "tag" {
+				return &results[i]
+			}
+		}
+		return nil
+	}
+	require.Nil(t, findTaggedTable(), "the table name lives in the other database until reindexed")
+
+	stats, err := repo.Reindex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.SearchIndexStats{Schemas: 1, Tables: 1, Views: 1, Columns: 2}, stats)
+
+	found := findTaggedTable()
+	require.NotNil(t, found, "expected table matched by tag after reindex")
+	require.NotNil(t, found.SchemaName)
+	assert.Equal(t, "analytics", *found.SchemaName)
+	require.NotNil(t, found.Comment)
+	assert.Equal(t, "Event tracking table", *found.Comment)
+
+	// Reindexing again replaces the rows rather than adding to them.
+	stats, err = repo.Reindex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Tables)
+	var rows int
+	require.NoError(t, controlDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM search_index WHERE catalog_name = 'lake'`).Scan(&rows))
+	assert.Equal(t, 5, rows)
+}
+
 // ---------------------------------------------------------------------------
 // mergeSearchResults (unexported, accessible from same package)
 // ---------------------------------------------------------------------------
@@ -38,6 +38,7 @@ func semanticModelFromDB(row dbstore.SemanticModel) *domain.SemanticModel {
 		CreatedBy:            row.CreatedBy,
 		CreatedAt:            parseDBTime(row.CreatedAt, "semantic_models.created_at"),
 		UpdatedAt:            parseDBTime(row.UpdatedAt, "semantic_models.updated_at"),
+		RowVersion:           row.RowVersion,
 	}
 }
 
@@ -132,17 +132,21 @@ func (r *SemanticModelRepo) Update(ctx context.Context, id string, req domain.Up
 		return nil, fmt.Errorf("marshal tags: %w", err)
 	}
 
-	err = r.q.UpdateSemanticModel(ctx, dbstore.UpdateSemanticModelParams{
+	n, err := r.q.UpdateSemanticModel(ctx, dbstore.UpdateSemanticModelParams{
 		Description:          description,
 		Owner:                owner,
 		BaseModelRef:         baseModelRef,
 		DefaultTimeDimension: defaultTimeDim,
 		Tags:                 string(tagsJSON),
 		ID:                   id,
+		ExpectedRowVersion:   rowVersionArg(req.ExpectedRowVersion),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, req.ExpectedRowVersion, "semantic model", current.Name); err != nil {
+		return nil, err
+	}
 	return r.GetByID(ctx, id)
 }
 
@@ -201,7 +201,7 @@ func (r *StorageCredentialRepo) Update(ctx context.Context, id string, req domai
 		comment = *req.Comment
 	}
 
-	err = r.q.UpdateStorageCredential(ctx, dbstore.UpdateStorageCredentialParams{
+	n, err := r.q.UpdateStorageCredential(ctx, dbstore.UpdateStorageCredentialParams{
 		KeyIDEncrypted:             keyIDEnc,
 		SecretEncrypted:            secretEnc,
 		Endpoint:                   endpoint,
@@ -215,10 +215,14 @@ func (r *StorageCredentialRepo) Update(ctx context.Context, id string, req domai
 		GcsKeyFilePath:             gcsKeyFilePath,
 		Comment:                    comment,
 		ID:                         id,
+		ExpectedRowVersion:         rowVersionArg(req.ExpectedRowVersion),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, req.ExpectedRowVersion, "storage credential", current.Name); err != nil {
+		return nil, err
+	}
 	return r.GetByID(ctx, id)
 }
 
@@ -267,5 +271,6 @@ func (r *StorageCredentialRepo) fromDB(row dbstore.StorageCredential) (*domain.S
 		Owner:             row.Owner,
 		CreatedAt:         createdAt,
 		UpdatedAt:         updatedAt,
+		RowVersion:        row.RowVersion,
 	}, nil
 }
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/db/mapper"
@@ -131,3 +133,128 @@ func (r *TagRepo) ListAssignmentsForTag(ctx context.Context, tagID string) ([]do
 	}
 	return assignments, nil
 }
+
+// AssignTagBatch assigns a tag to every target in a single transaction.
+// Targets that already carry the tag are reported as unchanged. If any target
+// fails, the transaction is rolled back and the batch is reported as not
+// applied, with the failing items' errors.
+func (r *TagRepo) AssignTagBatch(ctx context.Context, tagID string, targets []domain.TagAssignmentTarget, assignedBy string) (*domain.TagAssignmentBatch, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tag batch tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := r.q.WithTx(tx)
+	results := make([]domain.TagAssignmentBatchResult, len(targets))
+	for i, target := range targets {
+		results[i].Target = target
+		if err := target.Validate(); err != nil {
+			results[i].Status = domain.TagBatchStatusFailed
+			results[i].Error = errorString(err)
+			continue
+		}
+
+		existing, err := qtx.GetTagAssignmentByTarget(ctx, dbstore.GetTagAssignmentByTargetParams{
+			TagID:         tagID,
+			SecurableType: target.SecurableType,
+			SecurableID:   target.SecurableID,
+			ColumnName:    mapper.NullStrFromPtr(target.ColumnName),
+		})
+		switch {
+		case err == nil:
+			results[i].Status = domain.TagBatchStatusUnchanged
+			results[i].AssignmentID = &existing.ID
+			continue
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("look up tag assignment: %w", mapDBError(err))
+		}
+
+		row, err := qtx.CreateTagAssignment(ctx, dbstore.CreateTagAssignmentParams{
+			ID:            newID(),
+			TagID:         tagID,
+			SecurableType: target.SecurableType,
+			SecurableID:   target.SecurableID,
+			ColumnName:    mapper.NullStrFromPtr(target.ColumnName),
+			AssignedBy:    assignedBy,
+		})
+		if err != nil {
+			results[i].Status = domain.TagBatchStatusFailed
+			results[i].Error = errorString(mapDBError(err))
+			continue
+		}
+		results[i].Status = domain.TagBatchStatusAssigned
+		results[i].AssignmentID = &row.ID
+	}
+
+	return finishTagBatch(tx, results)
+}
+
+// UnassignTagBatch removes a tag from every target in a single transaction.
+// Targets without the tag are reported as not found and do not fail the batch.
+func (r *TagRepo) UnassignTagBatch(ctx context.Context, tagID string, targets []domain.TagAssignmentTarget) (*domain.TagAssignmentBatch, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tag batch tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := r.q.WithTx(tx)
+	results := make([]domain.TagAssignmentBatchResult, len(targets))
+	for i, target := range targets {
+		results[i].Target = target
+		if err := target.Validate(); err != nil {
+			results[i].Status = domain.TagBatchStatusFailed
+			results[i].Error = errorString(err)
+			continue
+		}
+
+		n, err := qtx.DeleteTagAssignmentByTarget(ctx, dbstore.DeleteTagAssignmentByTargetParams{
+			TagID:         tagID,
+			SecurableType: target.SecurableType,
+			SecurableID:   target.SecurableID,
+			ColumnName:    mapper.NullStrFromPtr(target.ColumnName),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("delete tag assignment: %w", mapDBError(err))
+		}
+		if n == 0 {
+			results[i].Status = domain.TagBatchStatusNotFound
+			continue
+		}
+		results[i].Status = domain.TagBatchStatusRemoved
+	}
+
+	return finishTagBatch(tx, results)
+}
+
+// finishTagBatch commits tx when every item succeeded. Otherwise it leaves tx
+// to be rolled back and marks the items that did not fail as skipped.
+func finishTagBatch(tx *sql.Tx, results []domain.TagAssignmentBatchResult) (*domain.TagAssignmentBatch, error) {
+	failed := false
+	for _, res := range results {
+		if res.Status == domain.TagBatchStatusFailed {
+			failed = true
+			break
+		}
+	}
+	if failed {
+		for i := range results {
+			if results[i].Status != domain.TagBatchStatusFailed {
+				results[i].Status = domain.TagBatchStatusSkipped
+				results[i].AssignmentID = nil
+			}
+		}
+		return &domain.TagAssignmentBatch{Applied: false, Results: results}, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tag batch: %w", mapDBError(err))
+	}
+	return &domain.TagAssignmentBatch{Applied: true, Results: results}, nil
+}
+
+func errorString(err error) *string {
+	msg := err.Error()
+	return &msg
+}
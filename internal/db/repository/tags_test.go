@@ -282,3 +282,71 @@ func TestTagRepo_ListAssignmentsForTag(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, assignments, 2)
 }
+
+func TestTagRepo_AssignTagBatch_ColumnsAtomically(t *testing.T) {
+	repo := setupTagRepo(t)
+	ctx := context.Background()
+
+	tag, err := repo.CreateTag(ctx, &domain.Tag{Key: "pii", Value: tagPtrStr("email"), CreatedBy: "admin"})
+	require.NoError(t, err)
+
+	// An existing assignment is reported as unchanged, not duplicated.
+	_, err = repo.AssignTag(ctx, &domain.TagAssignment{
+		TagID: tag.ID, SecurableType: "column", SecurableID: "tbl-1", ColumnName: tagPtrStr("email"), AssignedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	columns := []domain.TagAssignmentTarget{
+		{SecurableType: "column", SecurableID: "tbl-1", ColumnName: tagPtrStr("email")},
+		{SecurableType: "column", SecurableID: "tbl-1", ColumnName: tagPtrStr("backup_email")},
+		{SecurableType: "column", SecurableID: "tbl-1", ColumnName: tagPtrStr("contact_email")},
+	}
+	batch, err := repo.AssignTagBatch(ctx, tag.ID, columns, "alice")
+	require.NoError(t, err)
+	require.True(t, batch.Applied)
+	require.Len(t, batch.Results, 3)
+	assert.Equal(t, domain.TagBatchStatusUnchanged, batch.Results[0].Status)
+	assert.Equal(t, domain.TagBatchStatusAssigned, batch.Results[1].Status)
+	assert.Equal(t, domain.TagBatchStatusAssigned, batch.Results[2].Status)
+	for _, res := range batch.Results {
+		assert.NotNil(t, res.AssignmentID)
+	}
+
+	assignments, err := repo.ListAssignmentsForTag(ctx, tag.ID)
+	require.NoError(t, err)
+	assert.Len(t, assignments, 3)
+
+	t.Run("a rejected item rolls back the whole batch", func(t *testing.T) {
+		batch, err := repo.AssignTagBatch(ctx, tag.ID, []domain.TagAssignmentTarget{
+			{SecurableType: "column", SecurableID: "tbl-2", ColumnName: tagPtrStr("email")},
+			{SecurableType: "column", SecurableID: "tbl-2"}, // missing column_name
+		}, "alice")
+		require.NoError(t, err)
+		assert.False(t, batch.Applied)
+		assert.Equal(t, domain.TagBatchStatusSkipped, batch.Results[0].Status)
+		assert.Nil(t, batch.Results[0].AssignmentID)
+		assert.Equal(t, domain.TagBatchStatusFailed, batch.Results[1].Status)
+		require.NotNil(t, batch.Results[1].Error)
+		assert.Contains(t, *batch.Results[1].Error, "column_name")
+
+		assignments, err := repo.ListAssignmentsForTag(ctx, tag.ID)
+		require.NoError(t, err)
+		assert.Len(t, assignments, 3, "nothing from the failed batch should be written")
+	})
+
+	t.Run("unassign removes the batch", func(t *testing.T) {
+		batch, err := repo.UnassignTagBatch(ctx, tag.ID, append(columns,
+			domain.TagAssignmentTarget{SecurableType: "column", SecurableID: "tbl-1", ColumnName: tagPtrStr("phone")}))
+		require.NoError(t, err)
+		require.True(t, batch.Applied)
+		require.Len(t, batch.Results, 4)
+		for _, res := range batch.Results[:3] {
+			assert.Equal(t, domain.TagBatchStatusRemoved, res.Status)
+		}
+		assert.Equal(t, domain.TagBatchStatusNotFound, batch.Results[3].Status)
+
+		assignments, err := repo.ListAssignmentsForTag(ctx, tag.ID)
+		require.NoError(t, err)
+		assert.Empty(t, assignments)
+	})
+}
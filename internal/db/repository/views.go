@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	dbstore "duck-demo/internal/db/dbstore"
 	"duck-demo/internal/db/mapper"
@@ -24,7 +25,8 @@ func NewViewRepo(db *sql.DB) *ViewRepo {
 // GetByID returns a view by ID.
 func (r *ViewRepo) GetByID(ctx context.Context, id string) (*domain.ViewDetail, error) {
 	const query = `
-SELECT id, schema_id, name, view_definition, comment, properties, owner, source_tables, created_at, updated_at, deleted_at
+SELECT id, schema_id, name, view_definition, comment, properties, owner, source_tables, created_at, updated_at, deleted_at,
+       materialized, refresh_schedule, last_refreshed_at, catalog_name
 FROM views
 WHERE id = ? AND deleted_at IS NULL
 `
@@ -42,6 +44,10 @@ WHERE id = ? AND deleted_at IS NULL
 		&v.CreatedAt,
 		&v.UpdatedAt,
 		&v.DeletedAt,
+		&v.Materialized,
+		&v.RefreshSchedule,
+		&v.LastRefreshedAt,
+		&v.CatalogName,
 	)
 	if err != nil {
 		return nil, mapDBError(err)
@@ -55,14 +61,17 @@ func (r *ViewRepo) Create(ctx context.Context, view *domain.ViewDetail) (*domain
 	sourcesJSON, _ := json.Marshal(view.SourceTables)
 
 	row, err := r.q.CreateView(ctx, dbstore.CreateViewParams{
-		ID:             newID(),
-		SchemaID:       view.SchemaID,
-		Name:           view.Name,
-		ViewDefinition: view.ViewDefinition,
-		Comment:        sql.NullString{String: stringFromPtr(view.Comment), Valid: view.Comment != nil},
-		Properties:     sql.NullString{String: string(propsJSON), Valid: true},
-		Owner:          view.Owner,
-		SourceTables:   sql.NullString{String: string(sourcesJSON), Valid: true},
+		ID:              newID(),
+		SchemaID:        view.SchemaID,
+		Name:            view.Name,
+		ViewDefinition:  view.ViewDefinition,
+		Comment:         sql.NullString{String: stringFromPtr(view.Comment), Valid: view.Comment != nil},
+		Properties:      sql.NullString{String: string(propsJSON), Valid: true},
+		Owner:           view.Owner,
+		SourceTables:    sql.NullString{String: string(sourcesJSON), Valid: true},
+		Materialized:    boolToInt(view.Materialized),
+		RefreshSchedule: mapper.NullStrFromPtr(view.RefreshSchedule),
+		CatalogName:     mapper.NullStrFromStr(view.CatalogName),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
@@ -82,14 +91,19 @@ func (r *ViewRepo) GetByName(ctx context.Context, schemaID string, viewName stri
 	return mapper.ViewFromDB(row), nil
 }
 
-// List returns a paginated list of views in a schema.
-func (r *ViewRepo) List(ctx context.Context, schemaID string, page domain.PageRequest) ([]domain.ViewDetail, int64, error) {
-	total, err := r.q.CountViews(ctx, schemaID)
+// List returns a paginated list of views in a schema, limited to those owner
+// owns when it is non-nil.
+func (r *ViewRepo) List(ctx context.Context, schemaID string, owner *string, page domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+	total, err := r.q.CountViews(ctx, dbstore.CountViewsParams{
+		SchemaID: schemaID,
+		Owner:    mapper.InterfaceFromPtr(owner),
+	})
 	if err != nil {
 		return nil, 0, err
 	}
 	rows, err := r.q.ListViews(ctx, dbstore.ListViewsParams{
 		SchemaID: schemaID,
+		Owner:    mapper.InterfaceFromPtr(owner),
 		Limit:    int64(page.Limit()),
 		Offset:   int64(page.Offset()),
 	})
@@ -111,8 +125,10 @@ func (r *ViewRepo) Delete(ctx context.Context, schemaID string, viewName string)
 	})
 }
 
-// Update applies partial updates to a view's metadata and definition.
-func (r *ViewRepo) Update(ctx context.Context, schemaID string, viewName string, comment *string, props map[string]string, viewDef *string) (*domain.ViewDetail, error) {
+// Update applies partial updates to a view's metadata and definition. A
+// non-nil expectedRowVersion makes the update conditional on the view's row
+// version.
+func (r *ViewRepo) Update(ctx context.Context, schemaID string, viewName string, comment *string, props map[string]string, viewDef *string, expectedRowVersion *int64) (*domain.ViewDetail, error) {
 	// Verify view exists
 	existing, err := r.GetByName(ctx, schemaID, viewName)
 	if err != nil {
@@ -140,21 +156,63 @@ func (r *ViewRepo) Update(ctx context.Context, schemaID string, viewName string,
 	newSourceTables := existing.SourceTables
 	sourcesJSON, _ := json.Marshal(newSourceTables)
 
-	err = r.q.UpdateView(ctx, dbstore.UpdateViewParams{
-		Comment:        sql.NullString{String: stringFromPtr(newComment), Valid: newComment != nil},
-		Properties:     sql.NullString{String: string(propsJSON), Valid: true},
-		ViewDefinition: newViewDef,
-		SourceTables:   sql.NullString{String: string(sourcesJSON), Valid: true},
-		SchemaID:       schemaID,
-		Name:           viewName,
+	n, err := r.q.UpdateView(ctx, dbstore.UpdateViewParams{
+		Comment:            sql.NullString{String: stringFromPtr(newComment), Valid: newComment != nil},
+		Properties:         sql.NullString{String: string(propsJSON), Valid: true},
+		ViewDefinition:     newViewDef,
+		SourceTables:       sql.NullString{String: string(sourcesJSON), Valid: true},
+		SchemaID:           schemaID,
+		Name:               viewName,
+		ExpectedRowVersion: rowVersionArg(expectedRowVersion),
 	})
 	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, expectedRowVersion, "view", viewName); err != nil {
+		return nil, err
+	}
 
 	return r.GetByName(ctx, schemaID, viewName)
 }
 
+// ListScheduled returns the live materialized views that have a refresh
+// schedule.
+func (r *ViewRepo) ListScheduled(ctx context.Context) ([]domain.ViewDetail, error) {
+	rows, err := r.q.ListScheduledViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]domain.ViewDetail, len(rows))
+	for i, row := range rows {
+		views[i] = *mapper.ViewFromDB(row)
+	}
+	return views, nil
+}
+
+// MarkRefreshed records when a materialized view was last refreshed.
+func (r *ViewRepo) MarkRefreshed(ctx context.Context, schemaID string, viewName string, at time.Time) error {
+	return r.q.MarkViewRefreshed(ctx, dbstore.MarkViewRefreshedParams{
+		LastRefreshedAt: mapper.NullStrFromStr(at.UTC().Format(time.DateTime)),
+		SchemaID:        schemaID,
+		Name:            viewName,
+	})
+}
+
+// Rename changes the name of a live view.
+func (r *ViewRepo) Rename(ctx context.Context, schemaID string, viewName, newName string) (*domain.ViewDetail, error) {
+	if _, err := r.GetByName(ctx, schemaID, viewName); err != nil {
+		return nil, err
+	}
+	if err := r.q.RenameView(ctx, dbstore.RenameViewParams{
+		NewName:  newName,
+		SchemaID: schemaID,
+		OldName:  viewName,
+	}); err != nil {
+		return nil, mapDBError(err)
+	}
+	return r.GetByName(ctx, schemaID, newName)
+}
+
 func stringFromPtr(s *string) string {
 	if s == nil {
 		return ""
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
@@ -79,7 +80,7 @@ func TestViewRepo_List(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	views, total, err := repo.List(ctx, "schema-001", domain.PageRequest{})
+	views, total, err := repo.List(ctx, "schema-001", nil, domain.PageRequest{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), total)
 	assert.Len(t, views, 2)
@@ -88,12 +89,37 @@ func TestViewRepo_List(t *testing.T) {
 	assert.Equal(t, "view_beta", views[1].Name)
 
 	// A different schema should return zero results.
-	views2, total2, err := repo.List(ctx, "schema-other", domain.PageRequest{})
+	views2, total2, err := repo.List(ctx, "schema-other", nil, domain.PageRequest{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(0), total2)
 	assert.Empty(t, views2)
 }
 
+func TestViewRepo_List_FilterByOwner(t *testing.T) {
+	repo := setupViewRepo(t)
+	ctx := context.Background()
+
+	for _, v := range []struct{ name, owner string }{
+		{"view_alpha", "alice"}, {"view_beta", "bob"}, {"view_gamma", "alice"},
+	} {
+		_, err := repo.Create(ctx, &domain.ViewDetail{
+			SchemaID:       "schema-001",
+			Name:           v.name,
+			ViewDefinition: "SELECT 1",
+			Owner:          v.owner,
+		})
+		require.NoError(t, err)
+	}
+
+	alice := "alice"
+	views, total, err := repo.List(ctx, "schema-001", &alice, domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, views, 2)
+	assert.Equal(t, "view_alpha", views[0].Name)
+	assert.Equal(t, "view_gamma", views[1].Name)
+}
+
 func TestViewRepo_Delete(t *testing.T) {
 	repo := setupViewRepo(t)
 	ctx := context.Background()
@@ -131,7 +157,7 @@ func TestViewRepo_Update(t *testing.T) {
 
 	newComment := "updated comment"
 	newDef := "SELECT 2"
-	updated, err := repo.Update(ctx, "schema-001", "updatable_view", &newComment, nil, &newDef)
+	updated, err := repo.Update(ctx, "schema-001", "updatable_view", &newComment, nil, &newDef, nil)
 	require.NoError(t, err)
 	require.NotNil(t, updated)
 
@@ -162,7 +188,7 @@ func TestViewRepo_UpdateProperties(t *testing.T) {
 	require.NoError(t, err)
 
 	newProps := map[string]string{"key2": "val2", "key3": "val3"}
-	updated, err := repo.Update(ctx, "schema-001", "props_view", nil, newProps, nil)
+	updated, err := repo.Update(ctx, "schema-001", "props_view", nil, newProps, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, updated)
 
@@ -176,3 +202,38 @@ func TestViewRepo_UpdateProperties(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, map[string]string{"key2": "val2", "key3": "val3"}, got.Properties)
 }
+
+func TestViewRepo_ScheduledMaterializedViews(t *testing.T) {
+	repo := setupViewRepo(t)
+	ctx := context.Background()
+
+	for _, v := range []domain.ViewDetail{
+		{Name: "hourly", Materialized: true, RefreshSchedule: viewPtrStr("0 * * * *")},
+		{Name: "on_demand", Materialized: true},
+		{Name: "logical"},
+	} {
+		v.SchemaID = "schema-001"
+		v.CatalogName = "lake"
+		v.ViewDefinition = "SELECT 1"
+		v.Owner = "admin"
+		_, err := repo.Create(ctx, &v)
+		require.NoError(t, err)
+	}
+
+	scheduled, err := repo.ListScheduled(ctx)
+	require.NoError(t, err)
+	require.Len(t, scheduled, 1)
+	assert.Equal(t, "hourly", scheduled[0].Name)
+	assert.Equal(t, "lake", scheduled[0].CatalogName)
+	assert.True(t, scheduled[0].Materialized)
+	require.NotNil(t, scheduled[0].RefreshSchedule)
+	assert.Equal(t, "0 * * * *", *scheduled[0].RefreshSchedule)
+	assert.Nil(t, scheduled[0].LastRefreshedAt)
+
+	at := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	require.NoError(t, repo.MarkRefreshed(ctx, "schema-001", "hourly", at))
+	got, err := repo.GetByName(ctx, "schema-001", "hourly")
+	require.NoError(t, err)
+	require.NotNil(t, got.LastRefreshedAt)
+	assert.True(t, at.Equal(*got.LastRefreshedAt))
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"duck-demo/internal/db/dbstore"
+	"duck-demo/internal/db/mapper"
 	"duck-demo/internal/domain"
 )
 
@@ -55,15 +56,40 @@ func (r *VolumeRepo) GetByName(ctx context.Context, schemaName, name string) (*d
 	return volumeFromDB(row), nil
 }
 
-// List returns a paginated list of volumes in a schema.
-func (r *VolumeRepo) List(ctx context.Context, schemaName string, page domain.PageRequest) ([]domain.Volume, int64, error) {
-	total, err := r.q.CountVolumes(ctx, schemaName)
+// GetByID returns a volume by ID.
+func (r *VolumeRepo) GetByID(ctx context.Context, id string) (*domain.Volume, error) {
+	row, err := r.getRow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return volumeFromDB(row), nil
+}
+
+// getRow reads the volume row with the given ID.
+func (r *VolumeRepo) getRow(ctx context.Context, id string) (dbstore.Volume, error) {
+	var row dbstore.Volume
+	err := r.db.QueryRowContext(ctx, "SELECT id, name, schema_name, catalog_name, volume_type, storage_location, comment, owner, created_at, updated_at FROM volumes WHERE id = ?", id).Scan(
+		&row.ID, &row.Name, &row.SchemaName, &row.CatalogName,
+		&row.VolumeType, &row.StorageLocation, &row.Comment,
+		&row.Owner, &row.CreatedAt, &row.UpdatedAt,
+	)
+	return row, mapDBError(err)
+}
+
+// List returns a paginated list of volumes in a schema, limited to those owner
+// owns when it is non-nil.
+func (r *VolumeRepo) List(ctx context.Context, schemaName string, owner *string, page domain.PageRequest) ([]domain.Volume, int64, error) {
+	total, err := r.q.CountVolumes(ctx, dbstore.CountVolumesParams{
+		SchemaName: schemaName,
+		Owner:      mapper.InterfaceFromPtr(owner),
+	})
 	if err != nil {
 		return nil, 0, fmt.Errorf("count volumes: %w", err)
 	}
 
 	rows, err := r.q.ListVolumes(ctx, dbstore.ListVolumesParams{
 		SchemaName: schemaName,
+		Owner:      mapper.InterfaceFromPtr(owner),
 		Limit:      int64(page.Limit()),
 		Offset:     int64(page.Offset()),
 	})
@@ -81,14 +107,9 @@ func (r *VolumeRepo) List(ctx context.Context, schemaName string, page domain.Pa
 // Update applies partial updates to a volume by ID.
 func (r *VolumeRepo) Update(ctx context.Context, id string, req domain.UpdateVolumeRequest) (*domain.Volume, error) {
 	// Fetch current to merge fields.
-	var current dbstore.Volume
-	row := r.db.QueryRowContext(ctx, "SELECT id, name, schema_name, catalog_name, volume_type, storage_location, comment, owner, created_at, updated_at FROM volumes WHERE id = ?", id)
-	if err := row.Scan(
-		&current.ID, &current.Name, &current.SchemaName, &current.CatalogName,
-		&current.VolumeType, &current.StorageLocation, &current.Comment,
-		&current.Owner, &current.CreatedAt, &current.UpdatedAt,
-	); err != nil {
-		return nil, mapDBError(err)
+	current, err := r.getRow(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
 	name := current.Name
@@ -104,14 +125,19 @@ func (r *VolumeRepo) Update(ctx context.Context, id string, req domain.UpdateVol
 		owner = *req.Owner
 	}
 
-	if err := r.q.UpdateVolume(ctx, dbstore.UpdateVolumeParams{
-		Name:    name,
-		Comment: comment,
-		Owner:   owner,
-		ID:      id,
-	}); err != nil {
+	n, err := r.q.UpdateVolume(ctx, dbstore.UpdateVolumeParams{
+		Name:               name,
+		Comment:            comment,
+		Owner:              owner,
+		ID:                 id,
+		ExpectedRowVersion: rowVersionArg(req.ExpectedRowVersion),
+	})
+	if err != nil {
 		return nil, mapDBError(err)
 	}
+	if err := checkRowVersion(n, req.ExpectedRowVersion, "volume", current.Name); err != nil {
+		return nil, err
+	}
 
 	// Re-fetch to return updated state.
 	updated, err := r.q.GetVolumeByName(ctx, dbstore.GetVolumeByNameParams{
@@ -150,5 +176,6 @@ func volumeFromDB(row dbstore.Volume) *domain.Volume {
 		Owner:           row.Owner,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
+		RowVersion:      row.RowVersion,
 	}
 }
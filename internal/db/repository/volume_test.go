@@ -53,6 +53,26 @@ func TestVolume_CreateAndGet(t *testing.T) {
 	assert.Equal(t, vol.StorageLocation, found.StorageLocation)
 }
 
+func TestVolume_GetByID(t *testing.T) {
+	repo := setupVolumeRepo(t)
+	ctx := context.Background()
+
+	vol, err := repo.Create(ctx, &domain.Volume{
+		Name: "by_id", SchemaName: "analytics", CatalogName: "archive",
+		VolumeType: domain.VolumeTypeManaged,
+	})
+	require.NoError(t, err)
+
+	found, err := repo.GetByID(ctx, vol.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "by_id", found.Name)
+	assert.Equal(t, "archive", found.CatalogName)
+
+	_, err = repo.GetByID(ctx, "nonexistent")
+	var notFound *domain.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
 func TestVolume_GetByName_NotFound(t *testing.T) {
 	repo := setupVolumeRepo(t)
 	ctx := context.Background()
@@ -90,24 +110,41 @@ func TestVolume_List(t *testing.T) {
 	require.NoError(t, err)
 
 	// List analytics
-	vols, total, err := repo.List(ctx, "analytics", domain.PageRequest{})
+	vols, total, err := repo.List(ctx, "analytics", nil, domain.PageRequest{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), total)
 	assert.Len(t, vols, 2)
 	assert.Equal(t, "alpha", vols[0].Name) // sorted by name
 
 	// List other
-	vols, total, err = repo.List(ctx, "other", domain.PageRequest{})
+	vols, total, err = repo.List(ctx, "other", nil, domain.PageRequest{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), total)
 	assert.Len(t, vols, 1)
 	assert.Equal(t, "gamma", vols[0].Name)
 
 	// List empty schema
-	vols, total, err = repo.List(ctx, "empty", domain.PageRequest{})
+	vols, total, err = repo.List(ctx, "empty", nil, domain.PageRequest{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(0), total)
 	assert.Empty(t, vols)
+
+	// Filter by owner
+	_, err = repo.Create(ctx, &domain.Volume{
+		Name:            "delta",
+		SchemaName:      "analytics",
+		CatalogName:     "lake",
+		VolumeType:      domain.VolumeTypeManaged,
+		StorageLocation: "s3://bucket/delta",
+		Owner:           "alice",
+	})
+	require.NoError(t, err)
+	alice := "alice"
+	vols, total, err = repo.List(ctx, "analytics", &alice, domain.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, vols, 1)
+	assert.Equal(t, "delta", vols[0].Name)
 }
 
 func TestVolume_Update(t *testing.T) {
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"duck-demo/internal/db/crypto"
+	"duck-demo/internal/domain"
+)
+
+var _ domain.WebhookRepository = (*WebhookRepo)(nil)
+
+// WebhookRepo stores webhook subscriptions in SQLite. Signing secrets are
+// encrypted at rest.
+type WebhookRepo struct {
+	db  *sql.DB
+	enc *crypto.Encryptor
+}
+
+// NewWebhookRepo creates a new WebhookRepo.
+func NewWebhookRepo(db *sql.DB, enc *crypto.Encryptor) *WebhookRepo {
+	return &WebhookRepo{db: db, enc: enc}
+}
+
+const webhookColumns = `id, url, secret_encrypted, events_json, description, created_by, created_at, updated_at`
+
+// Create inserts a new webhook subscription.
+func (r *WebhookRepo) Create(ctx context.Context, hook *domain.Webhook) (*domain.Webhook, error) {
+	if hook.ID == "" {
+		hook.ID = domain.NewID()
+	}
+	secret, err := r.enc.Encrypt(hook.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt webhook secret: %w", err)
+	}
+	events, err := json.Marshal(hook.Events)
+	if err != nil {
+		return nil, fmt.Errorf("marshal events: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO webhooks (id, url, secret_encrypted, events_json, description, created_by)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, hook.ID, hook.URL, secret, string(events), hook.Description, hook.CreatedBy)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	return r.GetByID(ctx, hook.ID)
+}
+
+// GetByID returns a webhook by ID.
+func (r *WebhookRepo) GetByID(ctx context.Context, id string) (*domain.Webhook, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`, id)
+	hook, err := r.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound("webhook %q not found", id)
+	}
+	return hook, err
+}
+
+// List returns a page of webhooks ordered by creation time.
+func (r *WebhookRepo) List(ctx context.Context, page domain.PageRequest) ([]domain.Webhook, int64, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhooks`).Scan(&total); err != nil {
+		return nil, 0, mapDBError(err)
+	}
+	hooks, err := r.query(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY created_at, id LIMIT ? OFFSET ?`,
+		page.Limit(), page.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	return hooks, total, nil
+}
+
+// ListAll returns every webhook, for event fan-out.
+func (r *WebhookRepo) ListAll(ctx context.Context) ([]domain.Webhook, error) {
+	return r.query(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY created_at, id`)
+}
+
+// Delete removes a webhook.
+func (r *WebhookRepo) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return mapDBError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrNotFound("webhook %q not found", id)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) query(ctx context.Context, stmt string, args ...interface{}) ([]domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var hooks []domain.Webhook
+	for rows.Next() {
+		hook, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, *hook)
+	}
+	return hooks, rows.Err()
+}
+
+type webhookRowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *WebhookRepo) scan(row webhookRowScanner) (*domain.Webhook, error) {
+	var (
+		hook              domain.Webhook
+		secretEnc, events string
+		description       sql.NullString
+	)
+	if err := row.Scan(&hook.ID, &hook.URL, &secretEnc, &events, &description,
+		&hook.CreatedBy, &hook.CreatedAt, &hook.UpdatedAt); err != nil {
+		return nil, err
+	}
+	secret, err := r.enc.Decrypt(secretEnc)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt webhook secret: %w", err)
+	}
+	hook.Secret = secret
+	if err := json.Unmarshal([]byte(events), &hook.Events); err != nil {
+		return nil, fmt.Errorf("unmarshal events: %w", err)
+	}
+	if description.Valid {
+		hook.Description = &description.String
+	}
+	return &hook, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/db"
+	"duck-demo/internal/db/crypto"
+	"duck-demo/internal/domain"
+)
+
+func setupWebhookRepo(t *testing.T) *WebhookRepo {
+	t.Helper()
+	writeDB, _ := db.OpenTestSQLite(t)
+	enc, err := crypto.NewEncryptor("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	return NewWebhookRepo(writeDB, enc)
+}
+
+func TestWebhookRepo_CRUD(t *testing.T) {
+	t.Parallel()
+	repo := setupWebhookRepo(t)
+	ctx := context.Background()
+
+	desc := "security alerts"
+	created, err := repo.Create(ctx, &domain.Webhook{
+		URL:         "https://hooks.example.com/duck",
+		Secret:      "super-secret-signing-key",
+		Events:      []string{domain.EventGrantCreated, domain.EventPrincipalAdminChanged},
+		Description: &desc,
+		CreatedBy:   "admin",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	assert.Equal(t, "super-secret-signing-key", created.Secret, "secret round-trips through encryption")
+	assert.Equal(t, []string{domain.EventGrantCreated, domain.EventPrincipalAdminChanged}, created.Events)
+	require.NotNil(t, created.Description)
+	assert.Equal(t, desc, *created.Description)
+
+	hooks, total, err := repo.List(ctx, domain.PageRequest{MaxResults: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, hooks, 1)
+
+	all, err := repo.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	require.NoError(t, repo.Delete(ctx, created.ID))
+	_, err = repo.GetByID(ctx, created.ID)
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+	require.ErrorAs(t, repo.Delete(ctx, created.ID), &notFound)
+}
+
+func TestWebhookRepo_SecretEncryptedAtRest(t *testing.T) {
+	t.Parallel()
+	repo := setupWebhookRepo(t)
+
+	created, err := repo.Create(context.Background(), &domain.Webhook{
+		URL: "https://hooks.example.com/duck", Secret: "super-secret-signing-key",
+		Events: []string{domain.EventAll}, CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	var stored string
+	require.NoError(t, repo.db.QueryRowContext(context.Background(),
+		`SELECT secret_encrypted FROM webhooks WHERE id = ?`, created.ID).Scan(&stored))
+	assert.NotContains(t, stored, "super-secret-signing-key")
+}
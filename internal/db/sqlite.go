@@ -4,18 +4,51 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"duck-demo/internal/domain"
 )
 
 // SQLite DSN parameters for production hardening.
 const (
-	defaultBusyTimeout = "5000" // 5 seconds
+	defaultBusyTimeout = 5 * time.Second
 	defaultSynchronous = "NORMAL"
 	defaultJournalMode = "WAL"
+	defaultReadMaxOpen = 4
 )
 
+// SQLiteOptions tunes the SQLite connection pools. Zero values keep the
+// defaults: a 4-connection read pool, a 5s busy_timeout, and SQLite's own
+// page cache size.
+type SQLiteOptions struct {
+	// ReadMaxOpen is the read pool size.
+	ReadMaxOpen int
+	// BusyTimeout is how long a connection waits on a locked database before
+	// failing with SQLITE_BUSY.
+	BusyTimeout time.Duration
+	// CacheSizeKiB sets the per-connection page cache (PRAGMA cache_size).
+	CacheSizeKiB int
+}
+
+func (o SQLiteOptions) withDefaults() SQLiteOptions {
+	if o.ReadMaxOpen <= 0 {
+		o.ReadMaxOpen = defaultReadMaxOpen
+	}
+	if o.BusyTimeout <= 0 {
+		o.BusyTimeout = defaultBusyTimeout
+	}
+	return o
+}
+
 // OpenSQLite opens a *sql.DB pool for the given SQLite file path.
 //
 // mode controls write-safety and pool sizing:
@@ -25,11 +58,31 @@ const (
 // Both modes set WAL journal, busy_timeout=5000ms, synchronous=NORMAL,
 // and foreign_keys=on.
 func OpenSQLite(path string, mode string, maxOpen int) (*sql.DB, error) {
-	if mode != "read" && mode != "write" {
-		return nil, fmt.Errorf("invalid SQLite mode %q: must be \"read\" or \"write\"", mode)
+	return OpenSQLiteWithOptions(path, mode, SQLiteOptions{ReadMaxOpen: maxOpen})
+}
+
+// OpenSQLiteWithOptions is OpenSQLite with tunable busy timeout, cache size,
+// and read pool size. opts.ReadMaxOpen is ignored in "write" mode.
+//
+// A third mode, "replica", opens a read pool on a copy of the metastore kept
+// in sync by an external process (e.g. Litestream or LiteFS). The file must
+// already exist, connections are query-only, and the journal mode is left to
+// the process that maintains the copy.
+func OpenSQLiteWithOptions(path string, mode string, opts SQLiteOptions) (*sql.DB, error) {
+	switch mode {
+	case "read", "write":
+	case "replica":
+		// SQLite would otherwise create an empty file and every read would
+		// fail with "no such table".
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("open sqlite replica: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid SQLite mode %q: must be \"read\", \"write\" or \"replica\"", mode)
 	}
+	opts = opts.withDefaults()
 
-	dsn := buildDSN(path, mode)
+	dsn := buildDSNWithOptions(path, mode, opts)
 
 	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
@@ -40,12 +93,9 @@ func OpenSQLite(path string, mode string, maxOpen int) (*sql.DB, error) {
 	case "write":
 		db.SetMaxOpenConns(1)
 		db.SetMaxIdleConns(1)
-	case "read":
-		if maxOpen <= 0 {
-			maxOpen = 4
-		}
-		db.SetMaxOpenConns(maxOpen)
-		db.SetMaxIdleConns(maxOpen)
+	case "read", "replica":
+		db.SetMaxOpenConns(opts.ReadMaxOpen)
+		db.SetMaxIdleConns(opts.ReadMaxOpen)
 	}
 	db.SetConnMaxLifetime(time.Hour)
 
@@ -66,12 +116,17 @@ func OpenSQLite(path string, mode string, maxOpen int) (*sql.DB, error) {
 //
 // readMaxOpen controls the read pool size (0 defaults to 4).
 func OpenSQLitePair(path string, readMaxOpen int) (writeDB, readDB *sql.DB, err error) {
-	writeDB, err = OpenSQLite(path, "write", 0)
+	return OpenSQLitePairWithOptions(path, SQLiteOptions{ReadMaxOpen: readMaxOpen})
+}
+
+// OpenSQLitePairWithOptions is OpenSQLitePair with tunable pool settings.
+func OpenSQLitePairWithOptions(path string, opts SQLiteOptions) (writeDB, readDB *sql.DB, err error) {
+	writeDB, err = OpenSQLiteWithOptions(path, "write", opts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	readDB, err = OpenSQLite(path, "read", readMaxOpen)
+	readDB, err = OpenSQLiteWithOptions(path, "read", opts)
 	if err != nil {
 		_ = writeDB.Close()
 		return nil, nil, err
@@ -80,17 +135,79 @@ func OpenSQLitePair(path string, readMaxOpen int) (writeDB, readDB *sql.DB, err
 	return writeDB, readDB, nil
 }
 
-// buildDSN constructs a SQLite DSN with hardened parameters.
+// buildDSN constructs a SQLite DSN with hardened default parameters.
 func buildDSN(path string, mode string) string {
+	return buildDSNWithOptions(path, mode, SQLiteOptions{}.withDefaults())
+}
+
+// buildDSNWithOptions constructs a SQLite DSN with hardened parameters and
+// the tunables from opts.
+func buildDSNWithOptions(path string, mode string, opts SQLiteOptions) string {
 	params := url.Values{}
-	params.Set("_journal_mode", defaultJournalMode)
-	params.Set("_busy_timeout", defaultBusyTimeout)
+	if mode != "replica" {
+		params.Set("_journal_mode", defaultJournalMode)
+	}
+	params.Set("_busy_timeout", strconv.FormatInt(opts.BusyTimeout.Milliseconds(), 10))
 	params.Set("_synchronous", defaultSynchronous)
 	params.Set("_foreign_keys", "on")
+	if opts.CacheSizeKiB > 0 {
+		// Negative cache_size is interpreted by SQLite as KiB rather than pages.
+		params.Set("_cache_size", strconv.Itoa(-opts.CacheSizeKiB))
+	}
 
-	if mode == "write" {
+	switch mode {
+	case "write":
 		params.Set("_txlock", "immediate")
+	case "replica":
+		params.Set("_query_only", "on")
 	}
 
 	return path + "?" + params.Encode()
 }
+
+// IsBusy reports whether err is SQLite lock contention (SQLITE_BUSY or
+// SQLITE_LOCKED) that outlasted the busy_timeout.
+func IsBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	return err != nil && strings.Contains(err.Error(), "database is locked")
+}
+
+// MapBusy converts SQLite lock contention into a retryable "metastore busy"
+// domain error. Other errors are returned unchanged.
+func MapBusy(err error) error {
+	if !IsBusy(err) {
+		return err
+	}
+	return domain.ErrUnavailable("metastore busy: another write is in progress, retry the request")
+}
+
+// WatchPoolContention logs a warning whenever callers of db had to wait for a
+// connection during the last interval, with the added wait count and time and
+// the current in-use count. It returns when ctx is canceled.
+func WatchPoolContention(ctx context.Context, name string, db *sql.DB, logger *slog.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	prev := db.Stats()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cur := db.Stats()
+			if waits := cur.WaitCount - prev.WaitCount; waits > 0 {
+				logger.Warn("sqlite pool contention",
+					"pool", name,
+					"waits", waits,
+					"wait_duration", cur.WaitDuration-prev.WaitDuration,
+					"in_use", cur.InUse,
+					"max_open", cur.MaxOpenConnections,
+				)
+			}
+			prev = cur
+		}
+	}
+}
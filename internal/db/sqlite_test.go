@@ -3,15 +3,19 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
 )
 
 // ctx is a package-level background context used by all tests in this file.
@@ -38,6 +42,15 @@ func TestBuildDSN_Read(t *testing.T) {
 	assert.NotContains(t, dsn, "_txlock")
 }
 
+func TestBuildDSN_Replica(t *testing.T) {
+	dsn := buildDSN("/tmp/replica.sqlite", "replica")
+
+	assert.Contains(t, dsn, "_query_only=on")
+	assert.Contains(t, dsn, "_busy_timeout=5000")
+	assert.NotContains(t, dsn, "_journal_mode")
+	assert.NotContains(t, dsn, "_txlock")
+}
+
 func TestOpenSQLite_InvalidMode(t *testing.T) {
 	_, err := OpenSQLite(filepath.Join(t.TempDir(), "test.db"), "invalid", 0)
 	require.Error(t, err)
@@ -232,5 +245,187 @@ func TestOpenSQLite_BusyTimeoutPreventsErrors(t *testing.T) {
 	assert.Equal(t, 20, n)
 }
 
+// TestOpenSQLitePair_ConcurrentWritersSerialize runs read-modify-write
+// transactions from many goroutines through the single-connection write pool
+// and checks that none are lost and none deadlock.
+func TestOpenSQLitePair_ConcurrentWritersSerialize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	writeDB, readDB, err := OpenSQLitePair(path, 4)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = writeDB.Close()
+		_ = readDB.Close()
+	})
+
+	_, err = writeDB.ExecContext(ctx, "CREATE TABLE counter (id INTEGER PRIMARY KEY, n INTEGER)")
+	require.NoError(t, err)
+	_, err = writeDB.ExecContext(ctx, "INSERT INTO counter (id, n) VALUES (1, 0)")
+	require.NoError(t, err)
+
+	tctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	const writers = 25
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			tx, err := writeDB.BeginTx(tctx, nil)
+			if err != nil {
+				errs[idx] = err
+				return
+			}
+			var n int
+			if err := tx.QueryRowContext(tctx, "SELECT n FROM counter WHERE id = 1").Scan(&n); err != nil {
+				_ = tx.Rollback()
+				errs[idx] = err
+				return
+			}
+			if _, err := tx.ExecContext(tctx, "UPDATE counter SET n = ? WHERE id = 1", n+1); err != nil {
+				_ = tx.Rollback()
+				errs[idx] = err
+				return
+			}
+			errs[idx] = tx.Commit()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, e := range errs {
+		require.NoError(t, e, "writer %d failed", i)
+	}
+
+	var n int
+	err = readDB.QueryRowContext(ctx, "SELECT n FROM counter WHERE id = 1").Scan(&n)
+	require.NoError(t, err)
+	assert.Equal(t, writers, n)
+}
+
+func TestOpenSQLitePairWithOptions_Tuned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	writeDB, readDB, err := OpenSQLitePairWithOptions(path, SQLiteOptions{
+		ReadMaxOpen:  16,
+		BusyTimeout:  250 * time.Millisecond,
+		CacheSizeKiB: 8192,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = writeDB.Close()
+		_ = readDB.Close()
+	})
+
+	assert.Equal(t, 1, writeDB.Stats().MaxOpenConnections)
+	assert.Equal(t, 16, readDB.Stats().MaxOpenConnections)
+
+	var busyTimeout, cacheSize int
+	require.NoError(t, readDB.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout))
+	require.NoError(t, readDB.QueryRowContext(ctx, "PRAGMA cache_size").Scan(&cacheSize))
+	assert.Equal(t, 250, busyTimeout)
+	assert.Equal(t, -8192, cacheSize)
+
+	// Hold 16 read connections at once; a 4-connection pool would block here.
+	conns := make([]*sql.Conn, 0, 16)
+	for i := 0; i < 16; i++ {
+		cctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		conn, err := readDB.Conn(cctx)
+		cancel()
+		require.NoError(t, err, "read connection %d", i)
+		conns = append(conns, conn)
+	}
+	assert.Equal(t, 16, readDB.Stats().InUse)
+	for _, c := range conns {
+		_ = c.Close()
+	}
+}
+
+func TestMapBusy_WriteContention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	opts := SQLiteOptions{BusyTimeout: 50 * time.Millisecond}
+
+	// Two independent write pools stand in for an external writer holding
+	// the lock longer than busy_timeout.
+	holder, err := OpenSQLiteWithOptions(path, "write", opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = holder.Close() })
+	contender, err := OpenSQLiteWithOptions(path, "write", opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = contender.Close() })
+
+	_, err = holder.ExecContext(ctx, "CREATE TABLE t (n INTEGER)")
+	require.NoError(t, err)
+
+	tx, err := holder.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = tx.Rollback() })
+	_, err = tx.ExecContext(ctx, "INSERT INTO t (n) VALUES (1)")
+	require.NoError(t, err)
+
+	_, err = contender.ExecContext(ctx, "INSERT INTO t (n) VALUES (2)")
+	require.Error(t, err)
+	assert.True(t, IsBusy(err), "expected busy error, got %v", err)
+
+	mapped := MapBusy(err)
+	var unavailable *domain.UnavailableError
+	require.ErrorAs(t, mapped, &unavailable)
+	assert.Contains(t, mapped.Error(), "metastore busy")
+
+	other := errors.New("boom")
+	assert.Equal(t, other, MapBusy(other))
+	assert.False(t, IsBusy(nil))
+}
+
 // verify sql.DB is interface compatible for test use
 var _ interface{ Stats() sql.DBStats } = (*sql.DB)(nil)
+
+func TestOpenSQLite_ReplicaRequiresExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.db")
+
+	_, err := OpenSQLiteWithOptions(path, "replica", SQLiteOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "open sqlite replica")
+	assert.NoFileExists(t, path)
+}
+
+func TestOpenSQLite_ReplicaServesReadsWhileWritesGoToPrimary(t *testing.T) {
+	dir := t.TempDir()
+	primaryPath := filepath.Join(dir, "primary.db")
+	replicaPath := filepath.Join(dir, "replica.db")
+
+	writeDB, readDB, err := OpenSQLitePair(primaryPath, 2)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = writeDB.Close()
+		_ = readDB.Close()
+	})
+	_, err = writeDB.ExecContext(ctx, "CREATE TABLE test (id INTEGER PRIMARY KEY, val TEXT)")
+	require.NoError(t, err)
+	_, err = writeDB.ExecContext(ctx, "INSERT INTO test (val) VALUES ('synced')")
+	require.NoError(t, err)
+
+	// Stand in for the replication process: copy the primary once.
+	_, err = readDB.ExecContext(ctx, "VACUUM INTO ?", replicaPath)
+	require.NoError(t, err)
+
+	replicaDB, err := OpenSQLiteWithOptions(replicaPath, "replica", SQLiteOptions{ReadMaxOpen: 3})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = replicaDB.Close() })
+	assert.Equal(t, 3, replicaDB.Stats().MaxOpenConnections)
+
+	// Writes land on the primary only; the replica serves what was synced.
+	_, err = writeDB.ExecContext(ctx, "INSERT INTO test (val) VALUES ('primary-only')")
+	require.NoError(t, err)
+
+	var n int
+	require.NoError(t, writeDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM test").Scan(&n))
+	assert.Equal(t, 2, n)
+	require.NoError(t, replicaDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM test").Scan(&n))
+	assert.Equal(t, 1, n)
+
+	// The replica pool refuses writes.
+	_, err = replicaDB.ExecContext(ctx, "INSERT INTO test (val) VALUES ('nope')")
+	require.Error(t, err)
+}
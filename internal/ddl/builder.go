@@ -4,6 +4,7 @@ package ddl
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // ColumnDef describes a column for CREATE TABLE.
@@ -73,6 +74,166 @@ func CreateTable(catalog, schema, table string, columns []ColumnDef) (string, er
 	), nil
 }
 
+// SetPartitionedBy returns a DuckLake DDL statement:
+// ALTER TABLE <catalog>."<schema>"."<table>" SET PARTITIONED BY ("<col1>", ...).
+func SetPartitionedBy(catalog, schema, table string, columns []string) (string, error) {
+	if err := ValidateIdentifier(catalog); err != nil {
+		return "", fmt.Errorf("invalid catalog name: %w", err)
+	}
+	if err := ValidateIdentifier(schema); err != nil {
+		return "", fmt.Errorf("invalid schema name: %w", err)
+	}
+	if err := ValidateIdentifier(table); err != nil {
+		return "", fmt.Errorf("invalid table name: %w", err)
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("at least one partition column is required")
+	}
+
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		if err := ValidateIdentifier(c); err != nil {
+			return "", fmt.Errorf("invalid partition column %q: %w", c, err)
+		}
+		quoted[i] = QuoteIdentifier(c)
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s.%s.%s SET PARTITIONED BY (%s)",
+		QuoteIdentifier(catalog),
+		QuoteIdentifier(schema),
+		QuoteIdentifier(table),
+		strings.Join(quoted, ", "),
+	), nil
+}
+
+// MergeAdjacentFiles returns a DuckLake maintenance call that rewrites a
+// table's small data files into larger ones:
+// CALL ducklake_merge_adjacent_files('<catalog>', '<table>', schema => '<schema>').
+func MergeAdjacentFiles(catalog, schema, table string) (string, error) {
+	if err := ValidateIdentifier(catalog); err != nil {
+		return "", fmt.Errorf("invalid catalog name: %w", err)
+	}
+	if err := ValidateIdentifier(schema); err != nil {
+		return "", fmt.Errorf("invalid schema name: %w", err)
+	}
+	if err := ValidateIdentifier(table); err != nil {
+		return "", fmt.Errorf("invalid table name: %w", err)
+	}
+	return fmt.Sprintf("CALL ducklake_merge_adjacent_files(%s, %s, schema => %s)",
+		QuoteLiteral(catalog),
+		QuoteLiteral(table),
+		QuoteLiteral(schema),
+	), nil
+}
+
+// ExpireSnapshots returns a DuckLake maintenance call that expires every
+// snapshot older than olderThan. Snapshots are catalog-wide in DuckLake.
+func ExpireSnapshots(catalog string, olderThan time.Duration) (string, error) {
+	if err := ValidateIdentifier(catalog); err != nil {
+		return "", fmt.Errorf("invalid catalog name: %w", err)
+	}
+	secs := int64(olderThan / time.Second)
+	if secs <= 0 {
+		return "", fmt.Errorf("snapshot age must be at least one second")
+	}
+	return fmt.Sprintf("CALL ducklake_expire_snapshots(%s, older_than => now() - INTERVAL %s)",
+		QuoteLiteral(catalog),
+		QuoteLiteral(fmt.Sprintf("%d seconds", secs)),
+	), nil
+}
+
+// CleanupOldFiles returns a DuckLake maintenance call that deletes every data
+// file no remaining snapshot references.
+func CleanupOldFiles(catalog string) (string, error) {
+	if err := ValidateIdentifier(catalog); err != nil {
+		return "", fmt.Errorf("invalid catalog name: %w", err)
+	}
+	return fmt.Sprintf("CALL ducklake_cleanup_old_files(%s, cleanup_all => true)", QuoteLiteral(catalog)), nil
+}
+
+// TableAtSnapshot returns a time-travel reference to a DuckLake table as of
+// a snapshot: <catalog>."<schema>"."<table>" AT (VERSION => <snapshot>).
+func TableAtSnapshot(catalog, schema, table string, snapshotID int64) (string, error) {
+	if err := ValidateIdentifier(catalog); err != nil {
+		return "", fmt.Errorf("invalid catalog name: %w", err)
+	}
+	if err := ValidateIdentifier(schema); err != nil {
+		return "", fmt.Errorf("invalid schema name: %w", err)
+	}
+	if err := ValidateIdentifier(table); err != nil {
+		return "", fmt.Errorf("invalid table name: %w", err)
+	}
+	if snapshotID < 0 {
+		return "", fmt.Errorf("snapshot id must not be negative")
+	}
+	return fmt.Sprintf("%s.%s.%s AT (VERSION => %d)",
+		QuoteIdentifier(catalog),
+		QuoteIdentifier(schema),
+		QuoteIdentifier(table),
+		snapshotID,
+	), nil
+}
+
+// RestoreTableFromSnapshot returns the statements that replace a table's
+// rows with its rows as of a snapshot. Run them in one transaction so the
+// restore commits as a single new snapshot.
+func RestoreTableFromSnapshot(catalog, schema, table string, snapshotID int64) ([]string, error) {
+	source, err := TableAtSnapshot(catalog, schema, table, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	target := fmt.Sprintf("%s.%s.%s", QuoteIdentifier(catalog), QuoteIdentifier(schema), QuoteIdentifier(table))
+	return []string{
+		"DELETE FROM " + target,
+		fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", target, source),
+	}, nil
+}
+
+// CreateTableAs returns a DuckDB DDL statement creating a table from a query:
+// CREATE TABLE <catalog>."<schema>"."<table>" AS SELECT * FROM (<query>).
+// The query is embedded as is; callers must have validated it.
+func CreateTableAs(catalog, schema, table, query string) (string, error) {
+	target, err := qualifiedTable(catalog, schema, table)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	// The newlines keep a trailing line comment from swallowing the ")".
+	return fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM (\n%s\n)", target, query), nil
+}
+
+// ReplaceTableContents returns the statements that replace a table's rows
+// with the result of a query, keeping the table itself and so its ID. Run
+// them in one transaction so readers never see the table empty.
+func ReplaceTableContents(catalog, schema, table, query string) ([]string, error) {
+	target, err := qualifiedTable(catalog, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	return []string{
+		"DELETE FROM " + target,
+		fmt.Sprintf("INSERT INTO %s SELECT * FROM (\n%s\n)", target, query),
+	}, nil
+}
+
+func qualifiedTable(catalog, schema, table string) (string, error) {
+	if err := ValidateIdentifier(catalog); err != nil {
+		return "", fmt.Errorf("invalid catalog name: %w", err)
+	}
+	if err := ValidateIdentifier(schema); err != nil {
+		return "", fmt.Errorf("invalid schema name: %w", err)
+	}
+	if err := ValidateIdentifier(table); err != nil {
+		return "", fmt.Errorf("invalid table name: %w", err)
+	}
+	return fmt.Sprintf("%s.%s.%s", QuoteIdentifier(catalog), QuoteIdentifier(schema), QuoteIdentifier(table)), nil
+}
+
 // DropTable returns a DuckDB DDL statement: DROP TABLE <catalog>."<schema>"."<table>".
 func DropTable(catalog, schema, table string) (string, error) {
 	if err := ValidateIdentifier(catalog); err != nil {
@@ -91,6 +252,19 @@ func DropTable(catalog, schema, table string) (string, error) {
 	), nil
 }
 
+// RenameTable returns a DuckDB DDL statement:
+// ALTER TABLE <catalog>."<schema>"."<table>" RENAME TO "<newName>".
+func RenameTable(catalog, schema, table, newName string) (string, error) {
+	qualified, err := qualifiedTable(catalog, schema, table)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateIdentifier(newName); err != nil {
+		return "", fmt.Errorf("invalid new table name: %w", err)
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", qualified, QuoteIdentifier(newName)), nil
+}
+
 // CreateS3Secret returns a DuckDB DDL statement to create an S3 secret.
 func CreateS3Secret(name, keyID, secret, endpoint, region, urlStyle string) (string, error) {
 	if name == "" {
@@ -215,6 +389,19 @@ func DropView(catalog, schema, table string) (string, error) {
 	), nil
 }
 
+// RenameView returns a DuckDB DDL statement:
+// ALTER VIEW <catalog>."<schema>"."<view>" RENAME TO "<newName>".
+func RenameView(catalog, schema, view, newName string) (string, error) {
+	qualified, err := qualifiedTable(catalog, schema, view)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateIdentifier(newName); err != nil {
+		return "", fmt.Errorf("invalid new view name: %w", err)
+	}
+	return fmt.Sprintf("ALTER VIEW %s RENAME TO %s", qualified, QuoteIdentifier(newName)), nil
+}
+
 // DiscoverColumnsSQL generates a DESCRIBE statement to discover column metadata
 // from a Parquet or CSV file.
 func DiscoverColumnsSQL(sourcePath, fileFormat string) (string, error) {
@@ -238,6 +425,15 @@ func DiscoverColumnsSQL(sourcePath, fileFormat string) (string, error) {
 	), nil
 }
 
+// GlobFilesSQL generates a query listing the files that match a glob pattern,
+// in path order. Remote patterns are read with the DuckDB secret in scope.
+func GlobFilesSQL(pattern string) (string, error) {
+	if pattern == "" {
+		return "", fmt.Errorf("glob pattern is required")
+	}
+	return fmt.Sprintf("SELECT file FROM glob(%s) ORDER BY file", QuoteLiteral(pattern)), nil
+}
+
 // AttachDuckLakePostgres returns a DuckDB DDL statement to attach a DuckLake catalog
 // using a PostgreSQL metastore instead of SQLite.
 func AttachDuckLakePostgres(catalogName, dsn, dataPath string) (string, error) {
@@ -2,6 +2,7 @@ package ddl
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -207,6 +208,48 @@ func TestCreateTable(t *testing.T) {
 	}
 }
 
+func TestSetPartitionedBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "single_column",
+			columns: []string{"region"},
+			want:    `ALTER TABLE "lake"."analytics"."events" SET PARTITIONED BY ("region")`,
+		},
+		{
+			name:    "multiple_columns",
+			columns: []string{"region", "day"},
+			want:    `ALTER TABLE "lake"."analytics"."events" SET PARTITIONED BY ("region", "day")`,
+		},
+		{
+			name:    "no_columns",
+			wantErr: "at least one partition column is required",
+		},
+		{
+			name:    "invalid_column",
+			columns: []string{"region; DROP TABLE x"},
+			wantErr: "invalid partition column",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetPartitionedBy("lake", "analytics", "events", tt.columns)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestDropTable(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -253,6 +296,46 @@ func TestDropTable(t *testing.T) {
 	}
 }
 
+func TestCreateTableAs(t *testing.T) {
+	got, err := CreateTableAs("lake", "analytics", "daily", "SELECT 1 AS n -- one")
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE \"lake\".\"analytics\".\"daily\" AS SELECT * FROM (\nSELECT 1 AS n -- one\n)", got)
+
+	_, err = CreateTableAs("lake", "analytics", "bad name;", "SELECT 1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid table name")
+
+	_, err = CreateTableAs("lake", "analytics", "daily", "  ")
+	require.Error(t, err)
+}
+
+func TestReplaceTableContents(t *testing.T) {
+	got, err := ReplaceTableContents("lake", "analytics", "daily", "SELECT 1 AS n")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`DELETE FROM "lake"."analytics"."daily"`,
+		"INSERT INTO \"lake\".\"analytics\".\"daily\" SELECT * FROM (\nSELECT 1 AS n\n)",
+	}, got)
+
+	_, err = ReplaceTableContents("", "analytics", "daily", "SELECT 1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid catalog name")
+}
+
+func TestRenameTable(t *testing.T) {
+	got, err := RenameTable("lake", "analytics", "events", "events_v2")
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER TABLE "lake"."analytics"."events" RENAME TO "events_v2"`, got)
+
+	_, err = RenameTable("lake", "analytics", "events", "bad name;")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid new table name")
+
+	got, err = RenameView("lake", "analytics", "ext", "ext_v2")
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER VIEW "lake"."analytics"."ext" RENAME TO "ext_v2"`, got)
+}
+
 func TestCreateS3Secret(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -640,6 +723,19 @@ func TestDiscoverColumnsSQL(t *testing.T) {
 	}
 }
 
+func TestGlobFilesSQL(t *testing.T) {
+	got, err := GlobFilesSQL("s3://bucket/landing/*.parquet")
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT file FROM glob('s3://bucket/landing/*.parquet') ORDER BY file`, got)
+
+	got, err = GlobFilesSQL("s3://bucket/it's/*.csv")
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT file FROM glob('s3://bucket/it''s/*.csv') ORDER BY file`, got)
+
+	_, err = GlobFilesSQL("")
+	require.Error(t, err)
+}
+
 func TestAttachDuckLake(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -784,3 +880,75 @@ func TestSetDefaultCatalog(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeAdjacentFiles(t *testing.T) {
+	got, err := MergeAdjacentFiles("lake", "analytics", "events")
+	require.NoError(t, err)
+	assert.Equal(t, `CALL ducklake_merge_adjacent_files('lake', 'events', schema => 'analytics')`, got)
+
+	_, err = MergeAdjacentFiles("lake", "analytics", "events'); DROP TABLE x; --")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid table name")
+}
+
+func TestExpireSnapshots(t *testing.T) {
+	tests := []struct {
+		name      string
+		olderThan time.Duration
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "seven_days",
+			olderThan: 7 * 24 * time.Hour,
+			want:      `CALL ducklake_expire_snapshots('lake', older_than => now() - INTERVAL '604800 seconds')`,
+		},
+		{
+			name:      "sub_second",
+			olderThan: time.Millisecond,
+			wantErr:   "at least one second",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpireSnapshots("lake", tt.olderThan)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCleanupOldFiles(t *testing.T) {
+	got, err := CleanupOldFiles("lake")
+	require.NoError(t, err)
+	assert.Equal(t, `CALL ducklake_cleanup_old_files('lake', cleanup_all => true)`, got)
+}
+
+func TestTableAtSnapshot(t *testing.T) {
+	got, err := TableAtSnapshot("lake", "analytics", "events", 12)
+	require.NoError(t, err)
+	assert.Equal(t, `"lake"."analytics"."events" AT (VERSION => 12)`, got)
+
+	_, err = TableAtSnapshot("lake", "analytics", "events", -1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be negative")
+}
+
+func TestRestoreTableFromSnapshot(t *testing.T) {
+	got, err := RestoreTableFromSnapshot("lake", "analytics", "events", 12)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`DELETE FROM "lake"."analytics"."events"`,
+		`INSERT INTO "lake"."analytics"."events" SELECT * FROM "lake"."analytics"."events" AT (VERSION => 12)`,
+	}, got)
+
+	_, err = RestoreTableFromSnapshot("lake", "analytics", "bad;name", 12)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid table name")
+}
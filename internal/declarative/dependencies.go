@@ -0,0 +1,227 @@
+package declarative
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"duck-demo/internal/duckdbsql"
+)
+
+// modelRefPattern matches ref('name') and ref("name") in model SQL.
+var modelRefPattern = regexp.MustCompile(`\bref\(\s*['"]([^'"]+)['"]\s*\)`)
+
+// ActionDependencies returns, for each action, the indices of the actions that
+// must finish before it starts. Actions in one dependency layer can still
+// depend on each other: a pipeline job needs its pipeline, a view the tables
+// and views its SQL reads, and a model the models it ref()s. Deletes run the
+// other way round, so a pipeline is deleted after its jobs. Only edges between
+// actions in the slice and of the same kind of operation (delete or not) are
+// reported, and the result never has a cycle: where the references form one,
+// an action only waits for actions earlier in the slice.
+func ActionDependencies(actions []Action) [][]int {
+	type actionKey struct {
+		kind   ResourceKind
+		name   string
+		delete bool
+	}
+	index := make(map[actionKey]int, len(actions))
+	for i, a := range actions {
+		index[actionKey{a.ResourceKind, a.ResourceName, a.Operation == OpDelete}] = i
+	}
+
+	deps := make([][]int, len(actions))
+	for i, a := range actions {
+		isDelete := a.Operation == OpDelete
+		for _, p := range actionPrerequisites(a) {
+			j, ok := index[actionKey{p.kind, p.name, isDelete}]
+			if !ok || j == i {
+				continue
+			}
+			if isDelete {
+				deps[j] = append(deps[j], i)
+			} else {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+	for i := range deps {
+		deps[i] = dedupeSorted(deps[i])
+	}
+	breakDependencyCycles(deps)
+	return deps
+}
+
+// DependencyOrder returns members, a subset of the indices deps describes,
+// ordered so that each comes after the members it depends on and otherwise in
+// their original order. deps must be acyclic, as ActionDependencies returns.
+func DependencyOrder(deps [][]int, members []int) []int {
+	in := make(map[int]bool, len(members))
+	for _, m := range members {
+		in[m] = true
+	}
+	placed := make(map[int]bool, len(members))
+	order := make([]int, 0, len(members))
+	for len(order) < len(members) {
+		for _, m := range members {
+			if placed[m] {
+				continue
+			}
+			ready := true
+			for _, d := range deps[m] {
+				if in[d] && !placed[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				placed[m] = true
+				order = append(order, m)
+				break
+			}
+		}
+	}
+	return order
+}
+
+// resourceRef names a resource an action refers to.
+type resourceRef struct {
+	kind ResourceKind
+	name string
+}
+
+// actionPrerequisites returns the resources that must exist before a's
+// resource can be created, read from its desired spec, or for a delete from
+// its actual one.
+func actionPrerequisites(a Action) []resourceRef {
+	spec := a.Desired
+	if a.Operation == OpDelete {
+		spec = a.Actual
+	}
+	switch a.ResourceKind {
+	case KindPipelineJob:
+		if pipeline, _, ok := strings.Cut(a.ResourceName, "/"); ok {
+			return []resourceRef{{KindPipeline, pipeline}}
+		}
+	case KindView:
+		if vw, ok := spec.(ViewResource); ok {
+			return viewPrerequisites(vw)
+		}
+	case KindModel:
+		if m, ok := spec.(ModelResource); ok {
+			return modelPrerequisites(m)
+		}
+	}
+	return nil
+}
+
+// viewPrerequisites returns the tables and views a view reads. Unqualified
+// names resolve against the view's own catalog and schema. A definition that
+// does not parse yields no references.
+func viewPrerequisites(vw ViewResource) []resourceRef {
+	stmt, err := duckdbsql.Parse(vw.Spec.ViewDefinition)
+	if err != nil {
+		return nil
+	}
+	var refs []resourceRef
+	for _, t := range duckdbsql.CollectTableRefs(stmt) {
+		catalog, schema := t.Catalog, t.Schema
+		if schema == "" {
+			schema = vw.SchemaName
+		}
+		if catalog == "" {
+			catalog = vw.CatalogName
+		}
+		name := tableKey(catalog, schema, t.Name)
+		refs = append(refs, resourceRef{KindTable, name}, resourceRef{KindView, name})
+	}
+	return refs
+}
+
+// modelPrerequisites returns the models a model ref()s. Unqualified names
+// resolve within the model's project.
+func modelPrerequisites(m ModelResource) []resourceRef {
+	var refs []resourceRef
+	for _, match := range modelRefPattern.FindAllStringSubmatch(m.Spec.SQL, -1) {
+		name := match[1]
+		if !strings.Contains(name, ".") {
+			name = modelKey(m.ProjectName, name)
+		}
+		refs = append(refs, resourceRef{KindModel, name})
+	}
+	return refs
+}
+
+func dedupeSorted(ids []int) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Ints(ids)
+	out := ids[:1]
+	for _, id := range ids[1:] {
+		if id != out[len(out)-1] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// breakDependencyCycles drops the edges that point to a later action within
+// each cycle, found as the strongly connected components of the graph. Edges
+// that only point backwards cannot form a cycle, and edges between components
+// are kept.
+func breakDependencyCycles(deps [][]int) {
+	component := make([]int, len(deps))
+	index := make([]int, len(deps))
+	low := make([]int, len(deps))
+	onStack := make([]bool, len(deps))
+	for i := range index {
+		index[i] = -1
+	}
+	var stack []int
+	next, components := 0, 0
+	var visit func(v int)
+	visit = func(v int) {
+		index[v], low[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range deps[v] {
+			switch {
+			case index[w] < 0:
+				visit(w)
+				low[v] = min(low[v], low[w])
+			case onStack[w]:
+				low[v] = min(low[v], index[w])
+			}
+		}
+		if low[v] != index[v] {
+			return
+		}
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component[w] = components
+			if w == v {
+				break
+			}
+		}
+		components++
+	}
+	for v := range deps {
+		if index[v] < 0 {
+			visit(v)
+		}
+	}
+
+	for i, ds := range deps {
+		kept := ds[:0]
+		for _, d := range ds {
+			if d < i || component[d] != component[i] {
+				kept = append(kept, d)
+			}
+		}
+		deps[i] = kept
+	}
+}
@@ -0,0 +1,102 @@
+package declarative
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionDependencies(t *testing.T) {
+	view := func(name, sql string) ViewResource {
+		return ViewResource{CatalogName: "lake", SchemaName: "sales", ViewName: name, Spec: ViewSpec{ViewDefinition: sql}}
+	}
+	model := func(name, sql string) ModelResource {
+		return ModelResource{ProjectName: "shop", ModelName: name, Spec: ModelSpec{SQL: sql}}
+	}
+
+	t.Run("pipeline job waits for its pipeline", func(t *testing.T) {
+		actions := []Action{
+			{Operation: OpCreate, ResourceKind: KindPipeline, ResourceName: "etl"},
+			{Operation: OpCreate, ResourceKind: KindPipelineJob, ResourceName: "etl/load"},
+			{Operation: OpCreate, ResourceKind: KindPipeline, ResourceName: "other"},
+		}
+		assert.Equal(t, [][]int{nil, {0}, nil}, ActionDependencies(actions))
+	})
+
+	t.Run("view waits for the tables and views it reads", func(t *testing.T) {
+		actions := []Action{
+			{Operation: OpCreate, ResourceKind: KindView, ResourceName: "lake.sales.a_view", Desired: view("a_view", "SELECT * FROM orders JOIN lake.sales.b_view USING (id)")},
+			{Operation: OpCreate, ResourceKind: KindView, ResourceName: "lake.sales.b_view", Desired: view("b_view", "SELECT id FROM sales.orders")},
+			{Operation: OpCreate, ResourceKind: KindTable, ResourceName: "lake.sales.orders"},
+			{Operation: OpCreate, ResourceKind: KindTable, ResourceName: "lake.sales.unrelated"},
+		}
+		deps := ActionDependencies(actions)
+		assert.Equal(t, []int{1, 2}, deps[0])
+		assert.Equal(t, []int{2}, deps[1])
+		assert.Empty(t, deps[2])
+		assert.Empty(t, deps[3])
+	})
+
+	t.Run("model waits for the models it refs", func(t *testing.T) {
+		actions := []Action{
+			{Operation: OpCreate, ResourceKind: KindModel, ResourceName: "shop.orders_daily", Desired: model("orders_daily", "SELECT * FROM {{ ref('stg_orders') }}")},
+			{Operation: OpUpdate, ResourceKind: KindModel, ResourceName: "shop.stg_orders", Desired: model("stg_orders", "SELECT * FROM {{ source('raw', 'orders') }}")},
+		}
+		assert.Equal(t, [][]int{{1}, nil}, ActionDependencies(actions))
+	})
+
+	t.Run("deletes run in reverse", func(t *testing.T) {
+		actions := []Action{
+			{Operation: OpDelete, ResourceKind: KindPipeline, ResourceName: "etl"},
+			{Operation: OpDelete, ResourceKind: KindPipelineJob, ResourceName: "etl/load"},
+		}
+		assert.Equal(t, [][]int{{1}, nil}, ActionDependencies(actions))
+	})
+
+	t.Run("creates do not wait for deletes", func(t *testing.T) {
+		actions := []Action{
+			{Operation: OpCreate, ResourceKind: KindPipelineJob, ResourceName: "etl/load"},
+			{Operation: OpDelete, ResourceKind: KindPipeline, ResourceName: "etl"},
+		}
+		assert.Equal(t, [][]int{nil, nil}, ActionDependencies(actions))
+	})
+
+	t.Run("reference cycles are broken in plan order", func(t *testing.T) {
+		actions := []Action{
+			{Operation: OpCreate, ResourceKind: KindModel, ResourceName: "shop.a", Desired: model("a", "SELECT * FROM {{ ref('b') }}")},
+			{Operation: OpCreate, ResourceKind: KindModel, ResourceName: "shop.b", Desired: model("b", "SELECT * FROM {{ ref('a') }}")},
+			{Operation: OpCreate, ResourceKind: KindModel, ResourceName: "shop.c", Desired: model("c", "SELECT * FROM {{ ref('a') }}")},
+		}
+		deps := ActionDependencies(actions)
+		assert.Empty(t, deps[0])
+		assert.Equal(t, []int{0}, deps[1])
+		assert.Equal(t, []int{0}, deps[2], "edges leaving the cycle are kept")
+	})
+}
+
+func TestDependencyOrder(t *testing.T) {
+	deps := [][]int{{2}, nil, nil, {0}}
+	assert.Equal(t, []int{1, 2, 0, 3}, DependencyOrder(deps, []int{0, 1, 2, 3}))
+	// Dependencies outside members do not hold members back.
+	assert.Equal(t, []int{0, 3}, DependencyOrder(deps, []int{0, 3}))
+}
+
+func TestPlanTiers_OrdersByDependencies(t *testing.T) {
+	plan := &Plan{Actions: []Action{
+		{Operation: OpCreate, ResourceKind: KindTable, ResourceName: "lake.sales.orders"},
+		{Operation: OpCreate, ResourceKind: KindView, ResourceName: "lake.sales.a_view", Desired: ViewResource{
+			CatalogName: "lake", SchemaName: "sales", ViewName: "a_view", Spec: ViewSpec{ViewDefinition: "SELECT * FROM z_view"},
+		}},
+		{Operation: OpCreate, ResourceKind: KindView, ResourceName: "lake.sales.z_view", Desired: ViewResource{
+			CatalogName: "lake", SchemaName: "sales", ViewName: "z_view", Spec: ViewSpec{ViewDefinition: "SELECT * FROM orders"},
+		}},
+	}}
+	tiers := plan.Tiers()
+	require.Len(t, tiers, 1)
+	var names []string
+	for _, a := range tiers[0].Actions {
+		names = append(names, a.ResourceName)
+	}
+	assert.Equal(t, []string{"lake.sales.orders", "lake.sales.z_view", "lake.sales.a_view"}, names)
+}
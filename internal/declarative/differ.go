@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 // Diff compares the desired state (from YAML) against the actual state (from server)
@@ -117,6 +118,21 @@ func diffStringPtrField(changes *[]FieldDiff, field string, oldVal, newVal *stri
 	diffField(changes, field, oldStr, newStr)
 }
 
+// normalizeTimestamp renders an RFC3339 timestamp in UTC so equal instants
+// written with different offsets compare equal. Unparseable values are
+// returned unchanged for the validator to report.
+func normalizeTimestamp(ts *string) *string {
+	if ts == nil {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, *ts)
+	if err != nil {
+		return ts
+	}
+	out := t.UTC().Format(time.RFC3339)
+	return &out
+}
+
 func diffMapField(changes *[]FieldDiff, field string, oldVal, newVal map[string]string) {
 	diffField(changes, field, formatMap(oldVal), formatMap(newVal))
 }
@@ -161,8 +177,10 @@ func diffPrincipals(plan *Plan, desired, actual []PrincipalSpec) {
 		}
 		var changes []FieldDiff
 		// Note: principal type is immutable (no API endpoint supports changing it),
-		// so we only diff is_admin which can be toggled via PUT /principals/{id}/admin.
+		// so we only diff is_admin which can be toggled via PUT /principals/{id}/admin,
+		// and attributes, replaced via PUT /principals/{id}/attributes.
 		diffBoolField(&changes, "is_admin", a.IsAdmin, d.IsAdmin)
+		diffMapField(&changes, "attributes", a.Attributes, d.Attributes)
 		if len(changes) > 0 {
 			addUpdate(plan, KindPrincipal, d.Name, "", d, a, changes)
 		}
@@ -258,9 +276,16 @@ func diffGrants(plan *Plan, desired, actual []GrantSpec) {
 	for _, d := range desired {
 		k := grantIdentityKey(d)
 		seen[k] = true
-		if _, exists := actualMap[k]; !exists {
-			name := fmt.Sprintf("%s:%s on %s.%s", d.PrincipalType, d.Principal, d.SecurableType, d.Securable)
+		name := fmt.Sprintf("%s:%s on %s.%s", d.PrincipalType, d.Principal, d.SecurableType, d.Securable)
+		a, exists := actualMap[k]
+		if !exists {
 			addCreate(plan, KindPrivilegeGrant, name, "", d)
+			continue
+		}
+		var changes []FieldDiff
+		diffStringPtrField(&changes, "expires_at", normalizeTimestamp(a.ExpiresAt), normalizeTimestamp(d.ExpiresAt))
+		if len(changes) > 0 {
+			addUpdate(plan, KindPrivilegeGrant, name, "", d, a, changes)
 		}
 	}
 
@@ -402,7 +427,8 @@ func diffTables(plan *Plan, desired, actual []TableResource) {
 }
 
 // diffTableSpec compares two TableSpecs and returns field diffs plus a flag
-// indicating whether a column type change error was emitted.
+// indicating whether an unsupported change (a column type or partitioning
+// change) was reported as a plan error.
 func diffTableSpec(plan *Plan, tableName string, actual, desired TableSpec) ([]FieldDiff, bool) {
 	var changes []FieldDiff
 	diffField(&changes, "table_type", actual.TableType, desired.TableType)
@@ -415,6 +441,14 @@ func diffTableSpec(plan *Plan, tableName string, actual, desired TableSpec) ([]F
 
 	// Column-level diff.
 	hasTypeError := diffColumns(plan, tableName, &changes, actual.Columns, desired.Columns)
+
+	// Partitioning is fixed at creation; an omitted partition_by leaves it unmanaged.
+	if len(desired.PartitionBy) > 0 && strings.Join(actual.PartitionBy, ",") != strings.Join(desired.PartitionBy, ",") {
+		addError(plan, KindTable, tableName,
+			fmt.Sprintf("cannot change partition_by from [%s] to [%s]; recreate the table",
+				strings.Join(actual.PartitionBy, ", "), strings.Join(desired.PartitionBy, ", ")))
+		hasTypeError = true
+	}
 	return changes, hasTypeError
 }
 
@@ -422,51 +456,84 @@ func diffTableSpec(plan *Plan, tableName string, actual, desired TableSpec) ([]F
 // and comment changes. Column type changes produce a PlanError. Returns true if a
 // column type error was emitted.
 func diffColumns(plan *Plan, tableName string, changes *[]FieldDiff, actual, desired []ColumnDef) bool {
+	hasTypeError := false
+	for _, c := range DiffColumns(actual, desired) {
+		switch c.Change {
+		case ColumnAdded:
+			*changes = append(*changes, FieldDiff{
+				Field:    fmt.Sprintf("columns.%s", c.Name),
+				OldValue: "",
+				NewValue: fmt.Sprintf("%s %s", c.Name, c.NewType),
+			})
+		case ColumnRemoved:
+			*changes = append(*changes, FieldDiff{
+				Field:    fmt.Sprintf("columns.%s", c.Name),
+				OldValue: fmt.Sprintf("%s %s", c.Name, c.OldType),
+				NewValue: "",
+			})
+		case ColumnRetyped:
+			addError(plan, KindTable, tableName,
+				fmt.Sprintf("column %q: cannot change type from %q to %q", c.Name, c.OldType, c.NewType))
+			hasTypeError = true
+		case ColumnCommentChanged:
+			*changes = append(*changes, FieldDiff{
+				Field:    fmt.Sprintf("columns.%s.comment", c.Name),
+				OldValue: c.OldComment,
+				NewValue: c.NewComment,
+			})
+		}
+	}
+	return hasTypeError
+}
+
+// Column change kinds reported by DiffColumns.
+const (
+	ColumnAdded          = "added"
+	ColumnRemoved        = "removed"
+	ColumnRetyped        = "retyped"
+	ColumnCommentChanged = "comment"
+)
+
+// ColumnChange describes how a single column differs between two column lists.
+type ColumnChange struct {
+	Name       string `json:"name"`
+	Change     string `json:"change"`
+	OldType    string `json:"old_type,omitempty"`
+	NewType    string `json:"new_type,omitempty"`
+	OldComment string `json:"old_comment,omitempty"`
+	NewComment string `json:"new_comment,omitempty"`
+}
+
+// DiffColumns compares an actual column list against a desired one. Changes
+// are listed in desired order, followed by columns that only exist in actual.
+// A retyped column is not also reported as a comment change.
+func DiffColumns(actual, desired []ColumnDef) []ColumnChange {
 	actualMap := make(map[string]ColumnDef, len(actual))
 	for _, c := range actual {
 		actualMap[c.Name] = c
 	}
 
-	hasTypeError := false
+	var changes []ColumnChange
 	seen := make(map[string]bool, len(desired))
-
 	for _, dc := range desired {
 		seen[dc.Name] = true
 		ac, exists := actualMap[dc.Name]
-		if !exists {
-			*changes = append(*changes, FieldDiff{
-				Field:    fmt.Sprintf("columns.%s", dc.Name),
-				OldValue: "",
-				NewValue: fmt.Sprintf("%s %s", dc.Name, dc.Type),
-			})
-			continue
-		}
-		if ac.Type != dc.Type {
-			addError(plan, KindTable, tableName,
-				fmt.Sprintf("column %q: cannot change type from %q to %q", dc.Name, ac.Type, dc.Type))
-			hasTypeError = true
-			continue
-		}
-		if ac.Comment != dc.Comment {
-			*changes = append(*changes, FieldDiff{
-				Field:    fmt.Sprintf("columns.%s.comment", dc.Name),
-				OldValue: ac.Comment,
-				NewValue: dc.Comment,
-			})
+		switch {
+		case !exists:
+			changes = append(changes, ColumnChange{Name: dc.Name, Change: ColumnAdded, NewType: dc.Type, NewComment: dc.Comment})
+		case ac.Type != dc.Type:
+			changes = append(changes, ColumnChange{Name: dc.Name, Change: ColumnRetyped, OldType: ac.Type, NewType: dc.Type})
+		case ac.Comment != dc.Comment:
+			changes = append(changes, ColumnChange{Name: dc.Name, Change: ColumnCommentChanged, OldComment: ac.Comment, NewComment: dc.Comment})
 		}
 	}
 
 	for _, ac := range actual {
 		if !seen[ac.Name] {
-			*changes = append(*changes, FieldDiff{
-				Field:    fmt.Sprintf("columns.%s", ac.Name),
-				OldValue: fmt.Sprintf("%s %s", ac.Name, ac.Type),
-				NewValue: "",
-			})
+			changes = append(changes, ColumnChange{Name: ac.Name, Change: ColumnRemoved, OldType: ac.Type, OldComment: ac.Comment})
 		}
 	}
-
-	return hasTypeError
+	return changes
 }
 
 // === Views ===
@@ -491,6 +558,13 @@ func diffViews(plan *Plan, desired, actual []ViewResource) {
 			addCreate(plan, KindView, name, "", d)
 			continue
 		}
+		// Materialization is fixed at creation.
+		if a.Spec.Materialized != d.Spec.Materialized || a.Spec.RefreshSchedule != d.Spec.RefreshSchedule {
+			addError(plan, KindView, name,
+				fmt.Sprintf("cannot change materialized or refresh_schedule from (%t, %q) to (%t, %q); recreate the view",
+					a.Spec.Materialized, a.Spec.RefreshSchedule, d.Spec.Materialized, d.Spec.RefreshSchedule))
+			continue
+		}
 		var changes []FieldDiff
 		diffField(&changes, "view_definition", a.Spec.ViewDefinition, d.Spec.ViewDefinition)
 		diffField(&changes, "comment", a.Spec.Comment, d.Spec.Comment)
@@ -847,7 +921,7 @@ func diffStorageCredentialDetails(changes *[]FieldDiff, actual, desired StorageC
 	if actual.S3 != nil && desired.S3 != nil {
 		diffField(changes, "s3.endpoint", actual.S3.Endpoint, desired.S3.Endpoint)
 		diffField(changes, "s3.region", actual.S3.Region, desired.S3.Region)
-		diffField(changes, "s3.url_style", actual.S3.URLStyle, desired.S3.URLStyle)
+		diffField(changes, "s3.url_style", s3URLStyle(actual.S3.URLStyle), s3URLStyle(desired.S3.URLStyle))
 	} else if actual.S3 != desired.S3 {
 		// One is nil, the other is not — credential sub-type changed.
 		oldVal := ""
@@ -892,6 +966,43 @@ func diffStorageCredentialDetails(changes *[]FieldDiff, actual, desired StorageC
 	}
 }
 
+// s3URLStyle returns the URL style the server stores for a credential that
+// does not set one.
+func s3URLStyle(style string) string {
+	if style == "" {
+		return "path"
+	}
+	return style
+}
+
+// RotateStorageCredentials adds an update of every desired storage credential
+// that already exists on the server and has no update planned, so applying
+// the plan re-sends the credentials' secrets from their environment
+// variables. The server never returns secrets, so Diff cannot tell that they
+// changed.
+func RotateStorageCredentials(plan *Plan, desired, actual []StorageCredentialSpec) {
+	planned := make(map[string]bool)
+	for _, a := range plan.Actions {
+		if a.ResourceKind == KindStorageCredential {
+			planned[a.ResourceName] = true
+		}
+	}
+	actualMap := make(map[string]StorageCredentialSpec, len(actual))
+	for _, a := range actual {
+		actualMap[a.Name] = a
+	}
+	for _, d := range desired {
+		a, exists := actualMap[d.Name]
+		if !exists || planned[d.Name] {
+			continue
+		}
+		addUpdate(plan, KindStorageCredential, d.Name, "", d, a, []FieldDiff{
+			{Field: "secrets", OldValue: "(write-only)", NewValue: "(rotated)"},
+		})
+	}
+	plan.SortActions()
+}
+
 // === External Locations ===
 
 func diffExternalLocations(plan *Plan, desired, actual []ExternalLocationSpec) {
@@ -911,7 +1022,7 @@ func diffExternalLocations(plan *Plan, desired, actual []ExternalLocationSpec) {
 		var changes []FieldDiff
 		diffField(&changes, "url", a.URL, d.URL)
 		diffField(&changes, "credential_name", a.CredentialName, d.CredentialName)
-		diffField(&changes, "storage_type", a.StorageType, d.StorageType)
+		diffField(&changes, "storage_type", locationStorageType(a.StorageType), locationStorageType(d.StorageType))
 		diffField(&changes, "comment", a.Comment, d.Comment)
 		diffBoolField(&changes, "read_only", a.ReadOnly, d.ReadOnly)
 		if len(changes) > 0 {
@@ -926,6 +1037,15 @@ func diffExternalLocations(plan *Plan, desired, actual []ExternalLocationSpec) {
 	}
 }
 
+// locationStorageType returns the storage type the server stores for an
+// external location that does not set one.
+func locationStorageType(storageType string) string {
+	if storageType == "" {
+		return "S3"
+	}
+	return storageType
+}
+
 // === Compute Endpoints ===
 
 func diffComputeEndpoints(plan *Plan, desired, actual []ComputeEndpointSpec) {
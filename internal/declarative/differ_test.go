@@ -56,6 +56,24 @@ func TestDiff_UpdatePrincipalAdmin(t *testing.T) {
 	assert.NotEmpty(t, plan.Actions[0].Changes)
 }
 
+func TestDiff_UpdatePrincipalAttributes(t *testing.T) {
+	desired := &DesiredState{
+		Principals: []PrincipalSpec{{Name: "user1", Type: "user", Attributes: map[string]string{"region": "EU"}}},
+	}
+	actual := &DesiredState{
+		Principals: []PrincipalSpec{{Name: "user1", Type: "user", Attributes: map[string]string{"region": "US"}}},
+	}
+
+	plan := Diff(desired, actual)
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, OpUpdate, plan.Actions[0].Operation)
+	assert.True(t, plan.Actions[0].HasFieldChange("attributes"))
+	assert.False(t, plan.Actions[0].HasFieldChange("is_admin"))
+
+	// Matching attributes, in any map order, produce no change.
+	assert.False(t, Diff(desired, desired).HasChanges())
+}
+
 func TestDiff_NoPrincipalChanges(t *testing.T) {
 	state := &DesiredState{
 		Principals: []PrincipalSpec{{Name: "user1", Type: "user", IsAdmin: false}},
@@ -101,6 +119,40 @@ func TestDiff_CreateAndDeleteGrants(t *testing.T) {
 	assert.Equal(t, 1, ops[OpDelete])
 }
 
+func TestDiff_GrantExpiry(t *testing.T) {
+	grant := func(expiresAt *string) GrantSpec {
+		return GrantSpec{Principal: "user1", PrincipalType: "user", SecurableType: "catalog", Securable: "main", Privilege: "USAGE", ExpiresAt: expiresAt}
+	}
+
+	t.Run("changed expiry is an update", func(t *testing.T) {
+		plan := Diff(
+			&DesiredState{Grants: []GrantSpec{grant(strPtr("2030-01-02T00:00:00Z"))}},
+			&DesiredState{Grants: []GrantSpec{grant(strPtr("2030-01-01T00:00:00Z"))}},
+		)
+		require.Len(t, plan.Actions, 1)
+		assert.Equal(t, OpUpdate, plan.Actions[0].Operation)
+		require.Len(t, plan.Actions[0].Changes, 1)
+		assert.Equal(t, "expires_at", plan.Actions[0].Changes[0].Field)
+	})
+
+	t.Run("removing expiry is an update", func(t *testing.T) {
+		plan := Diff(
+			&DesiredState{Grants: []GrantSpec{grant(nil)}},
+			&DesiredState{Grants: []GrantSpec{grant(strPtr("2030-01-01T00:00:00Z"))}},
+		)
+		require.Len(t, plan.Actions, 1)
+		assert.Equal(t, OpUpdate, plan.Actions[0].Operation)
+	})
+
+	t.Run("same instant in another offset is unchanged", func(t *testing.T) {
+		plan := Diff(
+			&DesiredState{Grants: []GrantSpec{grant(strPtr("2030-01-01T02:00:00+02:00"))}},
+			&DesiredState{Grants: []GrantSpec{grant(strPtr("2030-01-01T00:00:00Z"))}},
+		)
+		assert.Empty(t, plan.Actions)
+	})
+}
+
 func TestDiff_ExpandsBindingPresetsToGrants(t *testing.T) {
 	desired := &DesiredState{
 		Principals: []PrincipalSpec{{Name: "user1", Type: "user"}},
@@ -300,6 +352,33 @@ func TestDiff_TableColumnDiff(t *testing.T) {
 	}
 }
 
+func TestDiff_TablePartitionBy(t *testing.T) {
+	cols := []ColumnDef{{Name: "id", Type: "BIGINT"}, {Name: "region", Type: "VARCHAR"}}
+	table := func(partitionBy ...string) []TableResource {
+		return []TableResource{{CatalogName: "c", SchemaName: "s", TableName: "t",
+			Spec: TableSpec{TableType: "MANAGED", Columns: cols, PartitionBy: partitionBy}}}
+	}
+
+	t.Run("same partitioning is a no-op", func(t *testing.T) {
+		plan := Diff(&DesiredState{Tables: table("region")}, &DesiredState{Tables: table("region")})
+		assert.Empty(t, plan.Errors)
+		assert.Empty(t, plan.Actions)
+	})
+
+	t.Run("omitted partition_by leaves partitioning unmanaged", func(t *testing.T) {
+		plan := Diff(&DesiredState{Tables: table()}, &DesiredState{Tables: table("region")})
+		assert.Empty(t, plan.Errors)
+		assert.Empty(t, plan.Actions)
+	})
+
+	t.Run("changed partitioning is a plan error", func(t *testing.T) {
+		plan := Diff(&DesiredState{Tables: table("region")}, &DesiredState{Tables: table()})
+		require.Len(t, plan.Errors, 1)
+		assert.Contains(t, plan.Errors[0].Message, "cannot change partition_by from [] to [region]")
+		assert.Empty(t, plan.Actions)
+	})
+}
+
 func TestDiff_SchemaUpdate(t *testing.T) {
 	desired := &DesiredState{
 		Schemas: []SchemaResource{
@@ -374,6 +453,25 @@ func TestDiff_Views(t *testing.T) {
 		assert.True(t, found, "expected view_definition field diff")
 	})
 
+	t.Run("materialization change is a plan error", func(t *testing.T) {
+		desired := &DesiredState{
+			Views: []ViewResource{
+				{CatalogName: "c", SchemaName: "s", ViewName: "v1",
+					Spec: ViewSpec{ViewDefinition: "SELECT 1", Materialized: true}},
+			},
+		}
+		actual := &DesiredState{
+			Views: []ViewResource{
+				{CatalogName: "c", SchemaName: "s", ViewName: "v1",
+					Spec: ViewSpec{ViewDefinition: "SELECT 1"}},
+			},
+		}
+		plan := Diff(desired, actual)
+		assert.Empty(t, plan.Actions)
+		require.Len(t, plan.Errors, 1)
+		assert.Contains(t, plan.Errors[0].Message, "recreate the view")
+	})
+
 	t.Run("delete view", func(t *testing.T) {
 		desired := &DesiredState{}
 		actual := &DesiredState{
@@ -582,6 +680,28 @@ func TestDiff_RowFilterBindings(t *testing.T) {
 	})
 }
 
+func TestDiffColumns(t *testing.T) {
+	actual := []ColumnDef{
+		{Name: "id", Type: "BIGINT"},
+		{Name: "amount", Type: "INTEGER", Comment: "cents"},
+		{Name: "legacy", Type: "VARCHAR"},
+	}
+	desired := []ColumnDef{
+		{Name: "id", Type: "BIGINT", Comment: "primary key"},
+		{Name: "amount", Type: "DECIMAL(18,2)", Comment: "dollars"},
+		{Name: "created_at", Type: "TIMESTAMP"},
+	}
+
+	assert.Equal(t, []ColumnChange{
+		{Name: "id", Change: ColumnCommentChanged, NewComment: "primary key"},
+		{Name: "amount", Change: ColumnRetyped, OldType: "INTEGER", NewType: "DECIMAL(18,2)"},
+		{Name: "created_at", Change: ColumnAdded, NewType: "TIMESTAMP"},
+		{Name: "legacy", Change: ColumnRemoved, OldType: "VARCHAR"},
+	}, DiffColumns(actual, desired))
+
+	assert.Empty(t, DiffColumns(actual, actual))
+}
+
 func TestDiff_ColumnMasks(t *testing.T) {
 	t.Parallel()
 	t.Run("create column mask", func(t *testing.T) {
@@ -1169,6 +1289,62 @@ func TestDiff_Pipelines(t *testing.T) {
 	})
 }
 
+func TestDiff_StorageCredentialDefaultURLStyle(t *testing.T) {
+	desired := &DesiredState{
+		StorageCredentials: []StorageCredentialSpec{
+			{Name: "cred1", CredentialType: "S3", S3: &S3CredentialSpec{KeyIDFromEnv: "K", SecretFromEnv: "S"}},
+		},
+	}
+	actual := &DesiredState{
+		StorageCredentials: []StorageCredentialSpec{
+			{Name: "cred1", CredentialType: "S3", S3: &S3CredentialSpec{URLStyle: "path"}},
+		},
+	}
+
+	plan := Diff(desired, actual)
+	assert.Empty(t, plan.Actions)
+}
+
+func TestDiff_ExternalLocationDefaultStorageType(t *testing.T) {
+	desired := &DesiredState{
+		ExternalLocations: []ExternalLocationSpec{{Name: "raw", URL: "s3://raw/", CredentialName: "aws"}},
+	}
+	actual := &DesiredState{
+		ExternalLocations: []ExternalLocationSpec{{Name: "raw", URL: "s3://raw/", CredentialName: "aws", StorageType: "S3"}},
+	}
+
+	plan := Diff(desired, actual)
+	assert.Empty(t, plan.Actions)
+}
+
+func TestRotateStorageCredentials(t *testing.T) {
+	desired := []StorageCredentialSpec{
+		{Name: "changed", CredentialType: "S3", Comment: "new"},
+		{Name: "unchanged", CredentialType: "S3"},
+		{Name: "new", CredentialType: "S3"},
+	}
+	actual := []StorageCredentialSpec{
+		{Name: "changed", CredentialType: "S3", Comment: "old"},
+		{Name: "unchanged", CredentialType: "S3"},
+	}
+
+	plan := Diff(&DesiredState{StorageCredentials: desired}, &DesiredState{StorageCredentials: actual})
+	RotateStorageCredentials(plan, desired, actual)
+
+	ops := map[string]Operation{}
+	for _, a := range plan.Actions {
+		ops[a.ResourceName] = a.Operation
+	}
+	assert.Equal(t, map[string]Operation{"changed": OpUpdate, "unchanged": OpUpdate, "new": OpCreate}, ops)
+	require.Len(t, plan.Actions, 3)
+	for _, a := range plan.Actions {
+		if a.ResourceName == "unchanged" {
+			require.Len(t, a.Changes, 1)
+			assert.Equal(t, "secrets", a.Changes[0].Field)
+		}
+	}
+}
+
 func TestDiff_StorageCredentialUpdate(t *testing.T) {
 	desired := &DesiredState{
 		StorageCredentials: []StorageCredentialSpec{
@@ -0,0 +1,94 @@
+package declarative
+
+import (
+	"fmt"
+	"io"
+)
+
+// DriftChange classifies how a resource changed outside of declarative apply.
+type DriftChange string
+
+// Possible values for DriftChange.
+const (
+	DriftAdded    DriftChange = "added"
+	DriftRemoved  DriftChange = "removed"
+	DriftModified DriftChange = "modified"
+)
+
+// DriftEntry describes a single resource that changed on the server since the
+// last successful apply.
+type DriftEntry struct {
+	Change       DriftChange  `json:"change"`
+	ResourceKind ResourceKind `json:"-"`
+	ResourceType string       `json:"resource_type"`
+	ResourceName string       `json:"resource_name"`
+	Changes      []FieldDiff  `json:"changes,omitempty"`
+}
+
+// DetectDrift compares the server state recorded after the last successful
+// apply against the current server state and returns the out-of-band changes.
+// It reuses Diff with the snapshot as the desired side, so a planned create
+// means the resource was removed, a planned delete means it was added, and a
+// planned update means it was modified. Field diffs are reported from the
+// snapshot value to the current value.
+func DetectDrift(lastApplied, current *DesiredState) []DriftEntry {
+	plan := Diff(lastApplied, current)
+
+	entries := make([]DriftEntry, 0, len(plan.Actions))
+	for _, a := range plan.Actions {
+		entry := DriftEntry{
+			ResourceKind: a.ResourceKind,
+			ResourceType: a.ResourceKind.String(),
+			ResourceName: a.ResourceName,
+		}
+		switch a.Operation {
+		case OpCreate:
+			entry.Change = DriftRemoved
+		case OpDelete:
+			entry.Change = DriftAdded
+		case OpUpdate:
+			entry.Change = DriftModified
+			entry.Changes = make([]FieldDiff, len(a.Changes))
+			for i, d := range a.Changes {
+				entry.Changes[i] = FieldDiff{Field: d.Field, OldValue: d.NewValue, NewValue: d.OldValue}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// FormatDriftText writes a human-readable drift report to w.
+// If noColor is true, ANSI codes are suppressed.
+func FormatDriftText(w io.Writer, entries []DriftEntry, noColor bool) {
+	c := func(code string) string {
+		if noColor {
+			return ""
+		}
+		return code
+	}
+	write := func(format string, args ...any) {
+		_, _ = fmt.Fprintf(w, format, args...)
+	}
+
+	if len(entries) == 0 {
+		write("No drift detected since the last apply.\n")
+		return
+	}
+
+	write("%sDrift detected since the last apply:%s\n", c(colorCyan), c(colorReset))
+	for _, e := range entries {
+		switch e.Change {
+		case DriftAdded:
+			write("  %s+%s %s %q was added outside of apply\n", c(colorGreen), c(colorReset), e.ResourceType, e.ResourceName)
+		case DriftRemoved:
+			write("  %s-%s %s %q was removed outside of apply\n", c(colorRed), c(colorReset), e.ResourceType, e.ResourceName)
+		case DriftModified:
+			write("  %s~%s %s %q was modified outside of apply\n", c(colorYellow), c(colorReset), e.ResourceType, e.ResourceName)
+			for _, d := range e.Changes {
+				write("      %s: %q → %q\n", d.Field, d.OldValue, d.NewValue)
+			}
+		}
+	}
+	write("\n%sDrift:%s %d resource(s) changed outside of apply.\n", c(colorDim), c(colorReset), len(entries))
+}
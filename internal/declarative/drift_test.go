@@ -0,0 +1,99 @@
+package declarative
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDrift_NoDrift(t *testing.T) {
+	state := &DesiredState{
+		Principals: []PrincipalSpec{{Name: "alice", Type: "user"}},
+		Grants: []GrantSpec{
+			{Principal: "alice", PrincipalType: "user", SecurableType: "catalog", Securable: "main", Privilege: "USE_CATALOG"},
+		},
+	}
+
+	assert.Empty(t, DetectDrift(state, state))
+}
+
+func TestDetectDrift_ClassifiesOutOfBandChanges(t *testing.T) {
+	lastApplied := &DesiredState{
+		Principals: []PrincipalSpec{
+			{Name: "alice", Type: "user", IsAdmin: false},
+			{Name: "bob", Type: "user"},
+		},
+		Grants: []GrantSpec{
+			{Principal: "alice", PrincipalType: "user", SecurableType: "catalog", Securable: "main", Privilege: "USE_CATALOG"},
+		},
+	}
+	current := &DesiredState{
+		Principals: []PrincipalSpec{
+			// alice was promoted in the UI.
+			{Name: "alice", Type: "user", IsAdmin: true},
+			// bob was deleted, carol was created by hand.
+			{Name: "carol", Type: "user"},
+		},
+		Grants: []GrantSpec{
+			{Principal: "alice", PrincipalType: "user", SecurableType: "catalog", Securable: "main", Privilege: "USE_CATALOG"},
+			{Principal: "carol", PrincipalType: "user", SecurableType: "catalog", Securable: "main", Privilege: "ALL_PRIVILEGES"},
+		},
+	}
+
+	entries := DetectDrift(lastApplied, current)
+
+	byName := make(map[string]DriftEntry, len(entries))
+	for _, e := range entries {
+		byName[e.ResourceName] = e
+	}
+	require.Len(t, byName, 4)
+
+	assert.Equal(t, DriftModified, byName["alice"].Change)
+	require.Len(t, byName["alice"].Changes, 1)
+	assert.Equal(t, "is_admin", byName["alice"].Changes[0].Field)
+	assert.Equal(t, "false", byName["alice"].Changes[0].OldValue)
+	assert.Equal(t, "true", byName["alice"].Changes[0].NewValue)
+
+	assert.Equal(t, DriftRemoved, byName["bob"].Change)
+	assert.Equal(t, DriftAdded, byName["carol"].Change)
+
+	grant := byName["user:carol on catalog.main"]
+	assert.Equal(t, DriftAdded, grant.Change)
+	assert.Equal(t, KindPrivilegeGrant, grant.ResourceKind)
+	assert.Equal(t, "privilege-grant", grant.ResourceType)
+}
+
+func TestFormatDriftText(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []DriftEntry
+		want    []string
+	}{
+		{
+			name: "no drift",
+			want: []string{"No drift detected"},
+		},
+		{
+			name: "modified resource",
+			entries: []DriftEntry{{
+				Change:       DriftModified,
+				ResourceType: "principal",
+				ResourceName: "alice",
+				Changes:      []FieldDiff{{Field: "is_admin", OldValue: "false", NewValue: "true"}},
+			}},
+			want: []string{`~ principal "alice" was modified outside of apply`, `is_admin: "false" → "true"`, "1 resource(s)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			FormatDriftText(&buf, tt.entries, true)
+			for _, want := range tt.want {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
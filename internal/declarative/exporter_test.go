@@ -14,7 +14,7 @@ func TestExporter_RoundTrip(t *testing.T) {
 	original := &DesiredState{
 		Principals: []PrincipalSpec{
 			{Name: "admin", Type: "user", IsAdmin: true},
-			{Name: "analyst1", Type: "user", IsAdmin: false},
+			{Name: "analyst1", Type: "user", IsAdmin: false, Attributes: map[string]string{"region": "EU"}},
 		},
 		Groups: []GroupSpec{
 			{Name: "analysts", Description: "Data analysts", Members: []MemberRef{
@@ -97,6 +97,7 @@ func TestExporter_RoundTrip(t *testing.T) {
 	assert.Len(t, loaded.Principals, len(original.Principals))
 	assert.Equal(t, original.Principals[0].Name, loaded.Principals[0].Name)
 	assert.Equal(t, original.Principals[0].IsAdmin, loaded.Principals[0].IsAdmin)
+	assert.Equal(t, original.Principals[1].Attributes, loaded.Principals[1].Attributes)
 
 	assert.Len(t, loaded.Groups, len(original.Groups))
 	assert.Equal(t, original.Groups[0].Name, loaded.Groups[0].Name)
@@ -115,31 +115,45 @@ func formatDesired(w io.Writer, desired any, c func(string) string) {
 	}
 }
 
-// FormatJSON writes the plan as JSON to w.
-func FormatJSON(w io.Writer, plan *Plan) error {
-	type jsonAction struct {
-		Operation    string      `json:"operation"`
-		ResourceType string      `json:"resource_type"`
-		ResourceName string      `json:"resource_name"`
-		Path         string      `json:"path,omitempty"`
-		Changes      []FieldDiff `json:"changes,omitempty"`
-	}
-	type jsonPlan struct {
-		Actions []jsonAction `json:"actions"`
-		Errors  []PlanError  `json:"errors,omitempty"`
-		Summary PlanSummary  `json:"summary"`
-	}
+// PlanJSONFormatVersion is the version of the JSON plan document written by
+// FormatJSON. It changes only when a field is removed or its meaning changes;
+// new fields may be added within a version.
+const PlanJSONFormatVersion = 1
+
+// PlanJSON is the machine-readable plan written by FormatJSON.
+type PlanJSON struct {
+	FormatVersion int              `json:"format_version"`
+	Actions       []PlanJSONAction `json:"actions"`
+	Errors        []PlanJSONError  `json:"errors,omitempty"`
+	Summary       PlanSummary      `json:"summary"`
+}
 
-	jp := jsonPlan{
-		Actions: make([]jsonAction, 0, len(plan.Actions)),
-		Summary: plan.Summary(),
-	}
-	if len(plan.Errors) > 0 {
-		jp.Errors = plan.Errors
-	}
+// PlanJSONAction is one planned change. Changes is set only for updates.
+type PlanJSONAction struct {
+	Operation    string      `json:"operation"`     // "create", "update" or "delete"
+	ResourceType string      `json:"resource_type"` // kebab-case kind, e.g. "principal"
+	ResourceName string      `json:"resource_name"`
+	Path         string      `json:"path,omitempty"` // source YAML file
+	Changes      []FieldDiff `json:"changes,omitempty"`
+}
 
+// PlanJSONError is a problem that blocks the plan, such as a
+// deletion-protected resource missing from the configuration.
+type PlanJSONError struct {
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	Message      string `json:"message"`
+}
+
+// NewPlanJSON converts a plan to its machine-readable form.
+func NewPlanJSON(plan *Plan) PlanJSON {
+	jp := PlanJSON{
+		FormatVersion: PlanJSONFormatVersion,
+		Actions:       make([]PlanJSONAction, 0, len(plan.Actions)),
+		Summary:       plan.Summary(),
+	}
 	for _, a := range plan.Actions {
-		ja := jsonAction{
+		ja := PlanJSONAction{
 			Operation:    a.Operation.String(),
 			ResourceType: a.ResourceKind.String(),
 			ResourceName: a.ResourceName,
@@ -150,8 +164,19 @@ func FormatJSON(w io.Writer, plan *Plan) error {
 		}
 		jp.Actions = append(jp.Actions, ja)
 	}
+	for _, e := range plan.Errors {
+		jp.Errors = append(jp.Errors, PlanJSONError{
+			ResourceType: e.ResourceKind.String(),
+			ResourceName: e.ResourceName,
+			Message:      e.Message,
+		})
+	}
+	return jp
+}
 
-	data, err := json.MarshalIndent(jp, "", "  ")
+// FormatJSON writes the plan as JSON to w. See PlanJSON for the schema.
+func FormatJSON(w io.Writer, plan *Plan) error {
+	data, err := json.MarshalIndent(NewPlanJSON(plan), "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal plan: %w", err)
 	}
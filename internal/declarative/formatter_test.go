@@ -165,3 +165,59 @@ func TestFormatJSON_IncludesErrors(t *testing.T) {
 	assert.Equal(t, "cat1", planErr["resource_name"])
 	assert.Contains(t, planErr["message"], "deletion_protection")
 }
+
+func TestFormatJSON_MixedPlanFromDiff(t *testing.T) {
+	desired := &DesiredState{
+		Principals: []PrincipalSpec{
+			{Name: "alice", Type: "user", IsAdmin: true},
+			{Name: "carol", Type: "user"},
+		},
+	}
+	actual := &DesiredState{
+		Principals: []PrincipalSpec{
+			{Name: "alice", Type: "user", IsAdmin: false},
+			{Name: "bob", Type: "user"},
+		},
+	}
+	plan := Diff(desired, actual)
+
+	var buf bytes.Buffer
+	require.NoError(t, FormatJSON(&buf, plan))
+
+	var got PlanJSON
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, PlanJSONFormatVersion, got.FormatVersion)
+	assert.Equal(t, PlanSummary{Creates: 1, Updates: 1, Deletes: 1}, got.Summary)
+
+	byName := make(map[string]PlanJSONAction, len(got.Actions))
+	for _, a := range got.Actions {
+		assert.Equal(t, "principal", a.ResourceType)
+		byName[a.ResourceName] = a
+	}
+	require.Len(t, byName, 3)
+
+	assert.Equal(t, "create", byName["carol"].Operation)
+	assert.Empty(t, byName["carol"].Changes)
+
+	assert.Equal(t, "delete", byName["bob"].Operation)
+
+	update := byName["alice"]
+	assert.Equal(t, "update", update.Operation)
+	assert.Contains(t, update.Changes, FieldDiff{Field: "is_admin", OldValue: "false", NewValue: "true"})
+}
+
+func TestFormatJSON_ErrorsUseKindNames(t *testing.T) {
+	plan := &Plan{
+		Errors: []PlanError{
+			{ResourceKind: KindCatalogRegistration, ResourceName: "cat1", Message: "deletion_protection is enabled"},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, FormatJSON(&buf, plan))
+
+	var got PlanJSON
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Len(t, got.Errors, 1)
+	assert.Equal(t, "catalog-registration", got.Errors[0].ResourceType)
+	assert.Equal(t, 1, got.Summary.Errors)
+}
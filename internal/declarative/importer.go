@@ -0,0 +1,148 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+)
+
+// ValidateImport validates a snapshot for import. References may resolve to
+// resources in the snapshot or already on the server, so a partial snapshot
+// can be imported on top of existing state.
+func ValidateImport(desired, actual *DesiredState) []ValidationError {
+	return validateAgainst(desired, mergeReferences(desired, actual))
+}
+
+// mergeReferences returns a state whose resources are the union of a and b,
+// used only as a lookup table for reference checks.
+func mergeReferences(a, b *DesiredState) *DesiredState {
+	return &DesiredState{
+		Principals:         append(append([]PrincipalSpec(nil), a.Principals...), b.Principals...),
+		Groups:             append(append([]GroupSpec(nil), a.Groups...), b.Groups...),
+		Catalogs:           append(append([]CatalogResource(nil), a.Catalogs...), b.Catalogs...),
+		Schemas:            append(append([]SchemaResource(nil), a.Schemas...), b.Schemas...),
+		Tables:             append(append([]TableResource(nil), a.Tables...), b.Tables...),
+		Views:              append(append([]ViewResource(nil), a.Views...), b.Views...),
+		Volumes:            append(append([]VolumeResource(nil), a.Volumes...), b.Volumes...),
+		StorageCredentials: append(append([]StorageCredentialSpec(nil), a.StorageCredentials...), b.StorageCredentials...),
+		ExternalLocations:  append(append([]ExternalLocationSpec(nil), a.ExternalLocations...), b.ExternalLocations...),
+		ComputeEndpoints:   append(append([]ComputeEndpointSpec(nil), a.ComputeEndpoints...), b.ComputeEndpoints...),
+		Notebooks:          append(append([]NotebookResource(nil), a.Notebooks...), b.Notebooks...),
+		Tags:               append(append([]TagSpec(nil), a.Tags...), b.Tags...),
+		Macros:             append(append([]MacroResource(nil), a.Macros...), b.Macros...),
+		PrivilegePresets:   append(append([]PrivilegePresetSpec(nil), a.PrivilegePresets...), b.PrivilegePresets...),
+	}
+}
+
+// ImportPlan diffs a snapshot against the server like Diff, but is additive:
+// resources that exist only on the server are kept, so the plan never
+// contains deletes.
+func ImportPlan(desired, actual *DesiredState) *Plan {
+	full := Diff(desired, actual)
+	plan := &Plan{Errors: full.Errors}
+	for _, a := range full.Actions {
+		if a.Operation != OpDelete {
+			plan.Actions = append(plan.Actions, a)
+		}
+	}
+	return plan
+}
+
+// Tier is a group of actions in the same dependency layer. Actions within a
+// tier can depend on each other, such as a pipeline job on its pipeline or a
+// view on a table; they are ordered so that each follows the actions it
+// depends on.
+type Tier struct {
+	Layer   int
+	Actions []Action
+}
+
+// Tiers splits sorted plan actions into consecutive dependency tiers and
+// orders each tier by ActionDependencies.
+func (p *Plan) Tiers() []Tier {
+	deps := ActionDependencies(p.Actions)
+	var tiers []Tier
+	var members [][]int
+	for i, a := range p.Actions {
+		layer := a.ResourceKind.Layer()
+		if n := len(tiers); n > 0 && tiers[n-1].Layer == layer {
+			members[n-1] = append(members[n-1], i)
+			continue
+		}
+		tiers = append(tiers, Tier{Layer: layer})
+		members = append(members, []int{i})
+	}
+	for t := range tiers {
+		for _, i := range DependencyOrder(deps, members[t]) {
+			tiers[t].Actions = append(tiers[t].Actions, p.Actions[i])
+		}
+	}
+	return tiers
+}
+
+// ActionExecutor executes a single planned action against the server.
+type ActionExecutor interface {
+	Execute(ctx context.Context, action Action) error
+}
+
+// TierResult reports the outcome of applying one tier.
+type TierResult struct {
+	Layer      int
+	Applied    []Action // actions that took effect and were kept
+	Failed     *Action  // the action that failed, if any
+	Err        error
+	RolledBack []Action // actions undone after the failure
+	// RollbackErrs lists compensating actions that themselves failed, which
+	// leaves the tier partially applied.
+	RollbackErrs []error
+}
+
+// ApplyTiers applies tiers in order with all-or-nothing semantics per tier:
+// when an action fails, the actions already applied in that tier are undone
+// in reverse order and no later tier runs. Earlier tiers stay applied since
+// they are complete and consistent on their own.
+func ApplyTiers(ctx context.Context, exec ActionExecutor, tiers []Tier) ([]TierResult, error) {
+	results := make([]TierResult, 0, len(tiers))
+	for _, tier := range tiers {
+		res := TierResult{Layer: tier.Layer}
+		for i, action := range tier.Actions {
+			if err := exec.Execute(ctx, action); err != nil {
+				failed := action
+				res.Failed = &failed
+				res.Err = err
+				res.Applied = nil
+				for j := i - 1; j >= 0; j-- {
+					done := tier.Actions[j]
+					if rbErr := exec.Execute(ctx, compensate(done)); rbErr != nil {
+						res.RollbackErrs = append(res.RollbackErrs,
+							fmt.Errorf("undo %s %s %q: %w", done.Operation, done.ResourceKind, done.ResourceName, rbErr))
+						res.Applied = append(res.Applied, done)
+						continue
+					}
+					res.RolledBack = append(res.RolledBack, done)
+				}
+				results = append(results, res)
+				return results, fmt.Errorf("tier %d: %s %s %q: %w", tier.Layer, action.Operation, action.ResourceKind, action.ResourceName, err)
+			}
+			res.Applied = append(res.Applied, action)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// compensate returns the action that undoes a: a delete for a create, and
+// an update back to the previous spec for an update.
+func compensate(a Action) Action {
+	switch a.Operation {
+	case OpCreate:
+		return Action{Operation: OpDelete, ResourceKind: a.ResourceKind, ResourceName: a.ResourceName, Actual: a.Desired}
+	case OpUpdate:
+		changes := make([]FieldDiff, len(a.Changes))
+		for i, c := range a.Changes {
+			changes[i] = FieldDiff{Field: c.Field, OldValue: c.NewValue, NewValue: c.OldValue}
+		}
+		return Action{Operation: OpUpdate, ResourceKind: a.ResourceKind, ResourceName: a.ResourceName, FilePath: a.FilePath, Desired: a.Actual, Actual: a.Desired, Changes: changes}
+	default:
+		return Action{Operation: OpCreate, ResourceKind: a.ResourceKind, ResourceName: a.ResourceName, Desired: a.Actual}
+	}
+}
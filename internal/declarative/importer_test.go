@@ -0,0 +1,127 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateImport_ResolvesReferencesOnServer(t *testing.T) {
+	snapshot := &DesiredState{
+		Grants: []GrantSpec{
+			{Principal: "analysts", PrincipalType: "group", SecurableType: "catalog", Securable: "main", Privilege: "USE_CATALOG"},
+		},
+	}
+	server := &DesiredState{
+		Groups:   []GroupSpec{{Name: "analysts"}},
+		Catalogs: []CatalogResource{{CatalogName: "main", Spec: CatalogSpec{MetastoreType: "sqlite", DSN: "/tmp/meta.sqlite", DataPath: "/tmp/data"}}},
+	}
+
+	assert.NotEmpty(t, Validate(snapshot), "snapshot alone has dangling references")
+	assert.Empty(t, ValidateImport(snapshot, server))
+}
+
+func TestValidateImport_RejectsUnresolvableReferences(t *testing.T) {
+	snapshot := &DesiredState{
+		Grants: []GrantSpec{
+			{Principal: "ghost", PrincipalType: "user", SecurableType: "schema", Securable: "main.missing", Privilege: "USE_SCHEMA"},
+		},
+	}
+	server := &DesiredState{
+		Catalogs: []CatalogResource{{CatalogName: "main"}},
+	}
+
+	errs := ValidateImport(snapshot, server)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), `"ghost" references unknown user`)
+	assert.Contains(t, errs[1].Error(), `unknown schema "main.missing"`)
+}
+
+func TestImportPlan_NeverDeletes(t *testing.T) {
+	snapshot := &DesiredState{Principals: []PrincipalSpec{{Name: "alice", Type: "user"}}}
+	server := &DesiredState{Principals: []PrincipalSpec{{Name: "bob", Type: "user"}}}
+
+	require.Len(t, Diff(snapshot, server).Actions, 2)
+	plan := ImportPlan(snapshot, server)
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, OpCreate, plan.Actions[0].Operation)
+	assert.Equal(t, "alice", plan.Actions[0].ResourceName)
+}
+
+func TestPlanTiers_GroupsByLayer(t *testing.T) {
+	snapshot := &DesiredState{
+		Principals: []PrincipalSpec{{Name: "alice", Type: "user"}, {Name: "bob", Type: "user"}},
+		Groups:     []GroupSpec{{Name: "analysts", Members: []MemberRef{{Name: "alice", Type: "user"}}}},
+	}
+	tiers := ImportPlan(snapshot, &DesiredState{}).Tiers()
+	require.Len(t, tiers, 3)
+	assert.Equal(t, 0, tiers[0].Layer)
+	assert.Len(t, tiers[0].Actions, 2)
+	assert.Equal(t, KindGroup, tiers[1].Actions[0].ResourceKind)
+	assert.Equal(t, KindGroupMembership, tiers[2].Actions[0].ResourceKind)
+}
+
+// recordingExecutor records executed actions and fails on a named resource.
+type recordingExecutor struct {
+	failOn   string
+	executed []string
+}
+
+func (e *recordingExecutor) Execute(_ context.Context, a Action) error {
+	if a.ResourceName == e.failOn && a.Operation == OpCreate {
+		return errors.New("boom")
+	}
+	e.executed = append(e.executed, a.Operation.String()+" "+a.ResourceName)
+	return nil
+}
+
+func TestApplyTiers_RollsBackFailedTier(t *testing.T) {
+	tiers := []Tier{
+		{Layer: 0, Actions: []Action{
+			{Operation: OpCreate, ResourceKind: KindPrincipal, ResourceName: "alice", Desired: PrincipalSpec{Name: "alice"}},
+		}},
+		{Layer: 1, Actions: []Action{
+			{Operation: OpCreate, ResourceKind: KindGroup, ResourceName: "a", Desired: GroupSpec{Name: "a"}},
+			{Operation: OpUpdate, ResourceKind: KindGroup, ResourceName: "b", Desired: GroupSpec{Name: "b", Description: "new"}, Actual: GroupSpec{Name: "b", Description: "old"}},
+			{Operation: OpCreate, ResourceKind: KindGroup, ResourceName: "c", Desired: GroupSpec{Name: "c"}},
+		}},
+		{Layer: 2, Actions: []Action{
+			{Operation: OpCreate, ResourceKind: KindGroupMembership, ResourceName: "a/alice"},
+		}},
+	}
+	exec := &recordingExecutor{failOn: "c"}
+
+	results, err := ApplyTiers(context.Background(), exec, tiers)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `tier 1: create group "c"`)
+
+	assert.Equal(t, []string{
+		"create alice",
+		"create a", "update b",
+		// rollback of tier 1 in reverse order
+		"update b", "delete a",
+	}, exec.executed)
+
+	require.Len(t, results, 2)
+	assert.Len(t, results[0].Applied, 1)
+	assert.Empty(t, results[1].Applied)
+	assert.Len(t, results[1].RolledBack, 2)
+	require.NotNil(t, results[1].Failed)
+	assert.Equal(t, "c", results[1].Failed.ResourceName)
+}
+
+func TestCompensate_UpdateRestoresPreviousSpec(t *testing.T) {
+	a := Action{
+		Operation: OpUpdate, ResourceKind: KindGroup, ResourceName: "b",
+		Desired: GroupSpec{Name: "b", Description: "new"}, Actual: GroupSpec{Name: "b", Description: "old"},
+		Changes: []FieldDiff{{Field: "description", OldValue: "old", NewValue: "new"}},
+	}
+	undo := compensate(a)
+	assert.Equal(t, OpUpdate, undo.Operation)
+	assert.Equal(t, GroupSpec{Name: "b", Description: "old"}, undo.Desired)
+	assert.Equal(t, "new", undo.Changes[0].OldValue)
+	assert.Equal(t, "old", undo.Changes[0].NewValue)
+}
@@ -0,0 +1,567 @@
+package declarative
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+
+	"duck-demo/schemas"
+)
+
+// LintDirectory checks every declarative document under dir without
+// contacting a server. Each YAML file is validated against the embedded JSON
+// Schema for its kind, then the loaded configuration is validated for
+// semantic errors and cross-document references (grants naming principals and
+// securables that are not declared, and so on). All problems are returned
+// together, located by file and line where possible. The error is non-nil
+// only when dir cannot be read or the embedded schemas are broken.
+func LintDirectory(dir string, opts LoadOptions) ([]ValidationError, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("config directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("config directory: %s is not a directory", dir)
+	}
+
+	kindSchemas, err := compiledKindSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []ValidationError
+	locator := &sourceLocator{roots: make(map[string]locatedNode)}
+	filesWithErrors := make(map[string]bool)
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".yaml") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fileErrs := lintFile(path, filepath.ToSlash(rel), kindSchemas, opts, locator)
+		if len(fileErrs) > 0 {
+			filesWithErrors[path] = true
+			errs = append(errs, fileErrs...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk config directory: %w", err)
+	}
+
+	// The loader stops at the first problem, which is usually one already
+	// reported against its file above; only surface it when it is new.
+	state, err := LoadDirectoryWithOptions(dir, opts)
+	if err != nil {
+		if !mentionsAnyFile(err.Error(), filesWithErrors) {
+			errs = append(errs, ValidationError{Message: err.Error()})
+		}
+		return errs, nil
+	}
+
+	for _, ve := range Validate(state) {
+		ve.File, ve.Line = locator.locate(ve.Path)
+		errs = append(errs, ve)
+	}
+	return errs, nil
+}
+
+// lintFile validates one YAML document against the schema for its kind and
+// records where its resources are defined.
+func lintFile(path, rel string, kindSchemas map[string]*jsonschema.Schema, opts LoadOptions, locator *sourceLocator) []ValidationError {
+	data, err := os.ReadFile(path) //nolint:gosec // intentional: reading user-specified config files
+	if err != nil {
+		return []ValidationError{{File: path, Message: err.Error()}}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []ValidationError{{File: path, Line: yamlErrorLine(err), Message: err.Error()}}
+	}
+	if len(doc.Content) == 0 {
+		return []ValidationError{{File: path, Message: "empty document"}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []ValidationError{{File: path, Line: root.Line, Message: "document must be a mapping with apiVersion and kind"}}
+	}
+
+	kindName := ""
+	kindLine := root.Line
+	if n := mappingValue(root, "kind"); n != nil {
+		kindName, kindLine = n.Value, n.Line
+	}
+	schema, ok := kindSchemas[kindName]
+	if !ok {
+		msg := "kind is required"
+		if kindName != "" {
+			msg = fmt.Sprintf("unknown kind %q", kindName)
+		}
+		return []ValidationError{{File: path, Line: kindLine, Path: "kind", Message: msg}}
+	}
+
+	locator.register(rel, path, kindName, root)
+
+	value, err := yamlNodeValue(root)
+	if err != nil {
+		return []ValidationError{{File: path, Line: root.Line, Message: err.Error()}}
+	}
+	err = schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return []ValidationError{{File: path, Line: root.Line, Message: err.Error()}}
+	}
+
+	var errs []ValidationError
+	for _, leaf := range schemaLeafErrors(verr) {
+		line := yamlNodeAt(root, leaf.InstanceLocation).Line
+		if extra, ok := leaf.ErrorKind.(*kind.AdditionalProperties); ok {
+			if opts.AllowUnknownFields {
+				continue
+			}
+			if len(extra.Properties) > 0 {
+				if key := mappingKey(yamlNodeAt(root, leaf.InstanceLocation), extra.Properties[0]); key != nil {
+					line = key.Line
+				}
+			}
+		}
+		errs = append(errs, ValidationError{
+			File:    path,
+			Line:    line,
+			Path:    instancePath(leaf.InstanceLocation),
+			Message: leaf.ErrorKind.LocalizedString(schemaMessagePrinter),
+		})
+	}
+	return errs
+}
+
+var schemaMessagePrinter = message.NewPrinter(language.English)
+
+var (
+	kindSchemasOnce sync.Once
+	kindSchemaMap   map[string]*jsonschema.Schema
+	kindSchemasErr  error
+)
+
+// compiledKindSchemas compiles the embedded per-kind JSON Schemas once,
+// keyed by document kind.
+func compiledKindSchemas() (map[string]*jsonschema.Schema, error) {
+	kindSchemasOnce.Do(func() {
+		compiler := jsonschema.NewCompiler()
+		out := make(map[string]*jsonschema.Schema)
+		for _, doc := range SchemaDocumentTypes() {
+			name := "declarative/v1/kinds/" + doc.FileName + ".schema.json"
+			f, err := schemas.Declarative.Open(name)
+			if err != nil {
+				kindSchemasErr = fmt.Errorf("open embedded schema %s: %w", name, err)
+				return
+			}
+			v, err := jsonschema.UnmarshalJSON(f)
+			_ = f.Close()
+			if err != nil {
+				kindSchemasErr = fmt.Errorf("parse embedded schema %s: %w", name, err)
+				return
+			}
+			url := "embed:///" + name
+			if err := compiler.AddResource(url, v); err != nil {
+				kindSchemasErr = fmt.Errorf("add embedded schema %s: %w", name, err)
+				return
+			}
+			sch, err := compiler.Compile(url)
+			if err != nil {
+				kindSchemasErr = fmt.Errorf("compile embedded schema %s: %w", name, err)
+				return
+			}
+			out[doc.Kind] = sch
+		}
+		kindSchemaMap = out
+	})
+	return kindSchemaMap, kindSchemasErr
+}
+
+// schemaLeafErrors flattens a validation error tree to its most specific
+// causes.
+func schemaLeafErrors(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+	var out []*jsonschema.ValidationError
+	for _, c := range err.Causes {
+		out = append(out, schemaLeafErrors(c)...)
+	}
+	return out
+}
+
+// instancePath renders a JSON instance location in the validator's path
+// style, e.g. ["grants", "0", "privilege"] becomes "grants[0].privilege".
+func instancePath(loc []string) string {
+	var b strings.Builder
+	for _, tok := range loc {
+		if _, err := strconv.Atoi(tok); err == nil {
+			fmt.Fprintf(&b, "[%s]", tok)
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(tok)
+	}
+	return b.String()
+}
+
+// yamlNodeValue converts a YAML node to the plain Go values a JSON Schema
+// validator expects. Timestamps stay strings, as they would in JSON.
+func yamlNodeValue(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(n.Content[0])
+	case yaml.AliasNode:
+		return yamlNodeValue(n.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			v, err := yamlNodeValue(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[n.Content[i].Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		items := make([]any, 0, len(n.Content))
+		for _, c := range n.Content {
+			v, err := yamlNodeValue(c)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!str", "!!timestamp", "!!binary":
+			return n.Value, nil
+		}
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		return v, nil
+	}
+	return nil, nil
+}
+
+// yamlNodeAt returns the node at a JSON instance location, or the deepest
+// ancestor that exists.
+func yamlNodeAt(root *yaml.Node, loc []string) *yaml.Node {
+	n := root
+	for _, tok := range loc {
+		var next *yaml.Node
+		switch n.Kind {
+		case yaml.MappingNode:
+			next = mappingValue(n, tok)
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(tok); err == nil && i >= 0 && i < len(n.Content) {
+				next = n.Content[i]
+			}
+		}
+		if next == nil {
+			break
+		}
+		n = next
+	}
+	return n
+}
+
+func mappingKey(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i]
+		}
+	}
+	return nil
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// yamlErrorLine extracts the line number from a YAML syntax error.
+func yamlErrorLine(err error) int {
+	m := yamlLinePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0
+	}
+	line, _ := strconv.Atoi(m[1])
+	return line
+}
+
+func mentionsAnyFile(msg string, files map[string]bool) bool {
+	for f := range files {
+		if strings.Contains(msg, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// === Source locations for semantic errors ===
+
+// locatedNode is a YAML node together with the file it came from.
+type locatedNode struct {
+	file string
+	node *yaml.Node
+}
+
+// sourceLocator maps the root segment of a validator path, such as
+// "principal[alice]", "grant[3]" or "table[main.sales.orders]", to the YAML
+// node that declared it.
+type sourceLocator struct {
+	roots map[string]locatedNode
+}
+
+// lintListField describes one list inside a list document: the YAML field
+// holding the items, the validator path prefix for an item, and the item
+// field the validator uses as its name.
+type lintListField struct {
+	field, prefix, nameKey string
+}
+
+var lintListFields = map[string][]lintListField{
+	KindNamePrincipalList:         {{"principals", "principal", "name"}},
+	KindNameGroupList:             {{"groups", "group", "name"}},
+	KindNameGrantList:             {{"grants", "grant", ""}},
+	KindNamePrivilegePresetList:   {{"presets", "privilege_preset", "name"}},
+	KindNameBindingList:           {{"bindings", "binding", ""}},
+	KindNameAPIKeyList:            {{"api_keys", "api_key", "name"}},
+	KindNameTagConfig:             {{"tags", "tag", "key"}, {"assignments", "tag_assignment", ""}},
+	KindNameStorageCredentialList: {{"credentials", "storage_credential", "name"}},
+	KindNameExternalLocationList:  {{"locations", "external_location", "name"}},
+	KindNameComputeEndpointList:   {{"endpoints", "compute_endpoint", "name"}},
+	KindNameComputeAssignmentList: {{"assignments", "compute_assignment", ""}},
+}
+
+// lintResourcePrefixes maps single-resource document kinds to their
+// validator path prefix.
+var lintResourcePrefixes = map[string]string{
+	KindNameCatalog:        "catalog",
+	KindNameSchema:         "schema",
+	KindNameTable:          "table",
+	KindNameView:           "view",
+	KindNameVolume:         "volume",
+	KindNameRowFilterList:  "row_filter",
+	KindNameColumnMaskList: "column_mask",
+	KindNameNotebook:       "notebook",
+	KindNamePipeline:       "pipeline",
+	KindNameMacro:          "macro",
+	KindNameModel:          "model",
+	KindNameSemanticModel:  "semantic_model",
+}
+
+// register records the resources declared by one document. rel is the
+// slash-separated path relative to the config root, from which the loader
+// derives resource names.
+func (l *sourceLocator) register(rel, file, kindName string, root *yaml.Node) {
+	add := func(key string, n *yaml.Node) {
+		if _, exists := l.roots[key]; !exists {
+			l.roots[key] = locatedNode{file: file, node: n}
+		}
+	}
+
+	if fields, ok := lintListFields[kindName]; ok {
+		for _, f := range fields {
+			seq := mappingValue(root, f.field)
+			if seq == nil || seq.Kind != yaml.SequenceNode {
+				continue
+			}
+			for i, item := range seq.Content {
+				add(fmt.Sprintf("%s[%d]", f.prefix, i), item)
+				if f.nameKey == "" {
+					continue
+				}
+				if name := mappingValue(item, f.nameKey); name != nil && name.Value != "" {
+					add(fmt.Sprintf("%s[%s]", f.prefix, name.Value), item)
+				}
+			}
+		}
+		return
+	}
+
+	prefix, ok := lintResourcePrefixes[kindName]
+	if !ok {
+		return
+	}
+	node := root
+	if kindName != KindNameRowFilterList && kindName != KindNameColumnMaskList {
+		if spec := mappingValue(root, "spec"); spec != nil {
+			node = spec
+		}
+	}
+	add(fmt.Sprintf("%s[%s]", prefix, resourceKeyFromPath(rel, kindName)), node)
+}
+
+// resourceKeyFromPath derives the validator's qualified resource name from a
+// document's location, mirroring the directory layout the loader reads.
+func resourceKeyFromPath(rel, kindName string) string {
+	parts := strings.Split(rel, "/")
+	name := strings.TrimSuffix(parts[len(parts)-1], ".yaml")
+	switch kindName {
+	case KindNameCatalog, KindNameSchema, KindNameTable, KindNameRowFilterList, KindNameColumnMaskList:
+		if len(parts) >= 2 {
+			name = parts[len(parts)-2]
+		}
+	}
+
+	after := func(dir string) string {
+		for i := 0; i+1 < len(parts); i++ {
+			if parts[i] == dir {
+				return parts[i+1]
+			}
+		}
+		return ""
+	}
+
+	switch kindName {
+	case KindNameSchema:
+		return after("catalogs") + "." + name
+	case KindNameTable, KindNameView, KindNameVolume, KindNameRowFilterList, KindNameColumnMaskList:
+		return after("catalogs") + "." + after("schemas") + "." + name
+	case KindNameModel:
+		return after("models") + "." + name
+	case KindNameSemanticModel:
+		return after("semantic_models") + "." + name
+	}
+	return name
+}
+
+var lintPathSegment = regexp.MustCompile(`^([A-Za-z_]+)(?:\[([^\]]*)\])?$`)
+
+// locate returns the file and line for a validator path such as
+// "group[eng].members[2]". It falls back to the nearest enclosing node it can
+// find, and returns an empty file when the root resource is unknown.
+func (l *sourceLocator) locate(path string) (string, int) {
+	segs := splitValidatorPath(path)
+	if len(segs) == 0 {
+		return "", 0
+	}
+	root, ok := l.roots[segs[0]]
+	if !ok {
+		return "", 0
+	}
+
+	n := root.node
+	for _, seg := range segs[1:] {
+		m := lintPathSegment.FindStringSubmatch(seg)
+		if m == nil {
+			break
+		}
+		child := mappingValue(n, m[1])
+		if child == nil {
+			// Validator paths use singular names for some lists ("job[x]"
+			// for the "jobs" field).
+			child = mappingValue(n, m[1]+"s")
+		}
+		if child == nil {
+			break
+		}
+		n = child
+		if !strings.Contains(seg, "[") {
+			continue
+		}
+		item := sequenceItem(n, m[2])
+		if item == nil {
+			break
+		}
+		n = item
+	}
+	return root.file, n.Line
+}
+
+// sequenceItem finds a list item by index or by its name field.
+func sequenceItem(seq *yaml.Node, key string) *yaml.Node {
+	if seq.Kind != yaml.SequenceNode {
+		return nil
+	}
+	if i, err := strconv.Atoi(key); err == nil {
+		if i >= 0 && i < len(seq.Content) {
+			return seq.Content[i]
+		}
+		return nil
+	}
+	for _, item := range seq.Content {
+		if name := mappingValue(item, "name"); name != nil && name.Value == key {
+			return item
+		}
+	}
+	return nil
+}
+
+// splitValidatorPath splits a validator path on dots outside brackets, so
+// "table[main.sales.orders].columns[0]" yields two segments.
+func splitValidatorPath(path string) []string {
+	var (
+		segs  []string
+		depth int
+		start int
+	)
+	for i, r := range path {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				segs = append(segs, path[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(path) {
+		segs = append(segs, path[start:])
+	}
+	return segs
+}
@@ -0,0 +1,201 @@
+package declarative
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLintFiles writes files (relative path -> content) under a new temp dir.
+func writeLintFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func findLintError(errs []ValidationError, substr string) (ValidationError, bool) {
+	for _, e := range errs {
+		if strings.Contains(e.Error(), substr) {
+			return e, true
+		}
+	}
+	return ValidationError{}, false
+}
+
+func TestLintDirectory_ValidConfig(t *testing.T) {
+	for _, name := range []string{"full", "minimal", "models-only"} {
+		t.Run(name, func(t *testing.T) {
+			errs, err := LintDirectory(filepath.Join(testdataDir(t), "valid", name), LoadOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, errs)
+		})
+	}
+}
+
+func TestLintDirectory_SchemaViolations(t *testing.T) {
+	dir := writeLintFiles(t, map[string]string{
+		"security/principals.yaml": `apiVersion: duck/v1
+kind: PrincipalList
+principals:
+  - name: alice
+    type: user
+    is_admin: false
+    shoe_size: 42
+`,
+		"security/grants.yaml": `apiVersion: duck/v1
+kind: GrantList
+grants:
+  - principal: alice
+    principal_type: user
+    securable_type: catalog
+    securable: main
+    privilege: SELECTT
+`,
+		"storage/credentials.yaml": `apiVersion: duck/v2
+kind: StorageCredentialList
+credentials: []
+`,
+	})
+
+	errs, err := LintDirectory(dir, LoadOptions{})
+	require.NoError(t, err)
+
+	unknownField, ok := findLintError(errs, "shoe_size")
+	require.True(t, ok, "expected unknown field error; got %v", errs)
+	assert.Equal(t, filepath.Join(dir, "security", "principals.yaml"), unknownField.File)
+	assert.Equal(t, 7, unknownField.Line)
+
+	badEnum, ok := findLintError(errs, "grants[0].privilege")
+	require.True(t, ok, "expected privilege enum error; got %v", errs)
+	assert.Equal(t, filepath.Join(dir, "security", "grants.yaml"), badEnum.File)
+	assert.Equal(t, 8, badEnum.Line)
+
+	badVersion, ok := findLintError(errs, "apiVersion")
+	require.True(t, ok, "expected apiVersion error; got %v", errs)
+	assert.Equal(t, 1, badVersion.Line)
+}
+
+func TestLintDirectory_AllowUnknownFields(t *testing.T) {
+	dir := writeLintFiles(t, map[string]string{
+		"security/principals.yaml": `apiVersion: duck/v1
+kind: PrincipalList
+principals:
+  - name: alice
+    type: user
+    is_admin: false
+    shoe_size: 42
+`,
+	})
+
+	errs, err := LintDirectory(dir, LoadOptions{AllowUnknownFields: true})
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestLintDirectory_DanglingReferences(t *testing.T) {
+	dir := filepath.Join(testdataDir(t), "invalid", "dangling-ref")
+
+	errs, err := LintDirectory(dir, LoadOptions{})
+	require.NoError(t, err)
+
+	principal, ok := findLintError(errs, `principal "nonexistent-user" references unknown user`)
+	require.True(t, ok, "expected unknown principal error; got %v", errs)
+	assert.Equal(t, filepath.Join(dir, "security", "grants.yaml"), principal.File)
+	assert.Equal(t, 4, principal.Line)
+	assert.Equal(t, "grant[0]", principal.Path)
+
+	_, ok = findLintError(errs, `securable references unknown catalog "main"`)
+	assert.True(t, ok, "expected unknown catalog error; got %v", errs)
+}
+
+func TestLintDirectory_NestedReferenceLocation(t *testing.T) {
+	dir := writeLintFiles(t, map[string]string{
+		"security/principals.yaml": `apiVersion: duck/v1
+kind: PrincipalList
+principals:
+  - name: alice
+    type: user
+    is_admin: false
+`,
+		"security/groups.yaml": `apiVersion: duck/v1
+kind: GroupList
+groups:
+  - name: eng
+    members:
+      - name: alice
+        type: user
+      - name: bob
+        type: user
+`,
+	})
+
+	errs, err := LintDirectory(dir, LoadOptions{})
+	require.NoError(t, err)
+
+	e, ok := findLintError(errs, `member "bob" references unknown principal`)
+	require.True(t, ok, "got %v", errs)
+	assert.Equal(t, filepath.Join(dir, "security", "groups.yaml"), e.File)
+	assert.Equal(t, 8, e.Line)
+}
+
+func TestLintDirectory_ReportsAllErrorsAcrossFiles(t *testing.T) {
+	dir := writeLintFiles(t, map[string]string{
+		"security/principals.yaml": `apiVersion: duck/v1
+kind: PrincipalList
+principals:
+  - name: alice
+    type: robot
+`,
+		"security/groups.yaml": `apiVersion: duck/v1
+kind: GroupList
+groups:
+  - name: eng
+    members: [{name: bob, type: user}
+`,
+		"notes/readme.yaml": `title: not a declarative document
+`,
+	})
+
+	errs, err := LintDirectory(dir, LoadOptions{})
+	require.NoError(t, err)
+
+	files := make(map[string]bool)
+	for _, e := range errs {
+		files[e.File] = true
+	}
+	assert.True(t, files[filepath.Join(dir, "security", "principals.yaml")], "got %v", errs)
+	assert.True(t, files[filepath.Join(dir, "security", "groups.yaml")], "got %v", errs)
+	assert.True(t, files[filepath.Join(dir, "notes", "readme.yaml")], "got %v", errs)
+
+	syntax, ok := findLintError(errs, "groups.yaml")
+	require.True(t, ok)
+	assert.Positive(t, syntax.Line, "YAML syntax errors should carry a line")
+
+	_, ok = findLintError(errs, "kind is required")
+	assert.True(t, ok, "got %v", errs)
+}
+
+func TestLintDirectory_MissingDirectory(t *testing.T) {
+	_, err := LintDirectory(filepath.Join(t.TempDir(), "missing"), LoadOptions{})
+	require.Error(t, err)
+}
+
+func TestValidationError_ErrorIncludesLocation(t *testing.T) {
+	e := ValidationError{Path: "grant[0]", Message: "bad", File: "security/grants.yaml", Line: 4}
+	assert.Equal(t, "security/grants.yaml:4: grant[0]: bad", e.Error())
+	assert.Equal(t, "grant[0]: bad", ValidationError{Path: "grant[0]", Message: "bad"}.Error())
+}
+
+func TestSplitValidatorPath(t *testing.T) {
+	assert.Equal(t, []string{"table[main.sales.orders]", "columns[0]"}, splitValidatorPath("table[main.sales.orders].columns[0]"))
+	assert.Equal(t, []string{"groups"}, splitValidatorPath("groups"))
+}
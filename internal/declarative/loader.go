@@ -454,6 +454,23 @@ func loadTables(schemaPath, catalogName, schemaName string, state *DesiredState,
 	return nil
 }
 
+// LoadTableFile reads a single table.yaml manifest outside of a config
+// directory, for commands that compare one table against the server.
+func LoadTableFile(path string, opts LoadOptions) (*TableDoc, error) {
+	var doc TableDoc
+	found, err := loadYAMLFile(path, &doc, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("read %s: file does not exist", path)
+	}
+	if err := validateDocument(path, doc.APIVersion, doc.Kind, KindNameTable); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
 // loadOneTable loads a single table directory: table.yaml, row-filters.yaml, column-masks.yaml.
 func loadOneTable(tablePath, catalogName, schemaName, tableName string, state *DesiredState, opts LoadOptions) error {
 	// table.yaml
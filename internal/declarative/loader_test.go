@@ -27,6 +27,7 @@ func TestLoader_FullConfig(t *testing.T) {
 		assert.Len(t, state.Principals, 3)
 		assert.Equal(t, "admin-user", state.Principals[0].Name)
 		assert.True(t, state.Principals[0].IsAdmin)
+		assert.Equal(t, map[string]string{"region": "EU"}, state.Principals[1].Attributes)
 	})
 
 	t.Run("groups loaded", func(t *testing.T) {
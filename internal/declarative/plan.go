@@ -13,6 +13,16 @@ type Action struct {
 	Changes      []FieldDiff
 }
 
+// HasFieldChange reports whether the action changes the named field.
+func (a Action) HasFieldChange(field string) bool {
+	for _, change := range a.Changes {
+		if change.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
 // FieldDiff describes a single field change within an Update action.
 type FieldDiff struct {
 	Field    string `json:"field"`
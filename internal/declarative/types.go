@@ -26,6 +26,8 @@ type PrincipalSpec struct {
 	Name    string `yaml:"name"`
 	Type    string `yaml:"type"` // "user" or "service_principal"
 	IsAdmin bool   `yaml:"is_admin"`
+	// Attributes are read by row filters through current_principal_attr.
+	Attributes map[string]string `yaml:"attributes,omitempty"`
 }
 
 // GroupListDoc declares a set of groups with optional membership.
@@ -88,11 +90,12 @@ type BindingSpec struct {
 
 // GrantSpec describes a single privilege grant on a securable object.
 type GrantSpec struct {
-	Principal     string `yaml:"principal"`
-	PrincipalType string `yaml:"principal_type"` // "user" or "group"
-	SecurableType string `yaml:"securable_type"` // catalog, schema, table, external_location, storage_credential, volume
-	Securable     string `yaml:"securable"`      // dot-path: "main.analytics.orders"
-	Privilege     string `yaml:"privilege"`      // SELECT, INSERT, UPDATE, DELETE, USAGE, CREATE_TABLE, CREATE_SCHEMA, ALL_PRIVILEGES, etc.
+	Principal     string  `yaml:"principal"`
+	PrincipalType string  `yaml:"principal_type"`       // "user" or "group"
+	SecurableType string  `yaml:"securable_type"`       // catalog, schema, table, external_location, storage_credential, volume
+	Securable     string  `yaml:"securable"`            // dot-path: "main.analytics.orders"
+	Privilege     string  `yaml:"privilege"`            // SELECT, INSERT, UPDATE, DELETE, USAGE, CREATE_TABLE, CREATE_SCHEMA, ALL_PRIVILEGES, etc.
+	ExpiresAt     *string `yaml:"expires_at,omitempty"` // RFC3339; the grant lapses at this time
 }
 
 // APIKeyListDoc declares a set of API keys.
@@ -162,6 +165,7 @@ type TableSpec struct {
 	SourcePath   string            `yaml:"source_path,omitempty"`   // for EXTERNAL tables
 	FileFormat   string            `yaml:"file_format,omitempty"`   // for EXTERNAL tables
 	LocationName string            `yaml:"location_name,omitempty"` // for EXTERNAL tables
+	PartitionBy  []string          `yaml:"partition_by,omitempty"`  // for MANAGED tables; fixed at creation
 }
 
 // ColumnDef describes a single column in a table definition.
@@ -185,6 +189,11 @@ type ViewSpec struct {
 	Comment        string            `yaml:"comment,omitempty"`
 	Owner          string            `yaml:"owner,omitempty"`
 	Properties     map[string]string `yaml:"properties,omitempty"`
+	// Materialized stores the view's result in a table that queries read;
+	// RefreshSchedule is the cron on which that table is recomputed. Both
+	// are fixed at creation.
+	Materialized    bool   `yaml:"materialized,omitempty"`
+	RefreshSchedule string `yaml:"refresh_schedule,omitempty"`
 }
 
 // VolumeDoc declares a volume within a schema.
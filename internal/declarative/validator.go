@@ -3,7 +3,9 @@ package declarative
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"duck-demo/internal/duckdbsql"
 	"github.com/robfig/cron/v3"
@@ -13,13 +15,25 @@ import (
 type ValidationError struct {
 	Path    string // e.g. "security/principals.yaml" or "principal[analyst1]"
 	Message string
+
+	// File and Line locate the problem in the source YAML when known; they
+	// are set by LintDirectory and are empty for in-memory validation.
+	File string
+	Line int
 }
 
 func (e ValidationError) Error() string {
+	msg := e.Message
 	if e.Path != "" {
-		return fmt.Sprintf("%s: %s", e.Path, e.Message)
+		msg = fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	switch {
+	case e.File != "" && e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, msg)
+	case e.File != "":
+		return fmt.Sprintf("%s: %s", e.File, msg)
 	}
-	return e.Message
+	return msg
 }
 
 // Valid principal types.
@@ -89,6 +103,16 @@ var validPrivileges = map[string]bool{
 	"MANAGE_PIPELINES":          true,
 }
 
+// ValidPrivileges returns the privilege names accepted in grants, sorted.
+func ValidPrivileges() []string {
+	out := make([]string, 0, len(validPrivileges))
+	for p := range validPrivileges {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
 var allowedPrivilegesBySecurable = map[string]map[string]bool{
 	"catalog": {
 		"USE_CATALOG":               true,
@@ -206,33 +230,39 @@ var validTagSecurableTypes = map[string]bool{
 // Validate checks the DesiredState for structural correctness and referential integrity.
 // It returns a list of all validation errors (does not stop at first error).
 func Validate(state *DesiredState) []ValidationError {
+	return validateAgainst(state, state)
+}
+
+// validateAgainst validates state, resolving cross-resource references
+// against refs rather than state itself.
+func validateAgainst(state, refs *DesiredState) []ValidationError {
 	var errs []ValidationError
 
 	// Build lookup sets for referential integrity checks.
-	principalNames := make(map[string]bool, len(state.Principals))
-	for _, p := range state.Principals {
+	principalNames := make(map[string]bool, len(refs.Principals))
+	for _, p := range refs.Principals {
 		principalNames[p.Name] = true
 	}
 
-	groupNames := make(map[string]bool, len(state.Groups))
-	for _, g := range state.Groups {
+	groupNames := make(map[string]bool, len(refs.Groups))
+	for _, g := range refs.Groups {
 		groupNames[g.Name] = true
 	}
 
-	catalogNames := make(map[string]bool, len(state.Catalogs))
-	for _, c := range state.Catalogs {
+	catalogNames := make(map[string]bool, len(refs.Catalogs))
+	for _, c := range refs.Catalogs {
 		catalogNames[c.CatalogName] = true
 	}
 
-	schemaKeys := make(map[string]bool, len(state.Schemas))
-	for _, s := range state.Schemas {
+	schemaKeys := make(map[string]bool, len(refs.Schemas))
+	for _, s := range refs.Schemas {
 		schemaKeys[s.CatalogName+"."+s.SchemaName] = true
 	}
 
-	tableKeys := make(map[string]bool, len(state.Tables))
+	tableKeys := make(map[string]bool, len(refs.Tables))
 	// Also build column lookup: tableKey -> set of column names.
-	tableColumns := make(map[string]map[string]bool, len(state.Tables))
-	for _, t := range state.Tables {
+	tableColumns := make(map[string]map[string]bool, len(refs.Tables))
+	for _, t := range refs.Tables {
 		key := t.CatalogName + "." + t.SchemaName + "." + t.TableName
 		tableKeys[key] = true
 		if len(t.Spec.Columns) > 0 {
@@ -244,50 +274,50 @@ func Validate(state *DesiredState) []ValidationError {
 		}
 	}
 
-	viewKeys := make(map[string]bool, len(state.Views))
-	for _, v := range state.Views {
+	viewKeys := make(map[string]bool, len(refs.Views))
+	for _, v := range refs.Views {
 		viewKeys[v.CatalogName+"."+v.SchemaName+"."+v.ViewName] = true
 	}
 
-	volumeKeys := make(map[string]bool, len(state.Volumes))
-	for _, v := range state.Volumes {
+	volumeKeys := make(map[string]bool, len(refs.Volumes))
+	for _, v := range refs.Volumes {
 		volumeKeys[v.CatalogName+"."+v.SchemaName+"."+v.VolumeName] = true
 	}
 
-	credentialNames := make(map[string]bool, len(state.StorageCredentials))
-	for _, c := range state.StorageCredentials {
+	credentialNames := make(map[string]bool, len(refs.StorageCredentials))
+	for _, c := range refs.StorageCredentials {
 		credentialNames[c.Name] = true
 	}
 
-	locationNames := make(map[string]bool, len(state.ExternalLocations))
-	for _, l := range state.ExternalLocations {
+	locationNames := make(map[string]bool, len(refs.ExternalLocations))
+	for _, l := range refs.ExternalLocations {
 		locationNames[l.Name] = true
 	}
 
-	endpointNames := make(map[string]bool, len(state.ComputeEndpoints))
-	for _, e := range state.ComputeEndpoints {
+	endpointNames := make(map[string]bool, len(refs.ComputeEndpoints))
+	for _, e := range refs.ComputeEndpoints {
 		endpointNames[e.Name] = true
 	}
 
-	notebookNames := make(map[string]bool, len(state.Notebooks))
-	for _, n := range state.Notebooks {
+	notebookNames := make(map[string]bool, len(refs.Notebooks))
+	for _, n := range refs.Notebooks {
 		notebookNames[n.Name] = true
 	}
 
-	tagKeys := make(map[string]bool, len(state.Tags))
-	for _, t := range state.Tags {
+	tagKeys := make(map[string]bool, len(refs.Tags))
+	for _, t := range refs.Tags {
 		tagKeys[formatTagKey(t)] = true
 	}
 
-	macroNames := make(map[string]bool, len(state.Macros))
-	for _, m := range state.Macros {
+	macroNames := make(map[string]bool, len(refs.Macros))
+	for _, m := range refs.Macros {
 		if m.Name != "" {
 			macroNames[m.Name] = true
 		}
 	}
 
-	presetNames := make(map[string]bool, len(state.PrivilegePresets))
-	for _, p := range state.PrivilegePresets {
+	presetNames := make(map[string]bool, len(refs.PrivilegePresets))
+	for _, p := range refs.PrivilegePresets {
 		presetNames[p.Name] = true
 	}
 
@@ -540,6 +570,39 @@ func validatePrincipals(principals []PrincipalSpec, errs *[]ValidationError) {
 			}
 			seen[p.Name] = true
 		}
+		validatePrincipalAttributes(p.Attributes, path, errs)
+	}
+}
+
+// principalAttributeKeyPattern and the limits below mirror the server's
+// validation of principal attributes.
+var principalAttributeKeyPattern = regexp.MustCompile(`^[A-Za-z_.-][A-Za-z0-9_.-]*$`)
+
+const (
+	maxPrincipalAttributes        = 64
+	maxPrincipalAttributeKeyLen   = 128
+	maxPrincipalAttributeValueLen = 1024
+)
+
+func validatePrincipalAttributes(attrs map[string]string, path string, errs *[]ValidationError) {
+	if len(attrs) > maxPrincipalAttributes {
+		addErr(errs, path, "at most %d attributes are allowed, got %d", maxPrincipalAttributes, len(attrs))
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !principalAttributeKeyPattern.MatchString(k) {
+			addErr(errs, path, "invalid attribute key %q: use letters, digits, '_', '-' or '.', not starting with a digit", k)
+		}
+		if len(k) > maxPrincipalAttributeKeyLen {
+			addErr(errs, path, "attribute key %q exceeds %d characters", k, maxPrincipalAttributeKeyLen)
+		}
+		if len(attrs[k]) > maxPrincipalAttributeValueLen {
+			addErr(errs, path, "value of attribute %q exceeds %d characters", k, maxPrincipalAttributeValueLen)
+		}
 	}
 }
 
@@ -685,6 +748,12 @@ func validateGrants(
 			validateGrantSecurable(g, catalogNames, schemaKeys, tableKeys, locationNames, credentialNames, volumeKeys, path, errs)
 		}
 
+		if g.ExpiresAt != nil {
+			if _, err := time.Parse(time.RFC3339, *g.ExpiresAt); err != nil {
+				addErr(errs, path, "expires_at must be an RFC3339 timestamp, got %q", *g.ExpiresAt)
+			}
+		}
+
 		// Duplicate detection.
 		key := fmt.Sprintf("%s|%s|%s|%s|%s", g.Principal, g.PrincipalType, g.SecurableType, g.Securable, g.Privilege)
 		if seen[key] {
@@ -898,6 +967,22 @@ func validateTables(tables []TableResource, schemaKeys map[string]bool, errs *[]
 			}
 		}
 
+		// Validate partition columns.
+		if len(t.Spec.PartitionBy) > 0 && t.Spec.TableType == "EXTERNAL" {
+			addErr(errs, path+".partition_by", "partition_by is only supported for MANAGED tables")
+		}
+		partSeen := make(map[string]bool, len(t.Spec.PartitionBy))
+		for j, p := range t.Spec.PartitionBy {
+			ppath := fmt.Sprintf("%s.partition_by[%d]", path, j)
+			if partSeen[p] {
+				addErr(errs, ppath, "duplicate partition column %q", p)
+			}
+			partSeen[p] = true
+			if !colSeen[p] {
+				addErr(errs, ppath, "partition column %q is not a column of the table", p)
+			}
+		}
+
 		tableKey := t.CatalogName + "." + t.SchemaName + "." + t.TableName
 		if t.CatalogName != "" && t.SchemaName != "" && t.TableName != "" {
 			if seen[tableKey] {
@@ -935,6 +1020,14 @@ func validateViews(views []ViewResource, schemaKeys map[string]bool, errs *[]Val
 		if v.Spec.ViewDefinition == "" {
 			addErr(errs, path, "view_definition is required")
 		}
+		if v.Spec.RefreshSchedule != "" {
+			if !v.Spec.Materialized {
+				addErr(errs, path, "refresh_schedule requires materialized: true")
+			}
+			if _, err := cron.ParseStandard(v.Spec.RefreshSchedule); err != nil {
+				addErr(errs, path, "refresh_schedule is invalid: %v", err)
+			}
+		}
 
 		viewKey := v.CatalogName + "." + v.SchemaName + "." + v.ViewName
 		if v.CatalogName != "" && v.SchemaName != "" && v.ViewName != "" {
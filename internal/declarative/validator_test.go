@@ -197,6 +197,8 @@ func TestValidate_PrincipalErrors(t *testing.T) {
 		{"empty name", []PrincipalSpec{{Name: "", Type: "user"}}, 1, "name is required"},
 		{"invalid type", []PrincipalSpec{{Name: "p1", Type: "invalid"}}, 1, "type"},
 		{"duplicate", []PrincipalSpec{{Name: "p1", Type: "user"}, {Name: "p1", Type: "user"}}, 1, "duplicate"},
+		{"invalid attribute key", []PrincipalSpec{{Name: "p1", Type: "user", Attributes: map[string]string{"1region": "EU"}}}, 1, "invalid attribute key"},
+		{"attribute value too long", []PrincipalSpec{{Name: "p1", Type: "user", Attributes: map[string]string{"region": strings.Repeat("x", 1025)}}}, 1, "exceeds 1024"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -322,6 +324,17 @@ func TestValidate_GrantErrors(t *testing.T) {
 			},
 			"duplicate grant",
 		},
+		{
+			"malformed expires_at",
+			&DesiredState{
+				Principals: basePrincipals,
+				Catalogs:   baseCatalogs,
+				Grants: []GrantSpec{
+					{Principal: "user1", PrincipalType: "user", SecurableType: "catalog", Securable: "main", Privilege: "USAGE", ExpiresAt: strPtr("next tuesday")},
+				},
+			},
+			"expires_at must be an RFC3339 timestamp",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -453,6 +466,21 @@ func TestValidate_TableErrors(t *testing.T) {
 			},
 			"duplicate column name",
 		},
+		{
+			"partition column not in columns",
+			&DesiredState{
+				Catalogs: baseCatalogs,
+				Schemas:  baseSchemas,
+				Tables: []TableResource{
+					{CatalogName: "main", SchemaName: "analytics", TableName: "t1", Spec: TableSpec{
+						TableType:   "MANAGED",
+						Columns:     []ColumnDef{{Name: "id", Type: "BIGINT"}},
+						PartitionBy: []string{"region"},
+					}},
+				},
+			},
+			`partition column "region" is not a column of the table`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -470,6 +498,46 @@ func TestValidate_TableErrors(t *testing.T) {
 	}
 }
 
+func TestValidate_ViewErrors(t *testing.T) {
+	base := func(spec ViewSpec) *DesiredState {
+		return &DesiredState{
+			Catalogs: []CatalogResource{
+				{CatalogName: "main", Spec: CatalogSpec{MetastoreType: "sqlite", DSN: "/db", DataPath: "/data"}},
+			},
+			Schemas: []SchemaResource{{CatalogName: "main", SchemaName: "analytics"}},
+			Views:   []ViewResource{{CatalogName: "main", SchemaName: "analytics", ViewName: "daily", Spec: spec}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		spec    ViewSpec
+		wantErr string
+	}{
+		{"schedule without materialized", ViewSpec{ViewDefinition: "SELECT 1", RefreshSchedule: "0 * * * *"}, "refresh_schedule requires materialized: true"},
+		{"invalid schedule", ViewSpec{ViewDefinition: "SELECT 1", Materialized: true, RefreshSchedule: "hourly"}, "refresh_schedule is invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(base(tt.spec))
+			require.NotEmpty(t, errs)
+			found := false
+			for _, e := range errs {
+				if containsStr(e.Error(), tt.wantErr) {
+					found = true
+					break
+				}
+			}
+			assert.True(t, found, "expected error containing %q, got %v", tt.wantErr, errs)
+		})
+	}
+
+	t.Run("scheduled materialized view", func(t *testing.T) {
+		errs := Validate(base(ViewSpec{ViewDefinition: "SELECT 1", Materialized: true, RefreshSchedule: "0 * * * *"}))
+		assert.Empty(t, errs)
+	})
+}
+
 func TestValidate_StorageCredentialErrors(t *testing.T) {
 	tests := []struct {
 		name    string
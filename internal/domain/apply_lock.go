@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// ApplyLockName is the name of the advisory lock held by declarative apply.
+const ApplyLockName = "apply"
+
+// Apply lock lease bounds.
+const (
+	DefaultApplyLockTTL = 10 * time.Minute
+	MaxApplyLockTTL     = 2 * time.Hour
+)
+
+// ApplyLock is an advisory lease that serializes declarative applies against
+// one server. A lease that has passed ExpiresAt is stale and may be taken over.
+type ApplyLock struct {
+	Name          string
+	LeaseID       string
+	Owner         string
+	PrincipalName string
+	AcquiredAt    time.Time
+	ExpiresAt     time.Time
+}
+
+// Expired reports whether the lease has lapsed at now.
+func (l *ApplyLock) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// AcquireApplyLockRequest holds parameters for acquiring the apply lock.
+type AcquireApplyLockRequest struct {
+	Owner string
+	TTL   time.Duration
+	Force bool
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Operations that can be configured to require a second admin's approval.
+const (
+	ApprovalOpSetAdmin      = "set_admin"
+	ApprovalOpDeleteCatalog = "delete_catalog"
+)
+
+// ApprovalOperations lists every operation that supports dual control.
+var ApprovalOperations = []string{ApprovalOpSetAdmin, ApprovalOpDeleteCatalog}
+
+// Approval request statuses. A request is PENDING until a second admin
+// approves or rejects it; an approved request whose operation fails is FAILED.
+const (
+	ApprovalStatusPending  = "PENDING"
+	ApprovalStatusApproved = "APPROVED"
+	ApprovalStatusRejected = "REJECTED"
+	ApprovalStatusFailed   = "FAILED"
+)
+
+// ApprovalRequest is a destructive admin operation held until an admin other
+// than the requester approves it.
+type ApprovalRequest struct {
+	ID           string
+	Operation    string
+	Params       map[string]string
+	Status       string
+	RequestedBy  string
+	DecidedBy    *string
+	ErrorMessage *string
+	CreatedAt    time.Time
+	DecidedAt    *time.Time
+}
+
+// ApprovalFilter narrows an approval request listing. A nil Status matches
+// every status.
+type ApprovalFilter struct {
+	Status *string
+	Page   PageRequest
+}
@@ -17,3 +17,40 @@ type AuditEntry struct {
 	RowsReturned   *int64
 	CreatedAt      time.Time
 }
+
+// AuditActionAccessDenied is the audit action recorded for denied
+// authorization attempts, distinct from the action that was attempted.
+const AuditActionAccessDenied = "ACCESS_DENIED"
+
+// AccessDenial describes an authorization attempt that was refused.
+type AccessDenial struct {
+	PrincipalName string
+	Action        string // attempted privilege or operation, e.g. SELECT
+	Securable     string // object the action targeted, e.g. a table path
+	Reason        string
+}
+
+// AuditArchive records an object of archived audit log entries. The entries
+// are hash chained: PrevHash is the chain hash the archive continues from,
+// the Hash of the archive before it, and Hash is the chain hash of its last
+// entry.
+type AuditArchive struct {
+	ID             string
+	Location       string // object URL the entries were written to
+	EntryCount     int64
+	FirstEntryID   string
+	LastEntryID    string
+	FirstCreatedAt time.Time
+	LastCreatedAt  time.Time
+	PrevHash       string // empty for the first archive
+	Hash           string
+	CreatedAt      time.Time
+}
+
+// AuditArchiveResult reports an audit log archival run.
+type AuditArchiveResult struct {
+	Before   time.Time // entries recorded before this time were archived
+	Archives []AuditArchive
+	Archived int64
+	Deleted  int64
+}
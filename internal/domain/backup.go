@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// MetastoreSnapshotter writes a consistent copy of the metastore to a file.
+type MetastoreSnapshotter interface {
+	Snapshot(ctx context.Context, destPath string) error
+}
+
+// BackupOptions controls how a metastore backup is produced.
+type BackupOptions struct {
+	Gzip bool
+}
+
+// Backup is a metastore backup ready to be streamed to the caller. Closing
+// Content releases the backing temporary file.
+type Backup struct {
+	Content       io.ReadCloser
+	SizeBytes     int64
+	Checksum      string // SHA-256 of Content, hex encoded
+	SchemaVersion int64  // highest applied migration version
+	Gzip          bool
+	CreatedAt     time.Time
+}
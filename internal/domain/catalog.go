@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // CatalogInfo represents the single DuckLake catalog.
 type CatalogInfo struct {
@@ -22,6 +27,8 @@ type SchemaDetail struct {
 	UpdatedAt   time.Time
 	Tags        []Tag
 	DeletedAt   *time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // TableDetail is an enriched table representation for the catalog API.
@@ -40,10 +47,13 @@ type TableDetail struct {
 	Tags         []Tag
 	Statistics   *TableStatistics
 	DeletedAt    *time.Time
-	StoragePath  string // resolved DuckLake data path for MANAGED tables
-	SourcePath   string // populated for EXTERNAL tables
-	FileFormat   string // populated for EXTERNAL tables
-	LocationName string // populated for EXTERNAL tables
+	StoragePath  string   // resolved DuckLake data path for MANAGED tables
+	SourcePath   string   // populated for EXTERNAL tables
+	FileFormat   string   // populated for EXTERNAL tables
+	LocationName string   // populated for EXTERNAL tables
+	PartitionBy  []string // partition columns of MANAGED tables, in key order
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // ColumnDetail represents a column with full metadata.
@@ -76,6 +86,8 @@ func (r *CreateSchemaRequest) Validate() error {
 type UpdateSchemaRequest struct {
 	Comment    *string
 	Properties map[string]string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // UpdateTableRequest holds parameters for updating table metadata.
@@ -83,6 +95,8 @@ type UpdateTableRequest struct {
 	Comment    *string
 	Properties map[string]string
 	Owner      *string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // UpdateColumnRequest holds parameters for updating column metadata.
@@ -105,15 +119,187 @@ type TableStatistics struct {
 	ProfiledBy     string
 }
 
+// Table maintenance operations reported in TableMaintenanceResult.Operation.
+const (
+	TableMaintenanceCompact = "COMPACT"
+	TableMaintenanceVacuum  = "VACUUM"
+)
+
+// DefaultVacuumOlderThan is the snapshot age a vacuum expires when the
+// caller does not give one.
+const DefaultVacuumOlderThan = 7 * 24 * time.Hour
+
+// TableMaintenanceResult reports the effect of a compaction or vacuum on a
+// DuckLake table's data files. For COMPACT the counts cover the files the
+// current snapshot reads; for VACUUM they cover every file still stored for
+// the table, including those only older snapshots reference.
+type TableMaintenanceResult struct {
+	Operation        string
+	FilesBefore      int64
+	FilesAfter       int64
+	BytesBefore      int64
+	BytesAfter       int64
+	SnapshotsExpired int64
+}
+
+// BytesReclaimed returns how much storage the operation freed, or zero when
+// the table grew (compaction rewrites data before old files are cleaned up).
+func (r *TableMaintenanceResult) BytesReclaimed() int64 {
+	if r.BytesAfter >= r.BytesBefore {
+		return 0
+	}
+	return r.BytesBefore - r.BytesAfter
+}
+
+// ParseSnapshotAge parses a snapshot age such as "7d", "36h" or "90m". A "d"
+// suffix counts whole days; anything else must be a Go duration.
+func ParseSnapshotAge(v string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, ErrValidation("invalid age %q: expected a number of days such as 7d", v)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(v); err != nil {
+			return 0, ErrValidation("invalid age %q: expected a duration such as 7d or 36h", v)
+		}
+	}
+	if d <= 0 {
+		return 0, ErrValidation("invalid age %q: must be positive", v)
+	}
+	return d, nil
+}
+
+// RollbackTableRequest selects the DuckLake snapshot a table is restored to,
+// either by snapshot ID or as of a point in time. Without Confirm the
+// rollback is only previewed.
+type RollbackTableRequest struct {
+	SnapshotID *int64
+	Timestamp  *time.Time
+	Confirm    bool
+}
+
+// Validate checks that exactly one rollback target is set.
+func (r *RollbackTableRequest) Validate() error {
+	switch {
+	case r.SnapshotID == nil && r.Timestamp == nil:
+		return ErrValidation("one of snapshot_id or timestamp is required")
+	case r.SnapshotID != nil && r.Timestamp != nil:
+		return ErrValidation("snapshot_id and timestamp are mutually exclusive")
+	case r.SnapshotID != nil && *r.SnapshotID < 0:
+		return ErrValidation("snapshot_id must not be negative")
+	}
+	return nil
+}
+
+// RenameRequest holds parameters for renaming a table or view in place.
+type RenameRequest struct {
+	NewName string
+	// Force renames even when views or models read the object; their SQL
+	// keeps the old name until it is updated.
+	Force bool
+}
+
+// Validate checks that a new name is given.
+func (r *RenameRequest) Validate() error {
+	if strings.TrimSpace(r.NewName) == "" {
+		return ErrValidation("new_name is required")
+	}
+	return nil
+}
+
+// Dependent object types reported by a rollback's impact analysis.
+const (
+	DependentObjectView  = "VIEW"
+	DependentObjectModel = "MODEL"
+)
+
+// DependentObject is a view or model that reads from a table and may be
+// affected when the table's data changes.
+type DependentObject struct {
+	Type string // DependentObjectView or DependentObjectModel
+	Name string // schema.view for views, project.model for models
+}
+
+// TableRollbackResult reports a table rollback. RowsAfter is the row count
+// at the target snapshot when previewing and the restored count once
+// applied. Restoring writes a new snapshot, so NewSnapshot is only set
+// when Applied.
+type TableRollbackResult struct {
+	CurrentSnapshot int64
+	TargetSnapshot  int64
+	NewSnapshot     *int64
+	RowsBefore      int64
+	RowsAfter       int64
+	Applied         bool
+	Dependents      []DependentObject
+}
+
+// CopyPoliciesRequest names the table, in the source table's catalog, that a
+// table's grants, row filters, column masks and tags are copied to. With
+// DryRun the copy is only reported.
+type CopyPoliciesRequest struct {
+	TargetSchema string
+	TargetTable  string
+	DryRun       bool
+}
+
+// Validate checks that a target table is given.
+func (r *CopyPoliciesRequest) Validate() error {
+	if strings.TrimSpace(r.TargetSchema) == "" {
+		return ErrValidation("target_schema is required")
+	}
+	if strings.TrimSpace(r.TargetTable) == "" {
+		return ErrValidation("target_table is required")
+	}
+	return nil
+}
+
+// Policy kinds reported by a policy copy.
+const (
+	PolicyKindGrant      = "GRANT"
+	PolicyKindRowFilter  = "ROW_FILTER"
+	PolicyKindColumnMask = "COLUMN_MASK"
+	PolicyKindTag        = "TAG"
+)
+
+// CopiedPolicy is one policy copied, or with a dry run to be copied, to the
+// target table.
+type CopiedPolicy struct {
+	Kind        string // one of the PolicyKind constants
+	Description string
+}
+
+// SkippedPolicy is a source policy that was not copied, with the reason.
+type SkippedPolicy struct {
+	Kind        string
+	Description string
+	Reason      string
+}
+
+// CopyPoliciesResult reports a policy copy between two tables. Policies the
+// target already has are neither copied nor reported as skipped.
+type CopyPoliciesResult struct {
+	Source  string // schema.table
+	Target  string // schema.table
+	DryRun  bool
+	Copied  []CopiedPolicy
+	Skipped []SkippedPolicy
+}
+
 // CreateTableRequest holds parameters for creating a new table.
 type CreateTableRequest struct {
 	Name         string
 	Columns      []CreateColumnDef
 	Comment      string
-	TableType    string // "MANAGED" (default) or "EXTERNAL"
-	SourcePath   string // required for EXTERNAL
-	FileFormat   string // "parquet" (default) or "csv"; only for EXTERNAL
-	LocationName string // required for EXTERNAL
+	TableType    string   // "MANAGED" (default) or "EXTERNAL"
+	SourcePath   string   // required for EXTERNAL
+	FileFormat   string   // "parquet" (default) or "csv"; only for EXTERNAL
+	LocationName string   // required for EXTERNAL
+	PartitionBy  []string // partition columns; only for MANAGED
 }
 
 // Validate checks that the request is well-formed.
@@ -134,9 +320,73 @@ func (r *CreateTableRequest) Validate() error {
 	default:
 		return ErrValidation("unsupported table_type: %q", r.TableType)
 	}
+	return r.validatePartitionBy()
+}
+
+// validatePartitionBy checks that every partition column is a distinct
+// column of the table. Column names compare case-insensitively, as in DuckDB.
+func (r *CreateTableRequest) validatePartitionBy() error {
+	if len(r.PartitionBy) == 0 {
+		return nil
+	}
+	if r.TableType == TableTypeExternal {
+		return ErrValidation("partition_by is only supported for MANAGED tables")
+	}
+	seen := make(map[string]bool, len(r.PartitionBy))
+	for _, p := range r.PartitionBy {
+		key := strings.ToLower(p)
+		if seen[key] {
+			return ErrValidation("partition column %q is listed more than once", p)
+		}
+		seen[key] = true
+		found := false
+		for _, c := range r.Columns {
+			if strings.EqualFold(c.Name, p) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrValidation("partition column %q is not a column of table %q", p, r.Name)
+		}
+	}
 	return nil
 }
 
+// DefinitionDiff describes how existing differs from the table the request
+// would create in table type, columns or partition columns, or returns "" when
+// they match. Columns are only compared when the request lists them; names and
+// types compare case-insensitively, as in DuckDB.
+func (r *CreateTableRequest) DefinitionDiff(existing *TableDetail) string {
+	wantType := r.TableType
+	if wantType == "" {
+		wantType = TableTypeManaged
+	}
+	if !strings.EqualFold(wantType, existing.TableType) {
+		return fmt.Sprintf("table_type is %s, not %s", existing.TableType, wantType)
+	}
+	if len(r.Columns) > 0 {
+		if len(r.Columns) != len(existing.Columns) {
+			return fmt.Sprintf("it has %d columns, not %d", len(existing.Columns), len(r.Columns))
+		}
+		for i, c := range r.Columns {
+			have := existing.Columns[i]
+			if !strings.EqualFold(c.Name, have.Name) || !strings.EqualFold(c.Type, have.Type) {
+				return fmt.Sprintf("column %d is %s %s, not %s %s", i+1, have.Name, have.Type, c.Name, c.Type)
+			}
+		}
+	}
+	if len(r.PartitionBy) != len(existing.PartitionBy) {
+		return fmt.Sprintf("partition_by is %v, not %v", existing.PartitionBy, r.PartitionBy)
+	}
+	for i, p := range r.PartitionBy {
+		if !strings.EqualFold(p, existing.PartitionBy[i]) {
+			return fmt.Sprintf("partition_by is %v, not %v", existing.PartitionBy, r.PartitionBy)
+		}
+	}
+	return ""
+}
+
 // CreateColumnDef defines a column for table creation.
 type CreateColumnDef struct {
 	Name string
@@ -152,3 +402,29 @@ type MetastoreSummary struct {
 	SchemaCount    int64
 	TableCount     int64
 }
+
+// CatalogStatsLargestTables is how many of the largest tables CatalogStats
+// lists.
+const CatalogStatsLargestTables = 10
+
+// CatalogStats summarizes the size of a catalog from its stored table
+// statistics, without scanning any data.
+type CatalogStats struct {
+	CatalogName string
+	SchemaCount int64
+	// TableCount includes external tables; they have no stored statistics
+	// and add nothing to TotalRows and TotalBytes.
+	TableCount    int64
+	ViewCount     int64
+	TotalRows     int64
+	TotalBytes    int64
+	LargestTables []TableSize
+}
+
+// TableSize is the stored row count and data size of a table.
+type TableSize struct {
+	SchemaName string
+	TableName  string
+	RowCount   int64
+	SizeBytes  int64
+}
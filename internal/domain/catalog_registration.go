@@ -34,6 +34,8 @@ type CatalogRegistration struct {
 	Comment       string
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // CreateCatalogRequest holds parameters for registering a new catalog.
@@ -50,4 +52,6 @@ type UpdateCatalogRegistrationRequest struct {
 	Comment  *string
 	DataPath *string
 	DSN      *string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCreateTableRequest_PartitionBy(t *testing.T) {
+	cols := []CreateColumnDef{{Name: "id", Type: "BIGINT"}, {Name: "Region", Type: "VARCHAR"}}
+	tests := []struct {
+		name    string
+		req     CreateTableRequest
+		wantErr string
+	}{
+		{
+			name: "partition column exists",
+			req:  CreateTableRequest{Name: "events", Columns: cols, PartitionBy: []string{"region"}},
+		},
+		{
+			name:    "unknown partition column",
+			req:     CreateTableRequest{Name: "events", Columns: cols, PartitionBy: []string{"day"}},
+			wantErr: `partition column "day" is not a column of table "events"`,
+		},
+		{
+			name:    "duplicate partition column",
+			req:     CreateTableRequest{Name: "events", Columns: cols, PartitionBy: []string{"id", "ID"}},
+			wantErr: "listed more than once",
+		},
+		{
+			name: "external table",
+			req: CreateTableRequest{
+				Name: "ext", Columns: cols, TableType: TableTypeExternal,
+				SourcePath: "s3://bucket/ext/", LocationName: "loc", PartitionBy: []string{"id"},
+			},
+			wantErr: "only supported for MANAGED tables",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCreateTableRequest_DefinitionDiff(t *testing.T) {
+	existing := &TableDetail{
+		Name:        "events",
+		TableType:   TableTypeManaged,
+		Columns:     []ColumnDetail{{Name: "id", Type: "BIGINT"}, {Name: "region", Type: "VARCHAR"}},
+		PartitionBy: []string{"region"},
+	}
+	cols := []CreateColumnDef{{Name: "id", Type: "bigint"}, {Name: "Region", Type: "VARCHAR"}}
+	tests := []struct {
+		name     string
+		req      CreateTableRequest
+		wantDiff string
+	}{
+		{
+			name: "same definition",
+			req:  CreateTableRequest{Name: "events", Columns: cols, PartitionBy: []string{"REGION"}},
+		},
+		{
+			name:     "column type differs",
+			req:      CreateTableRequest{Name: "events", Columns: []CreateColumnDef{{Name: "id", Type: "INTEGER"}, cols[1]}, PartitionBy: []string{"region"}},
+			wantDiff: "column 1 is id BIGINT, not id INTEGER",
+		},
+		{
+			name:     "extra column",
+			req:      CreateTableRequest{Name: "events", Columns: append(cols, CreateColumnDef{Name: "day", Type: "DATE"}), PartitionBy: []string{"region"}},
+			wantDiff: "it has 2 columns, not 3",
+		},
+		{
+			name:     "partitioning differs",
+			req:      CreateTableRequest{Name: "events", Columns: cols},
+			wantDiff: "partition_by is [region], not []",
+		},
+		{
+			name: "table type differs",
+			req: CreateTableRequest{
+				Name: "events", TableType: TableTypeExternal, SourcePath: "s3://bucket/events/", LocationName: "loc",
+			},
+			wantDiff: "table_type is MANAGED, not EXTERNAL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantDiff, tt.req.DefinitionDiff(existing))
+		})
+	}
+}
+
+func TestParseSnapshotAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "7d", want: 7 * 24 * time.Hour},
+		{in: "36h", want: 36 * time.Hour},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "0d", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "xd", wantErr: true},
+		{in: "week", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSnapshotAge(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTableMaintenanceResult_BytesReclaimed(t *testing.T) {
+	assert.Equal(t, int64(300), (&TableMaintenanceResult{BytesBefore: 1000, BytesAfter: 700}).BytesReclaimed())
+	assert.Equal(t, int64(0), (&TableMaintenanceResult{BytesBefore: 700, BytesAfter: 1000}).BytesReclaimed())
+}
+
+func TestRollbackTableRequest_Validate(t *testing.T) {
+	snap := int64(3)
+	neg := int64(-1)
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		req     RollbackTableRequest
+		wantErr string
+	}{
+		{name: "snapshot id", req: RollbackTableRequest{SnapshotID: &snap}},
+		{name: "timestamp", req: RollbackTableRequest{Timestamp: &ts}},
+		{name: "no target", req: RollbackTableRequest{}, wantErr: "one of snapshot_id or timestamp is required"},
+		{name: "both targets", req: RollbackTableRequest{SnapshotID: &snap, Timestamp: &ts}, wantErr: "mutually exclusive"},
+		{name: "negative snapshot", req: RollbackTableRequest{SnapshotID: &neg}, wantErr: "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
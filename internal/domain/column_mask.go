@@ -2,11 +2,15 @@ package domain
 
 import "time"
 
-// ColumnMask represents a masking expression for a column on a table.
+// ColumnMask represents a masking expression for a column on a table, or for
+// every column carrying a tag. A tag-based mask has TagID set and empty
+// TableID and ColumnName; it applies to whichever columns hold the tag when a
+// query runs, so a column tagged later is masked without further setup.
 type ColumnMask struct {
 	ID             string
 	TableID        string
 	ColumnName     string
+	TagID          *string
 	MaskExpression string
 	Description    string
 	CreatedAt      time.Time
@@ -16,12 +20,22 @@ type ColumnMask struct {
 type CreateColumnMaskRequest struct {
 	TableID        string
 	ColumnName     string
+	TagID          string // targets tagged columns instead of TableID and ColumnName
 	MaskExpression string
 	Description    string
 }
 
 // Validate checks that the request is well-formed.
 func (r *CreateColumnMaskRequest) Validate() error {
+	if r.TagID != "" {
+		if r.TableID != "" || r.ColumnName != "" {
+			return ErrValidation("a tag-based mask cannot also name a table or column")
+		}
+		if r.MaskExpression == "" {
+			return ErrValidation("mask_expression is required")
+		}
+		return nil
+	}
 	if r.TableID == "" {
 		return ErrValidation("table_id is required")
 	}
@@ -67,8 +81,36 @@ type ColumnMaskBinding struct {
 
 // ColumnMaskWithBinding is a denormalised view combining mask + binding info,
 // returned by repository queries that join column_masks with their bindings.
+// TagID is set when the mask reaches ColumnName through a tag on the column.
 type ColumnMaskWithBinding struct {
 	ColumnName     string
 	MaskExpression string
 	SeeOriginal    bool
+	TagID          *string
+}
+
+// Preview sample sizes for ColumnMaskService.Preview.
+const (
+	DefaultColumnMaskPreviewLimit = 10
+	MaxColumnMaskPreviewLimit     = 100
+)
+
+// ColumnMaskPreview shows how a masked column reads for one principal: each
+// sampled row pairs the stored value with the value the principal sees.
+// Rows are sampled with the principal's row filters applied. Masked is false
+// when no mask applies to the principal, e.g. for a see_original binding.
+type ColumnMaskPreview struct {
+	ColumnMaskID   string
+	TableName      string // schema.table
+	ColumnName     string
+	Principal      string
+	Masked         bool
+	MaskExpression string // the expression applied for the principal; empty when not Masked
+	Rows           []ColumnMaskPreviewRow
+}
+
+// ColumnMaskPreviewRow is one sampled value before and after masking.
+type ColumnMaskPreviewRow struct {
+	Original interface{}
+	Masked   interface{}
 }
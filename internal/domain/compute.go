@@ -22,6 +22,48 @@ type ComputeEndpoint struct {
 	Owner       string // principal who created it
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	Load *ComputeEndpointLoad // latest polled load; nil until the agent has been polled
+
+	RowVersion int64 // bumped by every update; see ETag
+}
+
+// Scaling hints derived from an endpoint's reported load.
+const (
+	ScalingHintScaleUp     = "SCALE_UP"    // statements are queuing or every slot is busy
+	ScalingHintOK          = "OK"          // busy but keeping up
+	ScalingHintIdle        = "IDLE"        // nothing running or queued
+	ScalingHintUnreachable = "UNREACHABLE" // the last poll failed
+)
+
+// ComputeEndpointLoad is the load a remote agent reported on the control
+// plane's most recent health poll.
+type ComputeEndpointLoad struct {
+	EndpointID           string
+	Reachable            bool
+	ActiveQueries        int64
+	WaitingQueries       int64 // statements queued for a concurrency slot
+	MaxConcurrentQueries int64 // 0 when the agent does not limit concurrency
+	QueuedJobs           int64
+	RunningJobs          int64
+	MemoryUsedMB         int64
+	LastError            string
+	LastHeartbeatAt      *time.Time // last successful poll
+	CheckedAt            time.Time  // last poll attempt
+}
+
+// ScalingHint summarises the load as a capacity recommendation.
+func (l *ComputeEndpointLoad) ScalingHint() string {
+	switch {
+	case !l.Reachable:
+		return ScalingHintUnreachable
+	case l.WaitingQueries > 0, l.MaxConcurrentQueries > 0 && l.ActiveQueries >= l.MaxConcurrentQueries:
+		return ScalingHintScaleUp
+	case l.ActiveQueries == 0 && l.QueuedJobs == 0 && l.RunningJobs == 0:
+		return ScalingHintIdle
+	default:
+		return ScalingHintOK
+	}
 }
 
 // ComputeAssignment binds a principal to a compute endpoint.
@@ -53,6 +95,8 @@ type UpdateComputeEndpointRequest struct {
 	MaxMemoryGB *int64
 	AuthToken   *string
 	Status      *string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // Validate checks that the request is well-formed.
@@ -119,6 +163,27 @@ func ValidateComputeEndpointURL(rawURL, endpointType string) error {
 	return nil
 }
 
+// ComputeEndpointAgent is an additional agent URL serving a REMOTE endpoint.
+// Queries routed to the endpoint are spread across its URL and all agents.
+type ComputeEndpointAgent struct {
+	ID         string
+	EndpointID string
+	URL        string
+	CreatedAt  time.Time
+}
+
+// CreateComputeEndpointAgentRequest holds parameters for adding an agent URL
+// to an endpoint.
+type CreateComputeEndpointAgentRequest struct {
+	URL string
+}
+
+// Agent routing policies for endpoints backed by more than one agent.
+const (
+	AgentRoutingRoundRobin  = "round_robin"  // rotate across healthy agents
+	AgentRoutingLeastLoaded = "least_loaded" // pick the agent with the fewest running and queued statements
+)
+
 // CreateComputeAssignmentRequest holds parameters for assigning a principal to an endpoint.
 type CreateComputeAssignmentRequest struct {
 	PrincipalID   string
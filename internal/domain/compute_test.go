@@ -222,3 +222,22 @@ func TestValidateCreateComputeAssignmentRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeEndpointLoad_ScalingHint(t *testing.T) {
+	tests := []struct {
+		name string
+		load ComputeEndpointLoad
+		want string
+	}{
+		{"unreachable", ComputeEndpointLoad{Reachable: false, WaitingQueries: 3}, ScalingHintUnreachable},
+		{"queue_building", ComputeEndpointLoad{Reachable: true, ActiveQueries: 2, MaxConcurrentQueries: 4, WaitingQueries: 1}, ScalingHintScaleUp},
+		{"all_slots_busy", ComputeEndpointLoad{Reachable: true, ActiveQueries: 4, MaxConcurrentQueries: 4}, ScalingHintScaleUp},
+		{"busy_without_limit", ComputeEndpointLoad{Reachable: true, ActiveQueries: 12}, ScalingHintOK},
+		{"idle", ComputeEndpointLoad{Reachable: true}, ScalingHintIdle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.load.ScalingHint())
+		})
+	}
+}
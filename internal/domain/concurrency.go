@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Updatable resources carry a row version that their repository bumps on
+// every update. The API exposes it as the resource's ETag; an update sent
+// with If-Match is applied only while the row version still equals the one
+// the client read, which the repository checks in the UPDATE itself.
+
+// ETag returns the strong entity tag of a resource at rowVersion. A resource
+// with no stored row yet, such as a schema without metadata, is at row
+// version 0.
+func ETag(rowVersion int64) string {
+	return `"` + strconv.FormatInt(rowVersion, 10) + `"`
+}
+
+// ParseIfMatch returns the row version an If-Match header requires, or nil
+// when the header is empty or "*" and the update is unconditional. A header
+// that is not a single ETag of this API can never match, so it yields a
+// PreconditionFailedError.
+func ParseIfMatch(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	if len(header) >= 2 && header[0] == '"' && header[len(header)-1] == '"' {
+		if v, err := strconv.ParseInt(header[1:len(header)-1], 10, 64); err == nil && v >= 0 {
+			return &v, nil
+		}
+	}
+	return nil, ErrPreconditionFailed("If-Match %s does not match the resource's ETag; fetch it again and retry", header)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	v := func(n int64) *int64 { return &n }
+	tests := []struct {
+		name    string
+		header  string
+		want    *int64
+		wantErr bool
+	}{
+		{"absent", "", nil, false},
+		{"any", "*", nil, false},
+		{"etag", ETag(7), v(7), false},
+		{"surrounding space", " " + ETag(7) + " ", v(7), false},
+		{"weak", "W/" + ETag(7), nil, true},
+		{"unquoted", "7", nil, true},
+		{"body hash", `"3f2a9c"`, nil, true},
+		{"zero", ETag(0), v(0), false},
+		{"negative", `"-1"`, nil, true},
+		{"list", ETag(7) + ", " + ETag(8), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIfMatch(tt.header)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorAs(t, err, new(*PreconditionFailedError))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
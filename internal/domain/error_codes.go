@@ -0,0 +1,70 @@
+package domain
+
+import "net/http"
+
+// ErrorCode is a stable, machine-readable code carried in the error_code
+// field of API error responses. Clients branch on it rather than on the
+// message, which is meant for people and may change. Codes are never renamed
+// or reused; new ones may be added.
+type ErrorCode string
+
+// The error catalog.
+const (
+	ErrorCodeValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	ErrorCodeUnauthenticated    ErrorCode = "UNAUTHENTICATED"
+	ErrorCodeForbidden          ErrorCode = "FORBIDDEN"
+	ErrorCodeNotFound           ErrorCode = "NOT_FOUND"
+	ErrorCodeMethodNotAllowed   ErrorCode = "METHOD_NOT_ALLOWED"
+	ErrorCodeResourceExists     ErrorCode = "RESOURCE_EXISTS"
+	ErrorCodeConflict           ErrorCode = "CONFLICT"
+	ErrorCodePreconditionFailed ErrorCode = "PRECONDITION_FAILED"
+	ErrorCodeRateLimited        ErrorCode = "RATE_LIMITED"
+	ErrorCodeInternal           ErrorCode = "INTERNAL"
+	ErrorCodeNotImplemented     ErrorCode = "NOT_IMPLEMENTED"
+	ErrorCodeUpstreamFailed     ErrorCode = "UPSTREAM_FAILED"
+	ErrorCodeUnavailable        ErrorCode = "UNAVAILABLE"
+)
+
+// ErrorCodeForStatus returns the code for an error answered with an HTTP
+// status. A 409 maps to CONFLICT; use ErrorCodeOf to tell RESOURCE_EXISTS
+// apart.
+func ErrorCodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrorCodeValidationFailed
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthenticated
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrorCodeMethodNotAllowed
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusPreconditionFailed:
+		return ErrorCodePreconditionFailed
+	case http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case http.StatusNotImplemented:
+		return ErrorCodeNotImplemented
+	case http.StatusBadGateway:
+		return ErrorCodeUpstreamFailed
+	case http.StatusServiceUnavailable:
+		return ErrorCodeUnavailable
+	}
+	if status >= 400 && status < 500 {
+		return ErrorCodeValidationFailed
+	}
+	return ErrorCodeInternal
+}
+
+// ErrorCodeOf returns the code for err answered with an HTTP status. It is
+// ErrorCodeForStatus, except that a ConflictError for an existing resource is
+// RESOURCE_EXISTS.
+func ErrorCodeOf(err error, status int) ErrorCode {
+	if IsAlreadyExists(err) {
+		return ErrorCodeResourceExists
+	}
+	return ErrorCodeForStatus(status)
+}
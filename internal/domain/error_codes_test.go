@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCodeForStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   ErrorCode
+	}{
+		{http.StatusBadRequest, ErrorCodeValidationFailed},
+		{http.StatusUnauthorized, ErrorCodeUnauthenticated},
+		{http.StatusForbidden, ErrorCodeForbidden},
+		{http.StatusNotFound, ErrorCodeNotFound},
+		{http.StatusConflict, ErrorCodeConflict},
+		{http.StatusPreconditionFailed, ErrorCodePreconditionFailed},
+		{http.StatusTooManyRequests, ErrorCodeRateLimited},
+		{http.StatusTeapot, ErrorCodeValidationFailed},
+		{http.StatusInternalServerError, ErrorCodeInternal},
+		{http.StatusServiceUnavailable, ErrorCodeUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorCodeForStatus(tt.status))
+		})
+	}
+}
+
+func TestErrorCodeOf(t *testing.T) {
+	assert.Equal(t, ErrorCodeResourceExists, ErrorCodeOf(ErrAlreadyExists("schema %q already exists", "sales"), http.StatusConflict))
+	assert.Equal(t, ErrorCodeResourceExists,
+		ErrorCodeOf(fmt.Errorf("create schema: %w", ErrAlreadyExists("schema %q already exists", "sales")), http.StatusConflict),
+		"wrapped errors keep their code")
+	assert.Equal(t, ErrorCodeConflict, ErrorCodeOf(ErrConflict("approval request %q is not pending", "a-1"), http.StatusConflict))
+	assert.Equal(t, ErrorCodeNotFound, ErrorCodeOf(ErrNotFound("schema %q not found", "sales"), http.StatusNotFound))
+}
@@ -1,7 +1,10 @@
 // Package domain defines core types, interfaces, and errors for the data platform.
 package domain
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // NotFoundError indicates a resource was not found.
 type NotFoundError struct {
@@ -27,6 +30,9 @@ func (e *ValidationError) Error() string { return e.Message }
 // ConflictError indicates a conflict (e.g., duplicate resource).
 type ConflictError struct {
 	Message string
+	// AlreadyExists is true when the conflict is a resource that already
+	// exists, as opposed to one in the wrong state for the request.
+	AlreadyExists bool
 }
 
 func (e *ConflictError) Error() string { return e.Message }
@@ -51,6 +57,18 @@ func ErrConflict(format string, args ...interface{}) *ConflictError {
 	return &ConflictError{Message: fmt.Sprintf(format, args...)}
 }
 
+// ErrAlreadyExists creates a ConflictError for a resource that already exists.
+func ErrAlreadyExists(format string, args ...interface{}) *ConflictError {
+	return &ConflictError{Message: fmt.Sprintf(format, args...), AlreadyExists: true}
+}
+
+// IsAlreadyExists reports whether err is a ConflictError for a resource that
+// already exists.
+func IsAlreadyExists(err error) bool {
+	var conflict *ConflictError
+	return errors.As(err, &conflict) && conflict.AlreadyExists
+}
+
 // NotImplementedError indicates a feature is not yet implemented.
 type NotImplementedError struct {
 	Message string
@@ -62,3 +80,31 @@ func (e *NotImplementedError) Error() string { return e.Message }
 func ErrNotImplemented(format string, args ...interface{}) *NotImplementedError {
 	return &NotImplementedError{Message: fmt.Sprintf(format, args...)}
 }
+
+// UnavailableError indicates a dependency is temporarily unable to serve the
+// request (e.g. the metastore is locked by a concurrent writer). Callers may
+// retry.
+type UnavailableError struct {
+	Message string
+}
+
+func (e *UnavailableError) Error() string { return e.Message }
+
+// ErrUnavailable creates an UnavailableError with a formatted message.
+func ErrUnavailable(format string, args ...interface{}) *UnavailableError {
+	return &UnavailableError{Message: fmt.Sprintf(format, args...)}
+}
+
+// PreconditionFailedError indicates a conditional update of a resource that
+// changed since the client read it.
+type PreconditionFailedError struct {
+	Message string
+}
+
+func (e *PreconditionFailedError) Error() string { return e.Message }
+
+// ErrPreconditionFailed creates a PreconditionFailedError with a formatted
+// message.
+func ErrPreconditionFailed(format string, args ...interface{}) *PreconditionFailedError {
+	return &PreconditionFailedError{Message: fmt.Sprintf(format, args...)}
+}
@@ -50,6 +50,8 @@ type StorageCredential struct {
 	Owner     string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // ExternalLocation represents a named storage location that can be referenced
@@ -65,6 +67,8 @@ type ExternalLocation struct {
 	ReadOnly       bool
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // CreateStorageCredentialRequest holds parameters for creating a credential.
@@ -112,6 +116,8 @@ type UpdateStorageCredentialRequest struct {
 	GCSKeyFilePath *string
 
 	Comment *string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // CreateExternalLocationRequest holds parameters for creating a location.
@@ -131,6 +137,8 @@ type UpdateExternalLocationRequest struct {
 	Comment        *string
 	ReadOnly       *bool
 	Owner          *string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // Validate checks that the request is well-formed.
@@ -33,3 +33,50 @@ type ExternalTableColumn struct {
 	ColumnType      string
 	Position        int
 }
+
+// RegisterDirectoryRequest holds parameters for registering the files under a
+// storage prefix as external tables.
+type RegisterDirectoryRequest struct {
+	CatalogName  string // empty for the default catalog
+	SchemaName   string
+	LocationName string
+	Path         string // storage prefix under the location, e.g. "s3://bucket/landing/"
+	FileFormat   string // "parquet" (default) or "csv"
+	// TableName, when set, registers one table reading every matching file
+	// below Path, with hive-style partition directories as columns. Otherwise
+	// each file directly under Path becomes a table named after the file.
+	TableName string
+}
+
+// Validate checks that the request is well-formed.
+func (r *RegisterDirectoryRequest) Validate() error {
+	if r.SchemaName == "" {
+		return ErrValidation("schema_name is required")
+	}
+	if r.LocationName == "" {
+		return ErrValidation("location_name is required")
+	}
+	if r.Path == "" {
+		return ErrValidation("path is required")
+	}
+	switch r.FileFormat {
+	case "", "parquet", "csv":
+	default:
+		return ErrValidation("unsupported file_format: %q", r.FileFormat)
+	}
+	return nil
+}
+
+// RegisterDirectoryResult reports the tables a directory registration created
+// and the files it passed over.
+type RegisterDirectoryResult struct {
+	Registered []TableDetail
+	Skipped    []SkippedFile
+}
+
+// SkippedFile is a file a directory registration did not register.
+type SkippedFile struct {
+	Path      string
+	TableName string
+	Reason    string
+}
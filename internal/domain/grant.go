@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Privilege constants matching the Databricks/Hive model.
 const (
@@ -55,6 +58,30 @@ type PrivilegeGrant struct {
 	Privilege     string
 	GrantedBy     *string
 	GrantedAt     time.Time
+	ExpiresAt     *time.Time // nil means the grant never expires
+}
+
+// RevokedAccess reports what RevokeAllForPrincipal removed from a
+// principal: its direct grants and, when requested, its group memberships.
+type RevokedAccess struct {
+	Grants      []PrivilegeGrant
+	Memberships []GroupMember
+}
+
+// Expired reports whether the grant has an expiry at or before now.
+func (g *PrivilegeGrant) Expired(now time.Time) bool {
+	return g.ExpiresAt != nil && !g.ExpiresAt.After(now)
+}
+
+// GrantFilter holds filter parameters for listing grants. Set fields are
+// combined with AND.
+type GrantFilter struct {
+	PrincipalID   *string
+	PrincipalType *string
+	SecurableType *string
+	SecurableID   *string
+	Privilege     *string
+	Page          PageRequest
 }
 
 // CreateGrantRequest holds parameters for granting a privilege.
@@ -64,6 +91,7 @@ type CreateGrantRequest struct {
 	SecurableType string // "catalog", "schema", "table"
 	SecurableID   string
 	Privilege     string
+	ExpiresAt     *time.Time // optional; the grant is ignored from this time on
 }
 
 // Validate checks that the request is well-formed.
@@ -83,5 +111,160 @@ func (r *CreateGrantRequest) Validate() error {
 	if r.Privilege == "" {
 		return ErrValidation("privilege is required")
 	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return ErrValidation("expires_at must be in the future")
+	}
+	return nil
+}
+
+// UpdateGrantRequest holds the mutable fields of a grant. Only the expiry can
+// change; principal, securable and privilege identify the grant.
+type UpdateGrantRequest struct {
+	ExpiresAt *time.Time // nil removes the expiry
+}
+
+// Validate checks that the request is well-formed.
+func (r *UpdateGrantRequest) Validate() error {
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return ErrValidation("expires_at must be in the future")
+	}
+	return nil
+}
+
+// DefaultGrant is a privilege template applied to new objects: when a schema
+// or table matching its scope is created, a PrivilegeGrant with the same
+// principal and privilege is recorded on the new object.
+type DefaultGrant struct {
+	ID            string
+	CatalogName   string
+	SchemaName    string // empty matches every schema in the catalog
+	ObjectType    string // SecurableSchema or SecurableTable
+	PrincipalID   string
+	PrincipalType string // "user" or "group"
+	Privilege     string
+	CreatedBy     string
+	CreatedAt     time.Time
+}
+
+// DefaultGrantFilter holds filter parameters for listing default grants.
+type DefaultGrantFilter struct {
+	CatalogName *string
+	SchemaName  *string
+	Page        PageRequest
+}
+
+// CreateDefaultGrantRequest holds parameters for creating a default grant.
+type CreateDefaultGrantRequest struct {
+	CatalogName   string
+	SchemaName    string
+	ObjectType    string
+	PrincipalID   string
+	PrincipalType string
+	Privilege     string
+}
+
+// Validate checks that the request is well-formed.
+func (r *CreateDefaultGrantRequest) Validate() error {
+	if r.CatalogName == "" {
+		return ErrValidation("catalog_name is required")
+	}
+	switch r.ObjectType {
+	case SecurableTable:
+	case SecurableSchema:
+		if r.SchemaName != "" {
+			return ErrValidation("schema default grants apply to a whole catalog; omit schema_name")
+		}
+	default:
+		return ErrValidation("object_type must be 'schema' or 'table'")
+	}
+	if r.PrincipalID == "" {
+		return ErrValidation("principal_id is required")
+	}
+	if r.PrincipalType != "user" && r.PrincipalType != "group" {
+		return ErrValidation("principal_type must be 'user' or 'group'")
+	}
+	if r.Privilege == "" {
+		return ErrValidation("privilege is required")
+	}
 	return nil
 }
+
+// AccessCheckRequest asks whether a principal holds a privilege on a
+// securable. Securable is written as "<type>:<name>", for example
+// "table:demo.analytics.orders" or "schema:analytics".
+type AccessCheckRequest struct {
+	Principal string
+	Privilege string
+	Securable string
+}
+
+// Validate checks that the request is well-formed.
+func (r *AccessCheckRequest) Validate() error {
+	if r.Principal == "" {
+		return ErrValidation("principal is required")
+	}
+	if r.Privilege == "" {
+		return ErrValidation("privilege is required")
+	}
+	if typ, name, ok := strings.Cut(r.Securable, ":"); !ok || typ == "" || name == "" {
+		return ErrValidation("securable must be written as <type>:<name>")
+	}
+	return nil
+}
+
+// AccessDecision is the outcome of a privilege check together with the
+// reason it was reached.
+type AccessDecision struct {
+	Allowed       bool
+	Reason        string
+	Principal     string
+	Privilege     string
+	SecurableType string
+	SecurableID   string
+	Via           *AccessGrantPath // grant that allowed access; nil when denied or for admins
+}
+
+// AccessGrantPath identifies the grant that satisfied a privilege check.
+type AccessGrantPath struct {
+	PrincipalType string // "user" or "group"
+	PrincipalName string
+	SecurableType string
+	SecurableID   string
+	Privilege     string
+}
+
+// How an AccessEntry reaches a principal.
+const (
+	AccessSourceDirect  = "DIRECT"  // granted to the principal on the securable itself
+	AccessSourceGroup   = "GROUP"   // granted on the securable to a group the principal belongs to
+	AccessSourceCascade = "CASCADE" // granted on a parent schema or catalog
+	AccessSourceAdmin   = "ADMIN"   // the principal is an admin
+)
+
+// SecurableAccess lists everyone who can access a securable and the row
+// filters and column masks that apply to each of them.
+type SecurableAccess struct {
+	SecurableType string
+	SecurableID   string
+	Entries       []AccessEntry
+	Policies      []PrincipalPolicies // tables only; principals without policies are omitted
+}
+
+// AccessEntry is one privilege a user or group holds on a securable.
+type AccessEntry struct {
+	PrincipalType string // "user" or "group"
+	PrincipalName string
+	Privilege     string
+	Source        string // one of the AccessSource constants
+	ViaGroup      string // group holding the grant, for users who get it through membership
+	GrantedOnType string // securable the grant is recorded on; empty for admins
+	GrantedOnID   string
+}
+
+// PrincipalPolicies are the row filters and column masks applied to one
+// principal's reads of a table.
+type PrincipalPolicies struct {
+	PrincipalName string
+	RowFilters    []string
+	ColumnMasks   map[string]string // column name -> mask expression
+}
@@ -100,3 +100,51 @@ func TestCreateGrantRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateDefaultGrantRequest_Validate(t *testing.T) {
+	valid := func() CreateDefaultGrantRequest {
+		return CreateDefaultGrantRequest{
+			CatalogName:   "main",
+			SchemaName:    "analytics",
+			ObjectType:    SecurableTable,
+			PrincipalID:   "group-1",
+			PrincipalType: "group",
+			Privilege:     PrivSelect,
+		}
+	}
+	tests := []struct {
+		name    string
+		mutate  func(r *CreateDefaultGrantRequest)
+		wantErr string
+	}{
+		{name: "valid schema-scoped table grant", mutate: func(_ *CreateDefaultGrantRequest) {}},
+		{name: "valid catalog-scoped table grant", mutate: func(r *CreateDefaultGrantRequest) { r.SchemaName = "" }},
+		{name: "valid schema grant", mutate: func(r *CreateDefaultGrantRequest) {
+			r.SchemaName = ""
+			r.ObjectType = SecurableSchema
+			r.Privilege = PrivUseSchema
+		}},
+		{name: "schema grant scoped to a schema", mutate: func(r *CreateDefaultGrantRequest) { r.ObjectType = SecurableSchema }, wantErr: "omit schema_name"},
+		{name: "empty catalog", mutate: func(r *CreateDefaultGrantRequest) { r.CatalogName = "" }, wantErr: "catalog_name is required"},
+		{name: "unsupported object type", mutate: func(r *CreateDefaultGrantRequest) { r.ObjectType = SecurableVolume }, wantErr: "object_type must be"},
+		{name: "empty principal_id", mutate: func(r *CreateDefaultGrantRequest) { r.PrincipalID = "" }, wantErr: "principal_id is required"},
+		{name: "invalid principal_type", mutate: func(r *CreateDefaultGrantRequest) { r.PrincipalType = "robot" }, wantErr: "principal_type must be"},
+		{name: "empty privilege", mutate: func(r *CreateDefaultGrantRequest) { r.Privilege = "" }, wantErr: "privilege is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.mutate(&req)
+			err := req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
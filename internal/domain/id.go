@@ -2,6 +2,7 @@ package domain
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -20,3 +21,25 @@ func DuckLakeIDToString(id int64) string {
 func StringToDuckLakeID(s string) (int64, error) {
 	return strconv.ParseInt(s, 10, 64)
 }
+
+// catalogScopeSep separates the catalog registration ID from the object ID
+// in a catalog-scoped ID.
+const catalogScopeSep = "/"
+
+// CatalogScopedID qualifies the ID of a table or schema with the
+// registration ID of the catalog that owns it. DuckLake IDs are only unique
+// within one metastore, so objects in non-default catalogs are authorized
+// under the scoped form; unscoped IDs belong to the default catalog.
+func CatalogScopedID(catalogID, id string) string {
+	return catalogID + catalogScopeSep + id
+}
+
+// SplitCatalogScopedID reverses CatalogScopedID. ok is false when id is not
+// catalog-scoped.
+func SplitCatalogScopedID(id string) (catalogID, objectID string, ok bool) {
+	catalogID, objectID, ok = strings.Cut(id, catalogScopeSep)
+	if !ok || catalogID == "" || objectID == "" {
+		return "", "", false
+	}
+	return catalogID, objectID, true
+}
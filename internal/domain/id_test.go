@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCatalogScopedID(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		wantCatalog string
+		wantObject  string
+		wantOK      bool
+	}{
+		{"scoped", CatalogScopedID("cat-1", "5"), "cat-1", "5", true},
+		{"unscoped ducklake id", "5", "", "", false},
+		{"missing catalog", "/5", "", "", false},
+		{"missing object", "cat-1/", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalogID, objectID, ok := SplitCatalogScopedID(tt.id)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantCatalog, catalogID)
+			assert.Equal(t, tt.wantObject, objectID)
+		})
+	}
+}
@@ -33,6 +33,8 @@ type Macro struct {
 	CreatedBy   string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // MacroRevision captures a point-in-time macro definition snapshot.
@@ -131,6 +133,8 @@ type UpdateMacroRequest struct {
 	Owner       *string
 	Properties  map[string]string
 	Tags        []string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // PromoteNotebookRequest holds parameters for promoting a notebook cell to a model.
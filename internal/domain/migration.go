@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MigrationStatus describes one metastore schema migration.
+type MigrationStatus struct {
+	Version         int64
+	Name            string
+	Checksum        string // SHA-256 of the migration shipped with this binary
+	AppliedChecksum string // checksum recorded when the migration was applied
+	Applied         bool
+	AppliedAt       *time.Time
+	Drifted         bool // applied, but the shipped file no longer matches
+}
+
+// MigrationRunner reports and applies metastore schema migrations.
+type MigrationRunner interface {
+	Status(ctx context.Context) ([]MigrationStatus, error)
+	Up(ctx context.Context) ([]MigrationStatus, error)
+}
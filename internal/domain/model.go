@@ -50,12 +50,15 @@ type Model struct {
 	Owner           string
 	Tags            []string    // for selector syntax
 	DependsOn       []string    // auto-extracted: ["sales.stg_customers", "warehouse.orders"]
+	MacroDeps       []string    // auto-extracted: registered macros the SQL calls, e.g. ["fmt_money"]
 	Config          ModelConfig // materialization-specific config
 	Contract        *ModelContract
 	Freshness       *FreshnessPolicy
 	CreatedBy       string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // ModelConfig holds materialization-specific configuration.
@@ -123,6 +126,8 @@ type UpdateModelRequest struct {
 	Config          *ModelConfig
 	Contract        *ModelContract
 	Freshness       *FreshnessPolicy
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // Validate checks that the update payload is well-formed.
@@ -198,6 +203,8 @@ type ModelRunStep struct {
 	Status       string
 	Tier         int // DAG tier (0 = roots)
 	RowsAffected *int64
+	BytesWritten *int64 // size of the materialized table's data files, when known
+	DurationMs   *int64
 	StartedAt    *time.Time
 	FinishedAt   *time.Time
 	ErrorMessage *string
@@ -218,6 +225,7 @@ type TriggerModelRunRequest struct {
 	TriggerType   string
 	Variables     map[string]string
 	FullRefresh   bool
+	StoreFailures bool // write the failing rows of failed tests to <model>_test_failures
 }
 
 // Validate checks that the request is well-formed.
@@ -231,6 +239,33 @@ func (r *TriggerModelRunRequest) Validate() error {
 	return nil
 }
 
+// RunModelTestsRequest holds parameters for running a model's tests against
+// its already materialized relation.
+type RunModelTestsRequest struct {
+	TargetCatalog string
+	TargetSchema  string
+	StoreFailures bool
+}
+
+// Validate checks that the request is well-formed.
+func (r *RunModelTestsRequest) Validate() error {
+	if r.TargetCatalog == "" {
+		return ErrValidation("target_catalog is required")
+	}
+	if r.TargetSchema == "" {
+		return ErrValidation("target_schema is required")
+	}
+	return nil
+}
+
+// ModelTestRun is the outcome of running a model's tests outside a model run.
+type ModelTestRun struct {
+	Results []ModelTestResult
+	// FailuresTable names the relation failing rows were stored in; empty
+	// unless store_failures was requested and a test failed.
+	FailuresTable string
+}
+
 // ModelTest defines a test assertion for a model's output.
 type ModelTest struct {
 	ID        string
@@ -257,7 +292,7 @@ type ModelTestResult struct {
 	TestID       string
 	TestName     string
 	Status       string // "PASS", "FAIL", "ERROR"
-	RowsReturned *int64
+	RowsReturned *int64 // number of failing rows; 0 when the test passed
 	ErrorMessage *string
 	CreatedAt    time.Time
 }
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -24,6 +26,8 @@ type Notebook struct {
 	GitPath     *string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // Cell represents a single cell within a notebook.
@@ -57,6 +61,8 @@ func (r *CreateNotebookRequest) Validate() error {
 type UpdateNotebookRequest struct {
 	Name        *string
 	Description *string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // CreateCellRequest holds parameters for creating a cell.
@@ -172,16 +178,66 @@ func (r *CreateGitRepoRequest) Validate() error {
 	if r.URL == "" {
 		return ErrValidation("url is required")
 	}
+	if !validGitRepoURL(r.URL) {
+		return ErrValidation("url must be an https:// or ssh:// URL or user@host:path")
+	}
 	if r.Branch == "" {
 		return ErrValidation("branch is required")
 	}
 	return nil
 }
 
+// scpLikeGitURL matches the scp-like SSH syntax git accepts, user@host:path.
+var scpLikeGitURL = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^\s]+$`)
+
+// validGitRepoURL reports whether u uses a transport git sync allows: https
+// and ssh. Local paths and the file, git, http and ext transports are
+// rejected.
+func validGitRepoURL(u string) bool {
+	if strings.HasPrefix(u, "-") {
+		return false
+	}
+	if !strings.Contains(u, "://") {
+		return scpLikeGitURL.MatchString(u)
+	}
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	return parsed.Scheme == "https" || parsed.Scheme == "ssh"
+}
+
+// GitSyncOptions holds parameters for a sync operation.
+type GitSyncOptions struct {
+	Ref    string // branch, tag or commit; defaults to the repo's branch
+	DryRun bool
+}
+
+// GitSyncAction is the change a sync makes to one notebook.
+type GitSyncAction string
+
+// GitSyncAction constants define the changes a sync can make.
+const (
+	GitSyncCreate GitSyncAction = "create"
+	GitSyncUpdate GitSyncAction = "update"
+	GitSyncDelete GitSyncAction = "delete"
+)
+
+// GitSyncChange describes one notebook changed (or, in a dry run, to be
+// changed) by a sync.
+type GitSyncChange struct {
+	Action   GitSyncAction
+	Notebook string
+	Path     string // manifest path within the repository
+}
+
 // GitSyncResult holds the result of a sync operation.
 type GitSyncResult struct {
 	NotebooksCreated int
 	NotebooksUpdated int
 	NotebooksDeleted int
 	CommitSHA        string
+	Ref              string
+	DryRun           bool
+	Changes          []GitSyncChange
 }
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateGitRepoRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "https", url: "https://github.com/org/repo.git"},
+		{name: "ssh", url: "ssh://git@github.com/org/repo.git"},
+		{name: "scp-like ssh", url: "git@github.com:org/repo.git"},
+		{name: "empty url", url: "", wantErr: "url is required"},
+		{name: "http", url: "http://github.com/org/repo.git", wantErr: "url must be"},
+		{name: "git protocol", url: "git://github.com/org/repo.git", wantErr: "url must be"},
+		{name: "file", url: "file:///srv/repo.git", wantErr: "url must be"},
+		{name: "ext", url: "ext::sh -c touch% /tmp/pwned", wantErr: "url must be"},
+		{name: "local path", url: "/srv/repo.git", wantErr: "url must be"},
+		{name: "option", url: "--upload-pack=evil", wantErr: "url must be"},
+		{name: "https without host", url: "https:///repo.git", wantErr: "url must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := CreateGitRepoRequest{URL: tt.url, Branch: "main"}
+			err := req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -3,19 +3,97 @@ package domain
 import (
 	"encoding/base64"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"sync/atomic"
 )
 
-// DefaultMaxResults is the default page size when none is specified.
+// DefaultMaxResults is the default page size when none is specified and no
+// other default is configured.
 const DefaultMaxResults = 100
 
-// MaxMaxResults is the maximum allowed page size.
+// MaxMaxResults is the maximum page size when no other maximum is configured.
 const MaxMaxResults = 1000
 
+// PageSizeCeiling is the largest page size that may be configured or
+// requested. Requests above the configured maximum are clamped to it, but
+// requests above the ceiling are rejected as invalid.
+const PageSizeCeiling = 100000
+
+// Configured page sizes; zero means the built-in default applies.
+var (
+	defaultPageSize atomic.Int64
+	maxPageSize     atomic.Int64
+)
+
+// SetPageSizeLimits configures the page size used when a list request does
+// not ask for one and the largest page a list request may return. It is
+// called once at startup from configuration.
+func SetPageSizeLimits(defaultSize, maxSize int) {
+	defaultPageSize.Store(int64(defaultSize))
+	maxPageSize.Store(int64(maxSize))
+}
+
+// DefaultPageSize returns the configured default page size.
+func DefaultPageSize() int {
+	if v := defaultPageSize.Load(); v > 0 {
+		return int(v)
+	}
+	return DefaultMaxResults
+}
+
+// MaxPageSize returns the configured maximum page size.
+func MaxPageSize() int {
+	if v := maxPageSize.Load(); v > 0 {
+		return int(v)
+	}
+	return MaxMaxResults
+}
+
+// ValidatePageSize checks a page size requested by a client. Values above
+// the configured maximum are valid; ClampPageSize reduces them.
+func ValidatePageSize(n int) error {
+	if n < 1 {
+		return ErrValidation("max_results must be at least 1, got %d", n)
+	}
+	if n > PageSizeCeiling {
+		return ErrValidation("max_results must be at most %d, got %d", PageSizeCeiling, n)
+	}
+	return nil
+}
+
+// ClampPageSize returns the page size to serve for a requested one: the
+// default page size when unset, capped at the maximum page size.
+func ClampPageSize(n int) int {
+	if n <= 0 {
+		return DefaultPageSize()
+	}
+	return min(n, MaxPageSize())
+}
+
+// Sort orders for list operations that accept one. Every order breaks ties
+// by ID, so consecutive pages never overlap or skip rows.
+const (
+	SortByName      = "name"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+)
+
 // PageRequest holds pagination parameters for list operations.
 type PageRequest struct {
 	MaxResults int
 	PageToken  string // opaque token (base64-encoded offset)
+	Sort       string // empty for the list's default order; see ValidateSort
+}
+
+// ValidateSort checks that Sort is empty or one of the orders a list
+// supports.
+func (p PageRequest) ValidateSort(allowed ...string) error {
+	if p.Sort == "" || slices.Contains(allowed, p.Sort) {
+		return nil
+	}
+	return ErrValidation("sort must be one of %s, got %q", strings.Join(allowed, ", "), p.Sort)
 }
 
 // Offset decodes the page token into an integer offset.
@@ -35,15 +113,15 @@ func (p PageRequest) Offset() int {
 	return offset
 }
 
-// Limit returns the effective page size, clamped to [1, MaxMaxResults].
+// Limit returns the effective page size: the default page size when unset,
+// capped at the maximum page size. The cap is never below MaxMaxResults, so
+// lowering the maximum for API clients does not shrink the batches internal
+// callers read with; list handlers clamp client requests with ClampPageSize.
 func (p PageRequest) Limit() int {
 	if p.MaxResults <= 0 {
-		return DefaultMaxResults
-	}
-	if p.MaxResults > MaxMaxResults {
-		return MaxMaxResults
+		return DefaultPageSize()
 	}
-	return p.MaxResults
+	return min(p.MaxResults, max(MaxPageSize(), MaxMaxResults))
 }
 
 // EncodePageToken creates an opaque page token from an offset.
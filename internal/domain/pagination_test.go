@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePageSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		wantErr string
+	}{
+		{name: "one", n: 1},
+		{name: "above default maximum", n: 5000},
+		{name: "ceiling", n: PageSizeCeiling},
+		{name: "zero", n: 0, wantErr: "max_results must be at least 1, got 0"},
+		{name: "negative", n: -5, wantErr: "max_results must be at least 1, got -5"},
+		{name: "absurd", n: PageSizeCeiling + 1, wantErr: "max_results must be at most 100000, got 100001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePageSize(tt.n)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			var validation *ValidationError
+			require.ErrorAs(t, err, &validation)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPageSizeLimits(t *testing.T) {
+	t.Run("built-in defaults", func(t *testing.T) {
+		assert.Equal(t, DefaultMaxResults, ClampPageSize(0))
+		assert.Equal(t, 50, ClampPageSize(50))
+		assert.Equal(t, MaxMaxResults, ClampPageSize(5000))
+		assert.Equal(t, MaxMaxResults, PageRequest{MaxResults: 5000}.Limit())
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		SetPageSizeLimits(25, 200)
+		t.Cleanup(func() { SetPageSizeLimits(0, 0) })
+
+		assert.Equal(t, 25, ClampPageSize(0))
+		assert.Equal(t, 25, PageRequest{}.Limit())
+		assert.Equal(t, 200, ClampPageSize(5000), "oversized page sizes are clamped")
+		assert.Equal(t, 150, ClampPageSize(150))
+		// Internal batch reads keep the built-in maximum.
+		assert.Equal(t, MaxMaxResults, PageRequest{MaxResults: MaxMaxResults}.Limit())
+	})
+
+	t.Run("configured maximum above the built-in one", func(t *testing.T) {
+		SetPageSizeLimits(100, 5000)
+		t.Cleanup(func() { SetPageSizeLimits(0, 0) })
+
+		assert.Equal(t, 5000, ClampPageSize(20000))
+		assert.Equal(t, 5000, PageRequest{MaxResults: 20000}.Limit())
+	})
+}
+
+func TestPageRequest_ValidateSort(t *testing.T) {
+	allowed := []string{SortByName, SortByCreatedAt}
+
+	require.NoError(t, PageRequest{}.ValidateSort(allowed...))
+	require.NoError(t, PageRequest{Sort: SortByName}.ValidateSort(allowed...))
+	require.NoError(t, PageRequest{Sort: SortByCreatedAt}.ValidateSort(allowed...))
+
+	err := PageRequest{Sort: "updated_at"}.ValidateSort(allowed...)
+	var validation *ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Contains(t, err.Error(), `sort must be one of name, created_at, got "updated_at"`)
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -26,6 +27,14 @@ const (
 	TriggerTypeScheduled = "SCHEDULED"
 )
 
+// ScheduledJob is a recurring task that another service runs on the
+// pipeline scheduler, such as a materialized view refresh.
+type ScheduledJob struct {
+	Name     string
+	Schedule string // standard cron expression
+	Run      func(ctx context.Context) error
+}
+
 // Pipeline represents a workflow definition.
 type Pipeline struct {
 	ID               string
@@ -37,6 +46,8 @@ type Pipeline struct {
 	CreatedBy        string
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // Pipeline job type constants.
@@ -121,6 +132,8 @@ type UpdatePipelineRequest struct {
 	ScheduleCron     *string // pointer-to-pointer semantics: nil=no change, non-nil sets
 	IsPaused         *bool
 	ConcurrencyLimit *int
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // CreatePipelineJobRequest holds parameters for creating a pipeline job.
@@ -37,6 +37,13 @@ type CatalogAttacher interface {
 	SetDefaultCatalog(ctx context.Context, catalogName string) error
 }
 
+// DenialRecorder records denied authorization attempts for auditing.
+// Implementations must be safe for concurrent use and must not block the
+// caller on audit failures.
+type DenialRecorder interface {
+	RecordDenial(ctx context.Context, d AccessDenial)
+}
+
 // AuthorizationService defines the interface for permission checking.
 // The engine depends on this interface rather than a concrete service type.
 type AuthorizationService interface {
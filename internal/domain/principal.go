@@ -14,6 +14,7 @@ type Principal struct {
 	ExternalID     *string // IdP subject identifier (JWT `sub` claim)
 	ExternalIssuer *string // Issuer URL that owns this external ID
 	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // Group represents a named collection of principals.
@@ -21,7 +22,9 @@ type Group struct {
 	ID          string
 	Name        string
 	Description string
+	MemberCount int64 // direct members; populated when listing groups
 	CreatedAt   time.Time
+	UpdatedAt   time.Time // bumped when the membership changes
 }
 
 // CreatePrincipalRequest holds parameters for creating a new principal.
@@ -45,12 +48,163 @@ func (r *CreatePrincipalRequest) Validate() error {
 	return nil
 }
 
+// MaxPrincipalImportRows caps the number of principals in one bulk import.
+const MaxPrincipalImportRows = 10000
+
+// PrincipalImportRow is one principal of a bulk import.
+type PrincipalImportRow struct {
+	Name    string
+	Type    string // "user" or "service_principal"; defaults to "user"
+	IsAdmin bool
+	Groups  []string // names of existing groups to add the principal to
+}
+
+// Outcomes of a principal import row.
+const (
+	PrincipalImportCreated = "created"
+	PrincipalImportSkipped = "skipped" // the principal already existed
+)
+
+// PrincipalImportResult reports what a bulk import did with one row.
+type PrincipalImportResult struct {
+	Name        string
+	PrincipalID string
+	Status      string   // PrincipalImportCreated or PrincipalImportSkipped
+	GroupsAdded []string // groups the principal was not yet a member of
+}
+
+// ValidatePrincipalImport checks the rows of a bulk import and defaults empty
+// types to "user". Errors name the offending row by its 1-based position.
+func ValidatePrincipalImport(rows []PrincipalImportRow) error {
+	if len(rows) == 0 {
+		return ErrValidation("at least one principal is required")
+	}
+	if len(rows) > MaxPrincipalImportRows {
+		return ErrValidation("at most %d principals can be imported at once, got %d", MaxPrincipalImportRows, len(rows))
+	}
+	seen := make(map[string]int, len(rows))
+	for i := range rows {
+		r := &rows[i]
+		req := CreatePrincipalRequest{Name: r.Name, Type: r.Type}
+		if err := req.Validate(); err != nil {
+			return ErrValidation("row %d: %s", i+1, err.Error())
+		}
+		r.Type = req.Type
+		if prev, ok := seen[r.Name]; ok {
+			return ErrValidation("row %d: principal %q is already listed in row %d", i+1, r.Name, prev)
+		}
+		seen[r.Name] = i + 1
+		for _, g := range r.Groups {
+			if strings.TrimSpace(g) == "" {
+				return ErrValidation("row %d: group names must not be empty", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// Limits on principal attributes.
+const (
+	MaxPrincipalAttributes        = 64
+	MaxPrincipalAttributeKeyLen   = 128
+	MaxPrincipalAttributeValueLen = 1024
+)
+
+// ValidatePrincipalAttributes checks that attribute keys are identifiers
+// (letters, digits, '_', '-' and '.', not starting with a digit) and that
+// keys, values and the attribute count are within limits.
+func ValidatePrincipalAttributes(attrs map[string]string) error {
+	if len(attrs) > MaxPrincipalAttributes {
+		return ErrValidation("at most %d attributes are allowed, got %d", MaxPrincipalAttributes, len(attrs))
+	}
+	for k, v := range attrs {
+		if !validAttributeKey(k) {
+			return ErrValidation("invalid attribute key %q: use letters, digits, '_', '-' or '.', not starting with a digit", k)
+		}
+		if len(k) > MaxPrincipalAttributeKeyLen {
+			return ErrValidation("attribute key %q exceeds %d characters", k, MaxPrincipalAttributeKeyLen)
+		}
+		if len(v) > MaxPrincipalAttributeValueLen {
+			return ErrValidation("value of attribute %q exceeds %d characters", k, MaxPrincipalAttributeValueLen)
+		}
+	}
+	return nil
+}
+
+func validAttributeKey(k string) bool {
+	if k == "" || (k[0] >= '0' && k[0] <= '9') {
+		return false
+	}
+	for _, r := range k {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// PrincipalQueryDefaults are the session settings applied to every query a
+// principal runs: the catalog and schema unqualified table names resolve
+// against, and the time zone timestamps are rendered in. Empty fields keep
+// the server's defaults.
+type PrincipalQueryDefaults struct {
+	DefaultCatalog string
+	DefaultSchema  string
+	Timezone       string
+
+	RowVersion int64 // bumped by every update; see ETag
+}
+
+// IsZero reports whether no default is set.
+func (d PrincipalQueryDefaults) IsZero() bool {
+	return d.DefaultCatalog == "" && d.DefaultSchema == "" && d.Timezone == ""
+}
+
+// SearchPath returns the DuckDB search_path for the defaults, or "" when
+// neither a default catalog nor schema is set.
+func (d PrincipalQueryDefaults) SearchPath() string {
+	switch {
+	case d.DefaultCatalog != "" && d.DefaultSchema != "":
+		return d.DefaultCatalog + "." + d.DefaultSchema
+	case d.DefaultCatalog != "":
+		return d.DefaultCatalog + ".main"
+	default:
+		return d.DefaultSchema
+	}
+}
+
+// Validate checks that the catalog and schema are plain identifiers and that
+// the time zone is a known IANA name.
+func (d PrincipalQueryDefaults) Validate() error {
+	if d.DefaultCatalog != "" && !validQueryDefaultIdentifier(d.DefaultCatalog) {
+		return ErrValidation("invalid default catalog %q: use letters, digits and '_', not starting with a digit", d.DefaultCatalog)
+	}
+	if d.DefaultSchema != "" && !validQueryDefaultIdentifier(d.DefaultSchema) {
+		return ErrValidation("invalid default schema %q: use letters, digits and '_', not starting with a digit", d.DefaultSchema)
+	}
+	if d.Timezone != "" {
+		if _, err := time.LoadLocation(d.Timezone); err != nil || d.Timezone == "Local" {
+			return ErrValidation("unknown time zone %q", d.Timezone)
+		}
+	}
+	return nil
+}
+
+func validQueryDefaultIdentifier(s string) bool {
+	return validAttributeKey(s) && !strings.ContainsAny(s, "-.")
+}
+
 // ResolveOrProvisionRequest holds parameters for resolving or JIT-provisioning a principal.
 type ResolveOrProvisionRequest struct {
 	Issuer      string
 	ExternalID  string
 	DisplayName string
 	IsBootstrap bool
+	// Attributes are stored on the principal when it is provisioned or
+	// first bound to the external identity.
+	Attributes map[string]string
 }
 
 // Validate checks that the request is well-formed.
@@ -117,9 +271,50 @@ func (r *RemoveGroupMemberRequest) Validate() error {
 	return nil
 }
 
+// SyncGroupMembersRequest holds the complete desired membership of a group.
+// Members not listed are removed; listed members not yet present are added.
+type SyncGroupMembersRequest struct {
+	GroupID string
+	Members []GroupMember
+}
+
+// Validate checks that the request is well-formed and that no member is
+// listed twice.
+func (r *SyncGroupMembersRequest) Validate() error {
+	if r.GroupID == "" {
+		return ErrValidation("group_id is required")
+	}
+	seen := make(map[string]bool, len(r.Members))
+	for i, m := range r.Members {
+		if m.MemberID == "" {
+			return ErrValidation("members[%d]: member_id is required", i)
+		}
+		if m.MemberType != "user" && m.MemberType != "group" {
+			return ErrValidation("members[%d]: member_type must be 'user' or 'group'", i)
+		}
+		if m.MemberType == "group" && m.MemberID == r.GroupID {
+			return ErrValidation("members[%d]: a group cannot be a member of itself", i)
+		}
+		key := m.MemberType + ":" + m.MemberID
+		if seen[key] {
+			return ErrValidation("members[%d]: duplicate member %s %s", i, m.MemberType, m.MemberID)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// GroupMembershipChanges reports the net effect of a membership sync.
+type GroupMembershipChanges struct {
+	Added   []GroupMember
+	Removed []GroupMember
+}
+
 // GroupMember represents the membership of a principal in a group.
 type GroupMember struct {
 	GroupID    string
 	MemberType string // "user" or "group"
 	MemberID   string
+	MemberName string // resolved when listing members; empty if the member no longer exists
+	MemberKind string // "user", "service_principal", or "group"; resolved when listing members
 }
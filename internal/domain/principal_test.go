@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePrincipalImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []PrincipalImportRow
+		wantErr string
+	}{
+		{name: "valid", rows: []PrincipalImportRow{
+			{Name: "alice", Type: "user", Groups: []string{"analysts"}},
+			{Name: "etl-bot", Type: "service_principal", IsAdmin: true},
+		}},
+		{name: "empty", wantErr: "at least one principal is required"},
+		{name: "missing name", rows: []PrincipalImportRow{{Name: "alice"}, {Name: " "}},
+			wantErr: "row 2: principal name is required"},
+		{name: "invalid type", rows: []PrincipalImportRow{{Name: "alice", Type: "robot"}},
+			wantErr: "row 1: type must be 'user' or 'service_principal'"},
+		{name: "duplicate name", rows: []PrincipalImportRow{{Name: "alice"}, {Name: "bob"}, {Name: "alice"}},
+			wantErr: `row 3: principal "alice" is already listed in row 1`},
+		{name: "empty group", rows: []PrincipalImportRow{{Name: "alice", Groups: []string{"analysts", ""}}},
+			wantErr: "row 1: group names must not be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePrincipalImport(tt.rows)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			var validation *ValidationError
+			require.ErrorAs(t, err, &validation)
+			assert.Equal(t, tt.wantErr, err.Error())
+		})
+	}
+}
+
+func TestValidatePrincipalImport_DefaultsType(t *testing.T) {
+	rows := []PrincipalImportRow{{Name: "alice"}, {Name: "etl-bot", Type: "service_principal"}}
+	require.NoError(t, ValidatePrincipalImport(rows))
+	assert.Equal(t, "user", rows[0].Type)
+	assert.Equal(t, "service_principal", rows[1].Type)
+}
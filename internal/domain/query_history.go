@@ -21,7 +21,15 @@ type QueryHistoryEntry struct {
 type QueryHistoryFilter struct {
 	PrincipalName *string
 	Status        *string
+	TableName     *string // matches unqualified or qualified references to the table
 	From          *time.Time
 	To            *time.Time
+	Mine          bool // scope to the calling principal, overriding PrincipalName
 	Page          PageRequest
 }
+
+// QueryHistoryPurgeResult reports a query history purge.
+type QueryHistoryPurgeResult struct {
+	Before  time.Time // entries recorded before this time were deleted
+	Deleted int64
+}
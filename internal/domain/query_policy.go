@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// TablePolicy reports how security policies altered a query result for one
+// table the query read. It names masked columns but never reveals the mask
+// expressions or row filter SQL.
+type TablePolicy struct {
+	Table         string
+	MaskedColumns []string
+	RowFiltered   bool
+}
+
+// PolicyTrace collects the policies the engine applied while rewriting a
+// query. Attach one to the context with WithPolicyTrace to receive them.
+// It is safe for concurrent use.
+type PolicyTrace struct {
+	mu     sync.Mutex
+	tables []TablePolicy
+}
+
+// Record adds p to the trace, merging it into an earlier entry for the same
+// table so self-joins are reported once.
+func (t *PolicyTrace) Record(p TablePolicy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range t.tables {
+		if t.tables[i].Table != p.Table {
+			continue
+		}
+		existing := &t.tables[i]
+		existing.RowFiltered = existing.RowFiltered || p.RowFiltered
+		for _, col := range p.MaskedColumns {
+			if !slices.Contains(existing.MaskedColumns, col) {
+				existing.MaskedColumns = append(existing.MaskedColumns, col)
+			}
+		}
+		return
+	}
+	t.tables = append(t.tables, p)
+}
+
+// Tables returns the recorded table policies in the order tables were first
+// seen.
+func (t *PolicyTrace) Tables() []TablePolicy {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]TablePolicy, len(t.tables))
+	copy(out, t.tables)
+	return out
+}
+
+type policyTraceKey struct{}
+
+// WithPolicyTrace stores a PolicyTrace in the context.
+func WithPolicyTrace(ctx context.Context, t *PolicyTrace) context.Context {
+	return context.WithValue(ctx, policyTraceKey{}, t)
+}
+
+// PolicyTraceFromContext returns the PolicyTrace stored in the context, or
+// nil when the caller did not ask for one.
+func PolicyTraceFromContext(ctx context.Context) *PolicyTrace {
+	t, _ := ctx.Value(policyTraceKey{}).(*PolicyTrace)
+	return t
+}
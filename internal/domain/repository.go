@@ -15,6 +15,22 @@ type PrincipalRepository interface {
 	Delete(ctx context.Context, id string) error
 	SetAdmin(ctx context.Context, id string, isAdmin bool) error
 	BindExternalID(ctx context.Context, id string, externalID string, externalIssuer string) error
+	Import(ctx context.Context, rows []PrincipalImportRow) ([]PrincipalImportResult, error)
+}
+
+// PrincipalAttributeRepository stores the key/value attributes of principals.
+type PrincipalAttributeRepository interface {
+	Get(ctx context.Context, principalID string) (map[string]string, error)
+	GetVersioned(ctx context.Context, principalID string) (map[string]string, int64, error)
+	Replace(ctx context.Context, principalID string, attrs map[string]string, expectedRowVersion *int64) (int64, error)
+}
+
+// PrincipalQueryDefaultsRepository stores the query session defaults of
+// principals. A principal without stored defaults yields the zero value.
+type PrincipalQueryDefaultsRepository interface {
+	Get(ctx context.Context, principalID string) (PrincipalQueryDefaults, error)
+	GetByPrincipalName(ctx context.Context, principalName string) (PrincipalQueryDefaults, error)
+	Set(ctx context.Context, principalID string, defaults PrincipalQueryDefaults, expectedRowVersion *int64) (int64, error)
 }
 
 // GroupRepository provides CRUD operations for groups and membership.
@@ -27,6 +43,7 @@ type GroupRepository interface {
 	AddMember(ctx context.Context, m *GroupMember) error
 	RemoveMember(ctx context.Context, m *GroupMember) error
 	ListMembers(ctx context.Context, groupID string, page PageRequest) ([]GroupMember, int64, error)
+	SyncMembers(ctx context.Context, groupID string, members []GroupMember) (*GroupMembershipChanges, error)
 	GetGroupsForMember(ctx context.Context, memberType string, memberID string) ([]Group, error)
 }
 
@@ -35,31 +52,64 @@ type GrantRepository interface {
 	Grant(ctx context.Context, g *PrivilegeGrant) (*PrivilegeGrant, error)
 	Revoke(ctx context.Context, g *PrivilegeGrant) error
 	RevokeByID(ctx context.Context, id string) error
+	// UpdateExpiry sets the expiry of grant id, nil meaning never, in a
+	// single transaction and returns the updated grant.
+	UpdateExpiry(ctx context.Context, id string, expiresAt *time.Time) (*PrivilegeGrant, error)
 	ListAll(ctx context.Context, page PageRequest) ([]PrivilegeGrant, int64, error)
 	ListForPrincipal(ctx context.Context, principalID string, principalType string, page PageRequest) ([]PrivilegeGrant, int64, error)
 	ListForSecurable(ctx context.Context, securableType string, securableID string, page PageRequest) ([]PrivilegeGrant, int64, error)
+	List(ctx context.Context, filter GrantFilter) ([]PrivilegeGrant, int64, error)
+	DeleteExpired(ctx context.Context) (int64, error)
 	HasPrivilege(ctx context.Context, principalID string, principalType, securableType string, securableID string, privilege string) (bool, error)
+	// NextExpiry returns the earliest expiry among the unexpired grants held
+	// by the user principalID or any of groupIDs, or nil when none of them
+	// expires.
+	NextExpiry(ctx context.Context, principalID string, groupIDs []string) (*time.Time, error)
+	// RevokeAllForPrincipal deletes every direct grant held by the principal
+	// and, when removeMemberships is set, its group memberships, in a single
+	// transaction.
+	RevokeAllForPrincipal(ctx context.Context, principalID, principalType string, removeMemberships bool) (*RevokedAccess, error)
+}
+
+// DefaultGrantRepository provides CRUD operations for default grants.
+// ListForObject returns the default grants that apply to a new object of
+// objectType created in catalogName, under schemaName for tables.
+type DefaultGrantRepository interface {
+	Create(ctx context.Context, g *DefaultGrant) (*DefaultGrant, error)
+	GetByID(ctx context.Context, id string) (*DefaultGrant, error)
+	List(ctx context.Context, filter DefaultGrantFilter) ([]DefaultGrant, int64, error)
+	ListForObject(ctx context.Context, catalogName, schemaName, objectType string) ([]DefaultGrant, error)
+	Delete(ctx context.Context, id string) error
 }
 
 // RowFilterRepository provides CRUD operations for row filters and bindings.
 type RowFilterRepository interface {
 	Create(ctx context.Context, f *RowFilter) (*RowFilter, error)
 	GetForTable(ctx context.Context, tableID string, page PageRequest) ([]RowFilter, int64, error)
+	GetForTag(ctx context.Context, tagID string, page PageRequest) ([]RowFilter, int64, error)
 	Delete(ctx context.Context, id string) error
 	Bind(ctx context.Context, b *RowFilterBinding) error
 	Unbind(ctx context.Context, b *RowFilterBinding) error
 	ListBindings(ctx context.Context, filterID string) ([]RowFilterBinding, error)
+	// GetForTableAndPrincipal returns the filters bound to the principal that
+	// apply to the table: filters on the table itself, then tag-based
+	// filters for tags assigned to the table.
 	GetForTableAndPrincipal(ctx context.Context, tableID, principalID string, principalType string) ([]RowFilter, error)
 }
 
 // ColumnMaskRepository provides CRUD operations for column masks and bindings.
 type ColumnMaskRepository interface {
 	Create(ctx context.Context, m *ColumnMask) (*ColumnMask, error)
+	GetByID(ctx context.Context, id string) (*ColumnMask, error)
 	GetForTable(ctx context.Context, tableID string, page PageRequest) ([]ColumnMask, int64, error)
+	GetForTag(ctx context.Context, tagID string, page PageRequest) ([]ColumnMask, int64, error)
 	Delete(ctx context.Context, id string) error
 	Bind(ctx context.Context, b *ColumnMaskBinding) error
 	Unbind(ctx context.Context, b *ColumnMaskBinding) error
 	ListBindings(ctx context.Context, maskID string) ([]ColumnMaskBinding, error)
+	// GetForTableAndPrincipal returns the masks bound to the principal that
+	// apply to the table: masks on its columns first, then tag-based masks
+	// on its tagged columns.
 	GetForTableAndPrincipal(ctx context.Context, tableID, principalID string, principalType string) ([]ColumnMaskWithBinding, error)
 }
 
@@ -89,6 +139,16 @@ type AuditRepository interface {
 	List(ctx context.Context, filter AuditFilter) ([]AuditEntry, int64, error)
 }
 
+// AuditArchiveRepository tracks which audit log entries have been archived.
+// Entries are archived oldest first, so the latest archive marks how far the
+// log has been archived; only entries up to that mark may be deleted.
+type AuditArchiveRepository interface {
+	Latest(ctx context.Context) (*AuditArchive, error)
+	ListUnarchived(ctx context.Context, before time.Time, limit int) ([]AuditEntry, error)
+	Create(ctx context.Context, a *AuditArchive) error
+	DeleteArchived(ctx context.Context) (int64, error)
+}
+
 // IntrospectionRepository provides read-only access to DuckLake metadata.
 type IntrospectionRepository interface {
 	ListSchemas(ctx context.Context, page PageRequest) ([]Schema, int64, error)
@@ -103,28 +163,45 @@ type IntrospectionRepository interface {
 type CatalogRepository interface {
 	GetCatalogInfo(ctx context.Context) (*CatalogInfo, error)
 	GetMetastoreSummary(ctx context.Context) (*MetastoreSummary, error)
+	// GetCatalogStats aggregates object counts and stored table statistics,
+	// listing up to largest tables by size.
+	GetCatalogStats(ctx context.Context, largest int) (*CatalogStats, error)
 
 	CreateSchema(ctx context.Context, name, comment, owner string) (*SchemaDetail, error)
 	GetSchema(ctx context.Context, name string) (*SchemaDetail, error)
-	ListSchemas(ctx context.Context, page PageRequest) ([]SchemaDetail, int64, error)
-	UpdateSchema(ctx context.Context, name string, comment *string, props map[string]string) (*SchemaDetail, error)
+	// ListSchemas and ListTables return only objects owned by owner when it is non-nil.
+	ListSchemas(ctx context.Context, owner *string, page PageRequest) ([]SchemaDetail, int64, error)
+	UpdateSchema(ctx context.Context, name string, comment *string, props map[string]string, expectedRowVersion *int64) (*SchemaDetail, error)
 	DeleteSchema(ctx context.Context, name string, force bool) error
 
 	CreateTable(ctx context.Context, schemaName string, req CreateTableRequest, owner string) (*TableDetail, error)
 	CreateExternalTable(ctx context.Context, schemaName string, req CreateTableRequest, owner string) (*TableDetail, error)
+	// ListFiles returns the storage paths matching a glob pattern, read with
+	// the engine's credentials for the pattern's location.
+	ListFiles(ctx context.Context, pattern string) ([]string, error)
 	GetTable(ctx context.Context, schemaName, tableName string) (*TableDetail, error)
-	ListTables(ctx context.Context, schemaName string, page PageRequest) ([]TableDetail, int64, error)
+	ListTables(ctx context.Context, schemaName string, owner *string, page PageRequest) ([]TableDetail, int64, error)
 	DeleteTable(ctx context.Context, schemaName, tableName string) error
-	UpdateTable(ctx context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string) (*TableDetail, error)
+	UpdateTable(ctx context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string, expectedRowVersion *int64) (*TableDetail, error)
 	UpdateCatalog(ctx context.Context, comment *string) (*CatalogInfo, error)
 	UpdateColumn(ctx context.Context, schemaName, tableName, columnName string, comment *string, props map[string]string) (*ColumnDetail, error)
 	ListColumns(ctx context.Context, schemaName, tableName string, page PageRequest) ([]ColumnDetail, int64, error)
 	SetSchemaStoragePath(ctx context.Context, schemaID string, path string) error
+	CompactTable(ctx context.Context, schemaName, tableName string) (*TableMaintenanceResult, error)
+	VacuumTable(ctx context.Context, schemaName, tableName string, olderThan time.Duration) (*TableMaintenanceResult, error)
+	RollbackTable(ctx context.Context, schemaName, tableName string, req RollbackTableRequest) (*TableRollbackResult, error)
+	// MaterializeTable creates or replaces a table with the result of query
+	// and returns its row count.
+	MaterializeTable(ctx context.Context, schemaName, tableName, query string) (int64, error)
+	// RenameTable renames a table in place. The table keeps its ID, so
+	// grants, tags, row filters and column masks follow it.
+	RenameTable(ctx context.Context, schemaName, tableName, newName string) (*TableDetail, error)
 }
 
 // QueryHistoryRepository provides query history operations.
 type QueryHistoryRepository interface {
 	List(ctx context.Context, filter QueryHistoryFilter) ([]QueryHistoryEntry, int64, error)
+	PurgeOlderThan(ctx context.Context, before time.Time) (int64, error)
 }
 
 // QueryJobRepository provides CRUD operations for durable async query jobs.
@@ -139,6 +216,43 @@ type QueryJobRepository interface {
 	MarkFailed(ctx context.Context, id string, message string) error
 	MarkCanceled(ctx context.Context, id string) error
 	Delete(ctx context.Context, id string) error
+	// DeleteCompletedBefore removes succeeded, failed and canceled jobs that
+	// completed before cutoff and returns how many were removed.
+	DeleteCompletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// ApprovalRepository persists approval requests for dual-controlled operations.
+type ApprovalRepository interface {
+	Create(ctx context.Context, r *ApprovalRequest) (*ApprovalRequest, error)
+	GetByID(ctx context.Context, id string) (*ApprovalRequest, error)
+	List(ctx context.Context, filter ApprovalFilter) ([]ApprovalRequest, int64, error)
+	// Decide moves a PENDING request to status, recording who decided it. It
+	// reports false when the request was no longer pending.
+	Decide(ctx context.Context, id, status, decidedBy string) (bool, error)
+	// SetFailed marks an approved request whose operation failed.
+	SetFailed(ctx context.Context, id, message string) error
+}
+
+// ApplyLockRepository persists the advisory lock held by declarative apply.
+type ApplyLockRepository interface {
+	Get(ctx context.Context, name string) (*ApplyLock, error)
+	// TryAcquire stores lock if no lease exists, the current lease expired
+	// before now, or force is set. It reports whether the lock was stored.
+	TryAcquire(ctx context.Context, lock *ApplyLock, now time.Time, force bool) (bool, error)
+	// Release deletes the lease with the given ID and reports whether it was held.
+	Release(ctx context.Context, name, leaseID string) (bool, error)
+	// Renew moves the expiry of the lease with the given ID to expiresAt and
+	// reports whether it was held.
+	Renew(ctx context.Context, name, leaseID string, expiresAt time.Time) (bool, error)
+}
+
+// WebhookRepository provides CRUD operations for webhook subscriptions.
+type WebhookRepository interface {
+	Create(ctx context.Context, hook *Webhook) (*Webhook, error)
+	GetByID(ctx context.Context, id string) (*Webhook, error)
+	List(ctx context.Context, page PageRequest) ([]Webhook, int64, error)
+	ListAll(ctx context.Context) ([]Webhook, error)
+	Delete(ctx context.Context, id string) error
 }
 
 // LineageRepository provides operations for lineage edges.
@@ -147,6 +261,7 @@ type LineageRepository interface {
 	GetUpstream(ctx context.Context, tableName string, page PageRequest) ([]LineageEdge, int64, error)
 	GetDownstream(ctx context.Context, tableName string, page PageRequest) ([]LineageEdge, int64, error)
 	DeleteEdge(ctx context.Context, id string) error
+	DeleteEdgesByTarget(ctx context.Context, targetTable, edgeType string) error
 	PurgeOlderThan(ctx context.Context, before time.Time) (int64, error)
 }
 
@@ -169,6 +284,7 @@ type TableStatisticsRepository interface {
 // SearchRepository provides catalog search operations.
 type SearchRepository interface {
 	Search(ctx context.Context, query string, objectType *string, maxResults int, offset int) ([]SearchResult, int64, error)
+	Reindex(ctx context.Context) (*SearchIndexStats, error)
 }
 
 // TagRepository provides CRUD operations for tags and assignments.
@@ -181,6 +297,8 @@ type TagRepository interface {
 	UnassignTag(ctx context.Context, id string) error
 	ListTagsForSecurable(ctx context.Context, securableType string, securableID string, columnName *string) ([]Tag, error)
 	ListAssignmentsForTag(ctx context.Context, tagID string) ([]TagAssignment, error)
+	AssignTagBatch(ctx context.Context, tagID string, targets []TagAssignmentTarget, assignedBy string) (*TagAssignmentBatch, error)
+	UnassignTagBatch(ctx context.Context, tagID string, targets []TagAssignmentTarget) (*TagAssignmentBatch, error)
 }
 
 // ViewRepository provides CRUD operations for views.
@@ -188,9 +306,14 @@ type ViewRepository interface {
 	Create(ctx context.Context, view *ViewDetail) (*ViewDetail, error)
 	GetByID(ctx context.Context, id string) (*ViewDetail, error)
 	GetByName(ctx context.Context, schemaID string, viewName string) (*ViewDetail, error)
-	List(ctx context.Context, schemaID string, page PageRequest) ([]ViewDetail, int64, error)
+	List(ctx context.Context, schemaID string, owner *string, page PageRequest) ([]ViewDetail, int64, error)
 	Delete(ctx context.Context, schemaID string, viewName string) error
-	Update(ctx context.Context, schemaID string, viewName string, comment *string, props map[string]string, viewDef *string) (*ViewDetail, error)
+	Update(ctx context.Context, schemaID string, viewName string, comment *string, props map[string]string, viewDef *string, expectedRowVersion *int64) (*ViewDetail, error)
+	// ListScheduled returns the materialized views that have a refresh
+	// schedule, across all schemas, with CatalogName set.
+	ListScheduled(ctx context.Context) ([]ViewDetail, error)
+	MarkRefreshed(ctx context.Context, schemaID string, viewName string, at time.Time) error
+	Rename(ctx context.Context, schemaID string, viewName, newName string) (*ViewDetail, error)
 }
 
 // StorageCredentialRepository provides CRUD operations for storage credentials.
@@ -217,7 +340,8 @@ type ExternalLocationRepository interface {
 type VolumeRepository interface {
 	Create(ctx context.Context, vol *Volume) (*Volume, error)
 	GetByName(ctx context.Context, schemaName, name string) (*Volume, error)
-	List(ctx context.Context, schemaName string, page PageRequest) ([]Volume, int64, error)
+	GetByID(ctx context.Context, id string) (*Volume, error)
+	List(ctx context.Context, schemaName string, owner *string, page PageRequest) ([]Volume, int64, error)
 	Update(ctx context.Context, id string, req UpdateVolumeRequest) (*Volume, error)
 	Delete(ctx context.Context, id string) error
 }
@@ -232,6 +356,7 @@ type ExternalTableRepository interface {
 	ListAll(ctx context.Context) ([]ExternalTableRecord, error)
 	Delete(ctx context.Context, schemaName, tableName string) error
 	DeleteBySchema(ctx context.Context, schemaName string) error
+	Rename(ctx context.Context, schemaName, tableName, newName string) error
 }
 
 // CatalogRegistrationRepository provides CRUD operations for catalog registrations.
@@ -263,11 +388,27 @@ type ComputeEndpointRepository interface {
 	GetAssignmentsForPrincipal(ctx context.Context, principalID string, principalType string) ([]ComputeEndpoint, error)
 }
 
+// ComputeEndpointLoadRepository stores the latest load polled from each
+// remote compute agent.
+type ComputeEndpointLoadRepository interface {
+	RecordLoad(ctx context.Context, load *ComputeEndpointLoad) error
+	ListLoads(ctx context.Context) (map[string]ComputeEndpointLoad, error)
+}
+
+// ComputeEndpointAgentRepository stores the additional agent URLs backing a
+// remote compute endpoint.
+type ComputeEndpointAgentRepository interface {
+	AddAgent(ctx context.Context, agent *ComputeEndpointAgent) (*ComputeEndpointAgent, error)
+	RemoveAgent(ctx context.Context, endpointID, agentID string) error
+	ListAgents(ctx context.Context, endpointID string) ([]ComputeEndpointAgent, error)
+}
+
 // NotebookRepository provides CRUD operations for notebooks and cells.
 type NotebookRepository interface {
 	CreateNotebook(ctx context.Context, nb *Notebook) (*Notebook, error)
 	GetNotebook(ctx context.Context, id string) (*Notebook, error)
 	ListNotebooks(ctx context.Context, owner *string, page PageRequest) ([]Notebook, int64, error)
+	ListNotebooksByGitRepo(ctx context.Context, gitRepoID string) ([]Notebook, error)
 	UpdateNotebook(ctx context.Context, id string, req UpdateNotebookRequest) (*Notebook, error)
 	DeleteNotebook(ctx context.Context, id string) error
 
@@ -342,6 +483,7 @@ type ModelRepository interface {
 	Delete(ctx context.Context, id string) error
 	ListAll(ctx context.Context) ([]Model, error)
 	UpdateDependencies(ctx context.Context, id string, deps []string) error
+	UpdateMacroDependencies(ctx context.Context, id string, macros []string) error
 }
 
 // ModelRunRepository provides CRUD operations for model runs and steps.
@@ -355,6 +497,8 @@ type ModelRunRepository interface {
 	ListStepsByRun(ctx context.Context, runID string) ([]ModelRunStep, error)
 	UpdateStepStarted(ctx context.Context, id string) error
 	UpdateStepFinished(ctx context.Context, id string, status string, rowsAffected *int64, errMsg *string) error
+	UpdateStepMetrics(ctx context.Context, id string, durationMs int64, bytesWritten *int64) error
+	ListStepsByModel(ctx context.Context, modelName string, page PageRequest) ([]ModelRunStep, int64, error)
 }
 
 // ModelTestRepository provides CRUD operations for model tests.
@@ -369,6 +513,7 @@ type ModelTestRepository interface {
 type ModelTestResultRepository interface {
 	Create(ctx context.Context, result *ModelTestResult) (*ModelTestResult, error)
 	ListByStep(ctx context.Context, runStepID string) ([]ModelTestResult, error)
+	ListByTest(ctx context.Context, testID string, page PageRequest) ([]ModelTestResult, int64, error)
 }
 
 // MacroRepository provides CRUD operations for SQL macros.
@@ -2,10 +2,13 @@ package domain
 
 import "time"
 
-// RowFilter represents a row-level security filter on a table.
+// RowFilter represents a row-level security filter on a table, or on every
+// table carrying a tag. A tag-based filter has TagID set and an empty
+// TableID; tables acquiring the tag are filtered without further setup.
 type RowFilter struct {
 	ID          string
 	TableID     string
+	TagID       *string
 	FilterSQL   string
 	Description string
 	CreatedAt   time.Time
@@ -14,13 +17,17 @@ type RowFilter struct {
 // CreateRowFilterRequest holds parameters for creating a row filter.
 type CreateRowFilterRequest struct {
 	TableID     string
+	TagID       string // targets tagged tables instead of TableID
 	FilterSQL   string
 	Description string
 }
 
 // Validate checks that the request is well-formed.
 func (r *CreateRowFilterRequest) Validate() error {
-	if r.TableID == "" {
+	switch {
+	case r.TagID != "" && r.TableID != "":
+		return ErrValidation("a tag-based row filter cannot also name a table")
+	case r.TagID == "" && r.TableID == "":
 		return ErrValidation("table_id is required")
 	}
 	if r.FilterSQL == "" {
@@ -57,3 +64,53 @@ type RowFilterBinding struct {
 	PrincipalID   string
 	PrincipalType string // "user" or "group"
 }
+
+// Sample sizes for RowFilterService.SimulatePolicy.
+const (
+	DefaultPolicySimulationLimit = 10
+	MaxPolicySimulationLimit     = 100
+)
+
+// PolicySimulationRequest holds a candidate row filter and/or column mask to
+// try against a table as a principal, without persisting either.
+type PolicySimulationRequest struct {
+	Table          string // table name, optionally schema- or catalog-qualified
+	Principal      string
+	RowFilter      string // candidate filter_sql
+	ColumnName     string // column the candidate mask applies to
+	MaskExpression string // candidate mask_expression
+	Limit          int
+}
+
+// Validate checks that the request is well-formed.
+func (r *PolicySimulationRequest) Validate() error {
+	if r.Table == "" {
+		return ErrValidation("table is required")
+	}
+	if r.Principal == "" {
+		return ErrValidation("principal is required")
+	}
+	if r.RowFilter == "" && r.ColumnName == "" && r.MaskExpression == "" {
+		return ErrValidation("a row filter or a column mask is required")
+	}
+	if (r.ColumnName == "") != (r.MaskExpression == "") {
+		return ErrValidation("a column mask needs both column_name and mask_expression")
+	}
+	if r.Limit < 0 || r.Limit > MaxPolicySimulationLimit {
+		return ErrValidation("limit must be between 1 and %d", MaxPolicySimulationLimit)
+	}
+	return nil
+}
+
+// PolicySimulation is a sample of a table as a principal would read it with
+// a candidate policy in place alongside the policies already bound to them.
+// Admins bypass row filters and masks, so RowFiltered is false and
+// MaskedColumns is empty when the principal is an admin.
+type PolicySimulation struct {
+	TableName     string
+	Principal     string
+	Columns       []string
+	Rows          [][]interface{}
+	RowFiltered   bool     // whether any row filter, bound or candidate, applied
+	MaskedColumns []string // columns read through a mask, bound or candidate
+}
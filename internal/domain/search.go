@@ -2,10 +2,19 @@ package domain
 
 // SearchResult represents a single catalog search result.
 type SearchResult struct {
-	Type       string // "schema", "table", "column"
+	Type       string // "schema", "table", "column", "macro", "view", "model"
 	Name       string
-	SchemaName *string
+	SchemaName *string // for models, the project name
 	TableName  *string
 	Comment    *string
-	MatchField string // "name", "comment", "property"
+	MatchField string  // "name", "comment", "property", "tag"
+	Snippet    *string // excerpt of Comment around the match, set for comment matches
+}
+
+// SearchIndexStats counts the objects written by a search reindex.
+type SearchIndexStats struct {
+	Schemas int64
+	Tables  int64
+	Views   int64
+	Columns int64
 }
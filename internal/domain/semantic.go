@@ -43,6 +43,8 @@ type SemanticModel struct {
 	CreatedBy            string
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // CreateSemanticModelRequest holds parameters for creating a semantic model.
@@ -79,6 +81,8 @@ type UpdateSemanticModelRequest struct {
 	BaseModelRef         *string
 	DefaultTimeDimension *string
 	Tags                 []string // nil = no change, empty = clear
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // SemanticMetric defines a business metric attached to a semantic model.
@@ -44,9 +44,85 @@ func (r *AssignTagRequest) Validate() error {
 	if !IsValidTagSecurableType(r.SecurableType) {
 		return ErrValidation("securable_type must be one of: schema, table, column, macro")
 	}
+	if r.SecurableType == TagSecurableTypeColumn && (r.ColumnName == nil || *r.ColumnName == "") {
+		return ErrValidation("column_name is required for column assignments")
+	}
+	return nil
+}
+
+// MaxTagAssignmentBatchSize caps the number of securables in one batch tag
+// request so a single transaction stays short.
+const MaxTagAssignmentBatchSize = 500
+
+// TagAssignmentTarget identifies one securable in a batch tag request.
+type TagAssignmentTarget struct {
+	SecurableType string // "schema", "table", "column", "macro"
+	SecurableID   string
+	ColumnName    *string
+}
+
+// Validate checks that the target names a taggable securable.
+func (t *TagAssignmentTarget) Validate() error {
+	if !IsValidTagSecurableType(t.SecurableType) {
+		return ErrValidation("securable_type must be one of: schema, table, column, macro")
+	}
+	if t.SecurableID == "" {
+		return ErrValidation("securable_id is required")
+	}
+	if t.SecurableType == TagSecurableTypeColumn && (t.ColumnName == nil || *t.ColumnName == "") {
+		return ErrValidation("column_name is required for column assignments")
+	}
 	return nil
 }
 
+// BatchTagAssignmentRequest holds parameters for assigning a tag to, or
+// removing it from, several securables at once.
+type BatchTagAssignmentRequest struct {
+	TagID   string
+	Targets []TagAssignmentTarget
+}
+
+// Validate checks that the request is well-formed. Individual targets are
+// validated per item so that each failure can be reported separately.
+func (r *BatchTagAssignmentRequest) Validate() error {
+	if r.TagID == "" {
+		return ErrValidation("tag_id is required")
+	}
+	if len(r.Targets) == 0 {
+		return ErrValidation("at least one assignment is required")
+	}
+	if len(r.Targets) > MaxTagAssignmentBatchSize {
+		return ErrValidation("at most %d assignments are allowed per batch", MaxTagAssignmentBatchSize)
+	}
+	return nil
+}
+
+// Per-item statuses reported by batch tag operations.
+const (
+	TagBatchStatusAssigned  = "ASSIGNED"  // a new assignment was created
+	TagBatchStatusUnchanged = "UNCHANGED" // the assignment already existed
+	TagBatchStatusRemoved   = "REMOVED"   // the assignment was deleted
+	TagBatchStatusNotFound  = "NOT_FOUND" // there was no assignment to delete
+	TagBatchStatusFailed    = "FAILED"    // the item was rejected
+	TagBatchStatusSkipped   = "SKIPPED"   // not applied because another item failed
+)
+
+// TagAssignmentBatchResult reports the outcome for one target of a batch.
+type TagAssignmentBatchResult struct {
+	Target       TagAssignmentTarget
+	Status       string
+	AssignmentID *string
+	Error        *string
+}
+
+// TagAssignmentBatch is the outcome of a batch tag operation. Batches are
+// atomic: Applied is false when any item failed, in which case nothing was
+// written and the remaining items are reported as skipped.
+type TagAssignmentBatch struct {
+	Applied bool
+	Results []TagAssignmentBatchResult
+}
+
 // TagAssignment represents a tag assigned to a securable object.
 type TagAssignment struct {
 	ID            string
@@ -0,0 +1,50 @@
+package domain
+
+import "context"
+
+// ServerVersion reports the versions of the components a server runs, for
+// support and upgrade checks.
+type ServerVersion struct {
+	AppVersion             string
+	Commit                 string
+	GoVersion              string
+	DuckDBVersion          string
+	Extensions             []ExtensionVersion
+	MetastoreSchemaVersion int64 // highest applied migration; 0 when none
+	ComputeEndpoints       []ComputeEndpointVersion
+}
+
+// ExtensionVersion is a DuckDB extension loaded into the server's engine.
+type ExtensionVersion struct {
+	Name    string
+	Version string
+}
+
+// ComputeEndpointVersion is the DuckDB version reported by a compute
+// endpoint's health check. Error is set when the endpoint could not be
+// reached.
+type ComputeEndpointVersion struct {
+	Name          string
+	Type          string
+	DuckDBVersion string
+	Error         string
+}
+
+// DuckDBExtension is an extension installed or loaded in a DuckDB instance,
+// as reported to operators auditing the extension allowlist.
+type DuckDBExtension struct {
+	Name             string
+	Version          string
+	InstallMode      string
+	Installed        bool
+	Loaded           bool
+	StaticallyLinked bool // built into DuckDB; always available
+	Allowed          bool // statically linked or on the allowlist
+}
+
+// ExtensionInventory lists the extensions of the server's DuckDB engine
+// together with the configured allowlist.
+type ExtensionInventory interface {
+	ListExtensions(ctx context.Context) ([]DuckDBExtension, error)
+	ExtensionAllowlist() []string
+}
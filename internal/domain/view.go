@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
 
 // ViewDetail represents a view in the catalog.
 type ViewDetail struct {
@@ -17,6 +21,15 @@ type ViewDetail struct {
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	DeletedAt      *time.Time
+
+	// Materialized views are backed by a table of the same name in the
+	// view's schema, holding the result of the definition as of the last
+	// refresh. RefreshSchedule is a cron expression for scheduled refreshes.
+	Materialized    bool
+	RefreshSchedule *string
+	LastRefreshedAt *time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // CreateViewRequest holds parameters for creating a view.
@@ -24,6 +37,17 @@ type CreateViewRequest struct {
 	Name           string
 	ViewDefinition string
 	Comment        string
+
+	// Materialized stores the definition's result in a table that queries
+	// read instead of evaluating the definition. RefreshSchedule, a cron
+	// expression, refreshes it on a schedule; without one it is refreshed
+	// only on request.
+	Materialized    bool
+	RefreshSchedule string
+
+	// SkipValidation persists the definition without checking it against
+	// the catalog first.
+	SkipValidation bool
 }
 
 // Validate checks that the request is well-formed.
@@ -34,6 +58,14 @@ func (r *CreateViewRequest) Validate() error {
 	if r.ViewDefinition == "" {
 		return ErrValidation("view_definition is required")
 	}
+	if r.RefreshSchedule != "" {
+		if !r.Materialized {
+			return ErrValidation("refresh_schedule requires a materialized view")
+		}
+		if _, err := cron.ParseStandard(r.RefreshSchedule); err != nil {
+			return ErrValidation("refresh_schedule is invalid: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -42,4 +74,6 @@ type UpdateViewRequest struct {
 	Comment        *string
 	Properties     map[string]string
 	ViewDefinition *string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
@@ -20,6 +20,8 @@ type Volume struct {
 	Owner           string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+
+	RowVersion int64 // bumped by every update; see ETag
 }
 
 // CreateVolumeRequest holds parameters for creating a volume.
@@ -35,6 +37,8 @@ type UpdateVolumeRequest struct {
 	NewName *string
 	Comment *string
 	Owner   *string
+
+	ExpectedRowVersion *int64 // from If-Match; nil updates unconditionally
 }
 
 // Validate checks that the request is well-formed.
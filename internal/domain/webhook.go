@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Event types emitted to webhook subscribers.
+const (
+	EventGrantCreated          = "grant.created"
+	EventGrantDeleted          = "grant.deleted"
+	EventPrincipalAdminChanged = "principal.admin_changed"
+	EventCatalogCreated        = "catalog.created"
+	EventCatalogDeleted        = "catalog.deleted"
+
+	// EventAll subscribes a webhook to every event type.
+	EventAll = "*"
+)
+
+// WebhookEventTypes lists the event types a webhook may subscribe to.
+var WebhookEventTypes = []string{
+	EventGrantCreated,
+	EventGrantDeleted,
+	EventPrincipalAdminChanged,
+	EventCatalogCreated,
+	EventCatalogDeleted,
+}
+
+// Event is a change notification published by a service.
+type Event struct {
+	ID         string
+	Type       string
+	OccurredAt time.Time
+	Actor      string
+	Data       map[string]interface{}
+}
+
+// NewEvent builds an event attributed to the principal in ctx.
+func NewEvent(ctx context.Context, eventType string, data map[string]interface{}) Event {
+	actor := "system"
+	if p, ok := PrincipalFromContext(ctx); ok {
+		actor = p.Name
+	}
+	return Event{
+		ID:         NewID(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Actor:      actor,
+		Data:       data,
+	}
+}
+
+// EventPublisher delivers events to subscribers. Publish must not block the
+// caller on delivery and never fails the operation that emitted the event.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// Webhook is a subscription that receives signed event payloads over HTTP.
+type Webhook struct {
+	ID          string
+	URL         string
+	Secret      string // HMAC signing key; never returned by the API
+	Events      []string
+	Description *string
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Subscribes reports whether the webhook wants events of eventType.
+func (w *Webhook) Subscribes(eventType string) bool {
+	return slices.Contains(w.Events, EventAll) || slices.Contains(w.Events, eventType)
+}
+
+// CreateWebhookRequest holds parameters for creating a webhook subscription.
+type CreateWebhookRequest struct {
+	URL         string
+	Secret      string
+	Events      []string
+	Description *string
+}
+
+// Validate checks that the request is well-formed.
+func (r *CreateWebhookRequest) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrValidation("url must be an absolute http or https URL")
+	}
+	if len(r.Secret) < 16 {
+		return ErrValidation("secret must be at least 16 characters")
+	}
+	if len(r.Events) == 0 {
+		return ErrValidation("at least one event is required")
+	}
+	for _, e := range r.Events {
+		if e != EventAll && !slices.Contains(WebhookEventTypes, e) {
+			return ErrValidation("unknown event %q; valid events: %s, or %q for all",
+				e, strings.Join(WebhookEventTypes, ", "), EventAll)
+		}
+	}
+	return nil
+}
@@ -288,6 +288,212 @@ func addNamedTableRef(catalog, schema, name string, seen map[string]bool, refs *
 	*refs = append(*refs, TableRefName{Catalog: catalog, Schema: schema, Name: name})
 }
 
+// === Function Name Collection ===
+
+// CollectFuncNames returns a deduplicated, lowercased list of the functions
+// the statement calls, in order of first appearance. Scalar, aggregate,
+// window and table functions are included; subqueries and CTEs are walked.
+func CollectFuncNames(stmt Stmt) []string {
+	c := funcNameCollector{seen: make(map[string]bool)}
+	switch s := stmt.(type) {
+	case *SelectStmt:
+		c.selectStmt(s)
+	case *InsertStmt:
+		for _, row := range s.Values {
+			c.exprs(row)
+		}
+		c.selectStmt(s.Query)
+	case *UpdateStmt:
+		for _, set := range s.Sets {
+			c.expr(set.Value)
+		}
+		c.from(s.From)
+		c.expr(s.Where)
+	case *DeleteStmt:
+		c.expr(s.Where)
+	}
+	return c.names
+}
+
+type funcNameCollector struct {
+	seen  map[string]bool
+	names []string
+}
+
+func (c *funcNameCollector) add(name string) {
+	name = strings.ToLower(name)
+	if name == "" || c.seen[name] {
+		return
+	}
+	c.seen[name] = true
+	c.names = append(c.names, name)
+}
+
+func (c *funcNameCollector) selectStmt(sel *SelectStmt) {
+	if sel == nil {
+		return
+	}
+	if sel.With != nil {
+		for _, cte := range sel.With.CTEs {
+			c.selectStmt(cte.Select)
+		}
+	}
+	for body := sel.Body; body != nil; body = body.Right {
+		c.core(body.Left)
+	}
+}
+
+func (c *funcNameCollector) core(sc *SelectCore) {
+	if sc == nil {
+		return
+	}
+	for _, col := range sc.Columns {
+		c.expr(col.Expr)
+	}
+	c.from(sc.From)
+	c.expr(sc.Where)
+	c.exprs(sc.GroupBy)
+	c.expr(sc.Having)
+	c.expr(sc.Qualify)
+	c.orderBy(sc.OrderBy)
+	for _, row := range sc.ValuesRows {
+		c.exprs(row)
+	}
+}
+
+func (c *funcNameCollector) from(from *FromClause) {
+	if from == nil {
+		return
+	}
+	c.tableRef(from.Source)
+	for _, join := range from.Joins {
+		c.tableRef(join.Right)
+		c.expr(join.Condition)
+	}
+}
+
+func (c *funcNameCollector) tableRef(ref TableRef) {
+	switch t := ref.(type) {
+	case *DerivedTable:
+		c.selectStmt(t.Select)
+	case *LateralTable:
+		c.selectStmt(t.Select)
+	case *FuncTable:
+		if t.Func != nil {
+			c.expr(t.Func)
+		}
+	case *PivotTable:
+		c.tableRef(t.Source)
+	case *UnpivotTable:
+		c.tableRef(t.Source)
+	}
+}
+
+func (c *funcNameCollector) orderBy(items []OrderByItem) {
+	for _, item := range items {
+		c.expr(item.Expr)
+	}
+}
+
+func (c *funcNameCollector) exprs(list []Expr) {
+	for _, e := range list {
+		c.expr(e)
+	}
+}
+
+func (c *funcNameCollector) expr(e Expr) {
+	if e == nil {
+		return
+	}
+	switch expr := e.(type) {
+	case *FuncCall:
+		c.add(expr.Name)
+		c.exprs(expr.Args)
+		c.orderBy(expr.OrderBy)
+		c.expr(expr.Filter)
+		if expr.Window != nil {
+			c.exprs(expr.Window.PartitionBy)
+			c.orderBy(expr.Window.OrderBy)
+		}
+	case *SubqueryExpr:
+		c.selectStmt(expr.Select)
+	case *ExistsExpr:
+		c.selectStmt(expr.Select)
+	case *InExpr:
+		c.expr(expr.Expr)
+		c.exprs(expr.Values)
+		c.selectStmt(expr.Query)
+	case *BinaryExpr:
+		c.expr(expr.Left)
+		c.expr(expr.Right)
+	case *UnaryExpr:
+		c.expr(expr.Expr)
+	case *ParenExpr:
+		c.expr(expr.Expr)
+	case *CaseExpr:
+		c.expr(expr.Operand)
+		for _, w := range expr.Whens {
+			c.expr(w.Condition)
+			c.expr(w.Result)
+		}
+		c.expr(expr.Else)
+	case *CastExpr:
+		c.expr(expr.Expr)
+	case *TypeCastExpr:
+		c.expr(expr.Expr)
+	case *BetweenExpr:
+		c.expr(expr.Expr)
+		c.expr(expr.Low)
+		c.expr(expr.High)
+	case *IsNullExpr:
+		c.expr(expr.Expr)
+	case *IsBoolExpr:
+		c.expr(expr.Expr)
+	case *LikeExpr:
+		c.expr(expr.Expr)
+		c.expr(expr.Pattern)
+		c.expr(expr.Escape)
+	case *GlobExpr:
+		c.expr(expr.Expr)
+		c.expr(expr.Pattern)
+	case *SimilarToExpr:
+		c.expr(expr.Expr)
+		c.expr(expr.Pattern)
+	case *IsDistinctExpr:
+		c.expr(expr.Left)
+		c.expr(expr.Right)
+	case *CollateExpr:
+		c.expr(expr.Expr)
+	case *LambdaExpr:
+		c.expr(expr.Body)
+	case *StructLiteral:
+		for _, f := range expr.Fields {
+			c.expr(f.Value)
+		}
+	case *MapLiteral:
+		for _, entry := range expr.Entries {
+			c.expr(entry.Value)
+		}
+	case *ListLiteral:
+		c.exprs(expr.Elements)
+	case *IndexExpr:
+		c.expr(expr.Expr)
+		c.expr(expr.Index)
+		c.expr(expr.Start)
+		c.expr(expr.Stop)
+	case *ListComprehension:
+		c.expr(expr.Expr)
+		c.expr(expr.List)
+		c.expr(expr.Cond)
+	case *NamedArgExpr:
+		c.expr(expr.Value)
+	case *GroupingExpr:
+		for _, group := range expr.Groups {
+			c.exprs(group)
+		}
+	}
+}
+
 // === Target Table Extraction ===
 
 // TargetTable returns the target table name for INSERT, UPDATE, or DELETE.
@@ -883,3 +1089,100 @@ func dangerousFuncInExpr(e Expr, blocklist map[string]bool) (string, bool) {
 	}
 	return "", false
 }
+
+// === Function Call Rewriting ===
+
+// RewriteFuncCalls returns e with every function call for which fn returns a
+// non-nil replacement swapped for that replacement. Calls that are kept have
+// their arguments rewritten in turn. The tree is modified in place.
+// Subqueries are not entered; callers that must not leave a call behind can
+// check the result with ContainsDangerousFunction.
+func RewriteFuncCalls(e Expr, fn func(*FuncCall) (Expr, error)) (Expr, error) {
+	r := funcRewriter{fn: fn}
+	out := r.expr(e)
+	return out, r.err
+}
+
+type funcRewriter struct {
+	fn  func(*FuncCall) (Expr, error)
+	err error
+}
+
+func (r *funcRewriter) exprs(list []Expr) {
+	for i := range list {
+		list[i] = r.expr(list[i])
+	}
+}
+
+func (r *funcRewriter) expr(e Expr) Expr {
+	if e == nil || r.err != nil {
+		return e
+	}
+	switch expr := e.(type) {
+	case *FuncCall:
+		repl, err := r.fn(expr)
+		if err != nil {
+			r.err = err
+			return e
+		}
+		if repl != nil {
+			return repl
+		}
+		r.exprs(expr.Args)
+		expr.Filter = r.expr(expr.Filter)
+	case *BinaryExpr:
+		expr.Left = r.expr(expr.Left)
+		expr.Right = r.expr(expr.Right)
+	case *UnaryExpr:
+		expr.Expr = r.expr(expr.Expr)
+	case *ParenExpr:
+		expr.Expr = r.expr(expr.Expr)
+	case *CaseExpr:
+		expr.Operand = r.expr(expr.Operand)
+		for i := range expr.Whens {
+			expr.Whens[i].Condition = r.expr(expr.Whens[i].Condition)
+			expr.Whens[i].Result = r.expr(expr.Whens[i].Result)
+		}
+		expr.Else = r.expr(expr.Else)
+	case *CastExpr:
+		expr.Expr = r.expr(expr.Expr)
+	case *TypeCastExpr:
+		expr.Expr = r.expr(expr.Expr)
+	case *InExpr:
+		expr.Expr = r.expr(expr.Expr)
+		r.exprs(expr.Values)
+	case *BetweenExpr:
+		expr.Expr = r.expr(expr.Expr)
+		expr.Low = r.expr(expr.Low)
+		expr.High = r.expr(expr.High)
+	case *IsNullExpr:
+		expr.Expr = r.expr(expr.Expr)
+	case *IsBoolExpr:
+		expr.Expr = r.expr(expr.Expr)
+	case *LikeExpr:
+		expr.Expr = r.expr(expr.Expr)
+		expr.Pattern = r.expr(expr.Pattern)
+		expr.Escape = r.expr(expr.Escape)
+	case *GlobExpr:
+		expr.Expr = r.expr(expr.Expr)
+		expr.Pattern = r.expr(expr.Pattern)
+	case *SimilarToExpr:
+		expr.Expr = r.expr(expr.Expr)
+		expr.Pattern = r.expr(expr.Pattern)
+	case *IsDistinctExpr:
+		expr.Left = r.expr(expr.Left)
+		expr.Right = r.expr(expr.Right)
+	case *CollateExpr:
+		expr.Expr = r.expr(expr.Expr)
+	case *ListLiteral:
+		r.exprs(expr.Elements)
+	case *IndexExpr:
+		expr.Expr = r.expr(expr.Expr)
+		expr.Index = r.expr(expr.Index)
+		expr.Start = r.expr(expr.Start)
+		expr.Stop = r.expr(expr.Stop)
+	case *NamedArgExpr:
+		expr.Value = r.expr(expr.Value)
+	}
+	return e
+}
@@ -168,6 +168,35 @@ func TestCollectTableRefs(t *testing.T) {
 	}
 }
 
+// === CollectFuncNames tests ===
+
+func TestCollectFuncNames(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{"none", "SELECT a FROM t", nil},
+		{"select_list", "SELECT fmt_money(amount), UPPER(name) FROM orders", []string{"fmt_money", "upper"}},
+		{"nested_args", "SELECT round(fmt_money(amount), 2) FROM orders", []string{"round", "fmt_money"}},
+		{"deduplicated", "SELECT fmt_money(a), fmt_money(b) FROM t", []string{"fmt_money"}},
+		{"where_and_case", "SELECT CASE WHEN is_vip(c) THEN 1 END FROM t WHERE lower(x) = 'a'", []string{"is_vip", "lower"}},
+		{"cte_and_subquery", "WITH x AS (SELECT f1(a) AS a FROM t) SELECT * FROM x WHERE a IN (SELECT f2(b) FROM u)", []string{"f1", "f2"}},
+		{"table_function", "SELECT * FROM read_parquet('s3://b/x.parquet')", []string{"read_parquet"}},
+		{"window", "SELECT sum(a) OVER (PARTITION BY bucket(b)) FROM t", []string{"sum", "bucket"}},
+		{"insert_select", "INSERT INTO t SELECT fmt_money(a) FROM s", []string{"fmt_money"}},
+		{"update", "UPDATE t SET a = fmt_money(b) WHERE c = f(d)", []string{"fmt_money", "f"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt, err := Parse(tc.sql)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, CollectFuncNames(stmt))
+		})
+	}
+}
+
 // === TargetTable tests ===
 
 func TestTargetTable(t *testing.T) {
@@ -541,3 +570,59 @@ func TestIntegration_SecurityPipeline(t *testing.T) {
 	_, err = Parse(got)
 	assert.NoError(t, err, "output should be valid SQL")
 }
+
+// === RewriteFuncCalls tests ===
+
+func TestRewriteFuncCalls(t *testing.T) {
+	replaceAttr := func(fc *FuncCall) (Expr, error) {
+		if !strings.EqualFold(fc.Name, "attr") {
+			return nil, nil
+		}
+		return &Literal{Type: LiteralString, Value: "EU"}, nil
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"top level", "attr('region')", "'EU'"},
+		{"comparison", "region = attr('region')", `"region" = 'EU'`},
+		{"nested in kept call", "lower(region) = lower(attr('region'))", `lower("region") = lower('EU')`},
+		{"in list", "region IN (attr('a'), 'US')", `"region" IN ('EU', 'US')`},
+		{"case", "CASE WHEN attr('x') IS NULL THEN false ELSE region = attr('x') END",
+			`CASE WHEN 'EU' IS NULL THEN FALSE ELSE "region" = 'EU' END`},
+		{"untouched", "region = 'US'", `"region" = 'US'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ParseExpr(tt.expr)
+			require.NoError(t, err)
+			out, err := RewriteFuncCalls(e, replaceAttr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, FormatExpr(out))
+		})
+	}
+}
+
+func TestRewriteFuncCalls_Error(t *testing.T) {
+	e, err := ParseExpr("a = f(1) AND b = f(2)")
+	require.NoError(t, err)
+	calls := 0
+	_, err = RewriteFuncCalls(e, func(*FuncCall) (Expr, error) {
+		calls++
+		return nil, assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, calls, "rewriting stops at the first error")
+}
+
+func TestRewriteFuncCalls_SkipsSubqueries(t *testing.T) {
+	e, err := ParseExpr("region IN (SELECT r FROM t WHERE r = attr('region'))")
+	require.NoError(t, err)
+	out, err := RewriteFuncCalls(e, func(*FuncCall) (Expr, error) {
+		return &Literal{Type: LiteralString, Value: "EU"}, nil
+	})
+	require.NoError(t, err)
+	assert.Contains(t, FormatExpr(out), "attr('region')")
+}
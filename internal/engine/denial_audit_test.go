@@ -0,0 +1,71 @@
+package engine_test
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"testing"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/engine"
+	"duck-demo/internal/testutil"
+)
+
+type recordedDenials struct {
+	mu      sync.Mutex
+	denials []domain.AccessDenial
+}
+
+func (r *recordedDenials) RecordDenial(_ context.Context, d domain.AccessDenial) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.denials = append(r.denials, d)
+}
+
+func TestUnauthorizedQueryRecordsDenial(t *testing.T) {
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.ExecContext(context.Background(), "CREATE TABLE secrets (id INTEGER)")
+	require.NoError(t, err)
+
+	authz := &testutil.MockAuthService{
+		LookupTableIDFn: func(context.Context, string) (string, string, bool, error) {
+			return "t-1", "s-1", false, nil
+		},
+		CheckPrivilegeFn: func(_ context.Context, principalName, _, _, _ string) (bool, error) {
+			return principalName == "alice", nil
+		},
+		GetEffectiveRowFiltersFn: func(context.Context, string, string) ([]string, error) {
+			return nil, nil
+		},
+		GetEffectiveColumnMasksFn: func(context.Context, string, string) (map[string]string, error) {
+			return nil, nil
+		},
+	}
+	rec := &recordedDenials{}
+	eng := engine.NewSecureEngine(db, authz, nil, nil, slog.New(slog.DiscardHandler))
+	eng.SetDenialRecorder(rec)
+
+	rows, err := eng.Query(context.Background(), "mallory", "SELECT * FROM secrets") //nolint:rowserrcheck // denied before any rows exist
+	require.Nil(t, rows)
+	var denied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &denied)
+
+	require.Len(t, rec.denials, 1)
+	d := rec.denials[0]
+	assert.Equal(t, "mallory", d.PrincipalName)
+	assert.Equal(t, domain.PrivSelect, d.Action)
+	assert.Equal(t, "secrets", d.Securable)
+	assert.Contains(t, d.Reason, "lacks SELECT")
+
+	rows, err = eng.Query(context.Background(), "alice", "SELECT * FROM secrets")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	assert.Len(t, rec.denials, 1, "allowed queries are not recorded")
+}
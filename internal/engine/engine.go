@@ -4,8 +4,11 @@ package engine
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 
 	"duck-demo/internal/ddl"
@@ -20,6 +23,9 @@ type SecureEngine struct {
 	catalog    domain.AuthorizationService
 	resolver   domain.ComputeResolver
 	infoSchema *InformationSchemaProvider
+	extensions *ExtensionAllowlist // nil means DefaultExtensionAllowlist
+	denials    domain.DenialRecorder
+	defaults   domain.PrincipalQueryDefaultsRepository
 	logger     *slog.Logger
 }
 
@@ -35,24 +41,125 @@ func NewSecureEngine(db *sql.DB, cat domain.AuthorizationService, resolver domai
 	return &SecureEngine{db: db, catalog: cat, resolver: resolver, infoSchema: infoSchema, logger: logger}
 }
 
-// execQuery resolves a ComputeExecutor for the principal and executes the query.
-// When the resolver is nil or returns a nil executor, the local *sql.DB is used.
-func (e *SecureEngine) execQuery(ctx context.Context, principalName, query string) (*sql.Rows, error) {
-	if e.resolver != nil {
-		executor, err := e.resolver.Resolve(ctx, principalName)
-		if err != nil {
-			return nil, fmt.Errorf("resolve compute executor: %w", err)
+// SetExtensionAllowlist sets the allowlist used to explain queries that fail
+// because they need an extension the server refuses to load.
+func (e *SecureEngine) SetExtensionAllowlist(allow *ExtensionAllowlist) {
+	e.extensions = allow
+}
+
+// SetDenialRecorder sets where refused privilege checks are reported for
+// auditing. Without it, denials are only returned to the caller.
+func (e *SecureEngine) SetDenialRecorder(r domain.DenialRecorder) {
+	e.denials = r
+}
+
+// SetQueryDefaults sets where the per-principal query session defaults are
+// read from. Without it, every query runs with the server's session settings.
+func (e *SecureEngine) SetQueryDefaults(repo domain.PrincipalQueryDefaultsRepository) {
+	e.defaults = repo
+}
+
+// denied reports a refused privilege check and returns err unchanged.
+func (e *SecureEngine) denied(ctx context.Context, principalName, privilege, securable string, err error) error {
+	if e.denials != nil {
+		e.denials.RecordDenial(ctx, domain.AccessDenial{
+			PrincipalName: principalName,
+			Action:        privilege,
+			Securable:     securable,
+			Reason:        err.Error(),
+		})
+	}
+	return err
+}
+
+// resolveExecutor resolves the ComputeExecutor for the principal. A nil
+// executor means the query runs on the local *sql.DB.
+func (e *SecureEngine) resolveExecutor(ctx context.Context, principalName string) (domain.ComputeExecutor, error) {
+	if e.resolver == nil {
+		return nil, nil
+	}
+	executor, err := e.resolver.Resolve(ctx, principalName)
+	if err != nil {
+		return nil, fmt.Errorf("resolve compute executor: %w", err)
+	}
+	return executor, nil
+}
+
+// queryDefaults returns the principal's query session defaults, or the zero
+// value when none are stored or no store is configured.
+func (e *SecureEngine) queryDefaults(ctx context.Context, principalName string) (domain.PrincipalQueryDefaults, error) {
+	if e.defaults == nil {
+		return domain.PrincipalQueryDefaults{}, nil
+	}
+	defaults, err := e.defaults.GetByPrincipalName(ctx, principalName)
+	if err != nil {
+		return domain.PrincipalQueryDefaults{}, fmt.Errorf("load query defaults: %w", err)
+	}
+	return defaults, nil
+}
+
+// queryWithDefaults runs the query on a dedicated local connection after
+// applying the session defaults with SET. Session settings outlive the query,
+// so the connection is discarded instead of returned to the pool once the
+// caller closes the rows.
+func (e *SecureEngine) queryWithDefaults(ctx context.Context, defaults domain.PrincipalQueryDefaults, query string) (*sql.Rows, error) {
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pin connection: %w", err)
+	}
+	discard := func() {
+		// Returning ErrBadConn makes database/sql close the connection
+		// once the rows read from it are closed.
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+
+	var settings []string
+	if path := defaults.SearchPath(); path != "" {
+		settings = append(settings, "SET search_path = "+ddl.QuoteLiteral(path))
+	}
+	if defaults.Timezone != "" {
+		settings = append(settings, "SET TimeZone = "+ddl.QuoteLiteral(defaults.Timezone))
+	}
+	for _, stmt := range settings {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			discard()
+			return nil, fmt.Errorf("apply query defaults: %w", err)
 		}
-		if executor != nil {
-			return executor.QueryContext(ctx, query)
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		discard()
+		return nil, err
+	}
+	go discard()
+	return rows, nil
+}
+
+// qualifyTableRef returns the table a reference resolves to under the
+// principal's query defaults, so privileges are checked on the table DuckDB
+// executes against: unqualified names resolve in the default search path and
+// schema-qualified names in the default catalog.
+func qualifyTableRef(ref sqlrewrite.TableRef, defaults domain.PrincipalQueryDefaults) sqlrewrite.TableRef {
+	switch {
+	case ref.Catalog != "" || strings.HasPrefix(ref.Name, "__func__"):
+		return ref
+	case ref.Schema == "":
+		if catalog, schema, ok := strings.Cut(defaults.SearchPath(), "."); ok {
+			ref.Catalog, ref.Schema = catalog, schema
+		} else {
+			ref.Schema = defaults.SearchPath()
 		}
+	case defaults.DefaultCatalog != "":
+		ref.Catalog = defaults.DefaultCatalog
 	}
-	return e.db.QueryContext(ctx, query)
+	return ref
 }
 
 // rewriteQuery runs the full security pipeline (classify → RBAC → RLS → column masking)
 // and returns the rewritten SQL string. Used by both Query() and QueryOnConn().
-func (e *SecureEngine) rewriteQuery(ctx context.Context, principalName, sqlQuery string) (string, error) {
+// Table references are resolved under the given query defaults.
+func (e *SecureEngine) rewriteQuery(ctx context.Context, principalName, sqlQuery string, defaults domain.PrincipalQueryDefaults) (string, error) {
 	// 1. Classify statement type
 	stmtType, err := sqlrewrite.ClassifyStatement(sqlQuery)
 	if err != nil {
@@ -82,17 +189,19 @@ func (e *SecureEngine) rewriteQuery(ctx context.Context, principalName, sqlQuery
 				return "", fmt.Errorf("privilege check: %w", authErr)
 			}
 			if !allowed {
-				return "", domain.ErrAccessDenied("%q lacks %s privilege for table-less queries", principalName, requiredPriv)
+				return "", e.denied(ctx, principalName, requiredPriv, domain.SecurableCatalog,
+					domain.ErrAccessDenied("%q lacks %s privilege for table-less queries", principalName, requiredPriv))
 			}
 		}
 		return sqlQuery, nil
 	}
 
 	// 3. Check privileges + collect filters/masks for each table
+	trace := domain.PolicyTraceFromContext(ctx)
 	rewritten := sqlQuery
 	for _, tableRef := range tableRefs {
 		tableName := tableRef.Name
-		tablePath := formatTableRef(tableRef)
+		tablePath := formatTableRef(qualifyTableRef(tableRef, defaults))
 
 		// Skip sentinel entries added by ExtractTableNames for table-valued
 		// functions (e.g., range(), generate_series()). These sentinels exist
@@ -110,7 +219,8 @@ func (e *SecureEngine) rewriteQuery(ctx context.Context, principalName, sqlQuery
 
 		// Block DML on external (read-only) tables
 		if isExternal && stmtType != sqlrewrite.StmtSelect {
-			return "", fmt.Errorf("access denied: table %q is read-only (EXTERNAL)", tablePath)
+			return "", e.denied(ctx, principalName, requiredPriv, tablePath,
+				fmt.Errorf("access denied: table %q is read-only (EXTERNAL)", tablePath))
 		}
 
 		// Check privilege
@@ -119,9 +229,12 @@ func (e *SecureEngine) rewriteQuery(ctx context.Context, principalName, sqlQuery
 			return "", fmt.Errorf("privilege check: %w", err)
 		}
 		if !allowed {
-			return "", domain.ErrAccessDenied("principal %q lacks %s on table %q", principalName, requiredPriv, tablePath)
+			return "", e.denied(ctx, principalName, requiredPriv, tablePath,
+				domain.ErrAccessDenied("principal %q lacks %s on table %q", principalName, requiredPriv, tablePath))
 		}
 
+		applied := domain.TablePolicy{Table: tablePath}
+
 		// Get row filters (for SELECT, UPDATE, and DELETE)
 		if stmtType == sqlrewrite.StmtSelect || stmtType == sqlrewrite.StmtUpdate || stmtType == sqlrewrite.StmtDelete {
 			filters, err := e.catalog.GetEffectiveRowFilters(ctx, principalName, tableID)
@@ -133,6 +246,7 @@ func (e *SecureEngine) rewriteQuery(ctx context.Context, principalName, sqlQuery
 				if err != nil {
 					return "", fmt.Errorf("inject row filter: %w", err)
 				}
+				applied.RowFiltered = true
 			}
 		}
 
@@ -152,8 +266,13 @@ func (e *SecureEngine) rewriteQuery(ctx context.Context, principalName, sqlQuery
 				if err != nil {
 					return "", fmt.Errorf("apply column masks: %w", err)
 				}
+				applied.MaskedColumns = slices.Sorted(maps.Keys(masks))
 			}
 		}
+
+		if trace != nil {
+			trace.Record(applied)
+		}
 	}
 
 	e.logger.Debug("query rewritten", "principal", principalName, "statement", stmtType, "tables", tableRefs, "sql", rewritten)
@@ -184,20 +303,43 @@ func formatTableRef(ref sqlrewrite.TableRef) string {
 //  3. For each table: check privilege, get row filter, get column masks
 //  4. Inject row filters and column masks into the SQL
 //  5. Execute the rewritten SQL against DuckDB
+//
+// Queries run locally use the principal's query defaults (search path and
+// time zone) for their session. Principals assigned a remote compute
+// endpoint keep that endpoint's session settings.
 func (e *SecureEngine) Query(ctx context.Context, principalName, sqlQuery string) (*sql.Rows, error) {
 	// Intercept information_schema queries
 	if e.infoSchema != nil && IsInformationSchemaQuery(sqlQuery) {
 		return e.infoSchema.HandleQuery(ctx, e.db, principalName, sqlQuery)
 	}
 
-	rewritten, err := e.rewriteQuery(ctx, principalName, sqlQuery)
+	executor, err := e.resolveExecutor(ctx, principalName)
 	if err != nil {
 		return nil, err
 	}
+	var defaults domain.PrincipalQueryDefaults
+	if executor == nil {
+		if defaults, err = e.queryDefaults(ctx, principalName); err != nil {
+			return nil, err
+		}
+	}
 
-	rows, err := e.execQuery(ctx, principalName, rewritten)
+	rewritten, err := e.rewriteQuery(ctx, principalName, sqlQuery, defaults)
 	if err != nil {
-		return nil, fmt.Errorf("execute query: %w", err)
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	switch {
+	case executor != nil:
+		rows, err = executor.QueryContext(ctx, rewritten)
+	case defaults.IsZero():
+		rows, err = e.db.QueryContext(ctx, rewritten)
+	default:
+		rows, err = e.queryWithDefaults(ctx, defaults, rewritten)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", e.extensions.explainQueryError(err))
 	}
 	return rows, nil
 }
@@ -210,12 +352,16 @@ func (e *SecureEngine) QueryOnConn(ctx context.Context, conn *sql.Conn, principa
 		return e.infoSchema.HandleQuery(ctx, e.db, principalName, sqlQuery)
 	}
 
-	rewritten, err := e.rewriteQuery(ctx, principalName, sqlQuery)
+	rewritten, err := e.rewriteQuery(ctx, principalName, sqlQuery, domain.PrincipalQueryDefaults{})
 	if err != nil {
 		return nil, err
 	}
 
-	return conn.QueryContext(ctx, rewritten)
+	rows, err := conn.QueryContext(ctx, rewritten)
+	if err != nil {
+		return nil, e.extensions.explainQueryError(err)
+	}
+	return rows, nil
 }
 
 // privilegeForStatement maps a statement type to the required privilege.
@@ -242,7 +388,7 @@ func privilegeForStatement(t sqlrewrite.StatementType) (string, error) {
 
 // InstallPostgresExtension installs and loads the DuckDB postgres extension.
 func InstallPostgresExtension(ctx context.Context, db *sql.DB) error {
-	if _, err := db.ExecContext(ctx, "INSTALL postgres; LOAD postgres;"); err != nil {
+	if err := LoadExtension(ctx, db, nil, "postgres"); err != nil {
 		return fmt.Errorf("postgres extension: %w", err)
 	}
 	return nil
@@ -250,16 +396,18 @@ func InstallPostgresExtension(ctx context.Context, db *sql.DB) error {
 
 // === DuckLake Setup Functions ===
 
-// InstallExtensions installs and loads DuckDB extensions needed for DuckLake.
+// InstallExtensions installs and loads DuckDB extensions needed for DuckLake
+// from the default allowlist.
 // Safe to call without S3 credentials — just makes the extensions available.
 func InstallExtensions(ctx context.Context, db *sql.DB) error {
-	extensions := []string{
-		"INSTALL ducklake; LOAD ducklake;",
-		"INSTALL sqlite; LOAD sqlite;",
-		"INSTALL httpfs; LOAD httpfs;",
-	}
-	for _, ext := range extensions {
-		if _, err := db.ExecContext(ctx, ext); err != nil {
+	return InstallExtensionsWithAllowlist(ctx, db, nil)
+}
+
+// InstallExtensionsWithAllowlist installs and loads the extensions DuckLake
+// needs, refusing to start when the allowlist excludes one of them.
+func InstallExtensionsWithAllowlist(ctx context.Context, db *sql.DB, allow *ExtensionAllowlist) error {
+	for _, ext := range []string{"ducklake", "sqlite", "httpfs"} {
+		if err := LoadExtension(ctx, db, allow, ext); err != nil {
 			return fmt.Errorf("extension setup (%s): %w", ext, err)
 		}
 	}
@@ -0,0 +1,208 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"duck-demo/internal/domain"
+)
+
+// DefaultExtensionAllowlist lists the DuckDB extensions the server may load
+// when DUCKDB_EXTENSION_ALLOWLIST is not set: DuckLake with its SQLite and
+// PostgreSQL metastores, and the object storage backends.
+var DefaultExtensionAllowlist = []string{"ducklake", "sqlite", "httpfs", "postgres", "azure"}
+
+// extensionAliases maps the names accepted by INSTALL/LOAD to the names
+// DuckDB reports in duckdb_extensions().
+var extensionAliases = map[string]string{
+	"postgres": "postgres_scanner",
+	"sqlite":   "sqlite_scanner",
+	"sqlite3":  "sqlite_scanner",
+	"mysql":    "mysql_scanner",
+}
+
+var extensionNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// missingExtensionPattern matches DuckDB's hint for a function, file scheme
+// or secret type that lives in an extension that is not loaded.
+var missingExtensionPattern = regexp.MustCompile(`loading the (\w+) extension`)
+
+// canonicalExtension returns the duckdb_extensions() name for an extension.
+func canonicalExtension(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := extensionAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// ExtensionAllowlist is the set of DuckDB extensions the server may install
+// and load. Extensions statically linked into DuckDB are always available
+// and are not subject to it. A nil allowlist allows DefaultExtensionAllowlist.
+type ExtensionAllowlist struct {
+	names   []string
+	allowed map[string]bool
+}
+
+var defaultExtensionAllowlist = NewExtensionAllowlist(nil)
+
+// NewExtensionAllowlist builds an allowlist from extension names. An empty
+// list selects DefaultExtensionAllowlist.
+func NewExtensionAllowlist(names []string) *ExtensionAllowlist {
+	if len(names) == 0 {
+		names = DefaultExtensionAllowlist
+	}
+	a := &ExtensionAllowlist{allowed: make(map[string]bool, len(names))}
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n == "" {
+			continue
+		}
+		a.names = append(a.names, n)
+		a.allowed[canonicalExtension(n)] = true
+	}
+	return a
+}
+
+// Names returns the allowlisted extension names as configured.
+func (a *ExtensionAllowlist) Names() []string {
+	if a == nil {
+		a = defaultExtensionAllowlist
+	}
+	return append([]string(nil), a.names...)
+}
+
+// Allows reports whether the extension may be installed and loaded.
+func (a *ExtensionAllowlist) Allows(name string) bool {
+	if a == nil {
+		a = defaultExtensionAllowlist
+	}
+	return a.allowed[canonicalExtension(name)]
+}
+
+// Check returns a validation error naming the extension when it is not on
+// the allowlist.
+func (a *ExtensionAllowlist) Check(name string) error {
+	if !a.Allows(name) {
+		return domain.ErrValidation("DuckDB extension %q is not on the extension allowlist (DUCKDB_EXTENSION_ALLOWLIST); allowed: %s",
+			name, strings.Join(a.Names(), ", "))
+	}
+	return nil
+}
+
+// explainQueryError replaces DuckDB's advice to install a missing extension
+// with an allowlist error when the extension is not allowlisted, so a query
+// that needs a refused extension fails with a clear message.
+func (a *ExtensionAllowlist) explainQueryError(err error) error {
+	if err == nil {
+		return nil
+	}
+	m := missingExtensionPattern.FindStringSubmatch(err.Error())
+	if m == nil || a.Allows(m[1]) {
+		return err
+	}
+	return domain.ErrValidation("query requires DuckDB extension %q, which is not on the extension allowlist", m[1])
+}
+
+// LoadExtension installs (when needed) and loads an allowlisted extension.
+// Extensions that are already loaded, including statically linked ones, are
+// left alone so no download is attempted.
+func LoadExtension(ctx context.Context, db *sql.DB, allow *ExtensionAllowlist, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !extensionNamePattern.MatchString(name) {
+		return domain.ErrValidation("invalid DuckDB extension name %q", name)
+	}
+	if err := allow.Check(name); err != nil {
+		return err
+	}
+
+	var installed, loaded bool
+	err := db.QueryRowContext(ctx,
+		"SELECT installed, loaded FROM duckdb_extensions() WHERE extension_name = ?", canonicalExtension(name),
+	).Scan(&installed, &loaded)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("look up extension %s: %w", name, err)
+	}
+	if loaded {
+		return nil
+	}
+	if !installed {
+		if _, err := db.ExecContext(ctx, "INSTALL "+name); err != nil {
+			return fmt.Errorf("install extension %s: %w", name, err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, "LOAD "+name); err != nil {
+		return fmt.Errorf("load extension %s: %w", name, err)
+	}
+	return nil
+}
+
+// RestrictExtensionAutoload stops DuckDB from installing or loading known
+// extensions on demand, so only extensions loaded through LoadExtension are
+// available to queries.
+func RestrictExtensionAutoload(ctx context.Context, db *sql.DB) error {
+	for _, stmt := range []string{
+		"SET autoinstall_known_extensions = false",
+		"SET autoload_known_extensions = false",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("restrict extension autoload: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListExtensions reports the extensions that are installed or loaded in db
+// and whether each is allowlisted.
+func ListExtensions(ctx context.Context, db *sql.DB, allow *ExtensionAllowlist) ([]domain.DuckDBExtension, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT extension_name, installed, loaded, COALESCE(extension_version, ''), COALESCE(install_mode, '')
+		FROM duckdb_extensions()
+		WHERE installed OR loaded
+		ORDER BY extension_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list duckdb extensions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []domain.DuckDBExtension
+	for rows.Next() {
+		var e domain.DuckDBExtension
+		if err := rows.Scan(&e.Name, &e.Installed, &e.Loaded, &e.Version, &e.InstallMode); err != nil {
+			return nil, fmt.Errorf("scan duckdb extension: %w", err)
+		}
+		e.StaticallyLinked = e.InstallMode == "STATICALLY_LINKED"
+		e.Allowed = e.StaticallyLinked || allow.Allows(e.Name)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// DuckDBExtensionInventory reports the extensions of a DuckDB instance
+// against an allowlist.
+type DuckDBExtensionInventory struct {
+	db    *sql.DB
+	allow *ExtensionAllowlist
+}
+
+var _ domain.ExtensionInventory = (*DuckDBExtensionInventory)(nil)
+
+// NewExtensionInventory creates a DuckDBExtensionInventory.
+func NewExtensionInventory(db *sql.DB, allow *ExtensionAllowlist) *DuckDBExtensionInventory {
+	return &DuckDBExtensionInventory{db: db, allow: allow}
+}
+
+// ListExtensions reports the installed and loaded extensions.
+func (i *DuckDBExtensionInventory) ListExtensions(ctx context.Context) ([]domain.DuckDBExtension, error) {
+	return ListExtensions(ctx, i.db, i.allow)
+}
+
+// ExtensionAllowlist returns the allowlisted extension names.
+func (i *DuckDBExtensionInventory) ExtensionAllowlist() []string {
+	return i.allow.Names()
+}
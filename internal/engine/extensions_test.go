@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+func openExtensionTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestExtensionAllowlist(t *testing.T) {
+	t.Run("defaults when empty", func(t *testing.T) {
+		allow := NewExtensionAllowlist(nil)
+		assert.Equal(t, DefaultExtensionAllowlist, allow.Names())
+		assert.True(t, allow.Allows("ducklake"))
+		assert.False(t, allow.Allows("spatial"))
+
+		var nilAllow *ExtensionAllowlist
+		assert.True(t, nilAllow.Allows("httpfs"))
+	})
+
+	t.Run("matches canonical names", func(t *testing.T) {
+		allow := NewExtensionAllowlist([]string{" Postgres ", "sqlite"})
+		assert.True(t, allow.Allows("postgres_scanner"))
+		assert.True(t, allow.Allows("sqlite_scanner"))
+		assert.False(t, allow.Allows("httpfs"))
+	})
+}
+
+func TestLoadExtension_Allowlist(t *testing.T) {
+	ctx := context.Background()
+	db := openExtensionTestDB(t)
+	allow := NewExtensionAllowlist([]string{"json"})
+
+	// json is statically linked, so loading it needs no download.
+	require.NoError(t, LoadExtension(ctx, db, allow, "json"))
+
+	err := LoadExtension(ctx, db, allow, "httpfs")
+	var verr *domain.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Contains(t, err.Error(), `"httpfs" is not on the extension allowlist`)
+
+	err = LoadExtension(ctx, db, allow, "json; DROP TABLE x")
+	require.ErrorAs(t, err, &verr)
+}
+
+func TestListExtensions(t *testing.T) {
+	ctx := context.Background()
+	db := openExtensionTestDB(t)
+
+	inv := NewExtensionInventory(db, NewExtensionAllowlist([]string{"ducklake"}))
+	exts, err := inv.ListExtensions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ducklake"}, inv.ExtensionAllowlist())
+
+	var json *domain.DuckDBExtension
+	for i := range exts {
+		if exts[i].Name == "json" {
+			json = &exts[i]
+		}
+	}
+	require.NotNil(t, json, "json is statically linked and always reported")
+	assert.True(t, json.StaticallyLinked)
+	assert.True(t, json.Allowed)
+	assert.NotEmpty(t, json.Version)
+}
+
+func TestExplainQueryError(t *testing.T) {
+	ctx := context.Background()
+	db := openExtensionTestDB(t)
+	require.NoError(t, RestrictExtensionAutoload(ctx, db))
+
+	_, err := db.ExecContext(ctx, "SELECT * FROM read_csv('https://example.com/data.csv')")
+	require.Error(t, err)
+
+	refused := NewExtensionAllowlist([]string{"ducklake"}).explainQueryError(err)
+	var verr *domain.ValidationError
+	require.ErrorAs(t, refused, &verr)
+	assert.Contains(t, refused.Error(), `query requires DuckDB extension "httpfs"`)
+
+	allowed := NewExtensionAllowlist([]string{"httpfs"}).explainQueryError(err)
+	assert.Equal(t, err, allowed)
+
+	other := errors.New("syntax error")
+	assert.Equal(t, other, NewExtensionAllowlist(nil).explainQueryError(other))
+}
+
+func TestSecretManagerAttach_RefusesUnallowlistedMetastore(t *testing.T) {
+	m := NewDuckDBSecretManager(openExtensionTestDB(t))
+	m.SetExtensionAllowlist(NewExtensionAllowlist([]string{"ducklake", "sqlite"}))
+
+	err := m.Attach(context.Background(), domain.CatalogRegistration{
+		Name:          "pg",
+		MetastoreType: domain.MetastoreTypePostgres,
+		DSN:           "host=localhost",
+	})
+	var verr *domain.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Contains(t, err.Error(), `"postgres" is not on the extension allowlist`)
+}
@@ -96,7 +96,7 @@ func (p *InformationSchemaProvider) BuildSchemataRows(ctx context.Context) ([][]
 			continue // skip catalogs that fail
 		}
 		page := domain.PageRequest{MaxResults: 1000}
-		schemas, _, err := repo.ListSchemas(ctx, page)
+		schemas, _, err := repo.ListSchemas(ctx, nil, page)
 		if err != nil {
 			continue // skip catalogs that fail
 		}
@@ -118,12 +118,12 @@ func (p *InformationSchemaProvider) BuildTablesRows(ctx context.Context) ([][]in
 			continue
 		}
 		page := domain.PageRequest{MaxResults: 1000}
-		schemas, _, err := repo.ListSchemas(ctx, page)
+		schemas, _, err := repo.ListSchemas(ctx, nil, page)
 		if err != nil {
 			continue
 		}
 		for _, s := range schemas {
-			tables, _, err := repo.ListTables(ctx, s.Name, page)
+			tables, _, err := repo.ListTables(ctx, s.Name, nil, page)
 			if err != nil {
 				continue
 			}
@@ -146,12 +146,12 @@ func (p *InformationSchemaProvider) BuildColumnsRows(ctx context.Context) ([][]i
 			continue
 		}
 		page := domain.PageRequest{MaxResults: 1000}
-		schemas, _, err := repo.ListSchemas(ctx, page)
+		schemas, _, err := repo.ListSchemas(ctx, nil, page)
 		if err != nil {
 			continue
 		}
 		for _, s := range schemas {
-			tables, _, err := repo.ListTables(ctx, s.Name, page)
+			tables, _, err := repo.ListTables(ctx, s.Name, nil, page)
 			if err != nil {
 				continue
 			}
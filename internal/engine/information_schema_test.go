@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	_ "github.com/duckdb/duckdb-go/v2"
 	"github.com/stretchr/testify/assert"
@@ -27,19 +28,22 @@ func (m *mockEngineCatalog) GetCatalogInfo(_ context.Context) (*domain.CatalogIn
 func (m *mockEngineCatalog) GetMetastoreSummary(_ context.Context) (*domain.MetastoreSummary, error) {
 	panic("unexpected call")
 }
+func (m *mockEngineCatalog) GetCatalogStats(_ context.Context, _ int) (*domain.CatalogStats, error) {
+	panic("unexpected call")
+}
 func (m *mockEngineCatalog) CreateSchema(_ context.Context, _, _, _ string) (*domain.SchemaDetail, error) {
 	panic("unexpected call")
 }
 func (m *mockEngineCatalog) GetSchema(_ context.Context, _ string) (*domain.SchemaDetail, error) {
 	panic("unexpected call")
 }
-func (m *mockEngineCatalog) ListSchemas(ctx context.Context, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+func (m *mockEngineCatalog) ListSchemas(ctx context.Context, _ *string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
 	if m.listSchemasFn != nil {
 		return m.listSchemasFn(ctx, page)
 	}
 	panic("unexpected call to ListSchemas")
 }
-func (m *mockEngineCatalog) UpdateSchema(_ context.Context, _ string, _ *string, _ map[string]string) (*domain.SchemaDetail, error) {
+func (m *mockEngineCatalog) UpdateSchema(_ context.Context, _ string, _ *string, _ map[string]string, _ *int64) (*domain.SchemaDetail, error) {
 	panic("unexpected call")
 }
 func (m *mockEngineCatalog) DeleteSchema(_ context.Context, _ string, _ bool) error {
@@ -51,7 +55,7 @@ func (m *mockEngineCatalog) CreateTable(_ context.Context, _ string, _ domain.Cr
 func (m *mockEngineCatalog) GetTable(_ context.Context, _, _ string) (*domain.TableDetail, error) {
 	panic("unexpected call")
 }
-func (m *mockEngineCatalog) ListTables(ctx context.Context, schemaName string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
+func (m *mockEngineCatalog) ListTables(ctx context.Context, schemaName string, _ *string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
 	if m.listTablesFn != nil {
 		return m.listTablesFn(ctx, schemaName, page)
 	}
@@ -66,7 +70,7 @@ func (m *mockEngineCatalog) ListColumns(ctx context.Context, schemaName, tableNa
 	}
 	panic("unexpected call to ListColumns")
 }
-func (m *mockEngineCatalog) UpdateTable(_ context.Context, _, _ string, _ *string, _ map[string]string, _ *string) (*domain.TableDetail, error) {
+func (m *mockEngineCatalog) UpdateTable(_ context.Context, _, _ string, _ *string, _ map[string]string, _ *string, _ *int64) (*domain.TableDetail, error) {
 	panic("unexpected call")
 }
 func (m *mockEngineCatalog) UpdateCatalog(_ context.Context, _ *string) (*domain.CatalogInfo, error) {
@@ -81,6 +85,26 @@ func (m *mockEngineCatalog) SetSchemaStoragePath(_ context.Context, _ string, _
 func (m *mockEngineCatalog) CreateExternalTable(_ context.Context, _ string, _ domain.CreateTableRequest, _ string) (*domain.TableDetail, error) {
 	panic("unexpected call")
 }
+func (m *mockEngineCatalog) ListFiles(_ context.Context, _ string) ([]string, error) {
+	panic("unexpected call")
+}
+func (m *mockEngineCatalog) CompactTable(_ context.Context, _, _ string) (*domain.TableMaintenanceResult, error) {
+	panic("unexpected call")
+}
+func (m *mockEngineCatalog) VacuumTable(_ context.Context, _, _ string, _ time.Duration) (*domain.TableMaintenanceResult, error) {
+	panic("unexpected call")
+}
+func (m *mockEngineCatalog) RollbackTable(_ context.Context, _, _ string, _ domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+	panic("unexpected call")
+}
+
+func (m *mockEngineCatalog) MaterializeTable(_ context.Context, _, _, _ string) (int64, error) {
+	panic("unexpected call")
+}
+
+func (m *mockEngineCatalog) RenameTable(_ context.Context, _, _, _ string) (*domain.TableDetail, error) {
+	panic("unexpected call")
+}
 
 var _ domain.CatalogRepository = (*mockEngineCatalog)(nil)
 
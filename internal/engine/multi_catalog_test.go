@@ -0,0 +1,238 @@
+//go:build integration
+
+package engine_test
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internaldb "duck-demo/internal/db"
+	dbstore "duck-demo/internal/db/dbstore"
+	"duck-demo/internal/db/repository"
+	"duck-demo/internal/domain"
+	"duck-demo/internal/engine"
+	"duck-demo/internal/service/security"
+)
+
+// duckLakeOrdersMetastore creates a DuckLake-like metastore holding schema
+// main (ID 0) with table orders (ID 1). Every catalog gets the same IDs, as
+// fresh DuckLake metastores do.
+func duckLakeOrdersMetastore(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS ducklake_schema (
+			schema_id INTEGER PRIMARY KEY,
+			schema_uuid TEXT,
+			begin_snapshot INTEGER,
+			end_snapshot INTEGER,
+			schema_name TEXT,
+			path TEXT,
+			path_is_relative INTEGER
+		);
+		CREATE TABLE IF NOT EXISTS ducklake_table (
+			table_id INTEGER,
+			table_uuid TEXT,
+			begin_snapshot INTEGER,
+			end_snapshot INTEGER,
+			schema_id INTEGER,
+			table_name TEXT,
+			path TEXT,
+			path_is_relative INTEGER
+		);
+		INSERT OR IGNORE INTO ducklake_schema (schema_id, schema_name, begin_snapshot) VALUES (0, 'main', 0);
+		INSERT OR IGNORE INTO ducklake_table (table_id, table_name, schema_id, begin_snapshot) VALUES (1, 'orders', 0, 1);
+	`)
+	require.NoError(t, err)
+}
+
+// setupMultiCatalogEngine creates a SecureEngine over two attached catalogs,
+// lake (the default) and archive, that both hold main.orders with different
+// rows. The authorization service resolves each catalog in its own metastore.
+//
+// Policies:
+//   - analyst: SELECT on lake.main.orders filtered to region 'eu', and
+//     unfiltered SELECT on archive.main.orders through grants scoped to the
+//     archive catalog
+//   - archivist: ALL_PRIVILEGES on the archive catalog, with archive.main.orders
+//     filtered to region 'us'
+func setupMultiCatalogEngine(t *testing.T) *engine.SecureEngine {
+	t.Helper()
+
+	duck, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = duck.Close() })
+	// One connection, so USE lake applies to every query.
+	duck.SetMaxOpenConns(1)
+	_, err = duck.ExecContext(ctx, `
+		ATTACH ':memory:' AS lake;
+		ATTACH ':memory:' AS archive;
+		CREATE TABLE lake.main.orders (id INTEGER, region VARCHAR);
+		INSERT INTO lake.main.orders VALUES (1, 'eu'), (2, 'us');
+		CREATE TABLE archive.main.orders (id INTEGER, region VARCHAR);
+		INSERT INTO archive.main.orders VALUES (10, 'eu'), (20, 'us'), (30, 'us');
+		USE lake;
+	`)
+	require.NoError(t, err)
+
+	controlDB, _ := internaldb.OpenTestSQLite(t)
+	duckLakeOrdersMetastore(t, controlDB)
+	archiveMeta, _ := internaldb.OpenTestSQLite(t)
+	duckLakeOrdersMetastore(t, archiveMeta)
+
+	metastores := map[string]*repository.IntrospectionRepo{
+		"lake":    repository.NewIntrospectionRepo(controlDB),
+		"archive": repository.NewIntrospectionRepo(archiveMeta),
+	}
+	catalogRegs := repository.NewCatalogRegistrationRepo(controlDB)
+
+	cat := security.NewAuthorizationService(
+		repository.NewPrincipalRepo(controlDB),
+		repository.NewGroupRepo(controlDB),
+		repository.NewGrantRepo(controlDB),
+		repository.NewRowFilterRepo(controlDB),
+		repository.NewColumnMaskRepo(controlDB),
+		metastores["lake"],
+		nil,
+	)
+	cat.SetCatalogRegistrationRepository(catalogRegs)
+	cat.SetDefaultCatalogLookup(func(context.Context) (string, error) { return "lake", nil })
+	cat.SetCatalogIntrospection(func(_ context.Context, catalogName string) (domain.IntrospectionRepository, error) {
+		repo, ok := metastores[catalogName]
+		if !ok {
+			return nil, domain.ErrNotFound("catalog %q not found", catalogName)
+		}
+		return repo, nil
+	})
+	cat.SetCatalogTableLookup(func(ctx context.Context, catalogName, schemaName, tableName string) (*domain.TableDetail, error) {
+		repo, ok := metastores[catalogName]
+		if !ok {
+			return nil, domain.ErrNotFound("catalog %q not found", catalogName)
+		}
+		tbl, err := repo.GetTableBySchemaAndName(ctx, schemaName, tableName)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.TableDetail{TableID: tbl.ID, Name: tbl.Name, SchemaName: schemaName, CatalogName: catalogName, TableType: domain.TableTypeManaged}, nil
+	})
+
+	catalogIDs := map[string]string{}
+	for _, reg := range []domain.CatalogRegistration{{Name: "lake", IsDefault: true}, {Name: "archive"}} {
+		reg.MetastoreType = domain.MetastoreTypeSQLite
+		reg.DSN = reg.Name + ".sqlite"
+		reg.DataPath = "/data/" + reg.Name
+		reg.Status = domain.CatalogStatusActive
+		created, err := catalogRegs.Create(ctx, &reg)
+		require.NoError(t, err)
+		catalogIDs[reg.Name] = created.ID
+	}
+	require.NoError(t, catalogRegs.SetDefault(ctx, catalogIDs["lake"]))
+	archiveTable := domain.CatalogScopedID(catalogIDs["archive"], "1")
+	archiveSchema := domain.CatalogScopedID(catalogIDs["archive"], "0")
+
+	q := dbstore.New(controlDB)
+	analyst, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{
+		ID: uuid.New().String(), Name: "analyst", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+	archivist, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{
+		ID: uuid.New().String(), Name: "archivist", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+
+	grant := func(principalID, securableType, securableID, privilege string) {
+		t.Helper()
+		_, err := q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+			ID: uuid.New().String(), PrincipalID: principalID, PrincipalType: "user",
+			SecurableType: securableType, SecurableID: securableID,
+			Privilege: privilege,
+		})
+		require.NoError(t, err)
+	}
+	grant(analyst.ID, domain.SecurableSchema, "0", domain.PrivUseSchema)
+	grant(analyst.ID, domain.SecurableTable, "1", domain.PrivSelect)
+	grant(analyst.ID, domain.SecurableSchema, archiveSchema, domain.PrivUseSchema)
+	grant(analyst.ID, domain.SecurableTable, archiveTable, domain.PrivSelect)
+	grant(archivist.ID, domain.SecurableCatalog, catalogIDs["archive"], domain.PrivAllPrivileges)
+
+	rowFilter := func(principalID, tableID, filterSQL string) {
+		t.Helper()
+		filter, err := q.CreateRowFilter(ctx, dbstore.CreateRowFilterParams{
+			ID: uuid.New().String(), TableID: tableID, FilterSql: filterSQL,
+		})
+		require.NoError(t, err)
+		require.NoError(t, q.BindRowFilter(ctx, dbstore.BindRowFilterParams{
+			ID: uuid.New().String(), RowFilterID: filter.ID, PrincipalID: principalID, PrincipalType: "user",
+		}))
+	}
+	rowFilter(analyst.ID, "1", `"region" = 'eu'`)
+	rowFilter(archivist.ID, archiveTable, `"region" = 'us'`)
+
+	return engine.NewSecureEngine(duck, cat, nil, nil, slog.New(slog.DiscardHandler))
+}
+
+// queryIDs runs sqlStr as principal and returns the id column.
+func queryIDs(t *testing.T, eng *engine.SecureEngine, principal, sqlStr string) ([]int, error) {
+	t.Helper()
+
+	rows, err := eng.Query(ctx, principal, sqlStr)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Err())
+	return ids, nil
+}
+
+func TestMultiCatalog_PoliciesResolvedPerCatalog(t *testing.T) {
+	eng := setupMultiCatalogEngine(t)
+
+	tests := []struct {
+		name      string
+		principal string
+		query     string
+		wantIDs   []int
+		wantErr   bool
+	}{
+		{"row filter applies in the catalog it was created on",
+			"analyst", "SELECT id FROM lake.main.orders ORDER BY id", []int{1}, false},
+		{"unqualified names resolve to the default catalog",
+			"analyst", "SELECT id FROM orders ORDER BY id", []int{1}, false},
+		{"row filter does not follow the table ID into another catalog",
+			"analyst", "SELECT id FROM archive.main.orders ORDER BY id", []int{10, 20, 30}, false},
+		{"catalog grant cascades to the catalog's tables",
+			"archivist", "SELECT id FROM archive.main.orders ORDER BY id", []int{20, 30}, false},
+		{"catalog grant does not reach another catalog's table with the same ID",
+			"archivist", "SELECT id FROM lake.main.orders", nil, true},
+		{"catalog grant does not reach unqualified default catalog tables",
+			"archivist", "SELECT id FROM orders", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := queryIDs(t, eng, tt.principal, tt.query)
+			if tt.wantErr {
+				require.Error(t, err)
+				var denied *domain.AccessDeniedError
+				assert.ErrorAs(t, err, &denied)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package engine_test
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/engine"
+	"duck-demo/internal/testutil"
+)
+
+func TestQueryRecordsAppliedPolicies(t *testing.T) {
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.ExecContext(context.Background(), `
+		CREATE TABLE users (id INTEGER, email VARCHAR, region VARCHAR);
+		INSERT INTO users VALUES (1, 'a@example.com', 'eu'), (2, 'b@example.com', 'us');
+		CREATE TABLE orders (id INTEGER, user_id INTEGER)`)
+	require.NoError(t, err)
+
+	authz := &testutil.MockAuthService{
+		LookupTableIDFn: func(_ context.Context, name string) (string, string, bool, error) {
+			return name, "s-1", false, nil
+		},
+		CheckPrivilegeFn: func(context.Context, string, string, string, string) (bool, error) {
+			return true, nil
+		},
+		GetEffectiveRowFiltersFn: func(_ context.Context, _, tableID string) ([]string, error) {
+			if tableID == "users" {
+				return []string{`region = 'eu'`}, nil
+			}
+			return nil, nil
+		},
+		GetEffectiveColumnMasksFn: func(_ context.Context, _, tableID string) (map[string]string, error) {
+			if tableID == "users" {
+				return map[string]string{"email": `'***'`}, nil
+			}
+			return nil, nil
+		},
+		GetTableColumnNamesFn: func(context.Context, string) ([]string, error) {
+			return []string{"id", "email", "region"}, nil
+		},
+	}
+	eng := engine.NewSecureEngine(db, authz, nil, nil, slog.New(slog.DiscardHandler))
+
+	trace := &domain.PolicyTrace{}
+	rows, err := eng.Query(domain.WithPolicyTrace(context.Background(), trace), "analyst", "SELECT email FROM users")
+	require.NoError(t, err)
+	var emails []string
+	for rows.Next() {
+		var email string
+		require.NoError(t, rows.Scan(&email))
+		emails = append(emails, email)
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+
+	assert.Equal(t, []string{"***"}, emails)
+	assert.Equal(t, []domain.TablePolicy{
+		{Table: "users", MaskedColumns: []string{"email"}, RowFiltered: true},
+	}, trace.Tables())
+
+	t.Run("unaltered tables are reported without policies", func(t *testing.T) {
+		trace := &domain.PolicyTrace{}
+		rows, err := eng.Query(domain.WithPolicyTrace(context.Background(), trace), "analyst", "SELECT id FROM orders")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+		assert.Equal(t, []domain.TablePolicy{{Table: "orders"}}, trace.Tables())
+	})
+}
@@ -0,0 +1,105 @@
+package engine_test
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/engine"
+	"duck-demo/internal/testutil"
+)
+
+// queryDefaultsByName serves query defaults from a map keyed by principal name.
+type queryDefaultsByName map[string]domain.PrincipalQueryDefaults
+
+func (m queryDefaultsByName) Get(context.Context, string) (domain.PrincipalQueryDefaults, error) {
+	panic("unexpected call to Get")
+}
+
+func (m queryDefaultsByName) GetByPrincipalName(_ context.Context, name string) (domain.PrincipalQueryDefaults, error) {
+	return m[name], nil
+}
+
+func (m queryDefaultsByName) Set(context.Context, string, domain.PrincipalQueryDefaults, *int64) (int64, error) {
+	panic("unexpected call to Set")
+}
+
+// queryScalar runs a single-value query through the engine.
+func queryScalar(t *testing.T, eng *engine.SecureEngine, principal, query string) string {
+	t.Helper()
+	rows, err := eng.Query(context.Background(), principal, query)
+	require.NoError(t, err)
+	defer rows.Close() //nolint:errcheck
+	require.True(t, rows.Next(), "no rows for %q", query)
+	var v string
+	require.NoError(t, rows.Scan(&v))
+	require.NoError(t, rows.Err())
+	return v
+}
+
+func TestQueryDefaultsResolveUnqualifiedTables(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	for _, stmt := range []string{
+		"CREATE TABLE orders AS SELECT 'main' AS src",
+		"ATTACH ':memory:' AS demo",
+		"CREATE SCHEMA demo.analytics",
+		"CREATE TABLE demo.analytics.orders AS SELECT 'analytics' AS src",
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	var lookups []string
+	authz := &testutil.MockAuthService{
+		LookupTableIDFn: func(_ context.Context, name string) (string, string, bool, error) {
+			lookups = append(lookups, name)
+			return name, "s-1", false, nil
+		},
+		CheckPrivilegeFn: func(context.Context, string, string, string, string) (bool, error) {
+			return true, nil
+		},
+		GetEffectiveRowFiltersFn: func(context.Context, string, string) ([]string, error) {
+			return nil, nil
+		},
+		GetEffectiveColumnMasksFn: func(context.Context, string, string) (map[string]string, error) {
+			return nil, nil
+		},
+	}
+	eng := engine.NewSecureEngine(db, authz, nil, nil, slog.New(slog.DiscardHandler))
+	eng.SetQueryDefaults(queryDefaultsByName{
+		"alice": {DefaultCatalog: "demo", DefaultSchema: "analytics", Timezone: "America/New_York"},
+	})
+
+	t.Run("principal search path", func(t *testing.T) {
+		lookups = nil
+		assert.Equal(t, "analytics", queryScalar(t, eng, "alice", "SELECT src FROM orders"))
+		assert.Equal(t, []string{"demo.analytics.orders"}, lookups)
+	})
+
+	t.Run("schema-qualified names use the default catalog", func(t *testing.T) {
+		lookups = nil
+		assert.Equal(t, "analytics", queryScalar(t, eng, "alice", "SELECT src FROM analytics.orders"))
+		assert.Equal(t, []string{"demo.analytics.orders"}, lookups)
+	})
+
+	t.Run("principal time zone", func(t *testing.T) {
+		assert.Equal(t, "America/New_York", queryScalar(t, eng, "alice", "SELECT current_setting('TimeZone')"))
+	})
+
+	t.Run("principal without defaults", func(t *testing.T) {
+		lookups = nil
+		assert.Equal(t, "main", queryScalar(t, eng, "bob", "SELECT src FROM orders"))
+		assert.Equal(t, []string{"orders"}, lookups)
+		// Connections used for alice are not returned to the pool with her settings.
+		assert.NotEqual(t, "America/New_York", queryScalar(t, eng, "bob", "SELECT current_setting('TimeZone')"))
+	})
+}
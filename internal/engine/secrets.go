@@ -12,6 +12,7 @@ import (
 // DuckDBSecretManager wraps a DuckDB connection to manage secrets and catalog attachment.
 type DuckDBSecretManager struct {
 	db             *sql.DB
+	extensions     *ExtensionAllowlist // nil means DefaultExtensionAllowlist
 	postgresMu     sync.Mutex
 	postgresLoaded bool
 }
@@ -21,6 +22,12 @@ func NewDuckDBSecretManager(db *sql.DB) *DuckDBSecretManager {
 	return &DuckDBSecretManager{db: db}
 }
 
+// SetExtensionAllowlist restricts the extensions loaded on demand for
+// secrets and catalog attachment.
+func (m *DuckDBSecretManager) SetExtensionAllowlist(allow *ExtensionAllowlist) {
+	m.extensions = allow
+}
+
 // Compile-time interface checks.
 var _ domain.SecretManager = (*DuckDBSecretManager)(nil)
 var _ domain.CatalogAttacher = (*DuckDBSecretManager)(nil)
@@ -32,6 +39,9 @@ func (m *DuckDBSecretManager) CreateS3Secret(ctx context.Context, name, keyID, s
 
 // CreateAzureSecret creates an Azure-type secret in DuckDB with the given credentials.
 func (m *DuckDBSecretManager) CreateAzureSecret(ctx context.Context, name, accountName, accountKey, connectionString string) error {
+	if err := LoadExtension(ctx, m.db, m.extensions, "azure"); err != nil {
+		return fmt.Errorf("azure extension: %w", err)
+	}
 	return CreateAzureSecret(ctx, m.db, name, accountName, accountKey, connectionString)
 }
 
@@ -47,8 +57,14 @@ func (m *DuckDBSecretManager) DropSecret(ctx context.Context, name string) error
 
 // Attach inspects reg.MetastoreType and dispatches to the right DDL.
 func (m *DuckDBSecretManager) Attach(ctx context.Context, reg domain.CatalogRegistration) error {
+	if err := m.extensions.Check("ducklake"); err != nil {
+		return err
+	}
 	switch reg.MetastoreType {
 	case domain.MetastoreTypeSQLite:
+		if err := m.extensions.Check("sqlite"); err != nil {
+			return err
+		}
 		return AttachDuckLake(ctx, m.db, reg.Name, reg.DSN, reg.DataPath)
 	case domain.MetastoreTypePostgres:
 		// Install postgres extension if not yet loaded. Uses a mutex + bool
@@ -72,7 +88,7 @@ func (m *DuckDBSecretManager) ensurePostgresExtension(ctx context.Context) error
 	if m.postgresLoaded {
 		return nil
 	}
-	if err := InstallPostgresExtension(ctx, m.db); err != nil {
+	if err := LoadExtension(ctx, m.db, m.extensions, "postgres"); err != nil {
 		return err
 	}
 	m.postgresLoaded = true
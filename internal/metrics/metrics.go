@@ -0,0 +1,163 @@
+// Package metrics exposes Prometheus metrics for the HTTP server, query
+// execution, notebook sessions, metastore connection pools, and auth.
+//
+// Label cardinality is bounded on purpose: routes are recorded by their chi
+// pattern (e.g. /v1/catalogs/{catalogName}), never the raw path, request
+// methods outside the standard set are recorded as OTHER, and no metric is
+// labelled by principal.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "duck"
+
+// unmatchedRoute is the route label for requests that matched no route, so
+// scanners probing random paths cannot blow up the label set.
+const unmatchedRoute = "unmatched"
+
+// otherMethod is the method label for non-standard request methods, which
+// clients can choose freely.
+const otherMethod = "OTHER"
+
+// standardMethods are the request methods recorded under their own label.
+var standardMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// Metrics owns a private Prometheus registry and the collectors recorded by
+// the server. The zero value is not usable; create one with New.
+type Metrics struct {
+	registry        *prometheus.Registry
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+	authFailures    *prometheus.CounterVec
+}
+
+// New creates a Metrics instance with the Go runtime and process collectors
+// registered alongside the application collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests processed, by method, route pattern, and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency, by method, route pattern, and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "query",
+			Name:      "duration_seconds",
+			Help:      "SQL query execution time, by outcome (ok or error).",
+			Buckets:   []float64{.005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+		}, []string{"status"}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "failures_total",
+			Help:      "Rejected authentication attempts, by reason.",
+		}, []string{"reason"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestsTotal,
+		m.requestDuration,
+		m.queryDuration,
+		m.authFailures,
+	)
+	return m
+}
+
+// Handler returns the HTTP handler that serves the metrics in the Prometheus
+// text exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware records request counts and latencies. It must be installed on
+// the root router so the matched route pattern is available once the request
+// has been served.
+func (m *Metrics) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if p := rctx.RoutePattern(); p != "" {
+					route = p
+				}
+			}
+			method := r.Method
+			if !standardMethods[method] {
+				method = otherMethod
+			}
+			labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+			m.requestsTotal.With(labels).Inc()
+			m.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// ObserveQuery records the execution time of a SQL query. status should be
+// "ok" or "error".
+func (m *Metrics) ObserveQuery(status string, d time.Duration) {
+	m.queryDuration.WithLabelValues(status).Observe(d.Seconds())
+}
+
+// IncAuthFailure counts a rejected authentication attempt. reason must come
+// from a small fixed set (e.g. "missing_credentials", "invalid_credentials").
+func (m *Metrics) IncAuthFailure(reason string) {
+	m.authFailures.WithLabelValues(reason).Inc()
+}
+
+// RegisterActiveSessions exposes the number of open notebook sessions as a
+// gauge sampled from count at scrape time.
+func (m *Metrics) RegisterActiveSessions(count func() int) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "notebook",
+		Name:      "active_sessions",
+		Help:      "Notebook sessions currently holding a pinned DuckDB connection.",
+	}, func() float64 { return float64(count()) }))
+}
+
+// RegisterDBPool exposes database/sql pool statistics (open, in-use, idle
+// connections and wait counts) for db under the given pool name.
+func (m *Metrics) RegisterDBPool(name string, db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
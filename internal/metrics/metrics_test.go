@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics_RecordsRequestsAfterTraffic(t *testing.T) {
+	m := New()
+
+	r := chi.NewRouter()
+	r.Use(m.Middleware())
+	r.Get("/v1/catalogs/{catalogName}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r.Get("/v1/missing", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Method(http.MethodGet, "/metrics", m.Handler())
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	for _, path := range []string{"/v1/catalogs/main", "/v1/catalogs/other", "/v1/missing", "/no/such/route"} {
+		resp, err := http.Get(srv.URL + path) //nolint:noctx // test helper
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	resp, err := http.Get(srv.URL + "/metrics") //nolint:noctx // test helper
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	out := string(body)
+
+	// Both catalog requests collapse onto the route pattern.
+	assert.Contains(t, out, `duck_http_requests_total{method="GET",route="/v1/catalogs/{catalogName}",status="200"} 2`)
+	assert.Contains(t, out, `duck_http_requests_total{method="GET",route="/v1/missing",status="404"} 1`)
+	assert.Contains(t, out, `duck_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, out, `duck_http_request_duration_seconds_count{method="GET",route="/v1/catalogs/{catalogName}",status="200"} 2`)
+	assert.NotContains(t, out, "/v1/catalogs/main")
+}
+
+func TestMetrics_NonStandardMethodsShareOneLabel(t *testing.T) {
+	m := New()
+
+	r := chi.NewRouter()
+	r.Use(m.Middleware())
+	r.Get("/v1/catalogs", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, method := range []string{http.MethodGet, "PROPFIND", "FOO", "BAR123"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, "/v1/catalogs", nil))
+	}
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `duck_http_requests_total{method="GET",route="/v1/catalogs",status="200"} 1`)
+	assert.Contains(t, out, `duck_http_requests_total{method="OTHER",route="unmatched",status="405"} 3`)
+	for _, method := range []string{"PROPFIND", "FOO", "BAR123"} {
+		assert.NotContains(t, out, `method="`+method+`"`)
+	}
+}
+
+func TestMetrics_QueryAuthSessionsAndPool(t *testing.T) {
+	m := New()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, db.Ping())
+
+	m.RegisterDBPool("metastore_write", db)
+	m.RegisterActiveSessions(func() int { return 3 })
+	m.ObserveQuery("ok", 20*time.Millisecond)
+	m.ObserveQuery("error", time.Millisecond)
+	m.IncAuthFailure("invalid_credentials")
+
+	out := scrape(t, m)
+
+	assert.Contains(t, out, `duck_query_duration_seconds_count{status="ok"} 1`)
+	assert.Contains(t, out, `duck_query_duration_seconds_count{status="error"} 1`)
+	assert.Contains(t, out, `duck_auth_failures_total{reason="invalid_credentials"} 1`)
+	assert.Contains(t, out, `duck_notebook_active_sessions 3`)
+	assert.Contains(t, out, `go_sql_open_connections{db_name="metastore_write"} 1`)
+}
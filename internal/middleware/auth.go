@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"duck-demo/internal/config"
@@ -29,6 +30,11 @@ type PrincipalLookup interface {
 	GetByName(ctx context.Context, name string) (*domain.Principal, error)
 }
 
+// AuthFailureRecorder counts rejected authentication attempts by reason.
+type AuthFailureRecorder interface {
+	IncAuthFailure(reason string)
+}
+
 // Authenticator handles JWT and API key authentication.
 type Authenticator struct {
 	jwtValidator  JWTValidator
@@ -37,6 +43,7 @@ type Authenticator struct {
 	provisioner   PrincipalProvisioner
 	cfg           config.AuthConfig
 	logger        *slog.Logger
+	failures      AuthFailureRecorder
 }
 
 // NewAuthenticator creates a new Authenticator with the given dependencies.
@@ -61,6 +68,11 @@ func NewAuthenticator(
 	}
 }
 
+// SetFailureRecorder configures counting of rejected authentication attempts.
+func (a *Authenticator) SetFailureRecorder(r AuthFailureRecorder) {
+	a.failures = r
+}
+
 // Middleware returns an HTTP middleware that authenticates requests.
 func (a *Authenticator) Middleware() func(http.Handler) http.Handler {
 	return a.MiddlewareWithUnauthorized(writeUnauthorized)
@@ -75,8 +87,11 @@ func (a *Authenticator) MiddlewareWithUnauthorized(unauthorized func(http.Respon
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
 
+			presented := false
+
 			// Try JWT Bearer token first.
 			if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+				presented = true
 				tokenStr := strings.TrimPrefix(auth, "Bearer ")
 				if principal, err := a.authenticateJWT(ctx, tokenStr); err == nil {
 					ctx = domain.WithPrincipal(ctx, *principal)
@@ -88,6 +103,7 @@ func (a *Authenticator) MiddlewareWithUnauthorized(unauthorized func(http.Respon
 			// Try API Key.
 			if a.cfg.APIKeyEnabled {
 				if apiKey := r.Header.Get(a.cfg.APIKeyHeader); apiKey != "" && a.apiKeyLookup != nil {
+					presented = true
 					if principal, err := a.authenticateAPIKey(ctx, apiKey); err == nil {
 						ctx = domain.WithPrincipal(ctx, *principal)
 						next.ServeHTTP(w, r.WithContext(ctx))
@@ -97,6 +113,13 @@ func (a *Authenticator) MiddlewareWithUnauthorized(unauthorized func(http.Respon
 			}
 
 			// Both methods failed.
+			if a.failures != nil {
+				if presented {
+					a.failures.IncAuthFailure("invalid_credentials")
+				} else {
+					a.failures.IncAuthFailure("missing_credentials")
+				}
+			}
 			unauthorized(w, r)
 		})
 	}
@@ -131,6 +154,7 @@ func (a *Authenticator) authenticateJWT(ctx context.Context, tokenStr string) (*
 			ExternalID:  claims.Subject,
 			DisplayName: displayName,
 			IsBootstrap: isBootstrap,
+			Attributes:  a.claimAttributes(claims),
 		})
 		if err != nil {
 			a.logger.Error("JIT provisioning failed", "error", err, "sub", claims.Subject)
@@ -212,6 +236,36 @@ func (a *Authenticator) resolveDisplayName(claims *JWTClaims) string {
 	return sanitizePrincipalName(claims.Subject)
 }
 
+// claimAttributes maps the configured JWT claims to principal attributes.
+// String, number and boolean claims are kept; missing, empty, structured or
+// oversized values are skipped.
+func (a *Authenticator) claimAttributes(claims *JWTClaims) map[string]string {
+	if len(a.cfg.AttributeClaims) == 0 || claims.Raw == nil {
+		return nil
+	}
+	attrs := make(map[string]string, len(a.cfg.AttributeClaims))
+	for key, claim := range a.cfg.AttributeClaims {
+		var val string
+		switch v := claims.Raw[claim].(type) {
+		case string:
+			val = v
+		case float64:
+			val = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			val = strconv.FormatBool(v)
+		case json.Number:
+			val = v.String()
+		default:
+			continue
+		}
+		if val == "" || len(val) > domain.MaxPrincipalAttributeValueLen {
+			continue
+		}
+		attrs[key] = val
+	}
+	return attrs
+}
+
 // sanitizePrincipalName normalizes a principal name.
 func sanitizePrincipalName(name string) string {
 	name = strings.TrimSpace(name)
@@ -226,8 +280,9 @@ func writeUnauthorized(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusUnauthorized)
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"code":    401,
-		"message": "unauthorized: provide a valid JWT Bearer token or API key",
+		"code":       401,
+		"error_code": domain.ErrorCodeUnauthenticated,
+		"message":    "unauthorized: provide a valid JWT Bearer token or API key",
 	})
 }
 
@@ -60,10 +60,12 @@ type stubProvisioner struct {
 	result *domain.Principal
 	err    error
 	called bool
+	req    domain.ResolveOrProvisionRequest
 }
 
-func (s *stubProvisioner) ResolveOrProvision(_ context.Context, _ domain.ResolveOrProvisionRequest) (*domain.Principal, error) {
+func (s *stubProvisioner) ResolveOrProvision(_ context.Context, req domain.ResolveOrProvisionRequest) (*domain.Principal, error) {
 	s.called = true
+	s.req = req
 	return s.result, s.err
 }
 
@@ -224,6 +226,36 @@ func TestAuth_NoCredentials(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+type recordingFailures struct {
+	reasons []string
+}
+
+func (r *recordingFailures) IncAuthFailure(reason string) {
+	r.reasons = append(r.reasons, reason)
+}
+
+func TestAuth_FailureRecorder(t *testing.T) {
+	failures := &recordingFailures{}
+	auth := NewAuthenticator(
+		nil,
+		&stubAPIKeyLookup{keys: map[string]string{}},
+		nil, nil,
+		config.AuthConfig{APIKeyEnabled: true, APIKeyHeader: "X-API-Key"},
+		nil,
+	)
+	auth.SetFailureRecorder(failures)
+	mw := auth.Middleware()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fatal("handler should not be called")
+	}))
+
+	mw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "unknown-key")
+	mw.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []string{"missing_credentials", "invalid_credentials"}, failures.reasons)
+}
+
 func TestAuth_BearerPrecedence(t *testing.T) {
 	handler, getPrincipal := nextHandler()
 	rawKey := "test-api-key-12345678"
@@ -290,6 +322,57 @@ func TestAuth_JITProvisionNewUser(t *testing.T) {
 	assert.Equal(t, "new-user@example.com", cp.Name)
 }
 
+func TestAuth_JITProvisionAttributesFromClaims(t *testing.T) {
+	handler, _ := nextHandler()
+
+	prov := &stubProvisioner{
+		result: &domain.Principal{Name: "new-user@example.com", Type: "user"},
+	}
+	auth := NewAuthenticator(
+		&stubValidator{claims: &JWTClaims{
+			Subject: "ext-id-123",
+			Issuer:  "https://issuer.example.com",
+			Email:   strPtr("new-user@example.com"),
+			Raw: map[string]interface{}{
+				"sub":         "ext-id-123",
+				"email":       "new-user@example.com",
+				"custom:area": "EU",
+				"level":       float64(3),
+				"contractor":  false,
+				"groups":      []interface{}{"a", "b"},
+			},
+		}},
+		nil, nil,
+		prov,
+		config.AuthConfig{
+			NameClaim: "email",
+			AttributeClaims: map[string]string{
+				"region":     "custom:area",
+				"level":      "level",
+				"contractor": "contractor",
+				"groups":     "groups",
+				"dept":       "department",
+			},
+		},
+		nil,
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer new-user-token")
+	w := httptest.NewRecorder()
+
+	auth.Middleware()(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.True(t, prov.called)
+	// List-valued and missing claims are skipped.
+	assert.Equal(t, map[string]string{
+		"region":     "EU",
+		"level":      "3",
+		"contractor": "false",
+	}, prov.req.Attributes)
+}
+
 func TestAuth_JITBootstrapAdmin(t *testing.T) {
 	handler, getPrincipal := nextHandler()
 
@@ -0,0 +1,242 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultCompressMinSize is the smallest response body compressed when no
+// threshold is configured; smaller bodies gain little and cost CPU.
+const DefaultCompressMinSize = 1024
+
+// CompressConfig holds configuration for the response compression middleware.
+type CompressConfig struct {
+	// MinSize is the smallest response body, in bytes, that is compressed.
+	MinSize int
+}
+
+// compressibleTypes are the media types worth compressing. Binary payloads,
+// such as streamed metastore backups, are sent as they are.
+var compressibleTypes = map[string]bool{
+	"application/json":         true,
+	"application/problem+json": true,
+	"application/x-ndjson":     true,
+	"application/yaml":         true,
+	"application/javascript":   true,
+	"text/plain":               true,
+	"text/csv":                 true,
+	"text/html":                true,
+	"text/css":                 true,
+	"text/javascript":          true,
+}
+
+var (
+	gzipWriters  = sync.Pool{New: func() any { w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression); return w }}
+	flateWriters = sync.Pool{New: func() any { w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression); return w }}
+)
+
+// Compress returns an HTTP middleware that gzip- or deflate-encodes response
+// bodies for clients that accept it, preferring gzip. Only text and JSON
+// responses of at least MinSize bytes are compressed; the body is buffered
+// until the threshold is reached, so small responses go out unchanged. A
+// handler that flushes before reaching the threshold gets its response sent
+// uncompressed, so streaming responses are never held back.
+func Compress(cfg CompressConfig) func(http.Handler) http.Handler {
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = DefaultCompressMinSize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Whether the body is encoded depends on Accept-Encoding, so
+			// caches must key on it even when this response goes out plain.
+			addVary(w.Header(), "Accept-Encoding")
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding, minSize: minSize, status: http.StatusOK}
+			defer cw.Close() //nolint:errcheck
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// addVary adds field to the Vary header unless it is already listed.
+func addVary(h http.Header, field string) {
+	for _, v := range h.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f == "*" || strings.EqualFold(f, field) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", field)
+}
+
+// negotiateEncoding returns "gzip" or "deflate" when the Accept-Encoding
+// header allows it, or "" when the response must not be encoded.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+		accepted[name] = q > 0
+	}
+	for _, enc := range []string{"gzip", "deflate"} {
+		if ok, listed := accepted[enc]; ok || (!listed && accepted["*"]) {
+			return enc
+		}
+	}
+	return ""
+}
+
+// compressResponseWriter buffers the start of a response until it knows
+// whether to compress it: once the body reaches the size threshold, or when
+// the handler finishes or flushes.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	wroteHeader bool // handler called WriteHeader
+	decided     bool // headers sent; buf drained
+	buf         []byte
+	encoder     io.WriteCloser // nil when the response is sent as is
+}
+
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader || w.decided {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	// Informational and bodiless responses cannot be compressed.
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		_ = w.start(false)
+	}
+}
+
+func (w *compressResponseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.minSize {
+			return len(p), nil
+		}
+		if err := w.start(w.compressible()); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends what has been written so far, without compression if the
+// threshold has not been reached yet.
+func (w *compressResponseWriter) Flush() {
+	if !w.decided {
+		_ = w.start(false)
+	}
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close sends a response still below the threshold and finishes the
+// compressed stream.
+func (w *compressResponseWriter) Close() error {
+	if !w.decided {
+		if w.buf == nil && !w.wroteHeader {
+			return nil
+		}
+		if err := w.start(false); err != nil {
+			return err
+		}
+	}
+	if w.encoder == nil {
+		return nil
+	}
+	err := w.encoder.Close()
+	switch enc := w.encoder.(type) {
+	case *gzip.Writer:
+		gzipWriters.Put(enc)
+	case *flate.Writer:
+		flateWriters.Put(enc)
+	}
+	w.encoder = nil
+	return err
+}
+
+// compressible reports whether the response may be encoded.
+func (w *compressResponseWriter) compressible() bool {
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType = http.DetectContentType(w.buf)
+		mediaType, _, _ = strings.Cut(mediaType, ";")
+	}
+	return compressibleTypes[mediaType]
+}
+
+// start sends the headers, compressed or not, followed by the buffered body.
+func (w *compressResponseWriter) start(compress bool) error {
+	w.decided = true
+	h := w.Header()
+	if compress {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		addVary(h, "Accept-Encoding")
+		switch w.encoding {
+		case "gzip":
+			gz := gzipWriters.Get().(*gzip.Writer)
+			gz.Reset(w.ResponseWriter)
+			w.encoder = gz
+		default:
+			fl := flateWriters.Get().(*flate.Writer)
+			fl.Reset(w.ResponseWriter)
+			w.encoder = fl
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.encoder != nil {
+		_, err := w.encoder.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeListBody returns a JSON list response well above the default threshold.
+func largeListBody(t *testing.T) []byte {
+	t.Helper()
+	items := make([]map[string]string, 200)
+	for i := range items {
+		items[i] = map[string]string{"name": fmt.Sprintf("table_%d", i), "schema_name": "analytics", "catalog_name": "demo"}
+	}
+	body, err := json.Marshal(map[string]any{"data": items})
+	require.NoError(t, err)
+	return body
+}
+
+func compressTestHandler(contentType string, body []byte) http.Handler {
+	return Compress(CompressConfig{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+}
+
+func serveCompress(h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/catalogs/demo/schemas/analytics/tables", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompress_LargeListResponse(t *testing.T) {
+	body := largeListBody(t)
+	handler := compressTestHandler("application/json", body)
+
+	t.Run("gzip when accepted", func(t *testing.T) {
+		rec := serveCompress(handler, "gzip, deflate, br")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+		assert.Less(t, rec.Body.Len(), len(body))
+
+		zr, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		got, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, body, got)
+	})
+
+	t.Run("deflate when gzip is refused", func(t *testing.T) {
+		rec := serveCompress(handler, "gzip;q=0, deflate")
+
+		assert.Equal(t, "deflate", rec.Header().Get("Content-Encoding"))
+		got, err := io.ReadAll(flate.NewReader(rec.Body))
+		require.NoError(t, err)
+		assert.Equal(t, body, got)
+	})
+
+	t.Run("plain without Accept-Encoding", func(t *testing.T) {
+		rec := serveCompress(handler, "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rec.Body.Bytes())
+	})
+
+	t.Run("plain for identity only", func(t *testing.T) {
+		rec := serveCompress(handler, "identity")
+
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rec.Body.Bytes())
+	})
+}
+
+func TestCompress_SkipsSmallAndBinaryResponses(t *testing.T) {
+	t.Run("below threshold", func(t *testing.T) {
+		body := []byte(`{"data":[]}`)
+		rec := serveCompress(compressTestHandler("application/json", body), "gzip")
+
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rec.Body.Bytes())
+	})
+
+	t.Run("binary content type", func(t *testing.T) {
+		body := make([]byte, 4*DefaultCompressMinSize)
+		rec := serveCompress(compressTestHandler("application/octet-stream", body), "gzip")
+
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rec.Body.Bytes())
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		handler := Compress(CompressConfig{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		rec := serveCompress(handler, "gzip")
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	})
+}
+
+func TestCompress_VaryOnUncompressedResponses(t *testing.T) {
+	large := largeListBody(t)
+	tests := []struct {
+		name           string
+		handler        http.Handler
+		acceptEncoding string
+	}{
+		{"no Accept-Encoding", compressTestHandler("application/json", large), ""},
+		{"identity only", compressTestHandler("application/json", large), "identity"},
+		{"below threshold", compressTestHandler("application/json", []byte(`{"data":[]}`)), "gzip"},
+		{"binary content type", compressTestHandler("application/octet-stream", make([]byte, 4*DefaultCompressMinSize)), "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCompress(tt.handler, tt.acceptEncoding)
+
+			assert.Empty(t, rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, []string{"Accept-Encoding"}, rec.Header().Values("Vary"))
+		})
+	}
+
+	t.Run("keeps Vary set upstream", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+			compressTestHandler("application/json", large).ServeHTTP(w, r)
+		})
+		rec := serveCompress(handler, "gzip")
+
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, []string{"Origin", "Accept-Encoding"}, rec.Header().Values("Vary"))
+	})
+}
+
+func TestCompress_FlushStreamsUncompressed(t *testing.T) {
+	handler := Compress(CompressConfig{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte("{\"row\":1}\n"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte("{\"row\":2}\n"))
+	}))
+	rec := serveCompress(handler, "gzip")
+
+	assert.True(t, rec.Flushed)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "{\"row\":1}\n{\"row\":2}\n", rec.Body.String())
+}
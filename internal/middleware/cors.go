@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/cors"
+
+	"duck-demo/internal/domain"
+)
+
+// CORSConfig holds configuration for the CORS middleware.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins browsers may call the API from. "*"
+	// allows any origin, and one "*" inside an origin matches any substring
+	// (https://*.example.com). Empty disables CORS: only same-origin pages
+	// can read responses.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods a cross-origin request may use.
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers a cross-origin request may
+	// send, such as Authorization or X-API-Key for authenticated calls.
+	AllowedHeaders []string
+	// AllowCredentials lets browsers send cookies and HTTP auth with
+	// cross-origin requests.
+	AllowCredentials bool
+}
+
+// CORS returns an HTTP middleware that answers CORS preflight requests and
+// sets CORS headers for the configured origins. Preflight requests from other
+// origins are rejected with 403 Forbidden. Without allowed origins the
+// middleware passes requests through unchanged.
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	handler := cors.Handler(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", IdempotentReplayedHeader},
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           300,
+	})
+
+	return func(next http.Handler) http.Handler {
+		withCORS := handler(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPreflight(r) && !originAllowed(cfg.AllowedOrigins, r.Header.Get("Origin")) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"code":       http.StatusForbidden,
+					"error_code": domain.ErrorCodeForbidden,
+					"message":    "origin not allowed by CORS policy",
+				})
+				return
+			}
+			withCORS.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isPreflight reports whether r is a CORS preflight request.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// originAllowed matches origin against the allowed origins the same way the
+// cors package does: case-insensitively, with "*" as a wildcard.
+func originAllowed(allowed []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == "*" || a == origin {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(a, "*"); ok &&
+			len(origin) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func corsTestHandler(cfg CORSConfig) http.Handler {
+	return CORS(cfg)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func preflight(origin, headers string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/v1/query", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return req
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	handler := corsTestHandler(CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.tools.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "X-API-Key"},
+		AllowCredentials: true,
+	})
+
+	t.Run("allowed origin", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, preflight("https://app.example.com", "Authorization, X-API-Key"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, http.MethodPost, rec.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization, X-Api-Key", rec.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("wildcard origin", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, preflight("https://bi.tools.example.com", ""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://bi.tools.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed origin is rejected", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, preflight("https://evil.example.org", "Authorization"))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestCORS_SimpleRequest(t *testing.T) {
+	handler := corsTestHandler(CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/catalogs", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "X-Request-Id")
+
+	// Requests from other origins still reach the handler but get no CORS
+	// headers, so the browser does not expose the response.
+	req.Header.Set("Origin", "https://evil.example.org")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_DisabledByDefault(t *testing.T) {
+	handler := corsTestHandler(CORSConfig{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflight("https://app.example.com", ""))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
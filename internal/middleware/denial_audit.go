@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"duck-demo/internal/domain"
+)
+
+// maxDenialBodyBytes bounds how much of a 403 body is kept to extract the
+// denial reason.
+const maxDenialBodyBytes = 4096
+
+// DenialAudit returns an HTTP middleware that reports every 403 response to
+// recorder as a denied authorization attempt. The attempted action is the
+// request method, the securable is the request path and the reason is the
+// message of the error body.
+//
+// The middleware must run after authentication so the denied principal is
+// known.
+func DenialAudit(recorder domain.DenialRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dw := &denialResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(dw, r)
+			if dw.status != http.StatusForbidden {
+				return
+			}
+
+			principal := "anonymous"
+			if p, ok := domain.PrincipalFromContext(r.Context()); ok && p.Name != "" {
+				principal = p.Name
+			}
+			var body struct {
+				Message string `json:"message"`
+			}
+			_ = json.Unmarshal(dw.body.Bytes(), &body)
+			recorder.RecordDenial(r.Context(), domain.AccessDenial{
+				PrincipalName: principal,
+				Action:        r.Method,
+				Securable:     r.URL.Path,
+				Reason:        body.Message,
+			})
+		})
+	}
+}
+
+// denialResponseWriter passes a response through while keeping its status
+// and, for 403 responses, the start of its body.
+type denialResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *denialResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *denialResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.status == http.StatusForbidden && w.body.Len() < maxDenialBodyBytes {
+		w.body.Write(p[:min(len(p), maxDenialBodyBytes-w.body.Len())])
+	}
+	return w.ResponseWriter.Write(p)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+type recordedDenials struct {
+	mu      sync.Mutex
+	denials []domain.AccessDenial
+}
+
+func (r *recordedDenials) RecordDenial(_ context.Context, d domain.AccessDenial) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.denials = append(r.denials, d)
+}
+
+func TestDenialAudit(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/principals" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"code":403,"message":"admin privileges required"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	rec := &recordedDenials{}
+	srv := DenialAudit(rec)(handler)
+
+	ctx := domain.WithPrincipal(context.Background(), domain.ContextPrincipal{Name: "mallory"})
+
+	t.Run("403 is recorded", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/principals", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.JSONEq(t, `{"code":403,"message":"admin privileges required"}`, w.Body.String())
+		require.Len(t, rec.denials, 1)
+		assert.Equal(t, domain.AccessDenial{
+			PrincipalName: "mallory",
+			Action:        http.MethodPost,
+			Securable:     "/v1/principals",
+			Reason:        "admin privileges required",
+		}, rec.denials[0])
+	})
+
+	t.Run("other statuses are not recorded", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/catalogs", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, rec.denials, 1)
+	})
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"duck-demo/internal/domain"
+)
+
+// maxErrorBodySize bounds the error responses buffered to add an error code;
+// larger ones are passed through as they are.
+const maxErrorBodySize = 64 << 10
+
+// ErrorCodes returns an HTTP middleware that adds the catalog error_code to
+// JSON error responses that do not carry one, derived from the status, so
+// every error a client sees has a stable code to branch on. Handlers set
+// error_code themselves only where the status alone is ambiguous, such as
+// RESOURCE_EXISTS for a 409.
+//
+// The middleware must be mounted inside Compress so it sees the uncompressed
+// body.
+func ErrorCodes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ew := &errorCodeResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(ew, r)
+		ew.finish()
+	})
+}
+
+// errorCodeResponseWriter buffers a JSON error response so its error code
+// can be added before it is sent. Other responses pass straight through.
+type errorCodeResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buffering   bool
+	body        bytes.Buffer
+}
+
+func (w *errorCodeResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	if status >= http.StatusBadRequest && isJSON(w.Header().Get("Content-Type")) {
+		w.buffering = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *errorCodeResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.buffering {
+		return w.ResponseWriter.Write(p)
+	}
+	if w.body.Len()+len(p) > maxErrorBodySize {
+		w.flushBuffered(w.body.Bytes())
+		w.body.Reset()
+		return w.ResponseWriter.Write(p)
+	}
+	return w.body.Write(p)
+}
+
+// Flush sends what has been written so far.
+func (w *errorCodeResponseWriter) Flush() {
+	if w.buffering {
+		w.flushBuffered(w.body.Bytes())
+		w.body.Reset()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *errorCodeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flushBuffered stops buffering and sends the status and body unchanged.
+func (w *errorCodeResponseWriter) flushBuffered(body []byte) {
+	w.buffering = false
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+// finish sends a buffered error response with its error code.
+func (w *errorCodeResponseWriter) finish() {
+	if !w.buffering {
+		return
+	}
+	body := w.body.Bytes()
+	if coded, ok := withErrorCode(body, w.status); ok {
+		body = coded
+		w.Header().Del("Content-Length")
+	}
+	w.flushBuffered(body)
+}
+
+// withErrorCode adds the error code for status to a JSON error object that
+// has none. ok is false when body is not a JSON object or already has one.
+func withErrorCode(body []byte, status int) ([]byte, bool) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil || obj == nil {
+		return nil, false
+	}
+	if code, ok := obj["error_code"]; ok && string(code) != `""` && string(code) != "null" {
+		return nil, false
+	}
+	code, err := json.Marshal(domain.ErrorCodeForStatus(status))
+	if err != nil {
+		return nil, false
+	}
+	obj["error_code"] = code
+	coded, err := marshalJSON(obj)
+	if err != nil {
+		return nil, false
+	}
+	return coded, true
+}
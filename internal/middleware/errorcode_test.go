@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveErrorCodes(status int, contentType, body string) *httptest.ResponseRecorder {
+	h := ErrorCodes(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/catalogs/main", nil))
+	return rec
+}
+
+func decodeErrorBody(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestErrorCodes_AddsCodeFromStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusBadRequest, "VALIDATION_FAILED"},
+		{http.StatusForbidden, "FORBIDDEN"},
+		{http.StatusNotFound, "NOT_FOUND"},
+		{http.StatusConflict, "CONFLICT"},
+		{http.StatusInternalServerError, "INTERNAL"},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			rec := serveErrorCodes(tt.status, "application/json", `{"code":1,"message":"boom"}`)
+
+			assert.Equal(t, tt.status, rec.Code)
+			body := decodeErrorBody(t, rec)
+			assert.Equal(t, tt.want, body["error_code"])
+			assert.Equal(t, "boom", body["message"])
+		})
+	}
+}
+
+func TestErrorCodes_KeepsHandlerCode(t *testing.T) {
+	rec := serveErrorCodes(http.StatusConflict, "application/json",
+		`{"code":409,"error_code":"RESOURCE_EXISTS","message":"schema \"sales\" already exists"}`)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "RESOURCE_EXISTS", decodeErrorBody(t, rec)["error_code"])
+}
+
+func TestErrorCodes_PassesThroughOtherResponses(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rec := serveErrorCodes(http.StatusOK, "application/json", `{"name":"main"}`)
+		assert.JSONEq(t, `{"name":"main"}`, rec.Body.String())
+	})
+	t.Run("non_json_error", func(t *testing.T) {
+		rec := serveErrorCodes(http.StatusBadRequest, "text/plain", "bad request\n")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "bad request\n", rec.Body.String())
+	})
+	t.Run("json_error_that_is_not_an_object", func(t *testing.T) {
+		rec := serveErrorCodes(http.StatusBadRequest, "application/json", `["a"]`)
+		assert.Equal(t, `["a"]`, rec.Body.String())
+	})
+}
@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+// FieldsParam is the query parameter selecting the fields of a GET response.
+const FieldsParam = "fields"
+
+// ResponseFields describes the fields a GET endpoint's response can be
+// projected to.
+type ResponseFields struct {
+	// Names lists the properties of the response object or, for a list
+	// response, of each item.
+	Names []string
+	// List is true when the response is a {"data": [...]} page whose items
+	// are projected, keeping the other top-level properties such as
+	// next_page_token.
+	List bool
+}
+
+// FieldLookup returns the response fields of the route matching method and
+// chi route pattern, or false when the route does not support projection.
+type FieldLookup func(method, pattern string) (ResponseFields, bool)
+
+// Fields returns an HTTP middleware that projects GET responses to the
+// comma-separated fields named by the fields query parameter, such as
+// ?fields=id,name, so clients needing only a few fields receive and parse
+// less. A request naming a field the endpoint's response does not have, or
+// sent to an endpoint whose response cannot be projected, is rejected with
+// 400 Bad Request before the handler runs. Error responses are sent as they
+// are.
+//
+// The middleware must be mounted inside the chi router that serves the
+// resources.
+func Fields(lookup FieldLookup) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := r.URL.Query().Get(FieldsParam)
+			if r.Method != http.MethodGet || raw == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			pattern := routePattern(r)
+			if pattern == "" {
+				// Unknown routes get the router's 404.
+				next.ServeHTTP(w, r)
+				return
+			}
+			spec, ok := lookup(http.MethodGet, pattern)
+			if !ok {
+				writeBadRequest(w, "fields is not supported by this endpoint")
+				return
+			}
+			selected, err := parseFields(raw, spec.Names)
+			if err != nil {
+				writeBadRequest(w, err.Error())
+				return
+			}
+
+			rec := &responseRecorder{header: w.Header(), status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			body := rec.body.Bytes()
+			if rec.status == http.StatusOK && isJSON(w.Header().Get("Content-Type")) {
+				if projected, err := projectFields(body, selected, spec.List); err == nil {
+					body = projected
+					w.Header().Del("Content-Length")
+				}
+			}
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(body)
+		})
+	}
+}
+
+// responseRecorder captures a response so it can be rewritten before it is
+// sent.
+type responseRecorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header { return r.header }
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(p)
+}
+
+// routePattern returns the chi route pattern that r will be routed to, such
+// as /v1/catalogs/{catalogName}, or "" when no route matches.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+	return rctx.Routes.Find(chi.NewRouteContext(), r.Method, path)
+}
+
+// parseFields splits the fields parameter and checks each name against the
+// fields the response has.
+func parseFields(raw string, valid []string) (map[string]bool, error) {
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(valid, name) {
+			return nil, fmt.Errorf("unknown field %q in fields; valid fields are %s", name, strings.Join(valid, ", "))
+		}
+		selected[name] = true
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("fields must name at least one field")
+	}
+	return selected, nil
+}
+
+// projectFields keeps only the selected properties of a JSON object, or of
+// each item in a list response's data array.
+func projectFields(body []byte, selected map[string]bool, list bool) ([]byte, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, err
+	}
+	if !list {
+		return marshalJSON(projectObject(obj, selected))
+	}
+	var items []map[string]json.RawMessage
+	if data, ok := obj["data"]; ok {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+	}
+	for i, item := range items {
+		items[i] = projectObject(item, selected)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	obj["data"] = data
+	return marshalJSON(obj)
+}
+
+func projectObject(obj map[string]json.RawMessage, selected map[string]bool) map[string]json.RawMessage {
+	for name := range obj {
+		if !selected[name] {
+			delete(obj, name)
+		}
+	}
+	return obj
+}
+
+// marshalJSON encodes v the way the API handlers do, with a trailing newline.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// FieldsFromSpec returns a FieldLookup answering from the 200 responses of
+// the GET operations in an OpenAPI spec. basePath is the prefix the spec's
+// paths are served under, such as /v1. Operations whose response is not a
+// JSON object with declared properties do not support projection.
+func FieldsFromSpec(spec *openapi3.T, basePath string) FieldLookup {
+	fields := make(map[string]ResponseFields)
+	if spec != nil && spec.Paths != nil {
+		for path, item := range spec.Paths.Map() {
+			if item.Get == nil || item.Get.Responses == nil {
+				continue
+			}
+			resp := item.Get.Responses.Status(http.StatusOK)
+			if resp == nil || resp.Value == nil {
+				continue
+			}
+			media := resp.Value.Content.Get("application/json")
+			if media == nil || media.Schema == nil {
+				continue
+			}
+			if rf, ok := responseFields(media.Schema.Value); ok {
+				fields[basePath+path] = rf
+			}
+		}
+	}
+	return func(method, pattern string) (ResponseFields, bool) {
+		if method != http.MethodGet {
+			return ResponseFields{}, false
+		}
+		rf, ok := fields[pattern]
+		return rf, ok
+	}
+}
+
+// responseFields returns the projectable fields of a response schema: the
+// item properties of a {"data": [...]} page, or the object's properties.
+func responseFields(schema *openapi3.Schema) (ResponseFields, bool) {
+	props := schemaProperties(schema)
+	if data, ok := props["data"]; ok && data.Value != nil && data.Value.Items != nil {
+		if names := propertyNames(schemaProperties(data.Value.Items.Value)); len(names) > 0 {
+			return ResponseFields{Names: names, List: true}, true
+		}
+	}
+	names := propertyNames(props)
+	return ResponseFields{Names: names}, len(names) > 0
+}
+
+// schemaProperties returns a schema's properties, including those of its
+// allOf components.
+func schemaProperties(schema *openapi3.Schema) openapi3.Schemas {
+	if schema == nil {
+		return nil
+	}
+	props := make(openapi3.Schemas, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		props[name] = prop
+	}
+	for _, sub := range schema.AllOf {
+		for name, prop := range schemaProperties(sub.Value) {
+			props[name] = prop
+		}
+	}
+	return props
+}
+
+func propertyNames(props openapi3.Schemas) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
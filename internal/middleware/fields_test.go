@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fieldsTestSpec = `
+openapi: "3.0.3"
+info: {title: test, version: "1"}
+paths:
+  /principals:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/PaginatedPrincipals'}
+  /principals/{principalId}:
+    get:
+      parameters:
+        - {name: principalId, in: path, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Principal'}
+components:
+  schemas:
+    Principal:
+      type: object
+      properties:
+        id: {type: string}
+        name: {type: string}
+        type: {type: string}
+        is_admin: {type: boolean}
+    PaginatedPrincipals:
+      type: object
+      properties:
+        data:
+          type: array
+          items: {$ref: '#/components/schemas/Principal'}
+        next_page_token: {type: string}
+`
+
+var fieldsTestPrincipal = map[string]any{"id": "p-1", "name": "alice", "type": "user", "is_admin": false}
+
+func fieldsTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(fieldsTestSpec))
+	require.NoError(t, err)
+
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	r := chi.NewRouter()
+	r.Route("/v1", func(r chi.Router) {
+		r.Use(Fields(FieldsFromSpec(spec, "/v1")))
+		r.Get("/principals", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, map[string]any{"data": []any{fieldsTestPrincipal, fieldsTestPrincipal}, "next_page_token": "abc"})
+		})
+		r.Get("/principals/{principalId}", func(w http.ResponseWriter, r *http.Request) {
+			if chi.URLParam(r, "principalId") != "p-1" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": 404, "message": "principal not found"})
+				return
+			}
+			writeJSON(w, fieldsTestPrincipal)
+		})
+		r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, map[string]string{"status": "ok"})
+		})
+	})
+	return r
+}
+
+func getFields(t *testing.T, h http.Handler, target string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+	return rec, body
+}
+
+func TestFields_ProjectsResponses(t *testing.T) {
+	h := fieldsTestRouter(t)
+
+	t.Run("object", func(t *testing.T) {
+		rec, body := getFields(t, h, "/v1/principals/p-1?fields=id,name")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, map[string]any{"id": "p-1", "name": "alice"}, body)
+	})
+
+	t.Run("list items keep the page token", func(t *testing.T) {
+		rec, body := getFields(t, h, "/v1/principals?fields=id,name")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "abc", body["next_page_token"])
+		require.Len(t, body["data"], 2)
+		for _, item := range body["data"].([]any) {
+			assert.Equal(t, map[string]any{"id": "p-1", "name": "alice"}, item)
+		}
+	})
+
+	t.Run("without fields the full response is sent", func(t *testing.T) {
+		_, body := getFields(t, h, "/v1/principals/p-1")
+		assert.Equal(t, fieldsTestPrincipal, body)
+	})
+
+	t.Run("errors are not projected", func(t *testing.T) {
+		rec, body := getFields(t, h, "/v1/principals/p-2?fields=id")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "principal not found", body["message"])
+	})
+}
+
+func TestFields_RejectsInvalidFields(t *testing.T) {
+	h := fieldsTestRouter(t)
+
+	tests := []struct {
+		name    string
+		target  string
+		wantMsg string
+	}{
+		{"unknown field", "/v1/principals?fields=id,email", `unknown field "email"`},
+		{"no field names", "/v1/principals/p-1?fields=,", "at least one field"},
+		{"undocumented endpoint", "/v1/health?fields=status", "not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, body := getFields(t, h, tt.target)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, body["message"], tt.wantMsg)
+		})
+	}
+}
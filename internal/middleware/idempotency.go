@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"duck-demo/internal/domain"
+)
+
+// IdempotencyKeyHeader is the request header carrying a client-chosen key
+// that makes a POST safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set to "true" on responses replayed for a
+// repeated Idempotency-Key.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength bounds the accepted Idempotency-Key length.
+const maxIdempotencyKeyLength = 255
+
+// Defaults for the IdempotencyConfig size limits.
+const (
+	defaultIdempotencyMaxRequestBytes  = 8 << 20
+	defaultIdempotencyMaxResponseBytes = 1 << 20
+	defaultIdempotencyMaxEntries       = 10000
+)
+
+// IdempotencyConfig holds configuration for the idempotency middleware.
+type IdempotencyConfig struct {
+	// TTL is how long a completed response is replayed for its key.
+	TTL time.Duration
+	// MaxRequestBytes caps the body of a keyed request, which is read in
+	// full to fingerprint it; larger bodies are rejected with 413.
+	// Default 8 MiB.
+	MaxRequestBytes int64
+	// MaxResponseBytes caps the response body kept for replay. Larger
+	// responses are passed through but not recorded, so a retry runs the
+	// request again. Default 1 MiB.
+	MaxResponseBytes int
+	// MaxEntries caps the number of recorded keys. When full, the completed
+	// entry closest to expiry is evicted. Default 10000.
+	MaxEntries int
+}
+
+// idempotencyEntry is the recorded outcome of a keyed request.
+type idempotencyEntry struct {
+	fingerprint [sha256.Size]byte
+	inFlight    bool
+	status      int
+	header      http.Header
+	body        []byte
+	expiresAt   time.Time
+}
+
+// Idempotency returns an HTTP middleware that makes POST requests carrying an
+// Idempotency-Key header safe to retry. The first request with a key runs
+// normally and its response is recorded; a repeat of the same request by the
+// same principal within cfg.TTL gets the recorded response instead of running
+// again. Reusing a key for a different request is rejected with 422, and a
+// repeat that arrives while the original is still running gets 409. Server
+// errors (5xx) are not recorded, so the request can be retried.
+//
+// Recorded responses live in process memory, so keys do not survive a server
+// restart. The middleware must run after authentication so keys are scoped
+// per principal.
+func Idempotency(cfg IdempotencyConfig) func(http.Handler) http.Handler {
+	if cfg.MaxRequestBytes <= 0 {
+		cfg.MaxRequestBytes = defaultIdempotencyMaxRequestBytes
+	}
+	if cfg.MaxResponseBytes <= 0 {
+		cfg.MaxResponseBytes = defaultIdempotencyMaxResponseBytes
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = defaultIdempotencyMaxEntries
+	}
+
+	var (
+		mu        sync.Mutex
+		entries   = make(map[string]*idempotencyEntry)
+		lastSweep time.Time
+	)
+
+	// sweep drops expired entries; it runs at most once per minute and must
+	// be called with mu held.
+	sweep := func(now time.Time) {
+		if now.Sub(lastSweep) < time.Minute {
+			return
+		}
+		lastSweep = now
+		for k, e := range entries {
+			if !e.inFlight && now.After(e.expiresAt) {
+				delete(entries, k)
+			}
+		}
+	}
+
+	// evict makes room for one more entry by dropping expired entries and,
+	// if that is not enough, the completed entry closest to expiry. In-flight
+	// entries are never evicted. Must be called with mu held.
+	evict := func(now time.Time) {
+		if len(entries) < cfg.MaxEntries {
+			return
+		}
+		lastSweep = time.Time{}
+		sweep(now)
+		for len(entries) >= cfg.MaxEntries {
+			var (
+				oldestKey string
+				oldest    *idempotencyEntry
+			)
+			for k, e := range entries {
+				if !e.inFlight && (oldest == nil || e.expiresAt.Before(oldest.expiresAt)) {
+					oldestKey, oldest = k, e
+				}
+			}
+			if oldest == nil {
+				return
+			}
+			delete(entries, oldestKey)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				writeIdempotencyError(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			var body []byte
+			if r.Body != nil {
+				var err error
+				body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, cfg.MaxRequestBytes))
+				if err != nil {
+					var tooLarge *http.MaxBytesError
+					if errors.As(err, &tooLarge) {
+						writeIdempotencyError(w, http.StatusRequestEntityTooLarge,
+							fmt.Sprintf("request body exceeds %d bytes allowed with an Idempotency-Key", cfg.MaxRequestBytes))
+						return
+					}
+					writeIdempotencyError(w, http.StatusBadRequest, "read request body: "+err.Error())
+					return
+				}
+				_ = r.Body.Close()
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			fingerprint := sha256.Sum256(append([]byte(r.URL.RequestURI()+"\x00"), body...))
+
+			principal, _ := domain.PrincipalFromContext(r.Context())
+			scope := principal.Name + "\x00" + key
+
+			now := time.Now()
+			mu.Lock()
+			sweep(now)
+			if e, ok := entries[scope]; ok && (e.inFlight || now.Before(e.expiresAt)) {
+				mu.Unlock()
+				switch {
+				case e.fingerprint != fingerprint:
+					writeIdempotencyError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				case e.inFlight:
+					writeIdempotencyError(w, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+				default:
+					replayIdempotentResponse(w, e)
+				}
+				return
+			}
+			evict(now)
+			entry := &idempotencyEntry{fingerprint: fingerprint, inFlight: true}
+			entries[scope] = entry
+			mu.Unlock()
+
+			rec := &recordingResponseWriter{ResponseWriter: w, status: http.StatusOK, limit: cfg.MaxResponseBytes}
+			completed := false
+			defer func() {
+				mu.Lock()
+				defer mu.Unlock()
+				if !completed || rec.status >= http.StatusInternalServerError || rec.overflow {
+					delete(entries, scope)
+					return
+				}
+				entry.inFlight = false
+				entry.status = rec.status
+				entry.header = rec.Header().Clone()
+				entry.body = rec.body.Bytes()
+				entry.expiresAt = time.Now().Add(cfg.TTL)
+			}()
+			next.ServeHTTP(rec, r)
+			completed = true
+		})
+	}
+}
+
+// replayIdempotentResponse writes a recorded response.
+func replayIdempotentResponse(w http.ResponseWriter, e *idempotencyEntry) {
+	for k, v := range e.header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(e.status)
+	_, _ = w.Write(e.body)
+}
+
+// recordingResponseWriter passes a response through while keeping a copy of
+// its status and body. The copy is dropped, and overflow set, once the body
+// grows past limit bytes.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	limit       int
+	overflow    bool
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	if !w.overflow {
+		if w.body.Len()+len(p) > w.limit {
+			w.overflow = true
+			w.body = bytes.Buffer{}
+		} else {
+			w.body.Write(p)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func writeIdempotencyError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":       status,
+		"error_code": domain.ErrorCodeForStatus(status),
+		"message":    message,
+	})
+}
@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// createHandler emulates a create endpoint: it assigns a new ID per name and
+// answers 409 when the name already exists.
+func createHandler(calls *int) http.Handler {
+	var (
+		mu    sync.Mutex
+		names = make(map[string]bool)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Name string `json:"name"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		if names[body.Name] {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = fmt.Fprintf(w, `{"code":409,"message":"%s already exists"}`, body.Name)
+			return
+		}
+		names[body.Name] = true
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"id":"id-%d","name":%q}`, *calls, body.Name)
+	})
+}
+
+func keyedPost(principal, key, path, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return req.WithContext(domain.WithPrincipal(req.Context(), domain.ContextPrincipal{Name: principal}))
+}
+
+func TestIdempotency_ReplayReturnsOriginalResource(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour})(createHandler(&calls))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, keyedPost("alice", "key-1", "/v1/principals", `{"name":"bob"}`))
+	require.Equal(t, http.StatusCreated, first.Code)
+
+	replay := httptest.NewRecorder()
+	handler.ServeHTTP(replay, keyedPost("alice", "key-1", "/v1/principals", `{"name":"bob"}`))
+	assert.Equal(t, http.StatusCreated, replay.Code, "replay must not surface a 409")
+	assert.JSONEq(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, "application/json", replay.Header().Get("Content-Type"))
+	assert.Equal(t, "true", replay.Header().Get(IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls, "the handler runs once per key")
+}
+
+func TestIdempotency_WithoutKeyRunsEveryTime(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour})(createHandler(&calls))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, keyedPost("alice", "", "/v1/principals", `{"name":"bob"}`))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, keyedPost("alice", "", "/v1/principals", `{"name":"bob"}`))
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusConflict, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_KeyReuseForDifferentRequestIsRejected(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour})(createHandler(&calls))
+
+	handler.ServeHTTP(httptest.NewRecorder(), keyedPost("alice", "key-1", "/v1/principals", `{"name":"bob"}`))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("alice", "key-1", "/v1/principals", `{"name":"carol"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("alice", "key-1", "/v1/groups", `{"name":"bob"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_KeysAreScopedPerPrincipal(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour})(createHandler(&calls))
+
+	handler.ServeHTTP(httptest.NewRecorder(), keyedPost("alice", "key-1", "/v1/principals", `{"name":"bob"}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("mallory", "key-1", "/v1/principals", `{"name":"bob"}`))
+
+	assert.Equal(t, http.StatusConflict, rec.Code, "another principal's key must not replay alice's response")
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ExpiredKeyRunsAgain(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Nanosecond})(createHandler(&calls))
+
+	handler.ServeHTTP(httptest.NewRecorder(), keyedPost("alice", "key-1", "/v1/principals", `{"name":"bob"}`))
+	time.Sleep(time.Millisecond)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("alice", "key-1", "/v1/principals", `{"name":"bob"}`))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ServerErrorsAreNotRecorded(t *testing.T) {
+	calls := 0
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, keyedPost("alice", "key-1", "/v1/principals", `{}`))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, keyedPost("alice", "key-1", "/v1/principals", `{}`))
+
+	assert.Equal(t, http.StatusServiceUnavailable, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ConcurrentRepeatGetsConflict(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), keyedPost("alice", "key-1", "/v1/principals", `{}`))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("alice", "key-1", "/v1/principals", `{}`))
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestIdempotency_IgnoresNonPostAndRejectsLongKeys(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour})(createHandler(&calls))
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/groups/g/members", strings.NewReader(`{"name":"x"}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), req.Clone(req.Context()))
+	assert.Equal(t, 2, calls)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("alice", strings.Repeat("k", 256), "/v1/principals", `{"name":"y"}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestIdempotency_RejectsOversizedRequestBody(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour, MaxRequestBytes: 16})(createHandler(&calls))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("alice", "key-1", "/v1/principals", `{"name":"a-very-long-name"}`))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotency_OversizedResponseIsNotRecorded(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour, MaxResponseBytes: 8})(createHandler(&calls))
+
+	handler.ServeHTTP(httptest.NewRecorder(), keyedPost("alice", "key-1", "/v1/principals", `{"name":"bob"}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("alice", "key-1", "/v1/principals", `{"name":"bob"}`))
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_EvictsOldestEntryWhenFull(t *testing.T) {
+	var calls int
+	handler := Idempotency(IdempotencyConfig{TTL: time.Hour, MaxEntries: 2})(createHandler(&calls))
+
+	for _, key := range []string{"key-1", "key-2", "key-3"} {
+		handler.ServeHTTP(httptest.NewRecorder(), keyedPost("alice", key, "/v1/principals", `{"name":"`+key+`"}`))
+	}
+	require.Equal(t, 3, calls)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, keyedPost("alice", "key-3", "/v1/principals", `{"name":"key-3"}`))
+	assert.Equal(t, "true", rec.Header().Get(IdempotentReplayedHeader))
+
+	handler.ServeHTTP(httptest.NewRecorder(), keyedPost("alice", "key-1", "/v1/principals", `{"name":"key-1"}`))
+	assert.Equal(t, 4, calls, "evicted key-1 runs again")
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"duck-demo/internal/domain"
+)
+
+// PageSize returns an HTTP middleware that rejects list requests asking for
+// an invalid page size with 400 Bad Request: a max_results that is not an
+// integer, is below 1, or exceeds domain.PageSizeCeiling. Sizes between the
+// configured maximum and the ceiling pass through and are clamped by the
+// handlers, so clients written against a larger maximum keep working.
+func PageSize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.URL.Query().Get("max_results"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				writeBadRequest(w, "max_results must be an integer, got "+strconv.Quote(v))
+				return
+			}
+			if err := domain.ValidatePageSize(n); err != nil {
+				writeBadRequest(w, err.Error())
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeBadRequest(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":       400,
+		"error_code": domain.ErrorCodeValidationFailed,
+		"message":    message,
+	})
+}
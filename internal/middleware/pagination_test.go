@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageSize(t *testing.T) {
+	handler := PageSize(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		query       string
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "unset", query: "", wantStatus: http.StatusOK},
+		{name: "within limits", query: "?max_results=50", wantStatus: http.StatusOK},
+		{name: "oversized is left for the handler to clamp", query: "?max_results=5000", wantStatus: http.StatusOK},
+		{name: "zero", query: "?max_results=0", wantStatus: http.StatusBadRequest,
+			wantMessage: "max_results must be at least 1, got 0"},
+		{name: "negative", query: "?max_results=-10", wantStatus: http.StatusBadRequest,
+			wantMessage: "max_results must be at least 1, got -10"},
+		{name: "absurd", query: "?max_results=99999999", wantStatus: http.StatusBadRequest,
+			wantMessage: "max_results must be at most 100000, got 99999999"},
+		{name: "not an integer", query: "?max_results=lots", wantStatus: http.StatusBadRequest,
+			wantMessage: `max_results must be an integer, got "lots"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/principals"+tt.query, nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantMessage == "" {
+				return
+			}
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+			assert.InDelta(t, float64(400), body["code"], 0.001)
+			assert.Contains(t, body["message"], tt.wantMessage)
+		})
+	}
+}
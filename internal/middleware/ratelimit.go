@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"duck-demo/internal/domain"
 )
 
 // RateLimitConfig holds configuration for the rate limiter middleware.
@@ -105,7 +107,8 @@ func writeTooManyRequests(w http.ResponseWriter, retryAfterSecs int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusTooManyRequests)
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"code":    429,
-		"message": "rate limit exceeded",
+		"code":       429,
+		"error_code": domain.ErrorCodeRateLimited,
+		"message":    "rate limit exceeded",
 	})
 }
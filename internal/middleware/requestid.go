@@ -6,6 +6,8 @@ import (
 	"regexp"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type requestIDKey struct{}
@@ -15,15 +17,22 @@ var validRequestID = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
 // RequestID returns an HTTP middleware that assigns a unique request ID to each
 // request. If the incoming request contains a valid X-Request-ID header, it
-// is reused; otherwise a new UUID is generated. The header is validated to
-// contain only alphanumeric characters, hyphens, and underscores (max 128 chars)
-// to prevent log-forging attacks.
+// is reused; otherwise the trace ID of the active span is used, falling back to
+// a new UUID when tracing is disabled. The header is validated to contain only
+// alphanumeric characters, hyphens, and underscores (max 128 chars) to prevent
+// log-forging attacks. The request ID is recorded on the active span so logs
+// and traces can be joined either way.
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := r.Header.Get("X-Request-ID")
 		if !isValidRequestID(id) {
-			id = uuid.NewString()
+			if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+				id = sc.TraceID().String()
+			} else {
+				id = uuid.NewString()
+			}
 		}
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request_id", id))
 		w.Header().Set("X-Request-ID", id)
 		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -0,0 +1,94 @@
+// Package seed populates a metastore with demo principals and groups for
+// training and testing.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"duck-demo/internal/domain"
+)
+
+// Result counts what a seeding run created. Resources that already existed
+// are not counted.
+type Result struct {
+	PrincipalsCreated int
+	GroupsCreated     int
+	MembershipsAdded  int
+}
+
+// demoGroup is a demo group and the demo users that belong to it.
+type demoGroup struct {
+	name        string
+	description string
+	members     []string
+}
+
+// demoUsers are the demo principals. None of them is an admin.
+var demoUsers = []string{"analyst1", "analyst2", "researcher1", "no_access_user"}
+
+var demoGroups = []demoGroup{
+	{name: "analysts", description: "Demo analysts", members: []string{"analyst1", "analyst2"}},
+	{name: "researchers", description: "Demo researchers", members: []string{"researcher1"}},
+}
+
+// Demo creates the demo users, groups and memberships. Whatever already
+// exists by name is kept as it is, so seeding again creates nothing.
+func Demo(ctx context.Context, principals domain.PrincipalRepository, groups domain.GroupRepository) (*Result, error) {
+	res := &Result{}
+	userIDs := make(map[string]string, len(demoUsers))
+	for _, name := range demoUsers {
+		p, err := principals.GetByName(ctx, name)
+		if errors.As(err, new(*domain.NotFoundError)) {
+			p, err = principals.Create(ctx, &domain.Principal{Name: name, Type: "user"})
+			if err == nil {
+				res.PrincipalsCreated++
+			}
+		}
+		if err != nil {
+			return res, fmt.Errorf("seed principal %q: %w", name, err)
+		}
+		userIDs[name] = p.ID
+	}
+
+	for _, dg := range demoGroups {
+		g, err := groups.GetByName(ctx, dg.name)
+		if errors.As(err, new(*domain.NotFoundError)) {
+			g, err = groups.Create(ctx, &domain.Group{Name: dg.name, Description: dg.description})
+			if err == nil {
+				res.GroupsCreated++
+			}
+		}
+		if err != nil {
+			return res, fmt.Errorf("seed group %q: %w", dg.name, err)
+		}
+		for _, member := range dg.members {
+			added, err := addMember(ctx, groups, g.ID, userIDs[member])
+			if err != nil {
+				return res, fmt.Errorf("seed membership of %q in %q: %w", member, dg.name, err)
+			}
+			if added {
+				res.MembershipsAdded++
+			}
+		}
+	}
+	return res, nil
+}
+
+// addMember adds a user to a group unless it is already a direct member.
+func addMember(ctx context.Context, groups domain.GroupRepository, groupID, userID string) (bool, error) {
+	current, err := groups.GetGroupsForMember(ctx, "user", userID)
+	if err != nil {
+		return false, err
+	}
+	for _, g := range current {
+		if g.ID == groupID {
+			return false, nil
+		}
+	}
+	if err := groups.AddMember(ctx, &domain.GroupMember{GroupID: groupID, MemberType: "user", MemberID: userID}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
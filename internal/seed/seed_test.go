@@ -0,0 +1,104 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// memPrincipals is an in-memory PrincipalRepository that, like the SQLite
+// one, refuses a second principal with the same name.
+type memPrincipals struct {
+	domain.PrincipalRepository
+	byName map[string]domain.Principal
+}
+
+func (m *memPrincipals) GetByName(_ context.Context, name string) (*domain.Principal, error) {
+	p, ok := m.byName[name]
+	if !ok {
+		return nil, domain.ErrNotFound("principal %q not found", name)
+	}
+	return &p, nil
+}
+
+func (m *memPrincipals) Create(_ context.Context, p *domain.Principal) (*domain.Principal, error) {
+	if _, ok := m.byName[p.Name]; ok {
+		return nil, domain.ErrAlreadyExists("principal %q already exists", p.Name)
+	}
+	c := *p
+	c.ID = fmt.Sprintf("principal-%d", len(m.byName)+1)
+	m.byName[c.Name] = c
+	return &c, nil
+}
+
+// memGroups is an in-memory GroupRepository.
+type memGroups struct {
+	domain.GroupRepository
+	byName  map[string]domain.Group
+	members map[string][]string // member ID -> group IDs
+}
+
+func (m *memGroups) GetByName(_ context.Context, name string) (*domain.Group, error) {
+	g, ok := m.byName[name]
+	if !ok {
+		return nil, domain.ErrNotFound("group %q not found", name)
+	}
+	return &g, nil
+}
+
+func (m *memGroups) Create(_ context.Context, g *domain.Group) (*domain.Group, error) {
+	if _, ok := m.byName[g.Name]; ok {
+		return nil, domain.ErrAlreadyExists("group %q already exists", g.Name)
+	}
+	c := *g
+	c.ID = fmt.Sprintf("group-%d", len(m.byName)+1)
+	m.byName[c.Name] = c
+	return &c, nil
+}
+
+func (m *memGroups) AddMember(_ context.Context, gm *domain.GroupMember) error {
+	m.members[gm.MemberID] = append(m.members[gm.MemberID], gm.GroupID)
+	return nil
+}
+
+func (m *memGroups) GetGroupsForMember(_ context.Context, _ string, memberID string) ([]domain.Group, error) {
+	var out []domain.Group
+	for _, id := range m.members[memberID] {
+		out = append(out, domain.Group{ID: id})
+	}
+	return out, nil
+}
+
+func TestDemo_IsIdempotent(t *testing.T) {
+	principals := &memPrincipals{byName: map[string]domain.Principal{}}
+	groups := &memGroups{byName: map[string]domain.Group{}, members: map[string][]string{}}
+
+	first, err := Demo(context.Background(), principals, groups)
+	require.NoError(t, err)
+	assert.Equal(t, &Result{PrincipalsCreated: 4, GroupsCreated: 2, MembershipsAdded: 3}, first)
+
+	second, err := Demo(context.Background(), principals, groups)
+	require.NoError(t, err)
+	assert.Equal(t, &Result{}, second, "seeding again creates nothing")
+	assert.Len(t, principals.byName, 4, "no duplicate principals")
+	assert.Len(t, groups.byName, 2)
+	assert.Len(t, groups.members[principals.byName["analyst1"].ID], 1, "no duplicate memberships")
+}
+
+func TestDemo_KeepsExistingPrincipals(t *testing.T) {
+	principals := &memPrincipals{byName: map[string]domain.Principal{
+		"analyst1": {ID: "existing", Name: "analyst1", Type: "user", IsAdmin: true},
+	}}
+	groups := &memGroups{byName: map[string]domain.Group{}, members: map[string][]string{}}
+
+	res, err := Demo(context.Background(), principals, groups)
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.PrincipalsCreated)
+	assert.True(t, principals.byName["analyst1"].IsAdmin, "an existing principal is left as it is")
+	assert.Equal(t, []string{"group-1"}, groups.members["existing"])
+}
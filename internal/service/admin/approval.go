@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/auditutil"
+)
+
+// ApprovalExecutor runs an approved operation with the parameters it was
+// requested with. ctx carries the approving admin.
+type ApprovalExecutor func(ctx context.Context, params map[string]string) error
+
+// ApprovalService implements dual control: configured operations are held as
+// pending requests until an admin other than the requester approves them.
+type ApprovalService struct {
+	repo      domain.ApprovalRepository
+	audit     domain.AuditRepository
+	required  map[string]bool
+	executors map[string]ApprovalExecutor
+}
+
+// NewApprovalService creates a new ApprovalService. No operation requires
+// approval until SetRequiredOperations is called.
+func NewApprovalService(repo domain.ApprovalRepository, audit domain.AuditRepository) *ApprovalService {
+	return &ApprovalService{
+		repo:      repo,
+		audit:     audit,
+		required:  map[string]bool{},
+		executors: map[string]ApprovalExecutor{},
+	}
+}
+
+// SetRequiredOperations configures which operations need a second admin's
+// approval. Unknown operation names are rejected.
+func (s *ApprovalService) SetRequiredOperations(ops []string) error {
+	required := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		if !slices.Contains(domain.ApprovalOperations, op) {
+			return fmt.Errorf("unknown approval operation %q (supported: %s)", op, strings.Join(domain.ApprovalOperations, ", "))
+		}
+		required[op] = true
+	}
+	s.required = required
+	return nil
+}
+
+// RegisterExecutor sets the function that runs op once it is approved.
+func (s *ApprovalService) RegisterExecutor(op string, exec ApprovalExecutor) {
+	s.executors[op] = exec
+}
+
+// RequiresApproval reports whether op is configured to need a second admin's
+// approval.
+func (s *ApprovalService) RequiresApproval(op string) bool {
+	return s.required[op]
+}
+
+// Submit holds op for approval when it is configured to require one and
+// returns the pending request. It returns nil when op may run immediately.
+// Requires admin privileges.
+func (s *ApprovalService) Submit(ctx context.Context, op string, params map[string]string) (*domain.ApprovalRequest, error) {
+	if !s.required[op] {
+		return nil, nil
+	}
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.repo.Create(ctx, &domain.ApprovalRequest{
+		Operation:   op,
+		Params:      params,
+		RequestedBy: caller,
+	})
+	if err != nil {
+		return nil, err
+	}
+	auditutil.LogAllowed(ctx, s.audit, caller, "REQUEST_APPROVAL", fmt.Sprintf("Requested approval %s for %s %s", req.ID, op, formatParams(params)))
+	return req, nil
+}
+
+// Get returns an approval request. Requires admin privileges.
+func (s *ApprovalService) Get(ctx context.Context, id string) (*domain.ApprovalRequest, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// List returns approval requests matching the filter. Requires admin privileges.
+func (s *ApprovalService) List(ctx context.Context, filter domain.ApprovalFilter) ([]domain.ApprovalRequest, int64, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// Approve approves a pending request and runs its operation as the approving
+// admin. The requester cannot approve their own request. When the operation
+// fails the request is marked FAILED and the error is returned.
+func (s *ApprovalService) Approve(ctx context.Context, id string) (*domain.ApprovalRequest, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.claimApproval(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+	exec, ok := s.executors[req.Operation]
+	if !ok {
+		err = fmt.Errorf("no executor registered for operation %q", req.Operation)
+	} else {
+		err = exec(ctx, req.Params)
+	}
+	if err != nil {
+		_ = s.repo.SetFailed(ctx, id, err.Error())
+		auditutil.LogDenied(ctx, s.audit, caller, "APPROVE", fmt.Sprintf("Approval %s for %s failed: %v", id, req.Operation, err))
+		return nil, err
+	}
+	auditutil.LogAllowed(ctx, s.audit, caller, "APPROVE", fmt.Sprintf("Approved %s requested by %s: %s %s", id, req.RequestedBy, req.Operation, formatParams(req.Params)))
+	return s.repo.GetByID(ctx, id)
+}
+
+// Reject rejects a pending request without running it. Requires admin
+// privileges; the requester may reject their own request to withdraw it.
+func (s *ApprovalService) Reject(ctx context.Context, id string) (*domain.ApprovalRequest, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.repo.Decide(ctx, id, domain.ApprovalStatusRejected, caller)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrConflict("approval request %q is not pending", id)
+	}
+	auditutil.LogAllowed(ctx, s.audit, caller, "REJECT", fmt.Sprintf("Rejected %s requested by %s: %s", id, req.RequestedBy, req.Operation))
+	return s.repo.GetByID(ctx, id)
+}
+
+// claimApproval checks that caller may approve the request and records the
+// approval before the operation runs, so it can run at most once.
+func (s *ApprovalService) claimApproval(ctx context.Context, caller, id string) (*domain.ApprovalRequest, error) {
+	req, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.RequestedBy == caller {
+		auditutil.LogDenied(ctx, s.audit, caller, "APPROVE", fmt.Sprintf("Self-approval of %s refused", id))
+		return nil, domain.ErrAccessDenied("approval request %q must be approved by an admin other than the requester", id)
+	}
+	ok, err := s.repo.Decide(ctx, id, domain.ApprovalStatusApproved, caller)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrConflict("approval request %q is not pending", id)
+	}
+	return req, nil
+}
+
+// formatParams renders params as sorted key=value pairs for audit details.
+func formatParams(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + params[k]
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,171 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// memApprovalRepo is an in-memory domain.ApprovalRepository.
+type memApprovalRepo struct {
+	requests map[string]*domain.ApprovalRequest
+	nextID   int
+}
+
+func newMemApprovalRepo() *memApprovalRepo {
+	return &memApprovalRepo{requests: map[string]*domain.ApprovalRequest{}}
+}
+
+func (m *memApprovalRepo) Create(_ context.Context, r *domain.ApprovalRequest) (*domain.ApprovalRequest, error) {
+	m.nextID++
+	c := *r
+	c.ID = fmt.Sprintf("req-%d", m.nextID)
+	c.Status = domain.ApprovalStatusPending
+	m.requests[c.ID] = &c
+	out := c
+	return &out, nil
+}
+
+func (m *memApprovalRepo) GetByID(_ context.Context, id string) (*domain.ApprovalRequest, error) {
+	r, ok := m.requests[id]
+	if !ok {
+		return nil, domain.ErrNotFound("approval request %q not found", id)
+	}
+	out := *r
+	return &out, nil
+}
+
+func (m *memApprovalRepo) List(_ context.Context, filter domain.ApprovalFilter) ([]domain.ApprovalRequest, int64, error) {
+	var out []domain.ApprovalRequest
+	for _, r := range m.requests {
+		if filter.Status == nil || *filter.Status == r.Status {
+			out = append(out, *r)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *memApprovalRepo) Decide(_ context.Context, id, status, decidedBy string) (bool, error) {
+	r, ok := m.requests[id]
+	if !ok || r.Status != domain.ApprovalStatusPending {
+		return false, nil
+	}
+	r.Status = status
+	r.DecidedBy = &decidedBy
+	return true, nil
+}
+
+func (m *memApprovalRepo) SetFailed(_ context.Context, id, message string) error {
+	m.requests[id].Status = domain.ApprovalStatusFailed
+	m.requests[id].ErrorMessage = &message
+	return nil
+}
+
+func namedAdminCtx(name string) context.Context {
+	return domain.WithPrincipal(context.Background(), domain.ContextPrincipal{Name: name, IsAdmin: true, Type: "user"})
+}
+
+// newSetAdminApprovals returns an ApprovalService that requires approval for
+// set_admin, and the admin flags its executor changes.
+func newSetAdminApprovals(t *testing.T) (*ApprovalService, map[string]bool) {
+	t.Helper()
+	admins := map[string]bool{}
+	svc := NewApprovalService(newMemApprovalRepo(), &recordingAudit{})
+	require.NoError(t, svc.SetRequiredOperations([]string{domain.ApprovalOpSetAdmin}))
+	svc.RegisterExecutor(domain.ApprovalOpSetAdmin, func(ctx context.Context, params map[string]string) error {
+		p, _ := domain.PrincipalFromContext(ctx)
+		if !p.IsAdmin {
+			return domain.ErrAccessDenied("admin privileges required")
+		}
+		admins[params["principal_id"]] = true
+		return nil
+	})
+	return svc, admins
+}
+
+func TestApprovalService_SetAdminPendingUntilSecondAdminApproves(t *testing.T) {
+	svc, admins := newSetAdminApprovals(t)
+
+	req, err := svc.Submit(namedAdminCtx("alice"), domain.ApprovalOpSetAdmin, map[string]string{"principal_id": "p-1"})
+	require.NoError(t, err)
+	require.NotNil(t, req, "set_admin requires approval")
+	assert.Equal(t, domain.ApprovalStatusPending, req.Status)
+	assert.Equal(t, "alice", req.RequestedBy)
+	assert.False(t, admins["p-1"], "nothing runs while the request is pending")
+
+	_, err = svc.Approve(namedAdminCtx("alice"), req.ID)
+	var denied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &denied, "the requester cannot approve their own request")
+	assert.False(t, admins["p-1"])
+
+	_, err = svc.Approve(userCtx(), req.ID)
+	require.ErrorAs(t, err, &denied, "non-admins cannot approve")
+
+	approved, err := svc.Approve(namedAdminCtx("bob"), req.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ApprovalStatusApproved, approved.Status)
+	require.NotNil(t, approved.DecidedBy)
+	assert.Equal(t, "bob", *approved.DecidedBy)
+	assert.True(t, admins["p-1"], "the operation applies once approved")
+
+	_, err = svc.Approve(namedAdminCtx("carol"), req.ID)
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict, "an approved request cannot be approved again")
+}
+
+func TestApprovalService_SubmitNotRequired(t *testing.T) {
+	svc, _ := newSetAdminApprovals(t)
+
+	req, err := svc.Submit(namedAdminCtx("alice"), domain.ApprovalOpDeleteCatalog, map[string]string{"catalog_name": "main"})
+	require.NoError(t, err)
+	assert.Nil(t, req, "operations not configured for approval run immediately")
+}
+
+func TestApprovalService_Reject(t *testing.T) {
+	svc, admins := newSetAdminApprovals(t)
+
+	req, err := svc.Submit(namedAdminCtx("alice"), domain.ApprovalOpSetAdmin, map[string]string{"principal_id": "p-1"})
+	require.NoError(t, err)
+
+	rejected, err := svc.Reject(namedAdminCtx("alice"), req.ID)
+	require.NoError(t, err, "the requester may withdraw their own request")
+	assert.Equal(t, domain.ApprovalStatusRejected, rejected.Status)
+
+	_, err = svc.Approve(namedAdminCtx("bob"), req.ID)
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.False(t, admins["p-1"])
+}
+
+func TestApprovalService_ApproveExecutorFailure(t *testing.T) {
+	svc := NewApprovalService(newMemApprovalRepo(), &recordingAudit{})
+	require.NoError(t, svc.SetRequiredOperations([]string{domain.ApprovalOpDeleteCatalog}))
+	svc.RegisterExecutor(domain.ApprovalOpDeleteCatalog, func(context.Context, map[string]string) error {
+		return errors.New("catalog is attached")
+	})
+
+	req, err := svc.Submit(namedAdminCtx("alice"), domain.ApprovalOpDeleteCatalog, map[string]string{"catalog_name": "main"})
+	require.NoError(t, err)
+
+	_, err = svc.Approve(namedAdminCtx("bob"), req.ID)
+	require.EqualError(t, err, "catalog is attached")
+
+	got, err := svc.Get(namedAdminCtx("bob"), req.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ApprovalStatusFailed, got.Status)
+	require.NotNil(t, got.ErrorMessage)
+	assert.Equal(t, "catalog is attached", *got.ErrorMessage)
+}
+
+func TestApprovalService_SetRequiredOperationsRejectsUnknown(t *testing.T) {
+	svc := NewApprovalService(newMemApprovalRepo(), &recordingAudit{})
+
+	err := svc.SetRequiredOperations([]string{"drop_everything"})
+	require.ErrorContains(t, err, `unknown approval operation "drop_everything"`)
+}
@@ -0,0 +1,166 @@
+package admin
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/auditutil"
+)
+
+// BackupService produces consistent metastore backups while the server
+// keeps running. It requires admin privileges.
+type BackupService struct {
+	snapshotter domain.MetastoreSnapshotter
+	migrations  domain.MigrationRunner
+	audit       domain.AuditRepository
+	tempDir     string
+}
+
+// NewBackupService creates a new BackupService. Backups are staged in the
+// system temporary directory until the caller closes them.
+func NewBackupService(snapshotter domain.MetastoreSnapshotter, migrations domain.MigrationRunner, audit domain.AuditRepository) *BackupService {
+	return &BackupService{snapshotter: snapshotter, migrations: migrations, audit: audit, tempDir: os.TempDir()}
+}
+
+// Create snapshots the metastore, optionally gzips it, and returns the
+// backup with its checksum and schema version. The caller must close
+// Backup.Content to remove the staged file.
+func (s *BackupService) Create(ctx context.Context, opts domain.BackupOptions) (*domain.Backup, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.schemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp(s.tempDir, "duck-backup-*")
+	if err != nil {
+		return nil, fmt.Errorf("create backup staging dir: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	snapshot := filepath.Join(dir, "metastore.sqlite")
+	if err := s.snapshotter.Snapshot(ctx, snapshot); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("snapshot metastore: %w", err)
+	}
+
+	final := snapshot
+	if opts.Gzip {
+		final = snapshot + ".gz"
+		if err := gzipFile(snapshot, final); err != nil {
+			cleanup()
+			return nil, err
+		}
+	}
+
+	sum, size, err := checksumFile(final)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	f, err := os.Open(final) //nolint:gosec // path is inside our own staging dir
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("open backup: %w", err)
+	}
+
+	s.logAudit(ctx, caller, "BACKUP_METASTORE",
+		fmt.Sprintf("Created metastore backup (%d bytes, schema version %d, sha256 %s)", size, version, sum))
+
+	return &domain.Backup{
+		Content:       &stagedFile{File: f, dir: dir},
+		SizeBytes:     size,
+		Checksum:      sum,
+		SchemaVersion: version,
+		Gzip:          opts.Gzip,
+		CreatedAt:     time.Now().UTC(),
+	}, nil
+}
+
+func (s *BackupService) logAudit(ctx context.Context, principal, action, detail string) {
+	auditutil.LogAllowed(ctx, s.audit, principal, action, detail)
+}
+
+// schemaVersion returns the highest applied migration version.
+func (s *BackupService) schemaVersion(ctx context.Context) (int64, error) {
+	return appliedSchemaVersion(ctx, s.migrations)
+}
+
+// appliedSchemaVersion returns the highest applied migration version, or 0
+// when none is applied.
+func appliedSchemaVersion(ctx context.Context, runner domain.MigrationRunner) (int64, error) {
+	statuses, err := runner.Status(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	var version int64
+	for _, st := range statuses {
+		if st.Applied && st.Version > version {
+			version = st.Version
+		}
+	}
+	return version, nil
+}
+
+// stagedFile removes its staging directory when closed.
+type stagedFile struct {
+	*os.File
+	dir string
+}
+
+func (f *stagedFile) Close() error {
+	err := f.File.Close()
+	if rmErr := os.RemoveAll(f.dir); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // path is inside our own staging dir
+	if err != nil {
+		return fmt.Errorf("open snapshot: %w", err)
+	}
+	defer in.Close() //nolint:errcheck
+
+	out, err := os.Create(dst) //nolint:gosec // path is inside our own staging dir
+	if err != nil {
+		return fmt.Errorf("create gzip backup: %w", err)
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("gzip backup: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("gzip backup: %w", err)
+	}
+	return out.Close()
+}
+
+func checksumFile(path string) (string, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // path is inside our own staging dir
+	if err != nil {
+		return "", 0, fmt.Errorf("open backup: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("checksum backup: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
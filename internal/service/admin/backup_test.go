@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+const snapshotContent = "SQLite format 3\x00 pretend database"
+
+type fakeSnapshotter struct{}
+
+func (fakeSnapshotter) Snapshot(_ context.Context, destPath string) error {
+	return os.WriteFile(destPath, []byte(snapshotContent), 0o600)
+}
+
+func newTestBackupService(t *testing.T) (*BackupService, string) {
+	t.Helper()
+	runner := &fakeRunner{statuses: []domain.MigrationStatus{
+		{Version: 49, Applied: true},
+		{Version: 50, Applied: true},
+		{Version: 51},
+	}}
+	svc := NewBackupService(fakeSnapshotter{}, runner, &recordingAudit{})
+	svc.tempDir = t.TempDir()
+	return svc, svc.tempDir
+}
+
+func TestBackupService_Create(t *testing.T) {
+	t.Run("plain backup", func(t *testing.T) {
+		svc, staging := newTestBackupService(t)
+
+		b, err := svc.Create(adminCtx(), domain.BackupOptions{})
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(b.Content)
+		require.NoError(t, err)
+		assert.Equal(t, snapshotContent, string(data))
+		sum := sha256.Sum256(data)
+		assert.Equal(t, hex.EncodeToString(sum[:]), b.Checksum)
+		assert.Equal(t, int64(len(data)), b.SizeBytes)
+		assert.Equal(t, int64(50), b.SchemaVersion)
+
+		require.NoError(t, b.Content.Close())
+		entries, err := os.ReadDir(staging)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "closing the backup removes the staged file")
+	})
+
+	t.Run("gzip backup", func(t *testing.T) {
+		svc, _ := newTestBackupService(t)
+
+		b, err := svc.Create(adminCtx(), domain.BackupOptions{Gzip: true})
+		require.NoError(t, err)
+		defer b.Content.Close() //nolint:errcheck
+		assert.True(t, b.Gzip)
+
+		raw, err := io.ReadAll(b.Content)
+		require.NoError(t, err)
+		sum := sha256.Sum256(raw)
+		assert.Equal(t, hex.EncodeToString(sum[:]), b.Checksum)
+
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		require.NoError(t, err)
+		data, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, snapshotContent, string(data))
+	})
+
+	t.Run("non-admin is denied", func(t *testing.T) {
+		svc, staging := newTestBackupService(t)
+		_, err := svc.Create(userCtx(), domain.BackupOptions{})
+		require.ErrorAs(t, err, new(*domain.AccessDeniedError))
+		entries, _ := os.ReadDir(filepath.Clean(staging))
+		assert.Empty(t, entries)
+	})
+}
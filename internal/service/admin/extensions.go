@@ -0,0 +1,38 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"duck-demo/internal/domain"
+)
+
+// ExtensionList is the server's DuckDB extension inventory together with the
+// configured allowlist.
+type ExtensionList struct {
+	Allowlist  []string
+	Extensions []domain.DuckDBExtension
+}
+
+// ExtensionService reports the DuckDB extensions installed and loaded in the
+// server's engine so operators can audit them against the allowlist.
+type ExtensionService struct {
+	inventory domain.ExtensionInventory
+}
+
+// NewExtensionService creates a new ExtensionService.
+func NewExtensionService(inventory domain.ExtensionInventory) *ExtensionService {
+	return &ExtensionService{inventory: inventory}
+}
+
+// List returns the installed and loaded extensions. Admin only.
+func (s *ExtensionService) List(ctx context.Context) (*ExtensionList, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	extensions, err := s.inventory.ListExtensions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list extensions: %w", err)
+	}
+	return &ExtensionList{Allowlist: s.inventory.ExtensionAllowlist(), Extensions: extensions}, nil
+}
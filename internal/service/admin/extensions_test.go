@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+type fakeInventory struct {
+	extensions []domain.DuckDBExtension
+	err        error
+}
+
+func (f *fakeInventory) ListExtensions(context.Context) ([]domain.DuckDBExtension, error) {
+	return f.extensions, f.err
+}
+
+func (f *fakeInventory) ExtensionAllowlist() []string {
+	return []string{"ducklake", "httpfs"}
+}
+
+func TestExtensionService_List(t *testing.T) {
+	inv := &fakeInventory{extensions: []domain.DuckDBExtension{
+		{Name: "ducklake", Version: "0.1.0", Installed: true, Loaded: true, Allowed: true},
+	}}
+	svc := NewExtensionService(inv)
+
+	got, err := svc.List(adminCtx())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ducklake", "httpfs"}, got.Allowlist)
+	require.Len(t, got.Extensions, 1)
+	assert.Equal(t, "ducklake", got.Extensions[0].Name)
+
+	_, err = svc.List(userCtx())
+	var denied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &denied)
+
+	inv.err = errors.New("boom")
+	_, err = svc.List(adminCtx())
+	require.ErrorContains(t, err, "boom")
+}
@@ -0,0 +1,74 @@
+// Package admin implements operator-only maintenance operations on the
+// metastore.
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/auditutil"
+)
+
+// MigrationService exposes metastore schema migration status and lets
+// operators apply pending migrations explicitly. All operations require
+// admin privileges.
+type MigrationService struct {
+	runner domain.MigrationRunner
+	audit  domain.AuditRepository
+}
+
+// NewMigrationService creates a new MigrationService.
+func NewMigrationService(runner domain.MigrationRunner, audit domain.AuditRepository) *MigrationService {
+	return &MigrationService{runner: runner, audit: audit}
+}
+
+// Status lists every known migration with its applied state and checksum.
+func (s *MigrationService) Status(ctx context.Context) ([]domain.MigrationStatus, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.runner.Status(ctx)
+}
+
+// Up applies all pending migrations and returns the resulting status.
+func (s *MigrationService) Up(ctx context.Context) ([]domain.MigrationStatus, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.runner.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.runner.Up(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("apply migrations: %w", err)
+	}
+	applied := countApplied(after) - countApplied(before)
+	auditutil.LogAllowed(ctx, s.audit, caller, "APPLY_MIGRATIONS", fmt.Sprintf("Applied %d pending metastore migration(s)", applied))
+	return after, nil
+}
+
+func countApplied(statuses []domain.MigrationStatus) int {
+	n := 0
+	for _, st := range statuses {
+		if st.Applied {
+			n++
+		}
+	}
+	return n
+}
+
+// requireAdmin checks that the caller in context is an admin and returns
+// the caller's name.
+func requireAdmin(ctx context.Context) (string, error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return "", domain.ErrAccessDenied("authentication required")
+	}
+	if !p.IsAdmin {
+		return "", domain.ErrAccessDenied("admin privileges required")
+	}
+	return p.Name, nil
+}
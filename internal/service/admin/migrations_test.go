@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+type fakeRunner struct {
+	statuses []domain.MigrationStatus
+	upErr    error
+	upCalls  int
+}
+
+func (f *fakeRunner) Status(context.Context) ([]domain.MigrationStatus, error) {
+	return append([]domain.MigrationStatus(nil), f.statuses...), nil
+}
+
+func (f *fakeRunner) Up(context.Context) ([]domain.MigrationStatus, error) {
+	f.upCalls++
+	if f.upErr != nil {
+		return nil, f.upErr
+	}
+	for i := range f.statuses {
+		f.statuses[i].Applied = true
+	}
+	return f.Status(context.Background())
+}
+
+type recordingAudit struct {
+	entries []domain.AuditEntry
+}
+
+func (a *recordingAudit) Insert(_ context.Context, e *domain.AuditEntry) error {
+	a.entries = append(a.entries, *e)
+	return nil
+}
+
+func (a *recordingAudit) List(context.Context, domain.AuditFilter) ([]domain.AuditEntry, int64, error) {
+	return nil, 0, nil
+}
+
+func adminCtx() context.Context {
+	return domain.WithPrincipal(context.Background(), domain.ContextPrincipal{Name: "admin-user", IsAdmin: true, Type: "user"})
+}
+
+func userCtx() context.Context {
+	return domain.WithPrincipal(context.Background(), domain.ContextPrincipal{Name: "bob", Type: "user"})
+}
+
+func TestMigrationService_Status(t *testing.T) {
+	runner := &fakeRunner{statuses: []domain.MigrationStatus{{Version: 1, Name: "001_a.sql", Applied: true}}}
+	svc := NewMigrationService(runner, &recordingAudit{})
+
+	got, err := svc.Status(adminCtx())
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	_, err = svc.Status(userCtx())
+	require.ErrorAs(t, err, new(*domain.AccessDeniedError))
+}
+
+func TestMigrationService_Up(t *testing.T) {
+	t.Run("admin applies pending and is audited", func(t *testing.T) {
+		runner := &fakeRunner{statuses: []domain.MigrationStatus{
+			{Version: 1, Name: "001_a.sql", Applied: true},
+			{Version: 2, Name: "002_b.sql"},
+		}}
+		audit := &recordingAudit{}
+		svc := NewMigrationService(runner, audit)
+
+		got, err := svc.Up(adminCtx())
+		require.NoError(t, err)
+		assert.True(t, got[1].Applied)
+		require.Len(t, audit.entries, 1)
+		assert.Equal(t, "APPLY_MIGRATIONS", audit.entries[0].Action)
+		require.NotNil(t, audit.entries[0].OriginalSQL)
+		assert.Contains(t, *audit.entries[0].OriginalSQL, "Applied 1 pending")
+	})
+
+	t.Run("non-admin is denied", func(t *testing.T) {
+		runner := &fakeRunner{}
+		svc := NewMigrationService(runner, &recordingAudit{})
+		_, err := svc.Up(userCtx())
+		require.ErrorAs(t, err, new(*domain.AccessDeniedError))
+		assert.Zero(t, runner.upCalls)
+	})
+
+	t.Run("drift error is returned", func(t *testing.T) {
+		runner := &fakeRunner{upErr: errors.New("migration checksum drift: 003_c.sql changed after being applied")}
+		svc := NewMigrationService(runner, &recordingAudit{})
+		_, err := svc.Up(adminCtx())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "checksum drift")
+	})
+}
@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/auditutil"
+)
+
+// searchReindexer is the part of the catalog search service used to rebuild
+// a catalog's search index.
+type searchReindexer interface {
+	Reindex(ctx context.Context, catalogName *string) (*domain.SearchIndexStats, error)
+}
+
+// SearchIndexService rebuilds the catalog search index, for example after a
+// bulk import or a restored backup. It requires admin privileges.
+type SearchIndexService struct {
+	search searchReindexer
+	audit  domain.AuditRepository
+}
+
+// NewSearchIndexService creates a new SearchIndexService.
+func NewSearchIndexService(search searchReindexer, audit domain.AuditRepository) *SearchIndexService {
+	return &SearchIndexService{search: search, audit: audit}
+}
+
+// Reindex rebuilds the search index of a catalog, or of the default catalog
+// when catalogName is nil, and reports how many objects it indexed.
+func (s *SearchIndexService) Reindex(ctx context.Context, catalogName *string) (*domain.SearchIndexStats, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.search.Reindex(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	target := "the default catalog"
+	if catalogName != nil && *catalogName != "" {
+		target = fmt.Sprintf("catalog %q", *catalogName)
+	}
+	auditutil.LogAllowed(ctx, s.audit, caller, "REINDEX_SEARCH", fmt.Sprintf(
+		"Reindexed search for %s: %d schema(s), %d table(s), %d view(s), %d column(s)",
+		target, stats.Schemas, stats.Tables, stats.Views, stats.Columns))
+	return stats, nil
+}
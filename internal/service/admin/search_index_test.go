@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// fakeReindexer records the catalog it was asked to reindex.
+type fakeReindexer struct {
+	catalog *string
+	calls   int
+}
+
+func (f *fakeReindexer) Reindex(_ context.Context, catalogName *string) (*domain.SearchIndexStats, error) {
+	f.catalog = catalogName
+	f.calls++
+	return &domain.SearchIndexStats{Schemas: 1, Tables: 2, Columns: 7}, nil
+}
+
+func TestSearchIndexService_Reindex(t *testing.T) {
+	t.Run("requires admin", func(t *testing.T) {
+		search := &fakeReindexer{}
+		svc := NewSearchIndexService(search, &recordingAudit{})
+		_, err := svc.Reindex(userCtx(), nil)
+		var denied *domain.AccessDeniedError
+		require.ErrorAs(t, err, &denied)
+		assert.Zero(t, search.calls)
+	})
+
+	t.Run("audits the run", func(t *testing.T) {
+		audit := &recordingAudit{}
+		search := &fakeReindexer{}
+		svc := NewSearchIndexService(search, audit)
+		catalogName := "lake"
+		stats, err := svc.Reindex(adminCtx(), &catalogName)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), stats.Tables)
+		assert.Equal(t, &catalogName, search.catalog)
+		require.Len(t, audit.entries, 1)
+		assert.Equal(t, "REINDEX_SEARCH", audit.entries[0].Action)
+	})
+}
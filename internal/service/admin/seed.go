@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/seed"
+	"duck-demo/internal/service/auditutil"
+)
+
+// SeedService populates a running server with demo data on demand. All
+// operations require admin privileges.
+type SeedService struct {
+	principals domain.PrincipalRepository
+	groups     domain.GroupRepository
+	audit      domain.AuditRepository
+}
+
+// NewSeedService creates a new SeedService.
+func NewSeedService(principals domain.PrincipalRepository, groups domain.GroupRepository, audit domain.AuditRepository) *SeedService {
+	return &SeedService{principals: principals, groups: groups, audit: audit}
+}
+
+// Demo creates the demo users, groups and memberships that do not exist yet.
+// Running it again creates nothing.
+func (s *SeedService) Demo(ctx context.Context) (*seed.Result, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := seed.Demo(ctx, s.principals, s.groups)
+	if err != nil {
+		return nil, err
+	}
+	auditutil.LogAllowed(ctx, s.audit, caller, "SEED_DEMO", fmt.Sprintf(
+		"Seeded demo data: %d principal(s), %d group(s), %d membership(s) created",
+		res.PrincipalsCreated, res.GroupsCreated, res.MembershipsAdded))
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// seedPrincipals is a PrincipalRepository that already holds every principal.
+type seedPrincipals struct{ domain.PrincipalRepository }
+
+func (seedPrincipals) GetByName(_ context.Context, name string) (*domain.Principal, error) {
+	return &domain.Principal{ID: "id-" + name, Name: name, Type: "user"}, nil
+}
+
+// seedGroups is a GroupRepository that already holds every group and
+// membership.
+type seedGroups struct{ domain.GroupRepository }
+
+func (seedGroups) GetByName(_ context.Context, name string) (*domain.Group, error) {
+	return &domain.Group{ID: "id-" + name, Name: name}, nil
+}
+
+func (seedGroups) GetGroupsForMember(_ context.Context, _, _ string) ([]domain.Group, error) {
+	return []domain.Group{{ID: "id-analysts"}, {ID: "id-researchers"}}, nil
+}
+
+func TestSeedService_Demo(t *testing.T) {
+	t.Run("requires admin", func(t *testing.T) {
+		svc := NewSeedService(seedPrincipals{}, seedGroups{}, &recordingAudit{})
+		_, err := svc.Demo(userCtx())
+		var denied *domain.AccessDeniedError
+		require.ErrorAs(t, err, &denied)
+	})
+
+	t.Run("audits the run", func(t *testing.T) {
+		audit := &recordingAudit{}
+		svc := NewSeedService(seedPrincipals{}, seedGroups{}, audit)
+		res, err := svc.Demo(adminCtx())
+		require.NoError(t, err)
+		assert.Zero(t, res.PrincipalsCreated, "existing demo data is not recreated")
+		require.Len(t, audit.entries, 1)
+		assert.Equal(t, "SEED_DEMO", audit.entries[0].Action)
+	})
+}
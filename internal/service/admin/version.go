@@ -0,0 +1,123 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"duck-demo/internal/domain"
+)
+
+// computeEndpointChecker is the part of the compute endpoint service used to
+// collect endpoint versions.
+type computeEndpointChecker interface {
+	List(ctx context.Context, principal string, page domain.PageRequest) ([]domain.ComputeEndpoint, int64, error)
+	HealthCheck(ctx context.Context, principal string, endpointName string) (*domain.ComputeEndpointHealthResult, error)
+}
+
+// VersionService reports the versions of the server binary, its embedded
+// DuckDB engine and the metastore schema. Any authenticated caller may read
+// it; including compute endpoints requires MANAGE_COMPUTE.
+type VersionService struct {
+	appVersion string
+	duckDB     *sql.DB
+	migrations domain.MigrationRunner
+	compute    computeEndpointChecker
+}
+
+// NewVersionService creates a new VersionService. appVersion is the release
+// the binary was built as; compute may be nil when compute endpoints are
+// not configured.
+func NewVersionService(appVersion string, duckDB *sql.DB, migrations domain.MigrationRunner, compute computeEndpointChecker) *VersionService {
+	return &VersionService{appVersion: appVersion, duckDB: duckDB, migrations: migrations, compute: compute}
+}
+
+// Get returns the server's versions. With includeCompute, each compute
+// endpoint is health checked for its DuckDB version; an unreachable endpoint
+// is reported with an error instead of failing the call.
+func (s *VersionService) Get(ctx context.Context, includeCompute bool) (*domain.ServerVersion, error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrAccessDenied("authentication required")
+	}
+
+	v := &domain.ServerVersion{AppVersion: s.appVersion, GoVersion: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				v.Commit = setting.Value
+			}
+		}
+	}
+
+	if err := s.duckDB.QueryRowContext(ctx, "SELECT version()").Scan(&v.DuckDBVersion); err != nil {
+		return nil, fmt.Errorf("read duckdb version: %w", err)
+	}
+	extensions, err := s.loadedExtensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.Extensions = extensions
+
+	if v.MetastoreSchemaVersion, err = appliedSchemaVersion(ctx, s.migrations); err != nil {
+		return nil, err
+	}
+
+	if includeCompute && s.compute != nil {
+		if v.ComputeEndpoints, err = s.computeVersions(ctx, p.Name); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (s *VersionService) loadedExtensions(ctx context.Context) ([]domain.ExtensionVersion, error) {
+	rows, err := s.duckDB.QueryContext(ctx, `
+		SELECT extension_name, COALESCE(extension_version, '')
+		FROM duckdb_extensions()
+		WHERE loaded
+		ORDER BY extension_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list duckdb extensions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var extensions []domain.ExtensionVersion
+	for rows.Next() {
+		var e domain.ExtensionVersion
+		if err := rows.Scan(&e.Name, &e.Version); err != nil {
+			return nil, fmt.Errorf("scan duckdb extension: %w", err)
+		}
+		extensions = append(extensions, e)
+	}
+	return extensions, rows.Err()
+}
+
+func (s *VersionService) computeVersions(ctx context.Context, principal string) ([]domain.ComputeEndpointVersion, error) {
+	var versions []domain.ComputeEndpointVersion
+	page := domain.PageRequest{MaxResults: domain.MaxMaxResults}
+	for {
+		endpoints, total, err := s.compute.List(ctx, principal, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, ep := range endpoints {
+			ev := domain.ComputeEndpointVersion{Name: ep.Name, Type: ep.Type}
+			health, err := s.compute.HealthCheck(ctx, principal, ep.Name)
+			switch {
+			case err != nil:
+				ev.Error = err.Error()
+			case health.DuckdbVersion != nil:
+				ev.DuckDBVersion = *health.DuckdbVersion
+			}
+			versions = append(versions, ev)
+		}
+		page.PageToken = domain.NextPageToken(page.Offset(), page.Limit(), total)
+		if page.PageToken == "" {
+			return versions, nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+type fakeComputeChecker struct {
+	endpoints []domain.ComputeEndpoint
+	versions  map[string]string
+	listErr   error
+}
+
+func (f *fakeComputeChecker) List(context.Context, string, domain.PageRequest) ([]domain.ComputeEndpoint, int64, error) {
+	if f.listErr != nil {
+		return nil, 0, f.listErr
+	}
+	return f.endpoints, int64(len(f.endpoints)), nil
+}
+
+func (f *fakeComputeChecker) HealthCheck(_ context.Context, _ string, name string) (*domain.ComputeEndpointHealthResult, error) {
+	v, ok := f.versions[name]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	return &domain.ComputeEndpointHealthResult{DuckdbVersion: &v}, nil
+}
+
+func openDuckDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestVersionService_Get(t *testing.T) {
+	runner := &fakeRunner{statuses: []domain.MigrationStatus{
+		{Version: 50, Applied: true},
+		{Version: 51, Applied: true},
+		{Version: 52},
+	}}
+	svc := NewVersionService("v1.4.0", openDuckDB(t), runner, nil)
+
+	got, err := svc.Get(userCtx(), false)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.4.0", got.AppVersion)
+	assert.NotEmpty(t, got.GoVersion)
+	assert.NotEmpty(t, got.DuckDBVersion)
+	assert.Equal(t, int64(51), got.MetastoreSchemaVersion)
+	assert.Nil(t, got.ComputeEndpoints)
+}
+
+func TestVersionService_ComputeEndpoints(t *testing.T) {
+	compute := &fakeComputeChecker{
+		endpoints: []domain.ComputeEndpoint{{Name: "analytics-xl", Type: "REMOTE"}, {Name: "offline", Type: "REMOTE"}},
+		versions:  map[string]string{"analytics-xl": "v1.1.3"},
+	}
+	svc := NewVersionService("dev", openDuckDB(t), &fakeRunner{}, compute)
+
+	got, err := svc.Get(adminCtx(), false)
+	require.NoError(t, err)
+	assert.Nil(t, got.ComputeEndpoints, "endpoints are only checked on request")
+
+	got, err = svc.Get(adminCtx(), true)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ComputeEndpointVersion{
+		{Name: "analytics-xl", Type: "REMOTE", DuckDBVersion: "v1.1.3"},
+		{Name: "offline", Type: "REMOTE", Error: "connection refused"},
+	}, got.ComputeEndpoints)
+
+	compute.listErr = domain.ErrAccessDenied("MANAGE_COMPUTE required")
+	_, err = svc.Get(userCtx(), true)
+	var denied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &denied)
+}
+
+func TestVersionService_RequiresAuthentication(t *testing.T) {
+	svc := NewVersionService("dev", openDuckDB(t), &fakeRunner{}, nil)
+	_, err := svc.Get(context.Background(), false)
+	var denied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &denied)
+}
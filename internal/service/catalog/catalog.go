@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"duck-demo/internal/domain"
 )
@@ -27,6 +28,19 @@ type CatalogService struct {
 	tags        domain.TagRepository
 	stats       domain.TableStatisticsRepository
 	locations   domain.ExternalLocationRepository // optional, nil when not configured
+
+	// Optional sources for rollback impact analysis and lineage.
+	views   domain.ViewRepository
+	models  domain.ModelRepository
+	lineage domain.LineageRepository
+
+	// Optional default grant templates applied to new schemas and tables.
+	defaultGrants domain.DefaultGrantRepository
+	grants        domain.GrantRepository
+
+	// Optional policy repositories used to copy policies between tables.
+	rowFilters  domain.RowFilterRepository
+	columnMasks domain.ColumnMaskRepository
 }
 
 // NewCatalogService creates a new CatalogService.
@@ -48,6 +62,15 @@ func NewCatalogService(
 	}
 }
 
+// SetDependencyRepos sets the view, model and lineage repositories a table
+// rollback uses to report dependent objects and record lineage. Any of them
+// may be nil.
+func (s *CatalogService) SetDependencyRepos(views domain.ViewRepository, models domain.ModelRepository, lineage domain.LineageRepository) {
+	s.views = views
+	s.models = models
+	s.lineage = lineage
+}
+
 // GetCatalogInfo returns information about a catalog.
 func (s *CatalogService) GetCatalogInfo(ctx context.Context, catalogName string) (*domain.CatalogInfo, error) {
 	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
@@ -66,13 +89,24 @@ func (s *CatalogService) GetMetastoreSummary(ctx context.Context, catalogName st
 	return repo.GetMetastoreSummary(ctx)
 }
 
-// ListSchemas returns a paginated list of schemas.
-func (s *CatalogService) ListSchemas(ctx context.Context, catalogName string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+// GetCatalogStats returns a catalog's object counts, total rows and bytes,
+// and its largest tables, aggregated from stored table statistics.
+func (s *CatalogService) GetCatalogStats(ctx context.Context, catalogName string) (*domain.CatalogStats, error) {
+	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetCatalogStats(ctx, domain.CatalogStatsLargestTables)
+}
+
+// ListSchemas returns a paginated list of schemas. A non-nil owner limits the
+// list to schemas that principal owns.
+func (s *CatalogService) ListSchemas(ctx context.Context, catalogName string, owner *string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
 	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
 	if err != nil {
 		return nil, 0, err
 	}
-	schemas, total, err := repo.ListSchemas(ctx, page)
+	schemas, total, err := repo.ListSchemas(ctx, owner, page)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -126,6 +160,7 @@ func (s *CatalogService) CreateSchema(ctx context.Context, catalogName string, p
 	}
 
 	s.logAudit(ctx, principal, "CREATE_SCHEMA", fmt.Sprintf("Created schema %q in catalog %q", req.Name, catalogName))
+	s.applyDefaultGrants(ctx, principal, catalogName, "", domain.SecurableSchema, result.SchemaID, req.Name)
 	return result, nil
 }
 
@@ -170,7 +205,7 @@ func (s *CatalogService) UpdateSchema(ctx context.Context, catalogName string, p
 		return nil, domain.ErrAccessDenied("%q lacks permission to update schema %q", principal, name)
 	}
 
-	result, err := repo.UpdateSchema(ctx, name, req.Comment, req.Properties)
+	result, err := repo.UpdateSchema(ctx, name, req.Comment, req.Properties, req.ExpectedRowVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -214,13 +249,14 @@ func (s *CatalogService) DeleteSchema(ctx context.Context, catalogName string, p
 	return nil
 }
 
-// ListTables returns a paginated list of tables in a schema.
-func (s *CatalogService) ListTables(ctx context.Context, catalogName string, schemaName string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
+// ListTables returns a paginated list of tables in a schema. A non-nil owner
+// limits the list to tables that principal owns.
+func (s *CatalogService) ListTables(ctx context.Context, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
 	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
 	if err != nil {
 		return nil, 0, err
 	}
-	tables, total, err := repo.ListTables(ctx, schemaName, page)
+	tables, total, err := repo.ListTables(ctx, schemaName, owner, page)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -252,6 +288,9 @@ func (s *CatalogService) CreateTable(ctx context.Context, catalogName string, pr
 		s.logAuditDenied(ctx, principal, "CREATE_TABLE", fmt.Sprintf("Denied create table %q in schema %q", req.Name, schemaName))
 		return nil, domain.ErrAccessDenied("%q lacks CREATE_TABLE privilege", principal)
 	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 
 	switch req.TableType {
 	case "", domain.TableTypeManaged:
@@ -260,6 +299,7 @@ func (s *CatalogService) CreateTable(ctx context.Context, catalogName string, pr
 			return nil, err
 		}
 		s.logAudit(ctx, principal, "CREATE_TABLE", fmt.Sprintf("Created table %q in schema %q", req.Name, schemaName))
+		s.applyDefaultGrants(ctx, principal, catalogName, schemaName, domain.SecurableTable, result.TableID, schemaName+"."+req.Name)
 		return result, nil
 
 	case domain.TableTypeExternal:
@@ -301,6 +341,7 @@ func (s *CatalogService) createExternalTable(ctx context.Context, catalogName st
 	}
 
 	s.logAudit(ctx, principal, "CREATE_EXTERNAL_TABLE", fmt.Sprintf("Created external table %q in schema %q", req.Name, schemaName))
+	s.applyDefaultGrants(ctx, principal, catalogName, schemaName, domain.SecurableTable, result.TableID, schemaName+"."+req.Name)
 	return result, nil
 }
 
@@ -319,8 +360,10 @@ func (s *CatalogService) GetTable(ctx context.Context, catalogName string, schem
 	return result, nil
 }
 
-// DeleteTable drops a table, checking authorization.
-func (s *CatalogService) DeleteTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string) error {
+// DeleteTable drops a table, checking authorization. A table that views
+// read is only dropped with cascade, which drops those views first and
+// requires MANAGE on their schemas; otherwise a ConflictError names them.
+func (s *CatalogService) DeleteTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, cascade bool) error {
 	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
 	if err != nil {
 		return err
@@ -345,6 +388,24 @@ func (s *CatalogService) DeleteTable(ctx context.Context, catalogName string, pr
 		return domain.ErrAccessDenied("%q lacks permission to delete table %q.%q", principal, schemaName, tableName)
 	}
 
+	views, err := s.dependentViews(ctx, repo, catalogName, schemaName, tableName)
+	if err != nil {
+		return fmt.Errorf("find dependent views: %w", err)
+	}
+	if len(views) > 0 {
+		if !cascade {
+			names := make([]string, len(views))
+			for i, v := range views {
+				names[i] = v.SchemaName + "." + v.Name
+			}
+			return domain.ErrConflict("table %q.%q has dependent views: %s; drop them first or retry with cascade",
+				schemaName, tableName, strings.Join(names, ", "))
+		}
+		if err := s.dropDependentViews(ctx, principal, schemaName, tableName, views); err != nil {
+			return err
+		}
+	}
+
 	if err := repo.DeleteTable(ctx, schemaName, tableName); err != nil {
 		return err
 	}
@@ -353,6 +414,28 @@ func (s *CatalogService) DeleteTable(ctx context.Context, catalogName string, pr
 	return nil
 }
 
+// dropDependentViews drops the views a cascading table drop removes. All
+// privileges are checked before any view is dropped.
+func (s *CatalogService) dropDependentViews(ctx context.Context, principal, schemaName, tableName string, views []domain.ViewDetail) error {
+	for _, v := range views {
+		allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableSchema, v.SchemaID, domain.PrivManage)
+		if err != nil {
+			return fmt.Errorf("check privilege: %w", err)
+		}
+		if !allowed {
+			s.logAuditDenied(ctx, principal, "DROP_TABLE", fmt.Sprintf("Denied cascading delete of table %q.%q to view %q.%q", schemaName, tableName, v.SchemaName, v.Name))
+			return domain.ErrAccessDenied("%q lacks permission to drop dependent view %q.%q", principal, v.SchemaName, v.Name)
+		}
+	}
+	for _, v := range views {
+		if err := s.views.Delete(ctx, v.SchemaID, v.Name); err != nil {
+			return fmt.Errorf("drop dependent view %q.%q: %w", v.SchemaName, v.Name, err)
+		}
+		s.logAudit(ctx, principal, "DROP_VIEW", fmt.Sprintf("Dropped view %q.%q with table %q.%q", v.SchemaName, v.Name, schemaName, tableName))
+	}
+	return nil
+}
+
 // ListColumns returns a paginated list of columns for a table.
 func (s *CatalogService) ListColumns(ctx context.Context, catalogName string, schemaName, tableName string, page domain.PageRequest) ([]domain.ColumnDetail, int64, error) {
 	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
@@ -388,7 +471,7 @@ func (s *CatalogService) UpdateTable(ctx context.Context, catalogName string, pr
 		return nil, domain.ErrAccessDenied("%q lacks permission to update table %q.%q", principal, schemaName, tableName)
 	}
 
-	result, err := repo.UpdateTable(ctx, schemaName, tableName, req.Comment, req.Properties, req.Owner)
+	result, err := repo.UpdateTable(ctx, schemaName, tableName, req.Comment, req.Properties, req.Owner, req.ExpectedRowVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -399,6 +482,90 @@ func (s *CatalogService) UpdateTable(ctx context.Context, catalogName string, pr
 	return result, nil
 }
 
+// RenameTable renames a table in place. The table keeps its ID, so grants,
+// tags, row filters and column masks still apply under the new name. Views
+// and models that read the table block the rename unless req.Force is set,
+// since their SQL still uses the old name.
+func (s *CatalogService) RenameTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.RenameRequest) (*domain.TableDetail, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := repo.GetTable(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableTable, tbl.TableID, domain.PrivManage)
+	if err != nil {
+		return nil, fmt.Errorf("check privilege: %w", err)
+	}
+	if !allowed {
+		allowed, err = s.auth.CheckPrivilege(ctx, principal, domain.SecurableTable, tbl.TableID, domain.PrivCreateTable)
+		if err != nil {
+			return nil, fmt.Errorf("check privilege: %w", err)
+		}
+	}
+	if !allowed {
+		s.logAuditDenied(ctx, principal, "RENAME_TABLE", fmt.Sprintf("Denied rename table %q.%q", schemaName, tableName))
+		return nil, domain.ErrAccessDenied("%q lacks permission to rename table %q.%q", principal, schemaName, tableName)
+	}
+
+	if s.views != nil {
+		if err := s.checkRenameAgainstViews(ctx, repo, schemaName, tableName, req.NewName); err != nil {
+			return nil, err
+		}
+	}
+
+	deps, err := s.listTableDependents(ctx, repo, catalogName, schemaName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("find dependent views: %w", err)
+	}
+	if len(deps) > 0 && !req.Force {
+		return nil, domain.ErrConflict("table %q.%q is read by %s, whose SQL would still use the old name; update them first or retry with force",
+			schemaName, tableName, describeDependents(deps))
+	}
+
+	result, err := repo.RenameTable(ctx, schemaName, tableName, req.NewName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.enrichTableTags(ctx, result)
+	s.enrichTableStats(ctx, result)
+	s.logAudit(ctx, principal, "RENAME_TABLE", fmt.Sprintf("Renamed table %q.%q to %q", schemaName, tableName, req.NewName))
+	return result, nil
+}
+
+// checkRenameAgainstViews refuses to rename the table backing a materialized
+// view, which is renamed with its view, or to give a table a view's name.
+func (s *CatalogService) checkRenameAgainstViews(ctx context.Context, repo domain.CatalogRepository, schemaName, tableName, newName string) error {
+	schema, err := repo.GetSchema(ctx, schemaName)
+	if err != nil {
+		return err
+	}
+	if v, err := s.views.GetByName(ctx, schema.SchemaID, tableName); err == nil && v.Materialized {
+		return domain.ErrValidation("table %q.%q stores materialized view %q; rename the view instead", schemaName, tableName, v.Name)
+	}
+	if _, err := s.views.GetByName(ctx, schema.SchemaID, newName); err == nil {
+		return domain.ErrAlreadyExists("view %q already exists in schema %q", newName, schemaName)
+	}
+	return nil
+}
+
+// describeDependents lists dependents for an error message, such as
+// "view analytics.daily, model sales.orders".
+func describeDependents(deps []domain.DependentObject) string {
+	names := make([]string, len(deps))
+	for i, d := range deps {
+		names[i] = strings.ToLower(d.Type) + " " + d.Name
+	}
+	return strings.Join(names, ", ")
+}
+
 // UpdateCatalog updates catalog-level metadata (admin only).
 func (s *CatalogService) UpdateCatalog(ctx context.Context, catalogName string, principal string, req domain.UpdateCatalogRequest) (*domain.CatalogInfo, error) {
 
@@ -506,6 +673,113 @@ func (s *CatalogService) ProfileTable(ctx context.Context, catalogName string, p
 	return result, nil
 }
 
+// CompactTable merges a table's small data files, requiring MODIFY on the table.
+func (s *CatalogService) CompactTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string) (*domain.TableMaintenanceResult, error) {
+	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := repo.GetTable(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableTable, tbl.TableID, domain.PrivModify)
+	if err != nil {
+		return nil, fmt.Errorf("check privilege: %w", err)
+	}
+	if !allowed {
+		s.logAuditDenied(ctx, principal, "COMPACT_TABLE", fmt.Sprintf("Denied compact table %q.%q", schemaName, tableName))
+		return nil, domain.ErrAccessDenied("principal %q lacks MODIFY on %s.%s", principal, schemaName, tableName)
+	}
+
+	result, err := repo.CompactTable(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, principal, "COMPACT_TABLE", fmt.Sprintf("Compacted table %q.%q: %d -> %d files", schemaName, tableName, result.FilesBefore, result.FilesAfter))
+	return result, nil
+}
+
+// VacuumTable expires snapshots older than olderThan and deletes the files
+// they alone referenced. Snapshot expiry affects every table in the catalog,
+// so it requires MANAGE on the catalog rather than a table privilege.
+func (s *CatalogService) VacuumTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, olderThan time.Duration) (*domain.TableMaintenanceResult, error) {
+	allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableCatalog, catalogName, domain.PrivManage)
+	if err != nil {
+		return nil, fmt.Errorf("check privilege: %w", err)
+	}
+	if !allowed {
+		s.logAuditDenied(ctx, principal, "VACUUM_TABLE", fmt.Sprintf("Denied vacuum table %q.%q", schemaName, tableName))
+		return nil, domain.ErrAccessDenied("principal %q lacks MANAGE on catalog %q", principal, catalogName)
+	}
+
+	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	result, err := repo.VacuumTable(ctx, schemaName, tableName, olderThan)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, principal, "VACUUM_TABLE", fmt.Sprintf("Vacuumed table %q.%q older than %s: %d snapshots expired, %d bytes reclaimed",
+		schemaName, tableName, olderThan, result.SnapshotsExpired, result.BytesReclaimed()))
+	return result, nil
+}
+
+// RollbackTable restores a table's data to an earlier snapshot, requiring
+// MANAGE on the table. Without req.Confirm the rollback is only previewed.
+// Either way the result lists the views and models that read the table, so
+// callers can review the impact before confirming.
+func (s *CatalogService) RollbackTable(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := repo.GetTable(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableTable, tbl.TableID, domain.PrivManage)
+	if err != nil {
+		return nil, fmt.Errorf("check privilege: %w", err)
+	}
+	if !allowed {
+		s.logAuditDenied(ctx, principal, "ROLLBACK_TABLE", fmt.Sprintf("Denied rollback table %q.%q", schemaName, tableName))
+		return nil, domain.ErrAccessDenied("principal %q lacks MANAGE on %s.%s", principal, schemaName, tableName)
+	}
+
+	result, err := repo.RollbackTable(ctx, schemaName, tableName, req)
+	if err != nil {
+		return nil, err
+	}
+	result.Dependents = s.tableDependents(ctx, repo, catalogName, schemaName, tableName)
+	if !result.Applied {
+		return result, nil
+	}
+
+	s.logAudit(ctx, principal, "ROLLBACK_TABLE", fmt.Sprintf("Rolled back table %q.%q from snapshot %d to %d: %d -> %d rows",
+		schemaName, tableName, result.CurrentSnapshot, result.TargetSnapshot, result.RowsBefore, result.RowsAfter))
+	if s.lineage != nil {
+		// The table is rewritten from its own history.
+		_ = s.lineage.InsertEdge(ctx, &domain.LineageEdge{
+			SourceTable:   tableName,
+			TargetTable:   &tableName,
+			SourceSchema:  schemaName,
+			TargetSchema:  schemaName,
+			EdgeType:      "WRITE",
+			PrincipalName: principal,
+		})
+	}
+	return result, nil
+}
+
 func (s *CatalogService) enrichSchemaTags(ctx context.Context, schema *domain.SchemaDetail) {
 	if s.tags == nil {
 		return
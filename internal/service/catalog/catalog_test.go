@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -586,7 +587,7 @@ func TestCatalogService_UpdateTable(t *testing.T) {
 				}
 			},
 			setupRepo: func(repo *mockCatalogRepo) {
-				repo.UpdateTableFn = func(_ context.Context, schema, table string, _ *string, _ map[string]string, _ *string) (*domain.TableDetail, error) {
+				repo.UpdateTableFn = func(_ context.Context, schema, table string, _ *string, _ map[string]string, _ *string, _ *int64) (*domain.TableDetail, error) {
 					return &domain.TableDetail{
 						TableID:    "1",
 						Name:       table,
@@ -718,7 +719,7 @@ func TestCatalogService_DeleteTable(t *testing.T) {
 			audit := &mockAuditRepo{}
 			svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
 
-			err := svc.DeleteTable(context.Background(), "lake", tt.principal, "main", "events")
+			err := svc.DeleteTable(context.Background(), "lake", tt.principal, "main", "events", false)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -732,6 +733,209 @@ func TestCatalogService_DeleteTable(t *testing.T) {
 	}
 }
 
+func TestCatalogService_DeleteTable_DependentViews(t *testing.T) {
+	t.Parallel()
+
+	setup := func(allowView bool) (*CatalogService, *[]string, *mockAuditRepo) {
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, securableType, _ string, _ string) (bool, error) {
+				return securableType != domain.SecurableSchema || allowView, nil
+			},
+		}
+		var dropped []string
+		repo := &mockCatalogRepo{
+			DeleteTableFn: func(_ context.Context, schemaName, tableName string) error {
+				dropped = append(dropped, "table:"+schemaName+"."+tableName)
+				return nil
+			},
+			ListSchemasFn: func(_ context.Context, _ *string, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+				return []domain.SchemaDetail{{SchemaID: "1", Name: "main"}, {SchemaID: "2", Name: "reporting"}}, 2, nil
+			},
+		}
+		ensureCatalogLookupDefaults(repo, "main", "events")
+		views := &mockViewRepo{
+			ListFn: func(_ context.Context, schemaID string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+				if schemaID == "1" {
+					return []domain.ViewDetail{{Name: "other", ViewDefinition: "SELECT * FROM main.customers"}}, 1, nil
+				}
+				return []domain.ViewDetail{{Name: "daily_events", ViewDefinition: "SELECT count(*) FROM main.events"}}, 1, nil
+			},
+			DeleteFn: func(_ context.Context, schemaID, viewName string) error {
+				dropped = append(dropped, "view:"+schemaID+"."+viewName)
+				return nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+		svc.SetDependencyRepos(views, nil, nil)
+		return svc, &dropped, audit
+	}
+
+	t.Run("blocked without cascade", func(t *testing.T) {
+		t.Parallel()
+		svc, dropped, _ := setup(true)
+
+		err := svc.DeleteTable(context.Background(), "lake", "alice", "main", "events", false)
+
+		var conflict *domain.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Contains(t, err.Error(), "reporting.daily_events")
+		assert.NotContains(t, err.Error(), "main.other")
+		assert.Empty(t, *dropped, "nothing is dropped")
+	})
+
+	t.Run("cascade drops dependent views first", func(t *testing.T) {
+		t.Parallel()
+		svc, dropped, audit := setup(true)
+
+		err := svc.DeleteTable(context.Background(), "lake", "alice", "main", "events", true)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"view:2.daily_events", "table:main.events"}, *dropped)
+		assert.True(t, audit.HasAction("DROP_VIEW"))
+		assert.True(t, audit.HasAction("DROP_TABLE"))
+	})
+
+	t.Run("cascade requires MANAGE on the view schema", func(t *testing.T) {
+		t.Parallel()
+		svc, dropped, _ := setup(false)
+
+		err := svc.DeleteTable(context.Background(), "lake", "alice", "main", "events", true)
+
+		var ade *domain.AccessDeniedError
+		require.ErrorAs(t, err, &ade)
+		assert.Empty(t, *dropped)
+	})
+}
+
+// === RenameTable ===
+
+func TestCatalogService_RenameTable(t *testing.T) {
+	t.Parallel()
+
+	// setup serves a catalog whose tables and grants are keyed on the table
+	// ID, as in DuckLake: renaming moves a name to the same ID.
+	setup := func(viewDefs map[string]string) (*CatalogService, *mockAuthService, *mockAuditRepo) {
+		tables := map[string]string{"orders": "table-7", "customers": "table-8"}
+		grants := map[string]map[string]bool{
+			"table-7": {"alice:" + domain.PrivManage: true, "bob:" + domain.PrivSelect: true},
+		}
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, principal, _ string, securableID string, privilege string) (bool, error) {
+				return grants[securableID][principal+":"+privilege], nil
+			},
+		}
+		repo := &mockCatalogRepo{
+			GetSchemaFn: func(_ context.Context, name string) (*domain.SchemaDetail, error) {
+				return &domain.SchemaDetail{SchemaID: "schema-1", Name: name, CatalogName: "lake"}, nil
+			},
+			ListSchemasFn: func(_ context.Context, _ *string, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+				return []domain.SchemaDetail{{SchemaID: "schema-1", Name: "main"}}, 1, nil
+			},
+			GetTableFn: func(_ context.Context, schemaName, tableName string) (*domain.TableDetail, error) {
+				id, ok := tables[tableName]
+				if !ok {
+					return nil, domain.ErrNotFound("table %q not found", tableName)
+				}
+				return &domain.TableDetail{TableID: id, SchemaName: schemaName, Name: tableName, CatalogName: "lake"}, nil
+			},
+			RenameTableFn: func(_ context.Context, schemaName, tableName, newName string) (*domain.TableDetail, error) {
+				id := tables[tableName]
+				delete(tables, tableName)
+				tables[newName] = id
+				return &domain.TableDetail{TableID: id, SchemaName: schemaName, Name: newName, CatalogName: "lake"}, nil
+			},
+		}
+		views := &mockViewRepo{
+			GetByNameFn: func(_ context.Context, _, viewName string) (*domain.ViewDetail, error) {
+				if def, ok := viewDefs[viewName]; ok {
+					return &domain.ViewDetail{Name: viewName, ViewDefinition: def, Materialized: viewName == "orders"}, nil
+				}
+				return nil, domain.ErrNotFound("view %q not found", viewName)
+			},
+			ListFn: func(_ context.Context, _ string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+				var out []domain.ViewDetail
+				for name, def := range viewDefs {
+					out = append(out, domain.ViewDetail{Name: name, ViewDefinition: def})
+				}
+				return out, int64(len(out)), nil
+			},
+		}
+		tags := &mockTagRepo{
+			ListTagsForSecurableFn: func(_ context.Context, _ string, _ string, _ *string) ([]domain.Tag, error) {
+				return nil, nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(repo, auth, audit, tags, &mockStatsRepo{}, nil)
+		svc.SetDependencyRepos(views, nil, nil)
+		return svc, auth, audit
+	}
+
+	t.Run("renamed table keeps its grants", func(t *testing.T) {
+		t.Parallel()
+		svc, auth, audit := setup(nil)
+		ctx := context.Background()
+
+		renamed, err := svc.RenameTable(ctx, "lake", "alice", "main", "orders", domain.RenameRequest{NewName: "orders_v2"})
+		require.NoError(t, err)
+		assert.Equal(t, "orders_v2", renamed.Name)
+		assert.Equal(t, "table-7", renamed.TableID)
+		assert.True(t, audit.HasAction("RENAME_TABLE"))
+
+		tbl, err := svc.GetTable(ctx, "lake", "main", "orders_v2")
+		require.NoError(t, err)
+		canRead, err := auth.CheckPrivilege(ctx, "bob", domain.SecurableTable, tbl.TableID, domain.PrivSelect)
+		require.NoError(t, err)
+		assert.True(t, canRead, "bob's SELECT grant follows the table to its new name")
+
+		_, err = svc.GetTable(ctx, "lake", "main", "orders")
+		assert.ErrorAs(t, err, new(*domain.NotFoundError))
+	})
+
+	t.Run("dependent views block the rename unless forced", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _ := setup(map[string]string{"big_orders": "SELECT * FROM main.orders WHERE amount > 100"})
+		ctx := context.Background()
+
+		_, err := svc.RenameTable(ctx, "lake", "alice", "main", "orders", domain.RenameRequest{NewName: "orders_v2"})
+		var conflict *domain.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Contains(t, err.Error(), "view main.big_orders")
+
+		_, err = svc.RenameTable(ctx, "lake", "alice", "main", "orders", domain.RenameRequest{NewName: "orders_v2", Force: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("table of a materialized view is renamed with the view", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _ := setup(map[string]string{"orders": "SELECT 1"})
+
+		_, err := svc.RenameTable(context.Background(), "lake", "alice", "main", "orders", domain.RenameRequest{NewName: "orders_v2"})
+		var validation *domain.ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Contains(t, err.Error(), "rename the view instead")
+	})
+
+	t.Run("requires MANAGE on the table", func(t *testing.T) {
+		t.Parallel()
+		svc, _, audit := setup(nil)
+
+		_, err := svc.RenameTable(context.Background(), "lake", "bob", "main", "orders", domain.RenameRequest{NewName: "orders_v2"})
+		var denied *domain.AccessDeniedError
+		require.ErrorAs(t, err, &denied)
+		assert.True(t, audit.HasAction("RENAME_TABLE"))
+	})
+
+	t.Run("new name is required", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _ := setup(nil)
+
+		_, err := svc.RenameTable(context.Background(), "lake", "alice", "main", "orders", domain.RenameRequest{NewName: " "})
+		assert.ErrorAs(t, err, new(*domain.ValidationError))
+	})
+}
+
 // === ProfileTable ===
 
 func TestCatalogService_ProfileTable(t *testing.T) {
@@ -854,6 +1058,236 @@ func TestCatalogService_ProfileTable(t *testing.T) {
 	}
 }
 
+// === CompactTable / VacuumTable ===
+
+func TestCatalogService_CompactTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires MODIFY on the table", func(t *testing.T) {
+		t.Parallel()
+		var gotType, gotID, gotPriv string
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, securableType, securableID, privilege string) (bool, error) {
+				gotType, gotID, gotPriv = securableType, securableID, privilege
+				return true, nil
+			},
+		}
+		repo := &mockCatalogRepo{
+			CompactTableFn: func(_ context.Context, _, _ string) (*domain.TableMaintenanceResult, error) {
+				return &domain.TableMaintenanceResult{Operation: domain.TableMaintenanceCompact, FilesBefore: 12, FilesAfter: 1}, nil
+			},
+		}
+		ensureCatalogLookupDefaults(repo, "main", "events")
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+
+		res, err := svc.CompactTable(context.Background(), "lake", "alice", "main", "events")
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), res.FilesBefore)
+		assert.Equal(t, int64(1), res.FilesAfter)
+		assert.Equal(t, domain.SecurableTable, gotType)
+		assert.Equal(t, "table-1", gotID)
+		assert.Equal(t, domain.PrivModify, gotPriv)
+		assert.True(t, audit.HasAction("COMPACT_TABLE"))
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		t.Parallel()
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, _ string, _ string, _ string) (bool, error) {
+				return false, nil
+			},
+		}
+		repo := &mockCatalogRepo{}
+		ensureCatalogLookupDefaults(repo, "main", "events")
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+
+		_, err := svc.CompactTable(context.Background(), "lake", "bob", "main", "events")
+		var ade *domain.AccessDeniedError
+		require.ErrorAs(t, err, &ade)
+		assert.True(t, audit.HasAction("COMPACT_TABLE"))
+	})
+}
+
+// stubModelRepo lists a fixed set of models.
+type stubModelRepo struct {
+	domain.ModelRepository
+	models []domain.Model
+}
+
+func (r stubModelRepo) ListAll(_ context.Context) ([]domain.Model, error) {
+	return r.models, nil
+}
+
+func TestCatalogService_RollbackTable(t *testing.T) {
+	t.Parallel()
+
+	snap := int64(7)
+
+	t.Run("requires MANAGE and reports dependents", func(t *testing.T) {
+		t.Parallel()
+		var gotType, gotID, gotPriv string
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, securableType, securableID, privilege string) (bool, error) {
+				gotType, gotID, gotPriv = securableType, securableID, privilege
+				return true, nil
+			},
+		}
+		repo := &mockCatalogRepo{
+			RollbackTableFn: func(_ context.Context, _, _ string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+				return &domain.TableRollbackResult{CurrentSnapshot: 9, TargetSnapshot: *req.SnapshotID, RowsBefore: 5, RowsAfter: 3}, nil
+			},
+			ListSchemasFn: func(_ context.Context, _ *string, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+				return []domain.SchemaDetail{{SchemaID: "1", Name: "main"}, {SchemaID: "2", Name: "reporting"}}, 2, nil
+			},
+		}
+		ensureCatalogLookupDefaults(repo, "main", "orders")
+		views := &mockViewRepo{
+			ListFn: func(_ context.Context, schemaID string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+				if schemaID == "1" {
+					return []domain.ViewDetail{{Name: "unrelated", ViewDefinition: "SELECT * FROM main.customers"}}, 1, nil
+				}
+				return []domain.ViewDetail{
+					{Name: "daily_orders", ViewDefinition: "SELECT * FROM lake.main.orders"},
+					{Name: "other_orders", ViewDefinition: "SELECT * FROM staging.orders"},
+				}, 2, nil
+			},
+		}
+		models := stubModelRepo{models: []domain.Model{
+			{ProjectName: "sales", Name: "stg_orders", SQL: "SELECT * FROM {{ source('main', 'orders') }}", DependsOn: []string{"source:main.orders"}},
+			{ProjectName: "sales", Name: "fct_orders", SQL: "SELECT * FROM main.orders JOIN main.customers USING (id)"},
+			{ProjectName: "sales", Name: "dim_customers", SQL: "SELECT * FROM main.customers"},
+		}}
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+		svc.SetDependencyRepos(views, models, nil)
+
+		res, err := svc.RollbackTable(context.Background(), "lake", "alice", "main", "orders", domain.RollbackTableRequest{SnapshotID: &snap})
+		require.NoError(t, err)
+		assert.Equal(t, domain.SecurableTable, gotType)
+		assert.Equal(t, "table-1", gotID)
+		assert.Equal(t, domain.PrivManage, gotPriv)
+		assert.Equal(t, int64(7), res.TargetSnapshot)
+		assert.Equal(t, []domain.DependentObject{
+			{Type: domain.DependentObjectView, Name: "reporting.daily_orders"},
+			{Type: domain.DependentObjectModel, Name: "sales.fct_orders"},
+			{Type: domain.DependentObjectModel, Name: "sales.stg_orders"},
+		}, res.Dependents)
+		assert.False(t, audit.HasAction("ROLLBACK_TABLE"), "a preview is not audited")
+	})
+
+	t.Run("confirmed rollback is audited and recorded in lineage", func(t *testing.T) {
+		t.Parallel()
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, _, _, _ string) (bool, error) { return true, nil },
+		}
+		newSnap := int64(10)
+		repo := &mockCatalogRepo{
+			RollbackTableFn: func(_ context.Context, _, _ string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+				require.True(t, req.Confirm)
+				return &domain.TableRollbackResult{CurrentSnapshot: 9, TargetSnapshot: 7, NewSnapshot: &newSnap, RowsBefore: 5, RowsAfter: 3, Applied: true}, nil
+			},
+		}
+		ensureCatalogLookupDefaults(repo, "main", "orders")
+		var edge *domain.LineageEdge
+		lineage := &testutil.MockLineageRepo{
+			InsertEdgeFn: func(_ context.Context, e *domain.LineageEdge) error {
+				edge = e
+				return nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+		svc.SetDependencyRepos(nil, nil, lineage)
+
+		res, err := svc.RollbackTable(context.Background(), "lake", "alice", "main", "orders", domain.RollbackTableRequest{SnapshotID: &snap, Confirm: true})
+		require.NoError(t, err)
+		assert.True(t, res.Applied)
+		assert.Empty(t, res.Dependents)
+		assert.True(t, audit.HasAction("ROLLBACK_TABLE"))
+		require.NotNil(t, edge)
+		assert.Equal(t, "WRITE", edge.EdgeType)
+		assert.Equal(t, "orders", edge.SourceTable)
+		assert.Equal(t, "main", edge.TargetSchema)
+		assert.Equal(t, "alice", edge.PrincipalName)
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		t.Parallel()
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, _, _, _ string) (bool, error) { return false, nil },
+		}
+		repo := &mockCatalogRepo{}
+		ensureCatalogLookupDefaults(repo, "main", "orders")
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+
+		_, err := svc.RollbackTable(context.Background(), "lake", "bob", "main", "orders", domain.RollbackTableRequest{SnapshotID: &snap, Confirm: true})
+		var ade *domain.AccessDeniedError
+		require.ErrorAs(t, err, &ade)
+		assert.True(t, audit.HasAction("ROLLBACK_TABLE"))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		t.Parallel()
+		svc := newTestCatalogService(&mockCatalogRepo{}, &mockAuthService{}, &mockAuditRepo{}, &mockTagRepo{}, &mockStatsRepo{}, nil)
+
+		_, err := svc.RollbackTable(context.Background(), "lake", "alice", "main", "orders", domain.RollbackTableRequest{})
+		var ve *domain.ValidationError
+		require.ErrorAs(t, err, &ve)
+	})
+}
+
+func TestCatalogService_VacuumTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires MANAGE on the catalog", func(t *testing.T) {
+		t.Parallel()
+		var gotType, gotID, gotPriv string
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, securableType, securableID, privilege string) (bool, error) {
+				gotType, gotID, gotPriv = securableType, securableID, privilege
+				return true, nil
+			},
+		}
+		var gotOlderThan time.Duration
+		repo := &mockCatalogRepo{
+			VacuumTableFn: func(_ context.Context, _, _ string, olderThan time.Duration) (*domain.TableMaintenanceResult, error) {
+				gotOlderThan = olderThan
+				return &domain.TableMaintenanceResult{Operation: domain.TableMaintenanceVacuum, SnapshotsExpired: 3}, nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+
+		res, err := svc.VacuumTable(context.Background(), "lake", "admin", "main", "events", 48*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), res.SnapshotsExpired)
+		assert.Equal(t, 48*time.Hour, gotOlderThan)
+		assert.Equal(t, domain.SecurableCatalog, gotType)
+		assert.Equal(t, "lake", gotID)
+		assert.Equal(t, domain.PrivManage, gotPriv)
+		assert.True(t, audit.HasAction("VACUUM_TABLE"))
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		t.Parallel()
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, _ string, _ string, _ string) (bool, error) {
+				return false, nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		svc := newTestCatalogService(&mockCatalogRepo{}, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+
+		_, err := svc.VacuumTable(context.Background(), "lake", "bob", "main", "events", domain.DefaultVacuumOlderThan)
+		var ade *domain.AccessDeniedError
+		require.ErrorAs(t, err, &ade)
+		assert.True(t, audit.HasAction("VACUUM_TABLE"))
+	})
+}
+
 // === ListSchemas ===
 
 func TestCatalogService_ListSchemas(t *testing.T) {
@@ -863,7 +1297,7 @@ func TestCatalogService_ListSchemas(t *testing.T) {
 		t.Parallel()
 
 		repo := &mockCatalogRepo{
-			ListSchemasFn: func(_ context.Context, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+			ListSchemasFn: func(_ context.Context, _ *string, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
 				return []domain.SchemaDetail{
 					{SchemaID: "1", Name: "main"},
 					{SchemaID: "2", Name: "analytics"},
@@ -877,7 +1311,7 @@ func TestCatalogService_ListSchemas(t *testing.T) {
 		}
 		svc := newTestCatalogService(repo, &mockAuthService{}, &mockAuditRepo{}, tags, &mockStatsRepo{}, nil)
 
-		schemas, total, err := svc.ListSchemas(context.Background(), "lake", domain.PageRequest{MaxResults: 100})
+		schemas, total, err := svc.ListSchemas(context.Background(), "lake", nil, domain.PageRequest{MaxResults: 100})
 
 		require.NoError(t, err)
 		assert.Equal(t, int64(2), total)
@@ -925,7 +1359,7 @@ func TestCatalogService_ListTables(t *testing.T) {
 		t.Parallel()
 
 		repo := &mockCatalogRepo{
-			ListTablesFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.TableDetail, int64, error) {
+			ListTablesFn: func(_ context.Context, _ string, _ *string, _ domain.PageRequest) ([]domain.TableDetail, int64, error) {
 				return []domain.TableDetail{
 					{TableID: "1", Name: "events", SchemaName: "main"},
 				}, 1, nil
@@ -944,7 +1378,7 @@ func TestCatalogService_ListTables(t *testing.T) {
 		}
 		svc := newTestCatalogService(repo, &mockAuthService{}, &mockAuditRepo{}, tags, stats, nil)
 
-		tables, total, err := svc.ListTables(context.Background(), "lake", "main", domain.PageRequest{MaxResults: 100})
+		tables, total, err := svc.ListTables(context.Background(), "lake", "main", nil, domain.PageRequest{MaxResults: 100})
 
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), total)
@@ -0,0 +1,315 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"duck-demo/internal/domain"
+)
+
+// allPolicies bounds how many policies of one kind a copy reads from a table.
+var allPolicies = domain.PageRequest{MaxResults: 10000}
+
+// SetPolicyRepos sets the row filter and column mask repositories that
+// CopyTablePolicies reads and writes. Grants go through the repository set by
+// SetDefaultGrants.
+func (s *CatalogService) SetPolicyRepos(rowFilters domain.RowFilterRepository, columnMasks domain.ColumnMaskRepository) {
+	s.rowFilters = rowFilters
+	s.columnMasks = columnMasks
+}
+
+// CopyTablePolicies copies a table's grants, row filters, column masks and
+// tags to another table in the same catalog, so a table modeled on an
+// existing one starts out governed the same way. Column masks and column tags
+// are copied only to columns the target also has; the rest are reported as
+// skipped. Policies the target already has are left alone, so a copy can be
+// repeated. Policies attached through tags follow the copied tags. With
+// req.DryRun nothing is written. Requires admin privileges, like creating the
+// policies one by one.
+func (s *CatalogService) CopyTablePolicies(ctx context.Context, catalogName string, principal string, schemaName, tableName string, req domain.CopyPoliciesRequest) (*domain.CopyPoliciesResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.TargetSchema == schemaName && req.TargetTable == tableName {
+		return nil, domain.ErrValidation("source and target are the same table")
+	}
+	if caller, _ := domain.PrincipalFromContext(ctx); !caller.IsAdmin {
+		s.logAuditDenied(ctx, principal, "COPY_TABLE_POLICIES", fmt.Sprintf("Denied copy policies from %q.%q to %q.%q", schemaName, tableName, req.TargetSchema, req.TargetTable))
+		return nil, domain.ErrAccessDenied("admin privileges required to copy policies")
+	}
+	if s.grants == nil || s.rowFilters == nil || s.columnMasks == nil || s.tags == nil {
+		return nil, domain.ErrValidation("policy copying is not configured")
+	}
+
+	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	src, err := repo.GetTable(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := repo.GetTable(ctx, req.TargetSchema, req.TargetTable)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &policyCopy{
+		svc:       s,
+		principal: principal,
+		src:       src,
+		dst:       dst,
+		dryRun:    req.DryRun,
+		columns:   make(map[string]bool, len(dst.Columns)),
+		result: &domain.CopyPoliciesResult{
+			Source: schemaName + "." + tableName,
+			Target: req.TargetSchema + "." + req.TargetTable,
+			DryRun: req.DryRun,
+		},
+	}
+	for _, col := range dst.Columns {
+		c.columns[strings.ToLower(col.Name)] = true
+	}
+	for _, step := range []func(context.Context) error{c.grants, c.rowFilters, c.columnMasks, c.tags} {
+		if err := step(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if !req.DryRun {
+		s.logAudit(ctx, principal, "COPY_TABLE_POLICIES", fmt.Sprintf("Copied %d policies from %q.%q to %q.%q, %d skipped",
+			len(c.result.Copied), schemaName, tableName, req.TargetSchema, req.TargetTable, len(c.result.Skipped)))
+	}
+	return c.result, nil
+}
+
+// policyCopy carries the state of one CopyTablePolicies call. Listing a
+// table's policies fails the copy; failing to create one is reported as
+// skipped so the caller sees what did get copied.
+type policyCopy struct {
+	svc       *CatalogService
+	principal string
+	src, dst  *domain.TableDetail
+	dryRun    bool
+	columns   map[string]bool // lower-cased target column names
+	result    *domain.CopyPoliciesResult
+}
+
+func (c *policyCopy) copied(kind, desc string) {
+	c.result.Copied = append(c.result.Copied, domain.CopiedPolicy{Kind: kind, Description: desc})
+}
+
+func (c *policyCopy) skipped(kind, desc, reason string) {
+	c.result.Skipped = append(c.result.Skipped, domain.SkippedPolicy{Kind: kind, Description: desc, Reason: reason})
+}
+
+func (c *policyCopy) hasColumn(name string) bool {
+	return c.columns[strings.ToLower(name)]
+}
+
+func (c *policyCopy) grants(ctx context.Context) error {
+	grants, _, err := c.svc.grants.ListForSecurable(ctx, domain.SecurableTable, c.src.TableID, allPolicies)
+	if err != nil {
+		return fmt.Errorf("list grants: %w", err)
+	}
+	existing, _, err := c.svc.grants.ListForSecurable(ctx, domain.SecurableTable, c.dst.TableID, allPolicies)
+	if err != nil {
+		return fmt.Errorf("list target grants: %w", err)
+	}
+	have := make(map[string]bool, len(existing))
+	for _, g := range existing {
+		have[g.PrincipalType+"/"+g.PrincipalID+"/"+g.Privilege] = true
+	}
+	for _, g := range grants {
+		if have[g.PrincipalType+"/"+g.PrincipalID+"/"+g.Privilege] {
+			continue
+		}
+		desc := fmt.Sprintf("%s to %s %s", g.Privilege, g.PrincipalType, g.PrincipalID)
+		if !c.dryRun {
+			_, err := c.svc.grants.Grant(ctx, &domain.PrivilegeGrant{
+				PrincipalID:   g.PrincipalID,
+				PrincipalType: g.PrincipalType,
+				SecurableType: domain.SecurableTable,
+				SecurableID:   c.dst.TableID,
+				Privilege:     g.Privilege,
+				GrantedBy:     &c.principal,
+				ExpiresAt:     g.ExpiresAt,
+			})
+			if err != nil {
+				c.skipped(domain.PolicyKindGrant, desc, err.Error())
+				continue
+			}
+		}
+		c.copied(domain.PolicyKindGrant, desc)
+	}
+	return nil
+}
+
+func (c *policyCopy) rowFilters(ctx context.Context) error {
+	filters, _, err := c.svc.rowFilters.GetForTable(ctx, c.src.TableID, allPolicies)
+	if err != nil {
+		return fmt.Errorf("list row filters: %w", err)
+	}
+	existing, _, err := c.svc.rowFilters.GetForTable(ctx, c.dst.TableID, allPolicies)
+	if err != nil {
+		return fmt.Errorf("list target row filters: %w", err)
+	}
+	have := make(map[string]bool, len(existing))
+	for _, f := range existing {
+		have[f.FilterSQL] = true
+	}
+	for _, f := range filters {
+		if f.TagID != nil || have[f.FilterSQL] {
+			continue
+		}
+		desc := f.FilterSQL
+		if c.dryRun {
+			c.copied(domain.PolicyKindRowFilter, desc)
+			continue
+		}
+		bindings, err := c.svc.rowFilters.ListBindings(ctx, f.ID)
+		if err != nil {
+			return fmt.Errorf("list row filter bindings: %w", err)
+		}
+		created, err := c.svc.rowFilters.Create(ctx, &domain.RowFilter{TableID: c.dst.TableID, FilterSQL: f.FilterSQL, Description: f.Description})
+		if err != nil {
+			c.skipped(domain.PolicyKindRowFilter, desc, err.Error())
+			continue
+		}
+		for _, b := range bindings {
+			if err := c.svc.rowFilters.Bind(ctx, &domain.RowFilterBinding{RowFilterID: created.ID, PrincipalID: b.PrincipalID, PrincipalType: b.PrincipalType}); err != nil {
+				return fmt.Errorf("bind row filter: %w", err)
+			}
+		}
+		c.copied(domain.PolicyKindRowFilter, desc)
+	}
+	return nil
+}
+
+func (c *policyCopy) columnMasks(ctx context.Context) error {
+	masks, _, err := c.svc.columnMasks.GetForTable(ctx, c.src.TableID, allPolicies)
+	if err != nil {
+		return fmt.Errorf("list column masks: %w", err)
+	}
+	existing, _, err := c.svc.columnMasks.GetForTable(ctx, c.dst.TableID, allPolicies)
+	if err != nil {
+		return fmt.Errorf("list target column masks: %w", err)
+	}
+	have := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		have[strings.ToLower(m.ColumnName)+"/"+m.MaskExpression] = true
+	}
+	for _, m := range masks {
+		if m.TagID != nil || have[strings.ToLower(m.ColumnName)+"/"+m.MaskExpression] {
+			continue
+		}
+		desc := fmt.Sprintf("%s: %s", m.ColumnName, m.MaskExpression)
+		if !c.hasColumn(m.ColumnName) {
+			c.skipped(domain.PolicyKindColumnMask, desc, fmt.Sprintf("column %q does not exist on the target", m.ColumnName))
+			continue
+		}
+		if c.dryRun {
+			c.copied(domain.PolicyKindColumnMask, desc)
+			continue
+		}
+		bindings, err := c.svc.columnMasks.ListBindings(ctx, m.ID)
+		if err != nil {
+			return fmt.Errorf("list column mask bindings: %w", err)
+		}
+		created, err := c.svc.columnMasks.Create(ctx, &domain.ColumnMask{
+			TableID:        c.dst.TableID,
+			ColumnName:     m.ColumnName,
+			MaskExpression: m.MaskExpression,
+			Description:    m.Description,
+		})
+		if err != nil {
+			c.skipped(domain.PolicyKindColumnMask, desc, err.Error())
+			continue
+		}
+		for _, b := range bindings {
+			err := c.svc.columnMasks.Bind(ctx, &domain.ColumnMaskBinding{
+				ColumnMaskID:  created.ID,
+				PrincipalID:   b.PrincipalID,
+				PrincipalType: b.PrincipalType,
+				SeeOriginal:   b.SeeOriginal,
+			})
+			if err != nil {
+				return fmt.Errorf("bind column mask: %w", err)
+			}
+		}
+		c.copied(domain.PolicyKindColumnMask, desc)
+	}
+	return nil
+}
+
+// tags copies the table's own tags and the tags on each of its columns.
+func (c *policyCopy) tags(ctx context.Context) error {
+	if err := c.copyTags(ctx, domain.TagSecurableTypeTable, nil); err != nil {
+		return err
+	}
+	for _, col := range c.src.Columns {
+		if err := c.copyTags(ctx, domain.TagSecurableTypeColumn, &col.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *policyCopy) copyTags(ctx context.Context, securableType string, column *string) error {
+	tags, err := c.svc.tags.ListTagsForSecurable(ctx, securableType, c.src.TableID, column)
+	if err != nil {
+		return fmt.Errorf("list tags: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	if column != nil && !c.hasColumn(*column) {
+		for _, t := range tags {
+			c.skipped(domain.PolicyKindTag, tagOnColumn(t, column), fmt.Sprintf("column %q does not exist on the target", *column))
+		}
+		return nil
+	}
+	existing, err := c.svc.tags.ListTagsForSecurable(ctx, securableType, c.dst.TableID, column)
+	if err != nil {
+		return fmt.Errorf("list target tags: %w", err)
+	}
+	have := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		have[t.ID] = true
+	}
+	for _, t := range tags {
+		if have[t.ID] {
+			continue
+		}
+		desc := tagOnColumn(t, column)
+		if !c.dryRun {
+			_, err := c.svc.tags.AssignTag(ctx, &domain.TagAssignment{
+				TagID:         t.ID,
+				SecurableType: securableType,
+				SecurableID:   c.dst.TableID,
+				ColumnName:    column,
+				AssignedBy:    c.principal,
+			})
+			if err != nil {
+				c.skipped(domain.PolicyKindTag, desc, err.Error())
+				continue
+			}
+		}
+		c.copied(domain.PolicyKindTag, desc)
+	}
+	return nil
+}
+
+// tagOnColumn describes a tag as key or key:value, followed by the column it
+// is on, if any.
+func tagOnColumn(t domain.Tag, column *string) string {
+	desc := t.Key
+	if t.Value != nil {
+		desc += ":" + *t.Value
+	}
+	if column != nil {
+		desc += " on column " + *column
+	}
+	return desc
+}
@@ -0,0 +1,307 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// memGrantRepo keeps grants in memory for ListForSecurable and Grant.
+type memGrantRepo struct {
+	domain.GrantRepository
+	grants []domain.PrivilegeGrant
+}
+
+func (r *memGrantRepo) ListForSecurable(_ context.Context, securableType, securableID string, _ domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
+	var out []domain.PrivilegeGrant
+	for _, g := range r.grants {
+		if g.SecurableType == securableType && g.SecurableID == securableID {
+			out = append(out, g)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (r *memGrantRepo) Grant(_ context.Context, g *domain.PrivilegeGrant) (*domain.PrivilegeGrant, error) {
+	r.grants = append(r.grants, *g)
+	return g, nil
+}
+
+// memRowFilterRepo keeps row filters and their bindings in memory.
+type memRowFilterRepo struct {
+	domain.RowFilterRepository
+	filters  []domain.RowFilter
+	bindings []domain.RowFilterBinding
+}
+
+func (r *memRowFilterRepo) GetForTable(_ context.Context, tableID string, _ domain.PageRequest) ([]domain.RowFilter, int64, error) {
+	var out []domain.RowFilter
+	for _, f := range r.filters {
+		if f.TableID == tableID {
+			out = append(out, f)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (r *memRowFilterRepo) Create(_ context.Context, f *domain.RowFilter) (*domain.RowFilter, error) {
+	f.ID = "rf-" + f.TableID
+	r.filters = append(r.filters, *f)
+	return f, nil
+}
+
+func (r *memRowFilterRepo) ListBindings(_ context.Context, filterID string) ([]domain.RowFilterBinding, error) {
+	var out []domain.RowFilterBinding
+	for _, b := range r.bindings {
+		if b.RowFilterID == filterID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRowFilterRepo) Bind(_ context.Context, b *domain.RowFilterBinding) error {
+	r.bindings = append(r.bindings, *b)
+	return nil
+}
+
+// memColumnMaskRepo keeps column masks and their bindings in memory.
+type memColumnMaskRepo struct {
+	domain.ColumnMaskRepository
+	masks    []domain.ColumnMask
+	bindings []domain.ColumnMaskBinding
+}
+
+func (r *memColumnMaskRepo) GetForTable(_ context.Context, tableID string, _ domain.PageRequest) ([]domain.ColumnMask, int64, error) {
+	var out []domain.ColumnMask
+	for _, m := range r.masks {
+		if m.TableID == tableID {
+			out = append(out, m)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (r *memColumnMaskRepo) Create(_ context.Context, m *domain.ColumnMask) (*domain.ColumnMask, error) {
+	m.ID = "cm-" + m.TableID + "-" + m.ColumnName
+	r.masks = append(r.masks, *m)
+	return m, nil
+}
+
+func (r *memColumnMaskRepo) ListBindings(_ context.Context, maskID string) ([]domain.ColumnMaskBinding, error) {
+	var out []domain.ColumnMaskBinding
+	for _, b := range r.bindings {
+		if b.ColumnMaskID == maskID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (r *memColumnMaskRepo) Bind(_ context.Context, b *domain.ColumnMaskBinding) error {
+	r.bindings = append(r.bindings, *b)
+	return nil
+}
+
+// newMemTagRepo serves tag assignments from memory through a mockTagRepo.
+func newMemTagRepo(tags map[string]domain.Tag, assignments *[]domain.TagAssignment) *mockTagRepo {
+	return &mockTagRepo{
+		ListTagsForSecurableFn: func(_ context.Context, securableType, securableID string, columnName *string) ([]domain.Tag, error) {
+			var out []domain.Tag
+			for _, a := range *assignments {
+				if a.SecurableType != securableType || a.SecurableID != securableID {
+					continue
+				}
+				if columnName != nil && (a.ColumnName == nil || *a.ColumnName != *columnName) {
+					continue
+				}
+				out = append(out, tags[a.TagID])
+			}
+			return out, nil
+		},
+		AssignTagFn: func(_ context.Context, a *domain.TagAssignment) (*domain.TagAssignment, error) {
+			*assignments = append(*assignments, *a)
+			return a, nil
+		},
+	}
+}
+
+type policyCopyFixture struct {
+	svc         *CatalogService
+	audit       *mockAuditRepo
+	grants      *memGrantRepo
+	rowFilters  *memRowFilterRepo
+	columnMasks *memColumnMaskRepo
+	assignments []domain.TagAssignment
+}
+
+// newPolicyCopyFixture sets up analytics.orders (id, email, ssn) with one of
+// each policy kind, and analytics.orders_v2 (id, email) without any.
+func newPolicyCopyFixture() *policyCopyFixture {
+	tables := map[string]*domain.TableDetail{
+		"orders": {TableID: "t-orders", Name: "orders", SchemaName: "analytics", Columns: []domain.ColumnDetail{
+			{Name: "id"}, {Name: "email"}, {Name: "ssn"},
+		}},
+		"orders_v2": {TableID: "t-orders-v2", Name: "orders_v2", SchemaName: "analytics", Columns: []domain.ColumnDetail{
+			{Name: "id"}, {Name: "EMAIL"},
+		}},
+	}
+	repo := &mockCatalogRepo{
+		GetTableFn: func(_ context.Context, _ string, tableName string) (*domain.TableDetail, error) {
+			if t, ok := tables[tableName]; ok {
+				return t, nil
+			}
+			return nil, domain.ErrNotFound("table %q not found", tableName)
+		},
+	}
+	value := "email"
+	email := "email"
+	ssn := "ssn"
+	f := &policyCopyFixture{
+		audit: &mockAuditRepo{},
+		grants: &memGrantRepo{grants: []domain.PrivilegeGrant{
+			{PrincipalID: "analysts-id", PrincipalType: "group", SecurableType: domain.SecurableTable, SecurableID: "t-orders", Privilege: domain.PrivSelect},
+		}},
+		rowFilters: &memRowFilterRepo{
+			filters:  []domain.RowFilter{{ID: "rf-1", TableID: "t-orders", FilterSQL: "region = 'EU'"}},
+			bindings: []domain.RowFilterBinding{{RowFilterID: "rf-1", PrincipalID: "analysts-id", PrincipalType: "group"}},
+		},
+		columnMasks: &memColumnMaskRepo{
+			masks: []domain.ColumnMask{
+				{ID: "cm-1", TableID: "t-orders", ColumnName: "email", MaskExpression: "'***'"},
+				{ID: "cm-2", TableID: "t-orders", ColumnName: "ssn", MaskExpression: "NULL"},
+			},
+			bindings: []domain.ColumnMaskBinding{{ColumnMaskID: "cm-1", PrincipalID: "analysts-id", PrincipalType: "group", SeeOriginal: false}},
+		},
+		assignments: []domain.TagAssignment{
+			{TagID: "tag-gold", SecurableType: domain.TagSecurableTypeTable, SecurableID: "t-orders"},
+			{TagID: "tag-pii", SecurableType: domain.TagSecurableTypeColumn, SecurableID: "t-orders", ColumnName: &email},
+			{TagID: "tag-pii", SecurableType: domain.TagSecurableTypeColumn, SecurableID: "t-orders", ColumnName: &ssn},
+		},
+	}
+	tags := newMemTagRepo(map[string]domain.Tag{
+		"tag-gold": {ID: "tag-gold", Key: "tier"},
+		"tag-pii":  {ID: "tag-pii", Key: "pii", Value: &value},
+	}, &f.assignments)
+	f.svc = newTestCatalogService(repo, &mockAuthService{}, f.audit, tags, &mockStatsRepo{}, nil)
+	f.svc.SetDefaultGrants(nil, f.grants)
+	f.svc.SetPolicyRepos(f.rowFilters, f.columnMasks)
+	return f
+}
+
+func adminCtx() context.Context {
+	return domain.WithPrincipal(context.Background(), domain.ContextPrincipal{Name: "admin", Type: "user", IsAdmin: true})
+}
+
+func TestCatalogService_CopyTablePolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replicates policies to the target", func(t *testing.T) {
+		t.Parallel()
+		f := newPolicyCopyFixture()
+
+		res, err := f.svc.CopyTablePolicies(adminCtx(), "lake", "admin", "analytics", "orders",
+			domain.CopyPoliciesRequest{TargetSchema: "analytics", TargetTable: "orders_v2"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "analytics.orders", res.Source)
+		assert.Equal(t, "analytics.orders_v2", res.Target)
+		assert.Equal(t, []domain.CopiedPolicy{
+			{Kind: domain.PolicyKindGrant, Description: "SELECT to group analysts-id"},
+			{Kind: domain.PolicyKindRowFilter, Description: "region = 'EU'"},
+			{Kind: domain.PolicyKindColumnMask, Description: "email: '***'"},
+			{Kind: domain.PolicyKindTag, Description: "tier"},
+			{Kind: domain.PolicyKindTag, Description: "pii:email on column email"},
+		}, res.Copied)
+		assert.Equal(t, []domain.SkippedPolicy{
+			{Kind: domain.PolicyKindColumnMask, Description: "ssn: NULL", Reason: `column "ssn" does not exist on the target`},
+			{Kind: domain.PolicyKindTag, Description: "pii:email on column ssn", Reason: `column "ssn" does not exist on the target`},
+		}, res.Skipped)
+
+		granted, _, _ := f.grants.ListForSecurable(context.Background(), domain.SecurableTable, "t-orders-v2", allPolicies)
+		require.Len(t, granted, 1)
+		assert.Equal(t, "analysts-id", granted[0].PrincipalID)
+		assert.Equal(t, domain.PrivSelect, granted[0].Privilege)
+
+		filters, _, _ := f.rowFilters.GetForTable(context.Background(), "t-orders-v2", allPolicies)
+		require.Len(t, filters, 1)
+		filterBindings, _ := f.rowFilters.ListBindings(context.Background(), filters[0].ID)
+		assert.Len(t, filterBindings, 1, "row filter bindings are copied")
+
+		masks, _, _ := f.columnMasks.GetForTable(context.Background(), "t-orders-v2", allPolicies)
+		require.Len(t, masks, 1)
+		assert.Equal(t, "email", masks[0].ColumnName)
+		maskBindings, _ := f.columnMasks.ListBindings(context.Background(), masks[0].ID)
+		assert.Len(t, maskBindings, 1, "column mask bindings are copied")
+
+		assert.Len(t, f.assignments, 5)
+		assert.Equal(t, "COPY_TABLE_POLICIES", f.audit.LastEntry().Action)
+	})
+
+	t.Run("repeating the copy changes nothing", func(t *testing.T) {
+		t.Parallel()
+		f := newPolicyCopyFixture()
+		req := domain.CopyPoliciesRequest{TargetSchema: "analytics", TargetTable: "orders_v2"}
+		_, err := f.svc.CopyTablePolicies(adminCtx(), "lake", "admin", "analytics", "orders", req)
+		require.NoError(t, err)
+
+		res, err := f.svc.CopyTablePolicies(adminCtx(), "lake", "admin", "analytics", "orders", req)
+		require.NoError(t, err)
+		assert.Empty(t, res.Copied)
+		assert.Len(t, f.grants.grants, 2)
+		assert.Len(t, f.assignments, 5)
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		t.Parallel()
+		f := newPolicyCopyFixture()
+
+		res, err := f.svc.CopyTablePolicies(adminCtx(), "lake", "admin", "analytics", "orders",
+			domain.CopyPoliciesRequest{TargetSchema: "analytics", TargetTable: "orders_v2", DryRun: true})
+		require.NoError(t, err)
+
+		assert.True(t, res.DryRun)
+		assert.Len(t, res.Copied, 5)
+		assert.Len(t, res.Skipped, 2)
+		assert.Len(t, f.grants.grants, 1)
+		assert.Len(t, f.rowFilters.filters, 1)
+		assert.Len(t, f.columnMasks.masks, 2)
+		assert.Len(t, f.assignments, 3)
+		assert.Nil(t, f.audit.LastEntry())
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		t.Parallel()
+		f := newPolicyCopyFixture()
+
+		_, err := f.svc.CopyTablePolicies(ctxWithPrincipal("bob"), "lake", "bob", "analytics", "orders",
+			domain.CopyPoliciesRequest{TargetSchema: "analytics", TargetTable: "orders_v2"})
+		var denied *domain.AccessDeniedError
+		require.ErrorAs(t, err, &denied)
+		assert.Len(t, f.grants.grants, 1)
+	})
+
+	t.Run("rejects copying a table onto itself", func(t *testing.T) {
+		t.Parallel()
+		f := newPolicyCopyFixture()
+
+		_, err := f.svc.CopyTablePolicies(adminCtx(), "lake", "admin", "analytics", "orders",
+			domain.CopyPoliciesRequest{TargetSchema: "analytics", TargetTable: "orders"})
+		var validation *domain.ValidationError
+		require.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		t.Parallel()
+		f := newPolicyCopyFixture()
+
+		_, err := f.svc.CopyTablePolicies(adminCtx(), "lake", "admin", "analytics", "orders",
+			domain.CopyPoliciesRequest{TargetSchema: "analytics", TargetTable: "nope"})
+		var notFound *domain.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+	})
+}
@@ -0,0 +1,58 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"duck-demo/internal/domain"
+)
+
+// SetDefaultGrants configures the default grant templates applied to new
+// schemas and tables, and the grant repository the resulting grants are
+// recorded in. Either may be nil to disable default grants.
+func (s *CatalogService) SetDefaultGrants(defaults domain.DefaultGrantRepository, grants domain.GrantRepository) {
+	s.defaultGrants = defaults
+	s.grants = grants
+}
+
+// applyDefaultGrants records a grant on a newly created object for every
+// default grant matching its catalog, schema and type. The object already
+// exists, so failures are audited rather than returned.
+func (s *CatalogService) applyDefaultGrants(ctx context.Context, principal, catalogName, schemaName, objectType, objectID, objectName string) {
+	if s.defaultGrants == nil || s.grants == nil {
+		return
+	}
+	defaults, err := s.defaultGrants.ListForObject(ctx, catalogName, schemaName, objectType)
+	if err != nil {
+		s.logAuditError(ctx, principal, "APPLY_DEFAULT_GRANTS", fmt.Sprintf("Default grants for %s %q", objectType, objectName), err)
+		return
+	}
+	for _, d := range defaults {
+		grantedBy := d.CreatedBy
+		_, err := s.grants.Grant(ctx, &domain.PrivilegeGrant{
+			PrincipalID:   d.PrincipalID,
+			PrincipalType: d.PrincipalType,
+			SecurableType: objectType,
+			SecurableID:   objectID,
+			Privilege:     d.Privilege,
+			GrantedBy:     &grantedBy,
+		})
+		detail := fmt.Sprintf("Default grant %s on %s %q to %s %s", d.Privilege, objectType, objectName, d.PrincipalType, d.PrincipalID)
+		if err != nil {
+			s.logAuditError(ctx, principal, "APPLY_DEFAULT_GRANT", detail, err)
+			continue
+		}
+		s.logAudit(ctx, principal, "APPLY_DEFAULT_GRANT", detail)
+	}
+}
+
+func (s *CatalogService) logAuditError(ctx context.Context, principal, action, detail string, err error) {
+	msg := err.Error()
+	_ = s.audit.Insert(ctx, &domain.AuditEntry{
+		PrincipalName: principal,
+		Action:        action,
+		Status:        "ERROR",
+		OriginalSQL:   &detail,
+		ErrorMessage:  &msg,
+	})
+}
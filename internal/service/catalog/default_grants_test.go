@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// mockDefaultGrantRepo serves a fixed set of templates from ListForObject,
+// filtered the way the SQLite repository filters them.
+type mockDefaultGrantRepo struct {
+	domain.DefaultGrantRepository
+	templates []domain.DefaultGrant
+}
+
+func (m *mockDefaultGrantRepo) ListForObject(_ context.Context, catalogName, schemaName, objectType string) ([]domain.DefaultGrant, error) {
+	var out []domain.DefaultGrant
+	for _, d := range m.templates {
+		if d.CatalogName == catalogName && d.ObjectType == objectType && (d.SchemaName == "" || d.SchemaName == schemaName) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// recordingGrantRepo records the grants created through Grant.
+type recordingGrantRepo struct {
+	domain.GrantRepository
+	granted []domain.PrivilegeGrant
+	err     error
+}
+
+func (r *recordingGrantRepo) Grant(_ context.Context, g *domain.PrivilegeGrant) (*domain.PrivilegeGrant, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.granted = append(r.granted, *g)
+	return g, nil
+}
+
+func newDefaultGrantTestService(repo *mockCatalogRepo, audit *mockAuditRepo, grants *recordingGrantRepo) *CatalogService {
+	auth := &mockAuthService{CheckPrivilegeFn: func(_ context.Context, _, _ string, _ string, _ string) (bool, error) {
+		return true, nil
+	}}
+	svc := newTestCatalogService(repo, auth, audit, &mockTagRepo{}, &mockStatsRepo{}, nil)
+	svc.SetDefaultGrants(&mockDefaultGrantRepo{templates: []domain.DefaultGrant{
+		{CatalogName: "lake", SchemaName: "analytics", ObjectType: domain.SecurableTable, PrincipalID: "analysts-id", PrincipalType: "group", Privilege: domain.PrivSelect, CreatedBy: "admin"},
+		{CatalogName: "lake", SchemaName: "staging", ObjectType: domain.SecurableTable, PrincipalID: "loaders-id", PrincipalType: "group", Privilege: domain.PrivInsert, CreatedBy: "admin"},
+		{CatalogName: "lake", ObjectType: domain.SecurableSchema, PrincipalID: "analysts-id", PrincipalType: "group", Privilege: domain.PrivUseSchema, CreatedBy: "admin"},
+	}}, grants)
+	return svc
+}
+
+func TestCatalogService_CreateTable_AppliesDefaultGrants(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockCatalogRepo{}
+	ensureCatalogLookupDefaults(repo, "analytics", "")
+	repo.CreateTableFn = func(_ context.Context, schemaName string, req domain.CreateTableRequest, owner string) (*domain.TableDetail, error) {
+		return &domain.TableDetail{TableID: "table-7", Name: req.Name, SchemaName: schemaName, Owner: owner}, nil
+	}
+	audit := &mockAuditRepo{}
+	grants := &recordingGrantRepo{}
+	svc := newDefaultGrantTestService(repo, audit, grants)
+
+	_, err := svc.CreateTable(ctxWithPrincipal("alice"), "lake", "alice", "analytics", domain.CreateTableRequest{Name: "orders"})
+	require.NoError(t, err)
+
+	require.Len(t, grants.granted, 1, "only the analytics template applies")
+	g := grants.granted[0]
+	assert.Equal(t, "analysts-id", g.PrincipalID)
+	assert.Equal(t, "group", g.PrincipalType)
+	assert.Equal(t, domain.SecurableTable, g.SecurableType)
+	assert.Equal(t, "table-7", g.SecurableID)
+	assert.Equal(t, domain.PrivSelect, g.Privilege)
+	require.NotNil(t, g.GrantedBy)
+	assert.Equal(t, "admin", *g.GrantedBy)
+	assert.True(t, audit.HasAction("APPLY_DEFAULT_GRANT"))
+}
+
+func TestCatalogService_CreateSchema_AppliesDefaultGrants(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockCatalogRepo{}
+	repo.CreateSchemaFn = func(_ context.Context, name, _ string, owner string) (*domain.SchemaDetail, error) {
+		return &domain.SchemaDetail{SchemaID: "schema-9", Name: name, Owner: owner}, nil
+	}
+	grants := &recordingGrantRepo{}
+	svc := newDefaultGrantTestService(repo, &mockAuditRepo{}, grants)
+
+	_, err := svc.CreateSchema(ctxWithPrincipal("alice"), "lake", "alice", domain.CreateSchemaRequest{Name: "marts"})
+	require.NoError(t, err)
+
+	require.Len(t, grants.granted, 1)
+	assert.Equal(t, domain.SecurableSchema, grants.granted[0].SecurableType)
+	assert.Equal(t, "schema-9", grants.granted[0].SecurableID)
+	assert.Equal(t, domain.PrivUseSchema, grants.granted[0].Privilege)
+}
+
+func TestCatalogService_CreateTable_DefaultGrantFailureIsAudited(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockCatalogRepo{}
+	ensureCatalogLookupDefaults(repo, "analytics", "")
+	repo.CreateTableFn = func(_ context.Context, schemaName string, req domain.CreateTableRequest, _ string) (*domain.TableDetail, error) {
+		return &domain.TableDetail{TableID: "table-8", Name: req.Name, SchemaName: schemaName}, nil
+	}
+	audit := &mockAuditRepo{}
+	svc := newDefaultGrantTestService(repo, audit, &recordingGrantRepo{err: errTest})
+
+	result, err := svc.CreateTable(ctxWithPrincipal("alice"), "lake", "alice", "analytics", domain.CreateTableRequest{Name: "orders"})
+	require.NoError(t, err, "the table exists even when a default grant fails")
+	assert.Equal(t, "table-8", result.TableID)
+
+	last := audit.LastEntry()
+	require.NotNil(t, last)
+	assert.Equal(t, "APPLY_DEFAULT_GRANT", last.Action)
+	assert.Equal(t, "ERROR", last.Status)
+}
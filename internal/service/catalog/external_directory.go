@@ -0,0 +1,143 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"duck-demo/internal/domain"
+)
+
+// RegisterDirectory registers the files under a storage prefix as external
+// tables, checking CREATE_TABLE on the schema once for the whole batch. Files
+// are listed with the engine's credentials for the location. Without a table
+// name each file directly under the prefix becomes its own table; files whose
+// table cannot be created, such as an existing name, are reported as skipped.
+func (s *CatalogService) RegisterDirectory(ctx context.Context, principal string, req domain.RegisterDirectoryRequest) (*domain.RegisterDirectoryResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.FileFormat == "" {
+		req.FileFormat = "parquet"
+	}
+	if s.locations == nil {
+		return nil, domain.ErrValidation("external locations are not configured")
+	}
+	loc, err := s.locations.GetByName(ctx, req.LocationName)
+	if err != nil {
+		return nil, fmt.Errorf("lookup location %q: %w", req.LocationName, err)
+	}
+	dir := strings.TrimSuffix(req.Path, "/") + "/"
+	if !strings.HasPrefix(dir, loc.URL) {
+		return nil, domain.ErrValidation("path %q is not under location %q (URL: %s)", req.Path, req.LocationName, loc.URL)
+	}
+
+	repo, err := s.repoFactory.ForCatalog(ctx, req.CatalogName)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := repo.GetSchema(ctx, req.SchemaName)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableSchema, schema.SchemaID, domain.PrivCreateTable)
+	if err != nil {
+		return nil, fmt.Errorf("check privilege: %w", err)
+	}
+	if !allowed {
+		s.logAuditDenied(ctx, principal, "REGISTER_DIRECTORY", fmt.Sprintf("Denied registering %q in schema %q", req.Path, req.SchemaName))
+		return nil, domain.ErrAccessDenied("%q lacks CREATE_TABLE privilege", principal)
+	}
+
+	pattern := directoryGlob(dir, req.FileFormat, req.TableName != "")
+	files, err := repo.ListFiles(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, domain.ErrValidation("no %s files found under %q", req.FileFormat, req.Path)
+	}
+
+	result := &domain.RegisterDirectoryResult{}
+	if req.TableName != "" {
+		tbl, err := s.createExternalTable(ctx, req.CatalogName, req.SchemaName, domain.CreateTableRequest{
+			Name:         req.TableName,
+			TableType:    domain.TableTypeExternal,
+			SourcePath:   pattern,
+			FileFormat:   req.FileFormat,
+			LocationName: req.LocationName,
+		}, principal)
+		if err != nil {
+			return nil, err
+		}
+		result.Registered = append(result.Registered, *tbl)
+	} else {
+		seen := make(map[string]bool, len(files))
+		for _, file := range files {
+			name := tableNameForFile(file)
+			if seen[name] {
+				result.Skipped = append(result.Skipped, domain.SkippedFile{Path: file, TableName: name, Reason: "another file maps to the same table name"})
+				continue
+			}
+			seen[name] = true
+
+			tbl, err := s.createExternalTable(ctx, req.CatalogName, req.SchemaName, domain.CreateTableRequest{
+				Name:         name,
+				TableType:    domain.TableTypeExternal,
+				SourcePath:   file,
+				FileFormat:   req.FileFormat,
+				LocationName: req.LocationName,
+			}, principal)
+			switch {
+			case err == nil:
+				result.Registered = append(result.Registered, *tbl)
+			case errors.As(err, new(*domain.ConflictError)), errors.As(err, new(*domain.ValidationError)):
+				result.Skipped = append(result.Skipped, domain.SkippedFile{Path: file, TableName: name, Reason: err.Error()})
+			default:
+				return nil, fmt.Errorf("register %q: %w", file, err)
+			}
+		}
+	}
+
+	s.logAudit(ctx, principal, "REGISTER_DIRECTORY", fmt.Sprintf("Registered %d external tables from %q in schema %q (%d skipped)",
+		len(result.Registered), req.Path, req.SchemaName, len(result.Skipped)))
+	return result, nil
+}
+
+// maxTableNameLen is the longest identifier the DDL builder accepts.
+const maxTableNameLen = 128
+
+// directoryGlob returns the glob matching the files of a format under dir:
+// only those directly in it, or with recursive those at any depth.
+func directoryGlob(dir, fileFormat string, recursive bool) string {
+	if recursive {
+		dir += "**/"
+	}
+	return dir + "*." + fileFormat
+}
+
+// tableNameForFile derives a table name from a file's base name: lowercased,
+// without its extension, and with characters that are not valid in an
+// identifier replaced by underscores.
+func tableNameForFile(file string) string {
+	base := path.Base(file)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "t_" + name
+	}
+	if len(name) > maxTableNameLen {
+		name = name[:maxTableNameLen]
+	}
+	return name
+}
@@ -0,0 +1,163 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+func TestCatalogService_RegisterDirectory(t *testing.T) {
+	t.Parallel()
+
+	landing := func() domain.ExternalLocationRepository {
+		return &mockExternalLocationRepo{
+			GetByNameFn: func(_ context.Context, _ string) (*domain.ExternalLocation, error) {
+				return &domain.ExternalLocation{Name: "landing", URL: "s3://bucket/landing/"}, nil
+			},
+		}
+	}
+	allowAll := func() *mockAuthService {
+		return &mockAuthService{CheckPrivilegeFn: func(_ context.Context, _, _, _, _ string) (bool, error) {
+			return true, nil
+		}}
+	}
+	newRepo := func(files []string, existing map[string]bool) (*mockCatalogRepo, *[]domain.CreateTableRequest) {
+		var created []domain.CreateTableRequest
+		repo := &mockCatalogRepo{
+			ListFilesFn: func(_ context.Context, _ string) ([]string, error) { return files, nil },
+			CreateExternalTableFn: func(_ context.Context, schemaName string, req domain.CreateTableRequest, _ string) (*domain.TableDetail, error) {
+				if existing[req.Name] {
+					return nil, domain.ErrConflict("table %q already exists in schema %q", req.Name, schemaName)
+				}
+				created = append(created, req)
+				return &domain.TableDetail{Name: req.Name, SchemaName: schemaName, TableType: domain.TableTypeExternal, SourcePath: req.SourcePath}, nil
+			},
+		}
+		ensureCatalogLookupDefaults(repo, "raw", "")
+		return repo, &created
+	}
+
+	t.Run("registers one table per file", func(t *testing.T) {
+		t.Parallel()
+		repo, created := newRepo([]string{
+			"s3://bucket/landing/orders.parquet",
+			"s3://bucket/landing/2024-Customers.parquet",
+		}, nil)
+		var pattern string
+		listFiles := repo.ListFilesFn
+		repo.ListFilesFn = func(ctx context.Context, p string) ([]string, error) {
+			pattern = p
+			return listFiles(ctx, p)
+		}
+		svc := newTestCatalogService(repo, allowAll(), &mockAuditRepo{}, &mockTagRepo{}, &mockStatsRepo{}, landing())
+
+		res, err := svc.RegisterDirectory(context.Background(), "alice", domain.RegisterDirectoryRequest{
+			SchemaName: "raw", LocationName: "landing", Path: "s3://bucket/landing",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "s3://bucket/landing/*.parquet", pattern)
+		require.Len(t, res.Registered, 2)
+		assert.Equal(t, "orders", res.Registered[0].Name)
+		assert.Equal(t, "t_2024_customers", res.Registered[1].Name)
+		assert.Empty(t, res.Skipped)
+		for _, req := range *created {
+			assert.Equal(t, "parquet", req.FileFormat)
+			assert.Equal(t, "landing", req.LocationName)
+		}
+	})
+
+	t.Run("existing tables are skipped", func(t *testing.T) {
+		t.Parallel()
+		repo, _ := newRepo([]string{
+			"s3://bucket/landing/orders.csv",
+			"s3://bucket/landing/returns.csv",
+		}, map[string]bool{"orders": true})
+		svc := newTestCatalogService(repo, allowAll(), &mockAuditRepo{}, &mockTagRepo{}, &mockStatsRepo{}, landing())
+
+		res, err := svc.RegisterDirectory(context.Background(), "alice", domain.RegisterDirectoryRequest{
+			SchemaName: "raw", LocationName: "landing", Path: "s3://bucket/landing/", FileFormat: "csv",
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Registered, 1)
+		assert.Equal(t, "returns", res.Registered[0].Name)
+		require.Len(t, res.Skipped, 1)
+		assert.Equal(t, "orders", res.Skipped[0].TableName)
+		assert.Contains(t, res.Skipped[0].Reason, "already exists")
+	})
+
+	t.Run("table name registers one partitioned table", func(t *testing.T) {
+		t.Parallel()
+		repo, created := newRepo([]string{
+			"s3://bucket/landing/events/day=1/a.parquet",
+			"s3://bucket/landing/events/day=2/b.parquet",
+		}, nil)
+		svc := newTestCatalogService(repo, allowAll(), &mockAuditRepo{}, &mockTagRepo{}, &mockStatsRepo{}, landing())
+
+		res, err := svc.RegisterDirectory(context.Background(), "alice", domain.RegisterDirectoryRequest{
+			SchemaName: "raw", LocationName: "landing", Path: "s3://bucket/landing/events/", TableName: "events",
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Registered, 1)
+		require.Len(t, *created, 1)
+		assert.Equal(t, "events", (*created)[0].Name)
+		assert.Equal(t, "s3://bucket/landing/events/**/*.parquet", (*created)[0].SourcePath)
+	})
+
+	t.Run("no matching files is a validation error", func(t *testing.T) {
+		t.Parallel()
+		repo, _ := newRepo(nil, nil)
+		svc := newTestCatalogService(repo, allowAll(), &mockAuditRepo{}, &mockTagRepo{}, &mockStatsRepo{}, landing())
+
+		_, err := svc.RegisterDirectory(context.Background(), "alice", domain.RegisterDirectoryRequest{
+			SchemaName: "raw", LocationName: "landing", Path: "s3://bucket/landing/",
+		})
+		var ve *domain.ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Contains(t, ve.Message, "no parquet files")
+	})
+
+	t.Run("path outside the location is rejected", func(t *testing.T) {
+		t.Parallel()
+		repo, _ := newRepo(nil, nil)
+		svc := newTestCatalogService(repo, allowAll(), &mockAuditRepo{}, &mockTagRepo{}, &mockStatsRepo{}, landing())
+
+		_, err := svc.RegisterDirectory(context.Background(), "alice", domain.RegisterDirectoryRequest{
+			SchemaName: "raw", LocationName: "landing", Path: "s3://other/",
+		})
+		var ve *domain.ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Contains(t, ve.Message, "not under location")
+	})
+
+	t.Run("requires CREATE_TABLE on the schema", func(t *testing.T) {
+		t.Parallel()
+		repo, _ := newRepo(nil, nil)
+		auth := &mockAuthService{CheckPrivilegeFn: func(_ context.Context, _, _, _, _ string) (bool, error) {
+			return false, nil
+		}}
+		svc := newTestCatalogService(repo, auth, &mockAuditRepo{}, &mockTagRepo{}, &mockStatsRepo{}, landing())
+
+		_, err := svc.RegisterDirectory(context.Background(), "bob", domain.RegisterDirectoryRequest{
+			SchemaName: "raw", LocationName: "landing", Path: "s3://bucket/landing/",
+		})
+		var ade *domain.AccessDeniedError
+		require.ErrorAs(t, err, &ade)
+	})
+}
+
+func TestTableNameForFile(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"s3://bucket/landing/orders.parquet":         "orders",
+		"s3://bucket/landing/Daily Sales.csv":        "daily_sales",
+		"s3://bucket/landing/2024-01-orders.parquet": "t_2024_01_orders",
+		"/data/part.0.parquet":                       "part_0",
+	}
+	for file, want := range tests {
+		assert.Equal(t, want, tableNameForFile(file), file)
+	}
+}
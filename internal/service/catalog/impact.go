@@ -0,0 +1,148 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/sqlrewrite"
+)
+
+// tableDependents returns the views and models whose SQL reads the given
+// table. It is best effort: a definition that fails to parse, or a
+// repository that fails to list, is skipped rather than failing the caller.
+func (s *CatalogService) tableDependents(ctx context.Context, repo domain.CatalogRepository, catalogName, schemaName, tableName string) []domain.DependentObject {
+	deps, _ := s.listTableDependents(ctx, repo, catalogName, schemaName, tableName)
+	return deps
+}
+
+// listTableDependents is tableDependents for callers that must not miss a
+// dependent view: it also returns the error from listing views, alongside
+// the dependents it did find.
+func (s *CatalogService) listTableDependents(ctx context.Context, repo domain.CatalogRepository, catalogName, schemaName, tableName string) ([]domain.DependentObject, error) {
+	var deps []domain.DependentObject
+
+	views, viewErr := s.dependentViews(ctx, repo, catalogName, schemaName, tableName)
+	for _, v := range views {
+		deps = append(deps, domain.DependentObject{Type: domain.DependentObjectView, Name: v.SchemaName + "." + v.Name})
+	}
+
+	if s.models != nil {
+		models, err := s.models.ListAll(ctx)
+		if err == nil {
+			for _, m := range models {
+				if modelReadsTable(m, catalogName, schemaName, tableName) {
+					deps = append(deps, domain.DependentObject{Type: domain.DependentObjectModel, Name: m.ProjectName + "." + m.Name})
+				}
+			}
+		}
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Type != deps[j].Type {
+			return deps[i].Type > deps[j].Type // views first
+		}
+		return deps[i].Name < deps[j].Name
+	})
+	return deps, viewErr
+}
+
+func (s *CatalogService) dependentViews(ctx context.Context, repo domain.CatalogRepository, catalogName, schemaName, tableName string) ([]domain.ViewDetail, error) {
+	if s.views == nil {
+		return nil, nil
+	}
+	return dependentViews(ctx, s.views, repo, catalogName, schemaName, tableName)
+}
+
+// dependentViews returns the views, across all schemas of the catalog, whose
+// definition reads the given table or view, with SchemaID and SchemaName
+// set. A definition that fails to parse is skipped; failing to list schemas
+// or views is an error, so callers that must not miss a dependent can refuse.
+func dependentViews(ctx context.Context, viewRepo domain.ViewRepository, repo domain.CatalogRepository, catalogName, schemaName, tableName string) ([]domain.ViewDetail, error) {
+	page := domain.PageRequest{MaxResults: domain.MaxMaxResults}
+	schemas, _, err := repo.ListSchemas(ctx, nil, page)
+	if err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+	var deps []domain.ViewDetail
+	for _, schema := range schemas {
+		views, _, err := viewRepo.List(ctx, schema.SchemaID, nil, page)
+		if err != nil {
+			return nil, fmt.Errorf("list views in schema %q: %w", schema.Name, err)
+		}
+		for _, v := range views {
+			if sqlReadsTable(v.ViewDefinition, catalogName, schemaName, tableName) {
+				v.SchemaID = schema.SchemaID
+				v.SchemaName = schema.Name
+				deps = append(deps, v)
+			}
+		}
+	}
+	return deps, nil
+}
+
+// TableDependents returns the views and models that read the given table,
+// the objects a drop or rollback of it would affect.
+func (s *CatalogService) TableDependents(ctx context.Context, catalogName string, schemaName, tableName string) ([]domain.DependentObject, error) {
+	repo, err := s.repoFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := repo.GetTable(ctx, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	return s.tableDependents(ctx, repo, catalogName, schemaName, tableName), nil
+}
+
+// modelReadsTable reports whether a model declares the table as a source or
+// its SQL references it directly.
+func modelReadsTable(m domain.Model, catalogName, schemaName, tableName string) bool {
+	for _, dep := range m.DependsOn {
+		src, ok := strings.CutPrefix(dep, "source:")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(src, ".")
+		ref := sqlrewrite.TableRef{Name: parts[len(parts)-1]}
+		if len(parts) >= 2 {
+			ref.Schema = parts[len(parts)-2]
+		}
+		if len(parts) >= 3 {
+			ref.Catalog = parts[len(parts)-3]
+		}
+		if refMatchesTable(ref, catalogName, schemaName, tableName) {
+			return true
+		}
+	}
+	// Model SQL may still contain template expressions that do not parse;
+	// DependsOn has already covered those references.
+	return sqlReadsTable(m.SQL, catalogName, schemaName, tableName)
+}
+
+// sqlReadsTable reports whether sql references the table. Unqualified
+// references match by name, since they resolve against a search path the
+// definition does not record.
+func sqlReadsTable(sql, catalogName, schemaName, tableName string) bool {
+	refs, err := sqlrewrite.ExtractTableRefs(sql)
+	if err != nil {
+		return false
+	}
+	for _, ref := range refs {
+		if refMatchesTable(ref, catalogName, schemaName, tableName) {
+			return true
+		}
+	}
+	return false
+}
+
+func refMatchesTable(ref sqlrewrite.TableRef, catalogName, schemaName, tableName string) bool {
+	if !strings.EqualFold(ref.Name, tableName) {
+		return false
+	}
+	if ref.Schema != "" && !strings.EqualFold(ref.Schema, schemaName) {
+		return false
+	}
+	return ref.Catalog == "" || strings.EqualFold(ref.Catalog, catalogName)
+}
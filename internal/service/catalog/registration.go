@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"log/slog"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -28,6 +31,13 @@ type CatalogRegistrationService struct {
 	metastoreFactory    domain.MetastoreQuerierFactory
 	introspectionCloser func(catalogName string) error
 	catalogRepoEvict    func(catalogName string)
+
+	// Startup attach behaviour; see RegistrationServiceDeps.
+	attachAttempts int
+	attachBackoff  time.Duration
+	requireAttach  bool
+
+	events domain.EventPublisher
 }
 
 // RegistrationServiceDeps holds dependencies for CatalogRegistrationService.
@@ -40,11 +50,19 @@ type RegistrationServiceDeps struct {
 	MetastoreFactory   domain.MetastoreQuerierFactory
 	IntrospectionClose func(catalogName string) error
 	CatalogRepoEvict   func(catalogName string)
+
+	// AttachAttempts bounds how often AttachAll tries each catalog (default 3).
+	// Retries wait AttachBackoff (default 1s), doubling after every attempt.
+	AttachAttempts int
+	AttachBackoff  time.Duration
+	// RequireAttach makes AttachAll fail when any catalog cannot be attached
+	// instead of leaving it in ERROR status.
+	RequireAttach bool
 }
 
 // NewCatalogRegistrationService creates a new CatalogRegistrationService.
 func NewCatalogRegistrationService(deps RegistrationServiceDeps) *CatalogRegistrationService {
-	return &CatalogRegistrationService{
+	svc := &CatalogRegistrationService{
 		repo:                deps.Repo,
 		attacher:            deps.Attacher,
 		audit:               deps.Audit,
@@ -53,7 +71,22 @@ func NewCatalogRegistrationService(deps RegistrationServiceDeps) *CatalogRegistr
 		metastoreFactory:    deps.MetastoreFactory,
 		introspectionCloser: deps.IntrospectionClose,
 		catalogRepoEvict:    deps.CatalogRepoEvict,
+		attachAttempts:      deps.AttachAttempts,
+		attachBackoff:       deps.AttachBackoff,
+		requireAttach:       deps.RequireAttach,
+	}
+	if svc.attachAttempts <= 0 {
+		svc.attachAttempts = 3
+	}
+	if svc.attachBackoff <= 0 {
+		svc.attachBackoff = time.Second
 	}
+	return svc
+}
+
+// SetEventPublisher configures where catalog change events are published.
+func (s *CatalogRegistrationService) SetEventPublisher(events domain.EventPublisher) {
+	s.events = events
 }
 
 // Register validates and persists a new catalog, then attempts to ATTACH it.
@@ -91,7 +124,7 @@ func (s *CatalogRegistrationService) Register(ctx context.Context, req domain.Cr
 
 	// Check for duplicate name
 	if _, err := s.repo.GetByName(ctx, req.Name); err == nil {
-		return nil, domain.ErrConflict("catalog %q already exists", req.Name)
+		return nil, domain.ErrAlreadyExists("catalog %q already exists", req.Name)
 	}
 
 	// Persist with status DETACHED
@@ -108,6 +141,7 @@ func (s *CatalogRegistrationService) Register(ctx context.Context, req domain.Cr
 	if err != nil {
 		return nil, fmt.Errorf("create catalog registration: %w", err)
 	}
+	s.publish(ctx, domain.EventCatalogCreated, created)
 
 	// Attempt ATTACH
 	if err := s.attacher.Attach(ctx, *created); err != nil {
@@ -188,6 +222,7 @@ func (s *CatalogRegistrationService) Delete(ctx context.Context, name string) er
 		return fmt.Errorf("delete catalog: %w", err)
 	}
 	s.logAudit(ctx, "DELETE_CATALOG_REGISTRATION")
+	s.publish(ctx, domain.EventCatalogDeleted, existing)
 
 	s.logger.Info("catalog deleted", "catalog", name)
 	return nil
@@ -217,6 +252,17 @@ func (s *CatalogRegistrationService) SetDefault(ctx context.Context, name string
 	return s.repo.GetByID(ctx, existing.ID)
 }
 
+func (s *CatalogRegistrationService) publish(ctx context.Context, eventType string, reg *domain.CatalogRegistration) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(ctx, domain.NewEvent(ctx, eventType, map[string]interface{}{
+		"catalog_id":     reg.ID,
+		"catalog_name":   reg.Name,
+		"metastore_type": string(reg.MetastoreType),
+	}))
+}
+
 func (s *CatalogRegistrationService) logAudit(ctx context.Context, action string) {
 	if s.audit == nil {
 		return
@@ -234,7 +280,11 @@ func (s *CatalogRegistrationService) logAudit(ctx context.Context, action string
 	})
 }
 
-// AttachAll loads all registered catalogs and attaches them concurrently at startup.
+// AttachAll loads all registered catalogs and attaches them concurrently at
+// startup, retrying each with exponential backoff. A catalog that still fails
+// is marked ERROR so catalog-scoped requests report it as unavailable; with
+// RequireAttach set AttachAll instead returns an error naming every such
+// catalog.
 func (s *CatalogRegistrationService) AttachAll(ctx context.Context) error {
 	catalogs, _, err := s.repo.List(ctx, domain.PageRequest{MaxResults: 10000})
 	if err != nil {
@@ -246,19 +296,30 @@ func (s *CatalogRegistrationService) AttachAll(ctx context.Context) error {
 		return nil
 	}
 
+	start := time.Now()
+	var (
+		mu       sync.Mutex
+		failures []string
+	)
 	g, gctx := errgroup.WithContext(ctx)
 	g.SetLimit(8) // bounded parallelism
 
 	for i := range catalogs {
 		cat := catalogs[i]
 		g.Go(func() error {
-			if err := s.attacher.Attach(gctx, cat); err != nil {
+			attachStart := time.Now()
+			if err := s.attachWithRetry(gctx, cat); err != nil {
+				mu.Lock()
+				failures = append(failures, fmt.Sprintf("%s (%s): %v", cat.Name, cat.MetastoreType, err))
+				mu.Unlock()
 				_ = s.repo.UpdateStatus(gctx, cat.ID, domain.CatalogStatusError, err.Error())
-				s.logger.Warn("attach failed at startup", "catalog", cat.Name, "error", err)
+				s.logger.Error("catalog attach failed at startup; catalog marked unavailable",
+					"catalog", cat.Name, "metastore_type", cat.MetastoreType, "attempts", s.attachAttempts, "error", err)
 				return nil // don't fail all catalogs
 			}
 			_ = s.repo.UpdateStatus(gctx, cat.ID, domain.CatalogStatusActive, "")
-			s.logger.Info("catalog attached at startup", "catalog", cat.Name)
+			s.logger.Info("catalog attached at startup", "catalog", cat.Name, "metastore_type", cat.MetastoreType,
+				"duration_ms", time.Since(attachStart).Milliseconds())
 			return nil
 		})
 	}
@@ -266,6 +327,11 @@ func (s *CatalogRegistrationService) AttachAll(ctx context.Context) error {
 	if err := g.Wait(); err != nil {
 		return fmt.Errorf("attach catalogs: %w", err)
 	}
+	if len(failures) > 0 && s.requireAttach {
+		sort.Strings(failures)
+		return fmt.Errorf("required catalogs could not be attached after %d attempts: %s",
+			s.attachAttempts, strings.Join(failures, "; "))
+	}
 
 	// USE the default catalog
 	defCat, err := s.repo.GetDefault(ctx)
@@ -277,10 +343,34 @@ func (s *CatalogRegistrationService) AttachAll(ctx context.Context) error {
 		}
 	}
 
-	s.logger.Info("catalog startup complete", "total", len(catalogs))
+	s.logger.Info("catalog startup complete", "total", len(catalogs), "failed", len(failures),
+		"duration_ms", time.Since(start).Milliseconds())
 	return nil
 }
 
+// attachWithRetry attaches cat, retrying up to attachAttempts times with a
+// doubling backoff. It returns the last attach error.
+func (s *CatalogRegistrationService) attachWithRetry(ctx context.Context, cat domain.CatalogRegistration) error {
+	backoff := s.attachBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = s.attacher.Attach(ctx, cat); err == nil {
+			return nil
+		}
+		if attempt >= s.attachAttempts {
+			return err
+		}
+		s.logger.Warn("catalog attach failed; retrying", "catalog", cat.Name, "attempt", attempt,
+			"retry_in", backoff.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
 // enforceSQLiteSeparation ensures the given DSN doesn't point to the control plane DB.
 func (s *CatalogRegistrationService) enforceSQLiteSeparation(dsn string) error {
 	if s.controlPlaneDBPath == "" {
@@ -2,8 +2,11 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -135,3 +138,184 @@ func TestCatalogRegistrationService_Register_RejectsReservedNames(t *testing.T)
 		})
 	}
 }
+
+func TestCatalogRegistrationService_SetDefault_UnsetsPreviousDefault(t *testing.T) {
+	catalogs := map[string]*domain.CatalogRegistration{
+		"sales":   {ID: "1", Name: "sales", Status: domain.CatalogStatusActive, IsDefault: true},
+		"finance": {ID: "2", Name: "finance", Status: domain.CatalogStatusActive},
+	}
+	byID := func(id string) *domain.CatalogRegistration {
+		for _, c := range catalogs {
+			if c.ID == id {
+				return c
+			}
+		}
+		return nil
+	}
+
+	repo := &mockRegistrationRepo{
+		GetByNameFn: func(_ context.Context, name string) (*domain.CatalogRegistration, error) {
+			if c, ok := catalogs[name]; ok {
+				cp := *c
+				return &cp, nil
+			}
+			return nil, domain.ErrNotFound("catalog %q not found", name)
+		},
+		GetByIDFn: func(_ context.Context, id string) (*domain.CatalogRegistration, error) {
+			cp := *byID(id)
+			return &cp, nil
+		},
+		SetDefaultFn: func(_ context.Context, id string) error {
+			for _, c := range catalogs {
+				c.IsDefault = c.ID == id
+			}
+			return nil
+		},
+	}
+
+	var used []string
+	attacher := &recordingAttacher{onSetDefault: func(name string) { used = append(used, name) }}
+	svc := NewCatalogRegistrationService(RegistrationServiceDeps{
+		Repo:     repo,
+		Attacher: attacher,
+		Logger:   slog.Default(),
+	})
+
+	got, err := svc.SetDefault(context.Background(), "finance")
+	require.NoError(t, err)
+	assert.True(t, got.IsDefault)
+	assert.False(t, catalogs["sales"].IsDefault, "previous default must be cleared")
+	assert.True(t, catalogs["finance"].IsDefault)
+	assert.Equal(t, []string{"finance"}, used)
+
+	defaults := 0
+	for _, c := range catalogs {
+		if c.IsDefault {
+			defaults++
+		}
+	}
+	assert.Equal(t, 1, defaults)
+}
+
+func TestCatalogRegistrationService_SetDefault_RequiresActiveCatalog(t *testing.T) {
+	repo := &mockRegistrationRepo{
+		GetByNameFn: func(_ context.Context, name string) (*domain.CatalogRegistration, error) {
+			return &domain.CatalogRegistration{ID: "1", Name: name, Status: domain.CatalogStatusError}, nil
+		},
+	}
+	svc := NewCatalogRegistrationService(RegistrationServiceDeps{
+		Repo:     repo,
+		Attacher: noopAttacher{},
+		Logger:   slog.Default(),
+	})
+
+	_, err := svc.SetDefault(context.Background(), "broken")
+	require.Error(t, err)
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+// recordingAttacher records SetDefaultCatalog calls.
+type recordingAttacher struct {
+	noopAttacher
+	onSetDefault func(name string)
+}
+
+func (a *recordingAttacher) SetDefaultCatalog(_ context.Context, name string) error {
+	a.onSetDefault(name)
+	return nil
+}
+
+// flakyAttacher fails the first failures Attach calls per catalog.
+type flakyAttacher struct {
+	noopAttacher
+	failures int
+	err      error
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (a *flakyAttacher) Attach(_ context.Context, reg domain.CatalogRegistration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.calls == nil {
+		a.calls = make(map[string]int)
+	}
+	a.calls[reg.Name]++
+	if a.failures < 0 || a.calls[reg.Name] <= a.failures {
+		return a.err
+	}
+	return nil
+}
+
+func attachAllRepo(statuses map[string]domain.CatalogStatus) *mockRegistrationRepo {
+	var mu sync.Mutex
+	return &mockRegistrationRepo{
+		ListFn: func(_ context.Context, _ domain.PageRequest) ([]domain.CatalogRegistration, int64, error) {
+			return []domain.CatalogRegistration{
+				{ID: "1", Name: "lake", MetastoreType: domain.MetastoreTypePostgres},
+			}, 1, nil
+		},
+		UpdateStatusFn: func(_ context.Context, id string, status domain.CatalogStatus, _ string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[id] = status
+			return nil
+		},
+		GetDefaultFn: func(_ context.Context) (*domain.CatalogRegistration, error) {
+			return nil, domain.ErrNotFound("no default catalog")
+		},
+	}
+}
+
+func TestCatalogRegistrationService_AttachAll_RetriesUntilAttached(t *testing.T) {
+	statuses := map[string]domain.CatalogStatus{}
+	attacher := &flakyAttacher{failures: 2, err: errors.New("connection refused")}
+	svc := NewCatalogRegistrationService(RegistrationServiceDeps{
+		Repo:           attachAllRepo(statuses),
+		Attacher:       attacher,
+		Logger:         slog.Default(),
+		AttachAttempts: 3,
+		AttachBackoff:  time.Millisecond,
+		RequireAttach:  true,
+	})
+
+	require.NoError(t, svc.AttachAll(context.Background()))
+	assert.Equal(t, 3, attacher.calls["lake"])
+	assert.Equal(t, domain.CatalogStatusActive, statuses["1"])
+}
+
+func TestCatalogRegistrationService_AttachAll_RequiredCatalogUnreachable(t *testing.T) {
+	statuses := map[string]domain.CatalogStatus{}
+	attacher := &flakyAttacher{failures: -1, err: errors.New(`connection to server at "pg.internal" failed: connection refused`)}
+	svc := NewCatalogRegistrationService(RegistrationServiceDeps{
+		Repo:           attachAllRepo(statuses),
+		Attacher:       attacher,
+		Logger:         slog.Default(),
+		AttachAttempts: 2,
+		AttachBackoff:  time.Millisecond,
+		RequireAttach:  true,
+	})
+
+	err := svc.AttachAll(context.Background())
+	require.Error(t, err)
+	assert.Equal(t,
+		`required catalogs could not be attached after 2 attempts: lake (postgres): connection to server at "pg.internal" failed: connection refused`,
+		err.Error())
+	assert.Equal(t, 2, attacher.calls["lake"])
+	assert.Equal(t, domain.CatalogStatusError, statuses["1"])
+}
+
+func TestCatalogRegistrationService_AttachAll_OptionalCatalogMarkedError(t *testing.T) {
+	statuses := map[string]domain.CatalogStatus{}
+	svc := NewCatalogRegistrationService(RegistrationServiceDeps{
+		Repo:           attachAllRepo(statuses),
+		Attacher:       &flakyAttacher{failures: -1, err: errors.New("unreachable")},
+		Logger:         slog.Default(),
+		AttachAttempts: 1,
+	})
+
+	require.NoError(t, svc.AttachAll(context.Background()))
+	assert.Equal(t, domain.CatalogStatusError, statuses["1"])
+}
@@ -48,6 +48,33 @@ func (s *SearchService) Search(ctx context.Context, query string, objectType *st
 	return results, total, nil
 }
 
+// Reindex rebuilds the search index of a catalog, or of the default catalog
+// when catalogName is nil.
+func (s *SearchService) Reindex(ctx context.Context, catalogName *string) (*domain.SearchIndexStats, error) {
+	// Unlike Search, there is no falling back to the static default repo:
+	// its search index would not belong to any catalog.
+	repo := s.defaultRepo
+	if s.factory != nil && (catalogName == nil || *catalogName == "") {
+		var err error
+		if repo, err = s.factory.ForDefault(ctx); err != nil {
+			return nil, domain.ErrValidation("reindex unavailable: no default catalog is configured")
+		}
+	} else if s.factory != nil {
+		var err error
+		if repo, err = s.resolveRepo(ctx, catalogName); err != nil {
+			return nil, err
+		}
+	}
+	stats, err := repo.Reindex(ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such table") {
+			return nil, domain.ErrValidation("reindex unavailable: no catalog is currently attached")
+		}
+		return nil, fmt.Errorf("reindex search: %w", err)
+	}
+	return stats, nil
+}
+
 // resolveRepo returns the appropriate SearchRepository for the given catalog name.
 // When no catalog name is provided, it dynamically resolves the current default catalog.
 func (s *SearchService) resolveRepo(ctx context.Context, catalogName *string) (domain.SearchRepository, error) {
@@ -168,6 +168,55 @@ func TestSearchService_Search_NoCatalogAttached(t *testing.T) {
 	assert.Contains(t, err.Error(), "no catalog")
 }
 
+func TestSearchService_Reindex(t *testing.T) {
+	stats := &domain.SearchIndexStats{Schemas: 1, Tables: 2, Columns: 5}
+
+	t.Run("reindexes_named_catalog", func(t *testing.T) {
+		var gotCatalog string
+		factory := &mockSearchRepoFactory{
+			ForCatalogFn: func(_ context.Context, name string) (domain.SearchRepository, error) {
+				gotCatalog = name
+				return &mockSearchRepo{
+					ReindexFn: func(_ context.Context) (*domain.SearchIndexStats, error) { return stats, nil },
+				}, nil
+			},
+		}
+		svc := NewSearchService(&mockSearchRepo{}, factory)
+		catalogName := "sales"
+
+		got, err := svc.Reindex(context.Background(), &catalogName)
+
+		require.NoError(t, err)
+		assert.Equal(t, "sales", gotCatalog)
+		assert.Equal(t, stats, got)
+	})
+
+	t.Run("reindexes_default_catalog", func(t *testing.T) {
+		factory := &mockSearchRepoFactory{
+			ForDefaultFn: func(_ context.Context) (domain.SearchRepository, error) {
+				return &mockSearchRepo{
+					ReindexFn: func(_ context.Context) (*domain.SearchIndexStats, error) { return stats, nil },
+				}, nil
+			},
+		}
+		svc := NewSearchService(&mockSearchRepo{}, factory)
+
+		got, err := svc.Reindex(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, stats, got)
+	})
+
+	t.Run("no_default_catalog_is_a_validation_error", func(t *testing.T) {
+		svc := NewSearchService(&mockSearchRepo{}, &mockSearchRepoFactory{})
+
+		_, err := svc.Reindex(context.Background(), nil)
+
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr, "the static default repo is never reindexed")
+	})
+}
+
 // mockSearchRepoFactory implements SearchRepoFactory for testing.
 type mockSearchRepoFactory struct {
 	ForCatalogFn func(ctx context.Context, catalogName string) (domain.SearchRepository, error)
@@ -2,9 +2,13 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"duck-demo/internal/domain"
+	"duck-demo/internal/duckdbsql"
 )
 
 // ViewService provides view management operations.
@@ -14,6 +18,16 @@ type ViewService struct {
 	catalogFactory CatalogRepoFactory
 	auth           domain.AuthorizationService
 	audit          domain.AuditRepository
+
+	// Optional: when set, CreateView checks definitions against the catalog.
+	engine domain.QueryEngine
+	// Optional: notified when scheduled materialized view refreshes change.
+	reloader ScheduleReloader
+}
+
+// ScheduleReloader is notified when the set of scheduled jobs changes.
+type ScheduleReloader interface {
+	Reload(ctx context.Context) error
 }
 
 // NewViewService creates a new ViewService.
@@ -31,8 +45,28 @@ func NewViewService(
 	}
 }
 
-// CreateView creates a new view in the given schema.
+// SetValidationEngine wires the engine CreateView uses to check view
+// definitions against the catalog before persisting them.
+func (s *ViewService) SetValidationEngine(engine domain.QueryEngine) {
+	s.engine = engine
+}
+
+// SetScheduleReloader wires the scheduler that runs scheduled refreshes of
+// materialized views, so it picks up views as they are created and dropped.
+func (s *ViewService) SetScheduleReloader(r ScheduleReloader) {
+	s.reloader = r
+}
+
+// CreateView creates a new view in the given schema. Unless
+// req.SkipValidation is set, the definition must be a single SELECT that
+// runs as the principal: referenced tables and columns must exist and be
+// readable by them. A materialized view is always validated, and its table
+// is populated before CreateView returns.
 func (s *ViewService) CreateView(ctx context.Context, catalogName string, principal string, schemaName string, req domain.CreateViewRequest) (*domain.ViewDetail, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableCatalog, catalogName, domain.PrivCreateTable)
 	if err != nil {
 		return nil, fmt.Errorf("check privilege: %w", err)
@@ -66,6 +100,23 @@ func (s *ViewService) CreateView(ctx context.Context, catalogName string, princi
 		return nil, domain.ErrAccessDenied("%q lacks CREATE_TABLE privilege for creating views", principal)
 	}
 
+	// Materializing runs the definition directly, so the principal's read
+	// access is always checked first.
+	if !req.SkipValidation || req.Materialized {
+		if err := s.validateDefinition(ctx, principal, req.ViewDefinition); err != nil {
+			return nil, err
+		}
+	}
+	if req.Materialized {
+		_, err := catalogRepo.GetTable(ctx, schemaName, req.Name)
+		if err == nil {
+			return nil, domain.ErrAlreadyExists("table %q already exists in schema %q", req.Name, schemaName)
+		}
+		if !errors.As(err, new(*domain.NotFoundError)) {
+			return nil, err
+		}
+	}
+
 	view := &domain.ViewDetail{
 		SchemaID:       schema.SchemaID,
 		SchemaName:     schemaName,
@@ -74,6 +125,10 @@ func (s *ViewService) CreateView(ctx context.Context, catalogName string, princi
 		ViewDefinition: req.ViewDefinition,
 		Comment:        &req.Comment,
 		Owner:          principal,
+		Materialized:   req.Materialized,
+	}
+	if req.RefreshSchedule != "" {
+		view.RefreshSchedule = &req.RefreshSchedule
 	}
 
 	result, err := s.repo.Create(ctx, view)
@@ -85,10 +140,132 @@ func (s *ViewService) CreateView(ctx context.Context, catalogName string, princi
 	result.SchemaName = schemaName
 	result.CatalogName = schema.CatalogName
 
+	if req.Materialized {
+		if _, err := s.materialize(ctx, catalogRepo, schema.SchemaID, schemaName, result); err != nil {
+			_ = s.repo.Delete(ctx, schema.SchemaID, req.Name)
+			return nil, err
+		}
+		if result.RefreshSchedule != nil {
+			s.reloadSchedules(ctx)
+		}
+	}
+
 	s.logAudit(ctx, principal, "CREATE_VIEW", fmt.Sprintf("Created view %q in schema %q", req.Name, schemaName))
 	return result, nil
 }
 
+// RefreshView recomputes a materialized view's table from its definition.
+// The definition runs as the principal, who needs MODIFY on the schema and
+// read access to the tables the view reads.
+func (s *ViewService) RefreshView(ctx context.Context, catalogName string, principal string, schemaName, viewName string) (*domain.ViewDetail, error) {
+	repo, err := s.catalogFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := repo.GetSchema(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableSchema, schema.SchemaID, domain.PrivModify)
+	if err != nil {
+		return nil, fmt.Errorf("check privilege: %w", err)
+	}
+	if !allowed {
+		s.logAuditDenied(ctx, principal, "REFRESH_VIEW", fmt.Sprintf("Denied refresh view %q.%q", schemaName, viewName))
+		return nil, domain.ErrAccessDenied("%q lacks permission to refresh view %q.%q", principal, schemaName, viewName)
+	}
+
+	view, err := s.repo.GetByName(ctx, schema.SchemaID, viewName)
+	if err != nil {
+		return nil, err
+	}
+	if !view.Materialized {
+		return nil, domain.ErrValidation("view %q.%q is not materialized", schemaName, viewName)
+	}
+	if err := s.validateDefinition(ctx, principal, view.ViewDefinition); err != nil {
+		return nil, err
+	}
+	view.SchemaName = schemaName
+	view.CatalogName = schema.CatalogName
+
+	rows, err := s.materialize(ctx, repo, schema.SchemaID, schemaName, view)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, principal, "REFRESH_VIEW", fmt.Sprintf("Refreshed view %q.%q (%d rows)", schemaName, viewName, rows))
+	return view, nil
+}
+
+// ScheduledJobs returns a job per materialized view with a refresh
+// schedule. Each refresh runs as the view's owner.
+func (s *ViewService) ScheduledJobs(ctx context.Context) ([]domain.ScheduledJob, error) {
+	views, err := s.repo.ListScheduled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled views: %w", err)
+	}
+	jobs := make([]domain.ScheduledJob, 0, len(views))
+	for _, v := range views {
+		if v.RefreshSchedule == nil {
+			continue
+		}
+		jobs = append(jobs, domain.ScheduledJob{
+			Name:     fmt.Sprintf("refresh view %s.%s", v.CatalogName, v.Name),
+			Schedule: *v.RefreshSchedule,
+			Run: func(ctx context.Context) error {
+				schemaName, err := s.schemaNameByID(ctx, v.CatalogName, v.SchemaID)
+				if err != nil {
+					return err
+				}
+				_, err = s.RefreshView(ctx, v.CatalogName, v.Owner, schemaName, v.Name)
+				return err
+			},
+		})
+	}
+	return jobs, nil
+}
+
+// materialize fills a materialized view's table and records the refresh on
+// view. It returns the table's row count.
+func (s *ViewService) materialize(ctx context.Context, repo domain.CatalogRepository, schemaID, schemaName string, view *domain.ViewDetail) (int64, error) {
+	query := strings.TrimRight(view.ViewDefinition, "; \t\r\n")
+	rows, err := repo.MaterializeTable(ctx, schemaName, view.Name, query)
+	if err != nil {
+		return 0, fmt.Errorf("materialize view %q.%q: %w", schemaName, view.Name, err)
+	}
+	now := time.Now().UTC()
+	if err := s.repo.MarkRefreshed(ctx, schemaID, view.Name, now); err != nil {
+		return 0, fmt.Errorf("record refresh: %w", err)
+	}
+	view.LastRefreshedAt = &now
+	return rows, nil
+}
+
+// schemaNameByID resolves a schema ID within a catalog to its name.
+func (s *ViewService) schemaNameByID(ctx context.Context, catalogName, schemaID string) (string, error) {
+	repo, err := s.catalogFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return "", err
+	}
+	schemas, _, err := repo.ListSchemas(ctx, nil, domain.PageRequest{MaxResults: domain.MaxMaxResults})
+	if err != nil {
+		return "", fmt.Errorf("list schemas: %w", err)
+	}
+	for _, schema := range schemas {
+		if schema.SchemaID == schemaID {
+			return schema.Name, nil
+		}
+	}
+	return "", domain.ErrNotFound("schema %q not found in catalog %q", schemaID, catalogName)
+}
+
+// reloadSchedules tells the scheduler that scheduled refreshes changed.
+func (s *ViewService) reloadSchedules(ctx context.Context) {
+	if s.reloader != nil {
+		_ = s.reloader.Reload(ctx)
+	}
+}
+
 // GetView returns a view by schema and name.
 func (s *ViewService) GetView(ctx context.Context, catalogName string, schemaName, viewName string) (*domain.ViewDetail, error) {
 	repo, err := s.catalogFactory.ForCatalog(ctx, catalogName)
@@ -109,7 +286,7 @@ func (s *ViewService) GetView(ctx context.Context, catalogName string, schemaNam
 }
 
 // ListViews returns a paginated list of views in a schema.
-func (s *ViewService) ListViews(ctx context.Context, catalogName string, schemaName string, page domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+func (s *ViewService) ListViews(ctx context.Context, catalogName string, schemaName string, owner *string, page domain.PageRequest) ([]domain.ViewDetail, int64, error) {
 	repo, err := s.catalogFactory.ForCatalog(ctx, catalogName)
 	if err != nil {
 		return nil, 0, err
@@ -118,7 +295,7 @@ func (s *ViewService) ListViews(ctx context.Context, catalogName string, schemaN
 	if err != nil {
 		return nil, 0, err
 	}
-	views, total, err := s.repo.List(ctx, schema.SchemaID, page)
+	views, total, err := s.repo.List(ctx, schema.SchemaID, owner, page)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -157,14 +334,99 @@ func (s *ViewService) DeleteView(ctx context.Context, catalogName string, princi
 		return domain.ErrAccessDenied("%q lacks permission to delete view %q.%q", principal, schemaName, viewName)
 	}
 
+	view, err := s.repo.GetByName(ctx, schema.SchemaID, viewName)
+	if err != nil {
+		return err
+	}
 	if err := s.repo.Delete(ctx, schema.SchemaID, viewName); err != nil {
 		return err
 	}
+	if view.Materialized {
+		if err := repo.DeleteTable(ctx, schemaName, viewName); err != nil && !errors.As(err, new(*domain.NotFoundError)) {
+			return fmt.Errorf("drop materialized table: %w", err)
+		}
+		if view.RefreshSchedule != nil {
+			s.reloadSchedules(ctx)
+		}
+	}
 
 	s.logAudit(ctx, principal, "DROP_VIEW", fmt.Sprintf("Dropped view %q.%q", schemaName, viewName))
 	return nil
 }
 
+// RenameView renames a view in place, keeping its ID and so its grants. A
+// materialized view's table is renamed with it. Views that read the view
+// block the rename unless req.Force is set.
+func (s *ViewService) RenameView(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.RenameRequest) (*domain.ViewDetail, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	repo, err := s.catalogFactory.ForCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := repo.GetSchema(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.auth.CheckPrivilege(ctx, principal, domain.SecurableSchema, schema.SchemaID, domain.PrivManage)
+	if err != nil {
+		return nil, fmt.Errorf("check privilege: %w", err)
+	}
+	if !allowed {
+		s.logAuditDenied(ctx, principal, "RENAME_VIEW", fmt.Sprintf("Denied rename view %q.%q", schemaName, viewName))
+		return nil, domain.ErrAccessDenied("%q lacks permission to rename view %q.%q", principal, schemaName, viewName)
+	}
+
+	view, err := s.repo.GetByName(ctx, schema.SchemaID, viewName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByName(ctx, schema.SchemaID, req.NewName); err == nil {
+		return nil, domain.ErrAlreadyExists("view %q already exists in schema %q", req.NewName, schemaName)
+	}
+	if !view.Materialized {
+		// A materialized view's table conflict is reported by RenameTable.
+		if _, err := repo.GetTable(ctx, schemaName, req.NewName); err == nil {
+			return nil, domain.ErrAlreadyExists("table %q already exists in schema %q", req.NewName, schemaName)
+		}
+	}
+
+	deps, err := dependentViews(ctx, s.repo, repo, catalogName, schemaName, viewName)
+	if err != nil {
+		return nil, fmt.Errorf("find dependent views: %w", err)
+	}
+	if len(deps) > 0 && !req.Force {
+		names := make([]string, len(deps))
+		for i, d := range deps {
+			names[i] = d.SchemaName + "." + d.Name
+		}
+		return nil, domain.ErrConflict("view %q.%q is read by views %s, whose SQL would still use the old name; update them first or retry with force",
+			schemaName, viewName, strings.Join(names, ", "))
+	}
+
+	if view.Materialized {
+		if _, err := repo.RenameTable(ctx, schemaName, viewName, req.NewName); err != nil {
+			return nil, fmt.Errorf("rename materialized table: %w", err)
+		}
+	}
+	result, err := s.repo.Rename(ctx, schema.SchemaID, viewName, req.NewName)
+	if err != nil {
+		if view.Materialized {
+			_, _ = repo.RenameTable(ctx, schemaName, req.NewName, viewName)
+		}
+		return nil, err
+	}
+	result.SchemaName = schemaName
+	result.CatalogName = schema.CatalogName
+	if result.Materialized && result.RefreshSchedule != nil {
+		s.reloadSchedules(ctx)
+	}
+
+	s.logAudit(ctx, principal, "RENAME_VIEW", fmt.Sprintf("Renamed view %q.%q to %q", schemaName, viewName, req.NewName))
+	return result, nil
+}
+
 // UpdateView updates a view's metadata.
 func (s *ViewService) UpdateView(ctx context.Context, catalogName string, principal string, schemaName, viewName string, req domain.UpdateViewRequest) (*domain.ViewDetail, error) {
 	repo, err := s.catalogFactory.ForCatalog(ctx, catalogName)
@@ -193,7 +455,7 @@ func (s *ViewService) UpdateView(ctx context.Context, catalogName string, princi
 		return nil, domain.ErrAccessDenied("%q lacks permission to update view %q.%q", principal, schemaName, viewName)
 	}
 
-	result, err := s.repo.Update(ctx, schema.SchemaID, viewName, req.Comment, req.Properties, req.ViewDefinition)
+	result, err := s.repo.Update(ctx, schema.SchemaID, viewName, req.Comment, req.Properties, req.ViewDefinition, req.ExpectedRowVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -205,6 +467,36 @@ func (s *ViewService) UpdateView(ctx context.Context, catalogName string, princi
 	return result, nil
 }
 
+// validateDefinition checks that a view definition is a single SELECT and,
+// when an engine is configured, plans it as the principal with LIMIT 0 so
+// missing tables or columns and unreadable tables surface before the view is
+// stored. Access errors are returned as is; everything else is a validation
+// error.
+func (s *ViewService) validateDefinition(ctx context.Context, principal, definition string) error {
+	definition = strings.TrimRight(definition, "; \t\r\n")
+	stmt, err := duckdbsql.Parse(definition)
+	if err != nil {
+		return domain.ErrValidation("view_definition is not valid SQL: %v", err)
+	}
+	if _, ok := stmt.(*duckdbsql.SelectStmt); !ok {
+		return domain.ErrValidation("view_definition must be a SELECT statement")
+	}
+	if s.engine == nil {
+		return nil
+	}
+
+	// The newlines keep a trailing line comment from swallowing the ")".
+	query := "SELECT * FROM (\n" + definition + "\n) AS view_definition_check LIMIT 0"
+	rows, err := s.engine.Query(ctx, principal, query)
+	if err != nil {
+		if errors.As(err, new(*domain.AccessDeniedError)) {
+			return err
+		}
+		return domain.ErrValidation("view_definition is invalid: %v", err)
+	}
+	return rows.Close()
+}
+
 func (s *ViewService) logAudit(ctx context.Context, principal, action, detail string) {
 	_ = s.audit.Insert(ctx, &domain.AuditEntry{
 		PrincipalName: principal,
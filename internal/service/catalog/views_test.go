@@ -2,13 +2,18 @@ package catalog
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
+	_ "github.com/duckdb/duckdb-go/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"duck-demo/internal/ddl"
 	"duck-demo/internal/domain"
+	"duck-demo/internal/testutil"
 )
 
 func newTestViewService(viewRepo *mockViewRepo, catalog *mockCatalogRepo, auth *mockAuthService, audit *mockAuditRepo) *ViewService {
@@ -208,6 +213,247 @@ func TestViewService_CreateView(t *testing.T) {
 	})
 }
 
+func TestViewService_CreateView_ValidatesDefinition(t *testing.T) {
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec("CREATE TABLE orders (id INTEGER, amount DOUBLE); CREATE TABLE salaries (id INTEGER, salary DOUBLE)")
+	require.NoError(t, err)
+
+	engine := &testutil.MockSessionEngine{
+		QueryFn: func(ctx context.Context, principal, query string) (*sql.Rows, error) {
+			if strings.Contains(query, "salaries") {
+				return nil, domain.ErrAccessDenied("principal %q lacks SELECT on table %q", principal, "salaries")
+			}
+			return db.QueryContext(ctx, query)
+		},
+	}
+
+	newService := func(created *bool) *ViewService {
+		viewRepo := &mockViewRepo{
+			CreateFn: func(_ context.Context, v *domain.ViewDetail) (*domain.ViewDetail, error) {
+				*created = true
+				return &domain.ViewDetail{ID: "1", Name: v.Name, ViewDefinition: v.ViewDefinition}, nil
+			},
+		}
+		catalog := &mockCatalogRepo{
+			GetSchemaFn: func(_ context.Context, _ string) (*domain.SchemaDetail, error) {
+				return &domain.SchemaDetail{SchemaID: "42", Name: "main", CatalogName: "lake"}, nil
+			},
+		}
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, _, _ string, _ string, _ string) (bool, error) {
+				return true, nil
+			},
+		}
+		svc := newTestViewService(viewRepo, catalog, auth, &mockAuditRepo{})
+		svc.SetValidationEngine(engine)
+		return svc
+	}
+
+	tests := []struct {
+		name       string
+		definition string
+		skip       bool
+		wantErr    string
+		wantDenied bool
+	}{
+		{name: "valid", definition: "SELECT id, amount FROM orders WHERE amount > 10;"},
+		{name: "trailing comment", definition: "SELECT id FROM orders -- all orders"},
+		{name: "nonexistent table", definition: "SELECT * FROM missing_orders", wantErr: "missing_orders"},
+		{name: "nonexistent column", definition: "SELECT total FROM orders", wantErr: "total"},
+		{name: "syntax error", definition: "SELECT FROM WHERE", wantErr: "view_definition is not valid SQL"},
+		{name: "not a select", definition: "DELETE FROM orders", wantErr: "must be a SELECT statement"},
+		{name: "unreadable table", definition: "SELECT salary FROM salaries", wantDenied: true},
+		{name: "validation skipped", definition: "SELECT * FROM missing_orders", skip: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created bool
+			svc := newService(&created)
+			_, err := svc.CreateView(ctxWithPrincipal("alice"), "lake", "alice", "main", domain.CreateViewRequest{
+				Name:           "v_test",
+				ViewDefinition: tt.definition,
+				SkipValidation: tt.skip,
+			})
+
+			switch {
+			case tt.wantDenied:
+				require.ErrorAs(t, err, new(*domain.AccessDeniedError))
+				assert.False(t, created)
+			case tt.wantErr != "":
+				var validation *domain.ValidationError
+				require.ErrorAs(t, err, &validation)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.False(t, created, "an invalid view must not be persisted")
+			default:
+				require.NoError(t, err)
+				assert.True(t, created)
+			}
+		})
+	}
+}
+
+// === Materialized views ===
+
+func TestViewService_MaterializedView(t *testing.T) {
+	ctx := ctxWithPrincipal("alice")
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec("CREATE TABLE main.orders (id INTEGER, amount DOUBLE); INSERT INTO main.orders VALUES (1, 10), (2, 20)")
+	require.NoError(t, err)
+
+	engine := &testutil.MockSessionEngine{
+		QueryFn: func(ctx context.Context, _, query string) (*sql.Rows, error) {
+			return db.QueryContext(ctx, query)
+		},
+	}
+	// The catalog repo materializes into DuckDB's in-memory catalog.
+	catalog := &mockCatalogRepo{
+		GetSchemaFn: func(_ context.Context, _ string) (*domain.SchemaDetail, error) {
+			return &domain.SchemaDetail{SchemaID: "42", Name: "main", CatalogName: "lake"}, nil
+		},
+		GetTableFn: func(ctx context.Context, schemaName, tableName string) (*domain.TableDetail, error) {
+			var n int
+			err := db.QueryRowContext(ctx, "SELECT count(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = ?", schemaName, tableName).Scan(&n)
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return nil, domain.ErrNotFound("table %q not found", tableName)
+			}
+			return &domain.TableDetail{Name: tableName, SchemaName: schemaName}, nil
+		},
+		MaterializeTableFn: func(ctx context.Context, schemaName, tableName, query string) (int64, error) {
+			var stmts []string
+			if _, err := db.ExecContext(ctx, "SELECT 1 FROM "+schemaName+"."+tableName+" LIMIT 0"); err == nil {
+				stmts, err = ddl.ReplaceTableContents("memory", schemaName, tableName, query)
+				require.NoError(t, err)
+			} else {
+				stmt, err := ddl.CreateTableAs("memory", schemaName, tableName, query)
+				require.NoError(t, err)
+				stmts = []string{stmt}
+			}
+			for _, stmt := range stmts {
+				if _, err := db.ExecContext(ctx, stmt); err != nil {
+					return 0, err
+				}
+			}
+			var n int64
+			err := db.QueryRowContext(ctx, "SELECT count(*) FROM "+schemaName+"."+tableName).Scan(&n)
+			return n, err
+		},
+	}
+	views := map[string]*domain.ViewDetail{}
+	viewRepo := &mockViewRepo{
+		CreateFn: func(_ context.Context, v *domain.ViewDetail) (*domain.ViewDetail, error) {
+			stored := *v
+			views[v.Name] = &stored
+			out := stored
+			return &out, nil
+		},
+		GetByNameFn: func(_ context.Context, _ string, name string) (*domain.ViewDetail, error) {
+			v, ok := views[name]
+			if !ok {
+				return nil, domain.ErrNotFound("view %q not found", name)
+			}
+			out := *v
+			return &out, nil
+		},
+		MarkRefreshedFn: func(_ context.Context, _ string, name string, at time.Time) error {
+			views[name].LastRefreshedAt = &at
+			return nil
+		},
+	}
+	canModify := true
+	auth := &mockAuthService{
+		CheckPrivilegeFn: func(_ context.Context, _, _ string, _ string, privilege string) (bool, error) {
+			return privilege != domain.PrivModify || canModify, nil
+		},
+	}
+	svc := newTestViewService(viewRepo, catalog, auth, &mockAuditRepo{})
+	svc.SetValidationEngine(engine)
+
+	orderCount := func() int {
+		t.Helper()
+		rows, err := engine.Query(ctx, "alice", "SELECT n FROM main.order_stats")
+		require.NoError(t, err)
+		defer func() { _ = rows.Close() }()
+		require.True(t, rows.Next())
+		var n int
+		require.NoError(t, rows.Scan(&n))
+		return n
+	}
+
+	created, err := svc.CreateView(ctx, "lake", "alice", "main", domain.CreateViewRequest{
+		Name:            "order_stats",
+		ViewDefinition:  "SELECT count(*) AS n, sum(amount) AS total FROM main.orders;",
+		Materialized:    true,
+		RefreshSchedule: "0 * * * *",
+	})
+	require.NoError(t, err)
+	assert.True(t, created.Materialized)
+	require.NotNil(t, created.LastRefreshedAt)
+	assert.Equal(t, 2, orderCount())
+
+	// The view returns precomputed data until it is refreshed.
+	_, err = db.Exec("INSERT INTO main.orders VALUES (3, 30)")
+	require.NoError(t, err)
+	assert.Equal(t, 2, orderCount())
+
+	refreshed, err := svc.RefreshView(ctx, "lake", "alice", "main", "order_stats")
+	require.NoError(t, err)
+	require.NotNil(t, refreshed.LastRefreshedAt)
+	assert.False(t, refreshed.LastRefreshedAt.Before(*created.LastRefreshedAt))
+	assert.Equal(t, 3, orderCount())
+
+	t.Run("name taken by a table", func(t *testing.T) {
+		_, err := svc.CreateView(ctx, "lake", "alice", "main", domain.CreateViewRequest{
+			Name: "orders", ViewDefinition: "SELECT 1 AS n", Materialized: true,
+		})
+		require.ErrorAs(t, err, new(*domain.ConflictError))
+	})
+
+	t.Run("schedule requires materialized", func(t *testing.T) {
+		_, err := svc.CreateView(ctx, "lake", "alice", "main", domain.CreateViewRequest{
+			Name: "plain", ViewDefinition: "SELECT 1 AS n", RefreshSchedule: "0 * * * *",
+		})
+		require.ErrorAs(t, err, new(*domain.ValidationError))
+	})
+
+	t.Run("logical views cannot be refreshed", func(t *testing.T) {
+		views["plain"] = &domain.ViewDetail{Name: "plain", ViewDefinition: "SELECT 1 AS n"}
+		_, err := svc.RefreshView(ctx, "lake", "alice", "main", "plain")
+		require.ErrorAs(t, err, new(*domain.ValidationError))
+	})
+
+	t.Run("refresh requires MODIFY", func(t *testing.T) {
+		canModify = false
+		t.Cleanup(func() { canModify = true })
+		_, err := svc.RefreshView(ctx, "lake", "bob", "main", "order_stats")
+		require.ErrorAs(t, err, new(*domain.AccessDeniedError))
+	})
+
+	t.Run("scheduled jobs", func(t *testing.T) {
+		viewRepo.ListScheduledFn = func(_ context.Context) ([]domain.ViewDetail, error) {
+			return []domain.ViewDetail{*views["order_stats"]}, nil
+		}
+		catalog.ListSchemasFn = func(_ context.Context, _ *string, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+			return []domain.SchemaDetail{{SchemaID: "42", Name: "main"}}, 1, nil
+		}
+		jobs, err := svc.ScheduledJobs(ctx)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "0 * * * *", jobs[0].Schedule)
+
+		_, err = db.Exec("INSERT INTO main.orders VALUES (4, 40)")
+		require.NoError(t, err)
+		require.NoError(t, jobs[0].Run(context.Background()))
+		assert.Equal(t, 4, orderCount())
+	})
+}
+
 // === GetView ===
 
 func TestViewService_GetView(t *testing.T) {
@@ -286,7 +532,7 @@ func TestViewService_ListViews(t *testing.T) {
 
 	t.Run("happy_path", func(t *testing.T) {
 		viewRepo := &mockViewRepo{
-			ListFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+			ListFn: func(_ context.Context, _ string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
 				return []domain.ViewDetail{
 					{ID: "1", Name: "v1"},
 					{ID: "2", Name: "v2"},
@@ -300,7 +546,7 @@ func TestViewService_ListViews(t *testing.T) {
 		}
 
 		svc := NewViewService(viewRepo, &mockCatalogRepoFactory{repo: catalog}, &mockAuthService{}, &mockAuditRepo{})
-		views, total, err := svc.ListViews(context.Background(), "lake", "main", page)
+		views, total, err := svc.ListViews(context.Background(), "lake", "main", nil, page)
 
 		require.NoError(t, err)
 		assert.Equal(t, int64(2), total)
@@ -313,7 +559,7 @@ func TestViewService_ListViews(t *testing.T) {
 
 	t.Run("empty_result", func(t *testing.T) {
 		viewRepo := &mockViewRepo{
-			ListFn: func(_ context.Context, _ string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+			ListFn: func(_ context.Context, _ string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
 				return []domain.ViewDetail{}, 0, nil
 			},
 		}
@@ -324,7 +570,7 @@ func TestViewService_ListViews(t *testing.T) {
 		}
 
 		svc := NewViewService(viewRepo, &mockCatalogRepoFactory{repo: catalog}, &mockAuthService{}, &mockAuditRepo{})
-		views, total, err := svc.ListViews(context.Background(), "lake", "main", page)
+		views, total, err := svc.ListViews(context.Background(), "lake", "main", nil, page)
 
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), total)
@@ -339,7 +585,7 @@ func TestViewService_ListViews(t *testing.T) {
 		}
 
 		svc := NewViewService(&mockViewRepo{}, &mockCatalogRepoFactory{repo: catalog}, &mockAuthService{}, &mockAuditRepo{})
-		_, _, err := svc.ListViews(context.Background(), "lake", "bad", page)
+		_, _, err := svc.ListViews(context.Background(), "lake", "bad", nil, page)
 
 		require.Error(t, err)
 		var notFound *domain.NotFoundError
@@ -358,6 +604,9 @@ func TestViewService_DeleteView(t *testing.T) {
 
 	t.Run("happy_path", func(t *testing.T) {
 		viewRepo := &mockViewRepo{
+			GetByNameFn: func(_ context.Context, _ string, name string) (*domain.ViewDetail, error) {
+				return &domain.ViewDetail{Name: name}, nil
+			},
 			DeleteFn: func(_ context.Context, _ string, _ string) error {
 				return nil
 			},
@@ -424,6 +673,9 @@ func TestViewService_DeleteView(t *testing.T) {
 
 	t.Run("repo_delete_error", func(t *testing.T) {
 		viewRepo := &mockViewRepo{
+			GetByNameFn: func(_ context.Context, _ string, name string) (*domain.ViewDetail, error) {
+				return &domain.ViewDetail{Name: name}, nil
+			},
 			DeleteFn: func(_ context.Context, _ string, _ string) error {
 				return errTest
 			},
@@ -446,3 +698,114 @@ func TestViewService_DeleteView(t *testing.T) {
 		assert.ErrorIs(t, err, errTest)
 	})
 }
+
+func TestViewService_RenameView(t *testing.T) {
+	schema := &domain.SchemaDetail{SchemaID: "42", Name: "main", CatalogName: "lake"}
+
+	// setup serves the views in defs, marking those in materialized as
+	// materialized, and records table and view renames.
+	setup := func(defs map[string]string, materialized ...string) (*ViewService, *[]string, *mockAuditRepo) {
+		var renames []string
+		isMaterialized := func(name string) bool {
+			for _, m := range materialized {
+				if m == name {
+					return true
+				}
+			}
+			return false
+		}
+		viewRepo := &mockViewRepo{
+			GetByNameFn: func(_ context.Context, _ string, name string) (*domain.ViewDetail, error) {
+				def, ok := defs[name]
+				if !ok {
+					return nil, domain.ErrNotFound("view %q not found", name)
+				}
+				return &domain.ViewDetail{ID: "view-" + name, Name: name, ViewDefinition: def, Materialized: isMaterialized(name)}, nil
+			},
+			ListFn: func(_ context.Context, _ string, _ *string, _ domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+				var out []domain.ViewDetail
+				for name, def := range defs {
+					out = append(out, domain.ViewDetail{Name: name, ViewDefinition: def})
+				}
+				return out, int64(len(out)), nil
+			},
+			RenameFn: func(_ context.Context, _ string, viewName, newName string) (*domain.ViewDetail, error) {
+				renames = append(renames, "view:"+viewName+"->"+newName)
+				return &domain.ViewDetail{ID: "view-" + viewName, Name: newName, Materialized: isMaterialized(viewName)}, nil
+			},
+		}
+		catalog := &mockCatalogRepo{
+			GetSchemaFn: func(_ context.Context, _ string) (*domain.SchemaDetail, error) {
+				return schema, nil
+			},
+			ListSchemasFn: func(_ context.Context, _ *string, _ domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+				return []domain.SchemaDetail{*schema}, 1, nil
+			},
+			GetTableFn: func(_ context.Context, _, tableName string) (*domain.TableDetail, error) {
+				return nil, domain.ErrNotFound("table %q not found", tableName)
+			},
+			RenameTableFn: func(_ context.Context, _, tableName, newName string) (*domain.TableDetail, error) {
+				renames = append(renames, "table:"+tableName+"->"+newName)
+				return &domain.TableDetail{Name: newName}, nil
+			},
+		}
+		auth := &mockAuthService{
+			CheckPrivilegeFn: func(_ context.Context, principal, _ string, _ string, _ string) (bool, error) {
+				return principal == "alice", nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		return newTestViewService(viewRepo, catalog, auth, audit), &renames, audit
+	}
+
+	t.Run("renames a view in place", func(t *testing.T) {
+		svc, renames, audit := setup(map[string]string{"daily": "SELECT 1"})
+
+		v, err := svc.RenameView(ctxWithPrincipal("alice"), "lake", "alice", "main", "daily", domain.RenameRequest{NewName: "daily_v2"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "daily_v2", v.Name)
+		assert.Equal(t, "view-daily", v.ID, "the view keeps its ID and grants")
+		assert.Equal(t, "main", v.SchemaName)
+		assert.Equal(t, []string{"view:daily->daily_v2"}, *renames)
+		assert.Equal(t, "RENAME_VIEW", audit.LastEntry().Action)
+	})
+
+	t.Run("materialized view renames its table first", func(t *testing.T) {
+		svc, renames, _ := setup(map[string]string{"daily": "SELECT 1"}, "daily")
+
+		_, err := svc.RenameView(ctxWithPrincipal("alice"), "lake", "alice", "main", "daily", domain.RenameRequest{NewName: "daily_v2"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"table:daily->daily_v2", "view:daily->daily_v2"}, *renames)
+	})
+
+	t.Run("dependent views block the rename unless forced", func(t *testing.T) {
+		svc, renames, _ := setup(map[string]string{"daily": "SELECT 1", "weekly": "SELECT * FROM main.daily"})
+
+		_, err := svc.RenameView(ctxWithPrincipal("alice"), "lake", "alice", "main", "daily", domain.RenameRequest{NewName: "daily_v2"})
+		var conflict *domain.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Contains(t, err.Error(), "main.weekly")
+		assert.Empty(t, *renames)
+
+		_, err = svc.RenameView(ctxWithPrincipal("alice"), "lake", "alice", "main", "daily", domain.RenameRequest{NewName: "daily_v2", Force: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("existing view name conflicts", func(t *testing.T) {
+		svc, _, _ := setup(map[string]string{"daily": "SELECT 1", "weekly": "SELECT 2"})
+
+		_, err := svc.RenameView(ctxWithPrincipal("alice"), "lake", "alice", "main", "daily", domain.RenameRequest{NewName: "weekly"})
+		assert.ErrorAs(t, err, new(*domain.ConflictError))
+	})
+
+	t.Run("requires MANAGE on the schema", func(t *testing.T) {
+		svc, renames, audit := setup(map[string]string{"daily": "SELECT 1"})
+
+		_, err := svc.RenameView(ctxWithPrincipal("bob"), "lake", "bob", "main", "daily", domain.RenameRequest{NewName: "daily_v2"})
+		assert.ErrorAs(t, err, new(*domain.AccessDeniedError))
+		assert.Empty(t, *renames)
+		assert.True(t, audit.HasAction("RENAME_VIEW"))
+	})
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"log/slog"
 	"net/url"
 	"strings"
+	"time"
 
 	workercompute "duck-demo/internal/compute"
 	computeproto "duck-demo/internal/compute/proto"
@@ -19,14 +21,25 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
+const (
+	// loadPollPageSize is the page size used to walk endpoints when polling.
+	loadPollPageSize = 200
+	// loadPollTimeout bounds a single agent health poll.
+	loadPollTimeout = 5 * time.Second
+)
+
 // ComputeEndpointService provides CRUD operations for compute endpoints
 // and assignments with RBAC enforcement and audit logging.
 //
 //nolint:revive // Name chosen for clarity across package boundaries
 type ComputeEndpointService struct {
-	repo  domain.ComputeEndpointRepository
-	auth  domain.AuthorizationService
-	audit domain.AuditRepository
+	repo      domain.ComputeEndpointRepository
+	auth      domain.AuthorizationService
+	audit     domain.AuditRepository
+	loads     domain.ComputeEndpointLoadRepository
+	agents    domain.ComputeEndpointAgentRepository
+	tlsConfig *tls.Config
+	logger    *slog.Logger
 }
 
 // NewComputeEndpointService creates a new ComputeEndpointService.
@@ -42,6 +55,29 @@ func NewComputeEndpointService(
 	}
 }
 
+// SetLoadRepository enables storing polled agent load and attaching it to
+// endpoints returned by List and GetByName.
+func (s *ComputeEndpointService) SetLoadRepository(loads domain.ComputeEndpointLoadRepository) {
+	s.loads = loads
+}
+
+// SetAgentRepository enables managing the additional agent URLs of remote
+// endpoints.
+func (s *ComputeEndpointService) SetAgentRepository(agents domain.ComputeEndpointAgentRepository) {
+	s.agents = agents
+}
+
+// SetTLSConfig sets the TLS configuration used to reach grpcs:// agents,
+// including the client certificate for agents that require mutual TLS.
+func (s *ComputeEndpointService) SetTLSConfig(cfg *tls.Config) {
+	s.tlsConfig = cfg
+}
+
+// SetLogger sets the logger used by the background load poller.
+func (s *ComputeEndpointService) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
 // Create validates and persists a new compute endpoint.
 // Requires MANAGE_COMPUTE on catalog.
 func (s *ComputeEndpointService) Create(ctx context.Context, principal string, req domain.CreateComputeEndpointRequest) (*domain.ComputeEndpoint, error) {
@@ -86,7 +122,9 @@ func (s *ComputeEndpointService) GetByName(ctx context.Context, principal, name
 		s.logAuditDenied(ctx, principal, "GET_COMPUTE_ENDPOINT", fmt.Sprintf("Denied get compute endpoint %q", name))
 		return nil, err
 	}
-	return ep, nil
+	eps := []domain.ComputeEndpoint{*ep}
+	s.attachLoads(ctx, eps)
+	return &eps[0], nil
 }
 
 // List returns a paginated list of compute endpoints.
@@ -94,7 +132,29 @@ func (s *ComputeEndpointService) List(ctx context.Context, principal string, pag
 	if err := s.requirePrivilege(ctx, principal, domain.PrivManageCompute, "LIST_COMPUTE_ENDPOINTS", "Denied list compute endpoints"); err != nil {
 		return nil, 0, err
 	}
-	return s.repo.List(ctx, page)
+	eps, total, err := s.repo.List(ctx, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.attachLoads(ctx, eps)
+	return eps, total, nil
+}
+
+// attachLoads sets the latest polled load on each endpoint. Load is
+// best-effort status, so a lookup failure leaves it unset.
+func (s *ComputeEndpointService) attachLoads(ctx context.Context, eps []domain.ComputeEndpoint) {
+	if s.loads == nil || len(eps) == 0 {
+		return
+	}
+	loads, err := s.loads.ListLoads(ctx)
+	if err != nil {
+		return
+	}
+	for i := range eps {
+		if l, ok := loads[eps[i].ID]; ok {
+			eps[i].Load = &l
+		}
+	}
 }
 
 // Update updates a compute endpoint by name.
@@ -119,7 +179,6 @@ func (s *ComputeEndpointService) Update(ctx context.Context, principal string, n
 		}
 	}
 
-	// Handle status update if provided
 	if req.Status != nil {
 		switch *req.Status {
 		case "ACTIVE", "INACTIVE", "STARTING", "STOPPING", "ERROR":
@@ -127,9 +186,6 @@ func (s *ComputeEndpointService) Update(ctx context.Context, principal string, n
 		default:
 			return nil, domain.ErrValidation("invalid status %q", *req.Status)
 		}
-		if err := s.repo.UpdateStatus(ctx, existing.ID, *req.Status); err != nil {
-			return nil, fmt.Errorf("update compute endpoint status: %w", err)
-		}
 	}
 
 	result, err := s.repo.Update(ctx, existing.ID, req)
@@ -137,6 +193,15 @@ func (s *ComputeEndpointService) Update(ctx context.Context, principal string, n
 		return nil, fmt.Errorf("update compute endpoint: %w", err)
 	}
 
+	// The status is written after the update so a request rejected by its
+	// row version check changes nothing.
+	if req.Status != nil {
+		if err := s.repo.UpdateStatus(ctx, existing.ID, *req.Status); err != nil {
+			return nil, fmt.Errorf("update compute endpoint status: %w", err)
+		}
+		result.Status = *req.Status
+	}
+
 	s.logAudit(ctx, principal, "UPDATE_COMPUTE_ENDPOINT", fmt.Sprintf("Updated compute endpoint %q", name))
 	return result, nil
 }
@@ -272,6 +337,95 @@ func (s *ComputeEndpointService) ListAssignments(ctx context.Context, principal,
 	return s.repo.ListAssignments(ctx, ep.ID, page)
 }
 
+// ListAgents returns the additional agent URLs of a compute endpoint.
+// Requires MANAGE_COMPUTE on the endpoint.
+func (s *ComputeEndpointService) ListAgents(ctx context.Context, principal, endpointName string) ([]domain.ComputeEndpointAgent, error) {
+	if err := s.requirePrivilege(ctx, principal, domain.PrivManageCompute, "LIST_COMPUTE_ENDPOINT_AGENTS", fmt.Sprintf("Denied list agents for endpoint %q", endpointName)); err != nil {
+		return nil, err
+	}
+	if s.agents == nil {
+		return nil, fmt.Errorf("compute endpoint agents are not configured")
+	}
+
+	ep, err := s.repo.GetByName(ctx, endpointName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireEndpointPrivilege(ctx, principal, ep.ID, domain.PrivManageCompute); err != nil {
+		s.logAuditDenied(ctx, principal, "LIST_COMPUTE_ENDPOINT_AGENTS", fmt.Sprintf("Denied list agents for endpoint %q", endpointName))
+		return nil, err
+	}
+	return s.agents.ListAgents(ctx, ep.ID)
+}
+
+// AddAgent adds an agent URL to a REMOTE compute endpoint. Queries routed to
+// the endpoint are then spread across its URL and all added agents.
+// Requires MANAGE_COMPUTE on the endpoint.
+func (s *ComputeEndpointService) AddAgent(ctx context.Context, principal, endpointName string, req domain.CreateComputeEndpointAgentRequest) (*domain.ComputeEndpointAgent, error) {
+	if err := s.requirePrivilege(ctx, principal, domain.PrivManageCompute, "ADD_COMPUTE_ENDPOINT_AGENT", fmt.Sprintf("Denied add agent to endpoint %q", endpointName)); err != nil {
+		return nil, err
+	}
+	if s.agents == nil {
+		return nil, fmt.Errorf("compute endpoint agents are not configured")
+	}
+
+	ep, err := s.repo.GetByName(ctx, endpointName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireEndpointPrivilege(ctx, principal, ep.ID, domain.PrivManageCompute); err != nil {
+		s.logAuditDenied(ctx, principal, "ADD_COMPUTE_ENDPOINT_AGENT", fmt.Sprintf("Denied add agent to endpoint %q", endpointName))
+		return nil, err
+	}
+	if ep.Type != "REMOTE" {
+		return nil, domain.ErrValidation("agents can only be added to REMOTE endpoints")
+	}
+	agentURL := strings.TrimSpace(req.URL)
+	if err := domain.ValidateComputeEndpointURL(agentURL, ep.Type); err != nil {
+		return nil, err
+	}
+	if agentURL == ep.URL {
+		return nil, domain.ErrConflict("url %q is already the endpoint url", agentURL)
+	}
+
+	result, err := s.agents.AddAgent(ctx, &domain.ComputeEndpointAgent{EndpointID: ep.ID, URL: agentURL})
+	if err != nil {
+		return nil, fmt.Errorf("add compute endpoint agent: %w", err)
+	}
+
+	s.logAudit(ctx, principal, "ADD_COMPUTE_ENDPOINT_AGENT",
+		fmt.Sprintf("Added agent %s to compute endpoint %q", agentURL, endpointName))
+	return result, nil
+}
+
+// RemoveAgent removes an agent URL from a compute endpoint.
+// Requires MANAGE_COMPUTE on the endpoint.
+func (s *ComputeEndpointService) RemoveAgent(ctx context.Context, principal, endpointName, agentID string) error {
+	if err := s.requirePrivilege(ctx, principal, domain.PrivManageCompute, "REMOVE_COMPUTE_ENDPOINT_AGENT", fmt.Sprintf("Denied remove agent from endpoint %q", endpointName)); err != nil {
+		return err
+	}
+	if s.agents == nil {
+		return fmt.Errorf("compute endpoint agents are not configured")
+	}
+
+	ep, err := s.repo.GetByName(ctx, endpointName)
+	if err != nil {
+		return err
+	}
+	if err := s.requireEndpointPrivilege(ctx, principal, ep.ID, domain.PrivManageCompute); err != nil {
+		s.logAuditDenied(ctx, principal, "REMOVE_COMPUTE_ENDPOINT_AGENT", fmt.Sprintf("Denied remove agent from endpoint %q", endpointName))
+		return err
+	}
+
+	if err := s.agents.RemoveAgent(ctx, ep.ID, agentID); err != nil {
+		return fmt.Errorf("remove compute endpoint agent: %w", err)
+	}
+
+	s.logAudit(ctx, principal, "REMOVE_COMPUTE_ENDPOINT_AGENT",
+		fmt.Sprintf("Removed agent %s from compute endpoint %q", agentID, endpointName))
+	return nil
+}
+
 // HealthCheck proxies a health check to the remote compute agent.
 // Requires MANAGE_COMPUTE on catalog.
 func (s *ComputeEndpointService) HealthCheck(ctx context.Context, principal string, endpointName string) (*domain.ComputeEndpointHealthResult, error) {
@@ -297,7 +451,98 @@ func (s *ComputeEndpointService) HealthCheck(ctx context.Context, principal stri
 	return s.grpcHealthCheck(ctx, ep.URL, ep.AuthToken)
 }
 
+// PollLoads asks every remote agent for its health and records the reported
+// load. Unreachable agents are recorded too, so a stale heartbeat is visible.
+func (s *ComputeEndpointService) PollLoads(ctx context.Context) error {
+	if s.loads == nil {
+		return nil
+	}
+	page := domain.PageRequest{MaxResults: loadPollPageSize}
+	for {
+		eps, total, err := s.repo.List(ctx, page)
+		if err != nil {
+			return fmt.Errorf("list compute endpoints: %w", err)
+		}
+		for _, ep := range eps {
+			if ep.Type != "REMOTE" {
+				continue
+			}
+			load := s.pollLoad(ctx, ep)
+			if err := s.loads.RecordLoad(ctx, load); err != nil {
+				return fmt.Errorf("record load for %q: %w", ep.Name, err)
+			}
+		}
+		next := domain.NextPageToken(page.Offset(), page.Limit(), total)
+		if next == "" {
+			return nil
+		}
+		page.PageToken = next
+	}
+}
+
+func (s *ComputeEndpointService) pollLoad(ctx context.Context, ep domain.ComputeEndpoint) *domain.ComputeEndpointLoad {
+	now := time.Now()
+	load := &domain.ComputeEndpointLoad{EndpointID: ep.ID, CheckedAt: now}
+
+	pollCtx, cancel := context.WithTimeout(ctx, loadPollTimeout)
+	defer cancel()
+	resp, err := s.agentHealth(pollCtx, ep.URL, ep.AuthToken)
+	if err != nil {
+		load.LastError = err.Error()
+		return load
+	}
+	load.Reachable = true
+	load.ActiveQueries = resp.ActiveQueries
+	load.WaitingQueries = resp.WaitingQueries
+	load.MaxConcurrentQueries = resp.MaxConcurrentQueries
+	load.QueuedJobs = resp.QueuedJobs
+	load.RunningJobs = resp.RunningJobs
+	load.MemoryUsedMB = resp.MemoryUsedMb
+	load.LastHeartbeatAt = &now
+	return load
+}
+
+// MonitorLoad polls agent load every interval until ctx is cancelled.
+// Should be called in a background goroutine.
+func (s *ComputeEndpointService) MonitorLoad(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.PollLoads(ctx); err != nil && s.logger != nil {
+				s.logger.Warn("poll compute endpoint load failed", "error", err)
+			}
+		}
+	}
+}
+
 func (s *ComputeEndpointService) grpcHealthCheck(ctx context.Context, endpointURL, authToken string) (*domain.ComputeEndpointHealthResult, error) {
+	resp, err := s.agentHealth(ctx, endpointURL, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	status := resp.Status
+	uptime := int(resp.UptimeSeconds)
+	duckDBVersion := resp.DuckdbVersion
+	memoryUsedMB := int(resp.MemoryUsedMb)
+	maxMemoryGB := int(resp.MaxMemoryGb)
+
+	return &domain.ComputeEndpointHealthResult{
+		Status:        &status,
+		UptimeSeconds: &uptime,
+		DuckdbVersion: &duckDBVersion,
+		MemoryUsedMb:  &memoryUsedMB,
+		MaxMemoryGb:   &maxMemoryGB,
+	}, nil
+}
+
+// agentHealth calls ComputeWorker.Health on a remote agent.
+func (s *ComputeEndpointService) agentHealth(ctx context.Context, endpointURL, authToken string) (*computeproto.HealthResponse, error) {
 	u, err := url.Parse(endpointURL)
 	if err != nil {
 		return nil, fmt.Errorf("parse grpc endpoint url: %w", err)
@@ -310,7 +555,11 @@ func (s *ComputeEndpointService) grpcHealthCheck(ctx context.Context, endpointUR
 
 	creds := insecure.NewCredentials()
 	if strings.EqualFold(u.Scheme, "grpcs") {
-		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		tlsConfig := s.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		creds = credentials.NewTLS(tlsConfig)
 	}
 
 	conn, err := grpc.NewClient(
@@ -329,20 +578,7 @@ func (s *ComputeEndpointService) grpcHealthCheck(ctx context.Context, endpointUR
 	if err != nil {
 		return nil, fmt.Errorf("grpc health check failed: %w", err)
 	}
-
-	status := resp.Status
-	uptime := int(resp.UptimeSeconds)
-	duckDBVersion := resp.DuckdbVersion
-	memoryUsedMB := int(resp.MemoryUsedMb)
-	maxMemoryGB := int(resp.MaxMemoryGb)
-
-	return &domain.ComputeEndpointHealthResult{
-		Status:        &status,
-		UptimeSeconds: &uptime,
-		DuckdbVersion: &duckDBVersion,
-		MemoryUsedMb:  &memoryUsedMB,
-		MaxMemoryGb:   &maxMemoryGB,
-	}, nil
+	return resp, nil
 }
 
 // requirePrivilege checks that the principal has the given privilege on the catalog.
@@ -3,6 +3,7 @@ package compute
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log/slog"
 	"net"
 	"testing"
@@ -499,8 +500,184 @@ func TestComputeEndpointService_HealthCheck(t *testing.T) {
 	})
 }
 
+// === Load polling ===
+
+// memAgentRepo is an in-memory ComputeEndpointAgentRepository.
+type memAgentRepo struct {
+	agents []domain.ComputeEndpointAgent
+}
+
+func (r *memAgentRepo) AddAgent(_ context.Context, a *domain.ComputeEndpointAgent) (*domain.ComputeEndpointAgent, error) {
+	for _, existing := range r.agents {
+		if existing.EndpointID == a.EndpointID && existing.URL == a.URL {
+			return nil, domain.ErrConflict("agent already exists")
+		}
+	}
+	out := *a
+	out.ID = fmt.Sprintf("agent-%d", len(r.agents)+1)
+	r.agents = append(r.agents, out)
+	return &out, nil
+}
+
+func (r *memAgentRepo) RemoveAgent(_ context.Context, endpointID, agentID string) error {
+	for i, a := range r.agents {
+		if a.EndpointID == endpointID && a.ID == agentID {
+			r.agents = append(r.agents[:i], r.agents[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFound("agent %q not found", agentID)
+}
+
+func (r *memAgentRepo) ListAgents(_ context.Context, endpointID string) ([]domain.ComputeEndpointAgent, error) {
+	var out []domain.ComputeEndpointAgent
+	for _, a := range r.agents {
+		if a.EndpointID == endpointID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func TestComputeEndpointService_Agents(t *testing.T) {
+	endpoints := map[string]*domain.ComputeEndpoint{
+		"remote": {ID: "1", Name: "remote", Type: "REMOTE", URL: "grpc://compute-1:9444"},
+		"local":  {ID: "2", Name: "local", Type: "LOCAL", URL: "local"},
+	}
+	repo := &mockComputeEndpointRepo{
+		GetByNameFn: func(_ context.Context, name string) (*domain.ComputeEndpoint, error) {
+			if ep, ok := endpoints[name]; ok {
+				return ep, nil
+			}
+			return nil, domain.ErrNotFound("endpoint %q not found", name)
+		},
+	}
+	newSvc := func(auth *mockAuthService, audit *mockAuditRepo) (*ComputeEndpointService, *memAgentRepo) {
+		agents := &memAgentRepo{}
+		svc := newTestComputeEndpointService(repo, auth, audit)
+		svc.SetAgentRepository(agents)
+		return svc, agents
+	}
+	ctx := context.Background()
+
+	t.Run("add_list_remove", func(t *testing.T) {
+		audit := &mockAuditRepo{}
+		svc, _ := newSvc(allowManageCompute(), audit)
+
+		added, err := svc.AddAgent(ctx, "admin", "remote", domain.CreateComputeEndpointAgentRequest{URL: " grpc://compute-2:9444 "})
+		require.NoError(t, err)
+		assert.Equal(t, "1", added.EndpointID)
+		assert.Equal(t, "grpc://compute-2:9444", added.URL)
+		assert.True(t, audit.HasAction("ADD_COMPUTE_ENDPOINT_AGENT"))
+
+		agents, err := svc.ListAgents(ctx, "admin", "remote")
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+
+		require.NoError(t, svc.RemoveAgent(ctx, "admin", "remote", added.ID))
+		assert.True(t, audit.HasAction("REMOVE_COMPUTE_ENDPOINT_AGENT"))
+		agents, err = svc.ListAgents(ctx, "admin", "remote")
+		require.NoError(t, err)
+		assert.Empty(t, agents)
+	})
+
+	t.Run("rejects_invalid_urls", func(t *testing.T) {
+		svc, _ := newSvc(allowManageCompute(), &mockAuditRepo{})
+
+		_, err := svc.AddAgent(ctx, "admin", "remote", domain.CreateComputeEndpointAgentRequest{URL: "https://compute-2:9443"})
+		var valErr *domain.ValidationError
+		require.ErrorAs(t, err, &valErr)
+
+		_, err = svc.AddAgent(ctx, "admin", "local", domain.CreateComputeEndpointAgentRequest{URL: "grpc://compute-2:9444"})
+		require.ErrorAs(t, err, &valErr)
+
+		_, err = svc.AddAgent(ctx, "admin", "remote", domain.CreateComputeEndpointAgentRequest{URL: "grpc://compute-1:9444"})
+		var conflict *domain.ConflictError
+		require.ErrorAs(t, err, &conflict)
+	})
+
+	t.Run("remove_unknown_agent", func(t *testing.T) {
+		svc, _ := newSvc(allowManageCompute(), &mockAuditRepo{})
+
+		err := svc.RemoveAgent(ctx, "admin", "remote", "missing")
+		var notFound *domain.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("access_denied", func(t *testing.T) {
+		svc, agents := newSvc(denyManageCompute(), &mockAuditRepo{})
+
+		_, err := svc.AddAgent(ctx, "user1", "remote", domain.CreateComputeEndpointAgentRequest{URL: "grpc://compute-2:9444"})
+		var accessDenied *domain.AccessDeniedError
+		require.ErrorAs(t, err, &accessDenied)
+		assert.Empty(t, agents.agents)
+	})
+}
+
+// memLoadRepo is an in-memory ComputeEndpointLoadRepository.
+type memLoadRepo struct {
+	loads map[string]domain.ComputeEndpointLoad
+}
+
+func (r *memLoadRepo) RecordLoad(_ context.Context, load *domain.ComputeEndpointLoad) error {
+	if r.loads == nil {
+		r.loads = map[string]domain.ComputeEndpointLoad{}
+	}
+	r.loads[load.EndpointID] = *load
+	return nil
+}
+
+func (r *memLoadRepo) ListLoads(_ context.Context) (map[string]domain.ComputeEndpointLoad, error) {
+	return r.loads, nil
+}
+
+func TestComputeEndpointService_PollLoads(t *testing.T) {
+	addr := startTestComputeGRPCServerWithConfig(t, agent.HandlerConfig{
+		AgentToken:           "tok",
+		MaxConcurrentQueries: 4,
+	})
+	eps := []domain.ComputeEndpoint{
+		{ID: "ep-remote", Name: "remote", Type: "REMOTE", URL: "grpc://" + addr, AuthToken: "tok"},
+		{ID: "ep-down", Name: "down", Type: "REMOTE", URL: "grpc://127.0.0.1:1", AuthToken: "tok"},
+		{ID: "ep-local", Name: "local", Type: "LOCAL", URL: "local"},
+	}
+	repo := &mockComputeEndpointRepo{
+		ListFn: func(_ context.Context, _ domain.PageRequest) ([]domain.ComputeEndpoint, int64, error) {
+			out := make([]domain.ComputeEndpoint, len(eps))
+			copy(out, eps)
+			return out, int64(len(out)), nil
+		},
+	}
+	loads := &memLoadRepo{}
+	svc := newTestComputeEndpointService(repo, allowManageCompute(), &mockAuditRepo{})
+	svc.SetLoadRepository(loads)
+
+	require.NoError(t, svc.PollLoads(context.Background()))
+	require.Len(t, loads.loads, 2, "local endpoints are not polled")
+	assert.False(t, loads.loads["ep-down"].Reachable)
+	assert.Contains(t, loads.loads["ep-down"].LastError, "grpc health check failed")
+	assert.Nil(t, loads.loads["ep-down"].LastHeartbeatAt)
+
+	listed, _, err := svc.List(context.Background(), "admin", domain.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, listed, 3)
+	require.NotNil(t, listed[0].Load)
+	assert.True(t, listed[0].Load.Reachable)
+	assert.Equal(t, int64(4), listed[0].Load.MaxConcurrentQueries)
+	assert.Equal(t, domain.ScalingHintIdle, listed[0].Load.ScalingHint())
+	assert.NotNil(t, listed[0].Load.LastHeartbeatAt)
+	require.NotNil(t, listed[1].Load)
+	assert.Equal(t, domain.ScalingHintUnreachable, listed[1].Load.ScalingHint())
+	assert.Nil(t, listed[2].Load)
+}
+
 func startTestComputeGRPCServer(t *testing.T, token string) string {
 	t.Helper()
+	return startTestComputeGRPCServerWithConfig(t, agent.HandlerConfig{AgentToken: token})
+}
+
+func startTestComputeGRPCServerWithConfig(t *testing.T, cfg agent.HandlerConfig) string {
+	t.Helper()
 
 	db, err := sql.Open("duckdb", "")
 	require.NoError(t, err)
@@ -508,13 +685,11 @@ func startTestComputeGRPCServer(t *testing.T, token string) string {
 		_ = db.Close()
 	})
 
-	server := agent.NewComputeGRPCServer(agent.HandlerConfig{
-		DB:         db,
-		AgentToken: token,
-		StartTime:  time.Now(),
-		CursorMode: true,
-		Logger:     slog.Default(),
-	})
+	cfg.DB = db
+	cfg.StartTime = time.Now()
+	cfg.CursorMode = true
+	cfg.Logger = slog.Default()
+	server := agent.NewComputeGRPCServer(cfg)
 
 	grpcServer := grpc.NewServer()
 	agent.RegisterComputeWorkerGRPCServer(grpcServer, server)
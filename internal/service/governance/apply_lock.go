@@ -0,0 +1,140 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/auditutil"
+)
+
+// ApplyLockService manages the advisory lease that serializes declarative
+// applies. The lease is advisory: it only coordinates clients that ask for it.
+type ApplyLockService struct {
+	repo  domain.ApplyLockRepository
+	audit domain.AuditRepository
+	now   func() time.Time
+}
+
+// NewApplyLockService creates a new ApplyLockService.
+func NewApplyLockService(repo domain.ApplyLockRepository, audit domain.AuditRepository) *ApplyLockService {
+	return &ApplyLockService{repo: repo, audit: audit, now: time.Now}
+}
+
+// Get returns the current lease. Returns NotFoundError when the lock is free
+// or the last lease has expired.
+func (s *ApplyLockService) Get(ctx context.Context) (*domain.ApplyLock, error) {
+	lock, err := s.repo.Get(ctx, domain.ApplyLockName)
+	if err != nil {
+		return nil, err
+	}
+	if lock.Expired(s.now()) {
+		return nil, domain.ErrNotFound("lock %q is not held", domain.ApplyLockName)
+	}
+	return lock, nil
+}
+
+// Acquire takes the apply lock for principal. An expired lease is taken over
+// automatically; a live lease is only broken when req.Force is set. Returns
+// ConflictError naming the current holder when the lock is held.
+func (s *ApplyLockService) Acquire(ctx context.Context, principal string, req domain.AcquireApplyLockRequest) (*domain.ApplyLock, error) {
+	ttl, err := applyLockTTL(req.TTL)
+	if err != nil {
+		return nil, err
+	}
+	owner := req.Owner
+	if owner == "" {
+		owner = principal
+	}
+
+	// Read the previous holder first so a forced takeover can be audited.
+	var previous *domain.ApplyLock
+	if req.Force {
+		prev, err := s.repo.Get(ctx, domain.ApplyLockName)
+		var notFound *domain.NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("read apply lock: %w", err)
+		}
+		previous = prev
+	}
+
+	now := s.now()
+	lock := &domain.ApplyLock{
+		Name:          domain.ApplyLockName,
+		LeaseID:       domain.NewID(),
+		Owner:         owner,
+		PrincipalName: principal,
+		AcquiredAt:    now,
+		ExpiresAt:     now.Add(ttl),
+	}
+	acquired, err := s.repo.TryAcquire(ctx, lock, now, req.Force)
+	if err != nil {
+		return nil, fmt.Errorf("acquire apply lock: %w", err)
+	}
+	if !acquired {
+		holder, err := s.repo.Get(ctx, domain.ApplyLockName)
+		if err != nil {
+			return nil, domain.ErrConflict("apply lock is held by another apply")
+		}
+		return nil, domain.ErrConflict("apply lock is held by %q (principal %q) until %s",
+			holder.Owner, holder.PrincipalName, holder.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+
+	if previous != nil && !previous.Expired(now) {
+		auditutil.LogAllowed(ctx, s.audit, principal, "BREAK_APPLY_LOCK",
+			fmt.Sprintf("Broke apply lock held by %q (principal %q)", previous.Owner, previous.PrincipalName))
+	}
+	return lock, nil
+}
+
+// Release gives up the lease identified by leaseID. Returns NotFoundError if
+// the lease is no longer held, e.g. because it expired and was taken over.
+func (s *ApplyLockService) Release(ctx context.Context, leaseID string) error {
+	if leaseID == "" {
+		return domain.ErrValidation("lease_id is required")
+	}
+	released, err := s.repo.Release(ctx, domain.ApplyLockName, leaseID)
+	if err != nil {
+		return fmt.Errorf("release apply lock: %w", err)
+	}
+	if !released {
+		return domain.ErrNotFound("apply lock lease %q is not held", leaseID)
+	}
+	return nil
+}
+
+// Renew extends the lease identified by leaseID to expire ttl from now; a
+// zero ttl uses the default. Returns NotFoundError if the lease is no longer
+// held, e.g. because it expired and was taken over.
+func (s *ApplyLockService) Renew(ctx context.Context, leaseID string, ttl time.Duration) (*domain.ApplyLock, error) {
+	if leaseID == "" {
+		return nil, domain.ErrValidation("lease_id is required")
+	}
+	ttl, err := applyLockTTL(ttl)
+	if err != nil {
+		return nil, err
+	}
+	renewed, err := s.repo.Renew(ctx, domain.ApplyLockName, leaseID, s.now().Add(ttl))
+	if err != nil {
+		return nil, fmt.Errorf("renew apply lock: %w", err)
+	}
+	if !renewed {
+		return nil, domain.ErrNotFound("apply lock lease %q is not held", leaseID)
+	}
+	return s.repo.Get(ctx, domain.ApplyLockName)
+}
+
+// applyLockTTL validates a requested lease duration and applies the default.
+func applyLockTTL(ttl time.Duration) (time.Duration, error) {
+	switch {
+	case ttl == 0:
+		return domain.DefaultApplyLockTTL, nil
+	case ttl < time.Second:
+		return 0, domain.ErrValidation("lock ttl must be at least 1 second")
+	case ttl > domain.MaxApplyLockTTL:
+		return 0, domain.ErrValidation("lock ttl must not exceed %s", domain.MaxApplyLockTTL)
+	}
+	return ttl, nil
+}
@@ -0,0 +1,178 @@
+package governance
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// memApplyLockRepo is an in-memory ApplyLockRepository with the same
+// takeover rule as the SQLite upsert.
+type memApplyLockRepo struct {
+	mu    sync.Mutex
+	locks map[string]domain.ApplyLock
+}
+
+func newMemApplyLockRepo() *memApplyLockRepo {
+	return &memApplyLockRepo{locks: make(map[string]domain.ApplyLock)}
+}
+
+func (r *memApplyLockRepo) Get(_ context.Context, name string) (*domain.ApplyLock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lock, ok := r.locks[name]
+	if !ok {
+		return nil, domain.ErrNotFound("lock %q is not held", name)
+	}
+	return &lock, nil
+}
+
+func (r *memApplyLockRepo) TryAcquire(_ context.Context, lock *domain.ApplyLock, now time.Time, force bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cur, ok := r.locks[lock.Name]; ok && !force && !cur.Expired(now) {
+		return false, nil
+	}
+	r.locks[lock.Name] = *lock
+	return true, nil
+}
+
+func (r *memApplyLockRepo) Release(_ context.Context, name, leaseID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cur, ok := r.locks[name]; !ok || cur.LeaseID != leaseID {
+		return false, nil
+	}
+	delete(r.locks, name)
+	return true, nil
+}
+
+func (r *memApplyLockRepo) Renew(_ context.Context, name, leaseID string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.locks[name]
+	if !ok || cur.LeaseID != leaseID {
+		return false, nil
+	}
+	cur.ExpiresAt = expiresAt
+	r.locks[name] = cur
+	return true, nil
+}
+
+func newTestApplyLockService(audit *mockAuditRepo) (*ApplyLockService, *time.Time) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	svc := NewApplyLockService(newMemApplyLockRepo(), audit)
+	svc.now = func() time.Time { return now }
+	return svc, &now
+}
+
+func TestApplyLockService_Acquire(t *testing.T) {
+	svc, now := newTestApplyLockService(&mockAuditRepo{})
+
+	lock, err := svc.Acquire(context.Background(), "alice", domain.AcquireApplyLockRequest{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, lock.LeaseID)
+	assert.Equal(t, "alice", lock.Owner, "owner defaults to the principal")
+	assert.Equal(t, now.Add(domain.DefaultApplyLockTTL), lock.ExpiresAt)
+
+	held, err := svc.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, lock.LeaseID, held.LeaseID)
+
+	require.NoError(t, svc.Release(context.Background(), lock.LeaseID))
+	_, err = svc.Get(context.Background())
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
+
+func TestApplyLockService_ContentionBlocksSecondApply(t *testing.T) {
+	svc, _ := newTestApplyLockService(&mockAuditRepo{})
+
+	_, err := svc.Acquire(context.Background(), "alice", domain.AcquireApplyLockRequest{Owner: "alice@laptop"})
+	require.NoError(t, err)
+
+	_, err = svc.Acquire(context.Background(), "bob", domain.AcquireApplyLockRequest{Owner: "bob@ci"})
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Contains(t, err.Error(), "alice@laptop")
+}
+
+func TestApplyLockService_StaleLeaseTakeover(t *testing.T) {
+	t.Run("expired lease is taken over", func(t *testing.T) {
+		svc, now := newTestApplyLockService(&mockAuditRepo{})
+
+		first, err := svc.Acquire(context.Background(), "alice", domain.AcquireApplyLockRequest{TTL: time.Minute})
+		require.NoError(t, err)
+
+		*now = now.Add(time.Minute)
+		second, err := svc.Acquire(context.Background(), "bob", domain.AcquireApplyLockRequest{})
+		require.NoError(t, err)
+		assert.NotEqual(t, first.LeaseID, second.LeaseID)
+
+		// The original holder finds its lease gone on release.
+		var notFound *domain.NotFoundError
+		require.ErrorAs(t, svc.Release(context.Background(), first.LeaseID), &notFound)
+	})
+
+	t.Run("force breaks a live lease and is audited", func(t *testing.T) {
+		audit := &mockAuditRepo{}
+		svc, _ := newTestApplyLockService(audit)
+
+		_, err := svc.Acquire(context.Background(), "alice", domain.AcquireApplyLockRequest{})
+		require.NoError(t, err)
+
+		lock, err := svc.Acquire(context.Background(), "bob", domain.AcquireApplyLockRequest{Force: true})
+		require.NoError(t, err)
+		assert.Equal(t, "bob", lock.PrincipalName)
+		assert.True(t, audit.HasAction("BREAK_APPLY_LOCK"))
+	})
+}
+
+func TestApplyLockService_AcquireValidatesTTL(t *testing.T) {
+	svc, _ := newTestApplyLockService(&mockAuditRepo{})
+
+	for _, ttl := range []time.Duration{-time.Second, time.Millisecond, domain.MaxApplyLockTTL + time.Second} {
+		_, err := svc.Acquire(context.Background(), "alice", domain.AcquireApplyLockRequest{TTL: ttl})
+		var validation *domain.ValidationError
+		require.ErrorAs(t, err, &validation, "ttl %s", ttl)
+	}
+}
+
+func TestApplyLockService_Renew(t *testing.T) {
+	t.Run("renewal keeps a long apply from losing its lease", func(t *testing.T) {
+		svc, now := newTestApplyLockService(&mockAuditRepo{})
+
+		lock, err := svc.Acquire(context.Background(), "alice", domain.AcquireApplyLockRequest{TTL: time.Minute})
+		require.NoError(t, err)
+
+		*now = now.Add(50 * time.Second)
+		renewed, err := svc.Renew(context.Background(), lock.LeaseID, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(time.Minute), renewed.ExpiresAt)
+
+		// Past the original expiry the renewed lease still blocks others.
+		*now = now.Add(30 * time.Second)
+		_, err = svc.Acquire(context.Background(), "bob", domain.AcquireApplyLockRequest{})
+		var conflict *domain.ConflictError
+		require.ErrorAs(t, err, &conflict)
+	})
+
+	t.Run("a lease that was taken over cannot be renewed", func(t *testing.T) {
+		svc, _ := newTestApplyLockService(&mockAuditRepo{})
+
+		first, err := svc.Acquire(context.Background(), "alice", domain.AcquireApplyLockRequest{})
+		require.NoError(t, err)
+		_, err = svc.Acquire(context.Background(), "bob", domain.AcquireApplyLockRequest{Force: true})
+		require.NoError(t, err)
+
+		_, err = svc.Renew(context.Background(), first.LeaseID, 0)
+		var notFound *domain.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+	})
+}
@@ -3,6 +3,9 @@ package governance
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"duck-demo/internal/domain"
 )
@@ -10,11 +13,30 @@ import (
 // AuditService provides audit log operations.
 type AuditService struct {
 	repo domain.AuditRepository
+
+	// Archiving; store is nil when it is not configured.
+	archives         domain.AuditArchiveRepository
+	store            ArchiveStore
+	retentionDays    int
+	archiveBatchSize int
+
+	// archiveMu serializes archive runs so the background reaper and a
+	// manual archive never chain off the same previous archive.
+	archiveMu sync.Mutex
 }
 
 // NewAuditService creates a new AuditService.
 func NewAuditService(repo domain.AuditRepository) *AuditService {
-	return &AuditService{repo: repo}
+	return &AuditService{repo: repo, archiveBatchSize: auditArchiveBatchSize}
+}
+
+// SetArchiving enables archival of the audit log to store. Entries older than
+// retentionDays are archived and then deleted in the background; zero
+// archives only on request.
+func (s *AuditService) SetArchiving(archives domain.AuditArchiveRepository, store ArchiveStore, retentionDays int) {
+	s.archives = archives
+	s.store = store
+	s.retentionDays = retentionDays
 }
 
 // List returns a filtered, paginated list of audit log entries. Requires admin privileges.
@@ -24,3 +46,146 @@ func (s *AuditService) List(ctx context.Context, filter domain.AuditFilter) ([]d
 	}
 	return s.repo.List(ctx, filter)
 }
+
+// Archive writes audit log entries recorded before the cutoff to the archive
+// store as hash-chained NDJSON, then deletes them. An entry is deleted only
+// once an archive holding it has been written and recorded. A nil cutoff
+// applies the retention period, which must then be configured. Requires admin
+// privileges.
+func (s *AuditService) Archive(ctx context.Context, before *time.Time) (*domain.AuditArchiveResult, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if s.store == nil {
+		return nil, domain.ErrValidation("audit log archiving is not configured; set AUDIT_ARCHIVE_LOCATION and AUDIT_ARCHIVE_CREDENTIAL")
+	}
+	if before == nil {
+		if s.retentionDays <= 0 {
+			return nil, domain.ErrValidation("no audit log retention period is configured; give older_than or before")
+		}
+		cutoff := s.retentionCutoff(time.Now())
+		before = &cutoff
+	}
+
+	result, err := s.archive(ctx, *before)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, fmt.Sprintf("ARCHIVE_AUDIT_LOG(%d archived, %d deleted)", result.Archived, result.Deleted))
+	return result, nil
+}
+
+// ArchiveExpired archives and deletes audit log entries older than the
+// retention period. It does nothing when archiving or retention is not
+// configured.
+func (s *AuditService) ArchiveExpired(ctx context.Context) (*domain.AuditArchiveResult, error) {
+	if s.store == nil || s.retentionDays <= 0 {
+		return &domain.AuditArchiveResult{}, nil
+	}
+	return s.archive(ctx, s.retentionCutoff(time.Now()))
+}
+
+// ReapExpired archives expired audit log entries every interval until ctx is
+// cancelled. Should be called in a background goroutine.
+func (s *AuditService) ReapExpired(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.ArchiveExpired(ctx)
+		}
+	}
+}
+
+func (s *AuditService) archive(ctx context.Context, before time.Time) (*domain.AuditArchiveResult, error) {
+	s.archiveMu.Lock()
+	defer s.archiveMu.Unlock()
+
+	// The store keeps second precision, so a partial second is never split
+	// across archives.
+	before = before.Truncate(time.Second)
+	result := &domain.AuditArchiveResult{Before: before}
+
+	// Entries archived by a run that failed before deleting them go first.
+	deleted, err := s.archives.DeleteArchived(ctx)
+	result.Deleted += deleted
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		latest, err := s.archives.Latest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := s.archives.ListUnarchived(ctx, before, s.archiveBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return result, nil
+		}
+
+		var prevHash string
+		if latest != nil {
+			prevHash = latest.Hash
+		}
+		data, hash, err := encodeAuditArchive(prevHash, entries)
+		if err != nil {
+			return nil, fmt.Errorf("encode audit archive: %w", err)
+		}
+		first, last := entries[0], entries[len(entries)-1]
+		location, err := s.store.Put(ctx, auditArchiveName(last, hash), data)
+		if err != nil {
+			return nil, err
+		}
+		a := &domain.AuditArchive{
+			Location:       location,
+			EntryCount:     int64(len(entries)),
+			FirstEntryID:   first.ID,
+			LastEntryID:    last.ID,
+			FirstCreatedAt: first.CreatedAt,
+			LastCreatedAt:  last.CreatedAt,
+			PrevHash:       prevHash,
+			Hash:           hash,
+		}
+		if err := s.archives.Create(ctx, a); err != nil {
+			return nil, fmt.Errorf("record audit archive %s: %w", location, err)
+		}
+		result.Archives = append(result.Archives, *a)
+		result.Archived += a.EntryCount
+
+		deleted, err := s.archives.DeleteArchived(ctx)
+		result.Deleted += deleted
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) < s.archiveBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// retentionCutoff is the time before which audit log entries have outlived
+// the retention period.
+func (s *AuditService) retentionCutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -s.retentionDays)
+}
+
+func (s *AuditService) logAudit(ctx context.Context, action string) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		principal.Name = "system"
+	}
+
+	_ = s.repo.Insert(ctx, &domain.AuditEntry{
+		PrincipalName: principal.Name,
+		Action:        action,
+		Status:        "ALLOWED",
+	})
+}
@@ -0,0 +1,206 @@
+package governance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/query"
+)
+
+// auditArchiveBatchSize is the most entries written to one archive object.
+const auditArchiveBatchSize = 10000
+
+// auditArchiveEntry is the archived form of an audit log entry. Its JSON
+// encoding is what the hash chain covers, so fields must not be reordered.
+type auditArchiveEntry struct {
+	ID             string   `json:"id"`
+	PrincipalName  string   `json:"principal_name"`
+	Action         string   `json:"action"`
+	StatementType  *string  `json:"statement_type"`
+	OriginalSQL    *string  `json:"original_sql"`
+	RewrittenSQL   *string  `json:"rewritten_sql"`
+	TablesAccessed []string `json:"tables_accessed"`
+	Status         string   `json:"status"`
+	ErrorMessage   *string  `json:"error_message"`
+	DurationMs     *int64   `json:"duration_ms"`
+	RowsReturned   *int64   `json:"rows_returned"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+// auditArchiveRecord is one NDJSON line of an archive: an entry and its link
+// in the hash chain.
+type auditArchiveRecord struct {
+	auditArchiveEntry
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// auditChainHash links an entry to the hash of the entry before it.
+func auditChainHash(prevHash string, e auditArchiveEntry) (string, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(prevHash))
+	h.Write([]byte{'\n'})
+	h.Write(data)
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// encodeAuditArchive writes entries as hash-chained NDJSON continuing from
+// prevHash and returns the hash of the last entry.
+func encodeAuditArchive(prevHash string, entries []domain.AuditEntry) ([]byte, string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	hash := prevHash
+	for _, e := range entries {
+		rec := auditArchiveRecord{
+			auditArchiveEntry: auditArchiveEntry{
+				ID:             e.ID,
+				PrincipalName:  e.PrincipalName,
+				Action:         e.Action,
+				StatementType:  e.StatementType,
+				OriginalSQL:    e.OriginalSQL,
+				RewrittenSQL:   e.RewrittenSQL,
+				TablesAccessed: e.TablesAccessed,
+				Status:         e.Status,
+				ErrorMessage:   e.ErrorMessage,
+				DurationMs:     e.DurationMs,
+				RowsReturned:   e.RowsReturned,
+				CreatedAt:      e.CreatedAt.UTC().Format(time.RFC3339),
+			},
+			PrevHash: hash,
+		}
+		var err error
+		if rec.Hash, err = auditChainHash(hash, rec.auditArchiveEntry); err != nil {
+			return nil, "", err
+		}
+		if err := enc.Encode(rec); err != nil {
+			return nil, "", err
+		}
+		hash = rec.Hash
+	}
+	return buf.Bytes(), hash, nil
+}
+
+// VerifyAuditArchive checks the hash chain of an archive read from r, which
+// must continue from prevHash, the Hash of the archive before it (empty for
+// the first archive). It returns the hash the archive ends with and how many
+// entries it holds. Verifying archives in order with each result as the next
+// prevHash checks the chain across archive boundaries.
+func VerifyAuditArchive(r io.Reader, prevHash string) (string, int64, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	hash := prevHash
+	var n int64
+	for sc.Scan() {
+		n++
+		var rec auditArchiveRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return "", n, fmt.Errorf("entry %d: %w", n, err)
+		}
+		if rec.PrevHash != hash {
+			return "", n, fmt.Errorf("entry %d (%s): chain broken: prev_hash %q, expected %q", n, rec.ID, rec.PrevHash, hash)
+		}
+		want, err := auditChainHash(hash, rec.auditArchiveEntry)
+		if err != nil {
+			return "", n, err
+		}
+		if rec.Hash != want {
+			return "", n, fmt.Errorf("entry %d (%s): hash mismatch: entry was modified", n, rec.ID)
+		}
+		hash = rec.Hash
+	}
+	if err := sc.Err(); err != nil {
+		return "", n, err
+	}
+	return hash, n, nil
+}
+
+// ArchiveStore writes audit archive objects to durable storage.
+type ArchiveStore interface {
+	// Put stores data under name and returns the object's location.
+	Put(ctx context.Context, name string, data []byte) (string, error)
+}
+
+// ObjectArchiveStore writes archives below an object store location, such as
+// s3://bucket/audit/, with the keys of a storage credential.
+type ObjectArchiveStore struct {
+	creds      domain.StorageCredentialRepository
+	credential string
+	location   string
+	client     *http.Client
+}
+
+// NewObjectArchiveStore creates an ObjectArchiveStore writing below location
+// with the named storage credential.
+func NewObjectArchiveStore(creds domain.StorageCredentialRepository, credential, location string) *ObjectArchiveStore {
+	return &ObjectArchiveStore{
+		creds:      creds,
+		credential: credential,
+		location:   strings.TrimSuffix(location, "/") + "/",
+		client:     &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Put uploads data through a presigned PUT URL, so every storage type with
+// an upload presigner is supported.
+func (s *ObjectArchiveStore) Put(ctx context.Context, name string, data []byte) (string, error) {
+	cred, err := s.creds.GetByName(ctx, s.credential)
+	if err != nil {
+		return "", fmt.Errorf("audit archive credential %q: %w", s.credential, err)
+	}
+	presigner, err := query.NewUploadPresignerFromCredential(cred, s.location)
+	if err != nil {
+		return "", fmt.Errorf("audit archive credential %q: %w", s.credential, err)
+	}
+	u, err := url.Parse(s.location)
+	if err != nil {
+		return "", fmt.Errorf("parse audit archive location %q: %w", s.location, err)
+	}
+	key := path.Join(strings.TrimPrefix(u.Path, "/"), name)
+	putURL, err := presigner.PresignPutObject(ctx, presigner.Bucket(), key, 15*time.Minute)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, putURL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("x-ms-blob-type", "BlockBlob") // required by Azure, ignored elsewhere
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("upload audit archive: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("upload audit archive: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return s.location + name, nil
+}
+
+// auditArchiveName names the object for an archive by its last entry, so
+// names sort in chain order.
+func auditArchiveName(last domain.AuditEntry, hash string) string {
+	short := strings.TrimPrefix(hash, "sha256:")
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	return fmt.Sprintf("audit-%s-%s.ndjson", last.CreatedAt.UTC().Format("20060102T150405Z"), short)
+}
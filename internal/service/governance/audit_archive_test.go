@@ -0,0 +1,277 @@
+package governance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+// memAuditArchiveRepo keeps audit entries and archives in memory with the
+// same archived-mark semantics as the SQLite repository.
+type memAuditArchiveRepo struct {
+	entries  []domain.AuditEntry // oldest first
+	archives []domain.AuditArchive
+}
+
+func (r *memAuditArchiveRepo) Latest(_ context.Context) (*domain.AuditArchive, error) {
+	if len(r.archives) == 0 {
+		return nil, nil
+	}
+	a := r.archives[len(r.archives)-1]
+	return &a, nil
+}
+
+// archived reports whether e is at or before the latest archive's last entry.
+func (r *memAuditArchiveRepo) archived(e domain.AuditEntry) bool {
+	if len(r.archives) == 0 {
+		return false
+	}
+	last := r.archives[len(r.archives)-1]
+	return e.CreatedAt.Before(last.LastCreatedAt) ||
+		(e.CreatedAt.Equal(last.LastCreatedAt) && e.ID <= last.LastEntryID)
+}
+
+func (r *memAuditArchiveRepo) ListUnarchived(_ context.Context, before time.Time, limit int) ([]domain.AuditEntry, error) {
+	var out []domain.AuditEntry
+	for _, e := range r.entries {
+		if len(out) == limit {
+			break
+		}
+		if e.CreatedAt.Before(before) && !r.archived(e) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r *memAuditArchiveRepo) Create(_ context.Context, a *domain.AuditArchive) error {
+	r.archives = append(r.archives, *a)
+	return nil
+}
+
+func (r *memAuditArchiveRepo) DeleteArchived(_ context.Context) (int64, error) {
+	var kept []domain.AuditEntry
+	for _, e := range r.entries {
+		if !r.archived(e) {
+			kept = append(kept, e)
+		}
+	}
+	deleted := int64(len(r.entries) - len(kept))
+	r.entries = kept
+	return deleted, nil
+}
+
+// memArchiveStore keeps archive objects in memory.
+type memArchiveStore struct {
+	names   []string
+	objects map[string][]byte
+	err     error
+}
+
+func (s *memArchiveStore) Put(_ context.Context, name string, data []byte) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	if s.objects == nil {
+		s.objects = make(map[string][]byte)
+	}
+	s.names = append(s.names, name)
+	s.objects[name] = data
+	return "mem://audit/" + name, nil
+}
+
+func auditEntriesAt(start time.Time, n int) []domain.AuditEntry {
+	entries := make([]domain.AuditEntry, n)
+	for i := range entries {
+		entries[i] = domain.AuditEntry{
+			ID:            fmt.Sprintf("entry-%02d", i),
+			PrincipalName: "alice",
+			Action:        "GRANT",
+			Status:        "ALLOWED",
+			CreatedAt:     start.Add(time.Duration(i) * time.Hour),
+		}
+	}
+	return entries
+}
+
+func newArchivingAuditService(t *testing.T, entries []domain.AuditEntry) (*AuditService, *memAuditArchiveRepo, *memArchiveStore, *mockAuditRepo) {
+	t.Helper()
+	audit := &mockAuditRepo{}
+	archives := &memAuditArchiveRepo{entries: entries}
+	store := &memArchiveStore{}
+	svc := NewAuditService(audit)
+	svc.SetArchiving(archives, store, 0)
+	return svc, archives, store, audit
+}
+
+func TestAuditService_Archive(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("archives_expected_records_then_deletes_them", func(t *testing.T) {
+		entries := auditEntriesAt(start, 5)
+		sql := "GRANT SELECT ON orders TO analysts"
+		entries[1].OriginalSQL = &sql
+		entries[1].TablesAccessed = []string{"main.orders"}
+		svc, archives, store, audit := newArchivingAuditService(t, entries)
+
+		cutoff := start.Add(3 * time.Hour) // entries 0, 1 and 2 are older
+		result, err := svc.Archive(adminCtx(), &cutoff)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(3), result.Archived)
+		assert.Equal(t, int64(3), result.Deleted)
+		require.Len(t, result.Archives, 1)
+		a := result.Archives[0]
+		assert.Equal(t, "entry-00", a.FirstEntryID)
+		assert.Equal(t, "entry-02", a.LastEntryID)
+		assert.Empty(t, a.PrevHash, "the first archive starts the chain")
+
+		require.Len(t, store.names, 1)
+		assert.Equal(t, "mem://audit/"+store.names[0], a.Location)
+		var lines []auditArchiveRecord
+		sc := bufio.NewScanner(bytes.NewReader(store.objects[store.names[0]]))
+		for sc.Scan() {
+			var rec auditArchiveRecord
+			require.NoError(t, json.Unmarshal(sc.Bytes(), &rec))
+			lines = append(lines, rec)
+		}
+		require.Len(t, lines, 3)
+		for i, rec := range lines {
+			assert.Equal(t, entries[i].ID, rec.ID)
+			assert.Equal(t, entries[i].CreatedAt.Format(time.RFC3339), rec.CreatedAt)
+		}
+		assert.Equal(t, &sql, lines[1].OriginalSQL)
+		assert.Equal(t, []string{"main.orders"}, lines[1].TablesAccessed)
+		assert.Equal(t, a.Hash, lines[2].Hash)
+
+		// Entries newer than the cutoff are kept.
+		require.Len(t, archives.entries, 2)
+		assert.Equal(t, "entry-03", archives.entries[0].ID)
+
+		require.Len(t, audit.Entries, 1)
+		assert.Equal(t, "ARCHIVE_AUDIT_LOG(3 archived, 3 deleted)", audit.Entries[0].Action)
+	})
+
+	t.Run("chain_is_verifiable_across_archives", func(t *testing.T) {
+		svc, _, store, _ := newArchivingAuditService(t, auditEntriesAt(start, 7))
+		svc.archiveBatchSize = 3 // split the run into several archives
+
+		first := start.Add(5 * time.Hour)
+		result, err := svc.Archive(adminCtx(), &first)
+		require.NoError(t, err)
+		require.Len(t, result.Archives, 2)
+		second := start.Add(24 * time.Hour)
+		result, err = svc.Archive(adminCtx(), &second)
+		require.NoError(t, err)
+		require.Len(t, result.Archives, 1)
+
+		require.Len(t, store.names, 3)
+		hash := ""
+		var total int64
+		for _, name := range store.names {
+			next, n, err := VerifyAuditArchive(bytes.NewReader(store.objects[name]), hash)
+			require.NoError(t, err, name)
+			hash = next
+			total += n
+		}
+		assert.Equal(t, int64(7), total)
+		assert.Equal(t, result.Archives[0].Hash, hash)
+	})
+
+	t.Run("upload_failure_deletes_nothing", func(t *testing.T) {
+		svc, archives, store, _ := newArchivingAuditService(t, auditEntriesAt(start, 3))
+		store.err = errors.New("bucket unavailable")
+
+		cutoff := start.Add(24 * time.Hour)
+		_, err := svc.Archive(adminCtx(), &cutoff)
+		require.Error(t, err)
+		assert.Len(t, archives.entries, 3)
+		assert.Empty(t, archives.archives)
+	})
+
+	t.Run("nil_cutoff_applies_retention", func(t *testing.T) {
+		now := time.Now().UTC()
+		entries := []domain.AuditEntry{
+			{ID: "old", Action: "GRANT", Status: "ALLOWED", CreatedAt: now.AddDate(0, 0, -400)},
+			{ID: "new", Action: "GRANT", Status: "ALLOWED", CreatedAt: now.AddDate(0, 0, -10)},
+		}
+		svc, archives, _, _ := newArchivingAuditService(t, entries)
+		svc.retentionDays = 365
+
+		result, err := svc.Archive(adminCtx(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Archived)
+		require.Len(t, archives.entries, 1)
+		assert.Equal(t, "new", archives.entries[0].ID)
+	})
+
+	t.Run("nil_cutoff_without_retention", func(t *testing.T) {
+		svc, _, _, _ := newArchivingAuditService(t, nil)
+
+		_, err := svc.Archive(adminCtx(), nil)
+		require.Error(t, err)
+		assert.True(t, errors.As(err, new(*domain.ValidationError)))
+	})
+
+	t.Run("not_configured", func(t *testing.T) {
+		svc := NewAuditService(&mockAuditRepo{})
+		cutoff := time.Now()
+
+		_, err := svc.Archive(adminCtx(), &cutoff)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AUDIT_ARCHIVE_LOCATION")
+	})
+
+	t.Run("requires_admin", func(t *testing.T) {
+		svc, _, _, _ := newArchivingAuditService(t, nil)
+		cutoff := time.Now()
+
+		_, err := svc.Archive(nonAdminCtx(), &cutoff)
+		require.Error(t, err)
+		assert.True(t, errors.As(err, new(*domain.AccessDeniedError)))
+	})
+}
+
+func TestAuditService_ArchiveExpired_NotConfigured(t *testing.T) {
+	svc := NewAuditService(&mockAuditRepo{})
+
+	result, err := svc.ArchiveExpired(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, result.Archived)
+}
+
+func TestVerifyAuditArchive_DetectsTampering(t *testing.T) {
+	entries := auditEntriesAt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 3)
+	data, hash, err := encodeAuditArchive("sha256:prev", entries)
+	require.NoError(t, err)
+
+	got, n, err := VerifyAuditArchive(bytes.NewReader(data), "sha256:prev")
+	require.NoError(t, err)
+	assert.Equal(t, hash, got)
+	assert.Equal(t, int64(3), n)
+
+	_, _, err = VerifyAuditArchive(bytes.NewReader(data), "")
+	require.Error(t, err, "an archive must continue from the previous archive's hash")
+	assert.Contains(t, err.Error(), "chain broken")
+
+	tampered := strings.Replace(string(data), `"principal_name":"alice"`, `"principal_name":"mallory"`, 1)
+	_, _, err = VerifyAuditArchive(strings.NewReader(tampered), "sha256:prev")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "entry was modified")
+
+	lines := strings.SplitAfter(string(data), "\n")
+	dropped := lines[0] + lines[2]
+	_, _, err = VerifyAuditArchive(strings.NewReader(dropped), "sha256:prev")
+	require.Error(t, err, "a removed entry breaks the chain")
+}
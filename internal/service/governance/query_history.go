@@ -2,24 +2,121 @@ package governance
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"duck-demo/internal/domain"
 )
 
 // QueryHistoryService provides query history operations.
 type QueryHistoryService struct {
-	repo domain.QueryHistoryRepository
+	repo          domain.QueryHistoryRepository
+	audit         domain.AuditRepository
+	retentionDays int
 }
 
 // NewQueryHistoryService creates a new QueryHistoryService.
-func NewQueryHistoryService(repo domain.QueryHistoryRepository) *QueryHistoryService {
-	return &QueryHistoryService{repo: repo}
+func NewQueryHistoryService(repo domain.QueryHistoryRepository, audit ...domain.AuditRepository) *QueryHistoryService {
+	var auditRepo domain.AuditRepository
+	if len(audit) > 0 {
+		auditRepo = audit[0]
+	}
+	return &QueryHistoryService{repo: repo, audit: auditRepo}
+}
+
+// SetRetention sets how many days of query history are kept. Zero keeps it
+// until it is purged explicitly.
+func (s *QueryHistoryService) SetRetention(days int) {
+	s.retentionDays = days
 }
 
-// List returns a paginated list of query history entries. Requires admin privileges.
+// List returns a paginated list of query history entries. Listing every
+// principal's history requires admin privileges; with filter.Mine set the
+// results are scoped to the caller and any authenticated principal may list them.
 func (s *QueryHistoryService) List(ctx context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error) {
+	if filter.Mine {
+		p, ok := domain.PrincipalFromContext(ctx)
+		if !ok {
+			return nil, 0, domain.ErrAccessDenied("authentication required")
+		}
+		filter.PrincipalName = &p.Name
+		return s.repo.List(ctx, filter)
+	}
 	if err := requireAdmin(ctx); err != nil {
 		return nil, 0, err
 	}
 	return s.repo.List(ctx, filter)
 }
+
+// Purge deletes query history recorded before the cutoff. A nil cutoff
+// applies the retention period, which must then be configured. Requires admin
+// privileges.
+func (s *QueryHistoryService) Purge(ctx context.Context, before *time.Time) (*domain.QueryHistoryPurgeResult, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if before == nil {
+		if s.retentionDays <= 0 {
+			return nil, domain.ErrValidation("no query history retention period is configured; give older_than or before")
+		}
+		cutoff := s.retentionCutoff(time.Now())
+		before = &cutoff
+	}
+
+	deleted, err := s.repo.PurgeOlderThan(ctx, *before)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, fmt.Sprintf("PURGE_QUERY_HISTORY(%d deleted)", deleted))
+	return &domain.QueryHistoryPurgeResult{Before: *before, Deleted: deleted}, nil
+}
+
+// PurgeExpired deletes query history older than the retention period and
+// returns how many entries were removed. It does nothing when no retention
+// period is set.
+func (s *QueryHistoryService) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retentionDays <= 0 {
+		return 0, nil
+	}
+	return s.repo.PurgeOlderThan(ctx, s.retentionCutoff(time.Now()))
+}
+
+// ReapExpired purges expired query history every interval until ctx is
+// cancelled. Should be called in a background goroutine.
+func (s *QueryHistoryService) ReapExpired(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.PurgeExpired(ctx)
+		}
+	}
+}
+
+// retentionCutoff is the time before which query history has outlived the
+// retention period.
+func (s *QueryHistoryService) retentionCutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -s.retentionDays)
+}
+
+func (s *QueryHistoryService) logAudit(ctx context.Context, action string) {
+	if s.audit == nil {
+		return
+	}
+
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		principal.Name = "system"
+	}
+
+	_ = s.audit.Insert(ctx, &domain.AuditEntry{
+		PrincipalName: principal.Name,
+		Action:        action,
+		Status:        "ALLOWED",
+	})
+}
@@ -2,6 +2,7 @@ package governance
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -68,3 +69,106 @@ func TestQueryHistoryService_List_RequiresAdmin(t *testing.T) {
 	var accessDenied *domain.AccessDeniedError
 	assert.ErrorAs(t, err, &accessDenied)
 }
+
+func TestQueryHistoryService_List_Mine(t *testing.T) {
+	var captured domain.QueryHistoryFilter
+	repo := &mockQueryHistoryRepo{
+		ListFn: func(_ context.Context, f domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error) {
+			captured = f
+			return nil, 0, nil
+		},
+	}
+	svc := NewQueryHistoryService(repo)
+
+	other := "alice"
+	_, _, err := svc.List(nonAdminCtx(), domain.QueryHistoryFilter{Mine: true, PrincipalName: &other})
+	require.NoError(t, err, "non-admin may list their own query history")
+	require.NotNil(t, captured.PrincipalName)
+	assert.Equal(t, "regular-user", *captured.PrincipalName)
+
+	_, _, err = svc.List(context.Background(), domain.QueryHistoryFilter{Mine: true})
+	var accessDenied *domain.AccessDeniedError
+	assert.ErrorAs(t, err, &accessDenied)
+}
+
+func TestQueryHistoryService_Purge(t *testing.T) {
+	t.Run("explicit_cutoff", func(t *testing.T) {
+		cutoff := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+		var got time.Time
+		repo := &mockQueryHistoryRepo{
+			PurgeOlderThanFn: func(_ context.Context, before time.Time) (int64, error) {
+				got = before
+				return 7, nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		svc := NewQueryHistoryService(repo, audit)
+
+		result, err := svc.Purge(adminCtx(), &cutoff)
+		require.NoError(t, err)
+		assert.Equal(t, cutoff, got)
+		assert.Equal(t, &domain.QueryHistoryPurgeResult{Before: cutoff, Deleted: 7}, result)
+		require.Len(t, audit.Entries, 1)
+		assert.Equal(t, "PURGE_QUERY_HISTORY(7 deleted)", audit.Entries[0].Action)
+	})
+
+	t.Run("retention_cutoff", func(t *testing.T) {
+		var got time.Time
+		repo := &mockQueryHistoryRepo{
+			PurgeOlderThanFn: func(_ context.Context, before time.Time) (int64, error) {
+				got = before
+				return 0, nil
+			},
+		}
+		svc := NewQueryHistoryService(repo)
+		svc.SetRetention(90)
+
+		_, err := svc.Purge(adminCtx(), nil)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, -90), got, time.Minute)
+	})
+
+	t.Run("no_cutoff_without_retention", func(t *testing.T) {
+		svc := NewQueryHistoryService(&mockQueryHistoryRepo{})
+
+		_, err := svc.Purge(adminCtx(), nil)
+		require.Error(t, err)
+		assert.True(t, errors.As(err, new(*domain.ValidationError)))
+	})
+
+	t.Run("requires_admin", func(t *testing.T) {
+		svc := NewQueryHistoryService(&mockQueryHistoryRepo{})
+		cutoff := time.Now()
+
+		_, err := svc.Purge(nonAdminCtx(), &cutoff)
+		require.Error(t, err)
+		assert.True(t, errors.As(err, new(*domain.AccessDeniedError)))
+	})
+}
+
+func TestQueryHistoryService_PurgeExpired(t *testing.T) {
+	t.Run("no_retention_is_a_noop", func(t *testing.T) {
+		svc := NewQueryHistoryService(&mockQueryHistoryRepo{})
+
+		deleted, err := svc.PurgeExpired(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("purges_past_retention", func(t *testing.T) {
+		var got time.Time
+		repo := &mockQueryHistoryRepo{
+			PurgeOlderThanFn: func(_ context.Context, before time.Time) (int64, error) {
+				got = before
+				return 3, nil
+			},
+		}
+		svc := NewQueryHistoryService(repo)
+		svc.SetRetention(30)
+
+		deleted, err := svc.PurgeExpired(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, -30), got, time.Minute)
+	})
+}
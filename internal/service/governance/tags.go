@@ -2,6 +2,7 @@ package governance
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"duck-demo/internal/domain"
@@ -99,6 +100,69 @@ func (s *TagService) UnassignTag(ctx context.Context, principal string, id strin
 	return nil
 }
 
+// AssignTagBatch assigns a tag to several securables in one transaction. The
+// batch is all-or-nothing: if any item is rejected, nothing is assigned.
+func (s *TagService) AssignTagBatch(ctx context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error) {
+	if err := s.validateTagBatch(ctx, req); err != nil {
+		return nil, err
+	}
+
+	batch, err := s.repo.AssignTagBatch(ctx, req.TagID, req.Targets, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Applied {
+		s.logAudit(ctx, principal, "ASSIGN_TAG_BATCH", fmt.Sprintf("Assigned tag %s to %d of %d securables",
+			req.TagID, countTagBatchStatus(batch, domain.TagBatchStatusAssigned), len(req.Targets)))
+	}
+	return batch, nil
+}
+
+// UnassignTagBatch removes a tag from several securables in one transaction.
+// Securables that do not carry the tag are reported but do not fail the batch.
+func (s *TagService) UnassignTagBatch(ctx context.Context, principal string, req domain.BatchTagAssignmentRequest) (*domain.TagAssignmentBatch, error) {
+	if err := s.validateTagBatch(ctx, req); err != nil {
+		return nil, err
+	}
+
+	batch, err := s.repo.UnassignTagBatch(ctx, req.TagID, req.Targets)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Applied {
+		s.logAudit(ctx, principal, "UNASSIGN_TAG_BATCH", fmt.Sprintf("Removed tag %s from %d of %d securables",
+			req.TagID, countTagBatchStatus(batch, domain.TagBatchStatusRemoved), len(req.Targets)))
+	}
+	return batch, nil
+}
+
+// validateTagBatch checks the request shape and that the tag exists.
+func (s *TagService) validateTagBatch(ctx context.Context, req domain.BatchTagAssignmentRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetTag(ctx, req.TagID); err != nil {
+		var notFound *domain.NotFoundError
+		if errors.As(err, &notFound) {
+			return domain.ErrNotFound("tag %q not found", req.TagID)
+		}
+		return err
+	}
+	return nil
+}
+
+func countTagBatchStatus(batch *domain.TagAssignmentBatch, status string) int {
+	n := 0
+	for _, res := range batch.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
 // ListTagsForSecurable returns all tags assigned to a securable object.
 func (s *TagService) ListTagsForSecurable(ctx context.Context, securableType string, securableID string, columnName *string) ([]domain.Tag, error) {
 	return s.repo.ListTagsForSecurable(ctx, securableType, securableID, columnName)
@@ -279,6 +279,110 @@ func TestTagService_UnassignTag(t *testing.T) {
 	})
 }
 
+// === AssignTagBatch / UnassignTagBatch ===
+
+func TestTagService_AssignTagBatch(t *testing.T) {
+	email, backup := "email", "backup_email"
+	req := domain.BatchTagAssignmentRequest{
+		TagID: "tag-1",
+		Targets: []domain.TagAssignmentTarget{
+			{SecurableType: "column", SecurableID: "t1", ColumnName: &email},
+			{SecurableType: "column", SecurableID: "t1", ColumnName: &backup},
+		},
+	}
+	getTag := func(_ context.Context, id string) (*domain.Tag, error) {
+		return &domain.Tag{ID: id, Key: "pii"}, nil
+	}
+
+	t.Run("happy_path", func(t *testing.T) {
+		repo := &mockTagRepo{
+			GetTagFn: getTag,
+			AssignTagBatchFn: func(_ context.Context, tagID string, targets []domain.TagAssignmentTarget, assignedBy string) (*domain.TagAssignmentBatch, error) {
+				assert.Equal(t, "tag-1", tagID)
+				assert.Equal(t, "alice", assignedBy)
+				assert.Len(t, targets, 2)
+				return &domain.TagAssignmentBatch{Applied: true, Results: []domain.TagAssignmentBatchResult{
+					{Target: targets[0], Status: domain.TagBatchStatusAssigned},
+					{Target: targets[1], Status: domain.TagBatchStatusUnchanged},
+				}}, nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		svc := NewTagService(repo, audit)
+
+		batch, err := svc.AssignTagBatch(ctxWithPrincipal("alice"), "alice", req)
+
+		require.NoError(t, err)
+		assert.True(t, batch.Applied)
+		assert.True(t, audit.HasAction("ASSIGN_TAG_BATCH"))
+	})
+
+	t.Run("not_applied_is_not_audited", func(t *testing.T) {
+		repo := &mockTagRepo{
+			GetTagFn: getTag,
+			AssignTagBatchFn: func(_ context.Context, _ string, _ []domain.TagAssignmentTarget, _ string) (*domain.TagAssignmentBatch, error) {
+				return &domain.TagAssignmentBatch{Applied: false}, nil
+			},
+		}
+		audit := &mockAuditRepo{}
+		svc := NewTagService(repo, audit)
+
+		batch, err := svc.AssignTagBatch(ctxWithPrincipal("alice"), "alice", req)
+
+		require.NoError(t, err)
+		assert.False(t, batch.Applied)
+		assert.Empty(t, audit.Entries)
+	})
+
+	t.Run("unknown_tag", func(t *testing.T) {
+		repo := &mockTagRepo{
+			GetTagFn: func(_ context.Context, _ string) (*domain.Tag, error) {
+				return nil, &domain.NotFoundError{Message: "resource not found"}
+			},
+		}
+		svc := NewTagService(repo, &mockAuditRepo{})
+
+		_, err := svc.AssignTagBatch(ctxWithPrincipal("alice"), "alice", req)
+
+		var notFound *domain.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Contains(t, err.Error(), "tag-1")
+	})
+
+	t.Run("empty_batch", func(t *testing.T) {
+		svc := NewTagService(&mockTagRepo{}, &mockAuditRepo{})
+
+		_, err := svc.AssignTagBatch(ctxWithPrincipal("alice"), "alice", domain.BatchTagAssignmentRequest{TagID: "tag-1"})
+
+		var validation *domain.ValidationError
+		require.ErrorAs(t, err, &validation)
+	})
+}
+
+func TestTagService_UnassignTagBatch(t *testing.T) {
+	repo := &mockTagRepo{
+		GetTagFn: func(_ context.Context, id string) (*domain.Tag, error) {
+			return &domain.Tag{ID: id, Key: "pii"}, nil
+		},
+		UnassignTagBatchFn: func(_ context.Context, _ string, targets []domain.TagAssignmentTarget) (*domain.TagAssignmentBatch, error) {
+			return &domain.TagAssignmentBatch{Applied: true, Results: []domain.TagAssignmentBatchResult{
+				{Target: targets[0], Status: domain.TagBatchStatusRemoved},
+			}}, nil
+		},
+	}
+	audit := &mockAuditRepo{}
+	svc := NewTagService(repo, audit)
+
+	batch, err := svc.UnassignTagBatch(ctxWithPrincipal("alice"), "alice", domain.BatchTagAssignmentRequest{
+		TagID:   "tag-1",
+		Targets: []domain.TagAssignmentTarget{{SecurableType: "table", SecurableID: "t1"}},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, batch.Applied)
+	assert.True(t, audit.HasAction("UNASSIGN_TAG_BATCH"))
+}
+
 // === ListTagsForSecurable ===
 
 func TestTagService_ListTagsForSecurable(t *testing.T) {
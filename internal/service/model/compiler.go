@@ -25,6 +25,7 @@ type compileContext struct {
 	sources       map[string]string
 	macros        map[string]compileMacroDefinition
 	macroRuntimes map[string]*starlarkMacroRuntime
+	macroDeps     []string
 }
 
 type compileMacroDefinition struct {
@@ -33,6 +34,7 @@ type compileMacroDefinition struct {
 	body       string
 	starlark   bool
 	runtimeKey string
+	deprecated bool
 }
 
 type compileResult struct {
@@ -41,6 +43,7 @@ type compileResult struct {
 	varsUsed     []string
 	macrosUsed   []string
 	compiledHash string
+	warnings     []string
 }
 
 func compileModelSQL(sqlText string, ctx compileContext) (*compileResult, error) {
@@ -61,6 +64,11 @@ func compileModelSQL(sqlText string, ctx compileContext) (*compileResult, error)
 	if err != nil {
 		return nil, fmt.Errorf("parse compiled SQL: %w", err)
 	}
+	sqlMacros, err := ExtractMacroCalls(rendered, sqlMacroNames(ctx.macros))
+	if err != nil {
+		return nil, fmt.Errorf("parse compiled SQL: %w", err)
+	}
+	macrosUsed = append(macrosUsed, sqlMacros...)
 	for _, dep := range deps {
 		depsSet[dep] = struct{}{}
 	}
@@ -71,7 +79,9 @@ func compileModelSQL(sqlText string, ctx compileContext) (*compileResult, error)
 	sort.Strings(varsUsed)
 	sort.Strings(macrosUsed)
 
-	hash, err := computeCompiledHash(rendered, ctx)
+	macrosUsed = dedupeSorted(macrosUsed)
+
+	hash, err := computeCompiledHash(rendered, ctx, macrosUsed)
 	if err != nil {
 		return nil, fmt.Errorf("compiled hash: %w", err)
 	}
@@ -79,13 +89,48 @@ func compileModelSQL(sqlText string, ctx compileContext) (*compileResult, error)
 		sql:          rendered,
 		dependsOn:    allDeps,
 		varsUsed:     dedupeSorted(varsUsed),
-		macrosUsed:   dedupeSorted(macrosUsed),
+		macrosUsed:   macrosUsed,
 		compiledHash: hash,
+		warnings:     macroWarnings(ctx, macrosUsed),
 	}, nil
 }
 
-func computeCompiledHash(sqlText string, ctx compileContext) (string, error) {
-	ctxDigest, err := compileContextDigest(ctx)
+// sqlMacroNames maps the lowercased names of the registered SQL macros a
+// model can call directly in its SQL to their registered names. Starlark
+// macros only expand inside templates.
+func sqlMacroNames(macros map[string]compileMacroDefinition) map[string]string {
+	names := make(map[string]string, len(macros))
+	for name, def := range macros {
+		if !def.starlark {
+			names[strings.ToLower(name)] = name
+		}
+	}
+	return names
+}
+
+// macroWarnings reports deprecated macros the model uses and macros it was
+// recorded as calling that are no longer registered. The latter fail when
+// the model runs, since only registered macros are created on the
+// execution connection.
+func macroWarnings(ctx compileContext, macrosUsed []string) []string {
+	model := ctx.projectName + "." + ctx.modelName
+	var warnings []string
+	for _, name := range macrosUsed {
+		if ctx.macros[name].deprecated {
+			warnings = append(warnings, fmt.Sprintf("model %s uses deprecated macro %q", model, name))
+		}
+	}
+	registered := sqlMacroNames(ctx.macros)
+	for _, name := range ctx.macroDeps {
+		if _, ok := registered[strings.ToLower(name)]; !ok {
+			warnings = append(warnings, fmt.Sprintf("model %s calls macro %q, which is not registered", model, name))
+		}
+	}
+	return warnings
+}
+
+func computeCompiledHash(sqlText string, ctx compileContext, macrosUsed []string) (string, error) {
+	ctxDigest, err := compileContextDigest(ctx, macrosUsed)
 	if err != nil {
 		return "", err
 	}
@@ -490,7 +535,19 @@ func dedupeSorted(values []string) []string {
 	return out
 }
 
-func compileContextDigest(ctx compileContext) (string, error) {
+// compileContextDigest serializes the parts of ctx that affect a model's
+// output. The bodies of SQL macros the model calls are included, so editing
+// a macro marks the models using it as modified.
+func compileContextDigest(ctx compileContext, macrosUsed []string) (string, error) {
+	var macroBodies map[string]string
+	for _, name := range macrosUsed {
+		if def, ok := ctx.macros[name]; ok && !def.starlark {
+			if macroBodies == nil {
+				macroBodies = make(map[string]string)
+			}
+			macroBodies[name] = strings.Join(def.parameters, ",") + ":" + def.body
+		}
+	}
 	p := struct {
 		TargetCatalog string            `json:"target_catalog"`
 		TargetSchema  string            `json:"target_schema"`
@@ -499,6 +556,7 @@ func compileContextDigest(ctx compileContext) (string, error) {
 		Materialize   string            `json:"materialization"`
 		FullRefresh   bool              `json:"full_refresh"`
 		Vars          map[string]string `json:"vars"`
+		Macros        map[string]string `json:"macros,omitempty"`
 	}{
 		TargetCatalog: ctx.targetCatalog,
 		TargetSchema:  ctx.targetSchema,
@@ -507,6 +565,7 @@ func compileContextDigest(ctx compileContext) (string, error) {
 		Materialize:   ctx.materialize,
 		FullRefresh:   ctx.fullRefresh,
 		Vars:          ctx.vars,
+		Macros:        macroBodies,
 	}
 	b, err := json.Marshal(p)
 	if err != nil {
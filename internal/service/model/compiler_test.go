@@ -188,6 +188,67 @@ func TestCompileModelSQL_FailsOnUnknownMacro(t *testing.T) {
 	assert.Contains(t, err.Error(), `unknown macro "utils.unknown_macro"`)
 }
 
+func TestCompileModelSQL_ResolvesSQLMacroCalls(t *testing.T) {
+	newCtx := func() compileContext {
+		return compileContext{
+			targetCatalog: "memory",
+			targetSchema:  "analytics",
+			projectName:   "analytics",
+			modelName:     "fct_orders",
+			materialize:   domain.MaterializationTable,
+			models: map[string]domain.Model{
+				"analytics.stg_orders": {ProjectName: "analytics", Name: "stg_orders"},
+			},
+			byName: map[string][]domain.Model{
+				"stg_orders": {{ProjectName: "analytics", Name: "stg_orders"}},
+			},
+			macros: map[string]compileMacroDefinition{
+				"fmt_money": {name: "fmt_money", parameters: []string{"x"}, body: "'$' || printf('%.2f', x)"},
+			},
+		}
+	}
+	const modelSQL = `select id, fmt_money(amount) as amount_fmt from {{ ref('stg_orders') }}`
+
+	t.Run("registered macro is resolved", func(t *testing.T) {
+		compiled, err := compileModelSQL(modelSQL, newCtx())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"fmt_money"}, compiled.macrosUsed)
+		assert.Equal(t, []string{"analytics.stg_orders"}, compiled.dependsOn)
+		assert.Contains(t, compiled.sql, "fmt_money(amount)")
+		assert.Empty(t, compiled.warnings)
+	})
+
+	t.Run("macro body changes the compiled hash", func(t *testing.T) {
+		before, err := compileModelSQL(modelSQL, newCtx())
+		require.NoError(t, err)
+		ctx := newCtx()
+		ctx.macros["fmt_money"] = compileMacroDefinition{name: "fmt_money", parameters: []string{"x"}, body: "'EUR ' || x"}
+		after, err := compileModelSQL(modelSQL, ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, before.compiledHash, after.compiledHash)
+	})
+
+	t.Run("deprecated macro warns", func(t *testing.T) {
+		ctx := newCtx()
+		def := ctx.macros["fmt_money"]
+		def.deprecated = true
+		ctx.macros["fmt_money"] = def
+		compiled, err := compileModelSQL(modelSQL, ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{`model analytics.fct_orders uses deprecated macro "fmt_money"`}, compiled.warnings)
+	})
+
+	t.Run("missing macro warns", func(t *testing.T) {
+		ctx := newCtx()
+		delete(ctx.macros, "fmt_money")
+		ctx.macroDeps = []string{"fmt_money"}
+		compiled, err := compileModelSQL(modelSQL, ctx)
+		require.NoError(t, err)
+		assert.Empty(t, compiled.macrosUsed)
+		assert.Equal(t, []string{`model analytics.fct_orders calls macro "fmt_money", which is not registered`}, compiled.warnings)
+	})
+}
+
 func TestCompileModelSQL_StarlarkKeywordAndListArgs(t *testing.T) {
 	ctx := compileContext{
 		projectName: "analytics",
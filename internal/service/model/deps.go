@@ -19,15 +19,7 @@ func ExtractDependencies(sqlText string, thisProject string, allModels []domain.
 
 	// Collect all table names from the statement
 	tableRefs := duckdbsql.CollectTableNames(stmt)
-
-	// Build lookup maps
-	byQualified := make(map[string]*domain.Model, len(allModels))
-	byName := make(map[string][]*domain.Model, len(allModels))
-	for i := range allModels {
-		m := &allModels[i]
-		byQualified[m.ProjectName+"."+m.Name] = m
-		byName[m.Name] = append(byName[m.Name], m)
-	}
+	index := newModelIndex(allModels)
 
 	seen := make(map[string]bool)
 	var deps []string
@@ -36,43 +28,126 @@ func ExtractDependencies(sqlText string, thisProject string, allModels []domain.
 		if strings.HasPrefix(ref, "__func__") {
 			continue
 		}
-
-		parts := strings.SplitN(ref, ".", 2)
-
-		if len(parts) == 2 {
-			qualName := ref
-			if _, ok := byQualified[qualName]; ok {
-				if !seen[qualName] {
-					seen[qualName] = true
-					deps = append(deps, qualName)
-				}
-				continue
-			}
+		qualName, ok := index.resolve(ref, thisProject)
+		if ok && !seen[qualName] {
+			seen[qualName] = true
+			deps = append(deps, qualName)
 		}
+	}
+
+	sort.Strings(deps)
+	return deps, nil
+}
+
+// ExtractSourceTables parses a model's SQL and returns the tables it reads
+// that are not models, as "schema.table" ("main" when unqualified). CTE
+// names are skipped.
+func ExtractSourceTables(sqlText string, thisProject string, allModels []domain.Model) ([]string, error) {
+	stmt, err := duckdbsql.Parse(sqlText)
+	if err != nil {
+		return nil, fmt.Errorf("parse model SQL: %w", err)
+	}
 
-		unqualName := ref
-		if len(parts) == 2 {
-			unqualName = parts[1]
+	ctes := make(map[string]bool)
+	if sel, ok := stmt.(*duckdbsql.SelectStmt); ok && sel.With != nil {
+		for _, cte := range sel.With.CTEs {
+			ctes[strings.ToLower(cte.Name)] = true
 		}
+	}
+	index := newModelIndex(allModels)
 
-		sameProjectQual := thisProject + "." + unqualName
-		if _, ok := byQualified[sameProjectQual]; ok {
-			if !seen[sameProjectQual] {
-				seen[sameProjectQual] = true
-				deps = append(deps, sameProjectQual)
-			}
+	seen := make(map[string]bool)
+	var tables []string
+	for _, ref := range duckdbsql.CollectTableRefs(stmt) {
+		if strings.HasPrefix(ref.Name, "__func__") {
 			continue
 		}
+		if ref.Schema == "" && ctes[strings.ToLower(ref.Name)] {
+			continue
+		}
+		if _, ok := index.resolve(ref.Name, thisProject); ok {
+			continue
+		}
+		schema := ref.Schema
+		if schema == "" {
+			schema = "main"
+		}
+		name := schema + "." + ref.Name
+		if !seen[name] {
+			seen[name] = true
+			tables = append(tables, name)
+		}
+	}
+
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// ExtractMacroCalls parses a model's SQL and returns the macros it calls,
+// sorted. macros maps lowercased macro names to their registered names;
+// calls to anything else, such as DuckDB built-ins, are ignored.
+func ExtractMacroCalls(sqlText string, macros map[string]string) ([]string, error) {
+	if len(macros) == 0 {
+		return nil, nil
+	}
+	stmt, err := duckdbsql.Parse(sqlText)
+	if err != nil {
+		return nil, fmt.Errorf("parse model SQL: %w", err)
+	}
+
+	var calls []string
+	for _, fn := range duckdbsql.CollectFuncNames(stmt) {
+		if name, ok := macros[fn]; ok {
+			calls = append(calls, name)
+		}
+	}
+	sort.Strings(calls)
+	return calls, nil
+}
+
+// modelIndex looks models up by qualified and by bare name.
+type modelIndex struct {
+	byQualified map[string]*domain.Model
+	byName      map[string][]*domain.Model
+}
+
+func newModelIndex(allModels []domain.Model) modelIndex {
+	idx := modelIndex{
+		byQualified: make(map[string]*domain.Model, len(allModels)),
+		byName:      make(map[string][]*domain.Model, len(allModels)),
+	}
+	for i := range allModels {
+		m := &allModels[i]
+		idx.byQualified[m.ProjectName+"."+m.Name] = m
+		idx.byName[m.Name] = append(idx.byName[m.Name], m)
+	}
+	return idx
+}
+
+// resolve maps a table reference to the qualified name of the model it
+// denotes: an exact "project.name" match first, then a model of the same
+// name in thisProject, then a model name that is unique across projects.
+func (idx modelIndex) resolve(ref, thisProject string) (string, bool) {
+	parts := strings.SplitN(ref, ".", 2)
 
-		if candidates, ok := byName[unqualName]; ok && len(candidates) == 1 {
-			qualName := candidates[0].ProjectName + "." + candidates[0].Name
-			if !seen[qualName] {
-				seen[qualName] = true
-				deps = append(deps, qualName)
-			}
+	if len(parts) == 2 {
+		if _, ok := idx.byQualified[ref]; ok {
+			return ref, true
 		}
 	}
 
-	sort.Strings(deps)
-	return deps, nil
+	unqualName := ref
+	if len(parts) == 2 {
+		unqualName = parts[1]
+	}
+
+	sameProjectQual := thisProject + "." + unqualName
+	if _, ok := idx.byQualified[sameProjectQual]; ok {
+		return sameProjectQual, true
+	}
+
+	if candidates, ok := idx.byName[unqualName]; ok && len(candidates) == 1 {
+		return candidates[0].ProjectName + "." + candidates[0].Name, true
+	}
+	return "", false
 }
@@ -84,3 +84,61 @@ func TestExtractDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractSourceTables(t *testing.T) {
+	allModels := []domain.Model{
+		{ProjectName: "sales", Name: "stg_orders"},
+		{ProjectName: "warehouse", Name: "raw_events"},
+	}
+
+	tests := []struct {
+		name       string
+		sql        string
+		wantTables []string
+	}{
+		{
+			name:       "schema-qualified source",
+			sql:        "SELECT * FROM raw_data.orders",
+			wantTables: []string{"raw_data.orders"},
+		},
+		{
+			name:       "unqualified source defaults to main",
+			sql:        "SELECT * FROM some_physical_table",
+			wantTables: []string{"main.some_physical_table"},
+		},
+		{
+			name:       "models are not sources",
+			sql:        "SELECT * FROM stg_orders o JOIN warehouse.raw_events e ON o.id = e.id JOIN raw.customers c ON o.cid = c.id",
+			wantTables: []string{"raw.customers"},
+		},
+		{
+			name:       "CTE is not a source",
+			sql:        "WITH recent AS (SELECT * FROM raw.orders) SELECT * FROM recent",
+			wantTables: []string{"raw.orders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := ExtractSourceTables(tt.sql, "sales", allModels)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTables, tables)
+		})
+	}
+}
+
+func TestExtractMacroCalls(t *testing.T) {
+	macros := map[string]string{"fmt_money": "fmt_money", "is_vip": "IS_VIP"}
+
+	calls, err := ExtractMacroCalls(
+		"SELECT FMT_MONEY(amount), upper(name) FROM orders WHERE is_vip(customer_id) AND fmt_money(tax) <> ''", macros)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"IS_VIP", "fmt_money"}, calls)
+
+	calls, err = ExtractMacroCalls("SELECT upper(name) FROM orders", macros)
+	require.NoError(t, err)
+	assert.Empty(t, calls)
+
+	_, err = ExtractMacroCalls("SELECT FROM WHERE", macros)
+	require.Error(t, err)
+}
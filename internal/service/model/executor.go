@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"regexp"
 	"strings"
+	"time"
 
 	"duck-demo/internal/domain"
 	"duck-demo/internal/sqlrewrite"
@@ -20,6 +21,7 @@ type ExecutionConfig struct {
 	TargetSchema  string
 	Variables     map[string]string
 	FullRefresh   bool
+	StoreFailures bool // write failing test rows to <model>_test_failures
 }
 
 // executeRun processes a model run in a background goroutine.
@@ -111,10 +113,12 @@ func (s *Service) executeRun(ctx context.Context, runID string,
 			}
 			execModel.SQL = *stepMeta.CompiledSQL
 
+			stepStart := time.Now()
 			rowsAffected, err := s.executeSingleModel(ctx, &execModel, config, principal, logger)
 			if err != nil {
 				runFailed = true
 				errMsg := err.Error()
+				s.recordStepMetrics(ctx, stepID, stepStart, nil, logger)
 				_ = s.runs.UpdateStepFinished(ctx, stepID, domain.ModelRunStatusFailed, nil, &errMsg)
 				continue
 			}
@@ -123,10 +127,12 @@ func (s *Service) executeRun(ctx context.Context, runID string,
 			if err := s.postMaterialize(ctx, &execModel, config, stepID, principal, logger); err != nil {
 				runFailed = true
 				errMsg := err.Error()
+				s.recordStepMetrics(ctx, stepID, stepStart, s.relationBytes(ctx, &execModel, config), logger)
 				_ = s.runs.UpdateStepFinished(ctx, stepID, domain.ModelRunStatusFailed, rowsAffected, &errMsg)
 				continue
 			}
 
+			s.recordStepMetrics(ctx, stepID, stepStart, s.relationBytes(ctx, &execModel, config), logger)
 			_ = s.runs.UpdateStepFinished(ctx, stepID, domain.ModelRunStatusSuccess, rowsAffected, nil)
 		}
 	}
@@ -261,6 +267,36 @@ func (s *Service) executeSingleModel(ctx context.Context, model *domain.Model,
 	}
 }
 
+// recordStepMetrics stores the wall-clock duration of a step measured from
+// start. Failures are logged; they never fail the run.
+func (s *Service) recordStepMetrics(ctx context.Context, stepID string, start time.Time,
+	bytesWritten *int64, logger *slog.Logger) {
+	durationMs := time.Since(start).Milliseconds()
+	if err := s.runs.UpdateStepMetrics(ctx, stepID, durationMs, bytesWritten); err != nil {
+		logger.Warn("failed to record step metrics", "step_id", stepID, "error", err)
+	}
+}
+
+// relationBytes returns the size of the data files backing a materialized
+// model, as reported by DuckLake. It returns nil for views, for targets that
+// are not DuckLake catalogs, and when the table name is ambiguous across
+// schemas of the catalog.
+func (s *Service) relationBytes(ctx context.Context, model *domain.Model, config ExecutionConfig) *int64 {
+	if model.Materialization == domain.MaterializationView || config.TargetCatalog == "" {
+		return nil
+	}
+	query := fmt.Sprintf(
+		"SELECT COUNT(*), COALESCE(SUM(file_size_bytes), 0) FROM ducklake_table_info('%s') WHERE table_name = '%s'",
+		strings.ReplaceAll(config.TargetCatalog, "'", "''"),
+		strings.ReplaceAll(model.Name, "'", "''"),
+	)
+	var matches, size int64
+	if err := s.duckDB.QueryRowContext(ctx, query).Scan(&matches, &size); err != nil || matches != 1 {
+		return nil
+	}
+	return &size
+}
+
 func (s *Service) materializeSeed(ctx context.Context, conn *sql.Conn,
 	model *domain.Model, config ExecutionConfig, principal string) (int64, error) {
 	return s.materializeTable(ctx, conn, model, config, principal)
@@ -718,6 +754,10 @@ func quoteIdent(name string) string {
 	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
 }
 
+func quoteLiteral(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
 func relationFQN(catalog, schema, name string) string {
 	if catalog == "" {
 		return quoteIdent(schema) + "." + quoteIdent(name)
@@ -740,6 +780,7 @@ func canDirectExecOnConn(stmtType sqlrewrite.StatementType, query string) bool {
 		"CREATE OR REPLACE VIEW ",
 		"CREATE OR REPLACE TABLE ",
 		"CREATE TEMP TABLE ",
+		"CREATE TABLE IF NOT EXISTS ",
 		"DROP TABLE ",
 		"CREATE OR REPLACE MACRO ",
 	}
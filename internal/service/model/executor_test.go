@@ -51,6 +51,7 @@ func TestCanDirectExecOnConn(t *testing.T) {
 		{name: "allow create view", stmtType: sqlrewrite.StmtDDL, query: "CREATE OR REPLACE VIEW main.v AS SELECT 1", want: true},
 		{name: "allow create table", stmtType: sqlrewrite.StmtDDL, query: "CREATE OR REPLACE TABLE main.t AS SELECT 1", want: true},
 		{name: "allow temp table", stmtType: sqlrewrite.StmtDDL, query: "CREATE TEMP TABLE _tmp AS SELECT 1", want: true},
+		{name: "allow create table if not exists", stmtType: sqlrewrite.StmtDDL, query: "CREATE TABLE IF NOT EXISTS main.t_test_failures (test_name VARCHAR)", want: true},
 		{name: "allow drop table", stmtType: sqlrewrite.StmtDDL, query: "DROP TABLE IF EXISTS _tmp", want: true},
 		{name: "allow create macro", stmtType: sqlrewrite.StmtDDL, query: "CREATE OR REPLACE MACRO m(x) AS x + 1", want: true},
 		{name: "allow set variable", stmtType: sqlrewrite.StmtOther, query: "SET VARIABLE load_window_days='7'", want: true},
@@ -218,6 +219,56 @@ func TestMaterializeIncremental(t *testing.T) {
 		assert.Equal(t, [2]int64{3, 30}, actual[0])
 	})
 
+	t.Run("full refresh ignores incremental strategy and schema policy", func(t *testing.T) {
+		svc, db := newDuckDBServiceForTest(t)
+		_, err := db.ExecContext(context.Background(), `CREATE TABLE analytics.orders (id INTEGER, amount INTEGER)`)
+		require.NoError(t, err)
+		_, err = db.ExecContext(context.Background(), `INSERT INTO analytics.orders VALUES (1, 10), (2, 20)`)
+		require.NoError(t, err)
+
+		conn, err := db.Conn(context.Background())
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		model := &domain.Model{
+			ProjectName:     "analytics",
+			Name:            "orders",
+			SQL:             "SELECT * FROM (VALUES (2, 200, 'eu'), (3, 30, 'us')) AS src(id, amount, region)",
+			Materialization: domain.MaterializationIncremental,
+			Config: domain.ModelConfig{
+				UniqueKey:           []string{"id"},
+				IncrementalStrategy: "merge",
+				OnSchemaChange:      "fail",
+			},
+		}
+
+		_, err = svc.materializeIncremental(context.Background(), conn, model,
+			ExecutionConfig{TargetSchema: "analytics"}, "admin")
+		require.Error(t, err, "incremental path rejects the new column")
+
+		rows, err := svc.materializeIncremental(context.Background(), conn, model,
+			ExecutionConfig{TargetSchema: "analytics", FullRefresh: true}, "admin")
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, rows)
+
+		var ids []int64
+		result, err := db.QueryContext(context.Background(), `SELECT id FROM analytics.orders ORDER BY id`)
+		require.NoError(t, err)
+		defer func() { _ = result.Close() }()
+		for result.Next() {
+			var id int64
+			require.NoError(t, result.Scan(&id))
+			ids = append(ids, id)
+		}
+		require.NoError(t, result.Err())
+		assert.Equal(t, []int64{2, 3}, ids, "rows outside the new result are gone")
+
+		var columns int
+		require.NoError(t, db.QueryRowContext(context.Background(),
+			`SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = 'analytics' AND table_name = 'orders'`).Scan(&columns))
+		assert.Equal(t, 3, columns, "table is rebuilt with the new schema")
+	})
+
 	t.Run("merge strategy updates and inserts", func(t *testing.T) {
 		svc, db := newDuckDBServiceForTest(t)
 		_, err := db.ExecContext(context.Background(), `CREATE TABLE analytics.orders (id INTEGER, amount INTEGER)`)
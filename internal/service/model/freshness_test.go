@@ -49,6 +49,10 @@ func (s freshnessModelRepoStub) UpdateDependencies(context.Context, string, []st
 	panic("unexpected call")
 }
 
+func (s freshnessModelRepoStub) UpdateMacroDependencies(context.Context, string, []string) error {
+	panic("unexpected call")
+}
+
 type freshnessRunRepoStub struct {
 	runs  []domain.ModelRun
 	steps map[string][]domain.ModelRunStep
@@ -90,6 +94,14 @@ func (s freshnessRunRepoStub) UpdateStepFinished(context.Context, string, string
 	panic("unexpected call")
 }
 
+func (s freshnessRunRepoStub) UpdateStepMetrics(context.Context, string, int64, *int64) error {
+	panic("unexpected call")
+}
+
+func (s freshnessRunRepoStub) ListStepsByModel(context.Context, string, domain.PageRequest) ([]domain.ModelRunStep, int64, error) {
+	panic("unexpected call")
+}
+
 func TestCheckFreshness_UsesModelSpecificLineage(t *testing.T) {
 	now := time.Now().UTC()
 	older := now.Add(-10 * time.Minute)
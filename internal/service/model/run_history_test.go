@@ -0,0 +1,151 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"duck-demo/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memRunRepo keeps runs and steps in memory in creation order.
+type memRunRepo struct {
+	runs  map[string]*domain.ModelRun
+	steps []*domain.ModelRunStep
+}
+
+func newMemRunRepo() *memRunRepo {
+	return &memRunRepo{runs: make(map[string]*domain.ModelRun)}
+}
+
+func (r *memRunRepo) CreateRun(_ context.Context, run *domain.ModelRun) (*domain.ModelRun, error) {
+	cp := *run
+	cp.ID = fmt.Sprintf("run-%d", len(r.runs)+1)
+	r.runs[cp.ID] = &cp
+	return &cp, nil
+}
+
+func (r *memRunRepo) GetRunByID(_ context.Context, id string) (*domain.ModelRun, error) {
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, domain.ErrNotFound("model run %s not found", id)
+	}
+	return run, nil
+}
+
+func (r *memRunRepo) ListRuns(context.Context, domain.ModelRunFilter) ([]domain.ModelRun, int64, error) {
+	panic("unexpected call")
+}
+
+func (r *memRunRepo) UpdateRunStarted(_ context.Context, id string) error {
+	r.runs[id].Status = domain.ModelRunStatusRunning
+	return nil
+}
+
+func (r *memRunRepo) UpdateRunFinished(_ context.Context, id string, status string, errMsg *string) error {
+	r.runs[id].Status = status
+	r.runs[id].ErrorMessage = errMsg
+	return nil
+}
+
+func (r *memRunRepo) CreateStep(_ context.Context, step *domain.ModelRunStep) (*domain.ModelRunStep, error) {
+	cp := *step
+	cp.ID = fmt.Sprintf("step-%d", len(r.steps)+1)
+	r.steps = append(r.steps, &cp)
+	return &cp, nil
+}
+
+func (r *memRunRepo) ListStepsByRun(_ context.Context, runID string) ([]domain.ModelRunStep, error) {
+	var out []domain.ModelRunStep
+	for _, st := range r.steps {
+		if st.RunID == runID {
+			out = append(out, *st)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRunRepo) step(id string) *domain.ModelRunStep {
+	for _, st := range r.steps {
+		if st.ID == id {
+			return st
+		}
+	}
+	return nil
+}
+
+func (r *memRunRepo) UpdateStepStarted(_ context.Context, id string) error {
+	r.step(id).Status = domain.ModelRunStatusRunning
+	return nil
+}
+
+func (r *memRunRepo) UpdateStepFinished(_ context.Context, id string, status string, rowsAffected *int64, errMsg *string) error {
+	st := r.step(id)
+	st.Status = status
+	st.RowsAffected = rowsAffected
+	st.ErrorMessage = errMsg
+	return nil
+}
+
+func (r *memRunRepo) UpdateStepMetrics(_ context.Context, id string, durationMs int64, bytesWritten *int64) error {
+	st := r.step(id)
+	st.DurationMs = &durationMs
+	st.BytesWritten = bytesWritten
+	return nil
+}
+
+func (r *memRunRepo) ListStepsByModel(_ context.Context, modelName string, _ domain.PageRequest) ([]domain.ModelRunStep, int64, error) {
+	var out []domain.ModelRunStep
+	for i := len(r.steps) - 1; i >= 0; i-- {
+		if r.steps[i].ModelName == modelName {
+			out = append(out, *r.steps[i])
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func TestExecuteRun_RecordsRowCountAndDuration(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newDuckDBServiceForTest(t)
+	runs := newMemRunRepo()
+	svc.runs = runs
+	svc.models = &memModelRepo{}
+
+	m, err := svc.models.Create(ctx, &domain.Model{
+		ProjectName:     "analytics",
+		Name:            "wide_numbers",
+		SQL:             "SELECT range AS id, md5(range::VARCHAR) AS digest FROM range(200000)",
+		Materialization: domain.MaterializationTable,
+	})
+	require.NoError(t, err)
+
+	run, err := runs.CreateRun(ctx, &domain.ModelRun{Status: domain.ModelRunStatusPending})
+	require.NoError(t, err)
+	_, err = runs.CreateStep(ctx, &domain.ModelRunStep{
+		RunID:       run.ID,
+		ModelID:     m.ID,
+		ModelName:   m.QualifiedName(),
+		CompiledSQL: &m.SQL,
+		Status:      domain.ModelRunStatusPending,
+	})
+	require.NoError(t, err)
+
+	svc.executeRun(ctx, run.ID, []domain.Model{*m}, [][]DAGNode{{{Model: m}}},
+		ExecutionConfig{TargetSchema: "analytics"}, "admin")
+	require.Equal(t, domain.ModelRunStatusSuccess, runs.runs[run.ID].Status)
+
+	history, total, err := svc.ListModelRunHistory(ctx, "analytics", "wide_numbers", domain.PageRequest{})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	step := history[0]
+	assert.Equal(t, domain.ModelRunStatusSuccess, step.Status)
+	require.NotNil(t, step.RowsAffected)
+	assert.EqualValues(t, 200000, *step.RowsAffected)
+	require.NotNil(t, step.DurationMs)
+	assert.Positive(t, *step.DurationMs)
+	// A plain DuckDB schema does not report data file sizes.
+	assert.Nil(t, step.BytesWritten)
+}
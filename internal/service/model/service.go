@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"duck-demo/internal/domain"
 )
@@ -31,6 +32,8 @@ type Service struct {
 	duckDB      *sql.DB
 	logger      *slog.Logger
 	runCancels  sync.Map
+
+	testFailureTTL time.Duration
 }
 
 // NewService creates a new model Service.
@@ -76,6 +79,7 @@ func (s *Service) CreateModel(ctx context.Context, principal string, req domain.
 		s.logger.Warn("dependency extraction failed", "project", req.ProjectName, "model", req.Name, "error", err)
 		deps = []string{}
 	}
+	macroDeps := s.extractMacroDependencies(ctx, req.SQL)
 
 	m := &domain.Model{
 		ProjectName:     req.ProjectName,
@@ -85,6 +89,7 @@ func (s *Service) CreateModel(ctx context.Context, principal string, req domain.
 		Description:     req.Description,
 		Tags:            req.Tags,
 		DependsOn:       deps,
+		MacroDeps:       macroDeps,
 		Config:          req.Config,
 		Contract:        req.Contract,
 		Freshness:       req.Freshness,
@@ -95,6 +100,7 @@ func (s *Service) CreateModel(ctx context.Context, principal string, req domain.
 	if err != nil {
 		return nil, err
 	}
+	s.syncModelLineage(ctx, principal, result, allModels)
 
 	s.logAudit(ctx, principal, "create_model", result.QualifiedName())
 	return result, nil
@@ -139,13 +145,44 @@ func (s *Service) UpdateModel(ctx context.Context, principal, projectName, name
 				}
 				result.DependsOn = deps
 			}
+			s.syncModelLineage(ctx, principal, result, allModels)
+		}
+		macroDeps := s.extractMacroDependencies(ctx, *req.SQL)
+		if err := s.models.UpdateMacroDependencies(ctx, result.ID, macroDeps); err != nil {
+			return nil, fmt.Errorf("update macro dependencies for %s: %w", result.QualifiedName(), err)
 		}
+		result.MacroDeps = macroDeps
 	}
 
 	s.logAudit(ctx, principal, "update_model", result.QualifiedName())
 	return result, nil
 }
 
+// extractMacroDependencies returns the registered macros sqlText calls.
+// Failures are logged and yield no dependencies; compilation reports
+// unresolvable SQL when the model runs.
+func (s *Service) extractMacroDependencies(ctx context.Context, sqlText string) []string {
+	macroDeps := []string{}
+	if s.macros == nil {
+		return macroDeps
+	}
+	macros, err := s.macros.ListAll(ctx)
+	if err != nil {
+		s.logger.Warn("list macros for dependency extraction failed", "error", err)
+		return macroDeps
+	}
+	defs := make(map[string]compileMacroDefinition, len(macros))
+	for _, m := range macros {
+		defs[m.Name] = compileMacroDefinition{name: m.Name, starlark: strings.Contains(m.Name, ".")}
+	}
+	calls, err := ExtractMacroCalls(sqlText, sqlMacroNames(defs))
+	if err != nil {
+		s.logger.Debug("macro dependency extraction failed", "error", err)
+		return macroDeps
+	}
+	return append(macroDeps, calls...)
+}
+
 // DeleteModel deletes a model.
 func (s *Service) DeleteModel(ctx context.Context, principal, projectName, name string) error {
 	existing, err := s.models.GetByName(ctx, projectName, name)
@@ -156,6 +193,11 @@ func (s *Service) DeleteModel(ctx context.Context, principal, projectName, name
 	if err := s.models.Delete(ctx, existing.ID); err != nil {
 		return err
 	}
+	if s.lineage != nil {
+		if err := s.lineage.DeleteEdgesByTarget(ctx, existing.QualifiedName(), modelLineageEdgeType); err != nil {
+			s.logger.Warn("remove model lineage failed", "model", existing.QualifiedName(), "error", err)
+		}
+	}
 
 	s.logAudit(ctx, principal, "delete_model", existing.QualifiedName())
 	return nil
@@ -279,6 +321,7 @@ func (s *Service) TriggerRun(ctx context.Context, principal string, req domain.T
 		TargetSchema:  req.TargetSchema,
 		Variables:     req.Variables,
 		FullRefresh:   req.FullRefresh,
+		StoreFailures: req.StoreFailures,
 	}
 	go s.executeRun(runCtx, run.ID, selected, tiers, config, principal)
 
@@ -385,6 +428,7 @@ func (s *Service) TriggerRunSync(ctx context.Context, principal string, req doma
 		TargetSchema:  req.TargetSchema,
 		Variables:     req.Variables,
 		FullRefresh:   req.FullRefresh,
+		StoreFailures: req.StoreFailures,
 	}
 	s.executeRun(ctx, run.ID, selected, tiers, config, principal)
 
@@ -420,6 +464,16 @@ func (s *Service) ListRunSteps(ctx context.Context, runID string) ([]domain.Mode
 	return s.runs.ListStepsByRun(ctx, runID)
 }
 
+// ListModelRunHistory returns the run steps recorded for one model across
+// runs, newest first.
+func (s *Service) ListModelRunHistory(ctx context.Context, projectName, modelName string, page domain.PageRequest) ([]domain.ModelRunStep, int64, error) {
+	m, err := s.models.GetByName(ctx, projectName, modelName)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.runs.ListStepsByModel(ctx, m.QualifiedName(), page)
+}
+
 // CancelRun cancels a running model execution.
 func (s *Service) CancelRun(ctx context.Context, principal, runID string) error {
 	run, err := s.runs.GetRunByID(ctx, runID)
@@ -450,6 +504,12 @@ func (s *Service) SetTestRepos(tests domain.ModelTestRepository, testResults dom
 	s.testResults = testResults
 }
 
+// SetTestFailureRetention sets how long rows stored by store_failures are
+// kept. Zero keeps them forever.
+func (s *Service) SetTestFailureRetention(ttl time.Duration) {
+	s.testFailureTTL = ttl
+}
+
 // SetMacroRepo sets the macro repository for loading macros during model runs.
 func (s *Service) SetMacroRepo(macros domain.MacroRepository) {
 	s.macros = macros
@@ -507,11 +567,76 @@ func (s *Service) DeleteTest(ctx context.Context, principal, projectName, modelN
 	return nil
 }
 
+// RunTests runs a model's tests against its materialized relation without
+// rebuilding it. The results are returned rather than added to the test
+// result history, which tracks model runs.
+func (s *Service) RunTests(ctx context.Context, principal, projectName, modelName string, req domain.RunModelTestsRequest) (*domain.ModelTestRun, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.tests == nil {
+		return nil, domain.ErrValidation("model tests are not configured")
+	}
+	model, err := s.models.GetByName(ctx, projectName, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.duckDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if err := s.loadMacros(ctx, conn, principal); err != nil {
+		return nil, err
+	}
+
+	config := ExecutionConfig{
+		TargetCatalog: req.TargetCatalog,
+		TargetSchema:  req.TargetSchema,
+		StoreFailures: req.StoreFailures,
+	}
+	results, err := s.evaluateTests(ctx, conn, model, config, "", principal)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &domain.ModelTestRun{Results: results}
+	if req.StoreFailures {
+		for _, r := range results {
+			if r.Status == domain.TestResultFail {
+				out.FailuresTable = req.TargetCatalog + "." + req.TargetSchema + "." + model.Name + "_test_failures"
+				break
+			}
+		}
+	}
+
+	s.logAudit(ctx, principal, "run_model_tests", model.QualifiedName())
+	return out, nil
+}
+
 // ListTestResults returns all test results for a model run step.
 func (s *Service) ListTestResults(ctx context.Context, _, stepID string) ([]domain.ModelTestResult, error) {
 	return s.testResults.ListByStep(ctx, stepID)
 }
 
+// ListTestResultHistory returns the results of one model test across runs,
+// newest first.
+func (s *Service) ListTestResultHistory(ctx context.Context, projectName, modelName, testID string, page domain.PageRequest) ([]domain.ModelTestResult, int64, error) {
+	model, err := s.models.GetByName(ctx, projectName, modelName)
+	if err != nil {
+		return nil, 0, err
+	}
+	test, err := s.tests.GetByID(ctx, testID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if test.ModelID != model.ID {
+		return nil, 0, domain.ErrNotFound("test %s not found on model %s", testID, model.QualifiedName())
+	}
+	return s.testResults.ListByTest(ctx, test.ID, page)
+}
+
 // PromoteNotebook promotes a notebook cell to a transformation model.
 func (s *Service) PromoteNotebook(ctx context.Context, principal string, req domain.PromoteNotebookRequest) (*domain.Model, error) {
 	if err := req.Validate(); err != nil {
@@ -607,11 +732,13 @@ func (s *Service) compileSelectedModels(
 			sources:       sources,
 			macros:        bundle.defs,
 			macroRuntimes: bundle.runtimes,
+			macroDeps:     m.MacroDeps,
 		}
 		compiled, err := compileModelSQL(m.SQL, ctx)
 		if err != nil {
 			return nil, nil, fmt.Errorf("compile model %s: %w", m.QualifiedName(), err)
 		}
+		compileWarnings = append(compileWarnings, compiled.warnings...)
 
 		selected[i].SQL = compiled.sql
 		selected[i].DependsOn = compiled.dependsOn
@@ -734,6 +861,7 @@ func (s *Service) loadCompileMacros(
 			body:       m.Body,
 			starlark:   strings.Contains(m.Name, "."),
 			runtimeKey: "db",
+			deprecated: m.Status == domain.MacroStatusDeprecated,
 		}
 		dbDefs[m.Name] = def
 		known[m.Name] = def
@@ -925,7 +1053,7 @@ func (s *Service) syncCompiledArtifacts(selected []domain.Model, artifacts map[s
 			projectName:   m.ProjectName,
 			modelName:     m.Name,
 			materialize:   m.Materialization,
-		})
+		}, nil)
 		if err != nil {
 			return fmt.Errorf("hash compiled SQL for %s: %w", m.QualifiedName(), err)
 		}
@@ -1062,6 +1190,48 @@ func (s *Service) persistCompileDependencyLineage(
 	return nil
 }
 
+// modelLineageEdgeType marks edges registered from a model definition, as
+// opposed to the READ edges recorded when a compiled model runs.
+const modelLineageEdgeType = "MODEL"
+
+// syncModelLineage replaces the MODEL edges feeding m with one edge per
+// upstream model ("project.model") and per source table its SQL reads
+// ("schema.table"), so the lineage graph shows the model DAG before the
+// first run. Failures are logged; the model itself is already saved.
+func (s *Service) syncModelLineage(ctx context.Context, principal string, m *domain.Model, allModels []domain.Model) {
+	if s.lineage == nil {
+		return
+	}
+	target := m.QualifiedName()
+
+	sources := append([]string(nil), m.DependsOn...)
+	tables, err := ExtractSourceTables(m.SQL, m.ProjectName, allModels)
+	if err != nil {
+		s.logger.Debug("source table extraction failed", "model", target, "error", err)
+	}
+	sources = append(sources, tables...)
+
+	if err := s.lineage.DeleteEdgesByTarget(ctx, target, modelLineageEdgeType); err != nil {
+		s.logger.Warn("reset model lineage failed", "model", target, "error", err)
+		return
+	}
+	for _, source := range sources {
+		sourceSchema, _, _ := strings.Cut(source, ".")
+		edge := &domain.LineageEdge{
+			SourceTable:   source,
+			TargetTable:   strPtr(target),
+			SourceSchema:  sourceSchema,
+			TargetSchema:  m.ProjectName,
+			EdgeType:      modelLineageEdgeType,
+			PrincipalName: principal,
+		}
+		if err := s.lineage.InsertEdge(ctx, edge); err != nil {
+			s.logger.Warn("insert model lineage edge failed", "source", source, "model", target, "error", err)
+			return
+		}
+	}
+}
+
 func depToLineageSource(dep, defaultSchema string) (schema, table string) {
 	if strings.HasPrefix(dep, "source:") {
 		dep = strings.TrimPrefix(dep, "source:")
@@ -1,9 +1,17 @@
 package model
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
 	"testing"
 
+	"duck-demo/internal/domain"
+	"duck-demo/internal/testutil"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDepToLineageSource(t *testing.T) {
@@ -33,3 +41,162 @@ func TestMakeLineageTableName(t *testing.T) {
 	assert.Equal(t, "analytics.orders", makeLineageTableName("", "analytics", "orders"))
 	assert.Equal(t, "memory.analytics.orders", makeLineageTableName("memory", "analytics", "orders"))
 }
+
+// memModelRepo is an in-memory domain.ModelRepository for service tests.
+type memModelRepo struct {
+	models []domain.Model
+}
+
+func (r *memModelRepo) Create(_ context.Context, m *domain.Model) (*domain.Model, error) {
+	created := *m
+	created.ID = fmt.Sprintf("m%d", len(r.models)+1)
+	r.models = append(r.models, created)
+	return &created, nil
+}
+
+func (r *memModelRepo) GetByID(context.Context, string) (*domain.Model, error) {
+	panic("unexpected call")
+}
+
+func (r *memModelRepo) GetByName(_ context.Context, projectName, name string) (*domain.Model, error) {
+	for _, m := range r.models {
+		if m.ProjectName == projectName && m.Name == name {
+			return &m, nil
+		}
+	}
+	return nil, domain.ErrNotFound("model %s.%s not found", projectName, name)
+}
+
+func (r *memModelRepo) List(context.Context, *string, domain.PageRequest) ([]domain.Model, int64, error) {
+	panic("unexpected call")
+}
+
+func (r *memModelRepo) Update(_ context.Context, id string, req domain.UpdateModelRequest) (*domain.Model, error) {
+	for i := range r.models {
+		if r.models[i].ID == id {
+			if req.SQL != nil {
+				r.models[i].SQL = *req.SQL
+			}
+			m := r.models[i]
+			return &m, nil
+		}
+	}
+	return nil, domain.ErrNotFound("model %s not found", id)
+}
+
+func (r *memModelRepo) Delete(_ context.Context, id string) error {
+	for i := range r.models {
+		if r.models[i].ID == id {
+			r.models = append(r.models[:i], r.models[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFound("model %s not found", id)
+}
+
+func (r *memModelRepo) ListAll(context.Context) ([]domain.Model, error) {
+	return append([]domain.Model(nil), r.models...), nil
+}
+
+func (r *memModelRepo) UpdateDependencies(_ context.Context, id string, deps []string) error {
+	for i := range r.models {
+		if r.models[i].ID == id {
+			r.models[i].DependsOn = deps
+		}
+	}
+	return nil
+}
+
+func (r *memModelRepo) UpdateMacroDependencies(_ context.Context, id string, macros []string) error {
+	for i := range r.models {
+		if r.models[i].ID == id {
+			r.models[i].MacroDeps = macros
+		}
+	}
+	return nil
+}
+
+// memLineageRepo records edges so tests can inspect the lineage graph.
+type memLineageRepo struct {
+	testutil.MockLineageRepo
+	edges []domain.LineageEdge
+}
+
+func (r *memLineageRepo) InsertEdge(_ context.Context, edge *domain.LineageEdge) error {
+	r.edges = append(r.edges, *edge)
+	return nil
+}
+
+func (r *memLineageRepo) DeleteEdgesByTarget(_ context.Context, targetTable, edgeType string) error {
+	kept := r.edges[:0]
+	for _, e := range r.edges {
+		if e.TargetTable == nil || *e.TargetTable != targetTable || e.EdgeType != edgeType {
+			kept = append(kept, e)
+		}
+	}
+	r.edges = kept
+	return nil
+}
+
+// upstreamOf lists "source -> target" for every edge into target.
+func (r *memLineageRepo) upstreamOf(target string) []string {
+	var out []string
+	for _, e := range r.edges {
+		if e.TargetTable != nil && *e.TargetTable == target {
+			out = append(out, e.SourceTable+" -> "+target)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestModelLineage_RegisteredOnCreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	lineage := &memLineageRepo{}
+	svc := &Service{
+		models:  &memModelRepo{},
+		audit:   &testutil.MockAuditRepo{},
+		lineage: lineage,
+		logger:  slog.New(slog.DiscardHandler),
+	}
+
+	_, err := svc.CreateModel(ctx, "alice", domain.CreateModelRequest{
+		ProjectName: "sales", Name: "stg_orders", SQL: "SELECT * FROM raw.orders",
+	})
+	require.NoError(t, err)
+	_, err = svc.CreateModel(ctx, "alice", domain.CreateModelRequest{
+		ProjectName: "sales", Name: "fct_orders",
+		SQL: "SELECT o.*, c.name FROM stg_orders o JOIN raw.customers c ON o.cid = c.id",
+	})
+	require.NoError(t, err)
+
+	t.Run("model reading a source table", func(t *testing.T) {
+		assert.Equal(t, []string{"raw.orders -> sales.stg_orders"}, lineage.upstreamOf("sales.stg_orders"))
+		require.NotEmpty(t, lineage.edges)
+		edge := lineage.edges[0]
+		assert.Equal(t, "MODEL", edge.EdgeType)
+		assert.Equal(t, "raw", edge.SourceSchema)
+		assert.Equal(t, "sales", edge.TargetSchema)
+		assert.Equal(t, "alice", edge.PrincipalName)
+	})
+
+	t.Run("model referencing a model", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"raw.customers -> sales.fct_orders",
+			"sales.stg_orders -> sales.fct_orders",
+		}, lineage.upstreamOf("sales.fct_orders"))
+	})
+
+	t.Run("update replaces edges", func(t *testing.T) {
+		sql := "SELECT * FROM stg_orders"
+		_, err := svc.UpdateModel(ctx, "bob", "sales", "fct_orders", domain.UpdateModelRequest{SQL: &sql})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"sales.stg_orders -> sales.fct_orders"}, lineage.upstreamOf("sales.fct_orders"))
+	})
+
+	t.Run("delete removes edges", func(t *testing.T) {
+		require.NoError(t, svc.DeleteModel(ctx, "bob", "sales", "fct_orders"))
+		assert.Empty(t, lineage.upstreamOf("sales.fct_orders"))
+		assert.NotEmpty(t, lineage.upstreamOf("sales.stg_orders"))
+	})
+}
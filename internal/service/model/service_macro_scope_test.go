@@ -1,12 +1,16 @@
 package model
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/testutil"
 )
 
 func TestLoadStarMacroScopes_PrecedenceProjectOverGlobalOverSystem(t *testing.T) {
@@ -64,3 +68,63 @@ func TestLoadStarModules(t *testing.T) {
 	assert.Contains(t, modules, "utils")
 	assert.Contains(t, modules, "nested.date")
 }
+
+// memMacroRepo serves registered macros to the model service.
+type memMacroRepo struct {
+	domain.MacroRepository
+	macros []domain.Macro
+}
+
+func (r *memMacroRepo) ListAll(context.Context) ([]domain.Macro, error) {
+	return append([]domain.Macro(nil), r.macros...), nil
+}
+
+func TestModelCallingSQLMacro_CompilesAndRuns(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newDuckDBServiceForTest(t)
+	runs := newMemRunRepo()
+	svc.runs = runs
+	svc.models = &memModelRepo{}
+	svc.audit = &testutil.MockAuditRepo{}
+	svc.SetMacroRepo(&memMacroRepo{macros: []domain.Macro{{
+		Name:       "fmt_money",
+		MacroType:  domain.MacroTypeScalar,
+		Parameters: []string{"x"},
+		Body:       "'$' || printf('%.2f', x)",
+		Status:     domain.MacroStatusActive,
+	}}})
+
+	_, err := db.ExecContext(ctx, `CREATE TABLE analytics.raw_orders AS
+		SELECT * FROM (VALUES (1, 12.5), (2, 3)) AS t(id, amount)`)
+	require.NoError(t, err)
+
+	m, err := svc.CreateModel(ctx, "admin", domain.CreateModelRequest{
+		ProjectName:     "analytics",
+		Name:            "fct_orders",
+		SQL:             "SELECT id, fmt_money(amount) AS amount_fmt FROM analytics.raw_orders",
+		Materialization: domain.MaterializationTable,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fmt_money"}, m.MacroDeps)
+
+	require.NoError(t, svc.TriggerRunSync(ctx, "admin", domain.TriggerModelRunRequest{
+		TargetCatalog: "memory",
+		TargetSchema:  "analytics",
+	}))
+	require.Len(t, runs.steps, 1)
+	step := runs.steps[0]
+	assert.Equal(t, domain.ModelRunStatusSuccess, step.Status)
+	assert.Equal(t, []string{"fmt_money"}, step.MacrosUsed)
+
+	rows, err := db.QueryContext(ctx, `SELECT amount_fmt FROM analytics.fct_orders ORDER BY id`)
+	require.NoError(t, err)
+	defer func() { _ = rows.Close() }()
+	var got []string
+	for rows.Next() {
+		var v string
+		require.NoError(t, rows.Scan(&v))
+		got = append(got, v)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"$12.50", "$3.00"}, got)
+}
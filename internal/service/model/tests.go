@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"duck-demo/internal/domain"
 )
 
+// duckDBTimestampLayout formats a time as a DuckDB TIMESTAMP literal.
+const duckDBTimestampLayout = "2006-01-02 15:04:05.000000"
+
 // generateTestSQL builds the SQL query for a model test.
 // The test passes if 0 rows are returned.
 func generateTestSQL(test domain.ModelTest, targetSchema, modelName string) (string, error) {
@@ -43,47 +47,79 @@ func generateTestSQL(test domain.ModelTest, targetSchema, modelName string) (str
 	}
 }
 
-// executeTests runs all tests for a model after materialization.
+// executeTests runs all tests for a model after materialization and records
+// their results against the run step.
 // Returns whether any test failed and any error encountered.
 func (s *Service) executeTests(ctx context.Context, conn *sql.Conn,
 	model *domain.Model, config ExecutionConfig, stepID, principal string) (bool, error) {
 
-	tests, err := s.tests.ListByModel(ctx, model.ID)
+	results, err := s.evaluateTests(ctx, conn, model, config, stepID, principal)
 	if err != nil {
-		return false, fmt.Errorf("list tests for %s: %w", model.QualifiedName(), err)
-	}
-	if len(tests) == 0 {
-		return false, nil
+		return false, err
 	}
 
 	anyFailed := false
+	for i := range results {
+		if results[i].Status != domain.TestResultPass {
+			anyFailed = true
+		}
+		s.recordTestResult(ctx, &results[i])
+	}
+	return anyFailed, nil
+}
+
+// evaluateTests runs all tests for a model against its materialized relation
+// and returns one result per test. With config.StoreFailures the failing rows
+// of each failed test are written to the model's failures table.
+func (s *Service) evaluateTests(ctx context.Context, conn *sql.Conn,
+	model *domain.Model, config ExecutionConfig, stepID, principal string) ([]domain.ModelTestResult, error) {
+
+	tests, err := s.tests.ListByModel(ctx, model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list tests for %s: %w", model.QualifiedName(), err)
+	}
+
+	results := make([]domain.ModelTestResult, 0, len(tests))
 	for _, test := range tests {
+		result := domain.ModelTestResult{
+			RunStepID: stepID,
+			TestID:    test.ID,
+			TestName:  test.Name,
+		}
+
 		testSQL, err := generateTestSQL(test, config.TargetSchema, model.Name)
 		if err != nil {
-			// Record error result
-			s.recordTestResult(ctx, stepID, test, domain.TestResultError, nil, err.Error())
-			anyFailed = true
+			result.Status = domain.TestResultError
+			result.ErrorMessage = strPtr(err.Error())
+			results = append(results, result)
 			continue
 		}
 
 		hasRows, queryErr := s.runTestQuery(ctx, conn, principal, testSQL)
 		if queryErr != nil {
-			s.recordTestResult(ctx, stepID, test, domain.TestResultError, nil, queryErr.Error())
-			anyFailed = true
+			result.Status = domain.TestResultError
+			result.ErrorMessage = strPtr(queryErr.Error())
+			results = append(results, result)
 			continue
 		}
 
+		var rowCount int64
+		result.Status = domain.TestResultPass
 		if hasRows {
-			var rowCount int64 = 1
-			s.recordTestResult(ctx, stepID, test, domain.TestResultFail, &rowCount, "")
-			anyFailed = true
-		} else {
-			var rowCount int64
-			s.recordTestResult(ctx, stepID, test, domain.TestResultPass, &rowCount, "")
+			rowCount = s.countFailingRows(ctx, conn, principal, testSQL)
+			result.Status = domain.TestResultFail
+			if config.StoreFailures {
+				if err := s.storeFailures(ctx, conn, model, config, test, stepID, principal, testSQL); err != nil {
+					s.logger.Warn("failed to store failing test rows", "test", test.Name, "error", err)
+					result.ErrorMessage = strPtr("failing rows not stored: " + err.Error())
+				}
+			}
 		}
+		result.RowsReturned = &rowCount
+		results = append(results, result)
 	}
 
-	return anyFailed, nil
+	return results, nil
 }
 
 // runTestQuery executes a test query and returns whether any rows were returned.
@@ -101,20 +137,77 @@ func (s *Service) runTestQuery(ctx context.Context, conn *sql.Conn, principal, t
 	return hasRows, nil
 }
 
-func (s *Service) recordTestResult(ctx context.Context, stepID string, test domain.ModelTest, status string, rowsReturned *int64, errMsg string) {
-	result := &domain.ModelTestResult{
-		RunStepID:    stepID,
-		TestID:       test.ID,
-		TestName:     test.Name,
-		Status:       status,
-		RowsReturned: rowsReturned,
+// failingRowsSQL returns a query for all rows a test fails on. Generated
+// test queries stop at the first failing row, so that limit is dropped.
+func failingRowsSQL(testSQL string) string {
+	body := strings.TrimRight(strings.TrimSpace(testSQL), ";")
+	return strings.TrimSuffix(body, " LIMIT 1")
+}
+
+// countFailingRows counts the rows a failed test query returns. Falls back
+// to 1 when the count query fails.
+func (s *Service) countFailingRows(ctx context.Context, conn *sql.Conn, principal, testSQL string) int64 {
+	rows, err := s.engine.QueryOnConn(ctx, conn, principal, fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS failures", failingRowsSQL(testSQL)))
+	if err != nil {
+		s.logger.Warn("failed to count failing test rows", "error", err)
+		return 1
+	}
+	defer func() { _ = rows.Close() }()
+	count := int64(1)
+	if rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 1
+		}
+	}
+	return count
+}
+
+// testFailuresTable returns the relation failing rows of a model's tests are
+// stored in: <model>_test_failures next to the model.
+func testFailuresTable(model *domain.Model, config ExecutionConfig) string {
+	return relationFQN(config.TargetCatalog, config.TargetSchema, model.Name+"_test_failures")
+}
+
+// storeFailures appends the rows a test fails on to the model's failures
+// table, one JSON document per row, after dropping rows older than the
+// retention period. The table is created on first use; the delete and
+// insert run through the secure engine as the principal, so they need the
+// same privileges as any other write to the target schema.
+func (s *Service) storeFailures(ctx context.Context, conn *sql.Conn, model *domain.Model,
+	config ExecutionConfig, test domain.ModelTest, stepID, principal, testSQL string) error {
+
+	table := testFailuresTable(model, config)
+	ddl := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s "+
+		"(test_name VARCHAR, run_step_id VARCHAR, stored_at TIMESTAMP, failing_row VARCHAR)", table)
+	if err := s.execOnConn(ctx, conn, principal, ddl); err != nil {
+		return fmt.Errorf("create %s: %w", table, err)
+	}
+
+	now := time.Now().UTC()
+	if s.testFailureTTL > 0 {
+		cutoff := now.Add(-s.testFailureTTL)
+		retention := fmt.Sprintf("DELETE FROM %s WHERE stored_at < CAST('%s' AS TIMESTAMP)", table, cutoff.Format(duckDBTimestampLayout))
+		if err := s.execOnConn(ctx, conn, principal, retention); err != nil {
+			return fmt.Errorf("apply retention to %s: %w", table, err)
+		}
+	}
+
+	step := "NULL"
+	if stepID != "" {
+		step = quoteLiteral(stepID)
 	}
-	if errMsg != "" {
-		result.ErrorMessage = &errMsg
+	insert := fmt.Sprintf("INSERT INTO %s SELECT %s, %s, CAST('%s' AS TIMESTAMP), CAST(to_json(failures) AS VARCHAR) FROM (%s) AS failures",
+		table, quoteLiteral(test.Name), step, now.Format(duckDBTimestampLayout), failingRowsSQL(testSQL))
+	if err := s.execOnConn(ctx, conn, principal, insert); err != nil {
+		return fmt.Errorf("insert into %s: %w", table, err)
 	}
+	return nil
+}
+
+func (s *Service) recordTestResult(ctx context.Context, result *domain.ModelTestResult) {
 	if s.testResults != nil {
 		if _, err := s.testResults.Create(ctx, result); err != nil {
-			s.logger.Warn("failed to record test result", "test", test.Name, "error", err)
+			s.logger.Warn("failed to record test result", "test", result.TestName, "error", err)
 		}
 	}
 }
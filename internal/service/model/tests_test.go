@@ -1,9 +1,13 @@
 package model
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"duck-demo/internal/domain"
+	"duck-demo/internal/testutil"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,3 +87,208 @@ func TestGenerateTestSQL(t *testing.T) {
 		})
 	}
 }
+
+// memTestRepo serves a fixed set of model tests.
+type memTestRepo struct {
+	tests []domain.ModelTest
+}
+
+func (r *memTestRepo) Create(context.Context, *domain.ModelTest) (*domain.ModelTest, error) {
+	panic("unexpected call")
+}
+
+func (r *memTestRepo) GetByID(_ context.Context, id string) (*domain.ModelTest, error) {
+	for _, t := range r.tests {
+		if t.ID == id {
+			return &t, nil
+		}
+	}
+	return nil, domain.ErrNotFound("model test %s not found", id)
+}
+
+func (r *memTestRepo) ListByModel(_ context.Context, modelID string) ([]domain.ModelTest, error) {
+	var out []domain.ModelTest
+	for _, t := range r.tests {
+		if t.ModelID == modelID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (r *memTestRepo) Delete(context.Context, string) error {
+	panic("unexpected call")
+}
+
+// memTestResultRepo keeps test results in insertion order.
+type memTestResultRepo struct {
+	results []domain.ModelTestResult
+}
+
+func (r *memTestResultRepo) Create(_ context.Context, result *domain.ModelTestResult) (*domain.ModelTestResult, error) {
+	cp := *result
+	cp.ID = fmt.Sprintf("result-%d", len(r.results)+1)
+	r.results = append(r.results, cp)
+	return &cp, nil
+}
+
+func (r *memTestResultRepo) ListByStep(context.Context, string) ([]domain.ModelTestResult, error) {
+	panic("unexpected call")
+}
+
+func (r *memTestResultRepo) ListByTest(_ context.Context, testID string, _ domain.PageRequest) ([]domain.ModelTestResult, int64, error) {
+	var out []domain.ModelTestResult
+	for i := len(r.results) - 1; i >= 0; i-- {
+		if r.results[i].TestID == testID {
+			out = append(out, r.results[i])
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func TestTestResultHistory_ConsecutiveRuns(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newDuckDBServiceForTest(t)
+	runs := newMemRunRepo()
+	svc.runs = runs
+	svc.models = &memModelRepo{}
+
+	_, err := db.ExecContext(ctx, `CREATE TABLE analytics.raw_orders AS SELECT range AS id, 'c' || range AS customer FROM range(10)`)
+	require.NoError(t, err)
+
+	m, err := svc.models.Create(ctx, &domain.Model{
+		ProjectName:     "analytics",
+		Name:            "stg_orders",
+		SQL:             "SELECT id, customer FROM analytics.raw_orders",
+		Materialization: domain.MaterializationTable,
+	})
+	require.NoError(t, err)
+	results := &memTestResultRepo{}
+	svc.SetTestRepos(&memTestRepo{tests: []domain.ModelTest{{
+		ID:       "test-1",
+		ModelID:  m.ID,
+		Name:     "not_null_customer",
+		TestType: domain.TestTypeNotNull,
+		Column:   "customer",
+	}}}, results)
+
+	runOnce := func() {
+		t.Helper()
+		run, err := runs.CreateRun(ctx, &domain.ModelRun{Status: domain.ModelRunStatusPending})
+		require.NoError(t, err)
+		_, err = runs.CreateStep(ctx, &domain.ModelRunStep{
+			RunID: run.ID, ModelID: m.ID, ModelName: m.QualifiedName(), CompiledSQL: &m.SQL,
+		})
+		require.NoError(t, err)
+		svc.executeRun(ctx, run.ID, []domain.Model{*m}, [][]DAGNode{{{Model: m}}},
+			ExecutionConfig{TargetSchema: "analytics"}, "admin")
+	}
+
+	runOnce()
+	_, err = db.ExecContext(ctx, `INSERT INTO analytics.raw_orders VALUES (10, NULL), (11, NULL), (12, NULL)`)
+	require.NoError(t, err)
+	runOnce()
+	runOnce()
+
+	history, total, err := svc.ListTestResultHistory(ctx, "analytics", "stg_orders", "test-1", domain.PageRequest{})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, total)
+	require.Len(t, history, 3)
+
+	// Newest first: two failing runs after the initial pass.
+	statuses := make([]string, len(history))
+	failing := make([]int64, len(history))
+	for i, r := range history {
+		statuses[i] = r.Status
+		require.NotNil(t, r.RowsReturned)
+		failing[i] = *r.RowsReturned
+	}
+	assert.Equal(t, []string{domain.TestResultFail, domain.TestResultFail, domain.TestResultPass}, statuses)
+	assert.Equal(t, []int64{3, 3, 0}, failing)
+
+	_, _, err = svc.ListTestResultHistory(ctx, "analytics", "stg_orders", "test-unknown", domain.PageRequest{})
+	require.Error(t, err)
+}
+
+func TestRunTests_StoreFailures(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newDuckDBServiceForTest(t)
+	svc.models = &memModelRepo{}
+	svc.audit = &testutil.MockAuditRepo{}
+
+	_, err := db.ExecContext(ctx, `CREATE TABLE analytics.stg_orders AS
+		SELECT * FROM (VALUES (1, 'alice'), (2, NULL), (3, 'carol'), (4, NULL)) AS t(id, customer)`)
+	require.NoError(t, err)
+	m, err := svc.models.Create(ctx, &domain.Model{
+		ProjectName:     "analytics",
+		Name:            "stg_orders",
+		SQL:             "SELECT 1",
+		Materialization: domain.MaterializationTable,
+	})
+	require.NoError(t, err)
+	svc.SetTestRepos(&memTestRepo{tests: []domain.ModelTest{
+		{ID: "test-1", ModelID: m.ID, Name: "not_null_customer", TestType: domain.TestTypeNotNull, Column: "customer"},
+		{ID: "test-2", ModelID: m.ID, Name: "unique_id", TestType: domain.TestTypeUnique, Column: "id"},
+	}}, &memTestResultRepo{})
+	svc.SetTestFailureRetention(24 * time.Hour)
+
+	req := domain.RunModelTestsRequest{TargetCatalog: "memory", TargetSchema: "analytics"}
+
+	t.Run("without store_failures no table is written", func(t *testing.T) {
+		run, err := svc.RunTests(ctx, "admin", "analytics", "stg_orders", req)
+		require.NoError(t, err)
+		require.Len(t, run.Results, 2)
+		assert.Equal(t, domain.TestResultFail, run.Results[0].Status)
+		assert.Empty(t, run.FailuresTable)
+
+		var n int
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'stg_orders_test_failures'`).Scan(&n))
+		assert.Zero(t, n)
+	})
+
+	t.Run("failing not_null rows are stored", func(t *testing.T) {
+		req := req
+		req.StoreFailures = true
+		run, err := svc.RunTests(ctx, "admin", "analytics", "stg_orders", req)
+		require.NoError(t, err)
+		assert.Equal(t, "memory.analytics.stg_orders_test_failures", run.FailuresTable)
+		require.Len(t, run.Results, 2)
+		assert.Equal(t, domain.TestResultFail, run.Results[0].Status)
+		require.NotNil(t, run.Results[0].RowsReturned)
+		assert.EqualValues(t, 2, *run.Results[0].RowsReturned)
+		assert.Nil(t, run.Results[0].ErrorMessage)
+		assert.Equal(t, domain.TestResultPass, run.Results[1].Status)
+
+		rows, err := db.QueryContext(ctx, `SELECT test_name, failing_row FROM analytics.stg_orders_test_failures ORDER BY failing_row`)
+		require.NoError(t, err)
+		defer func() { _ = rows.Close() }()
+		var stored []string
+		for rows.Next() {
+			var name, row string
+			require.NoError(t, rows.Scan(&name, &row))
+			assert.Equal(t, "not_null_customer", name)
+			stored = append(stored, row)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, []string{`{"id":2,"customer":null}`, `{"id":4,"customer":null}`}, stored)
+	})
+
+	t.Run("rows past the retention period are dropped", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, `INSERT INTO analytics.stg_orders_test_failures
+			VALUES ('not_null_customer', NULL, TIMESTAMP '2000-01-01 00:00:00', '{"id":99,"customer":null}')`)
+		require.NoError(t, err)
+
+		req := req
+		req.StoreFailures = true
+		_, err = svc.RunTests(ctx, "admin", "analytics", "stg_orders", req)
+		require.NoError(t, err)
+
+		var old, total int
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT
+			COUNT(*) FILTER (WHERE stored_at < TIMESTAMP '2001-01-01'), COUNT(*)
+			FROM analytics.stg_orders_test_failures`).Scan(&old, &total))
+		assert.Zero(t, old)
+		assert.Equal(t, 4, total, "both stored runs are kept within the retention period")
+	})
+}
@@ -9,17 +9,22 @@ import (
 
 // GitService provides business logic for Git repository operations.
 type GitService struct {
-	repo  domain.GitRepoRepository
-	audit domain.AuditRepository
+	repo      domain.GitRepoRepository
+	notebooks domain.NotebookRepository
+	audit     domain.AuditRepository
 }
 
 // NewGitService creates a new GitService.
-func NewGitService(repo domain.GitRepoRepository, audit domain.AuditRepository) *GitService {
-	return &GitService{repo: repo, audit: audit}
+func NewGitService(repo domain.GitRepoRepository, notebooks domain.NotebookRepository, audit domain.AuditRepository) *GitService {
+	return &GitService{repo: repo, notebooks: notebooks, audit: audit}
 }
 
-// CreateGitRepo registers a new Git repository for notebook sync.
-func (s *GitService) CreateGitRepo(ctx context.Context, principal string, req domain.CreateGitRepoRequest) (*domain.GitRepo, error) {
+// CreateGitRepo registers a new Git repository for notebook sync. Only admins
+// can register repos, since the server fetches from the URL.
+func (s *GitService) CreateGitRepo(ctx context.Context, principal string, isAdmin bool, req domain.CreateGitRepoRequest) (*domain.GitRepo, error) {
+	if !isAdmin {
+		return nil, domain.ErrAccessDenied("only admins can register git repos")
+	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -79,13 +84,3 @@ func (s *GitService) DeleteGitRepo(ctx context.Context, principal string, isAdmi
 
 	return nil
 }
-
-// SyncGitRepo triggers a sync from the Git repository to notebooks.
-// Currently stubbed — full go-git integration will be added in a follow-up.
-func (s *GitService) SyncGitRepo(ctx context.Context, id string) (*domain.GitSyncResult, error) {
-	// Verify the repo exists
-	if _, err := s.repo.GetByID(ctx, id); err != nil {
-		return nil, err
-	}
-	return nil, domain.ErrNotImplemented("git sync is not yet implemented")
-}
@@ -0,0 +1,317 @@
+package notebook
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"duck-demo/internal/declarative"
+	"duck-demo/internal/domain"
+)
+
+// gitRefPattern restricts refs to branch, tag and commit names; it rules out
+// options ("-x"), refspecs ("a:b", "+a") and revision ranges ("a..b").
+var gitRefPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_./-]*$`)
+
+// gitAllowProtocol is the transport allowlist passed to git. Only the
+// authenticated, encrypted transports are allowed; tests add file to fetch
+// from local fixtures.
+var gitAllowProtocol = "https:ssh"
+
+// SyncGitRepo checks out ref (default: the repo's branch) and reconciles the
+// notebooks declared under the repo's path with the notebooks previously
+// synced from the repo: new manifests create notebooks, changed manifests
+// update their description and cells, and removed manifests delete their
+// notebooks. Notebooks not created by a sync are never touched. Synced
+// notebooks are owned by the repo owner. With DryRun the changes are computed
+// and reported but not applied. Only admins can sync.
+//
+// Manifests use the declarative Notebook format read by duck apply; a
+// directory declaring any other kind is rejected.
+func (s *GitService) SyncGitRepo(ctx context.Context, principal string, isAdmin bool, id string, opts domain.GitSyncOptions) (*domain.GitSyncResult, error) {
+	if !isAdmin {
+		return nil, domain.ErrAccessDenied("only admins can sync git repos")
+	}
+	repo, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := opts.Ref
+	if ref == "" {
+		ref = repo.Branch
+	}
+	if len(ref) > 255 || strings.Contains(ref, "..") || !gitRefPattern.MatchString(ref) {
+		return nil, domain.ErrValidation("invalid git ref %q", ref)
+	}
+	manifestDir, err := cleanRepoPath(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	checkout, err := os.MkdirTemp("", "duck-git-sync-*")
+	if err != nil {
+		return nil, fmt.Errorf("create checkout dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(checkout) }()
+
+	commit, err := checkoutGitRef(ctx, checkout, repo.URL, ref, repo.AuthToken)
+	if err != nil {
+		return nil, err
+	}
+
+	desired, err := loadSyncedNotebooks(filepath.Join(checkout, filepath.FromSlash(manifestDir)))
+	if err != nil {
+		return nil, err
+	}
+	actual, existing, err := s.readSyncedNotebooks(ctx, repo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := declarative.Diff(
+		&declarative.DesiredState{Notebooks: desired},
+		&declarative.DesiredState{Notebooks: actual},
+	)
+	result := &domain.GitSyncResult{CommitSHA: commit, Ref: ref, DryRun: opts.DryRun}
+	for _, action := range plan.Actions {
+		change := domain.GitSyncChange{
+			Notebook: action.ResourceName,
+			Path:     path.Join(manifestDir, "notebooks", action.ResourceName+".yaml"),
+		}
+		switch action.Operation {
+		case declarative.OpCreate:
+			change.Action = domain.GitSyncCreate
+			result.NotebooksCreated++
+		case declarative.OpUpdate:
+			change.Action = domain.GitSyncUpdate
+			result.NotebooksUpdated++
+		case declarative.OpDelete:
+			change.Action = domain.GitSyncDelete
+			if p := existing[action.ResourceName].GitPath; p != nil {
+				change.Path = *p
+			}
+			result.NotebooksDeleted++
+		default:
+			continue
+		}
+		result.Changes = append(result.Changes, change)
+
+		if opts.DryRun {
+			continue
+		}
+		if err := s.applySyncChange(ctx, repo, change, action, existing[action.ResourceName]); err != nil {
+			return nil, fmt.Errorf("%s notebook %q: %w", change.Action, change.Notebook, err)
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if err := s.repo.UpdateSyncStatus(ctx, repo.ID, commit, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("update sync status: %w", err)
+	}
+	_ = s.audit.Insert(ctx, &domain.AuditEntry{
+		PrincipalName: principal,
+		Action:        "SYNC_GIT_REPO",
+		Status:        "ALLOWED",
+	})
+	return result, nil
+}
+
+// cleanRepoPath normalizes the repo's manifest path and rejects paths that
+// leave the checkout.
+func cleanRepoPath(p string) (string, error) {
+	if strings.Contains(p, "..") {
+		return "", domain.ErrValidation("git repo path %q must not contain '..'", p)
+	}
+	return strings.TrimPrefix(path.Clean("/"+p), "/"), nil
+}
+
+// loadSyncedNotebooks reads and validates the notebook manifests under dir.
+// Sync only reconciles notebooks, so manifests of any other kind are an
+// error rather than silently skipped. Owners are dropped: synced notebooks
+// always belong to the repo owner.
+func loadSyncedNotebooks(dir string) ([]declarative.NotebookResource, error) {
+	state, err := declarative.LoadDirectory(dir)
+	if err != nil {
+		return nil, domain.ErrValidation("load manifests: %v", err)
+	}
+	notebooks := state.Notebooks
+	state.Notebooks = nil
+	if kinds := declaredKinds(state); len(kinds) > 0 {
+		return nil, domain.ErrValidation("git sync only supports notebook manifests; found %s", strings.Join(kinds, ", "))
+	}
+	state.Notebooks = notebooks
+	for i, nb := range state.Notebooks {
+		state.Notebooks[i].Spec.Owner = ""
+		for j, c := range nb.Spec.Cells {
+			req := domain.CreateCellRequest{CellType: domain.CellType(c.Type)}
+			if err := req.Validate(); err != nil {
+				return nil, domain.ErrValidation("notebook %q cell %d: %v", nb.Name, j, err)
+			}
+		}
+	}
+	return state.Notebooks, nil
+}
+
+// declaredKinds returns the sorted resource kinds state declares.
+func declaredKinds(state *declarative.DesiredState) []string {
+	plan := declarative.Diff(state, &declarative.DesiredState{})
+	seen := make(map[string]bool)
+	var kinds []string
+	for _, action := range plan.Actions {
+		kind := action.ResourceKind.String()
+		if !seen[kind] {
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// readSyncedNotebooks returns the notebooks previously synced from the repo
+// in declarative form, plus the stored notebooks by name.
+func (s *GitService) readSyncedNotebooks(ctx context.Context, gitRepoID string) ([]declarative.NotebookResource, map[string]domain.Notebook, error) {
+	notebooks, err := s.notebooks.ListNotebooksByGitRepo(ctx, gitRepoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list synced notebooks: %w", err)
+	}
+	resources := make([]declarative.NotebookResource, 0, len(notebooks))
+	byName := make(map[string]domain.Notebook, len(notebooks))
+	for _, nb := range notebooks {
+		cells, err := s.notebooks.ListCells(ctx, nb.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("list cells of notebook %q: %w", nb.Name, err)
+		}
+		spec := declarative.NotebookSpec{Cells: make([]declarative.CellSpec, 0, len(cells))}
+		if nb.Description != nil {
+			spec.Description = *nb.Description
+		}
+		for _, c := range cells {
+			spec.Cells = append(spec.Cells, declarative.CellSpec{Type: string(c.CellType), Content: c.Content})
+		}
+		resources = append(resources, declarative.NotebookResource{Name: nb.Name, Spec: spec})
+		byName[nb.Name] = nb
+	}
+	return resources, byName, nil
+}
+
+// applySyncChange applies one planned change. Updates replace all cells, the
+// same way duck apply reconciles a notebook.
+func (s *GitService) applySyncChange(ctx context.Context, repo *domain.GitRepo, change domain.GitSyncChange, action declarative.Action, existing domain.Notebook) error {
+	switch change.Action {
+	case domain.GitSyncCreate:
+		desired := action.Desired.(declarative.NotebookResource)
+		gitPath := change.Path
+		nb, err := s.notebooks.CreateNotebook(ctx, &domain.Notebook{
+			Name:        desired.Name,
+			Description: optionalString(desired.Spec.Description),
+			Owner:       repo.Owner,
+			GitRepoID:   &repo.ID,
+			GitPath:     &gitPath,
+		})
+		if err != nil {
+			return err
+		}
+		return s.createSyncedCells(ctx, nb.ID, desired.Spec.Cells)
+
+	case domain.GitSyncUpdate:
+		desired := action.Desired.(declarative.NotebookResource)
+		description := desired.Spec.Description
+		if _, err := s.notebooks.UpdateNotebook(ctx, existing.ID, domain.UpdateNotebookRequest{Description: &description}); err != nil {
+			return err
+		}
+		cells, err := s.notebooks.ListCells(ctx, existing.ID)
+		if err != nil {
+			return err
+		}
+		for _, c := range cells {
+			if err := s.notebooks.DeleteCell(ctx, c.ID); err != nil {
+				return err
+			}
+		}
+		return s.createSyncedCells(ctx, existing.ID, desired.Spec.Cells)
+
+	case domain.GitSyncDelete:
+		return s.notebooks.DeleteNotebook(ctx, existing.ID)
+	}
+	return nil
+}
+
+func (s *GitService) createSyncedCells(ctx context.Context, notebookID string, cells []declarative.CellSpec) error {
+	for i, c := range cells {
+		if _, err := s.notebooks.CreateCell(ctx, &domain.Cell{
+			ID:         domain.NewID(),
+			NotebookID: notebookID,
+			CellType:   domain.CellType(c.Type),
+			Content:    c.Content,
+			Position:   i,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// checkoutGitRef fetches ref from url into dir with a shallow fetch and
+// returns the checked-out commit SHA. The auth token is passed to git through
+// the environment so it never appears in process arguments or error output.
+func checkoutGitRef(ctx context.Context, dir, url, ref, token string) (string, error) {
+	env := append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ALLOW_PROTOCOL="+gitAllowProtocol,
+	)
+	if token != "" {
+		basic := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+basic,
+		)
+	}
+	git := func(args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, "git", args...) //nolint:gosec // arguments are validated or passed after "--"
+		cmd.Dir = dir
+		cmd.Env = env
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			msg := strings.TrimSpace(stderr.String())
+			if msg == "" {
+				msg = err.Error()
+			}
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	if _, err := git("init", "--quiet"); err != nil {
+		return "", fmt.Errorf("prepare checkout: %w", err)
+	}
+	if _, err := git("fetch", "--quiet", "--depth", "1", "--", url, ref); err != nil {
+		return "", domain.ErrValidation("fetch %s from %s: %v", ref, url, err)
+	}
+	if _, err := git("checkout", "--quiet", "--detach", "FETCH_HEAD"); err != nil {
+		return "", fmt.Errorf("checkout %s: %w", ref, err)
+	}
+	return git("rev-parse", "HEAD")
+}
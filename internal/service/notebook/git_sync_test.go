@@ -0,0 +1,313 @@
+package notebook
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitFixture is a local bare repository with a work tree used to push
+// commits to it.
+type gitFixture struct {
+	t    *testing.T
+	bare string
+	work string
+}
+
+// newGitFixture creates the fixture and allows the file transport for the
+// rest of the test.
+func newGitFixture(t *testing.T) *gitFixture {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	allowed := gitAllowProtocol
+	gitAllowProtocol = allowed + ":file"
+	t.Cleanup(func() { gitAllowProtocol = allowed })
+	root := t.TempDir()
+	f := &gitFixture{t: t, bare: filepath.Join(root, "repo.git"), work: filepath.Join(root, "work")}
+	f.git(root, "init", "--quiet", "--bare", "--initial-branch=main", f.bare)
+	f.git(root, "init", "--quiet", "--initial-branch=main", f.work)
+	return f
+}
+
+func (f *gitFixture) git(dir string, args ...string) string {
+	f.t.Helper()
+	cmd := exec.CommandContext(context.Background(), "git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(f.t, err, "git %s: %s", strings.Join(args, " "), out)
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes files (relative path → content; empty content deletes) and
+// pushes them to branch, returning the commit SHA.
+func (f *gitFixture) commit(branch string, files map[string]string) string {
+	f.t.Helper()
+	for name, content := range files {
+		p := filepath.Join(f.work, filepath.FromSlash(name))
+		if content == "" {
+			require.NoError(f.t, os.Remove(p))
+			continue
+		}
+		require.NoError(f.t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(f.t, os.WriteFile(p, []byte(content), 0o600))
+	}
+	f.git(f.work, "checkout", "--quiet", "-B", branch)
+	f.git(f.work, "add", "--all")
+	f.git(f.work, "commit", "--quiet", "-m", "update")
+	f.git(f.work, "push", "--quiet", "--force", f.bare, branch)
+	return f.git(f.work, "rev-parse", "HEAD")
+}
+
+func notebookManifest(name, description string, cells ...string) string {
+	var b strings.Builder
+	b.WriteString("apiVersion: duck/v1\nkind: Notebook\nmetadata:\n  name: " + name + "\nspec:\n  description: " + description + "\n  cells:\n")
+	for _, c := range cells {
+		typ, content, _ := strings.Cut(c, ":")
+		b.WriteString("    - type: " + typ + "\n      content: " + content + "\n")
+	}
+	return b.String()
+}
+
+// memNotebookRepo returns a notebook repository mock backed by maps.
+func memNotebookRepo() (*testutil.MockNotebookRepo, map[string]*domain.Notebook, map[string][]domain.Cell) {
+	notebooks := make(map[string]*domain.Notebook)
+	cells := make(map[string][]domain.Cell)
+	repo := &testutil.MockNotebookRepo{
+		CreateNotebookFn: func(_ context.Context, nb *domain.Notebook) (*domain.Notebook, error) {
+			stored := *nb
+			stored.ID = domain.NewID()
+			notebooks[stored.ID] = &stored
+			return &stored, nil
+		},
+		ListNotebooksByGitRepoFn: func(_ context.Context, gitRepoID string) ([]domain.Notebook, error) {
+			var out []domain.Notebook
+			for _, nb := range notebooks {
+				if nb.GitRepoID != nil && *nb.GitRepoID == gitRepoID {
+					out = append(out, *nb)
+				}
+			}
+			sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+			return out, nil
+		},
+		UpdateNotebookFn: func(_ context.Context, id string, req domain.UpdateNotebookRequest) (*domain.Notebook, error) {
+			notebooks[id].Description = req.Description
+			return notebooks[id], nil
+		},
+		DeleteNotebookFn: func(_ context.Context, id string) error {
+			delete(notebooks, id)
+			delete(cells, id)
+			return nil
+		},
+		ListCellsFn: func(_ context.Context, notebookID string) ([]domain.Cell, error) {
+			return append([]domain.Cell(nil), cells[notebookID]...), nil
+		},
+		CreateCellFn: func(_ context.Context, c *domain.Cell) (*domain.Cell, error) {
+			cells[c.NotebookID] = append(cells[c.NotebookID], *c)
+			return c, nil
+		},
+		DeleteCellFn: func(_ context.Context, id string) error {
+			for nbID, list := range cells {
+				for i, c := range list {
+					if c.ID == id {
+						cells[nbID] = append(list[:i], list[i+1:]...)
+						return nil
+					}
+				}
+			}
+			return domain.ErrNotFound("cell not found")
+		},
+	}
+	return repo, notebooks, cells
+}
+
+func TestGitService_SyncGitRepo_LocalBareRepo(t *testing.T) {
+	fixture := newGitFixture(t)
+	first := fixture.commit("main", map[string]string{
+		"analytics/notebooks/kpi.yaml":     notebookManifest("kpi", "KPIs", "markdown:'# KPIs'", "sql:SELECT 1"),
+		"analytics/notebooks/scratch.yaml": notebookManifest("scratch", "Scratch", "sql:SELECT 2"),
+		"README.md":                        "not a manifest\n",
+	})
+
+	gitRepo := &domain.GitRepo{ID: "repo-1", URL: fixture.bare, Branch: "main", Path: "analytics/", Owner: "alice"}
+	repos := &testutil.MockGitRepoRepo{
+		GetByIDFn: func(_ context.Context, _ string) (*domain.GitRepo, error) { return gitRepo, nil },
+		UpdateSyncStatusFn: func(_ context.Context, _ string, commitSHA string, syncedAt time.Time) error {
+			gitRepo.LastCommit = &commitSHA
+			gitRepo.LastSyncAt = &syncedAt
+			return nil
+		},
+	}
+	nbRepo, notebooks, cells := memNotebookRepo()
+	// A notebook created outside the sync is never touched.
+	notebooks["manual"] = &domain.Notebook{ID: "manual", Name: "kpi", Owner: "bob"}
+	audit := &testutil.MockAuditRepo{}
+	svc := NewGitService(repos, nbRepo, audit)
+	ctx := context.Background()
+
+	byName := func() map[string]*domain.Notebook {
+		out := make(map[string]*domain.Notebook)
+		for _, nb := range notebooks {
+			if nb.GitRepoID != nil {
+				out[nb.Name] = nb
+			}
+		}
+		return out
+	}
+
+	t.Run("dry run reports without applying", func(t *testing.T) {
+		result, err := svc.SyncGitRepo(ctx, "admin", true, "repo-1", domain.GitSyncOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, first, result.CommitSHA)
+		assert.Equal(t, 2, result.NotebooksCreated)
+		assert.Equal(t, []domain.GitSyncChange{
+			{Action: domain.GitSyncCreate, Notebook: "kpi", Path: "analytics/notebooks/kpi.yaml"},
+			{Action: domain.GitSyncCreate, Notebook: "scratch", Path: "analytics/notebooks/scratch.yaml"},
+		}, result.Changes)
+		assert.Empty(t, byName())
+		assert.Nil(t, gitRepo.LastCommit)
+	})
+
+	t.Run("first sync creates notebooks", func(t *testing.T) {
+		result, err := svc.SyncGitRepo(ctx, "admin", true, "repo-1", domain.GitSyncOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.NotebooksCreated)
+		require.NotNil(t, gitRepo.LastCommit)
+		assert.Equal(t, first, *gitRepo.LastCommit)
+		assert.True(t, audit.HasAction("SYNC_GIT_REPO"))
+
+		synced := byName()
+		require.Len(t, synced, 2)
+		kpi := synced["kpi"]
+		assert.Equal(t, "alice", kpi.Owner)
+		assert.Equal(t, "analytics/notebooks/kpi.yaml", *kpi.GitPath)
+		require.Len(t, cells[kpi.ID], 2)
+		assert.Equal(t, domain.CellTypeMarkdown, cells[kpi.ID][0].CellType)
+		assert.Equal(t, "SELECT 1", cells[kpi.ID][1].Content)
+		assert.Equal(t, "bob", notebooks["manual"].Owner)
+	})
+
+	t.Run("resync without changes is a no-op", func(t *testing.T) {
+		result, err := svc.SyncGitRepo(ctx, "admin", true, "repo-1", domain.GitSyncOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, result.Changes)
+	})
+
+	t.Run("ref selects another branch", func(t *testing.T) {
+		second := fixture.commit("feature", map[string]string{
+			"analytics/notebooks/kpi.yaml":     notebookManifest("kpi", "KPIs v2", "sql:SELECT 42"),
+			"analytics/notebooks/scratch.yaml": "",
+		})
+
+		result, err := svc.SyncGitRepo(ctx, "admin", true, "repo-1", domain.GitSyncOptions{Ref: "feature"})
+		require.NoError(t, err)
+		assert.Equal(t, second, result.CommitSHA)
+		assert.Equal(t, "feature", result.Ref)
+		assert.Equal(t, []domain.GitSyncChange{
+			{Action: domain.GitSyncUpdate, Notebook: "kpi", Path: "analytics/notebooks/kpi.yaml"},
+			{Action: domain.GitSyncDelete, Notebook: "scratch", Path: "analytics/notebooks/scratch.yaml"},
+		}, result.Changes)
+
+		synced := byName()
+		require.Len(t, synced, 1)
+		kpi := synced["kpi"]
+		assert.Equal(t, "KPIs v2", *kpi.Description)
+		require.Len(t, cells[kpi.ID], 1)
+		assert.Equal(t, "SELECT 42", cells[kpi.ID][0].Content)
+		assert.Contains(t, notebooks, "manual")
+	})
+
+	t.Run("unknown ref is a validation error", func(t *testing.T) {
+		_, err := svc.SyncGitRepo(ctx, "admin", true, "repo-1", domain.GitSyncOptions{Ref: "missing"})
+		var validation *domain.ValidationError
+		require.ErrorAs(t, err, &validation)
+	})
+}
+
+func TestGitService_SyncGitRepo_RejectsOtherKinds(t *testing.T) {
+	fixture := newGitFixture(t)
+	fixture.commit("main", map[string]string{
+		"analytics/notebooks/kpi.yaml":       notebookManifest("kpi", "KPIs", "sql:SELECT 1"),
+		"analytics/security/principals.yaml": "apiVersion: duck/v1\nkind: PrincipalList\nprincipals:\n  - name: eve\n    type: user\n",
+	})
+
+	repos := &testutil.MockGitRepoRepo{
+		GetByIDFn: func(_ context.Context, id string) (*domain.GitRepo, error) {
+			return &domain.GitRepo{ID: id, URL: fixture.bare, Branch: "main", Path: "analytics/", Owner: "alice"}, nil
+		},
+	}
+	nbRepo, notebooks, _ := memNotebookRepo()
+	svc := NewGitService(repos, nbRepo, &testutil.MockAuditRepo{})
+
+	_, err := svc.SyncGitRepo(context.Background(), "admin", true, "repo-1", domain.GitSyncOptions{})
+	var validation *domain.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Contains(t, err.Error(), "principal")
+	assert.Empty(t, notebooks)
+}
+
+func TestGitService_SyncGitRepo_FileTransportDisabled(t *testing.T) {
+	fixture := newGitFixture(t)
+	fixture.commit("main", map[string]string{
+		"notebooks/kpi.yaml": notebookManifest("kpi", "KPIs", "sql:SELECT 1"),
+	})
+	gitAllowProtocol = "https:ssh"
+
+	repos := &testutil.MockGitRepoRepo{
+		GetByIDFn: func(_ context.Context, id string) (*domain.GitRepo, error) {
+			return &domain.GitRepo{ID: id, URL: fixture.bare, Branch: "main", Owner: "alice"}, nil
+		},
+	}
+	svc := NewGitService(repos, &testutil.MockNotebookRepo{}, &testutil.MockAuditRepo{})
+
+	_, err := svc.SyncGitRepo(context.Background(), "admin", true, "repo-1", domain.GitSyncOptions{})
+	var validation *domain.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Contains(t, err.Error(), "transport 'file' not allowed")
+}
+
+func TestGitService_SyncGitRepo_Rejections(t *testing.T) {
+	repos := &testutil.MockGitRepoRepo{
+		GetByIDFn: func(_ context.Context, id string) (*domain.GitRepo, error) {
+			if id == "nonexistent" {
+				return nil, domain.ErrNotFound("git repo not found")
+			}
+			return &domain.GitRepo{ID: id, URL: "https://example.com/repo.git", Branch: "main", Path: "../etc", Owner: "alice"}, nil
+		},
+	}
+	svc := NewGitService(repos, &testutil.MockNotebookRepo{}, &testutil.MockAuditRepo{})
+	ctx := context.Background()
+
+	_, err := svc.SyncGitRepo(ctx, "admin", true, "nonexistent", domain.GitSyncOptions{})
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+
+	for _, principal := range []string{"alice", "mallory"} {
+		_, err = svc.SyncGitRepo(ctx, principal, false, "repo-1", domain.GitSyncOptions{})
+		var denied *domain.AccessDeniedError
+		require.ErrorAs(t, err, &denied, "non-admin %q", principal)
+	}
+
+	for _, ref := range []string{"-upload-pack=evil", "main:other", "a..b", "+main", "a b"} {
+		_, err = svc.SyncGitRepo(ctx, "admin", true, "repo-1", domain.GitSyncOptions{Ref: ref})
+		var validation *domain.ValidationError
+		require.ErrorAs(t, err, &validation, "ref %q", ref)
+	}
+
+	_, err = svc.SyncGitRepo(ctx, "bob", true, "repo-1", domain.GitSyncOptions{})
+	var validation *domain.ValidationError
+	require.ErrorAs(t, err, &validation, "path escaping the checkout")
+}
@@ -15,7 +15,7 @@ func setupGitService(t *testing.T) (*GitService, *testutil.MockGitRepoRepo, *tes
 	t.Helper()
 	repo := &testutil.MockGitRepoRepo{}
 	audit := &testutil.MockAuditRepo{}
-	svc := NewGitService(repo, audit)
+	svc := NewGitService(repo, &testutil.MockNotebookRepo{}, audit)
 	return svc, repo, audit
 }
 
@@ -36,7 +36,7 @@ func TestGitService_CreateGitRepo(t *testing.T) {
 			return r, nil
 		}
 
-		result, err := svc.CreateGitRepo(ctx, "alice", domain.CreateGitRepoRequest{
+		result, err := svc.CreateGitRepo(ctx, "alice", true, domain.CreateGitRepoRequest{
 			URL:       "https://github.com/org/repo.git",
 			Branch:    "main",
 			Path:      "/notebooks",
@@ -53,7 +53,7 @@ func TestGitService_CreateGitRepo(t *testing.T) {
 		svc, _, _ := setupGitService(t)
 		ctx := context.Background()
 
-		_, err := svc.CreateGitRepo(ctx, "alice", domain.CreateGitRepoRequest{
+		_, err := svc.CreateGitRepo(ctx, "alice", true, domain.CreateGitRepoRequest{
 			URL:    "",
 			Branch: "main",
 		})
@@ -63,11 +63,37 @@ func TestGitService_CreateGitRepo(t *testing.T) {
 		assert.Contains(t, validationErr.Message, "url is required")
 	})
 
+	t.Run("validation_error_file_url", func(t *testing.T) {
+		svc, _, _ := setupGitService(t)
+		ctx := context.Background()
+
+		_, err := svc.CreateGitRepo(ctx, "alice", true, domain.CreateGitRepoRequest{
+			URL:    "file:///srv/repo.git",
+			Branch: "main",
+		})
+		require.Error(t, err)
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("non_admin_denied", func(t *testing.T) {
+		svc, _, _ := setupGitService(t)
+		ctx := context.Background()
+
+		_, err := svc.CreateGitRepo(ctx, "alice", false, domain.CreateGitRepoRequest{
+			URL:    "https://github.com/org/repo.git",
+			Branch: "main",
+		})
+		require.Error(t, err)
+		var accessDenied *domain.AccessDeniedError
+		require.ErrorAs(t, err, &accessDenied)
+	})
+
 	t.Run("validation_error_empty_branch", func(t *testing.T) {
 		svc, _, _ := setupGitService(t)
 		ctx := context.Background()
 
-		_, err := svc.CreateGitRepo(ctx, "alice", domain.CreateGitRepoRequest{
+		_, err := svc.CreateGitRepo(ctx, "alice", true, domain.CreateGitRepoRequest{
 			URL:    "https://github.com/org/repo.git",
 			Branch: "",
 		})
@@ -209,22 +235,6 @@ func TestGitService_DeleteGitRepo(t *testing.T) {
 // === SyncGitRepo ===
 
 func TestGitService_SyncGitRepo(t *testing.T) {
-	t.Run("returns_not_implemented_error", func(t *testing.T) {
-		svc, repo, _ := setupGitService(t)
-		ctx := context.Background()
-
-		repo.GetByIDFn = func(_ context.Context, id string) (*domain.GitRepo, error) {
-			return &domain.GitRepo{ID: id, Owner: "alice"}, nil
-		}
-
-		result, err := svc.SyncGitRepo(ctx, "repo-1")
-		assert.Nil(t, result)
-		require.Error(t, err)
-		var notImplErr *domain.NotImplementedError
-		require.ErrorAs(t, err, &notImplErr)
-		assert.Contains(t, notImplErr.Message, "not yet implemented")
-	})
-
 	t.Run("not_found_repo", func(t *testing.T) {
 		svc, repo, _ := setupGitService(t)
 		ctx := context.Background()
@@ -233,7 +243,7 @@ func TestGitService_SyncGitRepo(t *testing.T) {
 			return nil, domain.ErrNotFound("git repo not found")
 		}
 
-		result, err := svc.SyncGitRepo(ctx, "nonexistent")
+		result, err := svc.SyncGitRepo(ctx, "admin", true, "nonexistent", domain.GitSyncOptions{})
 		assert.Nil(t, result)
 		require.Error(t, err)
 		var notFound *domain.NotFoundError
@@ -70,6 +70,13 @@ func NewSessionManager(
 	}
 }
 
+// ActiveSessions returns the number of open sessions.
+func (m *SessionManager) ActiveSessions() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.sessions)
+}
+
 // CreateSession creates a new session with a pinned DuckDB connection.
 func (m *SessionManager) CreateSession(ctx context.Context, notebookID, principal string) (*domain.NotebookSession, error) {
 	// Verify notebook exists
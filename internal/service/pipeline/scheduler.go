@@ -17,7 +17,14 @@ type Scheduler struct {
 	pipelines domain.PipelineRepository
 	logger    *slog.Logger
 	mu        sync.Mutex
-	entries   map[string]cron.EntryID // pipeline ID → cron entry
+	entries   map[string]cron.EntryID // pipeline ID or "job:" + job name → cron entry
+	sources   []JobSource
+}
+
+// JobSource supplies scheduled jobs owned by other services. Sources are
+// listed again on every reload.
+type JobSource interface {
+	ScheduledJobs(ctx context.Context) ([]domain.ScheduledJob, error)
 }
 
 // NewScheduler creates a new pipeline scheduler.
@@ -41,6 +48,13 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	return nil
 }
 
+// AddJobSource registers a source of scheduled jobs. Call it before Start.
+func (s *Scheduler) AddJobSource(src JobSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sources = append(s.sources, src)
+}
+
 // Stop gracefully stops the cron scheduler.
 func (s *Scheduler) Stop() {
 	s.cron.Stop()
@@ -100,8 +114,34 @@ func (s *Scheduler) loadSchedules(ctx context.Context) error {
 		s.logger.Info("scheduled pipeline", "pipeline", pipelineName, "schedule", schedule)
 	}
 
+	s.loadJobs(ctx)
 	return nil
 }
 
+// loadJobs adds the jobs of every registered source to cron. A failing
+// source is logged and skipped so it cannot stop pipelines from running.
+func (s *Scheduler) loadJobs(ctx context.Context) {
+	for _, src := range s.sources {
+		jobs, err := src.ScheduledJobs(ctx)
+		if err != nil {
+			s.logger.Warn("list scheduled jobs failed", "error", err)
+			continue
+		}
+		for _, job := range jobs {
+			entryID, err := s.cron.AddFunc(job.Schedule, func() {
+				if runErr := job.Run(context.Background()); runErr != nil {
+					s.logger.Warn("scheduled job failed", "job", job.Name, "error", runErr)
+				}
+			})
+			if err != nil {
+				s.logger.Warn("invalid cron schedule", "job", job.Name, "schedule", job.Schedule, "error", err)
+				continue
+			}
+			s.entries["job:"+job.Name] = entryID
+			s.logger.Info("scheduled job", "job", job.Name, "schedule", job.Schedule)
+		}
+	}
+}
+
 // Compile-time check that Scheduler implements ScheduleReloader.
 var _ ScheduleReloader = (*Scheduler)(nil)
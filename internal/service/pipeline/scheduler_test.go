@@ -200,3 +200,39 @@ func TestScheduler_PipelineWithoutScheduleCron(t *testing.T) {
 	_, hasNoCron := scheduler.entries["no-cron"]
 	assert.False(t, hasNoCron, "pipeline without cron should be skipped")
 }
+
+type stubJobSource struct {
+	jobs []domain.ScheduledJob
+	err  error
+}
+
+func (s stubJobSource) ScheduledJobs(_ context.Context) ([]domain.ScheduledJob, error) {
+	return s.jobs, s.err
+}
+
+func TestScheduler_JobSources(t *testing.T) {
+	t.Parallel()
+
+	cron5min := "*/5 * * * *"
+	repo := &testutil.MockPipelineRepo{
+		ListScheduledPipelinesFn: func(_ context.Context) ([]domain.Pipeline, error) {
+			return []domain.Pipeline{{ID: "p1", Name: "etl", ScheduleCron: &cron5min, CreatedBy: "alice"}}, nil
+		},
+	}
+	noop := func(context.Context) error { return nil }
+
+	scheduler := NewScheduler(nil, repo, discardLogger())
+	scheduler.AddJobSource(stubJobSource{jobs: []domain.ScheduledJob{
+		{Name: "refresh view lake.daily", Schedule: "0 * * * *", Run: noop},
+		{Name: "broken", Schedule: "not a cron", Run: noop},
+	}})
+	scheduler.AddJobSource(stubJobSource{err: fmt.Errorf("views unavailable")})
+	t.Cleanup(func() { scheduler.Stop() })
+
+	require.NoError(t, scheduler.Start(context.Background()), "a failing job source must not stop pipelines")
+	assert.Len(t, scheduler.entries, 2)
+	_, hasPipeline := scheduler.entries["p1"]
+	assert.True(t, hasPipeline)
+	_, hasJob := scheduler.entries["job:refresh view lake.daily"]
+	assert.True(t, hasJob)
+}
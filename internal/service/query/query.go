@@ -34,12 +34,22 @@ type QueryService struct {
 	defaultSchema string
 	jobRepo       domain.QueryJobRepository
 	jobCancels    sync.Map
+	jobTTL        time.Duration
 	asyncEnabled  bool
+	cursors       map[string]*queryCursor
+	cursorsMu     sync.Mutex
+	cursorTTL     time.Duration
+	metrics       Metrics
+}
+
+// Metrics records query execution telemetry.
+type Metrics interface {
+	ObserveQuery(status string, d time.Duration)
 }
 
 // NewQueryService creates a new QueryService.
 func NewQueryService(eng domain.QueryEngine, audit domain.AuditRepository, lineage domain.LineageRepository) *QueryService {
-	return &QueryService{engine: eng, audit: audit, lineage: lineage, defaultSchema: "main", asyncEnabled: true}
+	return &QueryService{engine: eng, audit: audit, lineage: lineage, defaultSchema: "main", asyncEnabled: true, cursorTTL: defaultCursorTTL}
 }
 
 // SetColumnLineage configures column-level lineage capture.
@@ -54,11 +64,23 @@ func (s *QueryService) SetJobRepository(repo domain.QueryJobRepository) {
 	s.jobRepo = repo
 }
 
+// SetJobRetention sets how long finished async query jobs are kept. Zero
+// keeps them until they are deleted.
+func (s *QueryService) SetJobRetention(ttl time.Duration) {
+	s.jobTTL = ttl
+}
+
 // SetAsyncEnabled toggles async query lifecycle endpoints.
 func (s *QueryService) SetAsyncEnabled(enabled bool) {
 	s.asyncEnabled = enabled
 }
 
+// SetMetrics configures query duration metrics.
+// This is optional — if not called, no metrics are recorded.
+func (s *QueryService) SetMetrics(m Metrics) {
+	s.metrics = m
+}
+
 // Execute runs a SQL query as the given principal and returns structured results.
 func (s *QueryService) Execute(ctx context.Context, principalName, sqlQuery string) (*QueryResult, error) {
 	if strings.TrimSpace(sqlQuery) == "" {
@@ -73,6 +95,7 @@ func (s *QueryService) Execute(ctx context.Context, principalName, sqlQuery stri
 	if err != nil {
 		// Log failed query
 		s.logAudit(ctx, principalName, "QUERY", &sqlQuery, nil, nil, "DENIED", err.Error(), duration, nil)
+		s.observe("error", start)
 		return nil, err
 	}
 	defer rows.Close() //nolint:errcheck
@@ -80,8 +103,10 @@ func (s *QueryService) Execute(ctx context.Context, principalName, sqlQuery stri
 	result, err := scanRows(rows)
 	if err != nil {
 		s.logAudit(ctx, principalName, "QUERY", &sqlQuery, nil, nil, "ERROR", err.Error(), duration, nil)
+		s.observe("error", start)
 		return nil, fmt.Errorf("scan results: %w", err)
 	}
+	s.observe("ok", start)
 
 	rowCount := int64(result.RowCount)
 	s.logAudit(ctx, principalName, "QUERY", &sqlQuery, nil, nil, "ALLOWED", "", duration, &rowCount)
@@ -92,6 +117,13 @@ func (s *QueryService) Execute(ctx context.Context, principalName, sqlQuery stri
 	return result, nil
 }
 
+// observe records the query duration since start when metrics are configured.
+func (s *QueryService) observe(status string, start time.Time) {
+	if s.metrics != nil {
+		s.metrics.ObserveQuery(status, time.Since(start))
+	}
+}
+
 // emitLineage extracts table names and target table from the SQL to record lineage edges.
 func (s *QueryService) emitLineage(ctx context.Context, principalName, sqlQuery string) {
 	if s.lineage == nil {
@@ -194,23 +226,10 @@ func scanRows(rows *sql.Rows) (*QueryResult, error) {
 
 	var resultRows [][]interface{}
 	for rows.Next() {
-		vals := make([]interface{}, len(cols))
-		ptrs := make([]interface{}, len(cols))
-		for i := range vals {
-			ptrs[i] = &vals[i]
-		}
-		if err := rows.Scan(ptrs...); err != nil {
+		row, err := scanRow(rows, len(cols))
+		if err != nil {
 			return nil, err
 		}
-		// Convert byte slices to strings for JSON serialization
-		row := make([]interface{}, len(vals))
-		for i, v := range vals {
-			if b, ok := v.([]byte); ok {
-				row[i] = string(b)
-			} else {
-				row[i] = v
-			}
-		}
 		resultRows = append(resultRows, row)
 	}
 	if err := rows.Err(); err != nil {
@@ -224,6 +243,28 @@ func scanRows(rows *sql.Rows) (*QueryResult, error) {
 	}, nil
 }
 
+// scanRow scans the current row of rows into a JSON-friendly slice.
+func scanRow(rows *sql.Rows, ncols int) ([]interface{}, error) {
+	vals := make([]interface{}, ncols)
+	ptrs := make([]interface{}, ncols)
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	// Convert byte slices to strings for JSON serialization
+	row := make([]interface{}, ncols)
+	for i, v := range vals {
+		if b, ok := v.([]byte); ok {
+			row[i] = string(b)
+		} else {
+			row[i] = v
+		}
+	}
+	return row, nil
+}
+
 func (s *QueryService) logAudit(ctx context.Context, principal, action string, originalSQL, rewrittenSQL *string, tables []string, status, errMsg string, durationMs int64, rowsReturned *int64) {
 	entry := &domain.AuditEntry{
 		PrincipalName:  principal,
@@ -68,12 +68,43 @@ func (s *QueryService) GetAsyncJob(ctx context.Context, principalName, jobID str
 	if err != nil {
 		return nil, err
 	}
-	if job.PrincipalName != principalName {
+	if job.PrincipalName != principalName || s.jobExpired(job, time.Now()) {
 		return nil, domain.ErrNotFound("query job %q not found", jobID)
 	}
 	return job, nil
 }
 
+// jobExpired reports whether a finished job has outlived the retention TTL.
+// Expired jobs are hidden before the reaper gets to delete them.
+func (s *QueryService) jobExpired(job *domain.QueryJob, now time.Time) bool {
+	return s.jobTTL > 0 && job.CompletedAt != nil && now.Sub(*job.CompletedAt) > s.jobTTL
+}
+
+// PurgeExpiredJobs deletes finished jobs older than the retention TTL and
+// returns how many were removed.
+func (s *QueryService) PurgeExpiredJobs(ctx context.Context) (int64, error) {
+	if s.jobRepo == nil || s.jobTTL <= 0 {
+		return 0, nil
+	}
+	return s.jobRepo.DeleteCompletedBefore(ctx, time.Now().Add(-s.jobTTL))
+}
+
+// ReapExpiredJobs purges expired jobs every interval until ctx is cancelled.
+// Should be called in a background goroutine.
+func (s *QueryService) ReapExpiredJobs(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.PurgeExpiredJobs(ctx)
+		}
+	}
+}
+
 // CancelAsyncJob cancels a queued or running query job.
 func (s *QueryService) CancelAsyncJob(ctx context.Context, principalName, jobID string) error {
 	if !s.asyncEnabled {
@@ -59,15 +59,22 @@ func (r *memQueryJobRepo) GetByRequestID(_ context.Context, principalName, reque
 func (r *memQueryJobRepo) MarkRunning(_ context.Context, id string, attempt int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.jobs[id].Status = domain.QueryJobStatusRunning
-	r.jobs[id].AttemptCount = attempt
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil
+	}
+	job.Status = domain.QueryJobStatusRunning
+	job.AttemptCount = attempt
 	return nil
 }
 
 func (r *memQueryJobRepo) MarkRetrying(_ context.Context, id string, attempt int, nextRetryAt time.Time, message string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	job := r.jobs[id]
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil
+	}
 	job.Status = domain.QueryJobStatusQueued
 	job.AttemptCount = attempt
 	job.NextRetryAt = &nextRetryAt
@@ -78,7 +85,10 @@ func (r *memQueryJobRepo) MarkRetrying(_ context.Context, id string, attempt int
 func (r *memQueryJobRepo) Heartbeat(_ context.Context, id string, at time.Time) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	job := r.jobs[id]
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil
+	}
 	job.LastHeartbeat = &at
 	return nil
 }
@@ -86,28 +96,43 @@ func (r *memQueryJobRepo) Heartbeat(_ context.Context, id string, at time.Time)
 func (r *memQueryJobRepo) MarkSucceeded(_ context.Context, id string, columns []string, rows [][]interface{}, rowCount int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	job := r.jobs[id]
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
 	job.Status = domain.QueryJobStatusSucceeded
 	job.Columns = columns
 	job.Rows = rows
 	job.RowCount = rowCount
+	job.CompletedAt = &now
 	return nil
 }
 
 func (r *memQueryJobRepo) MarkFailed(_ context.Context, id string, message string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	job := r.jobs[id]
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
 	job.Status = domain.QueryJobStatusFailed
 	job.ErrorMessage = &message
+	job.CompletedAt = &now
 	return nil
 }
 
 func (r *memQueryJobRepo) MarkCanceled(_ context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	job := r.jobs[id]
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
 	job.Status = domain.QueryJobStatusCanceled
+	job.CompletedAt = &now
 	return nil
 }
 
@@ -118,6 +143,19 @@ func (r *memQueryJobRepo) Delete(_ context.Context, id string) error {
 	return nil
 }
 
+func (r *memQueryJobRepo) DeleteCompletedBefore(_ context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, job := range r.jobs {
+		if job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
+			delete(r.jobs, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
 func TestQueryService_SubmitAsync_IdempotentByRequestID(t *testing.T) {
 	t.Parallel()
 
@@ -183,3 +221,111 @@ func TestQueryService_SubmitAsync_Disabled(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "disabled")
 }
+
+// waitForJobStatus polls until the job reaches want.
+func waitForJobStatus(t *testing.T, svc *QueryService, jobID string, want domain.QueryJobStatus) *domain.QueryJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		current, err := svc.GetAsyncJob(context.Background(), "alice", jobID)
+		require.NoError(t, err)
+		if current.Status == want {
+			return current
+		}
+		require.True(t, time.Now().Before(deadline), "job stayed %s, want %s", current.Status, want)
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestQueryService_AsyncJob_RunningThenSucceeded(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	release := make(chan struct{})
+	eng := &testutil.MockSessionEngine{QueryFn: func(ctx context.Context, _ string, q string) (*sql.Rows, error) {
+		<-release
+		return db.QueryContext(ctx, q)
+	}}
+	svc := NewQueryService(eng, &testutil.MockAuditRepo{}, nil)
+	svc.SetJobRepository(newMemQueryJobRepo())
+
+	job, err := svc.SubmitAsync(context.Background(), "alice", "SELECT 42 AS answer", "")
+	require.NoError(t, err)
+
+	running := waitForJobStatus(t, svc, job.ID, domain.QueryJobStatusRunning)
+	assert.Empty(t, running.Rows)
+	_, err = svc.GetAsyncJob(context.Background(), "bob", job.ID)
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound, "jobs are scoped to the submitting principal")
+
+	close(release)
+	done := waitForJobStatus(t, svc, job.ID, domain.QueryJobStatusSucceeded)
+	assert.Equal(t, []string{"answer"}, done.Columns)
+	assert.Equal(t, 1, done.RowCount)
+}
+
+func TestQueryService_DeleteAsyncJob_CancelsRunningQuery(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	eng := &testutil.MockSessionEngine{QueryFn: func(ctx context.Context, _ string, _ string) (*sql.Rows, error) {
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	}}
+	repo := newMemQueryJobRepo()
+	audit := &testutil.MockAuditRepo{}
+	svc := NewQueryService(eng, audit, nil)
+	svc.SetJobRepository(repo)
+
+	job, err := svc.SubmitAsync(context.Background(), "alice", "SELECT * FROM big_table", "")
+	require.NoError(t, err)
+	<-started
+
+	err = svc.DeleteAsyncJob(context.Background(), "bob", job.ID)
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound, "other principals cannot delete the job")
+
+	require.NoError(t, svc.DeleteAsyncJob(context.Background(), "alice", job.ID))
+	select {
+	case <-canceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("running query was not canceled")
+	}
+	_, err = svc.GetAsyncJob(context.Background(), "alice", job.ID)
+	require.ErrorAs(t, err, &notFound)
+	assert.True(t, audit.HasAction("QUERY_JOB_DELETE"))
+}
+
+func TestQueryService_JobRetention(t *testing.T) {
+	t.Parallel()
+
+	repo := newMemQueryJobRepo()
+	svc := NewQueryService(&testutil.MockSessionEngine{}, &testutil.MockAuditRepo{}, nil)
+	svc.SetJobRepository(repo)
+	svc.SetJobRetention(time.Hour)
+
+	old := time.Now().Add(-2 * time.Hour)
+	recent := time.Now().Add(-time.Minute)
+	repo.jobs["old"] = &domain.QueryJob{ID: "old", PrincipalName: "alice", Status: domain.QueryJobStatusSucceeded, CompletedAt: &old}
+	repo.jobs["recent"] = &domain.QueryJob{ID: "recent", PrincipalName: "alice", Status: domain.QueryJobStatusSucceeded, CompletedAt: &recent}
+	repo.jobs["running"] = &domain.QueryJob{ID: "running", PrincipalName: "alice", Status: domain.QueryJobStatusRunning}
+
+	_, err := svc.GetAsyncJob(context.Background(), "alice", "old")
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound, "expired jobs are hidden before they are purged")
+	_, err = svc.GetAsyncJob(context.Background(), "alice", "recent")
+	require.NoError(t, err)
+
+	n, err := svc.PurgeExpiredJobs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.NotContains(t, repo.jobs, "old")
+	assert.Contains(t, repo.jobs, "recent")
+	assert.Contains(t, repo.jobs, "running")
+}
@@ -0,0 +1,214 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"duck-demo/internal/domain"
+)
+
+const (
+	// defaultCursorTTL is how long an idle result cursor is kept open.
+	defaultCursorTTL = 5 * time.Minute
+	// maxOpenCursors bounds the connections pinned by unfinished result sets.
+	maxOpenCursors = 64
+)
+
+// queryCursor is an open result set held between page requests. The open
+// *sql.Rows pins its connection until the last page is read or the cursor
+// expires.
+type queryCursor struct {
+	mu        sync.Mutex
+	principal string
+	pageSize  int
+	rows      *sql.Rows
+	columns   []string
+	pending   []interface{} // row read ahead to detect the end of the result
+	cancel    context.CancelFunc
+	expiry    *time.Timer
+	closed    bool
+}
+
+// close releases the result set. Callers hold c.mu once the cursor is shared.
+func (c *queryCursor) close() {
+	if c.expiry != nil {
+		c.expiry.Stop()
+	}
+	_ = c.rows.Close()
+	c.cancel()
+	c.closed = true
+}
+
+// SetCursorTTL sets how long an idle paged result stays open. Zero restores
+// the default.
+func (s *QueryService) SetCursorTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = defaultCursorTTL
+	}
+	s.cursorTTL = ttl
+}
+
+// ExecutePage runs a SQL query like Execute but returns at most maxRows rows.
+// When more rows remain, the result set is held open on the server and the
+// returned token fetches the next page through FetchPage. The token is empty
+// on the last page.
+func (s *QueryService) ExecutePage(ctx context.Context, principalName, sqlQuery string, maxRows int) (*QueryResult, string, error) {
+	if maxRows <= 0 {
+		return nil, "", domain.ErrValidation("max_rows must be positive")
+	}
+	if strings.TrimSpace(sqlQuery) == "" {
+		return nil, "", domain.ErrValidation("sql query is required")
+	}
+
+	start := time.Now()
+
+	// The rows outlive this request, so they must not be tied to its context.
+	cursorCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	rows, err := s.engine.Query(cursorCtx, principalName, sqlQuery)
+	duration := time.Since(start).Milliseconds()
+	if err != nil {
+		cancel()
+		s.logAudit(ctx, principalName, "QUERY", &sqlQuery, nil, nil, "DENIED", err.Error(), duration, nil)
+		s.observe("error", start)
+		return nil, "", err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		cancel()
+		s.logAudit(ctx, principalName, "QUERY", &sqlQuery, nil, nil, "ERROR", err.Error(), duration, nil)
+		s.observe("error", start)
+		return nil, "", fmt.Errorf("scan results: %w", err)
+	}
+
+	cursor := &queryCursor{
+		principal: principalName,
+		pageSize:  maxRows,
+		rows:      rows,
+		columns:   columns,
+		cancel:    cancel,
+	}
+	result, more, err := cursor.nextPage(maxRows)
+	if err != nil {
+		cursor.close()
+		s.logAudit(ctx, principalName, "QUERY", &sqlQuery, nil, nil, "ERROR", err.Error(), duration, nil)
+		s.observe("error", start)
+		return nil, "", fmt.Errorf("scan results: %w", err)
+	}
+	s.observe("ok", start)
+	s.logAudit(ctx, principalName, "QUERY", &sqlQuery, nil, nil, "ALLOWED", "", duration, nil)
+	s.emitLineage(ctx, principalName, sqlQuery)
+
+	if !more {
+		cursor.close()
+		return result, "", nil
+	}
+	token, err := s.storeCursor(cursor)
+	if err != nil {
+		cursor.close()
+		return nil, "", err
+	}
+	return result, token, nil
+}
+
+// FetchPage returns the next page of a result opened by ExecutePage. maxRows
+// of zero keeps the page size of the first request. Unknown, expired and
+// other principals' tokens are reported as not found.
+func (s *QueryService) FetchPage(_ context.Context, principalName, token string, maxRows int) (*QueryResult, string, error) {
+	s.cursorsMu.Lock()
+	cursor, ok := s.cursors[token]
+	s.cursorsMu.Unlock()
+	if !ok || cursor.principal != principalName {
+		return nil, "", domain.ErrNotFound("result token is unknown or expired")
+	}
+
+	cursor.mu.Lock()
+	defer cursor.mu.Unlock()
+	if cursor.closed {
+		return nil, "", domain.ErrNotFound("result token is unknown or expired")
+	}
+	if maxRows <= 0 {
+		maxRows = cursor.pageSize
+	}
+	result, more, err := cursor.nextPage(maxRows)
+	if err != nil || !more {
+		s.removeCursor(token)
+		cursor.close()
+		if err != nil {
+			return nil, "", fmt.Errorf("scan results: %w", err)
+		}
+		return result, "", nil
+	}
+	cursor.expiry.Reset(s.cursorTTL)
+	return result, token, nil
+}
+
+// nextPage reads up to n rows and reports whether more remain.
+func (c *queryCursor) nextPage(n int) (*QueryResult, bool, error) {
+	result := &QueryResult{Columns: c.columns, Rows: make([][]interface{}, 0, n)}
+	if c.pending != nil {
+		result.Rows = append(result.Rows, c.pending)
+		c.pending = nil
+	}
+	for len(result.Rows) < n && c.rows.Next() {
+		row, err := scanRow(c.rows, len(c.columns))
+		if err != nil {
+			return nil, false, err
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	more := false
+	if len(result.Rows) == n && c.rows.Next() {
+		row, err := scanRow(c.rows, len(c.columns))
+		if err != nil {
+			return nil, false, err
+		}
+		c.pending = row
+		more = true
+	}
+	if err := c.rows.Err(); err != nil {
+		return nil, false, err
+	}
+	result.RowCount = len(result.Rows)
+	return result, more, nil
+}
+
+func (s *QueryService) storeCursor(cursor *queryCursor) (string, error) {
+	s.cursorsMu.Lock()
+	defer s.cursorsMu.Unlock()
+	if len(s.cursors) >= maxOpenCursors {
+		return "", domain.ErrConflict("too many open paged results; read them to the end or wait for them to expire")
+	}
+	if s.cursors == nil {
+		s.cursors = make(map[string]*queryCursor)
+	}
+	token := uuid.NewString()
+	cursor.expiry = time.AfterFunc(s.cursorTTL, func() { s.expireCursor(token) })
+	s.cursors[token] = cursor
+	return token, nil
+}
+
+func (s *QueryService) removeCursor(token string) (*queryCursor, bool) {
+	s.cursorsMu.Lock()
+	defer s.cursorsMu.Unlock()
+	cursor, ok := s.cursors[token]
+	delete(s.cursors, token)
+	return cursor, ok
+}
+
+// expireCursor closes an idle cursor, waiting for a page read in progress.
+func (s *QueryService) expireCursor(token string) {
+	cursor, ok := s.removeCursor(token)
+	if !ok {
+		return
+	}
+	cursor.mu.Lock()
+	defer cursor.mu.Unlock()
+	cursor.close()
+}
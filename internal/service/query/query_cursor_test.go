@@ -0,0 +1,119 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/testutil"
+)
+
+func newPagingService(t *testing.T) *QueryService {
+	t.Helper()
+	db := openDuckDB(t)
+	eng := &testutil.MockSessionEngine{
+		QueryFn: func(ctx context.Context, _, q string) (*sql.Rows, error) {
+			return db.QueryContext(ctx, q)
+		},
+	}
+	return NewQueryService(eng, &testutil.MockAuditRepo{}, nil)
+}
+
+func TestQueryService_ExecutePage_MultiPage(t *testing.T) {
+	t.Parallel()
+
+	svc := newPagingService(t)
+	ctx := context.Background()
+
+	first, token, err := svc.ExecutePage(ctx, "alice", "SELECT i FROM generate_series(1, 25) AS t(i) ORDER BY i", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i"}, first.Columns)
+	assert.Equal(t, 10, first.RowCount)
+	require.NotEmpty(t, token)
+
+	_, _, err = svc.FetchPage(ctx, "bob", token, 0)
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound, "tokens are scoped to the principal")
+
+	var ids []int64
+	collect := func(r *QueryResult) {
+		for _, row := range r.Rows {
+			ids = append(ids, row[0].(int64))
+		}
+	}
+	collect(first)
+
+	second, next, err := svc.FetchPage(ctx, "alice", token, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 10, second.RowCount, "the first page size is kept")
+	assert.Equal(t, token, next)
+	collect(second)
+
+	last, next, err := svc.FetchPage(ctx, "alice", token, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 5, last.RowCount)
+	assert.Empty(t, next, "the last page has no token")
+	collect(last)
+
+	require.Len(t, ids, 25)
+	for i, id := range ids {
+		assert.Equal(t, int64(i+1), id)
+	}
+
+	_, _, err = svc.FetchPage(ctx, "alice", token, 0)
+	require.ErrorAs(t, err, &notFound, "the cursor is closed after the last page")
+}
+
+func TestQueryService_ExecutePage_ExactPageHasNoToken(t *testing.T) {
+	t.Parallel()
+
+	svc := newPagingService(t)
+	result, token, err := svc.ExecutePage(context.Background(), "alice", "SELECT i FROM generate_series(1, 10) AS t(i)", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.RowCount)
+	assert.Empty(t, token)
+}
+
+func TestQueryService_FetchPage_TokenExpiry(t *testing.T) {
+	t.Parallel()
+
+	svc := newPagingService(t)
+	svc.SetCursorTTL(50 * time.Millisecond)
+	ctx := context.Background()
+
+	_, token, err := svc.ExecutePage(ctx, "alice", "SELECT i FROM generate_series(1, 100) AS t(i)", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	// Fetching resets the idle timer.
+	time.Sleep(30 * time.Millisecond)
+	_, _, err = svc.FetchPage(ctx, "alice", token, 0)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		svc.cursorsMu.Lock()
+		defer svc.cursorsMu.Unlock()
+		return len(svc.cursors) == 0
+	}, 2*time.Second, 10*time.Millisecond, "idle cursor is closed")
+
+	_, _, err = svc.FetchPage(ctx, "alice", token, 0)
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
+
+func TestQueryService_ExecutePage_Validation(t *testing.T) {
+	t.Parallel()
+
+	svc := newPagingService(t)
+	_, _, err := svc.ExecutePage(context.Background(), "alice", "SELECT 1", 0)
+	var validation *domain.ValidationError
+	require.ErrorAs(t, err, &validation)
+
+	_, _, err = svc.ExecutePage(context.Background(), "alice", "  ", 10)
+	require.ErrorAs(t, err, &validation)
+}
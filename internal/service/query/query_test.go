@@ -252,6 +252,40 @@ func TestQueryService_Execute_MultipleRows(t *testing.T) {
 	assert.Equal(t, []string{"i"}, result.Columns)
 }
 
+// === Execute — metrics ===
+
+type recordingMetrics struct {
+	statuses []string
+}
+
+func (m *recordingMetrics) ObserveQuery(status string, _ time.Duration) {
+	m.statuses = append(m.statuses, status)
+}
+
+func TestQueryService_Execute_RecordsMetrics(t *testing.T) {
+	t.Parallel()
+
+	db := openDuckDB(t)
+	eng := &testutil.MockSessionEngine{
+		QueryFn: func(ctx context.Context, _, q string) (*sql.Rows, error) {
+			if q == "denied" {
+				return nil, domain.ErrAccessDenied("no SELECT on secret")
+			}
+			return db.QueryContext(ctx, q)
+		},
+	}
+	metrics := &recordingMetrics{}
+	svc := NewQueryService(eng, &testutil.MockAuditRepo{}, nil)
+	svc.SetMetrics(metrics)
+
+	_, err := svc.Execute(context.Background(), "alice", "SELECT 1")
+	require.NoError(t, err)
+	_, err = svc.Execute(context.Background(), "alice", "denied")
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"ok", "error"}, metrics.statuses)
+}
+
 // === Execute — DML lineage emits WRITE edge ===
 
 func TestQueryService_Execute_DMLLineage(t *testing.T) {
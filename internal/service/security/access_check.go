@@ -0,0 +1,145 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"duck-demo/internal/domain"
+)
+
+// authzTraceKey carries an *authzTrace through a privilege evaluation.
+type authzTraceKey struct{}
+
+// authzTrace records how a privilege evaluation reached its decision.
+type authzTrace struct {
+	match  *domain.AccessGrantPath // last grant found; PrincipalName holds the ID until resolved
+	denial string
+}
+
+// traceGrant records a matching grant when the evaluation is being traced.
+func traceGrant(ctx context.Context, principalType, principalID, securableType, securableID, privilege string) {
+	if t, ok := ctx.Value(authzTraceKey{}).(*authzTrace); ok {
+		t.match = &domain.AccessGrantPath{
+			PrincipalType: principalType,
+			PrincipalName: principalID,
+			SecurableType: securableType,
+			SecurableID:   securableID,
+			Privilege:     privilege,
+		}
+	}
+}
+
+// traceDenial records why an evaluation stopped before checking grants.
+func traceDenial(ctx context.Context, reason string) {
+	if t, ok := ctx.Value(authzTraceKey{}).(*authzTrace); ok {
+		t.denial = reason
+	}
+}
+
+// ExplainPrivilege evaluates whether a principal holds a privilege on a
+// securable and reports which grant allowed it or why it was denied. It
+// bypasses the privilege cache and has no side effects. Admins may check any
+// principal; other callers may only check themselves.
+func (s *AuthorizationService) ExplainPrivilege(ctx context.Context, req domain.AccessCheckRequest) (*domain.AccessDecision, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Principal != callerName(ctx) {
+		if err := requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	principal, err := s.principals.GetByName(ctx, req.Principal)
+	if err != nil {
+		return nil, domain.ErrNotFound("principal %q not found", req.Principal)
+	}
+	securableType, securableID, err := s.resolveSecurable(ctx, req.Securable)
+	if err != nil {
+		return nil, err
+	}
+	privilege := strings.ToUpper(req.Privilege)
+
+	decision := &domain.AccessDecision{
+		Principal:     principal.Name,
+		Privilege:     privilege,
+		SecurableType: securableType,
+		SecurableID:   securableID,
+	}
+	if principal.IsAdmin {
+		decision.Allowed = true
+		decision.Reason = fmt.Sprintf("%s is an admin", principal.Name)
+		return decision, nil
+	}
+
+	groupIDs, err := s.resolveGroupIDs(ctx, principal.ID)
+	if err != nil {
+		return nil, err
+	}
+	trace := &authzTrace{}
+	allowed, err := s.checkPrivilegeForIdentities(context.WithValue(ctx, authzTraceKey{}, trace),
+		principal.ID, groupIDs, securableType, securableID, privilege)
+	if err != nil {
+		return nil, err
+	}
+
+	decision.Allowed = allowed
+	if !allowed {
+		decision.Reason = trace.denial
+		if decision.Reason == "" {
+			decision.Reason = fmt.Sprintf("no %s grant on the %s or its parents for %s or its groups",
+				privilege, securableType, principal.Name)
+		}
+		return decision, nil
+	}
+
+	via := trace.match
+	if via.PrincipalType == "group" {
+		if g, gErr := s.groups.GetByID(ctx, via.PrincipalName); gErr == nil {
+			via.PrincipalName = g.Name
+		}
+	} else {
+		via.PrincipalName = principal.Name
+	}
+	decision.Via = via
+	decision.Reason = fmt.Sprintf("%s granted to %s %s on %s", via.Privilege, via.PrincipalType, via.PrincipalName, via.SecurableType)
+	if via.Privilege != privilege {
+		decision.Reason += fmt.Sprintf(" (implies %s)", privilege)
+	}
+	return decision, nil
+}
+
+// resolveSecurable turns a "<type>:<name>" reference into the securable type
+// and ID that grants are recorded against. Views are checked as tables.
+func (s *AuthorizationService) resolveSecurable(ctx context.Context, ref string) (securableType, securableID string, err error) {
+	typ, name, _ := strings.Cut(ref, ":")
+	switch strings.ToLower(typ) {
+	case domain.SecurableTable, "view":
+		tableID, _, _, lookupErr := s.LookupTableID(ctx, name)
+		if lookupErr != nil {
+			return "", "", domain.ErrNotFound("%s %q not found", typ, name)
+		}
+		return domain.SecurableTable, tableID, nil
+	case domain.SecurableSchema:
+		schemaName := name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			schemaName = name[i+1:]
+		}
+		schemaID, lookupErr := s.LookupSchemaID(ctx, schemaName)
+		if lookupErr != nil {
+			return "", "", domain.ErrNotFound("schema %q not found", name)
+		}
+		return domain.SecurableSchema, schemaID, nil
+	case domain.SecurableCatalog:
+		ids := s.catalogSecurableIDs(ctx, name)
+		if len(ids) == 0 {
+			return "", "", domain.ErrNotFound("catalog %q not found", name)
+		}
+		return domain.SecurableCatalog, ids[len(ids)-1], nil
+	case domain.SecurableExternalLocation, domain.SecurableStorageCredential, domain.SecurableVolume, domain.SecurableComputeEndpoint:
+		return strings.ToLower(typ), name, nil
+	default:
+		return "", "", domain.ErrValidation("unknown securable type %q", typ)
+	}
+}
@@ -0,0 +1,235 @@
+//go:build integration
+
+package security
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbstore "duck-demo/internal/db/dbstore"
+	"duck-demo/internal/domain"
+)
+
+func TestExplainPrivilege_GrantViaGroup(t *testing.T) {
+	svc, q, _ := setupTestService(t)
+
+	user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+		Name: "analyst", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+	group, err := q.CreateGroup(ctx, dbstore.CreateGroupParams{ID: uuid.New().String(), Name: "analysts"})
+	require.NoError(t, err)
+	require.NoError(t, q.AddGroupMember(ctx, dbstore.AddGroupMemberParams{
+		GroupID: group.ID, MemberType: "user", MemberID: user.ID,
+	}))
+	for _, g := range []dbstore.GrantPrivilegeParams{
+		{SecurableType: SecurableSchema, SecurableID: "0", Privilege: PrivUsage},
+		{SecurableType: SecurableTable, SecurableID: "1", Privilege: PrivSelect},
+	} {
+		g.ID = uuid.New().String()
+		g.PrincipalID = group.ID
+		g.PrincipalType = "group"
+		_, err = q.GrantPrivilege(ctx, g)
+		require.NoError(t, err)
+	}
+
+	decision, err := svc.ExplainPrivilege(adminCtx(), domain.AccessCheckRequest{
+		Principal: "analyst", Privilege: "select", Securable: "table:main.titanic",
+	})
+	require.NoError(t, err)
+
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, PrivSelect, decision.Privilege)
+	assert.Equal(t, SecurableTable, decision.SecurableType)
+	assert.Equal(t, "1", decision.SecurableID)
+	require.NotNil(t, decision.Via)
+	assert.Equal(t, domain.AccessGrantPath{
+		PrincipalType: "group", PrincipalName: "analysts",
+		SecurableType: SecurableTable, SecurableID: "1", Privilege: PrivSelect,
+	}, *decision.Via)
+	assert.Equal(t, "SELECT granted to group analysts on table", decision.Reason)
+}
+
+func TestExplainPrivilege_AllPrivilegesCascadeFromCatalog(t *testing.T) {
+	svc, q, _ := setupTestService(t)
+
+	user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+		Name: "superuser", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+	_, err = q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+		ID: uuid.New().String(), PrincipalID: user.ID, PrincipalType: "user",
+		SecurableType: SecurableCatalog, SecurableID: CatalogID,
+		Privilege: PrivAllPrivileges,
+	})
+	require.NoError(t, err)
+
+	decision, err := svc.ExplainPrivilege(adminCtx(), domain.AccessCheckRequest{
+		Principal: "superuser", Privilege: PrivSelect, Securable: "table:main.orders",
+	})
+	require.NoError(t, err)
+
+	assert.True(t, decision.Allowed)
+	require.NotNil(t, decision.Via)
+	assert.Equal(t, "user", decision.Via.PrincipalType)
+	assert.Equal(t, "superuser", decision.Via.PrincipalName)
+	assert.Equal(t, SecurableCatalog, decision.Via.SecurableType)
+	assert.Equal(t, PrivAllPrivileges, decision.Via.Privilege)
+	assert.Equal(t, "ALL_PRIVILEGES granted to user superuser on catalog (implies SELECT)", decision.Reason)
+}
+
+func TestExplainPrivilege_Denied(t *testing.T) {
+	svc, q, _ := setupTestService(t)
+
+	user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+		Name: "intern", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+	_, err = q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+		ID: uuid.New().String(), PrincipalID: user.ID, PrincipalType: "user",
+		SecurableType: SecurableTable, SecurableID: "1",
+		Privilege: PrivSelect,
+	})
+	require.NoError(t, err)
+
+	t.Run("missing_schema_gate", func(t *testing.T) {
+		decision, err := svc.ExplainPrivilege(adminCtx(), domain.AccessCheckRequest{
+			Principal: "intern", Privilege: PrivSelect, Securable: "table:main.titanic",
+		})
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Nil(t, decision.Via)
+		assert.Equal(t, "missing USE_SCHEMA on the parent schema", decision.Reason)
+	})
+
+	t.Run("no_grant", func(t *testing.T) {
+		_, err := q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+			ID: uuid.New().String(), PrincipalID: user.ID, PrincipalType: "user",
+			SecurableType: SecurableSchema, SecurableID: "0",
+			Privilege: PrivUsage,
+		})
+		require.NoError(t, err)
+
+		decision, err := svc.ExplainPrivilege(adminCtx(), domain.AccessCheckRequest{
+			Principal: "intern", Privilege: PrivInsert, Securable: "table:main.titanic",
+		})
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Nil(t, decision.Via)
+		assert.Equal(t, "no INSERT grant on the table or its parents for intern or its groups", decision.Reason)
+	})
+}
+
+func TestExplainPrivilege_CallerRestrictions(t *testing.T) {
+	svc, q, _ := setupTestService(t)
+
+	for _, name := range []string{"alice", "bob"} {
+		_, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+			Name: name, Type: "user", IsAdmin: 0,
+		})
+		require.NoError(t, err)
+	}
+	aliceCtx := principalCtx("alice-id", "alice", false)
+
+	_, err := svc.ExplainPrivilege(aliceCtx, domain.AccessCheckRequest{
+		Principal: "alice", Privilege: PrivSelect, Securable: "catalog:demo",
+	})
+	require.NoError(t, err, "principals may check their own access")
+
+	_, err = svc.ExplainPrivilege(aliceCtx, domain.AccessCheckRequest{
+		Principal: "bob", Privilege: PrivSelect, Securable: "catalog:demo",
+	})
+	var denied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &denied)
+
+	_, err = svc.ExplainPrivilege(adminCtx(), domain.AccessCheckRequest{
+		Principal: "alice", Privilege: PrivSelect, Securable: "table:main.missing",
+	})
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+
+	_, err = svc.ExplainPrivilege(adminCtx(), domain.AccessCheckRequest{
+		Principal: "alice", Privilege: PrivSelect, Securable: "warehouse:x",
+	})
+	var invalid *domain.ValidationError
+	require.ErrorAs(t, err, &invalid)
+}
+
+func TestEffectiveAccess_GroupGrantAndCatalogCascade(t *testing.T) {
+	svc, q, _ := setupTestService(t)
+
+	newUser := func(name string, admin bool) string {
+		params := dbstore.CreatePrincipalParams{ID: uuid.New().String(), Name: name, Type: "user"}
+		if admin {
+			params.IsAdmin = 1
+		}
+		p, err := q.CreatePrincipal(ctx, params)
+		require.NoError(t, err)
+		return p.ID
+	}
+	grant := func(principalID, principalType, securableType, securableID, privilege string) {
+		_, err := q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+			ID: uuid.New().String(), PrincipalID: principalID, PrincipalType: principalType,
+			SecurableType: securableType, SecurableID: securableID, Privilege: privilege,
+		})
+		require.NoError(t, err)
+	}
+
+	analystID := newUser("analyst", false)
+	superuserID := newUser("superuser", false)
+	internID := newUser("intern", false)
+	newUser("root", true)
+
+	group, err := q.CreateGroup(ctx, dbstore.CreateGroupParams{ID: uuid.New().String(), Name: "analysts"})
+	require.NoError(t, err)
+	require.NoError(t, q.AddGroupMember(ctx, dbstore.AddGroupMemberParams{
+		GroupID: group.ID, MemberType: "user", MemberID: analystID,
+	}))
+	grant(group.ID, "group", SecurableSchema, "0", PrivUsage)
+	grant(group.ID, "group", SecurableTable, "1", PrivSelect)
+	grant(superuserID, "user", SecurableCatalog, CatalogID, PrivAllPrivileges)
+	// Without USE_SCHEMA the intern's grant cannot be used.
+	grant(internID, "user", SecurableTable, "1", PrivSelect)
+
+	filter, err := q.CreateRowFilter(ctx, dbstore.CreateRowFilterParams{
+		ID: uuid.New().String(), TableID: "1", FilterSql: `"Pclass" = 1`,
+	})
+	require.NoError(t, err)
+	require.NoError(t, q.BindRowFilter(ctx, dbstore.BindRowFilterParams{
+		ID: uuid.New().String(), RowFilterID: filter.ID, PrincipalID: group.ID, PrincipalType: "group",
+	}))
+
+	access, err := svc.EffectiveAccess(adminCtx(), "table", "main.titanic")
+	require.NoError(t, err)
+
+	assert.Equal(t, SecurableTable, access.SecurableType)
+	assert.Equal(t, "1", access.SecurableID)
+	assert.Equal(t, []domain.AccessEntry{
+		{PrincipalType: "group", PrincipalName: "analysts", Privilege: PrivSelect,
+			Source: domain.AccessSourceDirect, GrantedOnType: SecurableTable, GrantedOnID: "1"},
+		{PrincipalType: "user", PrincipalName: "analyst", Privilege: PrivSelect,
+			Source: domain.AccessSourceGroup, ViaGroup: "analysts", GrantedOnType: SecurableTable, GrantedOnID: "1"},
+		{PrincipalType: "user", PrincipalName: "root", Privilege: PrivAllPrivileges,
+			Source: domain.AccessSourceAdmin},
+		{PrincipalType: "user", PrincipalName: "superuser", Privilege: PrivAllPrivileges,
+			Source: domain.AccessSourceCascade, GrantedOnType: SecurableCatalog, GrantedOnID: CatalogID},
+	}, access.Entries)
+	assert.Equal(t, []domain.PrincipalPolicies{
+		{PrincipalName: "analyst", RowFilters: []string{`"Pclass" = 1`}},
+	}, access.Policies)
+
+	t.Run("by_id", func(t *testing.T) {
+		byID, err := svc.EffectiveAccess(adminCtx(), "table", "1")
+		require.NoError(t, err)
+		assert.Equal(t, access.Entries, byID.Entries)
+	})
+
+	t.Run("admin_only", func(t *testing.T) {
+		_, err := svc.EffectiveAccess(nonAdminCtx(), "table", "main.titanic")
+		var denied *domain.AccessDeniedError
+		require.ErrorAs(t, err, &denied)
+	})
+}
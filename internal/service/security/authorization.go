@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"duck-demo/internal/domain"
+	"duck-demo/internal/sqlrewrite"
 )
 
 const syntheticViewIDPrefix = "__view__:"
@@ -15,18 +17,36 @@ const syntheticViewIDPrefix = "__view__:"
 // AuthorizationService provides permission checking using domain repository interfaces.
 // It implements the domain.AuthorizationService interface.
 type AuthorizationService struct {
-	principals         domain.PrincipalRepository
-	groups             domain.GroupRepository
-	grants             domain.GrantRepository
-	rowFilters         domain.RowFilterRepository
-	columnMasks        domain.ColumnMaskRepository
-	introspection      domain.IntrospectionRepository
-	extTableRepo       domain.ExternalTableRepository
-	viewRepo           domain.ViewRepository
-	lookupCatalogTable func(ctx context.Context, catalogName, schemaName, tableName string) (*domain.TableDetail, error)
-	lookupCatalogView  func(ctx context.Context, catalogName, schemaName, viewName string) (*domain.ViewDetail, error)
-	cacheMu            sync.RWMutex
-	privilegeCache     map[string]bool
+	principals           domain.PrincipalRepository
+	groups               domain.GroupRepository
+	grants               domain.GrantRepository
+	rowFilters           domain.RowFilterRepository
+	columnMasks          domain.ColumnMaskRepository
+	introspection        domain.IntrospectionRepository
+	extTableRepo         domain.ExternalTableRepository
+	viewRepo             domain.ViewRepository
+	catalogRegs          domain.CatalogRegistrationRepository
+	principalAttrs       domain.PrincipalAttributeRepository
+	lookupCatalogTable   func(ctx context.Context, catalogName, schemaName, tableName string) (*domain.TableDetail, error)
+	lookupCatalogView    func(ctx context.Context, catalogName, schemaName, viewName string) (*domain.ViewDetail, error)
+	defaultCatalog       func(ctx context.Context) (string, error)
+	catalogIntrospection func(ctx context.Context, catalogName string) (domain.IntrospectionRepository, error)
+	volumes              domain.VolumeRepository
+	cacheMu              sync.RWMutex
+	privilegeCache       map[string]privilegeDecision
+}
+
+// privilegeDecision is a memoized CheckPrivilege result. An allow backed by
+// time-bounded grants is only valid until expiresAt; a zero expiresAt never
+// lapses.
+type privilegeDecision struct {
+	allowed   bool
+	expiresAt time.Time
+}
+
+// valid reports whether the decision may still be served at now.
+func (d privilegeDecision) valid(now time.Time) bool {
+	return d.expiresAt.IsZero() || now.Before(d.expiresAt)
 }
 
 // NewAuthorizationService creates a new AuthorizationService backed by domain repositories.
@@ -47,7 +67,7 @@ func NewAuthorizationService(
 		columnMasks:    columnMasks,
 		introspection:  introspection,
 		extTableRepo:   extTableRepo,
-		privilegeCache: make(map[string]bool),
+		privilegeCache: make(map[string]privilegeDecision),
 	}
 }
 
@@ -55,7 +75,7 @@ func NewAuthorizationService(
 func (s *AuthorizationService) InvalidatePrivilegeCache() {
 	s.cacheMu.Lock()
 	defer s.cacheMu.Unlock()
-	s.privilegeCache = make(map[string]bool)
+	s.privilegeCache = make(map[string]privilegeDecision)
 }
 
 // SetCatalogTableLookup configures catalog-aware table lookup for three-part
@@ -70,17 +90,36 @@ func (s *AuthorizationService) SetCatalogViewLookup(lookup func(ctx context.Cont
 	s.lookupCatalogView = lookup
 }
 
+// SetDefaultCatalogLookup configures how unqualified table references
+// (table or schema.table) resolve to a registered catalog. When set, the
+// default catalog is consulted before the legacy single-metastore lookup.
+func (s *AuthorizationService) SetDefaultCatalogLookup(lookup func(ctx context.Context) (string, error)) {
+	s.defaultCatalog = lookup
+}
+
 // SetViewRepository configures direct view lookup support.
 func (s *AuthorizationService) SetViewRepository(repo domain.ViewRepository) {
 	s.viewRepo = repo
 }
 
+// SetPrincipalAttributeRepository configures the attribute store that
+// current_principal_attr calls in row filters resolve against. Without it,
+// every attribute resolves to NULL.
+func (s *AuthorizationService) SetPrincipalAttributeRepository(repo domain.PrincipalAttributeRepository) {
+	s.principalAttrs = repo
+}
+
 // resolveGroupIDs returns the set of group IDs a principal belongs to,
 // including nested groups (transitive closure).
 func (s *AuthorizationService) resolveGroupIDs(ctx context.Context, principalID string) ([]string, error) {
+	return s.resolveMemberGroupIDs(ctx, "user", principalID)
+}
+
+// resolveMemberGroupIDs returns the groups a user or group is a member of,
+// directly or through nested groups.
+func (s *AuthorizationService) resolveMemberGroupIDs(ctx context.Context, memberType, memberID string) ([]string, error) {
 	visited := map[string]bool{}
-	queue := []string{principalID}
-	memberType := "user"
+	queue := []string{memberID}
 
 	for len(queue) > 0 {
 		current := queue[0]
@@ -112,27 +151,25 @@ func (s *AuthorizationService) resolveGroupIDs(ctx context.Context, principalID
 func (s *AuthorizationService) LookupTableID(ctx context.Context, tableName string) (tableID, schemaID string, isExternal bool, err error) {
 	catalogName, schemaName, bareTableName := splitTableReference(tableName)
 
-	if catalogName != "" && schemaName != "" && s.lookupCatalogTable != nil {
-		tbl, lookupErr := s.lookupCatalogTable(ctx, catalogName, schemaName, bareTableName)
-		if lookupErr == nil {
-			return tbl.TableID, "", strings.EqualFold(tbl.TableType, domain.TableTypeExternal), nil
-		}
-
-		var notFoundErr *domain.NotFoundError
-		if !errors.As(lookupErr, &notFoundErr) {
-			return "", "", false, fmt.Errorf("lookup table %q in catalog %q: %w", bareTableName, catalogName, lookupErr)
+	if catalogName != "" && schemaName != "" {
+		id, sid, ext, found, lookupErr := s.lookupInCatalog(ctx, catalogName, schemaName, bareTableName)
+		if lookupErr != nil || found {
+			return id, sid, ext, lookupErr
 		}
 	}
 
-	if catalogName != "" && schemaName != "" && s.lookupCatalogView != nil {
-		view, lookupErr := s.lookupCatalogView(ctx, catalogName, schemaName, bareTableName)
-		if lookupErr == nil {
-			return resolvedViewIdentity(view, bareTableName)
-		}
-
-		var notFoundErr *domain.NotFoundError
-		if !errors.As(lookupErr, &notFoundErr) {
-			return "", "", false, fmt.Errorf("lookup view %q in catalog %q: %w", bareTableName, catalogName, lookupErr)
+	// Unqualified references resolve against the default catalog first so
+	// queries land on the same catalog DuckDB executes them against.
+	if catalogName == "" {
+		if defaultCatalog := s.resolveDefaultCatalog(ctx); defaultCatalog != "" {
+			lookupSchema := schemaName
+			if lookupSchema == "" {
+				lookupSchema = "main"
+			}
+			id, sid, ext, found, lookupErr := s.lookupInCatalog(ctx, defaultCatalog, lookupSchema, bareTableName)
+			if lookupErr != nil || found {
+				return id, sid, ext, lookupErr
+			}
 		}
 	}
 
@@ -202,6 +239,63 @@ func (s *AuthorizationService) LookupTableID(ctx context.Context, tableName stri
 	return "", "", false, fmt.Errorf("table %q not found in catalog", bareTableName)
 }
 
+// lookupInCatalog resolves a table or view inside a specific registered
+// catalog. found is false when neither lookup knows the object, so callers
+// can continue with their fallback resolution. DuckLake IDs of objects in a
+// non-default catalog are returned catalog-scoped; external tables and
+// views have IDs that are unique on their own.
+func (s *AuthorizationService) lookupInCatalog(ctx context.Context, catalogName, schemaName, objectName string) (tableID, schemaID string, isExternal, found bool, err error) {
+	if s.lookupCatalogTable != nil {
+		tbl, lookupErr := s.lookupCatalogTable(ctx, catalogName, schemaName, objectName)
+		if lookupErr == nil {
+			if strings.EqualFold(tbl.TableType, domain.TableTypeExternal) {
+				return tbl.TableID, "", true, true, nil
+			}
+			return scopeID(s.catalogScope(ctx, catalogName), tbl.TableID), "", false, true, nil
+		}
+
+		var notFoundErr *domain.NotFoundError
+		if !errors.As(lookupErr, &notFoundErr) {
+			return "", "", false, false, fmt.Errorf("lookup table %q in catalog %q: %w", objectName, catalogName, lookupErr)
+		}
+	}
+
+	if s.lookupCatalogView != nil {
+		view, lookupErr := s.lookupCatalogView(ctx, catalogName, schemaName, objectName)
+		if lookupErr == nil {
+			tableID, schemaID, isExternal, err = resolvedViewIdentity(view, objectName)
+			if err != nil {
+				return "", "", false, false, err
+			}
+			scope := s.catalogScope(ctx, catalogName)
+			if strings.HasPrefix(tableID, syntheticViewIDPrefix) {
+				tableID = syntheticViewID(scopeID(scope, schemaID), objectName)
+			}
+			return tableID, scopeID(scope, schemaID), isExternal, true, nil
+		}
+
+		var notFoundErr *domain.NotFoundError
+		if !errors.As(lookupErr, &notFoundErr) {
+			return "", "", false, false, fmt.Errorf("lookup view %q in catalog %q: %w", objectName, catalogName, lookupErr)
+		}
+	}
+
+	return "", "", false, false, nil
+}
+
+// resolveDefaultCatalog returns the configured default catalog name, or an
+// empty string when no default is registered or the lookup is not wired.
+func (s *AuthorizationService) resolveDefaultCatalog(ctx context.Context) string {
+	if s.defaultCatalog == nil || s.lookupCatalogTable == nil {
+		return ""
+	}
+	name, err := s.defaultCatalog(ctx)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
 func (s *AuthorizationService) lookupViewBySchema(ctx context.Context, schemaName, viewName string) (*domain.ViewDetail, error) {
 	if s.viewRepo == nil {
 		return nil, domain.ErrNotFound("view %q not found in schema %q", viewName, schemaName)
@@ -295,33 +389,32 @@ func (s *AuthorizationService) LookupSchemaID(ctx context.Context, schemaName st
 	return sch.ID, nil
 }
 
-// hasGrant checks if any of the given identities has a specific grant.
+// hasGrant checks if any of the given identities holds the privilege, or a
+// privilege that implies it per the cascade matrix, directly on the securable.
 func (s *AuthorizationService) hasGrant(ctx context.Context, principalID string, groupIDs []string, securableType string, securableID string, privilege string) (bool, error) {
-	// Check direct user grant
-	ok, err := s.grants.HasPrivilege(ctx, principalID, "user", securableType, securableID, privilege)
-	if err != nil {
-		return false, err
-	}
-	if ok {
-		return true, nil
-	}
-
-	// Check group grants
-	for _, gid := range groupIDs {
-		ok, err := s.grants.HasPrivilege(ctx, gid, "group", securableType, securableID, privilege)
+	for _, candidate := range satisfyingPrivileges(privilege) {
+		// Check direct user grant
+		ok, err := s.grants.HasPrivilege(ctx, principalID, "user", securableType, securableID, candidate)
 		if err != nil {
 			return false, err
 		}
 		if ok {
+			traceGrant(ctx, "user", principalID, securableType, securableID, candidate)
 			return true, nil
 		}
-	}
 
-	// If we didn't find the specific privilege, also check for ALL_PRIVILEGES
-	if privilege != domain.PrivAllPrivileges {
-		return s.hasGrant(ctx, principalID, groupIDs, securableType, securableID, domain.PrivAllPrivileges)
+		// Check group grants
+		for _, gid := range groupIDs {
+			ok, err := s.grants.HasPrivilege(ctx, gid, "group", securableType, securableID, candidate)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				traceGrant(ctx, "group", gid, securableType, securableID, candidate)
+				return true, nil
+			}
+		}
 	}
-
 	return false, nil
 }
 
@@ -330,13 +423,13 @@ func (s *AuthorizationService) hasGrant(ctx context.Context, principalID string,
 //  1. Admin bypass
 //  2. USAGE gate on parent schema (for table-level checks)
 //  3. Walk up hierarchy: table -> schema -> catalog
-//  4. ALL_PRIVILEGES expansion
+//  4. Privilege implication per the cascade matrix (see impliedBy)
 func (s *AuthorizationService) CheckPrivilege(ctx context.Context, principalName string, securableType string, securableID string, privilege string) (bool, error) {
 	cacheKey := principalName + "|" + securableType + "|" + securableID + "|" + privilege
 	s.cacheMu.RLock()
-	if cached, ok := s.privilegeCache[cacheKey]; ok {
+	if cached, ok := s.privilegeCache[cacheKey]; ok && cached.valid(time.Now()) {
 		s.cacheMu.RUnlock()
-		return cached, nil
+		return cached.allowed, nil
 	}
 	s.cacheMu.RUnlock()
 
@@ -347,9 +440,7 @@ func (s *AuthorizationService) CheckPrivilege(ctx context.Context, principalName
 
 	// Admin bypass
 	if principal.IsAdmin {
-		s.cacheMu.Lock()
-		s.privilegeCache[cacheKey] = true
-		s.cacheMu.Unlock()
+		s.cacheDecision(cacheKey, privilegeDecision{allowed: true})
 		return true, nil
 	}
 
@@ -363,10 +454,29 @@ func (s *AuthorizationService) CheckPrivilege(ctx context.Context, principalName
 	if err != nil {
 		return false, err
 	}
+
+	// An allow may rest on a grant that expires, so it is cached no longer
+	// than the earliest expiry among the principal's grants. When that is
+	// unknown the decision is not cached at all.
+	decision := privilegeDecision{allowed: allowed}
+	if allowed {
+		next, err := s.grants.NextExpiry(ctx, principal.ID, groupIDs)
+		if err != nil {
+			return true, nil
+		}
+		if next != nil {
+			decision.expiresAt = *next
+		}
+	}
+	s.cacheDecision(cacheKey, decision)
+	return allowed, nil
+}
+
+// cacheDecision memoizes a CheckPrivilege result under key.
+func (s *AuthorizationService) cacheDecision(key string, d privilegeDecision) {
 	s.cacheMu.Lock()
-	s.privilegeCache[cacheKey] = allowed
+	s.privilegeCache[key] = d
 	s.cacheMu.Unlock()
-	return allowed, nil
 }
 
 func (s *AuthorizationService) checkPrivilegeForIdentities(ctx context.Context, principalID string, groupIDs []string, securableType string, securableID string, privilege string) (bool, error) {
@@ -376,7 +486,7 @@ func (s *AuthorizationService) checkPrivilegeForIdentities(ctx context.Context,
 	case domain.SecurableSchema:
 		return s.checkSchemaPrivilege(ctx, principalID, groupIDs, securableID, privilege)
 	case domain.SecurableCatalog:
-		return s.hasGrant(ctx, principalID, groupIDs, domain.SecurableCatalog, domain.CatalogID, privilege)
+		return s.hasCatalogGrant(ctx, principalID, groupIDs, securableID, privilege)
 	case domain.SecurableExternalLocation, domain.SecurableStorageCredential, domain.SecurableVolume, domain.SecurableComputeEndpoint:
 		return s.checkCatalogScopedPrivilege(ctx, principalID, groupIDs, securableType, securableID, privilege)
 	default:
@@ -385,47 +495,9 @@ func (s *AuthorizationService) checkPrivilegeForIdentities(ctx context.Context,
 }
 
 func (s *AuthorizationService) checkTablePrivilege(ctx context.Context, principalID string, groupIDs []string, tableID string, privilege string) (bool, error) {
-	var (
-		schemaID       string
-		schemaResolved bool
-	)
-
-	if parsedSchemaID, ok := schemaIDFromSyntheticViewID(tableID); ok {
-		schemaID = parsedSchemaID
-		schemaResolved = true
-	}
-
-	// Try managed table first.
-	if !schemaResolved {
-		table, err := s.introspection.GetTable(ctx, tableID)
-		if err == nil {
-			schemaID = table.SchemaID
-			schemaResolved = true
-		}
-	}
-
-	// Fall back to external table lookup.
-	if !schemaResolved && s.extTableRepo != nil {
-		et, extErr := s.extTableRepo.GetByID(ctx, tableID)
-		if extErr == nil {
-			sch, schErr := s.introspection.GetSchemaByName(ctx, et.SchemaName)
-			if schErr == nil {
-				schemaID = sch.ID
-				schemaResolved = true
-			}
-		}
-	}
-
-	// Fall back to view lookup by ID.
-	if !schemaResolved && s.viewRepo != nil {
-		view, viewErr := s.viewRepo.GetByID(ctx, tableID)
-		if viewErr == nil {
-			schemaID = view.SchemaID
-			schemaResolved = true
-		}
-	}
-
+	schemaID, schemaResolved := s.tableSchemaID(ctx, tableID)
 	if !schemaResolved {
+		traceDenial(ctx, "table not found")
 		return false, nil
 	}
 
@@ -435,12 +507,7 @@ func (s *AuthorizationService) checkTablePrivilege(ctx context.Context, principa
 		return false, err
 	}
 	if !hasUseSchema {
-		hasUseSchema, err = s.checkSchemaPrivilege(ctx, principalID, groupIDs, schemaID, "USAGE")
-		if err != nil {
-			return false, err
-		}
-	}
-	if !hasUseSchema {
+		traceDenial(ctx, "missing USE_SCHEMA on the parent schema")
 		return false, nil
 	}
 
@@ -450,16 +517,58 @@ func (s *AuthorizationService) checkTablePrivilege(ctx context.Context, principa
 		return ok, err
 	}
 
-	if schemaResolved {
-		// Inherit from schema
-		ok, err = s.hasGrant(ctx, principalID, groupIDs, domain.SecurableSchema, schemaID, privilege)
-		if err != nil || ok {
-			return ok, err
+	// Inherit from schema
+	ok, err = s.hasGrant(ctx, principalID, groupIDs, domain.SecurableSchema, schemaID, privilege)
+	if err != nil || ok {
+		return ok, err
+	}
+
+	// Inherit from the catalog that owns the table's schema
+	return s.hasCatalogGrant(ctx, principalID, groupIDs, catalogOfID(schemaID), privilege)
+}
+
+// tableSchemaID returns the schema of a managed table, external table or
+// view by its ID. ok is false when no such object exists. The schema ID is
+// catalog-scoped when the object lives in a non-default catalog.
+func (s *AuthorizationService) tableSchemaID(ctx context.Context, tableID string) (string, bool) {
+	if schemaID, ok := schemaIDFromSyntheticViewID(tableID); ok {
+		return schemaID, true
+	}
+
+	// A scoped ID names a managed table in another catalog's metastore.
+	if catalogID, localID, ok := splitScopedID(tableID); ok {
+		intro, err := s.introspectionFor(ctx, catalogID)
+		if err != nil {
+			return "", false
+		}
+		table, err := intro.GetTable(ctx, localID)
+		if err != nil {
+			return "", false
+		}
+		return domain.CatalogScopedID(catalogID, table.SchemaID), true
+	}
+
+	// Try managed table first.
+	if table, err := s.introspection.GetTable(ctx, tableID); err == nil {
+		return table.SchemaID, true
+	}
+
+	// Fall back to external table lookup.
+	if s.extTableRepo != nil {
+		if et, err := s.extTableRepo.GetByID(ctx, tableID); err == nil {
+			if schemaID, ok := s.schemaIDInCatalog(ctx, et.CatalogName, et.SchemaName); ok {
+				return schemaID, true
+			}
 		}
 	}
 
-	// Inherit from catalog
-	return s.hasGrant(ctx, principalID, groupIDs, domain.SecurableCatalog, domain.CatalogID, privilege)
+	// Fall back to view lookup by ID.
+	if s.viewRepo != nil {
+		if view, err := s.viewRepo.GetByID(ctx, tableID); err == nil {
+			return scopeID(s.catalogScope(ctx, view.CatalogName), view.SchemaID), true
+		}
+	}
+	return "", false
 }
 
 func resolvedViewIdentity(view *domain.ViewDetail, fallbackName string) (tableID, schemaID string, isExternal bool, err error) {
@@ -496,7 +605,7 @@ func (s *AuthorizationService) checkSchemaPrivilege(ctx context.Context, princip
 	if err != nil || ok {
 		return ok, err
 	}
-	return s.hasGrant(ctx, principalID, groupIDs, domain.SecurableCatalog, domain.CatalogID, privilege)
+	return s.hasCatalogGrant(ctx, principalID, groupIDs, catalogOfID(schemaID), privilege)
 }
 
 // checkCatalogScopedPrivilege checks a privilege on a catalog-scoped securable
@@ -507,12 +616,17 @@ func (s *AuthorizationService) checkCatalogScopedPrivilege(ctx context.Context,
 	if err != nil || ok {
 		return ok, err
 	}
-	// Inherit from catalog
-	return s.hasGrant(ctx, principalID, groupIDs, domain.SecurableCatalog, domain.CatalogID, privilege)
+	// Inherit from the catalog that owns the securable
+	return s.hasCatalogGrant(ctx, principalID, groupIDs, s.owningCatalog(ctx, securableType, securableID), privilege)
 }
 
 // GetEffectiveRowFilters returns all SQL filter expressions for a table that
 // apply to the principal (or any of their groups). Returns nil if no filters apply.
+//
+// Filters on the table itself and tag-based filters for tags assigned to the
+// table are combined: every filter applies, so the principal sees only rows
+// that pass all of them. A tag can therefore narrow a table's rows further
+// but never widen them.
 func (s *AuthorizationService) GetEffectiveRowFilters(ctx context.Context, principalName string, tableID string) ([]string, error) {
 	principal, err := s.principals.GetByName(ctx, principalName)
 	if err != nil {
@@ -561,11 +675,44 @@ func (s *AuthorizationService) GetEffectiveRowFilters(ctx context.Context, princ
 	if len(filters) == 0 {
 		return nil, nil
 	}
+	return s.resolvePrincipalAttrs(ctx, principal.ID, filters)
+}
+
+// resolvePrincipalAttrs substitutes the principal's attribute values into
+// filters that call current_principal_attr. Attributes are only loaded when
+// some filter needs them.
+func (s *AuthorizationService) resolvePrincipalAttrs(ctx context.Context, principalID string, filters []string) ([]string, error) {
+	var attrs map[string]string
+	loaded := false
+	for i, f := range filters {
+		if !sqlrewrite.ReferencesPrincipalAttrs(f) {
+			continue
+		}
+		if !loaded && s.principalAttrs != nil {
+			var err error
+			attrs, err = s.principalAttrs.Get(ctx, principalID)
+			if err != nil {
+				return nil, fmt.Errorf("load principal attributes: %w", err)
+			}
+		}
+		loaded = true
+		resolved, err := sqlrewrite.ResolvePrincipalAttrs(f, attrs)
+		if err != nil {
+			return nil, fmt.Errorf("resolve row filter: %w", err)
+		}
+		filters[i] = resolved
+	}
 	return filters, nil
 }
 
 // GetEffectiveColumnMasks returns a map of column_name -> mask_expression for
 // columns the principal should see masked on the given table.
+//
+// A column gets at most one mask. Masks defined on the column itself take
+// precedence over tag-based masks reaching it through a column tag, and among
+// tag-based masks the oldest wins. User bindings are considered before group
+// bindings, and a see_original user binding exempts the column from masks
+// bound to the principal's groups.
 func (s *AuthorizationService) GetEffectiveColumnMasks(ctx context.Context, principalName string, tableID string) (map[string]string, error) {
 	principal, err := s.principals.GetByName(ctx, principalName)
 	if err != nil {
@@ -587,6 +734,11 @@ func (s *AuthorizationService) GetEffectiveColumnMasks(ctx context.Context, prin
 	}
 	for _, m := range userMasks {
 		key := strings.ToLower(m.ColumnName)
+		// Masks on the column itself come before tag-based ones, so the
+		// first binding seen for a column decides it.
+		if _, masked := masks[key]; masked || exempted[key] {
+			continue
+		}
 		if m.SeeOriginal {
 			exempted[key] = true
 		} else {
@@ -627,7 +779,15 @@ func (s *AuthorizationService) GetEffectiveColumnMasks(ctx context.Context, prin
 // GetTableColumnNames returns the ordered list of column names for a table.
 // This is used by the engine to expand SELECT * before applying column masks.
 func (s *AuthorizationService) GetTableColumnNames(ctx context.Context, tableID string) ([]string, error) {
-	cols, _, err := s.introspection.ListColumns(ctx, tableID, domain.PageRequest{MaxResults: 10000})
+	intro, localID := s.introspection, tableID
+	if catalogID, id, ok := splitScopedID(tableID); ok {
+		var err error
+		if intro, err = s.introspectionFor(ctx, catalogID); err != nil {
+			return nil, fmt.Errorf("list columns for table %s: %w", tableID, err)
+		}
+		localID = id
+	}
+	cols, _, err := intro.ListColumns(ctx, localID, domain.PageRequest{MaxResults: 10000})
 	if err != nil {
 		return nil, fmt.Errorf("list columns for table %s: %w", tableID, err)
 	}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
@@ -98,6 +99,7 @@ func setupTestService(t *testing.T) (*AuthorizationService, *dbstore.Queries, co
 		nil,
 	)
 	svc.SetViewRepository(viewRepo)
+	svc.SetCatalogRegistrationRepository(repository.NewCatalogRegistrationRepo(db))
 
 	// Return dbstore.Queries for test data seeding
 	q := dbstore.New(db)
@@ -634,6 +636,95 @@ func TestLookupTableID_CatalogQualified(t *testing.T) {
 	assert.False(t, isExternal)
 }
 
+// multiCatalogLookup wires catalog-aware lookups backed by an in-memory
+// catalog -> schema -> table -> table ID map.
+func multiCatalogLookup(t *testing.T, svc *AuthorizationService, tables map[string]map[string]map[string]string, defaultCatalog string) {
+	t.Helper()
+
+	svc.SetCatalogTableLookup(func(_ context.Context, catalogName, schemaName, tableName string) (*domain.TableDetail, error) {
+		if id, ok := tables[catalogName][schemaName][tableName]; ok {
+			return &domain.TableDetail{TableID: id, Name: tableName, SchemaName: schemaName, CatalogName: catalogName, TableType: domain.TableTypeManaged}, nil
+		}
+		return nil, domain.ErrNotFound("table %q not found in %s.%s", tableName, catalogName, schemaName)
+	})
+	svc.SetDefaultCatalogLookup(func(_ context.Context) (string, error) {
+		if defaultCatalog == "" {
+			return "", domain.ErrNotFound("no default catalog")
+		}
+		return defaultCatalog, nil
+	})
+}
+
+func TestLookupTableID_MultiCatalog(t *testing.T) {
+	tables := map[string]map[string]map[string]string{
+		"sales":   {"main": {"orders": "sales-orders"}},
+		"finance": {"main": {"orders": "finance-orders"}, "ledger": {"entries": "finance-entries"}},
+	}
+
+	tests := []struct {
+		name           string
+		defaultCatalog string
+		ref            string
+		wantID         string
+	}{
+		{name: "qualified routes to first catalog", defaultCatalog: "sales", ref: "sales.main.orders", wantID: "sales-orders"},
+		{name: "qualified routes to second catalog", defaultCatalog: "sales", ref: "finance.main.orders", wantID: "finance-orders"},
+		{name: "unqualified uses default catalog", defaultCatalog: "finance", ref: "orders", wantID: "finance-orders"},
+		{name: "schema qualified uses default catalog", defaultCatalog: "finance", ref: "ledger.entries", wantID: "finance-entries"},
+		{name: "unqualified falls back without default", defaultCatalog: "", ref: "titanic", wantID: "1"},
+		{name: "unqualified falls back when missing in default", defaultCatalog: "sales", ref: "titanic", wantID: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cat, _, ctx := setupTestService(t)
+			multiCatalogLookup(t, cat, tables, tt.defaultCatalog)
+
+			tableID, _, isExternal, err := cat.LookupTableID(ctx, tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, tableID)
+			assert.False(t, isExternal)
+		})
+	}
+}
+
+func TestRowFilters_ResolvedPerCatalog(t *testing.T) {
+	cat, q, ctx := setupTestService(t)
+	multiCatalogLookup(t, cat, map[string]map[string]map[string]string{
+		"sales":   {"main": {"orders": "sales-orders"}},
+		"finance": {"main": {"orders": "finance-orders"}},
+	}, "sales")
+
+	user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+		Name: "regional_analyst", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+
+	filter, err := q.CreateRowFilter(ctx, dbstore.CreateRowFilterParams{
+		ID: uuid.New().String(), TableID: "finance-orders",
+		FilterSql: `"region" = 'EU'`,
+	})
+	require.NoError(t, err)
+	err = q.BindRowFilter(ctx, dbstore.BindRowFilterParams{
+		ID: uuid.New().String(), RowFilterID: filter.ID, PrincipalID: user.ID, PrincipalType: "user",
+	})
+	require.NoError(t, err)
+
+	financeID, _, _, err := cat.LookupTableID(ctx, "finance.main.orders")
+	require.NoError(t, err)
+	financeFilters, err := cat.GetEffectiveRowFilters(ctx, "regional_analyst", financeID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`"region" = 'EU'`}, financeFilters)
+
+	// The same table name in the default catalog is a different securable.
+	salesID, _, _, err := cat.LookupTableID(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "sales-orders", salesID)
+	salesFilters, err := cat.GetEffectiveRowFilters(ctx, "regional_analyst", salesID)
+	require.NoError(t, err)
+	assert.Empty(t, salesFilters)
+}
+
 func TestLookupTableID_ViewSchemaQualified(t *testing.T) {
 	cat, q, ctx := setupTestService(t)
 
@@ -1048,6 +1139,113 @@ func TestColumnMask_CaseInsensitiveLookup(t *testing.T) {
 	require.False(t, hasUpper, "mask should not have mixed-case key")
 }
 
+func TestColumnMask_TagBasedMaskCoversNewlyTaggedColumn(t *testing.T) {
+	svc, q, ctx := setupTestService(t)
+
+	user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{
+		ID: uuid.New().String(), Name: "analyst", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+
+	tag, err := q.CreateTag(ctx, dbstore.CreateTagParams{
+		ID: uuid.New().String(), Key: "pii", CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	// The mask names the tag, not a column.
+	mask, err := q.CreateColumnMask(ctx, dbstore.CreateColumnMaskParams{
+		ID:             uuid.New().String(),
+		TagID:          sql.NullString{String: tag.ID, Valid: true},
+		MaskExpression: "'REDACTED'",
+	})
+	require.NoError(t, err)
+	err = q.BindColumnMask(ctx, dbstore.BindColumnMaskParams{
+		ID: uuid.New().String(), ColumnMaskID: mask.ID, PrincipalID: user.ID,
+		PrincipalType: "user", SeeOriginal: 0,
+	})
+	require.NoError(t, err)
+
+	masks, err := svc.GetEffectiveColumnMasks(ctx, "analyst", "1")
+	require.NoError(t, err)
+	assert.Nil(t, masks, "no column carries the tag yet")
+
+	// Tagging a column is enough to mask it; the mask itself is untouched.
+	_, err = q.CreateTagAssignment(ctx, dbstore.CreateTagAssignmentParams{
+		ID: uuid.New().String(), TagID: tag.ID, SecurableType: domain.TagSecurableTypeColumn,
+		SecurableID: "1", ColumnName: sql.NullString{String: "Email", Valid: true}, AssignedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	masks, err = svc.GetEffectiveColumnMasks(ctx, "analyst", "1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"email": "'REDACTED'"}, masks)
+
+	// The tag on table 1 does not reach table 2.
+	masks, err = svc.GetEffectiveColumnMasks(ctx, "analyst", "2")
+	require.NoError(t, err)
+	assert.Nil(t, masks)
+}
+
+func TestRowFilter_TableInheritsTagBasedFilter(t *testing.T) {
+	svc, q, ctx := setupTestService(t)
+
+	user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{
+		ID: uuid.New().String(), Name: "analyst", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+
+	tag, err := q.CreateTag(ctx, dbstore.CreateTagParams{
+		ID: uuid.New().String(), Key: "eu-only", CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	// The filter names the tag, not a table.
+	filter, err := q.CreateRowFilter(ctx, dbstore.CreateRowFilterParams{
+		ID:        uuid.New().String(),
+		TagID:     sql.NullString{String: tag.ID, Valid: true},
+		FilterSql: `"Embarked" = 'S'`,
+	})
+	require.NoError(t, err)
+	err = q.BindRowFilter(ctx, dbstore.BindRowFilterParams{
+		ID: uuid.New().String(), RowFilterID: filter.ID, PrincipalID: user.ID, PrincipalType: "user",
+	})
+	require.NoError(t, err)
+
+	filters, err := svc.GetEffectiveRowFilters(ctx, "analyst", "1")
+	require.NoError(t, err)
+	assert.Empty(t, filters, "table 1 does not carry the tag yet")
+
+	// Tagging the table is enough to filter it; the filter itself is untouched.
+	_, err = q.CreateTagAssignment(ctx, dbstore.CreateTagAssignmentParams{
+		ID: uuid.New().String(), TagID: tag.ID, SecurableType: domain.TagSecurableTypeTable,
+		SecurableID: "1", AssignedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	filters, err = svc.GetEffectiveRowFilters(ctx, "analyst", "1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{`"Embarked" = 'S'`}, filters)
+
+	// A filter on the table itself applies too; both are ANDed at query time.
+	direct, err := q.CreateRowFilter(ctx, dbstore.CreateRowFilterParams{
+		ID: uuid.New().String(), TableID: "1", FilterSql: `"Pclass" = 1`,
+	})
+	require.NoError(t, err)
+	err = q.BindRowFilter(ctx, dbstore.BindRowFilterParams{
+		ID: uuid.New().String(), RowFilterID: direct.ID, PrincipalID: user.ID, PrincipalType: "user",
+	})
+	require.NoError(t, err)
+
+	filters, err = svc.GetEffectiveRowFilters(ctx, "analyst", "1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{`"Pclass" = 1`, `"Embarked" = 'S'`}, filters)
+
+	// The tag on table 1 does not reach table 2.
+	filters, err = svc.GetEffectiveRowFilters(ctx, "analyst", "2")
+	require.NoError(t, err)
+	assert.Empty(t, filters)
+}
+
 // === Issue #46: ALL_PRIVILEGES expansion in hasGrant ===
 
 func TestAllPrivileges_ExpandsToSelect(t *testing.T) {
@@ -1136,3 +1334,78 @@ func TestAllPrivileges_GroupGrant_ExpandsToSelect(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ok, "group ALL_PRIVILEGES on schema should grant SELECT on table to group member")
 }
+
+func TestGrantExpiry_QueryDeniedAfterExpiry(t *testing.T) {
+	cat, q, ctx := setupTestService(t)
+
+	user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+		Name: "contractor", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+
+	_, err = q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+		ID: uuid.New().String(), PrincipalID: user.ID, PrincipalType: "user",
+		SecurableType: SecurableSchema, SecurableID: "0",
+		Privilege: PrivUsage,
+	})
+	require.NoError(t, err)
+
+	// Temporary SELECT that lapses shortly.
+	expiresAt := time.Now().Add(2 * time.Second)
+	_, err = q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+		ID: uuid.New().String(), PrincipalID: user.ID, PrincipalType: "user",
+		SecurableType: SecurableTable, SecurableID: "1",
+		Privilege: PrivSelect,
+		ExpiresAt: sql.NullString{String: expiresAt.UTC().Format(time.DateTime), Valid: true},
+	})
+	require.NoError(t, err)
+
+	ok, err := cat.CheckPrivilege(ctx, "contractor", SecurableTable, "1", PrivSelect)
+	require.NoError(t, err)
+	assert.True(t, ok, "unexpired grant should allow the query")
+
+	time.Sleep(time.Until(expiresAt) + time.Second)
+
+	// The memoized allow lapses with the grant, before any purge runs.
+	ok, err = cat.CheckPrivilege(ctx, "contractor", SecurableTable, "1", PrivSelect)
+	require.NoError(t, err)
+	assert.False(t, ok, "query should be denied once the grant expires")
+
+	grantSvc := NewGrantService(cat.grants, nil, cat)
+	n, err := grantSvc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	ok, err = cat.CheckPrivilege(ctx, "contractor", SecurableTable, "1", PrivSelect)
+	require.NoError(t, err)
+	assert.False(t, ok, "query stays denied after the purge")
+
+	ok, err = cat.CheckPrivilege(ctx, "contractor", SecurableSchema, "0", PrivUsage)
+	require.NoError(t, err)
+	assert.True(t, ok, "grants without expiry are unaffected")
+}
+
+func TestGrantExpiry_ExpiredGrantIgnoredBeforePurge(t *testing.T) {
+	cat, q, ctx := setupTestService(t)
+
+	user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+		Name: "former", Type: "user", IsAdmin: 0,
+	})
+	require.NoError(t, err)
+
+	for _, g := range []dbstore.GrantPrivilegeParams{
+		{SecurableType: SecurableSchema, SecurableID: "0", Privilege: PrivUsage},
+		{SecurableType: SecurableTable, SecurableID: "1", Privilege: PrivSelect},
+	} {
+		g.ID = uuid.New().String()
+		g.PrincipalID = user.ID
+		g.PrincipalType = "user"
+		g.ExpiresAt = sql.NullString{String: time.Now().Add(-time.Minute).UTC().Format(time.DateTime), Valid: true}
+		_, err = q.GrantPrivilege(ctx, g)
+		require.NoError(t, err)
+	}
+
+	ok, err := cat.CheckPrivilege(ctx, "former", SecurableTable, "1", PrivSelect)
+	require.NoError(t, err)
+	assert.False(t, ok, "expired grants should be treated as absent")
+}
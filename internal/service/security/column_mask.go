@@ -2,15 +2,25 @@ package security
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"strings"
 
+	"duck-demo/internal/ddl"
 	"duck-demo/internal/domain"
 	"duck-demo/internal/duckdbsql"
+	"duck-demo/internal/sqlrewrite"
 )
 
 // ColumnMaskService provides column masking operations.
 type ColumnMaskService struct {
 	repo  domain.ColumnMaskRepository
 	audit domain.AuditRepository
+
+	// Optional: required only by Preview.
+	auth          domain.AuthorizationService
+	introspection domain.IntrospectionRepository
+	engine        domain.QueryEngine
 }
 
 // NewColumnMaskService creates a new ColumnMaskService.
@@ -18,6 +28,14 @@ func NewColumnMaskService(repo domain.ColumnMaskRepository, audit domain.AuditRe
 	return &ColumnMaskService{repo: repo, audit: audit}
 }
 
+// SetPreviewDeps wires the dependencies Preview needs to sample a masked
+// column as another principal.
+func (s *ColumnMaskService) SetPreviewDeps(auth domain.AuthorizationService, introspection domain.IntrospectionRepository, engine domain.QueryEngine) {
+	s.auth = auth
+	s.introspection = introspection
+	s.engine = engine
+}
+
 // Create validates and persists a new column mask. Requires admin privileges.
 func (s *ColumnMaskService) Create(ctx context.Context, req domain.CreateColumnMaskRequest) (*domain.ColumnMask, error) {
 	if err := requireAdmin(ctx); err != nil {
@@ -35,6 +53,9 @@ func (s *ColumnMaskService) Create(ctx context.Context, req domain.CreateColumnM
 		MaskExpression: req.MaskExpression,
 		Description:    req.Description,
 	}
+	if req.TagID != "" {
+		m.TagID = &req.TagID
+	}
 	result, err := s.repo.Create(ctx, m)
 	if err != nil {
 		return nil, err
@@ -55,6 +76,15 @@ func (s *ColumnMaskService) GetForTable(ctx context.Context, tableID string, pag
 	return s.repo.GetForTable(ctx, tableID, page)
 }
 
+// GetForTag returns a paginated list of the tag-based column masks for a tag.
+// Requires admin privileges.
+func (s *ColumnMaskService) GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetForTag(ctx, tagID, page)
+}
+
 // Delete removes a column mask by ID. Requires admin privileges.
 func (s *ColumnMaskService) Delete(ctx context.Context, id string) error {
 	if err := requireAdmin(ctx); err != nil {
@@ -127,3 +157,136 @@ func (s *ColumnMaskService) ListBindings(ctx context.Context, maskID string) ([]
 	}
 	return s.repo.ListBindings(ctx, maskID)
 }
+
+// Preview samples up to limit rows of a mask's column as principalName would
+// read them, next to the stored values. The target's row filters decide which
+// rows are sampled and its effective mask decides the masked value, so a
+// see_original binding shows both values unchanged. Requires admin privileges,
+// since the original values are returned.
+func (s *ColumnMaskService) Preview(ctx context.Context, maskID, principalName string, limit int) (*domain.ColumnMaskPreview, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if s.auth == nil || s.introspection == nil || s.engine == nil {
+		return nil, fmt.Errorf("column mask preview is not configured")
+	}
+	if principalName == "" {
+		return nil, domain.ErrValidation("principal is required")
+	}
+	switch {
+	case limit == 0:
+		limit = domain.DefaultColumnMaskPreviewLimit
+	case limit < 0 || limit > domain.MaxColumnMaskPreviewLimit:
+		return nil, domain.ErrValidation("limit must be between 1 and %d", domain.MaxColumnMaskPreviewLimit)
+	}
+
+	mask, err := s.repo.GetByID(ctx, maskID)
+	if err != nil {
+		return nil, err
+	}
+	if mask.TagID != nil {
+		return nil, domain.ErrValidation("column mask %s targets tag %s rather than one column; preview is not supported", mask.ID, *mask.TagID)
+	}
+	schemaName, tableName, err := s.tableNames(ctx, mask.TableID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.auth.CheckPrivilege(ctx, principalName, domain.SecurableTable, mask.TableID, domain.PrivSelect)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, domain.ErrValidation("principal %q lacks SELECT on table %s.%s", principalName, schemaName, tableName)
+	}
+	masks, err := s.auth.GetEffectiveColumnMasks(ctx, principalName, mask.TableID)
+	if err != nil {
+		return nil, fmt.Errorf("column masks: %w", err)
+	}
+	filters, err := s.auth.GetEffectiveRowFilters(ctx, principalName, mask.TableID)
+	if err != nil {
+		return nil, fmt.Errorf("row filter: %w", err)
+	}
+
+	preview := &domain.ColumnMaskPreview{
+		ColumnMaskID: mask.ID,
+		TableName:    schemaName + "." + tableName,
+		ColumnName:   mask.ColumnName,
+		Principal:    principalName,
+	}
+	column := ddl.QuoteIdentifier(mask.ColumnName)
+	maskedExpr := column
+	if expr, ok := masks[strings.ToLower(mask.ColumnName)]; ok {
+		preview.Masked = true
+		preview.MaskExpression = expr
+		maskedExpr = "(" + expr + ")"
+	}
+
+	// The caller is an admin, so the engine applies no masks or filters of
+	// its own; the target's are spelled out in the query instead.
+	query := fmt.Sprintf("SELECT %s AS original, %s AS masked FROM %s.%s LIMIT %d",
+		column, maskedExpr, ddl.QuoteIdentifier(schemaName), ddl.QuoteIdentifier(tableName), limit)
+	if len(filters) > 0 {
+		query, err = sqlrewrite.InjectMultipleRowFilters(query, tableName, filters)
+		if err != nil {
+			return nil, fmt.Errorf("inject row filter: %w", err)
+		}
+	}
+	rows, err := s.engine.Query(ctx, callerName(ctx), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+	if preview.Rows, err = scanPreviewRows(rows); err != nil {
+		return nil, fmt.Errorf("read preview rows: %w", err)
+	}
+
+	_ = s.audit.Insert(ctx, &domain.AuditEntry{
+		PrincipalName: callerName(ctx),
+		Action:        "PREVIEW_COLUMN_MASK",
+		Status:        "ALLOWED",
+	})
+	return preview, nil
+}
+
+// tableNames resolves a table ID to its schema and table names.
+func (s *ColumnMaskService) tableNames(ctx context.Context, tableID string) (string, string, error) {
+	tbl, err := s.introspection.GetTable(ctx, tableID)
+	if err != nil {
+		return "", "", err
+	}
+	schemas, _, err := s.introspection.ListSchemas(ctx, domain.PageRequest{MaxResults: domain.MaxMaxResults})
+	if err != nil {
+		return "", "", err
+	}
+	for _, schema := range schemas {
+		if schema.ID == tbl.SchemaID {
+			return schema.Name, tbl.Name, nil
+		}
+	}
+	return "", "", domain.ErrNotFound("schema of table %q not found", tbl.Name)
+}
+
+// scanPreviewRows reads (original, masked) pairs, converting byte slices to
+// strings for JSON serialization.
+func scanPreviewRows(rows *sql.Rows) ([]domain.ColumnMaskPreviewRow, error) {
+	result := make([]domain.ColumnMaskPreviewRow, 0)
+	for rows.Next() {
+		var original, masked interface{}
+		if err := rows.Scan(&original, &masked); err != nil {
+			return nil, err
+		}
+		result = append(result, domain.ColumnMaskPreviewRow{
+			Original: jsonValue(original),
+			Masked:   jsonValue(masked),
+		})
+	}
+	return result, rows.Err()
+}
+
+func jsonValue(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
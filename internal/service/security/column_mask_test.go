@@ -2,8 +2,10 @@ package security
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
+	_ "github.com/duckdb/duckdb-go/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -13,7 +15,9 @@ import (
 
 type mockColumnMaskRepo struct {
 	CreateFn                  func(ctx context.Context, m *domain.ColumnMask) (*domain.ColumnMask, error)
+	GetByIDFn                 func(ctx context.Context, id string) (*domain.ColumnMask, error)
 	GetForTableFn             func(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error)
+	GetForTagFn               func(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error)
 	DeleteFn                  func(ctx context.Context, id string) error
 	BindFn                    func(ctx context.Context, b *domain.ColumnMaskBinding) error
 	UnbindFn                  func(ctx context.Context, b *domain.ColumnMaskBinding) error
@@ -25,10 +29,18 @@ func (m *mockColumnMaskRepo) Create(ctx context.Context, mask *domain.ColumnMask
 	return m.CreateFn(ctx, mask)
 }
 
+func (m *mockColumnMaskRepo) GetByID(ctx context.Context, id string) (*domain.ColumnMask, error) {
+	return m.GetByIDFn(ctx, id)
+}
+
 func (m *mockColumnMaskRepo) GetForTable(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error) {
 	return m.GetForTableFn(ctx, tableID, page)
 }
 
+func (m *mockColumnMaskRepo) GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.ColumnMask, int64, error) {
+	return m.GetForTagFn(ctx, tagID, page)
+}
+
 func (m *mockColumnMaskRepo) Delete(ctx context.Context, id string) error {
 	return m.DeleteFn(ctx, id)
 }
@@ -110,6 +122,71 @@ func TestColumnMaskService_Create_EmptyMaskExpression(t *testing.T) {
 	assert.ErrorAs(t, err, &validation)
 }
 
+func TestColumnMaskService_Create_TagBased(t *testing.T) {
+	var created *domain.ColumnMask
+	repo := &mockColumnMaskRepo{
+		CreateFn: func(_ context.Context, m *domain.ColumnMask) (*domain.ColumnMask, error) {
+			m.ID = "cm-1"
+			created = m
+			return m, nil
+		},
+	}
+	svc := NewColumnMaskService(repo, &testutil.MockAuditRepo{})
+
+	_, err := svc.Create(adminCtx(), domain.CreateColumnMaskRequest{
+		TagID:          "tag-pii",
+		MaskExpression: "'***'",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.TagID)
+	assert.Equal(t, "tag-pii", *created.TagID)
+	assert.Empty(t, created.TableID)
+	assert.Empty(t, created.ColumnName)
+
+	_, err = svc.Create(adminCtx(), domain.CreateColumnMaskRequest{
+		TagID:          "tag-pii",
+		TableID:        "t-1",
+		ColumnName:     "email",
+		MaskExpression: "'***'",
+	})
+	var validation *domain.ValidationError
+	assert.ErrorAs(t, err, &validation, "a mask targets either a tag or a column")
+}
+
+func TestColumnMaskService_GetForTag_NonAdminDenied(t *testing.T) {
+	svc := NewColumnMaskService(&mockColumnMaskRepo{}, &testutil.MockAuditRepo{})
+
+	_, _, err := svc.GetForTag(nonAdminCtx(), "tag-pii", domain.PageRequest{})
+	var denied *domain.AccessDeniedError
+	assert.ErrorAs(t, err, &denied)
+}
+
+func TestEffectiveColumnMasks_ColumnMaskOverridesTagMask(t *testing.T) {
+	ctx := context.Background()
+	principals := &attrPrincipalRepo{byName: map[string]*domain.Principal{
+		"bob": {ID: "p-bob", Name: "bob"},
+	}}
+	tagID := "tag-pii"
+	// email has its own mask and the pii tag; phone only has the tag.
+	masks := &mockColumnMaskRepo{
+		GetForTableAndPrincipalFn: func(_ context.Context, _, _ string, principalType string) ([]domain.ColumnMaskWithBinding, error) {
+			if principalType != "user" {
+				return nil, nil
+			}
+			return []domain.ColumnMaskWithBinding{
+				{ColumnName: "email", MaskExpression: "'***@***'"},
+				{ColumnName: "email", MaskExpression: "'REDACTED'", TagID: &tagID},
+				{ColumnName: "Phone", MaskExpression: "'REDACTED'", TagID: &tagID},
+			}, nil
+		},
+	}
+	auth := NewAuthorizationService(principals, noGroupsRepo{}, nil, nil, masks, nil, nil)
+
+	got, err := auth.GetEffectiveColumnMasks(ctx, "bob", "t-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"email": "'***@***'", "phone": "'REDACTED'"}, got)
+}
+
 func TestColumnMaskService_Delete_AdminAllowed(t *testing.T) {
 	called := false
 	repo := &mockColumnMaskRepo{
@@ -219,3 +296,142 @@ func TestColumnMaskService_ListBindings_RequiresAdmin(t *testing.T) {
 	var accessDenied *domain.AccessDeniedError
 	assert.ErrorAs(t, err, &accessDenied)
 }
+
+// newPreviewService returns a ColumnMaskService whose preview queries run
+// against an in-memory DuckDB holding sales.customers(id, email). Bob is
+// bound to mask cm-1 on email; seeOriginal is the binding's see_original flag.
+func newPreviewService(t *testing.T, seeOriginal bool, rowFilters []string) (*ColumnMaskService, *testutil.MockAuditRepo, *[]string) {
+	t.Helper()
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec(`CREATE SCHEMA sales;
+		CREATE TABLE sales.customers (id INTEGER, email VARCHAR);
+		INSERT INTO sales.customers VALUES (1, 'alice@example.com'), (2, 'bob@example.com'), (3, 'carol@example.com')`)
+	require.NoError(t, err)
+
+	repo := &mockColumnMaskRepo{
+		GetByIDFn: func(_ context.Context, id string) (*domain.ColumnMask, error) {
+			if id != "cm-1" {
+				return nil, domain.ErrNotFound("column mask %q not found", id)
+			}
+			return &domain.ColumnMask{ID: "cm-1", TableID: "t-1", ColumnName: "email", MaskExpression: "'***'"}, nil
+		},
+	}
+	auth := &testutil.MockAuthService{
+		CheckPrivilegeFn: func(_ context.Context, principal, _, _, _ string) (bool, error) {
+			return principal == "bob", nil
+		},
+		GetEffectiveColumnMasksFn: func(_ context.Context, _, _ string) (map[string]string, error) {
+			if seeOriginal {
+				return map[string]string{}, nil
+			}
+			return map[string]string{"email": "'***'"}, nil
+		},
+		GetEffectiveRowFiltersFn: func(_ context.Context, _, _ string) ([]string, error) {
+			return rowFilters, nil
+		},
+	}
+	introspection := &testutil.MockIntrospectionRepo{
+		GetTableFn: func(_ context.Context, _ string) (*domain.Table, error) {
+			return &domain.Table{ID: "t-1", SchemaID: "s-1", Name: "customers"}, nil
+		},
+		ListSchemasFn: func(_ context.Context, _ domain.PageRequest) ([]domain.Schema, int64, error) {
+			return []domain.Schema{{ID: "s-0", Name: "main"}, {ID: "s-1", Name: "sales"}}, 2, nil
+		},
+	}
+	var queriedAs []string
+	engine := &testutil.MockSessionEngine{
+		QueryFn: func(ctx context.Context, principal, query string) (*sql.Rows, error) {
+			queriedAs = append(queriedAs, principal)
+			return db.QueryContext(ctx, query)
+		},
+	}
+
+	audit := &testutil.MockAuditRepo{}
+	svc := NewColumnMaskService(repo, audit)
+	svc.SetPreviewDeps(auth, introspection, engine)
+	return svc, audit, &queriedAs
+}
+
+func TestColumnMaskService_Preview_MaskedDiffersFromOriginal(t *testing.T) {
+	svc, audit, queriedAs := newPreviewService(t, false, nil)
+
+	preview, err := svc.Preview(adminCtx(), "cm-1", "bob", 0)
+	require.NoError(t, err)
+	assert.True(t, preview.Masked)
+	assert.Equal(t, "'***'", preview.MaskExpression)
+	assert.Equal(t, "sales.customers", preview.TableName)
+	require.Len(t, preview.Rows, 3)
+	for _, row := range preview.Rows {
+		assert.Equal(t, "***", row.Masked)
+		assert.NotEqual(t, row.Original, row.Masked)
+		assert.Contains(t, row.Original, "@example.com")
+	}
+	assert.Equal(t, []string{"admin-user"}, *queriedAs, "the sample runs as the admin caller")
+	assert.True(t, audit.HasAction("PREVIEW_COLUMN_MASK"))
+}
+
+func TestColumnMaskService_Preview_SeeOriginalShowsOriginal(t *testing.T) {
+	svc, _, _ := newPreviewService(t, true, nil)
+
+	preview, err := svc.Preview(adminCtx(), "cm-1", "bob", 0)
+	require.NoError(t, err)
+	assert.False(t, preview.Masked)
+	assert.Empty(t, preview.MaskExpression)
+	require.Len(t, preview.Rows, 3)
+	for _, row := range preview.Rows {
+		assert.Equal(t, row.Original, row.Masked)
+	}
+}
+
+func TestColumnMaskService_Preview_AppliesRowFiltersAndLimit(t *testing.T) {
+	svc, _, _ := newPreviewService(t, false, []string{"id >= 2"})
+
+	preview, err := svc.Preview(adminCtx(), "cm-1", "bob", 1)
+	require.NoError(t, err)
+	require.Len(t, preview.Rows, 1)
+	assert.NotEqual(t, "alice@example.com", preview.Rows[0].Original)
+}
+
+func TestColumnMaskService_Preview_Errors(t *testing.T) {
+	svc, _, _ := newPreviewService(t, false, nil)
+
+	t.Run("non-admin denied", func(t *testing.T) {
+		_, err := svc.Preview(nonAdminCtx(), "cm-1", "bob", 0)
+		var denied *domain.AccessDeniedError
+		assert.ErrorAs(t, err, &denied)
+	})
+	t.Run("principal required", func(t *testing.T) {
+		_, err := svc.Preview(adminCtx(), "cm-1", "", 0)
+		var validation *domain.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+	t.Run("limit out of range", func(t *testing.T) {
+		_, err := svc.Preview(adminCtx(), "cm-1", "bob", domain.MaxColumnMaskPreviewLimit+1)
+		var validation *domain.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+	t.Run("unknown mask", func(t *testing.T) {
+		_, err := svc.Preview(adminCtx(), "cm-404", "bob", 0)
+		var notFound *domain.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+	t.Run("tag-based mask", func(t *testing.T) {
+		tagID := "tag-pii"
+		tagSvc := NewColumnMaskService(&mockColumnMaskRepo{
+			GetByIDFn: func(_ context.Context, _ string) (*domain.ColumnMask, error) {
+				return &domain.ColumnMask{ID: "cm-2", TagID: &tagID, MaskExpression: "'***'"}, nil
+			},
+		}, &testutil.MockAuditRepo{})
+		tagSvc.SetPreviewDeps(&testutil.MockAuthService{}, &testutil.MockIntrospectionRepo{}, &testutil.MockSessionEngine{})
+		_, err := tagSvc.Preview(adminCtx(), "cm-2", "bob", 0)
+		var validation *domain.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+	t.Run("principal without SELECT", func(t *testing.T) {
+		_, err := svc.Preview(adminCtx(), "cm-1", "eve", 0)
+		var validation *domain.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+}
@@ -0,0 +1,54 @@
+//go:build integration
+
+package security
+
+import (
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internaldb "duck-demo/internal/db"
+	"duck-demo/internal/db/repository"
+	"duck-demo/internal/domain"
+)
+
+func setupDefaultGrantService(t *testing.T) *DefaultGrantService {
+	t.Helper()
+	db, _ := internaldb.OpenTestSQLite(t)
+	return NewDefaultGrantService(repository.NewDefaultGrantRepo(db), repository.NewAuditRepo(db))
+}
+
+func TestDefaultGrantService_AdminRequired(t *testing.T) {
+	svc := setupDefaultGrantService(t)
+	req := domain.CreateDefaultGrantRequest{
+		CatalogName: "main", ObjectType: domain.SecurableTable,
+		PrincipalID: "group-1", PrincipalType: "group", Privilege: domain.PrivSelect,
+	}
+
+	_, err := svc.Create(nonAdminCtx(), req)
+	var accessDenied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &accessDenied)
+
+	_, _, err = svc.List(nonAdminCtx(), domain.DefaultGrantFilter{})
+	require.ErrorAs(t, err, &accessDenied)
+
+	created, err := svc.Create(adminCtx(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "admin-user", created.CreatedBy)
+
+	require.ErrorAs(t, svc.Delete(nonAdminCtx(), created.ID), &accessDenied)
+	require.NoError(t, svc.Delete(adminCtx(), created.ID))
+}
+
+func TestDefaultGrantService_Create_Validation(t *testing.T) {
+	svc := setupDefaultGrantService(t)
+
+	_, err := svc.Create(adminCtx(), domain.CreateDefaultGrantRequest{
+		CatalogName: "main", ObjectType: "volume",
+		PrincipalID: "group-1", PrincipalType: "group", Privilege: domain.PrivSelect,
+	})
+	var validation *domain.ValidationError
+	require.ErrorAs(t, err, &validation)
+}
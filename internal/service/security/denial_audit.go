@@ -0,0 +1,92 @@
+package security
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/auditutil"
+)
+
+// DefaultDenialAuditWindow is how long a repeated denial of the same
+// principal, action and securable is suppressed after it was recorded.
+const DefaultDenialAuditWindow = time.Minute
+
+// maxTrackedDenials bounds the suppression map; expired keys are pruned once
+// it grows past this size.
+const maxTrackedDenials = 4096
+
+// DenialAuditor records denied authorization attempts as ACCESS_DENIED audit
+// entries. Identical denials within the window are recorded once, so a client
+// retrying in a loop cannot flood the audit log.
+type DenialAuditor struct {
+	audit  domain.AuditRepository
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewDenialAuditor creates a DenialAuditor. A non-positive window disables
+// suppression.
+func NewDenialAuditor(audit domain.AuditRepository, window time.Duration) *DenialAuditor {
+	return &DenialAuditor{
+		audit:  audit,
+		window: window,
+		now:    time.Now,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// RecordDenial writes d to the audit log unless the same denial was recorded
+// within the window. It implements domain.DenialRecorder.
+func (a *DenialAuditor) RecordDenial(ctx context.Context, d domain.AccessDenial) {
+	if a == nil || a.audit == nil || !a.admit(d) {
+		return
+	}
+	entry := &domain.AuditEntry{
+		PrincipalName: d.PrincipalName,
+		Action:        domain.AuditActionAccessDenied,
+		Status:        "DENIED",
+	}
+	if d.Action != "" {
+		entry.StatementType = &d.Action
+	}
+	if d.Securable != "" {
+		entry.TablesAccessed = []string{d.Securable}
+	}
+	if d.Reason != "" {
+		entry.ErrorMessage = &d.Reason
+	}
+	// Detach from request cancellation: the attempt was already refused and
+	// the denial should be kept even if the client has gone away.
+	_ = auditutil.Insert(context.WithoutCancel(ctx), a.audit, entry)
+}
+
+// admit reports whether d should be recorded and marks it as seen.
+func (a *DenialAuditor) admit(d domain.AccessDenial) bool {
+	if a.window <= 0 {
+		return true
+	}
+	key := d.PrincipalName + "\x00" + d.Action + "\x00" + d.Securable
+	now := a.now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if last, ok := a.seen[key]; ok && now.Sub(last) < a.window {
+		return false
+	}
+	if len(a.seen) >= maxTrackedDenials {
+		for k, last := range a.seen {
+			if now.Sub(last) >= a.window {
+				delete(a.seen, k)
+			}
+		}
+	}
+	a.seen[key] = now
+	return true
+}
+
+var _ domain.DenialRecorder = (*DenialAuditor)(nil)
@@ -0,0 +1,55 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/testutil"
+)
+
+func TestDenialAuditor_RecordsDenial(t *testing.T) {
+	audit := &testutil.MockAuditRepo{}
+	a := NewDenialAuditor(audit, time.Minute)
+
+	a.RecordDenial(context.Background(), domain.AccessDenial{
+		PrincipalName: "mallory", Action: "SELECT", Securable: "main.secret", Reason: "missing SELECT",
+	})
+
+	e := audit.LastEntry()
+	require.NotNil(t, e)
+	assert.Equal(t, "mallory", e.PrincipalName)
+	assert.Equal(t, domain.AuditActionAccessDenied, e.Action)
+	assert.Equal(t, "DENIED", e.Status)
+	require.NotNil(t, e.StatementType)
+	assert.Equal(t, "SELECT", *e.StatementType)
+	assert.Equal(t, []string{"main.secret"}, e.TablesAccessed)
+	require.NotNil(t, e.ErrorMessage)
+	assert.Equal(t, "missing SELECT", *e.ErrorMessage)
+}
+
+func TestDenialAuditor_SuppressesRepeatsWithinWindow(t *testing.T) {
+	audit := &testutil.MockAuditRepo{}
+	a := NewDenialAuditor(audit, time.Minute)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+
+	denial := domain.AccessDenial{PrincipalName: "mallory", Action: "SELECT", Securable: "main.secret"}
+	for range 5 {
+		a.RecordDenial(context.Background(), denial)
+	}
+	assert.Len(t, audit.Entries, 1, "repeats inside the window are suppressed")
+
+	other := denial
+	other.Securable = "main.other"
+	a.RecordDenial(context.Background(), other)
+	assert.Len(t, audit.Entries, 2, "a different securable is recorded")
+
+	now = now.Add(time.Minute)
+	a.RecordDenial(context.Background(), denial)
+	assert.Len(t, audit.Entries, 3, "the denial is recorded again once the window passed")
+}
@@ -0,0 +1,409 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"duck-demo/internal/domain"
+)
+
+// accessPageSize is the page size used when walking grants, group members
+// and principals to resolve effective access.
+const accessPageSize = 500
+
+// tableAccessPrivileges are the privileges that open a table's data when
+// granted on the table or one of its parents. USE_SCHEMA and USE_CATALOG
+// only gate browsing and CREATE_* privileges act on the parent itself.
+var tableAccessPrivileges = map[string]bool{
+	domain.PrivSelect:        true,
+	domain.PrivInsert:        true,
+	domain.PrivUpdate:        true,
+	domain.PrivDelete:        true,
+	domain.PrivModify:        true,
+	domain.PrivAllPrivileges: true,
+}
+
+// accessLevel is a securable whose grants reach the securable being resolved.
+type accessLevel struct {
+	securableType string
+	securableID   string
+	inherited     bool // a parent of the resolved securable
+}
+
+// EffectiveAccess reverses privilege resolution for a securable: it lists
+// every user and group that holds a privilege on it, and whether that comes
+// from a direct grant, a group grant, or a grant cascading from a parent
+// schema or catalog. For tables it also reports the row filters and column
+// masks each user reads through. ref is the securable's ID or its name as
+// accepted by CheckPrivilege. Admin only.
+//
+// Users and groups that hold a table grant but lack USE_SCHEMA on the
+// parent schema cannot use it and are left out.
+func (s *AuthorizationService) EffectiveAccess(ctx context.Context, securableType, ref string) (*domain.SecurableAccess, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		return nil, domain.ErrValidation("securable id is required")
+	}
+
+	typ, id, schemaID, err := s.resolveAccessSecurable(ctx, securableType, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &accessResolver{s: s, securableType: typ, schemaID: schemaID, seen: map[domain.AccessEntry]bool{}, groupIDs: map[string][]string{}}
+	for _, lvl := range s.accessLevels(ctx, typ, id, schemaID) {
+		if err := r.addGrantsOn(ctx, lvl); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.addAdmins(ctx); err != nil {
+		return nil, err
+	}
+
+	entries := r.entries
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.PrincipalType != b.PrincipalType {
+			return a.PrincipalType < b.PrincipalType
+		}
+		if a.PrincipalName != b.PrincipalName {
+			return a.PrincipalName < b.PrincipalName
+		}
+		return a.Privilege < b.Privilege
+	})
+
+	access := &domain.SecurableAccess{SecurableType: typ, SecurableID: id, Entries: entries}
+	if typ == domain.SecurableTable {
+		access.Policies, err = s.policiesFor(ctx, id, entries)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return access, nil
+}
+
+// resolveAccessSecurable resolves ref to the securable grants are recorded
+// against. Tables and schemas may be given by ID or by name; schemaID is set
+// for tables.
+func (s *AuthorizationService) resolveAccessSecurable(ctx context.Context, securableType, ref string) (typ, id, schemaID string, err error) {
+	switch strings.ToLower(securableType) {
+	case domain.SecurableTable, "view":
+		if schemaID, ok := s.tableSchemaID(ctx, ref); ok {
+			return domain.SecurableTable, ref, schemaID, nil
+		}
+		_, tableID, err := s.resolveSecurable(ctx, securableType+":"+ref)
+		if err != nil {
+			return "", "", "", err
+		}
+		schemaID, ok := s.tableSchemaID(ctx, tableID)
+		if !ok {
+			return "", "", "", domain.ErrNotFound("%s %q not found", securableType, ref)
+		}
+		return domain.SecurableTable, tableID, schemaID, nil
+	case domain.SecurableSchema:
+		_, schemaID, err := s.resolveSecurable(ctx, securableType+":"+ref)
+		var notFound *domain.NotFoundError
+		if errors.As(err, &notFound) {
+			schemas, _, listErr := s.introspection.ListSchemas(ctx, domain.PageRequest{MaxResults: 10000})
+			if listErr != nil {
+				return "", "", "", fmt.Errorf("list schemas: %w", listErr)
+			}
+			for _, sch := range schemas {
+				if sch.ID == ref {
+					return domain.SecurableSchema, ref, "", nil
+				}
+			}
+		}
+		return domain.SecurableSchema, schemaID, "", err
+	default:
+		typ, id, err := s.resolveSecurable(ctx, securableType+":"+ref)
+		return typ, id, "", err
+	}
+}
+
+// accessLevels lists the securable followed by the parents whose grants
+// cascade into it, mirroring checkPrivilegeForIdentities.
+func (s *AuthorizationService) accessLevels(ctx context.Context, securableType, securableID, schemaID string) []accessLevel {
+	var levels []accessLevel
+	var catalogRef string
+	switch securableType {
+	case domain.SecurableCatalog:
+		catalogRef = securableID
+	case domain.SecurableTable:
+		levels = append(levels,
+			accessLevel{securableType: domain.SecurableTable, securableID: securableID},
+			accessLevel{securableType: domain.SecurableSchema, securableID: schemaID, inherited: true})
+		catalogRef = catalogOfID(schemaID)
+	default:
+		levels = append(levels, accessLevel{securableType: securableType, securableID: securableID})
+		catalogRef = s.owningCatalog(ctx, securableType, securableID)
+	}
+	for _, id := range s.catalogSecurableIDs(ctx, catalogRef) {
+		levels = append(levels, accessLevel{
+			securableType: domain.SecurableCatalog,
+			securableID:   id,
+			inherited:     securableType != domain.SecurableCatalog,
+		})
+	}
+	return levels
+}
+
+// grantReaches reports whether a grant of privilege on lvl gives access to
+// a securable of the given type.
+func grantReaches(securableType string, lvl accessLevel, privilege string) bool {
+	if securableType == domain.SecurableTable {
+		return tableAccessPrivileges[privilege]
+	}
+	// USE_CATALOG implies nothing below the catalog.
+	return !lvl.inherited || privilege != domain.PrivUseCatalog
+}
+
+// accessResolver accumulates the entries of one EffectiveAccess call.
+type accessResolver struct {
+	s             *AuthorizationService
+	securableType string
+	schemaID      string
+	entries       []domain.AccessEntry
+	seen          map[domain.AccessEntry]bool
+	groupIDs      map[string][]string // "user:<id>" or "group:<id>" -> groups it belongs to
+}
+
+func (r *accessResolver) add(e domain.AccessEntry) {
+	if !r.seen[e] {
+		r.seen[e] = true
+		r.entries = append(r.entries, e)
+	}
+}
+
+// addGrantsOn adds the users and groups reached by the grants on lvl.
+func (r *accessResolver) addGrantsOn(ctx context.Context, lvl accessLevel) error {
+	now := time.Now()
+	page := domain.PageRequest{MaxResults: accessPageSize}
+	for {
+		grants, total, err := r.s.grants.ListForSecurable(ctx, lvl.securableType, lvl.securableID, page)
+		if err != nil {
+			return fmt.Errorf("list grants on %s %s: %w", lvl.securableType, lvl.securableID, err)
+		}
+		for _, g := range grants {
+			if g.ExpiresAt != nil && !g.ExpiresAt.After(now) {
+				continue
+			}
+			if !grantReaches(r.securableType, lvl, g.Privilege) {
+				continue
+			}
+			if err := r.addGrant(ctx, lvl, g); err != nil {
+				return err
+			}
+		}
+		next := domain.NextPageToken(page.Offset(), page.Limit(), total)
+		if next == "" {
+			return nil
+		}
+		page.PageToken = next
+	}
+}
+
+func (r *accessResolver) addGrant(ctx context.Context, lvl accessLevel, g domain.PrivilegeGrant) error {
+	entry := domain.AccessEntry{
+		PrincipalType: g.PrincipalType,
+		Privilege:     g.Privilege,
+		Source:        domain.AccessSourceDirect,
+		GrantedOnType: lvl.securableType,
+		GrantedOnID:   lvl.securableID,
+	}
+	if lvl.inherited {
+		entry.Source = domain.AccessSourceCascade
+	}
+
+	if g.PrincipalType != "group" {
+		p, err := r.s.principals.GetByID(ctx, g.PrincipalID)
+		if err != nil {
+			return nil // grant left behind by a deleted principal
+		}
+		ok, err := r.passesSchemaGate(ctx, "user", p.ID)
+		if err != nil || !ok {
+			return err
+		}
+		entry.PrincipalName = p.Name
+		r.add(entry)
+		return nil
+	}
+
+	group, err := r.s.groups.GetByID(ctx, g.PrincipalID)
+	if err != nil {
+		return nil // grant left behind by a deleted group
+	}
+	ok, err := r.passesSchemaGate(ctx, "group", group.ID)
+	if err != nil {
+		return err
+	}
+	if ok {
+		entry.PrincipalName = group.Name
+		r.add(entry)
+	}
+
+	users, err := r.s.groupUsers(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		ok, err := r.passesSchemaGate(ctx, "user", u.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		member := entry
+		member.PrincipalType = "user"
+		member.PrincipalName = u.Name
+		member.ViaGroup = group.Name
+		if !lvl.inherited {
+			member.Source = domain.AccessSourceGroup
+		}
+		r.add(member)
+	}
+	return nil
+}
+
+// passesSchemaGate reports whether a user or group may use the parent
+// schema of the table being resolved. Other securables have no gate.
+func (r *accessResolver) passesSchemaGate(ctx context.Context, memberType, memberID string) (bool, error) {
+	if r.securableType != domain.SecurableTable {
+		return true, nil
+	}
+	key := memberType + ":" + memberID
+	groupIDs, ok := r.groupIDs[key]
+	if !ok {
+		var err error
+		groupIDs, err = r.s.resolveMemberGroupIDs(ctx, memberType, memberID)
+		if err != nil {
+			return false, err
+		}
+		r.groupIDs[key] = groupIDs
+	}
+
+	principalID := memberID
+	if memberType == "group" {
+		// A group acts through its own grants and those of its parents.
+		principalID = ""
+		groupIDs = append([]string{memberID}, groupIDs...)
+	}
+	return r.s.checkSchemaPrivilege(ctx, principalID, groupIDs, r.schemaID, domain.PrivUseSchema)
+}
+
+// addAdmins adds every admin, who can access any securable.
+func (r *accessResolver) addAdmins(ctx context.Context) error {
+	page := domain.PageRequest{MaxResults: accessPageSize}
+	for {
+		principals, total, err := r.s.principals.List(ctx, page)
+		if err != nil {
+			return fmt.Errorf("list principals: %w", err)
+		}
+		for _, p := range principals {
+			if p.IsAdmin {
+				r.add(domain.AccessEntry{
+					PrincipalType: "user",
+					PrincipalName: p.Name,
+					Privilege:     domain.PrivAllPrivileges,
+					Source:        domain.AccessSourceAdmin,
+				})
+			}
+		}
+		next := domain.NextPageToken(page.Offset(), page.Limit(), total)
+		if next == "" {
+			return nil
+		}
+		page.PageToken = next
+	}
+}
+
+// groupUsers returns the users that belong to a group directly or through
+// nested groups.
+func (s *AuthorizationService) groupUsers(ctx context.Context, groupID string) ([]domain.Principal, error) {
+	visited := map[string]bool{groupID: true}
+	queue := []string{groupID}
+	seenUsers := map[string]bool{}
+	var users []domain.Principal
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		page := domain.PageRequest{MaxResults: accessPageSize}
+		for {
+			members, total, err := s.groups.ListMembers(ctx, current, page)
+			if err != nil {
+				return nil, fmt.Errorf("list members of group %s: %w", current, err)
+			}
+			for _, m := range members {
+				if m.MemberType == "group" {
+					if !visited[m.MemberID] {
+						visited[m.MemberID] = true
+						queue = append(queue, m.MemberID)
+					}
+					continue
+				}
+				if seenUsers[m.MemberID] {
+					continue
+				}
+				seenUsers[m.MemberID] = true
+				p, err := s.principals.GetByID(ctx, m.MemberID)
+				if err != nil {
+					continue // membership left behind by a deleted principal
+				}
+				users = append(users, *p)
+			}
+			next := domain.NextPageToken(page.Offset(), page.Limit(), total)
+			if next == "" {
+				break
+			}
+			page.PageToken = next
+		}
+	}
+	return users, nil
+}
+
+// policiesFor returns the row filters and column masks applied to each user
+// with access to a table. Admins bypass both and are skipped.
+func (s *AuthorizationService) policiesFor(ctx context.Context, tableID string, entries []domain.AccessEntry) ([]domain.PrincipalPolicies, error) {
+	admins := map[string]bool{}
+	for _, e := range entries {
+		if e.Source == domain.AccessSourceAdmin {
+			admins[e.PrincipalName] = true
+		}
+	}
+
+	var policies []domain.PrincipalPolicies
+	done := map[string]bool{}
+	for _, e := range entries {
+		if e.PrincipalType != "user" || admins[e.PrincipalName] || done[e.PrincipalName] {
+			continue
+		}
+		done[e.PrincipalName] = true
+
+		filters, err := s.GetEffectiveRowFilters(ctx, e.PrincipalName, tableID)
+		if err != nil {
+			return nil, fmt.Errorf("row filters for %s: %w", e.PrincipalName, err)
+		}
+		masks, err := s.GetEffectiveColumnMasks(ctx, e.PrincipalName, tableID)
+		if err != nil {
+			return nil, fmt.Errorf("column masks for %s: %w", e.PrincipalName, err)
+		}
+		if len(filters) == 0 && len(masks) == 0 {
+			continue
+		}
+		policies = append(policies, domain.PrincipalPolicies{
+			PrincipalName: e.PrincipalName,
+			RowFilters:    filters,
+			ColumnMasks:   masks,
+		})
+	}
+	return policies, nil
+}
@@ -2,6 +2,8 @@ package security
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"duck-demo/internal/domain"
 )
@@ -15,6 +17,8 @@ type GrantService struct {
 	repo        domain.GrantRepository
 	audit       domain.AuditRepository
 	invalidator privilegeCacheInvalidator
+	events      domain.EventPublisher
+	principals  domain.PrincipalRepository
 }
 
 // NewGrantService creates a new GrantService.
@@ -26,6 +30,17 @@ func NewGrantService(repo domain.GrantRepository, audit domain.AuditRepository,
 	return &GrantService{repo: repo, audit: audit, invalidator: inv}
 }
 
+// SetEventPublisher configures where grant change events are published.
+func (s *GrantService) SetEventPublisher(events domain.EventPublisher) {
+	s.events = events
+}
+
+// SetPrincipalRepository configures the lookup RevokeAll uses to reject
+// unknown principals.
+func (s *GrantService) SetPrincipalRepository(principals domain.PrincipalRepository) {
+	s.principals = principals
+}
+
 // Grant creates a new privilege grant. Requires admin privileges.
 func (s *GrantService) Grant(ctx context.Context, req domain.CreateGrantRequest) (*domain.PrivilegeGrant, error) {
 	if err := requireAdmin(ctx); err != nil {
@@ -42,6 +57,7 @@ func (s *GrantService) Grant(ctx context.Context, req domain.CreateGrantRequest)
 		SecurableID:   req.SecurableID,
 		Privilege:     req.Privilege,
 		GrantedBy:     &caller,
+		ExpiresAt:     req.ExpiresAt,
 	}
 	result, err := s.repo.Grant(ctx, g)
 	if err != nil {
@@ -55,6 +71,16 @@ func (s *GrantService) Grant(ctx context.Context, req domain.CreateGrantRequest)
 	if s.invalidator != nil {
 		s.invalidator.InvalidatePrivilegeCache()
 	}
+	if s.events != nil {
+		s.events.Publish(ctx, domain.NewEvent(ctx, domain.EventGrantCreated, map[string]interface{}{
+			"grant_id":       result.ID,
+			"principal_id":   result.PrincipalID,
+			"principal_type": result.PrincipalType,
+			"securable_type": result.SecurableType,
+			"securable_id":   result.SecurableID,
+			"privilege":      result.Privilege,
+		}))
+	}
 	return result, nil
 }
 
@@ -74,9 +100,39 @@ func (s *GrantService) Revoke(ctx context.Context, _ string, grantID string) err
 	if s.invalidator != nil {
 		s.invalidator.InvalidatePrivilegeCache()
 	}
+	if s.events != nil {
+		s.events.Publish(ctx, domain.NewEvent(ctx, domain.EventGrantDeleted, map[string]interface{}{
+			"grant_id": grantID,
+		}))
+	}
 	return nil
 }
 
+// UpdateExpiry changes when a grant expires; a nil expiry makes it permanent.
+// The grant keeps its ID and is never absent while the expiry changes.
+// Requires admin privileges.
+func (s *GrantService) UpdateExpiry(ctx context.Context, grantID string, req domain.UpdateGrantRequest) (*domain.PrivilegeGrant, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.UpdateExpiry(ctx, grantID, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.audit.Insert(ctx, &domain.AuditEntry{
+		PrincipalName: callerName(ctx),
+		Action:        "UPDATE_GRANT",
+		Status:        "ALLOWED",
+	})
+	if s.invalidator != nil {
+		s.invalidator.InvalidatePrivilegeCache()
+	}
+	return result, nil
+}
+
 // ListAll returns all grants with pagination. Requires admin privileges.
 func (s *GrantService) ListAll(ctx context.Context, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
 	if err := requireAdmin(ctx); err != nil {
@@ -85,6 +141,45 @@ func (s *GrantService) ListAll(ctx context.Context, page domain.PageRequest) ([]
 	return s.repo.ListAll(ctx, page)
 }
 
+// PurgeExpired deletes grants whose expiry has passed and drops memoized
+// privilege decisions that may still reflect them. Expired grants are already
+// ignored by privilege resolution; purging keeps listings tidy and bounds how
+// long a cached allow can outlive its grant.
+func (s *GrantService) PurgeExpired(ctx context.Context) (int64, error) {
+	n, err := s.repo.DeleteExpired(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 && s.invalidator != nil {
+		s.invalidator.InvalidatePrivilegeCache()
+	}
+	return n, nil
+}
+
+// ReapExpired purges expired grants every interval until ctx is cancelled.
+// Should be called in a background goroutine.
+func (s *GrantService) ReapExpired(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.PurgeExpired(ctx)
+		}
+	}
+}
+
+// List returns grants matching the filter. Requires admin privileges.
+func (s *GrantService) List(ctx context.Context, filter domain.GrantFilter) ([]domain.PrivilegeGrant, int64, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, filter)
+}
+
 // ListForPrincipal returns grants assigned to a specific principal. Requires admin privileges.
 func (s *GrantService) ListForPrincipal(ctx context.Context, principalID string, principalType string, page domain.PageRequest) ([]domain.PrivilegeGrant, int64, error) {
 	if err := requireAdmin(ctx); err != nil {
@@ -100,3 +195,43 @@ func (s *GrantService) ListForSecurable(ctx context.Context, securableType strin
 	}
 	return s.repo.ListForSecurable(ctx, securableType, securableID, page)
 }
+
+// RevokeAll removes every direct grant held by a principal and, when
+// removeMemberships is set, its group memberships, in one transaction. It is
+// used when offboarding a principal. Requires admin privileges.
+func (s *GrantService) RevokeAll(ctx context.Context, principalID string, principalType string, removeMemberships bool) (*domain.RevokedAccess, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if principalType != "user" && principalType != "group" {
+		return nil, domain.ErrValidation("principal_type must be 'user' or 'group'")
+	}
+	if s.principals != nil && principalType == "user" {
+		if _, err := s.principals.GetByID(ctx, principalID); err != nil {
+			return nil, err
+		}
+	}
+	revoked, err := s.repo.RevokeAllForPrincipal(ctx, principalID, principalType, removeMemberships)
+	if err != nil {
+		return nil, err
+	}
+	detail := fmt.Sprintf("Revoked %d grants and %d memberships from %s %s",
+		len(revoked.Grants), len(revoked.Memberships), principalType, principalID)
+	_ = s.audit.Insert(ctx, &domain.AuditEntry{
+		PrincipalName: callerName(ctx),
+		Action:        "REVOKE_ALL",
+		Status:        "ALLOWED",
+		OriginalSQL:   &detail,
+	})
+	if s.invalidator != nil {
+		s.invalidator.InvalidatePrivilegeCache()
+	}
+	if s.events != nil {
+		for _, g := range revoked.Grants {
+			s.events.Publish(ctx, domain.NewEvent(ctx, domain.EventGrantDeleted, map[string]interface{}{
+				"grant_id": g.ID,
+			}))
+		}
+	}
+	return revoked, nil
+}
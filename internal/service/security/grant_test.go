@@ -149,3 +149,61 @@ func TestGrantService_ListForSecurable_RequiresAdmin(t *testing.T) {
 	var accessDenied *domain.AccessDeniedError
 	assert.ErrorAs(t, err, &accessDenied)
 }
+
+func TestGrantService_ListFiltered_RequiresAdmin(t *testing.T) {
+	svc, _ := setupGrantService(t)
+
+	securableType := "table"
+	_, _, err := svc.List(nonAdminCtx(), domain.GrantFilter{SecurableType: &securableType})
+	require.Error(t, err, "non-admin should not list filtered grants")
+	var accessDenied *domain.AccessDeniedError
+	assert.ErrorAs(t, err, &accessDenied)
+}
+
+func TestGrantService_RevokeAll(t *testing.T) {
+	db, _ := internaldb.OpenTestSQLite(t)
+	auditRepo := repository.NewAuditRepo(db)
+	principalRepo := repository.NewPrincipalRepo(db)
+	svc := NewGrantService(repository.NewGrantRepo(db), auditRepo)
+	svc.SetPrincipalRepository(principalRepo)
+	principalSvc := NewPrincipalService(principalRepo, auditRepo)
+	groupSvc := NewGroupService(repository.NewGroupRepo(db), auditRepo)
+
+	p, err := principalSvc.Create(adminCtx(), domain.CreatePrincipalRequest{Name: "leaver", Type: "user"})
+	require.NoError(t, err)
+	g, err := groupSvc.Create(adminCtx(), domain.CreateGroupRequest{Name: "analysts"})
+	require.NoError(t, err)
+	require.NoError(t, groupSvc.AddMember(adminCtx(), domain.AddGroupMemberRequest{GroupID: g.ID, MemberType: "user", MemberID: p.ID}))
+	for _, table := range []string{"1", "2"} {
+		_, err := svc.Grant(adminCtx(), domain.CreateGrantRequest{
+			PrincipalID: p.ID, PrincipalType: "user", Privilege: "SELECT", SecurableType: "table", SecurableID: table,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("non-admin denied", func(t *testing.T) {
+		_, err := svc.RevokeAll(nonAdminCtx(), p.ID, "user", true)
+		var accessDenied *domain.AccessDeniedError
+		assert.ErrorAs(t, err, &accessDenied)
+	})
+
+	t.Run("unknown principal", func(t *testing.T) {
+		_, err := svc.RevokeAll(adminCtx(), "00000000-0000-0000-0000-000000000000", "user", true)
+		var notFound *domain.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("removes all access", func(t *testing.T) {
+		revoked, err := svc.RevokeAll(adminCtx(), p.ID, "user", true)
+		require.NoError(t, err)
+		assert.Len(t, revoked.Grants, 2)
+		assert.Len(t, revoked.Memberships, 1)
+
+		_, total, err := svc.ListForPrincipal(adminCtx(), p.ID, "user", domain.PageRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		_, total, err = groupSvc.ListMembers(adminCtx(), g.ID, domain.PageRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+}
@@ -48,6 +48,9 @@ func (s *GroupService) List(ctx context.Context, page domain.PageRequest) ([]dom
 	if err := requireAdmin(ctx); err != nil {
 		return nil, 0, err
 	}
+	if err := page.ValidateSort(domain.SortByName, domain.SortByCreatedAt, domain.SortByUpdatedAt); err != nil {
+		return nil, 0, err
+	}
 	return s.repo.List(ctx, page)
 }
 
@@ -111,6 +114,29 @@ func (s *GroupService) ListMembers(ctx context.Context, groupID string, page dom
 	return s.repo.ListMembers(ctx, groupID, page)
 }
 
+// SyncMembers sets the exact membership of a group, adding missing members
+// and removing extra ones atomically. Requires admin privileges.
+func (s *GroupService) SyncMembers(ctx context.Context, req domain.SyncGroupMembersRequest) (*domain.GroupMembershipChanges, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByID(ctx, req.GroupID); err != nil {
+		return nil, err
+	}
+	changes, err := s.repo.SyncMembers(ctx, req.GroupID, req.Members)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes.Added) > 0 || len(changes.Removed) > 0 {
+		s.logAudit(ctx, fmt.Sprintf("SYNC_GROUP_MEMBERS(group=%s, added=%d, removed=%d)",
+			req.GroupID, len(changes.Added), len(changes.Removed)))
+	}
+	return changes, nil
+}
+
 func (s *GroupService) logAudit(ctx context.Context, action string) {
 	_ = s.audit.Insert(ctx, &domain.AuditEntry{
 		PrincipalName: callerName(ctx),
@@ -138,6 +138,15 @@ func TestGroupService_List_AdminAllowed(t *testing.T) {
 	assert.NotEmpty(t, groups)
 }
 
+func TestGroupService_List_InvalidSort(t *testing.T) {
+	svc, _ := setupGroupService(t)
+
+	_, _, err := svc.List(adminCtx(), domain.PageRequest{Sort: "member_count"})
+	require.Error(t, err)
+	var validation *domain.ValidationError
+	assert.ErrorAs(t, err, &validation)
+}
+
 func TestGroupService_GetByID_NoAdminRequired(t *testing.T) {
 	svc, _ := setupGroupService(t)
 
@@ -186,3 +195,38 @@ func TestGroupService_ListMembers_RequiresAdmin(t *testing.T) {
 	var accessDenied *domain.AccessDeniedError
 	assert.ErrorAs(t, err, &accessDenied)
 }
+
+func TestGroupService_SyncMembers(t *testing.T) {
+	svc, _ := setupGroupService(t)
+
+	g, err := svc.Create(adminCtx(), domain.CreateGroupRequest{Name: "synced"})
+	require.NoError(t, err)
+	members := []domain.GroupMember{{MemberType: "user", MemberID: "u-1"}}
+
+	t.Run("requires_admin", func(t *testing.T) {
+		_, err := svc.SyncMembers(nonAdminCtx(), domain.SyncGroupMembersRequest{GroupID: g.ID, Members: members})
+		var accessDenied *domain.AccessDeniedError
+		assert.ErrorAs(t, err, &accessDenied)
+	})
+
+	t.Run("rejects_duplicates", func(t *testing.T) {
+		_, err := svc.SyncMembers(adminCtx(), domain.SyncGroupMembersRequest{
+			GroupID: g.ID, Members: append(members, members[0]),
+		})
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("unknown_group", func(t *testing.T) {
+		_, err := svc.SyncMembers(adminCtx(), domain.SyncGroupMembersRequest{GroupID: "missing", Members: members})
+		var notFound *domain.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("adds_members", func(t *testing.T) {
+		changes, err := svc.SyncMembers(adminCtx(), domain.SyncGroupMembersRequest{GroupID: g.ID, Members: members})
+		require.NoError(t, err)
+		assert.Len(t, changes.Added, 1)
+		assert.Empty(t, changes.Removed)
+	})
+}
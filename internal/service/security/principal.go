@@ -11,8 +11,11 @@ import (
 
 // PrincipalService provides principal management operations.
 type PrincipalService struct {
-	repo  domain.PrincipalRepository
-	audit domain.AuditRepository
+	repo   domain.PrincipalRepository
+	audit  domain.AuditRepository
+	events domain.EventPublisher
+	attrs  domain.PrincipalAttributeRepository
+	qdefs  domain.PrincipalQueryDefaultsRepository
 }
 
 // NewPrincipalService creates a new PrincipalService.
@@ -20,6 +23,21 @@ func NewPrincipalService(repo domain.PrincipalRepository, audit domain.AuditRepo
 	return &PrincipalService{repo: repo, audit: audit}
 }
 
+// SetEventPublisher configures where principal change events are published.
+func (s *PrincipalService) SetEventPublisher(events domain.EventPublisher) {
+	s.events = events
+}
+
+// SetAttributeRepository configures storage for principal attributes.
+func (s *PrincipalService) SetAttributeRepository(attrs domain.PrincipalAttributeRepository) {
+	s.attrs = attrs
+}
+
+// SetQueryDefaultsRepository configures storage for principal query defaults.
+func (s *PrincipalService) SetQueryDefaultsRepository(qdefs domain.PrincipalQueryDefaultsRepository) {
+	s.qdefs = qdefs
+}
+
 // Create validates and persists a new principal. Requires admin privileges.
 func (s *PrincipalService) Create(ctx context.Context, req domain.CreatePrincipalRequest) (*domain.Principal, error) {
 	if err := requireAdmin(ctx); err != nil {
@@ -41,6 +59,30 @@ func (s *PrincipalService) Create(ctx context.Context, req domain.CreatePrincipa
 	return result, nil
 }
 
+// Import bulk-creates principals and adds them to existing groups in a single
+// transaction. Principals that already exist are skipped, though they still
+// join any listed groups they are not yet in. Requires admin privileges.
+func (s *PrincipalService) Import(ctx context.Context, rows []domain.PrincipalImportRow) ([]domain.PrincipalImportResult, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidatePrincipalImport(rows); err != nil {
+		return nil, err
+	}
+	results, err := s.repo.Import(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	created := 0
+	for _, r := range results {
+		if r.Status == domain.PrincipalImportCreated {
+			created++
+		}
+	}
+	s.logAudit(ctx, callerName(ctx), fmt.Sprintf("IMPORT_PRINCIPALS(%d created, %d skipped)", created, len(results)-created))
+	return results, nil
+}
+
 // GetByID returns a principal by ID.
 func (s *PrincipalService) GetByID(ctx context.Context, id string) (*domain.Principal, error) {
 	return s.repo.GetByID(ctx, id)
@@ -56,6 +98,9 @@ func (s *PrincipalService) List(ctx context.Context, page domain.PageRequest) ([
 	if err := requireAdmin(ctx); err != nil {
 		return nil, 0, err
 	}
+	if err := page.ValidateSort(domain.SortByName, domain.SortByCreatedAt, domain.SortByUpdatedAt); err != nil {
+		return nil, 0, err
+	}
 	return s.repo.List(ctx, page)
 }
 
@@ -91,9 +136,102 @@ func (s *PrincipalService) SetAdmin(ctx context.Context, id string, isAdmin bool
 	if p != nil {
 		s.logAudit(ctx, callerName(ctx), fmt.Sprintf("%s(%s)", action, p.Name))
 	}
+	if s.events != nil {
+		data := map[string]interface{}{"principal_id": id, "is_admin": isAdmin}
+		if p != nil {
+			data["principal_name"] = p.Name
+		}
+		s.events.Publish(ctx, domain.NewEvent(ctx, domain.EventPrincipalAdminChanged, data))
+	}
 	return nil
 }
 
+// SetAttributes replaces all attributes of a principal and returns them
+// with their new version. Attributes are read by row filters through
+// current_principal_attr. A non-nil expectedRowVersion rejects the write if
+// the attributes changed since they were read. Requires admin privileges.
+func (s *PrincipalService) SetAttributes(ctx context.Context, id string, attrs map[string]string, expectedRowVersion *int64) (map[string]string, int64, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	if s.attrs == nil {
+		return nil, 0, fmt.Errorf("principal attributes are not configured")
+	}
+	if err := domain.ValidatePrincipalAttributes(attrs); err != nil {
+		return nil, 0, err
+	}
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	version, err := s.attrs.Replace(ctx, id, attrs, expectedRowVersion)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.logAudit(ctx, callerName(ctx), fmt.Sprintf("SET_PRINCIPAL_ATTRIBUTES(%s)", p.Name))
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	return attrs, version, nil
+}
+
+// GetAttributes returns the attributes of a principal and their version.
+// Requires admin privileges.
+func (s *PrincipalService) GetAttributes(ctx context.Context, id string) (map[string]string, int64, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	if s.attrs == nil {
+		return nil, 0, fmt.Errorf("principal attributes are not configured")
+	}
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.attrs.GetVersioned(ctx, id)
+}
+
+// SetQueryDefaults replaces the query session defaults of a principal. The
+// query engine applies them to every query the principal runs. A non-nil
+// expectedRowVersion rejects the write if the defaults changed since they
+// were read. Requires admin privileges.
+func (s *PrincipalService) SetQueryDefaults(ctx context.Context, id string, defaults domain.PrincipalQueryDefaults, expectedRowVersion *int64) (domain.PrincipalQueryDefaults, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return domain.PrincipalQueryDefaults{}, err
+	}
+	if s.qdefs == nil {
+		return domain.PrincipalQueryDefaults{}, fmt.Errorf("principal query defaults are not configured")
+	}
+	if err := defaults.Validate(); err != nil {
+		return domain.PrincipalQueryDefaults{}, err
+	}
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.PrincipalQueryDefaults{}, err
+	}
+	version, err := s.qdefs.Set(ctx, id, defaults, expectedRowVersion)
+	if err != nil {
+		return domain.PrincipalQueryDefaults{}, err
+	}
+	defaults.RowVersion = version
+	s.logAudit(ctx, callerName(ctx), fmt.Sprintf("SET_PRINCIPAL_QUERY_DEFAULTS(%s)", p.Name))
+	return defaults, nil
+}
+
+// GetQueryDefaults returns the query session defaults of a principal.
+// Requires admin privileges.
+func (s *PrincipalService) GetQueryDefaults(ctx context.Context, id string) (domain.PrincipalQueryDefaults, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return domain.PrincipalQueryDefaults{}, err
+	}
+	if s.qdefs == nil {
+		return domain.PrincipalQueryDefaults{}, fmt.Errorf("principal query defaults are not configured")
+	}
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return domain.PrincipalQueryDefaults{}, err
+	}
+	return s.qdefs.Get(ctx, id)
+}
+
 // ResolveOrProvision resolves an existing principal by external identity,
 // or creates a new one via JIT provisioning.
 func (s *PrincipalService) ResolveOrProvision(ctx context.Context, req domain.ResolveOrProvisionRequest) (*domain.Principal, error) {
@@ -123,6 +261,9 @@ func (s *PrincipalService) ResolveOrProvision(ctx context.Context, req domain.Re
 			}
 			existing.ExternalID = &req.ExternalID
 			existing.ExternalIssuer = &req.Issuer
+			if err := s.provisionAttributes(ctx, existing.ID, req.Attributes); err != nil {
+				return nil, err
+			}
 			return existing, nil
 		}
 	}
@@ -149,6 +290,9 @@ func (s *PrincipalService) ResolveOrProvision(ctx context.Context, req domain.Re
 		}
 		return nil, fmt.Errorf("provision principal: %w", err)
 	}
+	if err := s.provisionAttributes(ctx, result.ID, req.Attributes); err != nil {
+		return nil, err
+	}
 
 	_ = s.audit.Insert(ctx, &domain.AuditEntry{
 		PrincipalName: result.Name,
@@ -159,6 +303,21 @@ func (s *PrincipalService) ResolveOrProvision(ctx context.Context, req domain.Re
 	return result, nil
 }
 
+// provisionAttributes stores attributes taken from identity-provider claims
+// when a principal is provisioned or bound to its external identity.
+func (s *PrincipalService) provisionAttributes(ctx context.Context, principalID string, attrs map[string]string) error {
+	if len(attrs) == 0 || s.attrs == nil {
+		return nil
+	}
+	if err := domain.ValidatePrincipalAttributes(attrs); err != nil {
+		return fmt.Errorf("provision attributes: %w", err)
+	}
+	if _, err := s.attrs.Replace(ctx, principalID, attrs, nil); err != nil {
+		return fmt.Errorf("provision attributes: %w", err)
+	}
+	return nil
+}
+
 func sanitizeName(name string) string {
 	name = strings.TrimSpace(name)
 	name = strings.ToLower(name)
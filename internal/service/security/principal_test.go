@@ -120,6 +120,108 @@ func TestPrincipalService_SetAdmin_AdminAllowed(t *testing.T) {
 	assert.True(t, found.IsAdmin)
 }
 
+func TestPrincipalService_SetAttributes(t *testing.T) {
+	db, _ := internaldb.OpenTestSQLite(t)
+	attrRepo := repository.NewPrincipalAttributeRepo(db)
+	svc := NewPrincipalService(repository.NewPrincipalRepo(db), repository.NewAuditRepo(db))
+	svc.SetAttributeRepository(attrRepo)
+
+	p, err := svc.Create(adminCtx(), domain.CreatePrincipalRequest{Name: "user1", Type: "user"})
+	require.NoError(t, err)
+
+	_, _, err = svc.SetAttributes(nonAdminCtx(), p.ID, map[string]string{"region": "EU"}, nil)
+	var accessDenied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &accessDenied)
+
+	_, _, err = svc.SetAttributes(adminCtx(), p.ID, map[string]string{"1bad": "x"}, nil)
+	var validation *domain.ValidationError
+	require.ErrorAs(t, err, &validation)
+
+	_, version, err := svc.SetAttributes(adminCtx(), p.ID, map[string]string{"region": "EU", "team": "finance"}, nil)
+	require.NoError(t, err)
+	// Setting attributes replaces the previous set.
+	got, _, err := svc.SetAttributes(adminCtx(), p.ID, map[string]string{"region": "US"}, &version)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"region": "US"}, got)
+
+	// A write based on the earlier version is rejected.
+	_, _, err = svc.SetAttributes(adminCtx(), p.ID, map[string]string{"region": "EU"}, &version)
+	var stale *domain.PreconditionFailedError
+	require.ErrorAs(t, err, &stale)
+
+	stored, storedVersion, err := svc.GetAttributes(adminCtx(), p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"region": "US"}, stored)
+	assert.Equal(t, version+1, storedVersion)
+
+	_, _, err = svc.GetAttributes(nonAdminCtx(), p.ID)
+	require.ErrorAs(t, err, &accessDenied)
+}
+
+func TestPrincipalService_SetQueryDefaults(t *testing.T) {
+	db, _ := internaldb.OpenTestSQLite(t)
+	svc := NewPrincipalService(repository.NewPrincipalRepo(db), repository.NewAuditRepo(db))
+	svc.SetQueryDefaultsRepository(repository.NewPrincipalQueryDefaultsRepo(db))
+
+	p, err := svc.Create(adminCtx(), domain.CreatePrincipalRequest{Name: "user1", Type: "user"})
+	require.NoError(t, err)
+
+	defaults := domain.PrincipalQueryDefaults{DefaultCatalog: "demo", DefaultSchema: "analytics", Timezone: "Europe/Amsterdam"}
+	_, err = svc.SetQueryDefaults(nonAdminCtx(), p.ID, defaults, nil)
+	var accessDenied *domain.AccessDeniedError
+	require.ErrorAs(t, err, &accessDenied)
+
+	_, err = svc.SetQueryDefaults(adminCtx(), p.ID, domain.PrincipalQueryDefaults{Timezone: "Mars/Olympus"}, nil)
+	var validation *domain.ValidationError
+	require.ErrorAs(t, err, &validation)
+
+	_, err = svc.SetQueryDefaults(adminCtx(), p.ID, domain.PrincipalQueryDefaults{DefaultSchema: "a.b"}, nil)
+	require.ErrorAs(t, err, &validation)
+
+	// Version 0 stands for "no defaults stored yet".
+	unset := int64(0)
+	set, err := svc.SetQueryDefaults(adminCtx(), p.ID, defaults, &unset)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), set.RowVersion)
+	stored, err := svc.GetQueryDefaults(adminCtx(), p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, set, stored)
+
+	_, err = svc.SetQueryDefaults(adminCtx(), p.ID, defaults, &unset)
+	var stale *domain.PreconditionFailedError
+	require.ErrorAs(t, err, &stale)
+
+	_, err = svc.GetQueryDefaults(adminCtx(), "missing")
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
+
+func TestPrincipalService_ResolveOrProvision_AttributesFromClaims(t *testing.T) {
+	db, _ := internaldb.OpenTestSQLite(t)
+	attrRepo := repository.NewPrincipalAttributeRepo(db)
+	svc := NewPrincipalService(repository.NewPrincipalRepo(db), repository.NewAuditRepo(db))
+	svc.SetAttributeRepository(attrRepo)
+
+	req := domain.ResolveOrProvisionRequest{
+		Issuer: "https://issuer.example.com", ExternalID: "ext-attrs", DisplayName: "alice",
+		Attributes: map[string]string{"region": "EU"},
+	}
+	p, err := svc.ResolveOrProvision(ctx, req)
+	require.NoError(t, err)
+
+	stored, err := attrRepo.Get(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"region": "EU"}, stored)
+
+	// Later logins resolve the existing principal and leave its attributes alone.
+	req.Attributes = map[string]string{"region": "US"}
+	_, err = svc.ResolveOrProvision(ctx, req)
+	require.NoError(t, err)
+	stored, err = attrRepo.Get(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"region": "EU"}, stored)
+}
+
 func TestPrincipalService_GetByID_NoAdminRequired(t *testing.T) {
 	svc, _ := setupPrincipalService(t)
 
@@ -153,6 +255,93 @@ func TestPrincipalService_List_RequiresAdmin(t *testing.T) {
 	})
 }
 
+func TestPrincipalService_List_InvalidSort(t *testing.T) {
+	svc, _ := setupPrincipalService(t)
+
+	_, _, err := svc.List(adminCtx(), domain.PageRequest{Sort: "type"})
+	require.Error(t, err)
+	var validation *domain.ValidationError
+	assert.ErrorAs(t, err, &validation)
+}
+
+func TestPrincipalService_Import(t *testing.T) {
+	db, _ := internaldb.OpenTestSQLite(t)
+	principalRepo := repository.NewPrincipalRepo(db)
+	groupRepo := repository.NewGroupRepo(db)
+	svc := NewPrincipalService(principalRepo, repository.NewAuditRepo(db))
+
+	analysts, err := groupRepo.Create(adminCtx(), &domain.Group{Name: "analysts"})
+	require.NoError(t, err)
+	existing, err := svc.Create(adminCtx(), domain.CreatePrincipalRequest{Name: "alice", Type: "user"})
+	require.NoError(t, err)
+
+	t.Run("mix_of_new_and_existing", func(t *testing.T) {
+		results, err := svc.Import(adminCtx(), []domain.PrincipalImportRow{
+			{Name: "alice", Groups: []string{"analysts"}},
+			{Name: "etl-bot", Type: "service_principal", Groups: []string{"analysts"}},
+			{Name: "bob", IsAdmin: true},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, domain.PrincipalImportResult{
+			Name: "alice", PrincipalID: existing.ID, Status: domain.PrincipalImportSkipped, GroupsAdded: []string{"analysts"},
+		}, results[0])
+		assert.Equal(t, domain.PrincipalImportCreated, results[1].Status)
+		assert.Equal(t, []string{"analysts"}, results[1].GroupsAdded)
+		assert.Equal(t, domain.PrincipalImportCreated, results[2].Status)
+		assert.Empty(t, results[2].GroupsAdded)
+
+		bot, err := principalRepo.GetByName(adminCtx(), "etl-bot")
+		require.NoError(t, err)
+		assert.Equal(t, "service_principal", bot.Type)
+		bob, err := principalRepo.GetByName(adminCtx(), "bob")
+		require.NoError(t, err)
+		assert.True(t, bob.IsAdmin)
+		members, total, err := groupRepo.ListMembers(adminCtx(), analysts.ID, domain.PageRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.ElementsMatch(t, []string{"alice", "etl-bot"}, []string{members[0].MemberName, members[1].MemberName})
+	})
+
+	t.Run("rerun_is_idempotent", func(t *testing.T) {
+		results, err := svc.Import(adminCtx(), []domain.PrincipalImportRow{
+			{Name: "alice", Groups: []string{"analysts"}},
+			{Name: "bob", IsAdmin: true},
+		})
+		require.NoError(t, err)
+		for _, r := range results {
+			assert.Equal(t, domain.PrincipalImportSkipped, r.Status, r.Name)
+			assert.Empty(t, r.GroupsAdded, r.Name)
+		}
+	})
+
+	t.Run("unknown_group_rolls_back", func(t *testing.T) {
+		_, err := svc.Import(adminCtx(), []domain.PrincipalImportRow{
+			{Name: "carol"},
+			{Name: "dave", Groups: []string{"nonexistent"}},
+		})
+		var validation *domain.ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Contains(t, err.Error(), `row 2: group "nonexistent" does not exist`)
+
+		_, err = principalRepo.GetByName(adminCtx(), "carol")
+		var notFound *domain.NotFoundError
+		assert.ErrorAs(t, err, &notFound, "rows before the failure are rolled back")
+	})
+
+	t.Run("invalid_type", func(t *testing.T) {
+		_, err := svc.Import(adminCtx(), []domain.PrincipalImportRow{{Name: "erin", Type: "robot"}})
+		var validation *domain.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("non_admin_denied", func(t *testing.T) {
+		_, err := svc.Import(nonAdminCtx(), []domain.PrincipalImportRow{{Name: "frank"}})
+		var accessDenied *domain.AccessDeniedError
+		assert.ErrorAs(t, err, &accessDenied)
+	})
+}
+
 func TestPrincipalService_ResolveOrProvision_Existing(t *testing.T) {
 	svc, repo := setupPrincipalService(t)
 
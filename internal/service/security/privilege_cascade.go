@@ -0,0 +1,207 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"duck-demo/internal/domain"
+)
+
+// privUsageLegacy is the pre-USE_SCHEMA spelling of the schema usage
+// privilege. Existing grants still store it.
+const privUsageLegacy = "USAGE"
+
+// impliedBy is the privilege cascade matrix. For each privilege it lists the
+// other privileges whose grant also satisfies it. Grants are honored on the
+// securable itself and on every ancestor (table -> schema -> catalog), so a
+// schema-level SELECT satisfies SELECT on each table in the schema and a
+// catalog-level ALL_PRIVILEGES satisfies every check in the catalog.
+//
+// Privileges absent from the matrix are satisfied by themselves and
+// ALL_PRIVILEGES only. USE_CATALOG implies nothing: it does not open schemas
+// or tables on its own.
+var impliedBy = map[string][]string{
+	domain.PrivSelect:    {domain.PrivAllPrivileges},
+	domain.PrivInsert:    {domain.PrivModify, domain.PrivAllPrivileges},
+	domain.PrivUpdate:    {domain.PrivModify, domain.PrivAllPrivileges},
+	domain.PrivDelete:    {domain.PrivModify, domain.PrivAllPrivileges},
+	domain.PrivUseSchema: {privUsageLegacy, domain.PrivAllPrivileges},
+	privUsageLegacy:      {domain.PrivUseSchema, domain.PrivAllPrivileges},
+}
+
+// satisfyingPrivileges returns the privileges whose grant satisfies a check
+// for privilege, starting with privilege itself.
+func satisfyingPrivileges(privilege string) []string {
+	if privilege == domain.PrivAllPrivileges {
+		return []string{privilege}
+	}
+	implied, ok := impliedBy[privilege]
+	if !ok {
+		implied = []string{domain.PrivAllPrivileges}
+	}
+	return append([]string{privilege}, implied...)
+}
+
+// SetCatalogRegistrationRepository configures catalog registration lookup so
+// catalog-level grants recorded against a registered catalog's ID are honored
+// alongside grants on the CatalogID sentinel.
+func (s *AuthorizationService) SetCatalogRegistrationRepository(repo domain.CatalogRegistrationRepository) {
+	s.catalogRegs = repo
+}
+
+// catalogSecurableIDs returns the securable IDs a catalog-level grant for the
+// referenced catalog may be recorded against. ref is a catalog name or
+// registration ID; empty or the sentinel means the default catalog. Only the
+// default catalog answers to the sentinel, and a catalog that is not
+// registered has no IDs, so no catalog-level grant reaches it.
+func (s *AuthorizationService) catalogSecurableIDs(ctx context.Context, ref string) []string {
+	isDefaultRef := ref == "" || ref == domain.CatalogID
+	if s.catalogRegs == nil {
+		if isDefaultRef {
+			return []string{domain.CatalogID}
+		}
+		return nil
+	}
+
+	var (
+		reg *domain.CatalogRegistration
+		err error
+	)
+	if isDefaultRef {
+		reg, err = s.catalogRegs.GetDefault(ctx)
+	} else if reg, err = s.catalogRegs.GetByName(ctx, ref); err != nil {
+		reg, err = s.catalogRegs.GetByID(ctx, ref)
+	}
+	if err != nil || reg == nil {
+		if isDefaultRef {
+			return []string{domain.CatalogID}
+		}
+		return nil
+	}
+	if !reg.IsDefault {
+		return []string{reg.ID}
+	}
+	ids := []string{domain.CatalogID}
+	if reg.ID != domain.CatalogID {
+		ids = append(ids, reg.ID)
+	}
+	return ids
+}
+
+// hasCatalogGrant checks for a catalog-level grant on the referenced catalog.
+func (s *AuthorizationService) hasCatalogGrant(ctx context.Context, principalID string, groupIDs []string, catalogRef string, privilege string) (bool, error) {
+	for _, id := range s.catalogSecurableIDs(ctx, catalogRef) {
+		ok, err := s.hasGrant(ctx, principalID, groupIDs, domain.SecurableCatalog, id, privilege)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// SetCatalogIntrospection configures how the metastore of a registered
+// catalog is reached, so catalog-scoped table and schema IDs resolve in the
+// catalog that owns them.
+func (s *AuthorizationService) SetCatalogIntrospection(lookup func(ctx context.Context, catalogName string) (domain.IntrospectionRepository, error)) {
+	s.catalogIntrospection = lookup
+}
+
+// SetVolumeRepository configures volume lookup, so catalog-level grants
+// cascade into a volume from the catalog it lives in.
+func (s *AuthorizationService) SetVolumeRepository(repo domain.VolumeRepository) {
+	s.volumes = repo
+}
+
+// catalogScope returns the registration ID that objects of the named catalog
+// are scoped to, or "" when their IDs are used unscoped: in the default
+// catalog, or when catalog registrations are not configured.
+func (s *AuthorizationService) catalogScope(ctx context.Context, catalogName string) string {
+	if s.catalogRegs == nil || catalogName == "" {
+		return ""
+	}
+	reg, err := s.catalogRegs.GetByName(ctx, catalogName)
+	if err != nil || reg.IsDefault {
+		return ""
+	}
+	return reg.ID
+}
+
+// scopeID qualifies id with catalogID unless either is empty.
+func scopeID(catalogID, id string) string {
+	if catalogID == "" || id == "" {
+		return id
+	}
+	return domain.CatalogScopedID(catalogID, id)
+}
+
+// splitScopedID splits a catalog-scoped table or schema ID. Synthetic view
+// IDs embed a view name that may contain the separator, so they are only
+// scoped as a whole.
+func splitScopedID(id string) (catalogID, localID string, ok bool) {
+	if strings.HasPrefix(id, syntheticViewIDPrefix) {
+		return "", "", false
+	}
+	return domain.SplitCatalogScopedID(id)
+}
+
+// catalogOfID returns the catalog a scoped ID belongs to, or "" for the
+// default catalog.
+func catalogOfID(id string) string {
+	catalogID, _, _ := splitScopedID(id)
+	return catalogID
+}
+
+// introspectionFor returns the metastore of the catalog registered under
+// catalogID.
+func (s *AuthorizationService) introspectionFor(ctx context.Context, catalogID string) (domain.IntrospectionRepository, error) {
+	if s.catalogRegs == nil || s.catalogIntrospection == nil {
+		return nil, fmt.Errorf("catalog introspection is not configured")
+	}
+	reg, err := s.catalogRegs.GetByID(ctx, catalogID)
+	if err != nil {
+		return nil, fmt.Errorf("lookup catalog %q: %w", catalogID, err)
+	}
+	return s.catalogIntrospection(ctx, reg.Name)
+}
+
+// schemaIDInCatalog resolves a schema by name in the named catalog and
+// returns its ID, scoped when the catalog is not the default.
+func (s *AuthorizationService) schemaIDInCatalog(ctx context.Context, catalogName, schemaName string) (string, bool) {
+	intro := s.introspection
+	scope := s.catalogScope(ctx, catalogName)
+	if scope != "" {
+		var err error
+		if intro, err = s.introspectionFor(ctx, scope); err != nil {
+			return "", false
+		}
+	}
+	sch, err := intro.GetSchemaByName(ctx, schemaName)
+	if err != nil {
+		return "", false
+	}
+	return scopeID(scope, sch.ID), true
+}
+
+// owningCatalog returns the catalog whose catalog-level grants cascade into
+// a securable that is not a table: the catalog a scoped schema ID names, a
+// volume's catalog, or "" for the default catalog. External locations,
+// storage credentials and compute endpoints belong to no catalog and
+// inherit from the default one.
+func (s *AuthorizationService) owningCatalog(ctx context.Context, securableType, securableID string) string {
+	switch securableType {
+	case domain.SecurableSchema:
+		return catalogOfID(securableID)
+	case domain.SecurableVolume:
+		if s.volumes == nil {
+			return ""
+		}
+		vol, err := s.volumes.GetByID(ctx, securableID)
+		if err != nil {
+			return ""
+		}
+		return vol.CatalogName
+	default:
+		return ""
+	}
+}
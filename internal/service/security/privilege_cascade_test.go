@@ -0,0 +1,346 @@
+//go:build integration
+
+package security
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internaldb "duck-demo/internal/db"
+	dbstore "duck-demo/internal/db/dbstore"
+	"duck-demo/internal/db/repository"
+	"duck-demo/internal/domain"
+	"duck-demo/internal/testutil"
+)
+
+func TestSatisfyingPrivileges(t *testing.T) {
+	tests := []struct {
+		privilege string
+		want      []string
+	}{
+		{PrivSelect, []string{PrivSelect, PrivAllPrivileges}},
+		{PrivInsert, []string{PrivInsert, domain.PrivModify, PrivAllPrivileges}},
+		{domain.PrivUseSchema, []string{domain.PrivUseSchema, "USAGE", PrivAllPrivileges}},
+		{"USAGE", []string{"USAGE", domain.PrivUseSchema, PrivAllPrivileges}},
+		{domain.PrivUseCatalog, []string{domain.PrivUseCatalog, PrivAllPrivileges}},
+		{domain.PrivCreateTable, []string{domain.PrivCreateTable, PrivAllPrivileges}},
+		{PrivAllPrivileges, []string{PrivAllPrivileges}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.privilege, func(t *testing.T) {
+			assert.Equal(t, tt.want, satisfyingPrivileges(tt.privilege))
+		})
+	}
+}
+
+// cascadeGrant is a grant seeded for the cascade matrix. A securableID of
+// "default" or "other" is replaced by the ID of that registered catalog.
+type cascadeGrant struct {
+	securableType string
+	securableID   string
+	privilege     string
+}
+
+func TestPrivilegeCascade(t *testing.T) {
+	catalogGrant := func(id, priv string) cascadeGrant { return cascadeGrant{SecurableCatalog, id, priv} }
+	schemaGrant := func(priv string) cascadeGrant { return cascadeGrant{SecurableSchema, "0", priv} }
+	tableGrant := func(priv string) cascadeGrant { return cascadeGrant{SecurableTable, "1", priv} }
+
+	tests := []struct {
+		name          string
+		grants        []cascadeGrant
+		securableType string
+		securableID   string
+		privilege     string
+		want          bool
+	}{
+		// Catalog -> schema -> table.
+		{"catalog ALL_PRIVILEGES on sentinel reaches table SELECT",
+			[]cascadeGrant{catalogGrant(CatalogID, PrivAllPrivileges)}, SecurableTable, "1", PrivSelect, true},
+		{"catalog ALL_PRIVILEGES on registered catalog reaches table SELECT",
+			[]cascadeGrant{catalogGrant("default", PrivAllPrivileges)}, SecurableTable, "1", PrivSelect, true},
+		{"catalog ALL_PRIVILEGES on registered catalog reaches table INSERT",
+			[]cascadeGrant{catalogGrant("default", PrivAllPrivileges)}, SecurableTable, "1", PrivInsert, true},
+		{"catalog ALL_PRIVILEGES on registered catalog reaches schema CREATE_TABLE",
+			[]cascadeGrant{catalogGrant("default", PrivAllPrivileges)}, SecurableSchema, "0", domain.PrivCreateTable, true},
+		{"catalog ALL_PRIVILEGES on registered catalog satisfies catalog check by name",
+			[]cascadeGrant{catalogGrant("default", PrivAllPrivileges)}, SecurableCatalog, "lake", domain.PrivCreateSchema, true},
+		{"catalog ALL_PRIVILEGES on another catalog does not reach default catalog tables",
+			[]cascadeGrant{catalogGrant("other", PrivAllPrivileges)}, SecurableTable, "1", PrivSelect, false},
+		{"catalog ALL_PRIVILEGES on another catalog satisfies that catalog",
+			[]cascadeGrant{catalogGrant("other", PrivAllPrivileges)}, SecurableCatalog, "archive", domain.PrivCreateSchema, true},
+		{"catalog SELECT with catalog USE_SCHEMA reaches table SELECT",
+			[]cascadeGrant{catalogGrant(CatalogID, domain.PrivUseSchema), catalogGrant(CatalogID, PrivSelect)}, SecurableTable, "1", PrivSelect, true},
+
+		// Schema -> table.
+		{"schema SELECT with USE_SCHEMA reaches table SELECT",
+			[]cascadeGrant{schemaGrant(domain.PrivUseSchema), schemaGrant(PrivSelect)}, SecurableTable, "1", PrivSelect, true},
+		{"schema SELECT does not imply table INSERT",
+			[]cascadeGrant{schemaGrant(domain.PrivUseSchema), schemaGrant(PrivSelect)}, SecurableTable, "1", PrivInsert, false},
+		{"schema ALL_PRIVILEGES reaches table INSERT",
+			[]cascadeGrant{schemaGrant(PrivAllPrivileges)}, SecurableTable, "1", PrivInsert, true},
+		{"schema MODIFY implies table DELETE",
+			[]cascadeGrant{schemaGrant(domain.PrivUseSchema), schemaGrant(domain.PrivModify)}, SecurableTable, "1", domain.PrivDelete, true},
+		{"table MODIFY does not imply SELECT",
+			[]cascadeGrant{schemaGrant(domain.PrivUseSchema), tableGrant(domain.PrivModify)}, SecurableTable, "1", PrivSelect, false},
+
+		// USE_SCHEMA gate and USE_CATALOG.
+		{"table SELECT without USE_SCHEMA is denied",
+			[]cascadeGrant{tableGrant(PrivSelect)}, SecurableTable, "1", PrivSelect, false},
+		{"legacy USAGE satisfies the USE_SCHEMA gate",
+			[]cascadeGrant{schemaGrant("USAGE"), tableGrant(PrivSelect)}, SecurableTable, "1", PrivSelect, true},
+		{"USE_SCHEMA check accepts a legacy USAGE grant",
+			[]cascadeGrant{schemaGrant("USAGE")}, SecurableSchema, "0", domain.PrivUseSchema, true},
+		{"catalog USE_SCHEMA satisfies the gate for every schema",
+			[]cascadeGrant{catalogGrant("default", domain.PrivUseSchema), tableGrant(PrivSelect)}, SecurableTable, "1", PrivSelect, true},
+		{"USE_CATALOG does not satisfy the USE_SCHEMA gate",
+			[]cascadeGrant{catalogGrant("default", domain.PrivUseCatalog), tableGrant(PrivSelect)}, SecurableTable, "1", PrivSelect, false},
+		{"USE_CATALOG does not grant SELECT",
+			[]cascadeGrant{catalogGrant("default", domain.PrivUseCatalog), schemaGrant(domain.PrivUseSchema)}, SecurableTable, "1", PrivSelect, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, q, ctx := setupTestService(t)
+
+			catalogIDs := map[string]string{}
+			for _, reg := range []domain.CatalogRegistration{
+				{Name: "lake", IsDefault: true},
+				{Name: "archive"},
+			} {
+				reg.MetastoreType = domain.MetastoreTypeSQLite
+				reg.DSN = reg.Name + ".sqlite"
+				reg.DataPath = "/data/" + reg.Name
+				reg.Status = domain.CatalogStatusActive
+				created, err := svc.catalogRegs.Create(ctx, &reg)
+				require.NoError(t, err)
+				if reg.IsDefault {
+					catalogIDs["default"] = created.ID
+					require.NoError(t, svc.catalogRegs.SetDefault(ctx, created.ID))
+				} else {
+					catalogIDs["other"] = created.ID
+				}
+			}
+
+			user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+				Name: "cascade", Type: "user", IsAdmin: 0,
+			})
+			require.NoError(t, err)
+			for _, g := range tt.grants {
+				securableID := g.securableID
+				if id, ok := catalogIDs[securableID]; ok {
+					securableID = id
+				}
+				_, err := q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+					ID: uuid.New().String(), PrincipalID: user.ID, PrincipalType: "user",
+					SecurableType: g.securableType, SecurableID: securableID,
+					Privilege: g.privilege,
+				})
+				require.NoError(t, err)
+			}
+
+			ok, err := svc.CheckPrivilege(ctx, "cascade", tt.securableType, tt.securableID, tt.privilege)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+// setupTwoCatalogs registers the default catalog "lake", backed by the
+// service's own metastore, and a second catalog "archive" whose metastore
+// holds a main schema and titanic table with the same DuckLake IDs. It
+// returns the registration IDs keyed "default" and "other".
+func setupTwoCatalogs(t *testing.T, svc *AuthorizationService) map[string]string {
+	t.Helper()
+
+	archiveDB, _ := internaldb.OpenTestSQLite(t)
+	_, err := archiveDB.ExecContext(ctx, `
+		CREATE TABLE ducklake_schema (
+			schema_id INTEGER PRIMARY KEY, schema_uuid TEXT, begin_snapshot INTEGER,
+			end_snapshot INTEGER, schema_name TEXT, path TEXT, path_is_relative INTEGER
+		);
+		CREATE TABLE ducklake_table (
+			table_id INTEGER, table_uuid TEXT, begin_snapshot INTEGER, end_snapshot INTEGER,
+			schema_id INTEGER, table_name TEXT, path TEXT, path_is_relative INTEGER
+		);
+		INSERT INTO ducklake_schema (schema_id, schema_name, begin_snapshot) VALUES (0, 'main', 0);
+		INSERT INTO ducklake_table (table_id, table_name, schema_id, begin_snapshot) VALUES (1, 'titanic', 0, 1);
+	`)
+	require.NoError(t, err)
+
+	metastores := map[string]*repository.IntrospectionRepo{
+		"lake":    svc.introspection.(*repository.IntrospectionRepo),
+		"archive": repository.NewIntrospectionRepo(archiveDB),
+	}
+	svc.SetCatalogIntrospection(func(_ context.Context, catalogName string) (domain.IntrospectionRepository, error) {
+		repo, ok := metastores[catalogName]
+		if !ok {
+			return nil, domain.ErrNotFound("catalog %q not found", catalogName)
+		}
+		return repo, nil
+	})
+	svc.SetCatalogTableLookup(func(ctx context.Context, catalogName, schemaName, tableName string) (*domain.TableDetail, error) {
+		repo, ok := metastores[catalogName]
+		if !ok {
+			return nil, domain.ErrNotFound("catalog %q not found", catalogName)
+		}
+		tbl, err := repo.GetTableBySchemaAndName(ctx, schemaName, tableName)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.TableDetail{TableID: tbl.ID, Name: tbl.Name, SchemaName: schemaName, CatalogName: catalogName, TableType: domain.TableTypeManaged}, nil
+	})
+
+	ids := map[string]string{}
+	for _, reg := range []domain.CatalogRegistration{
+		{Name: "lake", IsDefault: true},
+		{Name: "archive"},
+	} {
+		reg.MetastoreType = domain.MetastoreTypeSQLite
+		reg.DSN = reg.Name + ".sqlite"
+		reg.DataPath = "/data/" + reg.Name
+		reg.Status = domain.CatalogStatusActive
+		created, err := svc.catalogRegs.Create(ctx, &reg)
+		require.NoError(t, err)
+		if reg.IsDefault {
+			ids["default"] = created.ID
+			require.NoError(t, svc.catalogRegs.SetDefault(ctx, created.ID))
+		} else {
+			ids["other"] = created.ID
+		}
+	}
+	return ids
+}
+
+func TestPrivilegeCascade_PerCatalog(t *testing.T) {
+	// Securable IDs of the form "other/<id>" are scoped to the archive
+	// catalog; bare IDs belong to the default catalog.
+	catalogGrant := func(id, priv string) cascadeGrant { return cascadeGrant{SecurableCatalog, id, priv} }
+	schemaGrant := func(id, priv string) cascadeGrant { return cascadeGrant{SecurableSchema, id, priv} }
+	tableGrant := func(id, priv string) cascadeGrant { return cascadeGrant{SecurableTable, id, priv} }
+
+	tests := []struct {
+		name   string
+		grants []cascadeGrant
+		table  string
+		want   bool
+	}{
+		{"grant on default catalog does not reach another catalog's table",
+			[]cascadeGrant{catalogGrant("default", PrivAllPrivileges)}, "archive.main.titanic", false},
+		{"sentinel grant does not reach another catalog's table",
+			[]cascadeGrant{catalogGrant(CatalogID, PrivAllPrivileges)}, "archive.main.titanic", false},
+		{"grant on a catalog cascades to its tables",
+			[]cascadeGrant{catalogGrant("other", PrivAllPrivileges)}, "archive.main.titanic", true},
+		{"grant on a catalog does not reach the default catalog's table with the same ID",
+			[]cascadeGrant{catalogGrant("other", PrivAllPrivileges)}, "lake.main.titanic", false},
+		{"grant on a catalog does not reach unqualified default catalog tables",
+			[]cascadeGrant{catalogGrant("other", PrivAllPrivileges)}, "titanic", false},
+		{"table grants in the default catalog do not reach another catalog",
+			[]cascadeGrant{schemaGrant("0", domain.PrivUseSchema), tableGrant("1", PrivSelect)}, "archive.main.titanic", false},
+		{"table grants in the default catalog still apply there",
+			[]cascadeGrant{schemaGrant("0", domain.PrivUseSchema), tableGrant("1", PrivSelect)}, "lake.main.titanic", true},
+		{"scoped schema and table grants reach the other catalog's table",
+			[]cascadeGrant{schemaGrant("other/0", domain.PrivUseSchema), tableGrant("other/1", PrivSelect)}, "archive.main.titanic", true},
+		{"scoped schema SELECT cascades within its catalog",
+			[]cascadeGrant{schemaGrant("other/0", domain.PrivUseSchema), schemaGrant("other/0", PrivSelect)}, "archive.main.titanic", true},
+		{"scoped grants do not reach the default catalog",
+			[]cascadeGrant{schemaGrant("other/0", domain.PrivUseSchema), tableGrant("other/1", PrivSelect)}, "lake.main.titanic", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, q, ctx := setupTestService(t)
+			catalogIDs := setupTwoCatalogs(t, svc)
+			svc.SetDefaultCatalogLookup(func(context.Context) (string, error) { return "lake", nil })
+
+			user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+				Name: "cascade", Type: "user", IsAdmin: 0,
+			})
+			require.NoError(t, err)
+			for _, g := range tt.grants {
+				securableID := g.securableID
+				if id, ok := catalogIDs[securableID]; ok {
+					securableID = id
+				} else if local, ok := strings.CutPrefix(securableID, "other/"); ok {
+					securableID = domain.CatalogScopedID(catalogIDs["other"], local)
+				}
+				_, err := q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+					ID: uuid.New().String(), PrincipalID: user.ID, PrincipalType: "user",
+					SecurableType: g.securableType, SecurableID: securableID,
+					Privilege: g.privilege,
+				})
+				require.NoError(t, err)
+			}
+
+			tableID, _, _, err := svc.LookupTableID(ctx, tt.table)
+			require.NoError(t, err)
+			ok, err := svc.CheckPrivilege(ctx, "cascade", SecurableTable, tableID, PrivSelect)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestLookupTableID_ScopesNonDefaultCatalogs(t *testing.T) {
+	svc, _, ctx := setupTestService(t)
+	catalogIDs := setupTwoCatalogs(t, svc)
+
+	lakeID, _, _, err := svc.LookupTableID(ctx, "lake.main.titanic")
+	require.NoError(t, err)
+	assert.Equal(t, "1", lakeID)
+
+	archiveID, _, _, err := svc.LookupTableID(ctx, "archive.main.titanic")
+	require.NoError(t, err)
+	assert.Equal(t, domain.CatalogScopedID(catalogIDs["other"], "1"), archiveID)
+
+	schemaID, ok := svc.tableSchemaID(ctx, archiveID)
+	require.True(t, ok)
+	assert.Equal(t, domain.CatalogScopedID(catalogIDs["other"], "0"), schemaID)
+}
+
+func TestCatalogScopedPrivilege_VolumeInheritsFromItsCatalog(t *testing.T) {
+	tests := []struct {
+		name         string
+		grantCatalog string
+		want         bool
+	}{
+		{"grant on the volume's catalog", "other", true},
+		{"grant on the default catalog", "default", false},
+		{"grant on the sentinel", CatalogID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, q, ctx := setupTestService(t)
+			catalogIDs := setupTwoCatalogs(t, svc)
+			svc.SetVolumeRepository(&testutil.MockVolumeRepo{
+				GetByIDFn: func(_ context.Context, id string) (*domain.Volume, error) {
+					return &domain.Volume{ID: id, Name: "raw", SchemaName: "main", CatalogName: "archive"}, nil
+				},
+			})
+
+			user, err := q.CreatePrincipal(ctx, dbstore.CreatePrincipalParams{ID: uuid.New().String(),
+				Name: "volume_user", Type: "user", IsAdmin: 0,
+			})
+			require.NoError(t, err)
+			securableID := tt.grantCatalog
+			if id, ok := catalogIDs[securableID]; ok {
+				securableID = id
+			}
+			_, err = q.GrantPrivilege(ctx, dbstore.GrantPrivilegeParams{
+				ID: uuid.New().String(), PrincipalID: user.ID, PrincipalType: "user",
+				SecurableType: SecurableCatalog, SecurableID: securableID,
+				Privilege: domain.PrivReadVolume,
+			})
+			require.NoError(t, err)
+
+			ok, err := svc.CheckPrivilege(ctx, "volume_user", SecurableVolume, "vol-1", domain.PrivReadVolume)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
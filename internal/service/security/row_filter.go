@@ -2,15 +2,28 @@ package security
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
+	"duck-demo/internal/ddl"
 	"duck-demo/internal/domain"
 	"duck-demo/internal/duckdbsql"
+	"duck-demo/internal/sqlrewrite"
 )
 
 // RowFilterService provides row-level security filter operations.
 type RowFilterService struct {
 	repo  domain.RowFilterRepository
 	audit domain.AuditRepository
+
+	// Optional: required only by SimulatePolicy.
+	auth           domain.AuthorizationService
+	principals     domain.PrincipalRepository
+	principalAttrs domain.PrincipalAttributeRepository
+	engine         domain.QueryEngine
 }
 
 // NewRowFilterService creates a new RowFilterService.
@@ -18,6 +31,16 @@ func NewRowFilterService(repo domain.RowFilterRepository, audit domain.AuditRepo
 	return &RowFilterService{repo: repo, audit: audit}
 }
 
+// SetSimulationDeps wires the dependencies SimulatePolicy needs to read a
+// table as another principal. attrs may be nil, in which case candidate
+// filters see no principal attributes.
+func (s *RowFilterService) SetSimulationDeps(auth domain.AuthorizationService, principals domain.PrincipalRepository, attrs domain.PrincipalAttributeRepository, engine domain.QueryEngine) {
+	s.auth = auth
+	s.principals = principals
+	s.principalAttrs = attrs
+	s.engine = engine
+}
+
 // Create validates and persists a new row filter. Requires admin privileges.
 // The filter_sql expression is validated as syntactically correct SQL before persisting.
 func (s *RowFilterService) Create(ctx context.Context, req domain.CreateRowFilterRequest) (*domain.RowFilter, error) {
@@ -32,11 +55,17 @@ func (s *RowFilterService) Create(ctx context.Context, req domain.CreateRowFilte
 	if _, err := duckdbsql.ParseExpr(req.FilterSQL); err != nil {
 		return nil, domain.ErrValidation("filter_sql is not valid SQL: %v", err)
 	}
+	if _, err := sqlrewrite.ResolvePrincipalAttrs(req.FilterSQL, nil); err != nil {
+		return nil, domain.ErrValidation("filter_sql: %v", err)
+	}
 	f := &domain.RowFilter{
 		TableID:     req.TableID,
 		FilterSQL:   req.FilterSQL,
 		Description: req.Description,
 	}
+	if req.TagID != "" {
+		f.TagID = &req.TagID
+	}
 	result, err := s.repo.Create(ctx, f)
 	if err != nil {
 		return nil, err
@@ -57,6 +86,15 @@ func (s *RowFilterService) GetForTable(ctx context.Context, tableID string, page
 	return s.repo.GetForTable(ctx, tableID, page)
 }
 
+// GetForTag returns a paginated list of the tag-based row filters for a tag.
+// Requires admin privileges.
+func (s *RowFilterService) GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.RowFilter, int64, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetForTag(ctx, tagID, page)
+}
+
 // Delete removes a row filter by ID. Requires admin privileges.
 func (s *RowFilterService) Delete(ctx context.Context, id string) error {
 	if err := requireAdmin(ctx); err != nil {
@@ -128,3 +166,171 @@ func (s *RowFilterService) ListBindings(ctx context.Context, filterID string) ([
 	}
 	return s.repo.ListBindings(ctx, filterID)
 }
+
+// SimulatePolicy samples up to req.Limit rows of a table as req.Principal
+// would read them if the candidate row filter and/or column mask were bound
+// to them, on top of the policies already bound. Nothing is persisted. The
+// candidate filter widens the principal's view the way a new binding would,
+// and the candidate mask replaces any mask already on its column. Requires
+// admin privileges, since the rows are returned.
+func (s *RowFilterService) SimulatePolicy(ctx context.Context, req domain.PolicySimulationRequest) (*domain.PolicySimulation, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if s.auth == nil || s.principals == nil || s.engine == nil {
+		return nil, fmt.Errorf("policy simulation is not configured")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = domain.DefaultPolicySimulationLimit
+	}
+	if req.RowFilter != "" {
+		if _, err := duckdbsql.ParseExpr(req.RowFilter); err != nil {
+			return nil, domain.ErrValidation("filter_sql is not valid SQL: %v", err)
+		}
+		if _, err := sqlrewrite.ResolvePrincipalAttrs(req.RowFilter, nil); err != nil {
+			return nil, domain.ErrValidation("filter_sql: %v", err)
+		}
+	}
+	if req.MaskExpression != "" {
+		if _, err := duckdbsql.ParseExpr(req.MaskExpression); err != nil {
+			return nil, domain.ErrValidation("mask_expression must be a valid SQL expression: %v", err)
+		}
+	}
+
+	principal, err := s.principals.GetByName(ctx, req.Principal)
+	if err != nil {
+		return nil, err
+	}
+	tableID, _, _, err := s.auth.LookupTableID(ctx, req.Table)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.auth.CheckPrivilege(ctx, req.Principal, domain.SecurableTable, tableID, domain.PrivSelect)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, domain.ErrValidation("principal %q lacks SELECT on table %s", req.Principal, req.Table)
+	}
+
+	// Admins bypass row filters and masks, so a candidate would not apply
+	// to them either.
+	var filters []string
+	var masks map[string]string
+	if !principal.IsAdmin {
+		if filters, err = s.auth.GetEffectiveRowFilters(ctx, req.Principal, tableID); err != nil {
+			return nil, fmt.Errorf("row filter: %w", err)
+		}
+		if req.RowFilter != "" {
+			candidate, err := s.resolveCandidateFilter(ctx, principal.ID, req.RowFilter)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, candidate)
+		}
+		if masks, err = s.auth.GetEffectiveColumnMasks(ctx, req.Principal, tableID); err != nil {
+			return nil, fmt.Errorf("column masks: %w", err)
+		}
+		if req.ColumnName != "" {
+			if masks == nil {
+				masks = map[string]string{}
+			}
+			masks[strings.ToLower(req.ColumnName)] = req.MaskExpression
+		}
+	}
+
+	parts := strings.Split(req.Table, ".")
+	tableName := parts[len(parts)-1]
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		quoted[i] = ddl.QuoteIdentifier(part)
+	}
+	// The caller is an admin, so the engine applies no masks or filters of
+	// its own; the target's are spelled out in the query instead.
+	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", strings.Join(quoted, "."), limit)
+	if len(filters) > 0 {
+		if query, err = sqlrewrite.InjectMultipleRowFilters(query, tableName, filters); err != nil {
+			return nil, fmt.Errorf("inject row filter: %w", err)
+		}
+	}
+	if len(masks) > 0 {
+		colNames, err := s.auth.GetTableColumnNames(ctx, tableID)
+		if err != nil {
+			return nil, fmt.Errorf("get column names for masking: %w", err)
+		}
+		if req.ColumnName != "" && !slices.ContainsFunc(colNames, func(c string) bool { return strings.EqualFold(c, req.ColumnName) }) {
+			return nil, domain.ErrValidation("table %s has no column %q", req.Table, req.ColumnName)
+		}
+		if query, err = sqlrewrite.ApplyColumnMasks(query, tableName, masks, colNames); err != nil {
+			return nil, fmt.Errorf("apply column masks: %w", err)
+		}
+	}
+
+	rows, err := s.engine.Query(ctx, callerName(ctx), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+	sim := &domain.PolicySimulation{
+		TableName:     req.Table,
+		Principal:     req.Principal,
+		RowFiltered:   len(filters) > 0,
+		MaskedColumns: slices.Sorted(maps.Keys(masks)),
+	}
+	if sim.Columns, sim.Rows, err = scanSimulationRows(rows); err != nil {
+		return nil, fmt.Errorf("read simulation rows: %w", err)
+	}
+
+	_ = s.audit.Insert(ctx, &domain.AuditEntry{
+		PrincipalName: callerName(ctx),
+		Action:        "SIMULATE_POLICY",
+		Status:        "ALLOWED",
+	})
+	return sim, nil
+}
+
+// resolveCandidateFilter substitutes the principal's attribute values into a
+// candidate filter, as GetEffectiveRowFilters does for bound filters.
+func (s *RowFilterService) resolveCandidateFilter(ctx context.Context, principalID, filterSQL string) (string, error) {
+	var attrs map[string]string
+	if s.principalAttrs != nil && sqlrewrite.ReferencesPrincipalAttrs(filterSQL) {
+		var err error
+		if attrs, err = s.principalAttrs.Get(ctx, principalID); err != nil {
+			return "", fmt.Errorf("load principal attributes: %w", err)
+		}
+	}
+	resolved, err := sqlrewrite.ResolvePrincipalAttrs(filterSQL, attrs)
+	if err != nil {
+		return "", domain.ErrValidation("filter_sql: %v", err)
+	}
+	return resolved, nil
+}
+
+// scanSimulationRows reads every column of every row, converting byte slices
+// to strings for JSON serialization.
+func scanSimulationRows(rows *sql.Rows) ([]string, [][]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	result := make([][]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, err
+		}
+		for i, v := range values {
+			values[i] = jsonValue(v)
+		}
+		result = append(result, values)
+	}
+	return columns, result, rows.Err()
+}
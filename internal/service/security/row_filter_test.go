@@ -2,18 +2,22 @@ package security
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
+	_ "github.com/duckdb/duckdb-go/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"duck-demo/internal/domain"
+	"duck-demo/internal/sqlrewrite"
 	"duck-demo/internal/testutil"
 )
 
 type mockRowFilterRepo struct {
 	CreateFn                  func(ctx context.Context, f *domain.RowFilter) (*domain.RowFilter, error)
 	GetForTableFn             func(ctx context.Context, tableID string, page domain.PageRequest) ([]domain.RowFilter, int64, error)
+	GetForTagFn               func(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.RowFilter, int64, error)
 	DeleteFn                  func(ctx context.Context, id string) error
 	BindFn                    func(ctx context.Context, b *domain.RowFilterBinding) error
 	UnbindFn                  func(ctx context.Context, b *domain.RowFilterBinding) error
@@ -29,6 +33,10 @@ func (m *mockRowFilterRepo) GetForTable(ctx context.Context, tableID string, pag
 	return m.GetForTableFn(ctx, tableID, page)
 }
 
+func (m *mockRowFilterRepo) GetForTag(ctx context.Context, tagID string, page domain.PageRequest) ([]domain.RowFilter, int64, error) {
+	return m.GetForTagFn(ctx, tagID, page)
+}
+
 func (m *mockRowFilterRepo) Delete(ctx context.Context, id string) error {
 	return m.DeleteFn(ctx, id)
 }
@@ -94,6 +102,43 @@ func TestRowFilterService_Create_ValidationError(t *testing.T) {
 	require.ErrorAs(t, err, &validation)
 }
 
+func TestRowFilterService_Create_TagBased(t *testing.T) {
+	var created *domain.RowFilter
+	repo := &mockRowFilterRepo{
+		CreateFn: func(_ context.Context, f *domain.RowFilter) (*domain.RowFilter, error) {
+			f.ID = "rf-1"
+			created = f
+			return f, nil
+		},
+	}
+	svc := NewRowFilterService(repo, &testutil.MockAuditRepo{})
+
+	_, err := svc.Create(adminCtx(), domain.CreateRowFilterRequest{
+		TagID:     "tag-eu",
+		FilterSQL: `region = 'EU'`,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.TagID)
+	assert.Equal(t, "tag-eu", *created.TagID)
+	assert.Empty(t, created.TableID)
+
+	_, err = svc.Create(adminCtx(), domain.CreateRowFilterRequest{
+		TagID:     "tag-eu",
+		TableID:   "t-1",
+		FilterSQL: `region = 'EU'`,
+	})
+	var validation *domain.ValidationError
+	assert.ErrorAs(t, err, &validation, "a filter targets either a tag or a table")
+}
+
+func TestRowFilterService_GetForTag_NonAdminDenied(t *testing.T) {
+	svc := NewRowFilterService(&mockRowFilterRepo{}, &testutil.MockAuditRepo{})
+
+	_, _, err := svc.GetForTag(nonAdminCtx(), "tag-eu", domain.PageRequest{})
+	var denied *domain.AccessDeniedError
+	assert.ErrorAs(t, err, &denied)
+}
+
 func TestRowFilterService_Delete_AdminAllowed(t *testing.T) {
 	called := false
 	repo := &mockRowFilterRepo{
@@ -246,3 +291,296 @@ func TestRowFilterService_Create_InvalidSQL(t *testing.T) {
 	require.ErrorAs(t, err, &validation)
 	assert.Contains(t, err.Error(), "filter_sql is not valid SQL")
 }
+
+func TestRowFilterService_Create_PrincipalAttrFilter(t *testing.T) {
+	repo := &mockRowFilterRepo{
+		CreateFn: func(_ context.Context, f *domain.RowFilter) (*domain.RowFilter, error) {
+			return f, nil
+		},
+	}
+	svc := NewRowFilterService(repo, &testutil.MockAuditRepo{})
+
+	result, err := svc.Create(adminCtx(), domain.CreateRowFilterRequest{
+		TableID:   "t-1",
+		FilterSQL: `region = current_principal_attr('region')`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `region = current_principal_attr('region')`, result.FilterSQL)
+
+	_, err = svc.Create(adminCtx(), domain.CreateRowFilterRequest{
+		TableID:   "t-1",
+		FilterSQL: `region = current_principal_attr(region)`,
+	})
+	var validation *domain.ValidationError
+	require.ErrorAs(t, err, &validation)
+}
+
+// attrPrincipalRepo resolves principals by name from a fixed set.
+type attrPrincipalRepo struct {
+	domain.PrincipalRepository
+	byName map[string]*domain.Principal
+}
+
+func (r *attrPrincipalRepo) GetByName(_ context.Context, name string) (*domain.Principal, error) {
+	if p, ok := r.byName[name]; ok {
+		return p, nil
+	}
+	return nil, domain.ErrNotFound("principal %q not found", name)
+}
+
+// noGroupsRepo reports no group memberships.
+type noGroupsRepo struct {
+	domain.GroupRepository
+}
+
+func (noGroupsRepo) GetGroupsForMember(_ context.Context, _, _ string) ([]domain.Group, error) {
+	return nil, nil
+}
+
+type mockPrincipalAttributeRepo struct {
+	attrs map[string]map[string]string
+}
+
+func (m *mockPrincipalAttributeRepo) Get(_ context.Context, principalID string) (map[string]string, error) {
+	return m.attrs[principalID], nil
+}
+
+func (m *mockPrincipalAttributeRepo) GetVersioned(_ context.Context, principalID string) (map[string]string, int64, error) {
+	return m.attrs[principalID], 0, nil
+}
+
+func (m *mockPrincipalAttributeRepo) Replace(_ context.Context, principalID string, attrs map[string]string, _ *int64) (int64, error) {
+	m.attrs[principalID] = attrs
+	return 0, nil
+}
+
+func TestRowFilter_PrincipalAttributesSelectRowsPerPrincipal(t *testing.T) {
+	ctx := context.Background()
+	principals := &attrPrincipalRepo{byName: map[string]*domain.Principal{
+		"alice": {ID: "p-alice", Name: "alice"},
+		"bob":   {ID: "p-bob", Name: "bob"},
+		"carol": {ID: "p-carol", Name: "carol"},
+	}}
+	attrs := &mockPrincipalAttributeRepo{attrs: map[string]map[string]string{
+		"p-alice": {"region": "EU"},
+		"p-bob":   {"region": "US"},
+	}}
+	// One filter bound to all three principals.
+	rowFilters := &mockRowFilterRepo{
+		GetForTableAndPrincipalFn: func(_ context.Context, _, _ string, principalType string) ([]domain.RowFilter, error) {
+			if principalType != "user" {
+				return nil, nil
+			}
+			return []domain.RowFilter{{ID: "rf-1", FilterSQL: `region = current_principal_attr('region')`}}, nil
+		},
+	}
+	auth := NewAuthorizationService(principals, noGroupsRepo{}, nil, rowFilters, nil, nil, nil)
+	auth.SetPrincipalAttributeRepository(attrs)
+
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec(`CREATE TABLE sales (id INTEGER, region VARCHAR);
+		INSERT INTO sales VALUES (1, 'EU'), (2, 'US'), (3, 'EU'), (4, 'APAC')`)
+	require.NoError(t, err)
+
+	visibleIDs := func(principal string) []int {
+		filters, err := auth.GetEffectiveRowFilters(ctx, principal, "t-sales")
+		require.NoError(t, err)
+		query, err := sqlrewrite.InjectMultipleRowFilters(`SELECT id FROM sales ORDER BY id`, "sales", filters)
+		require.NoError(t, err)
+		rows, err := db.QueryContext(ctx, query)
+		require.NoError(t, err)
+		defer rows.Close() //nolint:errcheck
+		ids := []int{}
+		for rows.Next() {
+			var id int
+			require.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		require.NoError(t, rows.Err())
+		return ids
+	}
+
+	assert.Equal(t, []int{1, 3}, visibleIDs("alice"))
+	assert.Equal(t, []int{2}, visibleIDs("bob"))
+	// A principal without the attribute sees nothing.
+	assert.Empty(t, visibleIDs("carol"))
+}
+
+// newSimulationService returns a RowFilterService reading sales.customers,
+// where bob already has boundFilters and an email mask, and whose repository
+// fails the test if anything is persisted.
+func newSimulationService(t *testing.T, boundFilters []string) (*RowFilterService, *testutil.MockAuditRepo) {
+	t.Helper()
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec(`CREATE SCHEMA sales;
+		CREATE TABLE sales.customers (id INTEGER, email VARCHAR, region VARCHAR);
+		INSERT INTO sales.customers VALUES
+			(1, 'alice@example.com', 'EU'), (2, 'bob@example.com', 'US'), (3, 'carol@example.com', 'EU')`)
+	require.NoError(t, err)
+
+	persist := func(string) { t.Fatal("simulation must not persist anything") }
+	repo := &mockRowFilterRepo{
+		CreateFn: func(_ context.Context, _ *domain.RowFilter) (*domain.RowFilter, error) {
+			persist("Create")
+			return nil, nil
+		},
+		BindFn: func(_ context.Context, _ *domain.RowFilterBinding) error {
+			persist("Bind")
+			return nil
+		},
+	}
+	principals := &attrPrincipalRepo{byName: map[string]*domain.Principal{
+		"bob":   {ID: "p-bob", Name: "bob"},
+		"root":  {ID: "p-root", Name: "root", IsAdmin: true},
+		"carol": {ID: "p-carol", Name: "carol"},
+	}}
+	attrs := &mockPrincipalAttributeRepo{attrs: map[string]map[string]string{
+		"p-bob": {"region": "EU"},
+	}}
+	auth := &testutil.MockAuthService{
+		LookupTableIDFn: func(_ context.Context, name string) (string, string, bool, error) {
+			if name != "sales.customers" {
+				return "", "", false, domain.ErrNotFound("table %q not found", name)
+			}
+			return "t-1", "s-1", false, nil
+		},
+		CheckPrivilegeFn: func(_ context.Context, principal, _, _, _ string) (bool, error) {
+			return principal != "carol", nil
+		},
+		GetEffectiveRowFiltersFn: func(_ context.Context, _, _ string) ([]string, error) {
+			return boundFilters, nil
+		},
+		GetEffectiveColumnMasksFn: func(_ context.Context, _, _ string) (map[string]string, error) {
+			return map[string]string{"email": "'***'"}, nil
+		},
+		GetTableColumnNamesFn: func(_ context.Context, _ string) ([]string, error) {
+			return []string{"id", "email", "region"}, nil
+		},
+	}
+	engine := &testutil.MockSessionEngine{
+		QueryFn: func(ctx context.Context, _, query string) (*sql.Rows, error) {
+			return db.QueryContext(ctx, query)
+		},
+	}
+
+	audit := &testutil.MockAuditRepo{}
+	svc := NewRowFilterService(repo, audit)
+	svc.SetSimulationDeps(auth, principals, attrs, engine)
+	return svc, audit
+}
+
+func TestRowFilterService_SimulatePolicy_CandidateFilterNarrowsRows(t *testing.T) {
+	svc, audit := newSimulationService(t, nil)
+
+	sim, err := svc.SimulatePolicy(adminCtx(), domain.PolicySimulationRequest{
+		Table:     "sales.customers",
+		Principal: "bob",
+		RowFilter: `region = 'EU'`,
+	})
+	require.NoError(t, err)
+	assert.True(t, sim.RowFiltered)
+	assert.Equal(t, []string{"id", "email", "region"}, sim.Columns)
+	require.Len(t, sim.Rows, 2)
+	for _, row := range sim.Rows {
+		assert.Equal(t, "EU", row[2])
+		assert.Equal(t, "***", row[1], "bound masks still apply")
+	}
+	assert.Equal(t, []string{"email"}, sim.MaskedColumns)
+	assert.True(t, audit.HasAction("SIMULATE_POLICY"))
+}
+
+func TestRowFilterService_SimulatePolicy_CandidateFilterResolvesPrincipalAttrs(t *testing.T) {
+	svc, _ := newSimulationService(t, nil)
+
+	sim, err := svc.SimulatePolicy(adminCtx(), domain.PolicySimulationRequest{
+		Table:     "sales.customers",
+		Principal: "bob",
+		RowFilter: `region = current_principal_attr('region')`,
+	})
+	require.NoError(t, err)
+	require.Len(t, sim.Rows, 2)
+}
+
+func TestRowFilterService_SimulatePolicy_CandidateWidensBoundFilters(t *testing.T) {
+	svc, _ := newSimulationService(t, []string{"id = 2"})
+
+	sim, err := svc.SimulatePolicy(adminCtx(), domain.PolicySimulationRequest{
+		Table:     "sales.customers",
+		Principal: "bob",
+		RowFilter: "id = 1",
+	})
+	require.NoError(t, err)
+	assert.Len(t, sim.Rows, 2, "a new binding adds a visibility window")
+}
+
+func TestRowFilterService_SimulatePolicy_CandidateMaskOverridesColumn(t *testing.T) {
+	svc, _ := newSimulationService(t, nil)
+
+	sim, err := svc.SimulatePolicy(adminCtx(), domain.PolicySimulationRequest{
+		Table:          "sales.customers",
+		Principal:      "bob",
+		ColumnName:     "Region",
+		MaskExpression: "'hidden'",
+		Limit:          1,
+	})
+	require.NoError(t, err)
+	assert.False(t, sim.RowFiltered)
+	assert.Equal(t, []string{"email", "region"}, sim.MaskedColumns)
+	require.Len(t, sim.Rows, 1)
+	assert.Equal(t, "hidden", sim.Rows[0][2])
+	assert.Equal(t, "***", sim.Rows[0][1])
+}
+
+func TestRowFilterService_SimulatePolicy_AdminBypassesCandidate(t *testing.T) {
+	svc, _ := newSimulationService(t, nil)
+
+	sim, err := svc.SimulatePolicy(adminCtx(), domain.PolicySimulationRequest{
+		Table:     "sales.customers",
+		Principal: "root",
+		RowFilter: "false",
+	})
+	require.NoError(t, err)
+	assert.False(t, sim.RowFiltered)
+	assert.Empty(t, sim.MaskedColumns)
+	assert.Len(t, sim.Rows, 3)
+}
+
+func TestRowFilterService_SimulatePolicy_Errors(t *testing.T) {
+	svc, _ := newSimulationService(t, nil)
+	valid := domain.PolicySimulationRequest{Table: "sales.customers", Principal: "bob", RowFilter: "id = 1"}
+
+	t.Run("non-admin denied", func(t *testing.T) {
+		_, err := svc.SimulatePolicy(nonAdminCtx(), valid)
+		var denied *domain.AccessDeniedError
+		assert.ErrorAs(t, err, &denied)
+	})
+	for name, req := range map[string]domain.PolicySimulationRequest{
+		"no policy":          {Table: "sales.customers", Principal: "bob"},
+		"mask without expr":  {Table: "sales.customers", Principal: "bob", ColumnName: "email"},
+		"invalid filter":     {Table: "sales.customers", Principal: "bob", RowFilter: "id = = 1"},
+		"limit out of range": {Table: "sales.customers", Principal: "bob", RowFilter: "id = 1", Limit: domain.MaxPolicySimulationLimit + 1},
+		"unknown column":     {Table: "sales.customers", Principal: "bob", ColumnName: "ssn", MaskExpression: "'x'"},
+		"lacks SELECT":       {Table: "sales.customers", Principal: "carol", RowFilter: "id = 1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.SimulatePolicy(adminCtx(), req)
+			var validation *domain.ValidationError
+			assert.ErrorAs(t, err, &validation)
+		})
+	}
+	t.Run("unknown table", func(t *testing.T) {
+		req := valid
+		req.Table = "sales.nope"
+		_, err := svc.SimulatePolicy(adminCtx(), req)
+		var notFound *domain.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+	t.Run("not configured", func(t *testing.T) {
+		_, err := NewRowFilterService(&mockRowFilterRepo{}, &testutil.MockAuditRepo{}).SimulatePolicy(adminCtx(), valid)
+		assert.ErrorContains(t, err, "not configured")
+	})
+}
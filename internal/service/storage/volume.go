@@ -68,13 +68,13 @@ func (s *VolumeService) GetByName(ctx context.Context, principal, catalogName, s
 	return s.repo.GetByName(ctx, schemaName, name)
 }
 
-// List returns a paginated list of volumes in a schema.
-// Requires CREATE_VOLUME on catalog.
-func (s *VolumeService) List(ctx context.Context, principal, catalogName, schemaName string, page domain.PageRequest) ([]domain.Volume, int64, error) {
+// List returns a paginated list of volumes in a schema, limited to those owner
+// owns when it is non-nil. Requires CREATE_VOLUME on catalog.
+func (s *VolumeService) List(ctx context.Context, principal, catalogName, schemaName string, owner *string, page domain.PageRequest) ([]domain.Volume, int64, error) {
 	if err := s.requirePrivilege(ctx, principal, domain.SecurableCatalog, catalogName, domain.PrivCreateVolume, "LIST_VOLUMES", fmt.Sprintf("Denied list volumes in schema %q", schemaName)); err != nil {
 		return nil, 0, err
 	}
-	return s.repo.List(ctx, schemaName, page)
+	return s.repo.List(ctx, schemaName, owner, page)
 }
 
 // Update updates a volume by schema and name.
@@ -143,18 +143,36 @@ func TestVolumeService_List(t *testing.T) {
 			{ID: "vol-2", Name: "vol-b"},
 		}
 		repo := &mockVolumeRepo{
-			ListFn: func(_ context.Context, schemaName string, _ domain.PageRequest) ([]domain.Volume, int64, error) {
+			ListFn: func(_ context.Context, schemaName string, owner *string, _ domain.PageRequest) ([]domain.Volume, int64, error) {
 				assert.Equal(t, "schema1", schemaName)
+				assert.Nil(t, owner)
 				return vols, 2, nil
 			},
 		}
 		svc := NewVolumeService(repo, allowAllAuth(), &mockAuditRepo{})
 
-		got, total, err := svc.List(context.Background(), "alice", "catalog1", "schema1", domain.PageRequest{})
+		got, total, err := svc.List(context.Background(), "alice", "catalog1", "schema1", nil, domain.PageRequest{})
 		require.NoError(t, err)
 		assert.Len(t, got, 2)
 		assert.Equal(t, int64(2), total)
 	})
+
+	t.Run("owner_filter_passed_to_repo", func(t *testing.T) {
+		var gotOwner *string
+		repo := &mockVolumeRepo{
+			ListFn: func(_ context.Context, _ string, owner *string, _ domain.PageRequest) ([]domain.Volume, int64, error) {
+				gotOwner = owner
+				return nil, 0, nil
+			},
+		}
+		svc := NewVolumeService(repo, allowAllAuth(), &mockAuditRepo{})
+
+		owner := "alice"
+		_, _, err := svc.List(context.Background(), "alice", "catalog1", "schema1", &owner, domain.PageRequest{})
+		require.NoError(t, err)
+		require.NotNil(t, gotOwner)
+		assert.Equal(t, "alice", *gotOwner)
+	})
 }
 
 func TestVolumeService_Update(t *testing.T) {
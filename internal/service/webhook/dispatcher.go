@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"duck-demo/internal/domain"
+)
+
+// Delivery headers sent with every webhook request.
+const (
+	HeaderEvent     = "X-Duck-Event"
+	HeaderDelivery  = "X-Duck-Delivery"
+	HeaderTimestamp = "X-Duck-Timestamp"
+	HeaderSignature = "X-Duck-Signature"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	deliveryTimeout       = 10 * time.Second
+)
+
+var _ domain.EventPublisher = (*Dispatcher)(nil)
+
+// Payload is the JSON body delivered to webhook subscribers.
+type Payload struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Actor      string                 `json:"actor"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// Sign returns the signature header value for body delivered at timestamp:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by
+// secret. Receivers recompute it to authenticate the payload and should
+// reject stale timestamps to prevent replay.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%d.", timestamp)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher fans events out to subscribed webhooks. Deliveries run in the
+// background with exponential backoff; a delivery that still fails after the
+// last attempt is logged and dropped.
+type Dispatcher struct {
+	repo   domain.WebhookRepository
+	client *http.Client
+	logger *slog.Logger
+
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	wg sync.WaitGroup
+}
+
+// NewDispatcher creates a Dispatcher that reads subscriptions from repo.
+func NewDispatcher(repo domain.WebhookRepository, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:           repo,
+		client:         &http.Client{Timeout: deliveryTimeout},
+		logger:         logger,
+		maxAttempts:    defaultMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+}
+
+// SetRetryPolicy overrides the delivery attempt limit and backoff bounds.
+func (d *Dispatcher) SetRetryPolicy(maxAttempts int, initialBackoff, maxBackoff time.Duration) {
+	if maxAttempts > 0 {
+		d.maxAttempts = maxAttempts
+	}
+	if initialBackoff > 0 {
+		d.initialBackoff = initialBackoff
+	}
+	if maxBackoff > 0 {
+		d.maxBackoff = maxBackoff
+	}
+}
+
+// Publish schedules delivery of event to every subscribed webhook.
+func (d *Dispatcher) Publish(ctx context.Context, event domain.Event) {
+	hooks, err := d.repo.ListAll(ctx)
+	if err != nil {
+		d.logger.Warn("list webhooks for event", "event", event.Type, "error", err)
+		return
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Actor:      event.Actor,
+		Data:       event.Data,
+	})
+	if err != nil {
+		d.logger.Warn("marshal webhook payload", "event", event.Type, "error", err)
+		return
+	}
+
+	for _, hook := range hooks {
+		if !hook.Subscribes(event.Type) {
+			continue
+		}
+		d.wg.Add(1)
+		go func(hook domain.Webhook) {
+			defer d.wg.Done()
+			d.deliver(hook, event, body)
+		}(hook)
+	}
+}
+
+// Wait blocks until all in-flight deliveries finish. Used on shutdown and in tests.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) deliver(hook domain.Webhook, event domain.Event, body []byte) {
+	backoff := d.initialBackoff
+	var lastErr error
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		lastErr = d.send(hook, event, body)
+		if lastErr == nil {
+			return
+		}
+		d.logger.Warn("webhook delivery failed",
+			"webhook_id", hook.ID, "event", event.Type, "delivery", event.ID,
+			"attempt", attempt, "max_attempts", d.maxAttempts, "error", lastErr)
+		if attempt < d.maxAttempts {
+			time.Sleep(backoff)
+			backoff = min(backoff*2, d.maxBackoff)
+		}
+	}
+	d.logger.Error("webhook delivery abandoned",
+		"webhook_id", hook.ID, "event", event.Type, "delivery", event.ID, "error", lastErr)
+}
+
+func (d *Dispatcher) send(hook domain.Webhook, event domain.Event, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderDelivery, event.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, ts, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/security"
+)
+
+const testSecret = "test-signing-secret-0123"
+
+// memWebhookRepo is an in-memory WebhookRepository.
+type memWebhookRepo struct {
+	hooks []domain.Webhook
+}
+
+func (r *memWebhookRepo) Create(_ context.Context, hook *domain.Webhook) (*domain.Webhook, error) {
+	hook.ID = domain.NewID()
+	r.hooks = append(r.hooks, *hook)
+	return hook, nil
+}
+
+func (r *memWebhookRepo) GetByID(_ context.Context, id string) (*domain.Webhook, error) {
+	for i := range r.hooks {
+		if r.hooks[i].ID == id {
+			return &r.hooks[i], nil
+		}
+	}
+	return nil, domain.ErrNotFound("webhook %q not found", id)
+}
+
+func (r *memWebhookRepo) List(_ context.Context, _ domain.PageRequest) ([]domain.Webhook, int64, error) {
+	return r.hooks, int64(len(r.hooks)), nil
+}
+
+func (r *memWebhookRepo) ListAll(_ context.Context) ([]domain.Webhook, error) {
+	return r.hooks, nil
+}
+
+func (r *memWebhookRepo) Delete(_ context.Context, _ string) error {
+	panic("unexpected call to memWebhookRepo.Delete")
+}
+
+// stubGrantRepo returns the grant it is given with an ID assigned.
+type stubGrantRepo struct {
+	domain.GrantRepository
+}
+
+func (stubGrantRepo) Grant(_ context.Context, g *domain.PrivilegeGrant) (*domain.PrivilegeGrant, error) {
+	g.ID = "grant-1"
+	return g, nil
+}
+
+type noopAudit struct{}
+
+func (noopAudit) Insert(context.Context, *domain.AuditEntry) error { return nil }
+func (noopAudit) List(context.Context, domain.AuditFilter) ([]domain.AuditEntry, int64, error) {
+	return nil, 0, nil
+}
+
+type receivedHook struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver starts a test server that records deliveries. The first
+// failures requests are answered with 500.
+func newReceiver(t *testing.T, failures int32) (*httptest.Server, func() []receivedHook) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		received []receivedHook
+		calls    atomic.Int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedHook{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []receivedHook {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedHook(nil), received...)
+	}
+}
+
+func newTestDispatcher(hooks ...domain.Webhook) *Dispatcher {
+	d := NewDispatcher(&memWebhookRepo{hooks: hooks}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.SetRetryPolicy(3, time.Millisecond, 5*time.Millisecond)
+	return d
+}
+
+func adminCtx() context.Context {
+	return domain.WithPrincipal(context.Background(), domain.ContextPrincipal{Name: "admin-user", IsAdmin: true, Type: "user"})
+}
+
+func TestGrantCreated_DeliversSignedWebhook(t *testing.T) {
+	srv, received := newReceiver(t, 0)
+	dispatcher := newTestDispatcher(domain.Webhook{
+		ID: "hook-1", URL: srv.URL, Secret: testSecret, Events: []string{domain.EventGrantCreated},
+	})
+
+	grants := security.NewGrantService(stubGrantRepo{}, noopAudit{})
+	grants.SetEventPublisher(dispatcher)
+
+	_, err := grants.Grant(adminCtx(), domain.CreateGrantRequest{
+		PrincipalID: "principal-1", PrincipalType: "user",
+		SecurableType: "table", SecurableID: "42", Privilege: "SELECT",
+	})
+	require.NoError(t, err)
+	dispatcher.Wait()
+
+	hooks := received()
+	require.Len(t, hooks, 1)
+	got := hooks[0]
+	assert.Equal(t, domain.EventGrantCreated, got.header.Get(HeaderEvent))
+
+	ts, err := strconv.ParseInt(got.header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign(testSecret, ts, got.body), got.header.Get(HeaderSignature))
+	assert.NotEqual(t, Sign("wrong-secret", ts, got.body), got.header.Get(HeaderSignature))
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(got.body, &payload))
+	assert.Equal(t, domain.EventGrantCreated, payload.Type)
+	assert.Equal(t, "admin-user", payload.Actor)
+	assert.Equal(t, "grant-1", payload.Data["grant_id"])
+	assert.Equal(t, "SELECT", payload.Data["privilege"])
+}
+
+func TestDispatcher_FiltersByEventType(t *testing.T) {
+	srv, received := newReceiver(t, 0)
+	dispatcher := newTestDispatcher(
+		domain.Webhook{ID: "grants", URL: srv.URL, Secret: testSecret, Events: []string{domain.EventGrantCreated}},
+		domain.Webhook{ID: "all", URL: srv.URL, Secret: testSecret, Events: []string{domain.EventAll}},
+	)
+
+	dispatcher.Publish(context.Background(), domain.NewEvent(context.Background(), domain.EventPrincipalAdminChanged, nil))
+	dispatcher.Wait()
+
+	hooks := received()
+	require.Len(t, hooks, 1, "only the wildcard subscription receives admin changes")
+	assert.Equal(t, domain.EventPrincipalAdminChanged, hooks[0].header.Get(HeaderEvent))
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	t.Run("recovers after transient failures", func(t *testing.T) {
+		srv, received := newReceiver(t, 2)
+		dispatcher := newTestDispatcher(domain.Webhook{ID: "h", URL: srv.URL, Secret: testSecret, Events: []string{domain.EventAll}})
+
+		dispatcher.Publish(context.Background(), domain.NewEvent(context.Background(), domain.EventGrantDeleted, nil))
+		dispatcher.Wait()
+
+		assert.Len(t, received(), 1)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		srv, received := newReceiver(t, 100)
+		dispatcher := newTestDispatcher(domain.Webhook{ID: "h", URL: srv.URL, Secret: testSecret, Events: []string{domain.EventAll}})
+
+		dispatcher.Publish(context.Background(), domain.NewEvent(context.Background(), domain.EventGrantDeleted, nil))
+		dispatcher.Wait()
+
+		assert.Empty(t, received())
+	})
+}
@@ -0,0 +1,91 @@
+// Package webhook implements webhook subscriptions and signed event delivery.
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/auditutil"
+)
+
+// Service provides CRUD operations for webhook subscriptions. All operations
+// require admin privileges because subscriptions expose security events.
+type Service struct {
+	repo  domain.WebhookRepository
+	audit domain.AuditRepository
+}
+
+// NewService creates a new webhook Service.
+func NewService(repo domain.WebhookRepository, audit domain.AuditRepository) *Service {
+	return &Service{repo: repo, audit: audit}
+}
+
+// Create validates and persists a new webhook subscription.
+func (s *Service) Create(ctx context.Context, req domain.CreateWebhookRequest) (*domain.Webhook, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	hook, err := s.repo.Create(ctx, &domain.Webhook{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Description: req.Description,
+		CreatedBy:   caller,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create webhook: %w", err)
+	}
+	s.logAudit(ctx, caller, "CREATE_WEBHOOK", fmt.Sprintf("Created webhook %s to %s", hook.ID, hook.URL))
+	return hook, nil
+}
+
+// Get returns a webhook subscription by ID.
+func (s *Service) Get(ctx context.Context, id string) (*domain.Webhook, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// List returns a paginated list of webhook subscriptions.
+func (s *Service) List(ctx context.Context, page domain.PageRequest) ([]domain.Webhook, int64, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, page)
+}
+
+// Delete removes a webhook subscription.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logAudit(ctx, caller, "DELETE_WEBHOOK", fmt.Sprintf("Deleted webhook %s", id))
+	return nil
+}
+
+// requireAdmin checks that the caller in context is an admin and returns
+// the caller's name.
+func requireAdmin(ctx context.Context) (string, error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return "", domain.ErrAccessDenied("authentication required")
+	}
+	if !p.IsAdmin {
+		return "", domain.ErrAccessDenied("admin privileges required")
+	}
+	return p.Name, nil
+}
+
+func (s *Service) logAudit(ctx context.Context, principal, action, detail string) {
+	auditutil.LogAllowed(ctx, s.audit, principal, action, detail)
+}
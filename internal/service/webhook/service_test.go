@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/domain"
+)
+
+func TestService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		req     domain.CreateWebhookRequest
+		wantErr interface{}
+	}{
+		{
+			name: "admin creates subscription",
+			ctx:  adminCtx(),
+			req:  domain.CreateWebhookRequest{URL: "https://hooks.example.com/x", Secret: testSecret, Events: []string{domain.EventGrantCreated}},
+		},
+		{
+			name:    "non-admin is denied",
+			ctx:     domain.WithPrincipal(context.Background(), domain.ContextPrincipal{Name: "bob"}),
+			req:     domain.CreateWebhookRequest{URL: "https://hooks.example.com/x", Secret: testSecret, Events: []string{domain.EventGrantCreated}},
+			wantErr: new(*domain.AccessDeniedError),
+		},
+		{
+			name:    "unknown event is rejected",
+			ctx:     adminCtx(),
+			req:     domain.CreateWebhookRequest{URL: "https://hooks.example.com/x", Secret: testSecret, Events: []string{"grant.exploded"}},
+			wantErr: new(*domain.ValidationError),
+		},
+		{
+			name:    "short secret is rejected",
+			ctx:     adminCtx(),
+			req:     domain.CreateWebhookRequest{URL: "https://hooks.example.com/x", Secret: "short", Events: []string{domain.EventAll}},
+			wantErr: new(*domain.ValidationError),
+		},
+		{
+			name:    "non-http url is rejected",
+			ctx:     adminCtx(),
+			req:     domain.CreateWebhookRequest{URL: "file:///etc/passwd", Secret: testSecret, Events: []string{domain.EventAll}},
+			wantErr: new(*domain.ValidationError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&memWebhookRepo{}, noopAudit{})
+			hook, err := svc.Create(tt.ctx, tt.req)
+			if tt.wantErr != nil {
+				require.ErrorAs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "admin-user", hook.CreatedBy)
+		})
+	}
+}
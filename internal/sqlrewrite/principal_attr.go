@@ -0,0 +1,69 @@
+package sqlrewrite
+
+import (
+	"fmt"
+	"strings"
+
+	"duck-demo/internal/duckdbsql"
+)
+
+// PrincipalAttrFunc is the function row filters call to read an attribute of
+// the querying principal, e.g. `region = current_principal_attr('region')`.
+// It is not a DuckDB function: ResolvePrincipalAttrs replaces each call with
+// the attribute's value before the filter is injected.
+const PrincipalAttrFunc = "current_principal_attr"
+
+// ReferencesPrincipalAttrs reports whether a filter may call
+// current_principal_attr. It is a cheap textual check used to skip the
+// rewrite for filters that cannot need it.
+func ReferencesPrincipalAttrs(filterSQL string) bool {
+	return strings.Contains(strings.ToLower(filterSQL), PrincipalAttrFunc)
+}
+
+// ResolvePrincipalAttrs returns filterSQL with every
+// current_principal_attr('key') call replaced by the principal's value for
+// key as a string literal, or NULL when the principal has no such attribute
+// (so comparisons against it match no rows). Each call must take exactly one
+// string literal argument, and calls inside subqueries are rejected.
+// Validating a filter is ResolvePrincipalAttrs with no attributes.
+func ResolvePrincipalAttrs(filterSQL string, attrs map[string]string) (string, error) {
+	if !ReferencesPrincipalAttrs(filterSQL) {
+		return filterSQL, nil
+	}
+	expr, err := duckdbsql.ParseExpr(filterSQL)
+	if err != nil {
+		return "", fmt.Errorf("parse row filter %q: %w", filterSQL, err)
+	}
+
+	expr, err = duckdbsql.RewriteFuncCalls(expr, func(fc *duckdbsql.FuncCall) (duckdbsql.Expr, error) {
+		if fc.Schema != "" || !strings.EqualFold(fc.Name, PrincipalAttrFunc) {
+			return nil, nil
+		}
+		var key *duckdbsql.Literal
+		if len(fc.Args) == 1 {
+			key, _ = fc.Args[0].(*duckdbsql.Literal)
+		}
+		if key == nil || key.Type != duckdbsql.LiteralString || key.Value == "" || fc.Star || fc.Distinct {
+			return nil, fmt.Errorf("%s takes one attribute name as a string literal", PrincipalAttrFunc)
+		}
+		if v, ok := attrs[key.Value]; ok {
+			return &duckdbsql.Literal{Type: duckdbsql.LiteralString, Value: v}, nil
+		}
+		return &duckdbsql.Literal{Type: duckdbsql.LiteralNull, Value: "NULL"}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	resolved := duckdbsql.FormatExpr(expr)
+
+	// Calls the rewrite cannot reach (inside subqueries) would otherwise fail
+	// at query time with an unknown-function error.
+	stmt, err := duckdbsql.Parse("SELECT 1 WHERE " + resolved)
+	if err != nil {
+		return "", fmt.Errorf("parse resolved row filter: %w", err)
+	}
+	if _, found := duckdbsql.ContainsDangerousFunction(stmt, map[string]bool{PrincipalAttrFunc: true}); found {
+		return "", fmt.Errorf("%s is not supported inside subqueries", PrincipalAttrFunc)
+	}
+	return resolved, nil
+}
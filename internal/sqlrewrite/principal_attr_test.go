@@ -0,0 +1,69 @@
+package sqlrewrite
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolvePrincipalAttrs(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		attrs  map[string]string
+		want   string
+	}{
+		{"value", `region = current_principal_attr('region')`, map[string]string{"region": "EU"}, `"region" = 'EU'`},
+		{"other value", `region = current_principal_attr('region')`, map[string]string{"region": "US"}, `"region" = 'US'`},
+		{"missing attribute", `region = current_principal_attr('region')`, nil, `"region" = NULL`},
+		{"case-insensitive name", `region = CURRENT_PRINCIPAL_ATTR('region')`, map[string]string{"region": "EU"}, `"region" = 'EU'`},
+		{"quote in value", `owner = current_principal_attr('team')`, map[string]string{"team": "o'brien"}, `"owner" = 'o''brien'`},
+		{"nested in IN list", `region IN (current_principal_attr('a'), 'GLOBAL')`, map[string]string{"a": "EU"}, `"region" IN ('EU', 'GLOBAL')`},
+		{"no reference", `"Pclass" = 1`, map[string]string{"region": "EU"}, `"Pclass" = 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePrincipalAttrs(tt.filter, tt.attrs)
+			if err != nil {
+				t.Fatalf("ResolvePrincipalAttrs: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePrincipalAttrs_InjectsAsRowFilter(t *testing.T) {
+	filter, err := ResolvePrincipalAttrs(`region = current_principal_attr('region')`, map[string]string{"region": "EU"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := InjectRowFilterSQL(`SELECT * FROM sales`, "sales", filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, `'EU'`) || strings.Contains(strings.ToLower(result), PrincipalAttrFunc) {
+		t.Errorf("expected resolved filter in %q", result)
+	}
+}
+
+func TestResolvePrincipalAttrs_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+	}{
+		{"no argument", `region = current_principal_attr()`},
+		{"column argument", `region = current_principal_attr(region)`},
+		{"two arguments", `region = current_principal_attr('a', 'b')`},
+		{"empty name", `region = current_principal_attr('')`},
+		{"inside subquery", `region IN (SELECT r FROM regions WHERE r = current_principal_attr('region'))`},
+		{"unparseable", `region = current_principal_attr('region'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ResolvePrincipalAttrs(tt.filter, map[string]string{"region": "EU"}); err == nil {
+				t.Errorf("expected error for %q", tt.filter)
+			}
+		})
+	}
+}
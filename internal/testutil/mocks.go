@@ -6,6 +6,7 @@ package testutil
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"time"
 
 	"duck-demo/internal/domain"
@@ -13,11 +14,14 @@ import (
 
 // === Audit Repository Mock ===
 
-// MockAuditRepo implements domain.AuditRepository for testing.
+// MockAuditRepo implements domain.AuditRepository for testing. Insert is safe
+// for concurrent use, so it can back services that audit from goroutines.
 type MockAuditRepo struct {
 	InsertFn func(ctx context.Context, e *domain.AuditEntry) error
 	ListFn   func(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int64, error)
 	Entries  []*domain.AuditEntry // collected entries for assertions
+
+	mu sync.Mutex
 }
 
 // Insert implements the interface method for testing.
@@ -27,9 +31,9 @@ func (m *MockAuditRepo) Insert(ctx context.Context, e *domain.AuditEntry) error
 		if err != nil {
 			return err
 		}
-		m.Entries = append(m.Entries, e)
-		return nil
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Entries = append(m.Entries, e)
 	return nil
 }
@@ -44,6 +48,8 @@ func (m *MockAuditRepo) List(ctx context.Context, filter domain.AuditFilter) ([]
 
 // LastEntry returns the last collected audit entry, or nil if none.
 func (m *MockAuditRepo) LastEntry() *domain.AuditEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if len(m.Entries) == 0 {
 		return nil
 	}
@@ -52,6 +58,8 @@ func (m *MockAuditRepo) LastEntry() *domain.AuditEntry {
 
 // HasAction returns true if any collected entry has the given action.
 func (m *MockAuditRepo) HasAction(action string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	for _, e := range m.Entries {
 		if e.Action == action {
 			return true
@@ -127,6 +135,8 @@ type MockTagRepo struct {
 	UnassignTagFn           func(ctx context.Context, id string) error
 	ListTagsForSecurableFn  func(ctx context.Context, securableType string, securableID string, columnName *string) ([]domain.Tag, error)
 	ListAssignmentsForTagFn func(ctx context.Context, tagID string) ([]domain.TagAssignment, error)
+	AssignTagBatchFn        func(ctx context.Context, tagID string, targets []domain.TagAssignmentTarget, assignedBy string) (*domain.TagAssignmentBatch, error)
+	UnassignTagBatchFn      func(ctx context.Context, tagID string, targets []domain.TagAssignmentTarget) (*domain.TagAssignmentBatch, error)
 }
 
 // CreateTag implements the interface method for testing.
@@ -193,17 +203,34 @@ func (m *MockTagRepo) ListAssignmentsForTag(ctx context.Context, tagID string) (
 	panic("unexpected call to MockTagRepo.ListAssignmentsForTag")
 }
 
+// AssignTagBatch implements the interface method for testing.
+func (m *MockTagRepo) AssignTagBatch(ctx context.Context, tagID string, targets []domain.TagAssignmentTarget, assignedBy string) (*domain.TagAssignmentBatch, error) {
+	if m.AssignTagBatchFn != nil {
+		return m.AssignTagBatchFn(ctx, tagID, targets, assignedBy)
+	}
+	panic("unexpected call to MockTagRepo.AssignTagBatch")
+}
+
+// UnassignTagBatch implements the interface method for testing.
+func (m *MockTagRepo) UnassignTagBatch(ctx context.Context, tagID string, targets []domain.TagAssignmentTarget) (*domain.TagAssignmentBatch, error) {
+	if m.UnassignTagBatchFn != nil {
+		return m.UnassignTagBatchFn(ctx, tagID, targets)
+	}
+	panic("unexpected call to MockTagRepo.UnassignTagBatch")
+}
+
 var _ domain.TagRepository = (*MockTagRepo)(nil)
 
 // === Lineage Repository Mock ===
 
 // MockLineageRepo implements domain.LineageRepository for testing.
 type MockLineageRepo struct {
-	InsertEdgeFn     func(ctx context.Context, edge *domain.LineageEdge) error
-	GetUpstreamFn    func(ctx context.Context, tableName string, page domain.PageRequest) ([]domain.LineageEdge, int64, error)
-	GetDownstreamFn  func(ctx context.Context, tableName string, page domain.PageRequest) ([]domain.LineageEdge, int64, error)
-	DeleteEdgeFn     func(ctx context.Context, id string) error
-	PurgeOlderThanFn func(ctx context.Context, before time.Time) (int64, error)
+	InsertEdgeFn          func(ctx context.Context, edge *domain.LineageEdge) error
+	GetUpstreamFn         func(ctx context.Context, tableName string, page domain.PageRequest) ([]domain.LineageEdge, int64, error)
+	GetDownstreamFn       func(ctx context.Context, tableName string, page domain.PageRequest) ([]domain.LineageEdge, int64, error)
+	DeleteEdgeFn          func(ctx context.Context, id string) error
+	DeleteEdgesByTargetFn func(ctx context.Context, targetTable, edgeType string) error
+	PurgeOlderThanFn      func(ctx context.Context, before time.Time) (int64, error)
 }
 
 // InsertEdge implements the interface method for testing.
@@ -238,6 +265,14 @@ func (m *MockLineageRepo) DeleteEdge(ctx context.Context, id string) error {
 	panic("unexpected call to MockLineageRepo.DeleteEdge")
 }
 
+// DeleteEdgesByTarget implements the interface method for testing.
+func (m *MockLineageRepo) DeleteEdgesByTarget(ctx context.Context, targetTable, edgeType string) error {
+	if m.DeleteEdgesByTargetFn != nil {
+		return m.DeleteEdgesByTargetFn(ctx, targetTable, edgeType)
+	}
+	panic("unexpected call to MockLineageRepo.DeleteEdgesByTarget")
+}
+
 // PurgeOlderThan implements the interface method for testing.
 func (m *MockLineageRepo) PurgeOlderThan(ctx context.Context, before time.Time) (int64, error) {
 	if m.PurgeOlderThanFn != nil {
@@ -252,7 +287,8 @@ var _ domain.LineageRepository = (*MockLineageRepo)(nil)
 
 // MockQueryHistoryRepo implements domain.QueryHistoryRepository for testing.
 type MockQueryHistoryRepo struct {
-	ListFn func(ctx context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error)
+	ListFn           func(ctx context.Context, filter domain.QueryHistoryFilter) ([]domain.QueryHistoryEntry, int64, error)
+	PurgeOlderThanFn func(ctx context.Context, before time.Time) (int64, error)
 }
 
 // List implements the interface method for testing.
@@ -263,6 +299,14 @@ func (m *MockQueryHistoryRepo) List(ctx context.Context, filter domain.QueryHist
 	panic("unexpected call to MockQueryHistoryRepo.List")
 }
 
+// PurgeOlderThan implements the interface method for testing.
+func (m *MockQueryHistoryRepo) PurgeOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	if m.PurgeOlderThanFn != nil {
+		return m.PurgeOlderThanFn(ctx, before)
+	}
+	panic("unexpected call to MockQueryHistoryRepo.PurgeOlderThan")
+}
+
 var _ domain.QueryHistoryRepository = (*MockQueryHistoryRepo)(nil)
 
 // === View Repository Mock ===
@@ -272,9 +316,13 @@ type MockViewRepo struct {
 	CreateFn    func(ctx context.Context, view *domain.ViewDetail) (*domain.ViewDetail, error)
 	GetByIDFn   func(ctx context.Context, id string) (*domain.ViewDetail, error)
 	GetByNameFn func(ctx context.Context, schemaID string, viewName string) (*domain.ViewDetail, error)
-	ListFn      func(ctx context.Context, schemaID string, page domain.PageRequest) ([]domain.ViewDetail, int64, error)
+	ListFn      func(ctx context.Context, schemaID string, owner *string, page domain.PageRequest) ([]domain.ViewDetail, int64, error)
 	DeleteFn    func(ctx context.Context, schemaID string, viewName string) error
-	UpdateFn    func(ctx context.Context, schemaID string, viewName string, comment *string, props map[string]string, viewDef *string) (*domain.ViewDetail, error)
+	UpdateFn    func(ctx context.Context, schemaID string, viewName string, comment *string, props map[string]string, viewDef *string, expectedRowVersion *int64) (*domain.ViewDetail, error)
+
+	ListScheduledFn func(ctx context.Context) ([]domain.ViewDetail, error)
+	MarkRefreshedFn func(ctx context.Context, schemaID string, viewName string, at time.Time) error
+	RenameFn        func(ctx context.Context, schemaID string, viewName, newName string) (*domain.ViewDetail, error)
 }
 
 // Create implements the interface method for testing.
@@ -302,9 +350,9 @@ func (m *MockViewRepo) GetByName(ctx context.Context, schemaID string, viewName
 }
 
 // List implements the interface method for testing.
-func (m *MockViewRepo) List(ctx context.Context, schemaID string, page domain.PageRequest) ([]domain.ViewDetail, int64, error) {
+func (m *MockViewRepo) List(ctx context.Context, schemaID string, owner *string, page domain.PageRequest) ([]domain.ViewDetail, int64, error) {
 	if m.ListFn != nil {
-		return m.ListFn(ctx, schemaID, page)
+		return m.ListFn(ctx, schemaID, owner, page)
 	}
 	panic("unexpected call to MockViewRepo.List")
 }
@@ -318,20 +366,45 @@ func (m *MockViewRepo) Delete(ctx context.Context, schemaID string, viewName str
 }
 
 // Update implements the interface method for testing.
-func (m *MockViewRepo) Update(ctx context.Context, schemaID string, viewName string, comment *string, props map[string]string, viewDef *string) (*domain.ViewDetail, error) {
+func (m *MockViewRepo) Update(ctx context.Context, schemaID string, viewName string, comment *string, props map[string]string, viewDef *string, expectedRowVersion *int64) (*domain.ViewDetail, error) {
 	if m.UpdateFn != nil {
-		return m.UpdateFn(ctx, schemaID, viewName, comment, props, viewDef)
+		return m.UpdateFn(ctx, schemaID, viewName, comment, props, viewDef, expectedRowVersion)
 	}
 	panic("unexpected call to MockViewRepo.Update")
 }
 
+// ListScheduled implements the interface method for testing.
+func (m *MockViewRepo) ListScheduled(ctx context.Context) ([]domain.ViewDetail, error) {
+	if m.ListScheduledFn != nil {
+		return m.ListScheduledFn(ctx)
+	}
+	panic("unexpected call to MockViewRepo.ListScheduled")
+}
+
+// MarkRefreshed implements the interface method for testing.
+func (m *MockViewRepo) MarkRefreshed(ctx context.Context, schemaID string, viewName string, at time.Time) error {
+	if m.MarkRefreshedFn != nil {
+		return m.MarkRefreshedFn(ctx, schemaID, viewName, at)
+	}
+	panic("unexpected call to MockViewRepo.MarkRefreshed")
+}
+
+// Rename implements the interface method for testing.
+func (m *MockViewRepo) Rename(ctx context.Context, schemaID string, viewName, newName string) (*domain.ViewDetail, error) {
+	if m.RenameFn != nil {
+		return m.RenameFn(ctx, schemaID, viewName, newName)
+	}
+	panic("unexpected call to MockViewRepo.Rename")
+}
+
 var _ domain.ViewRepository = (*MockViewRepo)(nil)
 
 // === Search Repository Mock ===
 
 // MockSearchRepo implements domain.SearchRepository for testing.
 type MockSearchRepo struct {
-	SearchFn func(ctx context.Context, query string, objectType *string, maxResults int, offset int) ([]domain.SearchResult, int64, error)
+	SearchFn  func(ctx context.Context, query string, objectType *string, maxResults int, offset int) ([]domain.SearchResult, int64, error)
+	ReindexFn func(ctx context.Context) (*domain.SearchIndexStats, error)
 }
 
 // Search implements the interface method for testing.
@@ -342,6 +415,14 @@ func (m *MockSearchRepo) Search(ctx context.Context, query string, objectType *s
 	panic("unexpected call to MockSearchRepo.Search")
 }
 
+// Reindex implements the interface method for testing.
+func (m *MockSearchRepo) Reindex(ctx context.Context) (*domain.SearchIndexStats, error) {
+	if m.ReindexFn != nil {
+		return m.ReindexFn(ctx)
+	}
+	panic("unexpected call to MockSearchRepo.Reindex")
+}
+
 var _ domain.SearchRepository = (*MockSearchRepo)(nil)
 
 // === Catalog Repository Mock ===
@@ -351,21 +432,28 @@ var _ domain.SearchRepository = (*MockSearchRepo)(nil)
 type MockCatalogRepo struct {
 	GetCatalogInfoFn       func(ctx context.Context) (*domain.CatalogInfo, error)
 	GetMetastoreSummaryFn  func(ctx context.Context) (*domain.MetastoreSummary, error)
+	GetCatalogStatsFn      func(ctx context.Context, largest int) (*domain.CatalogStats, error)
 	CreateSchemaFn         func(ctx context.Context, name, comment, owner string) (*domain.SchemaDetail, error)
 	GetSchemaFn            func(ctx context.Context, name string) (*domain.SchemaDetail, error)
-	ListSchemasFn          func(ctx context.Context, page domain.PageRequest) ([]domain.SchemaDetail, int64, error)
-	UpdateSchemaFn         func(ctx context.Context, name string, comment *string, props map[string]string) (*domain.SchemaDetail, error)
+	ListSchemasFn          func(ctx context.Context, owner *string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error)
+	UpdateSchemaFn         func(ctx context.Context, name string, comment *string, props map[string]string, expectedRowVersion *int64) (*domain.SchemaDetail, error)
 	DeleteSchemaFn         func(ctx context.Context, name string, force bool) error
 	CreateTableFn          func(ctx context.Context, schemaName string, req domain.CreateTableRequest, owner string) (*domain.TableDetail, error)
 	CreateExternalTableFn  func(ctx context.Context, schemaName string, req domain.CreateTableRequest, owner string) (*domain.TableDetail, error)
+	ListFilesFn            func(ctx context.Context, pattern string) ([]string, error)
 	GetTableFn             func(ctx context.Context, schemaName, tableName string) (*domain.TableDetail, error)
-	ListTablesFn           func(ctx context.Context, schemaName string, page domain.PageRequest) ([]domain.TableDetail, int64, error)
+	ListTablesFn           func(ctx context.Context, schemaName string, owner *string, page domain.PageRequest) ([]domain.TableDetail, int64, error)
 	DeleteTableFn          func(ctx context.Context, schemaName, tableName string) error
-	UpdateTableFn          func(ctx context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string) (*domain.TableDetail, error)
+	UpdateTableFn          func(ctx context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string, expectedRowVersion *int64) (*domain.TableDetail, error)
 	UpdateCatalogFn        func(ctx context.Context, comment *string) (*domain.CatalogInfo, error)
 	UpdateColumnFn         func(ctx context.Context, schemaName, tableName, columnName string, comment *string, props map[string]string) (*domain.ColumnDetail, error)
 	ListColumnsFn          func(ctx context.Context, schemaName, tableName string, page domain.PageRequest) ([]domain.ColumnDetail, int64, error)
 	SetSchemaStoragePathFn func(ctx context.Context, schemaID string, path string) error
+	CompactTableFn         func(ctx context.Context, schemaName, tableName string) (*domain.TableMaintenanceResult, error)
+	VacuumTableFn          func(ctx context.Context, schemaName, tableName string, olderThan time.Duration) (*domain.TableMaintenanceResult, error)
+	RollbackTableFn        func(ctx context.Context, schemaName, tableName string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error)
+	MaterializeTableFn     func(ctx context.Context, schemaName, tableName, query string) (int64, error)
+	RenameTableFn          func(ctx context.Context, schemaName, tableName, newName string) (*domain.TableDetail, error)
 }
 
 // GetCatalogInfo implements the interface method for testing.
@@ -384,6 +472,14 @@ func (m *MockCatalogRepo) GetMetastoreSummary(ctx context.Context) (*domain.Meta
 	panic("unexpected call to MockCatalogRepo.GetMetastoreSummary")
 }
 
+// GetCatalogStats implements the interface method for testing.
+func (m *MockCatalogRepo) GetCatalogStats(ctx context.Context, largest int) (*domain.CatalogStats, error) {
+	if m.GetCatalogStatsFn != nil {
+		return m.GetCatalogStatsFn(ctx, largest)
+	}
+	panic("unexpected call to MockCatalogRepo.GetCatalogStats")
+}
+
 // CreateSchema implements the interface method for testing.
 func (m *MockCatalogRepo) CreateSchema(ctx context.Context, name, comment, owner string) (*domain.SchemaDetail, error) {
 	if m.CreateSchemaFn != nil {
@@ -401,17 +497,17 @@ func (m *MockCatalogRepo) GetSchema(ctx context.Context, name string) (*domain.S
 }
 
 // ListSchemas implements the interface method for testing.
-func (m *MockCatalogRepo) ListSchemas(ctx context.Context, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
+func (m *MockCatalogRepo) ListSchemas(ctx context.Context, owner *string, page domain.PageRequest) ([]domain.SchemaDetail, int64, error) {
 	if m.ListSchemasFn != nil {
-		return m.ListSchemasFn(ctx, page)
+		return m.ListSchemasFn(ctx, owner, page)
 	}
 	panic("unexpected call to MockCatalogRepo.ListSchemas")
 }
 
 // UpdateSchema implements the interface method for testing.
-func (m *MockCatalogRepo) UpdateSchema(ctx context.Context, name string, comment *string, props map[string]string) (*domain.SchemaDetail, error) {
+func (m *MockCatalogRepo) UpdateSchema(ctx context.Context, name string, comment *string, props map[string]string, expectedRowVersion *int64) (*domain.SchemaDetail, error) {
 	if m.UpdateSchemaFn != nil {
-		return m.UpdateSchemaFn(ctx, name, comment, props)
+		return m.UpdateSchemaFn(ctx, name, comment, props, expectedRowVersion)
 	}
 	panic("unexpected call to MockCatalogRepo.UpdateSchema")
 }
@@ -440,6 +536,14 @@ func (m *MockCatalogRepo) CreateExternalTable(ctx context.Context, schemaName st
 	panic("unexpected call to MockCatalogRepo.CreateExternalTable")
 }
 
+// ListFiles implements the interface method for testing.
+func (m *MockCatalogRepo) ListFiles(ctx context.Context, pattern string) ([]string, error) {
+	if m.ListFilesFn != nil {
+		return m.ListFilesFn(ctx, pattern)
+	}
+	panic("unexpected call to MockCatalogRepo.ListFiles")
+}
+
 // GetTable implements the interface method for testing.
 func (m *MockCatalogRepo) GetTable(ctx context.Context, schemaName, tableName string) (*domain.TableDetail, error) {
 	if m.GetTableFn != nil {
@@ -449,9 +553,9 @@ func (m *MockCatalogRepo) GetTable(ctx context.Context, schemaName, tableName st
 }
 
 // ListTables implements the interface method for testing.
-func (m *MockCatalogRepo) ListTables(ctx context.Context, schemaName string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
+func (m *MockCatalogRepo) ListTables(ctx context.Context, schemaName string, owner *string, page domain.PageRequest) ([]domain.TableDetail, int64, error) {
 	if m.ListTablesFn != nil {
-		return m.ListTablesFn(ctx, schemaName, page)
+		return m.ListTablesFn(ctx, schemaName, owner, page)
 	}
 	panic("unexpected call to MockCatalogRepo.ListTables")
 }
@@ -465,9 +569,9 @@ func (m *MockCatalogRepo) DeleteTable(ctx context.Context, schemaName, tableName
 }
 
 // UpdateTable implements the interface method for testing.
-func (m *MockCatalogRepo) UpdateTable(ctx context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string) (*domain.TableDetail, error) {
+func (m *MockCatalogRepo) UpdateTable(ctx context.Context, schemaName, tableName string, comment *string, props map[string]string, owner *string, expectedRowVersion *int64) (*domain.TableDetail, error) {
 	if m.UpdateTableFn != nil {
-		return m.UpdateTableFn(ctx, schemaName, tableName, comment, props, owner)
+		return m.UpdateTableFn(ctx, schemaName, tableName, comment, props, owner, expectedRowVersion)
 	}
 	panic("unexpected call to MockCatalogRepo.UpdateTable")
 }
@@ -504,6 +608,46 @@ func (m *MockCatalogRepo) SetSchemaStoragePath(ctx context.Context, schemaID str
 	panic("unexpected call to MockCatalogRepo.SetSchemaStoragePath")
 }
 
+// CompactTable implements the interface method for testing.
+func (m *MockCatalogRepo) CompactTable(ctx context.Context, schemaName, tableName string) (*domain.TableMaintenanceResult, error) {
+	if m.CompactTableFn != nil {
+		return m.CompactTableFn(ctx, schemaName, tableName)
+	}
+	panic("unexpected call to MockCatalogRepo.CompactTable")
+}
+
+// VacuumTable implements the interface method for testing.
+func (m *MockCatalogRepo) VacuumTable(ctx context.Context, schemaName, tableName string, olderThan time.Duration) (*domain.TableMaintenanceResult, error) {
+	if m.VacuumTableFn != nil {
+		return m.VacuumTableFn(ctx, schemaName, tableName, olderThan)
+	}
+	panic("unexpected call to MockCatalogRepo.VacuumTable")
+}
+
+// RollbackTable implements the interface method for testing.
+func (m *MockCatalogRepo) RollbackTable(ctx context.Context, schemaName, tableName string, req domain.RollbackTableRequest) (*domain.TableRollbackResult, error) {
+	if m.RollbackTableFn != nil {
+		return m.RollbackTableFn(ctx, schemaName, tableName, req)
+	}
+	panic("unexpected call to MockCatalogRepo.RollbackTable")
+}
+
+// MaterializeTable implements the interface method for testing.
+func (m *MockCatalogRepo) MaterializeTable(ctx context.Context, schemaName, tableName, query string) (int64, error) {
+	if m.MaterializeTableFn != nil {
+		return m.MaterializeTableFn(ctx, schemaName, tableName, query)
+	}
+	panic("unexpected call to MockCatalogRepo.MaterializeTable")
+}
+
+// RenameTable implements the interface method for testing.
+func (m *MockCatalogRepo) RenameTable(ctx context.Context, schemaName, tableName, newName string) (*domain.TableDetail, error) {
+	if m.RenameTableFn != nil {
+		return m.RenameTableFn(ctx, schemaName, tableName, newName)
+	}
+	panic("unexpected call to MockCatalogRepo.RenameTable")
+}
+
 var _ domain.CatalogRepository = (*MockCatalogRepo)(nil)
 
 // === Storage Credential Repository Mock ===
@@ -771,7 +915,8 @@ var _ domain.DuckDBExecutor = (*MockDuckDBExecutor)(nil)
 type MockVolumeRepo struct {
 	CreateFn    func(ctx context.Context, vol *domain.Volume) (*domain.Volume, error)
 	GetByNameFn func(ctx context.Context, schemaName, name string) (*domain.Volume, error)
-	ListFn      func(ctx context.Context, schemaName string, page domain.PageRequest) ([]domain.Volume, int64, error)
+	GetByIDFn   func(ctx context.Context, id string) (*domain.Volume, error)
+	ListFn      func(ctx context.Context, schemaName string, owner *string, page domain.PageRequest) ([]domain.Volume, int64, error)
 	UpdateFn    func(ctx context.Context, id string, req domain.UpdateVolumeRequest) (*domain.Volume, error)
 	DeleteFn    func(ctx context.Context, id string) error
 }
@@ -792,10 +937,18 @@ func (m *MockVolumeRepo) GetByName(ctx context.Context, schemaName, name string)
 	panic("unexpected call to MockVolumeRepo.GetByName")
 }
 
+// GetByID implements the interface method for testing.
+func (m *MockVolumeRepo) GetByID(ctx context.Context, id string) (*domain.Volume, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	panic("unexpected call to MockVolumeRepo.GetByID")
+}
+
 // List implements the interface method for testing.
-func (m *MockVolumeRepo) List(ctx context.Context, schemaName string, page domain.PageRequest) ([]domain.Volume, int64, error) {
+func (m *MockVolumeRepo) List(ctx context.Context, schemaName string, owner *string, page domain.PageRequest) ([]domain.Volume, int64, error) {
 	if m.ListFn != nil {
-		return m.ListFn(ctx, schemaName, page)
+		return m.ListFn(ctx, schemaName, owner, page)
 	}
 	panic("unexpected call to MockVolumeRepo.List")
 }
@@ -884,19 +1037,20 @@ var _ domain.IntrospectionRepository = (*MockIntrospectionRepo)(nil)
 
 // MockNotebookRepo implements domain.NotebookRepository for testing.
 type MockNotebookRepo struct {
-	CreateNotebookFn   func(ctx context.Context, nb *domain.Notebook) (*domain.Notebook, error)
-	GetNotebookFn      func(ctx context.Context, id string) (*domain.Notebook, error)
-	ListNotebooksFn    func(ctx context.Context, owner *string, page domain.PageRequest) ([]domain.Notebook, int64, error)
-	UpdateNotebookFn   func(ctx context.Context, id string, req domain.UpdateNotebookRequest) (*domain.Notebook, error)
-	DeleteNotebookFn   func(ctx context.Context, id string) error
-	CreateCellFn       func(ctx context.Context, cell *domain.Cell) (*domain.Cell, error)
-	GetCellFn          func(ctx context.Context, id string) (*domain.Cell, error)
-	ListCellsFn        func(ctx context.Context, notebookID string) ([]domain.Cell, error)
-	UpdateCellFn       func(ctx context.Context, id string, req domain.UpdateCellRequest) (*domain.Cell, error)
-	DeleteCellFn       func(ctx context.Context, id string) error
-	UpdateCellResultFn func(ctx context.Context, cellID string, result *string) error
-	ReorderCellsFn     func(ctx context.Context, notebookID string, cellIDs []string) error
-	GetMaxPositionFn   func(ctx context.Context, notebookID string) (int, error)
+	CreateNotebookFn         func(ctx context.Context, nb *domain.Notebook) (*domain.Notebook, error)
+	GetNotebookFn            func(ctx context.Context, id string) (*domain.Notebook, error)
+	ListNotebooksFn          func(ctx context.Context, owner *string, page domain.PageRequest) ([]domain.Notebook, int64, error)
+	ListNotebooksByGitRepoFn func(ctx context.Context, gitRepoID string) ([]domain.Notebook, error)
+	UpdateNotebookFn         func(ctx context.Context, id string, req domain.UpdateNotebookRequest) (*domain.Notebook, error)
+	DeleteNotebookFn         func(ctx context.Context, id string) error
+	CreateCellFn             func(ctx context.Context, cell *domain.Cell) (*domain.Cell, error)
+	GetCellFn                func(ctx context.Context, id string) (*domain.Cell, error)
+	ListCellsFn              func(ctx context.Context, notebookID string) ([]domain.Cell, error)
+	UpdateCellFn             func(ctx context.Context, id string, req domain.UpdateCellRequest) (*domain.Cell, error)
+	DeleteCellFn             func(ctx context.Context, id string) error
+	UpdateCellResultFn       func(ctx context.Context, cellID string, result *string) error
+	ReorderCellsFn           func(ctx context.Context, notebookID string, cellIDs []string) error
+	GetMaxPositionFn         func(ctx context.Context, notebookID string) (int, error)
 }
 
 // CreateNotebook implements the interface method for testing.
@@ -923,6 +1077,14 @@ func (m *MockNotebookRepo) ListNotebooks(ctx context.Context, owner *string, pag
 	panic("unexpected call to MockNotebookRepo.ListNotebooks")
 }
 
+// ListNotebooksByGitRepo implements the interface method for testing.
+func (m *MockNotebookRepo) ListNotebooksByGitRepo(ctx context.Context, gitRepoID string) ([]domain.Notebook, error) {
+	if m.ListNotebooksByGitRepoFn != nil {
+		return m.ListNotebooksByGitRepoFn(ctx, gitRepoID)
+	}
+	panic("unexpected call to MockNotebookRepo.ListNotebooksByGitRepo")
+}
+
 // UpdateNotebook implements the interface method for testing.
 func (m *MockNotebookRepo) UpdateNotebook(ctx context.Context, id string, req domain.UpdateNotebookRequest) (*domain.Notebook, error) {
 	if m.UpdateNotebookFn != nil {
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+func installRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	_, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	return recorder
+}
+
+func TestSetup_NoEndpointIsNoop(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestMiddleware_ContinuesIncomingTraceAndNamesRoute(t *testing.T) {
+	recorder := installRecorder(t)
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/v1/catalogs/{catalogName}", func(w http.ResponseWriter, req *http.Request) {
+		_, child := Start(req.Context(), "child")
+		child.End()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	req := httptest.NewRequest(http.MethodGet, "/v1/catalogs/main", nil)
+	otel.GetTextMapPropagator().Inject(trace.ContextWithRemoteSpanContext(context.Background(), parent), propagation.HeaderCarrier(req.Header))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "GET /v1/catalogs/{catalogName}", server.Name())
+	assert.Equal(t, parent.TraceID(), server.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanID(), server.Parent().SpanID())
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+}
+
+func TestMetadata_RoundTrip(t *testing.T) {
+	installRecorder(t)
+
+	ctx, span := Start(context.Background(), "client")
+	defer span.End()
+	out := InjectMetadata(metadata.NewOutgoingContext(ctx, metadata.Pairs("x-request-id", "req-1")))
+
+	md, ok := metadata.FromOutgoingContext(out)
+	require.True(t, ok)
+	assert.Equal(t, []string{"req-1"}, md.Get("x-request-id"), "existing metadata must be preserved")
+
+	in := ExtractMetadata(metadata.NewIncomingContext(context.Background(), md))
+	remote := trace.SpanContextFromContext(in)
+	assert.True(t, remote.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+}
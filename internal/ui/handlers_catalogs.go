@@ -82,7 +82,7 @@ func (h *Handler) renderCatalogWorkspace(w http.ResponseWriter, r *http.Request,
 	}
 
 	summary, summaryErr := h.Catalog.GetMetastoreSummary(r.Context(), catalogName)
-	schemas, _, schemasErr := h.Catalog.ListSchemas(r.Context(), catalogName, domain.PageRequest{MaxResults: 200})
+	schemas, _, schemasErr := h.Catalog.ListSchemas(r.Context(), catalogName, nil, domain.PageRequest{MaxResults: 200})
 	if selectedSchema == "" && len(schemas) > 0 {
 		selectedSchema = schemas[0].Name
 	}
@@ -114,7 +114,7 @@ func (h *Handler) renderCatalogWorkspace(w http.ResponseWriter, r *http.Request,
 			DeleteURL: "/ui/catalogs/" + url.PathEscape(catalogName) + "/schemas/" + url.PathEscape(s.Name) + "/delete",
 		}
 
-		tables, _, tableErr := h.Catalog.ListTables(r.Context(), catalogName, s.Name, domain.PageRequest{MaxResults: 200})
+		tables, _, tableErr := h.Catalog.ListTables(r.Context(), catalogName, s.Name, nil, domain.PageRequest{MaxResults: 200})
 		if tableErr == nil {
 			tableNodes := make([]catalogWorkspaceObjectNodeData, 0, len(tables))
 			for j := range tables {
@@ -131,7 +131,7 @@ func (h *Handler) renderCatalogWorkspace(w http.ResponseWriter, r *http.Request,
 			schemaNode.Tables = tableNodes
 		}
 
-		views, _, viewsErr := h.View.ListViews(r.Context(), catalogName, s.Name, domain.PageRequest{MaxResults: 200})
+		views, _, viewsErr := h.View.ListViews(r.Context(), catalogName, s.Name, nil, domain.PageRequest{MaxResults: 200})
 		if viewsErr == nil {
 			viewNodes := make([]catalogWorkspaceObjectNodeData, 0, len(views))
 			for j := range views {
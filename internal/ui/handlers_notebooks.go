@@ -84,7 +84,7 @@ func (h *Handler) NotebooksDetail(w http.ResponseWriter, r *http.Request) {
 
 	var schemas []domain.SchemaDetail
 	if selectedCatalog != "" {
-		s, _, err := h.Catalog.ListSchemas(r.Context(), selectedCatalog, domain.PageRequest{MaxResults: 200})
+		s, _, err := h.Catalog.ListSchemas(r.Context(), selectedCatalog, nil, domain.PageRequest{MaxResults: 200})
 		if err == nil {
 			schemas = s
 		}
@@ -130,7 +130,7 @@ func (h *Handler) sqlEditorState(r *http.Request, form url.Values) sqlEditorCont
 
 	var schemas []domain.SchemaDetail
 	if selectedCatalog != "" {
-		s, _, err := h.Catalog.ListSchemas(r.Context(), selectedCatalog, domain.PageRequest{MaxResults: 200})
+		s, _, err := h.Catalog.ListSchemas(r.Context(), selectedCatalog, nil, domain.PageRequest{MaxResults: 200})
 		if err == nil {
 			schemas = s
 		}
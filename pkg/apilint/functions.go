@@ -378,6 +378,7 @@ var actionVerbSet = map[string]bool{
 	"setDefaultCatalog": true, "reorderCells": true, "executeCell": true,
 	"runAllCells": true, "syncGitRepo": true, "cancelPipelineRun": true, "cancelModelRun": true,
 	"triggerPipelineRun": true, "explainMetricQuery": true, "runMetricQuery": true,
+	"compactTable": true, "vacuumTable": true, "rollbackTable": true,
 }
 
 func (f *fnCheckPostCreateStatus) RunRule(nodes []*yaml.Node, ctx model.RuleFunctionContext) []model.RuleFunctionResult {
@@ -704,32 +705,34 @@ func (f *fnCheckAuthzMetadataPresent) GetSchema() model.RuleFunctionSchema {
 func (f *fnCheckAuthzMetadataPresent) GetCategory() string { return model.CategoryOperations }
 
 var criticalAuthzOperationIDs = map[string]bool{
-	"createSchema":            true,
-	"updateSchema":            true,
-	"deleteSchema":            true,
-	"createTable":             true,
-	"updateTable":             true,
-	"deleteTable":             true,
-	"updateColumn":            true,
-	"createView":              true,
-	"updateView":              true,
-	"deleteView":              true,
-	"createVolume":            true,
-	"updateVolume":            true,
-	"deleteVolume":            true,
-	"createStorageCredential": true,
-	"updateStorageCredential": true,
-	"deleteStorageCredential": true,
-	"createExternalLocation":  true,
-	"updateExternalLocation":  true,
-	"deleteExternalLocation":  true,
-	"createComputeEndpoint":   true,
-	"updateComputeEndpoint":   true,
-	"deleteComputeEndpoint":   true,
-	"createComputeAssignment": true,
-	"deleteComputeAssignment": true,
-	"createGrant":             true,
-	"deleteGrant":             true,
+	"createSchema":               true,
+	"updateSchema":               true,
+	"deleteSchema":               true,
+	"createTable":                true,
+	"updateTable":                true,
+	"deleteTable":                true,
+	"updateColumn":               true,
+	"createView":                 true,
+	"updateView":                 true,
+	"deleteView":                 true,
+	"createVolume":               true,
+	"updateVolume":               true,
+	"deleteVolume":               true,
+	"createStorageCredential":    true,
+	"updateStorageCredential":    true,
+	"deleteStorageCredential":    true,
+	"createExternalLocation":     true,
+	"updateExternalLocation":     true,
+	"deleteExternalLocation":     true,
+	"createComputeEndpoint":      true,
+	"updateComputeEndpoint":      true,
+	"deleteComputeEndpoint":      true,
+	"createComputeAssignment":    true,
+	"deleteComputeAssignment":    true,
+	"createComputeEndpointAgent": true,
+	"deleteComputeEndpointAgent": true,
+	"createGrant":                true,
+	"deleteGrant":                true,
 }
 
 func (f *fnCheckAuthzMetadataPresent) RunRule(nodes []*yaml.Node, ctx model.RuleFunctionContext) []model.RuleFunctionResult {
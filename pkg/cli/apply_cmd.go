@@ -2,11 +2,18 @@ package cli
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 
 	"duck-demo/internal/declarative"
 	"duck-demo/pkg/cli/gen"
@@ -19,14 +26,64 @@ func newApplyCmd(client *gen.Client) *cobra.Command {
 		noColor                  bool
 		allowUnknownFields       bool
 		legacyOptionalReadErrors bool
+		detectDrift              bool
+		force                    bool
+		lockTTL                  time.Duration
+		resumeRunID              string
+		noResume                 bool
+		parallel                 int
+		rotateCredentials        bool
+		diffOnly                 bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "apply",
 		Short: "Apply declarative configuration changes to the server",
-		Long:  "Reads YAML configuration files, compares with the current server state, and applies the changes.",
+		Long: `Reads YAML configuration files, compares with the current server state, and applies the changes.
+
+Progress is journaled under ~/.duck/apply-journal/<run-id>.json. When an apply
+fails midway, the next apply of the same config dir against the same server
+resumes that run: it re-reads server state, so actions that already took
+effect drop out of the plan, and it reuses the run's idempotency keys so a
+create that succeeded without a response is not duplicated. The server keeps
+idempotency keys in memory only, so that protection does not hold across a
+server restart between the failed run and the resume. Use --resume to pick a
+run explicitly or --no-resume to start afresh.
+
+The server-side apply lock is leased for --lock-ttl and renewed every third
+of that while the apply runs. If a renewal fails, for example because another
+apply broke the lease with --force, the actions not yet started are skipped
+and apply exits 1.
+
+With --parallel N, up to N independent actions run at a time. Actions of the
+same dependency layer run concurrently unless one needs another: a pipeline
+job waits for its pipeline, a view for the tables and views it reads, and a
+model for the models it refs. Each layer completes before the next one
+starts, and the first failure stops the actions not yet started.
+
+Each action is reported as it finishes. At the end, apply reports for each
+resource kind how many resources were created, updated and deleted and how
+many actions failed or were skipped, and how long the actions took; with
+-o json this is the "summary" object.
+
+Storage credential secrets are read at apply time from the environment
+variables the configuration names (key_id_from_env, secret_from_env, ...)
+and are never exported. The server does not return secrets, so changing one
+is not detected; --rotate-credentials updates every declared credential to
+re-send its secrets.
+
+With --diff-only nothing is applied: the plan is shown and the command exits
+0 when there is nothing to change, 2 when there are changes, and 1 on errors,
+including plan errors such as a deletion-protected resource missing from the
+configuration. Use it as a no-drift gate in CI.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			isJSON := getOutputFormat(cmd) == "json"
+			if parallel < 1 {
+				return fmt.Errorf("--parallel must be at least 1")
+			}
+			if lockTTL < time.Second {
+				return fmt.Errorf("--lock-ttl must be at least 1s")
+			}
 
 			compatMode := CapabilityCompatibilityStrict
 			if legacyOptionalReadErrors {
@@ -61,8 +118,58 @@ func newApplyCmd(client *gen.Client) *cobra.Command {
 				os.Exit(1)
 			}
 
-			// 3. Read current state from server.
-			stateClient := NewAPIStateClientWithOptions(client, APIStateClientOptions{CompatibilityMode: compatMode})
+			// 2.5. With --diff-only, only report whether anything would change.
+			if diffOnly {
+				reader := NewAPIStateClientWithOptions(client, APIStateClientOptions{CompatibilityMode: compatMode})
+				return runApplyDiffOnly(cmd, reader, desired, rotateCredentials, noColor)
+			}
+
+			// 3. Pick the run to resume, if any, so the state client reuses
+			// its idempotency keys.
+			journal, err := selectApplyJournal(client.BaseURL, configDir, resumeRunID, noResume)
+			if err != nil {
+				return err
+			}
+			resumed := journal != nil
+			runID := ""
+			if resumed {
+				runID = journal.RunID
+			}
+
+			// 3.1. Take the server-side apply lock so concurrent applies cannot
+			// interleave, then read current state from server.
+			stateClient := NewAPIStateClientWithOptions(client, APIStateClientOptions{
+				CompatibilityMode: compatMode,
+				ApplyRunID:        runID,
+			})
+			if !resumed {
+				journal = newApplyJournal(ApplyJournalDir(), stateClient.ApplyRunID(), client.BaseURL, absConfigDir(configDir))
+			}
+			lease, err := stateClient.AcquireApplyLock(cmd.Context(), applyLockOwner(), lockTTL, force)
+			switch {
+			case errors.Is(err, errApplyLockUnsupported):
+				_, _ = fmt.Fprintf(os.Stderr, "warning: %v; applying without a lock\n", err)
+			case err != nil:
+				return err
+			}
+			// Renew the lease while the apply runs so a long apply does not
+			// outlive it and let a second apply in.
+			applyCtx, stopHeartbeat := cmd.Context(), func() {}
+			if lease != nil {
+				applyCtx, stopHeartbeat = heartbeatApplyLock(cmd.Context(), stateClient, lease.LeaseID, lockTTL, lockTTL/3)
+			}
+			releaseLock := func() {
+				stopHeartbeat()
+				if lease == nil {
+					return
+				}
+				if err := stateClient.ReleaseApplyLock(cmd.Context(), lease.LeaseID); err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+				}
+				lease = nil
+			}
+			defer releaseLock()
+
 			actual, err := stateClient.ReadState(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("read server state: %w", err)
@@ -73,17 +180,45 @@ func newApplyCmd(client *gen.Client) *cobra.Command {
 				}
 			}
 
+			// 3.5. Report out-of-band changes since the last successful apply.
+			var drift []declarative.DriftEntry
+			if detectDrift {
+				snapshot, err := LoadAppliedSnapshot(StateDir(), client.BaseURL)
+				if err != nil {
+					return fmt.Errorf("load last-applied snapshot: %w", err)
+				}
+				if snapshot == nil {
+					_, _ = fmt.Fprintf(os.Stderr, "warning: no last-applied snapshot for %s; drift detection starts after this apply\n", client.BaseURL)
+				} else {
+					drift = declarative.DetectDrift(snapshot.State, actual)
+					if !isJSON {
+						declarative.FormatDriftText(os.Stdout, drift, noColor)
+					}
+				}
+			}
+
 			// 4. Diff desired vs actual.
 			plan := declarative.Diff(desired, actual)
+			if rotateCredentials {
+				declarative.RotateStorageCredentials(plan, desired.StorageCredentials, actual.StorageCredentials)
+			}
 
 			if !plan.HasChanges() {
+				if resumed {
+					finishApplyJournal(journal)
+				}
+				recordAppliedSnapshot(client.BaseURL, actual)
 				if isJSON {
-					return gen.PrintJSON(os.Stdout, map[string]interface{}{
+					out := map[string]interface{}{
 						"status":    "ok",
 						"changes":   false,
 						"succeeded": 0,
 						"failed":    0,
-					})
+					}
+					if detectDrift {
+						out["drift"] = drift
+					}
+					return gen.PrintJSON(os.Stdout, out)
 				}
 				declarative.FormatText(os.Stdout, plan, noColor)
 				return nil
@@ -93,6 +228,9 @@ func newApplyCmd(client *gen.Client) *cobra.Command {
 			if !isJSON {
 				declarative.FormatText(os.Stdout, plan, noColor)
 			}
+			if resumed {
+				reportResume(journal, plan.Actions)
+			}
 
 			// 6. Confirm unless auto-approved.
 			if !autoApprove {
@@ -121,82 +259,73 @@ func newApplyCmd(client *gen.Client) *cobra.Command {
 				return fmt.Errorf("preflight capability validation: %w", err)
 			}
 
-			// 7. Execute each action.
-			type actionResult struct {
-				Operation    string `json:"operation"`
-				ResourceKind string `json:"resource_kind"`
-				ResourceName string `json:"resource_name"`
-				Status       string `json:"status"`
-				Error        string `json:"error,omitempty"`
+			// 7. Execute each action. Membership changes are batched so each
+			// group's members are set with one call.
+			stateClient.PrepareGroupMemberSync(plan.Actions)
+			if err := journal.Start(len(plan.Actions)); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: %v; this apply cannot be resumed\n", err)
 			}
-			results := make([]actionResult, 0, len(plan.Actions))
-			var succeeded, failed int
-			failedAt := -1
-			for i, action := range plan.Actions {
-				if !isJSON {
-					_, _ = fmt.Fprintf(os.Stdout, "  %s %s %q ... ",
-						action.Operation, action.ResourceKind, action.ResourceName)
-				}
-
-				err := stateClient.Execute(cmd.Context(), action)
-				if err != nil {
-					if !isJSON {
-						_, _ = fmt.Fprintf(os.Stdout, "failed: %v\n", err)
-					}
-					results = append(results, actionResult{
-						Operation:    action.Operation.String(),
-						ResourceKind: action.ResourceKind.String(),
-						ResourceName: action.ResourceName,
-						Status:       "failed",
-						Error:        err.Error(),
-					})
-					failed++
-					failedAt = i
-					break
-				}
-				if !isJSON {
-					_, _ = fmt.Fprintln(os.Stdout, "succeeded")
-				}
-				results = append(results, actionResult{
-					Operation:    action.Operation.String(),
-					ResourceKind: action.ResourceKind.String(),
-					ResourceName: action.ResourceName,
-					Status:       "succeeded",
-				})
-				succeeded++
-			}
-
-			if failedAt >= 0 {
-				for _, action := range plan.Actions[failedAt+1:] {
-					results = append(results, actionResult{
-						Operation:    action.Operation.String(),
-						ResourceKind: action.ResourceKind.String(),
-						ResourceName: action.ResourceName,
-						Status:       "skipped",
-						Error:        "not executed due to earlier failure",
-					})
-				}
+			var progress io.Writer
+			if !isJSON {
+				progress = os.Stdout
+			}
+			started := time.Now()
+			results, succeeded, failed := executeApplyActions(applyCtx, stateClient, plan.Actions, journal, progress, parallel)
+			summary := summarizeApplyResults(results, time.Since(started))
+			lockErr := context.Cause(applyCtx)
+			if !errors.Is(lockErr, errApplyLockLost) {
+				lockErr = nil
 			}
 
 			// 8. Print summary.
 			if isJSON {
 				status := "ok"
-				if failed > 0 {
+				if failed > 0 || lockErr != nil {
 					status = "partial"
 				}
-				_ = gen.PrintJSON(os.Stdout, map[string]interface{}{
+				out := map[string]interface{}{
 					"status":    status,
 					"changes":   true,
+					"run_id":    journal.RunID,
 					"succeeded": succeeded,
 					"failed":    failed,
 					"actions":   results,
-				})
+					"summary":   summary,
+				}
+				if detectDrift {
+					out["drift"] = drift
+				}
+				if lockErr != nil {
+					out["error"] = lockErr.Error()
+				}
+				_ = gen.PrintJSON(os.Stdout, out)
 			} else {
-				_, _ = fmt.Fprintf(os.Stdout, "\nApply complete: %d succeeded, %d failed.\n", succeeded, failed)
+				_, _ = fmt.Fprintf(os.Stdout, "\nApply complete: %d succeeded, %d failed in %s.\n",
+					succeeded, failed, summary.Duration.Round(time.Millisecond))
+				printApplySummary(os.Stdout, summary)
+				if lockErr != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "error: %v; remaining actions were skipped\n", lockErr)
+				}
+				if failed > 0 || lockErr != nil {
+					_, _ = fmt.Fprintf(os.Stdout, "Resume with: duck apply --resume %s\n", journal.RunID)
+				}
 			}
-			if failed > 0 {
+			if failed > 0 || lockErr != nil {
+				if lockErr != nil {
+					lease = nil // no longer ours to release
+				}
+				releaseLock()
 				os.Exit(1)
 			}
+			finishApplyJournal(journal)
+
+			// 9. Record the post-apply server state as the drift baseline.
+			applied, err := stateClient.ReadState(cmd.Context())
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: read state for last-applied snapshot: %v\n", err)
+				return nil
+			}
+			recordAppliedSnapshot(client.BaseURL, applied)
 
 			return nil
 		},
@@ -206,7 +335,337 @@ func newApplyCmd(client *gen.Client) *cobra.Command {
 	cmd.Flags().BoolVar(&autoApprove, "auto-approve", false, "Skip interactive confirmation prompt")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	cmd.Flags().BoolVar(&allowUnknownFields, "allow-unknown-fields", false, "Allow unknown YAML fields in declarative config")
-	cmd.Flags().BoolVar(&legacyOptionalReadErrors, "legacy-optional-read-errors", false, "Treat transport errors as optional for model/macro capability checks")
+	cmd.Flags().BoolVar(&legacyOptionalReadErrors, "legacy-optional-read-errors", false, "Treat transport errors as optional for model/macro capability checks on servers without the capabilities handshake")
+	cmd.Flags().BoolVar(&detectDrift, "detect-drift", false, "Report resources changed outside of apply since the last successful apply")
+	cmd.Flags().BoolVar(&force, "force", false, "Break an apply lock held by another (e.g. crashed) apply")
+	cmd.Flags().DurationVar(&lockTTL, "lock-ttl", 10*time.Minute, "Lease duration of the server-side apply lock")
+	cmd.Flags().StringVar(&resumeRunID, "resume", "", "Resume the failed apply run with this ID")
+	cmd.Flags().BoolVar(&noResume, "no-resume", false, "Start a new run even if an earlier apply of this config dir failed")
+	cmd.Flags().IntVar(&parallel, "parallel", 1, "Maximum number of independent actions to execute concurrently")
+	cmd.Flags().BoolVar(&rotateCredentials, "rotate-credentials", false, "Re-send the secrets of all declared storage credentials from their environment variables")
+	cmd.Flags().BoolVar(&diffOnly, "diff-only", false, "Show the plan without applying it; exit 2 if there are changes")
+	cmd.MarkFlagsMutuallyExclusive("resume", "no-resume")
+	cmd.MarkFlagsMutuallyExclusive("diff-only", "resume")
 
 	return cmd
 }
+
+// osExit ends the process with a status code; tests replace it.
+var osExit = os.Exit
+
+// runApplyDiffOnly shows the plan for desired against the server state and
+// exits 2 if it has actions. Plan errors are returned, so the command exits
+// 1. Nothing is applied and no apply lock is taken.
+func runApplyDiffOnly(cmd *cobra.Command, reader *APIStateClient, desired *declarative.DesiredState, rotateCredentials, noColor bool) error {
+	isJSON := getOutputFormat(cmd) == "json"
+	actual, err := reader.ReadState(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("read server state: %w", err)
+	}
+	if !isJSON {
+		for _, warning := range reader.OptionalReadWarnings() {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+	}
+
+	plan := declarative.Diff(desired, actual)
+	if rotateCredentials {
+		declarative.RotateStorageCredentials(plan, desired.StorageCredentials, actual.StorageCredentials)
+	}
+	out := cmd.OutOrStdout()
+	if isJSON {
+		if err := declarative.FormatJSON(out, plan); err != nil {
+			return fmt.Errorf("format plan: %w", err)
+		}
+	} else {
+		declarative.FormatText(out, plan, noColor)
+	}
+
+	if len(plan.Errors) > 0 {
+		return fmt.Errorf("plan has %d error(s)", len(plan.Errors))
+	}
+	if len(plan.Actions) > 0 {
+		osExit(2)
+	}
+	return nil
+}
+
+// recordAppliedSnapshot stores the server state as the last-applied baseline.
+// Failures are reported as warnings because the apply itself succeeded.
+func recordAppliedSnapshot(host string, state *declarative.DesiredState) {
+	if err := SaveAppliedSnapshot(StateDir(), host, state); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: write last-applied snapshot: %v\n", err)
+	}
+}
+
+// applyActionResult is the outcome of one action of duck apply.
+type applyActionResult struct {
+	Operation    string `json:"operation"`
+	ResourceKind string `json:"resource_kind"`
+	ResourceName string `json:"resource_name"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+// executeApplyActions runs actions in plan order, up to parallel of them at
+// a time. Consecutive actions of the same dependency layer, all creates and
+// updates or all deletes, may run concurrently once the actions they depend
+// on, such as a pipeline job's pipeline, have succeeded; each layer finishes
+// before the next starts. The first failure
+// cancels the remaining work, and actions not yet started are reported as
+// skipped. Each completed action and the failure are recorded in journal.
+// Progress lines, numbered in the order actions finish, go to progress when
+// it is not nil.
+func executeApplyActions(ctx context.Context, sc *APIStateClient, actions []declarative.Action, journal *ApplyJournal, progress io.Writer, parallel int) ([]applyActionResult, int, int) {
+	if parallel < 1 {
+		parallel = 1
+	}
+	results := make([]applyActionResult, len(actions))
+	var (
+		mu                sync.Mutex // guards journal, progress and the counts
+		succeeded, failed int
+	)
+	// step numbers the progress lines in the order actions finish.
+	step := func() string {
+		return fmt.Sprintf("[%d/%d]", succeeded+failed, len(actions))
+	}
+	run := func(ctx context.Context, i int) error {
+		action := actions[i]
+		if ctx.Err() != nil {
+			results[i] = skippedApplyResult(action)
+			return nil
+		}
+		results[i] = applyActionResult{
+			Operation:    action.Operation.String(),
+			ResourceKind: action.ResourceKind.String(),
+			ResourceName: action.ResourceName,
+		}
+		result := &results[i]
+
+		// The journal says this action ran, yet the fresh plan still
+		// contains it, so the server no longer reflects it. New keys make
+		// the server run it again instead of replaying the old response.
+		mu.Lock()
+		if journal.HasCompleted(action) {
+			sc.ReapplyWithNewKeys(action)
+		}
+		mu.Unlock()
+
+		err := sc.Execute(ctx, action)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			failed++
+			if progress != nil {
+				_, _ = fmt.Fprintf(progress, "  %s %s %s %q ... failed: %v\n",
+					step(), action.Operation, action.ResourceKind, action.ResourceName, err)
+			}
+			if jerr := journal.RecordFailed(action, err); jerr != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: %v\n", jerr)
+			}
+			result.Status = "failed"
+			result.Error = err.Error()
+			return err
+		}
+		if jerr := journal.RecordCompleted(action); jerr != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %v\n", jerr)
+		}
+		succeeded++
+		if progress != nil {
+			_, _ = fmt.Fprintf(progress, "  %s %s %s %q ... succeeded\n",
+				step(), action.Operation, action.ResourceKind, action.ResourceName)
+		}
+		result.Status = "succeeded"
+		return nil
+	}
+
+	deps := declarative.ActionDependencies(actions)
+	done := make([]chan struct{}, len(actions))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	start := 0
+	for start < len(actions) {
+		end := start + 1
+		for end < len(actions) && sameApplyLayer(actions[start], actions[end]) {
+			end++
+		}
+		layer := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			layer = append(layer, i)
+		}
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(parallel)
+		// Actions start in dependency order, so an action only ever waits for
+		// actions that are already running or done.
+		for _, i := range declarative.DependencyOrder(deps, layer) {
+			g.Go(func() error {
+				defer close(done[i])
+				for _, d := range deps[i] {
+					<-done[d]
+				}
+				return run(gctx, i)
+			})
+		}
+		err := g.Wait()
+		start = end
+		if err != nil {
+			break
+		}
+	}
+	for i := start; i < len(actions); i++ {
+		results[i] = skippedApplyResult(actions[i])
+	}
+	return results, succeeded, failed
+}
+
+func skippedApplyResult(action declarative.Action) applyActionResult {
+	return applyActionResult{
+		Operation:    action.Operation.String(),
+		ResourceKind: action.ResourceKind.String(),
+		ResourceName: action.ResourceName,
+		Status:       "skipped",
+		Error:        "not executed due to earlier failure",
+	}
+}
+
+// applySummary totals the results of duck apply by resource kind, with the
+// time the actions took. JSON output carries the duration in milliseconds.
+type applySummary struct {
+	Kinds      []applyKindSummary `json:"kinds"`
+	Duration   time.Duration      `json:"-"`
+	DurationMS int64              `json:"duration_ms"`
+}
+
+// applyKindSummary counts the actions of one resource kind. Created, Updated
+// and Deleted count succeeded actions only.
+type applyKindSummary struct {
+	ResourceKind string `json:"resource_kind"`
+	Created      int    `json:"created"`
+	Updated      int    `json:"updated"`
+	Deleted      int    `json:"deleted"`
+	Failed       int    `json:"failed"`
+	Skipped      int    `json:"skipped"`
+}
+
+// summarizeApplyResults groups results by resource kind, in the order the
+// kinds first appear in the plan.
+func summarizeApplyResults(results []applyActionResult, elapsed time.Duration) applySummary {
+	summary := applySummary{Kinds: []applyKindSummary{}, Duration: elapsed, DurationMS: elapsed.Milliseconds()}
+	index := make(map[string]int)
+	for _, r := range results {
+		i, ok := index[r.ResourceKind]
+		if !ok {
+			i = len(summary.Kinds)
+			index[r.ResourceKind] = i
+			summary.Kinds = append(summary.Kinds, applyKindSummary{ResourceKind: r.ResourceKind})
+		}
+		k := &summary.Kinds[i]
+		switch r.Status {
+		case "failed":
+			k.Failed++
+		case "skipped":
+			k.Skipped++
+		case "succeeded":
+			switch r.Operation {
+			case declarative.OpCreate.String():
+				k.Created++
+			case declarative.OpUpdate.String():
+				k.Updated++
+			case declarative.OpDelete.String():
+				k.Deleted++
+			}
+		}
+	}
+	return summary
+}
+
+// printApplySummary writes one line per resource kind, such as
+// "  principal   2 created, 1 failed".
+func printApplySummary(w io.Writer, summary applySummary) {
+	width := 0
+	for _, k := range summary.Kinds {
+		width = max(width, len(k.ResourceKind))
+	}
+	for _, k := range summary.Kinds {
+		var counts []string
+		for _, c := range []struct {
+			n    int
+			verb string
+		}{{k.Created, "created"}, {k.Updated, "updated"}, {k.Deleted, "deleted"}, {k.Failed, "failed"}, {k.Skipped, "skipped"}} {
+			if c.n > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", c.n, c.verb))
+			}
+		}
+		_, _ = fmt.Fprintf(w, "  %-*s  %s\n", width, k.ResourceKind, strings.Join(counts, ", "))
+	}
+}
+
+// sameApplyLayer reports whether a and b are in the same group of actions
+// that may run concurrently: the same dependency layer, and both deletes or
+// both creates and updates. Within a group, declarative.ActionDependencies
+// says which actions still have to wait for others.
+func sameApplyLayer(a, b declarative.Action) bool {
+	return a.ResourceKind.Layer() == b.ResourceKind.Layer() &&
+		(a.Operation == declarative.OpDelete) == (b.Operation == declarative.OpDelete)
+}
+
+// selectApplyJournal returns the journal of the run to resume: the run named
+// by resumeRunID, otherwise the latest unfinished run of configDir against
+// host unless noResume is set. It returns nil to start a new run.
+func selectApplyJournal(host, configDir, resumeRunID string, noResume bool) (*ApplyJournal, error) {
+	dir := ApplyJournalDir()
+	if resumeRunID != "" {
+		journal, err := LoadApplyJournal(dir, resumeRunID)
+		if err != nil {
+			return nil, err
+		}
+		if journal.Host != host {
+			return nil, fmt.Errorf("apply run %s targeted %s, not %s", resumeRunID, journal.Host, host)
+		}
+		return journal, nil
+	}
+	if noResume {
+		return nil, nil
+	}
+	journal, err := FindResumableJournal(dir, host, absConfigDir(configDir))
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %v; starting a new apply run\n", err)
+		return nil, nil
+	}
+	return journal, nil
+}
+
+// reportResume tells the user which run is resumed and how its journal
+// reconciles with the fresh plan.
+func reportResume(journal *ApplyJournal, actions []declarative.Action) {
+	var reapply int
+	for _, action := range actions {
+		if journal.HasCompleted(action) {
+			reapply++
+		}
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Resuming apply run %s: %d action(s) already applied, %d remaining.\n",
+		journal.RunID, len(journal.Completed)-reapply, len(actions))
+	if reapply > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %d action(s) recorded as applied are not reflected on the server and will be applied again\n", reapply)
+	}
+}
+
+// finishApplyJournal removes the journal of a completed run. Failures are
+// warnings because the apply itself succeeded.
+func finishApplyJournal(journal *ApplyJournal) {
+	if err := journal.Finish(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
+
+// absConfigDir returns configDir as an absolute path, so journals match the
+// same directory whatever the working directory.
+func absConfigDir(configDir string) string {
+	if abs, err := filepath.Abs(configDir); err == nil {
+		return abs
+	}
+	return configDir
+}
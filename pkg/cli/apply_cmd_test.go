@@ -0,0 +1,388 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/declarative"
+	"duck-demo/pkg/cli/gen"
+)
+
+// parallelApplyServer creates principals and groups, holding each principal
+// create until release principal creates are in flight at once.
+type parallelApplyServer struct {
+	release int
+	failing map[string]bool
+
+	mu                  sync.Mutex
+	inFlight            int
+	maxInFlight         int
+	principalsCreated   int
+	principalsAtGroup   int
+	principalsAtMembers int
+	members             []string
+	released            chan struct{}
+}
+
+func newParallelApplyServer(t *testing.T, release int) (*parallelApplyServer, *gen.Client) {
+	t.Helper()
+	s := &parallelApplyServer{release: release, failing: map[string]bool{}, released: make(chan struct{})}
+	srv := httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(srv.Close)
+	client := gen.NewClient(srv.URL, "", "test-token")
+	return s, client
+}
+
+func (s *parallelApplyServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/principals":
+		name, _ := body["name"].(string)
+		s.mu.Lock()
+		s.inFlight++
+		s.maxInFlight = max(s.maxInFlight, s.inFlight)
+		if s.inFlight == s.release {
+			close(s.released)
+		}
+		s.mu.Unlock()
+		select {
+		case <-s.released:
+		case <-time.After(2 * time.Second):
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.inFlight--
+		if s.failing[name] {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":400,"message":"simulated failure"}`))
+			return
+		}
+		s.principalsCreated++
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"principal-` + name + `","name":"` + name + `"}`))
+
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/groups":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.principalsAtGroup = s.principalsCreated
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"group-admins","name":"admins"}`))
+
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/groups/group-admins/members":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.principalsAtMembers = s.principalsCreated
+		members, _ := body["members"].([]interface{})
+		for _, m := range members {
+			s.members = append(s.members, m.(map[string]interface{})["member_id"].(string))
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+	}
+}
+
+// parallelApplyActions creates three principals, a group, and the group's
+// memberships: three dependency layers.
+func parallelApplyActions() []declarative.Action {
+	plan := &declarative.Plan{}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		plan.Actions = append(plan.Actions, declarative.Action{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindPrincipal,
+			ResourceName: name,
+			Desired:      declarative.PrincipalSpec{Name: name, Type: "user"},
+		})
+	}
+	plan.Actions = append(plan.Actions, declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindGroup,
+		ResourceName: "admins",
+		Desired:      declarative.GroupSpec{Name: "admins"},
+	})
+	for _, name := range []string{"alice", "carol"} {
+		plan.Actions = append(plan.Actions, declarative.Action{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "admins/" + name + "(user)",
+			Desired:      declarative.MemberRef{Name: name, Type: "user"},
+		})
+	}
+	plan.SortActions()
+	return plan.Actions
+}
+
+func newParallelApplyClient(client *gen.Client, actions []declarative.Action) *APIStateClient {
+	sc := NewAPIStateClient(client)
+	sc.index = newResourceIndex()
+	sc.PrepareGroupMemberSync(actions)
+	return sc
+}
+
+func TestExecuteApplyActions_ParallelRunsIndependentActionsConcurrently(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, client := newParallelApplyServer(t, 3)
+	actions := parallelApplyActions()
+	sc := newParallelApplyClient(client, actions)
+	journal := newApplyJournal(ApplyJournalDir(), sc.ApplyRunID(), client.BaseURL, t.TempDir())
+
+	var progress strings.Builder
+	results, succeeded, failed := executeApplyActions(context.Background(), sc, actions, journal, &progress, 4)
+	assert.Equal(t, 6, succeeded)
+	assert.Equal(t, 0, failed)
+	require.Len(t, results, len(actions))
+	for i, r := range results {
+		assert.Equal(t, actions[i].ResourceName, r.ResourceName, "results keep plan order")
+		assert.Equal(t, "succeeded", r.Status)
+	}
+
+	assert.Equal(t, 3, server.maxInFlight, "independent principal creates run concurrently")
+	assert.Equal(t, 3, server.principalsAtGroup, "the group is created after every principal")
+	assert.Equal(t, 3, server.principalsAtMembers, "memberships are set after their principals")
+	assert.ElementsMatch(t, []string{"principal-alice", "principal-carol"}, server.members,
+		"memberships resolve the IDs captured by the concurrent creates")
+	assert.Equal(t, 6, strings.Count(progress.String(), "succeeded\n"))
+}
+
+func TestExecuteApplyActions_SerialByDefault(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, client := newParallelApplyServer(t, 2)
+	close(server.released)
+	actions := parallelApplyActions()
+	sc := newParallelApplyClient(client, actions)
+	journal := newApplyJournal(ApplyJournalDir(), sc.ApplyRunID(), client.BaseURL, t.TempDir())
+
+	_, succeeded, failed := executeApplyActions(context.Background(), sc, actions, journal, nil, 1)
+	assert.Equal(t, 6, succeeded)
+	assert.Equal(t, 0, failed)
+	assert.Equal(t, 1, server.maxInFlight)
+}
+
+func TestExecuteApplyActions_ParallelFailureSkipsLaterLayers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, client := newParallelApplyServer(t, 3)
+	server.failing["bob"] = true
+	actions := parallelApplyActions()
+	sc := newParallelApplyClient(client, actions)
+	journal := newApplyJournal(ApplyJournalDir(), sc.ApplyRunID(), client.BaseURL, t.TempDir())
+
+	results, succeeded, failed := executeApplyActions(context.Background(), sc, actions, journal, nil, 3)
+	assert.Equal(t, 2, succeeded, "principal creates already in flight complete")
+	assert.Equal(t, 1, failed)
+	statuses := make(map[string]string, len(results))
+	for _, r := range results {
+		statuses[r.ResourceName] = r.Status
+	}
+	assert.Equal(t, map[string]string{
+		"alice":              "succeeded",
+		"bob":                "failed",
+		"carol":              "succeeded",
+		"admins":             "skipped",
+		"admins/alice(user)": "skipped",
+		"admins/carol(user)": "skipped",
+	}, statuses)
+	assert.Zero(t, server.principalsAtGroup, "no group is created after the failure")
+	assert.Empty(t, server.members)
+}
+
+func TestExecuteApplyActions_ParallelJobWaitsForItsPipeline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var (
+		mu        sync.Mutex
+		pipelines = map[string]bool{}
+		jobs      []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		name, _ := body["name"].(string)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/pipelines":
+			// A slow create gives a job that does not wait the chance to
+			// overtake its pipeline.
+			time.Sleep(50 * time.Millisecond)
+			mu.Lock()
+			pipelines[name] = true
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"pipeline-` + name + `","name":"` + name + `"}`))
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/v1/pipelines/") && strings.HasSuffix(r.URL.Path, "/jobs"):
+			pipeline := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/pipelines/"), "/jobs")
+			mu.Lock()
+			defer mu.Unlock()
+			if !pipelines[pipeline] {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":404,"message":"pipeline not found"}`))
+				return
+			}
+			jobs = append(jobs, pipeline+"/"+name)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"job-` + name + `","name":"` + name + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client := gen.NewClient(srv.URL, "", "test-token")
+
+	plan := &declarative.Plan{}
+	for _, pipeline := range []string{"etl", "reports"} {
+		plan.Actions = append(plan.Actions, declarative.Action{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindPipeline,
+			ResourceName: pipeline,
+			Desired:      declarative.PipelineResource{Name: pipeline},
+		}, declarative.Action{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindPipelineJob,
+			ResourceName: pipeline + "/load",
+			Desired:      declarative.PipelineJobSpec{Name: "load", Notebook: "nb"},
+		})
+	}
+	plan.SortActions()
+	sc := NewAPIStateClient(client)
+	sc.index = newResourceIndex()
+	sc.index.setNotebookID("nb", "notebook-1")
+	journal := newApplyJournal(ApplyJournalDir(), sc.ApplyRunID(), client.BaseURL, t.TempDir())
+
+	results, succeeded, failed := executeApplyActions(context.Background(), sc, plan.Actions, journal, nil, 4)
+	for _, r := range results {
+		assert.Equal(t, "succeeded", r.Status, "%s %s: %s", r.ResourceKind, r.ResourceName, r.Error)
+	}
+	assert.Equal(t, 4, succeeded)
+	assert.Equal(t, 0, failed)
+	assert.ElementsMatch(t, []string{"etl/load", "reports/load"}, jobs)
+}
+
+func TestSummarizeApplyResults_CountsExecutedActions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, client := newParallelApplyServer(t, 2)
+	close(server.released)
+	server.failing["bob"] = true
+	actions := parallelApplyActions()
+	sc := newParallelApplyClient(client, actions)
+	journal := newApplyJournal(ApplyJournalDir(), sc.ApplyRunID(), client.BaseURL, t.TempDir())
+
+	var progress strings.Builder
+	results, succeeded, failed := executeApplyActions(context.Background(), sc, actions, journal, &progress, 1)
+	summary := summarizeApplyResults(results, 1500*time.Millisecond)
+
+	assert.Equal(t, []applyKindSummary{
+		{ResourceKind: "principal", Created: 1, Failed: 1, Skipped: 1},
+		{ResourceKind: "group", Skipped: 1},
+		{ResourceKind: "group-membership", Skipped: 2},
+	}, summary.Kinds)
+	assert.Equal(t, int64(1500), summary.DurationMS)
+
+	var created, failedInSummary int
+	for _, k := range summary.Kinds {
+		created += k.Created + k.Updated + k.Deleted
+		failedInSummary += k.Failed
+	}
+	assert.Equal(t, succeeded, created)
+	assert.Equal(t, failed, failedInSummary)
+	assert.Contains(t, progress.String(), "[1/6] create principal \"alice\" ... succeeded")
+	assert.Contains(t, progress.String(), "[2/6] create principal \"bob\" ... failed")
+
+	var out strings.Builder
+	printApplySummary(&out, summary)
+	assert.Equal(t, "  principal         1 created, 1 failed, 1 skipped\n"+
+		"  group             1 skipped\n"+
+		"  group-membership  2 skipped\n", out.String())
+}
+
+func TestSummarizeApplyResults_GroupsByKindAndOperation(t *testing.T) {
+	results := []applyActionResult{
+		{Operation: "create", ResourceKind: "principal", Status: "succeeded"},
+		{Operation: "update", ResourceKind: "principal", Status: "succeeded"},
+		{Operation: "delete", ResourceKind: "table", Status: "succeeded"},
+		{Operation: "create", ResourceKind: "principal", Status: "succeeded"},
+		{Operation: "delete", ResourceKind: "table", Status: "failed"},
+	}
+
+	summary := summarizeApplyResults(results, time.Second)
+
+	assert.Equal(t, []applyKindSummary{
+		{ResourceKind: "principal", Created: 2, Updated: 1},
+		{ResourceKind: "table", Deleted: 1, Failed: 1},
+	}, summary.Kinds)
+}
+
+func TestApplyDiffOnly_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		principals string
+		wantCode   int
+	}{
+		{
+			name:       "matching manifest exits 0",
+			principals: "  - name: alice\n    type: user\n",
+			wantCode:   0,
+		},
+		{
+			name:       "diverging manifest exits 2",
+			principals: "  - name: alice\n    type: user\n  - name: bob\n    type: user\n",
+			wantCode:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mutations []string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					mutations = append(mutations, r.Method+" "+r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/v1/principals" {
+					_, _ = w.Write([]byte(`{"data":[{"id":"principal-id-alice","name":"alice","type":"user"}]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "security"), 0o755))
+			manifest := "apiVersion: duck/v1\nkind: PrincipalList\nprincipals:\n" + tt.principals
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "security", "principals.yaml"), []byte(manifest), 0o600))
+
+			code := 0
+			osExit = func(c int) { code = c }
+			t.Cleanup(func() { osExit = os.Exit })
+
+			var out strings.Builder
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs([]string{"--host", srv.URL, "apply", "--diff-only", "--config-dir", dir})
+			err := rootCmd.Execute()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Empty(t, mutations, "diff-only must not change the server")
+			if tt.wantCode == 2 {
+				assert.Contains(t, out.String(), "bob")
+			}
+		})
+	}
+}
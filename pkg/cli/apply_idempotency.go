@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"duck-demo/internal/declarative"
+)
+
+// newApplyRunID returns a random ID that prefixes the idempotency keys of one
+// apply run.
+func newApplyRunID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// actionContextKey is the context key of the idempotency state of the action
+// being executed.
+type actionContextKey struct{}
+
+// actionIdempotency identifies the POSTs of one action: key names the action
+// and posts counts the POSTs it has sent.
+type actionIdempotency struct {
+	key   string
+	posts int
+}
+
+// beginAction returns ctx carrying fresh idempotency state for action. The
+// state travels with the action rather than the client, so actions executed
+// concurrently number their POSTs independently.
+func (c *APIStateClient) beginAction(ctx context.Context, action declarative.Action) context.Context {
+	key := actionIdentity(action)
+	c.mu.Lock()
+	if c.reapply[key] {
+		key += "\x00reapply"
+	}
+	c.mu.Unlock()
+	return context.WithValue(ctx, actionContextKey{}, &actionIdempotency{key: key})
+}
+
+func actionIdentity(action declarative.Action) string {
+	return fmt.Sprintf("%s\x00%s\x00%s", action.Operation, action.ResourceKind, action.ResourceName)
+}
+
+// ReapplyWithNewKeys makes action use idempotency keys other than the run's
+// original ones, so the server runs it again instead of replaying an earlier
+// response. A resumed apply uses it for actions its journal records as done
+// that the server no longer reflects.
+func (c *APIStateClient) ReapplyWithNewKeys(action declarative.Action) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reapply == nil {
+		c.reapply = make(map[string]bool)
+	}
+	c.reapply[actionIdentity(action)] = true
+}
+
+// nextIdempotencyKey returns the Idempotency-Key for the next POST of the
+// action begun in ctx. Keys depend only on the run ID, the action and the POST's
+// position within it, so the same action in the same run always sends the
+// same keys: retried requests and resumed runs replay instead of creating
+// duplicates, while a later run creates afresh.
+func (c *APIStateClient) nextIdempotencyKey(ctx context.Context) string {
+	state, ok := ctx.Value(actionContextKey{}).(*actionIdempotency)
+	if !ok {
+		state = &actionIdempotency{}
+	}
+	state.posts++
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", state.key, state.posts)))
+	return c.runID + "-" + hex.EncodeToString(sum[:16])
+}
+
+// post sends a create request with the next idempotency key. The server
+// replays the original response for a repeated key, so the request is retried
+// on transient failures like any idempotent method.
+func (c *APIStateClient) post(ctx context.Context, path string, body interface{}) (*http.Response, error) {
+	return c.client.DoWithIdempotencyKey(http.MethodPost, path, nil, body, c.nextIdempotencyKey(ctx))
+}
+
+// upsert is post with upsert=true: a create naming a resource that already
+// exists updates it and answers 200 with its ID instead of 409, so the
+// caller needs no lookup to adopt it. Servers without upsert ignore the
+// parameter and answer 409 RESOURCE_EXISTS, so callers keep the lookup as a
+// fallback.
+func (c *APIStateClient) upsert(ctx context.Context, path string, body interface{}) (*http.Response, error) {
+	return c.client.DoWithIdempotencyKey(http.MethodPost, path, url.Values{"upsert": {"true"}}, body, c.nextIdempotencyKey(ctx))
+}
+
+// ApplyRunID returns the ID that prefixes this client's idempotency keys.
+func (c *APIStateClient) ApplyRunID() string {
+	return c.runID
+}
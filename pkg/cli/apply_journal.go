@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"duck-demo/internal/declarative"
+)
+
+// Apply journal statuses.
+const (
+	journalStatusInProgress = "in_progress"
+	journalStatusFailed     = "failed"
+)
+
+// ApplyJournal records the progress of one apply run so that a run that
+// fails midway can be resumed with duck apply --resume. The journal is
+// removed once the run completes.
+type ApplyJournal struct {
+	RunID     string              `json:"run_id"`
+	Host      string              `json:"host"`
+	ConfigDir string              `json:"config_dir"`
+	Status    string              `json:"status"`
+	StartedAt time.Time           `json:"started_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	Planned   int                 `json:"planned"`
+	Completed []ApplyJournalEntry `json:"completed"`
+	Failed    *ApplyJournalEntry  `json:"failed,omitempty"`
+
+	dir       string
+	completed map[string]struct{}
+}
+
+// ApplyJournalEntry identifies an action of an apply run.
+type ApplyJournalEntry struct {
+	Operation    string    `json:"operation"`
+	ResourceKind string    `json:"resource_kind"`
+	ResourceName string    `json:"resource_name"`
+	At           time.Time `json:"at"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// ApplyJournalDir returns the path to ~/.duck/apply-journal/.
+func ApplyJournalDir() string {
+	return filepath.Join(ConfigDir(), "apply-journal")
+}
+
+// journalPath returns the journal file of runID in dir.
+func journalPath(dir, runID string) string {
+	return filepath.Join(dir, runID+".json")
+}
+
+// validRunID reports whether runID can name a journal file.
+func validRunID(runID string) bool {
+	if runID == "" || len(runID) > 128 {
+		return false
+	}
+	for _, r := range runID {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// newApplyJournal starts the journal of a new or resumed run in dir.
+func newApplyJournal(dir, runID, host, configDir string) *ApplyJournal {
+	now := time.Now().UTC()
+	return &ApplyJournal{
+		RunID:     runID,
+		Host:      host,
+		ConfigDir: configDir,
+		Status:    journalStatusInProgress,
+		StartedAt: now,
+		UpdatedAt: now,
+		dir:       dir,
+	}
+}
+
+// LoadApplyJournal reads the journal of runID from dir.
+func LoadApplyJournal(dir, runID string) (*ApplyJournal, error) {
+	if !validRunID(runID) {
+		return nil, fmt.Errorf("invalid apply run ID %q", runID)
+	}
+	data, err := os.ReadFile(journalPath(dir, runID)) //nolint:gosec // run ID is validated above
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no apply journal for run %q in %s", runID, dir)
+		}
+		return nil, fmt.Errorf("read apply journal: %w", err)
+	}
+	var j ApplyJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("parse apply journal %s: %w", runID, err)
+	}
+	j.dir = dir
+	return &j, nil
+}
+
+// FindResumableJournal returns the most recently updated journal in dir for
+// host and configDir whose run did not complete, or nil when there is none.
+func FindResumableJournal(dir, host, configDir string) (*ApplyJournal, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read apply journal dir: %w", err)
+	}
+	var candidates []*ApplyJournal
+	for _, e := range entries {
+		runID, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok || !validRunID(runID) {
+			continue
+		}
+		j, err := LoadApplyJournal(dir, runID)
+		if err != nil {
+			continue
+		}
+		if j.Host == host && j.ConfigDir == configDir {
+			candidates = append(candidates, j)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	sort.Slice(candidates, func(i, k int) bool {
+		return candidates[i].UpdatedAt.After(candidates[k].UpdatedAt)
+	})
+	return candidates[0], nil
+}
+
+func journalKey(operation, kind, name string) string {
+	return operation + "\x00" + kind + "\x00" + name
+}
+
+func journalEntry(action declarative.Action) ApplyJournalEntry {
+	return ApplyJournalEntry{
+		Operation:    action.Operation.String(),
+		ResourceKind: action.ResourceKind.String(),
+		ResourceName: action.ResourceName,
+		At:           time.Now().UTC(),
+	}
+}
+
+// HasCompleted reports whether the journal records action as completed.
+func (j *ApplyJournal) HasCompleted(action declarative.Action) bool {
+	if j.completed == nil {
+		j.completed = make(map[string]struct{}, len(j.Completed))
+		for _, e := range j.Completed {
+			j.completed[journalKey(e.Operation, e.ResourceKind, e.ResourceName)] = struct{}{}
+		}
+	}
+	_, ok := j.completed[journalKey(action.Operation.String(), action.ResourceKind.String(), action.ResourceName)]
+	return ok
+}
+
+// Start records the number of planned actions and writes the journal.
+func (j *ApplyJournal) Start(planned int) error {
+	j.Status = journalStatusInProgress
+	j.Planned = planned
+	j.Failed = nil
+	return j.save()
+}
+
+// RecordCompleted appends action to the completed actions and writes the
+// journal.
+func (j *ApplyJournal) RecordCompleted(action declarative.Action) error {
+	if !j.HasCompleted(action) {
+		j.Completed = append(j.Completed, journalEntry(action))
+		j.completed[journalKey(action.Operation.String(), action.ResourceKind.String(), action.ResourceName)] = struct{}{}
+	}
+	return j.save()
+}
+
+// RecordFailed marks the run failed at action and writes the journal.
+func (j *ApplyJournal) RecordFailed(action declarative.Action, cause error) error {
+	entry := journalEntry(action)
+	entry.Error = cause.Error()
+	j.Failed = &entry
+	j.Status = journalStatusFailed
+	return j.save()
+}
+
+// Finish removes the journal of a completed run.
+func (j *ApplyJournal) Finish() error {
+	if err := os.Remove(journalPath(j.dir, j.RunID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove apply journal: %w", err)
+	}
+	return nil
+}
+
+func (j *ApplyJournal) save() error {
+	if err := os.MkdirAll(j.dir, 0o700); err != nil {
+		return fmt.Errorf("create apply journal dir: %w", err)
+	}
+	j.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal apply journal: %w", err)
+	}
+	path := journalPath(j.dir, j.RunID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write apply journal: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("replace apply journal: %w", err)
+	}
+	return nil
+}
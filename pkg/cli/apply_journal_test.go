@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/declarative"
+	"duck-demo/pkg/cli/gen"
+)
+
+// journalTestServer keeps principals in memory. Creating a principal named
+// in failing answers 400 until the name is removed from it.
+type journalTestServer struct {
+	mu         sync.Mutex
+	principals []map[string]interface{}
+	creates    map[string]int
+	failing    map[string]bool
+}
+
+func newJournalTestServer(t *testing.T) (*journalTestServer, *gen.Client) {
+	t.Helper()
+	s := &journalTestServer{creates: make(map[string]int), failing: make(map[string]bool)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/capabilities":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/v1/principals" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": s.principals})
+		case r.URL.Path == "/v1/principals" && r.Method == http.MethodPost:
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			name, _ := body["name"].(string)
+			if s.failing[name] {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":400,"message":"simulated failure"}`))
+				return
+			}
+			s.creates[name]++
+			body["id"] = "id-" + name
+			s.principals = append(s.principals, body)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(body)
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return s, gen.NewClient(srv.URL, "", "test-token")
+}
+
+func principalsDesired(names ...string) *declarative.DesiredState {
+	state := &declarative.DesiredState{}
+	for _, n := range names {
+		state.Principals = append(state.Principals, declarative.PrincipalSpec{Name: n, Type: "user"})
+	}
+	return state
+}
+
+// planFor reads server state through sc and diffs desired against it.
+func planFor(t *testing.T, sc *APIStateClient, desired *declarative.DesiredState) []declarative.Action {
+	t.Helper()
+	actual, err := sc.ReadState(context.Background())
+	require.NoError(t, err)
+	return declarative.Diff(desired, actual).Actions
+}
+
+func TestApplyJournal_ResumeMidRunFailureToCompletion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, client := newJournalTestServer(t)
+	configDir := t.TempDir()
+	desired := principalsDesired("alice", "bob", "carol")
+
+	// First run: the second create fails.
+	server.failing["bob"] = true
+	first := NewAPIStateClient(client)
+	actions := planFor(t, first, desired)
+	require.Len(t, actions, 3)
+	journal := newApplyJournal(ApplyJournalDir(), first.ApplyRunID(), client.BaseURL, absConfigDir(configDir))
+	require.NoError(t, journal.Start(len(actions)))
+	results, succeeded, failed := executeApplyActions(context.Background(), first, actions, journal, nil, 1)
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, failed)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"succeeded", "failed", "skipped"}, []string{results[0].Status, results[1].Status, results[2].Status})
+
+	onDisk, err := LoadApplyJournal(ApplyJournalDir(), first.ApplyRunID())
+	require.NoError(t, err)
+	assert.Equal(t, journalStatusFailed, onDisk.Status)
+	require.Len(t, onDisk.Completed, 1)
+	assert.Equal(t, "alice", onDisk.Completed[0].ResourceName)
+	require.NotNil(t, onDisk.Failed)
+	assert.Equal(t, "bob", onDisk.Failed.ResourceName)
+	assert.Contains(t, onDisk.Failed.Error, "simulated failure")
+
+	// Second run: the failed run is found automatically and resumed.
+	delete(server.failing, "bob")
+	resumedJournal, err := selectApplyJournal(client.BaseURL, configDir, "", false)
+	require.NoError(t, err)
+	require.NotNil(t, resumedJournal)
+	assert.Equal(t, first.ApplyRunID(), resumedJournal.RunID)
+
+	resumed := NewAPIStateClientWithOptions(client, APIStateClientOptions{ApplyRunID: resumedJournal.RunID})
+	remaining := planFor(t, resumed, desired)
+	require.Len(t, remaining, 2, "alice is already on the server and drops out of the plan")
+	for _, a := range remaining {
+		assert.False(t, resumedJournal.HasCompleted(a))
+	}
+	require.NoError(t, resumedJournal.Start(len(remaining)))
+	_, succeeded, failed = executeApplyActions(context.Background(), resumed, remaining, resumedJournal, nil, 1)
+	assert.Equal(t, 2, succeeded)
+	assert.Equal(t, 0, failed)
+	finishApplyJournal(resumedJournal)
+
+	assert.Equal(t, map[string]int{"alice": 1, "bob": 1, "carol": 1}, server.creates, "each principal is created exactly once")
+	assert.Empty(t, planFor(t, NewAPIStateClient(client), desired))
+	_, err = os.Stat(journalPath(ApplyJournalDir(), first.ApplyRunID()))
+	assert.True(t, os.IsNotExist(err), "the journal of a completed run is removed")
+
+	again, err := selectApplyJournal(client.BaseURL, configDir, "", false)
+	require.NoError(t, err)
+	assert.Nil(t, again)
+}
+
+func TestSelectApplyJournal(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configDir := t.TempDir()
+	journal := newApplyJournal(ApplyJournalDir(), "run-1", "http://a.example", absConfigDir(configDir))
+	require.NoError(t, journal.Start(3))
+
+	got, err := selectApplyJournal("http://a.example", configDir, "", true)
+	require.NoError(t, err)
+	assert.Nil(t, got, "--no-resume starts a new run")
+
+	got, err = selectApplyJournal("http://a.example", t.TempDir(), "", false)
+	require.NoError(t, err)
+	assert.Nil(t, got, "runs of other config dirs are not resumed automatically")
+
+	got, err = selectApplyJournal("http://b.example", configDir, "", false)
+	require.NoError(t, err)
+	assert.Nil(t, got, "runs against other servers are not resumed automatically")
+
+	got, err = selectApplyJournal("http://a.example", t.TempDir(), "run-1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", got.RunID, "--resume picks the run explicitly")
+
+	_, err = selectApplyJournal("http://b.example", configDir, "run-1", false)
+	require.ErrorContains(t, err, "targeted http://a.example")
+
+	_, err = selectApplyJournal("http://a.example", configDir, "missing", false)
+	require.ErrorContains(t, err, "no apply journal")
+
+	_, err = selectApplyJournal("http://a.example", configDir, "../etc/passwd", false)
+	require.ErrorContains(t, err, "invalid apply run ID")
+}
+
+func TestReapplyWithNewKeys_ChangesIdempotencyKeys(t *testing.T) {
+	sc := NewAPIStateClientWithOptions(gen.NewClient("http://localhost", "", ""), APIStateClientOptions{ApplyRunID: "run-1"})
+	action := declarative.Action{Operation: declarative.OpCreate, ResourceKind: declarative.KindPrincipal, ResourceName: "alice"}
+
+	ctx := context.Background()
+	original := sc.nextIdempotencyKey(sc.beginAction(ctx, action))
+	assert.Equal(t, original, sc.nextIdempotencyKey(sc.beginAction(ctx, action)), "keys are stable within a run")
+
+	sc.ReapplyWithNewKeys(action)
+	assert.NotEqual(t, original, sc.nextIdempotencyKey(sc.beginAction(ctx, action)))
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// errApplyLockUnsupported is returned when the server predates the apply lock
+// endpoint. Callers proceed without the lock.
+var errApplyLockUnsupported = errors.New("server does not support apply locks")
+
+// errApplyLockLost is the cancellation cause of an apply whose lease could not
+// be renewed.
+var errApplyLockLost = errors.New("apply lock lease lost")
+
+// ApplyLease is an apply lock lease held by this process.
+type ApplyLease struct {
+	LeaseID       string    `json:"lease_id"`
+	Owner         string    `json:"owner"`
+	PrincipalName string    `json:"principal_name"`
+	AcquiredAt    time.Time `json:"acquired_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// applyLockOwner describes this apply to contending applies.
+func applyLockOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown-host"
+	}
+	return fmt.Sprintf("duck apply on %s (pid %d)", host, os.Getpid())
+}
+
+// AcquireApplyLock takes the server-side apply lock. When another apply holds
+// a live lease it returns an error naming the holder; force breaks the lease.
+func (c *APIStateClient) AcquireApplyLock(_ context.Context, owner string, ttl time.Duration, force bool) (*ApplyLease, error) {
+	body := map[string]interface{}{
+		"owner":       owner,
+		"ttl_seconds": int64(ttl / time.Second),
+		"force":       force,
+	}
+	resp, err := c.client.Do(http.MethodPost, "/apply-lock", nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("acquire apply lock: %w", err)
+	}
+	data, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		return nil, fmt.Errorf("another apply is in progress: %s; re-run with --force to break a stale lease", apiErr.Message)
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed:
+		return nil, errApplyLockUnsupported
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("POST /apply-lock: HTTP %d: %s", resp.StatusCode, string(data))
+	}
+
+	var lease ApplyLease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return nil, fmt.Errorf("decode apply lock: %w", err)
+	}
+	return &lease, nil
+}
+
+// ReleaseApplyLock gives up a lease taken by AcquireApplyLock.
+func (c *APIStateClient) ReleaseApplyLock(_ context.Context, leaseID string) error {
+	q := url.Values{}
+	q.Set("lease_id", leaseID)
+	resp, err := c.client.Do(http.MethodDelete, "/apply-lock", q, nil)
+	if err != nil {
+		return fmt.Errorf("release apply lock: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return fmt.Errorf("apply lock lease %s was no longer held; another apply may have taken it over", leaseID)
+	}
+	return gen.CheckError(resp)
+}
+
+// RenewApplyLock extends a lease taken by AcquireApplyLock to ttl from now.
+// It fails when the lease has been broken or taken over by another apply.
+func (c *APIStateClient) RenewApplyLock(_ context.Context, leaseID string, ttl time.Duration) (*ApplyLease, error) {
+	body := map[string]interface{}{
+		"lease_id":    leaseID,
+		"ttl_seconds": int64(ttl / time.Second),
+	}
+	resp, err := c.client.Do(http.MethodPost, "/apply-lock:renew", nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("renew apply lock: %w", err)
+	}
+	data, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("apply lock lease %s was lost; another apply may have taken it over", leaseID)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("POST /apply-lock:renew: HTTP %d: %s", resp.StatusCode, string(data))
+	}
+
+	var lease ApplyLease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return nil, fmt.Errorf("decode apply lock: %w", err)
+	}
+	return &lease, nil
+}
+
+// heartbeatApplyLock renews leaseID every interval until stop is called. When a
+// renewal fails, the returned context is cancelled with errApplyLockLost as its
+// cause, so the remaining actions are skipped instead of running unlocked.
+func heartbeatApplyLock(ctx context.Context, c *APIStateClient, leaseID string, ttl, interval time.Duration) (context.Context, func()) {
+	hbCtx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-hbCtx.Done():
+				return
+			case <-ticker.C:
+				if _, err := c.RenewApplyLock(hbCtx, leaseID, ttl); err != nil {
+					cancel(fmt.Errorf("%w: %w", errApplyLockLost, err))
+					return
+				}
+			}
+		}
+	}()
+	return hbCtx, func() {
+		close(done)
+		<-stopped
+		cancel(nil)
+	}
+}
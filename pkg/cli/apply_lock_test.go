@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// newTestLockServer serves /v1/apply-lock and /v1/apply-lock:renew with a single in-memory lease.
+func newTestLockServer(t *testing.T) *gen.Client {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		holder  string
+		leaseID string
+		seq     int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/apply-lock:renew":
+			var body struct {
+				LeaseID string `json:"lease_id"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.LeaseID != leaseID {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":404,"message":"not held"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id": leaseID, "owner": holder, "principal_name": "alice",
+				"acquired_at": time.Now().UTC(), "expires_at": time.Now().UTC().Add(time.Minute),
+			})
+		case r.Method == http.MethodPost:
+			var body struct {
+				Owner string `json:"owner"`
+				Force bool   `json:"force"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if leaseID != "" && !body.Force {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"code":409,"message":"apply lock is held by \"` + holder + `\""}`))
+				return
+			}
+			seq++
+			holder, leaseID = body.Owner, fmt.Sprintf("lease-%d", seq)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id": leaseID, "owner": holder, "principal_name": "alice",
+				"acquired_at": time.Now().UTC(), "expires_at": time.Now().UTC().Add(time.Minute),
+			})
+		case r.Method == http.MethodDelete:
+			if r.URL.Query().Get("lease_id") != leaseID {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":404,"message":"not held"}`))
+				return
+			}
+			holder, leaseID = "", ""
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return gen.NewClient(srv.URL, "", "test-token")
+}
+
+func TestApplyLock_AcquireAndRelease(t *testing.T) {
+	sc := NewAPIStateClient(newTestLockServer(t))
+
+	lease, err := sc.AcquireApplyLock(context.Background(), "first", time.Minute, false)
+	require.NoError(t, err)
+	assert.Equal(t, "first", lease.Owner)
+	require.NoError(t, sc.ReleaseApplyLock(context.Background(), lease.LeaseID))
+
+	// The lock is free again after release.
+	_, err = sc.AcquireApplyLock(context.Background(), "second", time.Minute, false)
+	require.NoError(t, err)
+}
+
+func TestApplyLock_ContentionBlocksSecondApply(t *testing.T) {
+	sc := NewAPIStateClient(newTestLockServer(t))
+
+	_, err := sc.AcquireApplyLock(context.Background(), "first", time.Minute, false)
+	require.NoError(t, err)
+
+	_, err = sc.AcquireApplyLock(context.Background(), "second", time.Minute, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "first")
+	assert.Contains(t, err.Error(), "--force")
+}
+
+func TestApplyLock_ForceTakesOverStaleLease(t *testing.T) {
+	sc := NewAPIStateClient(newTestLockServer(t))
+
+	stale, err := sc.AcquireApplyLock(context.Background(), "crashed", time.Minute, false)
+	require.NoError(t, err)
+
+	lease, err := sc.AcquireApplyLock(context.Background(), "rescuer", time.Minute, true)
+	require.NoError(t, err)
+	assert.Equal(t, "rescuer", lease.Owner)
+
+	// The broken lease can no longer release the lock.
+	err = sc.ReleaseApplyLock(context.Background(), stale.LeaseID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no longer held")
+}
+
+func TestApplyLock_UnsupportedServer(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+
+	_, err := sc.AcquireApplyLock(context.Background(), "first", time.Minute, false)
+	require.ErrorIs(t, err, errApplyLockUnsupported)
+}
+
+func TestApplyLock_Renew(t *testing.T) {
+	sc := NewAPIStateClient(newTestLockServer(t))
+
+	lease, err := sc.AcquireApplyLock(context.Background(), "first", time.Minute, false)
+	require.NoError(t, err)
+	renewed, err := sc.RenewApplyLock(context.Background(), lease.LeaseID, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, lease.LeaseID, renewed.LeaseID)
+
+	// Once another apply breaks the lease, renewal reports it as lost.
+	_, err = sc.AcquireApplyLock(context.Background(), "rescuer", time.Minute, true)
+	require.NoError(t, err)
+	_, err = sc.RenewApplyLock(context.Background(), lease.LeaseID, time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lost")
+}
+
+func TestApplyLock_HeartbeatCancelsApplyWhenLeaseIsLost(t *testing.T) {
+	sc := NewAPIStateClient(newTestLockServer(t))
+
+	lease, err := sc.AcquireApplyLock(context.Background(), "first", time.Minute, false)
+	require.NoError(t, err)
+	ctx, stop := heartbeatApplyLock(context.Background(), sc, lease.LeaseID, time.Minute, 10*time.Millisecond)
+	defer stop()
+
+	// Renewals succeed while the lease is held.
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, ctx.Err())
+
+	_, err = sc.AcquireApplyLock(context.Background(), "rescuer", time.Minute, true)
+	require.NoError(t, err)
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("heartbeat did not cancel the apply after losing the lease")
+	}
+	require.ErrorIs(t, context.Cause(ctx), errApplyLockLost)
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// createBackup streams a binary file, so write it to --out instead of
+	// printing the response.
+	gen.RegisterOverride("createBackup", func(c *cobra.Command) {
+		c.Flags().String("out", "", "File to write the backup to (must not exist)")
+		_ = c.MarkFlagRequired("out")
+	})
+
+	gen.RegisterRunOverride("createBackup", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, _ []string) error {
+			out, _ := cmd.Flags().GetString("out")
+			gz, _ := cmd.Flags().GetBool("gzip")
+
+			query := url.Values{}
+			if gz {
+				query.Set("gzip", "true")
+			}
+			resp, err := client.Do("POST", "/admin/backup", query, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			defer resp.Body.Close() //nolint:errcheck
+
+			size, sum, err := writeBackup(out, resp.Body)
+			if err != nil {
+				return err
+			}
+			if want := resp.Header.Get("X-Backup-Checksum"); want != "" && want != sum {
+				_ = os.Remove(out)
+				return fmt.Errorf("backup checksum mismatch: server reported %s, received %s", want, sum)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s (%d bytes, schema version %s, sha256 %s)\n",
+				out, size, resp.Header.Get("X-Schema-Version"), sum)
+			return nil
+		}
+	})
+}
+
+// writeBackup copies r into a new file at path and returns the byte count
+// and SHA-256 of what was written. A partial file is removed on failure.
+func writeBackup(path string, r io.Reader) (int64, string, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) //nolint:gosec // path is chosen by the operator
+	if err != nil {
+		return 0, "", fmt.Errorf("create backup file: %w", err)
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), r)
+	if err == nil {
+		err = f.Close()
+	} else {
+		_ = f.Close()
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return 0, "", fmt.Errorf("write backup file: %w", err)
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
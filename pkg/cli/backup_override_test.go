@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupOverride(t *testing.T) {
+	const payload = "SQLite format 3\x00backup"
+	sum := sha256.Sum256([]byte(payload))
+	goodSum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name       string
+		extraArgs  []string
+		checksum   string
+		wantQuery  string
+		errContain string
+	}{
+		{name: "writes backup", checksum: goodSum},
+		{name: "gzip flag is forwarded", extraArgs: []string{"--gzip"}, checksum: goodSum, wantQuery: "gzip=true"},
+		{name: "checksum mismatch is rejected", checksum: "deadbeef", errContain: "checksum mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotQuery string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/octet-stream")
+				w.Header().Set("X-Backup-Checksum", tt.checksum)
+				w.Header().Set("X-Schema-Version", "50")
+				_, _ = w.Write([]byte(payload))
+			}))
+			defer srv.Close()
+
+			out := filepath.Join(t.TempDir(), "backup.sqlite")
+			rootCmd := newRootCmd()
+			rootCmd.SetArgs(append([]string{"--host", srv.URL, "admin", "backup", "--out", out}, tt.extraArgs...))
+			err := rootCmd.Execute()
+
+			assert.Equal(t, "/v1/admin/backup", gotPath)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			if tt.errContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContain)
+				assert.NoFileExists(t, out)
+				return
+			}
+			require.NoError(t, err)
+			data, err := os.ReadFile(out) //nolint:gosec // test path
+			require.NoError(t, err)
+			assert.Equal(t, payload, string(data))
+		})
+	}
+}
+
+func TestWriteBackup_RefusesExistingFile(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "backup.sqlite")
+	require.NoError(t, os.WriteFile(out, []byte("keep"), 0o600))
+
+	_, _, err := writeBackup(out, nil)
+	require.Error(t, err)
+	data, _ := os.ReadFile(out) //nolint:gosec // test path
+	assert.Equal(t, "keep", string(data))
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogAccessCmd(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/v1/securables/table/demo.analytics.orders/access", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"securable_type":"table","securable_id":"t-1","entries":[` +
+			`{"principal_type":"group","principal_name":"analysts","privilege":"SELECT","source":"DIRECT","granted_on_type":"table","granted_on_id":"t-1"},` +
+			`{"principal_type":"user","principal_name":"alice","privilege":"SELECT","source":"GROUP","via_group":"analysts","granted_on_type":"table","granted_on_id":"t-1"},` +
+			`{"principal_type":"user","principal_name":"bob","privilege":"ALL_PRIVILEGES","source":"CASCADE","granted_on_type":"catalog","granted_on_id":"c-1"}],` +
+			`"policies":[{"principal_name":"alice","row_filters":["region = 'EU'"],"column_masks":[{"column_name":"email","mask_expression":"'***'"}]}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	out := runCatalogTree(t, srv, "catalog", "access", "table:demo.analytics.orders")
+
+	assert.Regexp(t, `analysts\s+group\s+SELECT\s+DIRECT\s+-`, out)
+	assert.Regexp(t, `alice\s+user\s+SELECT\s+GROUP\s+group analysts`, out)
+	assert.Regexp(t, `bob\s+user\s+ALL_PRIVILEGES\s+CASCADE\s+catalog c-1`, out)
+	assert.Regexp(t, `alice\s+region = 'EU'\s+email => '\*\*\*'`, out)
+}
+
+func TestCatalogAccessCmd_RejectsBareName(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Fatal("no request expected")
+	}))
+	t.Cleanup(srv.Close)
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "catalog", "access", "demo.analytics.orders"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "<type>:<name>")
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// ownedObject is one row of `duck catalog mine` output.
+type ownedObject struct {
+	Type    string `json:"type"`
+	Catalog string `json:"catalog"`
+	Name    string `json:"name"`
+}
+
+// newCatalogMineCmd builds `duck catalog mine [catalog]`, which lists the
+// schemas, tables, views and volumes the caller owns across catalogs.
+func newCatalogMineCmd(client *gen.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mine [catalog]",
+		Short: "List the catalog objects you own",
+		Long: "Walks every ACTIVE catalog, or only the given one, and lists the schemas, tables, views and volumes " +
+			"owned by the caller. Each listing is requested with mine=true, so ownership is resolved by the server. " +
+			"Schemas are walked whether or not the caller owns them, since an owned table may live in someone " +
+			"else's schema; listings the caller is denied are skipped.",
+		Example: "  duck catalog mine\n" +
+			"  duck catalog mine main -o json",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var catalogs []*treeNode
+			var err error
+			if len(args) == 1 {
+				var c *treeNode
+				c, err = fetchTreeCatalog(client, args[0])
+				catalogs = []*treeNode{c}
+			} else {
+				catalogs, err = fetchTreeCatalogs(client)
+			}
+			if err != nil {
+				return err
+			}
+
+			var owned []ownedObject
+			for _, c := range catalogs {
+				if c.Status != "" && c.Status != "ACTIVE" {
+					continue
+				}
+				objs, err := fetchOwnedObjects(client, c.Name)
+				if err != nil {
+					return fmt.Errorf("catalog %s: %w", c.Name, err)
+				}
+				owned = append(owned, objs...)
+			}
+
+			if getOutputFormat(cmd) == "json" {
+				return gen.PrintJSON(cmd.OutOrStdout(), map[string]interface{}{"data": owned})
+			}
+			rows := make([][]string, 0, len(owned))
+			for _, o := range owned {
+				rows = append(rows, []string{o.Type, o.Catalog, o.Name})
+			}
+			gen.PrintTable(cmd.OutOrStdout(), []string{"TYPE", "CATALOG", "NAME"}, rows)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// fetchOwnedObjects lists the caller's schemas in one catalog and, for every
+// schema the caller can see, the tables, views and volumes they own.
+func fetchOwnedObjects(client *gen.Client, catalog string) ([]ownedObject, error) {
+	base := "/catalogs/" + url.PathEscape(catalog) + "/schemas"
+	var schemas []struct {
+		Name string `json:"name"`
+	}
+	denied, err := listTreeChildren(client, base, nil, &schemas)
+	if err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+	if denied {
+		return nil, nil
+	}
+	var mySchemas []struct {
+		Name string `json:"name"`
+	}
+	if _, err := listOwnedChildren(client, base, &mySchemas); err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+
+	var owned []ownedObject
+	for _, s := range mySchemas {
+		owned = append(owned, ownedObject{Type: "schema", Catalog: catalog, Name: s.Name})
+	}
+	for _, s := range schemas {
+		path := base + "/" + url.PathEscape(s.Name)
+		for _, kind := range []struct{ typ, segment string }{
+			{"table", "tables"},
+			{"view", "views"},
+			{"volume", "volumes"},
+		} {
+			var items []struct {
+				Name string `json:"name"`
+			}
+			if _, err := listOwnedChildren(client, path+"/"+kind.segment, &items); err != nil {
+				return nil, fmt.Errorf("list %s of %s: %w", kind.segment, s.Name, err)
+			}
+			for _, it := range items {
+				owned = append(owned, ownedObject{Type: kind.typ, Catalog: catalog, Name: s.Name + "." + it.Name})
+			}
+		}
+	}
+	return owned, nil
+}
+
+// listOwnedChildren is listTreeChildren restricted to objects the caller owns.
+func listOwnedChildren(client *gen.Client, path string, out interface{}) (bool, error) {
+	return listTreeChildren(client, path, url.Values{"mine": {"true"}}, out)
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCatalogMineServer serves catalog main with schemas analytics, owned by
+// the caller, and shared, where the caller owns only the table events. The
+// owner filter is honoured only when mine=true is sent.
+func newCatalogMineServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	all := map[string]string{
+		"/v1/catalogs":                               `{"data":[{"name":"main","status":"ACTIVE"},{"name":"archive","status":"DETACHED"}]}`,
+		"/v1/catalogs/main":                          `{"name":"main","status":"ACTIVE"}`,
+		"/v1/catalogs/main/schemas":                  `{"data":[{"name":"analytics"},{"name":"shared"}]}`,
+		"/v1/catalogs/main/schemas/analytics/tables": `{"data":[{"name":"orders"},{"name":"customers"}]}`,
+		"/v1/catalogs/main/schemas/analytics/views":  `{"data":[{"name":"recent_orders"}]}`,
+		"/v1/catalogs/main/schemas/shared/tables":    `{"data":[{"name":"events"},{"name":"sessions"}]}`,
+		"/v1/catalogs/main/schemas/shared/volumes":   `{"data":[{"name":"landing"}]}`,
+	}
+	mine := map[string]string{
+		"/v1/catalogs/main/schemas":                  `{"data":[{"name":"analytics"}]}`,
+		"/v1/catalogs/main/schemas/analytics/tables": `{"data":[{"name":"orders"}]}`,
+		"/v1/catalogs/main/schemas/shared/tables":    `{"data":[{"name":"events"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/catalogs/archive/schemas" {
+			t.Errorf("detached catalog must not be walked")
+		}
+		responses := all
+		if r.URL.Query().Get("mine") == "true" {
+			responses = mine
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			body = `{"data":[]}`
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCatalogMineCmd_ListsOnlyOwnedObjects(t *testing.T) {
+	srv := newCatalogMineServer(t)
+
+	out := runCatalogTree(t, srv, "--output", "json", "catalog", "mine")
+
+	var got struct {
+		Data []ownedObject `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, []ownedObject{
+		{Type: "schema", Catalog: "main", Name: "analytics"},
+		{Type: "table", Catalog: "main", Name: "analytics.orders"},
+		{Type: "table", Catalog: "main", Name: "shared.events"},
+	}, got.Data)
+}
+
+func TestCatalogMineCmd_Table(t *testing.T) {
+	srv := newCatalogMineServer(t)
+
+	out := runCatalogTree(t, srv, "catalog", "mine", "main")
+
+	assert.Contains(t, out, "TYPE")
+	assert.Contains(t, out, "shared.events")
+	assert.NotContains(t, out, "sessions")
+	assert.NotContains(t, out, "recent_orders")
+	assert.NotContains(t, out, "landing")
+}
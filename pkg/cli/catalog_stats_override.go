@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// getCatalogStats prints the counts and totals as one row followed by the
+	// largest tables, instead of the generic key/value rendering.
+	gen.RegisterRunOverride("getCatalogStats", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Do("GET", "/catalogs/"+url.PathEscape(args[0])+"/stats", nil, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			raw, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), raw)
+			}
+			var stats catalogStats
+			if err := json.Unmarshal(raw, &stats); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			printCatalogStats(cmd.OutOrStdout(), stats)
+			return nil
+		}
+	})
+}
+
+// catalogStats mirrors the API's CatalogStats.
+type catalogStats struct {
+	CatalogName   string `json:"catalog_name"`
+	SchemaCount   int64  `json:"schema_count"`
+	TableCount    int64  `json:"table_count"`
+	ViewCount     int64  `json:"view_count"`
+	TotalRows     int64  `json:"total_rows"`
+	TotalBytes    int64  `json:"total_bytes"`
+	LargestTables []struct {
+		SchemaName string `json:"schema_name"`
+		TableName  string `json:"table_name"`
+		RowCount   int64  `json:"row_count"`
+		SizeBytes  int64  `json:"size_bytes"`
+	} `json:"largest_tables"`
+}
+
+func printCatalogStats(w io.Writer, s catalogStats) {
+	gen.PrintTable(w, []string{"catalog", "schemas", "tables", "views", "rows", "size"},
+		[][]string{{
+			s.CatalogName,
+			strconv.FormatInt(s.SchemaCount, 10),
+			strconv.FormatInt(s.TableCount, 10),
+			strconv.FormatInt(s.ViewCount, 10),
+			strconv.FormatInt(s.TotalRows, 10),
+			formatByteSize(s.TotalBytes),
+		}})
+	if len(s.LargestTables) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\nLargest tables:\n")
+	rows := make([][]string, len(s.LargestTables))
+	for i, t := range s.LargestTables {
+		rows[i] = []string{t.SchemaName + "." + t.TableName, strconv.FormatInt(t.RowCount, 10), formatByteSize(t.SizeBytes)}
+	}
+	gen.PrintTable(w, []string{"table", "rows", "size"}, rows)
+}
+
+// formatByteSize renders n bytes with a binary unit, such as 1.5 MiB.
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
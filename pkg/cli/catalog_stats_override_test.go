@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogStatsOverride(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/catalogs/lake/stats" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"catalog_name":"lake","schema_count":2,"table_count":4,"view_count":1,
+			"total_rows":5200,"total_bytes":86000,"largest_tables":[
+			{"schema_name":"sales","table_name":"orders","row_count":5000,"size_bytes":80000},
+			{"schema_name":"sales","table_name":"customers","row_count":200,"size_bytes":6000}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	rootCmd := newRootCmd()
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "catalog", "stats", "lake"})
+	require.NoError(t, rootCmd.Execute())
+
+	got := out.String()
+	assert.Regexp(t, `lake\s+2\s+4\s+1\s+5200\s+84\.0 KiB`, got)
+	assert.Contains(t, got, "Largest tables:")
+	assert.Regexp(t, `sales\.orders\s+5000\s+78\.1 KiB`, got)
+	assert.Regexp(t, `sales\.customers\s+200\s+5\.9 KiB`, got)
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536 * 1024, "1.5 MiB"},
+		{5 << 30, "5.0 GiB"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatByteSize(tt.n))
+	}
+}
@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// Tree levels, counted from the catalogs at the top.
+const (
+	treeLevelCatalog = 1
+	treeLevelSchema  = 2
+	treeLevelObject  = 3
+	treeLevelColumn  = 4
+)
+
+// treeNode is one catalog object in `duck catalog tree` output.
+type treeNode struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Detail   string      `json:"detail,omitempty"`
+	Status   string      `json:"status,omitempty"`
+	Children []*treeNode `json:"children,omitempty"`
+	// expanded records whether Children were fetched, so an empty object
+	// prints a zero count while a node cut off by --depth prints none.
+	expanded bool
+}
+
+// newCatalogTreeCmd builds `duck catalog tree [catalog]`, which walks the
+// nested list endpoints and prints the catalog hierarchy as one tree.
+func newCatalogTreeCmd(client *gen.Client) *cobra.Command {
+	var (
+		depth          int
+		includeColumns bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tree [catalog]",
+		Short: "Print the catalog, schema and table hierarchy",
+		Long: "Lists catalogs, their schemas and the tables and views in each schema, and prints them as an indented tree " +
+			"with the number of children at every level. The listings are the caller's own, so objects the caller " +
+			"cannot see are left out, and a subtree the caller is denied is marked instead of failing the command. " +
+			"Catalogs that are not ACTIVE are shown with their status but not expanded. " +
+			"--depth limits how many levels are printed, counting catalogs as level 1; --include-columns adds the " +
+			"columns of each table as level 4.",
+		Example: "  duck catalog tree\n" +
+			"  duck catalog tree main --include-columns\n" +
+			"  duck catalog tree --depth 2 -o json",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if depth < 0 {
+				return fmt.Errorf("--depth must not be negative")
+			}
+			maxLevel := treeLevelObject
+			if includeColumns {
+				maxLevel = treeLevelColumn
+			}
+			if depth > 0 && depth < maxLevel {
+				maxLevel = depth
+			}
+
+			var catalogs []*treeNode
+			var err error
+			if len(args) == 1 {
+				var c *treeNode
+				c, err = fetchTreeCatalog(client, args[0])
+				catalogs = []*treeNode{c}
+			} else {
+				catalogs, err = fetchTreeCatalogs(client)
+			}
+			if err != nil {
+				return err
+			}
+			for _, c := range catalogs {
+				if err := expandTreeCatalog(client, c, maxLevel); err != nil {
+					return err
+				}
+			}
+
+			if getOutputFormat(cmd) == "json" {
+				return gen.PrintJSON(cmd.OutOrStdout(), map[string]interface{}{"catalogs": catalogs})
+			}
+			printTree(cmd.OutOrStdout(), catalogs)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&depth, "depth", 0, "Maximum number of levels to print (1 catalogs, 2 schemas, 3 tables and views, 4 columns); 0 prints all")
+	cmd.Flags().BoolVar(&includeColumns, "include-columns", false, "Print the columns of each table")
+
+	return cmd
+}
+
+func fetchTreeCatalogs(client *gen.Client) ([]*treeNode, error) {
+	var items []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := listAllItems(client, "/catalogs", nil, &items); err != nil {
+		return nil, fmt.Errorf("list catalogs: %w", err)
+	}
+	nodes := make([]*treeNode, 0, len(items))
+	for _, c := range items {
+		nodes = append(nodes, &treeNode{Name: c.Name, Type: "catalog", Status: c.Status})
+	}
+	return nodes, nil
+}
+
+func fetchTreeCatalog(client *gen.Client, name string) (*treeNode, error) {
+	resp, err := client.Do(http.MethodGet, "/catalogs/"+url.PathEscape(name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return nil, fmt.Errorf("catalog %s: %w", name, err)
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var c struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &c); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &treeNode{Name: c.Name, Type: "catalog", Status: c.Status}, nil
+}
+
+// expandTreeCatalog fills in a catalog's schemas and, below them, its tables
+// and views, down to maxLevel.
+func expandTreeCatalog(client *gen.Client, c *treeNode, maxLevel int) error {
+	if maxLevel < treeLevelSchema || (c.Status != "" && c.Status != "ACTIVE") {
+		return nil
+	}
+
+	base := "/catalogs/" + url.PathEscape(c.Name) + "/schemas"
+	var schemas []struct {
+		Name string `json:"name"`
+	}
+	if denied, err := listTreeChildren(client, base, nil, &schemas); err != nil {
+		return fmt.Errorf("list schemas of %s: %w", c.Name, err)
+	} else if denied {
+		c.Status = "ACCESS_DENIED"
+		return nil
+	}
+	c.expanded = true
+
+	for _, s := range schemas {
+		schema := &treeNode{Name: s.Name, Type: "schema"}
+		c.Children = append(c.Children, schema)
+		if maxLevel < treeLevelObject {
+			continue
+		}
+		if err := expandTreeSchema(client, schema, base+"/"+url.PathEscape(s.Name), maxLevel); err != nil {
+			return fmt.Errorf("%s.%s: %w", c.Name, s.Name, err)
+		}
+	}
+	return nil
+}
+
+func expandTreeSchema(client *gen.Client, schema *treeNode, path string, maxLevel int) error {
+	var tables []apiTable
+	denied, err := listTreeChildren(client, path+"/tables", nil, &tables)
+	if err != nil {
+		return fmt.Errorf("list tables: %w", err)
+	}
+	if denied {
+		schema.Status = "ACCESS_DENIED"
+		return nil
+	}
+	var views []apiView
+	if denied, err = listTreeChildren(client, path+"/views", nil, &views); err != nil {
+		return fmt.Errorf("list views: %w", err)
+	}
+	schema.expanded = true
+
+	for _, t := range tables {
+		table := &treeNode{Name: t.Name, Type: "table", Detail: t.TableType}
+		if maxLevel >= treeLevelColumn {
+			table.expanded = true
+			for _, col := range t.Columns {
+				table.Children = append(table.Children, &treeNode{Name: col.Name, Type: "column", Detail: col.Type})
+			}
+		}
+		schema.Children = append(schema.Children, table)
+	}
+	// A caller who may read tables but not views still gets the tables.
+	if !denied {
+		for _, v := range views {
+			schema.Children = append(schema.Children, &treeNode{Name: v.Name, Type: "view"})
+		}
+	}
+	return nil
+}
+
+// listTreeChildren lists every page of path into out. It reports a 403
+// as denied rather than as an error, so one restricted subtree does not
+// hide the rest of the tree.
+func listTreeChildren(client *gen.Client, path string, query url.Values, out interface{}) (bool, error) {
+	err := listAllItems(client, path, query, out)
+	var apiErr *gen.APIError
+	if errors.As(err, &apiErr) && apiErr.HTTPStatus == http.StatusForbidden {
+		return true, nil
+	}
+	return false, err
+}
+
+// listAllItems follows page tokens on a list endpoint and decodes every
+// item into out, which must point to a slice. query holds extra filters
+// and may be nil.
+func listAllItems(client *gen.Client, path string, query url.Values, out interface{}) error {
+	var pages []json.RawMessage
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("max_results", "1000")
+	for {
+		resp, err := client.Do(http.MethodGet, path, q, nil)
+		if err != nil {
+			return err
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return err
+		}
+		body, err := gen.ReadBody(resp)
+		if err != nil {
+			return fmt.Errorf("read response: %w", err)
+		}
+		var page listResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		if len(page.Data) > 0 && string(page.Data) != "null" {
+			pages = append(pages, page.Data)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		q.Set("page_token", page.NextPageToken)
+	}
+	return mergePages(pages, out)
+}
+
+// printTree writes the nodes with box-drawing connectors and ends with
+// totals per object type.
+func printTree(w io.Writer, catalogs []*treeNode) {
+	totals := map[string]int{}
+	var walk func(nodes []*treeNode, prefix string)
+	walk = func(nodes []*treeNode, prefix string) {
+		for i, n := range nodes {
+			totals[n.Type]++
+			connector, next := "├── ", "│   "
+			if i == len(nodes)-1 {
+				connector, next = "└── ", "    "
+			}
+			_, _ = fmt.Fprintf(w, "%s%s%s\n", prefix, connector, treeLabel(n))
+			walk(n.Children, prefix+next)
+		}
+	}
+	for _, c := range catalogs {
+		totals[c.Type]++
+		_, _ = fmt.Fprintln(w, treeLabel(c))
+		walk(c.Children, "")
+	}
+
+	parts := []string{plural(totals["catalog"], "catalog")}
+	for _, typ := range []string{"schema", "table", "view", "column"} {
+		if totals[typ] > 0 {
+			parts = append(parts, plural(totals[typ], typ))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n%s\n", strings.Join(parts, ", "))
+}
+
+// treeLabel renders a node as its name, detail, status and child counts,
+// e.g. "analytics (3 tables, 1 view)".
+func treeLabel(n *treeNode) string {
+	label := n.Name
+	switch {
+	case n.Type == "view":
+		label += " [VIEW]"
+	case n.Detail != "":
+		label += " [" + n.Detail + "]"
+	}
+	if n.Status != "" && n.Status != "ACTIVE" {
+		label += " <" + n.Status + ">"
+	}
+	if !n.expanded {
+		return label
+	}
+
+	counts := map[string]int{}
+	for _, c := range n.Children {
+		counts[c.Type]++
+	}
+	var parts []string
+	switch n.Type {
+	case "catalog":
+		parts = append(parts, plural(counts["schema"], "schema"))
+	case "schema":
+		parts = append(parts, plural(counts["table"], "table"), plural(counts["view"], "view"))
+	case "table":
+		parts = append(parts, plural(counts["column"], "column"))
+	}
+	return label + " (" + strings.Join(parts, ", ") + ")"
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCatalogTreeServer serves catalog main, with schemas analytics and
+// restricted, and a detached catalog archive. The caller may not list the
+// tables of main.restricted.
+func newCatalogTreeServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var paths []string
+	responses := map[string]string{
+		"/v1/catalogs":              `{"data":[{"name":"main","status":"ACTIVE"},{"name":"archive","status":"DETACHED"}]}`,
+		"/v1/catalogs/main":         `{"name":"main","status":"ACTIVE"}`,
+		"/v1/catalogs/main/schemas": `{"data":[{"name":"analytics"},{"name":"restricted"}]}`,
+		"/v1/catalogs/main/schemas/analytics/tables": `{"data":[
+  {"name":"orders","table_type":"MANAGED","columns":[{"name":"id","type":"BIGINT"},{"name":"amount","type":"DECIMAL(18,2)"}]},
+  {"name":"customers","table_type":"MANAGED","columns":[{"name":"id","type":"BIGINT"}]}
+]}`,
+		"/v1/catalogs/main/schemas/analytics/views": `{"data":[{"name":"recent_orders"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/catalogs/main/schemas/restricted/tables" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"code":403,"message":"access denied"}`))
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &paths
+}
+
+func runCatalogTree(t *testing.T, srv *httptest.Server, args ...string) string {
+	t.Helper()
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"--host", srv.URL}, args...))
+	require.NoError(t, rootCmd.Execute())
+	return out.String()
+}
+
+func TestCatalogTreeCmd_ReflectsSeededHierarchy(t *testing.T) {
+	srv, _ := newCatalogTreeServer(t)
+
+	out := runCatalogTree(t, srv, "catalog", "tree")
+
+	assert.Equal(t, `main (2 schemas)
+├── analytics (2 tables, 1 view)
+│   ├── orders [MANAGED]
+│   ├── customers [MANAGED]
+│   └── recent_orders [VIEW]
+└── restricted <ACCESS_DENIED>
+archive <DETACHED>
+
+2 catalogs, 2 schemas, 2 tables, 1 view
+`, out)
+}
+
+func TestCatalogTreeCmd_IncludeColumns(t *testing.T) {
+	srv, _ := newCatalogTreeServer(t)
+
+	out := runCatalogTree(t, srv, "catalog", "tree", "main", "--include-columns")
+
+	assert.Contains(t, out, "│   ├── orders [MANAGED] (2 columns)\n│   │   ├── id [BIGINT]\n│   │   └── amount [DECIMAL(18,2)]\n")
+	assert.NotContains(t, out, "archive")
+	assert.Contains(t, out, "1 catalog, 2 schemas, 2 tables, 1 view, 3 columns")
+}
+
+func TestCatalogTreeCmd_DepthStopsTraversal(t *testing.T) {
+	srv, paths := newCatalogTreeServer(t)
+
+	out := runCatalogTree(t, srv, "catalog", "tree", "--depth", "2")
+
+	assert.Contains(t, out, "main (2 schemas)\n├── analytics\n└── restricted\n")
+	for _, p := range *paths {
+		assert.NotContains(t, p, "/tables", "depth 2 must not list tables")
+	}
+}
+
+func TestCatalogTreeCmd_JSON(t *testing.T) {
+	srv, _ := newCatalogTreeServer(t)
+
+	out := runCatalogTree(t, srv, "--output", "json", "catalog", "tree", "main")
+
+	var got struct {
+		Catalogs []treeNode `json:"catalogs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	require.Len(t, got.Catalogs, 1)
+	main := got.Catalogs[0]
+	assert.Equal(t, "main", main.Name)
+	require.Len(t, main.Children, 2)
+	analytics := main.Children[0]
+	require.Len(t, analytics.Children, 3)
+	assert.Equal(t, "table", analytics.Children[0].Type)
+	assert.Equal(t, "view", analytics.Children[2].Type)
+	assert.Empty(t, analytics.Children[0].Children, "columns are omitted without --include-columns")
+	assert.Equal(t, "ACCESS_DENIED", main.Children[1].Status)
+}
@@ -10,12 +10,20 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// TestMain turns off the API version check, which would otherwise send an
+// extra request to each test server; version_check_test.go turns it back on.
+func TestMain(m *testing.M) {
+	_ = os.Setenv(noVersionCheckEnv, "1")
+	os.Exit(m.Run())
+}
+
 // capturedRequest holds details captured from an incoming HTTP request.
 type capturedRequest struct {
 	Method  string
@@ -997,3 +1005,168 @@ func TestCLI_VersionCommand_JSONOutput(t *testing.T) {
 	assert.Contains(t, result, "version")
 	assert.Contains(t, result, "commit")
 }
+
+func TestCLI_VersionCommand_Server(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"app_version":"v1.4.0","commit":"3f2c9a1e8b7d6c5f4a3b","go_version":"go1.25.1",
+"api_version":"3.0.0","duckdb_version":"v1.1.3","extensions":[{"name":"ducklake","version":"0.1.0"}],
+"metastore_schema_version":51,"compute_endpoints":[{"name":"analytics-xl","type":"REMOTE","duckdb_version":"v1.1.2"}]}`))
+	}))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "version", "--include-compute"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Equal(t, "include_compute=true", gotQuery)
+	assert.Contains(t, out.String(), "server version v1.4.0 (commit: 3f2c9a1e8b7d, go1.25.1)")
+	assert.Contains(t, out.String(), "duckdb version v1.1.3")
+	assert.Contains(t, out.String(), "extensions: ducklake 0.1.0")
+	assert.Regexp(t, `analytics-xl\s+REMOTE\s+v1\.1\.2`, out.String())
+}
+
+// === Timeout and Retry Tests ===
+
+// flakyHandler records each request and responds 503 until the succeedOn-th
+// attempt, which gets 200 with respBody.
+func flakyHandler(rec *requestRecorder, succeedOn int, respBody string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec.record(r)
+		rec.mu.Lock()
+		n := len(rec.requests)
+		rec.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if n < succeedOn {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":503,"message":"unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(respBody))
+	}
+}
+
+func TestCLI_RetriesFlakyServer(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(flakyHandler(rec, 3, `{"data":[]}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "catalog", "schemas", "list", "test"})
+
+	require.NoError(t, rootCmd.Execute())
+	assert.Len(t, rec.requests, 3)
+}
+
+func TestCLI_RetriesFlagZeroDisablesRetries(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(flakyHandler(rec, 3, `{"data":[]}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "--retries", "0", "catalog", "schemas", "list", "test"})
+
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Len(t, rec.requests, 1)
+}
+
+func TestCLI_CreateIsNotRetried(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(flakyHandler(rec, 3, `{"name":"s"}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "catalog", "schemas", "create", "s", "--catalog-name", "test"})
+
+	require.Error(t, rootCmd.Execute())
+	assert.Len(t, rec.requests, 1)
+}
+
+func TestCLI_ProfileRetriesAndTimeout(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(flakyHandler(rec, 3, `{"data":[]}`))
+	defer srv.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	zero := 0
+	require.NoError(t, SaveUserConfig(&UserConfig{
+		CurrentProfile: "default",
+		Profiles: map[string]Profile{
+			"default": {Host: srv.URL, Timeout: "5s", Retries: &zero},
+		},
+	}))
+
+	// Profile disables retries.
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"catalog", "schemas", "list", "test"})
+	require.Error(t, rootCmd.Execute())
+	assert.Len(t, rec.requests, 1)
+
+	// The flag overrides the profile.
+	rootCmd = newRootCmd()
+	rootCmd.SetArgs([]string{"--retries", "2", "catalog", "schemas", "list", "test"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Len(t, rec.requests, 3)
+}
+
+func TestCLI_TimeoutFlag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "--timeout", "20ms", "--retries", "0", "catalog", "schemas", "list", "test"})
+
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Timeout")
+}
+
+func TestCLI_InvalidTimeoutEnv(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(jsonHandler(rec, 200, `{"data":[]}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	t.Setenv("DUCK_TIMEOUT", "soon")
+	rootCmd.SetArgs([]string{"--host", srv.URL, "catalog", "schemas", "list", "test"})
+
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DUCK_TIMEOUT")
+	assert.Empty(t, rec.requests)
+}
+
+// === Verbose Tracing Tests ===
+
+func TestCLI_VerboseTracesToStderrWithRedactedAuth(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(jsonHandler(rec, 200, `{"data":[]}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "--token", "secret-jwt", "-v", "catalog", "schemas", "list", "test"})
+
+	getStderr := captureStderr(t)
+	err := rootCmd.Execute()
+	stderr := getStderr()
+	require.NoError(t, err)
+
+	assert.Contains(t, stderr, "> GET "+srv.URL+"/v1/catalogs/test/schemas")
+	assert.Contains(t, stderr, "> Authorization: [REDACTED]")
+	assert.Contains(t, stderr, "< HTTP/1.1 200 OK")
+	assert.NotContains(t, stderr, "secret-jwt")
+	assert.Equal(t, "Bearer secret-jwt", rec.last().Headers.Get("Authorization"))
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnMaskPreviewOverride(t *testing.T) {
+	const masked = `{"column_mask_id":"m-1","table_name":"sales.customers","column_name":"email","principal":"alice",
+		"masked":true,"mask_expression":"'***'","rows":[{"original":"alice@example.com","masked":"***"},{"original":null,"masked":null}]}`
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantQuery  string
+		wantOutput []string
+	}{
+		{
+			name:      "masks alias prints values side by side",
+			args:      []string{"security", "masks", "preview", "m-1", "--principal", "alice", "--limit", "5"},
+			wantQuery: "limit=5&principal=alice",
+			wantOutput: []string{
+				"sales.customers.email as seen by alice, masked with '***'",
+				"ORIGINAL",
+				"alice@example.com",
+				"***",
+			},
+		},
+		{
+			name:       "column-masks path and server default limit",
+			args:       []string{"security", "column-masks", "preview", "m-1", "--principal", "alice"},
+			wantQuery:  "principal=alice",
+			wantOutput: []string{"masked with '***'"},
+		},
+		{
+			name:       "json output",
+			args:       []string{"security", "masks", "preview", "m-1", "--principal", "alice", "-o", "json"},
+			wantQuery:  "principal=alice",
+			wantOutput: []string{`"mask_expression": "'***'"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotQuery string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotQuery = r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(masked))
+			}))
+			defer srv.Close()
+
+			var out bytes.Buffer
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL}, tt.args...))
+			require.NoError(t, rootCmd.Execute())
+
+			assert.Equal(t, "/v1/column-masks/m-1/preview", gotPath)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
+
+func TestColumnMaskPreviewOverride_RequiresPrincipal(t *testing.T) {
+	rootCmd := newRootCmd()
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"--host", "http://127.0.0.1:0", "security", "masks", "preview", "m-1"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "principal")
+}
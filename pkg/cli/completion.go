@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/internal/declarative"
+	"duck-demo/pkg/cli/gen"
+)
+
+// completionTimeout bounds each API call made while completing a resource
+// name, so an unreachable server never stalls the shell.
+const completionTimeout = 2 * time.Second
+
+// maxCompletionCandidates caps how many resources a completion request pages
+// through.
+const maxCompletionCandidates = 1000
+
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a shell completion script for duck.
+
+Resource names (principals, groups, catalogs) are completed dynamically by
+querying the API with the active profile's credentials. Without credentials,
+or when the server is unreachable, only commands and flags are completed.`,
+		Example: `  source <(duck completion bash)
+  duck completion zsh > "${fpath[1]}/_duck"
+  duck completion fish > ~/.config/fish/completions/duck.fish`,
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(os.Stdout)
+			case "fish":
+				return cmd.Root().GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				return cmd.Root().GenPowerShellCompletionWithDesc(os.Stdout)
+			default:
+				return fmt.Errorf("unsupported shell: %s", args[0])
+			}
+		},
+	}
+	return cmd
+}
+
+// completionResource describes an API list endpoint whose items complete a
+// positional argument or flag value.
+type completionResource struct {
+	path     string // list endpoint, e.g. "/principals"
+	valueKey string // item field inserted on the command line
+	descKey  string // item field shown as the description; empty for none
+}
+
+var (
+	principalIDCompletion   = completionResource{path: "/principals", valueKey: "id", descKey: "name"}
+	principalNameCompletion = completionResource{path: "/principals", valueKey: "name", descKey: "type"}
+	groupIDCompletion       = completionResource{path: "/groups", valueKey: "id", descKey: "name"}
+	catalogNameCompletion   = completionResource{path: "/catalogs", valueKey: "name", descKey: "comment"}
+)
+
+// positionalCompletions maps a positional argument name, as written in a
+// command's Use line, to the resource that completes it.
+var positionalCompletions = map[string]completionResource{
+	"principal-id": principalIDCompletion,
+	"principal":    principalNameCompletion,
+	"group-id":     groupIDCompletion,
+	"catalog-name": catalogNameCompletion,
+}
+
+// flagCompletions maps a flag name to the resource that completes its value.
+var flagCompletions = map[string]completionResource{
+	"catalog-name": catalogNameCompletion,
+	"principal":    principalNameCompletion,
+}
+
+// registerDynamicCompletions walks the command tree and attaches completion
+// functions for positional arguments and flags that name API resources.
+// clientFn returns a client configured from flags, environment and profile,
+// or nil when that configuration cannot be resolved.
+func registerDynamicCompletions(root *cobra.Command, clientFn func(*cobra.Command) *gen.Client) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+		if cmd.Name() == "completion" || cmd.Name() == "help" {
+			return
+		}
+
+		if cmd.ValidArgsFunction == nil {
+			if argNames := useArgNames(cmd.Use); hasCompletableArg(argNames) {
+				cmd.ValidArgsFunction = positionalCompletionFunc(argNames, clientFn)
+			}
+		}
+		for name, res := range flagCompletions {
+			if cmd.Flags().Lookup(name) == nil {
+				continue
+			}
+			if _, exists := cmd.GetFlagCompletionFunc(name); exists {
+				continue
+			}
+			_ = cmd.RegisterFlagCompletionFunc(name, resourceCompletionFunc(res, clientFn))
+		}
+	}
+	walk(root)
+}
+
+// useArgNames returns the positional argument names from a Use line such as
+// "get <schema-name> <table-name>".
+func useArgNames(use string) []string {
+	fields := strings.Fields(use)
+	if len(fields) < 2 {
+		return nil
+	}
+	names := make([]string, 0, len(fields)-1)
+	for _, f := range fields[1:] {
+		names = append(names, strings.Trim(f, "<>[]"))
+	}
+	return names
+}
+
+func hasCompletableArg(argNames []string) bool {
+	for _, name := range argNames {
+		if _, ok := positionalCompletions[name]; ok || name == "privilege" {
+			return true
+		}
+	}
+	return false
+}
+
+// positionalCompletionFunc completes the argument at the current position.
+func positionalCompletionFunc(argNames []string, clientFn func(*cobra.Command) *gen.Client) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(argNames) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		name := argNames[len(args)]
+		if name == "privilege" {
+			return filterByPrefix(declarative.ValidPrivileges(), strings.ToUpper(toComplete)), cobra.ShellCompDirectiveNoFileComp
+		}
+		res, ok := positionalCompletions[name]
+		if !ok {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return resourceCompletionFunc(res, clientFn)(cmd, args, toComplete)
+	}
+}
+
+// resourceCompletionFunc lists res from the API. It emits nothing when no
+// credentials are configured or the request fails for any reason.
+func resourceCompletionFunc(res completionResource, clientFn func(*cobra.Command) *gen.Client) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		client := clientFn(cmd)
+		if client == nil || (client.APIKey == "" && client.Token == "") {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		client.HTTPClient = &http.Client{Timeout: completionTimeout}
+		client.MaxRetries = 0
+		return listCompletionCandidates(client, res, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// listCompletionCandidates pages through a list endpoint and returns the
+// items whose value starts with prefix, formatted as "value\tdescription".
+func listCompletionCandidates(client *gen.Client, res completionResource, prefix string) []string {
+	var (
+		out       []string
+		seen      int
+		pageToken string
+	)
+	for seen < maxCompletionCandidates {
+		q := url.Values{}
+		q.Set("max_results", "100")
+		if pageToken != "" {
+			q.Set("page_token", pageToken)
+		}
+		resp, err := client.Do(http.MethodGet, res.path, q, nil)
+		if err != nil {
+			return nil
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return nil
+		}
+		body, err := gen.ReadBody(resp)
+		if err != nil {
+			return nil
+		}
+		var page struct {
+			Data          []map[string]interface{} `json:"data"`
+			NextPageToken string                   `json:"next_page_token"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil
+		}
+
+		for _, item := range page.Data {
+			seen++
+			value, _ := item[res.valueKey].(string)
+			if value == "" || !strings.HasPrefix(value, prefix) {
+				continue
+			}
+			if desc, _ := item[res.descKey].(string); desc != "" {
+				value += "\t" + desc
+			}
+			out = append(out, value)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return out
+}
+
+func filterByPrefix(values []string, prefix string) []string {
+	var out []string
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionCmd_StaticScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			getOutput := captureStdout(t)
+			rootCmd := newRootCmd()
+			rootCmd.SetArgs([]string{"completion", shell})
+			err := rootCmd.Execute()
+			out := getOutput()
+			require.NoError(t, err)
+			assert.Contains(t, out, "duck")
+			assert.Contains(t, out, "__complete", "script should call back into duck for dynamic completions")
+		})
+	}
+}
+
+func TestCompletionCmd_RejectsUnknownShell(t *testing.T) {
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"completion", "tcsh"})
+	require.Error(t, rootCmd.Execute())
+}
+
+// runComplete invokes cobra's hidden __complete command and returns the
+// candidate lines, without the trailing directive.
+func runComplete(t *testing.T, args ...string) []string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	for _, env := range []string{"DUCK_HOST", "DUCK_API_KEY", "DUCK_TOKEN"} {
+		t.Setenv(env, "")
+	}
+	getOutput := captureStdout(t)
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs(append([]string{"__complete"}, args...))
+	err := rootCmd.Execute()
+	out := getOutput()
+	require.NoError(t, err)
+
+	var candidates []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" || strings.HasPrefix(line, ":") {
+			continue
+		}
+		candidates = append(candidates, line)
+	}
+	return candidates
+}
+
+func TestDynamicCompletion_PrincipalIDs(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		require.Equal(t, "/v1/principals", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[
+			{"id":"11111111-1111-1111-1111-111111111111","name":"alice","type":"user"},
+			{"id":"22222222-2222-2222-2222-222222222222","name":"etl-bot","type":"service_principal"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	got := runComplete(t, "--host", srv.URL, "--token", "tok", "security", "principals", "get", "1")
+	assert.Equal(t, []string{"11111111-1111-1111-1111-111111111111\talice"}, got)
+	assert.Equal(t, "Bearer tok", gotAuth)
+}
+
+func TestDynamicCompletion_CatalogNames(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"name":"lake"},{"name":"archive"}]}`))
+	}))
+	defer srv.Close()
+
+	got := runComplete(t, "--host", srv.URL, "--api-key", "key", "catalog", "tables", "list", "--catalog-name", "")
+	assert.ElementsMatch(t, []string{"lake", "archive"}, got)
+
+	got = runComplete(t, "--host", srv.URL, "--api-key", "key", "catalog", "schemas", "list", "ar")
+	assert.Equal(t, []string{"archive"}, got)
+}
+
+func TestDynamicCompletion_Privileges(t *testing.T) {
+	got := runComplete(t, "security", "check", "alice", "sel")
+	assert.Equal(t, []string{"SELECT"}, got)
+}
+
+func TestDynamicCompletion_SilentWithoutCredentials(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	got := runComplete(t, "--host", srv.URL, "security", "principals", "get", "")
+	assert.Empty(t, got)
+	assert.False(t, called, "no API call should be made without credentials")
+}
+
+func TestDynamicCompletion_SilentWhenOffline(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	host := srv.URL
+	srv.Close()
+
+	got := runComplete(t, "--host", host, "--token", "tok", "security", "groups", "get", "")
+	assert.Empty(t, got)
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// endpointStatus is one row of `duck compute endpoints status` output.
+type endpointStatus struct {
+	Name   string        `json:"name"`
+	Type   string        `json:"type"`
+	Status string        `json:"status"`
+	Load   *endpointLoad `json:"load,omitempty"`
+}
+
+// endpointLoad mirrors the load the control plane last polled from an agent.
+type endpointLoad struct {
+	Reachable            bool       `json:"reachable"`
+	ActiveQueries        int64      `json:"active_queries"`
+	WaitingQueries       int64      `json:"waiting_queries"`
+	MaxConcurrentQueries int64      `json:"max_concurrent_queries"`
+	QueuedJobs           int64      `json:"queued_jobs"`
+	RunningJobs          int64      `json:"running_jobs"`
+	MemoryUsedMB         int64      `json:"memory_used_mb"`
+	ScalingHint          string     `json:"scaling_hint"`
+	LastError            string     `json:"last_error,omitempty"`
+	LastHeartbeatAt      *time.Time `json:"last_heartbeat_at,omitempty"`
+	CheckedAt            *time.Time `json:"checked_at,omitempty"`
+}
+
+// newComputeEndpointsStatusCmd builds `duck compute endpoints status`, which
+// shows the load and scaling hint of every compute endpoint.
+func newComputeEndpointsStatusCmd(client *gen.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show load and scaling hints of compute endpoints",
+		Long: "Lists every compute endpoint with the load the control plane last polled from its agent: " +
+			"running and queued statements, the agent's concurrency limit, the time of the last successful " +
+			"heartbeat and a scaling hint (SCALE_UP, OK, IDLE or UNREACHABLE). Endpoints that have not been " +
+			"polled yet, such as LOCAL ones, show no load.",
+		Example: "  duck compute endpoints status\n" +
+			"  duck compute endpoints status -o json",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var endpoints []endpointStatus
+			if err := listAllItems(client, "/compute-endpoints", nil, &endpoints); err != nil {
+				return err
+			}
+
+			if getOutputFormat(cmd) == "json" {
+				return gen.PrintJSON(cmd.OutOrStdout(), map[string]interface{}{"data": endpoints})
+			}
+			rows := make([][]string, 0, len(endpoints))
+			for _, ep := range endpoints {
+				rows = append(rows, endpointStatusRow(ep))
+			}
+			gen.PrintTable(cmd.OutOrStdout(),
+				[]string{"NAME", "TYPE", "STATUS", "ACTIVE", "WAITING", "LIMIT", "HINT", "LAST HEARTBEAT"}, rows)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// endpointStatusRow renders one table row; load columns are "-" when the
+// endpoint has not been polled.
+func endpointStatusRow(ep endpointStatus) []string {
+	row := []string{ep.Name, ep.Type, ep.Status, "-", "-", "-", "-", "-"}
+	l := ep.Load
+	if l == nil {
+		return row
+	}
+	row[3] = fmt.Sprint(l.ActiveQueries)
+	row[4] = fmt.Sprint(l.WaitingQueries)
+	if l.MaxConcurrentQueries > 0 {
+		row[5] = fmt.Sprint(l.MaxConcurrentQueries)
+	}
+	row[6] = l.ScalingHint
+	if l.LastHeartbeatAt != nil {
+		row[7] = l.LastHeartbeatAt.Local().Format(time.RFC3339)
+	}
+	return row
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newComputeStatusServer serves two endpoints over two pages: a polled
+// remote agent with a full queue and a local endpoint without load.
+func newComputeStatusServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v1/compute-endpoints" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("page_token") == "" {
+			_, _ = w.Write([]byte(`{"data":[{"name":"analytics-xl","type":"REMOTE","status":"ACTIVE",` +
+				`"load":{"reachable":true,"active_queries":4,"waiting_queries":2,"max_concurrent_queries":4,` +
+				`"scaling_hint":"SCALE_UP","last_heartbeat_at":"2025-01-15T10:30:00Z","checked_at":"2025-01-15T10:30:00Z"}}],` +
+				`"next_page_token":"p2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"name":"local","type":"LOCAL","status":"ACTIVE"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestComputeEndpointsStatusCmd_JSON(t *testing.T) {
+	srv := newComputeStatusServer(t)
+
+	out := runCatalogTree(t, srv, "--output", "json", "compute", "endpoints", "status")
+
+	var got struct {
+		Data []endpointStatus `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	require.Len(t, got.Data, 2)
+	require.NotNil(t, got.Data[0].Load)
+	assert.Equal(t, int64(4), got.Data[0].Load.ActiveQueries)
+	assert.Equal(t, int64(2), got.Data[0].Load.WaitingQueries)
+	assert.Equal(t, "SCALE_UP", got.Data[0].Load.ScalingHint)
+	assert.Nil(t, got.Data[1].Load)
+}
+
+func TestComputeEndpointsStatusCmd_Table(t *testing.T) {
+	srv := newComputeStatusServer(t)
+
+	out := runCatalogTree(t, srv, "compute", "endpoints", "status")
+
+	assert.Contains(t, out, "LAST HEARTBEAT")
+	assert.Regexp(t, `analytics-xl\s+REMOTE\s+ACTIVE\s+4\s+2\s+4\s+SCALE_UP`, out)
+	assert.Regexp(t, `local\s+LOCAL\s+ACTIVE\s+-\s+-\s+-\s+-\s+-`, out)
+}
@@ -20,6 +20,12 @@ type Profile struct {
 	APIKey string `yaml:"api-key,omitempty"`
 	Token  string `yaml:"token,omitempty"`
 	Output string `yaml:"output,omitempty"`
+
+	// Timeout is the per-request timeout as a Go duration, e.g. "45s".
+	Timeout string `yaml:"timeout,omitempty"`
+	// Retries is the retry count for transient failures; nil means the
+	// client default.
+	Retries *int `yaml:"retries,omitempty"`
 }
 
 // ActiveProfile returns the profile to use based on the override or current-profile.
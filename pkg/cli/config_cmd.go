@@ -63,10 +63,12 @@ func maskConfig(cfg *UserConfig) *UserConfig {
 	}
 	for name, p := range cfg.Profiles {
 		masked.Profiles[name] = Profile{
-			Host:   p.Host,
-			APIKey: maskSecret(p.APIKey),
-			Token:  maskSecret(p.Token),
-			Output: p.Output,
+			Host:    p.Host,
+			APIKey:  maskSecret(p.APIKey),
+			Token:   maskSecret(p.Token),
+			Output:  p.Output,
+			Timeout: p.Timeout,
+			Retries: p.Retries,
 		}
 	}
 	return masked
@@ -85,11 +87,13 @@ func maskSecret(s string) string {
 
 func newConfigSetProfileCmd() *cobra.Command {
 	var (
-		name   string
-		host   string
-		apiKey string
-		token  string
-		output string
+		name    string
+		host    string
+		apiKey  string
+		token   string
+		output  string
+		timeout string
+		retries int
 	)
 
 	cmd := &cobra.Command{
@@ -104,6 +108,14 @@ func newConfigSetProfileCmd() *cobra.Command {
 					return err
 				}
 			}
+			if cmd.Flags().Changed("timeout") {
+				if _, err := parseTimeout(timeout); err != nil {
+					return err
+				}
+			}
+			if cmd.Flags().Changed("retries") && retries < 0 {
+				return fmt.Errorf("--retries must not be negative")
+			}
 
 			cfg, err := LoadUserConfig()
 			if err != nil {
@@ -126,6 +138,12 @@ func newConfigSetProfileCmd() *cobra.Command {
 			if cmd.Flags().Changed("output") {
 				p.Output = output
 			}
+			if cmd.Flags().Changed("timeout") {
+				p.Timeout = timeout
+			}
+			if cmd.Flags().Changed("retries") {
+				p.Retries = &retries
+			}
 			cfg.Profiles[name] = p
 
 			if err := SaveUserConfig(cfg); err != nil {
@@ -148,6 +166,8 @@ func newConfigSetProfileCmd() *cobra.Command {
 	cmd.Flags().StringVar(&apiKey, "api-key", "", "API key")
 	cmd.Flags().StringVar(&token, "token", "", "JWT token")
 	cmd.Flags().StringVar(&output, "output", "", "Default output format")
+	cmd.Flags().StringVar(&timeout, "timeout", "", "Default request timeout (e.g. 45s, 2m)")
+	cmd.Flags().IntVar(&retries, "retries", 0, "Default retry count for transient failures")
 	_ = cmd.MarkFlagRequired("name")
 
 	return cmd
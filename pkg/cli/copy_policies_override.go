@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// copyTablePolicies names both tables as catalog.schema.table with --from
+	// and --to instead of positional source parts and target-* flags.
+	gen.RegisterOverride("copyTablePolicies", func(c *cobra.Command) {
+		c.Use = "copy-policies --from <catalog.schema.table> --to <catalog.schema.table>"
+		c.Args = cobra.NoArgs
+		_ = c.Flags().SetAnnotation("catalog-name", cobra.BashCompOneRequiredFlag, []string{"false"})
+		for _, name := range []string{"catalog-name", "target-schema", "target-table", "json"} {
+			_ = c.Flags().MarkHidden(name)
+		}
+		c.Flags().String("from", "", "Table to copy policies from, as catalog.schema.table")
+		c.Flags().String("to", "", "Table to copy policies to, as catalog.schema.table")
+		_ = c.MarkFlagRequired("from")
+		_ = c.MarkFlagRequired("to")
+	})
+	gen.RegisterRunOverride("copyTablePolicies", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, _ []string) error {
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			return runCopyPolicies(cmd, client, from, to)
+		}
+	})
+}
+
+// copyPoliciesResult mirrors the API's CopyPoliciesResult.
+type copyPoliciesResult struct {
+	DryRun bool `json:"dry_run"`
+	Copied []struct {
+		Kind        string `json:"kind"`
+		Description string `json:"description"`
+	} `json:"copied"`
+	Skipped []struct {
+		Kind        string `json:"kind"`
+		Description string `json:"description"`
+		Reason      string `json:"reason"`
+	} `json:"skipped"`
+}
+
+// runCopyPolicies copies the policies of the table at from to the table at
+// to, which must be in the same catalog, and reports what was copied and
+// what was not.
+func runCopyPolicies(cmd *cobra.Command, client *gen.Client, from, to string) error {
+	urlPath, err := tableActionURL(from, "copy-policies")
+	if err != nil {
+		return err
+	}
+	target := strings.Split(to, ".")
+	if len(target) != 3 {
+		return fmt.Errorf("invalid table path %q: expected catalog.schema.table", to)
+	}
+	if catalog := strings.SplitN(from, ".", 2)[0]; target[0] != catalog {
+		return fmt.Errorf("cannot copy policies across catalogs: %q is not in catalog %q", to, catalog)
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	body := map[string]interface{}{"target_schema": target[1], "target_table": target[2], "dry_run": dryRun}
+
+	resp, err := client.Do("POST", urlPath, nil, body)
+	if err != nil {
+		return err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return err
+	}
+	raw, err := gen.ReadBody(resp)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if getOutputFormat(cmd) == "json" {
+		return printRawJSON(cmd.OutOrStdout(), raw)
+	}
+	var res copyPoliciesResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	printCopyPolicies(cmd.OutOrStdout(), from, to, &res)
+	return nil
+}
+
+func printCopyPolicies(w io.Writer, from, to string, res *copyPoliciesResult) {
+	verb := "Copied"
+	if res.DryRun {
+		verb = "Would copy"
+	}
+	_, _ = fmt.Fprintf(w, "%s %d policies from %s to %s\n", verb, len(res.Copied), from, to)
+	for _, p := range res.Copied {
+		_, _ = fmt.Fprintf(w, "  %-11s %s\n", p.Kind, p.Description)
+	}
+	if len(res.Skipped) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Could not copy %d policies:\n", len(res.Skipped))
+	for _, p := range res.Skipped {
+		_, _ = fmt.Fprintf(w, "  %-11s %s (%s)\n", p.Kind, p.Description, p.Reason)
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyPoliciesOverride(t *testing.T) {
+	const result = `{"source":"analytics.orders","target":"analytics.orders_v2","dry_run":false,
+		"copied":[{"kind":"GRANT","description":"SELECT to group g-1"},{"kind":"COLUMN_MASK","description":"email: '***'"}],
+		"skipped":[{"kind":"COLUMN_MASK","description":"ssn: NULL","reason":"column \"ssn\" does not exist on the target"}]}`
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantBody   map[string]interface{}
+		wantOutput []string
+		errContain string
+	}{
+		{
+			name:     "copies and reports skipped policies",
+			args:     []string{"catalog", "copy-policies", "--from", "demo.analytics.orders", "--to", "demo.analytics.orders_v2"},
+			wantBody: map[string]interface{}{"target_schema": "analytics", "target_table": "orders_v2", "dry_run": false},
+			wantOutput: []string{
+				"Copied 2 policies from demo.analytics.orders to demo.analytics.orders_v2",
+				"GRANT       SELECT to group g-1",
+				"Could not copy 1 policies:",
+				`COLUMN_MASK ssn: NULL (column "ssn" does not exist on the target)`,
+			},
+		},
+		{
+			name:     "dry run",
+			args:     []string{"catalog", "copy-policies", "--from", "demo.analytics.orders", "--to", "demo.analytics.orders_v2", "--dry-run"},
+			wantBody: map[string]interface{}{"target_schema": "analytics", "target_table": "orders_v2", "dry_run": true},
+		},
+		{
+			name:       "requires --to",
+			args:       []string{"catalog", "copy-policies", "--from", "demo.analytics.orders"},
+			errContain: `"to" not set`,
+		},
+		{
+			name:       "rejects another catalog",
+			args:       []string{"catalog", "copy-policies", "--from", "demo.analytics.orders", "--to", "prod.analytics.orders"},
+			errContain: "cannot copy policies across catalogs",
+		},
+		{
+			name:       "rejects a target without catalog",
+			args:       []string{"catalog", "copy-policies", "--from", "demo.analytics.orders", "--to", "analytics.orders_v2"},
+			errContain: "expected catalog.schema.table",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotBody map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				data, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(data, &gotBody)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(result))
+			}))
+			defer srv.Close()
+
+			var out bytes.Buffer
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL}, tt.args...))
+			err := rootCmd.Execute()
+
+			if tt.errContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContain)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "/v1/catalogs/demo/schemas/analytics/tables/orders:copy-policies", gotPath)
+			assert.Equal(t, tt.wantBody, gotBody)
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -26,6 +27,37 @@ const (
 // APIStateClientOptions configures APIStateClient behavior.
 type APIStateClientOptions struct {
 	CompatibilityMode CapabilityCompatibilityMode
+	// ApplyRunID prefixes the Idempotency-Key of every create. Clients that
+	// share a run ID replay each other's creates, so a resumed apply passes
+	// the interrupted run's ID. Empty generates a new ID.
+	ApplyRunID string
+}
+
+// batchOpGroupMembersSync is the batch operation a server advertises when it
+// accepts PUT /groups/{id}/members.
+const batchOpGroupMembersSync = "group_members_sync"
+
+// ServerCapabilities is the server's answer to GET /capabilities.
+type ServerCapabilities struct {
+	APIVersion string `json:"api_version"`
+	Features   struct {
+		Models   bool `json:"models"`
+		Macros   bool `json:"macros"`
+		Semantic bool `json:"semantic"`
+		Compute  bool `json:"compute"`
+		Webhooks bool `json:"webhooks"`
+	} `json:"features"`
+	BatchOperations []string `json:"batch_operations"`
+}
+
+// SupportsBatch reports whether the server advertises the batch operation op.
+func (s *ServerCapabilities) SupportsBatch(op string) bool {
+	for _, o := range s.BatchOperations {
+		if o == op {
+			return true
+		}
+	}
+	return false
 }
 
 func normalizeCompatibilityMode(mode CapabilityCompatibilityMode) CapabilityCompatibilityMode {
@@ -101,6 +133,94 @@ func (c *APIStateClient) addOptionalReadWarning(resource string, err error) {
 		fmt.Sprintf("%s endpoint read failed in compatibility mode: %v", resource, err))
 }
 
+// NegotiateCapabilities performs the capability handshake once per client.
+// When the server advertises its capabilities, optional endpoints are known
+// up front: features it reports as disabled are skipped instead of probed,
+// batching follows the advertised batch operations, and the client switches
+// to strict compatibility because no endpoint absence needs to be guessed
+// from transport errors. Servers that predate the handshake yield nil, and
+// the client keeps probing each endpoint in the configured mode.
+func (c *APIStateClient) NegotiateCapabilities(_ context.Context) (*ServerCapabilities, error) {
+	if c.negotiated {
+		return c.capabilities, nil
+	}
+	caps, err := c.fetchCapabilities()
+	if err != nil {
+		if !c.isOptionalReadError(err) {
+			return nil, fmt.Errorf("negotiate capabilities: %w", err)
+		}
+		caps = nil
+	}
+	c.negotiated = true
+	c.capabilities = caps
+	if caps != nil {
+		c.compatibilityMode = CapabilityCompatibilityStrict
+	}
+	return caps, nil
+}
+
+func (c *APIStateClient) fetchCapabilities() (*ServerCapabilities, error) {
+	resp, err := c.client.Do(http.MethodGet, "/capabilities", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("GET /capabilities: %w", err)
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read GET /capabilities: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GET /capabilities: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+	var caps ServerCapabilities
+	if err := json.Unmarshal(body, &caps); err != nil || caps.APIVersion == "" {
+		// Not a capabilities document, e.g. a catch-all route in front of
+		// an older server; fall back to probing.
+		return nil, nil
+	}
+	return &caps, nil
+}
+
+// kindFeatures maps the resource kinds that belong to an optional feature
+// area to the feature name advertised in GET /capabilities.
+var kindFeatures = []struct {
+	kind    declarative.ResourceKind
+	feature string
+}{
+	{declarative.KindModel, "models"},
+	{declarative.KindMacro, "macros"},
+	{declarative.KindSemanticModel, "semantic"},
+	{declarative.KindComputeEndpoint, "compute"},
+	{declarative.KindComputeAssignment, "compute"},
+}
+
+// featureEnabled reports whether the named feature may be used. Without an
+// advertised capability set every feature is assumed present.
+func (c *APIStateClient) featureEnabled(feature string) bool {
+	if c.capabilities == nil {
+		return true
+	}
+	f := c.capabilities.Features
+	switch feature {
+	case "models":
+		return f.Models
+	case "macros":
+		return f.Macros
+	case "semantic":
+		return f.Semantic
+	case "compute":
+		return f.Compute
+	case "webhooks":
+		return f.Webhooks
+	default:
+		return true
+	}
+}
+
+func (c *APIStateClient) addDisabledFeatureWarning(resource string) {
+	c.optionalReadWarnings = append(c.optionalReadWarnings,
+		fmt.Sprintf("%s disabled on server; continuing without %s state", resource, resource))
+}
+
 func endpointRequiredByPlan(actions []declarative.Action, kind declarative.ResourceKind) bool {
 	for _, action := range actions {
 		if action.ResourceKind == kind {
@@ -129,8 +249,20 @@ func (c *APIStateClient) probeEndpoint(ctx context.Context, path string) error {
 }
 
 // ValidateApplyCapabilities validates that optional model/macro endpoints
-// required by the current plan are available before execution starts.
+// required by the current plan are available before execution starts. When
+// the server advertised its capabilities they are checked without probing.
 func (c *APIStateClient) ValidateApplyCapabilities(ctx context.Context, actions []declarative.Action) error {
+	if _, err := c.NegotiateCapabilities(ctx); err != nil {
+		return err
+	}
+	if c.capabilities != nil {
+		for _, kf := range kindFeatures {
+			if endpointRequiredByPlan(actions, kf.kind) && !c.featureEnabled(kf.feature) {
+				return fmt.Errorf("%s actions present but the server does not enable %s", kf.kind, kf.feature)
+			}
+		}
+		return nil
+	}
 	if endpointRequiredByPlan(actions, declarative.KindModel) {
 		if err := c.probeEndpoint(ctx, "/models"); err != nil {
 			if c.isOptionalReadError(err) {
@@ -3,11 +3,14 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"sort"
 	"strings"
+	"sync"
 
 	"duck-demo/internal/declarative"
 	"duck-demo/pkg/cli/gen"
@@ -42,6 +45,19 @@ type resourceIndex struct {
 	notebookIDByName      map[string]string // "kpi_walkthrough" → UUID
 	pipelineIDByName      map[string]string // "daily_pipeline" → UUID
 	jobIDByPath           map[string]string // "pipeline/job" → UUID
+
+	groupMembersByName map[string][]declarative.MemberRef // "admins" → current members
+	etagByPath         map[string]string                  // "/notebooks/<id>" → ETag read
+
+	// missing records schemas and tables ("schema:cat.sch") the server
+	// answered 404 for, or that this run deleted, so references to them are
+	// not fetched again. Recording the resource's ID clears the entry.
+	missing map[string]bool
+
+	// mu guards the maps, which are only accessed through the accessor
+	// methods: actions executed concurrently resolve names and record the
+	// IDs of what they create.
+	mu sync.Mutex
 }
 
 func newResourceIndex() *resourceIndex {
@@ -62,15 +78,329 @@ func newResourceIndex() *resourceIndex {
 		notebookIDByName:      make(map[string]string),
 		pipelineIDByName:      make(map[string]string),
 		jobIDByPath:           make(map[string]string),
+		groupMembersByName:    make(map[string][]declarative.MemberRef),
+		etagByPath:            make(map[string]string),
+		missing:               make(map[string]bool),
+	}
+}
+
+// The accessors below are the only way to reach the index's maps; each holds
+// ix.mu so actions executed concurrently can resolve and record IDs.
+
+func (ix *resourceIndex) get(m map[string]string, key string) (string, bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	id, ok := m[key]
+	return id, ok
+}
+
+func (ix *resourceIndex) set(m map[string]string, key, id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	m[key] = id
+}
+
+func (ix *resourceIndex) remove(m map[string]string, key string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	delete(m, key)
+}
+
+// setFound records id under key in m and clears any missing mark for the
+// resource, which may have been created since it was found absent.
+func (ix *resourceIndex) setFound(m map[string]string, kind, key, id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	m[key] = id
+	delete(ix.missing, kind+":"+key)
+}
+
+// isMissing reports whether the kind resource at key is known not to exist.
+func (ix *resourceIndex) isMissing(kind, key string) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return ix.missing[kind+":"+key]
+}
+
+func (ix *resourceIndex) markMissing(kind, key string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.missing[kind+":"+key] = true
+}
+
+// keyByID returns the key under which id is stored in m, or "" if it is not.
+func (ix *resourceIndex) keyByID(m map[string]string, id string) string {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for key, storedID := range m {
+		if storedID == id {
+			return key
+		}
+	}
+	return ""
+}
+
+func (ix *resourceIndex) getPrincipalID(name string) (string, bool) {
+	return ix.get(ix.principalIDByName, name)
+}
+
+func (ix *resourceIndex) setPrincipalID(name, id string) {
+	ix.set(ix.principalIDByName, name, id)
+}
+
+func (ix *resourceIndex) getPrincipalNameByID(id string) string {
+	return ix.keyByID(ix.principalIDByName, id)
+}
+
+func (ix *resourceIndex) getGroupID(name string) (string, bool) {
+	return ix.get(ix.groupIDByName, name)
+}
+
+func (ix *resourceIndex) setGroupID(name, id string) {
+	ix.set(ix.groupIDByName, name, id)
+}
+
+func (ix *resourceIndex) getGroupNameByID(id string) string {
+	return ix.keyByID(ix.groupIDByName, id)
+}
+
+func (ix *resourceIndex) getCatalogID(name string) (string, bool) {
+	return ix.get(ix.catalogIDByName, name)
+}
+
+func (ix *resourceIndex) setCatalogID(name, id string) {
+	ix.set(ix.catalogIDByName, name, id)
+}
+
+func (ix *resourceIndex) getCatalogNameByID(id string) string {
+	return ix.keyByID(ix.catalogIDByName, id)
+}
+
+func (ix *resourceIndex) getSemanticModelID(path string) (string, bool) {
+	return ix.get(ix.semanticModelIDByPath, path)
+}
+
+func (ix *resourceIndex) setSemanticModelID(path, id string) {
+	ix.set(ix.semanticModelIDByPath, path, id)
+}
+
+func (ix *resourceIndex) getSchemaID(path string) (string, bool) {
+	return ix.get(ix.schemaIDByPath, path)
+}
+
+func (ix *resourceIndex) setSchemaID(path, id string) {
+	ix.setFound(ix.schemaIDByPath, "schema", path, id)
+}
+
+// deleteSchemaID forgets a deleted schema, along with the tables and
+// volumes that were dropped with it, and records it as missing.
+func (ix *resourceIndex) deleteSchemaID(path string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	delete(ix.schemaIDByPath, path)
+	ix.missing["schema:"+path] = true
+	for _, m := range []map[string]string{ix.tableIDByPath, ix.volumeIDByPath} {
+		for key := range m {
+			if strings.HasPrefix(key, path+".") {
+				delete(m, key)
+			}
+		}
 	}
 }
 
+func (ix *resourceIndex) getSchemaPathByID(id string) string {
+	return ix.keyByID(ix.schemaIDByPath, id)
+}
+
+func (ix *resourceIndex) getTableID(path string) (string, bool) {
+	return ix.get(ix.tableIDByPath, path)
+}
+
+func (ix *resourceIndex) setTableID(path, id string) {
+	ix.setFound(ix.tableIDByPath, "table", path, id)
+}
+
+// deleteTableID forgets a deleted table and records it as missing.
+func (ix *resourceIndex) deleteTableID(path string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	delete(ix.tableIDByPath, path)
+	ix.missing["table:"+path] = true
+}
+
+func (ix *resourceIndex) getTablePathByID(id string) string {
+	return ix.keyByID(ix.tableIDByPath, id)
+}
+
+func (ix *resourceIndex) getVolumeID(path string) (string, bool) {
+	return ix.get(ix.volumeIDByPath, path)
+}
+
+func (ix *resourceIndex) setVolumeID(path, id string) {
+	ix.set(ix.volumeIDByPath, path, id)
+}
+
+func (ix *resourceIndex) deleteVolumeID(path string) {
+	ix.remove(ix.volumeIDByPath, path)
+}
+
+func (ix *resourceIndex) getVolumePathByID(id string) string {
+	return ix.keyByID(ix.volumeIDByPath, id)
+}
+
+func (ix *resourceIndex) getLocationID(name string) (string, bool) {
+	return ix.get(ix.locationIDByName, name)
+}
+
+func (ix *resourceIndex) setLocationID(name, id string) {
+	ix.set(ix.locationIDByName, name, id)
+}
+
+func (ix *resourceIndex) getLocationNameByID(id string) string {
+	return ix.keyByID(ix.locationIDByName, id)
+}
+
+func (ix *resourceIndex) getCredentialID(name string) (string, bool) {
+	return ix.get(ix.credentialIDByName, name)
+}
+
+func (ix *resourceIndex) setCredentialID(name, id string) {
+	ix.set(ix.credentialIDByName, name, id)
+}
+
+func (ix *resourceIndex) getCredentialNameByID(id string) string {
+	return ix.keyByID(ix.credentialIDByName, id)
+}
+
+func (ix *resourceIndex) getComputeID(name string) (string, bool) {
+	return ix.get(ix.computeIDByName, name)
+}
+
+func (ix *resourceIndex) setComputeID(name, id string) {
+	ix.set(ix.computeIDByName, name, id)
+}
+
+func (ix *resourceIndex) getComputeNameByID(id string) string {
+	return ix.keyByID(ix.computeIDByName, id)
+}
+
+func (ix *resourceIndex) getTagID(key string) (string, bool) {
+	return ix.get(ix.tagIDByKey, key)
+}
+
+func (ix *resourceIndex) setTagID(key, id string) {
+	ix.set(ix.tagIDByKey, key, id)
+}
+
+func (ix *resourceIndex) getRowFilterID(path string) (string, bool) {
+	return ix.get(ix.rowFilterIDByPath, path)
+}
+
+func (ix *resourceIndex) setRowFilterID(path, id string) {
+	ix.set(ix.rowFilterIDByPath, path, id)
+}
+
+func (ix *resourceIndex) getColumnMaskID(path string) (string, bool) {
+	return ix.get(ix.columnMaskIDByPath, path)
+}
+
+func (ix *resourceIndex) setColumnMaskID(path, id string) {
+	ix.set(ix.columnMaskIDByPath, path, id)
+}
+
+func (ix *resourceIndex) getNotebookID(name string) (string, bool) {
+	return ix.get(ix.notebookIDByName, name)
+}
+
+func (ix *resourceIndex) setNotebookID(name, id string) {
+	ix.set(ix.notebookIDByName, name, id)
+}
+
+func (ix *resourceIndex) deleteNotebookID(name string) {
+	ix.remove(ix.notebookIDByName, name)
+}
+
+func (ix *resourceIndex) getNotebookNameByID(id string) string {
+	return ix.keyByID(ix.notebookIDByName, id)
+}
+
+func (ix *resourceIndex) getPipelineID(name string) (string, bool) {
+	return ix.get(ix.pipelineIDByName, name)
+}
+
+func (ix *resourceIndex) setPipelineID(name, id string) {
+	ix.set(ix.pipelineIDByName, name, id)
+}
+
+func (ix *resourceIndex) deletePipelineID(name string) {
+	ix.remove(ix.pipelineIDByName, name)
+}
+
+func (ix *resourceIndex) getJobID(path string) (string, bool) {
+	return ix.get(ix.jobIDByPath, path)
+}
+
+func (ix *resourceIndex) setJobID(path, id string) {
+	ix.set(ix.jobIDByPath, path, id)
+}
+
+func (ix *resourceIndex) deleteJobID(path string) {
+	ix.remove(ix.jobIDByPath, path)
+}
+
+func (ix *resourceIndex) getGroupMembers(groupName string) []declarative.MemberRef {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return ix.groupMembersByName[groupName]
+}
+
+func (ix *resourceIndex) setGroupMembers(groupName string, members []declarative.MemberRef) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.groupMembersByName[groupName] = members
+}
+
+func (ix *resourceIndex) setETag(path, tag string) {
+	ix.set(ix.etagByPath, path, tag)
+}
+
+// takeETag returns the ETag recorded for path, or "" when none was, and
+// forgets it.
+func (ix *resourceIndex) takeETag(path string) string {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	tag := ix.etagByPath[path]
+	delete(ix.etagByPath, path)
+	return tag
+}
+
 // APIStateClient implements both StateReader and StateWriter using the gen.Client.
 type APIStateClient struct {
 	client               *gen.Client
 	index                *resourceIndex
 	compatibilityMode    CapabilityCompatibilityMode
 	optionalReadWarnings []string
+
+	// memberSyncs holds per-group membership batches built by
+	// PrepareGroupMemberSync; memberSyncUnsupported is set once the server
+	// rejects the bulk endpoint so remaining actions run one member at a time.
+	memberSyncs           map[string]*groupMemberSync
+	memberSyncUnsupported bool
+
+	// capabilities is the server's advertised capability set; nil when the
+	// server predates GET /capabilities. negotiated is set once the
+	// handshake has run.
+	capabilities *ServerCapabilities
+	negotiated   bool
+
+	// runID prefixes the Idempotency-Key of every create. Actions in
+	// reapply get keys distinct from the run's original ones.
+	runID   string
+	reapply map[string]bool
+
+	// mu guards reapply and the group membership batches, which actions
+	// executed concurrently share.
+	mu sync.Mutex
 }
 
 // Compile-time interface checks.
@@ -86,9 +416,14 @@ func NewAPIStateClient(client *gen.Client) *APIStateClient {
 
 // NewAPIStateClientWithOptions creates a new client adapter with behavior options.
 func NewAPIStateClientWithOptions(client *gen.Client, options APIStateClientOptions) *APIStateClient {
+	runID := options.ApplyRunID
+	if runID == "" {
+		runID = newApplyRunID()
+	}
 	return &APIStateClient{
 		client:            client,
 		compatibilityMode: normalizeCompatibilityMode(options.CompatibilityMode),
+		runID:             runID,
 	}
 }
 
@@ -99,14 +434,28 @@ type listResponse struct {
 }
 
 // fetchAllPages fetches all pages from a paginated list endpoint.
-// The dataKey param selects between the standard "data" key and alternate keys.
-func (c *APIStateClient) fetchAllPages(_ context.Context, path string) ([]json.RawMessage, error) {
+// When fields are given, the server returns only those fields of each item;
+// servers that do not support ?fields= ignore it and return them in full.
+func (c *APIStateClient) fetchAllPages(ctx context.Context, path string, fields ...string) ([]json.RawMessage, error) {
+	return c.fetchAllPagesSorted(ctx, path, "", fields...)
+}
+
+// fetchAllPagesSorted is fetchAllPages with an explicit ?sort= order, so
+// reads of endpoints whose default order is not by name export the same
+// way on every run.
+func (c *APIStateClient) fetchAllPagesSorted(_ context.Context, path, order string, fields ...string) ([]json.RawMessage, error) {
 	var all []json.RawMessage
 	pageToken := ""
 
 	for {
 		q := url.Values{}
 		q.Set("max_results", "1000")
+		if order != "" {
+			q.Set("sort", order)
+		}
+		if len(fields) > 0 {
+			q.Set("fields", strings.Join(fields, ","))
+		}
 		if pageToken != "" {
 			q.Set("page_token", pageToken)
 		}
@@ -169,6 +518,9 @@ func mergePages(pages []json.RawMessage, target interface{}) error {
 func (c *APIStateClient) ReadState(ctx context.Context) (*declarative.DesiredState, error) {
 	c.index = newResourceIndex()
 	c.optionalReadWarnings = nil
+	if _, err := c.NegotiateCapabilities(ctx); err != nil {
+		return nil, err
+	}
 	state := &declarative.DesiredState{}
 
 	if err := c.readPrincipals(ctx, state); err != nil {
@@ -192,7 +544,9 @@ func (c *APIStateClient) ReadState(ctx context.Context) (*declarative.DesiredSta
 	if err := c.readGrants(ctx, state); err != nil {
 		return nil, fmt.Errorf("read grants: %w", err)
 	}
-	if err := c.readComputeEndpoints(ctx, state); err != nil {
+	if !c.featureEnabled("compute") {
+		c.addDisabledFeatureWarning("compute endpoints")
+	} else if err := c.readComputeEndpoints(ctx, state); err != nil {
 		return nil, fmt.Errorf("read compute endpoints: %w", err)
 	}
 	if err := c.readTags(ctx, state); err != nil {
@@ -204,19 +558,25 @@ func (c *APIStateClient) ReadState(ctx context.Context) (*declarative.DesiredSta
 	if err := c.readPipelines(ctx, state); err != nil {
 		return nil, fmt.Errorf("read pipelines: %w", err)
 	}
-	if err := c.readMacros(ctx, state); err != nil {
+	if !c.featureEnabled("macros") {
+		c.addDisabledFeatureWarning("macros")
+	} else if err := c.readMacros(ctx, state); err != nil {
 		if !c.isOptionalReadError(err) {
 			return nil, fmt.Errorf("read macros: %w", err)
 		}
 		c.addOptionalReadWarning("macros", err)
 	}
-	if err := c.readModels(ctx, state); err != nil {
+	if !c.featureEnabled("models") {
+		c.addDisabledFeatureWarning("models")
+	} else if err := c.readModels(ctx, state); err != nil {
 		if !c.isOptionalReadError(err) {
 			return nil, fmt.Errorf("read models: %w", err)
 		}
 		c.addOptionalReadWarning("models", err)
 	}
-	if err := c.readSemanticModels(ctx, state); err != nil {
+	if !c.featureEnabled("semantic") {
+		c.addDisabledFeatureWarning("semantic models")
+	} else if err := c.readSemanticModels(ctx, state); err != nil {
 		return nil, fmt.Errorf("read semantic models: %w", err)
 	}
 
@@ -233,7 +593,7 @@ type apiPrincipal struct {
 }
 
 func (c *APIStateClient) readPrincipals(ctx context.Context, state *declarative.DesiredState) error {
-	pages, err := c.fetchAllPages(ctx, "/principals")
+	pages, err := c.fetchAllPagesSorted(ctx, "/principals", "name")
 	if err != nil {
 		return err
 	}
@@ -246,19 +606,97 @@ func (c *APIStateClient) readPrincipals(ctx context.Context, state *declarative.
 		return err
 	}
 
+	attrsAvailable := true
 	for _, p := range items {
-		state.Principals = append(state.Principals, declarative.PrincipalSpec{
+		spec := declarative.PrincipalSpec{
 			Name:    p.Name,
 			Type:    p.Type,
 			IsAdmin: p.IsAdmin,
-		})
+		}
+		if p.ID != "" && attrsAvailable {
+			attrs, err := c.readPrincipalAttributes(p.ID)
+			switch {
+			case err == nil:
+				spec.Attributes = attrs
+			case c.isOptionalReadError(err):
+				// Servers without principal attributes: read the rest.
+				c.addOptionalReadWarning("principal attributes", err)
+				attrsAvailable = false
+			default:
+				return fmt.Errorf("principal %q attributes: %w", p.Name, err)
+			}
+		}
+		state.Principals = append(state.Principals, spec)
 		if p.ID != "" && c.index != nil {
-			c.index.principalIDByName[p.Name] = p.ID
+			c.index.setPrincipalID(p.Name, p.ID)
 		}
 	}
 	return nil
 }
 
+// readPrincipalAttributes returns the attributes of the principal with the
+// given ID, or nil when it has none.
+func (c *APIStateClient) readPrincipalAttributes(id string) (map[string]string, error) {
+	path := "/principals/" + id + "/attributes"
+	resp, err := c.client.Do(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", path, err)
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read GET %s: %w", path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GET %s: HTTP %d: %s", path, resp.StatusCode, string(body))
+	}
+	c.rememberETag(path, resp)
+	var parsed struct {
+		Attributes map[string]string `json:"attributes"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("parse GET %s: %w", path, err)
+	}
+	if len(parsed.Attributes) == 0 {
+		return nil, nil
+	}
+	return parsed.Attributes, nil
+}
+
+// rememberETag records the ETag of a resource read from path, so a later
+// update of it can be made conditional on the resource being unchanged.
+func (c *APIStateClient) rememberETag(path string, resp *http.Response) {
+	if c.index == nil {
+		return
+	}
+	if tag := resp.Header.Get("ETag"); tag != "" {
+		c.index.setETag(path, tag)
+	}
+}
+
+// takeETag returns the ETag read from path, or "" when none was, and
+// forgets it: the update it guards changes the resource.
+func (c *APIStateClient) takeETag(path string) string {
+	if c.index == nil {
+		return ""
+	}
+	return c.index.takeETag(path)
+}
+
+// putPrincipalAttributes replaces the attributes of the principal with the
+// given ID.
+func (c *APIStateClient) putPrincipalAttributes(id string, attrs map[string]string) error {
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	path := "/principals/" + id + "/attributes"
+	resp, err := c.client.DoIfMatch(http.MethodPut, path, nil,
+		map[string]interface{}{"attributes": attrs}, c.takeETag(path))
+	if err != nil {
+		return err
+	}
+	return gen.CheckError(resp)
+}
+
 type apiGroup struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
@@ -271,7 +709,7 @@ type apiGroupMember struct {
 }
 
 func (c *APIStateClient) readGroups(ctx context.Context, state *declarative.DesiredState) error {
-	pages, err := c.fetchAllPages(ctx, "/groups")
+	pages, err := c.fetchAllPagesSorted(ctx, "/groups", "name")
 	if err != nil {
 		return err
 	}
@@ -318,19 +756,23 @@ func (c *APIStateClient) readGroups(ctx context.Context, state *declarative.Desi
 		}
 
 		state.Groups = append(state.Groups, spec)
-		if g.ID != "" && c.index != nil {
-			c.index.groupIDByName[g.Name] = g.ID
+		if c.index != nil {
+			if g.ID != "" {
+				c.index.setGroupID(g.Name, g.ID)
+			}
+			c.index.setGroupMembers(g.Name, spec.Members)
 		}
 	}
 	return nil
 }
 
 type apiGrant struct {
-	PrincipalID   string `json:"principal_id"`
-	PrincipalType string `json:"principal_type"`
-	SecurableType string `json:"securable_type"`
-	SecurableID   string `json:"securable_id"`
-	Privilege     string `json:"privilege"`
+	PrincipalID   string  `json:"principal_id"`
+	PrincipalType string  `json:"principal_type"`
+	SecurableType string  `json:"securable_type"`
+	SecurableID   string  `json:"securable_id"`
+	Privilege     string  `json:"privilege"`
+	ExpiresAt     *string `json:"expires_at"`
 }
 
 func (c *APIStateClient) readGrants(ctx context.Context, state *declarative.DesiredState) error {
@@ -372,6 +814,7 @@ func (c *APIStateClient) readGrants(ctx context.Context, state *declarative.Desi
 			SecurableType: g.SecurableType,
 			Securable:     securablePath,
 			Privilege:     g.Privilege,
+			ExpiresAt:     g.ExpiresAt,
 		})
 	}
 
@@ -457,7 +900,7 @@ func (c *APIStateClient) readCatalogs(ctx context.Context, state *declarative.De
 		})
 
 		if cat.ID != "" && c.index != nil {
-			c.index.catalogIDByName[cat.Name] = cat.ID
+			c.index.setCatalogID(cat.Name, cat.ID)
 		}
 
 		// Fetch schemas for this catalog.
@@ -503,7 +946,7 @@ func (c *APIStateClient) readSchemas(ctx context.Context, catalogName string, st
 			},
 		})
 		if s.ID != "" && c.index != nil {
-			c.index.schemaIDByPath[catalogName+"."+s.Name] = s.ID
+			c.index.setSchemaID(catalogName+"."+s.Name, s.ID)
 		}
 
 		// Fetch tables, views, volumes for this schema.
@@ -531,6 +974,7 @@ type apiTable struct {
 	SourcePath   string            `json:"source_path"`
 	FileFormat   string            `json:"file_format"`
 	LocationName string            `json:"location_name"`
+	PartitionBy  []string          `json:"partition_by"`
 }
 
 type apiColumn struct {
@@ -577,21 +1021,24 @@ func (c *APIStateClient) readTables(ctx context.Context, catalogName, schemaName
 				SourcePath:   t.SourcePath,
 				FileFormat:   t.FileFormat,
 				LocationName: t.LocationName,
+				PartitionBy:  t.PartitionBy,
 			},
 		})
 		if t.ID != "" && c.index != nil {
-			c.index.tableIDByPath[catalogName+"."+schemaName+"."+t.Name] = t.ID
+			c.index.setTableID(catalogName+"."+schemaName+"."+t.Name, t.ID)
 		}
 	}
 	return nil
 }
 
 type apiView struct {
-	Name           string            `json:"name"`
-	ViewDefinition string            `json:"view_definition"`
-	Comment        string            `json:"comment"`
-	Owner          string            `json:"owner"`
-	Properties     map[string]string `json:"properties"`
+	Name            string            `json:"name"`
+	ViewDefinition  string            `json:"view_definition"`
+	Comment         string            `json:"comment"`
+	Owner           string            `json:"owner"`
+	Properties      map[string]string `json:"properties"`
+	Materialized    bool              `json:"materialized"`
+	RefreshSchedule string            `json:"refresh_schedule"`
 }
 
 func (c *APIStateClient) readViews(ctx context.Context, catalogName, schemaName string, state *declarative.DesiredState) error {
@@ -615,10 +1062,12 @@ func (c *APIStateClient) readViews(ctx context.Context, catalogName, schemaName
 			SchemaName:  schemaName,
 			ViewName:    v.Name,
 			Spec: declarative.ViewSpec{
-				ViewDefinition: v.ViewDefinition,
-				Comment:        v.Comment,
-				Owner:          v.Owner,
-				Properties:     v.Properties,
+				ViewDefinition:  v.ViewDefinition,
+				Comment:         v.Comment,
+				Owner:           v.Owner,
+				Properties:      v.Properties,
+				Materialized:    v.Materialized,
+				RefreshSchedule: v.RefreshSchedule,
 			},
 		})
 	}
@@ -662,7 +1111,7 @@ func (c *APIStateClient) readVolumes(ctx context.Context, catalogName, schemaNam
 			},
 		})
 		if v.ID != "" && c.index != nil {
-			c.index.volumeIDByPath[catalogName+"."+schemaName+"."+v.Name] = v.ID
+			c.index.setVolumeID(catalogName+"."+schemaName+"."+v.Name, v.ID)
 		}
 	}
 	return nil
@@ -670,10 +1119,17 @@ func (c *APIStateClient) readVolumes(ctx context.Context, catalogName, schemaNam
 
 // --- Storage resources ---
 
+// apiStorageCredential holds the fields the API returns for a storage
+// credential. Secrets are write-only and never returned.
 type apiStorageCredential struct {
 	ID             string `json:"id"`
 	Name           string `json:"name"`
 	CredentialType string `json:"credential_type"`
+	Endpoint       string `json:"endpoint"`
+	Region         string `json:"region"`
+	URLStyle       string `json:"url_style"`
+	AzureTenantID  string `json:"azure_tenant_id"`
+	GCSKeyFilePath string `json:"gcs_key_file_path"`
 	Comment        string `json:"comment"`
 }
 
@@ -692,13 +1148,24 @@ func (c *APIStateClient) readStorageCredentials(ctx context.Context, state *decl
 	}
 
 	for _, sc := range items {
-		state.StorageCredentials = append(state.StorageCredentials, declarative.StorageCredentialSpec{
+		// The environment variables holding the secrets are not known to
+		// the server; export leaves placeholders for them.
+		spec := declarative.StorageCredentialSpec{
 			Name:           sc.Name,
 			CredentialType: sc.CredentialType,
 			Comment:        sc.Comment,
-		})
+		}
+		switch sc.CredentialType {
+		case "S3":
+			spec.S3 = &declarative.S3CredentialSpec{Endpoint: sc.Endpoint, Region: sc.Region, URLStyle: sc.URLStyle}
+		case "AZURE":
+			spec.Azure = &declarative.AzureCredentialSpec{TenantID: sc.AzureTenantID}
+		case "GCS":
+			spec.GCS = &declarative.GCSCredentialSpec{KeyFilePath: sc.GCSKeyFilePath}
+		}
+		state.StorageCredentials = append(state.StorageCredentials, spec)
 		if sc.ID != "" && c.index != nil {
-			c.index.credentialIDByName[sc.Name] = sc.ID
+			c.index.setCredentialID(sc.Name, sc.ID)
 		}
 	}
 	return nil
@@ -738,7 +1205,7 @@ func (c *APIStateClient) readExternalLocations(ctx context.Context, state *decla
 			ReadOnly:       el.ReadOnly,
 		})
 		if el.ID != "" && c.index != nil {
-			c.index.locationIDByName[el.Name] = el.ID
+			c.index.setLocationID(el.Name, el.ID)
 		}
 	}
 	return nil
@@ -755,8 +1222,10 @@ type apiComputeEndpoint struct {
 }
 
 type apiComputeAssignment struct {
+	ID            string `json:"id"`
 	Endpoint      string `json:"endpoint"`
 	Principal     string `json:"principal"`
+	PrincipalID   string `json:"principal_id"`
 	PrincipalType string `json:"principal_type"`
 	IsDefault     bool   `json:"is_default"`
 	FallbackLocal bool   `json:"fallback_local"`
@@ -784,7 +1253,7 @@ func (c *APIStateClient) readComputeEndpoints(ctx context.Context, state *declar
 			Size: ep.Size,
 		})
 		if ep.ID != "" && c.index != nil {
-			c.index.computeIDByName[ep.Name] = ep.ID
+			c.index.setComputeID(ep.Name, ep.ID)
 		}
 
 		// Fetch assignments for this endpoint.
@@ -798,9 +1267,20 @@ func (c *APIStateClient) readComputeEndpoints(ctx context.Context, state *declar
 				return fmt.Errorf("endpoint %q assignments parse: %w", ep.Name, err)
 			}
 			for _, a := range assignments {
+				principalName := a.Principal
+				if principalName == "" {
+					principalName = c.reverseLookupPrincipalName(a.PrincipalID, a.PrincipalType)
+				}
+				if principalName == "" {
+					resolvedName, lookupErr := c.lookupMemberNameByID(ctx, a.PrincipalID, a.PrincipalType)
+					if lookupErr != nil {
+						continue
+					}
+					principalName = resolvedName
+				}
 				state.ComputeAssignments = append(state.ComputeAssignments, declarative.ComputeAssignmentSpec{
 					Endpoint:      ep.Name,
-					Principal:     a.Principal,
+					Principal:     principalName,
 					PrincipalType: a.PrincipalType,
 					IsDefault:     a.IsDefault,
 					FallbackLocal: a.FallbackLocal,
@@ -839,7 +1319,7 @@ func (c *APIStateClient) readTags(ctx context.Context, state *declarative.Desire
 			Value: t.Value,
 		})
 		if t.ID != "" && c.index != nil {
-			c.index.tagIDByKey[tagKey(t.Key, t.Value)] = t.ID
+			c.index.setTagID(tagKey(t.Key, t.Value), t.ID)
 		}
 	}
 	return nil
@@ -890,7 +1370,7 @@ func (c *APIStateClient) readNotebooks(ctx context.Context, state *declarative.D
 
 	for _, nb := range items {
 		if nb.ID != "" && c.index != nil {
-			c.index.notebookIDByName[nb.Name] = nb.ID
+			c.index.setNotebookID(nb.Name, nb.ID)
 		}
 
 		cells := make([]declarative.CellSpec, 0)
@@ -899,16 +1379,7 @@ func (c *APIStateClient) readNotebooks(ctx context.Context, state *declarative.D
 			if err != nil {
 				return fmt.Errorf("notebook %q detail: %w", nb.Name, err)
 			}
-			sort.Slice(detail.Cells, func(i, j int) bool {
-				return detail.Cells[i].Position < detail.Cells[j].Position
-			})
-			cells = make([]declarative.CellSpec, 0, len(detail.Cells))
-			for _, cell := range detail.Cells {
-				cells = append(cells, declarative.CellSpec{
-					Type:    cell.CellType,
-					Content: cell.Content,
-				})
-			}
+			cells = notebookCellSpecs(detail)
 		}
 
 		state.Notebooks = append(state.Notebooks, declarative.NotebookResource{
@@ -923,6 +1394,42 @@ func (c *APIStateClient) readNotebooks(ctx context.Context, state *declarative.D
 	return nil
 }
 
+// ReadNotebook reads the notebook named name with its cells in position
+// order.
+func (c *APIStateClient) ReadNotebook(ctx context.Context, name string) (declarative.NotebookResource, error) {
+	notebookID, err := c.lookupNotebookIDByName(ctx, name)
+	if err != nil {
+		return declarative.NotebookResource{}, err
+	}
+	detail, err := c.readNotebookDetail(ctx, notebookID)
+	if err != nil {
+		return declarative.NotebookResource{}, fmt.Errorf("notebook %q detail: %w", name, err)
+	}
+	return declarative.NotebookResource{
+		Name: detail.Notebook.Name,
+		Spec: declarative.NotebookSpec{
+			Description: detail.Notebook.Description,
+			Owner:       detail.Notebook.Owner,
+			Cells:       notebookCellSpecs(detail),
+		},
+	}, nil
+}
+
+// notebookCellSpecs returns the cells of detail in position order.
+func notebookCellSpecs(detail *apiNotebookDetail) []declarative.CellSpec {
+	sort.Slice(detail.Cells, func(i, j int) bool {
+		return detail.Cells[i].Position < detail.Cells[j].Position
+	})
+	cells := make([]declarative.CellSpec, 0, len(detail.Cells))
+	for _, cell := range detail.Cells {
+		cells = append(cells, declarative.CellSpec{
+			Type:    cell.CellType,
+			Content: cell.Content,
+		})
+	}
+	return cells
+}
+
 func (c *APIStateClient) readNotebookDetail(_ context.Context, notebookID string) (*apiNotebookDetail, error) {
 	resp, err := c.client.Do(http.MethodGet, "/notebooks/"+notebookID, nil, nil)
 	if err != nil {
@@ -935,6 +1442,7 @@ func (c *APIStateClient) readNotebookDetail(_ context.Context, notebookID string
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, fmt.Errorf("GET /notebooks/%s: HTTP %d: %s", notebookID, resp.StatusCode, string(body))
 	}
+	c.rememberETag("/notebooks/"+notebookID, resp)
 	var detail apiNotebookDetail
 	if err := json.Unmarshal(body, &detail); err != nil {
 		return nil, err
@@ -978,7 +1486,7 @@ func (c *APIStateClient) readPipelines(ctx context.Context, state *declarative.D
 
 	for _, pl := range items {
 		if pl.ID != "" && c.index != nil {
-			c.index.pipelineIDByName[pl.Name] = pl.ID
+			c.index.setPipelineID(pl.Name, pl.ID)
 		}
 
 		jobs, err := c.readPipelineJobs(ctx, pl.Name)
@@ -990,21 +1498,13 @@ func (c *APIStateClient) readPipelines(ctx context.Context, state *declarative.D
 		for _, job := range jobs {
 			notebookName := job.NotebookID
 			if c.index != nil {
-				for name, id := range c.index.notebookIDByName {
-					if id == job.NotebookID {
-						notebookName = name
-						break
-					}
+				if name := c.index.getNotebookNameByID(job.NotebookID); name != "" {
+					notebookName = name
 				}
 			}
 			computeEndpoint := ""
 			if job.ComputeEndpointID != "" && c.index != nil {
-				for name, id := range c.index.computeIDByName {
-					if id == job.ComputeEndpointID {
-						computeEndpoint = name
-						break
-					}
-				}
+				computeEndpoint = c.index.getComputeNameByID(job.ComputeEndpointID)
 			}
 			jobSpecs = append(jobSpecs, declarative.PipelineJobSpec{
 				Name:            job.Name,
@@ -1016,7 +1516,7 @@ func (c *APIStateClient) readPipelines(ctx context.Context, state *declarative.D
 				Order:           job.JobOrder,
 			})
 			if job.ID != "" && c.index != nil {
-				c.index.jobIDByPath[pl.Name+"/"+job.Name] = job.ID
+				c.index.setJobID(pl.Name+"/"+job.Name, job.ID)
 			}
 		}
 
@@ -1338,7 +1838,7 @@ func (c *APIStateClient) readSemanticModels(ctx context.Context, state *declarat
 			modelIndexByID[m.ID] = idx
 			modelNameByID[m.ID] = semanticModelPath(m.ProjectName, m.Name)
 			if c.index != nil {
-				c.index.semanticModelIDByPath[semanticModelPath(m.ProjectName, m.Name)] = m.ID
+				c.index.setSemanticModelID(semanticModelPath(m.ProjectName, m.Name), m.ID)
 			}
 		}
 	}
@@ -1462,25 +1962,10 @@ func (c *APIStateClient) reverseLookupPrincipalName(id, memberType string) strin
 	if c.index == nil {
 		return ""
 	}
-	source := c.index.principalIDByName
 	if memberType == "group" {
-		source = c.index.groupIDByName
-	}
-	for name, storedID := range source {
-		if storedID == id {
-			return name
-		}
+		return c.index.getGroupNameByID(id)
 	}
-	return ""
-}
-
-func reverseLookupByID(source map[string]string, id string) string {
-	for name, storedID := range source {
-		if storedID == id {
-			return name
-		}
-	}
-	return ""
+	return c.index.getPrincipalNameByID(id)
 }
 
 func (c *APIStateClient) reverseLookupSecurablePath(securableType, id string) string {
@@ -1493,17 +1978,17 @@ func (c *APIStateClient) reverseLookupSecurablePath(securableType, id string) st
 
 	switch securableType {
 	case "catalog":
-		return reverseLookupByID(c.index.catalogIDByName, id)
+		return c.index.getCatalogNameByID(id)
 	case "schema":
-		return reverseLookupByID(c.index.schemaIDByPath, id)
+		return c.index.getSchemaPathByID(id)
 	case "table":
-		return reverseLookupByID(c.index.tableIDByPath, id)
+		return c.index.getTablePathByID(id)
 	case "volume":
-		return reverseLookupByID(c.index.volumeIDByPath, id)
+		return c.index.getVolumePathByID(id)
 	case "external_location":
-		return reverseLookupByID(c.index.locationIDByName, id)
+		return c.index.getLocationNameByID(id)
 	case "storage_credential":
-		return reverseLookupByID(c.index.credentialIDByName, id)
+		return c.index.getCredentialNameByID(id)
 	default:
 		return ""
 	}
@@ -1534,6 +2019,15 @@ func (c *APIStateClient) lookupMemberNameByID(_ context.Context, id, memberType
 	if parsed.Name == "" {
 		return "", fmt.Errorf("empty name in response")
 	}
+	// Remember the name so later references to the same ID resolve from the
+	// index instead of fetching it again.
+	if c.index != nil {
+		if memberType == "group" {
+			c.index.setGroupID(parsed.Name, id)
+		} else {
+			c.index.setPrincipalID(parsed.Name, id)
+		}
+	}
 	return parsed.Name, nil
 }
 
@@ -1543,13 +2037,13 @@ func (c *APIStateClient) resolvePrincipalID(name, principalType string) (string,
 		return "", fmt.Errorf("resource index not populated; call ReadState first")
 	}
 	if principalType == "group" {
-		id, ok := c.index.groupIDByName[name]
+		id, ok := c.index.getGroupID(name)
 		if !ok {
 			return "", fmt.Errorf("group %q not found in index", name)
 		}
 		return id, nil
 	}
-	id, ok := c.index.principalIDByName[name]
+	id, ok := c.index.getPrincipalID(name)
 	if !ok {
 		return "", fmt.Errorf("principal %q not found in index", name)
 	}
@@ -1564,83 +2058,100 @@ func (c *APIStateClient) resolveSecurableID(ctx context.Context, securableType,
 	}
 	switch securableType {
 	case "catalog":
-		if id, ok := c.index.catalogIDByName[path]; ok {
+		if id, ok := c.index.getCatalogID(path); ok {
 			return id, nil
 		}
 	case "schema":
-		if id, ok := c.index.schemaIDByPath[path]; ok {
+		if id, ok := c.findSchemaID(ctx, path); ok {
 			return id, nil
 		}
-		parts := strings.SplitN(path, ".", 2)
-		if len(parts) == 2 {
-			id, err := c.lookupSchemaIDByPath(ctx, parts[0], parts[1])
-			if err == nil && id != "" {
-				c.index.schemaIDByPath[path] = id
-				return id, nil
-			}
-		}
 	case "table":
-		if id, ok := c.index.tableIDByPath[path]; ok {
+		if id, ok := c.findTableID(ctx, path); ok {
 			return id, nil
 		}
-		parts := strings.SplitN(path, ".", 3)
-		if len(parts) == 3 {
-			id, err := c.lookupTableIDByPath(ctx, parts[0], parts[1], parts[2])
-			if err == nil && id != "" {
-				c.index.tableIDByPath[path] = id
-				return id, nil
-			}
-		}
 	case "volume":
-		if id, ok := c.index.volumeIDByPath[path]; ok {
+		if id, ok := c.index.getVolumeID(path); ok {
 			return id, nil
 		}
 	case "external_location":
-		if id, ok := c.index.locationIDByName[path]; ok {
+		if id, ok := c.index.getLocationID(path); ok {
 			return id, nil
 		}
 	case "storage_credential":
-		if id, ok := c.index.credentialIDByName[path]; ok {
+		if id, ok := c.index.getCredentialID(path); ok {
 			return id, nil
 		}
 	default:
 		// For other types (volume, external_location, etc.) try all maps.
-		if id, ok := c.index.volumeIDByPath[path]; ok {
+		if id, ok := c.index.getVolumeID(path); ok {
 			return id, nil
 		}
-		if id, ok := c.index.locationIDByName[path]; ok {
+		if id, ok := c.index.getLocationID(path); ok {
 			return id, nil
 		}
-		if id, ok := c.index.credentialIDByName[path]; ok {
+		if id, ok := c.index.getCredentialID(path); ok {
 			return id, nil
 		}
-		if id, ok := c.index.tableIDByPath[path]; ok {
+		if id, ok := c.index.getCatalogID(path); ok {
 			return id, nil
 		}
-		if id, ok := c.index.schemaIDByPath[path]; ok {
+		if id, ok := c.findTableID(ctx, path); ok {
 			return id, nil
 		}
-		if id, ok := c.index.catalogIDByName[path]; ok {
+		if id, ok := c.findSchemaID(ctx, path); ok {
 			return id, nil
 		}
-		parts := strings.SplitN(path, ".", 3)
-		if len(parts) == 3 {
-			id, err := c.lookupTableIDByPath(ctx, parts[0], parts[1], parts[2])
-			if err == nil && id != "" {
-				c.index.tableIDByPath[path] = id
-				return id, nil
-			}
+	}
+	return "", fmt.Errorf("%s %q not found in index", securableType, path)
+}
+
+// findSchemaID returns the ID of the schema at "catalog.schema", fetching it
+// when the index does not have it. Both a found ID and a 404 are remembered
+// for the rest of the run, so a schema referenced many times is fetched at
+// most once.
+func (c *APIStateClient) findSchemaID(ctx context.Context, path string) (string, bool) {
+	if id, ok := c.index.getSchemaID(path); ok {
+		return id, true
+	}
+	parts := strings.SplitN(path, ".", 2)
+	if len(parts) != 2 || c.index.isMissing("schema", path) {
+		return "", false
+	}
+	id, err := c.lookupSchemaIDByPath(ctx, parts[0], parts[1])
+	if err != nil {
+		if isNotFound(err) {
+			c.index.markMissing("schema", path)
 		}
-		parts = strings.SplitN(path, ".", 2)
-		if len(parts) == 2 {
-			id, err := c.lookupSchemaIDByPath(ctx, parts[0], parts[1])
-			if err == nil && id != "" {
-				c.index.schemaIDByPath[path] = id
-				return id, nil
-			}
+		return "", false
+	}
+	c.index.setSchemaID(path, id)
+	return id, true
+}
+
+// findTableID is findSchemaID for the table at "catalog.schema.table".
+func (c *APIStateClient) findTableID(ctx context.Context, path string) (string, bool) {
+	if id, ok := c.index.getTableID(path); ok {
+		return id, true
+	}
+	parts := strings.SplitN(path, ".", 3)
+	if len(parts) != 3 || c.index.isMissing("table", path) {
+		return "", false
+	}
+	id, err := c.lookupTableIDByPath(ctx, parts[0], parts[1], parts[2])
+	if err != nil {
+		if isNotFound(err) {
+			c.index.markMissing("table", path)
 		}
+		return "", false
 	}
-	return "", fmt.Errorf("%s %q not found in index", securableType, path)
+	c.index.setTableID(path, id)
+	return id, true
+}
+
+// isNotFound reports whether err is the server answering 404.
+func isNotFound(err error) bool {
+	var apiErr *gen.APIError
+	return errors.As(err, &apiErr) && apiErr.HTTPStatus == http.StatusNotFound
 }
 
 // resolveTagID looks up a tag UUID by key or "key:value" string.
@@ -1648,7 +2159,7 @@ func (c *APIStateClient) resolveTagID(keyOrKeyValue string) (string, error) {
 	if c.index == nil {
 		return "", fmt.Errorf("resource index not populated; call ReadState first")
 	}
-	id, ok := c.index.tagIDByKey[keyOrKeyValue]
+	id, ok := c.index.getTagID(keyOrKeyValue)
 	if !ok {
 		return "", fmt.Errorf("tag %q not found in index", keyOrKeyValue)
 	}
@@ -1660,7 +2171,7 @@ func (c *APIStateClient) resolveRowFilterID(resourceName string) (string, error)
 	if c.index == nil {
 		return "", fmt.Errorf("resource index not populated; call ReadState first")
 	}
-	id, ok := c.index.rowFilterIDByPath[resourceName]
+	id, ok := c.index.getRowFilterID(resourceName)
 	if !ok {
 		return "", fmt.Errorf("row filter %q not found in index", resourceName)
 	}
@@ -1672,7 +2183,7 @@ func (c *APIStateClient) resolveColumnMaskID(resourceName string) (string, error
 	if c.index == nil {
 		return "", fmt.Errorf("resource index not populated; call ReadState first")
 	}
-	id, ok := c.index.columnMaskIDByPath[resourceName]
+	id, ok := c.index.getColumnMaskID(resourceName)
 	if !ok {
 		return "", fmt.Errorf("column mask %q not found in index", resourceName)
 	}
@@ -1686,14 +2197,7 @@ func (c *APIStateClient) checkCreateResponse(resp *http.Response) (string, error
 		return "", err
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var apiErr struct {
-			Code    interface{} `json:"code"`
-			Message string      `json:"message"`
-		}
-		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
-			return "", fmt.Errorf("API error (HTTP %d): %s", resp.StatusCode, apiErr.Message)
-		}
-		return "", fmt.Errorf("API error (HTTP %d): %s", resp.StatusCode, string(body))
+		return "", gen.NewAPIError(resp.StatusCode, body)
 	}
 	var created struct {
 		ID       string `json:"id"`
@@ -1711,22 +2215,62 @@ func (c *APIStateClient) checkCreateResponse(resp *http.Response) (string, error
 	return id, nil
 }
 
-func (c *APIStateClient) lookupSchemaIDByPath(_ context.Context, catalogName, schemaName string) (string, error) {
-	resp, err := c.client.Do(http.MethodGet, "/catalogs/"+catalogName+"/schemas/"+schemaName, nil, nil)
-	if err != nil {
-		return "", err
+// checkApprovalResponse checks the response of an operation the server may
+// hold for dual-control approval. A 202 Accepted means the change was not
+// applied but is pending a second admin's approval; it is returned as an
+// error naming the approval request so the action is not reported as done.
+func checkApprovalResponse(resp *http.Response, what string) error {
+	if resp.StatusCode != http.StatusAccepted {
+		return gen.CheckError(resp)
 	}
-	id, err := c.checkCreateResponse(resp)
+	body, err := gen.ReadBody(resp)
 	if err != nil {
-		return "", err
+		return err
 	}
-	if id == "" {
-		return "", fmt.Errorf("schema %q has no id in API response", catalogName+"."+schemaName)
+	var pending struct {
+		ID string `json:"id"`
 	}
-	return id, nil
+	_ = json.Unmarshal(body, &pending)
+	return fmt.Errorf("%s is pending approval %s by a second admin (duck admin approvals approve %s)", what, pending.ID, pending.ID)
 }
 
-func (c *APIStateClient) lookupTableIDByPath(_ context.Context, catalogName, schemaName, tableName string) (string, error) {
+// errorCodeResourceExists is the error code of a create that found the
+// resource already there.
+const errorCodeResourceExists = "RESOURCE_EXISTS"
+
+// isResourceExists reports whether err is a create refused because the
+// resource already exists, so the caller can adopt the existing one. It
+// branches on the RESOURCE_EXISTS error code; for servers that predate the
+// error catalog and send none, a 409 or an "already exists" message is taken
+// to mean the same.
+func isResourceExists(err error) bool {
+	var apiErr *gen.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.ErrorCode != "" {
+		return apiErr.ErrorCode == errorCodeResourceExists
+	}
+	return apiErr.HTTPStatus == http.StatusConflict ||
+		strings.Contains(strings.ToLower(apiErr.Message), "already exists")
+}
+
+func (c *APIStateClient) lookupSchemaIDByPath(_ context.Context, catalogName, schemaName string) (string, error) {
+	resp, err := c.client.Do(http.MethodGet, "/catalogs/"+catalogName+"/schemas/"+schemaName, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	id, err := c.checkCreateResponse(resp)
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", fmt.Errorf("schema %q has no id in API response", catalogName+"."+schemaName)
+	}
+	return id, nil
+}
+
+func (c *APIStateClient) lookupTableIDByPath(_ context.Context, catalogName, schemaName, tableName string) (string, error) {
 	resp, err := c.client.Do(http.MethodGet, "/catalogs/"+catalogName+"/schemas/"+schemaName+"/tables/"+tableName, nil, nil)
 	if err != nil {
 		return "", err
@@ -1787,6 +2331,7 @@ func (c *APIStateClient) lookupColumnMaskIDBySpec(ctx context.Context, tablePath
 
 // Execute applies a single planned action to the server via the API.
 func (c *APIStateClient) Execute(ctx context.Context, action declarative.Action) error {
+	ctx = c.beginAction(ctx, action)
 	switch action.ResourceKind {
 	case declarative.KindPrincipal:
 		return c.executePrincipal(ctx, action)
@@ -1804,6 +2349,14 @@ func (c *APIStateClient) Execute(ctx context.Context, action declarative.Action)
 		return c.executeTable(ctx, action)
 	case declarative.KindView:
 		return c.executeView(ctx, action)
+	case declarative.KindVolume:
+		return c.executeVolume(ctx, action)
+	case declarative.KindComputeAssignment:
+		return c.executeComputeAssignment(ctx, action)
+	case declarative.KindStorageCredential:
+		return c.executeStorageCredential(ctx, action)
+	case declarative.KindExternalLocation:
+		return c.executeExternalLocation(ctx, action)
 	case declarative.KindTag:
 		return c.executeTag(ctx, action)
 	case declarative.KindTagAssignment:
@@ -1850,7 +2403,7 @@ func (c *APIStateClient) resolveSemanticModelRefID(_ context.Context, defaultPro
 
 	path := semanticModelPath(projectName, modelName)
 	if c.index != nil {
-		if id, ok := c.index.semanticModelIDByPath[path]; ok {
+		if id, ok := c.index.getSemanticModelID(path); ok {
 			return id, nil
 		}
 	}
@@ -1867,7 +2420,7 @@ func (c *APIStateClient) resolveSemanticModelRefID(_ context.Context, defaultPro
 		return "", fmt.Errorf("semantic model %q has no id in API response", path)
 	}
 	if c.index != nil {
-		c.index.semanticModelIDByPath[path] = id
+		c.index.setSemanticModelID(path, id)
 	}
 	return id, nil
 }
@@ -1908,7 +2461,7 @@ func (c *APIStateClient) reconcileSemanticMetrics(ctx context.Context, projectNa
 		}
 
 		body["name"] = metric.Name
-		resp, err := c.client.Do(http.MethodPost, "/semantic-models/"+projectName+"/"+modelName+"/metrics", nil, body)
+		resp, err := c.post(ctx, "/semantic-models/"+projectName+"/"+modelName+"/metrics", body)
 		if err != nil {
 			return err
 		}
@@ -1967,7 +2520,7 @@ func (c *APIStateClient) reconcileSemanticPreAggregations(ctx context.Context, p
 		}
 
 		body["name"] = preAgg.Name
-		resp, err := c.client.Do(http.MethodPost, "/semantic-models/"+projectName+"/"+modelName+"/pre-aggregations", nil, body)
+		resp, err := c.post(ctx, "/semantic-models/"+projectName+"/"+modelName+"/pre-aggregations", body)
 		if err != nil {
 			return err
 		}
@@ -2035,7 +2588,7 @@ func (c *APIStateClient) reconcileSemanticRelationships(ctx context.Context, mod
 		body["name"] = rel.Name
 		body["from_semantic_id"] = modelID
 		body["to_semantic_id"] = toModelID
-		resp, err := c.client.Do(http.MethodPost, "/semantic-relationships", nil, body)
+		resp, err := c.post(ctx, "/semantic-relationships", body)
 		if err != nil {
 			return err
 		}
@@ -2088,7 +2641,7 @@ func (c *APIStateClient) executeSemanticModel(ctx context.Context, action declar
 			body["default_time_dimension"] = model.Spec.DefaultTimeDimension
 		}
 
-		resp, err := c.client.Do(http.MethodPost, "/semantic-models", nil, body)
+		resp, err := c.post(ctx, "/semantic-models", body)
 		if err != nil {
 			return err
 		}
@@ -2103,7 +2656,7 @@ func (c *APIStateClient) executeSemanticModel(ctx context.Context, action declar
 			}
 		}
 		if c.index != nil {
-			c.index.semanticModelIDByPath[semanticModelPath(model.ProjectName, model.ModelName)] = id
+			c.index.setSemanticModelID(semanticModelPath(model.ProjectName, model.ModelName), id)
 		}
 		return c.reconcileSemanticChildren(ctx, id, model)
 
@@ -2162,7 +2715,7 @@ func (c *APIStateClient) executeAPIKey(ctx context.Context, action declarative.A
 		if spec.ExpiresAt != nil {
 			body["expires_at"] = *spec.ExpiresAt
 		}
-		resp, err := c.client.Do(http.MethodPost, "/api-keys", nil, body)
+		resp, err := c.post(ctx, "/api-keys", body)
 		if err != nil {
 			return err
 		}
@@ -2211,18 +2764,17 @@ func (c *APIStateClient) executeNotebook(ctx context.Context, action declarative
 		if nb.Spec.Description != "" {
 			body["description"] = nb.Spec.Description
 		}
-		resp, err := c.client.Do(http.MethodPost, "/notebooks", nil, body)
+		resp, err := c.post(ctx, "/notebooks", body)
 		if err != nil {
 			return err
 		}
-		var notebookID string
-		if resp.StatusCode == http.StatusConflict {
+		notebookID, err := c.checkCreateResponse(resp)
+		if isResourceExists(err) {
 			notebookID, err = c.lookupNotebookIDByName(ctx, nb.Name)
 			if err != nil {
 				return fmt.Errorf("notebook already exists and lookup failed: %w", err)
 			}
 		} else {
-			notebookID, err = c.checkCreateResponse(resp)
 			if err != nil {
 				return err
 			}
@@ -2234,7 +2786,7 @@ func (c *APIStateClient) executeNotebook(ctx context.Context, action declarative
 			}
 		}
 		if c.index != nil {
-			c.index.notebookIDByName[nb.Name] = notebookID
+			c.index.setNotebookID(nb.Name, notebookID)
 		}
 		return c.syncNotebookCells(ctx, notebookID, nb.Spec.Cells)
 
@@ -2248,7 +2800,8 @@ func (c *APIStateClient) executeNotebook(ctx context.Context, action declarative
 			"name":        nb.Name,
 			"description": nb.Spec.Description,
 		}
-		resp, err := c.client.Do(http.MethodPatch, "/notebooks/"+notebookID, nil, body)
+		path := "/notebooks/" + notebookID
+		resp, err := c.client.DoIfMatch(http.MethodPatch, path, nil, body, c.takeETag(path))
 		if err != nil {
 			return err
 		}
@@ -2274,7 +2827,7 @@ func (c *APIStateClient) executeNotebook(ctx context.Context, action declarative
 			return err
 		}
 		if c.index != nil {
-			delete(c.index.notebookIDByName, notebookName)
+			c.index.deleteNotebookID(notebookName)
 		}
 		return nil
 
@@ -2283,7 +2836,7 @@ func (c *APIStateClient) executeNotebook(ctx context.Context, action declarative
 	}
 }
 
-func (c *APIStateClient) executePipeline(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executePipeline(ctx context.Context, action declarative.Action) error {
 	switch action.Operation {
 	case declarative.OpCreate:
 		pipeline := action.Desired.(declarative.PipelineResource)
@@ -2300,7 +2853,7 @@ func (c *APIStateClient) executePipeline(_ context.Context, action declarative.A
 		if pipeline.Spec.ConcurrencyLimit != nil {
 			body["concurrency_limit"] = *pipeline.Spec.ConcurrencyLimit
 		}
-		resp, err := c.client.Do(http.MethodPost, "/pipelines", nil, body)
+		resp, err := c.post(ctx, "/pipelines", body)
 		if err != nil {
 			return err
 		}
@@ -2309,7 +2862,7 @@ func (c *APIStateClient) executePipeline(_ context.Context, action declarative.A
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.pipelineIDByName[pipeline.Name] = id
+			c.index.setPipelineID(pipeline.Name, id)
 		}
 		return nil
 
@@ -2339,7 +2892,7 @@ func (c *APIStateClient) executePipeline(_ context.Context, action declarative.A
 			return err
 		}
 		if c.index != nil {
-			delete(c.index.pipelineIDByName, pipelineName)
+			c.index.deletePipelineID(pipelineName)
 		}
 		return nil
 
@@ -2412,7 +2965,7 @@ func (c *APIStateClient) syncNotebookCells(ctx context.Context, notebookID strin
 		if cell.Content != "" {
 			body["content"] = cell.Content
 		}
-		resp, err := c.client.Do(http.MethodPost, "/notebooks/"+notebookID+"/cells", nil, body)
+		resp, err := c.post(ctx, "/notebooks/"+notebookID+"/cells", body)
 		if err != nil {
 			return err
 		}
@@ -2454,7 +3007,7 @@ func (c *APIStateClient) createPipelineJob(ctx context.Context, pipelineName str
 		body["job_order"] = *job.Order
 	}
 
-	resp, err := c.client.Do(http.MethodPost, "/pipelines/"+pipelineName+"/jobs", nil, body)
+	resp, err := c.post(ctx, "/pipelines/"+pipelineName+"/jobs", body)
 	if err != nil {
 		return err
 	}
@@ -2463,7 +3016,7 @@ func (c *APIStateClient) createPipelineJob(ctx context.Context, pipelineName str
 		return err
 	}
 	if id != "" && c.index != nil {
-		c.index.jobIDByPath[pipelineName+"/"+job.Name] = id
+		c.index.setJobID(pipelineName+"/"+job.Name, id)
 	}
 	return nil
 }
@@ -2481,27 +3034,30 @@ func (c *APIStateClient) deletePipelineJobByName(ctx context.Context, pipelineNa
 		return err
 	}
 	if c.index != nil {
-		delete(c.index.jobIDByPath, pipelineName+"/"+jobName)
+		c.index.deleteJobID(pipelineName + "/" + jobName)
 	}
 	return nil
 }
 
 func (c *APIStateClient) resolveNotebookID(ctx context.Context, notebookName string) (string, error) {
 	if c.index != nil {
-		if id, ok := c.index.notebookIDByName[notebookName]; ok {
+		if id, ok := c.index.getNotebookID(notebookName); ok {
 			return id, nil
 		}
 	}
 	return c.lookupNotebookIDByName(ctx, notebookName)
 }
 
+// errNotebookNotFound is returned when no notebook has the looked-up name.
+var errNotebookNotFound = errors.New("not found")
+
 func (c *APIStateClient) lookupNotebookIDByName(ctx context.Context, notebookName string) (string, error) {
-	pages, err := c.fetchAllPages(ctx, "/notebooks")
+	pages, err := c.fetchAllPages(ctx, "/notebooks", "id", "name")
 	if err != nil {
 		return "", err
 	}
 	if len(pages) == 0 {
-		return "", fmt.Errorf("notebook %q not found", notebookName)
+		return "", fmt.Errorf("notebook %q %w", notebookName, errNotebookNotFound)
 	}
 	var notebooks []apiNotebook
 	if err := mergePages(pages, &notebooks); err != nil {
@@ -2513,21 +3069,21 @@ func (c *APIStateClient) lookupNotebookIDByName(ctx context.Context, notebookNam
 				return "", fmt.Errorf("notebook %q has empty id", notebookName)
 			}
 			if c.index != nil {
-				c.index.notebookIDByName[notebookName] = notebook.ID
+				c.index.setNotebookID(notebookName, notebook.ID)
 			}
 			return notebook.ID, nil
 		}
 	}
-	return "", fmt.Errorf("notebook %q not found", notebookName)
+	return "", fmt.Errorf("notebook %q %w", notebookName, errNotebookNotFound)
 }
 
 func (c *APIStateClient) resolveComputeEndpointID(ctx context.Context, endpointName string) (string, error) {
 	if c.index != nil {
-		if id, ok := c.index.computeIDByName[endpointName]; ok {
+		if id, ok := c.index.getComputeID(endpointName); ok {
 			return id, nil
 		}
 	}
-	pages, err := c.fetchAllPages(ctx, "/compute-endpoints")
+	pages, err := c.fetchAllPages(ctx, "/compute-endpoints", "id", "name")
 	if err != nil {
 		return "", err
 	}
@@ -2544,7 +3100,7 @@ func (c *APIStateClient) resolveComputeEndpointID(ctx context.Context, endpointN
 				return "", fmt.Errorf("compute endpoint %q has empty id", endpointName)
 			}
 			if c.index != nil {
-				c.index.computeIDByName[endpointName] = endpoint.ID
+				c.index.setComputeID(endpointName, endpoint.ID)
 			}
 			return endpoint.ID, nil
 		}
@@ -2555,7 +3111,7 @@ func (c *APIStateClient) resolveComputeEndpointID(ctx context.Context, endpointN
 func (c *APIStateClient) lookupPipelineJobID(ctx context.Context, pipelineName, jobName string) (string, error) {
 	jobPath := pipelineName + "/" + jobName
 	if c.index != nil {
-		if id, ok := c.index.jobIDByPath[jobPath]; ok {
+		if id, ok := c.index.getJobID(jobPath); ok {
 			return id, nil
 		}
 	}
@@ -2569,7 +3125,7 @@ func (c *APIStateClient) lookupPipelineJobID(ctx context.Context, pipelineName,
 				return "", fmt.Errorf("pipeline job %q has empty id", jobPath)
 			}
 			if c.index != nil {
-				c.index.jobIDByPath[jobPath] = job.ID
+				c.index.setJobID(jobPath, job.ID)
 			}
 			return job.ID, nil
 		}
@@ -2857,7 +3413,7 @@ func (c *APIStateClient) reconcileModelTests(ctx context.Context, projectName, m
 			}
 		}
 
-		resp, err := c.client.Do(http.MethodPost, "/models/"+projectName+"/"+modelName+"/tests", nil, toModelTestBody(wanted))
+		resp, err := c.post(ctx, "/models/"+projectName+"/"+modelName+"/tests", toModelTestBody(wanted))
 		if err != nil {
 			return err
 		}
@@ -2885,7 +3441,7 @@ func (c *APIStateClient) reconcileModelTests(ctx context.Context, projectName, m
 	return nil
 }
 
-func (c *APIStateClient) executeMacro(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executeMacro(ctx context.Context, action declarative.Action) error {
 	switch action.Operation {
 	case declarative.OpCreate:
 		macro := action.Desired.(declarative.MacroResource)
@@ -2924,7 +3480,7 @@ func (c *APIStateClient) executeMacro(_ context.Context, action declarative.Acti
 			body["status"] = macro.Spec.Status
 		}
 
-		resp, err := c.client.Do(http.MethodPost, "/macros", nil, body)
+		resp, err := c.post(ctx, "/macros", body)
 		if err != nil {
 			return err
 		}
@@ -3002,7 +3558,7 @@ func (c *APIStateClient) executeModel(ctx context.Context, action declarative.Ac
 			body["freshness_policy"] = freshness
 		}
 
-		resp, err := c.client.Do(http.MethodPost, "/models", nil, body)
+		resp, err := c.post(ctx, "/models", body)
 		if err != nil {
 			return err
 		}
@@ -3053,7 +3609,7 @@ func (c *APIStateClient) executeModel(ctx context.Context, action declarative.Ac
 
 // --- Security resource execution ---
 
-func (c *APIStateClient) executePrincipal(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executePrincipal(ctx context.Context, action declarative.Action) error {
 	switch action.Operation {
 	case declarative.OpCreate:
 		spec := action.Desired.(declarative.PrincipalSpec)
@@ -3062,7 +3618,7 @@ func (c *APIStateClient) executePrincipal(_ context.Context, action declarative.
 			"type":     spec.Type,
 			"is_admin": spec.IsAdmin,
 		}
-		resp, err := c.client.Do(http.MethodPost, "/principals", nil, body)
+		resp, err := c.post(ctx, "/principals", body)
 		if err != nil {
 			return err
 		}
@@ -3071,7 +3627,18 @@ func (c *APIStateClient) executePrincipal(_ context.Context, action declarative.
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.principalIDByName[spec.Name] = id
+			c.index.setPrincipalID(spec.Name, id)
+		}
+		if len(spec.Attributes) > 0 {
+			if id == "" {
+				id, err = c.resolvePrincipalID(spec.Name, spec.Type)
+				if err != nil {
+					return fmt.Errorf("resolve principal for attributes: %w", err)
+				}
+			}
+			if err := c.putPrincipalAttributes(id, spec.Attributes); err != nil {
+				return fmt.Errorf("set attributes: %w", err)
+			}
 		}
 		return nil
 
@@ -3081,6 +3648,14 @@ func (c *APIStateClient) executePrincipal(_ context.Context, action declarative.
 		if err != nil {
 			return fmt.Errorf("resolve principal for update: %w", err)
 		}
+		if action.HasFieldChange("attributes") {
+			if err := c.putPrincipalAttributes(id, spec.Attributes); err != nil {
+				return fmt.Errorf("set attributes: %w", err)
+			}
+			if !action.HasFieldChange("is_admin") {
+				return nil
+			}
+		}
 		body := map[string]interface{}{
 			"is_admin": spec.IsAdmin,
 		}
@@ -3088,7 +3663,7 @@ func (c *APIStateClient) executePrincipal(_ context.Context, action declarative.
 		if err != nil {
 			return err
 		}
-		return gen.CheckError(resp)
+		return checkApprovalResponse(resp, "admin change for principal "+spec.Name)
 
 	case declarative.OpDelete:
 		spec := action.Actual.(declarative.PrincipalSpec)
@@ -3107,7 +3682,7 @@ func (c *APIStateClient) executePrincipal(_ context.Context, action declarative.
 	}
 }
 
-func (c *APIStateClient) executeGroup(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executeGroup(ctx context.Context, action declarative.Action) error {
 	switch action.Operation {
 	case declarative.OpCreate:
 		spec := action.Desired.(declarative.GroupSpec)
@@ -3117,7 +3692,7 @@ func (c *APIStateClient) executeGroup(_ context.Context, action declarative.Acti
 		if spec.Description != "" {
 			body["description"] = spec.Description
 		}
-		resp, err := c.client.Do(http.MethodPost, "/groups", nil, body)
+		resp, err := c.post(ctx, "/groups", body)
 		if err != nil {
 			return err
 		}
@@ -3126,7 +3701,7 @@ func (c *APIStateClient) executeGroup(_ context.Context, action declarative.Acti
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.groupIDByName[spec.Name] = id
+			c.index.setGroupID(spec.Name, id)
 		}
 		return nil
 
@@ -3152,60 +3727,137 @@ func (c *APIStateClient) executeGroup(_ context.Context, action declarative.Acti
 func (c *APIStateClient) executeGrant(ctx context.Context, action declarative.Action) error {
 	switch action.Operation {
 	case declarative.OpCreate:
-		grant := action.Desired.(declarative.GrantSpec)
-		principalID, err := c.resolvePrincipalID(grant.Principal, grant.PrincipalType)
-		if err != nil {
-			return fmt.Errorf("resolve principal for grant: %w", err)
-		}
-		securableID, err := c.resolveSecurableID(ctx, grant.SecurableType, grant.Securable)
+		return c.createGrant(ctx, action.Desired.(declarative.GrantSpec))
+
+	case declarative.OpDelete:
+		return c.deleteGrant(ctx, action.Actual.(declarative.GrantSpec))
+
+	case declarative.OpUpdate:
+		// Only the expiry of a grant can change; set it in place so the
+		// privilege never lapses mid-apply.
+		return c.updateGrantExpiry(ctx, action.Actual.(declarative.GrantSpec), action.Desired.(declarative.GrantSpec).ExpiresAt)
+
+	default:
+		return fmt.Errorf("unsupported operation %s for grant", action.Operation)
+	}
+}
+
+func (c *APIStateClient) createGrant(ctx context.Context, grant declarative.GrantSpec) error {
+	principalID, err := c.resolvePrincipalID(grant.Principal, grant.PrincipalType)
+	if err != nil {
+		return fmt.Errorf("resolve principal for grant: %w", err)
+	}
+	securableID, err := c.resolveSecurableID(ctx, grant.SecurableType, grant.Securable)
+	if err != nil {
+		return fmt.Errorf("resolve securable for grant: %w", err)
+	}
+	body := map[string]interface{}{
+		"principal_id":   principalID,
+		"principal_type": grant.PrincipalType,
+		"securable_id":   securableID,
+		"securable_type": grant.SecurableType,
+		"privilege":      grant.Privilege,
+	}
+	if grant.ExpiresAt != nil {
+		body["expires_at"] = *grant.ExpiresAt
+	}
+	resp, err := c.post(ctx, "/grants", body)
+	if err != nil {
+		return err
+	}
+	return gen.CheckError(resp)
+}
+
+func (c *APIStateClient) deleteGrant(ctx context.Context, grant declarative.GrantSpec) error {
+	ids, err := c.findGrantIDs(ctx, grant)
+	if err != nil {
+		return fmt.Errorf("find grant for delete: %w", err)
+	}
+	for _, id := range ids {
+		resp, err := c.client.Do(http.MethodDelete, "/grants/"+url.PathEscape(id), nil, nil)
 		if err != nil {
-			return fmt.Errorf("resolve securable for grant: %w", err)
-		}
-		body := map[string]interface{}{
-			"principal_id":   principalID,
-			"principal_type": grant.PrincipalType,
-			"securable_id":   securableID,
-			"securable_type": grant.SecurableType,
-			"privilege":      grant.Privilege,
+			return err
 		}
-		resp, err := c.client.Do(http.MethodPost, "/grants", nil, body)
-		if err != nil {
+		if err := gen.CheckError(resp); err != nil {
 			return err
 		}
-		return gen.CheckError(resp)
+	}
+	return nil
+}
 
-	case declarative.OpDelete:
-		grant := action.Actual.(declarative.GrantSpec)
-		principalID, err := c.resolvePrincipalID(grant.Principal, grant.PrincipalType)
-		if err != nil {
-			return fmt.Errorf("resolve principal for grant delete: %w", err)
-		}
-		securableID, err := c.resolveSecurableID(ctx, grant.SecurableType, grant.Securable)
+// updateGrantExpiry sets the expiry of an existing grant; a nil expiresAt
+// removes it.
+func (c *APIStateClient) updateGrantExpiry(ctx context.Context, grant declarative.GrantSpec, expiresAt *string) error {
+	ids, err := c.findGrantIDs(ctx, grant)
+	if err != nil {
+		return fmt.Errorf("find grant for update: %w", err)
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("grant %s on %s %q not found", grant.Privilege, grant.SecurableType, grant.Securable)
+	}
+	var value interface{}
+	if expiresAt != nil {
+		value = *expiresAt
+	}
+	for _, id := range ids {
+		resp, err := c.client.Do(http.MethodPatch, "/grants/"+url.PathEscape(id), nil,
+			map[string]interface{}{"expires_at": value})
 		if err != nil {
-			return fmt.Errorf("resolve securable for grant delete: %w", err)
-		}
-		body := map[string]interface{}{
-			"principal_id":   principalID,
-			"principal_type": grant.PrincipalType,
-			"securable_id":   securableID,
-			"securable_type": grant.SecurableType,
-			"privilege":      grant.Privilege,
+			return err
 		}
-		resp, err := c.client.Do(http.MethodDelete, "/grants", nil, body)
-		if err != nil {
+		if err := gen.CheckError(resp); err != nil {
 			return err
 		}
-		return gen.CheckError(resp)
+	}
+	return nil
+}
 
-	default:
-		// Grants are typically immutable — recreate via delete+create.
-		return fmt.Errorf("unsupported operation %s for grant (grants are immutable, delete and recreate)", action.Operation)
+// findGrantIDs returns the IDs of the grants matching grant. Grants are
+// addressed by ID on the server, so they are found with the list filters.
+func (c *APIStateClient) findGrantIDs(ctx context.Context, grant declarative.GrantSpec) ([]string, error) {
+	principalID, err := c.resolvePrincipalID(grant.Principal, grant.PrincipalType)
+	if err != nil {
+		return nil, fmt.Errorf("resolve principal for grant: %w", err)
+	}
+	securableID, err := c.resolveSecurableID(ctx, grant.SecurableType, grant.Securable)
+	if err != nil {
+		return nil, fmt.Errorf("resolve securable for grant: %w", err)
+	}
+	q := url.Values{}
+	q.Set("principal_id", principalID)
+	q.Set("principal_type", grant.PrincipalType)
+	q.Set("securable_type", grant.SecurableType)
+	q.Set("securable_id", securableID)
+	q.Set("privilege", grant.Privilege)
+	resp, err := c.client.Do(http.MethodGet, "/grants", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return nil, err
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read grants: %w", err)
+	}
+	var page struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("parse grants: %w", err)
 	}
+	ids := make([]string, 0, len(page.Data))
+	for _, g := range page.Data {
+		ids = append(ids, g.ID)
+	}
+	return ids, nil
 }
 
 // --- Catalog resource execution ---
 
-func (c *APIStateClient) executeCatalog(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executeCatalog(ctx context.Context, action declarative.Action) error {
 	switch action.Operation {
 	case declarative.OpCreate:
 		cat := action.Desired.(declarative.CatalogResource)
@@ -3215,13 +3867,10 @@ func (c *APIStateClient) executeCatalog(_ context.Context, action declarative.Ac
 			"dsn":            cat.Spec.DSN,
 			"data_path":      cat.Spec.DataPath,
 		}
-		if cat.Spec.IsDefault {
-			body["is_default"] = true
-		}
 		if cat.Spec.Comment != "" {
 			body["comment"] = cat.Spec.Comment
 		}
-		resp, err := c.client.Do(http.MethodPost, "/catalogs", nil, body)
+		resp, err := c.post(ctx, "/catalogs", body)
 		if err != nil {
 			return err
 		}
@@ -3230,7 +3879,10 @@ func (c *APIStateClient) executeCatalog(_ context.Context, action declarative.Ac
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.catalogIDByName[cat.CatalogName] = id
+			c.index.setCatalogID(cat.CatalogName, id)
+		}
+		if cat.Spec.IsDefault {
+			return c.setDefaultCatalog(ctx, cat.CatalogName)
 		}
 		return nil
 
@@ -3240,7 +3892,6 @@ func (c *APIStateClient) executeCatalog(_ context.Context, action declarative.Ac
 			"metastore_type": cat.Spec.MetastoreType,
 			"dsn":            cat.Spec.DSN,
 			"data_path":      cat.Spec.DataPath,
-			"is_default":     cat.Spec.IsDefault,
 		}
 		if cat.Spec.Comment != "" {
 			body["comment"] = cat.Spec.Comment
@@ -3249,20 +3900,38 @@ func (c *APIStateClient) executeCatalog(_ context.Context, action declarative.Ac
 		if err != nil {
 			return err
 		}
-		return gen.CheckError(resp)
+		if err := gen.CheckError(resp); err != nil {
+			return err
+		}
+		// The default flag is owned by the set-default operation, which
+		// clears the previous default in the same transaction. Clearing a
+		// default happens implicitly when another catalog becomes default.
+		if cat.Spec.IsDefault && action.HasFieldChange("is_default") {
+			return c.setDefaultCatalog(ctx, action.ResourceName)
+		}
+		return nil
 
 	case declarative.OpDelete:
 		resp, err := c.client.Do(http.MethodDelete, "/catalogs/"+action.ResourceName, nil, nil)
 		if err != nil {
 			return err
 		}
-		return gen.CheckError(resp)
+		return checkApprovalResponse(resp, "deletion of catalog "+action.ResourceName)
 
 	default:
 		return fmt.Errorf("unsupported operation %s for catalog", action.Operation)
 	}
 }
 
+// setDefaultCatalog marks the named catalog as the platform default.
+func (c *APIStateClient) setDefaultCatalog(ctx context.Context, name string) error {
+	resp, err := c.post(ctx, "/catalogs/"+name+"/set-default", map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("set default catalog %q: %w", name, err)
+	}
+	return gen.CheckError(resp)
+}
+
 func (c *APIStateClient) executeSchema(ctx context.Context, action declarative.Action) error {
 	// ResourceName is "catalog.schema" format.
 	switch action.Operation {
@@ -3280,26 +3949,27 @@ func (c *APIStateClient) executeSchema(ctx context.Context, action declarative.A
 		if len(schema.Spec.Properties) > 0 {
 			body["properties"] = schema.Spec.Properties
 		}
-		resp, err := c.client.Do(http.MethodPost, "/catalogs/"+schema.CatalogName+"/schemas", nil, body)
+		resp, err := c.upsert(ctx, "/catalogs/"+schema.CatalogName+"/schemas", body)
 		if err != nil {
 			return err
 		}
-		if resp.StatusCode == http.StatusConflict {
+		id, err := c.checkCreateResponse(resp)
+		if isResourceExists(err) {
+			// A server without upsert ignores the parameter and answers 409.
 			id, lookupErr := c.lookupSchemaIDByPath(ctx, schema.CatalogName, schema.SchemaName)
 			if lookupErr != nil {
 				return fmt.Errorf("schema already exists and lookup failed: %w", lookupErr)
 			}
 			if c.index != nil {
-				c.index.schemaIDByPath[schema.CatalogName+"."+schema.SchemaName] = id
+				c.index.setSchemaID(schema.CatalogName+"."+schema.SchemaName, id)
 			}
 			return nil
 		}
-		id, err := c.checkCreateResponse(resp)
 		if err != nil {
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.schemaIDByPath[schema.CatalogName+"."+schema.SchemaName] = id
+			c.index.setSchemaID(schema.CatalogName+"."+schema.SchemaName, id)
 		}
 		return nil
 
@@ -3327,7 +3997,13 @@ func (c *APIStateClient) executeSchema(ctx context.Context, action declarative.A
 		if err != nil {
 			return err
 		}
-		return gen.CheckError(resp)
+		if err := gen.CheckError(resp); err != nil {
+			return err
+		}
+		if c.index != nil {
+			c.index.deleteSchemaID(action.ResourceName)
+		}
+		return nil
 
 	default:
 		return fmt.Errorf("unsupported operation %s for schema", action.Operation)
@@ -3371,27 +4047,31 @@ func (c *APIStateClient) executeTable(ctx context.Context, action declarative.Ac
 		if tbl.Spec.LocationName != "" {
 			body["location_name"] = tbl.Spec.LocationName
 		}
+		if len(tbl.Spec.PartitionBy) > 0 {
+			body["partition_by"] = tbl.Spec.PartitionBy
+		}
 		basePath := "/catalogs/" + tbl.CatalogName + "/schemas/" + tbl.SchemaName + "/tables"
-		resp, err := c.client.Do(http.MethodPost, basePath, nil, body)
+		resp, err := c.upsert(ctx, basePath, body)
 		if err != nil {
 			return err
 		}
-		if resp.StatusCode == http.StatusConflict {
+		id, err := c.checkCreateResponse(resp)
+		if isResourceExists(err) {
+			// A server without upsert ignores the parameter and answers 409.
 			id, lookupErr := c.lookupTableIDByPath(ctx, tbl.CatalogName, tbl.SchemaName, tbl.TableName)
 			if lookupErr != nil {
 				return fmt.Errorf("table already exists and lookup failed: %w", lookupErr)
 			}
 			if c.index != nil {
-				c.index.tableIDByPath[tbl.CatalogName+"."+tbl.SchemaName+"."+tbl.TableName] = id
+				c.index.setTableID(tbl.CatalogName+"."+tbl.SchemaName+"."+tbl.TableName, id)
 			}
 			return nil
 		}
-		id, err := c.checkCreateResponse(resp)
 		if err != nil {
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.tableIDByPath[tbl.CatalogName+"."+tbl.SchemaName+"."+tbl.TableName] = id
+			c.index.setTableID(tbl.CatalogName+"."+tbl.SchemaName+"."+tbl.TableName, id)
 		}
 		return nil
 
@@ -3418,14 +4098,20 @@ func (c *APIStateClient) executeTable(ctx context.Context, action declarative.Ac
 		if err != nil {
 			return err
 		}
-		return gen.CheckError(resp)
+		if err := gen.CheckError(resp); err != nil {
+			return err
+		}
+		if c.index != nil {
+			c.index.deleteTableID(action.ResourceName)
+		}
+		return nil
 
 	default:
 		return fmt.Errorf("unsupported operation %s for table", action.Operation)
 	}
 }
 
-func (c *APIStateClient) executeView(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executeView(ctx context.Context, action declarative.Action) error {
 	// ResourceName is "catalog.schema.view" format.
 	switch action.Operation {
 	case declarative.OpCreate:
@@ -3440,8 +4126,14 @@ func (c *APIStateClient) executeView(_ context.Context, action declarative.Actio
 		if len(vw.Spec.Properties) > 0 {
 			body["properties"] = vw.Spec.Properties
 		}
+		if vw.Spec.Materialized {
+			body["materialized"] = true
+		}
+		if vw.Spec.RefreshSchedule != "" {
+			body["refresh_schedule"] = vw.Spec.RefreshSchedule
+		}
 		basePath := "/catalogs/" + vw.CatalogName + "/schemas/" + vw.SchemaName + "/views"
-		resp, err := c.client.Do(http.MethodPost, basePath, nil, body)
+		resp, err := c.post(ctx, basePath, body)
 		if err != nil {
 			return err
 		}
@@ -3483,9 +4175,367 @@ func (c *APIStateClient) executeView(_ context.Context, action declarative.Actio
 	}
 }
 
+func (c *APIStateClient) executeVolume(ctx context.Context, action declarative.Action) error {
+	// ResourceName is "catalog.schema.volume" format.
+	switch action.Operation {
+	case declarative.OpCreate:
+		vol := action.Desired.(declarative.VolumeResource)
+		volumeType := vol.Spec.VolumeType
+		if volumeType == "" {
+			volumeType = "MANAGED"
+		}
+		body := map[string]interface{}{
+			"name":        vol.VolumeName,
+			"volume_type": volumeType,
+		}
+		if vol.Spec.StorageLocation != "" {
+			body["storage_location"] = vol.Spec.StorageLocation
+		}
+		if vol.Spec.Comment != "" {
+			body["comment"] = vol.Spec.Comment
+		}
+		basePath := "/catalogs/" + vol.CatalogName + "/schemas/" + vol.SchemaName + "/volumes"
+		resp, err := c.post(ctx, basePath, body)
+		if err != nil {
+			return err
+		}
+		id, err := c.checkCreateResponse(resp)
+		if err != nil {
+			return err
+		}
+		if id != "" && c.index != nil {
+			c.index.setVolumeID(vol.CatalogName+"."+vol.SchemaName+"."+vol.VolumeName, id)
+		}
+		// The create request takes no owner; set it as a follow-up update.
+		if vol.Spec.Owner == "" {
+			return nil
+		}
+		resp, err = c.client.Do(http.MethodPatch, basePath+"/"+vol.VolumeName, nil, map[string]interface{}{"owner": vol.Spec.Owner})
+		if err != nil {
+			return err
+		}
+		return gen.CheckError(resp)
+
+	case declarative.OpUpdate:
+		vol := action.Desired.(declarative.VolumeResource)
+		// The server stores a volume's type and location at creation only.
+		for _, ch := range action.Changes {
+			if ch.Field == "volume_type" || ch.Field == "storage_location" {
+				return fmt.Errorf("volume %s: %s cannot be changed in place; delete and recreate the volume", action.ResourceName, ch.Field)
+			}
+		}
+		body := map[string]interface{}{}
+		if vol.Spec.Comment != "" {
+			body["comment"] = vol.Spec.Comment
+		}
+		if vol.Spec.Owner != "" {
+			body["owner"] = vol.Spec.Owner
+		}
+		basePath := "/catalogs/" + vol.CatalogName + "/schemas/" + vol.SchemaName + "/volumes/" + vol.VolumeName
+		resp, err := c.client.Do(http.MethodPatch, basePath, nil, body)
+		if err != nil {
+			return err
+		}
+		return gen.CheckError(resp)
+
+	case declarative.OpDelete:
+		parts := strings.SplitN(action.ResourceName, ".", 3)
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid volume resource name: %s", action.ResourceName)
+		}
+		basePath := "/catalogs/" + parts[0] + "/schemas/" + parts[1] + "/volumes/" + parts[2]
+		resp, err := c.client.Do(http.MethodDelete, basePath, nil, nil)
+		if err != nil {
+			return err
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return err
+		}
+		if c.index != nil {
+			c.index.deleteVolumeID(action.ResourceName)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported operation %s for volume", action.Operation)
+	}
+}
+
+func (c *APIStateClient) executeStorageCredential(ctx context.Context, action declarative.Action) error {
+	switch action.Operation {
+	case declarative.OpCreate:
+		cred := action.Desired.(declarative.StorageCredentialSpec)
+		body, err := storageCredentialBody(cred)
+		if err != nil {
+			return err
+		}
+		body["name"] = cred.Name
+		body["credential_type"] = cred.CredentialType
+		resp, err := c.post(ctx, "/storage-credentials", body)
+		if err != nil {
+			return err
+		}
+		id, err := c.checkCreateResponse(resp)
+		if err != nil {
+			return err
+		}
+		if id != "" && c.index != nil {
+			c.index.setCredentialID(cred.Name, id)
+		}
+		return nil
+
+	case declarative.OpUpdate:
+		if action.HasFieldChange("credential_type") {
+			return fmt.Errorf("storage credential %s: credential_type cannot be changed in place; delete and recreate the credential", action.ResourceName)
+		}
+		// Every update re-sends the secrets, which also rotates them.
+		cred := action.Desired.(declarative.StorageCredentialSpec)
+		body, err := storageCredentialBody(cred)
+		if err != nil {
+			return err
+		}
+		resp, err := c.client.Do(http.MethodPatch, "/storage-credentials/"+cred.Name, nil, body)
+		if err != nil {
+			return err
+		}
+		return gen.CheckError(resp)
+
+	case declarative.OpDelete:
+		resp, err := c.client.Do(http.MethodDelete, "/storage-credentials/"+action.ResourceName, nil, nil)
+		if err != nil {
+			return err
+		}
+		return gen.CheckError(resp)
+
+	default:
+		return fmt.Errorf("unsupported operation %s for storage credential", action.Operation)
+	}
+}
+
+// storageCredentialBody builds the create or update body of a storage
+// credential without name and type. Secrets are read from the environment
+// variables the spec names, at apply time, so they never appear in the
+// configuration files.
+func storageCredentialBody(cred declarative.StorageCredentialSpec) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	if cred.Comment != "" {
+		body["comment"] = cred.Comment
+	}
+	fromEnv := func(field, envVar string) error {
+		if envVar == "" {
+			return nil
+		}
+		v, ok := os.LookupEnv(envVar)
+		if !ok || v == "" {
+			return fmt.Errorf("storage credential %s: environment variable %s for %s is not set", cred.Name, envVar, field)
+		}
+		body[field] = v
+		return nil
+	}
+	setIf := func(field, v string) {
+		if v != "" {
+			body[field] = v
+		}
+	}
+	var err error
+	switch {
+	case cred.S3 != nil:
+		err = errors.Join(
+			fromEnv("key_id", cred.S3.KeyIDFromEnv),
+			fromEnv("secret", cred.S3.SecretFromEnv),
+		)
+		setIf("endpoint", cred.S3.Endpoint)
+		setIf("region", cred.S3.Region)
+		setIf("url_style", cred.S3.URLStyle)
+	case cred.Azure != nil:
+		err = errors.Join(
+			fromEnv("azure_account_name", cred.Azure.AccountNameFromEnv),
+			fromEnv("azure_account_key", cred.Azure.AccountKeyFromEnv),
+			fromEnv("azure_client_id", cred.Azure.ClientIDFromEnv),
+			fromEnv("azure_client_secret", cred.Azure.ClientSecretFromEnv),
+		)
+		setIf("azure_tenant_id", cred.Azure.TenantID)
+	case cred.GCS != nil:
+		setIf("gcs_key_file_path", cred.GCS.KeyFilePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// executeExternalLocation manages an external location. The server looks up
+// the location's storage credential and registers it as a DuckDB secret, so
+// the client only checks that the credential exists.
+func (c *APIStateClient) executeExternalLocation(ctx context.Context, action declarative.Action) error {
+	switch action.Operation {
+	case declarative.OpCreate:
+		loc := action.Desired.(declarative.ExternalLocationSpec)
+		if _, err := c.resolveStorageCredentialID(ctx, loc.CredentialName); err != nil {
+			return fmt.Errorf("external location %s: %w", loc.Name, err)
+		}
+		body := map[string]interface{}{
+			"name":            loc.Name,
+			"url":             loc.URL,
+			"credential_name": loc.CredentialName,
+			"read_only":       loc.ReadOnly,
+		}
+		if loc.StorageType != "" {
+			body["storage_type"] = loc.StorageType
+		}
+		if loc.Comment != "" {
+			body["comment"] = loc.Comment
+		}
+		resp, err := c.post(ctx, "/external-locations", body)
+		if err != nil {
+			return err
+		}
+		id, err := c.checkCreateResponse(resp)
+		if err != nil {
+			return err
+		}
+		if id != "" && c.index != nil {
+			c.index.setLocationID(loc.Name, id)
+		}
+		return nil
+
+	case declarative.OpUpdate:
+		if action.HasFieldChange("storage_type") {
+			return fmt.Errorf("external location %s: storage_type cannot be changed in place; delete and recreate the location", action.ResourceName)
+		}
+		loc := action.Desired.(declarative.ExternalLocationSpec)
+		body := map[string]interface{}{
+			"read_only": loc.ReadOnly,
+		}
+		if action.HasFieldChange("url") {
+			body["url"] = loc.URL
+		}
+		if action.HasFieldChange("credential_name") {
+			if _, err := c.resolveStorageCredentialID(ctx, loc.CredentialName); err != nil {
+				return fmt.Errorf("external location %s: %w", loc.Name, err)
+			}
+			body["credential_name"] = loc.CredentialName
+		}
+		if loc.Comment != "" {
+			body["comment"] = loc.Comment
+		}
+		resp, err := c.client.Do(http.MethodPatch, "/external-locations/"+loc.Name, nil, body)
+		if err != nil {
+			return err
+		}
+		return gen.CheckError(resp)
+
+	case declarative.OpDelete:
+		resp, err := c.client.Do(http.MethodDelete, "/external-locations/"+action.ResourceName, nil, nil)
+		if err != nil {
+			return err
+		}
+		return gen.CheckError(resp)
+
+	default:
+		return fmt.Errorf("unsupported operation %s for external location", action.Operation)
+	}
+}
+
+// resolveStorageCredentialID looks up a storage credential's UUID by name,
+// fetching the credential when it is not in the index.
+func (c *APIStateClient) resolveStorageCredentialID(_ context.Context, name string) (string, error) {
+	if c.index != nil {
+		if id, ok := c.index.getCredentialID(name); ok {
+			return id, nil
+		}
+	}
+	resp, err := c.client.Do(http.MethodGet, "/storage-credentials/"+name, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	id, err := c.checkCreateResponse(resp)
+	if err != nil {
+		if isNotFound(err) {
+			return "", fmt.Errorf("storage credential %q not found", name)
+		}
+		return "", err
+	}
+	if id == "" {
+		return "", fmt.Errorf("storage credential %q has no id in API response", name)
+	}
+	if c.index != nil {
+		c.index.setCredentialID(name, id)
+	}
+	return id, nil
+}
+
+func (c *APIStateClient) executeComputeAssignment(ctx context.Context, action declarative.Action) error {
+	switch action.Operation {
+	case declarative.OpCreate:
+		return c.createComputeAssignment(ctx, action.Desired.(declarative.ComputeAssignmentSpec))
+
+	case declarative.OpDelete:
+		return c.deleteComputeAssignment(ctx, action.Actual.(declarative.ComputeAssignmentSpec))
+
+	case declarative.OpUpdate:
+		// Assignments cannot be modified on the server, so a change to
+		// is_default or fallback_local replaces the assignment.
+		if err := c.deleteComputeAssignment(ctx, action.Actual.(declarative.ComputeAssignmentSpec)); err != nil {
+			return err
+		}
+		return c.createComputeAssignment(ctx, action.Desired.(declarative.ComputeAssignmentSpec))
+
+	default:
+		return fmt.Errorf("unsupported operation %s for compute assignment", action.Operation)
+	}
+}
+
+func (c *APIStateClient) createComputeAssignment(ctx context.Context, a declarative.ComputeAssignmentSpec) error {
+	principalID, err := c.resolvePrincipalID(a.Principal, a.PrincipalType)
+	if err != nil {
+		return fmt.Errorf("resolve principal for compute assignment: %w", err)
+	}
+	// Both flags are always sent: the API treats a missing is_default as true.
+	body := map[string]interface{}{
+		"principal_id":   principalID,
+		"principal_type": a.PrincipalType,
+		"is_default":     a.IsDefault,
+		"fallback_local": a.FallbackLocal,
+	}
+	resp, err := c.post(ctx, "/compute-endpoints/"+a.Endpoint+"/assignments", body)
+	if err != nil {
+		return err
+	}
+	return gen.CheckError(resp)
+}
+
+func (c *APIStateClient) deleteComputeAssignment(ctx context.Context, a declarative.ComputeAssignmentSpec) error {
+	principalID, err := c.resolvePrincipalID(a.Principal, a.PrincipalType)
+	if err != nil {
+		return fmt.Errorf("resolve principal for compute assignment delete: %w", err)
+	}
+	// Assignments are deleted by ID; find it among the endpoint's assignments.
+	basePath := "/compute-endpoints/" + a.Endpoint + "/assignments"
+	pages, err := c.fetchAllPages(ctx, basePath)
+	if err != nil {
+		return err
+	}
+	var assignments []apiComputeAssignment
+	if err := mergePages(pages, &assignments); err != nil {
+		return err
+	}
+	for _, existing := range assignments {
+		if existing.PrincipalID != principalID || existing.PrincipalType != a.PrincipalType {
+			continue
+		}
+		resp, err := c.client.Do(http.MethodDelete, basePath+"/"+existing.ID, nil, nil)
+		if err != nil {
+			return err
+		}
+		return gen.CheckError(resp)
+	}
+	return fmt.Errorf("compute assignment of endpoint %q to %s %q not found", a.Endpoint, a.PrincipalType, a.Principal)
+}
+
 // --- Group membership execution ---
 
-func (c *APIStateClient) executeGroupMembership(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executeGroupMembership(ctx context.Context, action declarative.Action) error {
 	// ResourceName format: "groupName/memberName(memberType)"
 	slashIdx := strings.Index(action.ResourceName, "/")
 	if slashIdx < 0 {
@@ -3497,6 +4547,10 @@ func (c *APIStateClient) executeGroupMembership(_ context.Context, action declar
 		return fmt.Errorf("resolve group for membership: %w", err)
 	}
 
+	if synced, err := c.syncGroupMemberBatch(groupName, groupID); synced || err != nil {
+		return err
+	}
+
 	switch action.Operation {
 	case declarative.OpCreate:
 		member := action.Desired.(declarative.MemberRef)
@@ -3508,7 +4562,7 @@ func (c *APIStateClient) executeGroupMembership(_ context.Context, action declar
 			"member_id":   memberID,
 			"member_type": member.Type,
 		}
-		resp, err := c.client.Do(http.MethodPost, "/groups/"+groupID+"/members", nil, body)
+		resp, err := c.post(ctx, "/groups/"+groupID+"/members", body)
 		if err != nil {
 			return err
 		}
@@ -3543,9 +4597,134 @@ func (c *APIStateClient) executeGroupMembership(_ context.Context, action declar
 	}
 }
 
+// groupMemberSync is the desired membership of one group, set with a single
+// PUT when the first of its membership actions executes.
+type groupMemberSync struct {
+	members []declarative.MemberRef
+	done    bool
+}
+
+// PrepareGroupMemberSync batches the group-membership actions of a plan per
+// group, so each group's membership is set with one bulk call instead of one
+// call per member. Groups the plan deletes keep per-member actions. It must be
+// called after ReadState. Servers whose advertised capabilities omit the bulk
+// endpoint get per-member calls without a failed bulk attempt.
+func (c *APIStateClient) PrepareGroupMemberSync(actions []declarative.Action) {
+	if c.capabilities != nil && !c.capabilities.SupportsBatch(batchOpGroupMembersSync) {
+		c.memberSyncUnsupported = true
+		c.memberSyncs = nil
+		return
+	}
+	deletedGroups := make(map[string]bool)
+	for _, a := range actions {
+		if a.ResourceKind == declarative.KindGroup && a.Operation == declarative.OpDelete {
+			deletedGroups[a.ResourceName] = true
+		}
+	}
+
+	syncs := make(map[string]*groupMemberSync)
+	for _, a := range actions {
+		if a.ResourceKind != declarative.KindGroupMembership {
+			continue
+		}
+		groupName, _, ok := strings.Cut(a.ResourceName, "/")
+		if !ok || deletedGroups[groupName] {
+			continue
+		}
+		batch, ok := syncs[groupName]
+		if !ok {
+			batch = &groupMemberSync{}
+			if c.index != nil {
+				batch.members = append(batch.members, c.index.getGroupMembers(groupName)...)
+			}
+			syncs[groupName] = batch
+		}
+		switch a.Operation {
+		case declarative.OpCreate:
+			batch.members = append(batch.members, a.Desired.(declarative.MemberRef))
+		case declarative.OpDelete:
+			removed := a.Actual.(declarative.MemberRef)
+			kept := batch.members[:0]
+			for _, m := range batch.members {
+				if !sameMember(m, removed) {
+					kept = append(kept, m)
+				}
+			}
+			batch.members = kept
+		}
+	}
+	c.memberSyncs = syncs
+}
+
+// syncGroupMemberBatch sets the membership of the group from its batch, once
+// per group, and reports whether it did or an earlier action already had.
+// It reports false when the group has no batch or the server lacks the bulk
+// endpoint, leaving the action to run one member at a time. The lock is held
+// across the call so concurrent actions of the same group wait for it.
+func (c *APIStateClient) syncGroupMemberBatch(groupName, groupID string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	batch, ok := c.memberSyncs[groupName]
+	if !ok || c.memberSyncUnsupported {
+		return false, nil
+	}
+	if batch.done {
+		return true, nil
+	}
+	err := c.syncGroupMembers(groupID, batch.members)
+	if err == nil {
+		batch.done = true
+		return true, nil
+	}
+	status, ok := httpStatusFromError(err)
+	if !ok || (status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented) {
+		return false, err
+	}
+	// Older servers lack the bulk endpoint; fall back to per-member calls.
+	c.memberSyncUnsupported = true
+	return false, nil
+}
+
+// sameMember reports whether two member references denote the same member,
+// preferring the member ID when both carry one.
+func sameMember(a, b declarative.MemberRef) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	if a.MemberID != "" && b.MemberID != "" {
+		return a.MemberID == b.MemberID
+	}
+	return a.Name != "" && a.Name == b.Name
+}
+
+// syncGroupMembers sets the exact membership of a group via
+// PUT /groups/{id}/members.
+func (c *APIStateClient) syncGroupMembers(groupID string, members []declarative.MemberRef) error {
+	body := make([]map[string]interface{}, 0, len(members))
+	for _, m := range members {
+		memberID := m.MemberID
+		if memberID == "" {
+			resolved, err := c.resolvePrincipalID(m.Name, m.Type)
+			if err != nil {
+				return fmt.Errorf("resolve member for group membership: %w", err)
+			}
+			memberID = resolved
+		}
+		body = append(body, map[string]interface{}{
+			"member_id":   memberID,
+			"member_type": m.Type,
+		})
+	}
+	resp, err := c.client.Do(http.MethodPut, "/groups/"+groupID+"/members", nil, map[string]interface{}{"members": body})
+	if err != nil {
+		return err
+	}
+	return gen.CheckError(resp)
+}
+
 // --- Tag execution ---
 
-func (c *APIStateClient) executeTag(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executeTag(ctx context.Context, action declarative.Action) error {
 	switch action.Operation {
 	case declarative.OpCreate:
 		tag := action.Desired.(declarative.TagSpec)
@@ -3555,7 +4734,7 @@ func (c *APIStateClient) executeTag(_ context.Context, action declarative.Action
 		if tag.Value != nil {
 			body["value"] = *tag.Value
 		}
-		resp, err := c.client.Do(http.MethodPost, "/tags", nil, body)
+		resp, err := c.post(ctx, "/tags", body)
 		if err != nil {
 			return err
 		}
@@ -3564,7 +4743,7 @@ func (c *APIStateClient) executeTag(_ context.Context, action declarative.Action
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.tagIDByKey[tagKey(tag.Key, tag.Value)] = id
+			c.index.setTagID(tagKey(tag.Key, tag.Value), id)
 		}
 		return nil
 
@@ -3605,7 +4784,7 @@ func (c *APIStateClient) executeTagAssignment(ctx context.Context, action declar
 		if assignment.ColumnName != "" {
 			body["column_name"] = assignment.ColumnName
 		}
-		resp, err := c.client.Do(http.MethodPost, "/tags/"+tagID+"/assignments", nil, body)
+		resp, err := c.post(ctx, "/tags/"+tagID+"/assignments", body)
 		if err != nil {
 			return err
 		}
@@ -3661,7 +4840,7 @@ func (c *APIStateClient) executeRowFilter(ctx context.Context, action declarativ
 		if filter.Description != "" {
 			body["description"] = filter.Description
 		}
-		resp, err := c.client.Do(http.MethodPost, "/tables/"+tableID+"/row-filters", nil, body)
+		resp, err := c.post(ctx, "/tables/"+tableID+"/row-filters", body)
 		if err != nil {
 			return err
 		}
@@ -3670,7 +4849,7 @@ func (c *APIStateClient) executeRowFilter(ctx context.Context, action declarativ
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.rowFilterIDByPath[action.ResourceName] = id
+			c.index.setRowFilterID(action.ResourceName, id)
 		}
 		return nil
 
@@ -3692,7 +4871,7 @@ func (c *APIStateClient) executeRowFilter(ctx context.Context, action declarativ
 
 // --- Row filter binding execution ---
 
-func (c *APIStateClient) executeRowFilterBinding(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executeRowFilterBinding(ctx context.Context, action declarative.Action) error {
 	// ResourceName format: "catalog.schema.table/filterName->principalType:principalName"
 	parts := strings.SplitN(action.ResourceName, "->", 2)
 	filterPath := parts[0]
@@ -3712,7 +4891,7 @@ func (c *APIStateClient) executeRowFilterBinding(_ context.Context, action decla
 			"principal_id":   principalID,
 			"principal_type": binding.PrincipalType,
 		}
-		resp, err := c.client.Do(http.MethodPost, "/row-filters/"+filterID+"/bindings", nil, body)
+		resp, err := c.post(ctx, "/row-filters/"+filterID+"/bindings", body)
 		if err != nil {
 			return err
 		}
@@ -3758,26 +4937,26 @@ func (c *APIStateClient) executeColumnMask(ctx context.Context, action declarati
 		if mask.Description != "" {
 			body["description"] = mask.Description
 		}
-		resp, err := c.client.Do(http.MethodPost, "/tables/"+tableID+"/column-masks", nil, body)
+		resp, err := c.post(ctx, "/tables/"+tableID+"/column-masks", body)
 		if err != nil {
 			return err
 		}
 		id, err := c.checkCreateResponse(resp)
 		if err != nil {
-			if strings.Contains(strings.ToLower(err.Error()), "resource already exists") {
+			if isResourceExists(err) {
 				id, lookupErr := c.lookupColumnMaskIDBySpec(ctx, tablePath, mask)
 				if lookupErr != nil {
 					return fmt.Errorf("column mask already exists and lookup failed: %w", lookupErr)
 				}
 				if c.index != nil {
-					c.index.columnMaskIDByPath[action.ResourceName] = id
+					c.index.setColumnMaskID(action.ResourceName, id)
 				}
 				return nil
 			}
 			return err
 		}
 		if id != "" && c.index != nil {
-			c.index.columnMaskIDByPath[action.ResourceName] = id
+			c.index.setColumnMaskID(action.ResourceName, id)
 		}
 		return nil
 
@@ -3799,7 +4978,7 @@ func (c *APIStateClient) executeColumnMask(ctx context.Context, action declarati
 
 // --- Column mask binding execution ---
 
-func (c *APIStateClient) executeColumnMaskBinding(_ context.Context, action declarative.Action) error {
+func (c *APIStateClient) executeColumnMaskBinding(ctx context.Context, action declarative.Action) error {
 	// ResourceName format: "catalog.schema.table/maskName->principalType:principalName"
 	parts := strings.SplitN(action.ResourceName, "->", 2)
 	maskPath := parts[0]
@@ -3820,7 +4999,7 @@ func (c *APIStateClient) executeColumnMaskBinding(_ context.Context, action decl
 			"principal_type": binding.PrincipalType,
 			"see_original":   binding.SeeOriginal,
 		}
-		resp, err := c.client.Do(http.MethodPost, "/column-masks/"+maskID+"/bindings", nil, body)
+		resp, err := c.post(ctx, "/column-masks/"+maskID+"/bindings", body)
 		if err != nil {
 			return err
 		}
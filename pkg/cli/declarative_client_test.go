@@ -3,18 +3,26 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"duck-demo/internal/declarative"
+	"duck-demo/internal/middleware"
 	"duck-demo/pkg/cli/gen"
 )
 
@@ -95,6 +103,42 @@ func queryStr(ec execCapture, key string) string {
 	return ""
 }
 
+// === Resource index ===
+
+func TestResourceIndex_ConcurrentAccess(t *testing.T) {
+	ix := newResourceIndex()
+	const workers, perWorker = 8, 100
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				path := fmt.Sprintf("demo.s%d.t%d", w, i)
+				id := fmt.Sprintf("table-%d-%d", w, i)
+				ix.setTableID(path, id)
+				got, ok := ix.getTableID(path)
+				assert.True(t, ok)
+				assert.Equal(t, id, got)
+				assert.Equal(t, path, ix.getTablePathByID(id))
+
+				ix.setETag("/tables/"+id, `"v1"`)
+				assert.Equal(t, `"v1"`, ix.takeETag("/tables/"+id))
+
+				group := fmt.Sprintf("g%d", w)
+				ix.setGroupMembers(group, []declarative.MemberRef{{Name: path, Type: "user"}})
+				assert.Len(t, ix.getGroupMembers(group), 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, ix.tableIDByPath, workers*perWorker)
+	assert.Empty(t, ix.etagByPath)
+	assert.Len(t, ix.groupMembersByName, workers)
+}
+
 // === Catalog execution tests (#129) ===
 
 func TestExecuteCatalog_CreateSendsName(t *testing.T) {
@@ -119,7 +163,7 @@ func TestExecuteCatalog_CreateSendsName(t *testing.T) {
 
 	err := sc.Execute(context.Background(), action)
 	require.NoError(t, err)
-	require.Len(t, captured, 1)
+	require.Len(t, captured, 2)
 
 	req := captured[0]
 	assert.Equal(t, http.MethodPost, req.Method)
@@ -128,8 +172,12 @@ func TestExecuteCatalog_CreateSendsName(t *testing.T) {
 	assert.Equal(t, "sqlite", bodyStr(req, "metastore_type"))
 	assert.Equal(t, "/tmp/meta.sqlite", bodyStr(req, "dsn"))
 	assert.Equal(t, "/tmp/data/", bodyStr(req, "data_path"))
-	assert.True(t, bodyBool(req, "is_default"))
+	assert.NotContains(t, req.Body, "is_default")
 	assert.Equal(t, "test catalog", bodyStr(req, "comment"))
+
+	setDefault := captured[1]
+	assert.Equal(t, http.MethodPost, setDefault.Method)
+	assert.Equal(t, "/v1/catalogs/demo/set-default", setDefault.Path)
 }
 
 func TestExecuteCatalog_Update(t *testing.T) {
@@ -160,6 +208,62 @@ func TestExecuteCatalog_Update(t *testing.T) {
 	assert.Equal(t, "sqlite", bodyStr(req, "metastore_type"))
 }
 
+func TestExecuteCatalog_UpdateRoutesDefaultThroughSetDefault(t *testing.T) {
+	tests := []struct {
+		name           string
+		isDefault      bool
+		changes        []declarative.FieldDiff
+		wantSetDefault bool
+	}{
+		{
+			name:           "becoming default calls set-default",
+			isDefault:      true,
+			changes:        []declarative.FieldDiff{{Field: "is_default", OldValue: "false", NewValue: "true"}},
+			wantSetDefault: true,
+		},
+		{
+			name:      "unchanged default only patches",
+			isDefault: true,
+			changes:   []declarative.FieldDiff{{Field: "comment", OldValue: "a", NewValue: "b"}},
+		},
+		{
+			name:      "dropping default only patches",
+			isDefault: false,
+			changes:   []declarative.FieldDiff{{Field: "is_default", OldValue: "true", NewValue: "false"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captured []execCapture
+			sc := newTestExecuteClient(t, &captured)
+
+			err := sc.Execute(context.Background(), declarative.Action{
+				Operation:    declarative.OpUpdate,
+				ResourceKind: declarative.KindCatalogRegistration,
+				ResourceName: "demo",
+				Desired: declarative.CatalogResource{
+					CatalogName: "demo",
+					Spec:        declarative.CatalogSpec{MetastoreType: "sqlite", IsDefault: tt.isDefault},
+				},
+				Changes: tt.changes,
+			})
+			require.NoError(t, err)
+
+			require.NotEmpty(t, captured)
+			assert.Equal(t, http.MethodPatch, captured[0].Method)
+			assert.NotContains(t, captured[0].Body, "is_default")
+			if tt.wantSetDefault {
+				require.Len(t, captured, 2)
+				assert.Equal(t, http.MethodPost, captured[1].Method)
+				assert.Equal(t, "/v1/catalogs/demo/set-default", captured[1].Path)
+			} else {
+				assert.Len(t, captured, 1)
+			}
+		})
+	}
+}
+
 // === Grant execution tests (#130) ===
 
 func TestExecuteGrant_ResolvesNamesToIDs(t *testing.T) {
@@ -195,7 +299,19 @@ func TestExecuteGrant_ResolvesNamesToIDs(t *testing.T) {
 
 func TestExecuteGrant_Delete(t *testing.T) {
 	var captured []execCapture
-	sc := withTestIndex(newTestExecuteClient(t, &captured))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = append(captured, execCapture{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()})
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"grant-id-1"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+	sc.index = newResourceIndex()
+	withTestIndex(sc)
 
 	action := declarative.Action{
 		Operation:    declarative.OpDelete,
@@ -212,11 +328,81 @@ func TestExecuteGrant_Delete(t *testing.T) {
 
 	err := sc.Execute(context.Background(), action)
 	require.NoError(t, err)
-	require.Len(t, captured, 1)
+	require.Len(t, captured, 2)
 
-	req := captured[0]
-	assert.Equal(t, http.MethodDelete, req.Method)
-	assert.Equal(t, "group-id-admins", bodyStr(req, "principal_id"))
+	lookup := captured[0]
+	assert.Equal(t, http.MethodGet, lookup.Method)
+	assert.Equal(t, "/v1/grants", lookup.Path)
+	assert.Equal(t, "group-id-admins", queryStr(lookup, "principal_id"))
+	assert.Equal(t, "schema-id-titanic", queryStr(lookup, "securable_id"))
+	assert.Equal(t, "ALL_PRIVILEGES", queryStr(lookup, "privilege"))
+
+	del := captured[1]
+	assert.Equal(t, http.MethodDelete, del.Method)
+	assert.Equal(t, "/v1/grants/grant-id-1", del.Path)
+}
+
+func TestExecuteGrant_UpdateExpiryInPlace(t *testing.T) {
+	var captured []execCapture
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ec := execCapture{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()}
+		_ = json.NewDecoder(r.Body).Decode(&ec.Body)
+		captured = append(captured, ec)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"data":[{"id":"grant-id-1"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"grant-id-1"}`))
+	}))
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+	sc.index = newResourceIndex()
+	withTestIndex(sc)
+
+	grant := declarative.GrantSpec{
+		Principal:     "admins",
+		PrincipalType: "group",
+		SecurableType: "schema",
+		Securable:     "demo.titanic",
+		Privilege:     "ALL_PRIVILEGES",
+	}
+	expiresAt := "2030-01-01T00:00:00Z"
+	desired := grant
+	desired.ExpiresAt = &expiresAt
+
+	tests := []struct {
+		name    string
+		actual  declarative.GrantSpec
+		desired declarative.GrantSpec
+		want    interface{}
+	}{
+		{"set expiry", grant, desired, "2030-01-01T00:00:00Z"},
+		{"remove expiry", desired, grant, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captured = nil
+			err := sc.Execute(context.Background(), declarative.Action{
+				Operation:    declarative.OpUpdate,
+				ResourceKind: declarative.KindPrivilegeGrant,
+				ResourceName: "admins->schema:demo.titanic:ALL_PRIVILEGES",
+				Actual:       tt.actual,
+				Desired:      tt.desired,
+			})
+			require.NoError(t, err)
+			require.Len(t, captured, 2)
+
+			assert.Equal(t, http.MethodGet, captured[0].Method)
+			assert.Equal(t, "/v1/grants", captured[0].Path)
+
+			patch := captured[1]
+			assert.Equal(t, http.MethodPatch, patch.Method)
+			assert.Equal(t, "/v1/grants/grant-id-1", patch.Path)
+			require.Contains(t, patch.Body, "expires_at")
+			assert.Equal(t, tt.want, patch.Body["expires_at"])
+		})
+	}
 }
 
 // === Group membership execution tests (#128) ===
@@ -271,6 +457,113 @@ func TestExecuteGroupMembership_Delete(t *testing.T) {
 	assert.Equal(t, "user", queryStr(req, "member_type"))
 }
 
+func TestExecuteGroupMembership_BatchedSync(t *testing.T) {
+	var captured []execCapture
+	sc := withTestIndex(newTestExecuteClient(t, &captured))
+	sc.index.principalIDByName["carol"] = "principal-id-carol"
+	sc.index.groupMembersByName["analysts"] = []declarative.MemberRef{
+		{Name: "alice", Type: "user", MemberID: "principal-id-alice"},
+		{Name: "bob", Type: "user", MemberID: "principal-id-bob"},
+	}
+
+	actions := []declarative.Action{
+		{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "analysts/admins(group)",
+			Desired:      declarative.MemberRef{Name: "admins", Type: "group"},
+		},
+		{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "analysts/carol(user)",
+			Desired:      declarative.MemberRef{Name: "carol", Type: "user"},
+		},
+		{
+			Operation:    declarative.OpDelete,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "analysts/bob(user)",
+			Actual:       declarative.MemberRef{Name: "bob", Type: "user", MemberID: "principal-id-bob"},
+		},
+	}
+	sc.PrepareGroupMemberSync(actions)
+	for _, action := range actions {
+		require.NoError(t, sc.Execute(context.Background(), action))
+	}
+
+	require.Len(t, captured, 1, "all membership changes for a group should be one call")
+	req := captured[0]
+	assert.Equal(t, http.MethodPut, req.Method)
+	assert.Equal(t, "/v1/groups/group-id-analysts/members", req.Path)
+	members, ok := req.Body["members"].([]interface{})
+	require.True(t, ok)
+	var got []string
+	for _, m := range members {
+		mm := m.(map[string]interface{})
+		got = append(got, mm["member_type"].(string)+":"+mm["member_id"].(string))
+	}
+	assert.ElementsMatch(t, []string{
+		"user:principal-id-alice",
+		"group:group-id-admins",
+		"user:principal-id-carol",
+	}, got)
+}
+
+func TestExecuteGroupMembership_BatchedSyncFallback(t *testing.T) {
+	var captured []execCapture
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = append(captured, execCapture{Method: r.Method, Path: r.URL.Path})
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+	sc.index = newResourceIndex()
+	withTestIndex(sc)
+
+	actions := []declarative.Action{
+		{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "analysts/alice(user)",
+			Desired:      declarative.MemberRef{Name: "alice", Type: "user"},
+		},
+		{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "analysts/bob(user)",
+			Desired:      declarative.MemberRef{Name: "bob", Type: "user"},
+		},
+	}
+	sc.PrepareGroupMemberSync(actions)
+	for _, action := range actions {
+		require.NoError(t, sc.Execute(context.Background(), action))
+	}
+
+	var methods []string
+	for _, c := range captured {
+		methods = append(methods, c.Method)
+	}
+	assert.Equal(t, []string{http.MethodPut, http.MethodPost, http.MethodPost}, methods)
+}
+
+func TestPrepareGroupMemberSync_SkipsDeletedGroups(t *testing.T) {
+	sc := withTestIndex(newTestExecuteClient(t, new([]execCapture)))
+	sc.PrepareGroupMemberSync([]declarative.Action{
+		{
+			Operation:    declarative.OpDelete,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "analysts/alice(user)",
+			Actual:       declarative.MemberRef{Name: "alice", Type: "user"},
+		},
+		{Operation: declarative.OpDelete, ResourceKind: declarative.KindGroup, ResourceName: "analysts"},
+	})
+	assert.Empty(t, sc.memberSyncs)
+}
+
 // === Tag execution tests (#128) ===
 
 func TestExecuteTag_Create(t *testing.T) {
@@ -580,6 +873,93 @@ func TestExecutePrincipal_CreateCapturesID(t *testing.T) {
 	assert.Equal(t, "generated-uuid-123", sc.index.principalIDByName["alice"])
 }
 
+// newIdempotentCreateServer serves POST /v1/principals behind the server's
+// idempotency middleware, answering 409 for a name that already exists.
+func newIdempotentCreateServer(t *testing.T) (*gen.Client, *[]string) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		keys  []string
+		names = make(map[string]string)
+	)
+	create := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Name string `json:"name"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if _, ok := names[body.Name]; ok {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"code":409,"message":"principal already exists"}`))
+			return
+		}
+		names[body.Name] = "principal-" + body.Name
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": names[body.Name], "name": body.Name})
+	})
+	handler := middleware.Idempotency(middleware.IdempotencyConfig{TTL: time.Hour})(create)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(gen.IdempotencyKeyHeader))
+		mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return gen.NewClient(srv.URL, "", "test-token"), &keys
+}
+
+func TestExecute_ReplayedCreateReturnsOriginalResource(t *testing.T) {
+	client, keys := newIdempotentCreateServer(t)
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindPrincipal,
+		ResourceName: "alice",
+		Desired:      declarative.PrincipalSpec{Name: "alice", Type: "user"},
+	}
+
+	first := NewAPIStateClient(client)
+	first.index = newResourceIndex()
+	require.NoError(t, first.Execute(context.Background(), action))
+
+	// A resumed run reuses the run ID, so the create is replayed, not
+	// rejected as a duplicate.
+	resumed := NewAPIStateClientWithOptions(client, APIStateClientOptions{ApplyRunID: first.ApplyRunID()})
+	resumed.index = newResourceIndex()
+	require.NoError(t, resumed.Execute(context.Background(), action))
+	assert.Equal(t, "principal-alice", resumed.index.principalIDByName["alice"])
+
+	require.Len(t, *keys, 2)
+	assert.NotEmpty(t, (*keys)[0])
+	assert.Equal(t, (*keys)[0], (*keys)[1])
+	assert.True(t, strings.HasPrefix((*keys)[0], first.ApplyRunID()+"-"))
+
+	// A new run gets new keys and sees the existing resource.
+	fresh := NewAPIStateClient(client)
+	fresh.index = newResourceIndex()
+	err := fresh.Execute(context.Background(), action)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "409")
+}
+
+func TestExecute_IdempotencyKeysDifferPerAction(t *testing.T) {
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+	var keys []string
+	for _, name := range []string{"alice", "bob"} {
+		ctx := sc.beginAction(context.Background(), declarative.Action{Operation: declarative.OpCreate, ResourceKind: declarative.KindPrincipal, ResourceName: name})
+		keys = append(keys, sc.nextIdempotencyKey(ctx), sc.nextIdempotencyKey(ctx))
+	}
+	assert.Len(t, keys, 4)
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		assert.False(t, seen[k], "duplicate key %s", k)
+		seen[k] = true
+		assert.LessOrEqual(t, len(k), 255)
+	}
+}
+
 // === Group execution tests (ID capture) ===
 
 func TestExecuteGroup_CreateCapturesID(t *testing.T) {
@@ -678,6 +1058,79 @@ func TestExecutePrincipal_UpdateSendsAdminEndpoint(t *testing.T) {
 	assert.True(t, bodyBool(req, "is_admin"))
 }
 
+func TestExecutePrincipal_UpdatePendingApprovalFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/v1/principals/principal-id-alice/admin", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"id":"approval-1","operation":"set_admin","status":"PENDING"}`))
+	}))
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+	sc.index = newResourceIndex()
+	withTestIndex(sc)
+
+	err := sc.Execute(context.Background(), declarative.Action{
+		Operation:    declarative.OpUpdate,
+		ResourceKind: declarative.KindPrincipal,
+		ResourceName: "alice",
+		Desired:      declarative.PrincipalSpec{Name: "alice", Type: "user", IsAdmin: true},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pending approval approval-1")
+}
+
+func TestExecutePrincipal_CreateSetsAttributes(t *testing.T) {
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindPrincipal,
+		ResourceName: "alice",
+		Desired: declarative.PrincipalSpec{
+			Name:       "alice",
+			Type:       "user",
+			Attributes: map[string]string{"region": "EU"},
+		},
+	}
+
+	require.NoError(t, sc.Execute(context.Background(), action))
+	require.Len(t, captured, 2)
+	assert.Equal(t, http.MethodPost, captured[0].Method)
+
+	req := captured[1]
+	assert.Equal(t, http.MethodPut, req.Method)
+	assert.Contains(t, req.Path, "/principals/generated-uuid-123/attributes")
+	assert.Equal(t, map[string]interface{}{"region": "EU"}, req.Body["attributes"])
+}
+
+func TestExecutePrincipal_UpdateAttributesOnly(t *testing.T) {
+	var captured []execCapture
+	sc := withTestIndex(newTestExecuteClient(t, &captured))
+
+	action := declarative.Action{
+		Operation:    declarative.OpUpdate,
+		ResourceKind: declarative.KindPrincipal,
+		ResourceName: "alice",
+		Desired: declarative.PrincipalSpec{
+			Name:       "alice",
+			Type:       "user",
+			Attributes: map[string]string{"region": "US"},
+		},
+		Changes: []declarative.FieldDiff{{Field: "attributes", OldValue: "region=EU", NewValue: "region=US"}},
+	}
+
+	require.NoError(t, sc.Execute(context.Background(), action))
+	require.Len(t, captured, 1)
+
+	req := captured[0]
+	assert.Equal(t, http.MethodPut, req.Method)
+	assert.Contains(t, req.Path, "/principals/principal-id-alice/attributes")
+	assert.Equal(t, map[string]interface{}{"region": "US"}, req.Body["attributes"])
+}
+
 func TestExecutePrincipal_DeleteResolvesID(t *testing.T) {
 	var captured []execCapture
 	sc := withTestIndex(newTestExecuteClient(t, &captured))
@@ -801,6 +1254,7 @@ func TestExecuteTable_CreateUsesNestedPath(t *testing.T) {
 					{Name: "id", Type: "INTEGER"},
 					{Name: "amount", Type: "DOUBLE", Comment: "order amount"},
 				},
+				PartitionBy: []string{"id"},
 			},
 		},
 	}
@@ -812,6 +1266,7 @@ func TestExecuteTable_CreateUsesNestedPath(t *testing.T) {
 	req := captured[0]
 	assert.Equal(t, http.MethodPost, req.Method)
 	assert.Contains(t, req.Path, "/catalogs/demo/schemas/analytics/tables")
+	assert.Equal(t, []interface{}{"id"}, req.Body["partition_by"])
 	assert.Equal(t, "orders", bodyStr(req, "name"))
 	assert.Equal(t, "MANAGED", bodyStr(req, "table_type"))
 	assert.Equal(t, "order table", bodyStr(req, "comment"))
@@ -952,58 +1407,732 @@ func TestExecuteView_DeleteUsesNestedPath(t *testing.T) {
 	assert.Contains(t, req.Path, "/catalogs/demo/schemas/analytics/views/order_summary")
 }
 
-// === Unimplemented resource kind test ===
+// === Volume execution tests ===
 
-func TestExecute_UnimplementedKindReturnsError(t *testing.T) {
+func TestExecuteVolume_CreateUsesNestedPath(t *testing.T) {
 	var captured []execCapture
 	sc := newTestExecuteClient(t, &captured)
 
 	action := declarative.Action{
 		Operation:    declarative.OpCreate,
 		ResourceKind: declarative.KindVolume,
-		ResourceName: "demo.analytics.stage",
+		ResourceName: "demo.analytics.landing",
+		Desired: declarative.VolumeResource{
+			CatalogName: "demo",
+			SchemaName:  "analytics",
+			VolumeName:  "landing",
+			Spec: declarative.VolumeSpec{
+				VolumeType:      "EXTERNAL",
+				StorageLocation: "s3://landing-bucket/raw/",
+				Comment:         "raw files",
+			},
+		},
 	}
 
 	err := sc.Execute(context.Background(), action)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not yet implemented")
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+
+	req := captured[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/v1/catalogs/demo/schemas/analytics/volumes", req.Path)
+	assert.Equal(t, "landing", bodyStr(req, "name"))
+	assert.Equal(t, "EXTERNAL", bodyStr(req, "volume_type"))
+	assert.Equal(t, "s3://landing-bucket/raw/", bodyStr(req, "storage_location"))
+	assert.Equal(t, "raw files", bodyStr(req, "comment"))
+	assert.NotContains(t, req.Body, "owner")
+
+	// Volume ID should be captured in the index.
+	assert.Equal(t, "generated-uuid-123", sc.index.volumeIDByPath["demo.analytics.landing"])
 }
 
-func TestExecuteNotebook_CreateCreatesCells(t *testing.T) {
+func TestExecuteVolume_CreateDefaultsToManagedAndSetsOwner(t *testing.T) {
 	var captured []execCapture
 	sc := newTestExecuteClient(t, &captured)
 
 	action := declarative.Action{
 		Operation:    declarative.OpCreate,
-		ResourceKind: declarative.KindNotebook,
-		ResourceName: "kpi_walkthrough",
-		Desired: declarative.NotebookResource{
-			Name: "kpi_walkthrough",
-			Spec: declarative.NotebookSpec{
-				Description: "KPI notebook",
-				Cells: []declarative.CellSpec{
-					{Type: "markdown", Content: "# Header"},
-					{Type: "sql", Content: "SELECT 1"},
-				},
-			},
+		ResourceKind: declarative.KindVolume,
+		ResourceName: "demo.analytics.scratch",
+		Desired: declarative.VolumeResource{
+			CatalogName: "demo",
+			SchemaName:  "analytics",
+			VolumeName:  "scratch",
+			Spec:        declarative.VolumeSpec{Owner: "data-eng"},
 		},
 	}
 
 	err := sc.Execute(context.Background(), action)
 	require.NoError(t, err)
-	require.GreaterOrEqual(t, len(captured), 4)
+	require.Len(t, captured, 2)
 
 	assert.Equal(t, http.MethodPost, captured[0].Method)
-	assert.Contains(t, captured[0].Path, "/notebooks")
-	assert.Equal(t, "kpi_walkthrough", bodyStr(captured[0], "name"))
+	assert.Equal(t, "MANAGED", bodyStr(captured[0], "volume_type"))
+	assert.NotContains(t, captured[0].Body, "storage_location")
 
-	assert.Equal(t, http.MethodPost, captured[2].Method)
-	assert.Contains(t, captured[2].Path, "/notebooks/generated-uuid-123/cells")
-	assert.Equal(t, "markdown", bodyStr(captured[2], "cell_type"))
+	assert.Equal(t, http.MethodPatch, captured[1].Method)
+	assert.Equal(t, "/v1/catalogs/demo/schemas/analytics/volumes/scratch", captured[1].Path)
+	assert.Equal(t, "data-eng", bodyStr(captured[1], "owner"))
+}
+
+func TestExecuteVolume_UpdateUsesNestedPath(t *testing.T) {
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpUpdate,
+		ResourceKind: declarative.KindVolume,
+		ResourceName: "demo.analytics.landing",
+		Desired: declarative.VolumeResource{
+			CatalogName: "demo",
+			SchemaName:  "analytics",
+			VolumeName:  "landing",
+			Spec: declarative.VolumeSpec{
+				VolumeType: "MANAGED",
+				Comment:    "updated comment",
+				Owner:      "data-eng",
+			},
+		},
+		Changes: []declarative.FieldDiff{
+			{Field: "comment", OldValue: "raw files", NewValue: "updated comment"},
+			{Field: "owner", OldValue: "alice", NewValue: "data-eng"},
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+
+	req := captured[0]
+	assert.Equal(t, http.MethodPatch, req.Method)
+	assert.Equal(t, "/v1/catalogs/demo/schemas/analytics/volumes/landing", req.Path)
+	assert.Equal(t, "updated comment", bodyStr(req, "comment"))
+	assert.Equal(t, "data-eng", bodyStr(req, "owner"))
+}
+
+func TestExecuteVolume_UpdateRejectsStorageChanges(t *testing.T) {
+	for _, field := range []string{"volume_type", "storage_location"} {
+		t.Run(field, func(t *testing.T) {
+			var captured []execCapture
+			sc := newTestExecuteClient(t, &captured)
+
+			action := declarative.Action{
+				Operation:    declarative.OpUpdate,
+				ResourceKind: declarative.KindVolume,
+				ResourceName: "demo.analytics.landing",
+				Desired: declarative.VolumeResource{
+					CatalogName: "demo",
+					SchemaName:  "analytics",
+					VolumeName:  "landing",
+				},
+				Changes: []declarative.FieldDiff{{Field: field, OldValue: "a", NewValue: "b"}},
+			}
+
+			err := sc.Execute(context.Background(), action)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "cannot be changed in place")
+			assert.Empty(t, captured)
+		})
+	}
+}
+
+func TestExecuteVolume_DeleteUsesNestedPath(t *testing.T) {
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+	sc.index.setVolumeID("demo.analytics.landing", "volume-id-landing")
+
+	action := declarative.Action{
+		Operation:    declarative.OpDelete,
+		ResourceKind: declarative.KindVolume,
+		ResourceName: "demo.analytics.landing",
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+
+	req := captured[0]
+	assert.Equal(t, http.MethodDelete, req.Method)
+	assert.Equal(t, "/v1/catalogs/demo/schemas/analytics/volumes/landing", req.Path)
+	_, ok := sc.index.getVolumeID("demo.analytics.landing")
+	assert.False(t, ok)
+}
+
+// === Storage credential execution tests ===
+
+func TestExecuteStorageCredential_CreateReadsSecretsFromEnv(t *testing.T) {
+	t.Setenv("TEST_AWS_KEY_ID", "AKIAEXAMPLE")
+	t.Setenv("TEST_AWS_SECRET", "s3cr3t")
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindStorageCredential,
+		ResourceName: "aws-creds",
+		Desired: declarative.StorageCredentialSpec{
+			Name:           "aws-creds",
+			CredentialType: "S3",
+			Comment:        "AWS access",
+			S3: &declarative.S3CredentialSpec{
+				KeyIDFromEnv:  "TEST_AWS_KEY_ID",
+				SecretFromEnv: "TEST_AWS_SECRET",
+				Region:        "eu-west-1",
+			},
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+
+	req := captured[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/v1/storage-credentials", req.Path)
+	assert.Equal(t, map[string]interface{}{
+		"name":            "aws-creds",
+		"credential_type": "S3",
+		"comment":         "AWS access",
+		"key_id":          "AKIAEXAMPLE",
+		"secret":          "s3cr3t",
+		"region":          "eu-west-1",
+	}, req.Body)
+	assert.Equal(t, "generated-uuid-123", sc.index.credentialIDByName["aws-creds"])
+}
+
+func TestExecuteStorageCredential_CreateFailsWhenEnvUnset(t *testing.T) {
+	t.Setenv("TEST_AZURE_ACCOUNT", "acct")
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindStorageCredential,
+		ResourceName: "azure-creds",
+		Desired: declarative.StorageCredentialSpec{
+			Name:           "azure-creds",
+			CredentialType: "AZURE",
+			Azure: &declarative.AzureCredentialSpec{
+				AccountNameFromEnv: "TEST_AZURE_ACCOUNT",
+				AccountKeyFromEnv:  "TEST_AZURE_KEY_UNSET",
+			},
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TEST_AZURE_KEY_UNSET")
+	assert.NotContains(t, err.Error(), "acct")
+	assert.Empty(t, captured)
+}
+
+func TestExecuteStorageCredential_UpdateResendsSecrets(t *testing.T) {
+	t.Setenv("TEST_AWS_KEY_ID", "AKIAROTATED")
+	t.Setenv("TEST_AWS_SECRET", "rotated-secret")
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	spec := declarative.StorageCredentialSpec{
+		Name:           "aws-creds",
+		CredentialType: "S3",
+		S3:             &declarative.S3CredentialSpec{KeyIDFromEnv: "TEST_AWS_KEY_ID", SecretFromEnv: "TEST_AWS_SECRET"},
+	}
+	action := declarative.Action{
+		Operation:    declarative.OpUpdate,
+		ResourceKind: declarative.KindStorageCredential,
+		ResourceName: "aws-creds",
+		Desired:      spec,
+		Actual:       declarative.StorageCredentialSpec{Name: "aws-creds", CredentialType: "S3"},
+		Changes:      []declarative.FieldDiff{{Field: "secrets", OldValue: "(write-only)", NewValue: "(rotated)"}},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+
+	req := captured[0]
+	assert.Equal(t, http.MethodPatch, req.Method)
+	assert.Equal(t, "/v1/storage-credentials/aws-creds", req.Path)
+	assert.Equal(t, "AKIAROTATED", bodyStr(req, "key_id"))
+	assert.Equal(t, "rotated-secret", bodyStr(req, "secret"))
+	assert.NotContains(t, req.Body, "name")
+	assert.NotContains(t, req.Body, "credential_type")
+}
+
+func TestExecuteStorageCredential_UpdateRejectsTypeChange(t *testing.T) {
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpUpdate,
+		ResourceKind: declarative.KindStorageCredential,
+		ResourceName: "creds",
+		Desired:      declarative.StorageCredentialSpec{Name: "creds", CredentialType: "GCS", GCS: &declarative.GCSCredentialSpec{}},
+		Changes:      []declarative.FieldDiff{{Field: "credential_type", OldValue: "S3", NewValue: "GCS"}},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be changed in place")
+	assert.Empty(t, captured)
+}
+
+func TestExecuteStorageCredential_Delete(t *testing.T) {
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpDelete,
+		ResourceKind: declarative.KindStorageCredential,
+		ResourceName: "aws-creds",
+		Actual:       declarative.StorageCredentialSpec{Name: "aws-creds", CredentialType: "S3"},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+	assert.Equal(t, http.MethodDelete, captured[0].Method)
+	assert.Equal(t, "/v1/storage-credentials/aws-creds", captured[0].Path)
+}
+
+// === External location execution tests ===
+
+// newExternalLocationClient records every request and knows one storage
+// credential, aws-creds; other credentials are answered with 404.
+func newExternalLocationClient(t *testing.T, captured *[]execCapture) *APIStateClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ec := execCapture{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			_ = json.Unmarshal(data, &ec.Body)
+		}
+		*captured = append(*captured, ec)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/storage-credentials/aws-creds":
+			_, _ = w.Write([]byte(`{"id":"cred-id-aws","name":"aws-creds","credential_type":"S3"}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"location-id-raw"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+	sc.index = newResourceIndex()
+	return sc
+}
+
+func TestExecuteExternalLocation_CreateReferencesExistingCredential(t *testing.T) {
+	var captured []execCapture
+	sc := newExternalLocationClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindExternalLocation,
+		ResourceName: "raw",
+		Desired: declarative.ExternalLocationSpec{
+			Name:           "raw",
+			URL:            "s3://landing/raw/",
+			CredentialName: "aws-creds",
+			Comment:        "raw landing zone",
+			ReadOnly:       true,
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 2)
+
+	assert.Equal(t, http.MethodGet, captured[0].Method)
+	assert.Equal(t, "/v1/storage-credentials/aws-creds", captured[0].Path)
+
+	req := captured[1]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/v1/external-locations", req.Path)
+	assert.Equal(t, map[string]interface{}{
+		"name":            "raw",
+		"url":             "s3://landing/raw/",
+		"credential_name": "aws-creds",
+		"comment":         "raw landing zone",
+		"read_only":       true,
+	}, req.Body)
+	assert.Equal(t, "cred-id-aws", sc.index.credentialIDByName["aws-creds"])
+	assert.Equal(t, "location-id-raw", sc.index.locationIDByName["raw"])
+}
+
+func TestExecuteExternalLocation_CreateUsesIndexedCredential(t *testing.T) {
+	var captured []execCapture
+	sc := newExternalLocationClient(t, &captured)
+	// Created earlier in the same apply.
+	sc.index.setCredentialID("new-creds", "cred-id-new")
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindExternalLocation,
+		ResourceName: "raw",
+		Desired:      declarative.ExternalLocationSpec{Name: "raw", URL: "s3://landing/raw/", CredentialName: "new-creds"},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+	assert.Equal(t, http.MethodPost, captured[0].Method)
+	assert.Equal(t, false, captured[0].Body["read_only"])
+}
+
+func TestExecuteExternalLocation_CreateFailsUnknownCredential(t *testing.T) {
+	var captured []execCapture
+	sc := newExternalLocationClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindExternalLocation,
+		ResourceName: "raw",
+		Desired:      declarative.ExternalLocationSpec{Name: "raw", URL: "s3://landing/raw/", CredentialName: "missing-creds"},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `storage credential "missing-creds" not found`)
+	require.Len(t, captured, 1)
+	assert.Equal(t, http.MethodGet, captured[0].Method)
+}
+
+func TestExecuteExternalLocation_UpdateSendsChangedFields(t *testing.T) {
+	var captured []execCapture
+	sc := newExternalLocationClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpUpdate,
+		ResourceKind: declarative.KindExternalLocation,
+		ResourceName: "raw",
+		Desired:      declarative.ExternalLocationSpec{Name: "raw", URL: "s3://landing/v2/", CredentialName: "aws-creds"},
+		Changes: []declarative.FieldDiff{
+			{Field: "url", OldValue: "s3://landing/raw/", NewValue: "s3://landing/v2/"},
+			{Field: "read_only", OldValue: "true", NewValue: "false"},
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+
+	req := captured[0]
+	assert.Equal(t, http.MethodPatch, req.Method)
+	assert.Equal(t, "/v1/external-locations/raw", req.Path)
+	assert.Equal(t, map[string]interface{}{"url": "s3://landing/v2/", "read_only": false}, req.Body)
+}
+
+func TestExecuteExternalLocation_Delete(t *testing.T) {
+	var captured []execCapture
+	sc := newExternalLocationClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpDelete,
+		ResourceKind: declarative.KindExternalLocation,
+		ResourceName: "raw",
+		Actual:       declarative.ExternalLocationSpec{Name: "raw", URL: "s3://landing/raw/", CredentialName: "aws-creds"},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+	assert.Equal(t, http.MethodDelete, captured[0].Method)
+	assert.Equal(t, "/v1/external-locations/raw", captured[0].Path)
+}
+
+// === Compute assignment execution tests ===
+
+func TestExecuteComputeAssignment_CreateResolvesPrincipal(t *testing.T) {
+	var captured []execCapture
+	sc := withTestIndex(newTestExecuteClient(t, &captured))
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindComputeAssignment,
+		ResourceName: "analytics-xl->group:analysts",
+		Desired: declarative.ComputeAssignmentSpec{
+			Endpoint:      "analytics-xl",
+			Principal:     "analysts",
+			PrincipalType: "group",
+			FallbackLocal: true,
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 1)
+
+	req := captured[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/v1/compute-endpoints/analytics-xl/assignments", req.Path)
+	assert.Equal(t, "group-id-analysts", bodyStr(req, "principal_id"))
+	assert.Equal(t, "group", bodyStr(req, "principal_type"))
+	assert.Equal(t, false, req.Body["is_default"])
+	assert.Equal(t, true, req.Body["fallback_local"])
+}
+
+func TestExecuteComputeAssignment_CreateFailsUnknownPrincipal(t *testing.T) {
+	var captured []execCapture
+	sc := withTestIndex(newTestExecuteClient(t, &captured))
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindComputeAssignment,
+		ResourceName: "analytics-xl->user:nobody",
+		Desired: declarative.ComputeAssignmentSpec{
+			Endpoint:      "analytics-xl",
+			Principal:     "nobody",
+			PrincipalType: "user",
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nobody")
+	assert.Empty(t, captured)
+}
+
+// newComputeAssignmentClient serves one existing assignment of endpoint
+// analytics-xl to alice and records every request.
+func newComputeAssignmentClient(t *testing.T, captured *[]execCapture) *APIStateClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ec := execCapture{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			_ = json.Unmarshal(data, &ec.Body)
+		}
+		*captured = append(*captured, ec)
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[
+				{"id":"assign-id-bob","principal_id":"principal-id-bob","principal_type":"user","is_default":true},
+				{"id":"assign-id-alice","principal_id":"principal-id-alice","principal_type":"user","is_default":true}]}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"assign-id-new"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+	sc.index = newResourceIndex()
+	return withTestIndex(sc)
+}
+
+func TestExecuteComputeAssignment_DeleteFindsAssignmentID(t *testing.T) {
+	var captured []execCapture
+	sc := newComputeAssignmentClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpDelete,
+		ResourceKind: declarative.KindComputeAssignment,
+		ResourceName: "analytics-xl->user:alice",
+		Actual: declarative.ComputeAssignmentSpec{
+			Endpoint:      "analytics-xl",
+			Principal:     "alice",
+			PrincipalType: "user",
+			IsDefault:     true,
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 2)
+
+	assert.Equal(t, http.MethodGet, captured[0].Method)
+	assert.Equal(t, "/v1/compute-endpoints/analytics-xl/assignments", captured[0].Path)
+
+	assert.Equal(t, http.MethodDelete, captured[1].Method)
+	assert.Equal(t, "/v1/compute-endpoints/analytics-xl/assignments/assign-id-alice", captured[1].Path)
+}
+
+func TestExecuteComputeAssignment_DeleteMissingAssignment(t *testing.T) {
+	var captured []execCapture
+	sc := newComputeAssignmentClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpDelete,
+		ResourceKind: declarative.KindComputeAssignment,
+		ResourceName: "analytics-xl->group:admins",
+		Actual: declarative.ComputeAssignmentSpec{
+			Endpoint:      "analytics-xl",
+			Principal:     "admins",
+			PrincipalType: "group",
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	require.Len(t, captured, 1)
+	assert.Equal(t, http.MethodGet, captured[0].Method)
+}
+
+func TestExecuteComputeAssignment_UpdateReplacesAssignment(t *testing.T) {
+	var captured []execCapture
+	sc := newComputeAssignmentClient(t, &captured)
+
+	actual := declarative.ComputeAssignmentSpec{
+		Endpoint:      "analytics-xl",
+		Principal:     "alice",
+		PrincipalType: "user",
+		IsDefault:     true,
+	}
+	desired := actual
+	desired.IsDefault = false
+	desired.FallbackLocal = true
+	action := declarative.Action{
+		Operation:    declarative.OpUpdate,
+		ResourceKind: declarative.KindComputeAssignment,
+		ResourceName: "analytics-xl->user:alice",
+		Desired:      desired,
+		Actual:       actual,
+		Changes: []declarative.FieldDiff{
+			{Field: "is_default", OldValue: "true", NewValue: "false"},
+			{Field: "fallback_local", OldValue: "false", NewValue: "true"},
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.Len(t, captured, 3)
+
+	assert.Equal(t, http.MethodDelete, captured[1].Method)
+	assert.Equal(t, "/v1/compute-endpoints/analytics-xl/assignments/assign-id-alice", captured[1].Path)
+
+	create := captured[2]
+	assert.Equal(t, http.MethodPost, create.Method)
+	assert.Equal(t, "/v1/compute-endpoints/analytics-xl/assignments", create.Path)
+	assert.Equal(t, "principal-id-alice", bodyStr(create, "principal_id"))
+	assert.Equal(t, false, create.Body["is_default"])
+	assert.Equal(t, true, create.Body["fallback_local"])
+}
+
+// === Unimplemented resource kind test ===
+
+func TestExecute_UnimplementedKindReturnsError(t *testing.T) {
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindComputeEndpoint,
+		ResourceName: "analytics-xl",
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not yet implemented")
+}
+
+func TestExecuteNotebook_CreateCreatesCells(t *testing.T) {
+	var captured []execCapture
+	sc := newTestExecuteClient(t, &captured)
+
+	action := declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindNotebook,
+		ResourceName: "kpi_walkthrough",
+		Desired: declarative.NotebookResource{
+			Name: "kpi_walkthrough",
+			Spec: declarative.NotebookSpec{
+				Description: "KPI notebook",
+				Cells: []declarative.CellSpec{
+					{Type: "markdown", Content: "# Header"},
+					{Type: "sql", Content: "SELECT 1"},
+				},
+			},
+		},
+	}
+
+	err := sc.Execute(context.Background(), action)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(captured), 4)
+
+	assert.Equal(t, http.MethodPost, captured[0].Method)
+	assert.Contains(t, captured[0].Path, "/notebooks")
+	assert.Equal(t, "kpi_walkthrough", bodyStr(captured[0], "name"))
+
+	assert.Equal(t, http.MethodPost, captured[2].Method)
+	assert.Contains(t, captured[2].Path, "/notebooks/generated-uuid-123/cells")
+	assert.Equal(t, "markdown", bodyStr(captured[2], "cell_type"))
+
+	assert.Equal(t, http.MethodPost, captured[3].Method)
+	assert.Contains(t, captured[3].Path, "/notebooks/generated-uuid-123/cells")
+	assert.Equal(t, "sql", bodyStr(captured[3], "cell_type"))
+}
+
+func TestExecuteNotebook_UpdateSendsETagRead(t *testing.T) {
+	update := declarative.Action{
+		Operation:    declarative.OpUpdate,
+		ResourceKind: declarative.KindNotebook,
+		ResourceName: "nb1",
+		Desired: declarative.NotebookResource{
+			Name: "nb1",
+			Spec: declarative.NotebookSpec{Description: "updated"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		changedRemote bool
+		wantErr       bool
+	}{
+		{name: "unchanged since read"},
+		{name: "changed since read", changedRemote: true, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			version := 1
+			var ifMatch []string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v1/notebooks/nb-id-1", func(w http.ResponseWriter, r *http.Request) {
+				etag := fmt.Sprintf(`"v%d"`, version)
+				w.Header().Set("Content-Type", "application/json")
+				switch r.Method {
+				case http.MethodGet:
+					w.Header().Set("ETag", etag)
+					_, _ = w.Write([]byte(`{"notebook":{"id":"nb-id-1","name":"nb1"},"cells":[]}`))
+				case http.MethodPatch:
+					ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+					if r.Header.Get("If-Match") != etag {
+						w.WriteHeader(http.StatusPreconditionFailed)
+						_, _ = w.Write([]byte(`{"code":412,"message":"resource has changed since it was read"}`))
+						return
+					}
+					version++
+					_, _ = w.Write([]byte(`{"id":"nb-id-1","name":"nb1"}`))
+				}
+			})
+			sc := setupReadStateClient(t, mux)
+			sc.index = newResourceIndex()
+			sc.index.notebookIDByName["nb1"] = "nb-id-1"
+
+			_, err := sc.readNotebookDetail(context.Background(), "nb-id-1")
+			require.NoError(t, err)
+			if tc.changedRemote {
+				version++
+			}
 
-	assert.Equal(t, http.MethodPost, captured[3].Method)
-	assert.Contains(t, captured[3].Path, "/notebooks/generated-uuid-123/cells")
-	assert.Equal(t, "sql", bodyStr(captured[3], "cell_type"))
+			err = sc.Execute(context.Background(), update)
+			assert.Equal(t, []string{`"v1"`}, ifMatch)
+			if tc.wantErr {
+				var apiErr *gen.APIError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, http.StatusPreconditionFailed, apiErr.HTTPStatus)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
 }
 
 func TestExecutePipelineJob_CreateResolvesNotebookAndComputeIDs(t *testing.T) {
@@ -1726,6 +2855,181 @@ func TestValidateApplyCapabilities_SemanticEndpointRequired(t *testing.T) {
 	assert.Contains(t, err.Error(), "/semantic-models endpoint is unavailable")
 }
 
+// newCapabilityTestClient serves capsJSON from /v1/capabilities, empty lists
+// elsewhere and 204 for writes, recording "METHOD path" for every request.
+func newCapabilityTestClient(t *testing.T, capsJSON string, mode CapabilityCompatibilityMode) (*APIStateClient, func() []string) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/v1/capabilities":
+			if capsJSON == "" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(capsJSON))
+		case r.URL.Path == "/v1/groups":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"group-id-analysts","name":"analysts"}]}`))
+		case r.URL.Path == "/v1/principals":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"principal-id-alice","name":"alice","type":"user"},{"id":"principal-id-bob","name":"bob","type":"user"}]}`))
+		case r.Method == http.MethodGet:
+			emptyListHandler()(w, r)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClientWithOptions(gen.NewClient(srv.URL, "", "test-token"), APIStateClientOptions{CompatibilityMode: mode})
+	return sc, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+func capabilityMembershipActions() []declarative.Action {
+	return []declarative.Action{
+		{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "analysts/alice(user)",
+			Desired:      declarative.MemberRef{Name: "alice", Type: "user"},
+		},
+		{
+			Operation:    declarative.OpCreate,
+			ResourceKind: declarative.KindGroupMembership,
+			ResourceName: "analysts/bob(user)",
+			Desired:      declarative.MemberRef{Name: "bob", Type: "user"},
+		},
+	}
+}
+
+// applyActions runs the apply sequence of duck apply against sc: read state,
+// preflight, batch preparation and execution.
+func applyActions(t *testing.T, sc *APIStateClient, actions []declarative.Action) error {
+	t.Helper()
+	_, err := sc.ReadState(context.Background())
+	require.NoError(t, err)
+	if err := sc.ValidateApplyCapabilities(context.Background(), actions); err != nil {
+		return err
+	}
+	sc.PrepareGroupMemberSync(actions)
+	for _, action := range actions {
+		if err := sc.Execute(context.Background(), action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeRequests(requests []string) []string {
+	var out []string
+	for _, r := range requests {
+		if !strings.HasPrefix(r, http.MethodGet+" ") {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func TestCapabilities_AdvertisedBatchSyncIsUsed(t *testing.T) {
+	t.Parallel()
+
+	sc, requests := newCapabilityTestClient(t,
+		`{"api_version":"3.0.0","features":{"models":true,"macros":true,"semantic":true,"compute":true,"webhooks":true},"batch_operations":["group_members_sync"]}`,
+		CapabilityCompatibilityStrict)
+
+	require.NoError(t, applyActions(t, sc, capabilityMembershipActions()))
+	assert.Equal(t, []string{"PUT /v1/groups/group-id-analysts/members"}, writeRequests(requests()))
+}
+
+func TestCapabilities_NoAdvertisedBatchSyncUsesPerMemberCalls(t *testing.T) {
+	t.Parallel()
+
+	sc, requests := newCapabilityTestClient(t,
+		`{"api_version":"3.0.0","features":{"models":true,"macros":true,"semantic":true,"compute":true,"webhooks":true},"batch_operations":[]}`,
+		CapabilityCompatibilityStrict)
+
+	require.NoError(t, applyActions(t, sc, capabilityMembershipActions()))
+	assert.Equal(t, []string{
+		"POST /v1/groups/group-id-analysts/members",
+		"POST /v1/groups/group-id-analysts/members",
+	}, writeRequests(requests()), "no bulk attempt should be made when the server does not advertise it")
+}
+
+func TestCapabilities_DisabledFeaturesAreNotProbed(t *testing.T) {
+	t.Parallel()
+
+	sc, requests := newCapabilityTestClient(t,
+		`{"api_version":"3.0.0","features":{"models":false,"macros":false,"semantic":true,"compute":true,"webhooks":true},"batch_operations":["group_members_sync"]}`,
+		CapabilityCompatibilityStrict)
+
+	err := applyActions(t, sc, []declarative.Action{{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindModel,
+		ResourceName: "analytics.stg_orders",
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server does not enable models")
+	assert.Contains(t, sc.OptionalReadWarnings(), "models disabled on server; continuing without models state")
+	for _, r := range requests() {
+		assert.NotContains(t, []string{"GET /v1/models", "GET /v1/macros"}, r)
+	}
+	assert.Equal(t, 1, countRequests(requests(), "GET /v1/capabilities"), "capabilities are negotiated once")
+}
+
+func TestCapabilities_HandshakeSelectsStrictMode(t *testing.T) {
+	t.Parallel()
+
+	sc, _ := newCapabilityTestClient(t,
+		`{"api_version":"3.0.0","features":{"models":true,"macros":true,"semantic":true,"compute":true,"webhooks":true},"batch_operations":[]}`,
+		CapabilityCompatibilityLegacy)
+	caps, err := sc.NegotiateCapabilities(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, caps)
+	assert.Equal(t, "3.0.0", caps.APIVersion)
+	assert.Equal(t, CapabilityCompatibilityStrict, sc.compatibilityMode)
+}
+
+func TestCapabilities_OlderServerFallsBackToProbing(t *testing.T) {
+	t.Parallel()
+
+	sc, requests := newCapabilityTestClient(t, "", CapabilityCompatibilityLegacy)
+
+	require.NoError(t, applyActions(t, sc, capabilityMembershipActions()))
+	assert.Nil(t, sc.capabilities)
+	assert.Equal(t, CapabilityCompatibilityLegacy, sc.compatibilityMode)
+	assert.Equal(t, []string{"PUT /v1/groups/group-id-analysts/members"}, writeRequests(requests()))
+
+	before := countRequests(requests(), "GET /v1/macros")
+	require.NoError(t, sc.ValidateApplyCapabilities(context.Background(), []declarative.Action{{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindMacro,
+		ResourceName: "fmt_money",
+	}}))
+	assert.Equal(t, before+1, countRequests(requests(), "GET /v1/macros"), "the macros endpoint should be probed")
+	assert.Equal(t, 1, countRequests(requests(), "GET /v1/capabilities"), "capabilities are negotiated once")
+}
+
+func countRequests(requests []string, want string) int {
+	n := 0
+	for _, r := range requests {
+		if r == want {
+			n++
+		}
+	}
+	return n
+}
+
 // === ReadState helper ===
 
 // setupReadStateClient creates an APIStateClient backed by a test server with the given mux.
@@ -1787,6 +3091,10 @@ func TestReadState_Principals(t *testing.T) {
 		}
 		_ = json.NewEncoder(w).Encode(resp)
 	})
+	mux.HandleFunc("/v1/principals/p-1/attributes", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"principal_id":"p-1","attributes":{"region":"EU"}}`))
+	})
 	// Other endpoints return empty.
 	mux.HandleFunc("/", emptyListHandler())
 
@@ -1799,6 +3107,8 @@ func TestReadState_Principals(t *testing.T) {
 	assert.Equal(t, "alice", state.Principals[0].Name)
 	assert.Equal(t, "user", state.Principals[0].Type)
 	assert.False(t, state.Principals[0].IsAdmin)
+	assert.Equal(t, map[string]string{"region": "EU"}, state.Principals[0].Attributes)
+	assert.Nil(t, state.Principals[1].Attributes)
 
 	assert.Equal(t, "bob", state.Principals[1].Name)
 	assert.True(t, state.Principals[1].IsAdmin)
@@ -1945,6 +3255,64 @@ func TestReadState_Pagination(t *testing.T) {
 	mu.Unlock()
 }
 
+func TestReadState_PrincipalsAndGroupsSortedByName(t *testing.T) {
+	t.Parallel()
+
+	// The server's default order is by ID; with ?sort=name it pages by name.
+	byID := []map[string]interface{}{
+		{"id": "p-1", "name": "erin", "type": "user"},
+		{"id": "p-2", "name": "bob", "type": "user"},
+		{"id": "p-3", "name": "dave", "type": "user"},
+		{"id": "p-4", "name": "alice", "type": "user"},
+		{"id": "p-5", "name": "carol", "type": "user"},
+	}
+	paginate := func(items []map[string]interface{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			sorted := slices.Clone(items)
+			if r.URL.Query().Get("sort") == "name" {
+				slices.SortFunc(sorted, func(a, b map[string]interface{}) int {
+					return strings.Compare(a["name"].(string), b["name"].(string))
+				})
+			}
+			offset, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+			end := min(offset+2, len(sorted))
+			resp := map[string]interface{}{"data": sorted[offset:end]}
+			if end < len(sorted) {
+				resp["next_page_token"] = strconv.Itoa(end)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(resp)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/principals", paginate(byID))
+	mux.HandleFunc("/v1/groups", paginate([]map[string]interface{}{
+		{"id": "g-1", "name": "writers"},
+		{"id": "g-2", "name": "admins"},
+		{"id": "g-3", "name": "readers"},
+	}))
+	mux.HandleFunc("/", emptyListHandler())
+
+	var runs [][]string
+	for range 2 {
+		state, err := setupReadStateClient(t, mux).ReadState(context.Background())
+		require.NoError(t, err)
+		var names []string
+		for _, p := range state.Principals {
+			names = append(names, p.Name)
+		}
+		for _, g := range state.Groups {
+			names = append(names, g.Name)
+		}
+		runs = append(runs, names)
+	}
+
+	assert.Equal(t, []string{"alice", "bob", "carol", "dave", "erin", "admins", "readers", "writers"}, runs[0],
+		"pages neither overlap nor skip, in name order")
+	assert.Equal(t, runs[0], runs[1], "reads are reproducible")
+}
+
 func TestReadState_APIError4xx(t *testing.T) {
 	t.Parallel()
 
@@ -1964,6 +3332,25 @@ func TestReadState_APIError4xx(t *testing.T) {
 	assert.Contains(t, err.Error(), "HTTP 403")
 }
 
+func TestLookupNotebookIDByName_RequestsOnlyIDAndName(t *testing.T) {
+	t.Parallel()
+
+	var fields string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/notebooks", func(w http.ResponseWriter, r *http.Request) {
+		fields = r.URL.Query().Get("fields")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"nb-id-1","name":"nb1"}]}`))
+	})
+
+	sc := setupReadStateClient(t, mux)
+	id, err := sc.lookupNotebookIDByName(context.Background(), "nb1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "nb-id-1", id)
+	assert.Equal(t, "id,name", fields)
+}
+
 func TestReadState_ServerErrorReturnsError(t *testing.T) {
 	t.Parallel()
 
@@ -2030,6 +3417,7 @@ func TestReadState_CatalogsWithSchemasAndTables(t *testing.T) {
 						{"name": "id", "type": "INTEGER", "comment": "PK"},
 						{"name": "amount", "type": "DOUBLE", "comment": ""},
 					},
+					"partition_by": []string{"id"},
 				},
 			},
 		}
@@ -2069,6 +3457,7 @@ func TestReadState_CatalogsWithSchemasAndTables(t *testing.T) {
 	assert.Equal(t, "id", state.Tables[0].Spec.Columns[0].Name)
 	assert.Equal(t, "INTEGER", state.Tables[0].Spec.Columns[0].Type)
 	assert.Equal(t, "PK", state.Tables[0].Spec.Columns[0].Comment)
+	assert.Equal(t, []string{"id"}, state.Tables[0].Spec.PartitionBy)
 	assert.Equal(t, "tbl-1", sc.index.tableIDByPath["demo.analytics.orders"])
 }
 
@@ -2112,7 +3501,7 @@ func TestReadState_StorageCredentials(t *testing.T) {
 		w.Header().Set("Content-Type", "application/json")
 		resp := map[string]interface{}{
 			"data": []map[string]interface{}{
-				{"name": "aws-creds", "credential_type": "S3", "comment": "AWS access"},
+				{"name": "aws-creds", "credential_type": "S3", "comment": "AWS access", "region": "eu-west-1", "url_style": "path"},
 			},
 		}
 		_ = json.NewEncoder(w).Encode(resp)
@@ -2127,6 +3516,33 @@ func TestReadState_StorageCredentials(t *testing.T) {
 	assert.Equal(t, "aws-creds", state.StorageCredentials[0].Name)
 	assert.Equal(t, "S3", state.StorageCredentials[0].CredentialType)
 	assert.Equal(t, "AWS access", state.StorageCredentials[0].Comment)
+	assert.Equal(t, &declarative.S3CredentialSpec{Region: "eu-west-1", URLStyle: "path"}, state.StorageCredentials[0].S3)
+}
+
+func TestExportStorageCredentials_OmitsSecrets(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/storage-credentials", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// A secret in the response must not reach the export either.
+		_, _ = w.Write([]byte(`{"data":[{"name":"aws-creds","credential_type":"S3","region":"eu-west-1","key_id":"AKIALEAKED","secret":"leaked-secret"}]}`))
+	})
+	mux.HandleFunc("/", emptyListHandler())
+
+	sc := setupReadStateClient(t, mux)
+	state, err := sc.ReadState(context.Background())
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "export")
+	require.NoError(t, declarative.ExportDirectory(dir, state, false))
+	data, err := os.ReadFile(filepath.Join(dir, "storage", "credentials.yaml"))
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), "leaked-secret")
+	assert.NotContains(t, string(data), "AKIALEAKED")
+	assert.Contains(t, string(data), "secret_from_env: REPLACE_ME")
+	assert.Contains(t, string(data), "region: eu-west-1")
 }
 
 func TestReadState_ExternalLocations(t *testing.T) {
@@ -2210,6 +3626,37 @@ func TestReadState_ComputeEndpointsWithAssignments(t *testing.T) {
 	assert.True(t, state.ComputeAssignments[0].IsDefault)
 }
 
+func TestReadState_ComputeAssignmentsResolvePrincipalIDs(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/principals", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"principal-id-alice","name":"alice","type":"user"}]}`))
+	})
+	mux.HandleFunc("/v1/compute-endpoints", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"ep-1","name":"analytics-xl","type":"REMOTE"}]}`))
+	})
+	mux.HandleFunc("/v1/compute-endpoints/analytics-xl/assignments", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"assign-1","principal_id":"principal-id-alice","principal_type":"user","is_default":false,"fallback_local":true}]}`))
+	})
+	mux.HandleFunc("/", emptyListHandler())
+
+	sc := setupReadStateClient(t, mux)
+	state, err := sc.ReadState(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, state.ComputeAssignments, 1)
+	assert.Equal(t, declarative.ComputeAssignmentSpec{
+		Endpoint:      "analytics-xl",
+		Principal:     "alice",
+		PrincipalType: "user",
+		FallbackLocal: true,
+	}, state.ComputeAssignments[0])
+}
+
 func TestReadState_NotebooksAndPipelines(t *testing.T) {
 	t.Parallel()
 
@@ -2991,16 +4438,14 @@ func TestExecuteTable_CreatePopulatesIndexFromTableID(t *testing.T) {
 	assert.Equal(t, "table-uuid-789", sc.index.tableIDByPath["demo.analytics.orders"])
 }
 
-func TestExecuteSchema_CreateConflictHydratesIndex(t *testing.T) {
+func TestExecuteSchema_CreateUpsertsExisting(t *testing.T) {
 	t.Parallel()
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		switch {
-		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas"):
-			w.WriteHeader(http.StatusConflict)
-			_, _ = w.Write([]byte(`{"code":409,"message":"schema exists"}`))
-		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas/analytics"):
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas") &&
+			r.URL.Query().Get("upsert") == "true":
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte(`{"schema_id":"schema-existing-123","name":"analytics"}`))
 		default:
@@ -3026,16 +4471,14 @@ func TestExecuteSchema_CreateConflictHydratesIndex(t *testing.T) {
 	assert.Equal(t, "schema-existing-123", sc.index.schemaIDByPath["demo.analytics"])
 }
 
-func TestExecuteTable_CreateConflictHydratesIndex(t *testing.T) {
+func TestExecuteTable_CreateUpsertsExisting(t *testing.T) {
 	t.Parallel()
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		switch {
-		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas/analytics/tables"):
-			w.WriteHeader(http.StatusConflict)
-			_, _ = w.Write([]byte(`{"code":409,"message":"table exists"}`))
-		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas/analytics/tables/orders"):
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas/analytics/tables") &&
+			r.URL.Query().Get("upsert") == "true":
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte(`{"table_id":"table-existing-456","name":"orders"}`))
 		default:
@@ -3061,6 +4504,91 @@ func TestExecuteTable_CreateConflictHydratesIndex(t *testing.T) {
 	assert.Equal(t, "table-existing-456", sc.index.tableIDByPath["demo.analytics.orders"])
 }
 
+func TestExecuteTable_CreateOnServerWithoutUpsert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		errorCode string
+		wantErr   string
+	}{
+		// An older server ignores upsert=true and reports the existing table.
+		{name: "resource_exists_hydrates_index", errorCode: "RESOURCE_EXISTS"},
+		// A server with upsert rejects a table whose definition differs.
+		{name: "definition_conflict_is_an_error", errorCode: "CONFLICT", wantErr: "different definition"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas/analytics/tables"):
+					w.WriteHeader(http.StatusConflict)
+					_, _ = fmt.Fprintf(w, `{"code":409,"error_code":%q,"message":"table \"orders\" already exists with a different definition"}`, tt.errorCode)
+				case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas/analytics/tables/orders"):
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write([]byte(`{"table_id":"table-existing-456","name":"orders"}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"code":404,"error_code":"NOT_FOUND","message":"not found"}`))
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+			sc.index = newResourceIndex()
+
+			err := sc.Execute(context.Background(), declarative.Action{
+				Operation:    declarative.OpCreate,
+				ResourceKind: declarative.KindTable,
+				ResourceName: "demo.analytics.orders",
+				Desired:      declarative.TableResource{CatalogName: "demo", SchemaName: "analytics", TableName: "orders"},
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "table-existing-456", sc.index.tableIDByPath["demo.analytics.orders"])
+		})
+	}
+}
+
+func TestExecuteSchema_CreateOnServerWithoutUpsertHydratesIndex(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas"):
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"code":409,"error_code":"RESOURCE_EXISTS","message":"schema exists"}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/catalogs/demo/schemas/analytics"):
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"schema_id":"schema-existing-123","name":"analytics"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+	sc.index = newResourceIndex()
+
+	err := sc.Execute(context.Background(), declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindSchema,
+		ResourceName: "demo.analytics",
+		Desired:      declarative.SchemaResource{CatalogName: "demo", SchemaName: "analytics"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "schema-existing-123", sc.index.schemaIDByPath["demo.analytics"])
+}
+
 func TestExecuteColumnMask_CreateAlreadyExistsHydratesIndex(t *testing.T) {
 	t.Parallel()
 
@@ -3120,6 +4648,88 @@ func TestExecuteColumnMask_CreateAlreadyExistsHydratesIndex(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestExecuteColumnMask_CreateByErrorCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		errorCode string
+		wantErr   string
+	}{
+		{name: "resource_exists_hydrates_index", errorCode: "RESOURCE_EXISTS"},
+		{name: "other_conflict_is_an_error", errorCode: "CONFLICT", wantErr: "table is being dropped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var lookups int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/tables/table-1/column-masks"):
+					// The message deliberately says nothing about existence:
+					// only the code decides.
+					w.WriteHeader(http.StatusConflict)
+					_, _ = fmt.Fprintf(w, `{"code":409,"error_code":%q,"message":"table is being dropped"}`, tt.errorCode)
+				case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/tables/table-1/column-masks"):
+					lookups++
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write([]byte(`{"data":[{"id":"mask-existing-789","column_name":"name","mask_expression":"'***'"}]}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"code":404,"error_code":"NOT_FOUND","message":"not found"}`))
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+			sc.index = newResourceIndex()
+			sc.index.tableIDByPath["demo.titanic.passengers"] = "table-1"
+
+			err := sc.Execute(context.Background(), declarative.Action{
+				Operation:    declarative.OpCreate,
+				ResourceKind: declarative.KindColumnMask,
+				ResourceName: "demo.titanic.passengers/mask-name",
+				Desired:      declarative.ColumnMaskSpec{Name: "mask-name", ColumnName: "name", MaskExpression: "'***'"},
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Zero(t, lookups)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "mask-existing-789", sc.index.columnMaskIDByPath["demo.titanic.passengers/mask-name"])
+		})
+	}
+}
+
+func TestIsResourceExists(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "resource_exists_code", err: &gen.APIError{HTTPStatus: 409, ErrorCode: "RESOURCE_EXISTS", Message: "duplicate"}, want: true},
+		{name: "conflict_code", err: &gen.APIError{HTTPStatus: 409, ErrorCode: "CONFLICT", Message: "tag already exists"}},
+		{name: "code_wins_over_status", err: &gen.APIError{HTTPStatus: 500, ErrorCode: "INTERNAL", Message: "resource already exists"}},
+		{name: "legacy_conflict_status", err: &gen.APIError{HTTPStatus: 409, Message: "schema exists"}, want: true},
+		{name: "legacy_message", err: &gen.APIError{HTTPStatus: 500, Message: "resource already exists"}, want: true},
+		{name: "wrapped", err: fmt.Errorf("create: %w", &gen.APIError{HTTPStatus: 409, ErrorCode: "RESOURCE_EXISTS"}), want: true},
+		{name: "not_an_api_error", err: errors.New("resource already exists")},
+		{name: "nil", err: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, isResourceExists(tt.err))
+		})
+	}
+}
+
 func TestExecuteTagAssignment_CreateResolvesTableIDViaLookup(t *testing.T) {
 	t.Parallel()
 
@@ -3402,3 +5012,107 @@ func TestReadState_GroupMembersReverseLookup(t *testing.T) {
 	assert.Equal(t, "user", member.Type)
 	assert.Equal(t, "p-abc", member.MemberID, "MemberID should be preserved from API response")
 }
+
+// === Existence caching within an apply run ===
+
+// newCountingLookupClient serves table lookups for demo.sales.orders (when
+// exists is true) and accepts grants, counting the GETs it receives.
+func newCountingLookupClient(t *testing.T, exists bool, gets *int) *APIStateClient {
+	t.Helper()
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			mu.Lock()
+			*gets++
+			mu.Unlock()
+			if exists && r.URL.Path == "/v1/catalogs/demo/schemas/sales/tables/orders" {
+				_, _ = w.Write([]byte(`{"table_id":"table-id-orders","name":"orders"}`))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"error_code":"NOT_FOUND","message":"not found"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"new-id"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	sc := NewAPIStateClient(gen.NewClient(srv.URL, "", "test-token"))
+	sc.index = newResourceIndex()
+	return withTestIndex(sc)
+}
+
+func tableGrantAction(principal string) declarative.Action {
+	return declarative.Action{
+		Operation:    declarative.OpCreate,
+		ResourceKind: declarative.KindPrivilegeGrant,
+		ResourceName: principal + "->table:demo.sales.orders:SELECT",
+		Desired: declarative.GrantSpec{
+			Principal:     principal,
+			PrincipalType: "user",
+			SecurableType: "table",
+			Securable:     "demo.sales.orders",
+			Privilege:     "SELECT",
+		},
+	}
+}
+
+func TestResolveSecurableID_FetchesReferencedTableOnce(t *testing.T) {
+	var gets int
+	sc := newCountingLookupClient(t, true, &gets)
+
+	require.NoError(t, sc.Execute(context.Background(), tableGrantAction("alice")))
+	require.NoError(t, sc.Execute(context.Background(), tableGrantAction("bob")))
+
+	assert.Equal(t, 1, gets, "a table referenced twice should be fetched at most once")
+}
+
+func TestResolveSecurableID_CachesMissingTable(t *testing.T) {
+	var gets int
+	sc := newCountingLookupClient(t, false, &gets)
+
+	for range 2 {
+		_, err := sc.resolveSecurableID(context.Background(), "table", "demo.sales.orders")
+		require.Error(t, err)
+	}
+
+	assert.Equal(t, 1, gets, "a table found missing should not be fetched again")
+}
+
+func TestResolveSecurableID_CreatedAfterMissing(t *testing.T) {
+	var gets int
+	sc := newCountingLookupClient(t, false, &gets)
+
+	_, err := sc.resolveSecurableID(context.Background(), "table", "demo.sales.orders")
+	require.Error(t, err)
+	sc.index.setTableID("demo.sales.orders", "table-id-created")
+
+	id, err := sc.resolveSecurableID(context.Background(), "table", "demo.sales.orders")
+	require.NoError(t, err)
+	assert.Equal(t, "table-id-created", id)
+	assert.Equal(t, 1, gets)
+}
+
+func TestExecuteDelete_InvalidatesCachedIDs(t *testing.T) {
+	var gets int
+	sc := newCountingLookupClient(t, true, &gets)
+	sc.index.setSchemaID("demo.sales", "schema-id-sales")
+	sc.index.setTableID("demo.sales.orders", "table-id-orders")
+
+	require.NoError(t, sc.Execute(context.Background(), declarative.Action{
+		Operation:    declarative.OpDelete,
+		ResourceKind: declarative.KindSchema,
+		ResourceName: "demo.sales",
+		Actual:       declarative.SchemaResource{CatalogName: "demo", SchemaName: "sales"},
+	}))
+
+	_, err := sc.resolveSecurableID(context.Background(), "schema", "demo.sales")
+	require.Error(t, err, "a deleted schema should no longer resolve")
+	_, ok := sc.index.getTableID("demo.sales.orders")
+	assert.False(t, ok, "tables of a deleted schema should be dropped from the index")
+	assert.Equal(t, 0, gets, "a deleted schema is known missing without a fetch")
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/internal/declarative"
+	"duck-demo/pkg/cli/gen"
+)
+
+// newDiffSchemaCmd builds `duck catalog diff-schema <catalog.schema.table>`,
+// which compares a table's live columns with the columns declared in a
+// table.yaml manifest. It only reads from the server.
+func newDiffSchemaCmd(client *gen.Client) *cobra.Command {
+	var (
+		file               string
+		exitCode           bool
+		allowUnknownFields bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff-schema <catalog.schema.table>",
+		Short: "Compare a table's live columns with a table manifest",
+		Long: "Fetches the table's columns from the server and lists the columns the manifest adds, removes, " +
+			"retypes or re-comments. Nothing is changed on the server. With --exit-code the command exits 2 " +
+			"when the schemas differ, as duck plan does, so it can gate CI pipelines.",
+		Example: "  duck catalog diff-schema main.analytics.orders --file duck-config/catalogs/main/schemas/analytics/tables/orders/table.yaml\n" +
+			"  duck catalog diff-schema main.analytics.orders --file table.yaml --exit-code -o json",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parts := strings.Split(args[0], ".")
+			if len(parts) != 3 {
+				return fmt.Errorf("invalid table path %q: expected catalog.schema.table", args[0])
+			}
+
+			doc, err := declarative.LoadTableFile(file, declarative.LoadOptions{AllowUnknownFields: allowUnknownFields})
+			if err != nil {
+				return fmt.Errorf("load manifest: %w", err)
+			}
+			if doc.Metadata.Name != "" && doc.Metadata.Name != parts[2] {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: manifest declares table %q, comparing it with %s\n", doc.Metadata.Name, args[0])
+			}
+
+			live, err := readLiveColumns(client, parts[0], parts[1], parts[2])
+			if err != nil {
+				return err
+			}
+
+			changes := declarative.DiffColumns(live, doc.Spec.Columns)
+			if getOutputFormat(cmd) == "json" {
+				if changes == nil {
+					changes = []declarative.ColumnChange{}
+				}
+				if err := gen.PrintJSON(cmd.OutOrStdout(), map[string]interface{}{
+					"table":   args[0],
+					"changes": changes,
+				}); err != nil {
+					return err
+				}
+			} else {
+				printColumnChanges(cmd, args[0], changes)
+			}
+
+			if exitCode && len(changes) > 0 {
+				os.Exit(2)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Path to the table.yaml manifest")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit 2 when the live schema differs from the manifest")
+	cmd.Flags().BoolVar(&allowUnknownFields, "allow-unknown-fields", false, "Allow unknown YAML fields in the manifest")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// readLiveColumns returns the server's columns for a table.
+func readLiveColumns(client *gen.Client, catalog, schema, table string) ([]declarative.ColumnDef, error) {
+	path := "/catalogs/" + url.PathEscape(catalog) + "/schemas/" + url.PathEscape(schema) + "/tables/" + url.PathEscape(table)
+	resp, err := client.Do("GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return nil, err
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var t apiTable
+	if err := json.Unmarshal(body, &t); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	cols := make([]declarative.ColumnDef, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		cols = append(cols, declarative.ColumnDef{Name: col.Name, Type: col.Type, Comment: col.Comment})
+	}
+	return cols, nil
+}
+
+func printColumnChanges(cmd *cobra.Command, table string, changes []declarative.ColumnChange) {
+	w := cmd.OutOrStdout()
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintf(w, "%s matches the manifest\n", table)
+		return
+	}
+
+	counts := make(map[string]int, 4)
+	rows := make([][]string, 0, len(changes))
+	for _, c := range changes {
+		counts[c.Change]++
+		live, manifest := c.OldType, c.NewType
+		if c.Change == declarative.ColumnCommentChanged {
+			live, manifest = c.OldComment, c.NewComment
+		}
+		rows = append(rows, []string{c.Name, c.Change, live, manifest})
+	}
+	gen.PrintTable(w, []string{"COLUMN", "CHANGE", "LIVE", "MANIFEST"}, rows)
+	_, _ = fmt.Fprintf(w, "\n%s: %d added, %d removed, %d retyped, %d comment changed\n", table,
+		counts[declarative.ColumnAdded], counts[declarative.ColumnRemoved],
+		counts[declarative.ColumnRetyped], counts[declarative.ColumnCommentChanged])
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffSchemaManifest = `apiVersion: duck/v1
+kind: Table
+metadata:
+  name: orders
+spec:
+  columns:
+    - name: id
+      type: BIGINT
+    - name: amount
+      type: DECIMAL(18,2)
+    - name: created_at
+      type: TIMESTAMP
+`
+
+func newDiffSchemaServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/catalogs/main/schemas/analytics/tables/orders" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"table not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"orders","columns":[
+  {"name":"id","type":"BIGINT"},
+  {"name":"amount","type":"INTEGER"},
+  {"name":"legacy","type":"VARCHAR"}
+]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writeDiffSchemaManifest(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "table.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(diffSchemaManifest), 0o600))
+	return path
+}
+
+func TestDiffSchemaCmd_ReportsTypeChange(t *testing.T) {
+	srv := newDiffSchemaServer(t)
+	file := writeDiffSchemaManifest(t)
+
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "catalog", "diff-schema", "main.analytics.orders", "--file", file})
+	require.NoError(t, rootCmd.Execute())
+
+	text := out.String()
+	assert.Regexp(t, `amount\s+retyped\s+INTEGER\s+DECIMAL\(18,2\)`, text)
+	assert.Regexp(t, `created_at\s+added\s+TIMESTAMP`, text)
+	assert.Regexp(t, `legacy\s+removed\s+VARCHAR`, text)
+	assert.Contains(t, text, "main.analytics.orders: 1 added, 1 removed, 1 retyped, 0 comment changed")
+}
+
+func TestDiffSchemaCmd_JSON(t *testing.T) {
+	srv := newDiffSchemaServer(t)
+	file := writeDiffSchemaManifest(t)
+
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "--output", "json", "catalog", "diff-schema", "main.analytics.orders", "-f", file})
+	require.NoError(t, rootCmd.Execute())
+
+	var result struct {
+		Table   string `json:"table"`
+		Changes []struct {
+			Name    string `json:"name"`
+			Change  string `json:"change"`
+			OldType string `json:"old_type"`
+			NewType string `json:"new_type"`
+		} `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &result))
+	assert.Equal(t, "main.analytics.orders", result.Table)
+	require.Len(t, result.Changes, 3)
+	assert.Equal(t, "amount", result.Changes[0].Name)
+	assert.Equal(t, "retyped", result.Changes[0].Change)
+	assert.Equal(t, "INTEGER", result.Changes[0].OldType)
+	assert.Equal(t, "DECIMAL(18,2)", result.Changes[0].NewType)
+}
+
+func TestDiffSchemaCmd_Errors(t *testing.T) {
+	srv := newDiffSchemaServer(t)
+	file := writeDiffSchemaManifest(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"bad path", []string{"catalog", "diff-schema", "main.orders", "--file", file}, "expected catalog.schema.table"},
+		{"missing table", []string{"catalog", "diff-schema", "main.analytics.missing", "--file", file}, "HTTP 404"},
+		{"missing manifest", []string{"catalog", "diff-schema", "main.analytics.orders", "--file", filepath.Join(t.TempDir(), "nope.yaml")}, "load manifest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newTestRootCmd(t, srv)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL}, tt.args...))
+			require.ErrorContains(t, rootCmd.Execute(), tt.wantErr)
+		})
+	}
+}
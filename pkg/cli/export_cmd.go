@@ -50,5 +50,7 @@ func newExportCmd(client *gen.Client) *cobra.Command {
 	cmd.Flags().StringVar(&configDir, "config-dir", "./duck-config", "Path to output configuration directory")
 	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Overwrite existing files in the output directory")
 
+	cmd.AddCommand(newExportOpenAPICmd(client))
+
 	return cmd
 }
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// newExportOpenAPICmd builds `duck export openapi`, which downloads the
+// OpenAPI spec the server serves.
+func newExportOpenAPICmd(client *gen.Client) *cobra.Command {
+	var (
+		out         string
+		specVersion string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Export the server's OpenAPI spec",
+		Long: "Downloads the OpenAPI spec the server serves at /openapi.json. --version pins the export to a " +
+			"spec revision, given as its info.version or its sha256: hash, and fails if the server serves a " +
+			"different one. Unless --no-version-check is set, a warning is printed to stderr when the " +
+			"server's spec differs from the one this CLI was generated from. Without --out the spec is written to stdout.",
+		Example: "  duck export openapi --out spec.json\n" +
+			"  duck export openapi --version 3.0.0 --out spec.json",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := client.BaseURL + "/openapi.json"
+			if specVersion != "" {
+				u += "?" + url.Values{"version": {specVersion}}.Encode()
+			}
+			req, err := http.NewRequestWithContext(cmd.Context(), http.MethodGet, u, nil)
+			if err != nil {
+				return fmt.Errorf("create request: %w", err)
+			}
+			req.Header.Set("Accept", "application/json")
+			resp, err := client.HTTPClient.Do(req)
+			if err != nil {
+				return fmt.Errorf("execute request: %w", err)
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			data, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			var spec struct {
+				Info struct {
+					Version string `json:"version"`
+				} `json:"info"`
+			}
+			if err := json.Unmarshal(data, &spec); err != nil {
+				return fmt.Errorf("parse spec: %w", err)
+			}
+			hash := strings.Trim(resp.Header.Get("ETag"), `"`)
+			server := &serverSpec{Version: spec.Info.Version, Hash: hash}
+			if noVersionCheck, _ := cmd.Flags().GetBool("no-version-check"); !noVersionCheck && hash != "" && specDrift(server) {
+				printSpecDriftWarning(cmd.ErrOrStderr(), server)
+			}
+
+			if out == "" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := os.WriteFile(out, data, 0o644); err != nil { //nolint:gosec // an API spec is meant to be shared
+				return fmt.Errorf("write %s: %w", out, err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return gen.PrintJSON(cmd.OutOrStdout(), map[string]interface{}{
+					"status":  "ok",
+					"version": spec.Info.Version,
+					"hash":    hash,
+					"path":    out,
+				})
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Exported OpenAPI spec %s to %s\n", spec.Info.Version, out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "Path to write the spec to (default stdout)")
+	cmd.Flags().StringVar(&specVersion, "version", "", "Spec revision to require, as info.version or sha256: hash")
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+const testSpec = `{"openapi":"3.0.3","info":{"title":"Duck API","version":"3.0.0"},"paths":{}}`
+
+// newOpenAPISpecServer serves testSpec with hash as its ETag, answering 404
+// to any version other than 3.0.0, and records the requested version.
+func newOpenAPISpecServer(t *testing.T, hash string) (*httptest.Server, *string) {
+	t.Helper()
+	var gotVersion string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/openapi.json", r.URL.Path)
+		gotVersion = r.URL.Query().Get("version")
+		w.Header().Set("Content-Type", "application/json")
+		if gotVersion != "" && gotVersion != "3.0.0" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"spec version \"` + gotVersion + `\" is not served"}`))
+			return
+		}
+		w.Header().Set("ETag", `"`+hash+`"`)
+		_, _ = w.Write([]byte(testSpec))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &gotVersion
+}
+
+func runExportOpenAPI(t *testing.T, srv *httptest.Server, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	t.Setenv(noVersionCheckEnv, "")
+	rootCmd := newTestRootCmd(t, srv)
+	var out, errOut strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+	rootCmd.SetArgs(append([]string{"--host", srv.URL, "export", "openapi"}, args...))
+	err = rootCmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestExportOpenAPICmd_WritesPinnedSpec(t *testing.T) {
+	srv, gotVersion := newOpenAPISpecServer(t, gen.SpecHash)
+	path := filepath.Join(t.TempDir(), "spec.json")
+
+	stdout, stderr, err := runExportOpenAPI(t, srv, "--version", "3.0.0", "--out", path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.0.0", *gotVersion)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, testSpec, string(data))
+	assert.Contains(t, stdout, "Exported OpenAPI spec 3.0.0 to "+path)
+	assert.Empty(t, stderr, "no drift warning when the server serves the CLI's spec")
+}
+
+func TestExportOpenAPICmd_WarnsOnDrift(t *testing.T) {
+	srv, _ := newOpenAPISpecServer(t, "sha256:"+strings.Repeat("0", 64))
+
+	stdout, stderr, err := runExportOpenAPI(t, srv)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, testSpec, stdout)
+	assert.Contains(t, stderr, "warning: the server's API spec 3.0.0 (sha256:000")
+	assert.Contains(t, stderr, describeSpec(gen.SpecVersion, gen.SpecHash))
+}
+
+func TestExportOpenAPICmd_UnservedVersion(t *testing.T) {
+	srv, _ := newOpenAPISpecServer(t, gen.SpecHash)
+
+	_, _, err := runExportOpenAPI(t, srv, "--version", "2.0.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `spec version "2.0.0" is not served`)
+}
@@ -23,7 +23,8 @@ func newFindCmd(client *gen.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "find <query>",
 		Short: "Search the data catalog for schemas, tables, and columns",
-		Long: `Search across all catalog objects (schemas, tables, columns) by name, comment, tag, or property.
+		Long: `Search across all catalog objects (schemas, tables, columns, macros, views, models) by name,
+comment, tag, or property. Comment matches include a snippet with the matched text marked.
 This is designed as the agent's "grep" for the data catalog.`,
 		Example: `  # Search for anything matching "revenue"
   duck find "revenue"
@@ -42,7 +43,7 @@ This is designed as the agent's "grep" for the data catalog.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&objectType, "type", "t", "", "Filter by object type: schema, table, column")
+	cmd.Flags().StringVarP(&objectType, "type", "t", "", "Filter by object type: schema, table, column, macro, view, model")
 	cmd.PersistentFlags().StringVar(&catalog, "catalog", "", "Scope search to a specific catalog")
 	cmd.PersistentFlags().Int64Var(&maxResults, "max-results", 100, "Maximum number of results")
 
@@ -119,6 +120,7 @@ func runFind(cmd *cobra.Command, client *gen.Client, query, objectType, catalog
 			TableName  *string `json:"table_name"`
 			Comment    *string `json:"comment"`
 			MatchField string  `json:"match_field"`
+			Snippet    *string `json:"snippet,omitempty"`
 		} `json:"data"`
 		NextPageToken string `json:"next_page_token"`
 	}
@@ -146,7 +148,7 @@ func runFind(cmd *cobra.Command, client *gen.Client, query, objectType, catalog
 	}
 
 	// Table output
-	columns := []string{"type", "name", "schema", "match"}
+	columns := []string{"type", "name", "schema", "match", "snippet"}
 	rows := make([][]string, 0, len(data.Data))
 	for _, item := range data.Data {
 		schema := ""
@@ -157,7 +159,11 @@ func runFind(cmd *cobra.Command, client *gen.Client, query, objectType, catalog
 		if item.TableName != nil && item.Type == "column" {
 			displayName = *item.TableName + "." + item.Name
 		}
-		rows = append(rows, []string{item.Type, displayName, schema, item.MatchField})
+		snippet := ""
+		if item.Snippet != nil {
+			snippet = *item.Snippet
+		}
+		rows = append(rows, []string{item.Type, displayName, schema, item.MatchField, snippet})
 	}
 	gen.PrintTable(os.Stdout, columns, rows)
 	return nil
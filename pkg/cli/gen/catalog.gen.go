@@ -54,12 +54,260 @@ func newCatalogCmd(client *Client) *cobra.Command {
 	}
 	cmd.AddCommand(volumesCmd)
 
+	// compactTable
+	{
+		c := &cobra.Command{
+			Use:     "compact <schema-name> <table-name>",
+			Short:   "Compact a table's data files",
+			Long:    "Merges the small data files DuckLake accumulates from frequent writes into larger ones and reports the table's live file count and size before and after. The replaced files stay on storage until a vacuum removes them. Only MANAGED tables can be compacted. Requires MODIFY on the table.\n",
+			Example: "duck catalog compact main.analytics.orders",
+			Args:    cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/tables/{tableName}:compact"
+				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
+				urlPath = strings.Replace(urlPath, "{tableName}", args[1], 1)
+				{
+					v, _ := cmd.Flags().GetString("catalog-name")
+					if v != "" {
+						urlPath = strings.Replace(urlPath, "{catalogName}", v, 1)
+					}
+				}
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, nil)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Name of the catalog.")
+		_ = c.MarkFlagRequired("catalog-name")
+
+		// Apply overrides
+		if fn, ok := runOverrides["compactTable"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["compactTable"]; ok {
+			fn(c)
+		}
+		cmd.AddCommand(c)
+	}
+
+	// copyTablePolicies
+	{
+		c := &cobra.Command{
+			Use:     "copy-policies <schema-name> <table-name>",
+			Short:   "Copy a table's policies to another table",
+			Long:    "Copies the table's grants, row filters (with their bindings), column masks (with their bindings) and tags to another table in the same catalog. Column masks and column tags are copied only to columns the target also has; the others are reported as skipped. Policies the target already has are left alone, so a copy can be repeated. Row filters and column masks that apply through tags follow the copied tags. With dry_run nothing is written. Requires admin privileges.\n",
+			Example: "duck catalog copy-policies --from demo.analytics.orders --to demo.analytics.orders_v2 --dry-run\nduck catalog copy-policies --from demo.analytics.orders --to demo.analytics.orders_v2",
+			Args:    cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/tables/{tableName}:copy-policies"
+				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
+				urlPath = strings.Replace(urlPath, "{tableName}", args[1], 1)
+				{
+					v, _ := cmd.Flags().GetString("catalog-name")
+					if v != "" {
+						urlPath = strings.Replace(urlPath, "{catalogName}", v, 1)
+					}
+				}
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+				// Build request body
+				var body interface{}
+				jsonInput, _ := cmd.Flags().GetString("json")
+				if jsonInput != "" {
+					var raw interface{}
+					jsonData := jsonInput
+					if jsonInput == "-" {
+						data, err := os.ReadFile("/dev/stdin")
+						if err != nil {
+							return fmt.Errorf("read stdin: %w", err)
+						}
+						jsonData = string(data)
+					} else if strings.HasPrefix(jsonInput, "@") {
+						data, err := os.ReadFile(jsonInput[1:])
+						if err != nil {
+							return fmt.Errorf("read file: %w", err)
+						}
+						jsonData = string(data)
+					}
+					if err := json.Unmarshal([]byte(jsonData), &raw); err != nil {
+						return fmt.Errorf("parse JSON input: %w", err)
+					}
+					body = raw
+				} else {
+					m := map[string]interface{}{}
+					if cmd.Flags().Changed("dry-run") {
+						v, _ := cmd.Flags().GetBool("dry-run")
+						m["dry_run"] = v
+					}
+					if cmd.Flags().Changed("target-schema") {
+						v, _ := cmd.Flags().GetString("target-schema")
+						m["target_schema"] = v
+					}
+					if cmd.Flags().Changed("target-table") {
+						v, _ := cmd.Flags().GetString("target-table")
+						m["target_table"] = v
+					}
+					body = m
+				}
+				// Validate required body fields when --json is not provided
+				if jsonInput == "" {
+					if !cmd.Flags().Changed("target-schema") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "target-schema")
+					}
+					if !cmd.Flags().Changed("target-table") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "target-table")
+					}
+				}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, body)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Name of the catalog.")
+		_ = c.MarkFlagRequired("catalog-name")
+		c.Flags().Bool("dry-run", false, "Report what would be copied without copying it.")
+		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
+		c.Flags().String("target-schema", "", "Schema of the table the policies are copied to.")
+		c.Flags().String("target-table", "", "Table the policies are copied to.")
+
+		// Apply overrides
+		if fn, ok := runOverrides["copyTablePolicies"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["copyTablePolicies"]; ok {
+			fn(c)
+		}
+		cmd.AddCommand(c)
+	}
+
 	// createSchema
 	{
 		c := &cobra.Command{
 			Use:     "create <name>",
 			Short:   "Create a new schema",
-			Long:    "Creates a new schema in the catalog with the specified name and optional properties.",
+			Long:    "Creates a new schema in the catalog with the specified name and optional properties. With upsert=true, an existing schema of that name has its comment and properties updated instead.",
 			Example: "duck catalog schemas create analytics --comment \"Analytics data schema\"",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -77,6 +325,10 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
 				}
 				query := url.Values{}
+				if cmd.Flags().Changed("upsert") {
+					v, _ := cmd.Flags().GetBool("upsert")
+					query.Set("upsert", fmt.Sprintf("%t", v))
+				}
 				// Build request body
 				var body interface{}
 				jsonInput, _ := cmd.Flags().GetString("json")
@@ -186,6 +438,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
 		c.Flags().String("location-name", "", "Optional external location name to use for schema storage path.")
 		c.Flags().StringSlice("properties", nil, "properties (key=value pairs)")
+		c.Flags().Bool("upsert", false, "When true, a create naming a resource that already exists updates it instead of failing with 409 RESOURCE_EXISTS, and answers 200 with the existing resource and its ID. Only the fields an update can change are applied; the others, such as a table's columns, are left as they are.")
 
 		// Apply overrides
 		if fn, ok := runOverrides["createSchema"]; ok {
@@ -202,8 +455,8 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c := &cobra.Command{
 			Use:     "create <schema-name>",
 			Short:   "Create a new table in a schema",
-			Long:    "Creates a new table with the specified columns and properties within the given schema.",
-			Example: "duck catalog tables create <schema-name> --columns id:BIGINT --columns email:VARCHAR --comment \"User accounts table\" --name users",
+			Long:    "Creates a new table with the specified columns and properties within the given schema. With upsert=true, an existing table of that name has its comment updated instead; its columns are left as they are.",
+			Example: "duck catalog tables create main --name users --columns id:BIGINT --columns email:VARCHAR --comment \"User accounts table\"\nduck catalog tables create analytics --name events --columns id:BIGINT --columns region:VARCHAR --partition-by region",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				outputFlag, _ := cmd.Flags().GetString("output")
@@ -221,6 +474,10 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
 				}
 				query := url.Values{}
+				if cmd.Flags().Changed("upsert") {
+					v, _ := cmd.Flags().GetBool("upsert")
+					query.Set("upsert", fmt.Sprintf("%t", v))
+				}
 				// Build request body
 				var body interface{}
 				jsonInput, _ := cmd.Flags().GetString("json")
@@ -278,6 +535,10 @@ func newCatalogCmd(client *Client) *cobra.Command {
 						v, _ := cmd.Flags().GetString("name")
 						m["name"] = v
 					}
+					if cmd.Flags().Changed("partition-by") {
+						v, _ := cmd.Flags().GetStringSlice("partition-by")
+						m["partition_by"] = v
+					}
 					if cmd.Flags().Changed("source-path") {
 						v, _ := cmd.Flags().GetString("source-path")
 						m["source_path"] = v
@@ -362,8 +623,10 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
 		c.Flags().String("location-name", "", "Name of the external location that owns the source path. Required for EXTERNAL tables.")
 		c.Flags().String("name", "", "Name")
+		c.Flags().StringSlice("partition-by", nil, "Columns to partition a MANAGED table by, in partition key order. Each must be one of the table's columns.")
 		c.Flags().String("source-path", "", "S3/storage path to the data file(s). Required for EXTERNAL tables.")
 		c.Flags().String("table-type", "MANAGED", "Type of table. MANAGED (default) or EXTERNAL. (one of: MANAGED, EXTERNAL)")
+		c.Flags().Bool("upsert", false, "When true, a create naming a resource that already exists updates it instead of failing with 409 RESOURCE_EXISTS, and answers 200 with the existing resource and its ID. Only the fields an update can change are applied; the others, such as a table's columns, are left as they are.")
 
 		// Apply overrides
 		if fn, ok := runOverrides["createTable"]; ok {
@@ -380,7 +643,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c := &cobra.Command{
 			Use:     "create <schema-name>",
 			Short:   "Create a view in a schema",
-			Long:    "Creates a new view with the specified SQL definition within the given schema.",
+			Long:    "Creates a new view with the specified SQL definition within the given schema. The definition must be a single SELECT; it is planned as the caller before the view is stored, and is rejected with 400 if it references a table or column that does not exist, or with 403 if it reads a table the caller cannot access. Set no_validate to store the definition unchecked.",
 			Example: "duck catalog views create <schema-name> --comment \"Active users view\" --name active_users --view-definition \"SELECT * FROM main.users WHERE active = true\"",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -399,6 +662,10 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
 				}
 				query := url.Values{}
+				if cmd.Flags().Changed("no-validate") {
+					v, _ := cmd.Flags().GetBool("no-validate")
+					query.Set("no_validate", fmt.Sprintf("%t", v))
+				}
 				// Build request body
 				var body interface{}
 				jsonInput, _ := cmd.Flags().GetString("json")
@@ -428,10 +695,18 @@ func newCatalogCmd(client *Client) *cobra.Command {
 						v, _ := cmd.Flags().GetString("comment")
 						m["comment"] = v
 					}
+					if cmd.Flags().Changed("materialized") {
+						v, _ := cmd.Flags().GetBool("materialized")
+						m["materialized"] = v
+					}
 					if cmd.Flags().Changed("name") {
 						v, _ := cmd.Flags().GetString("name")
 						m["name"] = v
 					}
+					if cmd.Flags().Changed("refresh-schedule") {
+						v, _ := cmd.Flags().GetString("refresh-schedule")
+						m["refresh_schedule"] = v
+					}
 					if cmd.Flags().Changed("view-definition") {
 						v, _ := cmd.Flags().GetString("view-definition")
 						m["view_definition"] = v
@@ -511,7 +786,10 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		_ = c.MarkFlagRequired("catalog-name")
 		c.Flags().String("comment", "", "Comment")
 		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
+		c.Flags().Bool("materialized", false, "Store the view's result in a table of the same name, which queries read instead of evaluating the definition. The table is populated on creation and on each refresh.\n")
 		c.Flags().String("name", "", "Name")
+		c.Flags().Bool("no-validate", false, "Store the view definition without checking it against the catalog.")
+		c.Flags().String("refresh-schedule", "", "Cron expression on which to refresh a materialized view. Without one it is refreshed only on request.")
 		c.Flags().String("view-definition", "", "View definition")
 
 		// Apply overrides
@@ -683,7 +961,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c := &cobra.Command{
 			Use:     "delete-registration <catalog-name>",
 			Short:   "Delete a catalog registration",
-			Long:    "Removes a catalog registration. Does not delete the underlying data.",
+			Long:    "Removes a catalog registration. Does not delete the underlying data. When delete_catalog is listed in APPROVAL_REQUIRED_OPERATIONS, returns 202 with a pending approval request that another admin must approve.",
 			Example: "duck catalog delete-registration <catalog-name>",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -796,7 +1074,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c := &cobra.Command{
 			Use:     "delete <schema-name> <table-name>",
 			Short:   "Delete a table",
-			Long:    "Permanently removes a table and all its data from the schema.",
+			Long:    "Permanently removes a table and all its data from the schema. A table that views read is not deleted unless cascade is set; the 409 response names the dependent views. With cascade those views are dropped first, which requires MANAGE on their schemas.\n",
 			Example: "duck catalog tables delete <schema-name> <table-name>",
 			Args:    cobra.ExactArgs(2),
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -819,6 +1097,10 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
 				}
 				query := url.Values{}
+				if cmd.Flags().Changed("cascade") {
+					v, _ := cmd.Flags().GetBool("cascade")
+					query.Set("cascade", fmt.Sprintf("%t", v))
+				}
 
 				// Execute request
 				resp, err := client.Do("DELETE", urlPath, query, nil)
@@ -836,6 +1118,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 				return nil
 			},
 		}
+		c.Flags().Bool("cascade", false, "Also drop the views that read the table.")
 		c.Flags().String("catalog-name", "", "Name of the catalog.")
 		_ = c.MarkFlagRequired("catalog-name")
 		c.Flags().Bool("yes", false, "Skip confirmation prompt")
@@ -1542,18 +1825,18 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		volumesCmd.AddCommand(c)
 	}
 
-	// listTableColumns
+	// getTableImpact
 	{
 		c := &cobra.Command{
-			Use:     "list <schema-name> <table-name>",
-			Short:   "List columns of a table",
-			Long:    "Returns a paginated list of all columns defined in the specified table.",
-			Example: "duck catalog columns list <schema-name> <table-name>",
+			Use:     "impact <schema-name> <table-name>",
+			Short:   "Get table reverse impact",
+			Long:    "Returns the views and models that read the table, the objects a drop or rollback of it would affect. View dependencies are found by parsing view definitions, model dependencies from model SQL and sources.\n",
+			Example: "duck catalog tables impact main orders",
 			Args:    cobra.ExactArgs(2),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				outputFlag, _ := cmd.Flags().GetString("output")
 				_ = outputFlag
-				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/tables/{tableName}/columns"
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/tables/{tableName}/impact"
 				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
 				urlPath = strings.Replace(urlPath, "{tableName}", args[1], 1)
 				{
@@ -1567,14 +1850,6 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
 				}
 				query := url.Values{}
-				if cmd.Flags().Changed("max-results") {
-					v, _ := cmd.Flags().GetInt64("max-results")
-					query.Set("max_results", fmt.Sprintf("%d", v))
-				}
-				if cmd.Flags().Changed("page-token") {
-					v, _ := cmd.Flags().GetString("page-token")
-					query.Set("page_token", v)
-				}
 
 				// Execute request
 				resp, err := client.Do("GET", urlPath, query, nil)
@@ -1630,34 +1905,140 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					if err := json.Unmarshal(respBody, &data); err != nil {
 						return fmt.Errorf("parse response: %w", err)
 					}
-					columns := []string{"name", "type", "position", "nullable", "comment"}
-					rows := ExtractRows(data, columns)
-					PrintTable(os.Stdout, columns, rows)
+					PrintDetail(os.Stdout, data)
 				}
 				return nil
 			},
 		}
 		c.Flags().String("catalog-name", "", "Name of the catalog.")
 		_ = c.MarkFlagRequired("catalog-name")
-		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page.")
-		c.Flags().String("page-token", "", "Opaque pagination token from a previous response.")
 
 		// Apply overrides
-		if fn, ok := runOverrides["listTableColumns"]; ok {
+		if fn, ok := runOverrides["getTableImpact"]; ok {
 			c.RunE = fn(client)
 		}
-		if fn, ok := commandOverrides["listTableColumns"]; ok {
+		if fn, ok := commandOverrides["getTableImpact"]; ok {
 			fn(c)
 		}
-		columnsCmd.AddCommand(c)
+		tablesCmd.AddCommand(c)
 	}
 
-	// listCatalogs
+	// listTableColumns
 	{
 		c := &cobra.Command{
-			Use:   "list-registrations",
-			Short: "List all registered catalogs",
-			Long:  "Returns a list of all registered catalogs.",
+			Use:     "list <schema-name> <table-name>",
+			Short:   "List columns of a table",
+			Long:    "Returns a paginated list of all columns defined in the specified table.",
+			Example: "duck catalog columns list <schema-name> <table-name>",
+			Args:    cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/tables/{tableName}/columns"
+				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
+				urlPath = strings.Replace(urlPath, "{tableName}", args[1], 1)
+				{
+					v, _ := cmd.Flags().GetString("catalog-name")
+					if v != "" {
+						urlPath = strings.Replace(urlPath, "{catalogName}", v, 1)
+					}
+				}
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+				if cmd.Flags().Changed("max-results") {
+					v, _ := cmd.Flags().GetInt64("max-results")
+					query.Set("max_results", fmt.Sprintf("%d", v))
+				}
+				if cmd.Flags().Changed("page-token") {
+					v, _ := cmd.Flags().GetString("page-token")
+					query.Set("page_token", v)
+				}
+
+				// Execute request
+				resp, err := client.Do("GET", urlPath, query, nil)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					columns := []string{"name", "type", "position", "nullable", "comment"}
+					rows := ExtractRows(data, columns)
+					PrintTable(os.Stdout, columns, rows)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Name of the catalog.")
+		_ = c.MarkFlagRequired("catalog-name")
+		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page. Defaults to the server's DEFAULT_PAGE_SIZE (100 unless configured). Values above the server's MAX_PAGE_SIZE (1000 unless configured) are clamped to it; values below 1 or above 100000 are rejected with 400.")
+		c.Flags().String("page-token", "", "Opaque pagination token from a previous response.")
+
+		// Apply overrides
+		if fn, ok := runOverrides["listTableColumns"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["listTableColumns"]; ok {
+			fn(c)
+		}
+		columnsCmd.AddCommand(c)
+	}
+
+	// listCatalogs
+	{
+		c := &cobra.Command{
+			Use:   "list-registrations",
+			Short: "List all registered catalogs",
+			Long:  "Returns a list of all registered catalogs.",
 			RunE: func(cmd *cobra.Command, args []string) error {
 				outputFlag, _ := cmd.Flags().GetString("output")
 				_ = outputFlag
@@ -1737,7 +2118,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 				return nil
 			},
 		}
-		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page.")
+		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page. Defaults to the server's DEFAULT_PAGE_SIZE (100 unless configured). Values above the server's MAX_PAGE_SIZE (1000 unless configured) are clamped to it; values below 1 or above 100000 are rejected with 400.")
 		c.Flags().String("page-token", "", "Opaque pagination token from a previous response.")
 
 		// Apply overrides
@@ -1755,7 +2136,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c := &cobra.Command{
 			Use:     "list <catalog-name>",
 			Short:   "List schemas in the catalog",
-			Long:    "Returns a paginated list of all schemas defined in the catalog.",
+			Long:    "Returns a paginated list of all schemas defined in the catalog, optionally only those a principal owns.",
 			Example: "duck catalog schemas list <catalog-name>",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -1772,6 +2153,14 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					v, _ := cmd.Flags().GetInt64("max-results")
 					query.Set("max_results", fmt.Sprintf("%d", v))
 				}
+				if cmd.Flags().Changed("mine") {
+					v, _ := cmd.Flags().GetBool("mine")
+					query.Set("mine", fmt.Sprintf("%t", v))
+				}
+				if cmd.Flags().Changed("owner") {
+					v, _ := cmd.Flags().GetString("owner")
+					query.Set("owner", v)
+				}
 				if cmd.Flags().Changed("page-token") {
 					v, _ := cmd.Flags().GetString("page-token")
 					query.Set("page_token", v)
@@ -1838,7 +2227,9 @@ func newCatalogCmd(client *Client) *cobra.Command {
 				return nil
 			},
 		}
-		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page.")
+		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page. Defaults to the server's DEFAULT_PAGE_SIZE (100 unless configured). Values above the server's MAX_PAGE_SIZE (1000 unless configured) are clamped to it; values below 1 or above 100000 are rejected with 400.")
+		c.Flags().Bool("mine", false, "Only return objects owned by the caller.")
+		c.Flags().String("owner", "", "Only return objects owned by this principal. Ignored when mine is true.")
 		c.Flags().String("page-token", "", "Opaque pagination token from a previous response.")
 
 		// Apply overrides
@@ -1856,7 +2247,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c := &cobra.Command{
 			Use:     "list <schema-name>",
 			Short:   "List tables in a schema",
-			Long:    "Returns a paginated list of all tables within the specified schema.",
+			Long:    "Returns a paginated list of all tables within the specified schema, optionally only those a principal owns.",
 			Example: "duck catalog tables list <schema-name>",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -1879,6 +2270,14 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					v, _ := cmd.Flags().GetInt64("max-results")
 					query.Set("max_results", fmt.Sprintf("%d", v))
 				}
+				if cmd.Flags().Changed("mine") {
+					v, _ := cmd.Flags().GetBool("mine")
+					query.Set("mine", fmt.Sprintf("%t", v))
+				}
+				if cmd.Flags().Changed("owner") {
+					v, _ := cmd.Flags().GetString("owner")
+					query.Set("owner", v)
+				}
 				if cmd.Flags().Changed("page-token") {
 					v, _ := cmd.Flags().GetString("page-token")
 					query.Set("page_token", v)
@@ -1947,7 +2346,9 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		}
 		c.Flags().String("catalog-name", "", "Name of the catalog.")
 		_ = c.MarkFlagRequired("catalog-name")
-		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page.")
+		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page. Defaults to the server's DEFAULT_PAGE_SIZE (100 unless configured). Values above the server's MAX_PAGE_SIZE (1000 unless configured) are clamped to it; values below 1 or above 100000 are rejected with 400.")
+		c.Flags().Bool("mine", false, "Only return objects owned by the caller.")
+		c.Flags().String("owner", "", "Only return objects owned by this principal. Ignored when mine is true.")
 		c.Flags().String("page-token", "", "Opaque pagination token from a previous response.")
 
 		// Apply overrides
@@ -1965,7 +2366,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c := &cobra.Command{
 			Use:     "list <schema-name>",
 			Short:   "List views in a schema",
-			Long:    "Returns a paginated list of all views defined within the specified schema.",
+			Long:    "Returns a paginated list of all views defined within the specified schema, optionally only those a principal owns.",
 			Example: "duck catalog views list <schema-name>",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -1988,6 +2389,14 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					v, _ := cmd.Flags().GetInt64("max-results")
 					query.Set("max_results", fmt.Sprintf("%d", v))
 				}
+				if cmd.Flags().Changed("mine") {
+					v, _ := cmd.Flags().GetBool("mine")
+					query.Set("mine", fmt.Sprintf("%t", v))
+				}
+				if cmd.Flags().Changed("owner") {
+					v, _ := cmd.Flags().GetString("owner")
+					query.Set("owner", v)
+				}
 				if cmd.Flags().Changed("page-token") {
 					v, _ := cmd.Flags().GetString("page-token")
 					query.Set("page_token", v)
@@ -2056,7 +2465,9 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		}
 		c.Flags().String("catalog-name", "", "Name of the catalog.")
 		_ = c.MarkFlagRequired("catalog-name")
-		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page.")
+		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page. Defaults to the server's DEFAULT_PAGE_SIZE (100 unless configured). Values above the server's MAX_PAGE_SIZE (1000 unless configured) are clamped to it; values below 1 or above 100000 are rejected with 400.")
+		c.Flags().Bool("mine", false, "Only return objects owned by the caller.")
+		c.Flags().String("owner", "", "Only return objects owned by this principal. Ignored when mine is true.")
 		c.Flags().String("page-token", "", "Opaque pagination token from a previous response.")
 
 		// Apply overrides
@@ -2074,7 +2485,7 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		c := &cobra.Command{
 			Use:     "list <schema-name>",
 			Short:   "List volumes in a schema",
-			Long:    "Returns a paginated list of all volumes defined within the specified schema.",
+			Long:    "Returns a paginated list of all volumes defined within the specified schema, optionally only those a principal owns.",
 			Example: "duck catalog volumes list <schema-name>",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -2097,6 +2508,14 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					v, _ := cmd.Flags().GetInt64("max-results")
 					query.Set("max_results", fmt.Sprintf("%d", v))
 				}
+				if cmd.Flags().Changed("mine") {
+					v, _ := cmd.Flags().GetBool("mine")
+					query.Set("mine", fmt.Sprintf("%t", v))
+				}
+				if cmd.Flags().Changed("owner") {
+					v, _ := cmd.Flags().GetString("owner")
+					query.Set("owner", v)
+				}
 				if cmd.Flags().Changed("page-token") {
 					v, _ := cmd.Flags().GetString("page-token")
 					query.Set("page_token", v)
@@ -2165,7 +2584,9 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		}
 		c.Flags().String("catalog-name", "", "Name of the catalog.")
 		_ = c.MarkFlagRequired("catalog-name")
-		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page.")
+		c.Flags().Int64("max-results", 100, "Maximum number of results to return per page. Defaults to the server's DEFAULT_PAGE_SIZE (100 unless configured). Values above the server's MAX_PAGE_SIZE (1000 unless configured) are clamped to it; values below 1 or above 100000 are rejected with 400.")
+		c.Flags().Bool("mine", false, "Only return objects owned by the caller.")
+		c.Flags().String("owner", "", "Only return objects owned by this principal. Ignored when mine is true.")
 		c.Flags().String("page-token", "", "Opaque pagination token from a previous response.")
 
 		// Apply overrides
@@ -2205,7 +2626,834 @@ func newCatalogCmd(client *Client) *cobra.Command {
 				query := url.Values{}
 
 				// Execute request
-				resp, err := client.Do("POST", urlPath, query, nil)
+				resp, err := client.Do("POST", urlPath, query, nil)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Name of the catalog.")
+		_ = c.MarkFlagRequired("catalog-name")
+
+		// Apply overrides
+		if fn, ok := runOverrides["profileTable"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["profileTable"]; ok {
+			fn(c)
+		}
+		tablesCmd.AddCommand(c)
+	}
+
+	// refreshView
+	{
+		c := &cobra.Command{
+			Use:     "refresh-view <schema-name> <view-name>",
+			Short:   "Refresh a materialized view",
+			Long:    "Recomputes a materialized view from its definition and replaces the contents of its backing table in one transaction. Grants, row filters and column masks on the view are kept. Only materialized views can be refreshed. Requires MODIFY on the schema and read access to the tables the view selects from.\n",
+			Example: "duck catalog refresh-view main daily_sales\nduck catalog refresh-view main daily_sales --catalog-name analytics",
+			Args:    cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/views/{viewName}:refresh"
+				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
+				urlPath = strings.Replace(urlPath, "{viewName}", args[1], 1)
+				{
+					v, _ := cmd.Flags().GetString("catalog-name")
+					if v != "" {
+						urlPath = strings.Replace(urlPath, "{catalogName}", v, 1)
+					}
+				}
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, nil)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Name of the catalog.")
+		_ = c.MarkFlagRequired("catalog-name")
+
+		// Apply overrides
+		if fn, ok := runOverrides["refreshView"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["refreshView"]; ok {
+			fn(c)
+		}
+		cmd.AddCommand(c)
+	}
+
+	// registerCatalog
+	{
+		c := &cobra.Command{
+			Use:     "register <name>",
+			Short:   "Register a new catalog",
+			Long:    "Registers a new DuckLake catalog with the platform.",
+			Example: "duck catalog register analytics --comment \"Analytics data catalog\" --data-path s3://my-bucket/analytics/ --dsn /data/analytics.db --metastore-type sqlite",
+			Args:    cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/catalogs"
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+				// Build request body
+				var body interface{}
+				jsonInput, _ := cmd.Flags().GetString("json")
+				if jsonInput != "" {
+					var raw interface{}
+					jsonData := jsonInput
+					if jsonInput == "-" {
+						data, err := os.ReadFile("/dev/stdin")
+						if err != nil {
+							return fmt.Errorf("read stdin: %w", err)
+						}
+						jsonData = string(data)
+					} else if strings.HasPrefix(jsonInput, "@") {
+						data, err := os.ReadFile(jsonInput[1:])
+						if err != nil {
+							return fmt.Errorf("read file: %w", err)
+						}
+						jsonData = string(data)
+					}
+					if err := json.Unmarshal([]byte(jsonData), &raw); err != nil {
+						return fmt.Errorf("parse JSON input: %w", err)
+					}
+					body = raw
+				} else {
+					m := map[string]interface{}{}
+					if cmd.Flags().Changed("comment") {
+						v, _ := cmd.Flags().GetString("comment")
+						m["comment"] = v
+					}
+					if cmd.Flags().Changed("data-path") {
+						v, _ := cmd.Flags().GetString("data-path")
+						m["data_path"] = v
+					}
+					if cmd.Flags().Changed("dsn") {
+						v, _ := cmd.Flags().GetString("dsn")
+						m["dsn"] = v
+					}
+					if cmd.Flags().Changed("metastore-type") {
+						v, _ := cmd.Flags().GetString("metastore-type")
+						m["metastore_type"] = v
+					}
+					m["name"] = args[0]
+					body = m
+				}
+				// Validate required body fields when --json is not provided
+				if jsonInput == "" {
+					if !cmd.Flags().Changed("data-path") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "data-path")
+					}
+					if !cmd.Flags().Changed("dsn") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "dsn")
+					}
+					if !cmd.Flags().Changed("metastore-type") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "metastore-type")
+					}
+				}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, body)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("comment", "", "Comment")
+		c.Flags().String("data-path", "", "Data path")
+		c.Flags().String("dsn", "", "Dsn")
+		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
+		c.Flags().String("metastore-type", "", "Metastore type (one of: sqlite, postgres)")
+
+		// Apply overrides
+		if fn, ok := runOverrides["registerCatalog"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["registerCatalog"]; ok {
+			fn(c)
+		}
+		cmd.AddCommand(c)
+	}
+
+	// registerDirectory
+	{
+		c := &cobra.Command{
+			Use:     "register-dir <path>",
+			Short:   "Register a directory of files as external tables",
+			Long:    "Lists the Parquet or CSV files under a storage prefix with the location's credential and registers them as external tables, inferring each table's columns from its files. By default every file directly under the prefix becomes a table named after the file; with table_name a single table reads all files below the prefix, partition directories included. Files whose table cannot be created, for instance because the name is taken, are reported as skipped. Requires CREATE_TABLE on the schema.\n",
+			Example: "duck catalog register-dir s3://landing/orders/ --format parquet --schema-name raw --location-name landing\nduck catalog register-dir s3://landing/events/ --table-name events --schema-name raw --location-name landing",
+			Args:    cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/external-tables:register-directory"
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+				// Build request body
+				var body interface{}
+				jsonInput, _ := cmd.Flags().GetString("json")
+				if jsonInput != "" {
+					var raw interface{}
+					jsonData := jsonInput
+					if jsonInput == "-" {
+						data, err := os.ReadFile("/dev/stdin")
+						if err != nil {
+							return fmt.Errorf("read stdin: %w", err)
+						}
+						jsonData = string(data)
+					} else if strings.HasPrefix(jsonInput, "@") {
+						data, err := os.ReadFile(jsonInput[1:])
+						if err != nil {
+							return fmt.Errorf("read file: %w", err)
+						}
+						jsonData = string(data)
+					}
+					if err := json.Unmarshal([]byte(jsonData), &raw); err != nil {
+						return fmt.Errorf("parse JSON input: %w", err)
+					}
+					body = raw
+				} else {
+					m := map[string]interface{}{}
+					if cmd.Flags().Changed("catalog-name") {
+						v, _ := cmd.Flags().GetString("catalog-name")
+						m["catalog_name"] = v
+					}
+					if cmd.Flags().Changed("file-format") {
+						v, _ := cmd.Flags().GetString("file-format")
+						m["file_format"] = v
+					}
+					if cmd.Flags().Changed("location-name") {
+						v, _ := cmd.Flags().GetString("location-name")
+						m["location_name"] = v
+					}
+					if cmd.Flags().Changed("schema-name") {
+						v, _ := cmd.Flags().GetString("schema-name")
+						m["schema_name"] = v
+					}
+					if cmd.Flags().Changed("table-name") {
+						v, _ := cmd.Flags().GetString("table-name")
+						m["table_name"] = v
+					}
+					m["path"] = args[0]
+					body = m
+				}
+				// Validate required body fields when --json is not provided
+				if jsonInput == "" {
+					if !cmd.Flags().Changed("location-name") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "location-name")
+					}
+					if !cmd.Flags().Changed("schema-name") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "schema-name")
+					}
+				}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, body)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Catalog to register the tables in. Defaults to the default catalog.")
+		c.Flags().String("file-format", "parquet", "Format of the files to register. (one of: parquet, csv)")
+		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
+		c.Flags().String("location-name", "", "External location whose credential is used to list and read the files.")
+		c.Flags().String("schema-name", "", "Schema name")
+		c.Flags().String("table-name", "", "Register one table reading every matching file below the path, with hive-style partition directories as columns. Without it each file directly under the path becomes a table named after the file.\n")
+
+		// Apply overrides
+		if fn, ok := runOverrides["registerDirectory"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["registerDirectory"]; ok {
+			fn(c)
+		}
+		cmd.AddCommand(c)
+	}
+
+	// renameTable
+	{
+		c := &cobra.Command{
+			Use:     "rename <schema-name> <table-name>",
+			Short:   "Rename a table",
+			Long:    "Renames a table within its schema. Grants, tags, row filters and column masks key on the table's ID and carry over unchanged; comments, column metadata, statistics and lineage move to the new name. Views that read the table block the rename unless force is set, because their SQL still uses the old name. Schemas cannot be renamed, as DuckLake does not support altering them. Requires MANAGE on the table.\n",
+			Example: "duck catalog rename main.analytics.orders purchases\nduck catalog rename main.analytics.daily_sales daily_revenue --force",
+			Args:    cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/tables/{tableName}:rename"
+				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
+				urlPath = strings.Replace(urlPath, "{tableName}", args[1], 1)
+				{
+					v, _ := cmd.Flags().GetString("catalog-name")
+					if v != "" {
+						urlPath = strings.Replace(urlPath, "{catalogName}", v, 1)
+					}
+				}
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+				// Build request body
+				var body interface{}
+				jsonInput, _ := cmd.Flags().GetString("json")
+				if jsonInput != "" {
+					var raw interface{}
+					jsonData := jsonInput
+					if jsonInput == "-" {
+						data, err := os.ReadFile("/dev/stdin")
+						if err != nil {
+							return fmt.Errorf("read stdin: %w", err)
+						}
+						jsonData = string(data)
+					} else if strings.HasPrefix(jsonInput, "@") {
+						data, err := os.ReadFile(jsonInput[1:])
+						if err != nil {
+							return fmt.Errorf("read file: %w", err)
+						}
+						jsonData = string(data)
+					}
+					if err := json.Unmarshal([]byte(jsonData), &raw); err != nil {
+						return fmt.Errorf("parse JSON input: %w", err)
+					}
+					body = raw
+				} else {
+					m := map[string]interface{}{}
+					if cmd.Flags().Changed("force") {
+						v, _ := cmd.Flags().GetBool("force")
+						m["force"] = v
+					}
+					if cmd.Flags().Changed("new-name") {
+						v, _ := cmd.Flags().GetString("new-name")
+						m["new_name"] = v
+					}
+					body = m
+				}
+				// Validate required body fields when --json is not provided
+				if jsonInput == "" {
+					if !cmd.Flags().Changed("new-name") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "new-name")
+					}
+				}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, body)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Name of the catalog.")
+		_ = c.MarkFlagRequired("catalog-name")
+		c.Flags().Bool("force", false, "Rename even though views read the object. Their definitions are not rewritten and keep referring to the old name until updated.\n")
+		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
+		c.Flags().String("new-name", "", "New name")
+
+		// Apply overrides
+		if fn, ok := runOverrides["renameTable"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["renameTable"]; ok {
+			fn(c)
+		}
+		cmd.AddCommand(c)
+	}
+
+	// renameView
+	{
+		c := &cobra.Command{
+			Use:     "rename-view <schema-name> <view-name>",
+			Short:   "Rename a view",
+			Long:    "Renames a view within its schema, together with the backing table of a materialized view. Grants and tags on the view carry over. Other views that read it block the rename unless force is set. Requires MANAGE on the schema.\n",
+			Example: "duck catalog rename-view main daily_sales --new-name daily_revenue",
+			Args:    cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/views/{viewName}:rename"
+				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
+				urlPath = strings.Replace(urlPath, "{viewName}", args[1], 1)
+				{
+					v, _ := cmd.Flags().GetString("catalog-name")
+					if v != "" {
+						urlPath = strings.Replace(urlPath, "{catalogName}", v, 1)
+					}
+				}
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+				// Build request body
+				var body interface{}
+				jsonInput, _ := cmd.Flags().GetString("json")
+				if jsonInput != "" {
+					var raw interface{}
+					jsonData := jsonInput
+					if jsonInput == "-" {
+						data, err := os.ReadFile("/dev/stdin")
+						if err != nil {
+							return fmt.Errorf("read stdin: %w", err)
+						}
+						jsonData = string(data)
+					} else if strings.HasPrefix(jsonInput, "@") {
+						data, err := os.ReadFile(jsonInput[1:])
+						if err != nil {
+							return fmt.Errorf("read file: %w", err)
+						}
+						jsonData = string(data)
+					}
+					if err := json.Unmarshal([]byte(jsonData), &raw); err != nil {
+						return fmt.Errorf("parse JSON input: %w", err)
+					}
+					body = raw
+				} else {
+					m := map[string]interface{}{}
+					if cmd.Flags().Changed("force") {
+						v, _ := cmd.Flags().GetBool("force")
+						m["force"] = v
+					}
+					if cmd.Flags().Changed("new-name") {
+						v, _ := cmd.Flags().GetString("new-name")
+						m["new_name"] = v
+					}
+					body = m
+				}
+				// Validate required body fields when --json is not provided
+				if jsonInput == "" {
+					if !cmd.Flags().Changed("new-name") {
+						return fmt.Errorf("required flag %q not set (or use --json)", "new-name")
+					}
+				}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, body)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Name of the catalog.")
+		_ = c.MarkFlagRequired("catalog-name")
+		c.Flags().Bool("force", false, "Rename even though views read the object. Their definitions are not rewritten and keep referring to the old name until updated.\n")
+		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
+		c.Flags().String("new-name", "", "New name")
+
+		// Apply overrides
+		if fn, ok := runOverrides["renameView"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["renameView"]; ok {
+			fn(c)
+		}
+		cmd.AddCommand(c)
+	}
+
+	// rollbackTable
+	{
+		c := &cobra.Command{
+			Use:     "rollback <schema-name> <table-name>",
+			Short:   "Roll a table back to an earlier snapshot",
+			Long:    "Restores a MANAGED table's data to a DuckLake snapshot, given by snapshot ID or timestamp. Without confirm the rollback is only previewed: the response reports the row counts and the views and models that read the table without changing any data. A confirmed rollback writes a new snapshot, so it can itself be rolled back. Only data is restored; the table's columns must be unchanged since the target snapshot. Requires MANAGE on the table.\n",
+			Example: "duck catalog rollback main.analytics.orders --to-snapshot 42 --dry-run\nduck catalog rollback main.analytics.orders --to-snapshot 42\nduck catalog rollback main.analytics.orders --to-timestamp 2025-01-15T10:30:00Z --yes",
+			Args:    cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				if !cmd.Flags().Changed("yes") {
+					if !ConfirmPrompt("Are you sure?") {
+						return nil
+					}
+				}
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/tables/{tableName}:rollback"
+				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
+				urlPath = strings.Replace(urlPath, "{tableName}", args[1], 1)
+				{
+					v, _ := cmd.Flags().GetString("catalog-name")
+					if v != "" {
+						urlPath = strings.Replace(urlPath, "{catalogName}", v, 1)
+					}
+				}
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+				// Build request body
+				var body interface{}
+				jsonInput, _ := cmd.Flags().GetString("json")
+				if jsonInput != "" {
+					var raw interface{}
+					jsonData := jsonInput
+					if jsonInput == "-" {
+						data, err := os.ReadFile("/dev/stdin")
+						if err != nil {
+							return fmt.Errorf("read stdin: %w", err)
+						}
+						jsonData = string(data)
+					} else if strings.HasPrefix(jsonInput, "@") {
+						data, err := os.ReadFile(jsonInput[1:])
+						if err != nil {
+							return fmt.Errorf("read file: %w", err)
+						}
+						jsonData = string(data)
+					}
+					if err := json.Unmarshal([]byte(jsonData), &raw); err != nil {
+						return fmt.Errorf("parse JSON input: %w", err)
+					}
+					body = raw
+				} else {
+					m := map[string]interface{}{}
+					if cmd.Flags().Changed("confirm") {
+						v, _ := cmd.Flags().GetBool("confirm")
+						m["confirm"] = v
+					}
+					if cmd.Flags().Changed("snapshot-id") {
+						v, _ := cmd.Flags().GetInt64("snapshot-id")
+						m["snapshot_id"] = v
+					}
+					if cmd.Flags().Changed("timestamp") {
+						v, _ := cmd.Flags().GetString("timestamp")
+						m["timestamp"] = v
+					}
+					body = m
+				}
+				// Validate required body fields when --json is not provided
+				if jsonInput == "" {
+				}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, body)
 				if err != nil {
 					return err
 				}
@@ -2265,29 +3513,35 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		}
 		c.Flags().String("catalog-name", "", "Name of the catalog.")
 		_ = c.MarkFlagRequired("catalog-name")
+		c.Flags().Bool("confirm", false, "Apply the rollback. When false the rollback is only previewed.")
+		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
+		c.Flags().Int64("snapshot-id", 0, "DuckLake snapshot to restore the table's data to.")
+		c.Flags().String("timestamp", "", "Restore the table's data as of this time, using the latest snapshot taken at or before it.")
+		c.Flags().Bool("yes", false, "Skip confirmation prompt")
 
 		// Apply overrides
-		if fn, ok := runOverrides["profileTable"]; ok {
+		if fn, ok := runOverrides["rollbackTable"]; ok {
 			c.RunE = fn(client)
 		}
-		if fn, ok := commandOverrides["profileTable"]; ok {
+		if fn, ok := commandOverrides["rollbackTable"]; ok {
 			fn(c)
 		}
-		tablesCmd.AddCommand(c)
+		cmd.AddCommand(c)
 	}
 
-	// registerCatalog
+	// setDefaultCatalog
 	{
 		c := &cobra.Command{
-			Use:     "register <name>",
-			Short:   "Register a new catalog",
-			Long:    "Registers a new DuckLake catalog with the platform.",
-			Example: "duck catalog register analytics --comment \"Analytics data catalog\" --data-path s3://my-bucket/analytics/ --dsn /data/analytics.db --metastore-type sqlite",
+			Use:     "set-default <catalog-name>",
+			Short:   "Set a catalog as the default",
+			Long:    "Sets the specified catalog as the default catalog for the platform.",
+			Example: "duck catalog set-default <catalog-name>",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				outputFlag, _ := cmd.Flags().GetString("output")
 				_ = outputFlag
-				urlPath := "/catalogs"
+				urlPath := "/catalogs/{catalogName}/set-default"
+				urlPath = strings.Replace(urlPath, "{catalogName}", args[0], 1)
 
 				if strings.Contains(urlPath, "{") {
 					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
@@ -2318,36 +3572,10 @@ func newCatalogCmd(client *Client) *cobra.Command {
 					body = raw
 				} else {
 					m := map[string]interface{}{}
-					if cmd.Flags().Changed("comment") {
-						v, _ := cmd.Flags().GetString("comment")
-						m["comment"] = v
-					}
-					if cmd.Flags().Changed("data-path") {
-						v, _ := cmd.Flags().GetString("data-path")
-						m["data_path"] = v
-					}
-					if cmd.Flags().Changed("dsn") {
-						v, _ := cmd.Flags().GetString("dsn")
-						m["dsn"] = v
-					}
-					if cmd.Flags().Changed("metastore-type") {
-						v, _ := cmd.Flags().GetString("metastore-type")
-						m["metastore_type"] = v
-					}
-					m["name"] = args[0]
 					body = m
 				}
 				// Validate required body fields when --json is not provided
 				if jsonInput == "" {
-					if !cmd.Flags().Changed("data-path") {
-						return fmt.Errorf("required flag %q not set (or use --json)", "data-path")
-					}
-					if !cmd.Flags().Changed("dsn") {
-						return fmt.Errorf("required flag %q not set (or use --json)", "dsn")
-					}
-					if !cmd.Flags().Changed("metastore-type") {
-						return fmt.Errorf("required flag %q not set (or use --json)", "metastore-type")
-					}
 				}
 
 				// Execute request
@@ -2409,73 +3637,39 @@ func newCatalogCmd(client *Client) *cobra.Command {
 				return nil
 			},
 		}
-		c.Flags().String("comment", "", "Comment")
-		c.Flags().String("data-path", "", "Data path")
-		c.Flags().String("dsn", "", "Dsn")
 		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
-		c.Flags().String("metastore-type", "", "Metastore type (one of: sqlite, postgres)")
 
 		// Apply overrides
-		if fn, ok := runOverrides["registerCatalog"]; ok {
+		if fn, ok := runOverrides["setDefaultCatalog"]; ok {
 			c.RunE = fn(client)
 		}
-		if fn, ok := commandOverrides["registerCatalog"]; ok {
+		if fn, ok := commandOverrides["setDefaultCatalog"]; ok {
 			fn(c)
 		}
 		cmd.AddCommand(c)
 	}
 
-	// setDefaultCatalog
+	// getCatalogStats
 	{
 		c := &cobra.Command{
-			Use:     "set-default <catalog-name>",
-			Short:   "Set a catalog as the default",
-			Long:    "Sets the specified catalog as the default catalog for the platform.",
-			Example: "duck catalog set-default <catalog-name>",
+			Use:     "stats <catalog-name>",
+			Short:   "Get catalog statistics",
+			Long:    "Returns the catalog's schema, table and view counts, its total rows and bytes, and its largest tables. Sizes come from the statistics DuckLake stores for each table, so no data is scanned.",
+			Example: "duck catalog stats lake\nduck catalog stats lake -o json",
 			Args:    cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				outputFlag, _ := cmd.Flags().GetString("output")
 				_ = outputFlag
-				urlPath := "/catalogs/{catalogName}/set-default"
+				urlPath := "/catalogs/{catalogName}/stats"
 				urlPath = strings.Replace(urlPath, "{catalogName}", args[0], 1)
 
 				if strings.Contains(urlPath, "{") {
 					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
 				}
 				query := url.Values{}
-				// Build request body
-				var body interface{}
-				jsonInput, _ := cmd.Flags().GetString("json")
-				if jsonInput != "" {
-					var raw interface{}
-					jsonData := jsonInput
-					if jsonInput == "-" {
-						data, err := os.ReadFile("/dev/stdin")
-						if err != nil {
-							return fmt.Errorf("read stdin: %w", err)
-						}
-						jsonData = string(data)
-					} else if strings.HasPrefix(jsonInput, "@") {
-						data, err := os.ReadFile(jsonInput[1:])
-						if err != nil {
-							return fmt.Errorf("read file: %w", err)
-						}
-						jsonData = string(data)
-					}
-					if err := json.Unmarshal([]byte(jsonData), &raw); err != nil {
-						return fmt.Errorf("parse JSON input: %w", err)
-					}
-					body = raw
-				} else {
-					m := map[string]interface{}{}
-					body = m
-				}
-				// Validate required body fields when --json is not provided
-				if jsonInput == "" {
-				}
 
 				// Execute request
-				resp, err := client.Do("POST", urlPath, query, body)
+				resp, err := client.Do("GET", urlPath, query, nil)
 				if err != nil {
 					return err
 				}
@@ -2533,13 +3727,12 @@ func newCatalogCmd(client *Client) *cobra.Command {
 				return nil
 			},
 		}
-		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
 
 		// Apply overrides
-		if fn, ok := runOverrides["setDefaultCatalog"]; ok {
+		if fn, ok := runOverrides["getCatalogStats"]; ok {
 			c.RunE = fn(client)
 		}
-		if fn, ok := commandOverrides["setDefaultCatalog"]; ok {
+		if fn, ok := commandOverrides["getCatalogStats"]; ok {
 			fn(c)
 		}
 		cmd.AddCommand(c)
@@ -3390,5 +4583,139 @@ func newCatalogCmd(client *Client) *cobra.Command {
 		volumesCmd.AddCommand(c)
 	}
 
+	// vacuumTable
+	{
+		c := &cobra.Command{
+			Use:     "vacuum <schema-name> <table-name>",
+			Short:   "Expire old snapshots and delete unreferenced files",
+			Long:    "Expires DuckLake snapshots older than older_than and deletes the data files only those snapshots referenced, then reports the table's stored files and size before and after. Snapshots are catalog-wide, so time travel to the expired snapshots is lost for every table in the catalog. Requires MANAGE on the catalog.\n",
+			Example: "duck catalog vacuum main.analytics.orders\nduck catalog vacuum main.analytics.orders --older-than 30d",
+			Args:    cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				outputFlag, _ := cmd.Flags().GetString("output")
+				_ = outputFlag
+				urlPath := "/catalogs/{catalogName}/schemas/{schemaName}/tables/{tableName}:vacuum"
+				urlPath = strings.Replace(urlPath, "{schemaName}", args[0], 1)
+				urlPath = strings.Replace(urlPath, "{tableName}", args[1], 1)
+				{
+					v, _ := cmd.Flags().GetString("catalog-name")
+					if v != "" {
+						urlPath = strings.Replace(urlPath, "{catalogName}", v, 1)
+					}
+				}
+
+				if strings.Contains(urlPath, "{") {
+					return fmt.Errorf("unresolved path parameter in URL: %s", urlPath)
+				}
+				query := url.Values{}
+				// Build request body
+				var body interface{}
+				jsonInput, _ := cmd.Flags().GetString("json")
+				if jsonInput != "" {
+					var raw interface{}
+					jsonData := jsonInput
+					if jsonInput == "-" {
+						data, err := os.ReadFile("/dev/stdin")
+						if err != nil {
+							return fmt.Errorf("read stdin: %w", err)
+						}
+						jsonData = string(data)
+					} else if strings.HasPrefix(jsonInput, "@") {
+						data, err := os.ReadFile(jsonInput[1:])
+						if err != nil {
+							return fmt.Errorf("read file: %w", err)
+						}
+						jsonData = string(data)
+					}
+					if err := json.Unmarshal([]byte(jsonData), &raw); err != nil {
+						return fmt.Errorf("parse JSON input: %w", err)
+					}
+					body = raw
+				} else {
+					m := map[string]interface{}{}
+					if cmd.Flags().Changed("older-than") {
+						v, _ := cmd.Flags().GetString("older-than")
+						m["older_than"] = v
+					}
+					body = m
+				}
+				// Validate required body fields when --json is not provided
+				if jsonInput == "" {
+				}
+
+				// Execute request
+				resp, err := client.Do("POST", urlPath, query, body)
+				if err != nil {
+					return err
+				}
+				if err := CheckError(resp); err != nil {
+					return err
+				}
+				respBody, err := ReadBody(resp)
+				if err != nil {
+					return fmt.Errorf("read response: %w", err)
+				}
+
+				// Handle --quiet
+				quiet, _ := cmd.Root().PersistentFlags().GetBool("quiet")
+				if quiet {
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err == nil {
+						// Handle paginated list responses ({"data": [...]})
+						if items, ok := data["data"].([]interface{}); ok {
+							for _, item := range items {
+								if m, ok := item.(map[string]interface{}); ok {
+									for _, key := range []string{"id", "name", "key"} {
+										if v, ok := m[key]; ok {
+											fmt.Fprintln(os.Stdout, v)
+											break
+										}
+									}
+								}
+							}
+							return nil
+						}
+						// Handle single resource responses
+						for _, key := range []string{"id", "name", "key"} {
+							if v, ok := data[key]; ok {
+								fmt.Fprintln(os.Stdout, v)
+								return nil
+							}
+						}
+					}
+					fmt.Fprintln(os.Stdout, string(respBody))
+					return nil
+				}
+
+				switch OutputFormat(outputFlag) {
+				case OutputJSON:
+					var pretty interface{}
+					json.Unmarshal(respBody, &pretty)
+					return PrintJSON(os.Stdout, pretty)
+				default:
+					var data map[string]interface{}
+					if err := json.Unmarshal(respBody, &data); err != nil {
+						return fmt.Errorf("parse response: %w", err)
+					}
+					PrintDetail(os.Stdout, data)
+				}
+				return nil
+			},
+		}
+		c.Flags().String("catalog-name", "", "Name of the catalog.")
+		_ = c.MarkFlagRequired("catalog-name")
+		c.Flags().String("json", "", "JSON input (raw string or @filename or - for stdin)")
+		c.Flags().String("older-than", "7d", "Expire snapshots older than this age, such as 7d or 36h.")
+
+		// Apply overrides
+		if fn, ok := runOverrides["vacuumTable"]; ok {
+			c.RunE = fn(client)
+		}
+		if fn, ok := commandOverrides["vacuumTable"]; ok {
+			fn(c)
+		}
+		cmd.AddCommand(c)
+	}
+
 	return cmd
 }
@@ -273,6 +273,382 @@ func TestDo_ConnectionRefused(t *testing.T) {
 	assert.Contains(t, err.Error(), "execute request")
 }
 
+// === Retries ===
+
+// flakyServer fails with status until the given attempt, then succeeds. It
+// records the body of every request it receives.
+func flakyServer(t *testing.T, succeedOn int, status int, header http.Header) (*httptest.Server, *[]string) {
+	t.Helper()
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		n := len(bodies)
+		mu.Unlock()
+		if n < succeedOn {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func newRetryClient(baseURL string) *Client {
+	c := NewClient(baseURL, "", "")
+	c.RetryBaseDelay = time.Millisecond
+	return c
+}
+
+func TestNewClient_RetryDefaults(t *testing.T) {
+	c := NewClient("http://localhost:8080", "", "")
+	assert.Equal(t, DefaultMaxRetries, c.MaxRetries)
+	assert.Positive(t, c.RetryBaseDelay)
+}
+
+func TestDo_RetriesGETUntilSuccess(t *testing.T) {
+	srv, bodies := flakyServer(t, 3, http.StatusServiceUnavailable, nil)
+
+	c := newRetryClient(srv.URL)
+	resp, err := c.Do(http.MethodGet, "/schemas", nil, nil)
+	require.NoError(t, err)
+	body, err := ReadBody(resp)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"ok":true}`, string(body))
+	assert.Len(t, *bodies, 3)
+}
+
+func TestDo_RetriesResendBody(t *testing.T) {
+	srv, bodies := flakyServer(t, 3, http.StatusBadGateway, nil)
+
+	c := newRetryClient(srv.URL)
+	resp, err := c.Do(http.MethodPut, "/groups/g1/members", nil, map[string]string{"name": "x"})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.Len(t, *bodies, 3)
+	for _, b := range *bodies {
+		assert.JSONEq(t, `{"name":"x"}`, b)
+	}
+}
+
+func TestDo_RetriesExhausted(t *testing.T) {
+	srv, bodies := flakyServer(t, 10, http.StatusInternalServerError, nil)
+
+	c := newRetryClient(srv.URL)
+	resp, err := c.Do(http.MethodDelete, "/schemas/s1", nil, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Len(t, *bodies, 1+DefaultMaxRetries)
+}
+
+func TestDo_RetriesDisabled(t *testing.T) {
+	srv, bodies := flakyServer(t, 3, http.StatusServiceUnavailable, nil)
+
+	c := newRetryClient(srv.URL)
+	c.MaxRetries = 0
+	resp, err := c.Do(http.MethodGet, "/schemas", nil, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestDo_DoesNotRetryPOST(t *testing.T) {
+	srv, bodies := flakyServer(t, 3, http.StatusServiceUnavailable, nil)
+
+	c := newRetryClient(srv.URL)
+	resp, err := c.Do(http.MethodPost, "/schemas", nil, map[string]string{"name": "s"})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestDoIdempotent_RetriesPOST(t *testing.T) {
+	srv, bodies := flakyServer(t, 3, http.StatusServiceUnavailable, nil)
+
+	c := newRetryClient(srv.URL)
+	resp, err := c.DoIdempotent(http.MethodPost, "/query", nil, map[string]string{"sql": "SELECT 1"})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, *bodies, 3)
+}
+
+func TestDoWithIdempotencyKey_RetriesPOSTWithSameKey(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		n := len(keys)
+		mu.Unlock()
+		if n < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newRetryClient(srv.URL)
+	resp, err := c.DoWithIdempotencyKey(http.MethodPost, "/principals", nil, map[string]string{"name": "alice"}, "key-1")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, []string{"key-1", "key-1"}, keys)
+}
+
+func TestDo_DoesNotRetryClientErrors(t *testing.T) {
+	srv, bodies := flakyServer(t, 3, http.StatusNotFound, nil)
+
+	c := newRetryClient(srv.URL)
+	resp, err := c.Do(http.MethodGet, "/schemas/missing", nil, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestDo_RetriesTooManyRequestsHonoringRetryAfter(t *testing.T) {
+	srv, bodies := flakyServer(t, 2, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}})
+
+	c := newRetryClient(srv.URL)
+	start := time.Now()
+	resp, err := c.Do(http.MethodGet, "/schemas", nil, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, *bodies, 2)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "Retry-After should override the shorter backoff")
+}
+
+func TestDo_RetriesConnectionErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.URL
+	srv.Close()
+
+	var attempts int
+	c := newRetryClient(addr)
+	c.HTTPClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	_, err := c.Do(http.MethodGet, "/schemas", nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, 1+DefaultMaxRetries, attempts)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"absent", "", 0, false},
+		{"seconds", "3", 3 * time.Second, true},
+		{"capped", "3600", maxRetryDelay, true},
+		{"past date", "Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+		{"garbage", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			got, ok := retryAfter(resp)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBackoff_DoublesAndCaps(t *testing.T) {
+	c := &Client{RetryBaseDelay: 100 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, c.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, c.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, c.backoff(3))
+	assert.Equal(t, maxRetryDelay, c.backoff(20))
+}
+
+// === Verbose tracing ===
+
+func TestDo_VerboseRedactsCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc123")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"k1","secret":"sk-live-raw","next_page_token":"p2"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var trace strings.Builder
+	c := NewClient(srv.URL, "my-api-key", "my-jwt-token")
+	c.Verbose = 1
+	c.TraceWriter = &trace
+
+	resp, err := c.Do(http.MethodPost, "/api-keys", nil, map[string]interface{}{
+		"name":     "ci",
+		"password": "hunter2",
+		"nested":   map[string]string{"client_secret": "cs-raw"},
+	})
+	require.NoError(t, err)
+	body, err := ReadBody(resp)
+	require.NoError(t, err)
+
+	out := trace.String()
+	assert.Contains(t, out, "> POST "+srv.URL+"/v1/api-keys")
+	assert.Contains(t, out, "> Authorization: [REDACTED]")
+	assert.Contains(t, out, "< HTTP/1.1 201 Created")
+	assert.Contains(t, out, "< Set-Cookie: [REDACTED]")
+	assert.Contains(t, out, `"name": "ci"`)
+	assert.Contains(t, out, `"next_page_token": "p2"`, "page tokens are not credentials")
+	for _, secret := range []string{"my-jwt-token", "hunter2", "cs-raw", "sk-live-raw", "abc123"} {
+		assert.NotContains(t, out, secret)
+	}
+
+	// The caller still sees the untouched response body.
+	assert.Contains(t, string(body), "sk-live-raw")
+}
+
+func TestDo_VerboseRedactsAPIKeyHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	var trace strings.Builder
+	c := NewClient(srv.URL, "my-api-key", "")
+	c.Verbose = 1
+	c.TraceWriter = &trace
+
+	resp, err := c.Do(http.MethodGet, "/schemas", nil, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Contains(t, trace.String(), "> X-Api-Key: [REDACTED]")
+	assert.NotContains(t, trace.String(), "my-api-key")
+}
+
+func TestDo_VeryVerboseLogsRetriesAndTiming(t *testing.T) {
+	srv, _ := flakyServer(t, 2, http.StatusServiceUnavailable, nil)
+
+	var trace strings.Builder
+	c := newRetryClient(srv.URL)
+	c.Verbose = 2
+	c.TraceWriter = &trace
+
+	resp, err := c.Do(http.MethodGet, "/schemas", nil, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	out := trace.String()
+	assert.Contains(t, out, "< HTTP/1.1 503 Service Unavailable")
+	assert.Contains(t, out, "* Retrying in")
+	assert.Contains(t, out, "(attempt 2 of 3)")
+	assert.Contains(t, out, "* Timing: dns=")
+	assert.Contains(t, out, "first_byte=")
+}
+
+func TestDo_VerboseOffByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	var trace strings.Builder
+	c := NewClient(srv.URL, "", "tok")
+	c.TraceWriter = &trace
+
+	resp, err := c.Do(http.MethodGet, "/schemas", nil, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Empty(t, trace.String())
+}
+
+func TestDo_VerboseTruncatesLargeBodies(t *testing.T) {
+	large := strings.Repeat("x", maxTraceBody+100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(large))
+	}))
+	t.Cleanup(srv.Close)
+
+	var trace strings.Builder
+	c := NewClient(srv.URL, "", "")
+	c.Verbose = 1
+	c.TraceWriter = &trace
+
+	resp, err := c.Do(http.MethodGet, "/schemas", nil, nil)
+	require.NoError(t, err)
+	body, err := ReadBody(resp)
+	require.NoError(t, err)
+
+	assert.Len(t, body, len(large))
+	assert.Contains(t, trace.String(), "... (100 more bytes)")
+}
+
+// === BeforeFirstRequest ===
+
+func TestDo_BeforeFirstRequestRunsOnce(t *testing.T) {
+	var paths []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient(srv.URL, "", "")
+	hookRuns := 0
+	c.BeforeFirstRequest = func() {
+		hookRuns++
+		// A request sent by the hook itself must not run the hook again.
+		resp, err := c.Do(http.MethodGet, "/probe", nil, nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	for range 3 {
+		resp, err := c.Do(http.MethodGet, "/items", nil, nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	assert.Equal(t, 1, hookRuns)
+	assert.Equal(t, []string{"/v1/probe", "/v1/items", "/v1/items", "/v1/items"}, paths)
+}
+
 // === CheckError ===
 
 func TestCheckError_SuccessRange(t *testing.T) {
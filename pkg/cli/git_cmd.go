@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// gitSyncResult is the syncGitRepo response.
+type gitSyncResult struct {
+	NotebooksCreated int    `json:"notebooks_created"`
+	NotebooksUpdated int    `json:"notebooks_updated"`
+	NotebooksDeleted int    `json:"notebooks_deleted"`
+	CommitSHA        string `json:"commit_sha"`
+	Ref              string `json:"ref"`
+	DryRun           bool   `json:"dry_run"`
+	Changes          []struct {
+		Action   string `json:"action"`
+		Notebook string `json:"notebook"`
+		Path     string `json:"path"`
+	} `json:"changes"`
+}
+
+// newGitCmd builds `duck git`, which holds commands that act on registered
+// Git repositories.
+func newGitCmd(client *gen.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "git",
+		Short: "Sync notebooks from registered Git repositories",
+	}
+	cmd.AddCommand(newGitSyncCmd(client))
+	return cmd
+}
+
+func newGitSyncCmd(client *gen.Client) *cobra.Command {
+	var (
+		ref    string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync <repo>",
+		Short: "Sync notebooks from a Git repository",
+		Long: "Checks out a registered Git repository on the server and reconciles the Notebook manifests under " +
+			"its path with the notebooks previously synced from it, creating, updating and deleting notebooks. " +
+			"<repo> is the repository ID or URL. Use --dry-run to see the changes without applying them.",
+		Example: "  duck git sync https://github.com/org/analytics.git\n" +
+			"  duck git sync 550e8400-e29b-41d4-a716-446655440000 --ref release-1.2 --dry-run",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := resolveGitRepoArg(client, args[0])
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			if ref != "" {
+				query.Set("ref", ref)
+			}
+			if dryRun {
+				query.Set("dry_run", "true")
+			}
+			resp, err := client.Do("POST", "/git-repos/"+url.PathEscape(id)+"/sync", query, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			body, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+
+			if getOutputFormat(cmd) == "json" {
+				var pretty interface{}
+				if err := json.Unmarshal(body, &pretty); err != nil {
+					return fmt.Errorf("parse response: %w", err)
+				}
+				return gen.PrintJSON(os.Stdout, pretty)
+			}
+
+			var result gitSyncResult
+			if err := json.Unmarshal(body, &result); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			printGitSyncResult(cmd, result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&ref, "ref", "", "Branch, tag or commit to sync (default: the repository's branch)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes without applying them")
+
+	return cmd
+}
+
+func printGitSyncResult(cmd *cobra.Command, result gitSyncResult) {
+	w := cmd.OutOrStdout()
+	commit := result.CommitSHA
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	if len(result.Changes) > 0 {
+		rows := make([][]string, 0, len(result.Changes))
+		for _, c := range result.Changes {
+			rows = append(rows, []string{c.Action, c.Notebook, c.Path})
+		}
+		gen.PrintTable(w, []string{"ACTION", "NOTEBOOK", "PATH"}, rows)
+		_, _ = fmt.Fprintln(w)
+	}
+	summary := fmt.Sprintf("%d created, %d updated, %d deleted", result.NotebooksCreated, result.NotebooksUpdated, result.NotebooksDeleted)
+	switch {
+	case len(result.Changes) == 0:
+		_, _ = fmt.Fprintf(w, "Notebooks are up to date with %s (%s)\n", result.Ref, commit)
+	case result.DryRun:
+		_, _ = fmt.Fprintf(w, "Dry run of %s (%s): %s. Nothing was changed.\n", result.Ref, commit, summary)
+	default:
+		_, _ = fmt.Fprintf(w, "Synced %s (%s): %s\n", result.Ref, commit, summary)
+	}
+}
+
+// resolveGitRepoArg returns arg unchanged when it looks like a UUID and
+// otherwise looks up the registered repository with that URL.
+func resolveGitRepoArg(client *gen.Client, arg string) (string, error) {
+	if isLikelyUUID(arg) {
+		return arg, nil
+	}
+
+	var (
+		matches   []string
+		pageToken string
+	)
+	for {
+		q := url.Values{}
+		q.Set("max_results", strconv.Itoa(100))
+		if pageToken != "" {
+			q.Set("page_token", pageToken)
+		}
+		resp, err := client.Do("GET", "/git-repos", q, nil)
+		if err != nil {
+			return "", fmt.Errorf("list git repos: %w", err)
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return "", fmt.Errorf("list git repos: %w", err)
+		}
+		body, err := gen.ReadBody(resp)
+		if err != nil {
+			return "", fmt.Errorf("read git repos response: %w", err)
+		}
+		var page struct {
+			Data []struct {
+				ID  string `json:"id"`
+				URL string `json:"url"`
+			} `json:"data"`
+			NextPageToken string `json:"next_page_token"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", fmt.Errorf("parse git repos response: %w", err)
+		}
+		for _, r := range page.Data {
+			if strings.TrimSuffix(r.URL, "/") == strings.TrimSuffix(arg, "/") {
+				matches = append(matches, r.ID)
+			}
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("git repo %q not found", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d git repos are registered with URL %q; pass the repo ID instead", len(matches), arg)
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitSyncCmd_ResolvesURLAndPassesOptions(t *testing.T) {
+	var syncs []*url.URL
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/git-repos":
+			if r.URL.Query().Get("page_token") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"data":            []map[string]string{{"id": "repo-1", "url": "https://example.com/other.git"}},
+					"next_page_token": "p2",
+				})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]string{{"id": "repo-2", "url": "https://example.com/analytics.git"}},
+			})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/sync"):
+			syncs = append(syncs, r.URL)
+			_, _ = w.Write([]byte(`{
+  "notebooks_created": 1, "notebooks_updated": 1, "notebooks_deleted": 0,
+  "commit_sha": "0123456789abcdef0123", "ref": "feature", "dry_run": true,
+  "changes": [
+    {"action": "create", "notebook": "kpi", "path": "analytics/notebooks/kpi.yaml"},
+    {"action": "update", "notebook": "churn", "path": "analytics/notebooks/churn.yaml"}
+  ]
+}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "git", "sync", "https://example.com/analytics.git", "--ref", "feature", "--dry-run"})
+	require.NoError(t, rootCmd.Execute())
+
+	require.Len(t, syncs, 1)
+	assert.Equal(t, "/v1/git-repos/repo-2/sync", syncs[0].Path)
+	assert.Equal(t, "feature", syncs[0].Query().Get("ref"))
+	assert.Equal(t, "true", syncs[0].Query().Get("dry_run"))
+
+	assert.Contains(t, out.String(), "analytics/notebooks/kpi.yaml")
+	assert.Contains(t, out.String(), "Dry run of feature (0123456789ab): 1 created, 1 updated, 0 deleted. Nothing was changed.")
+}
+
+func TestGitSyncCmd_UnknownURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "git", "sync", "https://example.com/missing.git"})
+	require.ErrorContains(t, rootCmd.Execute(), `git repo "https://example.com/missing.git" not found`)
+}
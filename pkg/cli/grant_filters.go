@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// addGrantFilterFlags gives `security grants list` --principal (name or ID)
+// and --securable (<type>:<id>) shorthands for access reviews. They expand
+// into the generated principal-id/principal-type and
+// securable-type/securable-id filters before the request is sent.
+func addGrantFilterFlags(root *cobra.Command, client *gen.Client) {
+	c, _, err := root.Find([]string{"security", "grants", "list"})
+	if err != nil || c == root {
+		return
+	}
+	c.Flags().String("principal", "", "Filter by user principal name or ID")
+	c.Flags().String("securable", "", "Filter by securable as <type>:<id> (e.g. table:<uuid>)")
+	c.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return expandGrantFilterFlags(cmd, func(arg string) (string, error) {
+			return resolvePrincipalArg(client, arg)
+		})
+	}
+}
+
+// expandGrantFilterFlags rewrites --principal and --securable into the
+// underlying query filter flags. Explicit low-level flags win over the
+// shorthands.
+func expandGrantFilterFlags(cmd *cobra.Command, resolve func(string) (string, error)) error {
+	flags := cmd.Flags()
+	if principal, _ := flags.GetString("principal"); principal != "" {
+		if flags.Changed("principal-id") {
+			return fmt.Errorf("--principal and --principal-id are mutually exclusive")
+		}
+		id, err := resolve(principal)
+		if err != nil {
+			return err
+		}
+		if err := flags.Set("principal-id", id); err != nil {
+			return err
+		}
+		if !flags.Changed("principal-type") {
+			if err := flags.Set("principal-type", "user"); err != nil {
+				return err
+			}
+		}
+	}
+	if securable, _ := flags.GetString("securable"); securable != "" {
+		if flags.Changed("securable-type") || flags.Changed("securable-id") {
+			return fmt.Errorf("--securable and --securable-type/--securable-id are mutually exclusive")
+		}
+		typ, id, ok := strings.Cut(securable, ":")
+		if !ok || typ == "" || id == "" {
+			return fmt.Errorf("--securable must be <type>:<id>, got %q", securable)
+		}
+		if err := flags.Set("securable-type", typ); err != nil {
+			return err
+		}
+		if err := flags.Set("securable-id", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGrantListTestCmd() *cobra.Command {
+	c := &cobra.Command{Use: "list"}
+	for _, name := range []string{"principal", "securable", "principal-id", "principal-type", "securable-type", "securable-id"} {
+		c.Flags().String(name, "", "")
+	}
+	return c
+}
+
+func TestExpandGrantFilterFlags(t *testing.T) {
+	resolve := func(name string) (string, error) { return "id-of-" + name, nil }
+
+	t.Run("principal_name_resolves_to_user_filter", func(t *testing.T) {
+		c := newGrantListTestCmd()
+		require.NoError(t, c.Flags().Set("principal", "alice"))
+		require.NoError(t, expandGrantFilterFlags(c, resolve))
+
+		id, _ := c.Flags().GetString("principal-id")
+		typ, _ := c.Flags().GetString("principal-type")
+		assert.Equal(t, "id-of-alice", id)
+		assert.Equal(t, "user", typ)
+	})
+
+	t.Run("explicit_principal_type_kept", func(t *testing.T) {
+		c := newGrantListTestCmd()
+		require.NoError(t, c.Flags().Set("principal", "analysts"))
+		require.NoError(t, c.Flags().Set("principal-type", "group"))
+		require.NoError(t, expandGrantFilterFlags(c, resolve))
+
+		typ, _ := c.Flags().GetString("principal-type")
+		assert.Equal(t, "group", typ)
+	})
+
+	t.Run("securable_splits_type_and_id", func(t *testing.T) {
+		c := newGrantListTestCmd()
+		require.NoError(t, c.Flags().Set("securable", "table:abc"))
+		require.NoError(t, expandGrantFilterFlags(c, resolve))
+
+		typ, _ := c.Flags().GetString("securable-type")
+		id, _ := c.Flags().GetString("securable-id")
+		assert.Equal(t, "table", typ)
+		assert.Equal(t, "abc", id)
+	})
+
+	t.Run("malformed_securable_rejected", func(t *testing.T) {
+		c := newGrantListTestCmd()
+		require.NoError(t, c.Flags().Set("securable", "table"))
+		assert.ErrorContains(t, expandGrantFilterFlags(c, resolve), "<type>:<id>")
+	})
+
+	t.Run("shorthand_conflicts_with_explicit_flag", func(t *testing.T) {
+		c := newGrantListTestCmd()
+		require.NoError(t, c.Flags().Set("principal", "alice"))
+		require.NoError(t, c.Flags().Set("principal-id", "x"))
+		assert.ErrorContains(t, expandGrantFilterFlags(c, resolve), "mutually exclusive")
+	})
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// Override listGroupMembers to accept a group name and render member
+	// names and kinds instead of bare IDs.
+	gen.RegisterRunOverride("listGroupMembers", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			id, err := resolveGroupArg(client, args[0])
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			if cmd.Flags().Changed("max-results") {
+				v, _ := cmd.Flags().GetInt64("max-results")
+				query.Set("max_results", fmt.Sprintf("%d", v))
+			}
+			if cmd.Flags().Changed("page-token") {
+				v, _ := cmd.Flags().GetString("page-token")
+				query.Set("page_token", v)
+			}
+
+			resp, err := client.Do("GET", "/groups/"+url.PathEscape(id)+"/members", query, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			respBody, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+
+			outputFlag, _ := cmd.Flags().GetString("output")
+			if gen.OutputFormat(outputFlag) == gen.OutputJSON {
+				var pretty interface{}
+				if err := json.Unmarshal(respBody, &pretty); err != nil {
+					return fmt.Errorf("parse response: %w", err)
+				}
+				return gen.PrintJSON(os.Stdout, pretty)
+			}
+
+			var page groupMembersPage
+			if err := json.Unmarshal(respBody, &page); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			return printGroupMembers(os.Stdout, page)
+		}
+	})
+}
+
+// groupMembersPage is one page of the listGroupMembers response.
+type groupMembersPage struct {
+	Data []struct {
+		MemberID   string `json:"member_id"`
+		MemberType string `json:"member_type"`
+		MemberName string `json:"member_name"`
+		MemberKind string `json:"member_kind"`
+	} `json:"data"`
+	NextPageToken string `json:"next_page_token"`
+}
+
+// memberKindLabel returns the display label for a member kind, falling back
+// to the stored member type when the server did not resolve one.
+func memberKindLabel(kind, memberType string) string {
+	switch kind {
+	case "user":
+		return "user"
+	case "service_principal":
+		return "service principal"
+	case "group":
+		return "nested group"
+	}
+	if memberType == "group" {
+		return "nested group"
+	}
+	return memberType
+}
+
+// printGroupMembers renders a page of group members as a table, followed by
+// a hint for fetching the next page when there is one.
+func printGroupMembers(w io.Writer, page groupMembersPage) error {
+	rows := make([][]string, 0, len(page.Data))
+	for _, m := range page.Data {
+		name := m.MemberName
+		if name == "" {
+			name = "(unknown)"
+		}
+		rows = append(rows, []string{name, memberKindLabel(m.MemberKind, m.MemberType), m.MemberID})
+	}
+	gen.PrintTable(w, []string{"name", "type", "id"}, rows)
+
+	if page.NextPageToken != "" {
+		if _, err := fmt.Fprintf(w, "\nMore members available: --page-token %s\n", page.NextPageToken); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupMembersOverride_MixedMemberTypes(t *testing.T) {
+	const groupID = "550e8400-e29b-41d4-a716-446655440001"
+
+	var gotPaths, gotQueries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		gotQueries = append(gotQueries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/groups":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]interface{}{{"id": groupID, "name": "engineering"}},
+			})
+		case "/v1/groups/" + groupID + "/members":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]interface{}{
+					{"group_id": groupID, "member_type": "user", "member_id": "u-1", "member_name": "alice", "member_kind": "user"},
+					{"group_id": groupID, "member_type": "user", "member_id": "sp-1", "member_name": "etl-bot", "member_kind": "service_principal"},
+					{"group_id": groupID, "member_type": "group", "member_id": "g-2", "member_name": "data-science", "member_kind": "group"},
+				},
+				"next_page_token": "tok-2",
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	getOutput := captureStdout(t)
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"--host", srv.URL, "security", "groups", "members", "engineering", "--max-results", "3"})
+	err := rootCmd.Execute()
+	out := getOutput()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/v1/groups", "/v1/groups/" + groupID + "/members"}, gotPaths)
+	assert.Equal(t, "max_results=3", gotQueries[1])
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.GreaterOrEqual(t, len(lines), 4)
+	assert.True(t, containsIgnoreCase(lines[0], "name"))
+	assert.Regexp(t, `alice\s+user\s+u-1`, lines[1])
+	assert.Regexp(t, `etl-bot\s+service principal\s+sp-1`, lines[2])
+	assert.Regexp(t, `data-science\s+nested group\s+g-2`, lines[3])
+	assert.Contains(t, out, "--page-token tok-2")
+}
+
+func TestMemberKindLabel(t *testing.T) {
+	tests := []struct {
+		kind, memberType, want string
+	}{
+		{"user", "user", "user"},
+		{"service_principal", "user", "service principal"},
+		{"group", "group", "nested group"},
+		{"", "group", "nested group"},
+		{"", "user", "user"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, memberKindLabel(tt.kind, tt.memberType))
+	}
+}
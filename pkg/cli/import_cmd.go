@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/internal/declarative"
+	"duck-demo/pkg/cli/gen"
+)
+
+// newImportCmd builds `duck catalog import <dir>`, the disaster-recovery
+// counterpart to export. Unlike apply it is additive (never deletes) and
+// applies each dependency tier all-or-nothing.
+func newImportCmd(client *gen.Client) *cobra.Command {
+	var (
+		autoApprove        bool
+		noColor            bool
+		allowUnknownFields bool
+		force              bool
+		lockTTL            time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <dir>",
+		Short: "Import an exported declarative snapshot into the server",
+		Long: "Loads declarative manifests, checks that every reference resolves to a resource in the snapshot " +
+			"or on the server, and creates or updates resources tier by tier. A failure rolls back its tier " +
+			"and stops the import; resources on the server that are not in the snapshot are left untouched.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			isJSON := getOutputFormat(cmd) == "json"
+
+			desired, err := declarative.LoadDirectoryWithOptions(args[0], declarative.LoadOptions{
+				AllowUnknownFields: allowUnknownFields,
+			})
+			if err != nil {
+				return fmt.Errorf("load snapshot: %w", err)
+			}
+
+			stateClient := NewAPIStateClient(client)
+			lease, err := stateClient.AcquireApplyLock(cmd.Context(), applyLockOwner(), lockTTL, force)
+			switch {
+			case errors.Is(err, errApplyLockUnsupported):
+				_, _ = fmt.Fprintf(os.Stderr, "warning: %v; importing without a lock\n", err)
+			case err != nil:
+				return err
+			}
+			defer func() {
+				if lease == nil {
+					return
+				}
+				if err := stateClient.ReleaseApplyLock(cmd.Context(), lease.LeaseID); err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+				}
+			}()
+
+			actual, err := stateClient.ReadState(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("read server state: %w", err)
+			}
+
+			// Refuse to start unless every reference resolves.
+			if validationErrs := declarative.ValidateImport(desired, actual); len(validationErrs) > 0 {
+				msgs := make([]string, len(validationErrs))
+				for i, ve := range validationErrs {
+					msgs[i] = ve.Error()
+				}
+				if isJSON {
+					_ = gen.PrintJSON(os.Stdout, map[string]interface{}{"status": "error", "errors": msgs})
+				}
+				return fmt.Errorf("snapshot has %d unresolved reference(s) or validation error(s):\n  - %s",
+					len(msgs), strings.Join(msgs, "\n  - "))
+			}
+
+			plan := declarative.ImportPlan(desired, actual)
+			if len(plan.Errors) > 0 {
+				declarative.FormatText(os.Stderr, plan, noColor)
+				return fmt.Errorf("snapshot cannot be imported: %d plan error(s)", len(plan.Errors))
+			}
+			if len(plan.Actions) == 0 {
+				if isJSON {
+					return gen.PrintJSON(os.Stdout, map[string]interface{}{"status": "ok", "changes": false})
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Server already matches the snapshot.")
+				return nil
+			}
+
+			if !isJSON {
+				declarative.FormatText(os.Stdout, plan, noColor)
+			}
+			if !autoApprove {
+				if !gen.IsStdinTTY() {
+					return fmt.Errorf("confirmation required but stdin is not a terminal; use --auto-approve")
+				}
+				_, _ = fmt.Fprint(os.Stdout, "\nImport these changes? [y/N] ")
+				answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+				if err != nil {
+					return fmt.Errorf("read confirmation: %w", err)
+				}
+				answer = strings.TrimSpace(strings.ToLower(answer))
+				if answer != "y" && answer != "yes" {
+					_, _ = fmt.Fprintln(os.Stdout, "Import cancelled.")
+					return nil
+				}
+			}
+
+			if err := stateClient.ValidateApplyCapabilities(cmd.Context(), plan.Actions); err != nil {
+				return fmt.Errorf("preflight capability validation: %w", err)
+			}
+
+			results, applyErr := declarative.ApplyTiers(cmd.Context(), stateClient, plan.Tiers())
+			printImportResults(cmd, isJSON, results, applyErr)
+			if applyErr != nil {
+				return fmt.Errorf("import stopped: %w", applyErr)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&autoApprove, "auto-approve", false, "Skip interactive confirmation prompt")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	cmd.Flags().BoolVar(&allowUnknownFields, "allow-unknown-fields", false, "Allow unknown YAML fields in declarative config")
+	cmd.Flags().BoolVar(&force, "force", false, "Break an apply lock held by another (e.g. crashed) apply")
+	cmd.Flags().DurationVar(&lockTTL, "lock-ttl", 10*time.Minute, "Lease duration of the server-side apply lock")
+
+	return cmd
+}
+
+// printImportResults reports per-tier outcomes of an import.
+func printImportResults(cmd *cobra.Command, isJSON bool, results []declarative.TierResult, applyErr error) {
+	type tierOut struct {
+		Layer        int      `json:"layer"`
+		Applied      int      `json:"applied"`
+		RolledBack   int      `json:"rolled_back"`
+		Failed       string   `json:"failed,omitempty"`
+		Error        string   `json:"error,omitempty"`
+		RollbackErrs []string `json:"rollback_errors,omitempty"`
+	}
+	tiers := make([]tierOut, len(results))
+	applied := 0
+	for i, r := range results {
+		t := tierOut{Layer: r.Layer, Applied: len(r.Applied), RolledBack: len(r.RolledBack)}
+		if r.Failed != nil {
+			t.Failed = fmt.Sprintf("%s %s %q", r.Failed.Operation, r.Failed.ResourceKind, r.Failed.ResourceName)
+			t.Error = r.Err.Error()
+		}
+		for _, e := range r.RollbackErrs {
+			t.RollbackErrs = append(t.RollbackErrs, e.Error())
+		}
+		tiers[i] = t
+		applied += len(r.Applied)
+	}
+
+	if isJSON {
+		status := "ok"
+		if applyErr != nil {
+			status = "failed"
+		}
+		_ = gen.PrintJSON(os.Stdout, map[string]interface{}{
+			"status":  status,
+			"changes": true,
+			"applied": applied,
+			"tiers":   tiers,
+		})
+		return
+	}
+
+	out := cmd.OutOrStdout()
+	for _, t := range tiers {
+		if t.Failed == "" {
+			_, _ = fmt.Fprintf(out, "  tier %d: %d applied\n", t.Layer, t.Applied)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "  tier %d: failed at %s: %s; rolled back %d\n", t.Layer, t.Failed, t.Error, t.RolledBack)
+		for _, e := range t.RollbackErrs {
+			_, _ = fmt.Fprintf(out, "    rollback failed: %s\n", e)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\nImport complete: %d applied across %d tier(s).\n", applied, len(tiers))
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// modelLineage mirrors the API's LineageNode for a model.
+type modelLineage struct {
+	TableName  string             `json:"table_name"`
+	Upstream   []modelLineageEdge `json:"upstream"`
+	Downstream []modelLineageEdge `json:"downstream"`
+}
+
+type modelLineageEdge struct {
+	SourceTable string `json:"source_table"`
+	TargetTable string `json:"target_table"`
+	EdgeType    string `json:"edge_type"`
+}
+
+// newModelsLineageCmd builds `duck models lineage <project.model>`, which shows
+// the models and tables a model reads from and the tables built from it.
+func newModelsLineageCmd(client *gen.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lineage <project.model>",
+		Short: "Show upstream and downstream lineage of a model",
+		Long: "Lists the lineage edges of a model. Upstream edges are the models and source tables the " +
+			"model's SQL references (edge type MODEL, registered when the model is created or updated) and " +
+			"the relations its compiled SQL read during runs (READ). Downstream edges are the models and " +
+			"queries that read from it.",
+		Example: "  duck models lineage sales.stg_orders\n" +
+			"  duck models lineage sales.stg_orders -o json",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project, model, err := parseModelRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.Do(http.MethodGet, "/models/"+url.PathEscape(project)+"/"+url.PathEscape(model), nil, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return fmt.Errorf("model %s: %w", args[0], err)
+			}
+			_ = resp.Body.Close()
+
+			resp, err = client.Do(http.MethodGet, "/lineage/tables/"+url.PathEscape(project)+"/"+url.PathEscape(model), nil, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			raw, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), raw)
+			}
+			var node modelLineage
+			if err := json.Unmarshal(raw, &node); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			gen.PrintTable(cmd.OutOrStdout(), []string{"DIRECTION", "TABLE", "TYPE"}, modelLineageRows(&node))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// parseModelRef splits a "<project>.<model>" argument.
+func parseModelRef(arg string) (project, model string, err error) {
+	project, model, ok := strings.Cut(arg, ".")
+	if !ok || project == "" || model == "" {
+		return "", "", fmt.Errorf("model must be given as <project>.<model>, got %q", arg)
+	}
+	return project, model, nil
+}
+
+// modelLineageRows renders upstream edges before downstream ones, dropping
+// the duplicates repeated runs record for the same relation.
+func modelLineageRows(node *modelLineage) [][]string {
+	seen := make(map[string]bool)
+	var rows [][]string
+	add := func(direction, table, edgeType string) {
+		key := direction + "\x00" + table + "\x00" + edgeType
+		if table == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		rows = append(rows, []string{direction, table, edgeType})
+	}
+	for _, e := range node.Upstream {
+		add("upstream", e.SourceTable, e.EdgeType)
+	}
+	for _, e := range node.Downstream {
+		add("downstream", e.TargetTable, e.EdgeType)
+	}
+	return rows
+}
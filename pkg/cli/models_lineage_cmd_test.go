@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newModelLineageServer serves model sales.stg_orders, which reads raw.orders
+// and feeds sales.fct_orders.
+func newModelLineageServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/models/sales/stg_orders":
+			_, _ = w.Write([]byte(`{"project_name":"sales","name":"stg_orders"}`))
+		case "/v1/lineage/tables/sales/stg_orders":
+			_, _ = w.Write([]byte(`{"table_name":"sales.stg_orders",` +
+				`"upstream":[{"source_table":"raw.orders","target_table":"sales.stg_orders","edge_type":"MODEL"},` +
+				`{"source_table":"raw.orders","target_table":"sales.stg_orders","edge_type":"MODEL"}],` +
+				`"downstream":[{"source_table":"sales.stg_orders","target_table":"sales.fct_orders","edge_type":"MODEL"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestModelsLineageCmd_Table(t *testing.T) {
+	srv := newModelLineageServer(t)
+
+	out := runCatalogTree(t, srv, "models", "lineage", "sales.stg_orders")
+
+	assert.Regexp(t, `DIRECTION\s+TABLE\s+TYPE`, out)
+	assert.Regexp(t, `upstream\s+raw\.orders\s+MODEL`, out)
+	assert.Regexp(t, `downstream\s+sales\.fct_orders\s+MODEL`, out)
+	assert.Equal(t, 1, strings.Count(out, "raw.orders"), "duplicate edges are shown once")
+}
+
+func TestModelsLineageCmd_JSON(t *testing.T) {
+	srv := newModelLineageServer(t)
+
+	out := runCatalogTree(t, srv, "--output", "json", "models", "lineage", "sales.stg_orders")
+
+	assert.Contains(t, out, `"table_name": "sales.stg_orders"`)
+}
+
+func TestModelsLineageCmd_Errors(t *testing.T) {
+	srv := newModelLineageServer(t)
+
+	for _, arg := range []string{"stg_orders", "sales.missing"} {
+		t.Run(arg, func(t *testing.T) {
+			rootCmd := newTestRootCmd(t, srv)
+			rootCmd.SetArgs([]string{"--host", srv.URL, "models", "lineage", arg})
+			require.Error(t, rootCmd.Execute())
+		})
+	}
+}
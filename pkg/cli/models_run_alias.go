@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// addModelRunDeprecatedAlias keeps `models model-runs trigger-model-run`, the
+// name `models run` had before it moved, working as a hidden, deprecated
+// command so existing scripts do not break.
+func addModelRunDeprecatedAlias(root *cobra.Command) {
+	run, _, err := root.Find([]string{"models", "run"})
+	if err != nil || run.Name() != "run" {
+		return
+	}
+	parent, _, err := root.Find([]string{"models", "model-runs"})
+	if err != nil || parent.Name() != "model-runs" {
+		parent = &cobra.Command{Use: "model-runs", Short: "Manage model-runs", Hidden: true}
+		run.Parent().AddCommand(parent)
+	}
+
+	old := &cobra.Command{
+		Use:        "trigger-model-run",
+		Short:      run.Short,
+		Args:       run.Args,
+		Hidden:     true,
+		Deprecated: `use "duck models run" instead`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run.RunE(cmd, args)
+		},
+	}
+	old.Flags().AddFlagSet(run.Flags())
+	parent.AddCommand(old)
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelsRun_DeprecatedTriggerModelRunStillWorks(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(jsonHandler(rec, http.StatusAccepted, `{"id":"run-1","status":"PENDING"}`))
+	t.Cleanup(srv.Close)
+
+	for _, args := range [][]string{
+		{"models", "run"},
+		{"models", "model-runs", "trigger-model-run"},
+	} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			rootCmd := newTestRootCmd(t, srv)
+			var out, errOut strings.Builder
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&errOut)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL, "--output", "json"}, append(args, "--project-name", "sales", "--full-refresh")...))
+			require.NoError(t, rootCmd.Execute())
+
+			req := rec.last()
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "/v1/model-runs", req.Path)
+			assert.Contains(t, req.Body, `"project_name":"sales"`)
+			assert.Contains(t, req.Body, `"full_refresh":true`)
+			if args[1] == "model-runs" {
+				assert.Contains(t, out.String()+errOut.String(), `use "duck models run" instead`)
+			}
+		})
+	}
+
+	rootCmd := newTestRootCmd(t, srv)
+	old, _, err := rootCmd.Find([]string{"models", "model-runs", "trigger-model-run"})
+	require.NoError(t, err)
+	assert.True(t, old.Hidden)
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// modelRunStep is the subset of a ModelRunStep the run history prints.
+type modelRunStep struct {
+	RunID        string  `json:"run_id"`
+	Status       string  `json:"status"`
+	StartedAt    *string `json:"started_at,omitempty"`
+	FinishedAt   *string `json:"finished_at,omitempty"`
+	DurationMs   *int64  `json:"duration_ms,omitempty"`
+	RowsAffected *int64  `json:"rows_affected,omitempty"`
+	BytesWritten *int64  `json:"bytes_written,omitempty"`
+	ErrorMessage *string `json:"error_message,omitempty"`
+}
+
+// newModelsRunsCmd builds `duck models runs <project.model>`, which lists the
+// recorded runs of a single model, newest first.
+func newModelsRunsCmd(client *gen.Client) *cobra.Command {
+	var limit int64
+
+	cmd := &cobra.Command{
+		Use:   "runs <project.model>",
+		Short: "List the run history of a model",
+		Long: "Lists the steps recorded for a model across model runs, newest first, with status, " +
+			"start and finish time, duration, rows affected, bytes written and error message. " +
+			"Bytes are only known for tables in DuckLake catalogs.",
+		Example: "  duck models runs sales.stg_orders\n" +
+			"  duck models runs sales.stg_orders --limit 5 -o json",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project, model, err := parseModelRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			if limit > 0 {
+				query.Set("max_results", strconv.FormatInt(limit, 10))
+			}
+			resp, err := client.Do(http.MethodGet, "/models/"+url.PathEscape(project)+"/"+url.PathEscape(model)+"/runs", query, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return fmt.Errorf("model %s: %w", args[0], err)
+			}
+			raw, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), raw)
+			}
+			var page struct {
+				Data []modelRunStep `json:"data"`
+			}
+			if err := json.Unmarshal(raw, &page); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			rows := make([][]string, 0, len(page.Data))
+			for _, st := range page.Data {
+				rows = append(rows, modelRunStepRow(st))
+			}
+			gen.PrintTable(cmd.OutOrStdout(),
+				[]string{"RUN", "STATUS", "STARTED", "FINISHED", "DURATION", "ROWS", "BYTES", "ERROR"}, rows)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64Var(&limit, "limit", 20, "Maximum number of runs to show")
+	return cmd
+}
+
+func modelRunStepRow(st modelRunStep) []string {
+	str := func(v *string) string {
+		if v == nil || *v == "" {
+			return "-"
+		}
+		return *v
+	}
+	num := func(v *int64) string {
+		if v == nil {
+			return "-"
+		}
+		return strconv.FormatInt(*v, 10)
+	}
+	duration := "-"
+	if st.DurationMs != nil {
+		duration = fmt.Sprintf("%dms", *st.DurationMs)
+	}
+	return []string{st.RunID, st.Status, str(st.StartedAt), str(st.FinishedAt), duration,
+		num(st.RowsAffected), num(st.BytesWritten), str(st.ErrorMessage)}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelsRunsCmd_Table(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v1/models/sales/stg_orders/runs" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"model sales.missing not found"}`))
+			return
+		}
+		gotQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{"data":[` +
+			`{"run_id":"run-2","status":"FAILED","started_at":"2026-02-16T10:05:00Z","duration_ms":12,"error_message":"boom"},` +
+			`{"run_id":"run-1","status":"SUCCESS","started_at":"2026-02-16T10:00:00Z","finished_at":"2026-02-16T10:00:04Z",` +
+			`"duration_ms":3812,"rows_affected":1500,"bytes_written":48213}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	out := runCatalogTree(t, srv, "models", "runs", "sales.stg_orders", "--limit", "5")
+
+	assert.Equal(t, "max_results=5", gotQuery)
+	assert.Regexp(t, `RUN\s+STATUS\s+STARTED\s+FINISHED\s+DURATION\s+ROWS\s+BYTES\s+ERROR`, out)
+	assert.Regexp(t, `run-2\s+FAILED\s+2026-02-16T10:05:00Z\s+-\s+12ms\s+-\s+-\s+boom`, out)
+	assert.Regexp(t, `run-1\s+SUCCESS\s+2026-02-16T10:00:00Z\s+2026-02-16T10:00:04Z\s+3812ms\s+1500\s+48213\s+-`, out)
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "models", "runs", "sales.missing"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sales.missing")
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// newModelsTestCmd builds `duck models test <project.model>`, which runs a
+// model's tests against its materialized relation without rebuilding it.
+func newModelsTestCmd(client *gen.Client) *cobra.Command {
+	var (
+		targetCatalog string
+		targetSchema  string
+		storeFailures bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "test <project.model>",
+		Short: "Run the tests of a model",
+		Long: "Runs all tests defined on a model against its materialized relation and prints one line per " +
+			"test. With --store-failures the rows each failing test returns are appended to a " +
+			"<model>_test_failures table next to the model, so they can be inspected with SQL. Rows older " +
+			"than the server's retention period (MODEL_TEST_FAILURES_TTL) are dropped from that table on " +
+			"each write. Exits with an error when any test fails.",
+		Example: "  duck models test sales.stg_orders\n" +
+			"  duck models test sales.stg_orders --store-failures\n" +
+			"  duck models test sales.stg_orders --target-schema sales_dev -o json",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project, model, err := parseModelRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{"store_failures": storeFailures}
+			if targetCatalog != "" {
+				body["target_catalog"] = targetCatalog
+			}
+			if targetSchema != "" {
+				body["target_schema"] = targetSchema
+			}
+			resp, err := client.Do(http.MethodPost, "/models/"+url.PathEscape(project)+"/"+url.PathEscape(model)+"/test-runs", nil, body)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return fmt.Errorf("model %s: %w", args[0], err)
+			}
+			raw, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			var run struct {
+				Results       []modelTestResult `json:"results"`
+				FailuresTable string            `json:"failures_table"`
+			}
+			if err := json.Unmarshal(raw, &run); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+
+			failed := 0
+			rows := make([][]string, 0, len(run.Results))
+			for _, r := range run.Results {
+				if r.Status != "PASS" {
+					failed++
+				}
+				failing, errMsg := "-", "-"
+				if r.RowsReturned != nil {
+					failing = strconv.FormatInt(*r.RowsReturned, 10)
+				}
+				if r.ErrorMessage != nil && *r.ErrorMessage != "" {
+					errMsg = *r.ErrorMessage
+				}
+				rows = append(rows, []string{r.TestName, r.Status, failing, errMsg})
+			}
+
+			w := cmd.OutOrStdout()
+			if getOutputFormat(cmd) == "json" {
+				if err := printRawJSON(w, raw); err != nil {
+					return err
+				}
+			} else {
+				gen.PrintTable(w, []string{"TEST", "STATUS", "FAILING ROWS", "ERROR"}, rows)
+				if run.FailuresTable != "" {
+					_, _ = fmt.Fprintf(w, "\nFailing rows stored in %s\n", run.FailuresTable)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d tests did not pass", failed, len(run.Results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&targetCatalog, "target-catalog", "", "Catalog the model is materialized in (default memory)")
+	cmd.Flags().StringVar(&targetSchema, "target-schema", "", "Schema the model is materialized in (default the project name)")
+	cmd.Flags().BoolVar(&storeFailures, "store-failures", false, "Write the rows of failing tests to <model>_test_failures")
+	return cmd
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelsTestCmd_StoreFailures(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/v1/models/sales/stg_orders/test-runs", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[` +
+			`{"test_name":"not_null_customer","status":"FAIL","rows_returned":2},` +
+			`{"test_name":"unique_id","status":"PASS","rows_returned":0}],` +
+			`"failures_table":"memory.sales.stg_orders_test_failures"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	rootCmd := newTestRootCmd(t, srv)
+	var stdout strings.Builder
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "models", "test", "sales.stg_orders", "--store-failures"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 tests did not pass")
+
+	out := stdout.String()
+	assert.Equal(t, map[string]interface{}{"store_failures": true}, body)
+	assert.Regexp(t, `not_null_customer\s+FAIL\s+2\s+-`, out)
+	assert.Regexp(t, `unique_id\s+PASS\s+0\s+-`, out)
+	assert.Contains(t, out, "Failing rows stored in memory.sales.stg_orders_test_failures")
+}
+
+func TestModelsTestCmd_AllPass(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"test_name":"unique_id","status":"PASS","rows_returned":0}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	out := runCatalogTree(t, srv, "models", "test", "sales.stg_orders")
+
+	assert.Regexp(t, `unique_id\s+PASS`, out)
+	assert.NotContains(t, out, "Failing rows stored")
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// modelTestResult is the subset of a ModelTestResult the model test
+// commands print.
+type modelTestResult struct {
+	RunStepID    string  `json:"run_step_id"`
+	TestName     string  `json:"test_name"`
+	Status       string  `json:"status"`
+	RowsReturned *int64  `json:"rows_returned,omitempty"`
+	ErrorMessage *string `json:"error_message,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+// newModelsTestHistoryCmd builds `duck models test-history <project.model> <test>`,
+// which lists a model test's results across runs to surface flaky or
+// degrading data quality.
+func newModelsTestHistoryCmd(client *gen.Client) *cobra.Command {
+	var limit int64
+
+	cmd := &cobra.Command{
+		Use:   "test-history <project.model> <test>",
+		Short: "Show the pass/fail history of a model test",
+		Long: "Lists the results of a model test across model runs, newest first, with the number of " +
+			"failing rows, followed by a pass/fail summary. The test is given by name or ID.",
+		Example: "  duck models test-history sales.stg_orders not_null_customer_id\n" +
+			"  duck models test-history sales.stg_orders not_null_customer_id --limit 50 -o json",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project, model, err := parseModelRef(args[0])
+			if err != nil {
+				return err
+			}
+			modelPath := "/models/" + url.PathEscape(project) + "/" + url.PathEscape(model)
+
+			testID, err := resolveModelTestID(client, modelPath, args[1])
+			if err != nil {
+				return fmt.Errorf("model %s: %w", args[0], err)
+			}
+
+			query := url.Values{}
+			if limit > 0 {
+				query.Set("max_results", strconv.FormatInt(limit, 10))
+			}
+			resp, err := client.Do(http.MethodGet, modelPath+"/tests/"+url.PathEscape(testID)+"/results", query, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			raw, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), raw)
+			}
+			var page struct {
+				Data []modelTestResult `json:"data"`
+			}
+			if err := json.Unmarshal(raw, &page); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+
+			counts := map[string]int{}
+			rows := make([][]string, 0, len(page.Data))
+			for _, r := range page.Data {
+				counts[r.Status]++
+				failing, errMsg := "-", "-"
+				if r.RowsReturned != nil {
+					failing = strconv.FormatInt(*r.RowsReturned, 10)
+				}
+				if r.ErrorMessage != nil && *r.ErrorMessage != "" {
+					errMsg = *r.ErrorMessage
+				}
+				rows = append(rows, []string{r.CreatedAt, r.Status, failing, errMsg})
+			}
+			w := cmd.OutOrStdout()
+			gen.PrintTable(w, []string{"TIME", "STATUS", "FAILING ROWS", "ERROR"}, rows)
+			_, _ = fmt.Fprintf(w, "\n%d results: %d passed, %d failed, %d errored\n",
+				len(page.Data), counts["PASS"], counts["FAIL"], counts["ERROR"])
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64Var(&limit, "limit", 20, "Maximum number of results to show")
+	return cmd
+}
+
+// resolveModelTestID maps a test name or ID to the ID of a test defined on
+// the model at modelPath.
+func resolveModelTestID(client *gen.Client, modelPath, nameOrID string) (string, error) {
+	resp, err := client.Do(http.MethodGet, modelPath+"/tests", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return "", err
+	}
+	raw, err := gen.ReadBody(resp)
+	if err != nil {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+	var list struct {
+		Data []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return "", fmt.Errorf("parse response: %w", err)
+	}
+	for _, t := range list.Data {
+		if t.ID == nameOrID || t.Name == nameOrID {
+			return t.ID, nil
+		}
+	}
+	return "", fmt.Errorf("test %q not found", nameOrID)
+}
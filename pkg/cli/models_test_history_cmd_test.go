@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newModelTestHistoryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/models/sales/stg_orders/tests":
+			_, _ = w.Write([]byte(`{"data":[{"id":"t-1","name":"not_null_customer"},{"id":"t-2","name":"unique_id"}]}`))
+		case "/v1/models/sales/stg_orders/tests/t-1/results":
+			_, _ = w.Write([]byte(`{"data":[` +
+				`{"status":"FAIL","rows_returned":3,"created_at":"2026-02-16T12:00:00Z"},` +
+				`{"status":"FAIL","rows_returned":1,"created_at":"2026-02-16T11:00:00Z"},` +
+				`{"status":"PASS","rows_returned":0,"created_at":"2026-02-16T10:00:00Z"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestModelsTestHistoryCmd_ByName(t *testing.T) {
+	srv := newModelTestHistoryServer(t)
+
+	out := runCatalogTree(t, srv, "models", "test-history", "sales.stg_orders", "not_null_customer")
+
+	assert.Regexp(t, `TIME\s+STATUS\s+FAILING ROWS\s+ERROR`, out)
+	assert.Regexp(t, `2026-02-16T12:00:00Z\s+FAIL\s+3\s+-`, out)
+	assert.Regexp(t, `2026-02-16T10:00:00Z\s+PASS\s+0\s+-`, out)
+	assert.Contains(t, out, "3 results: 1 passed, 2 failed, 0 errored")
+}
+
+func TestModelsTestHistoryCmd_ByID(t *testing.T) {
+	srv := newModelTestHistoryServer(t)
+
+	out := runCatalogTree(t, srv, "models", "test-history", "sales.stg_orders", "t-1")
+
+	assert.Contains(t, out, "3 results")
+}
+
+func TestModelsTestHistoryCmd_UnknownTest(t *testing.T) {
+	srv := newModelTestHistoryServer(t)
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "models", "test-history", "sales.stg_orders", "missing"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `test "missing" not found`)
+}
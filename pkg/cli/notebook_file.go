@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"duck-demo/internal/declarative"
+)
+
+// Notebook file formats accepted by duck notebooks export and import.
+const (
+	notebookFormatJSON  = "json"
+	notebookFormatIPYNB = "ipynb"
+)
+
+// notebookFileVersion identifies the portable notebook JSON format.
+const notebookFileVersion = "duck.notebook/v1"
+
+// notebookFile is the portable JSON form of a notebook. Field order and cell
+// positions are fixed so that exports of the same notebook are byte-identical
+// and diff cleanly under version control.
+type notebookFile struct {
+	Format      string             `json:"format"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Cells       []notebookFileCell `json:"cells"`
+}
+
+// notebookFileCell is one cell of a notebookFile.
+type notebookFileCell struct {
+	Type     string `json:"type"`
+	Position int    `json:"position"`
+	Content  string `json:"content"`
+}
+
+// validNotebookFormat reports an error for an unsupported --format value.
+func validNotebookFormat(format string) error {
+	switch format {
+	case notebookFormatJSON, notebookFormatIPYNB:
+		return nil
+	default:
+		return fmt.Errorf("unsupported notebook format %q (expected %s or %s)", format, notebookFormatJSON, notebookFormatIPYNB)
+	}
+}
+
+// encodeNotebook serializes nb in format. The owner is not exported: it is
+// server-specific and is kept by import.
+func encodeNotebook(nb declarative.NotebookResource, format string) ([]byte, error) {
+	var v interface{}
+	switch format {
+	case notebookFormatJSON:
+		file := notebookFile{
+			Format:      notebookFileVersion,
+			Name:        nb.Name,
+			Description: nb.Spec.Description,
+			Cells:       make([]notebookFileCell, 0, len(nb.Spec.Cells)),
+		}
+		for i, cell := range nb.Spec.Cells {
+			file.Cells = append(file.Cells, notebookFileCell{Type: cell.Type, Position: i, Content: cell.Content})
+		}
+		v = file
+	case notebookFormatIPYNB:
+		v = toIPYNB(nb)
+	default:
+		return nil, validNotebookFormat(format)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode notebook: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// decodeNotebook parses data in format. The name is taken from the file; it
+// is empty when an ipynb file carries no duck metadata.
+func decodeNotebook(data []byte, format string) (declarative.NotebookResource, error) {
+	switch format {
+	case notebookFormatJSON:
+		var file notebookFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return declarative.NotebookResource{}, fmt.Errorf("parse notebook: %w", err)
+		}
+		if file.Format != notebookFileVersion {
+			return declarative.NotebookResource{}, fmt.Errorf("unsupported notebook file format %q (expected %q)", file.Format, notebookFileVersion)
+		}
+		sort.SliceStable(file.Cells, func(i, j int) bool {
+			return file.Cells[i].Position < file.Cells[j].Position
+		})
+		nb := declarative.NotebookResource{
+			Name: file.Name,
+			Spec: declarative.NotebookSpec{
+				Description: file.Description,
+				Cells:       make([]declarative.CellSpec, 0, len(file.Cells)),
+			},
+		}
+		for _, cell := range file.Cells {
+			if cell.Type != "sql" && cell.Type != "markdown" {
+				return declarative.NotebookResource{}, fmt.Errorf("cell %d: type must be sql or markdown, got %q", cell.Position, cell.Type)
+			}
+			nb.Spec.Cells = append(nb.Spec.Cells, declarative.CellSpec{Type: cell.Type, Content: cell.Content})
+		}
+		return nb, nil
+	case notebookFormatIPYNB:
+		return fromIPYNB(data)
+	default:
+		return declarative.NotebookResource{}, validNotebookFormat(format)
+	}
+}
+
+// === Jupyter ===
+
+// ipynbNotebook is the subset of the Jupyter nbformat 4 document that duck
+// reads and writes.
+type ipynbNotebook struct {
+	Cells         []ipynbCell   `json:"cells"`
+	Metadata      ipynbMetadata `json:"metadata"`
+	NBFormat      int           `json:"nbformat"`
+	NBFormatMinor int           `json:"nbformat_minor"`
+}
+
+type ipynbMetadata struct {
+	Kernelspec   *ipynbKernelspec   `json:"kernelspec,omitempty"`
+	LanguageInfo *ipynbLanguageInfo `json:"language_info,omitempty"`
+	Duck         *ipynbDuckMetadata `json:"duck,omitempty"`
+}
+
+type ipynbKernelspec struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Language    string `json:"language"`
+}
+
+type ipynbLanguageInfo struct {
+	Name string `json:"name"`
+}
+
+// ipynbDuckMetadata carries the notebook fields Jupyter has no place for.
+type ipynbDuckMetadata struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ipynbCell is a Jupyter cell. Code cells must carry execution_count (null)
+// and outputs, markdown cells must not, so both are raw messages.
+type ipynbCell struct {
+	CellType       string                 `json:"cell_type"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	Source         json.RawMessage        `json:"source"`
+	ExecutionCount json.RawMessage        `json:"execution_count,omitempty"`
+	Outputs        json.RawMessage        `json:"outputs,omitempty"`
+}
+
+// toIPYNB maps SQL cells to code cells and markdown cells to markdown cells.
+func toIPYNB(nb declarative.NotebookResource) ipynbNotebook {
+	doc := ipynbNotebook{
+		Cells: make([]ipynbCell, 0, len(nb.Spec.Cells)),
+		Metadata: ipynbMetadata{
+			Kernelspec:   &ipynbKernelspec{Name: "duckdb", DisplayName: "DuckDB SQL", Language: "sql"},
+			LanguageInfo: &ipynbLanguageInfo{Name: "sql"},
+			Duck:         &ipynbDuckMetadata{Name: nb.Name, Description: nb.Spec.Description},
+		},
+		NBFormat:      4,
+		NBFormatMinor: 4,
+	}
+	for _, cell := range nb.Spec.Cells {
+		source, _ := json.Marshal(ipynbSourceLines(cell.Content))
+		out := ipynbCell{CellType: "markdown", Metadata: map[string]interface{}{}, Source: source}
+		if cell.Type == "sql" {
+			out.CellType = "code"
+			out.ExecutionCount = json.RawMessage("null")
+			out.Outputs = json.RawMessage("[]")
+		}
+		doc.Cells = append(doc.Cells, out)
+	}
+	return doc
+}
+
+// fromIPYNB maps code cells to SQL cells and markdown cells to markdown
+// cells. Raw cells have no counterpart and are rejected.
+func fromIPYNB(data []byte) (declarative.NotebookResource, error) {
+	var doc ipynbNotebook
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return declarative.NotebookResource{}, fmt.Errorf("parse ipynb: %w", err)
+	}
+	if doc.NBFormat != 4 {
+		return declarative.NotebookResource{}, fmt.Errorf("unsupported nbformat %d (expected 4)", doc.NBFormat)
+	}
+	var nb declarative.NotebookResource
+	if doc.Metadata.Duck != nil {
+		nb.Name = doc.Metadata.Duck.Name
+		nb.Spec.Description = doc.Metadata.Duck.Description
+	}
+	nb.Spec.Cells = make([]declarative.CellSpec, 0, len(doc.Cells))
+	for i, cell := range doc.Cells {
+		content, err := ipynbSourceText(cell.Source)
+		if err != nil {
+			return declarative.NotebookResource{}, fmt.Errorf("cell %d: %w", i, err)
+		}
+		switch cell.CellType {
+		case "code":
+			nb.Spec.Cells = append(nb.Spec.Cells, declarative.CellSpec{Type: "sql", Content: content})
+		case "markdown":
+			nb.Spec.Cells = append(nb.Spec.Cells, declarative.CellSpec{Type: "markdown", Content: content})
+		default:
+			return declarative.NotebookResource{}, fmt.Errorf("cell %d: unsupported ipynb cell type %q", i, cell.CellType)
+		}
+	}
+	return nb, nil
+}
+
+// ipynbSourceLines splits content into lines that keep their newlines, the
+// way Jupyter stores cell sources.
+func ipynbSourceLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// ipynbSourceText joins a cell source, which nbformat allows to be either a
+// string or a list of lines.
+func ipynbSourceText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var lines []string
+	if err := json.Unmarshal(raw, &lines); err != nil {
+		return "", fmt.Errorf("source must be a string or a list of strings")
+	}
+	return strings.Join(lines, ""), nil
+}
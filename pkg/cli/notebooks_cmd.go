@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/internal/declarative"
+	"duck-demo/pkg/cli/gen"
+)
+
+// newNotebooksExportCmd builds `duck notebooks export <name>`, which writes a
+// notebook and its cells to a portable file.
+func newNotebooksExportCmd(client *gen.Client) *cobra.Command {
+	var (
+		out    string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Export a notebook to a portable file",
+		Long: "Writes a notebook's description and cells to a file that can be versioned and shared. " +
+			"The default json format is stable across exports; --format ipynb writes a Jupyter notebook " +
+			"with SQL cells as code cells. Without --out the file is written to stdout.",
+		Example: "  duck notebooks export kpi_walkthrough --out kpi_walkthrough.json\n" +
+			"  duck notebooks export kpi_walkthrough --format ipynb --out kpi_walkthrough.ipynb",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validNotebookFormat(format); err != nil {
+				return err
+			}
+			nb, err := NewAPIStateClient(client).ReadNotebook(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("read notebook: %w", err)
+			}
+			data, err := encodeNotebook(nb, format)
+			if err != nil {
+				return err
+			}
+
+			if out == "" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := os.WriteFile(out, data, 0o644); err != nil { //nolint:gosec // exported notebooks are meant to be shared
+				return fmt.Errorf("write %s: %w", out, err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return gen.PrintJSON(os.Stdout, map[string]interface{}{
+					"status": "ok",
+					"name":   nb.Name,
+					"path":   out,
+					"cells":  len(nb.Spec.Cells),
+				})
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Exported notebook %s (%d cells) to %s\n", nb.Name, len(nb.Spec.Cells), out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "Path to write the notebook file to (default stdout)")
+	cmd.Flags().StringVar(&format, "format", notebookFormatJSON, "File format (json, ipynb)")
+
+	return cmd
+}
+
+// newNotebooksImportCmd builds `duck notebooks import <file>`, which creates
+// the notebook in a file or updates the notebook of the same name. Cells are
+// reconciled the same way duck apply reconciles a Notebook manifest.
+func newNotebooksImportCmd(client *gen.Client) *cobra.Command {
+	var (
+		name   string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create or update a notebook from a portable file",
+		Long: "Reads a file written by duck notebooks export (or a Jupyter notebook with --format ipynb) and " +
+			"creates the notebook, or updates the description and replaces the cells of the existing notebook " +
+			"with the same name. The notebook's owner is left unchanged.",
+		Example: "  duck notebooks import kpi_walkthrough.json\n" +
+			"  duck notebooks import analysis.ipynb --format ipynb --name analysis",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("format") && strings.EqualFold(filepath.Ext(args[0]), ".ipynb") {
+				format = notebookFormatIPYNB
+			}
+			if err := validNotebookFormat(format); err != nil {
+				return err
+			}
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("read %s: %w", args[0], err)
+			}
+			desired, err := decodeNotebook(data, format)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			if name != "" {
+				desired.Name = name
+			}
+			if desired.Name == "" {
+				desired.Name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+			}
+
+			status, err := importNotebook(cmd.Context(), NewAPIStateClient(client), desired)
+			if err != nil {
+				return err
+			}
+
+			if getOutputFormat(cmd) == "json" {
+				return gen.PrintJSON(os.Stdout, map[string]interface{}{
+					"status": status,
+					"name":   desired.Name,
+					"cells":  len(desired.Spec.Cells),
+				})
+			}
+			switch status {
+			case "created":
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created notebook %s (%d cells)\n", desired.Name, len(desired.Spec.Cells))
+			case "updated":
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Updated notebook %s (%d cells)\n", desired.Name, len(desired.Spec.Cells))
+			default:
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Notebook %s is up to date\n", desired.Name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Notebook name (default: the name in the file, or the file name)")
+	cmd.Flags().StringVar(&format, "format", notebookFormatJSON, "File format (json, ipynb); .ipynb files default to ipynb")
+
+	return cmd
+}
+
+// importNotebook diffs desired against the server's notebook of the same name
+// and executes the resulting create or update. It returns "created",
+// "updated" or "unchanged".
+func importNotebook(ctx context.Context, sc *APIStateClient, desired declarative.NotebookResource) (string, error) {
+	actual := &declarative.DesiredState{}
+	existing, err := sc.ReadNotebook(ctx, desired.Name)
+	switch {
+	case err == nil:
+		desired.Spec.Owner = existing.Spec.Owner
+		actual.Notebooks = append(actual.Notebooks, existing)
+	case !errors.Is(err, errNotebookNotFound):
+		return "", fmt.Errorf("read notebook: %w", err)
+	}
+
+	plan := declarative.Diff(&declarative.DesiredState{Notebooks: []declarative.NotebookResource{desired}}, actual)
+	if len(plan.Actions) == 0 {
+		return "unchanged", nil
+	}
+	for _, action := range plan.Actions {
+		if err := sc.Execute(ctx, action); err != nil {
+			return "", fmt.Errorf("%s notebook %s: %w", action.Operation, desired.Name, err)
+		}
+	}
+	if len(actual.Notebooks) == 0 {
+		return "created", nil
+	}
+	return "updated", nil
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/declarative"
+)
+
+// notebookTestServer keeps notebooks and their cells in memory and serves the
+// notebook endpoints used by export and import.
+type notebookTestServer struct {
+	mu        sync.Mutex
+	nextID    int
+	notebooks map[string]*apiNotebookDetail
+}
+
+func newNotebookTestServer(t *testing.T) (*notebookTestServer, *httptest.Server) {
+	t.Helper()
+	s := &notebookTestServer{notebooks: make(map[string]*apiNotebookDetail)}
+	srv := httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+func (s *notebookTestServer) id() string {
+	s.nextID++
+	return fmt.Sprintf("id-%d", s.nextID)
+}
+
+func (s *notebookTestServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/notebooks"), "/")
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		list := make([]apiNotebook, 0, len(s.notebooks))
+		for _, nb := range s.notebooks {
+			list = append(list, nb.Notebook)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": list})
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		nb := apiNotebook{ID: s.id(), Owner: "alice"}
+		nb.Name, _ = body["name"].(string)
+		nb.Description, _ = body["description"].(string)
+		s.notebooks[nb.ID] = &apiNotebookDetail{Notebook: nb}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(nb)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(s.notebooks[parts[1]])
+	case len(parts) == 2 && r.Method == http.MethodPatch:
+		s.notebooks[parts[1]].Notebook.Description, _ = body["description"].(string)
+		_ = json.NewEncoder(w).Encode(s.notebooks[parts[1]].Notebook)
+	case len(parts) == 3 && r.Method == http.MethodPost:
+		nb := s.notebooks[parts[1]]
+		cell := apiNotebookCell{ID: s.id()}
+		cell.CellType, _ = body["cell_type"].(string)
+		cell.Content, _ = body["content"].(string)
+		position, _ := body["position"].(float64)
+		cell.Position = int(position)
+		nb.Cells = append(nb.Cells, cell)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(cell)
+	case len(parts) == 4 && r.Method == http.MethodDelete:
+		nb := s.notebooks[parts[1]]
+		for i, cell := range nb.Cells {
+			if cell.ID == parts[3] {
+				nb.Cells = append(nb.Cells[:i], nb.Cells[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// seed creates a notebook with cells directly in the server.
+func (s *notebookTestServer) seed(name, description string, cells ...apiNotebookCell) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	detail := &apiNotebookDetail{Notebook: apiNotebook{ID: s.id(), Name: name, Description: description, Owner: "alice"}}
+	for _, c := range cells {
+		c.ID = s.id()
+		detail.Cells = append(detail.Cells, c)
+	}
+	s.notebooks[detail.Notebook.ID] = detail
+}
+
+func runNotebooksCmd(t *testing.T, srv *httptest.Server, args ...string) string {
+	t.Helper()
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"--host", srv.URL, "notebooks"}, args...))
+	require.NoError(t, rootCmd.Execute())
+	return out.String()
+}
+
+func TestNotebooksExportImport_RoundTrip(t *testing.T) {
+	for _, format := range []string{notebookFormatJSON, notebookFormatIPYNB} {
+		t.Run(format, func(t *testing.T) {
+			source, sourceSrv := newNotebookTestServer(t)
+			// Positions are stored out of order; export orders cells by position.
+			source.seed("kpi_walkthrough", "KPI notebook",
+				apiNotebookCell{CellType: "sql", Content: "SELECT region,\n  sum(amount)\nFROM sales\nGROUP BY 1\n", Position: 1},
+				apiNotebookCell{CellType: "markdown", Content: "# KPIs", Position: 0},
+				apiNotebookCell{CellType: "sql", Content: "", Position: 2},
+			)
+			file := filepath.Join(t.TempDir(), "kpi."+format)
+			runNotebooksCmd(t, sourceSrv, "export", "kpi_walkthrough", "--format", format, "--out", file)
+			exported, err := os.ReadFile(file) //nolint:gosec // test file
+			require.NoError(t, err)
+
+			target, targetSrv := newNotebookTestServer(t)
+			out := runNotebooksCmd(t, targetSrv, "import", file)
+			assert.Contains(t, out, "Created notebook kpi_walkthrough (3 cells)")
+
+			require.Len(t, target.notebooks, 1)
+			for _, nb := range target.notebooks {
+				assert.Equal(t, "kpi_walkthrough", nb.Notebook.Name)
+				assert.Equal(t, "KPI notebook", nb.Notebook.Description)
+				assert.Equal(t, []declarative.CellSpec{
+					{Type: "markdown", Content: "# KPIs"},
+					{Type: "sql", Content: "SELECT region,\n  sum(amount)\nFROM sales\nGROUP BY 1\n"},
+					{Type: "sql", Content: ""},
+				}, notebookCellSpecs(nb))
+			}
+
+			reexported := runNotebooksCmd(t, targetSrv, "export", "kpi_walkthrough", "--format", format)
+			assert.Equal(t, string(exported), reexported, "export is stable across a round trip")
+
+			out = runNotebooksCmd(t, targetSrv, "import", file, "--format", format)
+			assert.Contains(t, out, "Notebook kpi_walkthrough is up to date")
+		})
+	}
+}
+
+func TestNotebooksImport_UpdatesCellsOfExistingNotebook(t *testing.T) {
+	server, srv := newNotebookTestServer(t)
+	server.seed("kpi", "old", apiNotebookCell{CellType: "sql", Content: "SELECT 1", Position: 0})
+
+	file := filepath.Join(t.TempDir(), "kpi.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{
+  "format": "duck.notebook/v1",
+  "name": "kpi",
+  "description": "new",
+  "cells": [
+    {"type": "sql", "position": 1, "content": "SELECT 2"},
+    {"type": "markdown", "position": 0, "content": "# Intro"}
+  ]
+}`), 0o600))
+
+	out := runNotebooksCmd(t, srv, "import", file)
+	assert.Contains(t, out, "Updated notebook kpi (2 cells)")
+
+	require.Len(t, server.notebooks, 1)
+	for _, nb := range server.notebooks {
+		assert.Equal(t, "new", nb.Notebook.Description)
+		assert.Equal(t, "alice", nb.Notebook.Owner)
+		assert.Equal(t, []declarative.CellSpec{
+			{Type: "markdown", Content: "# Intro"},
+			{Type: "sql", Content: "SELECT 2"},
+		}, notebookCellSpecs(nb))
+	}
+}
+
+func TestDecodeNotebook_IPYNB(t *testing.T) {
+	// A notebook written by Jupyter: string and list sources, no duck metadata.
+	nb, err := decodeNotebook([]byte(`{
+  "cells": [
+    {"cell_type": "markdown", "metadata": {}, "source": "# Title"},
+    {"cell_type": "code", "execution_count": 3, "metadata": {}, "outputs": [{"output_type": "stream"}],
+     "source": ["SELECT *\n", "FROM t"]}
+  ],
+  "metadata": {"kernelspec": {"name": "python3", "display_name": "Python 3", "language": "python"}},
+  "nbformat": 4,
+  "nbformat_minor": 5
+}`), notebookFormatIPYNB)
+	require.NoError(t, err)
+	assert.Empty(t, nb.Name)
+	assert.Equal(t, []declarative.CellSpec{
+		{Type: "markdown", Content: "# Title"},
+		{Type: "sql", Content: "SELECT *\nFROM t"},
+	}, nb.Spec.Cells)
+
+	_, err = decodeNotebook([]byte(`{"cells":[{"cell_type":"raw","source":""}],"metadata":{},"nbformat":4}`), notebookFormatIPYNB)
+	require.ErrorContains(t, err, `unsupported ipynb cell type "raw"`)
+
+	_, err = decodeNotebook([]byte(`{"format":"other","name":"x","cells":[]}`), notebookFormatJSON)
+	require.ErrorContains(t, err, "unsupported notebook file format")
+}
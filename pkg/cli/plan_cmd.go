@@ -22,7 +22,14 @@ func newPlanCmd(client *gen.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "plan",
 		Short: "Show changes required to match the declarative configuration",
-		Long:  "Reads YAML configuration files, compares with the current server state, and shows a plan of changes.",
+		Long: `Reads YAML configuration files, compares with the current server state, and shows a plan of changes.
+
+With -o json the plan is written as a versioned JSON document listing each
+action (operation, resource type and name, and field-level changes for
+updates) plus summary counts, for gating CI pipelines. The command exits 0
+when there is nothing to change and 2 when the plan has changes or errors.`,
+		Example: `  duck plan --config-dir ./duck-config
+  duck plan -o json | jq -e '[.actions[] | select(.operation == "delete")] | length == 0'`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			// Check local -o flag first, then fall back to global --output.
 			effectiveOutput := output
@@ -91,7 +98,7 @@ func newPlanCmd(client *gen.Client) *cobra.Command {
 	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, json)")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	cmd.Flags().BoolVar(&allowUnknownFields, "allow-unknown-fields", false, "Allow unknown YAML fields in declarative config")
-	cmd.Flags().BoolVar(&legacyOptionalReadErrors, "legacy-optional-read-errors", false, "Treat transport errors as optional for model/macro capability checks")
+	cmd.Flags().BoolVar(&legacyOptionalReadErrors, "legacy-optional-read-errors", false, "Treat transport errors as optional for model/macro capability checks on servers without the capabilities handshake")
 
 	return cmd
 }
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// getPrincipalAttributes accepts a principal name and prints the
+	// attributes as a key/value table.
+	gen.RegisterOverride("getPrincipalAttributes", func(c *cobra.Command) {
+		c.Use = "get-attrs <principal>"
+	})
+	gen.RegisterRunOverride("getPrincipalAttributes", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			id, err := resolvePrincipalArg(client, args[0])
+			if err != nil {
+				return err
+			}
+			raw, attrs, err := getPrincipalAttributes(client, id)
+			if err != nil {
+				return err
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), raw)
+			}
+			printPrincipalAttributes(cmd.OutOrStdout(), attrs)
+			return nil
+		}
+	})
+
+	// setPrincipalAttributes takes key=value arguments and merges them into
+	// the principal's current attributes; key- removes an attribute and
+	// --replace drops every attribute not given.
+	gen.RegisterOverride("setPrincipalAttributes", func(c *cobra.Command) {
+		c.Use = "set-attr <principal> <key=value|key->..."
+		c.Args = cobra.MinimumNArgs(2)
+		_ = c.Flags().MarkHidden("attributes")
+		_ = c.Flags().MarkHidden("json")
+		c.Flags().Bool("replace", false, "Replace all attributes instead of merging into the current ones")
+	})
+	gen.RegisterRunOverride("setPrincipalAttributes", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			set, unset, err := parseAttributeArgs(args[1:])
+			if err != nil {
+				return err
+			}
+			id, err := resolvePrincipalArg(client, args[0])
+			if err != nil {
+				return err
+			}
+
+			attrs := map[string]string{}
+			if replace, _ := cmd.Flags().GetBool("replace"); !replace {
+				if _, attrs, err = getPrincipalAttributes(client, id); err != nil {
+					return err
+				}
+			}
+			for k, v := range set {
+				attrs[k] = v
+			}
+			for _, k := range unset {
+				delete(attrs, k)
+			}
+
+			resp, err := client.Do("PUT", "/principals/"+url.PathEscape(id)+"/attributes", nil,
+				map[string]interface{}{"attributes": attrs})
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			raw, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), raw)
+			}
+			var result struct {
+				Attributes map[string]string `json:"attributes"`
+			}
+			if err := json.Unmarshal(raw, &result); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			printPrincipalAttributes(cmd.OutOrStdout(), result.Attributes)
+			return nil
+		}
+	})
+}
+
+// parseAttributeArgs splits key=value arguments into attributes to set and
+// key- arguments into attributes to remove.
+func parseAttributeArgs(args []string) (map[string]string, []string, error) {
+	set := map[string]string{}
+	var unset []string
+	for _, arg := range args {
+		if key, value, ok := strings.Cut(arg, "="); ok {
+			if key == "" {
+				return nil, nil, fmt.Errorf("invalid attribute %q: missing key", arg)
+			}
+			set[key] = value
+			continue
+		}
+		if key, ok := strings.CutSuffix(arg, "-"); ok && key != "" {
+			unset = append(unset, key)
+			continue
+		}
+		return nil, nil, fmt.Errorf("invalid attribute %q: want key=value or key-", arg)
+	}
+	return set, unset, nil
+}
+
+// getPrincipalAttributes fetches a principal's attributes, returning both the
+// raw response and the parsed map.
+func getPrincipalAttributes(client *gen.Client, id string) ([]byte, map[string]string, error) {
+	resp, err := client.Do("GET", "/principals/"+url.PathEscape(id)+"/attributes", nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return nil, nil, err
+	}
+	raw, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+	var result struct {
+		Attributes map[string]string `json:"attributes"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, nil, fmt.Errorf("parse response: %w", err)
+	}
+	if result.Attributes == nil {
+		result.Attributes = map[string]string{}
+	}
+	return raw, result.Attributes, nil
+}
+
+// printPrincipalAttributes renders attributes as a key/value table sorted by key.
+func printPrincipalAttributes(w io.Writer, attrs map[string]string) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	rows := make([][]string, len(keys))
+	for i, k := range keys {
+		rows[i] = []string{k, attrs[k]}
+	}
+	gen.PrintTable(w, []string{"key", "value"}, rows)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const principalAttrsTestID = "550e8400-e29b-41d4-a716-446655440000"
+
+// principalAttrsServer serves one principal named alice whose attributes
+// start as current, recording the attributes PUT to it.
+func principalAttrsServer(t *testing.T, current map[string]string, put *map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/principals" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"data":[{"id":"` + principalAttrsTestID + `","name":"alice"}]}`))
+		case r.URL.Path == "/v1/principals/"+principalAttrsTestID+"/attributes" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"principal_id": principalAttrsTestID, "attributes": current})
+		case r.URL.Path == "/v1/principals/"+principalAttrsTestID+"/attributes" && r.Method == http.MethodPut:
+			var body struct {
+				Attributes map[string]string `json:"attributes"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			*put = body.Attributes
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"principal_id": principalAttrsTestID, "attributes": body.Attributes})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPrincipalSetAttrOverride(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantPut map[string]string
+	}{
+		{
+			name:    "merges into current attributes",
+			args:    []string{"alice", "region=EU"},
+			wantPut: map[string]string{"region": "EU", "team": "finance"},
+		},
+		{
+			name:    "removes with key-",
+			args:    []string{"alice", "region=US", "team-"},
+			wantPut: map[string]string{"region": "US"},
+		},
+		{
+			name:    "replace drops attributes not given",
+			args:    []string{"alice", "level=3", "--replace"},
+			wantPut: map[string]string{"level": "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var put map[string]string
+			srv := principalAttrsServer(t, map[string]string{"region": "US", "team": "finance"}, &put)
+
+			var out bytes.Buffer
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL, "security", "principals", "set-attr"}, tt.args...))
+			require.NoError(t, rootCmd.Execute())
+
+			assert.Equal(t, tt.wantPut, put)
+			for k, v := range tt.wantPut {
+				assert.Contains(t, out.String(), k)
+				assert.Contains(t, out.String(), v)
+			}
+		})
+	}
+}
+
+func TestPrincipalSetAttrOverride_InvalidArgument(t *testing.T) {
+	rootCmd := newRootCmd()
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"--host", "http://127.0.0.1:0", "security", "principals", "set-attr", "alice", "region"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "want key=value or key-")
+}
+
+func TestPrincipalGetAttrsOverride(t *testing.T) {
+	var put map[string]string
+	srv := principalAttrsServer(t, map[string]string{"region": "EU"}, &put)
+
+	var out bytes.Buffer
+	rootCmd := newRootCmd()
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "security", "principals", "get-attrs", "alice"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Contains(t, out.String(), "region")
+	assert.Contains(t, out.String(), "EU")
+	assert.Nil(t, put)
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPrincipalImportServer serves importPrincipals against a server that
+// already has principal alice and group analysts, recording each request.
+func newPrincipalImportServer(t *testing.T) (*httptest.Server, *[]principalImportRow) {
+	t.Helper()
+	var got []principalImportRow
+	existing := map[string]bool{"alice": true}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/v1/principals:import", r.URL.Path)
+		var req struct {
+			Principals []principalImportRow `json:"principals"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		got = req.Principals
+
+		type result struct {
+			Name        string   `json:"name"`
+			PrincipalID string   `json:"principal_id"`
+			Status      string   `json:"status"`
+			GroupsAdded []string `json:"groups_added"`
+		}
+		resp := struct {
+			Results []result `json:"results"`
+			Created int      `json:"created"`
+			Skipped int      `json:"skipped"`
+		}{Results: []result{}}
+		for _, p := range req.Principals {
+			res := result{Name: p.Name, PrincipalID: "p-" + p.Name, Status: "created", GroupsAdded: p.Groups}
+			if existing[p.Name] {
+				res.Status = "skipped"
+				resp.Skipped++
+			} else {
+				existing[p.Name] = true
+				resp.Created++
+			}
+			if res.GroupsAdded == nil {
+				res.GroupsAdded = []string{}
+			}
+			resp.Results = append(resp.Results, res)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func TestPrincipalImportCmd_MixOfNewAndExisting(t *testing.T) {
+	srv, got := newPrincipalImportServer(t)
+	path := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"name,type,is_admin,groups\n"+
+			"alice,user,false,analysts\n"+
+			"etl-bot,service_principal,,analysts; engineers\n"+
+			"bob,,true,\n"), 0o600))
+
+	out := runCatalogTree(t, srv, "security", "principals", "import", path)
+
+	assert.Equal(t, []principalImportRow{
+		{Name: "alice", Type: "user", Groups: []string{"analysts"}},
+		{Name: "etl-bot", Type: "service_principal", Groups: []string{"analysts", "engineers"}},
+		{Name: "bob", IsAdmin: true},
+	}, *got)
+	assert.Regexp(t, `alice\s+skipped\s+analysts`, out)
+	assert.Regexp(t, `etl-bot\s+created\s+analysts, engineers`, out)
+	assert.Regexp(t, `bob\s+created`, out)
+	assert.Contains(t, out, "2 created, 1 skipped")
+}
+
+func TestPrincipalImportCmd_ReadsStdinAndReordersColumns(t *testing.T) {
+	srv, got := newPrincipalImportServer(t)
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetIn(strings.NewReader("Groups,Name\nanalysts,carol\n"))
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "--output", "json", "security", "principals", "import", "-"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Equal(t, []principalImportRow{{Name: "carol", Groups: []string{"analysts"}}}, *got)
+	assert.Contains(t, out.String(), `"created": 1`)
+}
+
+func TestParsePrincipalImportCSV_Errors(t *testing.T) {
+	for name, tc := range map[string]struct{ csv, wantErr string }{
+		"empty":          {"", "empty file"},
+		"header only":    {"name,type\n", "no principals after the header row"},
+		"unknown column": {"name,role\nalice,admin\n", `unknown column "role"`},
+		"missing name":   {"type,groups\nuser,analysts\n", `missing required column "name"`},
+		"bad is_admin":   {"name,is_admin\nalice,false\nbob,maybe\n", `row 2: is_admin must be true or false, got "maybe"`},
+		"ragged row":     {"name,type\nalice\n", "wrong number of fields"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parsePrincipalImportCSV(strings.NewReader(tc.csv))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
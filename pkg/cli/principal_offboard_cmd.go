@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// offboardSummary is the output of `duck security principals offboard`.
+type offboardSummary struct {
+	PrincipalID        string   `json:"principal_id"`
+	Principal          string   `json:"principal"`
+	RevokedAdmin       bool     `json:"revoked_admin"`
+	RevokedGrants      int      `json:"revoked_grants"`
+	RemovedMemberships int      `json:"removed_memberships"`
+	DeletedAPIKeys     []string `json:"deleted_api_keys"`
+	DeletedPrincipal   bool     `json:"deleted_principal"`
+}
+
+// newPrincipalOffboardCmd builds `duck security principals offboard
+// <principal>`, which removes every access artifact of a principal.
+func newPrincipalOffboardCmd(client *gen.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "offboard <principal>",
+		Short: "Remove all access of a principal",
+		Long: "Clears the principal's admin flag, revokes every grant it holds and removes it from all groups " +
+			"in one server-side transaction, then deletes its API keys. With --delete-principal the principal " +
+			"itself is deleted last. Prints a summary of what was removed.",
+		Example: "  duck security principals offboard alice\n" +
+			"  duck security principals offboard alice --delete-principal --yes",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deletePrincipal, _ := cmd.Flags().GetBool("delete-principal")
+			if !cmd.Flags().Changed("yes") {
+				msg := fmt.Sprintf("Revoke admin, all grants, group memberships and API keys of %q?", args[0])
+				if deletePrincipal {
+					msg = fmt.Sprintf("Revoke all access of %q and delete the principal?", args[0])
+				}
+				if !gen.ConfirmPrompt(msg) {
+					return nil
+				}
+			}
+
+			id, err := resolvePrincipalArg(client, args[0])
+			if err != nil {
+				return err
+			}
+			summary, err := offboardPrincipal(client, id, deletePrincipal)
+			if err != nil {
+				return err
+			}
+			summary.Principal = args[0]
+
+			if getOutputFormat(cmd) == "json" {
+				return gen.PrintJSON(cmd.OutOrStdout(), summary)
+			}
+			yesNo := func(b bool) string {
+				if b {
+					return "yes"
+				}
+				return "no"
+			}
+			gen.PrintTable(cmd.OutOrStdout(), []string{"REMOVED", "COUNT"}, [][]string{
+				{"admin", yesNo(summary.RevokedAdmin)},
+				{"grants", fmt.Sprint(summary.RevokedGrants)},
+				{"group memberships", fmt.Sprint(summary.RemovedMemberships)},
+				{"api keys", fmt.Sprint(len(summary.DeletedAPIKeys))},
+				{"principal", yesNo(summary.DeletedPrincipal)},
+			})
+			return nil
+		},
+	}
+	cmd.Flags().Bool("delete-principal", false, "Delete the principal after removing its access")
+	cmd.Flags().Bool("yes", false, "Skip confirmation prompt")
+	return cmd
+}
+
+// offboardPrincipal clears the admin flag of the principal, revokes its
+// grants and memberships, deletes its API keys and, when deletePrincipal is
+// set, the principal. Admin goes first because it bypasses every grant; the
+// principal goes last so a failure part way leaves it in place for a rerun.
+func offboardPrincipal(client *gen.Client, id string, deletePrincipal bool) (*offboardSummary, error) {
+	summary := &offboardSummary{PrincipalID: id, DeletedAPIKeys: []string{}}
+
+	revokedAdmin, err := revokePrincipalAdmin(client, id)
+	if err != nil {
+		return nil, err
+	}
+	summary.RevokedAdmin = revokedAdmin
+
+	resp, err := client.Do(http.MethodPost, "/principals/"+url.PathEscape(id)+"/grants:revoke-all", nil,
+		map[string]interface{}{"remove_memberships": true})
+	if err != nil {
+		return nil, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return nil, fmt.Errorf("revoke grants: %w", err)
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var revoked struct {
+		RevokedGrants      []json.RawMessage `json:"revoked_grants"`
+		RemovedMemberships []json.RawMessage `json:"removed_memberships"`
+	}
+	if err := json.Unmarshal(body, &revoked); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	summary.RevokedGrants = len(revoked.RevokedGrants)
+	summary.RemovedMemberships = len(revoked.RemovedMemberships)
+
+	var keys []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := listAllItems(client, "/api-keys", url.Values{"principal_id": {id}}, &keys); err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	for _, k := range keys {
+		resp, err := client.Do(http.MethodDelete, "/api-keys/"+url.PathEscape(k.ID), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return nil, fmt.Errorf("delete api key %s: %w", k.Name, err)
+		}
+		summary.DeletedAPIKeys = append(summary.DeletedAPIKeys, k.Name)
+	}
+
+	if deletePrincipal {
+		resp, err := client.Do(http.MethodDelete, "/principals/"+url.PathEscape(id), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return nil, fmt.Errorf("delete principal: %w", err)
+		}
+		summary.DeletedPrincipal = true
+	}
+	return summary, nil
+}
+
+// revokePrincipalAdmin clears the admin flag of the principal and reports
+// whether it was set.
+func revokePrincipalAdmin(client *gen.Client, id string) (bool, error) {
+	resp, err := client.Do(http.MethodGet, "/principals/"+url.PathEscape(id), nil, nil)
+	if err != nil {
+		return false, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return false, fmt.Errorf("get principal: %w", err)
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return false, fmt.Errorf("read response: %w", err)
+	}
+	var principal struct {
+		IsAdmin bool `json:"is_admin"`
+	}
+	if err := json.Unmarshal(body, &principal); err != nil {
+		return false, fmt.Errorf("parse response: %w", err)
+	}
+	if !principal.IsAdmin {
+		return false, nil
+	}
+
+	resp, err = client.Do(http.MethodPut, "/principals/"+url.PathEscape(id)+"/admin", nil,
+		map[string]interface{}{"is_admin": false})
+	if err != nil {
+		return false, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return false, fmt.Errorf("revoke admin: %w", err)
+	}
+	return true, nil
+}
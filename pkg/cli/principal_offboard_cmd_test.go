@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const offboardPrincipalID = "550e8400-e29b-41d4-a716-446655440001"
+
+// newOffboardServer serves principal alice with two grants, one group
+// membership and two API keys, and records every mutating call. isAdmin sets
+// alice's admin flag.
+func newOffboardServer(t *testing.T, isAdmin bool) (*httptest.Server, *[]string) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			mu.Lock()
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/principals":
+			_, _ = w.Write([]byte(`{"data":[{"id":"` + offboardPrincipalID + `","name":"alice"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/principals/"+offboardPrincipalID:
+			_, _ = fmt.Fprintf(w, `{"id":%q,"name":"alice","is_admin":%t}`, offboardPrincipalID, isAdmin)
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/principals/"+offboardPrincipalID+"/admin":
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"is_admin":false}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/principals/"+offboardPrincipalID+"/grants:revoke-all":
+			body, _ := io.ReadAll(r.Body)
+			var req map[string]interface{}
+			require.NoError(t, json.Unmarshal(body, &req))
+			assert.Equal(t, true, req["remove_memberships"])
+			_, _ = w.Write([]byte(`{"principal_id":"` + offboardPrincipalID + `",
+				"revoked_grants":[{"id":"g-1"},{"id":"g-2"}],
+				"removed_memberships":[{"group_id":"grp-1"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/api-keys":
+			assert.Equal(t, offboardPrincipalID, r.URL.Query().Get("principal_id"))
+			_, _ = w.Write([]byte(`{"data":[{"id":"k-1","name":"laptop"},{"id":"k-2","name":"ci"}]}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestPrincipalOffboardCmd_RemovesAllAccess(t *testing.T) {
+	srv, calls := newOffboardServer(t, true)
+
+	out := runCatalogTree(t, srv, "--output", "json", "security", "principals", "offboard", "alice", "--delete-principal", "--yes")
+
+	assert.Equal(t, []string{
+		"PUT /v1/principals/" + offboardPrincipalID + "/admin",
+		"POST /v1/principals/" + offboardPrincipalID + "/grants:revoke-all",
+		"DELETE /v1/api-keys/k-1",
+		"DELETE /v1/api-keys/k-2",
+		"DELETE /v1/principals/" + offboardPrincipalID,
+	}, *calls)
+
+	var summary offboardSummary
+	require.NoError(t, json.Unmarshal([]byte(out), &summary))
+	assert.Equal(t, offboardSummary{
+		PrincipalID:        offboardPrincipalID,
+		Principal:          "alice",
+		RevokedAdmin:       true,
+		RevokedGrants:      2,
+		RemovedMemberships: 1,
+		DeletedAPIKeys:     []string{"laptop", "ci"},
+		DeletedPrincipal:   true,
+	}, summary)
+}
+
+func TestPrincipalOffboardCmd_KeepsPrincipalByDefault(t *testing.T) {
+	srv, calls := newOffboardServer(t, false)
+
+	out := runCatalogTree(t, srv, "security", "principals", "offboard", "alice", "--yes")
+
+	assert.NotContains(t, *calls, "DELETE /v1/principals/"+offboardPrincipalID)
+	assert.Contains(t, out, "group memberships")
+	assert.Contains(t, out, "api keys")
+}
+
+func TestPrincipalOffboardCmd_RevokesAdminWhenKeepingPrincipal(t *testing.T) {
+	srv, calls := newOffboardServer(t, true)
+
+	out := runCatalogTree(t, srv, "--output", "json", "security", "principals", "offboard", "alice", "--yes")
+
+	assert.Equal(t, "PUT /v1/principals/"+offboardPrincipalID+"/admin", (*calls)[0])
+	assert.NotContains(t, *calls, "DELETE /v1/principals/"+offboardPrincipalID)
+
+	var summary offboardSummary
+	require.NoError(t, json.Unmarshal([]byte(out), &summary))
+	assert.True(t, summary.RevokedAdmin)
+	assert.False(t, summary.DeletedPrincipal)
+}
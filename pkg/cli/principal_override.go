@@ -90,13 +90,26 @@ func init() {
 // If the argument looks like a UUID (contains hyphens), it is returned as-is.
 // Otherwise, it is treated as a name and resolved via the ListPrincipals API.
 func resolvePrincipalArg(client *gen.Client, arg string) (string, error) {
+	return resolveNamedArg(client, "/principals", "principal", arg)
+}
+
+// resolveGroupArg resolves a group argument that may be a name or UUID, the
+// same way resolvePrincipalArg does for principals.
+func resolveGroupArg(client *gen.Client, arg string) (string, error) {
+	return resolveNamedArg(client, "/groups", "group", arg)
+}
+
+// resolveNamedArg returns arg unchanged when it looks like a UUID. Otherwise
+// it pages through the list endpoint at listPath looking for a resource with
+// that name and returns its ID.
+func resolveNamedArg(client *gen.Client, listPath, kind, arg string) (string, error) {
 	// Heuristic: UUIDs contain hyphens, names typically don't.
 	// If it looks like a UUID, use it directly.
 	if isLikelyUUID(arg) {
 		return arg, nil
 	}
 
-	// Resolve name to UUID via ListPrincipals with pagination.
+	// Resolve name to UUID via the list endpoint with pagination.
 	var pageToken string
 	for {
 		q := url.Values{}
@@ -105,17 +118,17 @@ func resolvePrincipalArg(client *gen.Client, arg string) (string, error) {
 			q.Set("page_token", pageToken)
 		}
 
-		resp, err := client.Do("GET", "/principals", q, nil)
+		resp, err := client.Do("GET", listPath, q, nil)
 		if err != nil {
-			return "", fmt.Errorf("list principals: %w", err)
+			return "", fmt.Errorf("list %ss: %w", kind, err)
 		}
 		if err := gen.CheckError(resp); err != nil {
-			return "", fmt.Errorf("list principals: %w", err)
+			return "", fmt.Errorf("list %ss: %w", kind, err)
 		}
 
 		body, err := gen.ReadBody(resp)
 		if err != nil {
-			return "", fmt.Errorf("read principals response: %w", err)
+			return "", fmt.Errorf("read %ss response: %w", kind, err)
 		}
 
 		var result struct {
@@ -126,7 +139,7 @@ func resolvePrincipalArg(client *gen.Client, arg string) (string, error) {
 			NextPageToken *string `json:"next_page_token"`
 		}
 		if err := json.Unmarshal(body, &result); err != nil {
-			return "", fmt.Errorf("parse principals response: %w", err)
+			return "", fmt.Errorf("parse %ss response: %w", kind, err)
 		}
 
 		for _, p := range result.Data {
@@ -141,7 +154,7 @@ func resolvePrincipalArg(client *gen.Client, arg string) (string, error) {
 		pageToken = *result.NextPageToken
 	}
 
-	return "", fmt.Errorf("principal %q not found", arg)
+	return "", fmt.Errorf("%s %q not found", kind, arg)
 }
 
 // isLikelyUUID returns true if the string looks like a UUID (contains hyphens
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// getPrincipalQueryDefaults accepts a principal name and prints its
+	// search path and time zone.
+	gen.RegisterOverride("getPrincipalQueryDefaults", func(c *cobra.Command) {
+		c.Use = "get-defaults <principal>"
+	})
+	gen.RegisterRunOverride("getPrincipalQueryDefaults", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			id, err := resolvePrincipalArg(client, args[0])
+			if err != nil {
+				return err
+			}
+			raw, defaults, err := getPrincipalQueryDefaults(client, id)
+			if err != nil {
+				return err
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), raw)
+			}
+			printPrincipalQueryDefaults(cmd.OutOrStdout(), args[0], defaults)
+			return nil
+		}
+	})
+
+	// setPrincipalQueryDefaults takes the default schema as [catalog.]schema
+	// and keeps the principal's time zone unless --timezone is given.
+	gen.RegisterOverride("setPrincipalQueryDefaults", func(c *cobra.Command) {
+		c.Use = "set-default-schema <principal> [[catalog.]schema]"
+		c.Args = cobra.RangeArgs(1, 2)
+		for _, name := range []string{"default-catalog", "default-schema", "json"} {
+			_ = c.Flags().MarkHidden(name)
+		}
+	})
+	gen.RegisterRunOverride("setPrincipalQueryDefaults", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			id, err := resolvePrincipalArg(client, args[0])
+			if err != nil {
+				return err
+			}
+			_, defaults, err := getPrincipalQueryDefaults(client, id)
+			if err != nil {
+				return err
+			}
+			if len(args) == 2 {
+				defaults.DefaultCatalog, defaults.DefaultSchema = "", args[1]
+				if catalog, schema, ok := strings.Cut(args[1], "."); ok {
+					defaults.DefaultCatalog, defaults.DefaultSchema = catalog, schema
+				}
+			}
+			if cmd.Flags().Changed("timezone") {
+				defaults.Timezone, _ = cmd.Flags().GetString("timezone")
+			}
+
+			resp, err := client.Do("PUT", "/principals/"+url.PathEscape(id)+"/query-defaults", nil, defaults)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			raw, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), raw)
+			}
+			var result principalQueryDefaults
+			if err := json.Unmarshal(raw, &result); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			printPrincipalQueryDefaults(cmd.OutOrStdout(), args[0], result)
+			return nil
+		}
+	})
+}
+
+// principalQueryDefaults mirrors the API's PrincipalQueryDefaults without the
+// principal ID, so it can be sent back as a SetPrincipalQueryDefaultsRequest.
+type principalQueryDefaults struct {
+	DefaultCatalog string `json:"default_catalog"`
+	DefaultSchema  string `json:"default_schema"`
+	Timezone       string `json:"timezone"`
+}
+
+// getPrincipalQueryDefaults fetches a principal's query defaults, returning
+// both the raw response and the parsed defaults.
+func getPrincipalQueryDefaults(client *gen.Client, id string) ([]byte, principalQueryDefaults, error) {
+	var defaults principalQueryDefaults
+	resp, err := client.Do("GET", "/principals/"+url.PathEscape(id)+"/query-defaults", nil, nil)
+	if err != nil {
+		return nil, defaults, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return nil, defaults, err
+	}
+	raw, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, defaults, fmt.Errorf("read response: %w", err)
+	}
+	if err := json.Unmarshal(raw, &defaults); err != nil {
+		return nil, defaults, fmt.Errorf("parse response: %w", err)
+	}
+	return raw, defaults, nil
+}
+
+// printPrincipalQueryDefaults renders the defaults, showing unset ones as the
+// server default.
+func printPrincipalQueryDefaults(w io.Writer, principal string, d principalQueryDefaults) {
+	orDefault := func(v string) string {
+		if v == "" {
+			return "(server default)"
+		}
+		return v
+	}
+	schema := d.DefaultSchema
+	if d.DefaultCatalog != "" {
+		schema = d.DefaultCatalog + "." + schema
+		if d.DefaultSchema == "" {
+			schema += "main"
+		}
+	}
+	gen.PrintTable(w, []string{"principal", "default_schema", "timezone"},
+		[][]string{{principal, orDefault(schema), orDefault(d.Timezone)}})
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// principalQueryDefaultsServer serves one principal named alice whose query
+// defaults start as current, recording the defaults PUT to it.
+func principalQueryDefaultsServer(t *testing.T, current map[string]string, put *map[string]string) *httptest.Server {
+	t.Helper()
+	path := "/v1/principals/" + principalAttrsTestID + "/query-defaults"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/principals" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"data":[{"id":"` + principalAttrsTestID + `","name":"alice"}]}`))
+		case r.URL.Path == path && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(current)
+		case r.URL.Path == path && r.Method == http.MethodPut:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(put))
+			_ = json.NewEncoder(w).Encode(*put)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPrincipalSetDefaultSchemaOverride(t *testing.T) {
+	current := map[string]string{"default_catalog": "", "default_schema": "", "timezone": "Europe/Amsterdam"}
+	tests := []struct {
+		name    string
+		args    []string
+		wantPut map[string]string
+	}{
+		{
+			name:    "catalog and schema keep the time zone",
+			args:    []string{"alice", "demo.analytics"},
+			wantPut: map[string]string{"default_catalog": "demo", "default_schema": "analytics", "timezone": "Europe/Amsterdam"},
+		},
+		{
+			name:    "schema only",
+			args:    []string{"alice", "analytics"},
+			wantPut: map[string]string{"default_catalog": "", "default_schema": "analytics", "timezone": "Europe/Amsterdam"},
+		},
+		{
+			name:    "time zone only",
+			args:    []string{"alice", "--timezone", "UTC"},
+			wantPut: map[string]string{"default_catalog": "", "default_schema": "", "timezone": "UTC"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var put map[string]string
+			srv := principalQueryDefaultsServer(t, current, &put)
+
+			var out bytes.Buffer
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL, "security", "principals", "set-default-schema"}, tt.args...))
+			require.NoError(t, rootCmd.Execute())
+
+			assert.Equal(t, tt.wantPut, put)
+			assert.Contains(t, out.String(), "alice")
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// purgeQueryHistory can archive the entries it is about to delete as
+	// NDJSON before purging them.
+	gen.RegisterOverride("purgeQueryHistory", func(c *cobra.Command) {
+		c.Flags().String("archive", "", "Write the entries to purge to this NDJSON file first (must not exist)")
+		c.MarkFlagsMutuallyExclusive("older-than", "before")
+	})
+
+	gen.RegisterRunOverride("purgeQueryHistory", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, _ []string) error {
+			olderThan, _ := cmd.Flags().GetString("older-than")
+			before, _ := cmd.Flags().GetString("before")
+			archive, _ := cmd.Flags().GetString("archive")
+
+			query := url.Values{}
+			if olderThan != "" {
+				query.Set("older_than", olderThan)
+			}
+			if before != "" {
+				query.Set("before", before)
+			}
+
+			if archive != "" {
+				// Fix the cutoff once so the archive and the purge cover
+				// the same entries.
+				cutoff, err := purgeCutoff(olderThan, before, time.Now())
+				if err != nil {
+					return err
+				}
+				query = url.Values{"before": {cutoff.UTC().Format(time.RFC3339)}}
+			}
+
+			if !cmd.Flags().Changed("yes") {
+				if !gen.ConfirmPrompt("Permanently delete the selected query history?") {
+					return nil
+				}
+			}
+
+			if archive != "" {
+				n, err := archiveQueryHistory(client, query.Get("before"), archive)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Archived %d entries to %s\n", n, archive)
+			}
+
+			resp, err := client.Do("DELETE", "/admin/query-history", query, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			body, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			if getOutputFormat(cmd) == "json" {
+				return printRawJSON(cmd.OutOrStdout(), body)
+			}
+			var result struct {
+				DeletedCount int64  `json:"deleted_count"`
+				Before       string `json:"before"`
+			}
+			if err := json.Unmarshal(body, &result); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d query history entries recorded before %s\n", result.DeletedCount, result.Before)
+			return nil
+		}
+	})
+}
+
+// purgeCutoff resolves --older-than or --before to the time before which
+// entries are purged. Archiving needs the cutoff up front, so one of them
+// must be given; the server's retention period is not known to the CLI.
+func purgeCutoff(olderThan, before string, now time.Time) (time.Time, error) {
+	switch {
+	case olderThan != "":
+		d, err := parseAge(olderThan)
+		if err != nil {
+			return time.Time{}, err
+		}
+		// The server stores history with second precision.
+		return now.Add(-d).Truncate(time.Second), nil
+	case before != "":
+		t, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --before %q: use an RFC 3339 time", before)
+		}
+		return t, nil
+	default:
+		return time.Time{}, fmt.Errorf("--archive needs --older-than or --before")
+	}
+}
+
+// parseAge parses an age such as 90d or 36h, the same forms the server
+// accepts for older_than.
+func parseAge(v string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --older-than %q: expected a number of days such as 90d", v)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(v); err != nil {
+			return 0, fmt.Errorf("invalid --older-than %q: expected a duration such as 90d or 36h", v)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid --older-than %q: must be positive", v)
+	}
+	return d, nil
+}
+
+// archiveQueryHistory writes every query history entry recorded up to before
+// to a new NDJSON file at path, one entry per line as the server returns it,
+// and returns how many were written. A partial file is removed on failure.
+func archiveQueryHistory(client *gen.Client, before, path string) (int, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) //nolint:gosec // path is chosen by the operator
+	if err != nil {
+		return 0, fmt.Errorf("create archive file: %w", err)
+	}
+	n, err := writeQueryHistoryNDJSON(client, before, f)
+	if err == nil {
+		err = f.Close()
+	} else {
+		_ = f.Close()
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return 0, fmt.Errorf("archive query history: %w", err)
+	}
+	return n, nil
+}
+
+func writeQueryHistoryNDJSON(client *gen.Client, before string, w io.Writer) (int, error) {
+	query := url.Values{"to": {before}, "max_results": {"1000"}}
+	n := 0
+	for {
+		resp, err := client.Do("GET", "/query-history", query, nil)
+		if err != nil {
+			return n, err
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return n, err
+		}
+		body, err := gen.ReadBody(resp)
+		if err != nil {
+			return n, fmt.Errorf("read response: %w", err)
+		}
+		var page struct {
+			Data          []json.RawMessage `json:"data"`
+			NextPageToken string            `json:"next_page_token"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return n, fmt.Errorf("parse response: %w", err)
+		}
+		for _, e := range page.Data {
+			if _, err := fmt.Fprintf(w, "%s\n", e); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if page.NextPageToken == "" {
+			return n, nil
+		}
+		query.Set("page_token", page.NextPageToken)
+	}
+}
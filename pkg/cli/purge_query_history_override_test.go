@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeQueryHistoryOverride(t *testing.T) {
+	t.Run("older_than is forwarded", func(t *testing.T) {
+		var gotMethod, gotQuery string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/v1/admin/query-history", r.URL.Path)
+			gotMethod, gotQuery = r.Method, r.URL.RawQuery
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"deleted_count":42,"before":"2025-01-15T10:30:00Z"}`))
+		}))
+		defer srv.Close()
+
+		var out strings.Builder
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--host", srv.URL, "admin", "purge-query-history", "--older-than", "90d", "--yes"})
+		require.NoError(t, rootCmd.Execute())
+
+		assert.Equal(t, http.MethodDelete, gotMethod)
+		assert.Equal(t, "older_than=90d", gotQuery)
+		assert.Contains(t, out.String(), "Deleted 42 query history entries recorded before 2025-01-15T10:30:00Z")
+	})
+
+	t.Run("archive writes NDJSON before purging with the same cutoff", func(t *testing.T) {
+		var listTo, purgeBefore []string
+		var calls []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			calls = append(calls, r.Method)
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/v1/query-history":
+				listTo = append(listTo, r.URL.Query().Get("to"))
+				if r.URL.Query().Get("page_token") == "" {
+					_, _ = w.Write([]byte(`{"data":[{"id":"q1","status":"ALLOWED"}],"next_page_token":"p2"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"data":[{"id":"q2","status":"ERROR"}]}`))
+			case r.Method == http.MethodDelete && r.URL.Path == "/v1/admin/query-history":
+				assert.Empty(t, r.URL.Query().Get("older_than"))
+				purgeBefore = append(purgeBefore, r.URL.Query().Get("before"))
+				_, _ = w.Write([]byte(`{"deleted_count":2,"before":"` + r.URL.Query().Get("before") + `"}`))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+		defer srv.Close()
+
+		archive := filepath.Join(t.TempDir(), "history.ndjson")
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&strings.Builder{})
+		rootCmd.SetErr(&strings.Builder{})
+		rootCmd.SetArgs([]string{"--host", srv.URL, "admin", "purge-query-history", "--older-than", "90d", "--archive", archive, "--yes"})
+		require.NoError(t, rootCmd.Execute())
+
+		assert.Equal(t, []string{http.MethodGet, http.MethodGet, http.MethodDelete}, calls)
+		require.Len(t, purgeBefore, 1)
+		assert.Equal(t, []string{purgeBefore[0], purgeBefore[0]}, listTo)
+		cutoff, err := time.Parse(time.RFC3339, purgeBefore[0])
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, -90), cutoff, time.Minute)
+
+		data, err := os.ReadFile(archive) //nolint:gosec // test path
+		require.NoError(t, err)
+		assert.Equal(t, "{\"id\":\"q1\",\"status\":\"ALLOWED\"}\n{\"id\":\"q2\",\"status\":\"ERROR\"}\n", string(data))
+	})
+
+	t.Run("archive needs a cutoff", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}))
+		defer srv.Close()
+
+		rootCmd := newRootCmd()
+		rootCmd.SetArgs([]string{"--host", srv.URL, "admin", "purge-query-history", "--archive", filepath.Join(t.TempDir(), "h.ndjson"), "--yes"})
+		err := rootCmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--archive needs --older-than or --before")
+	})
+}
+
+func TestParseAge(t *testing.T) {
+	d, err := parseAge("90d")
+	require.NoError(t, err)
+	assert.Equal(t, 90*24*time.Hour, d)
+
+	d, err = parseAge("36h")
+	require.NoError(t, err)
+	assert.Equal(t, 36*time.Hour, d)
+
+	for _, v := range []string{"0d", "xd", "soon"} {
+		_, err := parseAge(v)
+		assert.Error(t, err, v)
+	}
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// queryHistoryEntry is the subset of a query history entry the viewer prints.
+type queryHistoryEntry struct {
+	ID             string   `json:"id"`
+	PrincipalName  string   `json:"principal_name"`
+	OriginalSQL    *string  `json:"original_sql,omitempty"`
+	StatementType  *string  `json:"statement_type,omitempty"`
+	TablesAccessed []string `json:"tables_accessed,omitempty"`
+	Status         string   `json:"status"`
+	ErrorMessage   *string  `json:"error_message,omitempty"`
+	DurationMs     *int64   `json:"duration_ms,omitempty"`
+	RowsReturned   *int64   `json:"rows_returned,omitempty"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+var queryHistoryColumns = []string{"time", "principal", "status", "duration", "rows", "tables"}
+
+// newQueryHistoryCmd builds `duck query history`, a viewer over the filtered
+// query history endpoint. With --follow it keeps polling and prints entries
+// as they are recorded, like tail -f.
+func newQueryHistoryCmd(client *gen.Client) *cobra.Command {
+	var (
+		principal string
+		table     string
+		status    string
+		since     string
+		mine      bool
+		limit     int64
+		follow    bool
+		interval  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show query execution history",
+		Long: "Lists recorded queries newest first, with their status, duration and row count. " +
+			"Listing other principals' queries requires admin privileges; --mine shows the caller's own. " +
+			"With --follow the command prints matching history oldest first and then polls for new entries until interrupted.",
+		Example: "  duck query history --mine --since 1h\n" +
+			"  duck query history --table orders --status ERROR\n" +
+			"  duck query history --principal alice --follow",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query := url.Values{}
+			if principal != "" {
+				query.Set("principal_name", principal)
+			}
+			if table != "" {
+				query.Set("table_name", table)
+			}
+			if status != "" {
+				query.Set("status", strings.ToUpper(status))
+			}
+			if mine {
+				query.Set("mine", "true")
+			}
+			if since != "" {
+				from, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				query.Set("from", from.UTC().Format(time.RFC3339))
+			}
+			if limit > 0 {
+				query.Set("max_results", strconv.FormatInt(limit, 10))
+			}
+
+			if follow {
+				if interval <= 0 {
+					return fmt.Errorf("--interval must be positive")
+				}
+				return followQueryHistory(cmd, client, query, interval)
+			}
+
+			entries, err := listQueryHistory(client, query, false)
+			if err != nil {
+				return err
+			}
+			if getOutputFormat(cmd) == "json" {
+				return gen.PrintJSON(cmd.OutOrStdout(), map[string]interface{}{"data": entries})
+			}
+			rows := make([][]string, 0, len(entries))
+			for _, e := range entries {
+				rows = append(rows, queryHistoryRow(e))
+			}
+			gen.PrintTable(cmd.OutOrStdout(), queryHistoryColumns, rows)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&principal, "principal", "", "Only show queries run by this principal")
+	cmd.Flags().StringVar(&table, "table", "", "Only show queries that accessed this table (name or qualified name)")
+	cmd.Flags().StringVar(&status, "status", "", "Only show queries with this status (e.g. ALLOWED, DENIED, ERROR)")
+	cmd.Flags().StringVar(&since, "since", "", "Only show queries since a duration ago (e.g. 30m, 24h) or an RFC 3339 time")
+	cmd.Flags().BoolVar(&mine, "mine", false, "Only show queries run by the caller")
+	cmd.Flags().Int64Var(&limit, "limit", 50, "Maximum number of entries to show")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep polling and print new entries as they arrive")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval for --follow")
+	cmd.MarkFlagsMutuallyExclusive("principal", "mine")
+
+	return cmd
+}
+
+// parseSince accepts either a Go duration, read as that long before now, or
+// an RFC 3339 timestamp.
+func parseSince(v string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid --since %q: duration must not be negative", v)
+		}
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: use a duration like 1h or an RFC 3339 time", v)
+	}
+	return t, nil
+}
+
+// listQueryHistory fetches history entries, newest first. With all set it
+// follows page tokens; otherwise it returns the first page only.
+func listQueryHistory(client *gen.Client, query url.Values, all bool) ([]queryHistoryEntry, error) {
+	var entries []queryHistoryEntry
+	for {
+		resp, err := client.Do("GET", "/query-history", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return nil, err
+		}
+		body, err := gen.ReadBody(resp)
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+		var page struct {
+			Data          []queryHistoryEntry `json:"data"`
+			NextPageToken string              `json:"next_page_token"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("parse response: %w", err)
+		}
+		entries = append(entries, page.Data...)
+		if !all || page.NextPageToken == "" {
+			return entries, nil
+		}
+		query.Set("page_token", page.NextPageToken)
+	}
+}
+
+// followQueryHistory prints the current matching history oldest first, then
+// polls from the newest entry seen and prints only entries it has not shown.
+func followQueryHistory(cmd *cobra.Command, client *gen.Client, query url.Values, interval time.Duration) error {
+	w := cmd.OutOrStdout()
+	asJSON := getOutputFormat(cmd) == "json"
+	if !asJSON {
+		printQueryHistoryLine(w, queryHistoryColumns)
+	}
+
+	seen := make(map[string]bool)
+	latest := ""
+	show := func(entries []queryHistoryEntry) error {
+		// Entries arrive newest first; print them in the order they ran.
+		for i := len(entries) - 1; i >= 0; i-- {
+			e := entries[i]
+			if seen[e.ID] {
+				continue
+			}
+			seen[e.ID] = true
+			if e.CreatedAt > latest {
+				latest = e.CreatedAt
+			}
+			if asJSON {
+				line, err := json.Marshal(e)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(w, string(line))
+				continue
+			}
+			printQueryHistoryLine(w, queryHistoryRow(e))
+		}
+		return nil
+	}
+
+	entries, err := listQueryHistory(client, query, false)
+	if err != nil {
+		return err
+	}
+	if err := show(entries); err != nil {
+		return err
+	}
+
+	// Polls page through everything newer than the last entry seen, so a
+	// burst larger than one page is not dropped.
+	query.Del("max_results")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+		if latest != "" {
+			query.Set("from", latest)
+		}
+		query.Del("page_token")
+		entries, err := listQueryHistory(client, query, true)
+		if err != nil {
+			return err
+		}
+		if err := show(entries); err != nil {
+			return err
+		}
+	}
+}
+
+func queryHistoryRow(e queryHistoryEntry) []string {
+	duration, rows := "-", "-"
+	if e.DurationMs != nil {
+		duration = fmt.Sprintf("%dms", *e.DurationMs)
+	}
+	if e.RowsReturned != nil {
+		rows = strconv.FormatInt(*e.RowsReturned, 10)
+	}
+	tables := strings.Join(e.TablesAccessed, ",")
+	if tables == "" {
+		tables = "-"
+	}
+	return []string{e.CreatedAt, e.PrincipalName, e.Status, duration, rows, tables}
+}
+
+// printQueryHistoryLine writes one fixed-width row. Follow mode streams rows
+// as they arrive, so it cannot size columns up front as gen.PrintTable does.
+func printQueryHistoryLine(w io.Writer, cols []string) {
+	_, _ = fmt.Fprintf(w, "%-20s  %-20s  %-8s  %10s  %8s  %s\n", cols[0], cols[1], cols[2], cols[3], cols[4], cols[5])
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const queryHistoryPage = `{"data":[
+  {"id":"q2","principal_name":"alice","status":"ERROR","duration_ms":7,"tables_accessed":["main.analytics.orders"],"created_at":"2026-01-02T10:00:05Z"},
+  {"id":"q1","principal_name":"alice","status":"ALLOWED","duration_ms":42,"rows_returned":10,"tables_accessed":["main.analytics.orders"],"created_at":"2026-01-02T10:00:00Z"}
+]}`
+
+func TestQueryHistoryCmd_PassesFiltersToAPI(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(jsonHandler(rec, http.StatusOK, queryHistoryPage))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "query", "history",
+		"--principal", "alice", "--table", "orders", "--status", "error",
+		"--since", "2026-01-02T09:00:00Z", "--limit", "20"})
+	require.NoError(t, rootCmd.Execute())
+
+	req := rec.last()
+	assert.Equal(t, "/v1/query-history", req.Path)
+	q, err := url.ParseQuery(req.Query)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", q.Get("principal_name"))
+	assert.Equal(t, "orders", q.Get("table_name"))
+	assert.Equal(t, "ERROR", q.Get("status"))
+	assert.Equal(t, "2026-01-02T09:00:00Z", q.Get("from"))
+	assert.Equal(t, "20", q.Get("max_results"))
+	assert.False(t, q.Has("mine"))
+
+	text := out.String()
+	assert.Regexp(t, `2026-01-02T10:00:00Z\s+alice\s+ALLOWED\s+42ms\s+10\s+main\.analytics\.orders`, text)
+	assert.Regexp(t, `2026-01-02T10:00:05Z\s+alice\s+ERROR\s+7ms\s+-`, text)
+}
+
+func TestQueryHistoryCmd_Mine(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(jsonHandler(rec, http.StatusOK, `{"data":[]}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetOut(&strings.Builder{})
+	rootCmd.SetArgs([]string{"--host", srv.URL, "query", "history", "--mine"})
+	require.NoError(t, rootCmd.Execute())
+
+	q, err := url.ParseQuery(rec.last().Query)
+	require.NoError(t, err)
+	assert.Equal(t, "true", q.Get("mine"))
+	assert.False(t, q.Has("principal_name"))
+}
+
+func TestQueryHistoryCmd_MineAndPrincipalConflict(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetOut(&strings.Builder{})
+	rootCmd.SetErr(&strings.Builder{})
+	rootCmd.SetArgs([]string{"--host", srv.URL, "query", "history", "--mine", "--principal", "bob"})
+	require.Error(t, rootCmd.Execute())
+}
+
+func TestQueryHistoryCmd_FollowPrintsOnlyNewEntries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		calls int
+		froms []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		froms = append(froms, r.URL.Query().Get("from"))
+		w.Header().Set("Content-Type", "application/json")
+		switch calls {
+		case 1:
+			_, _ = w.Write([]byte(queryHistoryPage))
+		default:
+			// The poll overlaps the last entry seen; only q3 is new.
+			_, _ = w.Write([]byte(`{"data":[
+  {"id":"q3","principal_name":"bob","status":"ALLOWED","duration_ms":3,"rows_returned":1,"created_at":"2026-01-02T10:00:09Z"},
+  {"id":"q2","principal_name":"alice","status":"ERROR","duration_ms":7,"created_at":"2026-01-02T10:00:05Z"}
+]}`))
+			cancel()
+		}
+	}))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "query", "history", "--follow", "--interval", "10ms"})
+
+	done := make(chan error, 1)
+	go func() { done <- rootCmd.ExecuteContext(ctx) }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("follow did not stop after the context was cancelled")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(t, len(froms), 2)
+	assert.Empty(t, froms[0])
+	assert.Equal(t, "2026-01-02T10:00:05Z", froms[1], "polls resume from the newest entry seen")
+
+	text := out.String()
+	assert.Equal(t, 1, strings.Count(text, "ERROR"), "entries already shown are not repeated")
+	// Oldest first, like tail -f.
+	i1, i2, i3 := strings.Index(text, "10:00:00Z"), strings.Index(text, "10:00:05Z"), strings.Index(text, "10:00:09Z")
+	assert.True(t, i1 >= 0 && i1 < i2 && i2 < i3, "expected chronological order, got:\n%s", text)
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseSince("90m", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC), got)
+
+	got, err = parseSince("2026-01-01T00:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), got)
+
+	_, err = parseSince("yesterday", now)
+	require.Error(t, err)
+}
@@ -19,21 +19,53 @@ import (
 func init() {
 	gen.RegisterRunOverride("executeQuery", func(client *gen.Client) func(*cobra.Command, []string) error {
 		return func(cmd *cobra.Command, _ []string) error {
-			sql, err := readSQLInput(cmd)
-			if err != nil {
-				return err
+			maxRows, _ := cmd.Flags().GetInt64("max-rows")
+			if maxRows <= 0 {
+				maxRows = defaultQueryPageSize
+			}
+			all, _ := cmd.Flags().GetBool("all")
+
+			body := map[string]interface{}{"max_rows": maxRows}
+			if token, _ := cmd.Flags().GetString("result-token"); token != "" {
+				body["result_token"] = token
+			} else {
+				sql, err := readSQLInput(cmd)
+				if err != nil {
+					return err
+				}
+				if async, _ := cmd.Flags().GetBool("async"); async {
+					return submitQuery(cmd, client, sql)
+				}
+				body["sql"] = sql
+				if showPolicy, _ := cmd.Flags().GetBool("show-policy"); showPolicy {
+					body["include_policy_metadata"] = true
+				}
 			}
 
-			body := map[string]interface{}{"sql": sql}
-			resp, err := client.Do("POST", "/query", nil, body)
+			page, err := postQueryPage(client, body)
 			if err != nil {
 				return err
 			}
-			if err := gen.CheckError(resp); err != nil {
-				return err
+			if !all {
+				return printQueryResultBody(cmd, page.raw, "--result-token")
 			}
 
-			return printQueryResult(cmd, resp)
+			// Follow result tokens and print the combined result once.
+			combined := page.queryPage
+			for page.NextPageToken != "" {
+				page, err = postQueryPage(client, map[string]interface{}{"result_token": page.NextPageToken, "max_rows": maxRows})
+				if err != nil {
+					return err
+				}
+				combined.Rows = append(combined.Rows, page.Rows...)
+			}
+			combined.RowCount = len(combined.Rows)
+			combined.NextPageToken = ""
+			raw, err := json.Marshal(combined)
+			if err != nil {
+				return fmt.Errorf("encode result: %w", err)
+			}
+			return printQueryResultBody(cmd, raw, "")
 		}
 	})
 
@@ -43,71 +75,7 @@ func init() {
 			if err != nil {
 				return err
 			}
-
-			requestID, _ := cmd.Flags().GetString("request-id")
-			body := map[string]interface{}{"sql": sql}
-			if requestID != "" {
-				body["request_id"] = requestID
-			}
-
-			resp, err := client.Do("POST", "/queries", nil, body)
-			if err != nil {
-				return err
-			}
-			if err := gen.CheckError(resp); err != nil {
-				return err
-			}
-
-			respBody, err := gen.ReadBody(resp)
-			if err != nil {
-				return fmt.Errorf("read response: %w", err)
-			}
-
-			var submit struct {
-				QueryID string `json:"query_id"`
-			}
-			if err := json.Unmarshal(respBody, &submit); err != nil {
-				return fmt.Errorf("parse response: %w", err)
-			}
-
-			wait, _ := cmd.Flags().GetBool("wait")
-			if !wait {
-				return printAnyResponse(cmd, respBody)
-			}
-
-			pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
-			if pollInterval <= 0 {
-				pollInterval = 1 * time.Second
-			}
-			timeout, _ := cmd.Flags().GetDuration("wait-timeout")
-
-			statusResp, err := waitForQuery(client, submit.QueryID, pollInterval, timeout)
-			if err != nil {
-				return err
-			}
-
-			if statusResp.Status != "SUCCEEDED" {
-				return printAnyResponse(cmd, statusResp.Raw)
-			}
-
-			showResults, _ := cmd.Flags().GetBool("results")
-			if !showResults {
-				return printAnyResponse(cmd, statusResp.Raw)
-			}
-
-			maxResults, _ := cmd.Flags().GetInt64("max-results")
-			query := url.Values{}
-			if maxResults > 0 {
-				query.Set("max_results", fmt.Sprintf("%d", maxResults))
-			}
-			resultsResp, err := client.Do("GET", "/queries/"+submit.QueryID+"/results", query, nil)
-			if err != nil {
-				return err
-			}
-			if err := gen.CheckError(resultsResp); err != nil {
-				return err
-			}
-			return printQueryResult(cmd, resultsResp)
+			return submitQuery(cmd, client, sql)
 		}
 	})
 
@@ -202,6 +170,18 @@ func init() {
 		}
 	})
 
+	gen.RegisterOverride("executeQuery", func(c *cobra.Command) {
+		c.Flags().Bool("async", false, "Submit as an async query job and print its ID instead of waiting for results")
+		c.Flags().Bool("all", false, "Fetch every page of the result instead of only the first")
+		c.Flags().Bool("show-policy", false, "Report which columns were masked and which tables were row-filtered for you")
+		if f := c.Flags().Lookup("include-policy-metadata"); f != nil {
+			f.Hidden = true
+		}
+		if f := c.Flags().Lookup("max-rows"); f != nil {
+			f.Usage = fmt.Sprintf("Rows per page (default %d)", defaultQueryPageSize)
+		}
+	})
+
 	gen.RegisterOverride("submitQuery", func(c *cobra.Command) {
 		c.Flags().Bool("wait", false, "Wait for query completion")
 		c.Flags().Duration("poll-interval", time.Second, "Status polling interval when --wait is enabled")
@@ -211,6 +191,76 @@ func init() {
 	})
 }
 
+// submitQuery submits sql as an async query job and prints the job, or with
+// --wait polls until it finishes. It backs both `duck query submit` and
+// `duck query execute --async`; flags the command does not define read as unset.
+func submitQuery(cmd *cobra.Command, client *gen.Client, sql string) error {
+	requestID, _ := cmd.Flags().GetString("request-id")
+	body := map[string]interface{}{"sql": sql}
+	if requestID != "" {
+		body["request_id"] = requestID
+	}
+
+	resp, err := client.Do("POST", "/queries", nil, body)
+	if err != nil {
+		return err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return err
+	}
+
+	respBody, err := gen.ReadBody(resp)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	var submit struct {
+		QueryID string `json:"query_id"`
+	}
+	if err := json.Unmarshal(respBody, &submit); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	wait, _ := cmd.Flags().GetBool("wait")
+	if !wait {
+		return printAnyResponse(cmd, respBody)
+	}
+
+	pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+	if pollInterval <= 0 {
+		pollInterval = 1 * time.Second
+	}
+	timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+
+	statusResp, err := waitForQuery(client, submit.QueryID, pollInterval, timeout)
+	if err != nil {
+		return err
+	}
+
+	if statusResp.Status != "SUCCEEDED" {
+		return printAnyResponse(cmd, statusResp.Raw)
+	}
+
+	showResults, _ := cmd.Flags().GetBool("results")
+	if !showResults {
+		return printAnyResponse(cmd, statusResp.Raw)
+	}
+
+	maxResults, _ := cmd.Flags().GetInt64("max-results")
+	query := url.Values{}
+	if maxResults > 0 {
+		query.Set("max_results", fmt.Sprintf("%d", maxResults))
+	}
+	resultsResp, err := client.Do("GET", "/queries/"+submit.QueryID+"/results", query, nil)
+	if err != nil {
+		return err
+	}
+	if err := gen.CheckError(resultsResp); err != nil {
+		return err
+	}
+	return printQueryResult(cmd, resultsResp)
+}
+
 func readSQLInput(cmd *cobra.Command) (string, error) {
 	sql, _ := cmd.Flags().GetString("sql")
 
@@ -261,18 +311,65 @@ func printAnyResponse(cmd *cobra.Command, body []byte) error {
 	}
 }
 
+// defaultQueryPageSize is the page size `duck query execute` requests when
+// --max-rows is not set.
+const defaultQueryPageSize = 1000
+
+// queryPage is a QueryResult response.
+type queryPage struct {
+	Columns        []string             `json:"columns"`
+	Rows           [][]interface{}      `json:"rows"`
+	RowCount       int                  `json:"row_count"`
+	NextPageToken  string               `json:"next_page_token,omitempty"`
+	PolicyMetadata *queryPolicyMetadata `json:"policy_metadata,omitempty"`
+}
+
+// queryPolicyMetadata is the policy_metadata of a QueryResult.
+type queryPolicyMetadata struct {
+	Tables []struct {
+		Table         string   `json:"table"`
+		MaskedColumns []string `json:"masked_columns"`
+		RowFiltered   bool     `json:"row_filtered"`
+	} `json:"tables"`
+}
+
+type rawQueryPage struct {
+	queryPage
+	raw []byte
+}
+
+// postQueryPage sends body to POST /query and decodes the result page.
+func postQueryPage(client *gen.Client, body map[string]interface{}) (*rawQueryPage, error) {
+	resp, err := client.Do("POST", "/query", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return nil, err
+	}
+	raw, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	page := &rawQueryPage{raw: raw}
+	if err := json.Unmarshal(raw, &page.queryPage); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return page, nil
+}
+
 func printQueryResult(cmd *cobra.Command, resp *http.Response) error {
 	respBody, err := gen.ReadBody(resp)
 	if err != nil {
 		return fmt.Errorf("read response: %w", err)
 	}
+	return printQueryResultBody(cmd, respBody, "--page-token")
+}
 
-	var result struct {
-		Columns       []string        `json:"columns"`
-		Rows          [][]interface{} `json:"rows"`
-		RowCount      int             `json:"row_count"`
-		NextPageToken string          `json:"next_page_token"`
-	}
+// printQueryResultBody prints a QueryResult. pageFlag names the flag that
+// fetches the next page in the hint printed after a partial table.
+func printQueryResultBody(cmd *cobra.Command, respBody []byte, pageFlag string) error {
+	var result queryPage
 	if err := json.Unmarshal(respBody, &result); err != nil {
 		return fmt.Errorf("parse response: %w", err)
 	}
@@ -316,8 +413,11 @@ func printQueryResult(cmd *cobra.Command, resp *http.Response) error {
 			}
 		}
 		gen.PrintTable(os.Stdout, result.Columns, rows)
+		if result.PolicyMetadata != nil {
+			printQueryPolicy(os.Stderr, result.PolicyMetadata)
+		}
 		if result.NextPageToken != "" {
-			fmt.Fprintf(os.Stderr, "\n(%d rows, more pages available: --page-token %s)\n", result.RowCount, result.NextPageToken)
+			fmt.Fprintf(os.Stderr, "\n(%d rows, more pages available: %s %s)\n", result.RowCount, pageFlag, result.NextPageToken)
 		} else {
 			fmt.Fprintf(os.Stderr, "\n(%d rows)\n", result.RowCount)
 		}
@@ -325,6 +425,25 @@ func printQueryResult(cmd *cobra.Command, resp *http.Response) error {
 	}
 }
 
+// printQueryPolicy prints one line per table the query read, saying whether
+// its rows were filtered and which of its columns were masked.
+func printQueryPolicy(w io.Writer, meta *queryPolicyMetadata) {
+	_, _ = fmt.Fprintln(w)
+	for _, t := range meta.Tables {
+		var notes []string
+		if t.RowFiltered {
+			notes = append(notes, "rows filtered")
+		}
+		if len(t.MaskedColumns) > 0 {
+			notes = append(notes, "values masked: "+strings.Join(t.MaskedColumns, ", "))
+		}
+		if len(notes) == 0 {
+			notes = append(notes, "no policies applied")
+		}
+		_, _ = fmt.Fprintf(w, "policy: %s: %s\n", t.Table, strings.Join(notes, "; "))
+	}
+}
+
 type waitedStatus struct {
 	Status string
 	Raw    []byte
@@ -80,6 +80,21 @@ func TestQueryOverride(t *testing.T) {
 			response:   `{"columns":["id","name"],"rows":[[1,null],[2,"bob"]],"row_count":2}`,
 			wantErr:    false,
 		},
+		{
+			name:       "async submits a query job",
+			args:       []string{"query", "execute", "--sql", "SELECT * FROM big_table", "--async"},
+			statusCode: http.StatusAccepted,
+			response:   `{"query_id":"job-1","status":"QUEUED"}`,
+			wantErr:    false,
+			checkReq: func(t *testing.T, c captured) {
+				t.Helper()
+				assert.Equal(t, "POST", c.method)
+				assert.Equal(t, "/v1/queries", c.path)
+				var body map[string]interface{}
+				require.NoError(t, json.Unmarshal(c.body, &body))
+				assert.Equal(t, "SELECT * FROM big_table", body["sql"])
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -206,3 +221,102 @@ func TestQueryOverride_SubmitAndWaitResults(t *testing.T) {
 		assert.Contains(t, joined, "/v1/queries/q-2/results")
 	})
 }
+
+func TestQueryOverride_Paging(t *testing.T) {
+	// pagingServer serves "SELECT ..." as three pages of two rows.
+	pagingServer := func(t *testing.T, bodies *[]map[string]interface{}) *httptest.Server {
+		t.Helper()
+		var mu sync.Mutex
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			*bodies = append(*bodies, body)
+
+			w.Header().Set("Content-Type", "application/json")
+			switch body["result_token"] {
+			case nil:
+				_, _ = w.Write([]byte(`{"columns":["id"],"rows":[[1],[2]],"row_count":2,"next_page_token":"tok"}`))
+			case "tok":
+				if len(*bodies) == 2 {
+					_, _ = w.Write([]byte(`{"columns":["id"],"rows":[[3],[4]],"row_count":2,"next_page_token":"tok"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"columns":["id"],"rows":[[5]],"row_count":1}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":404,"message":"result token is unknown or expired"}`))
+			}
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("first page by default", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		var bodies []map[string]interface{}
+		srv := pagingServer(t, &bodies)
+
+		rootCmd := newRootCmd()
+		rootCmd.SetArgs([]string{"--host", srv.URL, "query", "execute", "--sql", "SELECT id FROM t"})
+		require.NoError(t, rootCmd.Execute())
+
+		require.Len(t, bodies, 1)
+		assert.InDelta(t, defaultQueryPageSize, bodies[0]["max_rows"], 0)
+	})
+
+	t.Run("all follows result tokens", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		var bodies []map[string]interface{}
+		srv := pagingServer(t, &bodies)
+
+		rootCmd := newRootCmd()
+		rootCmd.SetArgs([]string{"--host", srv.URL, "query", "execute", "--sql", "SELECT id FROM t", "--max-rows", "2", "--all", "--output", "json"})
+		require.NoError(t, rootCmd.Execute())
+
+		require.Len(t, bodies, 3)
+		assert.Equal(t, "SELECT id FROM t", bodies[0]["sql"])
+		assert.InDelta(t, 2, bodies[0]["max_rows"], 0)
+		for _, b := range bodies[1:] {
+			assert.Equal(t, "tok", b["result_token"])
+			assert.NotContains(t, b, "sql")
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		var bodies []map[string]interface{}
+		srv := pagingServer(t, &bodies)
+
+		rootCmd := newRootCmd()
+		rootCmd.SetArgs([]string{"--host", srv.URL, "query", "execute", "--result-token", "stale"})
+		require.ErrorContains(t, rootCmd.Execute(), "HTTP 404")
+	})
+}
+
+func TestQueryOverride_ShowPolicy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"columns":["email"],"rows":[["***"]],"row_count":1,
+			"policy_metadata":{"tables":[{"table":"main.users","masked_columns":["email"],"row_filtered":true}]}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"--host", srv.URL, "query", "execute", "--sql", "SELECT email FROM main.users", "--show-policy"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, true, body["include_policy_metadata"])
+
+	var meta queryPolicyMetadata
+	require.NoError(t, json.Unmarshal([]byte(`{"tables":[
+		{"table":"main.users","masked_columns":["email","ssn"],"row_filtered":true},
+		{"table":"main.orders","masked_columns":[],"row_filtered":false}]}`), &meta))
+	var out strings.Builder
+	printQueryPolicy(&out, &meta)
+	assert.Contains(t, out.String(), "policy: main.users: rows filtered; values masked: email, ssn")
+	assert.Contains(t, out.String(), "policy: main.orders: no policies applied")
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// registerDirectory takes the storage prefix positionally and --format for
+	// the file format, and reports each table registered and file skipped.
+	gen.RegisterOverride("registerDirectory", func(c *cobra.Command) {
+		c.Use = "register-dir <path>"
+		for _, name := range []string{"file-format", "json"} {
+			_ = c.Flags().MarkHidden(name)
+		}
+		c.Flags().String("format", "parquet", "Format of the files to register (parquet or csv)")
+		_ = c.MarkFlagRequired("schema-name")
+		_ = c.MarkFlagRequired("location-name")
+	})
+	gen.RegisterRunOverride("registerDirectory", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			return runRegisterDirectory(cmd, client, args[0])
+		}
+	})
+}
+
+// registerDirectoryResult mirrors the API's RegisterDirectoryResult.
+type registerDirectoryResult struct {
+	Registered []struct {
+		Name       string `json:"name"`
+		SchemaName string `json:"schema_name"`
+		Columns    []struct {
+			Name string `json:"name"`
+		} `json:"columns"`
+	} `json:"registered"`
+	Skipped []struct {
+		Path      string `json:"path"`
+		TableName string `json:"table_name"`
+		Reason    string `json:"reason"`
+	} `json:"skipped"`
+}
+
+// runRegisterDirectory registers the files under path as external tables and
+// prints what was registered.
+func runRegisterDirectory(cmd *cobra.Command, client *gen.Client, path string) error {
+	format, _ := cmd.Flags().GetString("format")
+	body := map[string]interface{}{"path": path, "file_format": format}
+	for flag, field := range map[string]string{
+		"catalog-name":  "catalog_name",
+		"schema-name":   "schema_name",
+		"location-name": "location_name",
+		"table-name":    "table_name",
+	} {
+		if v, _ := cmd.Flags().GetString(flag); v != "" {
+			body[field] = v
+		}
+	}
+
+	resp, err := client.Do("POST", "/external-tables:register-directory", nil, body)
+	if err != nil {
+		return err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return err
+	}
+	respBody, err := gen.ReadBody(resp)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if getOutputFormat(cmd) == "json" {
+		return printRawJSON(cmd.OutOrStdout(), respBody)
+	}
+	var res registerDirectoryResult
+	if err := json.Unmarshal(respBody, &res); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	printRegisterDirectory(cmd.OutOrStdout(), path, res)
+	return nil
+}
+
+func printRegisterDirectory(w io.Writer, path string, res registerDirectoryResult) {
+	_, _ = fmt.Fprintf(w, "Registered %d table(s) from %s\n", len(res.Registered), path)
+	for _, t := range res.Registered {
+		_, _ = fmt.Fprintf(w, "  + %s.%s (%d columns)\n", t.SchemaName, t.Name, len(t.Columns))
+	}
+	if len(res.Skipped) > 0 {
+		_, _ = fmt.Fprintf(w, "Skipped %d file(s)\n", len(res.Skipped))
+		for _, s := range res.Skipped {
+			_, _ = fmt.Fprintf(w, "  - %s: %s\n", s.Path, s.Reason)
+		}
+	}
+}
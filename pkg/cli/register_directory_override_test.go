@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterDirectoryOverride(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"registered": [{"name": "orders", "schema_name": "raw", "columns": [{"name": "id"}, {"name": "amount"}]}],
+			"skipped": [{"path": "s3://landing/returns.csv", "table_name": "returns", "reason": "table \"returns\" already exists"}]
+		}`))
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	rootCmd := newRootCmd()
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "catalog", "register-dir", "s3://landing/",
+		"--format", "csv", "--schema-name", "raw", "--location-name", "landing"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Equal(t, "/v1/external-tables:register-directory", gotPath)
+	assert.Equal(t, map[string]interface{}{
+		"path":          "s3://landing/",
+		"file_format":   "csv",
+		"schema_name":   "raw",
+		"location_name": "landing",
+	}, gotBody)
+	assert.Contains(t, out.String(), "Registered 1 table(s) from s3://landing/")
+	assert.Contains(t, out.String(), "+ raw.orders (2 columns)")
+	assert.Contains(t, out.String(), "- s3://landing/returns.csv: table \"returns\" already exists")
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -46,61 +48,99 @@ func newRootCmd() *cobra.Command {
 		output  string
 		profile string
 		quiet   bool
+		timeout time.Duration
+		retries int
+		verbose int
+
+		noVersionCheck bool
 	)
 
-	rootCmd := &cobra.Command{
-		Use:           "duck",
-		Short:         "DuckDB Data Platform CLI",
-		Long:          "Command-line interface for the DuckDB Data Platform API.",
-		SilenceUsage:  true,
-		SilenceErrors: true,
-		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
-			// Load config from profile if flags/env not set
-			cfg, err := LoadUserConfig()
-			if err != nil {
-				// Config file is optional
-				cfg = &UserConfig{
-					CurrentProfile: "default",
-					Profiles:       map[string]Profile{},
-				}
+	// applyProfile fills connection settings that were not set by flag from
+	// the environment and the active config profile.
+	applyProfile := func(cmd *cobra.Command) error {
+		// Load config from profile if flags/env not set
+		cfg, err := LoadUserConfig()
+		if err != nil {
+			// Config file is optional
+			cfg = &UserConfig{
+				CurrentProfile: "default",
+				Profiles:       map[string]Profile{},
 			}
+		}
 
-			p, err := cfg.ActiveProfile(profile)
-			if err != nil {
-				return err
-			}
+		p, err := cfg.ActiveProfile(profile)
+		if err != nil {
+			return err
+		}
 
-			// Apply precedence: flag > env > profile > default
-			if !cmd.Flags().Changed("host") {
-				if v := os.Getenv("DUCK_HOST"); v != "" {
-					host = v
-				} else if p.Host != "" {
-					host = p.Host
-				}
+		// Apply precedence: flag > env > profile > default
+		if !cmd.Flags().Changed("host") {
+			if v := os.Getenv("DUCK_HOST"); v != "" {
+				host = v
+			} else if p.Host != "" {
+				host = p.Host
+			}
+		}
+		if !cmd.Flags().Changed("api-key") {
+			if v := os.Getenv("DUCK_API_KEY"); v != "" {
+				apiKey = v
+			} else if p.APIKey != "" {
+				apiKey = p.APIKey
+			}
+		}
+		if !cmd.Flags().Changed("token") {
+			if v := os.Getenv("DUCK_TOKEN"); v != "" {
+				token = v
+			} else if p.Token != "" {
+				token = p.Token
+			}
+		}
+		if !cmd.Flags().Changed("output") {
+			if v := os.Getenv("DUCK_OUTPUT"); v != "" {
+				output = v
+			} else if p.Output != "" {
+				output = p.Output
 			}
-			if !cmd.Flags().Changed("api-key") {
-				if v := os.Getenv("DUCK_API_KEY"); v != "" {
-					apiKey = v
-				} else if p.APIKey != "" {
-					apiKey = p.APIKey
+		}
+		if !cmd.Flags().Changed("timeout") {
+			if v := os.Getenv("DUCK_TIMEOUT"); v != "" {
+				if timeout, err = parseTimeout(v); err != nil {
+					return fmt.Errorf("DUCK_TIMEOUT: %w", err)
+				}
+			} else if p.Timeout != "" {
+				if timeout, err = parseTimeout(p.Timeout); err != nil {
+					return fmt.Errorf("profile timeout: %w", err)
 				}
 			}
-			if !cmd.Flags().Changed("token") {
-				if v := os.Getenv("DUCK_TOKEN"); v != "" {
-					token = v
-				} else if p.Token != "" {
-					token = p.Token
+		}
+		if !cmd.Flags().Changed("retries") {
+			if v := os.Getenv("DUCK_RETRIES"); v != "" {
+				if retries, err = parseRetries(v); err != nil {
+					return fmt.Errorf("DUCK_RETRIES: %w", err)
 				}
+			} else if p.Retries != nil {
+				retries = *p.Retries
 			}
-			if !cmd.Flags().Changed("output") {
-				if v := os.Getenv("DUCK_OUTPUT"); v != "" {
-					output = v
-				} else if p.Output != "" {
-					output = p.Output
+		}
+		if !cmd.Flags().Changed("no-version-check") {
+			if v := os.Getenv(noVersionCheckEnv); v != "" {
+				if noVersionCheck, err = strconv.ParseBool(v); err != nil {
+					return fmt.Errorf("%s: invalid boolean %q", noVersionCheckEnv, v)
 				}
 			}
+		}
 
-			return nil
+		return nil
+	}
+
+	rootCmd := &cobra.Command{
+		Use:           "duck",
+		Short:         "DuckDB Data Platform CLI",
+		Long:          "Command-line interface for the DuckDB Data Platform API.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			return applyProfile(cmd)
 		},
 	}
 
@@ -110,6 +150,10 @@ func newRootCmd() *cobra.Command {
 	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "Output format (table, json)")
 	rootCmd.PersistentFlags().StringVarP(&profile, "profile", "p", "", "Config profile to use")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only output resource identifiers")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", gen.DefaultTimeout, "Per-request timeout (e.g. 10s, 2m)")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", gen.DefaultMaxRetries, "Retries for idempotent requests on connection errors, 5xx and 429")
+	rootCmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "Trace HTTP requests and responses to stderr; repeat (-vv) for retries and timing")
+	rootCmd.PersistentFlags().BoolVar(&noVersionCheck, "no-version-check", false, "Do not warn when the server's API version differs from the CLI's")
 
 	// Create client using a lazy initializer
 	client := gen.NewClient(host, apiKey, token)
@@ -126,13 +170,22 @@ func newRootCmd() *cobra.Command {
 		if output != "" {
 			_ = cmd.Root().PersistentFlags().Set("output", output)
 		}
+		_ = cmd.Root().PersistentFlags().Set("no-version-check", strconv.FormatBool(noVersionCheck))
 		// Validate output format
 		if err := validateOutputFormat(output); err != nil {
 			return err
 		}
+		if timeout <= 0 {
+			return fmt.Errorf("--timeout must be positive")
+		}
+		if retries < 0 {
+			return fmt.Errorf("--retries must not be negative")
+		}
 		if yesFlag := cmd.Flags().Lookup("yes"); yesFlag != nil {
 			yes, _ := cmd.Flags().GetBool("yes")
-			if !yes && !gen.IsStdinTTY() {
+			// A dry run changes nothing, so it needs no confirmation.
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if !yes && !dryRun && !gen.IsStdinTTY() {
 				return fmt.Errorf("confirmation required but stdin is not a terminal; use --yes to skip")
 			}
 		}
@@ -140,14 +193,27 @@ func newRootCmd() *cobra.Command {
 		client.BaseURL = host
 		client.APIKey = apiKey
 		client.Token = token
+		client.HTTPClient.Timeout = timeout
+		client.MaxRetries = retries
+		client.Verbose = verbose
+		// Warn once, before the first API request, if the server serves a
+		// different API spec than this CLI was generated from.
+		client.BeforeFirstRequest = nil
+		if !noVersionCheck {
+			stderr := cmd.ErrOrStderr()
+			client.BeforeFirstRequest = func() { warnOnSpecDrift(client, stderr) }
+		}
 		return nil
 	}
 
 	// Add generated commands
 	gen.AddGeneratedCommands(rootCmd, client)
+	addGrantFilterFlags(rootCmd, client)
+	addColumnMasksAlias(rootCmd)
+	addModelRunDeprecatedAlias(rootCmd)
 
 	// Add hand-written commands
-	rootCmd.AddCommand(newVersionCmd())
+	rootCmd.AddCommand(newVersionCmd(client))
 	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newAuthCmd())
 
@@ -156,6 +222,25 @@ func newRootCmd() *cobra.Command {
 	rootCmd.AddCommand(newApplyCmd(client))
 	rootCmd.AddCommand(newExportCmd(client))
 	rootCmd.AddCommand(newValidateCmd(client))
+	addToGroup(rootCmd, "catalog", newImportCmd(client))
+	addToGroup(rootCmd, "catalog", newDiffSchemaCmd(client))
+	addToGroup(rootCmd, "catalog", newCatalogTreeCmd(client))
+	addToGroup(rootCmd, "catalog", newCatalogMineCmd(client))
+	addToGroup(rootCmd, "catalog", newCatalogAccessCmd(client))
+	addToGroup(rootCmd, "query", newQueryHistoryCmd(client))
+	addToSubgroup(rootCmd, "security", "principals", newPrincipalOffboardCmd(client))
+	addToSubgroup(rootCmd, "security", "principals", newPrincipalImportCmd(client))
+	addToGroup(rootCmd, "security", newSecuritySimulatePolicyCmd(client))
+	addToSubgroup(rootCmd, "compute", "endpoints", newComputeEndpointsStatusCmd(client))
+	addToSubgroup(rootCmd, "governance", "tags", newTagsAssignCmd(client))
+	addToSubgroup(rootCmd, "governance", "tags", newTagsUnassignCmd(client))
+	addToGroup(rootCmd, "models", newModelsLineageCmd(client))
+	addToGroup(rootCmd, "models", newModelsRunsCmd(client))
+	addToGroup(rootCmd, "models", newModelsTestCmd(client))
+	addToGroup(rootCmd, "models", newModelsTestHistoryCmd(client))
+	addToGroup(rootCmd, "notebooks", newNotebooksExportCmd(client))
+	addToGroup(rootCmd, "notebooks", newNotebooksImportCmd(client))
+	rootCmd.AddCommand(newGitCmd(client))
 
 	// Agent discovery commands
 	rootCmd.AddCommand(newCommandsCmd())
@@ -163,31 +248,64 @@ func newRootCmd() *cobra.Command {
 	rootCmd.AddCommand(newFindCmd(client))
 	rootCmd.AddCommand(newDescribeCmd(client))
 
-	// Shell completions
+	// Shell completions. Cobra does not run pre-run hooks for completion
+	// requests, so dynamic completions resolve the profile themselves.
 	rootCmd.AddCommand(newCompletionCmd())
+	registerDynamicCompletions(rootCmd, func(cmd *cobra.Command) *gen.Client {
+		if err := applyProfile(cmd); err != nil {
+			return nil
+		}
+		return gen.NewClient(host, apiKey, token)
+	})
 
 	return rootCmd
 }
 
-func newCompletionCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "completion [bash|zsh|fish|powershell]",
-		Short: "Generate shell completion scripts",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			switch args[0] {
-			case "bash":
-				return cmd.Root().GenBashCompletion(os.Stdout)
-			case "zsh":
-				return cmd.Root().GenZshCompletion(os.Stdout)
-			case "fish":
-				return cmd.Root().GenFishCompletion(os.Stdout, true)
-			case "powershell":
-				return cmd.Root().GenPowerShellCompletionWithDesc(os.Stdout)
-			default:
-				return fmt.Errorf("unsupported shell: %s", args[0])
-			}
-		},
+// addToGroup attaches a hand-written subcommand to a generated command group,
+// creating the group if the generated commands do not define it.
+func addToGroup(root *cobra.Command, group string, sub *cobra.Command) {
+	for _, c := range root.Commands() {
+		if c.Name() == group {
+			c.AddCommand(sub)
+			return
+		}
+	}
+	parent := &cobra.Command{Use: group}
+	parent.AddCommand(sub)
+	root.AddCommand(parent)
+}
+
+// addToSubgroup attaches a hand-written subcommand to a group nested inside a
+// generated group, such as `governance tags`, creating either level if needed.
+func addToSubgroup(root *cobra.Command, group, subgroup string, sub *cobra.Command) {
+	for _, c := range root.Commands() {
+		if c.Name() == group {
+			addToGroup(c, subgroup, sub)
+			return
+		}
+	}
+	parent := &cobra.Command{Use: group}
+	addToGroup(parent, subgroup, sub)
+	root.AddCommand(parent)
+}
+
+// parseTimeout parses a request timeout given as a Go duration.
+func parseTimeout(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", v, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be positive", v)
+	}
+	return d, nil
+}
+
+// parseRetries parses a non-negative retry count.
+func parseRetries(v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid retry count %q: must be a non-negative integer", v)
 	}
-	return cmd
+	return n, nil
 }
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecuritySimulatePolicyCmd(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/v1/authz/simulate-policy", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"table_name":"sales.customers","principal":"alice","columns":["id","email","region"],` +
+			`"rows":[[1,"***","EU"],[3,"***","EU"]],"row_filtered":true,"masked_columns":["email"]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	out := runCatalogTree(t, srv, "security", "simulate-policy",
+		"--table", "sales.customers", "--filter", "region = 'EU'", "--mask", "email='***'", "--as", "alice", "--limit", "5")
+
+	assert.Equal(t, map[string]interface{}{
+		"table":           "sales.customers",
+		"principal":       "alice",
+		"filter_sql":      "region = 'EU'",
+		"column_name":     "email",
+		"mask_expression": "'***'",
+		"limit":           float64(5),
+	}, got)
+	assert.Contains(t, out, "sales.customers as seen by alice (row filtered; masked: email)")
+	assert.Regexp(t, `1\s+\*\*\*\s+EU`, out)
+	assert.Regexp(t, `3\s+\*\*\*\s+EU`, out)
+}
+
+func TestSecuritySimulatePolicyCmd_RequiresPolicy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Fatal("no request expected")
+	}))
+	t.Cleanup(srv.Close)
+
+	for name, args := range map[string][]string{
+		"no policy":      {"--table", "sales.customers", "--as", "alice"},
+		"malformed mask": {"--table", "sales.customers", "--as", "alice", "--mask", "email"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := newTestRootCmd(t, srv)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL, "security", "simulate-policy"}, args...))
+			require.Error(t, rootCmd.Execute())
+		})
+	}
+}
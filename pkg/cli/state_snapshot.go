@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"duck-demo/internal/declarative"
+)
+
+// AppliedSnapshot is the server state recorded after a successful apply.
+// It is the baseline for drift detection on the next apply.
+type AppliedSnapshot struct {
+	Host      string                    `json:"host"`
+	AppliedAt time.Time                 `json:"applied_at"`
+	State     *declarative.DesiredState `json:"state"`
+}
+
+// StateDir returns the path to ~/.duck/state/.
+func StateDir() string {
+	return filepath.Join(ConfigDir(), "state")
+}
+
+// snapshotPath returns the last-applied snapshot path for a server host.
+func snapshotPath(dir, host string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.TrimRight(name, "/"))
+	return filepath.Join(dir, name+".last-applied.json")
+}
+
+// SaveAppliedSnapshot writes the last-applied snapshot for host into dir.
+func SaveAppliedSnapshot(dir, host string, state *declarative.DesiredState) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	data, err := json.MarshalIndent(AppliedSnapshot{
+		Host:      host,
+		AppliedAt: time.Now().UTC(),
+		State:     state,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+
+	// Write to a temp file first so an interrupted write never leaves a
+	// truncated snapshot behind.
+	path := snapshotPath(dir, host)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("replace snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadAppliedSnapshot reads the last-applied snapshot for host from dir.
+// It returns nil without error when no snapshot has been recorded yet.
+func LoadAppliedSnapshot(dir, host string) (*AppliedSnapshot, error) {
+	data, err := os.ReadFile(snapshotPath(dir, host)) //nolint:gosec // path is derived from home dir and a sanitized host
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	var snap AppliedSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parse snapshot: %w", err)
+	}
+	if snap.State == nil {
+		snap.State = &declarative.DesiredState{}
+	}
+	return &snap, nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/internal/declarative"
+)
+
+func TestAppliedSnapshot_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	state := &declarative.DesiredState{
+		Principals: []declarative.PrincipalSpec{{Name: "alice", Type: "user"}},
+		Grants: []declarative.GrantSpec{
+			{Principal: "alice", PrincipalType: "user", SecurableType: "catalog", Securable: "main", Privilege: "USE_CATALOG"},
+		},
+	}
+
+	require.NoError(t, SaveAppliedSnapshot(dir, "http://localhost:8080", state))
+
+	snap, err := LoadAppliedSnapshot(dir, "http://localhost:8080")
+	require.NoError(t, err)
+	require.NotNil(t, snap)
+	assert.Equal(t, "http://localhost:8080", snap.Host)
+	assert.False(t, snap.AppliedAt.IsZero())
+	assert.Equal(t, state, snap.State)
+
+	info, err := os.Stat(snapshotPath(dir, "http://localhost:8080"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestAppliedSnapshot_MissingReturnsNil(t *testing.T) {
+	snap, err := LoadAppliedSnapshot(t.TempDir(), "http://localhost:8080")
+	require.NoError(t, err)
+	assert.Nil(t, snap)
+}
+
+func TestAppliedSnapshot_ScopedPerHost(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, SaveAppliedSnapshot(dir, "https://prod.example.com/", &declarative.DesiredState{}))
+
+	snap, err := LoadAppliedSnapshot(dir, "https://staging.example.com")
+	require.NoError(t, err)
+	assert.Nil(t, snap)
+
+	assert.Equal(t, filepath.Join(dir, "prod.example.com.last-applied.json"), snapshotPath(dir, "https://prod.example.com/"))
+	assert.Equal(t, filepath.Join(dir, "localhost_8080.last-applied.json"), snapshotPath(dir, "http://localhost:8080"))
+}
+
+func TestAppliedSnapshot_DetectsSimulatedDrift(t *testing.T) {
+	dir := t.TempDir()
+	host := "http://localhost:8080"
+	applied := &declarative.DesiredState{
+		Principals: []declarative.PrincipalSpec{{Name: "alice", Type: "user"}},
+		Grants: []declarative.GrantSpec{
+			{Principal: "alice", PrincipalType: "user", SecurableType: "catalog", Securable: "main", Privilege: "USE_CATALOG"},
+		},
+	}
+	require.NoError(t, SaveAppliedSnapshot(dir, host, applied))
+
+	// Someone revokes the grant and promotes alice in the UI.
+	live := &declarative.DesiredState{
+		Principals: []declarative.PrincipalSpec{{Name: "alice", Type: "user", IsAdmin: true}},
+	}
+
+	snap, err := LoadAppliedSnapshot(dir, host)
+	require.NoError(t, err)
+	require.NotNil(t, snap)
+
+	drift := declarative.DetectDrift(snap.State, live)
+	require.Len(t, drift, 2)
+	changes := map[declarative.DriftChange]string{}
+	for _, d := range drift {
+		changes[d.Change] = d.ResourceType
+	}
+	assert.Equal(t, "principal", changes[declarative.DriftModified])
+	assert.Equal(t, "privilege-grant", changes[declarative.DriftRemoved])
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// compactTable and vacuumTable take the table as one catalog.schema.table
+	// argument, like diff-schema, and print a before/after summary.
+	for _, op := range []struct{ id, verb, action string }{
+		{id: "compactTable", verb: "compact", action: "compact"},
+		{id: "vacuumTable", verb: "vacuum", action: "vacuum"},
+	} {
+		gen.RegisterOverride(op.id, func(c *cobra.Command) {
+			c.Use = op.verb + " <catalog.schema.table>"
+			c.Args = cobra.ExactArgs(1)
+			// The catalog comes from the table path.
+			_ = c.Flags().SetAnnotation("catalog-name", cobra.BashCompOneRequiredFlag, []string{"false"})
+			_ = c.Flags().MarkHidden("catalog-name")
+		})
+		gen.RegisterRunOverride(op.id, func(client *gen.Client) func(*cobra.Command, []string) error {
+			return func(cmd *cobra.Command, args []string) error {
+				return runTableMaintenance(cmd, client, op.action, args[0])
+			}
+		})
+	}
+}
+
+// tableMaintenanceResult mirrors the API's TableMaintenanceResult.
+type tableMaintenanceResult struct {
+	Operation        string `json:"operation"`
+	FilesBefore      int64  `json:"files_before"`
+	FilesAfter       int64  `json:"files_after"`
+	BytesBefore      int64  `json:"bytes_before"`
+	BytesAfter       int64  `json:"bytes_after"`
+	BytesReclaimed   int64  `json:"bytes_reclaimed"`
+	SnapshotsExpired int64  `json:"snapshots_expired"`
+}
+
+// runTableMaintenance posts a :compact or :vacuum request for the table at
+// path and prints the result.
+func runTableMaintenance(cmd *cobra.Command, client *gen.Client, action, path string) error {
+	urlPath, err := tableActionURL(path, action)
+	if err != nil {
+		return err
+	}
+
+	var body interface{}
+	if action == "vacuum" {
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		body = map[string]interface{}{"older_than": olderThan}
+	}
+
+	resp, err := client.Do("POST", urlPath, nil, body)
+	if err != nil {
+		return err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return err
+	}
+	respBody, err := gen.ReadBody(resp)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if getOutputFormat(cmd) == "json" {
+		var pretty interface{}
+		if err := json.Unmarshal(respBody, &pretty); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		return gen.PrintJSON(cmd.OutOrStdout(), pretty)
+	}
+
+	var res tableMaintenanceResult
+	if err := json.Unmarshal(respBody, &res); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	printTableMaintenance(cmd.OutOrStdout(), path, res)
+	return nil
+}
+
+// tableActionURL returns the URL of a table action such as :compact for a
+// catalog.schema.table path.
+func tableActionURL(path, action string) (string, error) {
+	parts := strings.Split(path, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid table path %q: expected catalog.schema.table", path)
+	}
+	return "/catalogs/" + url.PathEscape(parts[0]) + "/schemas/" + url.PathEscape(parts[1]) +
+		"/tables/" + url.PathEscape(parts[2]) + ":" + action, nil
+}
+
+func printTableMaintenance(w io.Writer, table string, res tableMaintenanceResult) {
+	verb := "Compacted"
+	if res.Operation == "VACUUM" {
+		verb = "Vacuumed"
+	}
+	_, _ = fmt.Fprintf(w, "%s %s\n", verb, table)
+	_, _ = fmt.Fprintf(w, "  files:     %d -> %d\n", res.FilesBefore, res.FilesAfter)
+	_, _ = fmt.Fprintf(w, "  bytes:     %d -> %d (%d reclaimed)\n", res.BytesBefore, res.BytesAfter, res.BytesReclaimed)
+	if res.Operation == "VACUUM" {
+		_, _ = fmt.Fprintf(w, "  snapshots: %d expired\n", res.SnapshotsExpired)
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableMaintenanceOverride(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		response   string
+		wantPath   string
+		wantBody   map[string]interface{}
+		wantOutput []string
+		errContain string
+	}{
+		{
+			name:       "compact",
+			args:       []string{"catalog", "compact", "main.analytics.orders"},
+			response:   `{"operation":"COMPACT","files_before":48,"files_after":3,"bytes_before":5000,"bytes_after":4800,"bytes_reclaimed":200,"snapshots_expired":0}`,
+			wantPath:   "/v1/catalogs/main/schemas/analytics/tables/orders:compact",
+			wantOutput: []string{"Compacted main.analytics.orders", "48 -> 3", "(200 reclaimed)"},
+		},
+		{
+			name:       "vacuum sends older_than",
+			args:       []string{"catalog", "vacuum", "main.analytics.orders", "--older-than", "30d"},
+			response:   `{"operation":"VACUUM","files_before":51,"files_after":3,"bytes_before":9000,"bytes_after":4000,"bytes_reclaimed":5000,"snapshots_expired":47}`,
+			wantPath:   "/v1/catalogs/main/schemas/analytics/tables/orders:vacuum",
+			wantBody:   map[string]interface{}{"older_than": "30d"},
+			wantOutput: []string{"Vacuumed main.analytics.orders", "51 -> 3", "47 expired"},
+		},
+		{
+			name:       "vacuum defaults to 7d",
+			args:       []string{"catalog", "vacuum", "main.analytics.orders"},
+			response:   `{"operation":"VACUUM"}`,
+			wantPath:   "/v1/catalogs/main/schemas/analytics/tables/orders:vacuum",
+			wantBody:   map[string]interface{}{"older_than": "7d"},
+			wantOutput: []string{"Vacuumed main.analytics.orders"},
+		},
+		{
+			name:       "rejects a path without catalog",
+			args:       []string{"catalog", "compact", "analytics.orders"},
+			errContain: "expected catalog.schema.table",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotBody map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+					_ = json.Unmarshal(data, &gotBody)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			var out bytes.Buffer
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL}, tt.args...))
+			err := rootCmd.Execute()
+
+			if tt.errContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContain)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, gotPath)
+			assert.Equal(t, tt.wantBody, gotBody)
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// renameTable takes the object as one catalog.schema.name argument and the
+	// new name positionally. The name may also be a view: a table that does
+	// not exist is retried as a view rename.
+	gen.RegisterOverride("renameTable", func(c *cobra.Command) {
+		c.Use = "rename <catalog.schema.name> <new-name>"
+		c.Args = cobra.ExactArgs(2)
+		_ = c.Flags().SetAnnotation("catalog-name", cobra.BashCompOneRequiredFlag, []string{"false"})
+		_ = c.Flags().MarkHidden("catalog-name")
+		_ = c.Flags().MarkHidden("new-name")
+	})
+	gen.RegisterRunOverride("renameTable", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			return runCatalogRename(cmd, client, args[0], args[1])
+		}
+	})
+}
+
+// runCatalogRename renames the table at path to newName, falling back to the
+// view of that name when no such table exists.
+func runCatalogRename(cmd *cobra.Command, client *gen.Client, path, newName string) error {
+	tableURL, err := tableActionURL(path, "rename")
+	if err != nil {
+		return err
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	body := map[string]interface{}{"new_name": newName, "force": force}
+
+	kind := "table"
+	resp, err := client.Do("POST", tableURL, nil, body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		kind = "view"
+		viewURL := strings.Replace(tableURL, "/tables/", "/views/", 1)
+		if resp, err = client.Do("POST", viewURL, nil, body); err != nil {
+			return err
+		}
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return err
+	}
+	respBody, err := gen.ReadBody(resp)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if getOutputFormat(cmd) == "json" {
+		return printRawJSON(cmd.OutOrStdout(), respBody)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Renamed %s %s to %s\n", kind, path, newName)
+	return nil
+}
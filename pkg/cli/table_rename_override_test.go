@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableRenameOverride(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		views      bool // the server only knows the name as a view
+		wantPaths  []string
+		wantBody   map[string]interface{}
+		wantOutput string
+		errContain string
+	}{
+		{
+			name:       "renames a table",
+			args:       []string{"catalog", "rename", "main.analytics.orders", "purchases"},
+			wantPaths:  []string{"/v1/catalogs/main/schemas/analytics/tables/orders:rename"},
+			wantBody:   map[string]interface{}{"new_name": "purchases", "force": false},
+			wantOutput: "Renamed table main.analytics.orders to purchases",
+		},
+		{
+			name:  "falls back to a view",
+			args:  []string{"catalog", "rename", "main.analytics.daily_sales", "daily_revenue", "--force"},
+			views: true,
+			wantPaths: []string{
+				"/v1/catalogs/main/schemas/analytics/tables/daily_sales:rename",
+				"/v1/catalogs/main/schemas/analytics/views/daily_sales:rename",
+			},
+			wantBody:   map[string]interface{}{"new_name": "daily_revenue", "force": true},
+			wantOutput: "Renamed view main.analytics.daily_sales to daily_revenue",
+		},
+		{
+			name:       "rejects a path without catalog",
+			args:       []string{"catalog", "rename", "analytics.orders", "purchases"},
+			errContain: "expected catalog.schema.table",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPaths []string
+			var gotBody map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPaths = append(gotPaths, r.URL.Path)
+				if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+					_ = json.Unmarshal(data, &gotBody)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if tt.views && strings.Contains(r.URL.Path, "/tables/") {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"code":404,"message":"table not found"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"name":"renamed"}`))
+			}))
+			defer srv.Close()
+
+			var out bytes.Buffer
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL}, tt.args...))
+			err := rootCmd.Execute()
+
+			if tt.errContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContain)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPaths, gotPaths)
+			assert.Equal(t, tt.wantBody, gotBody)
+			assert.Contains(t, out.String(), tt.wantOutput)
+		})
+	}
+}
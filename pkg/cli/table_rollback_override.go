@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+func init() {
+	// rollbackTable takes the table as one catalog.schema.table argument and
+	// previews the rollback, including the dependent views and models, before
+	// asking to apply it. --dry-run stops after the preview.
+	gen.RegisterOverride("rollbackTable", func(c *cobra.Command) {
+		c.Use = "rollback <catalog.schema.table>"
+		c.Args = cobra.ExactArgs(1)
+		_ = c.Flags().SetAnnotation("catalog-name", cobra.BashCompOneRequiredFlag, []string{"false"})
+		_ = c.Flags().MarkHidden("catalog-name")
+		// The generated body flags are replaced by --to-snapshot/--to-timestamp;
+		// confirmation comes from the prompt or --yes.
+		for _, name := range []string{"snapshot-id", "timestamp", "confirm"} {
+			_ = c.Flags().MarkHidden(name)
+		}
+		c.Flags().Int64("to-snapshot", 0, "Snapshot ID to restore the table to")
+		c.Flags().String("to-timestamp", "", "Restore the table as of this RFC 3339 time")
+		c.Flags().Bool("dry-run", false, "Preview the rollback and its dependents without applying it")
+		c.MarkFlagsMutuallyExclusive("to-snapshot", "to-timestamp")
+		c.MarkFlagsOneRequired("to-snapshot", "to-timestamp")
+	})
+	gen.RegisterRunOverride("rollbackTable", func(client *gen.Client) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			return runTableRollback(cmd, client, args[0])
+		}
+	})
+}
+
+// tableRollbackResult mirrors the API's TableRollbackResult.
+type tableRollbackResult struct {
+	CurrentSnapshot int64  `json:"current_snapshot"`
+	TargetSnapshot  int64  `json:"target_snapshot"`
+	NewSnapshot     *int64 `json:"new_snapshot"`
+	RowsBefore      int64  `json:"rows_before"`
+	RowsAfter       int64  `json:"rows_after"`
+	Applied         bool   `json:"applied"`
+	Dependents      []struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	} `json:"dependents"`
+}
+
+// runTableRollback previews a rollback of the table at path, then applies it
+// once the user confirms or --yes is given.
+func runTableRollback(cmd *cobra.Command, client *gen.Client, path string) error {
+	urlPath, err := tableActionURL(path, "rollback")
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{"confirm": false}
+	if cmd.Flags().Changed("to-snapshot") {
+		body["snapshot_id"], _ = cmd.Flags().GetInt64("to-snapshot")
+	} else {
+		body["timestamp"], _ = cmd.Flags().GetString("to-timestamp")
+	}
+
+	preview, raw, err := postTableRollback(client, urlPath, body)
+	if err != nil {
+		return err
+	}
+	jsonOut := getOutputFormat(cmd) == "json"
+	if !jsonOut {
+		printRollbackPreview(cmd.OutOrStdout(), path, preview)
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		if jsonOut {
+			return printRawJSON(cmd.OutOrStdout(), raw)
+		}
+		return nil
+	}
+	if !cmd.Flags().Changed("yes") {
+		if !gen.ConfirmPrompt(fmt.Sprintf("Roll back %s to snapshot %d?", path, preview.TargetSnapshot)) {
+			return nil
+		}
+	}
+
+	// Pin the resolved snapshot so a --to-timestamp rollback applies exactly
+	// what was previewed.
+	applyBody := map[string]interface{}{"snapshot_id": preview.TargetSnapshot, "confirm": true}
+	res, raw, err := postTableRollback(client, urlPath, applyBody)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return printRawJSON(cmd.OutOrStdout(), raw)
+	}
+	newSnapshot := "?"
+	if res.NewSnapshot != nil {
+		newSnapshot = fmt.Sprint(*res.NewSnapshot)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Rolled back %s to snapshot %d (new snapshot %s): %d -> %d rows\n",
+		path, res.TargetSnapshot, newSnapshot, res.RowsBefore, res.RowsAfter)
+	return nil
+}
+
+func postTableRollback(client *gen.Client, urlPath string, body map[string]interface{}) (*tableRollbackResult, []byte, error) {
+	resp, err := client.Do("POST", urlPath, nil, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return nil, nil, err
+	}
+	raw, err := gen.ReadBody(resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+	var res tableRollbackResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &res, raw, nil
+}
+
+func printRawJSON(w io.Writer, raw []byte) error {
+	var pretty interface{}
+	if err := json.Unmarshal(raw, &pretty); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return gen.PrintJSON(w, pretty)
+}
+
+func printRollbackPreview(w io.Writer, table string, res *tableRollbackResult) {
+	_, _ = fmt.Fprintf(w, "Rollback of %s to snapshot %d (current snapshot %d)\n", table, res.TargetSnapshot, res.CurrentSnapshot)
+	_, _ = fmt.Fprintf(w, "  rows: %d -> %d\n", res.RowsBefore, res.RowsAfter)
+	if len(res.Dependents) == 0 {
+		_, _ = fmt.Fprintln(w, "  no dependent views or models")
+		return
+	}
+	_, _ = fmt.Fprintln(w, "  potentially affected:")
+	for _, d := range res.Dependents {
+		_, _ = fmt.Fprintf(w, "    %-5s %s\n", d.Type, d.Name)
+	}
+}
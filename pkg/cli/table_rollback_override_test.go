@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableRollbackOverride(t *testing.T) {
+	const preview = `{"current_snapshot":57,"target_snapshot":42,"rows_before":1250,"rows_after":1000,"applied":false,
+		"dependents":[{"type":"VIEW","name":"reporting.daily_orders"},{"type":"MODEL","name":"sales.fct_orders"}]}`
+	const applied = `{"current_snapshot":57,"target_snapshot":42,"new_snapshot":58,"rows_before":1250,"rows_after":1000,"applied":true,"dependents":[]}`
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantBodies []map[string]interface{}
+		wantOutput []string
+		errContain string
+	}{
+		{
+			name: "previews then applies with --yes",
+			args: []string{"catalog", "rollback", "main.analytics.orders", "--to-snapshot", "42", "--yes"},
+			wantBodies: []map[string]interface{}{
+				{"snapshot_id": float64(42), "confirm": false},
+				{"snapshot_id": float64(42), "confirm": true},
+			},
+			wantOutput: []string{
+				"Rollback of main.analytics.orders to snapshot 42 (current snapshot 57)",
+				"rows: 1250 -> 1000",
+				"VIEW  reporting.daily_orders",
+				"MODEL sales.fct_orders",
+				"Rolled back main.analytics.orders to snapshot 42 (new snapshot 58)",
+			},
+		},
+		{
+			name: "timestamp applies the previewed snapshot",
+			args: []string{"catalog", "rollback", "main.analytics.orders", "--to-timestamp", "2025-01-15T10:30:00Z", "--yes"},
+			wantBodies: []map[string]interface{}{
+				{"timestamp": "2025-01-15T10:30:00Z", "confirm": false},
+				{"snapshot_id": float64(42), "confirm": true},
+			},
+		},
+		{
+			name: "dry run only previews",
+			args: []string{"catalog", "rollback", "main.analytics.orders", "--to-snapshot", "42", "--dry-run"},
+			wantBodies: []map[string]interface{}{
+				{"snapshot_id": float64(42), "confirm": false},
+			},
+			wantOutput: []string{"potentially affected:"},
+		},
+		{
+			name:       "requires a target",
+			args:       []string{"catalog", "rollback", "main.analytics.orders", "--yes"},
+			errContain: "to-snapshot to-timestamp",
+		},
+		{
+			name:       "requires --yes without a terminal",
+			args:       []string{"catalog", "rollback", "main.analytics.orders", "--to-snapshot", "42"},
+			errContain: "use --yes to skip",
+		},
+		{
+			name:       "rejects a path without catalog",
+			args:       []string{"catalog", "rollback", "analytics.orders", "--to-snapshot", "42", "--yes"},
+			errContain: "expected catalog.schema.table",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotBodies []map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				var body map[string]interface{}
+				data, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(data, &body)
+				gotBodies = append(gotBodies, body)
+				w.Header().Set("Content-Type", "application/json")
+				if body["confirm"] == true {
+					_, _ = w.Write([]byte(applied))
+					return
+				}
+				_, _ = w.Write([]byte(preview))
+			}))
+			defer srv.Close()
+
+			var out bytes.Buffer
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--host", srv.URL}, tt.args...))
+			err := rootCmd.Execute()
+
+			if tt.errContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContain)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "/v1/catalogs/main/schemas/analytics/tables/orders:rollback", gotPath)
+			assert.Equal(t, tt.wantBodies, gotBodies)
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// tagBatchResult is one item of a batch tag assignment response.
+type tagBatchResult struct {
+	SecurableType string  `json:"securable_type"`
+	SecurableID   string  `json:"securable_id"`
+	ColumnName    *string `json:"column_name,omitempty"`
+	Status        string  `json:"status"`
+	AssignmentID  *string `json:"assignment_id,omitempty"`
+	Error         *string `json:"error,omitempty"`
+}
+
+// newTagsAssignCmd builds `duck governance tags assign`, which tags a table,
+// or several of its columns, in one atomic batch.
+func newTagsAssignCmd(client *gen.Client) *cobra.Command {
+	return newTagsBatchCmd(client, "assign", ":batch",
+		"Assign a tag to tables or columns in one batch",
+		"Assigns a tag, given as key or key:value, to each --table, or with --columns to those columns of each table. "+
+			"All assignments are made in one transaction: if any item is rejected, nothing is assigned. "+
+			"Items that already carry the tag are reported as UNCHANGED.",
+		"  duck governance tags assign pii:email --table demo.analytics.orders --columns email,backup_email\n"+
+			"  duck governance tags assign owner:finance --table demo.analytics.orders --table demo.analytics.invoices")
+}
+
+// newTagsUnassignCmd builds `duck governance tags unassign`, the batch
+// counterpart of assign.
+func newTagsUnassignCmd(client *gen.Client) *cobra.Command {
+	return newTagsBatchCmd(client, "unassign", ":batchDelete",
+		"Remove a tag from tables or columns in one batch",
+		"Removes a tag, given as key or key:value, from each --table, or with --columns from those columns of each table. "+
+			"All removals are made in one transaction. Items that do not carry the tag are reported as NOT_FOUND.",
+		"  duck governance tags unassign pii:email --table demo.analytics.orders --columns email,backup_email")
+}
+
+func newTagsBatchCmd(client *gen.Client, use, suffix, short, long, example string) *cobra.Command {
+	var (
+		tables  []string
+		columns []string
+	)
+
+	cmd := &cobra.Command{
+		Use:     use + " <tag>",
+		Short:   short,
+		Long:    long,
+		Example: example,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tagID, err := resolveTagArg(client, args[0])
+			if err != nil {
+				return err
+			}
+
+			var items []map[string]interface{}
+			tablePaths := make(map[string]string, len(tables))
+			for _, table := range tables {
+				tableID, err := lookupTableID(client, table)
+				if err != nil {
+					return err
+				}
+				tablePaths[tableID] = table
+				if len(columns) == 0 {
+					items = append(items, map[string]interface{}{"securable_type": "table", "securable_id": tableID})
+					continue
+				}
+				for _, col := range columns {
+					items = append(items, map[string]interface{}{"securable_type": "column", "securable_id": tableID, "column_name": col})
+				}
+			}
+
+			resp, err := client.Do("POST", "/tags/"+url.PathEscape(tagID)+"/assignments"+suffix, nil,
+				map[string]interface{}{"assignments": items})
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			body, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+			var batch struct {
+				Applied bool             `json:"applied"`
+				Results []tagBatchResult `json:"results"`
+			}
+			if err := json.Unmarshal(body, &batch); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+
+			if getOutputFormat(cmd) == "json" {
+				if err := gen.PrintJSON(cmd.OutOrStdout(), batch); err != nil {
+					return err
+				}
+			} else {
+				rows := make([][]string, 0, len(batch.Results))
+				for _, r := range batch.Results {
+					securable := tablePaths[r.SecurableID]
+					if securable == "" {
+						securable = r.SecurableID
+					}
+					if r.ColumnName != nil {
+						securable += "." + *r.ColumnName
+					}
+					msg := ""
+					if r.Error != nil {
+						msg = *r.Error
+					}
+					rows = append(rows, []string{r.SecurableType, securable, r.Status, msg})
+				}
+				gen.PrintTable(cmd.OutOrStdout(), []string{"type", "securable", "status", "error"}, rows)
+			}
+
+			if !batch.Applied {
+				return fmt.Errorf("no changes were made: %d of %d items failed", countFailed(batch.Results), len(batch.Results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&tables, "table", nil, "Table as catalog.schema.table (repeatable)")
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, "Comma-separated columns of each table to target instead of the table itself")
+	_ = cmd.MarkFlagRequired("table")
+
+	return cmd
+}
+
+func countFailed(results []tagBatchResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == "FAILED" {
+			n++
+		}
+	}
+	return n
+}
+
+// resolveTagArg returns arg unchanged when it looks like a UUID. Otherwise it
+// treats arg as key or key:value and pages through /tags for an exact match.
+func resolveTagArg(client *gen.Client, arg string) (string, error) {
+	if isLikelyUUID(arg) {
+		return arg, nil
+	}
+
+	q := url.Values{}
+	q.Set("max_results", "100")
+	for {
+		resp, err := client.Do("GET", "/tags", q, nil)
+		if err != nil {
+			return "", fmt.Errorf("list tags: %w", err)
+		}
+		if err := gen.CheckError(resp); err != nil {
+			return "", fmt.Errorf("list tags: %w", err)
+		}
+		body, err := gen.ReadBody(resp)
+		if err != nil {
+			return "", fmt.Errorf("read tags response: %w", err)
+		}
+		var page struct {
+			Data          []apiTag `json:"data"`
+			NextPageToken string   `json:"next_page_token"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", fmt.Errorf("parse tags response: %w", err)
+		}
+		for _, t := range page.Data {
+			if tagKey(t.Key, t.Value) == arg {
+				return t.ID, nil
+			}
+		}
+		if page.NextPageToken == "" {
+			return "", fmt.Errorf("tag %q not found", arg)
+		}
+		q.Set("page_token", page.NextPageToken)
+	}
+}
+
+// lookupTableID resolves a catalog.schema.table path to the table's ID.
+func lookupTableID(client *gen.Client, path string) (string, error) {
+	parts := strings.Split(path, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("invalid table %q: expected catalog.schema.table", path)
+	}
+	resp, err := client.Do("GET", "/catalogs/"+url.PathEscape(parts[0])+"/schemas/"+url.PathEscape(parts[1])+"/tables/"+url.PathEscape(parts[2]), nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return "", fmt.Errorf("table %s: %w", path, err)
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+	var table struct {
+		TableID string `json:"table_id"`
+	}
+	if err := json.Unmarshal(body, &table); err != nil {
+		return "", fmt.Errorf("parse response: %w", err)
+	}
+	if table.TableID == "" {
+		return "", fmt.Errorf("table %q has no id in API response", path)
+	}
+	return table.TableID, nil
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tagsBatchServer serves the tag list, a table lookup and the batch
+// endpoints, answering the batch call with batchResp.
+func tagsBatchServer(rec *requestRecorder, batchResp string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.record(r)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/tags":
+			_, _ = w.Write([]byte(`{"data":[{"id":"tag-0","key":"pii"},{"id":"tag-1","key":"pii","value":"email"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/catalogs/demo/schemas/analytics/tables/orders":
+			_, _ = w.Write([]byte(`{"table_id":"tbl-1","name":"orders"}`))
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/v1/tags/tag-1/assignments:"):
+			_, _ = w.Write([]byte(batchResp))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+		}
+	})
+}
+
+func TestTagsAssignCmd_AssignsColumnsInOneBatch(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(tagsBatchServer(rec, `{"applied":true,"results":[
+		{"securable_type":"column","securable_id":"tbl-1","column_name":"email","status":"ASSIGNED","assignment_id":"a1"},
+		{"securable_type":"column","securable_id":"tbl-1","column_name":"backup_email","status":"UNCHANGED","assignment_id":"a2"}]}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "governance", "tags", "assign", "pii:email",
+		"--table", "demo.analytics.orders", "--columns", "email,backup_email"})
+	require.NoError(t, rootCmd.Execute())
+
+	req := rec.last()
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/v1/tags/tag-1/assignments:batch", req.Path)
+	var body struct {
+		Assignments []map[string]string `json:"assignments"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(req.Body), &body))
+	assert.Equal(t, []map[string]string{
+		{"securable_type": "column", "securable_id": "tbl-1", "column_name": "email"},
+		{"securable_type": "column", "securable_id": "tbl-1", "column_name": "backup_email"},
+	}, body.Assignments)
+
+	text := out.String()
+	assert.Regexp(t, `column\s+demo\.analytics\.orders\.email\s+ASSIGNED`, text)
+	assert.Regexp(t, `column\s+demo\.analytics\.orders\.backup_email\s+UNCHANGED`, text)
+}
+
+func TestTagsAssignCmd_FailedBatchReturnsError(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(tagsBatchServer(rec, `{"applied":false,"results":[
+		{"securable_type":"table","securable_id":"tbl-1","status":"FAILED","error":"boom"}]}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&strings.Builder{})
+	rootCmd.SetArgs([]string{"--host", srv.URL, "governance", "tags", "assign", "pii:email", "--table", "demo.analytics.orders"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no changes were made")
+	assert.Regexp(t, `table\s+demo\.analytics\.orders\s+FAILED\s+boom`, out.String())
+
+	var body struct {
+		Assignments []map[string]string `json:"assignments"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(rec.last().Body), &body))
+	assert.Equal(t, []map[string]string{{"securable_type": "table", "securable_id": "tbl-1"}}, body.Assignments)
+}
+
+func TestTagsUnassignCmd_UsesBatchDelete(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(tagsBatchServer(rec, `{"applied":true,"results":[
+		{"securable_type":"column","securable_id":"tbl-1","column_name":"email","status":"REMOVED"}]}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetOut(&strings.Builder{})
+	rootCmd.SetArgs([]string{"--host", srv.URL, "governance", "tags", "unassign", "pii:email",
+		"--table", "demo.analytics.orders", "--columns", "email"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, "/v1/tags/tag-1/assignments:batchDelete", rec.last().Path)
+}
+
+func TestTagsAssignCmd_UnknownTag(t *testing.T) {
+	rec := &requestRecorder{}
+	srv := httptest.NewServer(tagsBatchServer(rec, `{}`))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetOut(&strings.Builder{})
+	rootCmd.SetErr(&strings.Builder{})
+	rootCmd.SetArgs([]string{"--host", srv.URL, "governance", "tags", "assign", "pii:phone", "--table", "demo.analytics.orders"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `tag "pii:phone" not found`)
+}
@@ -12,12 +12,23 @@ import (
 // Uses a goroutine to read concurrently, avoiding pipe buffer deadlocks.
 func captureStdout(t *testing.T) func() string {
 	t.Helper()
-	old := os.Stdout
+	return captureFile(t, &os.Stdout)
+}
+
+// captureStderr is captureStdout for os.Stderr.
+func captureStderr(t *testing.T) func() string {
+	t.Helper()
+	return captureFile(t, &os.Stderr)
+}
+
+func captureFile(t *testing.T, f **os.File) func() string {
+	t.Helper()
+	old := *f
 	r, w, err := os.Pipe()
 	if err != nil {
 		t.Fatal(err)
 	}
-	os.Stdout = w
+	*f = w
 
 	// Read concurrently to avoid pipe buffer deadlock on large outputs
 	var buf bytes.Buffer
@@ -30,7 +41,7 @@ func captureStdout(t *testing.T) func() string {
 	return func() string {
 		_ = w.Close()
 		<-done
-		os.Stdout = old
+		*f = old
 		return buf.String()
 	}
 }
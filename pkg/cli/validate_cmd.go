@@ -17,29 +17,47 @@ func newValidateCmd(_ *gen.Client) *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:   "validate",
+		Use:   "validate [dir]",
 		Short: "Validate declarative configuration files offline",
-		Long:  "Reads YAML configuration files and checks them for errors without contacting the server.",
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			// 1. Load desired state from YAML files.
-			desired, err := declarative.LoadDirectoryWithOptions(configDir, declarative.LoadOptions{
+		Long: `Lints declarative configuration files without contacting the server.
+
+Every YAML document is checked against the JSON Schema for its kind, and the
+configuration as a whole is checked for semantic errors and dangling
+references, such as grants naming principals or securables that are not
+declared in the set. All problems are reported at once with their file and
+line, and the command exits non-zero if there are any.`,
+		Example: `  duck validate ./duck-config
+  duck validate --config-dir ./duck-config -o json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				configDir = args[0]
+			}
+
+			validationErrs, err := declarative.LintDirectory(configDir, declarative.LoadOptions{
 				AllowUnknownFields: allowUnknownFields,
 			})
 			if err != nil {
 				return fmt.Errorf("load config: %w", err)
 			}
 
-			// 2. Validate the desired state.
-			validationErrs := declarative.Validate(desired)
 			if len(validationErrs) > 0 {
 				if getOutputFormat(cmd) == "json" {
 					errMsgs := make([]string, len(validationErrs))
+					issues := make([]map[string]interface{}, len(validationErrs))
 					for i, ve := range validationErrs {
 						errMsgs[i] = ve.Error()
+						issues[i] = map[string]interface{}{
+							"file":    ve.File,
+							"line":    ve.Line,
+							"path":    ve.Path,
+							"message": ve.Message,
+						}
 					}
 					if err := gen.PrintJSON(os.Stdout, map[string]interface{}{
 						"valid":  false,
 						"errors": errMsgs,
+						"issues": issues,
 					}); err != nil {
 						return err
 					}
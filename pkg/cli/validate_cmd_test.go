@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCmd_OfflineWithPositionalDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "security"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "security", "principals.yaml"), []byte(`apiVersion: duck/v1
+kind: PrincipalList
+principals:
+  - name: alice
+    type: user
+    is_admin: false
+`), 0o600))
+
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	rootCmd := newTestRootCmd(t, srv)
+	rootCmd.SetArgs([]string{"--host", srv.URL, "validate", dir})
+
+	getOutput := captureStdout(t)
+	err := rootCmd.Execute()
+	out := getOutput()
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "Configuration is valid.")
+	assert.False(t, called, "validate must not contact the server")
+}
+
+func TestValidateCmd_RejectsExtraArgs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"validate", "a", "b"})
+	require.Error(t, rootCmd.Execute())
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// noVersionCheckEnv disables the API version check when set to a true value.
+const noVersionCheckEnv = "DUCK_NO_VERSION_CHECK"
+
+// serverSpec identifies the API spec a server serves. Hash is empty when the
+// server predates GET /openapi/version and only reports its version.
+type serverSpec struct {
+	Version string `json:"version"`
+	Hash    string `json:"hash"`
+}
+
+// fetchServerSpec asks the server which API spec it serves, falling back to
+// the api_version of GET /capabilities on servers without GET
+// /openapi/version.
+func fetchServerSpec(client *gen.Client) (*serverSpec, error) {
+	var spec serverSpec
+	err := getJSON(client, "/openapi/version", &spec)
+	var apiErr *gen.APIError
+	if errors.As(err, &apiErr) && apiErr.HTTPStatus == http.StatusNotFound {
+		var caps struct {
+			APIVersion string `json:"api_version"`
+		}
+		if err := getJSON(client, "/capabilities", &caps); err != nil {
+			return nil, err
+		}
+		return &serverSpec{Version: caps.APIVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+func getJSON(client *gen.Client, path string, v interface{}) error {
+	resp, err := client.Do(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := gen.CheckError(resp); err != nil {
+		return err
+	}
+	body, err := gen.ReadBody(resp)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// specDrift reports whether the server's spec differs from the one this CLI
+// was generated from. The hash is compared when the server reports one, so a
+// spec that changed without a version bump still counts.
+func specDrift(s *serverSpec) bool {
+	if s.Hash != "" {
+		return s.Hash != gen.SpecHash
+	}
+	return s.Version != gen.SpecVersion
+}
+
+// warnOnSpecDrift prints a warning to w when the server serves a different
+// API spec than the one this CLI was generated from. The check never fails
+// the command: if the server cannot be asked, nothing is printed and the
+// command's own request reports the problem.
+func warnOnSpecDrift(client *gen.Client, w io.Writer) {
+	s, err := fetchServerSpec(client)
+	if err != nil || !specDrift(s) {
+		return
+	}
+	printSpecDriftWarning(w, s)
+}
+
+// printSpecDriftWarning prints the warning for a server spec that differs
+// from the CLI's.
+func printSpecDriftWarning(w io.Writer, s *serverSpec) {
+	_, _ = fmt.Fprintf(w, "warning: the server's API spec %s differs from the one this CLI was built for, %s; "+
+		"some commands may not work as expected, so consider upgrading the CLI. "+
+		"Use --no-version-check or %s=1 to silence this warning.\n",
+		describeSpec(s.Version, s.Hash), describeSpec(gen.SpecVersion, gen.SpecHash), noVersionCheckEnv)
+}
+
+// describeSpec formats a spec version and, when known, an abbreviated hash.
+func describeSpec(version, hash string) string {
+	if hash == "" {
+		return version
+	}
+	const shortHash = len("sha256:") + 12
+	if len(hash) > shortHash {
+		hash = hash[:shortHash]
+	}
+	return fmt.Sprintf("%s (%s)", version, hash)
+}
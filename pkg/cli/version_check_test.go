@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"duck-demo/pkg/cli/gen"
+)
+
+// newVersionCheckServer answers GET /v1/openapi/version with spec, or with
+// 404 when spec is empty so the CLI falls back to GET /v1/capabilities with
+// apiVersion. Every other request gets an empty principal list. It counts the
+// version checks it receives.
+func newVersionCheckServer(t *testing.T, spec, apiVersion string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var checks atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/openapi/version":
+			checks.Add(1)
+			if spec == "" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(spec))
+		case "/v1/capabilities":
+			_, _ = w.Write([]byte(`{"api_version":"` + apiVersion + `","features":{},"batch_operations":[]}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &checks
+}
+
+func runWithVersionCheck(t *testing.T, srv *httptest.Server, args ...string) string {
+	t.Helper()
+	t.Setenv(noVersionCheckEnv, "")
+	rootCmd := newTestRootCmd(t, srv)
+	var out, errOut strings.Builder
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+	rootCmd.SetArgs(append([]string{"--host", srv.URL}, args...))
+	require.NoError(t, rootCmd.Execute())
+	return errOut.String()
+}
+
+func TestVersionCheck_MismatchWarnsOnce(t *testing.T) {
+	srv, checks := newVersionCheckServer(t, `{"version":"9.0.0","hash":"sha256:`+strings.Repeat("0", 64)+`"}`, "")
+
+	stderr := runWithVersionCheck(t, srv, "security", "principals", "list")
+
+	assert.Contains(t, stderr, "warning: the server's API spec 9.0.0 (sha256:000000000000) differs")
+	assert.Contains(t, stderr, "this CLI was built for, "+gen.SpecVersion)
+	assert.Equal(t, 1, strings.Count(stderr, "warning:"))
+	assert.Equal(t, int32(1), checks.Load())
+}
+
+func TestVersionCheck_MatchIsSilent(t *testing.T) {
+	srv, checks := newVersionCheckServer(t, `{"version":"`+gen.SpecVersion+`","hash":"`+gen.SpecHash+`"}`, "")
+
+	stderr := runWithVersionCheck(t, srv, "security", "principals", "list")
+
+	assert.Empty(t, stderr)
+	assert.Equal(t, int32(1), checks.Load())
+}
+
+func TestVersionCheck_FallsBackToCapabilities(t *testing.T) {
+	t.Run("mismatch", func(t *testing.T) {
+		srv, _ := newVersionCheckServer(t, "", "0.1.0")
+		stderr := runWithVersionCheck(t, srv, "security", "principals", "list")
+		assert.Contains(t, stderr, "warning: the server's API spec 0.1.0 differs")
+	})
+	t.Run("match", func(t *testing.T) {
+		srv, _ := newVersionCheckServer(t, "", gen.SpecVersion)
+		assert.Empty(t, runWithVersionCheck(t, srv, "security", "principals", "list"))
+	})
+}
+
+func TestVersionCheck_Suppressed(t *testing.T) {
+	mismatch := `{"version":"9.0.0","hash":"sha256:` + strings.Repeat("0", 64) + `"}`
+	t.Run("flag", func(t *testing.T) {
+		srv, checks := newVersionCheckServer(t, mismatch, "")
+		stderr := runWithVersionCheck(t, srv, "--no-version-check", "security", "principals", "list")
+		assert.Empty(t, stderr)
+		assert.Equal(t, int32(0), checks.Load())
+	})
+	t.Run("env", func(t *testing.T) {
+		srv, checks := newVersionCheckServer(t, mismatch, "")
+		t.Setenv(noVersionCheckEnv, "true")
+		rootCmd := newTestRootCmd(t, srv)
+		var errOut strings.Builder
+		rootCmd.SetErr(&errOut)
+		rootCmd.SetOut(&strings.Builder{})
+		rootCmd.SetArgs([]string{"--host", srv.URL, "security", "principals", "list"})
+		require.NoError(t, rootCmd.Execute())
+		assert.Empty(t, errOut.String())
+		assert.Equal(t, int32(0), checks.Load())
+	})
+}
+
+func TestVersionCheck_NoRequestForOfflineCommands(t *testing.T) {
+	srv, checks := newVersionCheckServer(t, `{"version":"9.0.0","hash":"sha256:0"}`, "")
+
+	assert.Empty(t, runWithVersionCheck(t, srv, "version"))
+	assert.Equal(t, int32(0), checks.Load())
+}
@@ -1,27 +1,131 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
+	"net/url"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"duck-demo/pkg/cli/gen"
 )
 
-func newVersionCmd() *cobra.Command {
-	return &cobra.Command{
+// serverVersion is the getServerVersion response.
+type serverVersion struct {
+	AppVersion    string `json:"app_version"`
+	Commit        string `json:"commit"`
+	GoVersion     string `json:"go_version"`
+	APIVersion    string `json:"api_version"`
+	DuckDBVersion string `json:"duckdb_version"`
+	Extensions    []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"extensions"`
+	MetastoreSchemaVersion int64 `json:"metastore_schema_version"`
+	ComputeEndpoints       []struct {
+		Name          string `json:"name"`
+		Type          string `json:"type"`
+		DuckDBVersion string `json:"duckdb_version"`
+		Error         string `json:"error"`
+	} `json:"compute_endpoints"`
+}
+
+func newVersionCmd(client *gen.Client) *cobra.Command {
+	var (
+		server         bool
+		includeCompute bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the CLI version",
+		Long: "Prints the CLI version. With --server it also reports the server build, its DuckDB engine " +
+			"and loaded extensions, and the metastore schema version; --include-compute adds the DuckDB " +
+			"version of each compute endpoint.",
+		Example: "  duck version\n  duck version --server --include-compute",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if getOutputFormat(cmd) == "json" {
-				return gen.PrintJSON(os.Stdout, map[string]string{
+			isJSON := getOutputFormat(cmd) == "json"
+			if !server && !includeCompute {
+				if isJSON {
+					return gen.PrintJSON(cmd.OutOrStdout(), map[string]string{
+						"version": version,
+						"commit":  commit,
+					})
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "duck version %s (commit: %s)\n", version, commit)
+				return nil
+			}
+
+			query := url.Values{}
+			if includeCompute {
+				query.Set("include_compute", "true")
+			}
+			resp, err := client.Do("GET", "/version", query, nil)
+			if err != nil {
+				return err
+			}
+			if err := gen.CheckError(resp); err != nil {
+				return err
+			}
+			body, err := gen.ReadBody(resp)
+			if err != nil {
+				return fmt.Errorf("read response: %w", err)
+			}
+
+			if isJSON {
+				var pretty interface{}
+				if err := json.Unmarshal(body, &pretty); err != nil {
+					return fmt.Errorf("parse response: %w", err)
+				}
+				return gen.PrintJSON(cmd.OutOrStdout(), map[string]interface{}{
 					"version": version,
 					"commit":  commit,
+					"server":  pretty,
 				})
 			}
-			_, _ = fmt.Fprintf(os.Stdout, "duck version %s (commit: %s)\n", version, commit)
+
+			var sv serverVersion
+			if err := json.Unmarshal(body, &sv); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+			printServerVersion(cmd, sv)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&server, "server", false, "Also report the server, DuckDB and metastore schema versions")
+	cmd.Flags().BoolVar(&includeCompute, "include-compute", false, "Also report compute endpoint versions (implies --server)")
+
+	return cmd
+}
+
+func printServerVersion(cmd *cobra.Command, sv serverVersion) {
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(w, "duck version %s (commit: %s)\n", version, commit)
+	serverCommit := sv.Commit
+	if serverCommit == "" {
+		serverCommit = "unknown"
+	} else if len(serverCommit) > 12 {
+		serverCommit = serverCommit[:12]
+	}
+	_, _ = fmt.Fprintf(w, "server version %s (commit: %s, %s)\n", sv.AppVersion, serverCommit, sv.GoVersion)
+	_, _ = fmt.Fprintf(w, "api version %s\n", sv.APIVersion)
+	_, _ = fmt.Fprintf(w, "duckdb version %s\n", sv.DuckDBVersion)
+	_, _ = fmt.Fprintf(w, "metastore schema version %d\n", sv.MetastoreSchemaVersion)
+	if len(sv.Extensions) > 0 {
+		names := make([]string, len(sv.Extensions))
+		for i, e := range sv.Extensions {
+			names[i] = strings.TrimSpace(e.Name + " " + e.Version)
+		}
+		_, _ = fmt.Fprintf(w, "extensions: %s\n", strings.Join(names, ", "))
+	}
+	if len(sv.ComputeEndpoints) > 0 {
+		rows := make([][]string, len(sv.ComputeEndpoints))
+		for i, ep := range sv.ComputeEndpoints {
+			rows[i] = []string{ep.Name, ep.Type, ep.DuckDBVersion, ep.Error}
+		}
+		_, _ = fmt.Fprintln(w)
+		gen.PrintTable(w, []string{"ENDPOINT", "TYPE", "DUCKDB", "ERROR"}, rows)
+	}
 }
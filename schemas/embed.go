@@ -0,0 +1,11 @@
+// Package schemas embeds the generated declarative JSON Schema artifacts so
+// tools can validate documents without network or filesystem access.
+package schemas
+
+import "embed"
+
+// Declarative contains the per-kind declarative JSON Schemas, rooted at
+// declarative/v1/kinds/<file-name>.schema.json.
+//
+//go:embed declarative/v1/kinds/*.schema.json
+var Declarative embed.FS
@@ -126,7 +126,9 @@ func TestHTTP_SchemaErrors(t *testing.T) {
 
 		resp2 := doRequest(t, "POST", env.Server.URL+"/v1/catalogs/lake/schemas", env.Keys.Admin, body)
 		assert.Equal(t, 409, resp2.StatusCode)
-		_ = resp2.Body.Close()
+		var apiErr map[string]interface{}
+		decodeJSON(t, resp2, &apiErr)
+		assert.Equal(t, "RESOURCE_EXISTS", apiErr["error_code"])
 	})
 
 	t.Run("delete_nonexistent_404", func(t *testing.T) {
@@ -379,6 +381,83 @@ func TestHTTP_CatalogAuthorization(t *testing.T) {
 	})
 }
 
+// TestHTTP_CreateUpsert tests that ?upsert=true creates a resource that is
+// absent and updates one that is present, keeping its ID, unless the present
+// table has a different definition.
+func TestHTTP_CreateUpsert(t *testing.T) {
+	env := setupHTTPServer(t, httpTestOpts{WithDuckLake: true})
+	schemasURL := env.Server.URL + "/v1/catalogs/lake/schemas"
+
+	var schemaID interface{}
+	t.Run("schema_absent_creates_201", func(t *testing.T) {
+		resp := doRequest(t, "POST", schemasURL+"?upsert=true", env.Keys.Admin,
+			map[string]interface{}{"name": "upsert_schema", "comment": "first"})
+		require.Equal(t, 201, resp.StatusCode)
+		var result map[string]interface{}
+		decodeJSON(t, resp, &result)
+		schemaID = result["schema_id"]
+		require.NotEmpty(t, schemaID)
+	})
+
+	t.Run("schema_present_updates_200", func(t *testing.T) {
+		resp := doRequest(t, "POST", schemasURL+"?upsert=true", env.Keys.Admin,
+			map[string]interface{}{"name": "upsert_schema", "comment": "second"})
+		require.Equal(t, 200, resp.StatusCode)
+		var result map[string]interface{}
+		decodeJSON(t, resp, &result)
+		assert.Equal(t, schemaID, result["schema_id"], "upsert keeps the existing ID")
+		assert.Equal(t, "second", result["comment"])
+	})
+
+	t.Run("schema_present_without_upsert_409", func(t *testing.T) {
+		resp := doRequest(t, "POST", schemasURL, env.Keys.Admin,
+			map[string]interface{}{"name": "upsert_schema"})
+		require.Equal(t, 409, resp.StatusCode)
+		var apiErr map[string]interface{}
+		decodeJSON(t, resp, &apiErr)
+		assert.Equal(t, "RESOURCE_EXISTS", apiErr["error_code"])
+	})
+
+	tablesURL := schemasURL + "/upsert_schema/tables"
+	table := func(comment string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":    "upsert_table",
+			"columns": []map[string]interface{}{{"name": "id", "type": "INTEGER"}},
+			"comment": comment,
+		}
+	}
+
+	var tableID interface{}
+	t.Run("table_absent_creates_201", func(t *testing.T) {
+		resp := doRequest(t, "POST", tablesURL+"?upsert=true", env.Keys.Admin, table("first"))
+		require.Equal(t, 201, resp.StatusCode)
+		var result map[string]interface{}
+		decodeJSON(t, resp, &result)
+		tableID = result["table_id"]
+		require.NotEmpty(t, tableID)
+	})
+
+	t.Run("table_present_updates_200", func(t *testing.T) {
+		resp := doRequest(t, "POST", tablesURL+"?upsert=true", env.Keys.Admin, table("second"))
+		require.Equal(t, 200, resp.StatusCode)
+		var result map[string]interface{}
+		decodeJSON(t, resp, &result)
+		assert.Equal(t, tableID, result["table_id"], "upsert keeps the existing ID")
+		assert.Equal(t, "second", result["comment"])
+	})
+
+	t.Run("table_present_with_other_columns_409", func(t *testing.T) {
+		body := table("third")
+		body["columns"] = []map[string]interface{}{{"name": "id", "type": "VARCHAR"}}
+		resp := doRequest(t, "POST", tablesURL+"?upsert=true", env.Keys.Admin, body)
+		require.Equal(t, 409, resp.StatusCode)
+		var apiErr map[string]interface{}
+		decodeJSON(t, resp, &apiErr)
+		assert.Equal(t, "CONFLICT", apiErr["error_code"], "a mismatch is not an existing match")
+		assert.Contains(t, apiErr["message"], "different definition")
+	})
+}
+
 // TestHTTP_CatalogSchemaForceDelete tests cascade delete behavior through HTTP.
 func TestHTTP_CatalogSchemaForceDelete(t *testing.T) {
 	env := setupHTTPServer(t, httpTestOpts{WithDuckLake: true})
@@ -403,7 +482,9 @@ func TestHTTP_CatalogSchemaForceDelete(t *testing.T) {
 			env.Keys.Admin, nil)
 		// Should fail because schema has children (returns 409 Conflict)
 		assert.Equal(t, 409, resp.StatusCode)
-		_ = resp.Body.Close()
+		var apiErr map[string]interface{}
+		decodeJSON(t, resp, &apiErr)
+		assert.Equal(t, "CONFLICT", apiErr["error_code"], "a non-empty schema is not a duplicate")
 	})
 
 	t.Run("delete_non_empty_with_force_succeeds", func(t *testing.T) {
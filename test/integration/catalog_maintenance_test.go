@@ -0,0 +1,82 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"duck-demo/internal/db/dbstore"
+	"duck-demo/internal/db/repository"
+	"duck-demo/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// TestCatalog_CompactTable — compaction merges small files written by
+// separate inserts
+// ---------------------------------------------------------------------------
+
+func TestCatalog_CompactTable(t *testing.T) {
+	if sharedCatalogEnv == nil {
+		t.Skip("DuckLake extensions not available")
+	}
+	env := sharedCatalogEnv
+	repo := repository.NewCatalogRepo(env.MetaDB, env.MetaDB, dbstore.New(env.MetaDB), env.DuckDB, "lake", nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	if _, err := repo.CreateSchema(ctx, "maint_schema", "", "admin"); err != nil {
+		t.Fatalf("CreateSchema: %v", err)
+	}
+	req := domain.CreateTableRequest{
+		Name:    "events",
+		Columns: []domain.CreateColumnDef{{Name: "id", Type: "INTEGER"}, {Name: "region", Type: "VARCHAR"}},
+	}
+	if _, err := repo.CreateTable(ctx, "maint_schema", req, "admin"); err != nil {
+		t.Fatalf("CreateTable: %v", err)
+	}
+
+	// Each insert commits its own snapshot and writes its own data file.
+	const inserts = 4
+	for i := range inserts {
+		stmt := fmt.Sprintf(`INSERT INTO lake.maint_schema.events VALUES (%d, 'eu')`, i)
+		if _, err := env.DuckDB.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+	}
+
+	res, err := repo.CompactTable(ctx, "maint_schema", "events")
+	if err != nil {
+		t.Fatalf("CompactTable: %v", err)
+	}
+	if res.Operation != domain.TableMaintenanceCompact {
+		t.Errorf("Operation = %q, want %q", res.Operation, domain.TableMaintenanceCompact)
+	}
+	if res.FilesBefore < inserts {
+		t.Errorf("FilesBefore = %d, want at least %d", res.FilesBefore, inserts)
+	}
+	if res.FilesAfter >= res.FilesBefore {
+		t.Errorf("FilesAfter = %d, want fewer than FilesBefore (%d)", res.FilesAfter, res.FilesBefore)
+	}
+
+	var rows int
+	if err := env.DuckDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM lake.maint_schema.events`).Scan(&rows); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	if rows != inserts {
+		t.Errorf("row count after compaction = %d, want %d", rows, inserts)
+	}
+
+	// The replaced files belong to older snapshots; expiring them all lets
+	// the vacuum delete those files.
+	vac, err := repo.VacuumTable(ctx, "maint_schema", "events", time.Second)
+	if err != nil {
+		t.Fatalf("VacuumTable: %v", err)
+	}
+	if vac.FilesAfter > vac.FilesBefore {
+		t.Errorf("vacuum FilesAfter = %d, want at most FilesBefore (%d)", vac.FilesAfter, vac.FilesBefore)
+	}
+}
@@ -0,0 +1,127 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"duck-demo/internal/db/dbstore"
+	"duck-demo/internal/db/repository"
+	"duck-demo/internal/domain"
+	"duck-demo/internal/service/catalog"
+)
+
+// singleRepoFactory serves one CatalogRepo for every catalog name.
+type singleRepoFactory struct{ repo domain.CatalogRepository }
+
+func (f singleRepoFactory) ForCatalog(_ context.Context, _ string) (domain.CatalogRepository, error) {
+	return f.repo, nil
+}
+
+// allowAllAuth grants every privilege check.
+type allowAllAuth struct{ domain.AuthorizationService }
+
+func (allowAllAuth) CheckPrivilege(_ context.Context, _, _, _, _ string) (bool, error) {
+	return true, nil
+}
+
+// staticLocation resolves every location name to one location.
+type staticLocation struct {
+	domain.ExternalLocationRepository
+	loc domain.ExternalLocation
+}
+
+func (s staticLocation) GetByName(_ context.Context, _ string) (*domain.ExternalLocation, error) {
+	loc := s.loc
+	return &loc, nil
+}
+
+// ---------------------------------------------------------------------------
+// TestCatalog_RegisterDirectory — every parquet file in a local directory
+// becomes an external table
+// ---------------------------------------------------------------------------
+
+func TestCatalog_RegisterDirectory(t *testing.T) {
+	if sharedCatalogEnv == nil {
+		t.Skip("DuckLake extensions not available")
+	}
+	env := sharedCatalogEnv
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	for i, name := range []string{"orders", "customers", "returns"} {
+		path := filepath.Join(dir, name+".parquet")
+		stmt := fmt.Sprintf(`COPY (SELECT range AS id, 'r%d' AS region FROM range(%d)) TO '%s' (FORMAT parquet)`, i, i+1, path)
+		if _, err := env.DuckDB.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := repository.NewCatalogRepo(env.MetaDB, env.MetaDB, dbstore.New(env.MetaDB), env.DuckDB, "lake", repository.NewExternalTableRepo(env.MetaDB), logger)
+	if _, err := repo.CreateSchema(ctx, "landing_dir", "", "admin"); err != nil {
+		t.Fatalf("CreateSchema: %v", err)
+	}
+	svc := catalog.NewCatalogService(
+		singleRepoFactory{repo: repo},
+		allowAllAuth{},
+		repository.NewAuditRepo(env.MetaDB),
+		repository.NewTagRepo(env.MetaDB),
+		repository.NewTableStatisticsRepo(env.MetaDB),
+		staticLocation{loc: domain.ExternalLocation{Name: "local", URL: dir}},
+	)
+
+	res, err := svc.RegisterDirectory(ctx, "admin", domain.RegisterDirectoryRequest{
+		SchemaName:   "landing_dir",
+		LocationName: "local",
+		Path:         dir,
+		FileFormat:   "parquet",
+	})
+	if err != nil {
+		t.Fatalf("RegisterDirectory: %v", err)
+	}
+	if len(res.Skipped) != 0 {
+		t.Errorf("Skipped = %+v, want none", res.Skipped)
+	}
+	got := make(map[string]bool, len(res.Registered))
+	for _, tbl := range res.Registered {
+		got[tbl.Name] = true
+		if tbl.TableType != domain.TableTypeExternal {
+			t.Errorf("%s: TableType = %q, want %q", tbl.Name, tbl.TableType, domain.TableTypeExternal)
+		}
+		if len(tbl.Columns) != 2 {
+			t.Errorf("%s: %d columns inferred, want 2", tbl.Name, len(tbl.Columns))
+		}
+	}
+	for _, want := range []string{"orders", "customers", "returns"} {
+		if !got[want] {
+			t.Errorf("table %q not registered; got %v", want, got)
+		}
+	}
+
+	var rows int
+	if err := env.DuckDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM lake.landing_dir.returns`).Scan(&rows); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	if rows != 3 {
+		t.Errorf("returns row count = %d, want 3", rows)
+	}
+
+	// Registering the directory again skips the tables that now exist.
+	again, err := svc.RegisterDirectory(ctx, "admin", domain.RegisterDirectoryRequest{
+		SchemaName:   "landing_dir",
+		LocationName: "local",
+		Path:         dir,
+	})
+	if err != nil {
+		t.Fatalf("RegisterDirectory again: %v", err)
+	}
+	if len(again.Registered) != 0 || len(again.Skipped) != 3 {
+		t.Errorf("second run registered %d, skipped %d; want 0 and 3", len(again.Registered), len(again.Skipped))
+	}
+}
@@ -0,0 +1,108 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"duck-demo/internal/db/dbstore"
+	"duck-demo/internal/db/repository"
+	"duck-demo/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// TestCatalog_RollbackTable — rolling back to an earlier snapshot restores
+// the row count the table had at that snapshot
+// ---------------------------------------------------------------------------
+
+func TestCatalog_RollbackTable(t *testing.T) {
+	if sharedCatalogEnv == nil {
+		t.Skip("DuckLake extensions not available")
+	}
+	env := sharedCatalogEnv
+	repo := repository.NewCatalogRepo(env.MetaDB, env.MetaDB, dbstore.New(env.MetaDB), env.DuckDB, "lake", nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	if _, err := repo.CreateSchema(ctx, "rollback_schema", "", "admin"); err != nil {
+		t.Fatalf("CreateSchema: %v", err)
+	}
+	req := domain.CreateTableRequest{
+		Name:    "orders",
+		Columns: []domain.CreateColumnDef{{Name: "id", Type: "INTEGER"}, {Name: "amount", Type: "DOUBLE"}},
+	}
+	if _, err := repo.CreateTable(ctx, "rollback_schema", req, "admin"); err != nil {
+		t.Fatalf("CreateTable: %v", err)
+	}
+
+	countRows := func() int64 {
+		t.Helper()
+		var n int64
+		if err := env.DuckDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM lake.rollback_schema.orders`).Scan(&n); err != nil {
+			t.Fatalf("count rows: %v", err)
+		}
+		return n
+	}
+	exec := func(stmt string) {
+		t.Helper()
+		if _, err := env.DuckDB.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	exec(`INSERT INTO lake.rollback_schema.orders VALUES (1, 10.0), (2, 20.0), (3, 30.0)`)
+	var good int64
+	if err := env.DuckDB.QueryRowContext(ctx, `SELECT MAX(snapshot_id) FROM ducklake_snapshots('lake')`).Scan(&good); err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+
+	// A bad load and a bad delete after the good snapshot.
+	exec(`INSERT INTO lake.rollback_schema.orders VALUES (4, 40.0), (5, 50.0)`)
+	exec(`DELETE FROM lake.rollback_schema.orders WHERE id = 1`)
+	if got := countRows(); got != 4 {
+		t.Fatalf("row count before rollback = %d, want 4", got)
+	}
+
+	preview, err := repo.RollbackTable(ctx, "rollback_schema", "orders", domain.RollbackTableRequest{SnapshotID: &good})
+	if err != nil {
+		t.Fatalf("RollbackTable preview: %v", err)
+	}
+	if preview.Applied || preview.RowsBefore != 4 || preview.RowsAfter != 3 {
+		t.Errorf("preview = %+v, want unapplied 4 -> 3 rows", preview)
+	}
+	if got := countRows(); got != 4 {
+		t.Errorf("row count after preview = %d, want 4 (unchanged)", got)
+	}
+
+	res, err := repo.RollbackTable(ctx, "rollback_schema", "orders", domain.RollbackTableRequest{SnapshotID: &good, Confirm: true})
+	if err != nil {
+		t.Fatalf("RollbackTable: %v", err)
+	}
+	if !res.Applied || res.RowsAfter != 3 {
+		t.Errorf("result = %+v, want applied with 3 rows", res)
+	}
+	if res.NewSnapshot == nil || *res.NewSnapshot <= res.CurrentSnapshot {
+		t.Errorf("NewSnapshot = %v, want a snapshot after %d", res.NewSnapshot, res.CurrentSnapshot)
+	}
+	if got := countRows(); got != 3 {
+		t.Errorf("row count after rollback = %d, want 3", got)
+	}
+	var restoredID1 int64
+	if err := env.DuckDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM lake.rollback_schema.orders WHERE id = 1`).Scan(&restoredID1); err != nil {
+		t.Fatalf("query restored row: %v", err)
+	}
+	if restoredID1 != 1 {
+		t.Errorf("deleted row id=1 not restored")
+	}
+
+	// The rollback wrote a snapshot of its own, so it can be undone too.
+	undo, err := repo.RollbackTable(ctx, "rollback_schema", "orders", domain.RollbackTableRequest{SnapshotID: &res.CurrentSnapshot, Confirm: true})
+	if err != nil {
+		t.Fatalf("undo RollbackTable: %v", err)
+	}
+	if undo.RowsAfter != 4 || countRows() != 4 {
+		t.Errorf("row count after undoing rollback = %d, want 4", undo.RowsAfter)
+	}
+}
@@ -71,7 +71,7 @@ func TestCatalog_SchemaCRUD(t *testing.T) {
 		{
 			name: "ListSchemas_ContainsCreatedAndMain",
 			fn: func(t *testing.T) {
-				schemas, total, err := repo.ListSchemas(ctx, domain.PageRequest{})
+				schemas, total, err := repo.ListSchemas(ctx, nil, domain.PageRequest{})
 				if err != nil {
 					t.Fatalf("ListSchemas: %v", err)
 				}
@@ -218,7 +218,7 @@ func TestCatalog_TableCRUD(t *testing.T) {
 		{
 			name: "ListTables",
 			fn: func(t *testing.T) {
-				tables, total, err := repo.ListTables(ctx, "tbl_test_schema", domain.PageRequest{})
+				tables, total, err := repo.ListTables(ctx, "tbl_test_schema", nil, domain.PageRequest{})
 				if err != nil {
 					t.Fatalf("ListTables: %v", err)
 				}
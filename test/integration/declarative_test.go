@@ -503,6 +503,61 @@ func TestDeclarative_ExportRoundTrip(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestDeclarative_ImportSnapshotIntoEmptyServer — an exported snapshot
+// imported tier by tier into a fresh server reproduces the source state.
+// ---------------------------------------------------------------------------
+
+func TestDeclarative_ImportSnapshotIntoEmptyServer(t *testing.T) {
+	ctx := context.Background()
+
+	source := setupHTTPServer(t, httpTestOpts{})
+	pResp := doRequest(t, "POST", source.Server.URL+"/v1/principals", source.Keys.Admin,
+		map[string]interface{}{"name": "imported-user", "type": "user"})
+	require.Equal(t, http.StatusCreated, pResp.StatusCode)
+	_ = pResp.Body.Close()
+	gResp := doRequest(t, "POST", source.Server.URL+"/v1/groups", source.Keys.Admin,
+		map[string]interface{}{"name": "imported-group"})
+	require.Equal(t, http.StatusCreated, gResp.StatusCode)
+	_ = gResp.Body.Close()
+
+	sourceState, err := makeStateClient(t, source.Server.URL, source.Keys.Admin).ReadState(ctx)
+	require.NoError(t, err)
+
+	exportDir := filepath.Join(t.TempDir(), "exported")
+	require.NoError(t, declarative.ExportDirectory(exportDir, sourceState, false))
+	snapshot, err := declarative.LoadDirectory(exportDir)
+	require.NoError(t, err)
+
+	target := setupHTTPServer(t, httpTestOpts{})
+	targetClient := makeStateClient(t, target.Server.URL, target.Keys.Admin)
+	targetState, err := targetClient.ReadState(ctx)
+	require.NoError(t, err)
+
+	require.Empty(t, declarative.ValidateImport(snapshot, targetState), "snapshot references should resolve")
+	plan := declarative.ImportPlan(snapshot, targetState)
+	require.Empty(t, plan.Errors)
+	creates, _, deletes := countActions(plan)
+	assert.Positive(t, creates, "import should create the resources missing on the target")
+	assert.Zero(t, deletes, "import must never delete")
+
+	results, err := declarative.ApplyTiers(ctx, targetClient, plan.Tiers())
+	require.NoError(t, err)
+	for _, r := range results {
+		assert.Nil(t, r.Failed, "tier %d should not fail", r.Layer)
+	}
+
+	imported, err := targetClient.ReadState(ctx)
+	require.NoError(t, err)
+	replan := declarative.Diff(snapshot, imported)
+	creates, updates, _ := countActions(replan)
+	assert.Zero(t, creates, "imported server should contain every snapshot resource")
+	assert.Zero(t, updates, "imported resources should match the snapshot")
+	for _, a := range replan.Actions {
+		t.Logf("  remaining action: %s %s %s", a.Operation, a.ResourceKind, a.ResourceName)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestDeclarative_DeletionProtection — protected resources produce errors.
 // ---------------------------------------------------------------------------
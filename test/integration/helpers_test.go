@@ -652,7 +652,7 @@ func setupIntegrationServer(t *testing.T) *testEnv {
 	tagRepo := repository.NewTagRepo(metaDB)
 	lineageRepo := repository.NewLineageRepo(metaDB)
 	searchRepo := repository.NewSearchRepo(metaDB, metaDB)
-	queryHistoryRepo := repository.NewQueryHistoryRepo(metaDB)
+	queryHistoryRepo := repository.NewQueryHistoryRepo(metaDB, metaDB)
 	viewRepo := repository.NewViewRepo(metaDB)
 
 	// Build services
@@ -700,6 +700,17 @@ func setupIntegrationServer(t *testing.T) *testEnv {
 		nil, // modelSvc
 		nil, // macroSvc
 		nil, // semanticSvc
+		nil, // applyLockSvc
+		nil, // webhookSvc
+		nil, // migrationSvc
+		nil, // backupSvc
+		nil, // versionSvc
+		nil, // extensionSvc
+		authSvc,
+		nil, // defaultGrantSvc
+		nil, // approvalSvc
+		nil, // seedSvc
+		nil, // searchIndexSvc
 	)
 	strictHandler := api.NewStrictHandler(handler, nil)
 
@@ -792,7 +803,7 @@ func setupLocalExtensionServer(t *testing.T) *testEnv {
 	tagRepo := repository.NewTagRepo(metaDB)
 	lineageRepo := repository.NewLineageRepo(metaDB)
 	searchRepo := repository.NewSearchRepo(metaDB, metaDB)
-	queryHistoryRepo := repository.NewQueryHistoryRepo(metaDB)
+	queryHistoryRepo := repository.NewQueryHistoryRepo(metaDB, metaDB)
 	viewRepo := repository.NewViewRepo(metaDB)
 
 	// Build services
@@ -836,6 +847,17 @@ func setupLocalExtensionServer(t *testing.T) *testEnv {
 		nil, // modelSvc
 		nil, // macroSvc
 		nil, // semanticSvc
+		nil, // applyLockSvc
+		nil, // webhookSvc
+		nil, // migrationSvc
+		nil, // backupSvc
+		nil, // versionSvc
+		nil, // extensionSvc
+		authSvc,
+		nil, // defaultGrantSvc
+		nil, // approvalSvc
+		nil, // seedSvc
+		nil, // searchIndexSvc
 	)
 	strictHandler := api.NewStrictHandler(handler, nil)
 
@@ -1187,7 +1209,7 @@ func setupHTTPServer(t *testing.T, opts httpTestOpts) *httpTestEnv {
 	tagRepo := repository.NewTagRepo(metaDB)
 	lineageRepo := repository.NewLineageRepo(metaDB)
 	searchRepo := repository.NewSearchRepo(metaDB, metaDB)
-	queryHistoryRepo := repository.NewQueryHistoryRepo(metaDB)
+	queryHistoryRepo := repository.NewQueryHistoryRepo(metaDB, metaDB)
 	viewRepo := repository.NewViewRepo(metaDB)
 
 	// Build authorization service unconditionally (needed by viewSvc)
@@ -1256,7 +1278,7 @@ func setupHTTPServer(t *testing.T, opts httpTestOpts) *httpTestEnv {
 		tagRepo = repository.NewTagRepo(metaDB)
 		lineageRepo = repository.NewLineageRepo(metaDB)
 		searchRepo = repository.NewSearchRepo(metaDB, metaDB)
-		queryHistoryRepo = repository.NewQueryHistoryRepo(metaDB)
+		queryHistoryRepo = repository.NewQueryHistoryRepo(metaDB, metaDB)
 		viewRepo = repository.NewViewRepo(metaDB)
 
 		// Rebuild services on new repos
@@ -1449,6 +1471,17 @@ func setupHTTPServer(t *testing.T, opts httpTestOpts) *httpTestEnv {
 		modelSvc, // modelSvc
 		macroSvc, // macroSvc
 		semanticSvc,
+		nil, // applyLockSvc
+		nil, // webhookSvc
+		nil, // migrationSvc
+		nil, // backupSvc
+		nil, // versionSvc
+		nil, // extensionSvc
+		authSvc,
+		nil, // defaultGrantSvc
+		nil, // approvalSvc
+		nil, // seedSvc
+		nil, // searchIndexSvc
 	)
 	strictHandler := api.NewStrictHandler(handler, nil)
 
@@ -2117,7 +2150,7 @@ func setupMultiTableLocalServer(t *testing.T) *multiTableTestEnv {
 	tagRepo := repository.NewTagRepo(metaDB)
 	lineageRepo := repository.NewLineageRepo(metaDB)
 	searchRepo := repository.NewSearchRepo(metaDB, metaDB)
-	queryHistoryRepo := repository.NewQueryHistoryRepo(metaDB)
+	queryHistoryRepo := repository.NewQueryHistoryRepo(metaDB, metaDB)
 	viewRepo := repository.NewViewRepo(metaDB)
 
 	authSvc := security.NewAuthorizationService(
@@ -2158,6 +2191,17 @@ func setupMultiTableLocalServer(t *testing.T) *multiTableTestEnv {
 		nil, // modelSvc
 		nil, // macroSvc
 		nil, // semanticSvc
+		nil, // applyLockSvc
+		nil, // webhookSvc
+		nil, // migrationSvc
+		nil, // backupSvc
+		nil, // versionSvc
+		nil, // extensionSvc
+		authSvc,
+		nil, // defaultGrantSvc
+		nil, // approvalSvc
+		nil, // seedSvc
+		nil, // searchIndexSvc
 	)
 	strictHandler := api.NewStrictHandler(handler, nil)
 